@@ -72,11 +72,14 @@ class MCPClient:
         data = resp.json()
         if "error" in data:
             raise RuntimeError(data["error"])
-        content = data.get("result", {}).get("content", [])
+        result = data.get("result", {})
+        content = result.get("content", [])
         if not content:
             return None
         # tool responses are JSON strings
         text = content[0].get("text", "")
+        if result.get("isError"):
+            raise RuntimeError(_format_tool_error(text))
         if isinstance(text, str) and text.lower().startswith("error:"):
             raise RuntimeError(text)
         try:
@@ -93,6 +96,21 @@ class MCPClient:
         return [MCPTool(name=t["name"], description=t["description"]) for t in tools]
 
 
+def _format_tool_error(text: str) -> str:
+    """Render a tool error body as ``error: CODE: message``.
+
+    Tool errors carry ``{"error": {"code", "message", "details"}}``; anything
+    that does not parse that way is passed through as-is.
+    """
+    try:
+        err = json.loads(text).get("error", {})
+    except (json.JSONDecodeError, AttributeError):
+        return text
+    if not isinstance(err, dict) or "code" not in err:
+        return text
+    return f"error: {err['code']}: {err.get('message', '')}"
+
+
 _GO_PROCESS: Optional[subprocess.Popen] = None
 
 
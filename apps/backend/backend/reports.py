@@ -258,8 +258,11 @@ def _simple_waiver_md(report: Dict[str, Any]) -> str:
         f"Weighted score = {report.get('weight_fixtures', 0):.2f}*fixture_norm + "
         f"{report.get('weight_form', 0):.2f}*form_norm + "
         f"{report.get('weight_total_points', 0):.2f}*total_norm + "
-        f"{report.get('weight_xg', 0):.2f}*xg_norm"
+        f"{report.get('weight_xg', 0):.2f}*xg_norm + "
+        f"{report.get('weight_xa', 0):.2f}*xa_norm + "
+        f"{report.get('weight_bonus', 0):.2f}*bonus_norm"
     )
+    lines.append("GK/DEF use the defensive profile: defensive_norm takes the place of xg_norm.")
     lines.append(
         f"Fixture score blend = {report.get('fixture_season_weight', 0):.2f} season / "
         f"{report.get('fixture_recent_weight', 0):.2f} recent (home/away by position)."
@@ -277,7 +280,9 @@ def _simple_waiver_md(report: Dict[str, Any]) -> str:
         prev = ""
         if a.get("previous_owners"):
             prev = f" | Prev owners: {', '.join(a['previous_owners'])}"
-        lines.append(f"- {a['name']} ({a['team']}): {reasons}{prev}")
+        profile = (a.get("score") or {}).get("profile")
+        label = f"{a['team']}, {profile}" if profile else a["team"]
+        lines.append(f"- {a['name']} ({label}): {reasons}{prev}")
     lines.append("")
     lines.append("## Drop Candidates")
     drops_by_pos = report.get("drop_candidates_by_position") or {}
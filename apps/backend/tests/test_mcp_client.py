@@ -1,4 +1,4 @@
-"""Tests for backend.mcp_client — session ID validation."""
+"""Tests for backend.mcp_client — session ID validation and tool errors."""
 
 import sys
 import types
@@ -82,3 +82,27 @@ class TestEnsureSession:
         client._session = mock_session
         client.ensure_session()
         mock_session.post.assert_not_called()
+
+
+class TestCallToolErrors:
+    """Verify call_tool() surfaces structured tool errors as RuntimeError."""
+
+    def _client_returning(self, result: dict) -> MCPClient:
+        client = MCPClient("http://localhost:8080/mcp", "")
+        client.session_id = "sess"
+        resp = MagicMock()
+        resp.raise_for_status.return_value = None
+        resp.json.return_value = {"jsonrpc": "2.0", "id": 1, "result": result}
+        client._session = MagicMock()
+        client._session.post.return_value = resp
+        return client
+
+    def test_structured_error_includes_code(self):
+        body = '{"error":{"code":"INVALID_ARGUMENT","message":"league_id is required"}}'
+        client = self._client_returning({"isError": True, "content": [{"type": "text", "text": body}]})
+        with pytest.raises(RuntimeError, match="error: INVALID_ARGUMENT: league_id is required"):
+            client.call_tool("standings", {})
+
+    def test_success_body_is_decoded(self):
+        client = self._client_returning({"content": [{"type": "text", "text": '{"gw": 3}'}]})
+        assert client.call_tool("standings", {"league_id": 1}) == {"gw": 3}
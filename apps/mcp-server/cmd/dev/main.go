@@ -2,22 +2,27 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"io/fs"
 	"log"
 	"os"
 	"path/filepath"
-	"strconv"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/clock"
 	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/fetch"
 	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/ledger"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/livestats"
 	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/model"
 	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/points"
 	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/reconcile"
 	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
 	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/summary"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/validate"
 )
 
 type GameMeta struct {
@@ -46,17 +51,65 @@ func main() {
 		reconcileOn     = flag.Bool("reconcile", true, "compare draft ledger vs snapshots and write mismatch report")
 		summaryHorizons = flag.String("summary-horizons", "5,10,20", "comma-separated horizons in GWs for summaries")
 		summaryRisks    = flag.String("summary-risks", "low,med,high", "comma-separated risk levels for summaries")
+		forceSummaries  = flag.Bool("force-summaries", false, "rebuild summaries for finished GWs even if they already exist")
+		tiebreakers     = flag.String("tiebreakers", "", "comma-separated standings tiebreakers after match points: h2h, points_for, points_diff (default points_diff,points_for); match the server's --tiebreakers for the league")
+		derivedCompact  = flag.Bool("derived-compact", false, "write derived JSON without indentation")
+		derivedGzip     = flag.Bool("derived-gzip", false, "gzip derived JSON as .json.gz (implies --derived-compact)")
+		compactLedger   = flag.Bool("derived-compact-ledger", false, "apply --derived-compact/--derived-gzip to the ledger and snapshots too")
+		recompress      = flag.Bool("recompress-derived", false, "rewrite the existing derived tree in the --derived-* format, then exit")
+		validateRaw     = flag.Bool("validate", true, "sanity-check raw data before deriving; skip leagues/GWs that fail")
+		season          = flag.String("season", "", "season label like 2025-26; data goes under raw-root/{season} and derived-root/{season} (default: the season for today's date)")
+		migrateSeason   = flag.String("migrate-season", "", "move a flat raw/derived tree into this season's directories, then exit")
+		verifyTree      = flag.Bool("verify-tree", false, "cross-check the raw tree's files against each other without fetching, print a JSON report and exit 1 if any check fails")
 	)
 	flag.Parse()
 
+	format := store.DerivedFormat{Compact: *derivedCompact, Gzip: *derivedGzip, IncludeLedger: *compactLedger}
+	store.SetDerivedFormat(format)
+	if *recompress {
+		stats, err := store.RecompressTree(*derivedRoot, format)
+		must(err)
+		log.Printf("recompressed %d files under %s: %d -> %d bytes", stats.Files, *derivedRoot, stats.BytesBefore, stats.BytesAfter)
+		return
+	}
+
+	if *migrateSeason != "" {
+		for _, root := range []string{*rawRoot, *derivedRoot} {
+			n, err := store.MigrateToSeason(root, *migrateSeason)
+			must(err)
+			log.Printf("moved %d entries under %s into %s", n, root, *migrateSeason)
+		}
+		return
+	}
+	var chain []string
+	if *tiebreakers != "" {
+		var err error
+		if chain, err = summary.ParseTiebreakers(strings.Split(*tiebreakers, ",")); err != nil {
+			log.Fatalf("--tiebreakers: %v", err)
+		}
+	}
+	clk := clock.Real
+	if *season == "" {
+		*season = store.SeasonForDate(clk.Now())
+	}
+	if !store.ValidSeason(*season) {
+		log.Fatalf("invalid season %q, want a label like 2025-26", *season)
+	}
+	var err error
+	*rawRoot, *derivedRoot, err = seasonRoots(*rawRoot, *derivedRoot, *season)
+	must(err)
+
 	st := store.NewJSONStore(*rawRoot)
+	if *verifyTree {
+		os.Exit(runVerifyTree(os.Stdout, st, *leagueID))
+	}
 	client := fetch.NewClient(st)
 	client.PrettyWrite = *pretty && !*live
 	client.Sleep = time.Duration(*sleepMS) * time.Millisecond
 	client.UseCache = !*live
 	client.DisableWrite = *live
 
-	now := time.Now()
+	now := clk.Now()
 	loc, err := time.LoadLocation("America/New_York")
 	if err != nil {
 		log.Fatal(err)
@@ -68,7 +121,16 @@ func main() {
 		log.Fatalf("invalid refresh mode: %s", mode)
 	}
 
-	scheduledActive := mode == "scheduled" && isScheduledWindow(now.In(loc))
+	scheduledActive := false
+	if mode == "scheduled" {
+		sched, err := loadRefreshSchedule(st)
+		if err != nil {
+			log.Printf("refresh schedule unavailable: %v", err)
+		} else if len(sched.Events) == 0 && len(sched.Fixtures) == 0 {
+			log.Println("no cached schedule; run with --refresh=all once to seed bootstrap-static")
+		}
+		scheduledActive = isScheduledWindow(now, loc, sched)
+	}
 	forceAll := mode == "all" || *refreshNow
 
 	// Always fetch game meta; force refresh only when needed to gate decisions.
@@ -110,11 +172,11 @@ func main() {
 			log.Println("fast refresh complete (live mode)")
 			return
 		}
-		if err := summary.BuildTransactionsSummary(st, *derivedRoot, *leagueID, game.CurrentEvent); err != nil {
+		if err := summary.BuildTransactionsSummary(st, *derivedRoot, *leagueID, game.CurrentEvent, clk); err != nil {
 			log.Printf("derive-transactions failed: %v", err)
 		}
 		if game.WaiversProcessed && game.NextEvent > game.CurrentEvent {
-			if err := summary.BuildTransactionsSummary(st, *derivedRoot, *leagueID, game.NextEvent); err != nil {
+			if err := summary.BuildTransactionsSummary(st, *derivedRoot, *leagueID, game.NextEvent, clk); err != nil {
 				log.Printf("derive-next-transactions failed: %v", err)
 			} else {
 				log.Printf("derived transactions for GW %d\n", game.NextEvent)
@@ -152,19 +214,44 @@ func main() {
 	if minGW == 0 {
 		minGW = 1
 	}
+	// A league created mid-season has no entry picks before its start_event,
+	// so entry fetches and everything derived per league start there. Live
+	// files are league-independent and still cover the whole range.
+	leagueMinGW := max(minGW, ld.StartGW())
 
 	for gw := minGW; gw <= maxGW; gw++ {
+		if gw < leagueMinGW {
+			log.Printf("Queueing GW %d live (before league start GW %d)...\n", gw, leagueMinGW)
+			continue
+		}
 		log.Printf("Queueing GW %d live + entry events...\n", gw)
 	}
-	if err := runFetchTasks(client, entryIDs, minGW, maxGW, refreshLive, refreshEntry, *workers); err != nil {
+	if err := runFetchTasks(client, entryIDs, minGW, leagueMinGW, maxGW, refreshLive, refreshEntry, *workers); err != nil {
 		log.Fatalf("fetch failed: %v", err)
 	}
 
+	// gws is the range to derive; GWs whose raw files fail validation are
+	// dropped so their previous derived files are kept.
+	gws := gwRange(leagueMinGW, maxGW)
+	if *validateRaw && !client.DisableWrite {
+		report := validate.Raw(st, *leagueID, entryIDs, leagueMinGW, maxGW, game.CurrentEvent)
+		for _, f := range report.Failures {
+			log.Printf("validate: %s", f)
+		}
+		if !report.LeagueOK() {
+			log.Fatalf("validate: league %d raw data failed league-wide checks; derived files left unchanged", *leagueID)
+		}
+		gws = dropGWs(gws, report.BadGWs())
+		if len(gws) < maxGW-leagueMinGW+1 {
+			log.Printf("validate: deriving %d of %d GWs", len(gws), maxGW-leagueMinGW+1)
+		}
+	}
+
 	if *deriveDraft {
 		if client.DisableWrite {
 			log.Println("derive-draft skipped in live mode")
 		} else {
-			must(buildDraftLedger(st, *derivedRoot, *leagueID))
+			must(buildDraftLedger(st, *derivedRoot, *leagueID, clk))
 		}
 	}
 
@@ -172,7 +259,7 @@ func main() {
 		if client.DisableWrite {
 			log.Println("derive-snapshots skipped in live mode")
 		} else {
-			must(buildEntrySnapshots(st, *derivedRoot, *leagueID, entryIDs, minGW, maxGW))
+			must(eachGW(gws, func(gw int) error { return buildEntrySnapshots(st, *derivedRoot, *leagueID, entryIDs, gw, gw, clk) }))
 		}
 	}
 
@@ -180,25 +267,27 @@ func main() {
 		if client.DisableWrite {
 			log.Println("reconcile skipped in live mode")
 		} else {
-			must(buildReconcileReports(st, *derivedRoot, *leagueID, entryIDs, minGW, maxGW))
+			must(eachGW(gws, func(gw int) error { return buildReconcileReports(st, *derivedRoot, *leagueID, entryIDs, gw, gw, clk) }))
 		}
 	}
 
 	if client.DisableWrite {
 		log.Println("derive-points skipped in live mode")
 	} else {
-		must(buildPointsResults(st, *derivedRoot, *leagueID, entryIDs, minGW, maxGW))
+		must(eachGW(gws, func(gw int) error { return buildPointsResults(st, *derivedRoot, *leagueID, entryIDs, gw, gw, clk) }))
 	}
 
 	if client.DisableWrite {
 		log.Println("derive-summaries skipped in live mode")
+	} else if len(gws) == 0 {
+		log.Println("derive-summaries skipped: no GW passed validation")
 	} else {
 		horizons, err := summary.ParseHorizons(*summaryHorizons)
 		must(err)
 		riskLevels := summary.ParseRiskLevels(*summaryRisks)
-		must(summary.BuildLeagueSummaries(st, *derivedRoot, *leagueID, ld, entryIDs, minGW, maxGW, horizons, riskLevels))
+		must(summary.BuildLeagueSummaries(st, *derivedRoot, *leagueID, ld, entryIDs, leagueMinGW, maxGW, horizons, riskLevels, summary.BuildOptions{Force: *forceSummaries, OnlyGWs: gws, Clock: clk, Tiebreakers: chain}))
 		if game.WaiversProcessed && game.NextEvent > game.CurrentEvent {
-			if err := summary.BuildTransactionsSummary(st, *derivedRoot, *leagueID, game.NextEvent); err != nil {
+			if err := summary.BuildTransactionsSummary(st, *derivedRoot, *leagueID, game.NextEvent, clk); err != nil {
 				log.Printf("derive-next-transactions failed: %v", err)
 			} else {
 				log.Printf("derived transactions for GW %d\n", game.NextEvent)
@@ -209,21 +298,34 @@ func main() {
 	log.Println("Done.")
 }
 
-// Scheduled refresh window:
-// - Tuesday after 11:00am EST
-// - Friday after 7:00pm EST
-func isScheduledWindow(t time.Time) bool {
-	switch t.Weekday() {
-	case time.Tuesday:
-		return t.Hour() > 11 || (t.Hour() == 11 && t.Minute() >= 0)
-	case time.Friday:
-		return t.Hour() > 19 || (t.Hour() == 19 && t.Minute() >= 0)
-	default:
-		return false
+func gwRange(minGW int, maxGW int) []int {
+	out := make([]int, 0, maxGW-minGW+1)
+	for gw := minGW; gw <= maxGW; gw++ {
+		out = append(out, gw)
+	}
+	return out
+}
+
+func dropGWs(gws []int, bad map[int]bool) []int {
+	out := make([]int, 0, len(gws))
+	for _, gw := range gws {
+		if !bad[gw] {
+			out = append(out, gw)
+		}
+	}
+	return out
+}
+
+func eachGW(gws []int, fn func(gw int) error) error {
+	for _, gw := range gws {
+		if err := fn(gw); err != nil {
+			return err
+		}
 	}
+	return nil
 }
 
-func buildDraftLedger(st *store.JSONStore, derivedRoot string, leagueID int) error {
+func buildDraftLedger(st *store.JSONStore, derivedRoot string, leagueID int, clk clock.Clock) error {
 	raw, err := st.ReadRaw(fmt.Sprintf("draft/%d/choices.json", leagueID))
 	if err != nil {
 		return err
@@ -234,25 +336,25 @@ func buildDraftLedger(st *store.JSONStore, derivedRoot string, leagueID int) err
 		return err
 	}
 
-	out := ledger.BuildDraftLedger(leagueID, resp.Choices)
+	out := ledger.BuildDraftLedger(leagueID, resp.Choices, clk)
 	outPath := filepath.Join(derivedRoot, fmt.Sprintf("ledger/%d/event_0.json", leagueID))
 	return ledger.WriteDraftLedger(outPath, out)
 }
 
-func buildEntrySnapshots(st *store.JSONStore, derivedRoot string, leagueID int, entryIDs []int, minGW int, maxGW int) error {
+func buildEntrySnapshots(st *store.JSONStore, derivedRoot string, leagueID int, entryIDs []int, minGW int, maxGW int, clk clock.Clock) error {
 	for gw := minGW; gw <= maxGW; gw++ {
 		for _, entryID := range entryIDs {
-			raw, err := st.ReadRaw(fmt.Sprintf("entry/%d/gw/%d.json", entryID, gw))
+			snap, err := ledger.ReadEntrySnapshot(st, leagueID, entryID, gw, clk)
+			if errors.Is(err, fs.ErrNotExist) {
+				log.Printf("entry %d has no GW %d picks; summaries will reconstruct its roster", entryID, gw)
+				continue
+			}
 			if err != nil {
 				return err
 			}
-
-			var resp ledger.EntryEventRaw
-			if err := json.Unmarshal(raw, &resp); err != nil {
-				return err
+			if snap.Missing {
+				log.Printf("entry %d has no GW %d picks (first GW %d); writing a missing snapshot", entryID, gw, snap.FirstAvailableGW)
 			}
-
-			snap := ledger.BuildEntrySnapshot(leagueID, entryID, gw, resp)
 			outPath := filepath.Join(derivedRoot, fmt.Sprintf("snapshots/%d/entry/%d/gw/%d.json", leagueID, entryID, gw))
 			if err := ledger.WriteEntrySnapshot(outPath, snap); err != nil {
 				return err
@@ -267,7 +369,11 @@ type fetchTask struct {
 	fn    func() error
 }
 
-func runFetchTasks(client *fetch.Client, entryIDs []int, minGW int, maxGW int, refreshLive bool, refreshEntry bool, workers int) error {
+// runFetchTasks fetches live data for minGW..maxGW and entry events from
+// entryMinGW, the league's first GW, onwards. An entry event the API doesn't
+// have (a GW before a mid-season replacement joined) is logged and skipped;
+// snapshot building stubs it.
+func runFetchTasks(client *fetch.Client, entryIDs []int, minGW int, entryMinGW int, maxGW int, refreshLive bool, refreshEntry bool, workers int) error {
 	tasks := make([]fetchTask, 0, (maxGW-minGW+1)*(1+len(entryIDs)))
 	for gw := minGW; gw <= maxGW; gw++ {
 		gw := gw
@@ -277,12 +383,20 @@ func runFetchTasks(client *fetch.Client, entryIDs []int, minGW int, maxGW int, r
 				return client.EventLive(gw, refreshLive)
 			},
 		})
+		if gw < entryMinGW {
+			continue
+		}
 		for _, entryID := range entryIDs {
 			entryID := entryID
 			tasks = append(tasks, fetchTask{
 				label: fmt.Sprintf("entry_event entry=%d gw=%d", entryID, gw),
 				fn: func() error {
-					return client.EntryEvent(entryID, gw, refreshEntry)
+					err := client.EntryEvent(entryID, gw, refreshEntry)
+					if errors.Is(err, fetch.ErrNotFound) {
+						log.Printf("entry_event entry=%d gw=%d: not found, skipped", entryID, gw)
+						return nil
+					}
+					return err
 				},
 			})
 		}
@@ -321,9 +435,9 @@ func runFetchTasks(client *fetch.Client, entryIDs []int, minGW int, maxGW int, r
 	return nil
 }
 
-func buildReconcileReports(st *store.JSONStore, derivedRoot string, leagueID int, entryIDs []int, minGW int, maxGW int) error {
+func buildReconcileReports(st *store.JSONStore, derivedRoot string, leagueID int, entryIDs []int, minGW int, maxGW int, clk clock.Clock) error {
 	ledgerPath := filepath.Join(derivedRoot, fmt.Sprintf("ledger/%d/event_0.json", leagueID))
-	ledgerRaw, err := os.ReadFile(ledgerPath)
+	ledgerRaw, err := store.ReadDerived(ledgerPath)
 	if err != nil {
 		return err
 	}
@@ -347,7 +461,7 @@ func buildReconcileReports(st *store.JSONStore, derivedRoot string, leagueID int
 		snapshots := make(map[int]*ledger.EntrySnapshot)
 		for _, entryID := range entryIDs {
 			snapPath := filepath.Join(derivedRoot, fmt.Sprintf("snapshots/%d/entry/%d/gw/%d.json", leagueID, entryID, gw))
-			raw, err := os.ReadFile(snapPath)
+			raw, err := store.ReadDerived(snapPath)
 			if err != nil {
 				log.Printf("snapshot missing: %s", snapPath)
 				continue
@@ -361,7 +475,13 @@ func buildReconcileReports(st *store.JSONStore, derivedRoot string, leagueID int
 			snapshots[entryID] = &snap
 		}
 
-		report := reconcile.BuildReport(leagueID, gw, &ledgerOut, transactions, trades, snapshots, entryIDs)
+		report := reconcile.BuildReport(leagueID, gw, &ledgerOut, transactions, trades, snapshots, entryIDs, clk)
+		for _, w := range report.Warnings {
+			log.Printf("reconcile GW%d: %s", gw, w)
+		}
+		for _, d := range report.Duplicates {
+			log.Printf("reconcile GW%d: element %d owned by entries %v", gw, d.Element, d.Owners)
+		}
 		outPath := filepath.Join(derivedRoot, fmt.Sprintf("reconcile/%d/gw/%d.json", leagueID, gw))
 		if err := reconcile.WriteReport(outPath, report); err != nil {
 			return err
@@ -406,50 +526,16 @@ type bootstrapResponse struct {
 	} `json:"elements"`
 }
 
-type liveResponse struct {
-	Elements map[string]struct {
-		Stats struct {
-			Minutes     int `json:"minutes"`
-			TotalPoints int `json:"total_points"`
-		} `json:"stats"`
-	} `json:"elements"`
-}
-
-func loadLiveStatsForPoints(st *store.JSONStore, gw int) (map[int]points.LiveStats, error) {
-	raw, err := st.ReadRaw(fmt.Sprintf("gw/%d/live.json", gw))
-	if err != nil {
-		return nil, err
-	}
-
-	var resp liveResponse
-	if err := json.Unmarshal(raw, &resp); err != nil {
-		return nil, err
-	}
-
-	out := make(map[int]points.LiveStats, len(resp.Elements))
-	for k, v := range resp.Elements {
-		id, err := strconv.Atoi(k)
-		if err != nil {
-			continue
-		}
-		out[id] = points.LiveStats{
-			Minutes:     v.Stats.Minutes,
-			TotalPoints: v.Stats.TotalPoints,
-		}
-	}
-	return out, nil
-}
-
-func buildPointsResults(st *store.JSONStore, derivedRoot string, leagueID int, entryIDs []int, minGW int, maxGW int) error {
+func buildPointsResults(st *store.JSONStore, derivedRoot string, leagueID int, entryIDs []int, minGW int, maxGW int, clk clock.Clock) error {
 	for gw := minGW; gw <= maxGW; gw++ {
-		liveByElement, err := loadLiveStatsForPoints(st, gw)
+		live, err := livestats.LoadGW(st, gw)
 		if err != nil {
 			return err
 		}
 
 		for _, entryID := range entryIDs {
 			snapPath := filepath.Join(derivedRoot, fmt.Sprintf("snapshots/%d/entry/%d/gw/%d.json", leagueID, entryID, gw))
-			raw, err := os.ReadFile(snapPath)
+			raw, err := store.ReadDerived(snapPath)
 			if err != nil {
 				return err
 			}
@@ -459,7 +545,7 @@ func buildPointsResults(st *store.JSONStore, derivedRoot string, leagueID int, e
 				return err
 			}
 
-			result := points.BuildResult(leagueID, entryID, gw, &snap, liveByElement)
+			result := points.BuildResult(leagueID, entryID, gw, &snap, live.Elements, clk)
 			outPath := filepath.Join(derivedRoot, fmt.Sprintf("points/%d/entry/%d/gw/%d.json", leagueID, entryID, gw))
 			if err := points.WriteResult(outPath, result); err != nil {
 				return err
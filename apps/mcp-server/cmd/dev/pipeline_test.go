@@ -0,0 +1,269 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/clock"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/fetch"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/points"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/reconcile"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/summary"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/validate"
+)
+
+// lateJoinEntry replaced a manager at GW9: the API has no events for it
+// before then, and it has no matches in GWs 1-8 either.
+const (
+	lateJoinLeague = 100
+	lateJoinEntry  = 300
+	lateJoinFirst  = 9
+	lateJoinLastGW = 10
+)
+
+// squadOf is entry e's 15 players, element ids e+1..e+15.
+func squadOf(entryID int) []int {
+	out := make([]int, 15)
+	for i := range out {
+		out[i] = entryID + i + 1
+	}
+	return out
+}
+
+func writeRawJSON(t *testing.T, st *store.JSONStore, rel string, v any) {
+	t.Helper()
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := st.WriteRaw(rel, b, false); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// writeLateJoinLeague writes the league-wide raw files: a bootstrap big
+// enough to validate, league details where entries 1 and 2 (ids 100, 200)
+// play each other in GWs 1-8 and all four play in GWs 9-10, draft choices
+// and empty transactions and trades.
+func writeLateJoinLeague(t *testing.T, st *store.JSONStore) summary.LeagueDetails {
+	t.Helper()
+	elements := make([]any, 0, 450)
+	for id := 1; id <= 450; id++ {
+		elements = append(elements, map[string]any{"id": id, "web_name": fmt.Sprintf("P%d", id), "team": 1 + id%20, "element_type": 1 + id%4})
+	}
+	teams := make([]any, 0, 20)
+	for id := 1; id <= 20; id++ {
+		teams = append(teams, map[string]any{"id": id, "short_name": fmt.Sprintf("T%d", id)})
+	}
+	writeRawJSON(t, st, "bootstrap/bootstrap-static.json", map[string]any{"elements": elements, "teams": teams, "fixtures": map[string]any{}})
+
+	match := func(gw, a, aPts, b, bPts int) map[string]any {
+		return map[string]any{"event": gw, "started": true, "finished": true,
+			"league_entry_1": a, "league_entry_1_points": aPts, "league_entry_2": b, "league_entry_2_points": bPts}
+	}
+	matches := []any{}
+	for gw := 1; gw < lateJoinFirst; gw++ {
+		matches = append(matches, match(gw, 1, 50, 2, 40))
+	}
+	matches = append(matches,
+		match(9, 1, 50, 3, 60), match(9, 2, 45, 4, 45),
+		match(10, 1, 55, 4, 30), match(10, 2, 70, 3, 20),
+	)
+	details := map[string]any{
+		"league": map[string]any{"start_event": 1},
+		"league_entries": []any{
+			map[string]any{"id": 1, "entry_id": 100, "entry_name": "Alpha"},
+			map[string]any{"id": 2, "entry_id": 200, "entry_name": "Beta"},
+			map[string]any{"id": 3, "entry_id": lateJoinEntry, "entry_name": "Gamma"},
+			map[string]any{"id": 4, "entry_id": 400, "entry_name": "Delta"},
+		},
+		"matches": matches,
+	}
+	writeRawJSON(t, st, fmt.Sprintf("league/%d/details.json", lateJoinLeague), details)
+
+	choices := []any{}
+	for _, entryID := range []int{100, 200, lateJoinEntry, 400} {
+		for i, el := range squadOf(entryID) {
+			choices = append(choices, map[string]any{"entry": entryID, "element": el, "round": i + 1})
+		}
+	}
+	writeRawJSON(t, st, fmt.Sprintf("draft/%d/choices.json", lateJoinLeague), map[string]any{"choices": choices})
+	writeRawJSON(t, st, fmt.Sprintf("league/%d/transactions.json", lateJoinLeague), map[string]any{"transactions": []any{}})
+	writeRawJSON(t, st, fmt.Sprintf("league/%d/trades.json", lateJoinLeague), map[string]any{"trades": []any{}})
+
+	raw, err := json.Marshal(details)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ld summary.LeagueDetails
+	if err := json.Unmarshal(raw, &ld); err != nil {
+		t.Fatal(err)
+	}
+	return ld
+}
+
+// lateJoinAPI serves live data for every GW and entry events, answering 404
+// for lateJoinEntry before lateJoinFirst.
+func lateJoinAPI(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/event/{gw}/live", func(w http.ResponseWriter, r *http.Request) {
+		elements := map[string]any{}
+		for id := 1; id <= 450; id++ {
+			elements[fmt.Sprint(id)] = map[string]any{"stats": map[string]any{"minutes": 90, "total_points": 2}}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"elements": elements,
+			"fixtures": []any{map[string]any{"id": 1, "team_h": 1, "team_a": 2, "started": true, "finished": true}},
+		})
+	})
+	mux.HandleFunc("/entry/{entry}/event/{gw}", func(w http.ResponseWriter, r *http.Request) {
+		var entryID, gw int
+		fmt.Sscan(r.PathValue("entry"), &entryID)
+		fmt.Sscan(r.PathValue("gw"), &gw)
+		if entryID == lateJoinEntry && gw < lateJoinFirst {
+			http.NotFound(w, r)
+			return
+		}
+		picks := []any{}
+		for i, el := range squadOf(entryID) {
+			picks = append(picks, map[string]any{"element": el, "position": i + 1})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"picks": picks})
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// TestPipeline_LateJoiningEntry runs fetch, validation and every derive step
+// for a league where one entry has no events or matches for GWs 1-8.
+func TestPipeline_LateJoiningEntry(t *testing.T) {
+	rawRoot, derivedRoot := t.TempDir(), t.TempDir()
+	st := store.NewJSONStore(rawRoot)
+	ld := writeLateJoinLeague(t, st)
+	entryIDs := []int{100, 200, lateJoinEntry, 400}
+
+	client := fetch.NewClient(st)
+	client.BaseURL = lateJoinAPI(t).URL
+	client.Sleep = 0
+	if err := runFetchTasks(client, entryIDs, 1, 1, lateJoinLastGW, true, true, 2); err != nil {
+		t.Fatalf("runFetchTasks: %v", err)
+	}
+	if st.Exists(fmt.Sprintf("entry/%d/gw/1.json", lateJoinEntry)) {
+		t.Fatal("a 404 entry event was written")
+	}
+
+	report := validate.Raw(st, lateJoinLeague, entryIDs, 1, lateJoinLastGW, lateJoinLastGW)
+	if len(report.Failures) != 0 {
+		t.Fatalf("validate failures: %v", report.Failures)
+	}
+
+	gws := gwRange(1, lateJoinLastGW)
+	clk := clock.Fixed(time.Date(2025, 11, 1, 9, 0, 0, 0, time.UTC))
+	if err := buildDraftLedger(st, derivedRoot, lateJoinLeague, clk); err != nil {
+		t.Fatalf("buildDraftLedger: %v", err)
+	}
+	steps := map[string]func(*store.JSONStore, string, int, []int, int, int, clock.Clock) error{
+		"snapshots": buildEntrySnapshots,
+		"reconcile": buildReconcileReports,
+		"points":    buildPointsResults,
+	}
+	for _, name := range []string{"snapshots", "reconcile", "points"} {
+		step := steps[name]
+		if err := eachGW(gws, func(gw int) error { return step(st, derivedRoot, lateJoinLeague, entryIDs, gw, gw, clk) }); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+	}
+	if err := summary.BuildLeagueSummaries(st, derivedRoot, lateJoinLeague, ld, entryIDs, 1, lateJoinLastGW, []int{5}, []string{"med"}, summary.BuildOptions{Clock: clk}); err != nil {
+		t.Fatalf("BuildLeagueSummaries: %v", err)
+	}
+
+	readDerived := func(rel string, v any) {
+		t.Helper()
+		b, err := store.ReadDerived(filepath.Join(derivedRoot, rel))
+		if err != nil {
+			t.Fatalf("read %s: %v", rel, err)
+		}
+		if err := json.Unmarshal(b, v); err != nil {
+			t.Fatalf("parse %s: %v", rel, err)
+		}
+	}
+
+	var pts points.Result
+	readDerived(fmt.Sprintf("points/%d/entry/%d/gw/5.json", lateJoinLeague, lateJoinEntry), &pts)
+	if !pts.MissingSnapshot || pts.TotalPoints != 0 {
+		t.Errorf("GW5 points = %+v, want a zero missing snapshot", pts)
+	}
+	var joined points.Result
+	readDerived(fmt.Sprintf("points/%d/entry/%d/gw/9.json", lateJoinLeague, lateJoinEntry), &joined)
+	if joined.MissingSnapshot || joined.TotalPoints != 22 {
+		t.Errorf("GW9 points = %+v, want 11 starters at 2", joined)
+	}
+
+	var rec reconcile.Report
+	readDerived(fmt.Sprintf("reconcile/%d/gw/5.json", lateJoinLeague), &rec)
+	if len(rec.Entries) != 1 || rec.Entries[0].EntryID != lateJoinEntry || rec.Entries[0].FirstAvailableGW != lateJoinFirst {
+		t.Errorf("GW5 reconcile entries = %+v, want entry %d first available at GW%d", rec.Entries, lateJoinEntry, lateJoinFirst)
+	}
+
+	var week summary.LeagueWeekSummary
+	readDerived(fmt.Sprintf("summary/league/%d/gw/5.json", lateJoinLeague), &week)
+	for _, e := range week.Entries {
+		late := e.EntryID == lateJoinEntry
+		if e.MissingSnapshot != late {
+			t.Errorf("GW5 entry %d missing_snapshot = %v", e.EntryID, e.MissingSnapshot)
+		}
+		if wantMissingOpp := e.EntryID == lateJoinEntry || e.EntryID == 400; e.MissingOpponent != wantMissingOpp {
+			t.Errorf("GW5 entry %d missing_opponent = %v, want %v", e.EntryID, e.MissingOpponent, wantMissingOpp)
+		}
+		if late && (e.Points.Starters != 0 || len(e.Roster) != 0) {
+			t.Errorf("GW5 late entry = %+v, want no roster and zero points", e)
+		}
+	}
+
+	var standings summary.StandingsSummary
+	readDerived(fmt.Sprintf("summary/standings/%d/gw/%d.json", lateJoinLeague, lateJoinLastGW), &standings)
+	want := []struct{ entry, w, d, l, pts int }{
+		{100, 9, 0, 1, 27}, {200, 1, 1, 8, 4}, {lateJoinEntry, 1, 0, 1, 3}, {400, 0, 1, 1, 1},
+	}
+	if len(standings.Rows) != len(want) {
+		t.Fatalf("standings rows = %+v", standings.Rows)
+	}
+	for i, w := range want {
+		r := standings.Rows[i]
+		if r.EntryID != w.entry || r.Wins != w.w || r.Draws != w.d || r.Losses != w.l || r.MatchPoints != w.pts {
+			t.Errorf("row %d = %+v, want entry %d %d-%d-%d on %d", i, r, w.entry, w.w, w.d, w.l, w.pts)
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(derivedRoot, fmt.Sprintf("summary/lineup_efficiency/%d/gw/5.json", lateJoinLeague))); err != nil {
+		t.Errorf("lineup efficiency not written: %v", err)
+	}
+
+	// Every derived file is stamped by the run's clock, not the wall clock.
+	for _, rel := range []string{
+		fmt.Sprintf("ledger/%d/event_0.json", lateJoinLeague),
+		fmt.Sprintf("snapshots/%d/entry/%d/gw/5.json", lateJoinLeague, lateJoinEntry),
+		fmt.Sprintf("snapshots/%d/entry/%d/gw/9.json", lateJoinLeague, lateJoinEntry),
+		fmt.Sprintf("points/%d/entry/%d/gw/9.json", lateJoinLeague, lateJoinEntry),
+		fmt.Sprintf("reconcile/%d/gw/5.json", lateJoinLeague),
+		fmt.Sprintf("ownership/%d.json", lateJoinLeague),
+		"positions/gw/5.json",
+	} {
+		var stamped struct {
+			GeneratedAtUTC string `json:"generated_at_utc"`
+		}
+		readDerived(rel, &stamped)
+		if stamped.GeneratedAtUTC != "2025-11-01T09:00:00Z" {
+			t.Errorf("%s generated_at_utc = %q, want the run's clock", rel, stamped.GeneratedAtUTC)
+		}
+	}
+}
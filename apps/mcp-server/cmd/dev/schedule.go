@@ -0,0 +1,210 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/fixtures"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+)
+
+const (
+	// matchDuration approximates kickoff to final whistle, including half-time
+	// and stoppage. Kickoffs closer together than this form one cluster.
+	matchDuration = 2 * time.Hour
+	// postClusterWindow is how long after a cluster ends we keep refreshing so
+	// live points and bonus settle.
+	postClusterWindow = 2 * time.Hour
+	// postWaiversWindow is how long after waivers_time we refresh to pick up
+	// processed claims.
+	postWaiversWindow = time.Hour
+	// morningAfterStart/End bound the local-time window on the day after a
+	// GW's last fixture, when final points and bonus have been confirmed.
+	morningAfterStart = 8
+	morningAfterEnd   = 12
+)
+
+// scheduleEvent is the timing for one gameweek from bootstrap events.
+type scheduleEvent struct {
+	ID          int
+	WaiversTime time.Time
+}
+
+// scheduleFixture is one fixture kickoff.
+type scheduleFixture struct {
+	ID      int
+	Event   int
+	Kickoff time.Time
+}
+
+// refreshSchedule is the parsed event/fixture data used to gate scheduled
+// refreshes.
+type refreshSchedule struct {
+	Events   []scheduleEvent
+	Fixtures []scheduleFixture
+}
+
+// isScheduledWindow reports whether now falls in a refresh window derived
+// from the real schedule:
+//   - within postClusterWindow after each cluster of overlapping fixtures ends
+//   - the morning (loc time) after a gameweek's last fixture
+//   - within postWaiversWindow after an event's waivers_time
+func isScheduledWindow(now time.Time, loc *time.Location, sched refreshSchedule) bool {
+	for _, ev := range sched.Events {
+		if ev.WaiversTime.IsZero() {
+			continue
+		}
+		if !now.Before(ev.WaiversTime) && now.Before(ev.WaiversTime.Add(postWaiversWindow)) {
+			return true
+		}
+	}
+
+	byEvent := make(map[int][]time.Time)
+	for _, f := range sched.Fixtures {
+		if f.Kickoff.IsZero() {
+			continue
+		}
+		byEvent[f.Event] = append(byEvent[f.Event], f.Kickoff)
+	}
+	for _, kickoffs := range byEvent {
+		ends := fixtureClusterEnds(kickoffs)
+		for _, end := range ends {
+			if !now.Before(end) && now.Before(end.Add(postClusterWindow)) {
+				return true
+			}
+		}
+		if len(ends) > 0 && inMorningAfter(now, loc, ends[len(ends)-1]) {
+			return true
+		}
+	}
+	return false
+}
+
+// fixtureClusterEnds groups kickoffs whose matches overlap and returns the
+// estimated end time of each cluster in chronological order.
+func fixtureClusterEnds(kickoffs []time.Time) []time.Time {
+	sorted := append([]time.Time(nil), kickoffs...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Before(sorted[j]) })
+
+	var ends []time.Time
+	for _, k := range sorted {
+		end := k.Add(matchDuration)
+		if n := len(ends); n > 0 && !k.After(ends[n-1]) {
+			if end.After(ends[n-1]) {
+				ends[n-1] = end
+			}
+			continue
+		}
+		ends = append(ends, end)
+	}
+	return ends
+}
+
+// inMorningAfter reports whether now is between morningAfterStart and
+// morningAfterEnd on the local calendar day after lastEnd.
+func inMorningAfter(now time.Time, loc *time.Location, lastEnd time.Time) bool {
+	end := lastEnd.In(loc)
+	y, m, d := end.Date()
+	start := time.Date(y, m, d+1, morningAfterStart, 0, 0, 0, loc)
+	stop := time.Date(y, m, d+1, morningAfterEnd, 0, 0, 0, loc)
+	return !now.Before(start) && now.Before(stop)
+}
+
+// loadRefreshSchedule builds a refreshSchedule from cached bootstrap-static
+// and, for the current GW, the cached live.json (bootstrap drops a GW's
+// fixtures once it starts). Missing files yield an empty schedule.
+func loadRefreshSchedule(st *store.JSONStore) (refreshSchedule, error) {
+	var sched refreshSchedule
+	if !st.Exists("bootstrap/bootstrap-static.json") {
+		return sched, nil
+	}
+	raw, err := st.ReadRaw("bootstrap/bootstrap-static.json")
+	if err != nil {
+		return sched, err
+	}
+	var bs struct {
+		Events struct {
+			Current int `json:"current"`
+			Data    []struct {
+				ID          int    `json:"id"`
+				WaiversTime string `json:"waivers_time"`
+			} `json:"data"`
+		} `json:"events"`
+	}
+	if err := json.Unmarshal(raw, &bs); err != nil {
+		return sched, fmt.Errorf("parse bootstrap schedule: %w", err)
+	}
+	for _, ev := range bs.Events.Data {
+		sched.Events = append(sched.Events, scheduleEvent{
+			ID:          ev.ID,
+			WaiversTime: parseScheduleTime(ev.WaiversTime),
+		})
+	}
+
+	seen := make(map[int]bool)
+	add := func(gw int, list []scheduleFixtureRaw) {
+		for _, f := range list {
+			if seen[f.ID] {
+				continue
+			}
+			seen[f.ID] = true
+			event := f.Event
+			if event == 0 {
+				event = gw
+			}
+			sched.Fixtures = append(sched.Fixtures, scheduleFixture{
+				ID:      f.ID,
+				Event:   event,
+				Kickoff: parseScheduleTime(f.KickoffTime),
+			})
+		}
+	}
+
+	current := bs.Events.Current
+	if current > 0 && st.Exists(fmt.Sprintf("gw/%d/live.json", current)) {
+		liveRaw, err := st.ReadRaw(fmt.Sprintf("gw/%d/live.json", current))
+		if err != nil {
+			return sched, err
+		}
+		var live struct {
+			Fixtures []scheduleFixtureRaw `json:"fixtures"`
+		}
+		if err := json.Unmarshal(liveRaw, &live); err != nil {
+			return sched, fmt.Errorf("parse gw/%d/live.json fixtures: %w", current, err)
+		}
+		add(current, live.Fixtures)
+	}
+	set, err := fixtures.Parse(raw)
+	if err != nil {
+		return sched, err
+	}
+	for gw, list := range set.ByEvent() {
+		raws := make([]scheduleFixtureRaw, 0, len(list))
+		for _, f := range list {
+			raws = append(raws, scheduleFixtureRaw{ID: f.ID, Event: f.Event, KickoffTime: f.Kickoff})
+		}
+		add(gw, raws)
+	}
+	return sched, nil
+}
+
+type scheduleFixtureRaw struct {
+	ID          int    `json:"id"`
+	Event       int    `json:"event"`
+	KickoffTime string `json:"kickoff_time"`
+}
+
+// parseScheduleTime parses an RFC 3339 API timestamp; empty or malformed
+// values return the zero time, which isScheduledWindow ignores.
+func parseScheduleTime(s string) time.Time {
+	if s == "" {
+		return time.Time{}
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
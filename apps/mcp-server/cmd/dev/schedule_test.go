@@ -0,0 +1,155 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+)
+
+func mustLoadNY(t *testing.T) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("tzdata unavailable: %v", err)
+	}
+	return loc
+}
+
+func utc(s string) time.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestFixtureClusterEnds(t *testing.T) {
+	// Saturday 12:30, two 15:00s, 17:30: the 15:00s overlap the 12:30 match
+	// and 17:30 starts after it all ends, giving two clusters.
+	ends := fixtureClusterEnds([]time.Time{
+		utc("2025-10-04T17:30:00Z"),
+		utc("2025-10-04T12:30:00Z"),
+		utc("2025-10-04T14:00:00Z"),
+		utc("2025-10-04T14:00:00Z"),
+	})
+	if len(ends) != 2 {
+		t.Fatalf("clusters = %d, want 2 (%v)", len(ends), ends)
+	}
+	if !ends[0].Equal(utc("2025-10-04T16:00:00Z")) || !ends[1].Equal(utc("2025-10-04T19:30:00Z")) {
+		t.Errorf("ends = %v", ends)
+	}
+}
+
+func TestIsScheduledWindow(t *testing.T) {
+	loc := mustLoadNY(t)
+
+	t.Run("FridayNightKickoff", func(t *testing.T) {
+		sched := refreshSchedule{Fixtures: []scheduleFixture{
+			{ID: 1, Event: 7, Kickoff: utc("2025-10-03T19:00:00Z")}, // Fri 20:00 BST
+			{ID: 2, Event: 7, Kickoff: utc("2025-10-04T14:00:00Z")},
+		}}
+		cases := []struct {
+			now  string
+			want bool
+		}{
+			{"2025-10-03T20:30:00Z", false}, // mid-match
+			{"2025-10-03T21:15:00Z", true},  // just after full time
+			{"2025-10-03T23:30:00Z", false}, // window closed
+			{"2025-10-04T16:30:00Z", true},  // after the Saturday game
+		}
+		for _, c := range cases {
+			if got := isScheduledWindow(utc(c.now), loc, sched); got != c.want {
+				t.Errorf("at %s: got %v, want %v", c.now, got, c.want)
+			}
+		}
+	})
+
+	t.Run("MidweekDouble", func(t *testing.T) {
+		// GW 12 plays Saturday and again Tuesday/Wednesday; every cluster
+		// opens a window and the morning after Wednesday is the final one.
+		sched := refreshSchedule{Fixtures: []scheduleFixture{
+			{ID: 1, Event: 12, Kickoff: utc("2025-11-29T15:00:00Z")},
+			{ID: 2, Event: 12, Kickoff: utc("2025-12-02T19:30:00Z")},
+			{ID: 3, Event: 12, Kickoff: utc("2025-12-02T20:15:00Z")},
+			{ID: 4, Event: 12, Kickoff: utc("2025-12-03T20:00:00Z")},
+		}}
+		cases := []struct {
+			now  string
+			want bool
+		}{
+			{"2025-11-29T17:30:00Z", true},  // after Saturday
+			{"2025-12-02T22:00:00Z", false}, // Tuesday games still running
+			{"2025-12-02T23:00:00Z", true},  // after Tuesday cluster
+			{"2025-12-03T22:30:00Z", true},  // after Wednesday
+			{"2025-12-04T14:00:00Z", true},  // Thu 09:00 ET: morning after
+			{"2025-12-04T18:00:00Z", false}, // Thu 13:00 ET
+			{"2025-11-30T14:00:00Z", false}, // Saturday wasn't the GW's last fixture
+		}
+		for _, c := range cases {
+			if got := isScheduledWindow(utc(c.now), loc, sched); got != c.want {
+				t.Errorf("at %s: got %v, want %v", c.now, got, c.want)
+			}
+		}
+	})
+
+	t.Run("AfterWaivers", func(t *testing.T) {
+		sched := refreshSchedule{Events: []scheduleEvent{
+			{ID: 8, WaiversTime: utc("2025-10-16T23:00:00Z")},
+		}}
+		if !isScheduledWindow(utc("2025-10-16T23:30:00Z"), loc, sched) {
+			t.Error("expected window 30m after waivers")
+		}
+		if isScheduledWindow(utc("2025-10-16T22:30:00Z"), loc, sched) {
+			t.Error("unexpected window before waivers")
+		}
+		if isScheduledWindow(utc("2025-10-17T00:30:00Z"), loc, sched) {
+			t.Error("unexpected window 90m after waivers")
+		}
+	})
+
+	t.Run("EmptySchedule", func(t *testing.T) {
+		if isScheduledWindow(utc("2025-10-07T16:00:00Z"), loc, refreshSchedule{}) {
+			t.Error("empty schedule should never be active")
+		}
+	})
+}
+
+func TestLoadRefreshSchedule(t *testing.T) {
+	dir := t.TempDir()
+	write := func(rel, body string) {
+		path := filepath.Join(dir, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("bootstrap/bootstrap-static.json", `{
+		"events": {"current": 7, "data": [
+			{"id": 7, "waivers_time": "2025-10-02T23:00:00Z"},
+			{"id": 8, "waivers_time": "2025-10-16T23:00:00Z"}
+		]},
+		"fixtures": {"8": [{"id": 20, "event": 8, "kickoff_time": "2025-10-18T11:30:00Z"}]}
+	}`)
+	write("gw/7/live.json", `{"fixtures": [{"id": 10, "event": 7, "kickoff_time": "2025-10-03T19:00:00Z"}]}`)
+
+	sched, err := loadRefreshSchedule(store.NewJSONStore(dir))
+	if err != nil {
+		t.Fatalf("loadRefreshSchedule: %v", err)
+	}
+	if len(sched.Events) != 2 || !sched.Events[1].WaiversTime.Equal(utc("2025-10-16T23:00:00Z")) {
+		t.Errorf("events = %+v", sched.Events)
+	}
+	if len(sched.Fixtures) != 2 {
+		t.Fatalf("fixtures = %+v, want current GW from live.json plus GW 8", sched.Fixtures)
+	}
+
+	empty, err := loadRefreshSchedule(store.NewJSONStore(t.TempDir()))
+	if err != nil || len(empty.Events) != 0 {
+		t.Errorf("missing bootstrap: sched=%+v err=%v", empty, err)
+	}
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"log"
+	"path/filepath"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+)
+
+// seasonRoots returns the raw and derived directories a run for season works
+// in. A flat tree that holds season, or whose season can't be told from its
+// bootstrap, stays flat. A flat tree from another season is first archived
+// into its own season directory so the new season doesn't overwrite it.
+func seasonRoots(rawRoot string, derivedRoot string, season string) (string, string, error) {
+	if flat, ok := store.FlatSeason(rawRoot); ok && flat != season {
+		for _, root := range []string{rawRoot, derivedRoot} {
+			if _, err := store.MigrateToSeason(root, flat); err != nil {
+				return "", "", err
+			}
+		}
+		log.Printf("archived the %s season under %s and %s", flat, filepath.Join(rawRoot, flat), filepath.Join(derivedRoot, flat))
+	}
+	if store.IsFlat(rawRoot) {
+		return rawRoot, derivedRoot, nil
+	}
+	return filepath.Join(rawRoot, season), filepath.Join(derivedRoot, season), nil
+}
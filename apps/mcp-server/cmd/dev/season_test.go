@@ -0,0 +1,59 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeSeasonBootstrap(t *testing.T, root string, firstDeadline string) {
+	t.Helper()
+	path := filepath.Join(root, "bootstrap", "bootstrap-static.json")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	body := `{"events": {"data": [{"id": 1, "deadline_time": "` + firstDeadline + `"}]}}`
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSeasonRoots(t *testing.T) {
+	t.Run("flat tree of the same season stays flat", func(t *testing.T) {
+		raw, derived := t.TempDir(), t.TempDir()
+		writeSeasonBootstrap(t, raw, "2025-08-15T17:30:00Z")
+		gotRaw, gotDerived, err := seasonRoots(raw, derived, "2025-26")
+		if err != nil || gotRaw != raw || gotDerived != derived {
+			t.Errorf("roots = %s, %s, %v; want the flat roots", gotRaw, gotDerived, err)
+		}
+	})
+
+	t.Run("flat tree of an earlier season is archived", func(t *testing.T) {
+		raw, derived := t.TempDir(), t.TempDir()
+		writeSeasonBootstrap(t, raw, "2024-08-16T17:30:00Z")
+		if err := os.MkdirAll(filepath.Join(derived, "summary"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		gotRaw, gotDerived, err := seasonRoots(raw, derived, "2025-26")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if gotRaw != filepath.Join(raw, "2025-26") || gotDerived != filepath.Join(derived, "2025-26") {
+			t.Errorf("roots = %s, %s; want the 2025-26 dirs", gotRaw, gotDerived)
+		}
+		if _, err := os.Stat(filepath.Join(raw, "2024-25", "bootstrap", "bootstrap-static.json")); err != nil {
+			t.Errorf("raw not archived: %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(derived, "2024-25", "summary")); err != nil {
+			t.Errorf("derived not archived: %v", err)
+		}
+	})
+
+	t.Run("empty root starts the season layout", func(t *testing.T) {
+		raw, derived := t.TempDir(), t.TempDir()
+		gotRaw, _, err := seasonRoots(raw, derived, "2025-26")
+		if err != nil || gotRaw != filepath.Join(raw, "2025-26") {
+			t.Errorf("raw root = %s, %v", gotRaw, err)
+		}
+	})
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/validate"
+)
+
+// Exit codes for --verify-tree, for cron and CI.
+const (
+	verifyOK     = 0
+	verifyFailed = 1
+	verifyError  = 2
+)
+
+// runVerifyTree writes validate.Tree's report for leagueID to w as JSON and
+// returns the exit code: verifyFailed when any check fails, verifyError when
+// the report can't be written. It only reads; fixing what it finds is left
+// to the operator.
+func runVerifyTree(w io.Writer, st *store.JSONStore, leagueID int) int {
+	report := validate.Tree(st, leagueID)
+	for _, f := range report.Failures {
+		log.Printf("verify-tree: %s", f)
+	}
+	b, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		log.Printf("verify-tree: %v", err)
+		return verifyError
+	}
+	if _, err := w.Write(append(b, '\n')); err != nil {
+		log.Printf("verify-tree: %v", err)
+		return verifyError
+	}
+	if !report.OK() {
+		log.Printf("verify-tree: %d checks failed across %d files", len(report.Failures), report.FilesChecked)
+		return verifyFailed
+	}
+	return verifyOK
+}
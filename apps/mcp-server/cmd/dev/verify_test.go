@@ -0,0 +1,54 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/validate"
+)
+
+func TestRunVerifyTree(t *testing.T) {
+	st := store.NewJSONStore(t.TempDir())
+	write := func(rel string, v any) {
+		t.Helper()
+		b, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := st.WriteRaw(rel, b, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("bootstrap/bootstrap-static.json", map[string]any{"elements": []any{map[string]any{"id": 1}}})
+	write("game/game.json", map[string]any{"current_event": 1})
+	write("gw/1/live.json", map[string]any{"elements": map[string]any{"1": map[string]any{}}})
+	write("league/9/details.json", map[string]any{
+		"league_entries": []any{map[string]any{"id": 1, "entry_id": 100}},
+		"matches":        []any{map[string]any{"event": 1, "league_entry_1": 1, "league_entry_2": 1}},
+	})
+	write("league/9/transactions.json", map[string]any{"transactions": []any{}})
+	write("league/9/trades.json", map[string]any{"trades": []any{}})
+	write("entry/100/gw/1.json", map[string]any{"picks": []any{map[string]any{"element": 1}}})
+
+	var out bytes.Buffer
+	if code := runVerifyTree(&out, st, 9); code != verifyOK {
+		t.Fatalf("exit code = %d on a consistent tree: %s", code, out.String())
+	}
+
+	// A GW2 pick with no GW2 live.json, as after syncing entry files ahead
+	// of the live ones.
+	write("entry/100/gw/2.json", map[string]any{"picks": []any{map[string]any{"element": 1}}})
+	out.Reset()
+	if code := runVerifyTree(&out, st, 9); code != verifyFailed {
+		t.Fatalf("exit code = %d, want %d", code, verifyFailed)
+	}
+	var report validate.TreeReport
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatalf("report isn't JSON: %v\n%s", err, out.String())
+	}
+	if len(report.Failures) != 1 || report.Failures[0].Check != "live_missing" || report.Failures[0].GW != 2 {
+		t.Errorf("failures = %+v, want GW2 live_missing", report.Failures)
+	}
+}
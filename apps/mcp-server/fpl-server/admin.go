@@ -0,0 +1,427 @@
+package main
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/clock"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/summary"
+)
+
+// adminKeyEnv names the key the /admin routes require. It is separate from
+// FPL_MCP_API_KEY so a client that can call tools can't delete or rebuild
+// derived data.
+const adminKeyEnv = "FPL_MCP_ADMIN_KEY"
+
+// Summary families POST /admin/recompute can rebuild.
+const (
+	familyLeague           = "league"
+	familyFixtures         = "fixtures"
+	familyOptimalStandings = "optimal_standings"
+)
+
+var recomputeFamilies = []string{familyLeague, familyFixtures, familyOptimalStandings}
+
+// leagueGWSummaryDirs are the per-GW families BuildLeagueSummaries writes
+// as summary/{dir}/{league}/gw/{gw}.json.
+var leagueGWSummaryDirs = []string{"league", "matchup", "standings", "transactions", "lineup_efficiency", "ownership_scarcity", "strength_of_schedule"}
+
+// leagueLocks holds one lock per derived root and league. Summaries computed
+// on demand take it shared; admin recomputes and deletes take it exclusively,
+// so neither sees the other's files half-written or half-removed.
+var leagueLocks leagueLockSet
+
+type leagueLockSet struct {
+	mu    sync.Mutex
+	locks map[string]*sync.RWMutex
+}
+
+func (s *leagueLockSet) get(derivedRoot string, leagueID int) *sync.RWMutex {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.locks == nil {
+		s.locks = make(map[string]*sync.RWMutex)
+	}
+	key := fmt.Sprintf("%s|%d", derivedRoot, leagueID)
+	l, ok := s.locks[key]
+	if !ok {
+		l = &sync.RWMutex{}
+		s.locks[key] = l
+	}
+	return l
+}
+
+// keyAuth wraps handlers so they only run for requests carrying key in
+// header or as a bearer token. An empty key lets every request through.
+func keyAuth(key string, header string) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if key == "" {
+				next(w, r)
+				return
+			}
+			got := strings.TrimSpace(r.Header.Get(header))
+			if got == "" {
+				if authz := r.Header.Get("Authorization"); strings.HasPrefix(strings.ToLower(authz), "bearer ") {
+					got = strings.TrimSpace(authz[7:])
+				}
+			}
+			if subtle.ConstantTimeCompare([]byte(got), []byte(key)) != 1 {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusUnauthorized)
+				w.Write([]byte(`{"error":"unauthorized"}`))
+				return
+			}
+			next(w, r)
+		}
+	}
+}
+
+// registerAdminRoutes adds the /admin routes to mux behind adminKey, which
+// must not be empty: without it the routes aren't served at all.
+func registerAdminRoutes(mux *http.ServeMux, cfg ServerConfig, adminKey string, header string) {
+	auth := keyAuth(adminKey, header)
+	mux.HandleFunc("/admin/recompute", auth(adminRecomputeHandler(cfg)))
+	mux.HandleFunc("/admin/derived", auth(adminDerivedHandler(cfg)))
+}
+
+func writeAdminJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		http.Error(w, `{"error":"failed to marshal response"}`, http.StatusInternalServerError)
+		return
+	}
+	w.Write(b)
+}
+
+func failAdmin(w http.ResponseWriter, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(httpStatusFor(err))
+	w.Write(marshalToolError(err))
+}
+
+// RecomputeRequest is the POST /admin/recompute body. Either gw or
+// from_gw..to_gw picks the GWs; families defaults to all of
+// recomputeFamilies.
+type RecomputeRequest struct {
+	LeagueID int      `json:"league_id"`
+	GW       int      `json:"gw"`
+	FromGW   int      `json:"from_gw"`
+	ToGW     int      `json:"to_gw"`
+	Families []string `json:"families"`
+}
+
+// RecomputeResult reports what a recompute removed and rebuilt. Deleted
+// paths are relative to the league's derived root.
+type RecomputeResult struct {
+	LeagueID   int      `json:"league_id"`
+	FromGW     int      `json:"from_gw"`
+	ToGW       int      `json:"to_gw"`
+	Families   []string `json:"families"`
+	Deleted    []string `json:"deleted"`
+	DurationMS int64    `json:"duration_ms"`
+}
+
+// validate fills in the range and families and rejects anything it can't
+// rebuild.
+func (req *RecomputeRequest) validate() error {
+	if req.LeagueID <= 0 {
+		return invalidArgumentf("league_id is required")
+	}
+	if req.GW != 0 {
+		if req.FromGW != 0 || req.ToGW != 0 {
+			return invalidArgumentf("give gw or from_gw/to_gw, not both")
+		}
+		req.FromGW, req.ToGW = req.GW, req.GW
+	}
+	if req.FromGW <= 0 || req.ToGW < req.FromGW {
+		return invalidArgumentf("gw, or from_gw <= to_gw, is required")
+	}
+	if len(req.Families) == 0 {
+		req.Families = recomputeFamilies
+	}
+	for _, f := range req.Families {
+		if !slices.Contains(recomputeFamilies, f) {
+			return invalidArgumentf("unknown family %q (want %s)", f, strings.Join(recomputeFamilies, ", "))
+		}
+	}
+	return nil
+}
+
+// adminRecomputeHandler serves POST /admin/recompute: it deletes the chosen
+// families' files for the GWs and builds them again, holding the league's
+// lock throughout.
+func adminRecomputeHandler(cfg ServerConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", "POST")
+			http.Error(w, `{"error":"method not allowed"}`, http.StatusMethodNotAllowed)
+			return
+		}
+		var req RecomputeRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			failAdmin(w, invalidArgumentf("invalid request body: %v", err))
+			return
+		}
+		if err := req.validate(); err != nil {
+			failAdmin(w, err)
+			return
+		}
+		out, err := recompute(cfg.forLeague(req.LeagueID), req)
+		if err != nil {
+			failAdmin(w, err)
+			return
+		}
+		writeAdminJSON(w, out)
+	}
+}
+
+// recompute does the work of POST /admin/recompute against cfg, already
+// scoped to the league.
+func recompute(cfg ServerConfig, req RecomputeRequest) (RecomputeResult, error) {
+	start := time.Now()
+	lock := leagueLocks.get(cfg.DerivedRoot, req.LeagueID)
+	lock.Lock()
+	defer lock.Unlock()
+
+	out := RecomputeResult{LeagueID: req.LeagueID, FromGW: req.FromGW, ToGW: req.ToGW, Families: req.Families, Deleted: make([]string, 0)}
+	root := cfg.DerivedRoot
+	patterns := make([]string, 0)
+	for gw := req.FromGW; gw <= req.ToGW; gw++ {
+		for _, family := range req.Families {
+			patterns = append(patterns, familyPatterns(family, req.LeagueID, gw)...)
+		}
+	}
+	for _, p := range patterns {
+		matches, err := filepath.Glob(filepath.Join(root, p))
+		if err != nil {
+			return out, err
+		}
+		for _, m := range matches {
+			if err := os.Remove(m); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return out, err
+			}
+			rel, _ := filepath.Rel(root, m)
+			out.Deleted = append(out.Deleted, filepath.ToSlash(rel))
+		}
+	}
+	sort.Strings(out.Deleted)
+
+	st := store.NewJSONStore(cfg.RawRoot)
+	h := []int{5}
+	for _, family := range req.Families {
+		var err error
+		switch family {
+		case familyLeague:
+			err = rebuildLeagueFamily(st, root, req.LeagueID, req.FromGW, req.ToGW, h, cfg.Tiebreakers[req.LeagueID], cfg.Clock)
+		case familyFixtures:
+			for gw := req.FromGW; gw <= req.ToGW && err == nil; gw++ {
+				err = summary.BuildFixturesSummary(st, root, req.LeagueID, gw, h, cfg.Clock)
+			}
+		case familyOptimalStandings:
+			for gw := req.FromGW; gw <= req.ToGW && err == nil; gw++ {
+				err = buildOptimalStandingsSummary(st, root, req.LeagueID, gw, cfg.Clock)
+			}
+		}
+		if err != nil {
+			return out, fmt.Errorf("rebuild %s: %w", family, err)
+		}
+	}
+	out.DurationMS = time.Since(start).Milliseconds()
+	return out, nil
+}
+
+// familyPatterns are the globs, relative to the derived root, that match a
+// family's files for gw in any derived format.
+func familyPatterns(family string, leagueID int, gw int) []string {
+	switch family {
+	case familyLeague:
+		out := make([]string, 0, len(leagueGWSummaryDirs)+1)
+		for _, dir := range leagueGWSummaryDirs {
+			out = append(out, fmt.Sprintf("summary/%s/%d/gw/%d.json*", dir, leagueID, gw))
+		}
+		return append(out, fmt.Sprintf("summary/waiver_targets/%d/gw/%d_h*", leagueID, gw))
+	case familyFixtures:
+		return []string{fmt.Sprintf("summary/fixtures/%d/from_gw/%d_h*", leagueID, gw)}
+	case familyOptimalStandings:
+		return []string{fmt.Sprintf("summary/optimal_standings/%d/through_gw/%d.json*", leagueID, gw)}
+	}
+	return nil
+}
+
+// rebuildLeagueFamily forces BuildLeagueSummaries over fromGW..toGW, first
+// deriving the ledger and snapshots it reads if they are missing.
+func rebuildLeagueFamily(st *store.JSONStore, root string, leagueID int, fromGW int, toGW int, h []int, tiebreakers []string, clk clock.Clock) error {
+	ld, entryIDs, err := loadLeagueDetails(st, leagueID)
+	if err != nil {
+		return err
+	}
+	if err := ensureLedger(st, root, leagueID, clk); err != nil {
+		return err
+	}
+	for _, entryID := range entryIDs {
+		if err := ensureSnapshots(st, root, leagueID, []int{entryID}, fromGW, toGW, clk); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	return summary.BuildLeagueSummaries(st, root, leagueID, ld, entryIDs, fromGW, toGW, h, []string{"low", "med", "high"}, summary.BuildOptions{Force: true, Clock: clk, Tiebreakers: tiebreakers})
+}
+
+// DerivedFile is one file in a GET /admin/derived listing.
+type DerivedFile struct {
+	Path        string `json:"path"`
+	SizeBytes   int64  `json:"size_bytes"`
+	ModifiedUTC string `json:"modified_utc"`
+}
+
+// DerivedListing is the GET /admin/derived response; DELETE returns the
+// same shape for the files it removed.
+type DerivedListing struct {
+	LeagueID   int           `json:"league_id"`
+	Prefix     string        `json:"prefix,omitempty"`
+	Files      []DerivedFile `json:"files"`
+	TotalBytes int64         `json:"total_bytes"`
+}
+
+// adminDerivedHandler serves GET /admin/derived?league_id=N[&prefix=P],
+// listing the league's derived files, and DELETE with the same query,
+// removing them. DELETE requires a prefix.
+func adminDerivedHandler(cfg ServerConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodDelete {
+			w.Header().Set("Allow", "GET, DELETE")
+			http.Error(w, `{"error":"method not allowed"}`, http.StatusMethodNotAllowed)
+			return
+		}
+		q := r.URL.Query()
+		leagueID, err := strconv.Atoi(q.Get("league_id"))
+		if err != nil || leagueID <= 0 {
+			failAdmin(w, invalidArgumentf("league_id query parameter is required"))
+			return
+		}
+		prefix, err := cleanDerivedPrefix(q.Get("prefix"))
+		if err != nil {
+			failAdmin(w, err)
+			return
+		}
+		if r.Method == http.MethodDelete && prefix == "" {
+			failAdmin(w, invalidArgumentf("prefix is required to delete"))
+			return
+		}
+		lcfg := cfg.forLeague(leagueID)
+		lock := leagueLocks.get(lcfg.DerivedRoot, leagueID)
+		if r.Method == http.MethodGet {
+			lock.RLock()
+			defer lock.RUnlock()
+		} else {
+			lock.Lock()
+			defer lock.Unlock()
+		}
+		out, err := listDerived(lcfg.DerivedRoot, leagueID, prefix)
+		if err != nil {
+			failAdmin(w, err)
+			return
+		}
+		if r.Method == http.MethodDelete {
+			for _, f := range out.Files {
+				if err := os.Remove(filepath.Join(lcfg.DerivedRoot, filepath.FromSlash(f.Path))); err != nil && !errors.Is(err, fs.ErrNotExist) {
+					failAdmin(w, err)
+					return
+				}
+			}
+		}
+		writeAdminJSON(w, out)
+	}
+}
+
+// cleanDerivedPrefix normalizes a path prefix relative to the derived root
+// and rejects one that could reach outside it: absolute paths and any ".."
+// segment.
+func cleanDerivedPrefix(prefix string) (string, error) {
+	prefix = strings.TrimSpace(prefix)
+	if prefix == "" {
+		return "", nil
+	}
+	if strings.HasPrefix(prefix, "/") || strings.HasPrefix(prefix, `\`) || filepath.IsAbs(prefix) || filepath.VolumeName(prefix) != "" {
+		return "", invalidArgumentf("prefix must be relative to the derived root: %q", prefix)
+	}
+	for _, seg := range strings.FieldsFunc(prefix, func(r rune) bool { return r == '/' || r == '\\' }) {
+		if seg == ".." {
+			return "", invalidArgumentf("prefix must stay inside the derived root: %q", prefix)
+		}
+	}
+	return strings.TrimPrefix(filepath.ToSlash(prefix), "./"), nil
+}
+
+// derivedLeague returns the league id segment of a derived slash path, from
+// its fixed position in each family: ledger/{id}/, snapshots/{id}/,
+// points/{id}/, reconcile/{id}/, summary/{family}/{id}/ and
+// ownership/{id}.json. Entry ids deeper in a snapshot or points path are not
+// league ids. ok is false for paths that belong to no league, such as
+// positions/.
+func derivedLeague(rel string) (string, bool) {
+	parts := strings.Split(rel, "/")
+	switch parts[0] {
+	case "ledger", "snapshots", "points", "reconcile":
+		if len(parts) > 2 {
+			return parts[1], true
+		}
+	case "summary":
+		if len(parts) > 3 {
+			return parts[2], true
+		}
+	case "ownership":
+		if len(parts) == 2 && strings.HasSuffix(parts[1], ".json") {
+			return strings.TrimSuffix(parts[1], ".json"), true
+		}
+	}
+	return "", false
+}
+
+// listDerived lists the files under root that belong to leagueID (see
+// derivedLeague) and whose slash path relative to root starts with prefix.
+func listDerived(root string, leagueID int, prefix string) (DerivedListing, error) {
+	out := DerivedListing{LeagueID: leagueID, Prefix: prefix, Files: make([]DerivedFile, 0)}
+	id := strconv.Itoa(leagueID)
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() || d.Type()&fs.ModeSymlink != 0 {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if got, ok := derivedLeague(rel); !ok || got != id || !strings.HasPrefix(rel, prefix) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		out.Files = append(out.Files, DerivedFile{Path: rel, SizeBytes: info.Size(), ModifiedUTC: info.ModTime().UTC().Format(time.RFC3339)})
+		out.TotalBytes += info.Size()
+		return nil
+	})
+	return out, err
+}
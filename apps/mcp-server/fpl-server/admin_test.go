@@ -0,0 +1,211 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func adminMux(t *testing.T, cfg ServerConfig) *http.ServeMux {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health", keyAuth("api-secret", "X-API-Key")(func(w http.ResponseWriter, r *http.Request) {}))
+	registerAdminRoutes(mux, cfg, "admin-secret", "X-API-Key")
+	return mux
+}
+
+func adminDo(mux *http.ServeMux, method string, target string, key string, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if key != "" {
+		req.Header.Set("X-API-Key", key)
+	}
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestAdminRoutes_AuthSeparation(t *testing.T) {
+	_, cfg := resourceCfg(t)
+	mux := adminMux(t, cfg)
+
+	cases := []struct {
+		target string
+		key    string
+		want   int
+	}{
+		{"/admin/derived?league_id=100", "", http.StatusUnauthorized},
+		{"/admin/derived?league_id=100", "api-secret", http.StatusUnauthorized},
+		{"/admin/derived?league_id=100", "admin-secret", http.StatusOK},
+		{"/health", "admin-secret", http.StatusUnauthorized},
+		{"/health", "api-secret", http.StatusOK},
+	}
+	for _, c := range cases {
+		if rec := adminDo(mux, http.MethodGet, c.target, c.key, ""); rec.Code != c.want {
+			t.Errorf("GET %s with %q: status = %d, want %d", c.target, c.key, rec.Code, c.want)
+		}
+	}
+	if rec := adminDo(mux, http.MethodPost, "/admin/recompute", "api-secret", `{"league_id":100,"gw":1}`); rec.Code != http.StatusUnauthorized {
+		t.Errorf("recompute with the API key: status = %d, want 401", rec.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/derived?league_id=100", nil)
+	req.Header.Set("Authorization", "Bearer admin-secret")
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("bearer admin key: status = %d, want 200", rec.Code)
+	}
+}
+
+func TestAdminDerived_ListAndDelete(t *testing.T) {
+	dir, cfg := resourceCfg(t)
+	writeJSON(t, filepath.Join(dir, "summary/standings/100/gw/3.json"), map[string]any{"rows": []any{}})
+	writeJSON(t, filepath.Join(dir, "summary/league/100/gw/3.json"), map[string]any{})
+	writeJSON(t, filepath.Join(dir, "summary/standings/999/gw/3.json"), map[string]any{"rows": []any{}})
+	mux := adminMux(t, cfg)
+
+	rec := adminDo(mux, http.MethodGet, "/admin/derived?league_id=100", "admin-secret", "")
+	var listing DerivedListing
+	if err := json.Unmarshal(rec.Body.Bytes(), &listing); err != nil {
+		t.Fatalf("decode %s: %v", rec.Body.String(), err)
+	}
+	if len(listing.Files) != 2 || listing.TotalBytes == 0 {
+		t.Errorf("listing = %+v, want league 100's two files only", listing)
+	}
+	for _, f := range listing.Files {
+		if strings.Contains(f.Path, "999") || f.ModifiedUTC == "" {
+			t.Errorf("listed %+v", f)
+		}
+	}
+
+	rec = adminDo(mux, http.MethodDelete, "/admin/derived?league_id=100&prefix=summary/standings/", "admin-secret", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("delete: status = %d: %s", rec.Code, rec.Body.String())
+	}
+	if _, err := os.Stat(filepath.Join(dir, "summary/standings/100/gw/3.json")); !os.IsNotExist(err) {
+		t.Errorf("league 100 standings survived the delete: %v", err)
+	}
+	for _, keep := range []string{"summary/league/100/gw/3.json", "summary/standings/999/gw/3.json"} {
+		if _, err := os.Stat(filepath.Join(dir, keep)); err != nil {
+			t.Errorf("%s outside the prefix or league was removed: %v", keep, err)
+		}
+	}
+	if rec := adminDo(mux, http.MethodDelete, "/admin/derived?league_id=100", "admin-secret", ""); rec.Code != http.StatusBadRequest {
+		t.Errorf("delete without a prefix: status = %d, want 400", rec.Code)
+	}
+}
+
+// TestAdminDerived_LeagueIDIsNotEntryID: league 200's files are not listed
+// or deleted because league 100 has an entry 200.
+func TestAdminDerived_LeagueIDIsNotEntryID(t *testing.T) {
+	dir, cfg := resourceCfg(t)
+	other := []string{
+		"snapshots/100/entry/200/gw/3.json",
+		"points/100/entry/200/gw/3.json",
+		"summary/standings/100/gw/200.json",
+	}
+	own := []string{
+		"ledger/200/event_0.json",
+		"snapshots/200/entry/7/gw/3.json",
+		"points/200/entry/7/gw/3.json",
+		"reconcile/200/gw/3.json",
+		"summary/league/200/gw/3.json",
+		"ownership/200.json",
+	}
+	for _, rel := range append(append([]string{}, other...), own...) {
+		writeJSON(t, filepath.Join(dir, rel), map[string]any{})
+	}
+	mux := adminMux(t, cfg)
+
+	rec := adminDo(mux, http.MethodGet, "/admin/derived?league_id=200", "admin-secret", "")
+	var listing DerivedListing
+	if err := json.Unmarshal(rec.Body.Bytes(), &listing); err != nil {
+		t.Fatalf("decode %s: %v", rec.Body.String(), err)
+	}
+	var got []string
+	for _, f := range listing.Files {
+		got = append(got, f.Path)
+	}
+	slices.Sort(got)
+	want := slices.Sorted(slices.Values(own))
+	if !slices.Equal(got, want) {
+		t.Errorf("league 200 files = %v, want %v", got, want)
+	}
+	if strings.Contains(rec.Body.String(), dir) {
+		t.Errorf("response exposes the derived root: %s", rec.Body.String())
+	}
+
+	for _, prefix := range []string{"snapshots/", "points/", "summary/"} {
+		if rec := adminDo(mux, http.MethodDelete, "/admin/derived?league_id=200&prefix="+prefix, "admin-secret", ""); rec.Code != http.StatusOK {
+			t.Fatalf("delete %s: status = %d: %s", prefix, rec.Code, rec.Body.String())
+		}
+	}
+	for _, rel := range other {
+		if _, err := os.Stat(filepath.Join(dir, rel)); err != nil {
+			t.Errorf("league 100's %s was removed: %v", rel, err)
+		}
+	}
+}
+
+func TestAdminDerived_RejectsTraversal(t *testing.T) {
+	parent := t.TempDir()
+	dir := filepath.Join(parent, "derived")
+	outside := filepath.Join(parent, "raw", "100", "keep.json")
+	writeJSON(t, outside, map[string]any{})
+	writeJSON(t, filepath.Join(dir, "summary/league/100/gw/1.json"), map[string]any{})
+	mux := adminMux(t, ServerConfig{RawRoot: dir, DerivedRoot: dir})
+
+	for _, prefix := range []string{"../raw", "summary/../../raw", "/etc", `..\raw`, "summary/league/100/.."} {
+		for _, method := range []string{http.MethodGet, http.MethodDelete} {
+			rec := adminDo(mux, method, "/admin/derived?league_id=100&prefix="+prefix, "admin-secret", "")
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("%s prefix %q: status = %d, want 400", method, prefix, rec.Code)
+			}
+		}
+	}
+	if _, err := os.Stat(outside); err != nil {
+		t.Errorf("file outside the derived root was touched: %v", err)
+	}
+}
+
+func TestAdminRecompute(t *testing.T) {
+	dir, cfg := resourceCfg(t)
+	writeClaimFixture(t, dir)
+	writeJSON(t, filepath.Join(dir, "gw/1/live.json"), map[string]any{"elements": map[string]any{}})
+	stale := filepath.Join(dir, "summary/standings/100/gw/1.json")
+	writeJSON(t, stale, map[string]any{"stale": true})
+	mux := adminMux(t, cfg)
+
+	rec := adminDo(mux, http.MethodPost, "/admin/recompute", "admin-secret", `{"league_id":100,"gw":1,"families":["league"]}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("recompute: status = %d: %s", rec.Code, rec.Body.String())
+	}
+	var out RecomputeResult
+	if err := json.Unmarshal(rec.Body.Bytes(), &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.FromGW != 1 || out.ToGW != 1 || len(out.Deleted) != 1 || out.Deleted[0] != "summary/standings/100/gw/1.json" {
+		t.Errorf("result = %+v, want the stale GW1 standings deleted", out)
+	}
+	b, err := os.ReadFile(stale)
+	if err != nil {
+		t.Fatalf("standings not rebuilt: %v", err)
+	}
+	if strings.Contains(string(b), "stale") {
+		t.Errorf("standings still the stale file: %s", b)
+	}
+
+	for _, body := range []string{`{"gw":1}`, `{"league_id":100}`, `{"league_id":100,"gw":1,"from_gw":1,"to_gw":2}`, `{"league_id":100,"from_gw":3,"to_gw":2}`, `{"league_id":100,"gw":1,"families":["nope"]}`, `not json`} {
+		if rec := adminDo(mux, http.MethodPost, "/admin/recompute", "admin-secret", body); rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", body, rec.Code)
+		}
+	}
+	if rec := adminDo(mux, http.MethodGet, "/admin/recompute", "admin-secret", ""); rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("GET recompute: status = %d, want 405", rec.Code)
+	}
+}
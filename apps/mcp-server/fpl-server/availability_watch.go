@@ -0,0 +1,174 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/availability"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+)
+
+// defaultWatchMinPoints is the season points an unrostered player needs for
+// their availability changes to be reported.
+const defaultWatchMinPoints = 30
+
+// AvailabilityWatchArgs are the input arguments for the availability_watch tool.
+type AvailabilityWatchArgs struct {
+	LeagueID  int  `json:"league_id" jsonschema:"Draft league id (required)"`
+	MinPoints *int `json:"min_points,omitempty" jsonschema:"Season points an unrostered player needs to be included (default 30)"`
+}
+
+// AvailabilityChange is one player whose status, chance of playing or news
+// changed between the last two bootstrap refreshes.
+type AvailabilityChange struct {
+	Element      int                 `json:"element"`
+	Name         string              `json:"name"`
+	Team         string              `json:"team"`
+	PositionType int                 `json:"position_type"`
+	TotalPoints  int                 `json:"total_points"`
+	Kind         string              `json:"kind"`
+	Severity     string              `json:"severity,omitempty"`
+	Old          *availability.State `json:"old,omitempty"`
+	New          *availability.State `json:"new,omitempty"`
+	OwnerEntryID int                 `json:"owner_entry_id,omitempty"`
+	OwnerName    string              `json:"owner_name,omitempty"`
+}
+
+// AvailabilityWatchOutput is the result of the availability_watch tool.
+type AvailabilityWatchOutput struct {
+	LeagueID   int    `json:"league_id"`
+	RosterGW   int    `json:"roster_gw"`
+	RefreshUTC string `json:"refreshed_utc"`
+	MinPoints  int    `json:"min_points"`
+	// Skipped counts changes to unrostered players below MinPoints.
+	Skipped    int                  `json:"skipped"`
+	BySeverity map[string]int       `json:"by_severity"`
+	Changes    []AvailabilityChange `json:"changes"`
+	Notes      []string             `json:"notes"`
+}
+
+var severityRank = map[string]int{
+	availability.SeverityOut:      0,
+	availability.SeverityDoubtful: 1,
+	availability.SeverityReturned: 2,
+	"":                            3,
+}
+
+func fileModifiedUTC(path string) string {
+	info, err := os.Stat(path)
+	if err != nil {
+		return ""
+	}
+	return info.ModTime().UTC().Format(time.RFC3339)
+}
+
+func buildAvailabilityWatch(cfg ServerConfig, args AvailabilityWatchArgs) (AvailabilityWatchOutput, error) {
+	if args.LeagueID == 0 {
+		return AvailabilityWatchOutput{}, invalidArgumentf("league_id is required")
+	}
+	minPoints := defaultWatchMinPoints
+	if args.MinPoints != nil && *args.MinPoints >= 0 {
+		minPoints = *args.MinPoints
+	}
+
+	st := store.NewJSONStore(cfg.RawRoot)
+	curRaw, err := st.ReadRaw("bootstrap/bootstrap-static.json")
+	if err != nil {
+		return AvailabilityWatchOutput{}, err
+	}
+	cur, err := availability.Parse(curRaw)
+	if err != nil {
+		return AvailabilityWatchOutput{}, fmt.Errorf("parse bootstrap-static.json: %w", err)
+	}
+
+	out := AvailabilityWatchOutput{
+		LeagueID:   args.LeagueID,
+		RefreshUTC: fileModifiedUTC(st.Path("bootstrap/bootstrap-static.json")),
+		MinPoints:  minPoints,
+		Changes:    make([]AvailabilityChange, 0),
+		BySeverity: make(map[string]int),
+		Notes:      make([]string, 0),
+	}
+	prevRaw, err := st.ReadRaw("bootstrap/bootstrap-static.prev.json")
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return AvailabilityWatchOutput{}, err
+		}
+		out.Notes = append(out.Notes, "No previous bootstrap to compare against; changes are reported from the next bootstrap refresh.")
+		return out, nil
+	}
+	prev, err := availability.Parse(prevRaw)
+	if err != nil {
+		return AvailabilityWatchOutput{}, fmt.Errorf("parse bootstrap-static.prev.json: %w", err)
+	}
+
+	asOfGW, nextGW, err := resolveAsOfAndNextGW(cfg, 0, 0)
+	if err != nil {
+		return AvailabilityWatchOutput{}, err
+	}
+	out.RosterGW = resolveRosterGW(asOfGW, nextGW)
+	ownership, err := loadOwnershipAtGW(cfg, args.LeagueID, out.RosterGW)
+	if err != nil {
+		return AvailabilityWatchOutput{}, err
+	}
+	owner := make(map[int]int)
+	for entryID, owned := range ownership {
+		for element := range owned {
+			owner[element] = entryID
+		}
+	}
+	entryName := make(map[int]string)
+	if ld, _, err := loadLeagueDetails(st, args.LeagueID); err == nil {
+		for _, e := range ld.LeagueEntries {
+			entryName[e.EntryID] = e.EntryName
+		}
+	}
+	teams, err := loadTeams(cfg.RawRoot)
+	if err != nil {
+		return AvailabilityWatchOutput{}, err
+	}
+
+	for _, c := range availability.Diff(prev, cur) {
+		entryID, rostered := owner[c.Player.ID]
+		if !rostered && c.Player.TotalPoints < minPoints {
+			out.Skipped++
+			continue
+		}
+		out.Changes = append(out.Changes, AvailabilityChange{
+			Element:      c.Player.ID,
+			Name:         c.Player.Name,
+			Team:         teams[c.Player.Team].ShortName,
+			PositionType: c.Player.PositionType,
+			TotalPoints:  c.Player.TotalPoints,
+			Kind:         c.Kind,
+			Severity:     c.Severity,
+			Old:          c.Old,
+			New:          c.New,
+			OwnerEntryID: entryID,
+			OwnerName:    entryName[entryID],
+		})
+		if c.Severity != "" {
+			out.BySeverity[c.Severity]++
+		}
+	}
+	// Most severe first; rostered players ahead of free agents, then by points.
+	sort.SliceStable(out.Changes, func(i, j int) bool {
+		a, b := out.Changes[i], out.Changes[j]
+		if severityRank[a.Severity] != severityRank[b.Severity] {
+			return severityRank[a.Severity] < severityRank[b.Severity]
+		}
+		if (a.OwnerEntryID != 0) != (b.OwnerEntryID != 0) {
+			return a.OwnerEntryID != 0
+		}
+		if a.TotalPoints != b.TotalPoints {
+			return a.TotalPoints > b.TotalPoints
+		}
+		return a.Element < b.Element
+	})
+	if len(out.Changes) == 0 {
+		out.Notes = append(out.Notes, "No availability changes for rostered players or free agents above the points threshold since the previous refresh.")
+	}
+	return out, nil
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestBuildAvailabilityWatch(t *testing.T) {
+	dir, cfg := resourceCfg(t)
+	writeClaimFixture(t, dir)
+	el := func(id int, name string, status string, chance any, news string, points int) map[string]any {
+		return map[string]any{"id": id, "web_name": name, "team": 10, "element_type": 3, "status": status, "chance_of_playing_next_round": chance, "news": news, "total_points": points}
+	}
+	teams := []any{map[string]any{"id": 10, "short_name": "LIV"}}
+	writeJSON(t, filepath.Join(dir, "bootstrap", "bootstrap-static.prev.json"), map[string]any{
+		"elements": []any{
+			el(1, "Salah", "a", nil, "", 90),
+			el(2, "Palmer", "i", 0, "Groin", 60),
+			el(4, "Saka", "a", nil, "", 70),
+			el(10, "Mbeumo", "a", nil, "", 50),
+			el(11, "Kudus", "a", nil, "", 5),
+		},
+		"teams": teams,
+	})
+	writeJSON(t, filepath.Join(dir, "bootstrap", "bootstrap-static.json"), map[string]any{
+		"elements": []any{
+			el(1, "Salah", "a", nil, "", 90),
+			el(2, "Palmer", "a", 100, "", 60),
+			el(4, "Saka", "d", 50, "Knock", 70),
+			el(10, "Mbeumo", "s", 0, "Suspended for 1 match", 50),
+			el(11, "Kudus", "i", 0, "Ankle", 5), // free agent under the threshold
+		},
+		"teams": teams,
+	})
+
+	out, err := buildAvailabilityWatch(cfg, AvailabilityWatchArgs{LeagueID: 100})
+	if err != nil {
+		t.Fatalf("buildAvailabilityWatch: %v", err)
+	}
+	want := []struct {
+		element  int
+		severity string
+		owner    string
+	}{{10, "out", ""}, {4, "doubtful", "Beta FC"}, {2, "returned", "Alpha FC"}}
+	if len(out.Changes) != len(want) || out.Skipped != 1 {
+		t.Fatalf("changes = %+v skipped = %d, want 3 changes and Kudus skipped", out.Changes, out.Skipped)
+	}
+	for i, w := range want {
+		c := out.Changes[i]
+		if c.Element != w.element || c.Severity != w.severity || c.OwnerName != w.owner {
+			t.Errorf("change %d = %d/%s/%q, want %d/%s/%q", i, c.Element, c.Severity, c.OwnerName, w.element, w.severity, w.owner)
+		}
+	}
+	if c := out.Changes[1]; c.Old.Status != "a" || c.New.News != "Knock" || c.Team != "LIV" {
+		t.Errorf("saka = %+v", c)
+	}
+	if out.RosterGW != 3 || out.BySeverity["out"] != 1 {
+		t.Errorf("roster GW %d, by severity %v", out.RosterGW, out.BySeverity)
+	}
+
+	low := 0
+	if out, _ := buildAvailabilityWatch(cfg, AvailabilityWatchArgs{LeagueID: 100, MinPoints: &low}); len(out.Changes) != 4 || out.Skipped != 0 {
+		t.Errorf("min_points 0: %d changes, %d skipped, want all 4", len(out.Changes), out.Skipped)
+	}
+}
+
+func TestBuildAvailabilityWatch_NoPrevious(t *testing.T) {
+	dir, cfg := resourceCfg(t)
+	writeClaimFixture(t, dir)
+	out, err := buildAvailabilityWatch(cfg, AvailabilityWatchArgs{LeagueID: 100})
+	if err != nil {
+		t.Fatalf("buildAvailabilityWatch: %v", err)
+	}
+	if len(out.Changes) != 0 || len(out.Notes) != 1 {
+		t.Errorf("changes = %v notes = %v, want a note only", out.Changes, out.Notes)
+	}
+	if _, err := buildAvailabilityWatch(cfg, AvailabilityWatchArgs{}); classifyError(err).Code != codeInvalidArgument {
+		t.Errorf("missing league: err = %v", err)
+	}
+}
@@ -0,0 +1,270 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"sync"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/fetch"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/ledger"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/summary"
+)
+
+// defaultFetchOnMissLimit is the --fetch-on-miss-limit default: how many raw
+// files one tool call may fetch with --allow-fetch-on-miss.
+const defaultFetchOnMissLimit = 20
+
+// rawFetcher is the part of fetch.Client that fetch-on-miss uses. Both
+// methods write the file under the raw root they were made for.
+type rawFetcher interface {
+	EventLive(gw int, force bool) error
+	EntryEvent(entryID int, gw int, force bool) error
+}
+
+// newFetchClient is the --allow-fetch-on-miss ServerConfig.Fetcher: a
+// fetch client writing under rawRoot. Its own sleep between requests is the
+// rate limit; fetchOnMissMu keeps concurrent calls from stacking them.
+func newFetchClient(rawRoot string) rawFetcher {
+	return fetch.NewClient(store.NewJSONStore(rawRoot))
+}
+
+// fetchOnMissMu serialises fetch-on-miss requests across tool calls.
+var fetchOnMissMu sync.Mutex
+
+// fetchBudget is what is left of a tool call's fetch-on-miss allowance. It
+// is shared by everything the call loads (see forCall).
+type fetchBudget struct {
+	mu   sync.Mutex
+	left int
+}
+
+func newFetchBudget(limit int) *fetchBudget {
+	if limit <= 0 {
+		limit = defaultFetchOnMissLimit
+	}
+	return &fetchBudget{left: limit}
+}
+
+// take reserves n fetches, or none when fewer than n are left.
+func (b *fetchBudget) take(n int) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if n > b.left {
+		return false
+	}
+	b.left -= n
+	return true
+}
+
+// missingRaw is one raw file a gameweek needs, relative to the raw root.
+// EntryID is 0 for the GW's live.json.
+type missingRaw struct {
+	Path    string
+	GW      int
+	EntryID int
+}
+
+// rawNeed is the raw GW data a build reads for FromGW..ToGW: each GW's
+// live.json when Live is set, and the entry events of EntryIDs (nil means
+// every league entry).
+type rawNeed struct {
+	FromGW   int
+	ToGW     int
+	Live     bool
+	EntryIDs []int
+}
+
+// missingRawFiles lists the files of need that are absent. A GW before an
+// entry's first fetched event is only missing when it is also before every
+// other entry's first one; otherwise the entry joined the league later.
+func missingRawFiles(st *store.JSONStore, ld summary.LeagueDetails, leagueEntries []int, need rawNeed) []missingRaw {
+	entryIDs := need.EntryIDs
+	if entryIDs == nil {
+		entryIDs = leagueEntries
+	}
+	first := make(map[int]int, len(ld.LeagueEntries))
+	earliest := 0
+	for _, e := range ld.LeagueEntries {
+		f := ledger.FirstEntryEventGW(st, e.EntryID)
+		first[e.EntryID] = f
+		if f > 0 && (earliest == 0 || f < earliest) {
+			earliest = f
+		}
+	}
+	var out []missingRaw
+	for gw := max(need.FromGW, ld.StartGW()); gw <= need.ToGW; gw++ {
+		if rel := fmt.Sprintf("gw/%d/live.json", gw); need.Live && !st.Exists(rel) {
+			out = append(out, missingRaw{Path: rel, GW: gw})
+		}
+		for _, entryID := range entryIDs {
+			if f := first[entryID]; f > gw && gw >= earliest {
+				continue
+			}
+			if rel := fmt.Sprintf("entry/%d/gw/%d.json", entryID, gw); !st.Exists(rel) {
+				out = append(out, missingRaw{Path: rel, GW: gw, EntryID: entryID})
+			}
+		}
+	}
+	return out
+}
+
+// backfillCommand is the refresh pipeline run that fetches a league's raw
+// files for fromGW..toGW without refetching what is already there.
+func backfillCommand(leagueID int, fromGW int, toGW int) string {
+	return fmt.Sprintf("go run ./apps/mcp-server/cmd/dev --league %d --gw-min %d --gw-max %d --refresh=none", leagueID, fromGW, toGW)
+}
+
+// rawMissingError is the DATA_MISSING error for raw files a build needs,
+// naming every one of them and the command that fetches them.
+func rawMissingError(cfg ServerConfig, leagueID int, missing []missingRaw, reason string) error {
+	paths := make([]string, 0, len(missing))
+	fromGW, toGW := missing[0].GW, missing[0].GW
+	for _, m := range missing {
+		paths = append(paths, store.NewJSONStore(cfg.RawRoot).Path(m.Path))
+		fromGW, toGW = min(fromGW, m.GW), max(toGW, m.GW)
+	}
+	cmd := backfillCommand(leagueID, fromGW, toGW)
+	return &codedError{
+		Code:    codeDataMissing,
+		Message: fmt.Sprintf("%d raw files for GW %d-%d were never fetched%s", len(missing), fromGW, toGW, reason),
+		Details: map[string]any{
+			"missing":          paths,
+			"backfill_command": cmd,
+			"hint":             "run " + cmd + ", or start the server with --allow-fetch-on-miss",
+		},
+	}
+}
+
+// withRawBackfill runs build, which reads leagueID's raw files in need.
+// With fetch-on-miss on, missing files are fetched first, up to what is left
+// of the call's budget. A build that fails for a missing file returns
+// DATA_MISSING listing every file of need still absent.
+func withRawBackfill(cfg ServerConfig, leagueID int, need rawNeed, build func() error) error {
+	st := store.NewJSONStore(cfg.RawRoot)
+	ld, leagueEntries, err := loadLeagueDetails(st, leagueID)
+	if err != nil {
+		// Without details there is nothing to plan from; let build report
+		// what it can't read.
+		return build()
+	}
+	if cfg.Fetcher != nil {
+		if missing := missingRawFiles(st, ld, leagueEntries, need); len(missing) > 0 {
+			budget := cfg.fetches
+			if budget == nil {
+				budget = newFetchBudget(cfg.FetchOnMissLimit)
+			}
+			if !budget.take(len(missing)) {
+				return rawMissingError(cfg, leagueID, missing, ", more than this call may fetch")
+			}
+			if err := fetchMissingRaw(cfg.Fetcher(cfg.RawRoot), missing); err != nil {
+				return err
+			}
+		}
+	}
+	err = build()
+	if err == nil || !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	if missing := missingRawFiles(st, ld, leagueEntries, need); len(missing) > 0 {
+		return rawMissingError(cfg, leagueID, missing, "")
+	}
+	return err
+}
+
+// fetchMissingRaw fetches each missing file in turn. An entry event the API
+// doesn't have (the entry wasn't in the league yet) is skipped.
+func fetchMissingRaw(f rawFetcher, missing []missingRaw) error {
+	fetchOnMissMu.Lock()
+	defer fetchOnMissMu.Unlock()
+	for _, m := range missing {
+		var err error
+		if m.EntryID == 0 {
+			err = f.EventLive(m.GW, false)
+		} else {
+			err = f.EntryEvent(m.EntryID, m.GW, false)
+		}
+		if errors.Is(err, fetch.ErrNotFound) && m.EntryID != 0 {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("fetch %s: %w", m.Path, err)
+		}
+	}
+	return nil
+}
+
+type BackfillStatusArgs struct {
+	LeagueID int `json:"league_id" jsonschema:"Draft league id (required)"`
+}
+
+// GWRawStatus is one gameweek's raw data: complete when its live.json and
+// every entry's event are on disk. Missing lists the absent files relative
+// to the raw root.
+type GWRawStatus struct {
+	Gameweek int      `json:"gameweek"`
+	Complete bool     `json:"complete"`
+	Missing  []string `json:"missing,omitempty"`
+}
+
+// BackfillStatusOutput is the output of the backfill_status tool. It covers
+// the league's first GW through the latest one with results;
+// BackfillCommand fetches every missing GW and is empty when none are.
+type BackfillStatusOutput struct {
+	LeagueID        int           `json:"league_id"`
+	FromGW          int           `json:"from_gw"`
+	ThroughGW       int           `json:"through_gw"`
+	CompleteGWs     []int         `json:"complete_gws"`
+	MissingGWs      []int         `json:"missing_gws"`
+	Gameweeks       []GWRawStatus `json:"gameweeks"`
+	BackfillCommand string        `json:"backfill_command,omitempty"`
+	FetchOnMiss     bool          `json:"fetch_on_miss"`
+}
+
+func buildBackfillStatus(cfg ServerConfig, args BackfillStatusArgs) (BackfillStatusOutput, error) {
+	if args.LeagueID == 0 {
+		return BackfillStatusOutput{}, invalidArgumentf("league_id is required")
+	}
+	st := store.NewJSONStore(cfg.RawRoot)
+	ld, entryIDs, err := loadLeagueDetails(st, args.LeagueID)
+	if err != nil {
+		return BackfillStatusOutput{}, err
+	}
+	meta, err := loadGameMeta(cfg)
+	if err != nil {
+		return BackfillStatusOutput{}, err
+	}
+	// The current GW counts once it has started, which is when live data
+	// first appears.
+	through := meta.CurrentEvent
+	if !meta.CurrentEventFinished && !liveDataExists(cfg.RawRoot, through) {
+		through--
+	}
+	out := BackfillStatusOutput{
+		LeagueID:    args.LeagueID,
+		FromGW:      ld.StartGW(),
+		ThroughGW:   through,
+		CompleteGWs: []int{},
+		MissingGWs:  []int{},
+		Gameweeks:   []GWRawStatus{},
+		FetchOnMiss: cfg.Fetcher != nil,
+	}
+	byGW := make(map[int][]string)
+	for _, m := range missingRawFiles(st, ld, entryIDs, rawNeed{FromGW: out.FromGW, ToGW: through, Live: true}) {
+		byGW[m.GW] = append(byGW[m.GW], m.Path)
+	}
+	for gw := out.FromGW; gw <= through; gw++ {
+		s := GWRawStatus{Gameweek: gw, Complete: len(byGW[gw]) == 0, Missing: byGW[gw]}
+		out.Gameweeks = append(out.Gameweeks, s)
+		if s.Complete {
+			out.CompleteGWs = append(out.CompleteGWs, gw)
+		} else {
+			out.MissingGWs = append(out.MissingGWs, gw)
+		}
+	}
+	if n := len(out.MissingGWs); n > 0 {
+		out.BackfillCommand = backfillCommand(args.LeagueID, out.MissingGWs[0], out.MissingGWs[n-1])
+	}
+	return out, nil
+}
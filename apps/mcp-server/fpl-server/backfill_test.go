@@ -0,0 +1,192 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/fetch"
+)
+
+// fakeFetcher stands in for the fetch client: it writes a minimal file for
+// each fetch under dir and records what was asked for.
+type fakeFetcher struct {
+	t        *testing.T
+	dir      string
+	calls    []string
+	notFound bool
+}
+
+func (f *fakeFetcher) EventLive(gw int, force bool) error {
+	f.calls = append(f.calls, fmt.Sprintf("live %d", gw))
+	writeLiveJSON(f.t, f.dir, gw, map[string]any{"1": map[string]any{"stats": map[string]any{"minutes": 90, "total_points": 7}}})
+	return nil
+}
+
+func (f *fakeFetcher) EntryEvent(entryID int, gw int, force bool) error {
+	f.calls = append(f.calls, fmt.Sprintf("entry %d %d", entryID, gw))
+	if f.notFound {
+		return fmt.Errorf("entry %d gw %d: %w", entryID, gw, fetch.ErrNotFound)
+	}
+	writeJSON(f.t, filepath.Join(f.dir, "entry", itoa(entryID), "gw", itoa(gw)+".json"), map[string]any{
+		"picks": []any{map[string]any{"element": 1, "position": 1}},
+	})
+	return nil
+}
+
+func backfillCfg(t *testing.T) (string, ServerConfig) {
+	dir, cfg := tmpCfg(t)
+	cfg.DerivedRoot = filepath.Join(dir, "derived")
+	cfg.ComputeMissing = true
+	cfg.WriteDerived = true
+	writeEntryPointsFixture(t, dir)
+	return dir, cfg
+}
+
+func TestBuildBackfillStatus(t *testing.T) {
+	dir, cfg := backfillCfg(t)
+	// The pipeline started at GW10 and lost one of Beta's GW11 events.
+	if err := os.Remove(filepath.Join(dir, "entry/201/gw/11.json")); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := buildBackfillStatus(cfg, BackfillStatusArgs{LeagueID: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.FromGW != 1 || out.ThroughGW != 12 || len(out.Gameweeks) != 12 || out.FetchOnMiss {
+		t.Fatalf("out = %+v", out)
+	}
+	if !slices.Equal(out.CompleteGWs, []int{10, 12}) || !slices.Equal(out.MissingGWs, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 11}) {
+		t.Errorf("complete %v, missing %v", out.CompleteGWs, out.MissingGWs)
+	}
+	if got := out.Gameweeks[10].Missing; !slices.Equal(got, []string{"entry/201/gw/11.json"}) {
+		t.Errorf("GW11 missing = %v", got)
+	}
+	if got := out.Gameweeks[0].Missing; !slices.Equal(got, []string{"gw/1/live.json", "entry/200/gw/1.json", "entry/201/gw/1.json"}) {
+		t.Errorf("GW1 missing = %v", got)
+	}
+	if !strings.Contains(out.BackfillCommand, "--league 100 --gw-min 1 --gw-max 11") {
+		t.Errorf("command = %q", out.BackfillCommand)
+	}
+
+	if _, err := buildBackfillStatus(cfg, BackfillStatusArgs{}); classifyError(err).Code != codeInvalidArgument {
+		t.Errorf("no league: err = %v", err)
+	}
+}
+
+func TestBuildBackfillStatus_JoinedLater(t *testing.T) {
+	dir, cfg := backfillCfg(t)
+	// Beta joined at GW11: its missing GW10 is expected, not a gap.
+	if err := os.Remove(filepath.Join(dir, "entry/201/gw/10.json")); err != nil {
+		t.Fatal(err)
+	}
+	out, err := buildBackfillStatus(cfg, BackfillStatusArgs{LeagueID: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(out.CompleteGWs, []int{10, 11, 12}) {
+		t.Errorf("complete = %v", out.CompleteGWs)
+	}
+}
+
+func TestWithRawBackfill_FetchOff(t *testing.T) {
+	dir, cfg := backfillCfg(t)
+
+	_, err := loadPointsResult(cfg, 100, 200, 9)
+	ce := classifyError(err)
+	if ce.Code != codeDataMissing {
+		t.Fatalf("err = %v, want DATA_MISSING", err)
+	}
+	want := []string{filepath.Join(dir, "gw/9/live.json"), filepath.Join(dir, "entry/200/gw/9.json")}
+	if got, _ := ce.Details["missing"].([]string); !slices.Equal(got, want) {
+		t.Errorf("missing = %v, want %v", ce.Details["missing"], want)
+	}
+	if cmd, _ := ce.Details["backfill_command"].(string); !strings.Contains(cmd, "--gw-min 9 --gw-max 9") {
+		t.Errorf("backfill_command = %q", cmd)
+	}
+}
+
+func TestWithRawBackfill_FetchOnMiss(t *testing.T) {
+	dir, cfg := backfillCfg(t)
+	f := &fakeFetcher{t: t, dir: dir}
+	cfg.Fetcher = func(rawRoot string) rawFetcher {
+		if rawRoot != dir {
+			t.Errorf("fetcher for %s, want %s", rawRoot, dir)
+		}
+		return f
+	}
+
+	res, err := loadPointsResult(cfg, 100, 200, 9)
+	if err != nil {
+		t.Fatalf("loadPointsResult: %v", err)
+	}
+	if !slices.Equal(f.calls, []string{"live 9", "entry 200 9"}) {
+		t.Errorf("calls = %v", f.calls)
+	}
+	if res.TotalPoints != 7 || res.MissingSnapshot {
+		t.Errorf("result = %+v, want the fetched GW scored", res)
+	}
+
+	// Data already on disk is never refetched.
+	f.calls = nil
+	if _, err := loadPointsResult(cfg, 100, 200, 10); err != nil || len(f.calls) != 0 {
+		t.Errorf("GW10: calls %v, err %v", f.calls, err)
+	}
+}
+
+func TestWithRawBackfill_Budget(t *testing.T) {
+	dir, cfg := backfillCfg(t)
+	f := &fakeFetcher{t: t, dir: dir}
+	cfg.Fetcher = func(string) rawFetcher { return f }
+	cfg.FetchOnMissLimit = 3
+	cfg.fetches = newFetchBudget(cfg.FetchOnMissLimit)
+
+	// GW9 needs two fetches; the third GW8 file doesn't fit.
+	if _, err := loadPointsResult(cfg, 100, 200, 9); err != nil {
+		t.Fatal(err)
+	}
+	_, err := loadPointsResult(cfg, 100, 200, 8)
+	ce := classifyError(err)
+	if ce.Code != codeDataMissing || !strings.Contains(ce.Message, "more than this call may fetch") {
+		t.Errorf("err = %v, want DATA_MISSING over the limit", err)
+	}
+	if len(f.calls) != 2 {
+		t.Errorf("calls = %v, want only GW9's", f.calls)
+	}
+}
+
+func TestWithRawBackfill_EntryNotFound(t *testing.T) {
+	dir, cfg := backfillCfg(t)
+	f := &fakeFetcher{t: t, dir: dir, notFound: true}
+	cfg.Fetcher = func(string) rawFetcher { return f }
+
+	// The API has no GW9 for the entry: it wasn't in the league yet.
+	res, err := loadPointsResult(cfg, 100, 200, 9)
+	if err != nil {
+		t.Fatalf("loadPointsResult: %v", err)
+	}
+	if !res.MissingSnapshot || len(f.calls) != 2 {
+		t.Errorf("result = %+v, calls %v", res, f.calls)
+	}
+}
+
+func TestForSeason_NoFetchForPastSeasons(t *testing.T) {
+	dir, cfg := tmpCfg(t)
+	writeBootstrap(t, filepath.Join(dir, "2024-25"))
+	writeBootstrap(t, filepath.Join(dir, "2025-26"))
+	cfg = cfg.withSeasonRoots(dir, filepath.Join(dir, "derived"))
+	cfg.Fetcher = newFetchClient
+
+	past, err := cfg.forSeason("2024-25")
+	if err != nil || past.Fetcher != nil {
+		t.Errorf("past season: fetcher set = %v, err %v", past.Fetcher != nil, err)
+	}
+	cur, err := cfg.forSeason("2025-26")
+	if err != nil || cur.Fetcher == nil {
+		t.Errorf("current season: fetcher set = %v, err %v", cur.Fetcher != nil, err)
+	}
+}
@@ -0,0 +1,579 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/leagueconfig"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/summary"
+)
+
+// Rival claim estimation. A manager without explicit claims is assumed to
+// claim from the league's waiver_targets ranking, taking their i-th ranked
+// unowned target (from 1) with probability rivalClaimRate/i.
+const (
+	rivalClaimRate        = 0.5
+	defaultRivalDepth     = 5
+	claimSimulationTrials = 2000
+	claimSimulationSeed   = 1
+)
+
+// Claim statuses reported in ClaimOutcome.Status.
+const (
+	claimWon          = "won"
+	claimTaken        = "taken"         // a manager earlier in the order got the player
+	claimAlreadyAdded = "already_added" // an earlier claim of yours got the player
+	claimDropGone     = "drop_gone"     // an earlier claim of yours already dropped the player
+	claimIllegal      = "illegal_squad" // the swap would break the squad limits at that point
+)
+
+// ClaimInput is one proposed waiver claim.
+type ClaimInput struct {
+	Add  int `json:"add" jsonschema:"Element id to claim"`
+	Drop int `json:"drop" jsonschema:"Element id to drop if the claim succeeds"`
+}
+
+// RivalClaims is another manager's claim list. Their drops don't matter to
+// the simulation: dropped players only become claimable after processing.
+type RivalClaims struct {
+	EntryID int   `json:"entry_id" jsonschema:"Manager's entry id"`
+	Adds    []int `json:"adds" jsonschema:"Element ids the manager claims, in preference order"`
+}
+
+// ClaimSimulatorArgs are the input arguments for the claim_simulator tool.
+type ClaimSimulatorArgs struct {
+	LeagueID       int           `json:"league_id" jsonschema:"Draft league id (required)"`
+	EntryID        *int          `json:"entry_id,omitempty" jsonschema:"Entry id (required if entry_name not provided)"`
+	EntryName      *string       `json:"entry_name,omitempty" jsonschema:"Entry name (if entry_id not provided)"`
+	GW             *int          `json:"gw,omitempty" jsonschema:"Gameweek the waivers are for (0 = next gameweek)"`
+	Claims         []ClaimInput  `json:"claims" jsonschema:"Your claims in priority order (required)"`
+	WaiverOrder    []int         `json:"waiver_order,omitempty" jsonschema:"Entry ids in waiver priority order, first pick first (default: reverse standings)"`
+	RivalClaims    []RivalClaims `json:"rival_claims,omitempty" jsonschema:"Other managers' claims; managers not listed are estimated from waiver_targets"`
+	EstimateRivals *bool         `json:"estimate_rivals,omitempty" jsonschema:"Estimate claims for managers not in rival_claims (default true)"`
+	RivalDepth     int           `json:"rival_depth,omitempty" jsonschema:"How many waiver_targets an estimated rival considers (default 5)"`
+}
+
+// ClaimOutcome is how one of your claims resolved in a scenario. TakenBy is
+// the entry that got the player when Status is taken.
+type ClaimOutcome struct {
+	Claim    int    `json:"claim"`
+	Add      int    `json:"add"`
+	AddName  string `json:"add_name"`
+	Drop     int    `json:"drop"`
+	DropName string `json:"drop_name"`
+	Status   string `json:"status"`
+	TakenBy  int    `json:"taken_by,omitempty"`
+}
+
+// ClaimScenario is one distinct outcome of your claims and the roster it
+// leaves you with.
+type ClaimScenario struct {
+	Probability float64                `json:"probability"`
+	Outcomes    []ClaimOutcome         `json:"outcomes"`
+	Roster      []summary.RosterPlayer `json:"roster"`
+}
+
+// ClaimRival is a manager who takes a player you claimed, and how often.
+type ClaimRival struct {
+	EntryID     int     `json:"entry_id"`
+	EntryName   string  `json:"entry_name"`
+	Probability float64 `json:"probability"`
+}
+
+// ClaimRisk summarises one of your claims across all scenarios. LegalNow is
+// false when the swap breaks the league's squad limits against your current roster; it can
+// still succeed after an earlier claim changes the squad.
+type ClaimRisk struct {
+	Claim              int          `json:"claim"`
+	Add                int          `json:"add"`
+	AddName            string       `json:"add_name"`
+	Drop               int          `json:"drop"`
+	DropName           string       `json:"drop_name"`
+	SuccessProbability float64      `json:"success_probability"`
+	LegalNow           bool         `json:"legal_now"`
+	LostTo             []ClaimRival `json:"lost_to,omitempty"`
+}
+
+// WaiverSlot is one manager's place in the waiver order. Estimated is set
+// when their claims were estimated rather than given.
+type WaiverSlot struct {
+	Priority  int    `json:"priority"`
+	EntryID   int    `json:"entry_id"`
+	EntryName string `json:"entry_name"`
+	Estimated bool   `json:"estimated,omitempty"`
+}
+
+// ClaimSimulatorOutput is the output of the claim_simulator tool.
+type ClaimSimulatorOutput struct {
+	LeagueID      int             `json:"league_id"`
+	EntryID       int             `json:"entry_id"`
+	AsOfGW        int             `json:"as_of_gw"`
+	RosterGW      int             `json:"roster_gw"`
+	TargetGW      int             `json:"target_gw"`
+	WaiverOrder   []WaiverSlot    `json:"waiver_order"`
+	Deterministic bool            `json:"deterministic"`
+	Trials        int             `json:"trials"`
+	Scenarios     []ClaimScenario `json:"scenarios"`
+	Claims        []ClaimRisk     `json:"claims"`
+	// MostAtRisk lists claim numbers that can fail, lowest success first.
+	MostAtRisk []int    `json:"most_at_risk"`
+	Warnings   []string `json:"warnings,omitempty"`
+	Notes      []string `json:"notes"`
+}
+
+// waiverClaim is one queued claim. claim is the 1-based number of one of your
+// claims and 0 for rivals, whose drops and positions aren't tracked.
+type waiverClaim struct {
+	claim   int
+	add     int
+	addPos  int
+	drop    int
+	dropPos int
+}
+
+type waiverResult struct {
+	status  string
+	takenBy int
+}
+
+// claimKeepsSquadLegal reports whether swapping drop for add keeps the squad
+// within limits.
+func claimKeepsSquadLegal(limits leagueconfig.PositionLimits, counts map[int]int, addPos int, dropPos int) bool {
+	after := counts[addPos] + 1
+	if dropPos == addPos {
+		after--
+	}
+	return after <= limits.At(addPos)
+}
+
+// processWaivers runs one waiver pass. Claims resolve in rounds: each round
+// every manager, in order, is granted the first of their remaining claims
+// whose player is still available, and the order does not change between
+// rounds. It returns the result of each of me's claims and their roster
+// (element id to position type) afterwards.
+func processWaivers(limits leagueconfig.PositionLimits, order []int, me int, queues map[int][]waiverClaim, roster map[int]int, myClaims int) ([]waiverResult, map[int]int) {
+	mine := make(map[int]int, len(roster))
+	counts := make(map[int]int, 4)
+	for id, pos := range roster {
+		mine[id] = pos
+		counts[pos]++
+	}
+	pending := make(map[int][]waiverClaim, len(queues))
+	for entry, q := range queues {
+		pending[entry] = q
+	}
+	results := make([]waiverResult, myClaims)
+	taken := make(map[int]int) // element -> entry that got it
+
+	for progress := true; progress; {
+		progress = false
+		for _, entry := range order {
+			q := pending[entry]
+			for len(q) > 0 {
+				c := q[0]
+				q = q[1:]
+				if by, ok := taken[c.add]; ok {
+					if entry == me {
+						if by == me {
+							results[c.claim-1] = waiverResult{status: claimAlreadyAdded}
+						} else {
+							results[c.claim-1] = waiverResult{status: claimTaken, takenBy: by}
+						}
+					}
+					continue
+				}
+				if entry == me {
+					if _, ok := mine[c.drop]; !ok {
+						results[c.claim-1] = waiverResult{status: claimDropGone}
+						continue
+					}
+					if !claimKeepsSquadLegal(limits, counts, c.addPos, c.dropPos) {
+						results[c.claim-1] = waiverResult{status: claimIllegal}
+						continue
+					}
+					delete(mine, c.drop)
+					counts[c.dropPos]--
+					mine[c.add] = c.addPos
+					counts[c.addPos]++
+					results[c.claim-1] = waiverResult{status: claimWon}
+				}
+				taken[c.add] = entry
+				progress = true
+				break
+			}
+			pending[entry] = q
+		}
+	}
+	return results, mine
+}
+
+func buildClaimSimulator(cfg ServerConfig, args ClaimSimulatorArgs) (ClaimSimulatorOutput, error) {
+	if args.LeagueID == 0 {
+		return ClaimSimulatorOutput{}, invalidArgumentf("league_id is required")
+	}
+	if len(args.Claims) == 0 {
+		return ClaimSimulatorOutput{}, invalidArgumentf("claims is required")
+	}
+	st := store.NewJSONStore(cfg.RawRoot)
+	ld, _, err := loadLeagueDetails(st, args.LeagueID)
+	if err != nil {
+		return ClaimSimulatorOutput{}, err
+	}
+	entryID := 0
+	if args.EntryID != nil {
+		entryID = *args.EntryID
+	}
+	if entryID == 0 {
+		if args.EntryName == nil || strings.TrimSpace(*args.EntryName) == "" {
+			return ClaimSimulatorOutput{}, invalidArgumentf("entry_id or entry_name is required")
+		}
+		if entryID, err = resolveEntry(ld.LeagueEntries, *args.EntryName); err != nil {
+			return ClaimSimulatorOutput{}, err
+		}
+	}
+	entryName := make(map[int]string, len(ld.LeagueEntries))
+	for _, e := range ld.LeagueEntries {
+		entryName[e.EntryID] = e.EntryName
+	}
+	if _, ok := entryName[entryID]; !ok {
+		return ClaimSimulatorOutput{}, notFoundf("entry %d is not in league %d", entryID, args.LeagueID)
+	}
+
+	nextGWArg := 0
+	if args.GW != nil {
+		nextGWArg = *args.GW
+	}
+	asOfGW, targetGW, err := resolveAsOfAndNextGW(cfg, 0, nextGWArg)
+	if err != nil {
+		return ClaimSimulatorOutput{}, err
+	}
+	rosterGW := resolveRosterGW(asOfGW, targetGW)
+
+	elements, teamShort, _, err := loadBootstrapData(cfg.RawRoot)
+	if err != nil {
+		return ClaimSimulatorOutput{}, err
+	}
+	elementByID := make(map[int]elementInfo, len(elements))
+	for _, e := range elements {
+		elementByID[e.ID] = e
+	}
+	ownership, err := loadOwnershipAtGW(cfg, args.LeagueID, rosterGW)
+	if err != nil {
+		return ClaimSimulatorOutput{}, err
+	}
+	owned := make(map[int]bool)
+	for _, r := range ownership {
+		for id := range r {
+			owned[id] = true
+		}
+	}
+	roster := make(map[int]int, len(ownership[entryID]))
+	for id := range ownership[entryID] {
+		roster[id] = elementByID[id].PositionType
+	}
+	if len(roster) == 0 {
+		return ClaimSimulatorOutput{}, notFoundf("entry %d has no roster at GW %d", entryID, rosterGW)
+	}
+
+	out := ClaimSimulatorOutput{
+		LeagueID: args.LeagueID,
+		EntryID:  entryID,
+		AsOfGW:   asOfGW,
+		RosterGW: rosterGW,
+		TargetGW: targetGW,
+		Notes: []string{
+			"Claims resolve in rounds: each round every manager, in waiver order, gets their first remaining claim whose player is still available; the order does not change between rounds.",
+			"Players dropped during processing are not claimable until waivers clear, so rivals' drops are ignored.",
+		},
+	}
+
+	limits, err := loadSquadLimits(cfg, args.LeagueID)
+	if err != nil {
+		return ClaimSimulatorOutput{}, err
+	}
+	myClaims := make([]waiverClaim, 0, len(args.Claims))
+	counts := make(map[int]int, 4)
+	for _, pos := range roster {
+		counts[pos]++
+	}
+	for i, c := range args.Claims {
+		add, ok := elementByID[c.Add]
+		if !ok {
+			return ClaimSimulatorOutput{}, invalidArgumentf("claim %d: unknown element %d", i+1, c.Add)
+		}
+		if owned[c.Add] {
+			return ClaimSimulatorOutput{}, invalidArgumentf("claim %d: %s is already rostered", i+1, add.Name)
+		}
+		if _, ok := roster[c.Drop]; !ok {
+			return ClaimSimulatorOutput{}, invalidArgumentf("claim %d: element %d is not on your roster", i+1, c.Drop)
+		}
+		wc := waiverClaim{claim: i + 1, add: c.Add, addPos: add.PositionType, drop: c.Drop, dropPos: roster[c.Drop]}
+		myClaims = append(myClaims, wc)
+		legal := claimKeepsSquadLegal(limits, counts, wc.addPos, wc.dropPos)
+		if !legal {
+			out.Warnings = append(out.Warnings, fmt.Sprintf("claim %d: adding %s for %s breaks the %d-%s limit unless an earlier claim frees a %s spot.",
+				i+1, add.Name, elementByID[c.Drop].Name, limits.At(wc.addPos), positionLabel(wc.addPos), positionLabel(wc.addPos)))
+		}
+		out.Claims = append(out.Claims, ClaimRisk{
+			Claim:    i + 1,
+			Add:      c.Add,
+			AddName:  add.Name,
+			Drop:     c.Drop,
+			DropName: elementByID[c.Drop].Name,
+			LegalNow: legal,
+		})
+	}
+
+	order, err := claimWaiverOrder(cfg, args, ld.LeagueEntries, asOfGW, &out)
+	if err != nil {
+		return ClaimSimulatorOutput{}, err
+	}
+
+	explicit := make(map[int][]waiverClaim, len(args.RivalClaims))
+	for _, r := range args.RivalClaims {
+		if _, ok := entryName[r.EntryID]; !ok || r.EntryID == entryID {
+			return ClaimSimulatorOutput{}, invalidArgumentf("rival_claims: entry %d is not another manager in league %d", r.EntryID, args.LeagueID)
+		}
+		q := make([]waiverClaim, 0, len(r.Adds))
+		for _, id := range r.Adds {
+			if owned[id] {
+				return ClaimSimulatorOutput{}, invalidArgumentf("rival_claims: element %d is already rostered", id)
+			}
+			q = append(q, waiverClaim{add: id})
+		}
+		explicit[r.EntryID] = q
+	}
+	estimate := args.EstimateRivals == nil || *args.EstimateRivals
+	var estimated []int
+	for _, id := range order {
+		if _, ok := explicit[id]; !ok && id != entryID && estimate {
+			estimated = append(estimated, id)
+		}
+	}
+	for i := range out.WaiverOrder {
+		id := out.WaiverOrder[i].EntryID
+		_, given := explicit[id]
+		out.WaiverOrder[i].Estimated = estimate && !given && id != entryID
+	}
+
+	var targets []int
+	if len(estimated) > 0 {
+		depth := args.RivalDepth
+		if depth <= 0 {
+			depth = defaultRivalDepth
+		}
+		if targets, err = rivalTargets(cfg, args.LeagueID, asOfGW, owned, depth); err != nil {
+			return ClaimSimulatorOutput{}, err
+		}
+		out.Notes = append(out.Notes, fmt.Sprintf("Managers without rival_claims are estimated from waiver_targets (horizon 5, medium risk): each claims their i-th ranked unowned target with probability %.1f/i, considering the top %d.", rivalClaimRate, depth))
+	}
+
+	trials := 1
+	if len(estimated) > 0 {
+		trials = claimSimulationTrials
+	}
+	out.Deterministic = trials == 1
+	out.Trials = trials
+	rng := rand.New(rand.NewSource(claimSimulationSeed))
+
+	type scenarioTally struct {
+		count   int
+		results []waiverResult
+		roster  map[int]int
+	}
+	scenarios := make(map[string]*scenarioTally)
+	var keys []string
+	wins := make([]int, len(myClaims))
+	lostTo := make([]map[int]int, len(myClaims))
+	for i := range lostTo {
+		lostTo[i] = make(map[int]int)
+	}
+	for trial := 0; trial < trials; trial++ {
+		queues := make(map[int][]waiverClaim, len(order))
+		queues[entryID] = myClaims
+		for id, q := range explicit {
+			queues[id] = q
+		}
+		for _, id := range estimated {
+			var q []waiverClaim
+			for i, t := range targets {
+				if rng.Float64() < rivalClaimRate/float64(i+1) {
+					q = append(q, waiverClaim{add: t})
+				}
+			}
+			queues[id] = q
+		}
+		results, final := processWaivers(limits, order, entryID, queues, roster, len(myClaims))
+		var key strings.Builder
+		for i, r := range results {
+			fmt.Fprintf(&key, "%s:%d;", r.status, r.takenBy)
+			switch r.status {
+			case claimWon:
+				wins[i]++
+			case claimTaken:
+				lostTo[i][r.takenBy]++
+			}
+		}
+		k := key.String()
+		s, ok := scenarios[k]
+		if !ok {
+			s = &scenarioTally{results: results, roster: final}
+			scenarios[k] = s
+			keys = append(keys, k)
+		}
+		s.count++
+	}
+
+	sort.SliceStable(keys, func(i, j int) bool { return scenarios[keys[i]].count > scenarios[keys[j]].count })
+	out.Scenarios = make([]ClaimScenario, 0, len(keys))
+	for _, k := range keys {
+		s := scenarios[k]
+		sc := ClaimScenario{
+			Probability: float64(s.count) / float64(trials),
+			Outcomes:    make([]ClaimOutcome, 0, len(s.results)),
+			Roster:      rosterPlayersFromIDs(s.roster, elementByID, teamShort),
+		}
+		for i, r := range s.results {
+			c := out.Claims[i]
+			sc.Outcomes = append(sc.Outcomes, ClaimOutcome{
+				Claim:    c.Claim,
+				Add:      c.Add,
+				AddName:  c.AddName,
+				Drop:     c.Drop,
+				DropName: c.DropName,
+				Status:   r.status,
+				TakenBy:  r.takenBy,
+			})
+		}
+		out.Scenarios = append(out.Scenarios, sc)
+	}
+
+	out.MostAtRisk = make([]int, 0, len(out.Claims))
+	for i := range out.Claims {
+		c := &out.Claims[i]
+		c.SuccessProbability = float64(wins[i]) / float64(trials)
+		for id, n := range lostTo[i] {
+			c.LostTo = append(c.LostTo, ClaimRival{EntryID: id, EntryName: entryName[id], Probability: float64(n) / float64(trials)})
+		}
+		sort.Slice(c.LostTo, func(a, b int) bool {
+			if c.LostTo[a].Probability != c.LostTo[b].Probability {
+				return c.LostTo[a].Probability > c.LostTo[b].Probability
+			}
+			return c.LostTo[a].EntryID < c.LostTo[b].EntryID
+		})
+		if c.SuccessProbability < 1 {
+			out.MostAtRisk = append(out.MostAtRisk, c.Claim)
+		}
+	}
+	sort.SliceStable(out.MostAtRisk, func(i, j int) bool {
+		return out.Claims[out.MostAtRisk[i]-1].SuccessProbability < out.Claims[out.MostAtRisk[j]-1].SuccessProbability
+	})
+	return out, nil
+}
+
+// claimWaiverOrder returns the entry ids in waiver priority order and fills
+// out.WaiverOrder. An explicit order may list only some managers; the rest
+// follow in default order, which is reverse standings as of asOfGW (league
+// entry order before any standings exist).
+func claimWaiverOrder(cfg ServerConfig, args ClaimSimulatorArgs, entries []summary.LeagueEntry, asOfGW int, out *ClaimSimulatorOutput) ([]int, error) {
+	nameByID := make(map[int]string, len(entries))
+	defaults := make([]int, 0, len(entries))
+	for _, e := range entries {
+		nameByID[e.EntryID] = e.EntryName
+	}
+	var standings summary.StandingsSummary
+	if asOfGW > 0 && loadSummaryInto(cfg, args.LeagueID, asOfGW, fmt.Sprintf("summary/standings/%d/gw/%d.json", args.LeagueID, asOfGW), &standings) == nil && len(standings.Rows) > 0 {
+		rows := append([]summary.StandingsRow(nil), standings.Rows...)
+		sort.Slice(rows, func(i, j int) bool { return rows[i].Rank > rows[j].Rank })
+		for _, r := range rows {
+			if _, ok := nameByID[r.EntryID]; ok {
+				defaults = append(defaults, r.EntryID)
+			}
+		}
+	} else if len(args.WaiverOrder) == 0 {
+		out.Notes = append(out.Notes, "No standings yet, so the waiver order falls back to league entry order; pass waiver_order to set it.")
+	}
+	ranked := make(map[int]bool, len(defaults))
+	for _, id := range defaults {
+		ranked[id] = true
+	}
+	for _, e := range entries {
+		if !ranked[e.EntryID] {
+			defaults = append(defaults, e.EntryID)
+		}
+	}
+
+	order := make([]int, 0, len(entries))
+	listed := make(map[int]bool, len(entries))
+	for _, id := range args.WaiverOrder {
+		if _, ok := nameByID[id]; !ok {
+			return nil, invalidArgumentf("waiver_order: entry %d is not in league %d", id, args.LeagueID)
+		}
+		if listed[id] {
+			return nil, invalidArgumentf("waiver_order: entry %d is listed twice", id)
+		}
+		listed[id] = true
+		order = append(order, id)
+	}
+	for _, id := range defaults {
+		if !listed[id] {
+			listed[id] = true
+			order = append(order, id)
+		}
+	}
+	for i, id := range order {
+		out.WaiverOrder = append(out.WaiverOrder, WaiverSlot{Priority: i + 1, EntryID: id, EntryName: nameByID[id]})
+	}
+	return order, nil
+}
+
+// rivalTargets returns the top depth unowned players from the waiver_targets
+// ranking as of gw.
+func rivalTargets(cfg ServerConfig, leagueID int, gw int, owned map[int]bool, depth int) ([]int, error) {
+	if gw < 1 {
+		return nil, notFoundf("no finished gameweek to rank waiver targets from; pass rival_claims or estimate_rivals=false")
+	}
+	relPath := fmt.Sprintf("summary/waiver_targets/%d/gw/%d_h5_risk-med.json", leagueID, gw)
+	raw, err := loadSummaryFile(cfg, leagueID, gw, relPath, []int{5}, []string{"med"})
+	if err != nil {
+		return nil, err
+	}
+	var ranking summary.WaiverTargetsSummary
+	if err := json.Unmarshal(raw, &ranking); err != nil {
+		return nil, err
+	}
+	out := make([]int, 0, depth)
+	for _, t := range ranking.Targets {
+		if len(out) == depth {
+			break
+		}
+		if !owned[t.Element] {
+			out = append(out, t.Element)
+		}
+	}
+	return out, nil
+}
+
+// rosterPlayersFromIDs turns a roster map into RosterPlayers ordered by
+// position, team and name.
+func rosterPlayersFromIDs(ids map[int]int, elementByID map[int]elementInfo, teamShort map[int]string) []summary.RosterPlayer {
+	out := make([]summary.RosterPlayer, 0, len(ids))
+	for id := range ids {
+		info := elementByID[id]
+		out = append(out, summary.RosterPlayer{Element: id, Name: info.Name, Team: teamShort[info.TeamID], PositionType: info.PositionType})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].PositionType != out[j].PositionType {
+			return out[i].PositionType < out[j].PositionType
+		}
+		if out[i].Team != out[j].Team {
+			return out[i].Team < out[j].Team
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"math"
+	"path/filepath"
+	"testing"
+)
+
+// writeClaimFixture writes league 100 after GW3 with Alpha (200), Beta (201)
+// and Gamma (202). Alpha owns Salah (1, MID), Palmer (2, MID), Gabriel (3,
+// DEF) and three forwards (6-8), so another forward needs a forward dropped.
+// Free agents: 10 and 11 (MID), 12 (FWD), 13 (DEF).
+func writeClaimFixture(t *testing.T, dir string) {
+	t.Helper()
+	el := func(id int, name string, pos int) map[string]any {
+		return map[string]any{"id": id, "web_name": name, "team": 10, "element_type": pos, "status": "a"}
+	}
+	writeJSON(t, filepath.Join(dir, "bootstrap", "bootstrap-static.json"), map[string]any{
+		"elements": []any{
+			el(1, "Salah", 3), el(2, "Palmer", 3), el(3, "Gabriel", 2), el(4, "Saka", 3), el(5, "Isak", 4),
+			el(6, "Haaland", 4), el(7, "Watkins", 4), el(8, "Wood", 4),
+			el(10, "Mbeumo", 3), el(11, "Kudus", 3), el(12, "Wissa", 4), el(13, "Munoz", 2),
+		},
+		"teams":    []any{map[string]any{"id": 10, "short_name": "LIV"}},
+		"fixtures": map[string]any{},
+	})
+	writeFullGameJSON(t, dir, 3, true, 4, false, "")
+	writeLeagueDetailsFixture(t, dir, 100, []any{
+		map[string]any{"id": 1, "entry_id": 200, "entry_name": "Alpha FC"},
+		map[string]any{"id": 2, "entry_id": 201, "entry_name": "Beta FC"},
+		map[string]any{"id": 3, "entry_id": 202, "entry_name": "Gamma FC"},
+	}, []any{})
+	picks := map[int][]int{200: {1, 2, 3, 6, 7, 8}, 201: {4}, 202: {5}}
+	choices := []any{}
+	for entry, ids := range picks {
+		for _, id := range ids {
+			choices = append(choices, map[string]any{"entry": entry, "element": id, "round": 1, "pick": 1, "index": id})
+		}
+	}
+	writeJSON(t, filepath.Join(dir, "draft/100/choices.json"), map[string]any{"choices": choices})
+	writeJSON(t, filepath.Join(dir, "league/100/transactions.json"), map[string]any{"transactions": []any{}})
+	writeJSON(t, filepath.Join(dir, "league/100/trades.json"), map[string]any{"trades": []any{}})
+}
+
+func TestBuildClaimSimulator_Deterministic(t *testing.T) {
+	dir, cfg := resourceCfg(t)
+	writeClaimFixture(t, dir)
+	me := 200
+
+	out, err := buildClaimSimulator(cfg, ClaimSimulatorArgs{
+		LeagueID: 100,
+		EntryID:  &me,
+		Claims: []ClaimInput{
+			{Add: 10, Drop: 1},
+			{Add: 11, Drop: 1}, // fallback for claim 1
+			{Add: 12, Drop: 2}, // a fourth forward
+			{Add: 13, Drop: 3},
+		},
+		WaiverOrder: []int{201, 200, 202},
+		RivalClaims: []RivalClaims{
+			{EntryID: 201, Adds: []int{10, 13}},
+			{EntryID: 202, Adds: []int{}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("buildClaimSimulator: %v", err)
+	}
+	if !out.Deterministic || out.Trials != 1 || len(out.Scenarios) != 1 {
+		t.Fatalf("deterministic=%v trials=%d scenarios=%d, want one certain scenario", out.Deterministic, out.Trials, len(out.Scenarios))
+	}
+	if out.TargetGW != 4 || out.WaiverOrder[0].EntryID != 201 || out.WaiverOrder[1].EntryID != 200 {
+		t.Errorf("target GW %d order %+v", out.TargetGW, out.WaiverOrder)
+	}
+
+	// Round 1: Beta takes 10, so Alpha's fallback 11 goes through. Round 2:
+	// the forward swap is illegal and Beta, ahead in the order, takes 13.
+	want := []struct {
+		status  string
+		takenBy int
+	}{{claimTaken, 201}, {claimWon, 0}, {claimIllegal, 0}, {claimTaken, 201}}
+	sc := out.Scenarios[0]
+	for i, w := range want {
+		if got := sc.Outcomes[i]; got.Status != w.status || got.TakenBy != w.takenBy {
+			t.Errorf("claim %d = %s/%d, want %s/%d", i+1, got.Status, got.TakenBy, w.status, w.takenBy)
+		}
+	}
+	if sc.Probability != 1 || len(sc.Roster) != 6 {
+		t.Errorf("scenario probability=%v roster=%d, want 1 and 6 players", sc.Probability, len(sc.Roster))
+	}
+	for _, p := range sc.Roster {
+		if p.Element == 1 {
+			t.Errorf("Salah still on the roster after claim 2 dropped him")
+		}
+	}
+	if out.Claims[2].LegalNow || len(out.Warnings) != 1 {
+		t.Errorf("claim 3 legal_now=%v warnings=%v, want the forward swap flagged", out.Claims[2].LegalNow, out.Warnings)
+	}
+	if got := out.MostAtRisk; len(got) != 3 || got[0] != 1 {
+		t.Errorf("most_at_risk = %v, want claims 1, 3 and 4", got)
+	}
+	if lost := out.Claims[0].LostTo; len(lost) != 1 || lost[0].EntryName != "Beta FC" || lost[0].Probability != 1 {
+		t.Errorf("claim 1 lost_to = %+v, want Beta FC always", lost)
+	}
+}
+
+func TestBuildClaimSimulator_EstimatedRivals(t *testing.T) {
+	dir, cfg := resourceCfg(t)
+	writeClaimFixture(t, dir)
+	writeJSON(t, filepath.Join(dir, "summary/standings/100/gw/3.json"), map[string]any{
+		"rows": []any{
+			map[string]any{"entry_id": 201, "rank": 1},
+			map[string]any{"entry_id": 200, "rank": 2},
+			map[string]any{"entry_id": 202, "rank": 3},
+		},
+	})
+	writeJSON(t, filepath.Join(dir, "summary/waiver_targets/100/gw/3_h5_risk-med.json"), map[string]any{
+		"targets": []any{
+			map[string]any{"element": 4}, // owned by Beta; skipped
+			map[string]any{"element": 10},
+			map[string]any{"element": 11},
+		},
+	})
+	name := "alpha"
+	out, err := buildClaimSimulator(cfg, ClaimSimulatorArgs{
+		LeagueID:  100,
+		EntryName: &name,
+		Claims:    []ClaimInput{{Add: 10, Drop: 1}},
+	})
+	if err != nil {
+		t.Fatalf("buildClaimSimulator: %v", err)
+	}
+	// Reverse standings put Gamma first; they claim 10 half the time.
+	if out.WaiverOrder[0].EntryID != 202 || !out.WaiverOrder[0].Estimated || out.WaiverOrder[1].Estimated {
+		t.Errorf("waiver order = %+v, want estimated Gamma first", out.WaiverOrder)
+	}
+	if out.Deterministic || out.Trials != claimSimulationTrials || len(out.Scenarios) != 2 {
+		t.Fatalf("deterministic=%v trials=%d scenarios=%d, want two simulated scenarios", out.Deterministic, out.Trials, len(out.Scenarios))
+	}
+	sum := 0.0
+	for _, s := range out.Scenarios {
+		sum += s.Probability
+	}
+	if math.Abs(sum-1) > 1e-9 {
+		t.Errorf("scenario probabilities sum to %v", sum)
+	}
+	c := out.Claims[0]
+	if c.SuccessProbability < 0.4 || c.SuccessProbability > 0.6 {
+		t.Errorf("success = %v, want about 0.5", c.SuccessProbability)
+	}
+	if len(c.LostTo) != 1 || c.LostTo[0].EntryID != 202 || math.Abs(c.LostTo[0].Probability+c.SuccessProbability-1) > 1e-9 {
+		t.Errorf("lost_to = %+v, want only Gamma", c.LostTo)
+	}
+}
+
+func TestBuildClaimSimulator_Errors(t *testing.T) {
+	dir, cfg := resourceCfg(t)
+	writeClaimFixture(t, dir)
+	me := 200
+	noEstimate := false
+	tests := []struct {
+		name   string
+		claims []ClaimInput
+		order  []int
+	}{
+		{"no claims", nil, nil},
+		{"add already rostered", []ClaimInput{{Add: 4, Drop: 1}}, nil},
+		{"drop not on roster", []ClaimInput{{Add: 10, Drop: 5}}, nil},
+		{"unknown entry in order", []ClaimInput{{Add: 10, Drop: 1}}, []int{999}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := buildClaimSimulator(cfg, ClaimSimulatorArgs{LeagueID: 100, EntryID: &me, Claims: tt.claims, WaiverOrder: tt.order, EstimateRivals: &noEstimate})
+			if classifyError(err).Code != codeInvalidArgument {
+				t.Errorf("err = %v, want INVALID_ARGUMENT", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/livestats"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/reconcile"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/summary"
+)
+
+// Claim urgency model. The league's historical claim rate for the add's
+// score decile sets the odds; a big last GW and rival managers weak at the
+// player's position raise them. The history is shrunk towards a prior of
+// claimPriorRate weighted as claimPriorWeight players, so a new league
+// leans on the prior until it has claims of its own.
+const (
+	claimWindowGWs     = 3
+	claimPriorRate     = 0.1
+	claimPriorWeight   = 5.0
+	claimHaulBaseline  = 4    // last-GW points with no effect on the odds
+	claimHaulPerPoint  = 0.15 // log-odds per point above the baseline
+	claimNeedPerRival  = 0.35 // log-odds per rival weakest at the position
+	claimNeedMax       = 1.4
+	urgencyHighProb    = 0.5
+	urgencyMediumProb  = 0.2
+	claimHistoryDecile = 10
+)
+
+// ClaimUrgency estimates how likely an add is to be claimed by someone else
+// this waiver cycle. Factors add up, in log-odds, to the probability.
+type ClaimUrgency struct {
+	Label       string          `json:"label"` // low|medium|high
+	Probability float64         `json:"probability"`
+	Factors     []UrgencyFactor `json:"factors"`
+}
+
+// UrgencyFactor is one term of the claim-probability model. Effect is its
+// contribution in log-odds.
+type UrgencyFactor struct {
+	Factor string  `json:"factor"`
+	Value  float64 `json:"value"`
+	Effect float64 `json:"effect"`
+	Detail string  `json:"detail"`
+}
+
+// claimHistory counts, per score decile, the free agents seen at the end of
+// each past GW and how many of them were claimed in the next one.
+type claimHistory struct {
+	Seen    [claimHistoryDecile]int
+	Claimed [claimHistoryDecile]int
+	GWs     int
+}
+
+func scoreDecile(pct float64) int {
+	return min(max(int(pct*claimHistoryDecile), 0), claimHistoryDecile-1)
+}
+
+// mineClaimHistory replays fromGW..throughGW. At the end of each GW g the
+// pool is every unowned player with minutes in the claimWindowGWs GWs to g,
+// ranked by points over them; a player counts as claimed when an approved
+// waiver or free-agent move brings them in with event g+1. GWs
+// without a live file are skipped.
+func mineClaimHistory(ownersAt func(gw int) map[int]map[int]bool, live map[int]map[int]livestats.ElementStats, txs []reconcile.Transaction, fromGW int, throughGW int) claimHistory {
+	var out claimHistory
+	claimedAt := make(map[int]map[int]bool)
+	for _, tx := range approvedTransactions(txs, fromGW+1, throughGW+1) {
+		if tx.ElementIn == 0 {
+			continue
+		}
+		if claimedAt[tx.Event] == nil {
+			claimedAt[tx.Event] = make(map[int]bool)
+		}
+		claimedAt[tx.Event][tx.ElementIn] = true
+	}
+	for g := fromGW; g <= throughGW; g++ {
+		if _, ok := live[g]; !ok {
+			continue
+		}
+		points := make(map[int]int)
+		minutes := make(map[int]int)
+		for w := g - claimWindowGWs + 1; w <= g; w++ {
+			for id, s := range live[w] {
+				points[id] += s.TotalPoints
+				minutes[id] += s.Minutes
+			}
+		}
+		owned := make(map[int]bool)
+		for _, roster := range ownersAt(g) {
+			for id := range roster {
+				owned[id] = true
+			}
+		}
+		pool := make([]int, 0)
+		for id, m := range minutes {
+			if m > 0 && !owned[id] {
+				pool = append(pool, id)
+			}
+		}
+		if len(pool) == 0 {
+			continue
+		}
+		scores := make([]int, len(pool))
+		for i, id := range pool {
+			scores[i] = points[id]
+		}
+		sort.Ints(scores)
+		for _, id := range pool {
+			pct := 1.0
+			if len(pool) > 1 {
+				pct = float64(sort.SearchInts(scores, points[id])) / float64(len(pool)-1)
+			}
+			d := scoreDecile(pct)
+			out.Seen[d]++
+			if claimedAt[g+1][id] {
+				out.Claimed[d]++
+			}
+		}
+		out.GWs++
+	}
+	return out
+}
+
+// rates returns the league-wide claim rate and decile d's, each shrunk
+// towards its prior. Decile d's prior scales the league rate by the
+// player's percentile, so an untested top decile still beats the bottom.
+func (h claimHistory) rates(pct float64) (league float64, decile float64) {
+	seen, claimed := 0, 0
+	for i := range h.Seen {
+		seen += h.Seen[i]
+		claimed += h.Claimed[i]
+	}
+	league = (float64(claimed) + claimPriorWeight*claimPriorRate) / (float64(seen) + claimPriorWeight)
+	prior := min(league*(0.25+1.5*pct), 0.95)
+	d := scoreDecile(pct)
+	decile = (float64(h.Claimed[d]) + claimPriorWeight*prior) / (float64(h.Seen[d]) + claimPriorWeight)
+	return league, decile
+}
+
+func logit(p float64) float64 {
+	p = min(max(p, 1e-6), 1-1e-6)
+	return math.Log(p / (1 - p))
+}
+
+// rivalNeeds counts the entries other than entryID whose weakest position
+// by points/GW is pos.
+func rivalNeeds(ownership map[int]map[int]bool, entryID int, elementByID map[int]elementInfo, form map[int]summary.PlayerForm) map[int]int {
+	out := make(map[int]int, 4)
+	for id, roster := range ownership {
+		if id == entryID {
+			continue
+		}
+		if w, ok := weakestPosition(roster, elementByID, form); ok {
+			out[w.position]++
+		}
+	}
+	return out
+}
+
+// claimUrgency prices one add: pct is its weighted-score percentile in the
+// candidate pool, lastGWPoints its points in the as-of GW and needers how
+// many rivals are weakest at its position.
+func claimUrgency(h claimHistory, pct float64, lastGW int, lastGWPoints int, needers int) ClaimUrgency {
+	league, decile := h.rates(pct)
+	d := scoreDecile(pct)
+	haul := min(max(claimHaulPerPoint*float64(lastGWPoints-claimHaulBaseline), -0.3), 1.2)
+	need := min(claimNeedPerRival*float64(needers), claimNeedMax)
+	factors := []UrgencyFactor{
+		{
+			Factor: "claim_history",
+			Value:  round3(league),
+			Effect: round3(logit(league)),
+			Detail: fmt.Sprintf("%.0f%% of free agents were claimed within a GW over %d past GWs", league*100, h.GWs),
+		},
+		{
+			Factor: "score_percentile",
+			Value:  round3(pct),
+			Effect: round3(logit(decile) - logit(league)),
+			Detail: fmt.Sprintf("decile %d of the pool, where %d of %d historical free agents were claimed within a GW", d+1, h.Claimed[d], h.Seen[d]),
+		},
+		{
+			Factor: "last_gw_points",
+			Value:  float64(lastGWPoints),
+			Effect: round3(haul),
+			Detail: fmt.Sprintf("%d pts in GW%d", lastGWPoints, lastGW),
+		},
+		{
+			Factor: "rival_need",
+			Value:  float64(needers),
+			Effect: round3(need),
+			Detail: fmt.Sprintf("%d rival managers are weakest at this position", needers),
+		},
+	}
+	p := 1 / (1 + math.Exp(-(logit(decile) + haul + need)))
+	label := "low"
+	switch {
+	case p >= urgencyHighProb:
+		label = "high"
+	case p >= urgencyMediumProb:
+		label = "medium"
+	}
+	return ClaimUrgency{Label: label, Probability: round3(p), Factors: factors}
+}
+
+func round3(v float64) float64 {
+	return math.Round(v*1000) / 1000
+}
+
+// loadClaimHistory mines the league's claims from startGW to the GW before
+// asOfGW and returns it with asOfGW's live stats, empty when asOfGW has no
+// live file yet.
+func loadClaimHistory(cfg ServerConfig, leagueID int, startGW int, asOfGW int) (claimHistory, map[int]livestats.ElementStats, error) {
+	timeline, err := loadOwnershipTimeline(cfg, leagueID)
+	if err != nil {
+		return claimHistory{}, nil, err
+	}
+	startGW = max(startGW, 1)
+	live := make(map[int]map[int]livestats.ElementStats)
+	for gw := max(startGW-claimWindowGWs+1, 1); gw <= asOfGW; gw++ {
+		if stats, err := loadLiveStats(cfg.RawRoot, gw); err == nil {
+			live[gw] = stats
+		}
+	}
+	history := mineClaimHistory(timeline.at, live, timeline.transactions, startGW, asOfGW-1)
+	return history, live[asOfGW], nil
+}
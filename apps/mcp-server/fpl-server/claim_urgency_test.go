@@ -0,0 +1,95 @@
+package main
+
+import (
+	"math"
+	"testing"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/livestats"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/reconcile"
+)
+
+// claimFixture: free agents 1-10 score their id in points every GW; 99 is
+// rostered throughout. 10 and 9 are claimed for GW2, 8 for GW3.
+func claimFixture() (func(int) map[int]map[int]bool, map[int]map[int]livestats.ElementStats, []reconcile.Transaction) {
+	live := make(map[int]map[int]livestats.ElementStats)
+	for gw := 1; gw <= 3; gw++ {
+		live[gw] = map[int]livestats.ElementStats{99: {Minutes: 90, TotalPoints: 20}}
+		for id := 1; id <= 10; id++ {
+			live[gw][id] = livestats.ElementStats{Minutes: 90, TotalPoints: id}
+		}
+	}
+	owners := func(gw int) map[int]map[int]bool {
+		roster := map[int]bool{99: true}
+		if gw >= 2 {
+			roster[10], roster[9] = true, true
+		}
+		if gw >= 3 {
+			roster[8] = true
+		}
+		return map[int]map[int]bool{200: roster}
+	}
+	txs := []reconcile.Transaction{
+		{ID: 1, Event: 2, Kind: "w", Result: "a", ElementIn: 10, ElementOut: 50, Entry: 200},
+		{ID: 2, Event: 2, Kind: "f", Result: "a", ElementIn: 9, ElementOut: 51, Entry: 200},
+		{ID: 3, Event: 2, Kind: "w", Result: "do", ElementIn: 1, ElementOut: 52, Entry: 200}, // lost the claim
+		{ID: 4, Event: 3, Kind: "w", Result: "a", ElementIn: 8, ElementOut: 53, Entry: 200},
+		{ID: 5, Event: 4, Kind: "w", Result: "a", ElementIn: 7, ElementOut: 54, Entry: 200}, // after the window
+	}
+	return owners, live, txs
+}
+
+func TestMineClaimHistory(t *testing.T) {
+	owners, live, txs := claimFixture()
+	got := mineClaimHistory(owners, live, txs, 1, 2)
+
+	// GW1: ten free agents, one per decile; 9 and 10 claimed. GW2: the
+	// eight left spread over deciles 0-9 by (id-1)/7; 8, in decile 9, is
+	// claimed.
+	wantSeen := [claimHistoryDecile]int{2, 2, 2, 1, 2, 2, 1, 2, 2, 2}
+	wantClaimed := [claimHistoryDecile]int{8: 1, 9: 2}
+	if got.Seen != wantSeen || got.Claimed != wantClaimed || got.GWs != 2 {
+		t.Errorf("history = %+v, want seen %v claimed %v over 2 GWs", got, wantSeen, wantClaimed)
+	}
+
+	league, top := got.rates(1)
+	if math.Abs(league-3.5/23) > 1e-9 {
+		t.Errorf("league rate = %.4f, want (3 + 0.5) / (18 + 5)", league)
+	}
+	if want := (2 + 5*league*1.75) / 7; math.Abs(top-want) > 1e-9 {
+		t.Errorf("top decile rate = %.4f, want %.4f", top, want)
+	}
+
+	// Without history the prior alone ranks the top of the pool above the
+	// bottom.
+	_, empty := claimHistory{}.rates(1)
+	_, emptyLow := claimHistory{}.rates(0)
+	if empty <= emptyLow || math.Abs(empty-claimPriorRate*1.75) > 1e-9 {
+		t.Errorf("no-history rates = %.4f top, %.4f bottom", empty, emptyLow)
+	}
+}
+
+func TestClaimUrgency(t *testing.T) {
+	owners, live, txs := claimFixture()
+	h := mineClaimHistory(owners, live, txs, 1, 2)
+
+	hot := claimUrgency(h, 1, 3, 12, 2)
+	if hot.Label != "high" || hot.Probability != 0.859 {
+		t.Errorf("top-decile haul = %+v, want high at 0.859", hot)
+	}
+	logOdds := 0.0
+	for _, f := range hot.Factors {
+		logOdds += f.Effect
+	}
+	if p := 1 / (1 + math.Exp(-logOdds)); math.Abs(p-hot.Probability) > 0.002 {
+		t.Errorf("factors sum to p = %.3f, want %.3f", p, hot.Probability)
+	}
+
+	cold := claimUrgency(h, 0, 3, 0, 0)
+	if cold.Label != "low" || cold.Probability >= 0.05 {
+		t.Errorf("bottom-decile blank = %+v, want low", cold)
+	}
+	mid := claimUrgency(claimHistory{}, 1, 3, 6, 1)
+	if mid.Label != "medium" {
+		t.Errorf("top of the pool in a new league = %+v, want medium", mid)
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// coldStartGWs is how many finished GWs the history-based tools need before
+// their usual rules mean anything. Below it (coldStart) they fall back to
+// what bootstrap knows and say so.
+const coldStartGWs = 3
+
+// strengthBaseline is the fixture score an average opponent gets from the
+// team-strength fallback; stronger sides score lower, weaker ones higher.
+const strengthBaseline = 10.0
+
+// coldStart reports whether asOfGW is too early in the season for the
+// history-based rules.
+func coldStart(asOfGW int) bool {
+	return asOfGW < coldStartGWs
+}
+
+// coldStartEligible is the waiver minutes rule before there are 3 GWs to
+// look back over: a player who has started a match this season (bootstrap's
+// starts, or a 60-minute total where starts isn't counted). Until anyone has
+// played (see seasonStarted) every player qualifies.
+func coldStartEligible(info elementInfo, anyPlayed bool) bool {
+	if !anyPlayed {
+		return true
+	}
+	return info.Starts > 0 || info.Minutes >= 60
+}
+
+// seasonStarted reports whether bootstrap credits any player with minutes.
+func seasonStarted(elements []elementInfo) bool {
+	for _, e := range elements {
+		if e.Minutes > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// teamStrength is a team's bootstrap strength ratings (roughly 1000-1400).
+type teamStrength struct {
+	ID          int `json:"id"`
+	AttackHome  int `json:"strength_attack_home"`
+	AttackAway  int `json:"strength_attack_away"`
+	DefenceHome int `json:"strength_defence_home"`
+	DefenceAway int `json:"strength_defence_away"`
+}
+
+// loadTeamStrengths reads the teams' strength ratings from
+// bootstrap-static.json, leaving out teams with none.
+func loadTeamStrengths(rawRoot string) (map[int]teamStrength, error) {
+	raw, err := os.ReadFile(filepath.Join(rawRoot, "bootstrap", "bootstrap-static.json"))
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Teams []teamStrength `json:"teams"`
+	}
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return nil, err
+	}
+	out := make(map[int]teamStrength, len(resp.Teams))
+	for _, t := range resp.Teams {
+		if t.AttackHome > 0 && t.AttackAway > 0 && t.DefenceHome > 0 && t.DefenceAway > 0 {
+			out[t.ID] = t
+		}
+	}
+	return out, nil
+}
+
+// strengthConceded stands in for computePointsConcededByPosition when there
+// is no live history: it is keyed the same way (opponent, then the venue of
+// the player's side, then position), so fixtureDifficulty reads it
+// unchanged. GK and DEF face the opponent's attack and MID and FWD its
+// defence, each rated at the opponent's venue. A rating equal to the
+// league's average for that rating scores strengthBaseline.
+func strengthConceded(strengths map[int]teamStrength) map[int]map[string]map[int]avgStat {
+	out := make(map[int]map[string]map[int]avgStat, len(strengths))
+	if len(strengths) == 0 {
+		return out
+	}
+	// rating is t's attack or defence rating at the opponent's venue.
+	rating := func(t teamStrength, attack bool, oppHome bool) int {
+		switch {
+		case attack && oppHome:
+			return t.AttackHome
+		case attack:
+			return t.AttackAway
+		case oppHome:
+			return t.DefenceHome
+		default:
+			return t.DefenceAway
+		}
+	}
+	mean := func(attack bool, oppHome bool) float64 {
+		sum := 0
+		for _, t := range strengths {
+			sum += rating(t, attack, oppHome)
+		}
+		return float64(sum) / float64(len(strengths))
+	}
+	for id, t := range strengths {
+		out[id] = map[string]map[int]avgStat{"HOME": {}, "AWAY": {}}
+		for _, venue := range []string{"HOME", "AWAY"} {
+			// A player at home faces the opponent away, and vice versa.
+			oppHome := venue == "AWAY"
+			for pos := 1; pos <= 4; pos++ {
+				attack := pos <= 2
+				score := strengthBaseline * mean(attack, oppHome) / float64(rating(t, attack, oppHome))
+				out[id][venue][pos] = avgStat{Sum: score, Count: 1}
+			}
+		}
+	}
+	return out
+}
+
+// coldStartConceded swaps in strengthConceded for both conceded maps when
+// neither has any history, reporting whether it did.
+func coldStartConceded(rawRoot string, season, recent map[int]map[string]map[int]avgStat) (map[int]map[string]map[int]avgStat, map[int]map[string]map[int]avgStat, bool) {
+	if len(season) > 0 || len(recent) > 0 {
+		return season, recent, false
+	}
+	strengths, err := loadTeamStrengths(rawRoot)
+	if err != nil || len(strengths) == 0 {
+		return season, recent, false
+	}
+	fallback := strengthConceded(strengths)
+	return fallback, fallback, true
+}
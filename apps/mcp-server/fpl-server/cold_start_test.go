@@ -0,0 +1,216 @@
+package main
+
+import (
+	"encoding/json"
+	"math"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeGW1Fixture writes a raw tree holding GW1 and nothing earlier: league
+// 100 with Alpha (200) and Beta (201), two PL teams of unequal strength and
+// GW2's fixture ARS (home) v BUR. live is GW1's live.json elements, or nil
+// before GW1 has been played; finished sets current_event_finished.
+func writeGW1Fixture(t *testing.T, dir string, live map[string]any, finished bool) {
+	t.Helper()
+	el := func(id int, name string, team, pos, minutes, starts int) map[string]any {
+		return map[string]any{"id": id, "web_name": name, "team": team, "element_type": pos, "status": "a", "minutes": minutes, "starts": starts}
+	}
+	minutes := func(m int) int {
+		if live == nil {
+			return 0
+		}
+		return m
+	}
+	starts := func(m int) int {
+		if minutes(m) >= 60 {
+			return 1
+		}
+		return 0
+	}
+	// Alpha owns 1-3; 4-8 are free agents. 5 came off the bench and 6
+	// didn't play.
+	players := []struct {
+		id            int
+		name          string
+		team, pos, mn int
+	}{
+		{1, "Raya", 1, 1, 90}, {2, "Saliba", 1, 2, 90}, {3, "Saka", 1, 3, 90},
+		{4, "Odegaard", 1, 3, 85}, {5, "Trossard", 1, 3, 20}, {6, "Nwaneri", 1, 3, 0},
+		{7, "Foster", 2, 4, 90}, {8, "Esteve", 2, 2, 90},
+	}
+	elements := []any{}
+	for _, p := range players {
+		elements = append(elements, el(p.id, p.name, p.team, p.pos, minutes(p.mn), starts(p.mn)))
+	}
+	writeJSON(t, filepath.Join(dir, "bootstrap", "bootstrap-static.json"), map[string]any{
+		"elements": elements,
+		"teams": []any{
+			map[string]any{"id": 1, "short_name": "ARS", "strength_attack_home": 1350, "strength_attack_away": 1300, "strength_defence_home": 1350, "strength_defence_away": 1300},
+			map[string]any{"id": 2, "short_name": "BUR", "strength_attack_home": 1050, "strength_attack_away": 1000, "strength_defence_home": 1050, "strength_defence_away": 1000},
+		},
+		"fixtures": map[string]any{
+			"1": []any{map[string]any{"id": 1, "event": 1, "team_h": 2, "team_a": 1}},
+			"2": []any{map[string]any{"id": 2, "event": 2, "team_h": 1, "team_a": 2}},
+		},
+	})
+	writeFullGameJSON(t, dir, 1, finished, 2, false, "")
+	if live != nil {
+		writeJSON(t, filepath.Join(dir, "gw", "1", "live.json"), map[string]any{
+			"elements": live,
+			"fixtures": []any{map[string]any{"id": 1, "event": 1, "team_h": 2, "team_a": 1}},
+		})
+	}
+	writeLeagueDetailsFixture(t, dir, 100, []any{
+		map[string]any{"id": 1, "entry_id": 200, "entry_name": "Alpha FC"},
+		map[string]any{"id": 2, "entry_id": 201, "entry_name": "Beta FC"},
+	}, []any{map[string]any{"event": 2, "finished": false, "league_entry_1": 1, "league_entry_2": 2}})
+	choices := []any{}
+	for i, id := range []int{1, 2, 3} {
+		choices = append(choices, map[string]any{"entry": 200, "element": id, "round": i + 1, "pick": 1, "index": i + 1})
+	}
+	writeJSON(t, filepath.Join(dir, "draft/100/choices.json"), map[string]any{"choices": choices})
+	writeJSON(t, filepath.Join(dir, "league/100/transactions.json"), map[string]any{"transactions": []any{}})
+	writeJSON(t, filepath.Join(dir, "league/100/trades.json"), map[string]any{"trades": []any{}})
+}
+
+// gw1Live is GW1's live stats for writeGW1Fixture's players.
+func gw1Live() map[string]any {
+	stat := func(pts, minutes int) map[string]any {
+		return map[string]any{"stats": map[string]any{"total_points": pts, "minutes": minutes}}
+	}
+	return map[string]any{
+		"1": stat(6, 90), "2": stat(6, 90), "3": stat(8, 90), "4": stat(5, 85),
+		"5": stat(1, 20), "6": stat(0, 0), "7": stat(2, 90), "8": stat(1, 90),
+	}
+}
+
+func TestColdStartEligible(t *testing.T) {
+	started := elementInfo{Starts: 1, Minutes: 85}
+	sub := elementInfo{Minutes: 20}
+	noStarts := elementInfo{Minutes: 90} // a payload without starts
+	if !coldStartEligible(started, true) || coldStartEligible(sub, true) || !coldStartEligible(noStarts, true) {
+		t.Error("after GW1 a starter (or 60+ minutes) qualifies and a substitute doesn't")
+	}
+	if !coldStartEligible(elementInfo{}, false) {
+		t.Error("before anyone has played every player qualifies")
+	}
+	if seasonStarted([]elementInfo{{}, {}}) || !seasonStarted([]elementInfo{{}, sub}) {
+		t.Error("seasonStarted should look for any minutes")
+	}
+	if !coldStart(0) || !coldStart(2) || coldStart(3) {
+		t.Errorf("cold start should cover GWs below %d", coldStartGWs)
+	}
+}
+
+func TestStrengthConceded(t *testing.T) {
+	strengths := map[int]teamStrength{
+		1: {ID: 1, AttackHome: 1400, AttackAway: 1300, DefenceHome: 1200, DefenceAway: 1100},
+		2: {ID: 2, AttackHome: 1000, AttackAway: 1100, DefenceHome: 1000, DefenceAway: 900},
+	}
+	c := strengthConceded(strengths)
+	score := func(opp int, venue string, pos int) float64 {
+		return fixtureDifficulty(c, opp, venue, pos)
+	}
+	// A forward at home faces team 2's away defence, 900 against a mean
+	// of 1000: 10 * 1000/900.
+	if got := score(2, "HOME", 4); math.Abs(got-11.111) > 1e-3 {
+		t.Errorf("FWD v weak away defence = %.3f, want 11.111", got)
+	}
+	// A defender away faces team 1's home attack, 1400 against 1200.
+	if got := score(1, "AWAY", 2); math.Abs(got-8.571) > 1e-3 {
+		t.Errorf("DEF v strong home attack = %.3f, want 8.571", got)
+	}
+	for pos := 1; pos <= 4; pos++ {
+		for _, venue := range []string{"HOME", "AWAY"} {
+			if score(2, venue, pos) <= score(1, venue, pos) {
+				t.Errorf("%s %s: the weaker side should be the easier fixture", positionLabel(pos), venue)
+			}
+		}
+	}
+}
+
+func TestBuildFixtureDifficulty_ColdStartStrengths(t *testing.T) {
+	// GW1 is under way with no live data yet: nothing has been conceded.
+	dir, cfg := resourceCfg(t)
+	writeGW1Fixture(t, dir, nil, false)
+	next := 2
+	out, err := buildFixtureDifficulty(cfg, FixtureDifficultyArgs{LeagueID: 100, NextGW: &next})
+	if err != nil {
+		t.Fatalf("buildFixtureDifficulty: %v", err)
+	}
+	if !out.ColdStart || len(out.Notes) != 2 || !strings.Contains(out.Notes[1], "team strength") {
+		t.Errorf("cold start = %v notes %v, want the strength fallback noted", out.ColdStart, out.Notes)
+	}
+	// ARS, at home to the weaker BUR, has the better fixture everywhere.
+	for pos, items := range out.Positions {
+		if len(items) != 2 || items[0].TeamShort != "ARS" {
+			t.Errorf("%s = %+v, want ARS first", pos, items)
+		}
+	}
+}
+
+func TestBuildFixtureDifficulty_GW1History(t *testing.T) {
+	// With GW1 played, its one match is the history: still cold, no fallback.
+	dir, cfg := resourceCfg(t)
+	writeGW1Fixture(t, dir, gw1Live(), true)
+	next := 2
+	out, err := buildFixtureDifficulty(cfg, FixtureDifficultyArgs{LeagueID: 100, NextGW: &next})
+	if err != nil {
+		t.Fatalf("buildFixtureDifficulty: %v", err)
+	}
+	if !out.ColdStart || len(out.Notes) != 1 {
+		t.Errorf("cold start = %v notes %v, want one cold-start note", out.ColdStart, out.Notes)
+	}
+}
+
+func TestBuildWaiverRecommendations_ColdStart(t *testing.T) {
+	dir, cfg := resourceCfg(t)
+	cfg.ComputeMissing = true
+	writeGW1Fixture(t, dir, gw1Live(), true)
+	alpha, limit := 200, 10
+	raw, err := buildWaiverRecommendations(cfg, WaiverRecommendationsArgs{LeagueID: 100, EntryID: &alpha, Limit: &limit})
+	if err != nil {
+		t.Fatalf("buildWaiverRecommendations: %v", err)
+	}
+	var report WaiverRecommendationsReport
+	if err := json.Unmarshal(raw, &report); err != nil {
+		t.Fatal(err)
+	}
+	if !report.ColdStart || report.AsOfGW != 1 || report.TargetGW != 2 {
+		t.Fatalf("cold_start=%v as_of=%d target=%d", report.ColdStart, report.AsOfGW, report.TargetGW)
+	}
+	// The 60-minute rule would leave no one; starters qualify, the
+	// substitute (5) and the unused player (6) don't.
+	got := map[int]bool{}
+	for _, a := range report.Adds {
+		got[a.Element] = true
+		if !a.Availability.ColdStartEligible || a.Score.ConsistencyLabel != consistencyInsufficient {
+			t.Errorf("add %d availability %+v label %q", a.Element, a.Availability, a.Score.ConsistencyLabel)
+		}
+	}
+	if len(got) != 3 || !got[4] || !got[7] || !got[8] {
+		t.Errorf("adds = %v, want the GW1 starters 4, 7 and 8", got)
+	}
+	cold := false
+	for _, n := range report.Notes {
+		cold = cold || strings.HasPrefix(n, "Cold start: 1 GWs finished")
+	}
+	if !cold {
+		t.Errorf("notes = %v, want the cold-start note", report.Notes)
+	}
+}
+
+func TestBuildPlayerConsistency_ColdStart(t *testing.T) {
+	dir, cfg := resourceCfg(t)
+	writeGW1Fixture(t, dir, gw1Live(), true)
+	id := 3
+	out, err := buildPlayerConsistency(cfg, PlayerConsistencyArgs{ElementID: &id})
+	if err != nil {
+		t.Fatalf("buildPlayerConsistency: %v", err)
+	}
+	if !out.ColdStart || len(out.Notes) != 1 || out.All.Label != consistencyInsufficient || out.Played60.Label != consistencyInsufficient {
+		t.Errorf("cold start = %v notes %v labels %s/%s", out.ColdStart, out.Notes, out.All.Label, out.Played60.Label)
+	}
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/summary"
+)
+
+// A fixture the team goes into with under shortRestDays since its previous
+// kickoff is congested; its fixture score loses the congestion penalty
+// (defaultCongestionPenalty unless the caller sets one).
+const (
+	shortRestDays            = 4
+	congestionWindowDays     = 14
+	defaultCongestionPenalty = 0.1
+	// congestionLookbackGWs is how many finished GWs of live fixtures are
+	// read for previous kickoffs; bootstrap only lists upcoming ones.
+	congestionLookbackGWs = 3
+)
+
+// Congestion is a team's load going into one fixture. RestDays is the time
+// since the team's previous kickoff in days, nil when either kickoff is
+// unknown (the API sends a null kickoff_time for unscheduled matches).
+// MatchesLast14Days counts the team's kickoffs in the 14 days before this
+// one. Penalty is the fraction taken off this fixture's score.
+type Congestion struct {
+	RestDays           *float64 `json:"rest_days,omitempty"`
+	PreviousKickoffUTC string   `json:"previous_kickoff_utc,omitempty"`
+	MatchesLast14Days  int      `json:"matches_last_14_days"`
+	ShortRest          bool     `json:"short_rest,omitempty"`
+	Penalty            float64  `json:"penalty,omitempty"`
+}
+
+// loadTeamKickoffs gathers every known kickoff per team, sorted, from the
+// bootstrap fixtures up to throughGW and the live fixtures of the last
+// congestionLookbackGWs GWs up to asOfGW. A fixture in both counts once.
+func loadTeamKickoffs(rawRoot string, bootstrapFixtures map[int][]fixture, asOfGW int, throughGW int) map[int][]time.Time {
+	all := make([]fixture, 0)
+	for gw, list := range bootstrapFixtures {
+		if gw <= throughGW {
+			all = append(all, list...)
+		}
+	}
+	for gw := max(asOfGW-congestionLookbackGWs+1, 1); gw <= asOfGW; gw++ {
+		// A GW without live data just contributes nothing.
+		if data, err := loadLiveGWData(rawRoot, gw); err == nil {
+			all = append(all, data.Fixtures...)
+		}
+	}
+	return teamKickoffs(all)
+}
+
+// teamKickoffs returns each team's kickoffs in fixtures, sorted, counting a
+// fixture id once. Fixtures without a readable kickoff are skipped.
+func teamKickoffs(fixtures []fixture) map[int][]time.Time {
+	seen := make(map[int]bool, len(fixtures))
+	out := make(map[int][]time.Time)
+	for _, f := range fixtures {
+		if seen[f.ID] {
+			continue
+		}
+		at, ok := summary.ParseAPITime(f.Kickoff)
+		if !ok {
+			continue
+		}
+		seen[f.ID] = true
+		out[f.TeamH] = append(out[f.TeamH], at)
+		out[f.TeamA] = append(out[f.TeamA], at)
+	}
+	for team := range out {
+		sort.Slice(out[team], func(i, j int) bool { return out[team][i].Before(out[team][j]) })
+	}
+	return out
+}
+
+// congestionBefore measures a team's rest and load going into a kickoff at
+// at, given all of the team's kickoffs.
+func congestionBefore(kickoffs []time.Time, at time.Time, penalty float64) Congestion {
+	var c Congestion
+	windowStart := at.Add(-congestionWindowDays * 24 * time.Hour)
+	var prev time.Time
+	for _, k := range kickoffs {
+		if !k.Before(at) {
+			break
+		}
+		prev = k
+		if !k.Before(windowStart) {
+			c.MatchesLast14Days++
+		}
+	}
+	if prev.IsZero() {
+		return c
+	}
+	rest := math.Round(at.Sub(prev).Hours()/24*10) / 10
+	c.RestDays = &rest
+	c.PreviousKickoffUTC = prev.Format(time.RFC3339)
+	if rest < shortRestDays {
+		c.ShortRest = true
+		c.Penalty = penalty
+	}
+	return c
+}
+
+// annotateCongestion sets Congestion on each fixture in index from its
+// kickoff in fixtures. Fixtures with no kickoff are left without one.
+func annotateCongestion(index map[int][]FixtureContext, fixtures []fixture, kickoffs map[int][]time.Time, penalty float64) {
+	kickoffByID := make(map[int]time.Time, len(fixtures))
+	for _, f := range fixtures {
+		if at, ok := summary.ParseAPITime(f.Kickoff); ok {
+			kickoffByID[f.ID] = at
+		}
+	}
+	for team, list := range index {
+		for i := range list {
+			at, ok := kickoffByID[list[i].FixtureID]
+			if !ok {
+				continue
+			}
+			c := congestionBefore(kickoffs[team], at, penalty)
+			list[i].Congestion = &c
+		}
+	}
+}
+
+// congestionFactor is what a fixture's score is multiplied by for
+// congestion: 1 less its penalty.
+func congestionFactor(fx FixtureContext) float64 {
+	if fx.Congestion == nil {
+		return 1
+	}
+	return 1 - fx.Congestion.Penalty
+}
+
+// congestionReason explains a short-rest fixture for the add reasons, or
+// returns "" when the fixture isn't congested.
+func congestionReason(fx FixtureContext) string {
+	c := fx.Congestion
+	if c == nil || !c.ShortRest || c.RestDays == nil {
+		return ""
+	}
+	after := "the previous game"
+	if prev, ok := summary.ParseAPITime(c.PreviousKickoffUTC); ok {
+		switch prev.Weekday() {
+		case time.Tuesday, time.Wednesday, time.Thursday:
+			after = "a midweek game"
+		}
+	}
+	return fmt.Sprintf("only %.1f days rest after %s before %s (%d matches in 14 days); fixture score -%.0f%%",
+		*c.RestDays, after, fx.OpponentShort, c.MatchesLast14Days, c.Penalty*100)
+}
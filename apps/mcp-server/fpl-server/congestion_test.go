@@ -0,0 +1,84 @@
+package main
+
+import (
+	"math"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestCongestion_AcrossGWBoundary has team 10's GW4 Sunday fixture come
+// three days after a Thursday match in GW3, which only the GW3 live.json
+// knows about; bootstrap lists GW4 alone.
+func TestCongestion_AcrossGWBoundary(t *testing.T) {
+	dir := t.TempDir()
+	live := func(gw int, fixtures ...any) {
+		writeJSON(t, filepath.Join(dir, "gw", itoa(gw), "live.json"), map[string]any{"elements": map[string]any{}, "fixtures": fixtures})
+	}
+	live(1, map[string]any{"id": 10, "team_h": 10, "team_a": 12, "kickoff_time": "2025-08-30T14:00:00Z"})
+	live(2, map[string]any{"id": 20, "team_h": 12, "team_a": 10, "kickoff_time": "2025-09-13T14:00:00Z"})
+	live(3, map[string]any{"id": 30, "team_h": 10, "team_a": 11, "kickoff_time": "2025-09-18T19:00:00Z"})
+	gw4 := []fixture{
+		{ID: 40, Event: 4, TeamH: 10, TeamA: 12, Kickoff: "2025-09-21T13:00:00Z"},
+		{ID: 41, Event: 4, TeamH: 11, TeamA: 13}, // kickoff_time null
+		{ID: 42, Event: 4, TeamH: 14, TeamA: 15, Kickoff: "2025-09-21T15:30:00Z"},
+	}
+	// The GW3 fixture is also still in bootstrap; it must count once.
+	bootstrap := map[int][]fixture{3: {{ID: 30, Event: 3, TeamH: 10, TeamA: 11, Kickoff: "2025-09-18T19:00:00Z"}}, 4: gw4}
+
+	kickoffs := loadTeamKickoffs(dir, bootstrap, 3, 4)
+	if got := len(kickoffs[10]); got != 4 {
+		t.Fatalf("team 10 kickoffs = %v, want 4", kickoffs[10])
+	}
+	teamShort := map[int]string{10: "ARS", 11: "AVL", 12: "BHA", 13: "BOU", 14: "BRE", 15: "BUR"}
+	idx := buildFixtureIndex(gw4, teamShort)
+	annotateCongestion(idx, gw4, kickoffs, 0.1)
+
+	c := idx[10][0].Congestion
+	if c == nil || c.RestDays == nil {
+		t.Fatalf("team 10 congestion = %+v, want rest days", c)
+	}
+	// Thursday 19:00 to Sunday 13:00 is 2.75 days; the Aug 30 match is
+	// outside the 14-day window.
+	if *c.RestDays != 2.8 || !c.ShortRest || c.Penalty != 0.1 || c.MatchesLast14Days != 2 || c.PreviousKickoffUTC != "2025-09-18T19:00:00Z" {
+		t.Errorf("team 10 congestion = %+v (rest %v)", c, *c.RestDays)
+	}
+	if f := congestionFactor(idx[10][0]); math.Abs(f-0.9) > 1e-9 {
+		t.Errorf("congestion factor = %v, want 0.9", f)
+	}
+	if r := congestionReason(idx[10][0]); !strings.HasPrefix(r, "only 2.8 days rest after a midweek game before BHA") {
+		t.Errorf("reason = %q", r)
+	}
+	// Opponent 12 last played on the Saturday eight days earlier.
+	if c := idx[12][0].Congestion; c == nil || c.ShortRest || c.Penalty != 0 || *c.RestDays != 8 {
+		t.Errorf("team 12 congestion = %+v", c)
+	}
+
+	if c := idx[11][0].Congestion; c != nil {
+		t.Errorf("fixture with a null kickoff got congestion %+v", c)
+	}
+	if congestionFactor(idx[11][0]) != 1 || congestionReason(idx[11][0]) != "" {
+		t.Errorf("fixture with a null kickoff is penalized")
+	}
+	// No earlier match known: no rest figure and no penalty.
+	if c := idx[14][0].Congestion; c == nil || c.RestDays != nil || c.ShortRest || c.MatchesLast14Days != 0 {
+		t.Errorf("team 14 congestion = %+v", c)
+	}
+}
+
+func TestSumFixtureScores_CongestionPenalty(t *testing.T) {
+	conceded := map[int]map[string]map[int]avgStat{
+		11: {"HOME": {3: {Sum: 8, Count: 2}}, "AWAY": {3: {Sum: 8, Count: 2}}},
+	}
+	rest := 3.0
+	fresh := []FixtureContext{{OpponentID: 11, Venue: "AWAY"}}
+	tired := []FixtureContext{{OpponentID: 11, Venue: "AWAY", Congestion: &Congestion{RestDays: &rest, ShortRest: true, Penalty: 0.25}}}
+	s1, r1, b1 := sumFixtureScores(fresh, conceded, conceded, 3, 0.5, 0.5)
+	s2, r2, b2 := sumFixtureScores(tired, conceded, conceded, 3, 0.5, 0.5)
+	if b1 == 0 || math.Abs(b2-0.75*b1) > 1e-9 {
+		t.Errorf("blended = %v then %v, want a quarter off", b1, b2)
+	}
+	if s1 != s2 || r1 != r2 {
+		t.Errorf("season/recent changed by congestion: %v/%v vs %v/%v", s1, r1, s2, r2)
+	}
+}
@@ -35,11 +35,13 @@ type CurrentRosterOutput struct {
 	Gameweek  int                `json:"gameweek"`
 	Starters  []RosterPlayerInfo `json:"starters"`
 	Bench     []RosterPlayerInfo `json:"bench"`
+	// PlayerNamesUnavailable is playerNames.Unavailable.
+	PlayerNamesUnavailable bool `json:"player_names_unavailable,omitempty"`
 }
 
 func buildCurrentRoster(cfg ServerConfig, args CurrentRosterArgs) (CurrentRosterOutput, error) {
 	if args.LeagueID == 0 {
-		return CurrentRosterOutput{}, fmt.Errorf("league_id is required")
+		return CurrentRosterOutput{}, invalidArgumentf("league_id is required")
 	}
 
 	// Resolve gameweek.
@@ -78,22 +80,18 @@ func buildCurrentRoster(cfg ServerConfig, args CurrentRosterArgs) (CurrentRoster
 			name = strings.TrimSpace(*args.EntryName)
 		}
 		if name == "" {
-			return CurrentRosterOutput{}, fmt.Errorf("entry_id or entry_name is required")
+			return CurrentRosterOutput{}, invalidArgumentf("entry_id or entry_name is required")
 		}
-		for _, e := range details.LeagueEntries {
-			if strings.EqualFold(e.EntryName, name) || strings.EqualFold(e.ShortName, name) {
-				entryID = e.EntryID
-				break
-			}
-		}
-		if entryID == 0 {
-			return CurrentRosterOutput{}, fmt.Errorf("no entry found for name: %s", name)
+		id, err := resolveEntry(details.LeagueEntries, name)
+		if err != nil {
+			return CurrentRosterOutput{}, err
 		}
+		entryID = id
 	}
 
 	entryName := nameByEntry[entryID]
 	if entryName == "" {
-		return CurrentRosterOutput{}, fmt.Errorf("entry not found: %d", entryID)
+		return CurrentRosterOutput{}, notFoundf("entry not found: %d", entryID)
 	}
 
 	// Load the entry snapshot for this gameweek.
@@ -113,14 +111,10 @@ func buildCurrentRoster(cfg ServerConfig, args CurrentRosterArgs) (CurrentRoster
 	}
 
 	// Build player metadata map from bootstrap.
-	elements, teamShort, _, err := loadBootstrapData(cfg.RawRoot)
+	players, err := loadPlayerNames(cfg.RawRoot)
 	if err != nil {
 		return CurrentRosterOutput{}, err
 	}
-	playerByID := make(map[int]elementInfo, len(elements))
-	for _, e := range elements {
-		playerByID[e.ID] = e
-	}
 
 	starters := make([]RosterPlayerInfo, 0, 11)
 	bench := make([]RosterPlayerInfo, 0, 4)
@@ -129,14 +123,14 @@ func buildCurrentRoster(cfg ServerConfig, args CurrentRosterArgs) (CurrentRoster
 		// (e.g. data freshness gap, mid-season player addition).  A zero-value
 		// struct would produce blank Name/Team and PositionType 0, silently
 		// corrupting the roster output.
-		meta, ok := playerByID[p.Element]
+		meta, ok := players.get(p.Element)
 		if !ok {
 			continue
 		}
 		info := RosterPlayerInfo{
 			Element:      p.Element,
 			Name:         meta.Name,
-			Team:         teamShort[meta.TeamID],
+			Team:         players.team(meta.TeamID),
 			PositionType: meta.PositionType,
 			PositionSlot: p.Position,
 			OnBench:      p.Position > 11,
@@ -149,11 +143,12 @@ func buildCurrentRoster(cfg ServerConfig, args CurrentRosterArgs) (CurrentRoster
 	}
 
 	return CurrentRosterOutput{
-		LeagueID:  args.LeagueID,
-		EntryID:   entryID,
-		EntryName: entryName,
-		Gameweek:  resolvedGW,
-		Starters:  starters,
-		Bench:     bench,
+		LeagueID:               args.LeagueID,
+		EntryID:                entryID,
+		EntryName:              entryName,
+		Gameweek:               resolvedGW,
+		Starters:               starters,
+		Bench:                  bench,
+		PlayerNamesUnavailable: players.Unavailable,
 	}, nil
 }
@@ -0,0 +1,377 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/ledger"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/summary"
+)
+
+// checklistFormWindow is the GWs of form behind this GW's points projections.
+const checklistFormWindow = 5
+
+// DeadlineChecklistArgs are the input arguments for the deadline_checklist tool.
+type DeadlineChecklistArgs struct {
+	LeagueID int    `json:"league_id" jsonschema:"Draft league id (required)"`
+	EntryID  int    `json:"entry_id" jsonschema:"Entry id (required)"`
+	TZ       string `json:"tz,omitempty" jsonschema:"IANA time zone for the deadline's local times, e.g. America/New_York (default UTC)"`
+	Model    string `json:"model,omitempty" jsonschema:"Projection model behind bench upgrades: heuristic|poisson (default heuristic)"`
+}
+
+// ChecklistDeadline is the lineup deadline for the target GW relative to now.
+type ChecklistDeadline struct {
+	DeadlineUTC      string     `json:"deadline_utc"`
+	DeadlineLocal    *LocalTime `json:"deadline_local,omitempty"`
+	WaiversUTC       string     `json:"waivers_utc,omitempty"`
+	WaiversLocal     *LocalTime `json:"waivers_local,omitempty"`
+	TZ               string     `json:"tz"`
+	SecondsRemaining int64      `json:"seconds_remaining"`
+	Remaining        string     `json:"remaining"`
+	Passed           bool       `json:"passed"`
+}
+
+// StarterFlag is a starter with something to check before the deadline.
+type StarterFlag struct {
+	Element      int      `json:"element"`
+	Name         string   `json:"name"`
+	Team         string   `json:"team"`
+	PositionType int      `json:"position_type"`
+	Status       string   `json:"status"`
+	Blank        bool     `json:"blank"`
+	Projected    float64  `json:"projected_points"`
+	Issues       []string `json:"issues"`
+}
+
+// BenchUpgrade is a bench player projected to outscore a starter they could
+// legally replace.
+type BenchUpgrade struct {
+	BenchElement     int     `json:"bench_element"`
+	BenchName        string  `json:"bench_name"`
+	BenchProjected   float64 `json:"bench_projected"`
+	StarterElement   int     `json:"starter_element"`
+	StarterName      string  `json:"starter_name"`
+	StarterProjected float64 `json:"starter_projected"`
+}
+
+// PendingClaim is a waiver claim for the target GW that hasn't been decided.
+type PendingClaim struct {
+	TransactionID int    `json:"transaction_id"`
+	ElementIn     int    `json:"element_in"`
+	NameIn        string `json:"name_in"`
+	ElementOut    int    `json:"element_out"`
+	NameOut       string `json:"name_out"`
+}
+
+// DeadlineChecklistOutput is the output of the deadline_checklist tool.
+type DeadlineChecklistOutput struct {
+	LeagueID         int               `json:"league_id"`
+	EntryID          int               `json:"entry_id"`
+	EntryName        string            `json:"entry_name"`
+	TargetGW         int               `json:"target_gw"`
+	LineupGW         int               `json:"lineup_gw"`
+	Model            string            `json:"model"`
+	Scoring          ProjectionScoring `json:"scoring"`
+	Deadline         ChecklistDeadline `json:"deadline"`
+	WaiversProcessed bool              `json:"waivers_processed"`
+	StarterFlags     []StarterFlag     `json:"starter_flags"`
+	BenchUpgrades    []BenchUpgrade    `json:"bench_upgrades"`
+	PendingClaims    []PendingClaim    `json:"pending_claims"`
+	Problems         []string          `json:"problems"`
+}
+
+// statusLabel spells out a bootstrap availability code.
+func statusLabel(status string) string {
+	switch status {
+	case "d":
+		return "doubtful"
+	case "i":
+		return "injured"
+	case "s":
+		return "suspended"
+	case "u":
+		return "unavailable"
+	case "n":
+		return "not in squad"
+	default:
+		return status
+	}
+}
+
+// formatRemaining renders a duration as "2d 3h 15m", dropping leading zero units.
+func formatRemaining(d time.Duration) string {
+	if d <= 0 {
+		return "0m"
+	}
+	mins := int64(d / time.Minute)
+	days, hours, mins := mins/(24*60), (mins/60)%24, mins%60
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd %dh %dm", days, hours, mins)
+	case hours > 0:
+		return fmt.Sprintf("%dh %dm", hours, mins)
+	default:
+		return fmt.Sprintf("%dm", mins)
+	}
+}
+
+// checklistDeadline finds gw in bootstrap events and measures it against now,
+// showing its times in loc.
+func checklistDeadline(events []bootstrapEvent, gw int, now time.Time, loc *time.Location) ChecklistDeadline {
+	out := ChecklistDeadline{TZ: loc.String()}
+	for _, ev := range events {
+		if ev.ID != gw {
+			continue
+		}
+		out.DeadlineUTC = ev.DeadlineTime
+		out.DeadlineLocal = localTimeOf(ev.DeadlineTime, loc, now)
+		out.WaiversUTC = ev.WaiversTime
+		out.WaiversLocal = localTimeOf(ev.WaiversTime, loc, now)
+		deadline, ok := summary.ParseAPITime(ev.DeadlineTime)
+		if !ok {
+			break
+		}
+		remaining := deadline.Sub(now)
+		out.SecondsRemaining = int64(remaining / time.Second)
+		out.Remaining = formatRemaining(remaining)
+		out.Passed = remaining <= 0
+		break
+	}
+	return out
+}
+
+// swapKeepsFormation reports whether bench player in can replace starter out
+// while keeping a legal XI: exactly one GK, at least 3 DEF and 1 FWD.
+func swapKeepsFormation(counts map[int]int, out, in int) bool {
+	if out == 1 || in == 1 {
+		return out == in
+	}
+	after := map[int]int{2: counts[2], 3: counts[3], 4: counts[4]}
+	after[out]--
+	after[in]++
+	return after[2] >= 3 && after[4] >= 1
+}
+
+// benchUpgrades pairs each bench player with the lowest-projected starter they
+// outscore and could swap with.
+func benchUpgrades(starters, bench []StarterFlag) []BenchUpgrade {
+	counts := make(map[int]int)
+	for _, s := range starters {
+		counts[s.PositionType]++
+	}
+	out := make([]BenchUpgrade, 0)
+	for _, b := range bench {
+		best := -1
+		for i, s := range starters {
+			if b.Projected <= s.Projected || !swapKeepsFormation(counts, s.PositionType, b.PositionType) {
+				continue
+			}
+			if best < 0 || s.Projected < starters[best].Projected {
+				best = i
+			}
+		}
+		if best < 0 {
+			continue
+		}
+		s := starters[best]
+		out = append(out, BenchUpgrade{
+			BenchElement:     b.Element,
+			BenchName:        b.Name,
+			BenchProjected:   b.Projected,
+			StarterElement:   s.Element,
+			StarterName:      s.Name,
+			StarterProjected: s.Projected,
+		})
+	}
+	return out
+}
+
+// loadLatestPicks reads the entry's most recent raw picks at or before gw; in
+// draft the lineup carries over until the manager changes it.
+func loadLatestPicks(rawRoot string, entryID int, gw int) (int, []ledger.EntryPick, error) {
+	for g := gw; g >= 1; g-- {
+		raw, err := os.ReadFile(filepath.Join(rawRoot, fmt.Sprintf("entry/%d/gw/%d.json", entryID, g)))
+		if err != nil {
+			continue
+		}
+		var snap struct {
+			Picks []ledger.EntryPick `json:"picks"`
+		}
+		if err := json.Unmarshal(raw, &snap); err != nil {
+			return 0, nil, err
+		}
+		return g, snap.Picks, nil
+	}
+	return 0, nil, dataMissing(filepath.Join(rawRoot, fmt.Sprintf("entry/%d/gw/%d.json", entryID, gw)), os.ErrNotExist)
+}
+
+func buildDeadlineChecklist(cfg ServerConfig, args DeadlineChecklistArgs, now time.Time) (DeadlineChecklistOutput, error) {
+	if args.LeagueID == 0 {
+		return DeadlineChecklistOutput{}, invalidArgumentf("league_id is required")
+	}
+	if args.EntryID == 0 {
+		return DeadlineChecklistOutput{}, invalidArgumentf("entry_id is required")
+	}
+	loc, err := loadTimezone(args.TZ)
+	if err != nil {
+		return DeadlineChecklistOutput{}, err
+	}
+	modelName, err := parseProjectionModel(args.Model)
+	if err != nil {
+		return DeadlineChecklistOutput{}, err
+	}
+
+	meta, err := loadGameStatusMeta(cfg)
+	if err != nil {
+		return DeadlineChecklistOutput{}, fmt.Errorf("game.json: %w", err)
+	}
+	targetGW := meta.NextEvent
+	if targetGW <= 0 {
+		targetGW = meta.CurrentEvent + 1
+	}
+	asOfGW, _, err := resolveAsOfAndNextGW(cfg, 0, targetGW)
+	if err != nil {
+		return DeadlineChecklistOutput{}, err
+	}
+
+	st := store.NewJSONStore(cfg.RawRoot)
+	raw, err := st.ReadRaw(fmt.Sprintf("league/%d/details.json", args.LeagueID))
+	if err != nil {
+		return DeadlineChecklistOutput{}, err
+	}
+	var details leagueDetailsRaw
+	if err := json.Unmarshal(raw, &details); err != nil {
+		return DeadlineChecklistOutput{}, err
+	}
+	entryName := ""
+	for _, e := range details.LeagueEntries {
+		if e.EntryID == args.EntryID {
+			entryName = e.EntryName
+			break
+		}
+	}
+	if entryName == "" {
+		return DeadlineChecklistOutput{}, notFoundf("entry %d not found in league %d", args.EntryID, args.LeagueID)
+	}
+
+	events, err := loadBootstrapEvents(cfg.RawRoot)
+	if err != nil {
+		return DeadlineChecklistOutput{}, err
+	}
+	elements, teamShort, fixturesByGW, err := loadBootstrapData(cfg.RawRoot)
+	if err != nil {
+		return DeadlineChecklistOutput{}, err
+	}
+	playerByID := make(map[int]elementInfo, len(elements))
+	for _, e := range elements {
+		playerByID[e.ID] = e
+	}
+
+	lineupGW, picks, err := loadLatestPicks(cfg.RawRoot, args.EntryID, targetGW)
+	if err != nil {
+		return DeadlineChecklistOutput{}, err
+	}
+
+	// A GW with no fixtures listed is unknown rather than a league-wide blank,
+	// so blank warnings only fire when the target GW has a schedule.
+	gwFixtures := fixturesByGW[targetGW]
+	indexByGW := map[int]map[int][]FixtureContext{targetGW: buildFixtureIndex(gwFixtures, teamShort)}
+	rules, err := loadScoringRules(cfg, args.LeagueID)
+	if err != nil {
+		return DeadlineChecklistOutput{}, err
+	}
+	mult := fixtureMultiplierFunc(cfg.RawRoot, elements, teamShort, asOfGW, checklistFormWindow)
+	model, _, err := loadProjectionModel(cfg.RawRoot, modelName, elements, indexByGW, mult, asOfGW, checklistFormWindow, rules.Rules)
+	if err != nil {
+		return DeadlineChecklistOutput{}, err
+	}
+
+	out := DeadlineChecklistOutput{
+		LeagueID:         args.LeagueID,
+		EntryID:          args.EntryID,
+		EntryName:        entryName,
+		TargetGW:         targetGW,
+		LineupGW:         lineupGW,
+		Model:            modelName,
+		Scoring:          rules,
+		Deadline:         checklistDeadline(events, targetGW, now, loc),
+		WaiversProcessed: meta.WaiversProcessed,
+		StarterFlags:     []StarterFlag{},
+		PendingClaims:    []PendingClaim{},
+		Problems:         []string{},
+	}
+
+	var starters, bench []StarterFlag
+	for _, p := range picks {
+		info, ok := playerByID[p.Element]
+		if !ok {
+			continue
+		}
+		proj := projectRestOfSeason(info, teamShort[info.TeamID], model, []int{targetGW}, indexByGW, mult)
+		row := StarterFlag{
+			Element:      info.ID,
+			Name:         info.Name,
+			Team:         teamShort[info.TeamID],
+			PositionType: info.PositionType,
+			Status:       info.Status,
+			Blank:        len(gwFixtures) > 0 && proj.Blanks > 0,
+			Projected:    proj.Projected,
+			Issues:       []string{},
+		}
+		if p.Position > 11 {
+			bench = append(bench, row)
+			continue
+		}
+		if info.Status != "" && info.Status != "a" {
+			row.Issues = append(row.Issues, statusLabel(info.Status))
+		}
+		if row.Blank {
+			row.Issues = append(row.Issues, fmt.Sprintf("no fixture in GW %d", targetGW))
+		}
+		starters = append(starters, row)
+		if len(row.Issues) > 0 {
+			out.StarterFlags = append(out.StarterFlags, row)
+			out.Problems = append(out.Problems, fmt.Sprintf("Starter %s (%s) is flagged: %s.", row.Name, row.Team, strings.Join(row.Issues, ", ")))
+		}
+	}
+
+	out.BenchUpgrades = benchUpgrades(starters, bench)
+	for _, u := range out.BenchUpgrades {
+		out.Problems = append(out.Problems, fmt.Sprintf("Bench %s projects %.1f vs starter %s at %.1f.", u.BenchName, u.BenchProjected, u.StarterName, u.StarterProjected))
+	}
+
+	transactions, err := loadTransactionsRaw(st, args.LeagueID)
+	if err != nil {
+		return DeadlineChecklistOutput{}, err
+	}
+	for _, tx := range transactions {
+		if tx.Entry != args.EntryID || tx.Event != targetGW || tx.Kind != "w" {
+			continue
+		}
+		if tx.Result != "" && tx.Result != "p" {
+			continue
+		}
+		out.PendingClaims = append(out.PendingClaims, PendingClaim{
+			TransactionID: tx.ID,
+			ElementIn:     tx.ElementIn,
+			NameIn:        playerByID[tx.ElementIn].Name,
+			ElementOut:    tx.ElementOut,
+			NameOut:       playerByID[tx.ElementOut].Name,
+		})
+	}
+	sort.Slice(out.PendingClaims, func(i, j int) bool { return out.PendingClaims[i].TransactionID < out.PendingClaims[j].TransactionID })
+	if n := len(out.PendingClaims); n > 0 {
+		out.Problems = append(out.Problems, fmt.Sprintf("%d waiver claim(s) for GW %d are still pending.", n, targetGW))
+	}
+
+	if out.Deadline.Passed {
+		out.Problems = append([]string{fmt.Sprintf("The GW %d deadline has passed.", targetGW)}, out.Problems...)
+	}
+	return out, nil
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSwapKeepsFormation(t *testing.T) {
+	counts := map[int]int{1: 1, 2: 3, 3: 5, 4: 2}
+	cases := []struct {
+		out, in int
+		want    bool
+	}{
+		{1, 1, true},
+		{1, 3, false},
+		{3, 1, false},
+		{3, 4, true},
+		{2, 3, false}, // would leave 2 DEF
+		{3, 2, true},
+	}
+	for _, c := range cases {
+		if got := swapKeepsFormation(counts, c.out, c.in); got != c.want {
+			t.Errorf("swap out %d in %d = %v, want %v", c.out, c.in, got, c.want)
+		}
+	}
+	if swapKeepsFormation(map[int]int{1: 1, 2: 4, 3: 5, 4: 1}, 4, 3) {
+		t.Error("swapping the only FWD should be illegal")
+	}
+}
+
+func TestFormatRemaining(t *testing.T) {
+	cases := map[time.Duration]string{
+		49*time.Hour + 30*time.Minute: "2d 1h 30m",
+		3*time.Hour + 5*time.Minute:   "3h 5m",
+		59 * time.Second:              "0m",
+		-time.Hour:                    "0m",
+	}
+	for d, want := range cases {
+		if got := formatRemaining(d); got != want {
+			t.Errorf("formatRemaining(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
+
+func TestBuildDeadlineChecklist(t *testing.T) {
+	dir, cfg := tmpCfg(t)
+	writeJSON(t, filepath.Join(dir, "bootstrap", "bootstrap-static.json"), map[string]any{
+		"events": map[string]any{"data": []any{
+			map[string]any{"id": 2, "finished": true, "deadline_time": "2025-08-23T10:00:00Z"},
+			map[string]any{"id": 3, "finished": false, "deadline_time": "2025-08-30T10:00:00Z", "waivers_time": "2025-08-29T10:00:00Z"},
+		}},
+		"elements": []any{
+			map[string]any{"id": 1, "web_name": "Salah", "team": 10, "element_type": 3, "status": "d"},
+			map[string]any{"id": 2, "web_name": "Haaland", "team": 11, "element_type": 4, "status": "a"},
+			map[string]any{"id": 3, "web_name": "Alexander-Arnold", "team": 10, "element_type": 2, "status": "a"},
+			map[string]any{"id": 4, "web_name": "Palmer", "team": 13, "element_type": 3, "status": "a"},
+			map[string]any{"id": 5, "web_name": "Saka", "team": 12, "element_type": 3, "status": "a"},
+			map[string]any{"id": 6, "web_name": "Gvardiol", "team": 11, "element_type": 2, "status": "a"},
+			map[string]any{"id": 7, "web_name": "Saliba", "team": 12, "element_type": 2, "status": "a"},
+			map[string]any{"id": 8, "web_name": "Isak", "team": 14, "element_type": 4, "status": "a"},
+		},
+		"teams": []any{
+			map[string]any{"id": 10, "short_name": "LIV"},
+			map[string]any{"id": 11, "short_name": "MCI"},
+			map[string]any{"id": 12, "short_name": "ARS"},
+			map[string]any{"id": 13, "short_name": "CHE"},
+			map[string]any{"id": 14, "short_name": "NEW"},
+		},
+		// GW3: Arsenal blank.
+		"fixtures": map[string]any{
+			"3": []any{
+				map[string]any{"id": 30, "team_h": 10, "team_a": 11},
+				map[string]any{"id": 31, "team_h": 13, "team_a": 14},
+			},
+		},
+	})
+	writeFullGameJSON(t, dir, 2, true, 3, false, "")
+	for gw := 1; gw <= 2; gw++ {
+		writeJSON(t, filepath.Join(dir, "gw", itoa(gw), "live.json"), map[string]any{
+			"elements": map[string]any{
+				"1": map[string]any{"stats": map[string]any{"total_points": 6}},
+				"2": map[string]any{"stats": map[string]any{"total_points": 8}},
+				"3": map[string]any{"stats": map[string]any{"total_points": 4}},
+				"4": map[string]any{"stats": map[string]any{"total_points": 10}},
+				"5": map[string]any{"stats": map[string]any{"total_points": 5}},
+				"6": map[string]any{"stats": map[string]any{"total_points": 3}},
+				"7": map[string]any{"stats": map[string]any{"total_points": 3}},
+				"8": map[string]any{"stats": map[string]any{"total_points": 2}},
+			},
+			"fixtures": []any{
+				map[string]any{"id": gw * 10, "team_h": 10, "team_a": 11},
+				map[string]any{"id": gw*10 + 1, "team_h": 12, "team_a": 13},
+			},
+		})
+	}
+	writeLeagueDetailsFixture(t, dir, 100, []any{
+		map[string]any{"id": 1, "entry_id": 200, "entry_name": "Alpha FC", "short_name": "AFC"},
+	}, []any{})
+	// Only a GW2 lineup exists; it carries over into GW3.
+	writeJSON(t, filepath.Join(dir, "entry/200/gw/2.json"), map[string]any{
+		"picks": []any{
+			map[string]any{"element": 1, "position": 1},
+			map[string]any{"element": 2, "position": 2},
+			map[string]any{"element": 3, "position": 3},
+			map[string]any{"element": 5, "position": 4},
+			map[string]any{"element": 6, "position": 5},
+			map[string]any{"element": 7, "position": 6},
+			map[string]any{"element": 4, "position": 12},
+		},
+	})
+	writeJSON(t, filepath.Join(dir, "league/100/transactions.json"), map[string]any{
+		"transactions": []any{
+			map[string]any{"id": 1, "entry": 200, "event": 2, "element_in": 5, "element_out": 8, "kind": "w", "result": "a"},
+			map[string]any{"id": 2, "entry": 200, "event": 3, "element_in": 8, "element_out": 7, "kind": "w", "result": ""},
+		},
+	})
+
+	now := time.Date(2025, 8, 28, 8, 30, 0, 0, time.UTC)
+	out, err := buildDeadlineChecklist(cfg, DeadlineChecklistArgs{LeagueID: 100, EntryID: 200}, now)
+	if err != nil {
+		t.Fatalf("buildDeadlineChecklist: %v", err)
+	}
+	if out.TargetGW != 3 || out.LineupGW != 2 || out.EntryName != "Alpha FC" {
+		t.Errorf("target=%d lineup=%d name=%q", out.TargetGW, out.LineupGW, out.EntryName)
+	}
+	if d := out.Deadline; d.Passed || d.Remaining != "2d 1h 30m" || d.SecondsRemaining != 178200 {
+		t.Errorf("deadline = %+v, want 2d 1h 30m remaining", d)
+	}
+	if d := out.Deadline; d.TZ != "UTC" || d.DeadlineLocal == nil || d.DeadlineLocal.Relative != "in 2d 1h" {
+		t.Errorf("deadline local = %+v, want UTC in 2d 1h", d.DeadlineLocal)
+	}
+	if out.WaiversProcessed {
+		t.Error("waivers_processed = true, want false from game.json")
+	}
+
+	flagged := map[string]string{}
+	for _, f := range out.StarterFlags {
+		flagged[f.Name] = strings.Join(f.Issues, ",")
+	}
+	if len(flagged) != 3 || flagged["Salah"] != "doubtful" || flagged["Saka"] != "no fixture in GW 3" || flagged["Saliba"] != "no fixture in GW 3" {
+		t.Errorf("starter flags = %v", flagged)
+	}
+
+	// Saka and Saliba both project 0, but only Saka is a legal swap for a MID.
+	if len(out.BenchUpgrades) != 1 {
+		t.Fatalf("bench upgrades = %+v, want Palmer over Saka", out.BenchUpgrades)
+	}
+	if u := out.BenchUpgrades[0]; u.BenchName != "Palmer" || u.StarterName != "Saka" || u.StarterProjected != 0 || u.BenchProjected <= 0 {
+		t.Errorf("bench upgrade = %+v", u)
+	}
+
+	if len(out.PendingClaims) != 1 || out.PendingClaims[0].TransactionID != 2 || out.PendingClaims[0].NameIn != "Isak" {
+		t.Errorf("pending claims = %+v, want transaction 2", out.PendingClaims)
+	}
+	if len(out.Problems) != 5 {
+		t.Errorf("problems = %d, want 5:\n%s", len(out.Problems), strings.Join(out.Problems, "\n"))
+	}
+
+	t.Run("DeadlinePassed", func(t *testing.T) {
+		late := time.Date(2025, 8, 30, 10, 5, 0, 0, time.UTC)
+		out, err := buildDeadlineChecklist(cfg, DeadlineChecklistArgs{LeagueID: 100, EntryID: 200}, late)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !out.Deadline.Passed || !strings.Contains(out.Problems[0], "deadline has passed") {
+			t.Errorf("passed=%v problems[0]=%q", out.Deadline.Passed, out.Problems[0])
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		if _, err := buildDeadlineChecklist(cfg, DeadlineChecklistArgs{LeagueID: 100}, now); classifyError(err).Code != codeInvalidArgument {
+			t.Errorf("missing entry_id: err = %v", err)
+		}
+		if _, err := buildDeadlineChecklist(cfg, DeadlineChecklistArgs{LeagueID: 100, EntryID: 999}, now); classifyError(err).Code != codeNotFound {
+			t.Errorf("unknown entry: err = %v", err)
+		}
+	})
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// Synthetic double-gameweek fixture shared by the waiver_recommendations,
+// epl_fixtures, fixture_difficulty, and game_status tests.
+//
+// Teams: 1 ARS, 2 CHE, 3 LIV, 4 MCI. Arsenal play twice in both GWs:
+//
+//	GW9 (finished):    ARS v CHE, LIV v ARS; MCI blank.
+//	GW10 (in progress): ARS v MCI (finished), CHE v ARS (not started); LIV blank.
+//
+// In GW9 Saka (ARS MID) scored 12 across both matches, Palmer (CHE MID) 2,
+// Salah (LIV MID) 5. Split evenly, CHE and LIV each conceded 6 MID points.
+const (
+	dgwGW     = 10
+	dgwHistGW = 9
+	dgwARS    = 1
+)
+
+func writeDGWFixture(t *testing.T, dir string) {
+	t.Helper()
+	writeJSON(t, filepath.Join(dir, "bootstrap", "bootstrap-static.json"), map[string]any{
+		"elements": []any{
+			map[string]any{"id": 1, "web_name": "Saka", "team": 1, "element_type": 3, "status": "a"},
+			map[string]any{"id": 2, "web_name": "Palmer", "team": 2, "element_type": 3, "status": "a"},
+			map[string]any{"id": 3, "web_name": "Salah", "team": 3, "element_type": 3, "status": "a"},
+			map[string]any{"id": 4, "web_name": "Haaland", "team": 4, "element_type": 4, "status": "a"},
+		},
+		"teams": []any{
+			map[string]any{"id": 1, "name": "Arsenal", "short_name": "ARS"},
+			map[string]any{"id": 2, "name": "Chelsea", "short_name": "CHE"},
+			map[string]any{"id": 3, "name": "Liverpool", "short_name": "LIV"},
+			map[string]any{"id": 4, "name": "Man City", "short_name": "MCI"},
+		},
+		"fixtures": map[string]any{
+			"10": []any{
+				map[string]any{"id": 101, "event": 10, "team_h": 1, "team_a": 4, "started": true, "finished": true},
+				map[string]any{"id": 102, "event": 10, "team_h": 2, "team_a": 1, "started": false, "finished": false},
+			},
+		},
+	})
+	writeFullGameJSON(t, dir, dgwGW, false, dgwGW+1, true, "n")
+	writeJSON(t, filepath.Join(dir, "gw", "9", "live.json"), map[string]any{
+		"elements": map[string]any{
+			"1": map[string]any{"stats": map[string]any{"minutes": 180, "total_points": 12}},
+			"2": map[string]any{"stats": map[string]any{"minutes": 90, "total_points": 2}},
+			"3": map[string]any{"stats": map[string]any{"minutes": 90, "total_points": 5}},
+		},
+		"fixtures": []any{
+			map[string]any{"id": 91, "event": 9, "team_h": 1, "team_a": 2, "team_h_score": 2, "team_a_score": 0, "started": true, "finished": true},
+			map[string]any{"id": 92, "event": 9, "team_h": 3, "team_a": 1, "team_h_score": 1, "team_a_score": 1, "started": true, "finished": true},
+		},
+	})
+	writeJSON(t, filepath.Join(dir, "gw", "10", "live.json"), map[string]any{
+		"elements": map[string]any{
+			"1": map[string]any{"stats": map[string]any{"minutes": 0, "total_points": 0}},
+			"4": map[string]any{"stats": map[string]any{"minutes": 90, "total_points": 2}},
+		},
+		"fixtures": []any{
+			map[string]any{"id": 101, "event": 10, "team_h": 1, "team_a": 4, "team_h_score": 0, "team_a_score": 0, "started": true, "finished": true},
+			map[string]any{"id": 102, "event": 10, "team_h": 2, "team_a": 1, "started": false, "finished": false},
+		},
+	})
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/model"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+)
+
+// DraftBoardArgs are the input arguments for the draft_board tool.
+type DraftBoardArgs struct {
+	LeagueID  int     `json:"league_id" jsonschema:"Draft league id (required)"`
+	Round     *int    `json:"round,omitempty" jsonschema:"Only this draft round"`
+	EntryID   *int    `json:"entry_id,omitempty" jsonschema:"Only this entry's picks"`
+	EntryName *string `json:"entry_name,omitempty" jsonschema:"Only this entry's picks (if entry_id not provided)"`
+	Season    string  `json:"season,omitempty" jsonschema:"Season label like 2024-25 for an archived season (default current)"`
+	PageSize  *int    `json:"page_size,omitempty" jsonschema:"Picks per page, in draft order (default the whole board)"`
+	Cursor    string  `json:"cursor,omitempty" jsonschema:"next_cursor from the previous page"`
+}
+
+// DraftBoardPick is one cell of the draft grid.
+type DraftBoardPick struct {
+	Round        int    `json:"round"`
+	Pick         int    `json:"pick"`
+	OverallIndex int    `json:"overall_index"`
+	EntryID      int    `json:"entry_id"`
+	EntryName    string `json:"entry_name"`
+	Element      int    `json:"element"`
+	PlayerName   string `json:"player_name"`
+	Team         string `json:"team"`
+	PositionType int    `json:"position_type"`
+	SeasonPoints int    `json:"season_points"`
+	WasAuto      bool   `json:"was_auto"`
+}
+
+// DraftBoardRound is one row of the draft grid.
+type DraftBoardRound struct {
+	Round int              `json:"round"`
+	Picks []DraftBoardPick `json:"picks"`
+}
+
+// DraftBoardOutput is the output of the draft_board tool.
+type DraftBoardOutput struct {
+	LeagueID   int               `json:"league_id"`
+	TotalPicks int               `json:"total_picks"`
+	Round      int               `json:"round,omitempty"`
+	EntryID    int               `json:"entry_id,omitempty"`
+	EntryName  string            `json:"entry_name,omitempty"`
+	Rounds     []DraftBoardRound `json:"rounds"`
+	// Page is set when the caller pages; a page holds picks in draft order
+	// and may start or end mid-round.
+	Page *PageInfo `json:"page,omitempty"`
+	// PlayerNamesUnavailable is playerNames.Unavailable.
+	PlayerNamesUnavailable bool `json:"player_names_unavailable,omitempty"`
+}
+
+// loadDraftLedger reads the derived draft ledger, building it from the raw
+// draft choices when it is missing.
+func loadDraftLedger(cfg ServerConfig, leagueID int) (model.DraftLedger, error) {
+	out, _, err := loadDraftLedgerWithHash(cfg, leagueID)
+	return out, err
+}
+
+// loadDraftLedgerWithHash is loadDraftLedger plus the content hash of the
+// ledger file.
+func loadDraftLedgerWithHash(cfg ServerConfig, leagueID int) (model.DraftLedger, string, error) {
+	st := store.NewJSONStore(cfg.RawRoot)
+	raw, err := loadDerivedFile(cfg, fmt.Sprintf("ledger/%d/event_0.json", leagueID), func(root string) error {
+		return ensureLedger(st, root, leagueID, cfg.Clock)
+	})
+	if err != nil {
+		return model.DraftLedger{}, "", err
+	}
+	var out model.DraftLedger
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return model.DraftLedger{}, "", err
+	}
+	return out, hashBytes(raw), nil
+}
+
+func buildDraftBoard(cfg ServerConfig, args DraftBoardArgs) (DraftBoardOutput, error) {
+	if args.LeagueID == 0 {
+		return DraftBoardOutput{}, invalidArgumentf("league_id is required")
+	}
+	cfg, err := cfg.forSeason(args.Season)
+	if err != nil {
+		return DraftBoardOutput{}, err
+	}
+	round := 0
+	if args.Round != nil {
+		round = *args.Round
+	}
+	if round < 0 {
+		return DraftBoardOutput{}, invalidArgumentf("round must not be negative")
+	}
+
+	ledgerOut, hash, err := loadDraftLedgerWithHash(cfg, args.LeagueID)
+	if err != nil {
+		return DraftBoardOutput{}, err
+	}
+
+	entryID := 0
+	if args.EntryID != nil {
+		entryID = *args.EntryID
+	}
+	entryName := ""
+	if entryID == 0 && args.EntryName != nil {
+		if name := strings.TrimSpace(*args.EntryName); name != "" {
+			for _, m := range ledgerOut.Managers {
+				if strings.EqualFold(m.Name, name) {
+					entryID = m.EntryID
+					break
+				}
+			}
+			if entryID == 0 {
+				return DraftBoardOutput{}, notFoundf("no entry found for name: %s", name)
+			}
+		}
+	}
+	if entryID != 0 {
+		for _, m := range ledgerOut.Managers {
+			if m.EntryID == entryID {
+				entryName = m.Name
+				break
+			}
+		}
+		if entryName == "" {
+			return DraftBoardOutput{}, notFoundf("entry not found: %d", entryID)
+		}
+	}
+
+	players, err := loadPlayerNames(cfg.RawRoot)
+	if err != nil {
+		return DraftBoardOutput{}, err
+	}
+
+	picks := append([]model.DraftPick(nil), ledgerOut.Picks...)
+	sort.Slice(picks, func(i, j int) bool { return picks[i].Index < picks[j].Index })
+
+	out := DraftBoardOutput{
+		LeagueID:               args.LeagueID,
+		Round:                  round,
+		EntryID:                entryID,
+		EntryName:              entryName,
+		Rounds:                 []DraftBoardRound{},
+		PlayerNamesUnavailable: players.Unavailable,
+	}
+	cells := make([]DraftBoardPick, 0, len(picks))
+	for _, p := range picks {
+		if round != 0 && p.Round != round {
+			continue
+		}
+		if entryID != 0 && p.EntryID != entryID {
+			continue
+		}
+		meta, _ := players.get(p.Element)
+		cells = append(cells, DraftBoardPick{
+			Round:        p.Round,
+			Pick:         p.Pick,
+			OverallIndex: p.Index,
+			EntryID:      p.EntryID,
+			EntryName:    p.EntryName,
+			Element:      p.Element,
+			PlayerName:   meta.Name,
+			Team:         players.team(meta.TeamID),
+			PositionType: meta.PositionType,
+			SeasonPoints: meta.TotalPoints,
+			WasAuto:      p.WasAuto,
+		})
+	}
+	out.TotalPicks = len(cells)
+	if page := (PageArgs{PageSize: args.PageSize, Cursor: args.Cursor}); page.paged() {
+		var info PageInfo
+		key := fmt.Sprintf("draft_board|%d|%s|%d|%d", args.LeagueID, args.Season, round, entryID)
+		if cells, info, err = paginate(cells, page, key, hash); err != nil {
+			return DraftBoardOutput{}, err
+		}
+		out.Page = &info
+	}
+	rowByRound := make(map[int]int)
+	for _, cell := range cells {
+		i, ok := rowByRound[cell.Round]
+		if !ok {
+			i = len(out.Rounds)
+			rowByRound[cell.Round] = i
+			out.Rounds = append(out.Rounds, DraftBoardRound{Round: cell.Round})
+		}
+		out.Rounds[i].Picks = append(out.Rounds[i].Picks, cell)
+	}
+	return out, nil
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeDraftChoicesFixture(t *testing.T, dir string) {
+	t.Helper()
+	writeJSON(t, filepath.Join(dir, "draft/100/choices.json"), map[string]any{
+		"choices": []any{
+			map[string]any{"entry": 200, "entry_name": "Alpha FC", "element": 2, "round": 1, "pick": 1, "index": 1},
+			map[string]any{"entry": 201, "entry_name": "Beta FC", "element": 1, "round": 1, "pick": 2, "index": 2},
+			map[string]any{"entry": 201, "entry_name": "Beta FC", "element": 3, "round": 2, "pick": 1, "index": 3, "was_auto": true},
+		},
+	})
+}
+
+func TestBuildDraftBoard(t *testing.T) {
+	dir, cfg := tmpCfg(t)
+	cfg.DerivedRoot = filepath.Join(dir, "derived")
+	cfg.ComputeMissing = true
+	writeBootstrap(t, dir)
+	writeDraftChoicesFixture(t, dir)
+
+	t.Run("FullGrid", func(t *testing.T) {
+		out, err := buildDraftBoard(cfg, DraftBoardArgs{LeagueID: 100})
+		if err != nil {
+			t.Fatalf("buildDraftBoard: %v", err)
+		}
+		if out.TotalPicks != 3 || len(out.Rounds) != 2 {
+			t.Fatalf("picks=%d rounds=%d, want 3 and 2", out.TotalPicks, len(out.Rounds))
+		}
+		first := out.Rounds[0].Picks[0]
+		if first.PlayerName != "Haaland" || first.Team != "MCI" || first.SeasonPoints != 180 || first.EntryName != "Alpha FC" {
+			t.Errorf("first pick = %+v", first)
+		}
+		if last := out.Rounds[1].Picks[0]; !last.WasAuto || last.PlayerName != "Alexander-Arnold" {
+			t.Errorf("round 2 pick = %+v", last)
+		}
+		// WriteDerived is off, so the ledger must not be left behind.
+		if _, err := os.Stat(filepath.Join(cfg.DerivedRoot, "ledger/100/event_0.json")); !os.IsNotExist(err) {
+			t.Errorf("ledger written with WriteDerived=false (err=%v)", err)
+		}
+	})
+
+	t.Run("Filters", func(t *testing.T) {
+		round := 1
+		out, err := buildDraftBoard(cfg, DraftBoardArgs{LeagueID: 100, Round: &round})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if out.TotalPicks != 2 || len(out.Rounds) != 1 {
+			t.Errorf("round filter: picks=%d rounds=%d, want 2 and 1", out.TotalPicks, len(out.Rounds))
+		}
+		name := "beta fc"
+		out, err = buildDraftBoard(cfg, DraftBoardArgs{LeagueID: 100, EntryName: &name})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if out.TotalPicks != 2 || out.EntryID != 201 || out.EntryName != "Beta FC" {
+			t.Errorf("entry filter = %+v", out)
+		}
+		missing := 999
+		if _, err := buildDraftBoard(cfg, DraftBoardArgs{LeagueID: 100, EntryID: &missing}); classifyError(err).Code != codeNotFound {
+			t.Errorf("unknown entry: err = %v, want NOT_FOUND", err)
+		}
+	})
+
+	t.Run("NoComputeMissing", func(t *testing.T) {
+		noCompute := cfg
+		noCompute.ComputeMissing = false
+		if _, err := buildDraftBoard(noCompute, DraftBoardArgs{LeagueID: 100}); classifyError(err).Code != codeDataMissing {
+			t.Errorf("err = %v, want DATA_MISSING", err)
+		}
+	})
+}
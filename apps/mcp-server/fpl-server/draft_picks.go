@@ -36,11 +36,13 @@ type DraftPicksOutput struct {
 	TotalPicks int             `json:"total_picks"`
 	FilteredBy string          `json:"filtered_by,omitempty"`
 	Picks      []DraftPickInfo `json:"picks"`
+	// PlayerNamesUnavailable is playerNames.Unavailable.
+	PlayerNamesUnavailable bool `json:"player_names_unavailable,omitempty"`
 }
 
 func buildDraftPicks(cfg ServerConfig, args DraftPicksArgs) (DraftPicksOutput, error) {
 	if args.LeagueID == 0 {
-		return DraftPicksOutput{}, fmt.Errorf("league_id is required")
+		return DraftPicksOutput{}, invalidArgumentf("league_id is required")
 	}
 
 	// Load draft choices.
@@ -84,7 +86,7 @@ func buildDraftPicks(cfg ServerConfig, args DraftPicksArgs) (DraftPicksOutput, e
 				}
 			}
 			if filterEntryID == 0 {
-				return DraftPicksOutput{}, fmt.Errorf("no entry found for name: %s", name)
+				return DraftPicksOutput{}, notFoundf("no entry found for name: %s", name)
 			}
 		}
 	}
@@ -98,14 +100,10 @@ func buildDraftPicks(cfg ServerConfig, args DraftPicksArgs) (DraftPicksOutput, e
 	}
 
 	// Build player metadata map from bootstrap.
-	elements, teamShort, _, err := loadBootstrapData(cfg.RawRoot)
+	players, err := loadPlayerNames(cfg.RawRoot)
 	if err != nil {
 		return DraftPicksOutput{}, err
 	}
-	playerByID := make(map[int]elementInfo, len(elements))
-	for _, e := range elements {
-		playerByID[e.ID] = e
-	}
 
 	// Sort choices by overall draft index.
 	sort.Slice(resp.Choices, func(i, j int) bool {
@@ -117,7 +115,7 @@ func buildDraftPicks(cfg ServerConfig, args DraftPicksArgs) (DraftPicksOutput, e
 		if filterEntryID != 0 && c.Entry != filterEntryID {
 			continue
 		}
-		meta := playerByID[c.Element]
+		meta, _ := players.get(c.Element)
 		picks = append(picks, DraftPickInfo{
 			Round:        c.Round,
 			Pick:         c.Pick,
@@ -126,16 +124,17 @@ func buildDraftPicks(cfg ServerConfig, args DraftPicksArgs) (DraftPicksOutput, e
 			EntryName:    c.EntryName,
 			Element:      c.Element,
 			PlayerName:   meta.Name,
-			Team:         teamShort[meta.TeamID],
+			Team:         players.team(meta.TeamID),
 			PositionType: meta.PositionType,
 			WasAuto:      c.WasAuto,
 		})
 	}
 
 	return DraftPicksOutput{
-		LeagueID:   args.LeagueID,
-		TotalPicks: len(picks),
-		FilteredBy: filterLabel,
-		Picks:      picks,
+		LeagueID:               args.LeagueID,
+		TotalPicks:             len(picks),
+		FilteredBy:             filterLabel,
+		Picks:                  picks,
+		PlayerNamesUnavailable: players.Unavailable,
 	}, nil
 }
@@ -0,0 +1,392 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/jsonutil"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/leagueconfig"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/scoring"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+)
+
+// Draft ranking bases: what a player's value starts from.
+const (
+	draftBasisSeasonPoints = "season_points"
+	draftBasisPrice        = "price"
+)
+
+// Scoring profiles for draft_rankings.
+const (
+	draftScoringLeague   = "league"
+	draftScoringOfficial = "official"
+)
+
+const (
+	// draftStrengthStep is the value multiplier per step of bootstrap team
+	// strength (1-5) away from 3, so the strongest sides are worth 10% more.
+	draftStrengthStep = 0.05
+	// draftTierGap is how many times the position's mean gap between
+	// neighbouring players a drop must be to start a new tier.
+	draftTierGap = 1.5
+)
+
+// draftStatusFactor discounts a player by bootstrap status; "u" (left the
+// league) isn't ranked at all.
+var draftStatusFactor = map[string]float64{"a": 1, "d": 0.75, "i": 0.5, "s": 0.5, "n": 0.5}
+
+// DraftRankingsArgs are the input arguments for the draft_rankings tool.
+type DraftRankingsArgs struct {
+	LeagueID    int                          `json:"league_id,omitempty" jsonschema:"Draft league id; supplies the league size, squad limits and scoring rules"`
+	LeagueSize  *int                         `json:"league_size,omitempty" jsonschema:"Number of teams drafting (default the league's entry count; required without league_id)"`
+	SquadLimits *leagueconfig.PositionLimits `json:"squad_limits,omitempty" jsonschema:"Squad limits per position {gk,def,mid,fwd}, overriding the league's (default 2/5/5/3)"`
+	Scoring     string                       `json:"scoring,omitempty" jsonschema:"Scoring profile: league (default; the league's or server's scoring rules) or official (FPL's own)"`
+	Limit       *int                         `json:"limit,omitempty" jsonschema:"Overall board length (default league_size times squad size)"`
+}
+
+// DraftRankedPlayer is one player in the draft rankings. Value is the
+// player's base value after availability and team strength;
+// ValueOverReplacement is how far it sits above the best player still left
+// once every team has filled the position.
+type DraftRankedPlayer struct {
+	Rank                 int     `json:"rank,omitempty"`
+	Element              int     `json:"element"`
+	Name                 string  `json:"name"`
+	Team                 string  `json:"team"`
+	Position             string  `json:"position"`
+	Status               string  `json:"status"`
+	SeasonPoints         int     `json:"season_points"`
+	Price                float64 `json:"price"`
+	TeamStrength         int     `json:"team_strength,omitempty"`
+	Value                float64 `json:"value"`
+	ValueOverReplacement float64 `json:"value_over_replacement"`
+	PositionRank         int     `json:"position_rank"`
+	Tier                 int     `json:"tier"`
+}
+
+// DraftTier is a run of players at one position with no big drop between
+// them. DropOff is the value lost from its last player to the next tier's
+// first (0 for the last tier).
+type DraftTier struct {
+	Tier    int                 `json:"tier"`
+	DropOff float64             `json:"drop_off"`
+	Players []DraftRankedPlayer `json:"players"`
+}
+
+// DraftPositionRanking is the tier list for one position. Drafted is how
+// many the league's squads hold between them; ReplacementValue is the value
+// of the best player left after that.
+type DraftPositionRanking struct {
+	Position         string      `json:"position"`
+	Drafted          int         `json:"drafted"`
+	ReplacementValue float64     `json:"replacement_value"`
+	Tiers            []DraftTier `json:"tiers"`
+}
+
+// DraftRankingsOutput is the output of the draft_rankings tool.
+type DraftRankingsOutput struct {
+	LeagueID    int                         `json:"league_id,omitempty"`
+	LeagueSize  int                         `json:"league_size"`
+	SquadLimits leagueconfig.PositionLimits `json:"squad_limits"`
+	Scoring     ProjectionScoring           `json:"scoring"`
+	// Basis is what values start from: season_points, or price when no
+	// player has any points yet.
+	Basis       string                 `json:"basis"`
+	Explanation string                 `json:"explanation"`
+	Positions   []DraftPositionRanking `json:"positions"`
+	Board       []DraftRankedPlayer    `json:"board"`
+	Notes       []string               `json:"notes,omitempty"`
+}
+
+// draftPoolPlayer is a bootstrap element with the season stats draft
+// rankings re-score.
+type draftPoolPlayer struct {
+	ID              int              `json:"id"`
+	WebName         string           `json:"web_name"`
+	Team            int              `json:"team"`
+	ElementType     int              `json:"element_type"`
+	Status          string           `json:"status"`
+	NowCost         jsonutil.FlexInt `json:"now_cost"`
+	TotalPoints     jsonutil.FlexInt `json:"total_points"`
+	Minutes         jsonutil.FlexInt `json:"minutes"`
+	GoalsScored     jsonutil.FlexInt `json:"goals_scored"`
+	Assists         jsonutil.FlexInt `json:"assists"`
+	CleanSheets     jsonutil.FlexInt `json:"clean_sheets"`
+	GoalsConceded   jsonutil.FlexInt `json:"goals_conceded"`
+	OwnGoals        jsonutil.FlexInt `json:"own_goals"`
+	PenaltiesSaved  jsonutil.FlexInt `json:"penalties_saved"`
+	PenaltiesMissed jsonutil.FlexInt `json:"penalties_missed"`
+	YellowCards     jsonutil.FlexInt `json:"yellow_cards"`
+	RedCards        jsonutil.FlexInt `json:"red_cards"`
+	Saves           jsonutil.FlexInt `json:"saves"`
+}
+
+type draftPoolTeam struct {
+	ID        int    `json:"id"`
+	ShortName string `json:"short_name"`
+	Strength  int    `json:"strength"`
+}
+
+// loadDraftPool reads the players and teams from bootstrap-static.json.
+// It is the only file draft rankings read about players: no live data.
+func loadDraftPool(rawRoot string) ([]draftPoolPlayer, map[int]draftPoolTeam, error) {
+	path := filepath.Join(rawRoot, "bootstrap", "bootstrap-static.json")
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	var resp struct {
+		Elements []draftPoolPlayer `json:"elements"`
+		Teams    []draftPoolTeam   `json:"teams"`
+	}
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if len(resp.Elements) == 0 {
+		return nil, nil, &codedError{Code: codeDataMissing, Message: path + " lists no players yet", Details: map[string]any{"path": path, "hint": refreshHint}}
+	}
+	teams := make(map[int]draftPoolTeam, len(resp.Teams))
+	for _, t := range resp.Teams {
+		teams[t.ID] = t
+	}
+	return resp.Elements, teams, nil
+}
+
+// seasonPoints is p's season total under rules. Season totals can't say
+// which appearances reached 60 minutes or which matches hit the defensive
+// contribution threshold, so only the per-event rules are re-scored.
+func (p draftPoolPlayer) seasonPoints(rules scoring.ScoringRules) int {
+	return int(p.TotalPoints) + rules.Adjustment(p.ElementType, scoring.StatLine{
+		Goals:           int(p.GoalsScored),
+		Assists:         int(p.Assists),
+		CleanSheets:     int(p.CleanSheets),
+		GoalsConceded:   int(p.GoalsConceded),
+		OwnGoals:        int(p.OwnGoals),
+		PenaltiesSaved:  int(p.PenaltiesSaved),
+		PenaltiesMissed: int(p.PenaltiesMissed),
+		YellowCards:     int(p.YellowCards),
+		RedCards:        int(p.RedCards),
+		Saves:           int(p.Saves),
+		// Minutes only gate the line here; 60 keeps clean sheets in.
+		Minutes: min(int(p.Minutes), 60),
+	})
+}
+
+// draftTeamFactor scales value by bootstrap team strength; 1 when the
+// strength is unknown.
+func draftTeamFactor(strength int) float64 {
+	if strength <= 0 {
+		return 1
+	}
+	return 1 + draftStrengthStep*float64(strength-3)
+}
+
+func roundTenth(v float64) float64 { return math.Round(v*10) / 10 }
+
+// draftTiers splits players, sorted by value, wherever the drop to the next
+// player is more than draftTierGap times the mean drop, numbering each
+// player's tier.
+func draftTiers(players []DraftRankedPlayer) []DraftTier {
+	if len(players) == 0 {
+		return []DraftTier{}
+	}
+	mean := 0.0
+	if len(players) > 1 {
+		mean = (players[0].Value - players[len(players)-1].Value) / float64(len(players)-1)
+	}
+	tiers := []DraftTier{{Tier: 1}}
+	for i := range players {
+		cur := &tiers[len(tiers)-1]
+		if i > 0 {
+			if gap := players[i-1].Value - players[i].Value; gap > 0 && gap > draftTierGap*mean {
+				cur.DropOff = roundTenth(gap)
+				tiers = append(tiers, DraftTier{Tier: cur.Tier + 1})
+				cur = &tiers[len(tiers)-1]
+			}
+		}
+		players[i].Tier = cur.Tier
+		cur.Players = append(cur.Players, players[i])
+	}
+	return tiers
+}
+
+func buildDraftRankings(cfg ServerConfig, args DraftRankingsArgs) (DraftRankingsOutput, error) {
+	profile := args.Scoring
+	if profile == "" {
+		profile = draftScoringLeague
+	}
+	if profile != draftScoringLeague && profile != draftScoringOfficial {
+		return DraftRankingsOutput{}, invalidArgumentf("scoring must be %q or %q, got %q", draftScoringLeague, draftScoringOfficial, args.Scoring)
+	}
+	if args.LeagueSize != nil && *args.LeagueSize <= 0 {
+		return DraftRankingsOutput{}, invalidArgumentf("league_size must be positive, got %d", *args.LeagueSize)
+	}
+	if args.LeagueSize == nil && args.LeagueID == 0 {
+		return DraftRankingsOutput{}, invalidArgumentf("league_size is required without league_id")
+	}
+
+	out := DraftRankingsOutput{LeagueID: args.LeagueID, SquadLimits: leagueconfig.DefaultSquadLimits()}
+	if args.LeagueSize != nil {
+		out.LeagueSize = *args.LeagueSize
+	}
+	if args.LeagueID != 0 {
+		st := store.NewJSONStore(cfg.RawRoot)
+		lc, err := leagueconfig.Load(st, args.LeagueID)
+		if err != nil {
+			return DraftRankingsOutput{}, err
+		}
+		out.SquadLimits = lc.Squad.PositionLimits
+		if out.LeagueSize == 0 {
+			_, entries, err := loadLeagueDetails(st, args.LeagueID)
+			if err != nil {
+				return DraftRankingsOutput{}, err
+			}
+			out.LeagueSize = len(entries)
+			if out.LeagueSize == 0 && lc.Admin.MaxEntries > 0 {
+				out.LeagueSize = lc.Admin.MaxEntries
+				out.Notes = append(out.Notes, fmt.Sprintf("No one has joined the league yet; sized for its maximum of %d entries.", out.LeagueSize))
+			}
+			if out.LeagueSize == 0 {
+				return DraftRankingsOutput{}, invalidArgumentf("league %d has no entries yet; pass league_size", args.LeagueID)
+			}
+		}
+	}
+	if args.SquadLimits != nil {
+		out.SquadLimits = *args.SquadLimits
+	}
+	if out.SquadLimits.Total() <= 0 {
+		return DraftRankingsOutput{}, invalidArgumentf("squad_limits must allow at least one player")
+	}
+
+	out.Scoring = ProjectionScoring{Source: scoringSourceDefault, Rules: scoring.Default()}
+	if profile == draftScoringLeague {
+		rules, err := loadScoringRules(cfg, args.LeagueID)
+		if err != nil {
+			return DraftRankingsOutput{}, err
+		}
+		out.Scoring = rules
+	}
+
+	players, teams, err := loadDraftPool(cfg.RawRoot)
+	if err != nil {
+		return DraftRankingsOutput{}, err
+	}
+	out.Basis = draftBasisPrice
+	for _, p := range players {
+		if p.TotalPoints != 0 || p.Minutes > 0 {
+			out.Basis = draftBasisSeasonPoints
+			break
+		}
+	}
+	strengthKnown := false
+	for _, t := range teams {
+		if t.Strength > 0 {
+			strengthKnown = true
+			break
+		}
+	}
+
+	byPos := make(map[int][]DraftRankedPlayer, 4)
+	excluded := 0
+	for _, p := range players {
+		factor, ok := draftStatusFactor[p.Status]
+		if p.Status == "" {
+			factor, ok = 1, true
+		}
+		if !ok || p.ElementType < 1 || p.ElementType > 4 {
+			excluded++
+			continue
+		}
+		team := teams[p.Team]
+		r := DraftRankedPlayer{
+			Element:      p.ID,
+			Name:         p.WebName,
+			Team:         team.ShortName,
+			Position:     positionLabel(p.ElementType),
+			Status:       p.Status,
+			SeasonPoints: p.seasonPoints(out.Scoring.Rules),
+			Price:        float64(p.NowCost) / 10,
+			TeamStrength: team.Strength,
+		}
+		base := float64(r.SeasonPoints)
+		if out.Basis == draftBasisPrice {
+			base = r.Price
+		}
+		r.Value = roundTenth(base * factor * draftTeamFactor(team.Strength))
+		byPos[p.ElementType] = append(byPos[p.ElementType], r)
+	}
+
+	var pooled []DraftRankedPlayer
+	for pos := 1; pos <= 4; pos++ {
+		list := byPos[pos]
+		sort.Slice(list, func(i, j int) bool {
+			if list[i].Value != list[j].Value {
+				return list[i].Value > list[j].Value
+			}
+			return list[i].Element < list[j].Element
+		})
+		drafted := out.LeagueSize * out.SquadLimits.At(pos)
+		ranking := DraftPositionRanking{Position: positionLabel(pos), Drafted: drafted}
+		if drafted < len(list) {
+			ranking.ReplacementValue = list[drafted].Value
+		}
+		// Everyone who'll be drafted plus a round's worth of cover.
+		list = list[:min(len(list), drafted+out.LeagueSize)]
+		for i := range list {
+			list[i].PositionRank = i + 1
+			list[i].ValueOverReplacement = roundTenth(list[i].Value - ranking.ReplacementValue)
+		}
+		ranking.Tiers = draftTiers(list)
+		for _, tier := range ranking.Tiers {
+			pooled = append(pooled, tier.Players...)
+		}
+		out.Positions = append(out.Positions, ranking)
+	}
+
+	sort.SliceStable(pooled, func(i, j int) bool {
+		if pooled[i].ValueOverReplacement != pooled[j].ValueOverReplacement {
+			return pooled[i].ValueOverReplacement > pooled[j].ValueOverReplacement
+		}
+		if pooled[i].Value != pooled[j].Value {
+			return pooled[i].Value > pooled[j].Value
+		}
+		return pooled[i].Element < pooled[j].Element
+	})
+	limit := out.LeagueSize * out.SquadLimits.Total()
+	if args.Limit != nil && *args.Limit > 0 {
+		limit = *args.Limit
+	}
+	out.Board = pooled[:min(limit, len(pooled))]
+	for i := range out.Board {
+		out.Board[i].Rank = i + 1
+	}
+
+	switch out.Basis {
+	case draftBasisSeasonPoints:
+		out.Explanation = "Values start from each player's season points in bootstrap (last season's until FPL resets them for the new one)"
+	default:
+		out.Explanation = "No player has scored yet, so values start from list price as the game's own estimate of output"
+	}
+	if strengthKnown {
+		out.Explanation += ", scaled for availability and team strength"
+	} else {
+		out.Explanation += ", scaled for availability"
+		out.Notes = append(out.Notes, "Bootstrap carries no team strength, so teams are treated as equal.")
+	}
+	out.Explanation += fmt.Sprintf(". The board orders players by value over replacement: the margin over the best player still available once %d teams have filled %d GK / %d DEF / %d MID / %d FWD.",
+		out.LeagueSize, out.SquadLimits.GK, out.SquadLimits.DEF, out.SquadLimits.MID, out.SquadLimits.FWD)
+	if out.Basis == draftBasisPrice {
+		out.Notes = append(out.Notes, "Rankings use bootstrap alone and no points history; they firm up once gameweeks have been played.")
+	}
+	if out.Basis == draftBasisSeasonPoints && !out.Scoring.Rules.IsDefault() {
+		out.Notes = append(out.Notes, "Season points are re-scored from season totals under the league's rules; appearance and defensive contribution changes can't be, so those keep FPL's values.")
+	}
+	if excluded > 0 {
+		out.Notes = append(out.Notes, fmt.Sprintf("%d unranked: left the league or no position.", excluded))
+	}
+	return out, nil
+}
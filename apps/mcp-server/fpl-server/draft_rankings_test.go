@@ -0,0 +1,187 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/leagueconfig"
+)
+
+// writeDraftPoolBootstrap writes a preseason bootstrap: strong team 1 (ARS,
+// strength 5) and weak team 2 (BUR, strength 1), with season points from
+// players. No gw directory exists.
+func writeDraftPoolBootstrap(t *testing.T, dir string, players []map[string]any, strength bool) {
+	t.Helper()
+	elements := make([]any, 0, len(players))
+	for _, p := range players {
+		elements = append(elements, p)
+	}
+	teams := []any{
+		map[string]any{"id": 1, "short_name": "ARS"},
+		map[string]any{"id": 2, "short_name": "BUR"},
+	}
+	if strength {
+		teams[0].(map[string]any)["strength"] = 5
+		teams[1].(map[string]any)["strength"] = 1
+	}
+	writeJSON(t, filepath.Join(dir, "bootstrap", "bootstrap-static.json"), map[string]any{"elements": elements, "teams": teams, "fixtures": map[string]any{}})
+}
+
+func draftPlayer(id, team, pos, points int, status string) map[string]any {
+	return map[string]any{"id": id, "web_name": "P" + itoa(id), "team": team, "element_type": pos, "status": status, "total_points": points, "minutes": 900, "now_cost": 50}
+}
+
+func draftPoolFixture() []map[string]any {
+	return []map[string]any{
+		draftPlayer(1, 1, 1, 100, "a"), draftPlayer(2, 2, 1, 90, "a"), draftPlayer(3, 2, 1, 40, "a"),
+		draftPlayer(4, 1, 2, 120, "a"), draftPlayer(5, 2, 2, 60, "a"), draftPlayer(6, 1, 2, 50, "u"),
+		draftPlayer(7, 1, 3, 200, "a"), draftPlayer(8, 2, 3, 180, "a"), draftPlayer(9, 1, 3, 150, "a"),
+		draftPlayer(10, 2, 3, 100, "a"), draftPlayer(11, 1, 3, 90, "i"), draftPlayer(12, 2, 3, 20, "a"),
+		draftPlayer(13, 1, 4, 150, "a"), draftPlayer(14, 2, 4, 100, "a"), draftPlayer(15, 1, 4, 30, "a"),
+	}
+}
+
+var smallSquad = leagueconfig.PositionLimits{GK: 1, DEF: 1, MID: 2, FWD: 1}
+
+func TestBuildDraftRankings_SeasonPoints(t *testing.T) {
+	dir, cfg := tmpCfg(t)
+	writeDraftPoolBootstrap(t, dir, draftPoolFixture(), true)
+	size := 2
+	squad := smallSquad
+	out, err := buildDraftRankings(cfg, DraftRankingsArgs{LeagueSize: &size, SquadLimits: &squad})
+	if err != nil {
+		t.Fatalf("buildDraftRankings: %v", err)
+	}
+	if out.Basis != draftBasisSeasonPoints || out.Scoring.Source != scoringSourceDefault || !strings.Contains(out.Explanation, "team strength") {
+		t.Errorf("basis %q, scoring %q, explanation %q", out.Basis, out.Scoring.Source, out.Explanation)
+	}
+
+	// Midfield: 4 get drafted, so the 5th (the injured 11 at 49.5) is
+	// replacement level, and the 72-point drop after the top three splits
+	// the tiers.
+	mid := out.Positions[2]
+	if mid.Position != "MID" || mid.Drafted != 4 || mid.ReplacementValue != 49.5 || len(mid.Tiers) != 2 {
+		t.Fatalf("MID = %+v", mid)
+	}
+	top := mid.Tiers[0]
+	if len(top.Players) != 3 || top.DropOff != 72 || top.Players[0].Element != 7 || top.Players[0].Value != 220 || top.Players[1].Element != 9 {
+		t.Errorf("MID tier 1 = %+v", top)
+	}
+	if last := mid.Tiers[1].Players[2]; last.Element != 12 || last.ValueOverReplacement != -31.5 || last.Tier != 2 || last.PositionRank != 6 {
+		t.Errorf("MID last = %+v", last)
+	}
+
+	// The defender who left the league isn't ranked; with only two left
+	// nobody is spare, so replacement is 0.
+	def := out.Positions[1]
+	if len(def.Tiers) != 1 || len(def.Tiers[0].Players) != 2 || def.ReplacementValue != 0 {
+		t.Errorf("DEF = %+v", def)
+	}
+	for _, p := range def.Tiers[0].Players {
+		if p.Element == 6 {
+			t.Error("ranked a player with status u")
+		}
+	}
+
+	// The board runs league_size x squad size deep, by value over
+	// replacement; the forward and defender tied at 132 go by value.
+	if len(out.Board) != 10 {
+		t.Fatalf("board has %d players, want 10", len(out.Board))
+	}
+	want := []int{7, 13, 4, 9, 8, 1, 14, 5, 2, 10}
+	for i, id := range want {
+		if out.Board[i].Element != id || out.Board[i].Rank != i+1 {
+			t.Errorf("board[%d] = %d (rank %d), want %d", i, out.Board[i].Element, out.Board[i].Rank, id)
+		}
+	}
+	if b := out.Board[0]; b.Team != "ARS" || b.TeamStrength != 5 || b.Position != "MID" || b.SeasonPoints != 200 {
+		t.Errorf("board[0] = %+v", b)
+	}
+	if len(out.Notes) != 1 || !strings.Contains(out.Notes[0], "1 unranked") {
+		t.Errorf("notes = %v", out.Notes)
+	}
+}
+
+func TestBuildDraftRankings_PriceBasis(t *testing.T) {
+	dir, cfg := tmpCfg(t)
+	players := draftPoolFixture()
+	for i, p := range players {
+		p["total_points"], p["minutes"], p["now_cost"] = 0, 0, 40+i
+	}
+	writeDraftPoolBootstrap(t, dir, players, false)
+	size := 2
+	squad := smallSquad
+	out, err := buildDraftRankings(cfg, DraftRankingsArgs{LeagueSize: &size, SquadLimits: &squad})
+	if err != nil {
+		t.Fatalf("buildDraftRankings: %v", err)
+	}
+	if out.Basis != draftBasisPrice || !strings.Contains(out.Explanation, "list price") || strings.Contains(out.Explanation, "team strength") {
+		t.Errorf("basis %q, explanation %q", out.Basis, out.Explanation)
+	}
+	// The dearest goalkeeper leads the GKs on price alone.
+	if gk := out.Positions[0].Tiers[0].Players[0]; gk.Element != 3 || gk.Value != 4.2 {
+		t.Errorf("top GK = %+v", gk)
+	}
+	if len(out.Notes) != 3 {
+		t.Errorf("notes = %v, want team strength, bootstrap-only and exclusion notes", out.Notes)
+	}
+}
+
+func TestBuildDraftRankings_LeagueSettings(t *testing.T) {
+	dir, cfg := tmpCfg(t)
+	players := draftPoolFixture()
+	players[7]["goals_scored"] = 10 // P8, a MID
+	writeDraftPoolBootstrap(t, dir, players, true)
+	writeJSON(t, filepath.Join(dir, "league/100/details.json"), map[string]any{
+		"league": map[string]any{
+			"id":            100,
+			"squad_limits":  map[string]any{"gk": 1, "def": 1, "mid": 2, "fwd": 1},
+			"scoring_rules": map[string]any{"goal": map[string]any{"mid": 10}},
+		},
+		"league_entries": []any{
+			map[string]any{"id": 1, "entry_id": 200, "entry_name": "Alpha FC"},
+			map[string]any{"id": 2, "entry_id": 201, "entry_name": "Beta FC"},
+			map[string]any{"id": 3, "entry_id": 202, "entry_name": "Gamma FC"},
+		},
+		"matches": []any{},
+	})
+
+	out, err := buildDraftRankings(cfg, DraftRankingsArgs{LeagueID: 100})
+	if err != nil {
+		t.Fatalf("buildDraftRankings: %v", err)
+	}
+	if out.LeagueSize != 3 || out.SquadLimits != smallSquad || out.Scoring.Source != scoringSourceLeague {
+		t.Fatalf("size %d, squad %+v, scoring %q", out.LeagueSize, out.SquadLimits, out.Scoring.Source)
+	}
+	// Ten goals at 5 extra points each lift P8 to 230 x 0.9.
+	if p := out.Positions[2].Tiers[0].Players[1]; p.Element != 8 || p.SeasonPoints != 230 || p.Value != 207 {
+		t.Errorf("second MID = %+v", p)
+	}
+
+	out, err = buildDraftRankings(cfg, DraftRankingsArgs{LeagueID: 100, Scoring: draftScoringOfficial})
+	if err != nil {
+		t.Fatalf("official scoring: %v", err)
+	}
+	if out.Scoring.Source != scoringSourceDefault || out.Positions[2].Tiers[0].Players[1].Element != 9 {
+		t.Errorf("official scoring = %q, MIDs %+v", out.Scoring.Source, out.Positions[2].Tiers)
+	}
+}
+
+func TestBuildDraftRankings_Errors(t *testing.T) {
+	dir, cfg := tmpCfg(t)
+	size := 2
+	if _, err := buildDraftRankings(cfg, DraftRankingsArgs{}); classifyError(err).Code != codeInvalidArgument {
+		t.Errorf("no league: %v", err)
+	}
+	if _, err := buildDraftRankings(cfg, DraftRankingsArgs{LeagueSize: &size, Scoring: "ppr"}); classifyError(err).Code != codeInvalidArgument {
+		t.Errorf("bad scoring: %v", err)
+	}
+	if _, err := buildDraftRankings(cfg, DraftRankingsArgs{LeagueSize: &size}); classifyError(err).Code != codeDataMissing {
+		t.Errorf("no bootstrap: %v", err)
+	}
+	writeDraftPoolBootstrap(t, dir, nil, true)
+	if _, err := buildDraftRankings(cfg, DraftRankingsArgs{LeagueSize: &size}); classifyError(err).Code != codeDataMissing {
+		t.Errorf("empty bootstrap: %v", err)
+	}
+}
@@ -0,0 +1,334 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/ledger"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/livestats"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/model"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+)
+
+// What-if lineup statuses of the original pick in a GW.
+const (
+	whatIfStarted     = "started"
+	whatIfBenched     = "benched"
+	whatIfNotOnRoster = "not_on_roster"
+	whatIfNoSnapshot  = "no_snapshot"
+	whatIfNoLive      = "no_live_data"
+)
+
+// DraftWhatIfArgs are the input arguments for the draft_what_if tool.
+type DraftWhatIfArgs struct {
+	LeagueID  int  `json:"league_id" jsonschema:"Draft league id (required)"`
+	EntryID   int  `json:"entry_id" jsonschema:"Entry id (required)"`
+	Original  *int `json:"original_element,omitempty" jsonschema:"Element id the entry actually drafted (or give round)"`
+	Round     *int `json:"round,omitempty" jsonschema:"Draft round of the pick to replace (alternative to original_element)"`
+	Alternate int  `json:"alternate_element" jsonschema:"Element id to draft in its place (required)"`
+	ThroughGW int  `json:"through_gw,omitempty" jsonschema:"Replay through this gameweek (default: latest finished)"`
+}
+
+// WhatIfPlayer is the original or alternate pick.
+type WhatIfPlayer struct {
+	Element      int    `json:"element"`
+	PlayerName   string `json:"player_name"`
+	Team         string `json:"team"`
+	PositionType int    `json:"position_type"`
+}
+
+// WhatIfGW is one finished match replayed with the alternate. Points are
+// what each player added to the entry's score: the original's counted
+// points (a bench player's when they were auto-subbed on for the original)
+// and the alternate's in the same slot.
+type WhatIfGW struct {
+	GW              int    `json:"gw"`
+	OriginalStatus  string `json:"original_status"`
+	OriginalPoints  int    `json:"original_points"`
+	AlternatePoints int    `json:"alternate_points"`
+	Delta           int    `json:"delta"`
+	CumulativeDelta int    `json:"cumulative_delta"`
+	OpponentID      int    `json:"opponent_entry_id"`
+	OpponentName    string `json:"opponent_name"`
+	ActualScore     int    `json:"actual_score"`
+	AdjustedScore   int    `json:"adjusted_score"`
+	OpponentScore   int    `json:"opponent_score"`
+	ActualResult    string `json:"actual_result"`
+	AdjustedResult  string `json:"adjusted_result"`
+	Flipped         bool   `json:"flipped,omitempty"`
+}
+
+// DraftWhatIfOutput is the output of the draft_what_if tool.
+type DraftWhatIfOutput struct {
+	LeagueID       int          `json:"league_id"`
+	EntryID        int          `json:"entry_id"`
+	EntryName      string       `json:"entry_name"`
+	ThroughGW      int          `json:"through_gw"`
+	Round          int          `json:"round"`
+	Pick           int          `json:"pick"`
+	OverallIndex   int          `json:"overall_index"`
+	Original       WhatIfPlayer `json:"original"`
+	Alternate      WhatIfPlayer `json:"alternate"`
+	GWs            []WhatIfGW   `json:"gws"`
+	TotalDelta     int          `json:"total_delta"`
+	ActualRecord   SeasonRecord `json:"actual_record"`
+	AdjustedRecord SeasonRecord `json:"adjusted_record"`
+	FlippedGWs     []int        `json:"flipped_gws"`
+	GWNote         *GWNote      `json:"gw_note,omitempty"`
+	Notes          []string     `json:"notes"`
+}
+
+// draftSlot finds the entry's pick to replace: the one that took original,
+// or its pick in round when original is 0.
+func draftSlot(picks []model.DraftPick, entryID int, original int, round int) (model.DraftPick, error) {
+	for _, p := range picks {
+		if p.EntryID != entryID {
+			continue
+		}
+		if (original != 0 && p.Element == original) || (original == 0 && p.Round == round) {
+			if original != 0 && round != 0 && p.Round != round {
+				return model.DraftPick{}, invalidArgumentf("entry %d drafted element %d in round %d, not round %d", entryID, original, p.Round, round)
+			}
+			return p, nil
+		}
+	}
+	if original != 0 {
+		return model.DraftPick{}, notFoundf("entry %d did not draft element %d", entryID, original)
+	}
+	return model.DraftPick{}, notFoundf("entry %d has no pick in round %d", entryID, round)
+}
+
+// takenBefore returns the pick that took element ahead of slot, if any. An
+// element drafted after slot, or never, was still on the board.
+func takenBefore(picks []model.DraftPick, slot model.DraftPick, element int) (model.DraftPick, bool) {
+	for _, p := range picks {
+		if p.Element == element && p.Index < slot.Index {
+			return p, true
+		}
+	}
+	return model.DraftPick{}, false
+}
+
+// whatIfMatch is one of the entry's finished matches.
+type whatIfMatch struct {
+	gw            int
+	opponentID    int
+	opponentName  string
+	score         int
+	opponentScore int
+}
+
+// replayWhatIf swaps alternate into every lineup slot original held as a
+// starter and rescores each match. When the original was auto-subbed off
+// the bench player who came on is what the entry actually got; an
+// alternate who played takes that place instead, one who didn't leaves the
+// sub as it was. Benched or absent GWs, and GWs without a snapshot or live
+// data, are unchanged.
+func replayWhatIf(matches []whatIfMatch, snapshot func(gw int) (ledger.EntrySnapshot, bool), live map[int]map[int]livestats.ElementStats, original int, alternate int) []WhatIfGW {
+	out := make([]WhatIfGW, 0, len(matches))
+	cumulative := 0
+	for _, m := range matches {
+		row := WhatIfGW{
+			GW:             m.gw,
+			OpponentID:     m.opponentID,
+			OpponentName:   m.opponentName,
+			ActualScore:    m.score,
+			OpponentScore:  m.opponentScore,
+			ActualResult:   resultFromScore(m.score, m.opponentScore),
+			OriginalStatus: whatIfNotOnRoster,
+		}
+		snap, ok := snapshot(m.gw)
+		stats, haveLive := live[m.gw]
+		switch {
+		case !ok:
+			row.OriginalStatus = whatIfNoSnapshot
+		case !haveLive:
+			row.OriginalStatus = whatIfNoLive
+		default:
+			for _, p := range snap.Picks {
+				if p.Element != original {
+					continue
+				}
+				row.OriginalStatus = whatIfBenched
+				if p.Position <= 11 {
+					row.OriginalStatus = whatIfStarted
+				}
+			}
+		}
+		if row.OriginalStatus == whatIfStarted {
+			actual := stats[original].TotalPoints
+			subbedOn := 0
+			for _, s := range snap.Subs {
+				if s.ElementOut == original {
+					subbedOn = s.ElementIn
+				}
+			}
+			if subbedOn != 0 {
+				actual = stats[subbedOn].TotalPoints
+			}
+			row.OriginalPoints = actual
+			row.AlternatePoints = stats[alternate].TotalPoints
+			if subbedOn != 0 && stats[alternate].Minutes == 0 {
+				row.AlternatePoints = actual
+			}
+			row.Delta = row.AlternatePoints - row.OriginalPoints
+		}
+		cumulative += row.Delta
+		row.CumulativeDelta = cumulative
+		row.AdjustedScore = m.score + row.Delta
+		row.AdjustedResult = resultFromScore(row.AdjustedScore, m.opponentScore)
+		row.Flipped = row.AdjustedResult != row.ActualResult
+		out = append(out, row)
+	}
+	return out
+}
+
+func addResult(r *SeasonRecord, result string) {
+	switch result {
+	case "W":
+		r.Wins++
+	case "D":
+		r.Draws++
+	default:
+		r.Losses++
+	}
+}
+
+func buildDraftWhatIf(cfg ServerConfig, args DraftWhatIfArgs) (DraftWhatIfOutput, error) {
+	if args.LeagueID == 0 {
+		return DraftWhatIfOutput{}, invalidArgumentf("league_id is required")
+	}
+	if args.EntryID == 0 {
+		return DraftWhatIfOutput{}, invalidArgumentf("entry_id is required")
+	}
+	if args.Alternate == 0 {
+		return DraftWhatIfOutput{}, invalidArgumentf("alternate_element is required")
+	}
+	original, round := 0, 0
+	if args.Original != nil {
+		original = *args.Original
+	}
+	if args.Round != nil {
+		round = *args.Round
+	}
+	if original == 0 && round <= 0 {
+		return DraftWhatIfOutput{}, invalidArgumentf("original_element or round is required")
+	}
+	if original == args.Alternate {
+		return DraftWhatIfOutput{}, invalidArgumentf("alternate_element is the original pick")
+	}
+	throughGW, note, err := resolveEffectiveGW(cfg, args.ThroughGW, gwModeLatestFinished)
+	if err != nil {
+		return DraftWhatIfOutput{}, err
+	}
+
+	draft, err := loadDraftLedger(cfg, args.LeagueID)
+	if err != nil {
+		return DraftWhatIfOutput{}, err
+	}
+	slot, err := draftSlot(draft.Picks, args.EntryID, original, round)
+	if err != nil {
+		return DraftWhatIfOutput{}, err
+	}
+	players, err := loadPlayerNames(cfg.RawRoot)
+	if err != nil {
+		return DraftWhatIfOutput{}, err
+	}
+	player := func(id int) WhatIfPlayer {
+		meta, _ := players.get(id)
+		return WhatIfPlayer{Element: id, PlayerName: meta.Name, Team: players.team(meta.TeamID), PositionType: meta.PositionType}
+	}
+	alt := player(args.Alternate)
+	if alt.PlayerName == "" {
+		return DraftWhatIfOutput{}, notFoundf("element %d not found", args.Alternate)
+	}
+	ld, _, err := loadLeagueDetails(store.NewJSONStore(cfg.RawRoot), args.LeagueID)
+	if err != nil {
+		return DraftWhatIfOutput{}, err
+	}
+	byLeagueEntry := make(map[int]int, len(ld.LeagueEntries))
+	nameByEntry := make(map[int]string, len(ld.LeagueEntries))
+	for _, e := range ld.LeagueEntries {
+		byLeagueEntry[e.ID] = e.EntryID
+		nameByEntry[e.EntryID] = e.EntryName
+	}
+	if taken, ok := takenBefore(draft.Picks, slot, args.Alternate); ok {
+		by := nameByEntry[taken.EntryID]
+		if by == "" {
+			by = fmt.Sprintf("entry %d", taken.EntryID)
+		}
+		return DraftWhatIfOutput{}, invalidArgumentf("%s wasn't available at round %d pick %d (overall %d): %s took them at round %d pick %d (overall %d)",
+			alt.PlayerName, slot.Round, slot.Pick, slot.Index, by, taken.Round, taken.Pick, taken.Index)
+	}
+
+	matches := make([]whatIfMatch, 0)
+	for _, m := range ld.Matches {
+		if !m.Finished || m.Event > throughGW {
+			continue
+		}
+		a, b := byLeagueEntry[m.LeagueEntry1], byLeagueEntry[m.LeagueEntry2]
+		switch args.EntryID {
+		case a:
+			matches = append(matches, whatIfMatch{gw: m.Event, opponentID: b, opponentName: nameByEntry[b], score: m.LeagueEntry1Points, opponentScore: m.LeagueEntry2Points})
+		case b:
+			matches = append(matches, whatIfMatch{gw: m.Event, opponentID: a, opponentName: nameByEntry[a], score: m.LeagueEntry2Points, opponentScore: m.LeagueEntry1Points})
+		}
+	}
+	if len(matches) == 0 {
+		return DraftWhatIfOutput{}, notFoundf("entry %d has no finished matches through GW %d", args.EntryID, throughGW)
+	}
+
+	live := make(map[int]map[int]livestats.ElementStats, len(matches))
+	snapshots := make(map[int]ledger.EntrySnapshot, len(matches))
+	for _, m := range matches {
+		if stats, err := loadLiveStats(cfg.RawRoot, m.gw); err == nil {
+			live[m.gw] = stats
+		}
+		snap, err := loadEntrySnapshot(cfg, args.LeagueID, args.EntryID, m.gw)
+		if err != nil {
+			if classifyError(err).Code == codeDataMissing {
+				continue
+			}
+			return DraftWhatIfOutput{}, err
+		}
+		if !snap.Missing {
+			snapshots[m.gw] = snap
+		}
+	}
+
+	out := DraftWhatIfOutput{
+		LeagueID:     args.LeagueID,
+		EntryID:      args.EntryID,
+		EntryName:    nameByEntry[args.EntryID],
+		ThroughGW:    throughGW,
+		Round:        slot.Round,
+		Pick:         slot.Pick,
+		OverallIndex: slot.Index,
+		Original:     player(slot.Element),
+		Alternate:    alt,
+		FlippedGWs:   make([]int, 0),
+		GWNote:       note,
+		Notes: []string{
+			"The alternate starts in every GW the original started for this entry, from their actual lineup snapshots, and scores their real points. GWs the original was benched, traded or dropped are unchanged, as is everyone else's season.",
+			"When the original was auto-subbed off, the bench player who came on is what the entry actually got; an alternate who played replaces that, one who didn't leaves the sub as it was.",
+		},
+	}
+	out.GWs = replayWhatIf(matches, func(gw int) (ledger.EntrySnapshot, bool) {
+		s, ok := snapshots[gw]
+		return s, ok
+	}, live, slot.Element, args.Alternate)
+	for _, g := range out.GWs {
+		out.TotalDelta += g.Delta
+		addResult(&out.ActualRecord, g.ActualResult)
+		addResult(&out.AdjustedRecord, g.AdjustedResult)
+		if g.Flipped {
+			out.FlippedGWs = append(out.FlippedGWs, g.GW)
+		}
+		if g.OriginalStatus == whatIfNoSnapshot || g.OriginalStatus == whatIfNoLive {
+			out.Notes = append(out.Notes, fmt.Sprintf("GW%d has no lineup snapshot or live data, so it is left as played.", g.GW))
+		}
+	}
+	if out.Original.PositionType != 0 && out.Original.PositionType != alt.PositionType {
+		out.Notes = append(out.Notes, fmt.Sprintf("%s and %s play different positions; the replay ignores formation limits.", out.Original.PlayerName, alt.PlayerName))
+	}
+	return out, nil
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/ledger"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/livestats"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/model"
+)
+
+func TestDraftSlotAndAvailability(t *testing.T) {
+	// Two managers, snake draft: 200 picks 1 and 4, 201 picks 2 and 3.
+	picks := []model.DraftPick{
+		{EntryID: 200, EntryName: "Alpha FC", Element: 10, Round: 1, Pick: 1, Index: 1},
+		{EntryID: 201, EntryName: "Beta FC", Element: 20, Round: 1, Pick: 2, Index: 2},
+		{EntryID: 201, EntryName: "Beta FC", Element: 30, Round: 2, Pick: 1, Index: 3},
+		{EntryID: 200, EntryName: "Alpha FC", Element: 40, Round: 2, Pick: 2, Index: 4},
+	}
+	slot, err := draftSlot(picks, 200, 40, 0)
+	if err != nil || slot.Index != 4 {
+		t.Fatalf("slot for element 40 = %+v, %v", slot, err)
+	}
+	if byRound, err := draftSlot(picks, 200, 0, 2); err != nil || byRound != slot {
+		t.Errorf("round 2 slot = %+v, %v, want the same pick", byRound, err)
+	}
+	if _, err := draftSlot(picks, 200, 40, 1); classifyError(err).Code != codeInvalidArgument {
+		t.Errorf("element 40 in round 1: err = %v, want invalid argument", err)
+	}
+	if _, err := draftSlot(picks, 200, 20, 0); classifyError(err).Code != codeNotFound {
+		t.Errorf("element 20 isn't Alpha's: err = %v, want not found", err)
+	}
+
+	// At Alpha's round-2 slot, 20 and 30 are gone; at round 1 they weren't.
+	if taken, ok := takenBefore(picks, slot, 30); !ok || taken.EntryID != 201 || taken.Index != 3 {
+		t.Errorf("30 at overall 4 = %+v %v, want taken by Beta at 3", taken, ok)
+	}
+	first, _ := draftSlot(picks, 200, 10, 0)
+	for _, id := range []int{20, 30, 99} {
+		if _, ok := takenBefore(picks, first, id); ok {
+			t.Errorf("element %d reported taken before overall 1", id)
+		}
+	}
+}
+
+func TestReplayWhatIf(t *testing.T) {
+	const original, alternate, bench = 10, 99, 15
+	lineup := func(originalPos int, subs ...ledger.EntrySub) ledger.EntrySnapshot {
+		return ledger.EntrySnapshot{
+			Picks: []ledger.EntryPick{{Element: original, Position: originalPos}, {Element: bench, Position: 12}},
+			Subs:  subs,
+		}
+	}
+	snaps := map[int]ledger.EntrySnapshot{
+		1: lineup(1),
+		2: lineup(1),
+		3: lineup(13), // benched
+		4: lineup(1, ledger.EntrySub{ElementIn: bench, ElementOut: original}),
+		5: lineup(1, ledger.EntrySub{ElementIn: bench, ElementOut: original}),
+		// GW6 has no snapshot.
+	}
+	stat := func(pts, minutes int) livestats.ElementStats {
+		return livestats.ElementStats{TotalPoints: pts, Minutes: minutes}
+	}
+	live := map[int]map[int]livestats.ElementStats{
+		1: {original: stat(2, 90), alternate: stat(12, 90)},
+		2: {original: stat(8, 90), alternate: stat(2, 90)},
+		3: {original: stat(1, 90), alternate: stat(15, 90)},
+		4: {original: stat(0, 0), bench: stat(3, 90), alternate: stat(9, 90)},
+		5: {original: stat(0, 0), bench: stat(3, 90), alternate: stat(0, 0)},
+		6: {original: stat(2, 90), alternate: stat(20, 90)},
+	}
+	matches := []whatIfMatch{
+		{gw: 1, score: 40, opponentScore: 45}, // +10: loss becomes a win
+		{gw: 2, score: 50, opponentScore: 47}, // -6: win becomes a loss
+		{gw: 3, score: 30, opponentScore: 40}, // benched: unchanged
+		{gw: 4, score: 40, opponentScore: 44}, // sub's 3 replaced by 9: +6, a win
+		{gw: 5, score: 40, opponentScore: 42}, // alternate blanked too: the sub stands
+		{gw: 6, score: 40, opponentScore: 50}, // no snapshot
+	}
+	got := replayWhatIf(matches, func(gw int) (ledger.EntrySnapshot, bool) {
+		s, ok := snaps[gw]
+		return s, ok
+	}, live, original, alternate)
+
+	want := []struct {
+		status   string
+		delta    int
+		cum      int
+		adjusted string
+		flipped  bool
+	}{
+		{whatIfStarted, 10, 10, "W", true},
+		{whatIfStarted, -6, 4, "L", true},
+		{whatIfBenched, 0, 4, "L", false},
+		{whatIfStarted, 6, 10, "W", true},
+		{whatIfStarted, 0, 10, "L", false},
+		{whatIfNoSnapshot, 0, 10, "L", false},
+	}
+	for i, w := range want {
+		g := got[i]
+		if g.OriginalStatus != w.status || g.Delta != w.delta || g.CumulativeDelta != w.cum || g.AdjustedResult != w.adjusted || g.Flipped != w.flipped {
+			t.Errorf("GW%d = %+v, want %s delta %d cumulative %d %s flipped=%v", g.GW, g, w.status, w.delta, w.cum, w.adjusted, w.flipped)
+		}
+	}
+	if g := got[3]; g.OriginalPoints != 3 || g.AlternatePoints != 9 || g.AdjustedScore != 46 {
+		t.Errorf("GW4 auto-sub = %+v, want the sub's 3 replaced by 9", g)
+	}
+}
+
+func TestBuildDraftWhatIf_RefusesTakenPlayer(t *testing.T) {
+	dir, cfg := resourceCfg(t)
+	cfg.ComputeMissing = true
+	writeClaimFixture(t, dir)
+	// Alpha drafted Haaland (6) at overall 6; Saka (4) went to Beta at 4.
+	original := 6
+	_, err := buildDraftWhatIf(cfg, DraftWhatIfArgs{LeagueID: 100, EntryID: 200, Original: &original, Alternate: 4, ThroughGW: 3})
+	if classifyError(err).Code != codeInvalidArgument || !strings.Contains(err.Error(), "Saka wasn't available") || !strings.Contains(err.Error(), "Beta FC") {
+		t.Errorf("err = %v, want Saka refused as taken by Beta", err)
+	}
+	for _, args := range []DraftWhatIfArgs{
+		{LeagueID: 100, EntryID: 200, Alternate: 4},
+		{LeagueID: 100, EntryID: 200, Original: &original},
+		{LeagueID: 100, EntryID: 200, Original: &original, Alternate: 6},
+	} {
+		if _, err := buildDraftWhatIf(cfg, args); classifyError(err).Code != codeInvalidArgument {
+			t.Errorf("%+v: err = %v, want invalid argument", args, err)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// resolveEffectiveGW modes.
+const (
+	// gwModeLatestFinished is for backward-looking tools that need results:
+	// the current GW once it has started (live.json exists) or finished,
+	// otherwise the most recent GW that has live data.
+	gwModeLatestFinished = "latest_finished"
+	// gwModeCurrent is for forward-looking tools: the current GW until it
+	// finishes, then next_event.
+	gwModeCurrent = "current"
+)
+
+// GWNote explains why a tool answered for a different gameweek than the one
+// requested (or the current one when gw was 0).
+type GWNote struct {
+	RequestedGW int    `json:"requested_gw"`
+	CurrentGW   int    `json:"current_gw"`
+	EffectiveGW int    `json:"effective_gw"`
+	Reason      string `json:"reason"`
+}
+
+// resolveEffectiveGW resolves gw=0 according to mode. Early in a gameweek
+// (after the deadline, before kickoff) game.json's current_event has already
+// advanced but gw/{n}/live.json does not exist yet, so summaries for it can't
+// be built. An explicit requested GW is always honored.
+func resolveEffectiveGW(cfg ServerConfig, requested int, mode string) (int, *GWNote, error) {
+	if requested > 0 {
+		return requested, nil, nil
+	}
+	gamePath := filepath.Join(cfg.RawRoot, "game", "game.json")
+	raw, err := os.ReadFile(gamePath)
+	if err != nil {
+		return 0, nil, fmt.Errorf("missing game meta: %w", err)
+	}
+	var meta GameMeta
+	if err := json.Unmarshal(raw, &meta); err != nil {
+		return 0, nil, err
+	}
+	cur := meta.CurrentEvent
+	if cur == 0 {
+		return 0, nil, staleData(gamePath, "current_event missing in game.json")
+	}
+
+	switch mode {
+	case gwModeCurrent:
+		if meta.CurrentEventFinished && meta.NextEvent > cur {
+			return meta.NextEvent, &GWNote{
+				CurrentGW:   cur,
+				EffectiveGW: meta.NextEvent,
+				Reason:      fmt.Sprintf("GW %d has finished; using the next gameweek", cur),
+			}, nil
+		}
+		return cur, nil, nil
+	case gwModeLatestFinished:
+		if meta.CurrentEventFinished || liveDataExists(cfg.RawRoot, cur) {
+			return cur, nil, nil
+		}
+		for gw := cur - 1; gw >= 1; gw-- {
+			if liveDataExists(cfg.RawRoot, gw) {
+				return gw, &GWNote{
+					CurrentGW:   cur,
+					EffectiveGW: gw,
+					Reason:      fmt.Sprintf("GW %d has not started yet; using GW %d, the latest gameweek with results", cur, gw),
+				}, nil
+			}
+		}
+		return 0, nil, notFoundf("no gameweek has results yet (GW %d has not started)", cur)
+	default:
+		return 0, nil, fmt.Errorf("unknown gw mode: %s", mode)
+	}
+}
+
+func liveDataExists(rawRoot string, gw int) bool {
+	_, err := os.Stat(filepath.Join(rawRoot, "gw", strconv.Itoa(gw), "live.json"))
+	return err == nil
+}
+
+// withGWNote adds a "gw_note" field to a JSON object document. The input is
+// returned unchanged when note is nil or raw is not an object.
+func withGWNote(raw []byte, note *GWNote) []byte {
+	if note == nil {
+		return raw
+	}
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return raw
+	}
+	b, err := json.Marshal(note)
+	if err != nil {
+		return raw
+	}
+	obj["gw_note"] = b
+	out, err := json.MarshalIndent(obj, "", "  ")
+	if err != nil {
+		return raw
+	}
+	return out
+}
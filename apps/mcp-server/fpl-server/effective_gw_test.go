@@ -0,0 +1,170 @@
+package main
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+)
+
+// Pre-kickoff window: the GW 8 deadline has passed so current_event is 8,
+// but no fixture has started and gw/8/live.json doesn't exist yet.
+func writePreKickoffFixture(t *testing.T, dir string) {
+	t.Helper()
+	writeFullGameJSON(t, dir, 8, false, 9, true, "")
+	writeLiveJSON(t, dir, 6, map[string]any{})
+	writeLiveJSON(t, dir, 7, map[string]any{})
+}
+
+func TestResolveEffectiveGW(t *testing.T) {
+	t.Run("PreKickoffLatestFinished", func(t *testing.T) {
+		dir, cfg := tmpCfg(t)
+		writePreKickoffFixture(t, dir)
+		gw, note, err := resolveEffectiveGW(cfg, 0, gwModeLatestFinished)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if gw != 7 {
+			t.Errorf("gw = %d, want 7", gw)
+		}
+		if note == nil || note.CurrentGW != 8 || note.EffectiveGW != 7 {
+			t.Errorf("note = %+v, want current 8 effective 7", note)
+		}
+	})
+
+	t.Run("PreKickoffCurrent", func(t *testing.T) {
+		dir, cfg := tmpCfg(t)
+		writePreKickoffFixture(t, dir)
+		gw, note, err := resolveEffectiveGW(cfg, 0, gwModeCurrent)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if gw != 8 || note != nil {
+			t.Errorf("gw = %d note = %+v, want 8 with no note", gw, note)
+		}
+	})
+
+	t.Run("InProgressUsesCurrent", func(t *testing.T) {
+		dir, cfg := tmpCfg(t)
+		writePreKickoffFixture(t, dir)
+		writeLiveJSON(t, dir, 8, map[string]any{})
+		gw, note, err := resolveEffectiveGW(cfg, 0, gwModeLatestFinished)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if gw != 8 || note != nil {
+			t.Errorf("gw = %d note = %+v, want 8 with no note", gw, note)
+		}
+	})
+
+	t.Run("FinishedCurrentMovesForward", func(t *testing.T) {
+		dir, cfg := tmpCfg(t)
+		writeFullGameJSON(t, dir, 8, true, 9, false, "")
+		gw, note, err := resolveEffectiveGW(cfg, 0, gwModeCurrent)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if gw != 9 || note == nil {
+			t.Errorf("gw = %d note = %+v, want 9 with a note", gw, note)
+		}
+		if gw, _, _ := resolveEffectiveGW(cfg, 0, gwModeLatestFinished); gw != 8 {
+			t.Errorf("latest_finished gw = %d, want 8", gw)
+		}
+	})
+
+	t.Run("ExplicitGWHonored", func(t *testing.T) {
+		dir, cfg := tmpCfg(t)
+		writePreKickoffFixture(t, dir)
+		gw, note, err := resolveEffectiveGW(cfg, 8, gwModeLatestFinished)
+		if err != nil || gw != 8 || note != nil {
+			t.Errorf("gw = %d note = %+v err = %v, want 8", gw, note, err)
+		}
+	})
+
+	t.Run("NoResultsYet", func(t *testing.T) {
+		dir, cfg := tmpCfg(t)
+		writeFullGameJSON(t, dir, 1, false, 2, false, "")
+		_, _, err := resolveEffectiveGW(cfg, 0, gwModeLatestFinished)
+		if got := classifyError(err).Code; got != codeNotFound {
+			t.Errorf("code = %s, want NOT_FOUND", got)
+		}
+	})
+}
+
+func TestWithGWNote(t *testing.T) {
+	note := &GWNote{CurrentGW: 8, EffectiveGW: 7, Reason: "r"}
+	out := withGWNote([]byte(`{"rows":[]}`), note)
+	var got struct {
+		Rows   []any   `json:"rows"`
+		GWNote *GWNote `json:"gw_note"`
+	}
+	if err := json.Unmarshal(out, &got); err != nil {
+		t.Fatalf("unmarshal: %v (%s)", err, out)
+	}
+	if got.GWNote == nil || got.GWNote.EffectiveGW != 7 || got.Rows == nil {
+		t.Errorf("got %+v", got)
+	}
+	if string(withGWNote([]byte(`{"a":1}`), nil)) != `{"a":1}` {
+		t.Error("nil note should leave the body untouched")
+	}
+}
+
+// Fixtures for an unstarted GW are computed from bootstrap alone instead of
+// failing inside BuildLeagueSummaries on the missing live.json.
+func TestLoadSummaryFile_FixturesBeforeKickoff(t *testing.T) {
+	dir, cfg := tmpCfg(t)
+	cfg.DerivedRoot = dir
+	cfg.ComputeMissing = true
+	cfg.WriteDerived = true
+	writeJSON(t, filepath.Join(dir, "bootstrap", "bootstrap-static.json"), map[string]any{
+		"elements": []any{},
+		"teams": []any{
+			map[string]any{"id": 10, "short_name": "LIV"},
+			map[string]any{"id": 11, "short_name": "MCI"},
+		},
+		"fixtures": map[string]any{
+			"8": []any{map[string]any{"id": 1, "event": 8, "team_h": 10, "team_a": 11, "kickoff_time": "2025-10-18T11:30:00Z"}},
+		},
+	})
+
+	raw, err := loadSummaryFile(cfg, 100, 8, "summary/fixtures/100/from_gw/8_h5.json", []int{5}, nil)
+	if err != nil {
+		t.Fatalf("loadSummaryFile: %v", err)
+	}
+	var out struct {
+		Fixtures []struct {
+			TeamHShort string `json:"team_h_short"`
+		} `json:"fixtures"`
+	}
+	if err := json.Unmarshal(raw, &out); err != nil {
+		t.Fatal(err)
+	}
+	if len(out.Fixtures) != 1 || out.Fixtures[0].TeamHShort != "LIV" {
+		t.Errorf("fixtures = %+v", out.Fixtures)
+	}
+}
+
+// waiver_recommendations targets the GW gwModeCurrent picks: GW1 while it is
+// in progress, GW2 with a note once it has finished.
+func TestBuildWaiverRecommendations_CurrentMode(t *testing.T) {
+	for _, tc := range []struct {
+		finished bool
+		target   int
+		note     bool
+	}{{false, 1, false}, {true, 2, true}} {
+		dir, cfg := resourceCfg(t)
+		cfg.ComputeMissing = true
+		writeGW1Fixture(t, dir, gw1Live(), tc.finished)
+		alpha := 200
+		raw, err := buildWaiverRecommendations(cfg, WaiverRecommendationsArgs{LeagueID: 100, EntryID: &alpha})
+		if err != nil {
+			t.Fatalf("finished=%v: %v", tc.finished, err)
+		}
+		var report WaiverRecommendationsReport
+		if err := json.Unmarshal(raw, &report); err != nil {
+			t.Fatal(err)
+		}
+		if report.TargetGW != tc.target || (report.GWNote != nil) != tc.note {
+			t.Errorf("finished=%v: target_gw = %d gw_note = %+v, want %d (note %v)", tc.finished, report.TargetGW, report.GWNote, tc.target, tc.note)
+		}
+	}
+}
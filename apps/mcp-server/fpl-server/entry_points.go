@@ -0,0 +1,304 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/clock"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/ledger"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/livestats"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/points"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/summary"
+)
+
+// EntryPointsArgs are the input arguments for the entry_points tool.
+type EntryPointsArgs struct {
+	LeagueID  int     `json:"league_id" jsonschema:"Draft league id (required)"`
+	EntryID   *int    `json:"entry_id,omitempty" jsonschema:"Entry id"`
+	EntryName *string `json:"entry_name,omitempty" jsonschema:"Entry name (if entry_id not provided)"`
+	GW        *int    `json:"gw,omitempty" jsonschema:"Gameweek (0 = latest with results); ignored when start_gw or end_gw is set"`
+	StartGW   *int    `json:"start_gw,omitempty" jsonschema:"First gameweek of a range (default the league's first)"`
+	EndGW     *int    `json:"end_gw,omitempty" jsonschema:"Last gameweek of a range (default latest with results)"`
+}
+
+// Likely causes of a computed score disagreeing with the official one.
+const (
+	causeBonusNotFinal     = "bonus_not_final"
+	causeAutoSubs          = "auto_subs"
+	causeLateStatAmendment = "late_stat_amendment"
+)
+
+// PointsDiscrepancy compares the starter total computed from the entry's
+// snapshot and live.json with the score FPL recorded for its H2H match.
+// Delta is Computed minus Official; Cause is empty when they agree.
+type PointsDiscrepancy struct {
+	Computed    int    `json:"computed"`
+	Official    int    `json:"official"`
+	Delta       int    `json:"delta"`
+	Cause       string `json:"cause,omitempty"`
+	Explanation string `json:"explanation,omitempty"`
+}
+
+// EntryPointsGW is one GW's derived points result for the entry. Discrepancy
+// is nil when the league has no started match for the entry that GW.
+type EntryPointsGW struct {
+	points.Result
+	Discrepancy *PointsDiscrepancy `json:"discrepancy,omitempty"`
+}
+
+// EntryPointsOutput is the output of the entry_points tool.
+type EntryPointsOutput struct {
+	LeagueID    int             `json:"league_id"`
+	EntryID     int             `json:"entry_id"`
+	EntryName   string          `json:"entry_name"`
+	StartGW     int             `json:"start_gw"`
+	EndGW       int             `json:"end_gw"`
+	TotalPoints int             `json:"total_points"`
+	Gameweeks   []EntryPointsGW `json:"gameweeks"`
+	// MismatchedGWs lists the GWs whose computed score differs from the
+	// official one.
+	MismatchedGWs []int   `json:"mismatched_gws"`
+	GWNote        *GWNote `json:"gw_note,omitempty"`
+}
+
+// ensurePointsResults writes the points result for each entry and GW in
+// range that lacks one, from its snapshot and the GW's live stats, the way
+// cmd/dev derives them.
+func ensurePointsResults(st *store.JSONStore, derivedRoot string, leagueID int, entryIDs []int, minGW int, maxGW int, clk clock.Clock) error {
+	if err := ensureSnapshots(st, derivedRoot, leagueID, entryIDs, minGW, maxGW, clk); err != nil {
+		return err
+	}
+	minGW = max(minGW, leagueStartGW(st, leagueID))
+	for gw := minGW; gw <= maxGW; gw++ {
+		for _, entryID := range entryIDs {
+			outPath := filepath.Join(derivedRoot, fmt.Sprintf("points/%d/entry/%d/gw/%d.json", leagueID, entryID, gw))
+			if _, err := store.StatDerived(outPath); err == nil {
+				continue
+			}
+			_, err, _ := derivedFlights.Do(outPath, func() (struct{}, error) {
+				if _, err := store.StatDerived(outPath); err == nil {
+					return struct{}{}, nil
+				}
+				raw, err := store.ReadDerived(filepath.Join(derivedRoot, fmt.Sprintf("snapshots/%d/entry/%d/gw/%d.json", leagueID, entryID, gw)))
+				if err != nil {
+					return struct{}{}, err
+				}
+				var snap ledger.EntrySnapshot
+				if err := json.Unmarshal(raw, &snap); err != nil {
+					return struct{}{}, err
+				}
+				live, err := livestats.LoadGW(st, gw)
+				if err != nil {
+					return struct{}{}, err
+				}
+				return struct{}{}, points.WriteResult(outPath, points.BuildResult(leagueID, entryID, gw, &snap, live.Elements, clk))
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// loadPointsResult reads the derived points result for entryID at gw,
+// building it when it is missing.
+func loadPointsResult(cfg ServerConfig, leagueID int, entryID int, gw int) (points.Result, error) {
+	st := store.NewJSONStore(cfg.RawRoot)
+	relPath := fmt.Sprintf("points/%d/entry/%d/gw/%d.json", leagueID, entryID, gw)
+	raw, err := loadDerivedFile(cfg, relPath, func(root string) error {
+		return withRawBackfill(cfg, leagueID, rawNeed{FromGW: gw, ToGW: gw, Live: true, EntryIDs: []int{entryID}}, func() error {
+			return ensurePointsResults(st, root, leagueID, []int{entryID}, gw, gw, cfg.Clock)
+		})
+	})
+	if err != nil {
+		return points.Result{}, err
+	}
+	var res points.Result
+	if err := json.Unmarshal(raw, &res); err != nil {
+		return points.Result{}, err
+	}
+	return res, nil
+}
+
+func buildEntryPoints(cfg ServerConfig, args EntryPointsArgs) (EntryPointsOutput, error) {
+	if args.LeagueID == 0 {
+		return EntryPointsOutput{}, invalidArgumentf("league_id is required")
+	}
+	st := store.NewJSONStore(cfg.RawRoot)
+	ld, _, err := loadLeagueDetails(st, args.LeagueID)
+	if err != nil {
+		return EntryPointsOutput{}, err
+	}
+
+	entryID := 0
+	if args.EntryID != nil {
+		entryID = *args.EntryID
+	}
+	if entryID == 0 {
+		name := ""
+		if args.EntryName != nil {
+			name = strings.TrimSpace(*args.EntryName)
+		}
+		if entryID, err = resolveEntry(ld.LeagueEntries, name); err != nil {
+			return EntryPointsOutput{}, err
+		}
+	}
+	var entry *summary.LeagueEntry
+	for i := range ld.LeagueEntries {
+		if ld.LeagueEntries[i].EntryID == entryID {
+			entry = &ld.LeagueEntries[i]
+		}
+	}
+	if entry == nil {
+		return EntryPointsOutput{}, notFoundf("entry not found: %d", entryID)
+	}
+
+	var startGW, endGW int
+	var note *GWNote
+	if args.StartGW == nil && args.EndGW == nil {
+		requested := 0
+		if args.GW != nil {
+			requested = *args.GW
+		}
+		if startGW, note, err = resolveEffectiveGW(cfg, requested, gwModeLatestFinished); err != nil {
+			return EntryPointsOutput{}, err
+		}
+		endGW = startGW
+	} else {
+		startGW = ld.StartGW()
+		if args.StartGW != nil {
+			startGW = *args.StartGW
+		}
+		requested := 0
+		if args.EndGW != nil {
+			requested = *args.EndGW
+		}
+		if endGW, note, err = resolveEffectiveGW(cfg, requested, gwModeLatestFinished); err != nil {
+			return EntryPointsOutput{}, err
+		}
+		if startGW < 1 || startGW > endGW {
+			return EntryPointsOutput{}, invalidArgumentf("start_gw %d must be between 1 and end_gw %d", startGW, endGW)
+		}
+	}
+	if startGW < ld.StartGW() {
+		return EntryPointsOutput{}, invalidArgumentf("league %d starts at GW %d; GW %d is before it", args.LeagueID, ld.StartGW(), startGW)
+	}
+
+	teamOf := make(map[int]int)
+	if elements, _, _, err := loadBootstrapData(cfg.RawRoot); err == nil {
+		for _, e := range elements {
+			teamOf[e.ID] = e.TeamID
+		}
+	}
+
+	out := EntryPointsOutput{
+		LeagueID:      args.LeagueID,
+		EntryID:       entryID,
+		EntryName:     entry.EntryName,
+		StartGW:       startGW,
+		EndGW:         endGW,
+		Gameweeks:     make([]EntryPointsGW, 0, endGW-startGW+1),
+		MismatchedGWs: make([]int, 0),
+		GWNote:        note,
+	}
+	for gw := startGW; gw <= endGW; gw++ {
+		res, err := loadPointsResult(cfg, args.LeagueID, entryID, gw)
+		if err != nil {
+			return EntryPointsOutput{}, err
+		}
+		row := EntryPointsGW{Result: res}
+		if official, ok := officialScore(ld, entry.ID, gw); ok {
+			live, err := livestats.LoadGW(st, gw)
+			if err != nil {
+				return EntryPointsOutput{}, err
+			}
+			snap, err := loadEntrySnapshot(cfg, args.LeagueID, entryID, gw)
+			if err != nil {
+				return EntryPointsOutput{}, err
+			}
+			row.Discrepancy = explainDiscrepancy(res, official, &snap, live, teamOf)
+			if row.Discrepancy.Delta != 0 {
+				out.MismatchedGWs = append(out.MismatchedGWs, gw)
+			}
+		}
+		out.TotalPoints += res.TotalPoints
+		out.Gameweeks = append(out.Gameweeks, row)
+	}
+	return out, nil
+}
+
+// officialScore is the score league details record for leagueEntryID's
+// match in gw, if that match has started.
+func officialScore(ld summary.LeagueDetails, leagueEntryID int, gw int) (int, bool) {
+	for _, m := range ld.Matches {
+		if m.Event != gw || !m.Started {
+			continue
+		}
+		switch leagueEntryID {
+		case m.LeagueEntry1:
+			return m.LeagueEntry1Points, true
+		case m.LeagueEntry2:
+			return m.LeagueEntry2Points, true
+		}
+	}
+	return 0, false
+}
+
+// explainDiscrepancy compares res with official and names the likeliest
+// cause of a gap, checked in order: bonus on the counted players that FPL
+// hasn't confirmed yet; the official score matching the XI as picked, or a
+// counted starter with no minutes, which point at automatic substitutions;
+// otherwise a stat amended between the live.json and details.json fetches.
+func explainDiscrepancy(res points.Result, official int, snap *ledger.EntrySnapshot, live *livestats.GW, teamOf map[int]int) *PointsDiscrepancy {
+	d := &PointsDiscrepancy{Computed: res.TotalPoints, Official: official, Delta: res.TotalPoints - official}
+	if d.Delta == 0 {
+		return d
+	}
+
+	counted := make(map[int]bool, len(res.Players))
+	for _, p := range res.Players {
+		counted[p.Element] = true
+	}
+	provisional := 0
+	for _, fb := range livestats.ProvisionalBonus(live, teamOf) {
+		if fb.Status != livestats.BonusProvisional {
+			continue
+		}
+		for element, bonus := range fb.Bonus {
+			if counted[element] {
+				provisional += bonus
+			}
+		}
+	}
+	if provisional > 0 {
+		d.Cause = causeBonusNotFinal
+		d.Explanation = fmt.Sprintf("%d provisional bonus points on this lineup aren't confirmed yet; the scores should agree once FPL confirms bonus.", provisional)
+		return d
+	}
+
+	asPicked, blank := 0, 0
+	for _, p := range snap.Picks {
+		if p.Position > 11 {
+			continue
+		}
+		asPicked += live.Elements[p.Element].TotalPoints
+	}
+	for _, p := range res.Players {
+		if p.Minutes == 0 && !p.SubbedIn {
+			blank++
+		}
+	}
+	if (asPicked == official && asPicked != res.TotalPoints) || blank > 0 {
+		d.Cause = causeAutoSubs
+		d.Explanation = fmt.Sprintf("The XI as picked scores %d; automatic substitutions (%d applied here, %d starters left without minutes) account for the gap.", asPicked, len(res.AutoSubs), blank)
+		return d
+	}
+
+	d.Cause = causeLateStatAmendment
+	d.Explanation = "No provisional bonus or substitution explains the gap, so a stat was probably amended between the live.json and details.json fetches; refreshing both should reconcile them."
+	return d
+}
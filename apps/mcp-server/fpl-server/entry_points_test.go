@@ -0,0 +1,126 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeEntryPointsFixture writes GWs 10-12 for Alpha FC (200) against Beta
+// FC (201). Each GW's official score disagrees with what live.json gives
+// Alpha for a different reason: a stat amended in GW10, an auto sub in GW11
+// and unconfirmed bonus in GW12.
+func writeEntryPointsFixture(t *testing.T, dir string) {
+	t.Helper()
+	writeBootstrap(t, dir)
+	writeFullGameJSON(t, dir, 12, true, 13, false, "")
+	match := func(gw, alpha, beta int) map[string]any {
+		return map[string]any{"event": gw, "started": true, "finished": true, "league_entry_1": 1, "league_entry_1_points": alpha, "league_entry_2": 2, "league_entry_2_points": beta}
+	}
+	writeLeagueDetailsFixture(t, dir, 100, []any{
+		map[string]any{"id": 1, "entry_id": 200, "entry_name": "Alpha FC", "short_name": "AFC"},
+		map[string]any{"id": 2, "entry_id": 201, "entry_name": "Beta FC", "short_name": "BFC"},
+	}, []any{match(10, 14, 8), match(11, 12, 8), match(12, 20, 8)})
+
+	picks := []any{
+		map[string]any{"element": 1, "position": 1},
+		map[string]any{"element": 3, "position": 2},
+		map[string]any{"element": 2, "position": 12},
+	}
+	for gw := 10; gw <= 12; gw++ {
+		entry := map[string]any{"picks": picks, "subs": []any{}}
+		if gw == 11 {
+			entry["subs"] = []any{map[string]any{"element_in": 2, "element_out": 3, "event": 11}}
+		}
+		writeJSON(t, filepath.Join(dir, "entry/200/gw", itoa(gw)+".json"), entry)
+		writeJSON(t, filepath.Join(dir, "entry/201/gw", itoa(gw)+".json"), map[string]any{
+			"picks": []any{map[string]any{"element": 2, "position": 1}},
+		})
+	}
+	stats := func(minutes, points, bps int) map[string]any {
+		return map[string]any{"stats": map[string]any{"minutes": minutes, "total_points": points, "bps": bps}}
+	}
+	writeLiveJSON(t, dir, 10, map[string]any{"1": stats(90, 10, 0), "2": stats(90, 8, 0), "3": stats(90, 5, 0)})
+	writeLiveJSON(t, dir, 11, map[string]any{"1": stats(90, 12, 0), "2": stats(90, 8, 0), "3": stats(0, 0, 0)})
+	// Salah and Alexander-Arnold top the BPS in a fixture still in play.
+	writeJSON(t, filepath.Join(dir, "gw/12/live.json"), map[string]any{
+		"elements": map[string]any{"1": stats(90, 10, 40), "2": stats(90, 8, 5), "3": stats(90, 5, 30)},
+		"fixtures": []any{map[string]any{"id": 1, "event": 12, "team_h": 10, "team_a": 11, "started": true, "finished": false}},
+	})
+}
+
+func TestBuildEntryPoints_Discrepancies(t *testing.T) {
+	dir, cfg := tmpCfg(t)
+	cfg.DerivedRoot = filepath.Join(dir, "derived")
+	cfg.ComputeMissing = true
+	cfg.WriteDerived = true
+	writeEntryPointsFixture(t, dir)
+
+	name := "Alpha FC"
+	start := 10
+	out, err := buildEntryPoints(cfg, EntryPointsArgs{LeagueID: 100, EntryName: &name, StartGW: &start})
+	if err != nil {
+		t.Fatalf("buildEntryPoints: %v", err)
+	}
+	if out.EntryID != 200 || out.StartGW != 10 || out.EndGW != 12 || len(out.Gameweeks) != 3 {
+		t.Fatalf("out = %+v", out)
+	}
+	if out.TotalPoints != 15+20+15 || len(out.MismatchedGWs) != 3 {
+		t.Errorf("total %d, mismatched %v", out.TotalPoints, out.MismatchedGWs)
+	}
+	want := []struct {
+		computed, delta int
+		cause           string
+	}{
+		{15, 1, causeLateStatAmendment},
+		{20, 8, causeAutoSubs},
+		{15, -5, causeBonusNotFinal},
+	}
+	for i, w := range want {
+		d := out.Gameweeks[i].Discrepancy
+		if d == nil || d.Computed != w.computed || d.Delta != w.delta || d.Cause != w.cause || d.Explanation == "" {
+			t.Errorf("GW%d discrepancy = %+v, want %+v", 10+i, d, w)
+		}
+	}
+	if gw11 := out.Gameweeks[1]; len(gw11.AutoSubs) != 1 || !gw11.Players[1].SubbedIn {
+		t.Errorf("GW11 result = %+v", gw11.Result)
+	}
+	if _, err := os.Stat(filepath.Join(cfg.DerivedRoot, "points/100/entry/200/gw/11.json")); err != nil {
+		t.Errorf("points result not written with WriteDerived=true: %v", err)
+	}
+
+	// Beta's single GW defaults to the latest finished and matches.
+	beta := 201
+	out, err = buildEntryPoints(cfg, EntryPointsArgs{LeagueID: 100, EntryID: &beta})
+	if err != nil {
+		t.Fatalf("Beta: %v", err)
+	}
+	if len(out.Gameweeks) != 1 || out.Gameweeks[0].Gameweek != 12 {
+		t.Fatalf("Beta = %+v", out)
+	}
+	if d := out.Gameweeks[0].Discrepancy; d == nil || d.Delta != 0 || d.Cause != "" || len(out.MismatchedGWs) != 0 {
+		t.Errorf("Beta discrepancy = %+v", d)
+	}
+}
+
+func TestBuildEntryPoints_Errors(t *testing.T) {
+	dir, cfg := tmpCfg(t)
+	cfg.DerivedRoot = filepath.Join(dir, "derived")
+	writeEntryPointsFixture(t, dir)
+	entry := 200
+	if _, err := buildEntryPoints(cfg, EntryPointsArgs{LeagueID: 100}); classifyError(err).Code != codeInvalidArgument {
+		t.Errorf("no entry: %v", err)
+	}
+	missing := 999
+	if _, err := buildEntryPoints(cfg, EntryPointsArgs{LeagueID: 100, EntryID: &missing}); classifyError(err).Code != codeNotFound {
+		t.Errorf("unknown entry: %v", err)
+	}
+	start, end := 12, 11
+	if _, err := buildEntryPoints(cfg, EntryPointsArgs{LeagueID: 100, EntryID: &entry, StartGW: &start, EndGW: &end}); classifyError(err).Code != codeInvalidArgument {
+		t.Errorf("reversed range: %v", err)
+	}
+	// Without compute-missing an underived GW is DATA_MISSING.
+	if _, err := buildEntryPoints(cfg, EntryPointsArgs{LeagueID: 100, EntryID: &entry}); classifyError(err).Code != codeDataMissing {
+		t.Errorf("not derived: %v", err)
+	}
+}
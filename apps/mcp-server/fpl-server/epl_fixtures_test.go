@@ -177,3 +177,24 @@ func TestBuildEPLFixtures_DGW(t *testing.T) {
 		t.Errorf("expected 3 fixtures (DGW), got %d", len(result.Fixtures))
 	}
 }
+
+func TestBuildEPLFixtures_SharedDGW(t *testing.T) {
+	dir, cfg := tmpCfg(t)
+	writeDGWFixture(t, dir)
+
+	result, err := buildEPLFixtures(cfg, dgwGW)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Fixtures) != 2 {
+		t.Fatalf("expected 2 fixtures, got %d", len(result.Fixtures))
+	}
+	for _, f := range result.Fixtures {
+		if f.HomeShort != "ARS" && f.AwayShort != "ARS" {
+			t.Errorf("fixture %s v %s: expected Arsenal in both DGW fixtures", f.HomeShort, f.AwayShort)
+		}
+	}
+	if !result.Fixtures[0].Finished || result.Fixtures[1].Started {
+		t.Errorf("fixture states = %+v, want first finished and second not started", result.Fixtures)
+	}
+}
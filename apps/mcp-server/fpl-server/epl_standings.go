@@ -2,7 +2,6 @@ package main
 
 import (
 	"context"
-	"fmt"
 	"sort"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -55,7 +54,7 @@ func buildEPLStandings(cfg ServerConfig) (*EPLStandingsResult, error) {
 	}
 	currentGW := meta.CurrentEvent
 	if currentGW < 1 {
-		return nil, fmt.Errorf("no gameweeks played yet")
+		return nil, notFoundf("no gameweeks played yet")
 	}
 
 	accum := make(map[int]*teamAccum)
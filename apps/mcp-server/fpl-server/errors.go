@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+)
+
+// errorCode is the machine-readable category returned in tool error bodies so
+// clients can branch on the failure kind instead of parsing messages.
+type errorCode string
+
+const (
+	codeInvalidArgument errorCode = "INVALID_ARGUMENT"
+	codeNotFound        errorCode = "NOT_FOUND"
+	codeDataMissing     errorCode = "DATA_MISSING"
+	codeStaleData       errorCode = "STALE_DATA"
+	codeInternal        errorCode = "INTERNAL"
+)
+
+// refreshHint is attached to DATA_MISSING/STALE_DATA errors.
+const refreshHint = "run the refresh pipeline (go run ./apps/mcp-server/cmd/dev) to fetch or rebuild this data"
+
+// codedError is an error carrying an errorCode and optional details. It wraps
+// an underlying cause so errors.Is/As keep working through it.
+type codedError struct {
+	Code    errorCode
+	Message string
+	Details map[string]any
+	Err     error
+}
+
+func (e *codedError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.Err)
+	}
+	return e.Message
+}
+
+func (e *codedError) Unwrap() error { return e.Err }
+
+func invalidArgumentf(format string, args ...any) error {
+	return &codedError{Code: codeInvalidArgument, Message: fmt.Sprintf(format, args...)}
+}
+
+func notFoundf(format string, args ...any) error {
+	return &codedError{Code: codeNotFound, Message: fmt.Sprintf(format, args...)}
+}
+
+// dataMissing reports a data file that should exist at path but does not.
+func dataMissing(path string, err error) error {
+	return &codedError{
+		Code:    codeDataMissing,
+		Message: fmt.Sprintf("missing data file: %s", path),
+		Details: map[string]any{"path": path, "hint": refreshHint},
+		Err:     err,
+	}
+}
+
+// staleData reports a data file that exists but is too old to answer from.
+func staleData(path string, message string) error {
+	return &codedError{
+		Code:    codeStaleData,
+		Message: message,
+		Details: map[string]any{"path": path, "hint": refreshHint},
+	}
+}
+
+// classifyError maps any error onto a codedError. Coded errors anywhere in the
+// chain win; a not-exist filesystem error becomes DATA_MISSING with the path
+// from the *fs.PathError; everything else is INTERNAL.
+func classifyError(err error) *codedError {
+	var ce *codedError
+	if errors.As(err, &ce) {
+		if err == error(ce) {
+			return ce
+		}
+		// Keep the outer context added by fmt.Errorf wrapping.
+		return &codedError{Code: ce.Code, Message: err.Error(), Details: ce.Details, Err: ce.Err}
+	}
+	if errors.Is(err, fs.ErrNotExist) {
+		details := map[string]any{"hint": refreshHint}
+		var pe *fs.PathError
+		if errors.As(err, &pe) {
+			details["path"] = pe.Path
+		}
+		return &codedError{Code: codeDataMissing, Message: err.Error(), Details: details, Err: err}
+	}
+	return &codedError{Code: codeInternal, Message: err.Error(), Err: err}
+}
+
+// errorObject is the JSON shape of one classified error.
+type errorObject struct {
+	Code    errorCode      `json:"code"`
+	Message string         `json:"message"`
+	Details map[string]any `json:"details,omitempty"`
+}
+
+func newErrorObject(err error) errorObject {
+	ce := classifyError(err)
+	return errorObject{Code: ce.Code, Message: ce.Message, Details: ce.Details}
+}
+
+// toolErrorBody is the JSON shape of a tool error result.
+type toolErrorBody struct {
+	Error errorObject `json:"error"`
+}
+
+func marshalToolError(err error) []byte {
+	b, mErr := json.Marshal(toolErrorBody{Error: newErrorObject(err)})
+	if mErr != nil {
+		return []byte(fmt.Sprintf(`{"error":{"code":%q,"message":%q}}`, codeInternal, err.Error()))
+	}
+	return b
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// decodeToolError parses the JSON body of an IsError tool result.
+func decodeToolError(t *testing.T, res *mcp.CallToolResult) toolErrorBody {
+	t.Helper()
+	if !res.IsError {
+		t.Fatal("expected IsError=true")
+	}
+	text, ok := res.Content[0].(*mcp.TextContent)
+	if !ok {
+		t.Fatalf("content is %T, want *mcp.TextContent", res.Content[0])
+	}
+	var body toolErrorBody
+	if err := json.Unmarshal([]byte(text.Text), &body); err != nil {
+		t.Fatalf("error body is not JSON: %v (%s)", err, text.Text)
+	}
+	return body
+}
+
+func TestToolError_Body(t *testing.T) {
+	body := decodeToolError(t, toolError(invalidArgumentf("league_id is required")))
+	if body.Error.Code != codeInvalidArgument {
+		t.Errorf("code = %s, want %s", body.Error.Code, codeInvalidArgument)
+	}
+	if body.Error.Message != "league_id is required" {
+		t.Errorf("message = %q", body.Error.Message)
+	}
+}
+
+func TestClassifyError(t *testing.T) {
+	t.Run("WrappedCodeKeepsContext", func(t *testing.T) {
+		ce := classifyError(fmt.Errorf("team_a: %w", notFoundf("no entry found for name: X")))
+		if ce.Code != codeNotFound {
+			t.Errorf("code = %s, want NOT_FOUND", ce.Code)
+		}
+		if ce.Message != "team_a: no entry found for name: X" {
+			t.Errorf("message = %q", ce.Message)
+		}
+	})
+
+	t.Run("PlainErrorIsInternal", func(t *testing.T) {
+		if ce := classifyError(fmt.Errorf("boom")); ce.Code != codeInternal {
+			t.Errorf("code = %s, want INTERNAL", ce.Code)
+		}
+	})
+}
+
+// ---------------------------------------------------------------------------
+// Codes for common tool failures
+// ---------------------------------------------------------------------------
+
+func TestErrorCodes_CommonFailures(t *testing.T) {
+	t.Run("MissingLeagueID", func(t *testing.T) {
+		_, cfg := tmpCfg(t)
+		_, err := buildWaiverRecommendations(cfg, WaiverRecommendationsArgs{})
+		if got := classifyError(err).Code; got != codeInvalidArgument {
+			t.Errorf("code = %s, want INVALID_ARGUMENT", got)
+		}
+	})
+
+	t.Run("UnknownProjectionModel", func(t *testing.T) {
+		_, cfg := tmpCfg(t)
+		entry := 1
+		_, err := buildWaiverRecommendations(cfg, WaiverRecommendationsArgs{LeagueID: 1, EntryID: &entry, Model: "xgboost"})
+		if got := classifyError(err).Code; got != codeInvalidArgument {
+			t.Errorf("code = %s, want INVALID_ARGUMENT", got)
+		}
+	})
+
+	t.Run("MissingGameJSON", func(t *testing.T) {
+		dir, cfg := tmpCfg(t)
+		_, err := resolveGW(cfg, 0)
+		ce := classifyError(err)
+		if ce.Code != codeDataMissing {
+			t.Fatalf("code = %s, want DATA_MISSING", ce.Code)
+		}
+		if ce.Details["path"] != filepath.Join(dir, "game", "game.json") {
+			t.Errorf("details.path = %v", ce.Details["path"])
+		}
+		if ce.Details["hint"] == nil {
+			t.Error("expected refresh hint in details")
+		}
+	})
+
+	t.Run("CurrentEventMissing", func(t *testing.T) {
+		dir, cfg := tmpCfg(t)
+		writeGameJSON(t, dir, 0)
+		_, err := resolveGW(cfg, 0)
+		if got := classifyError(err).Code; got != codeStaleData {
+			t.Errorf("code = %s, want STALE_DATA", got)
+		}
+	})
+
+	t.Run("MissingSummaryFile", func(t *testing.T) {
+		dir, cfg := tmpCfg(t)
+		cfg.DerivedRoot = dir
+		_, err := loadSummaryFile(cfg, 1, 1, "summary/standings/1/gw/1.json", nil, nil)
+		ce := classifyError(err)
+		if ce.Code != codeDataMissing {
+			t.Fatalf("code = %s, want DATA_MISSING", ce.Code)
+		}
+		if ce.Details["path"] != filepath.Join(dir, "summary/standings/1/gw/1.json") {
+			t.Errorf("details.path = %v", ce.Details["path"])
+		}
+	})
+
+	t.Run("MissingLeagueDetails", func(t *testing.T) {
+		_, cfg := tmpCfg(t)
+		_, err := buildManagerSchedule(cfg, ManagerScheduleArgs{LeagueID: 999})
+		if got := classifyError(err).Code; got != codeDataMissing {
+			t.Errorf("code = %s, want DATA_MISSING", got)
+		}
+	})
+
+	t.Run("UnknownEntryName", func(t *testing.T) {
+		dir, cfg := tmpCfg(t)
+		writeLeagueDetailsFixture(t, dir, 100, []any{
+			map[string]any{"entry_id": 200, "entry_name": "Alpha FC", "id": 1},
+		}, nil)
+		name := "Nobody"
+		_, err := buildManagerSeason(cfg, ManagerSeasonArgs{LeagueID: 100, EntryName: &name})
+		if got := classifyError(err).Code; got != codeNotFound {
+			t.Errorf("code = %s, want NOT_FOUND", got)
+		}
+	})
+}
@@ -0,0 +1,318 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/clock"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Placeholders in example args. buildServer fills them in from the default
+// league's data; one it can't fill stays in the args as written, so a
+// client still sees what goes there.
+const (
+	exampleLeague   = "<league_id>"
+	exampleEntry    = "<entry_id>"
+	exampleOpponent = "<opponent_entry_id>"
+	exampleElement  = "<element_id>"
+)
+
+// Abridging limits for saved example outputs.
+const (
+	exampleMaxItems   = 3
+	exampleMaxStrLen  = 200
+	exampleMaxTextLen = 1500
+)
+
+// ToolExample is a sample call of a tool. Output is the abridged result
+// --emit-examples last saved for these args; Tested is false when it
+// hasn't run them against local data, with Note saying why.
+type ToolExample struct {
+	Args   map[string]any  `json:"args"`
+	Output json.RawMessage `json:"output,omitempty"`
+	Tested bool            `json:"tested"`
+	Note   string          `json:"note,omitempty"`
+}
+
+// savedExamples is the file --emit-examples writes per tool.
+type savedExamples struct {
+	Tool           string        `json:"tool"`
+	GeneratedAtUTC string        `json:"generated_at_utc"`
+	Examples       []ToolExample `json:"examples"`
+}
+
+// defaultLeagueFromEnv is LEAGUE_ID, the agent's league, or 0 when it is
+// unset or not a number.
+func defaultLeagueFromEnv() int {
+	id, _ := strconv.Atoi(strings.TrimSpace(os.Getenv("LEAGUE_ID")))
+	return id
+}
+
+// examplesDir is where example outputs are saved: next to the season
+// directories rather than inside one, since the tools' schemas don't change
+// with the season.
+func examplesDir(cfg ServerConfig) string {
+	root := cfg.DerivedSeasonsRoot
+	if root == "" {
+		root = cfg.DerivedRoot
+	}
+	if root == "" {
+		return ""
+	}
+	return filepath.Join(root, "examples")
+}
+
+// exampleValues resolves the placeholders it can: the league is
+// cfg.DefaultLeagueID, the entry and opponent its first two entries and the
+// element the top scorer in bootstrap.
+func exampleValues(cfg ServerConfig) map[string]any {
+	values := make(map[string]any)
+	if cfg.DefaultLeagueID == 0 {
+		return values
+	}
+	values[exampleLeague] = cfg.DefaultLeagueID
+	lcfg := cfg.forLeague(cfg.DefaultLeagueID)
+	if _, entryIDs, err := loadLeagueDetails(store.NewJSONStore(lcfg.RawRoot), cfg.DefaultLeagueID); err == nil {
+		if len(entryIDs) > 0 {
+			values[exampleEntry] = entryIDs[0]
+		}
+		if len(entryIDs) > 1 {
+			values[exampleOpponent] = entryIDs[1]
+		}
+	}
+	if elements, _, _, err := loadBootstrapData(lcfg.RawRoot); err == nil && len(elements) > 0 {
+		top := elements[0]
+		for _, e := range elements[1:] {
+			if e.TotalPoints > top.TotalPoints {
+				top = e
+			}
+		}
+		values[exampleElement] = top.ID
+	}
+	return values
+}
+
+// fillPlaceholders returns a copy of v with each placeholder string found in
+// values replaced, and the placeholders left unfilled.
+func fillPlaceholders(v any, values map[string]any, missing map[string]bool) any {
+	switch x := v.(type) {
+	case string:
+		if !strings.HasPrefix(x, "<") || !strings.HasSuffix(x, ">") {
+			return x
+		}
+		if filled, ok := values[x]; ok {
+			return filled
+		}
+		missing[x] = true
+		return x
+	case map[string]any:
+		out := make(map[string]any, len(x))
+		for k, item := range x {
+			out[k] = fillPlaceholders(item, values, missing)
+		}
+		return out
+	case []any:
+		out := make([]any, len(x))
+		for i, item := range x {
+			out[i] = fillPlaceholders(item, values, missing)
+		}
+		return out
+	}
+	return v
+}
+
+// templateExamples fills the placeholders in each example's args and
+// attaches the output saved for the same args, if there is one. An example
+// left with a placeholder is marked untested.
+func templateExamples(cfg ServerConfig, tool string, examples []ToolExample, values map[string]any) []ToolExample {
+	var saved savedExamples
+	if dir := examplesDir(cfg); dir != "" {
+		if raw, err := store.ReadDerived(filepath.Join(dir, tool+".json")); err == nil {
+			_ = json.Unmarshal(raw, &saved)
+		}
+	}
+	out := make([]ToolExample, 0, len(examples))
+	for _, ex := range examples {
+		missing := make(map[string]bool)
+		ex.Args = fillPlaceholders(ex.Args, values, missing).(map[string]any)
+		if len(missing) > 0 {
+			names := make([]string, 0, len(missing))
+			for name := range missing {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			ex.Note = "no local value for " + strings.Join(names, ", ")
+			out = append(out, ex)
+			continue
+		}
+		key, _ := json.Marshal(ex.Args)
+		for _, s := range saved.Examples {
+			if b, _ := json.Marshal(s.Args); string(b) == string(key) {
+				ex.Output, ex.Tested, ex.Note = s.Output, s.Tested, s.Note
+				break
+			}
+		}
+		out = append(out, ex)
+	}
+	return out
+}
+
+// describeExamples is the suffix added to a tool's MCP description: each
+// example's args and, once one has run, the top-level fields it returned.
+func describeExamples(examples []ToolExample) string {
+	parts := make([]string, 0, len(examples))
+	for _, ex := range examples {
+		args, err := json.Marshal(ex.Args)
+		if err != nil {
+			continue
+		}
+		part := string(args)
+		var fields map[string]json.RawMessage
+		if ex.Tested && json.Unmarshal(ex.Output, &fields) == nil && len(fields) > 0 {
+			keys := make([]string, 0, len(fields))
+			for k := range fields {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			part += " returns {" + strings.Join(keys, ", ") + "}"
+		}
+		parts = append(parts, part)
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return ". Example: " + strings.Join(parts, "; ")
+}
+
+// abridgeOutput shortens a tool result for saving as an example: arrays are
+// cut to their first exampleMaxItems items, long strings are clipped and
+// data_freshness, which describes the run rather than the tool, is dropped.
+// A result that isn't JSON, such as a markdown table, is kept as a clipped
+// string.
+func abridgeOutput(text string) json.RawMessage {
+	var v any
+	if err := json.Unmarshal([]byte(text), &v); err != nil {
+		b, _ := json.Marshal(clip(text, exampleMaxTextLen))
+		return b
+	}
+	if m, ok := v.(map[string]any); ok {
+		delete(m, "data_freshness")
+	}
+	b, _ := json.Marshal(abridgeValue(v))
+	return b
+}
+
+func abridgeValue(v any) any {
+	switch x := v.(type) {
+	case string:
+		return clip(x, exampleMaxStrLen)
+	case map[string]any:
+		for k, item := range x {
+			x[k] = abridgeValue(item)
+		}
+		return x
+	case []any:
+		if len(x) > exampleMaxItems {
+			x = x[:exampleMaxItems]
+		}
+		for i, item := range x {
+			x[i] = abridgeValue(item)
+		}
+		return x
+	}
+	return v
+}
+
+func clip(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n] + "…"
+}
+
+// emitExamples runs every tool's examples against the local data through an
+// in-memory MCP session and saves the abridged outputs under
+// examplesDir(cfg), one file per tool. An example that can't run (a
+// placeholder without a local value, or a tool error such as DATA_MISSING)
+// is saved untested with the reason; only failing to write is an error.
+func emitExamples(ctx context.Context, cfg ServerConfig) error {
+	dir := examplesDir(cfg)
+	if dir == "" {
+		return fmt.Errorf("no derived root to save examples under")
+	}
+	server, _, tools := buildServer(cfg)
+	serverT, clientT := mcp.NewInMemoryTransports()
+	ss, err := server.Connect(ctx, serverT, nil)
+	if err != nil {
+		return err
+	}
+	defer ss.Close()
+	client := mcp.NewClient(&mcp.Implementation{Name: "emit-examples", Version: "0"}, nil)
+	cs, err := client.Connect(ctx, clientT, nil)
+	if err != nil {
+		return err
+	}
+	defer cs.Close()
+
+	tested, total := 0, 0
+	for _, tool := range tools {
+		out := savedExamples{
+			Tool:           tool.Name,
+			GeneratedAtUTC: clock.UTC(cfg.now()),
+			Examples:       make([]ToolExample, 0, len(tool.Examples)),
+		}
+		for _, ex := range tool.Examples {
+			total++
+			ex.Output, ex.Tested = nil, false
+			// Filled placeholders are numbers, so any left are unfilled.
+			missing := make(map[string]bool)
+			fillPlaceholders(ex.Args, nil, missing)
+			if len(missing) > 0 {
+				out.Examples = append(out.Examples, ex)
+				continue
+			}
+			ex.Note = ""
+			res, err := cs.CallTool(ctx, &mcp.CallToolParams{Name: tool.Name, Arguments: ex.Args})
+			switch {
+			case err != nil:
+				ex.Note = err.Error()
+			case res.IsError:
+				ex.Note = clip(callText(res), exampleMaxStrLen)
+			default:
+				ex.Output = abridgeOutput(callText(res))
+				ex.Tested = true
+				tested++
+			}
+			out.Examples = append(out.Examples, ex)
+		}
+		if err := store.WriteDerivedJSON(filepath.Join(dir, tool.Name+".json"), out); err != nil {
+			return fmt.Errorf("save %s examples: %w", tool.Name, err)
+		}
+	}
+	log.Printf("emit-examples: %d of %d examples ran against local data; saved to %s", tested, total, dir)
+	return nil
+}
+
+func callText(res *mcp.CallToolResult) string {
+	var b strings.Builder
+	for _, c := range res.Content {
+		if text, ok := c.(*mcp.TextContent); ok {
+			b.WriteString(text.Text)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+)
+
+func TestToolExamples_Templated(t *testing.T) {
+	dir, cfg := resourceCfg(t)
+	writeClaimFixture(t, dir)
+	cfg.DefaultLeagueID = 100
+	_, _, tools := buildServer(cfg)
+
+	for _, tool := range tools {
+		if len(tool.Examples) == 0 {
+			t.Errorf("%s has no example", tool.Name)
+		}
+	}
+	byName := make(map[string]toolInfo, len(tools))
+	for _, tool := range tools {
+		byName[tool.Name] = tool
+	}
+	args := byName["manager_lookup"].Examples[0].Args
+	if args["league_id"] != 100 || args["entry_id"] != 200 {
+		t.Errorf("manager_lookup args = %v, want the default league and its first entry", args)
+	}
+	claim := byName["claim_simulator"].Examples[0]
+	if claim.Tested || !strings.Contains(claim.Note, "<free_agent_element_id>") {
+		t.Errorf("claim_simulator example = %+v, want untested with the unfilled placeholder named", claim)
+	}
+
+	// Without a default league the placeholder stays for the client to fill.
+	_, _, tools = buildServer(ServerConfig{RawRoot: dir, DerivedRoot: dir})
+	for _, tool := range tools {
+		if tool.Name == "standings" && tool.Examples[0].Args["league_id"] != exampleLeague {
+			t.Errorf("standings args = %v, want the league placeholder", tool.Examples[0].Args)
+		}
+	}
+}
+
+func TestEmitExamples(t *testing.T) {
+	dir, cfg := resourceCfg(t)
+	writeClaimFixture(t, dir)
+	cfg.DefaultLeagueID = 100
+
+	if err := emitExamples(context.Background(), cfg); err != nil {
+		t.Fatalf("emitExamples: %v", err)
+	}
+	read := func(tool string) savedExamples {
+		t.Helper()
+		raw, err := store.ReadDerived(filepath.Join(dir, "examples", tool+".json"))
+		if err != nil {
+			t.Fatalf("read %s examples: %v", tool, err)
+		}
+		var saved savedExamples
+		if err := json.Unmarshal(raw, &saved); err != nil {
+			t.Fatal(err)
+		}
+		return saved
+	}
+	entries := read("league_entries").Examples[0]
+	if !entries.Tested || !strings.Contains(string(entries.Output), "Alpha FC") {
+		t.Errorf("league_entries example = %+v, want a tested output", entries)
+	}
+	if strings.Contains(string(entries.Output), "data_freshness") {
+		t.Errorf("league_entries output kept data_freshness: %s", entries.Output)
+	}
+	// No GW3 live.json on disk: saved untested rather than failing.
+	if results := read("epl_fixtures").Examples[0]; results.Tested || !strings.Contains(results.Note, string(codeDataMissing)) {
+		t.Errorf("epl_fixtures example = %+v, want untested with DATA_MISSING", results)
+	}
+	// Whatever the data, every example's args must pass the tool's schema.
+	_, _, tools := buildServer(cfg)
+	for _, tool := range tools {
+		for _, ex := range read(tool.Name).Examples {
+			if strings.Contains(ex.Note, "invalid params") {
+				t.Errorf("%s example %v: %s", tool.Name, ex.Args, ex.Note)
+			}
+		}
+	}
+
+	// The next start serves the saved output and names its fields.
+	for _, tool := range tools {
+		if tool.Name != "league_entries" {
+			continue
+		}
+		if !tool.Examples[0].Tested || len(tool.Examples[0].Output) == 0 {
+			t.Errorf("league_entries example not loaded: %+v", tool.Examples[0])
+		}
+		if got := describeExamples(tool.Examples); !strings.Contains(got, `{"league_id":100} returns {`) {
+			t.Errorf("description suffix = %q", got)
+		}
+	}
+}
+
+func TestAbridgeOutput(t *testing.T) {
+	got := string(abridgeOutput(`{"rows":[1,2,3,4,5],"data_freshness":{"stale":false},"name":"` + strings.Repeat("x", 300) + `"}`))
+	var v struct {
+		Rows []int  `json:"rows"`
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal([]byte(got), &v); err != nil {
+		t.Fatal(err)
+	}
+	if len(v.Rows) != exampleMaxItems || strings.Contains(got, "data_freshness") || len(v.Name) > exampleMaxStrLen+len("…") {
+		t.Errorf("abridged = %s", got)
+	}
+	if got := string(abridgeOutput("| a | b |")); got != `"| a | b |"` {
+		t.Errorf("table abridged = %s, want it kept as a string", got)
+	}
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/render"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/summary"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// LeagueGWFormatArgs are LeagueGWArgs for the tools that can also render
+// their summary as a table.
+type LeagueGWFormatArgs struct {
+	LeagueID int    `json:"league_id" jsonschema:"Draft league id (required)"`
+	GW       int    `json:"gw" jsonschema:"Gameweek (0 = current)"`
+	Format   string `json:"format,omitempty" jsonschema:"json|markdown|csv (default json)"`
+}
+
+// exportTable builds the table a format other than json renders from a
+// tool's JSON output.
+type exportTable func(raw []byte) (render.Table, error)
+
+// parseToolFormat validates a tool's format argument.
+func parseToolFormat(format string) (string, error) {
+	f, err := render.ParseFormat(format)
+	if err != nil {
+		return "", invalidArgumentf("%v", err)
+	}
+	return f, nil
+}
+
+// toolFormatted returns raw unchanged for json and otherwise the table built
+// by table, rendered as markdown or CSV text.
+func toolFormatted(format string, table exportTable, raw []byte, err error) (*mcp.CallToolResult, any, error) {
+	if err != nil {
+		return toolError(err), nil, nil
+	}
+	f, err := parseToolFormat(format)
+	if err != nil {
+		return toolError(err), nil, nil
+	}
+	if f == render.FormatJSON {
+		return toolJSONBytes(raw), nil, nil
+	}
+	t, err := table(raw)
+	if err != nil {
+		return toolError(fmt.Errorf("render %s: %w", f, err)), nil, nil
+	}
+	out, err := render.Render(f, t)
+	if err != nil {
+		return toolError(fmt.Errorf("render %s: %w", f, err)), nil, nil
+	}
+	return toolJSONBytes(out), nil, nil
+}
+
+func positionColumn(field string) render.Column {
+	return render.Column{Field: field, Header: "Pos", Format: func(v any) string { return positionLabel(v.(int)) }}
+}
+
+func standingsTable(raw []byte) (render.Table, error) {
+	var s summary.StandingsSummary
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return render.Table{}, err
+	}
+	return render.Table{
+		Title: fmt.Sprintf("Standings after GW %d", s.Gameweek),
+		Columns: []render.Column{
+			{Field: "rank", Header: "#"},
+			{Field: "entry_name", Header: "Team"},
+			{Field: "played", Header: "P"},
+			{Field: "wins", Header: "W"},
+			{Field: "draws", Header: "D"},
+			{Field: "losses", Header: "L"},
+			{Field: "points_for", Header: "PF"},
+			{Field: "points_against", Header: "PA"},
+			{Field: "match_points", Header: "Pts"},
+			{Field: "form", Header: "Form"},
+		},
+		Rows: s.Rows,
+	}, nil
+}
+
+func leagueSummaryTable(raw []byte) (render.Table, error) {
+	var s summary.LeagueWeekSummary
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return render.Table{}, err
+	}
+	return render.Table{
+		Title: fmt.Sprintf("GW %d results", s.Gameweek),
+		Columns: []render.Column{
+			{Field: "entry_name", Header: "Team"},
+			{Field: "score_for", Header: "Score"},
+			{Field: "score_against", Header: "Opp score"},
+			{Field: "opponent_name", Header: "Opponent"},
+			{Field: "result", Header: "Result"},
+			{Field: "record.wins", Header: "W"},
+			{Field: "record.draws", Header: "D"},
+			{Field: "record.losses", Header: "L"},
+			{Field: "points.bench", Header: "Bench pts"},
+		},
+		Rows: s.Entries,
+	}, nil
+}
+
+func transactionsTable(raw []byte) (render.Table, error) {
+	var s summary.TransactionsSummary
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return render.Table{}, err
+	}
+	return render.Table{
+		Title: fmt.Sprintf("GW %d transactions", s.Gameweek),
+		Columns: []render.Column{
+			{Field: "entry_name", Header: "Team"},
+			{Field: "waiver_in", Header: "Waiver in"},
+			{Field: "waiver_out", Header: "Waiver out"},
+			{Field: "free_in", Header: "Free in"},
+			{Field: "free_out", Header: "Free out"},
+			{Field: "trade_in", Header: "Trade in"},
+			{Field: "trade_out", Header: "Trade out"},
+			{Field: "net", Header: "Net"},
+		},
+		Rows: s.Entries,
+	}, nil
+}
+
+func playerFormTable(raw []byte) (render.Table, error) {
+	var s PlayerFormOutput
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return render.Table{}, err
+	}
+	return render.Table{
+		Title: fmt.Sprintf("Player form over %d GWs to GW %d", s.Horizon, s.AsOfGW),
+		Columns: []render.Column{
+			{Field: "name", Header: "Player"},
+			{Field: "team", Header: "Team"},
+			positionColumn("position_type"),
+			{Field: "points", Header: "Pts"},
+			{Field: "points_per_gw", Header: "Pts/GW", Decimals: 1},
+			{Field: "minutes_per_gw", Header: "Mins/GW", Decimals: 1},
+			{Field: "ownership_pct", Header: "Owned %", Decimals: 1},
+			{Field: "risk_score", Header: "Risk", Decimals: 2},
+			{Field: "minutes_pattern", Header: "Minutes"},
+		},
+		Rows: s.Players,
+	}, nil
+}
+
+func waiverRecommendationsTable(raw []byte) (render.Table, error) {
+	var s WaiverRecommendationsReport
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return render.Table{}, err
+	}
+	columns := []render.Column{
+		{Field: "name", Header: "Player"},
+		{Field: "team", Header: "Team"},
+		positionColumn("position_type"),
+		{Field: "fixture.opponent_short", Header: "Opp"},
+		{Field: "fixture.venue", Header: "Venue"},
+		{Field: "fixture_count", Header: "Fixtures"},
+		{Field: "score.weighted_score", Header: "Score", Decimals: 2},
+		{Field: "suggested_drop.name", Header: "Drop"},
+	}
+	if s.FAAB != nil {
+		columns = append(columns, render.Column{Field: "suggested_bid", Header: "Bid"})
+	}
+	return render.Table{
+		Title:   fmt.Sprintf("Waiver adds for GW %d", s.TargetGW),
+		Columns: columns,
+		Rows:    s.Adds,
+	}, nil
+}
+
+// teamDefenseProfileTable has a row per team, side (conceded or scored) and
+// position, with the home and away splits side by side.
+func teamDefenseProfileTable(raw []byte) (render.Table, error) {
+	var s TeamDefenseProfileOutput
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return render.Table{}, err
+	}
+	type row struct {
+		Team string `json:"team"`
+		Side string `json:"side"`
+		TeamPositionSplit
+	}
+	rows := make([]row, 0, len(s.Teams)*8)
+	for _, t := range s.Teams {
+		for _, p := range t.Conceded {
+			rows = append(rows, row{t.Team, "conceded", p})
+		}
+		for _, p := range t.Scored {
+			rows = append(rows, row{t.Team, "scored", p})
+		}
+	}
+	return render.Table{
+		Title: fmt.Sprintf("Team points by position to GW %d (recent from GW %d)", s.AsOfGW, s.RecentFromGW),
+		Columns: []render.Column{
+			{Field: "team", Header: "Team"},
+			{Field: "side", Header: "Side"},
+			{Field: "position", Header: "Pos"},
+			{Field: "home.season", Header: "Home", Decimals: 2},
+			{Field: "home.season_rank", Header: "Home #"},
+			{Field: "home.recent", Header: "Home recent", Decimals: 2},
+			{Field: "home.trend", Header: "Home trend", Decimals: 2},
+			{Field: "away.season", Header: "Away", Decimals: 2},
+			{Field: "away.season_rank", Header: "Away #"},
+			{Field: "away.recent", Header: "Away recent", Decimals: 2},
+			{Field: "away.trend", Header: "Away trend", Decimals: 2},
+		},
+		Rows: rows,
+	}, nil
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+func resultText(t *testing.T, res *mcp.CallToolResult) string {
+	t.Helper()
+	if len(res.Content) != 1 {
+		t.Fatalf("content = %+v", res.Content)
+	}
+	return res.Content[0].(*mcp.TextContent).Text
+}
+
+func TestToolFormatted_JSONUnchanged(t *testing.T) {
+	raw := []byte("{\n  \"league_id\": 100,\n  \"rows\": []\n}")
+	for _, format := range []string{"", "json"} {
+		res, _, _ := toolFormatted(format, standingsTable, raw, nil)
+		if res.IsError || resultText(t, res) != string(raw) {
+			t.Errorf("format %q changed the JSON: %s", format, resultText(t, res))
+		}
+	}
+	res, _, _ := toolFormatted("xml", standingsTable, raw, nil)
+	if !res.IsError || !strings.Contains(resultText(t, res), string(codeInvalidArgument)) {
+		t.Errorf("format xml = %s, want INVALID_ARGUMENT", resultText(t, res))
+	}
+}
+
+func TestExportTables_Golden(t *testing.T) {
+	tests := []struct {
+		name  string
+		table exportTable
+		raw   string
+	}{
+		{"standings", standingsTable, `{"gameweek": 5, "rows": [
+			{"rank": 1, "entry_name": "Alpha FC", "played": 5, "wins": 4, "draws": 0, "losses": 1, "points_for": 260, "points_against": 201, "match_points": 12, "form": "WWLWW"},
+			{"rank": 2, "entry_name": "Beta | FC", "played": 5, "wins": 2, "draws": 1, "losses": 2, "points_for": 240, "points_against": 250, "match_points": 7, "form": "LDWWL"}]}`},
+		{"league_summary", leagueSummaryTable, `{"gameweek": 5, "entries": [
+			{"entry_name": "Alpha FC", "opponent_name": "Beta FC", "score_for": 61, "score_against": 44, "result": "W", "record": {"wins": 4, "draws": 0, "losses": 1}, "points": {"starters": 61, "bench": 9}}]}`},
+		{"transactions", transactionsTable, `{"gameweek": 5, "entries": [
+			{"entry_name": "Alpha FC", "waiver_in": [10, 11], "waiver_out": [1, 2], "free_in": [], "free_out": [], "trade_in": [], "trade_out": [], "net": 0}]}`},
+		{"player_form", playerFormTable, `{"as_of_gw": 5, "horizon": 3, "players": [
+			{"name": "Salah", "team": "LIV", "position_type": 3, "points": 30, "points_per_gw": 10, "minutes_per_gw": 88.333, "ownership_pct": 100, "risk_score": 0.125, "minutes_pattern": "nailed"}]}`},
+		{"waiver_recommendations", waiverRecommendationsTable, `{"target_gw": 6, "top_adds": [
+			{"name": "Mbeumo", "team": "BRE", "position_type": 3, "fixture": {"opponent_short": "SHU", "venue": "H"}, "fixture_count": 1, "score": {"weighted_score": 0.8123}, "suggested_drop": {"name": "Wood"}},
+			{"name": "Wissa", "team": "BRE", "position_type": 4, "fixture": {"opponent_short": "SHU", "venue": "H"}, "fixture_count": 1, "score": {"weighted_score": 0.5}}]}`},
+		{"team_defense_profile", teamDefenseProfileTable, `{"as_of_gw": 5, "recent_from_gw": 3, "teams": [
+			{"team": "ARS", "conceded": [{"position": "DEF", "home": {"season": 1.5, "season_rank": 20, "recent": 2, "recent_rank": 18, "trend": 0.5}, "away": {"season": 3.25, "season_rank": 12, "recent": null, "recent_rank": null, "trend": null}}],
+			"scored": [{"position": "MID", "home": {"season": 14.333, "season_rank": 1, "recent": 12, "recent_rank": 2, "trend": -2.333}, "away": {"season": 9, "season_rank": 4, "recent": 9, "recent_rank": 5, "trend": 0}}]}]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, _, _ := toolFormatted("markdown", tt.table, []byte(tt.raw), nil)
+			got := resultText(t, res)
+			if res.IsError {
+				t.Fatalf("render: %s", got)
+			}
+			path := filepath.Join("testdata", tt.name+".md")
+			if *updateGolden {
+				if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("read golden (run with -update to create): %v", err)
+			}
+			if got != string(want) {
+				t.Errorf("mismatch\n--- got ---\n%s\n--- want ---\n%s", got, want)
+			}
+
+			res, _, _ = toolFormatted("csv", tt.table, []byte(tt.raw), nil)
+			if res.IsError || !strings.HasSuffix(resultText(t, res), "\r\n") {
+				t.Errorf("csv = %q", resultText(t, res))
+			}
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/leagueconfig"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/reconcile"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+)
+
+const (
+	// defaultFAABReserve is the share of the season budget held back from
+	// any one bid unless the caller sets faab_reserve.
+	defaultFAABReserve = 0.1
+	// minTierBids is how many winning bids a position needs before its own
+	// distribution is used instead of the league-wide one.
+	minTierBids = 3
+)
+
+// FAABStatus is the requesting entry's bidding position in a FAAB league.
+// Remaining comes from the entry's faab_remaining in league details when
+// present, otherwise it is the budget less the entry's winning bids
+// (RemainingSource says which). MaxBid is Remaining less Reserve.
+type FAABStatus struct {
+	Budget          int       `json:"budget"`
+	Remaining       int       `json:"remaining"`
+	RemainingSource string    `json:"remaining_source"`
+	Reserve         int       `json:"reserve"`
+	MaxBid          int       `json:"max_bid"`
+	WinningBids     []BidTier `json:"winning_bids"`
+}
+
+// BidTier summarizes the league's winning bids for one position, or for
+// every position under "ALL".
+type BidTier struct {
+	Tier   string  `json:"tier"`
+	Count  int     `json:"count"`
+	Min    int     `json:"min"`
+	Median float64 `json:"median"`
+	Max    int     `json:"max"`
+}
+
+// bidDistribution is the league's winning bids, sorted, overall and by the
+// position type of the player claimed.
+type bidDistribution struct {
+	all   []int
+	byPos map[int][]int
+}
+
+// fitWinningBids collects the bids on accepted waiver claims. Claims without
+// a bid (priority waivers, free agents) are skipped.
+func fitWinningBids(transactions []reconcile.Transaction, positionOf map[int]int) bidDistribution {
+	d := bidDistribution{byPos: make(map[int][]int)}
+	for _, tx := range transactions {
+		if tx.Kind != "w" || tx.Result != "a" || tx.Bid == nil || *tx.Bid < 0 {
+			continue
+		}
+		d.all = append(d.all, *tx.Bid)
+		if pos := positionOf[tx.ElementIn]; pos != 0 {
+			d.byPos[pos] = append(d.byPos[pos], *tx.Bid)
+		}
+	}
+	sort.Ints(d.all)
+	for pos := range d.byPos {
+		sort.Ints(d.byPos[pos])
+	}
+	return d
+}
+
+// forPosition returns the bids to price a player at pos against: the
+// position's own when it has minTierBids of them, else the league's.
+func (d bidDistribution) forPosition(pos int) []int {
+	if bids := d.byPos[pos]; len(bids) >= minTierBids {
+		return bids
+	}
+	return d.all
+}
+
+// tiers summarizes the distribution overall and per position with bids.
+func (d bidDistribution) tiers() []BidTier {
+	summarize := func(tier string, bids []int) BidTier {
+		return BidTier{Tier: tier, Count: len(bids), Min: bids[0], Median: percentile(bids, 0.5), Max: bids[len(bids)-1]}
+	}
+	if len(d.all) == 0 {
+		return []BidTier{}
+	}
+	out := []BidTier{summarize("ALL", d.all)}
+	for pos := 1; pos <= 4; pos++ {
+		if bids := d.byPos[pos]; len(bids) > 0 {
+			out = append(out, summarize(positionLabel(pos), bids))
+		}
+	}
+	return out
+}
+
+// suggestBid maps a score percentile onto bids (sorted) and caps it at
+// maxBid. ok is false when there are no bids to map against.
+func suggestBid(bids []int, pct float64, maxBid int) (int, bool) {
+	if len(bids) == 0 {
+		return 0, false
+	}
+	bid := int(math.Round(percentile(bids, pct)))
+	return max(min(bid, maxBid), 0), true
+}
+
+// scorePercentiles is each candidate's weighted-score percentile rank in the
+// pool: the share of the other candidates it outscores.
+func scorePercentiles(candidates []scoredPlayer) map[int]float64 {
+	out := make(map[int]float64, len(candidates))
+	if len(candidates) == 1 {
+		out[candidates[0].info.ID] = 1
+		return out
+	}
+	scores := make([]float64, len(candidates))
+	for i, c := range candidates {
+		scores[i] = c.score.WeightedScore
+	}
+	sort.Float64s(scores)
+	for _, c := range candidates {
+		below := sort.SearchFloat64s(scores, c.score.WeightedScore)
+		out[c.info.ID] = float64(below) / float64(len(scores)-1)
+	}
+	return out
+}
+
+// poolPercentile places score within pool the way scorePercentiles places
+// the pool's own players, for a player scored outside it.
+func poolPercentile(pool []scoredPlayer, score float64) float64 {
+	if len(pool) == 0 {
+		return 1
+	}
+	below := 0
+	for _, c := range pool {
+		if c.score.WeightedScore < score {
+			below++
+		}
+	}
+	return min(float64(below)/float64(max(len(pool)-1, 1)), 1)
+}
+
+// loadFAABStatus reads entryID's budget and the league's winning bids. It
+// returns a nil status for a priority-waiver league.
+func loadFAABStatus(cfg ServerConfig, leagueID int, entryID int, reserveArg *int, positionOf map[int]int) (*FAABStatus, bidDistribution, error) {
+	st := store.NewJSONStore(cfg.RawRoot)
+	settings, err := leagueconfig.Load(st, leagueID)
+	if err != nil {
+		return nil, bidDistribution{}, err
+	}
+	if !settings.Waivers.FAAB() {
+		return nil, bidDistribution{}, nil
+	}
+	transactions, err := loadTransactionsRaw(st, leagueID)
+	if err != nil {
+		return nil, bidDistribution{}, err
+	}
+	dist := fitWinningBids(transactions, positionOf)
+
+	status := &FAABStatus{Budget: settings.Waivers.Budget, WinningBids: dist.tiers()}
+	remaining, ok, err := loadFAABRemaining(st, leagueID, entryID)
+	if err != nil {
+		return nil, bidDistribution{}, err
+	}
+	if ok {
+		status.Remaining, status.RemainingSource = remaining, "league_details"
+	} else {
+		spent := 0
+		for _, tx := range transactions {
+			if tx.Entry == entryID && tx.Kind == "w" && tx.Result == "a" && tx.Bid != nil {
+				spent += *tx.Bid
+			}
+		}
+		status.Remaining, status.RemainingSource = max(status.Budget-spent, 0), "transactions"
+	}
+	status.Reserve = int(math.Ceil(float64(status.Budget) * defaultFAABReserve))
+	if reserveArg != nil {
+		status.Reserve = max(*reserveArg, 0)
+	}
+	status.MaxBid = max(status.Remaining-status.Reserve, 0)
+	return status, dist, nil
+}
+
+// loadFAABRemaining reads entryID's faab_remaining from the league entries
+// in details.json; ok is false when the payload doesn't carry it.
+func loadFAABRemaining(st *store.JSONStore, leagueID int, entryID int) (int, bool, error) {
+	raw, err := st.ReadRaw(fmt.Sprintf("league/%d/details.json", leagueID))
+	if err != nil {
+		return 0, false, err
+	}
+	var details struct {
+		LeagueEntries []struct {
+			EntryID   int  `json:"entry_id"`
+			Remaining *int `json:"faab_remaining"`
+		} `json:"league_entries"`
+	}
+	if err := json.Unmarshal(raw, &details); err != nil {
+		return 0, false, err
+	}
+	for _, e := range details.LeagueEntries {
+		if e.EntryID == entryID && e.Remaining != nil {
+			return *e.Remaining, true, nil
+		}
+	}
+	return 0, false, nil
+}
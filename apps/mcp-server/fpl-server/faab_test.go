@@ -0,0 +1,149 @@
+package main
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/reconcile"
+)
+
+func bidTx(entry, in int, result string, bid int) reconcile.Transaction {
+	return reconcile.Transaction{Entry: entry, ElementIn: in, Kind: "w", Result: result, Bid: &bid}
+}
+
+func TestFitWinningBids(t *testing.T) {
+	positionOf := map[int]int{1: 3, 2: 3, 4: 3, 5: 4, 13: 2}
+	free := reconcile.Transaction{Entry: 202, ElementIn: 5, Kind: "f", Result: "a"}
+	d := fitWinningBids([]reconcile.Transaction{
+		bidTx(200, 1, "a", 30),
+		bidTx(201, 2, "a", 10),
+		bidTx(202, 4, "a", 20),
+		bidTx(201, 1, "do", 25), // outbid: not a winning bid
+		bidTx(200, 5, "a", 4),
+		bidTx(202, 13, "a", 0),
+		free,
+	}, positionOf)
+
+	if len(d.all) != 5 || d.all[0] != 0 || d.all[4] != 30 {
+		t.Fatalf("all bids = %v", d.all)
+	}
+	// MID has three winning bids of its own; FWD has one, so falls back.
+	if got := d.forPosition(3); len(got) != 3 || got[0] != 10 || got[2] != 30 {
+		t.Errorf("MID bids = %v", got)
+	}
+	if got := d.forPosition(4); len(got) != 5 {
+		t.Errorf("FWD bids = %v, want the league-wide ones", got)
+	}
+
+	tiers := d.tiers()
+	if len(tiers) != 4 || tiers[0].Tier != "ALL" || tiers[0].Median != 10 {
+		t.Fatalf("tiers = %+v", tiers)
+	}
+	if mid := tiers[2]; mid.Tier != "MID" || mid.Count != 3 || mid.Min != 10 || mid.Median != 20 || mid.Max != 30 {
+		t.Errorf("MID tier = %+v", mid)
+	}
+
+	if tiers := fitWinningBids(nil, positionOf).tiers(); len(tiers) != 0 {
+		t.Errorf("tiers without bids = %+v", tiers)
+	}
+}
+
+func TestSuggestBid(t *testing.T) {
+	bids := []int{10, 20, 30}
+	cases := []struct {
+		pct    float64
+		maxBid int
+		want   int
+	}{
+		{1, 100, 30},
+		{0.75, 100, 25},
+		{0, 100, 10},
+		{1, 18, 18}, // capped by what the entry can spend
+		{0.5, 0, 0},
+	}
+	for _, c := range cases {
+		if got, ok := suggestBid(bids, c.pct, c.maxBid); !ok || got != c.want {
+			t.Errorf("suggestBid(%v, %d) = %d, want %d", c.pct, c.maxBid, got, c.want)
+		}
+	}
+	if _, ok := suggestBid(nil, 1, 100); ok {
+		t.Error("suggested a bid with no history")
+	}
+
+	// A priority-waiver add leaves the FAAB fields out rather than at zero.
+	raw, _ := json.Marshal(AddRecommendation{})
+	for _, key := range []string{"suggested_bid", "remaining_budget", "max_bid"} {
+		if strings.Contains(string(raw), key) {
+			t.Errorf("%s in a priority-waiver add: %s", key, raw)
+		}
+	}
+}
+
+func TestScorePercentiles(t *testing.T) {
+	player := func(id int, score float64) scoredPlayer {
+		return scoredPlayer{info: elementInfo{ID: id}, score: ScoreComponents{WeightedScore: score}}
+	}
+	pct := scorePercentiles([]scoredPlayer{player(1, 0.9), player(2, 0.5), player(3, 0.5), player(4, 0.1)})
+	if pct[1] != 1 || pct[2] != 1.0/3 || pct[3] != 1.0/3 || pct[4] != 0 {
+		t.Errorf("percentiles = %v", pct)
+	}
+	if pct := scorePercentiles([]scoredPlayer{player(1, 0.2)}); pct[1] != 1 {
+		t.Errorf("single candidate = %v", pct)
+	}
+}
+
+func TestLoadFAABStatus(t *testing.T) {
+	dir, cfg := resourceCfg(t)
+	writeClaimFixture(t, dir)
+	positionOf := map[int]int{1: 3, 10: 3, 12: 4}
+	writeJSON(t, filepath.Join(dir, "league/100/transactions.json"), map[string]any{"transactions": []any{
+		map[string]any{"id": 1, "entry": 200, "event": 2, "kind": "w", "result": "a", "element_in": 10, "element_out": 2, "bid": 35},
+		map[string]any{"id": 2, "entry": 201, "event": 2, "kind": "w", "result": "do", "element_in": 10, "element_out": 4, "bid": 30},
+		map[string]any{"id": 3, "entry": 202, "event": 3, "kind": "w", "result": "a", "element_in": 12, "element_out": 5, "bid": 8},
+	}})
+
+	// Priority waivers: no status at all.
+	status, _, err := loadFAABStatus(cfg, 100, 200, nil, positionOf)
+	if err != nil || status != nil {
+		t.Fatalf("priority league: status %+v, err %v", status, err)
+	}
+
+	details := func(alphaRemaining any) {
+		entry := map[string]any{"id": 1, "entry_id": 200, "entry_name": "Alpha FC"}
+		if alphaRemaining != nil {
+			entry["faab_remaining"] = alphaRemaining
+		}
+		writeJSON(t, filepath.Join(dir, "league/100/details.json"), map[string]any{
+			"league":         map[string]any{"id": 100, "transaction_mode": "b", "faab_budget": 100},
+			"league_entries": []any{entry},
+			"matches":        []any{},
+		})
+	}
+
+	// Without faab_remaining the budget is reduced by Alpha's winning bids.
+	details(nil)
+	status, dist, err := loadFAABStatus(cfg, 100, 200, nil, positionOf)
+	if err != nil {
+		t.Fatalf("loadFAABStatus: %v", err)
+	}
+	if status.Budget != 100 || status.Remaining != 65 || status.RemainingSource != "transactions" || status.Reserve != 10 || status.MaxBid != 55 {
+		t.Errorf("status = %+v", status)
+	}
+	if len(dist.all) != 2 || len(status.WinningBids) != 3 {
+		t.Errorf("bids = %v, tiers %+v", dist.all, status.WinningBids)
+	}
+
+	// League details win when they carry the figure, and the reserve can be
+	// set; it never leaves a negative max bid.
+	details(20)
+	reserve := 25
+	status, _, err = loadFAABStatus(cfg, 100, 200, &reserve, positionOf)
+	if err != nil {
+		t.Fatalf("loadFAABStatus: %v", err)
+	}
+	if status.Remaining != 20 || status.RemainingSource != "league_details" || status.Reserve != 25 || status.MaxBid != 0 {
+		t.Errorf("status = %+v", status)
+	}
+}
@@ -6,9 +6,13 @@ import (
 	"math"
 	"os"
 	"sort"
+	"strconv"
 	"strings"
 )
 
+// maxFixtureGWCount caps fixture_difficulty's gw_count.
+const maxFixtureGWCount = 8
+
 type GameMeta struct {
 	CurrentEvent         int  `json:"current_event"`
 	CurrentEventFinished bool `json:"current_event_finished"`
@@ -16,12 +20,15 @@ type GameMeta struct {
 }
 
 type FixtureDifficultyArgs struct {
-	LeagueID   int   `json:"league_id" jsonschema:"Draft league id (required)"`
-	AsOfGW     *int  `json:"as_of_gw,omitempty" jsonschema:"As-of gameweek for stats (0 = auto)"`
-	NextGW     *int  `json:"next_gw,omitempty" jsonschema:"Gameweek to rank fixtures for (0 = next_event)"`
-	Horizon    *int  `json:"horizon,omitempty" jsonschema:"Rolling horizon in GWs (default 5)"`
-	Limit      *int  `json:"limit,omitempty" jsonschema:"Limit fixtures per position (0 = all)"`
-	IncludeRaw *bool `json:"include_raw,omitempty" jsonschema:"Include raw blended/season/recent scores"`
+	LeagueID   int     `json:"league_id" jsonschema:"Draft league id (required)"`
+	AsOfGW     *int    `json:"as_of_gw,omitempty" jsonschema:"As-of gameweek for stats (0 = auto)"`
+	NextGW     *int    `json:"next_gw,omitempty" jsonschema:"Gameweek to rank fixtures for (0 = next_event)"`
+	Horizon    *int    `json:"horizon,omitempty" jsonschema:"Rolling horizon in GWs (default 5)"`
+	Limit      *int    `json:"limit,omitempty" jsonschema:"Limit fixtures per position (0 = all)"`
+	IncludeRaw *bool   `json:"include_raw,omitempty" jsonschema:"Include raw blended/season/recent scores"`
+	Team       *string `json:"team,omitempty" jsonschema:"Only this Premier League team (short name, e.g. ARS, or team id)"`
+	ElementID  *int    `json:"element_id,omitempty" jsonschema:"Only this player's team, at their position"`
+	GWCount    *int    `json:"gw_count,omitempty" jsonschema:"Gameweeks to rank from next_gw (default 1, max 8)"`
 }
 
 type FixtureDifficultyOutput struct {
@@ -29,11 +36,52 @@ type FixtureDifficultyOutput struct {
 	AsOfGW   int `json:"as_of_gw"`
 	NextGW   int `json:"next_gw"`
 	Horizon  int `json:"horizon"`
-	Weights  struct {
+	GWCount  int `json:"gw_count"`
+	// TeamShort and ElementID echo the filters applied, if any.
+	TeamShort string `json:"team_short,omitempty"`
+	ElementID int    `json:"element_id,omitempty"`
+	Weights   struct {
 		Season float64 `json:"season"`
 		Recent float64 `json:"recent"`
 	} `json:"weights"`
-	Positions map[string][]FixtureDifficultyItem `json:"positions"`
+	// Positions ranks single fixtures when gw_count is 1; Runs ranks each
+	// team's fixtures across the window otherwise.
+	Positions map[string][]FixtureDifficultyItem `json:"positions,omitempty"`
+	Runs      map[string][]FixtureRun            `json:"runs,omitempty"`
+	// UnknownGWs are GWs in the window with no fixture data at all, so they
+	// are left out rather than counted as blanks.
+	UnknownGWs []int `json:"unknown_gws,omitempty"`
+	// Warning is set when bootstrap lists no fixtures at all.
+	Warning string `json:"warning,omitempty"`
+	// ColdStart is set before 3 GWs have finished, when scores rest on
+	// little or no history; Notes say how they were made.
+	ColdStart bool     `json:"cold_start,omitempty"`
+	Notes     []string `json:"notes,omitempty"`
+}
+
+// FixtureRun is one team's fixtures for a position over the gw_count window.
+// AverageScore is the blended score averaged per fixture: a double adds two
+// rows to GWs and a blank adds a marker row, neither of which skews it.
+type FixtureRun struct {
+	Rank         int            `json:"rank"`
+	TeamID       int            `json:"team_id"`
+	TeamShort    string         `json:"team_short"`
+	Fixtures     int            `json:"fixtures"`
+	AverageScore float64        `json:"average_score"`
+	GWs          []FixtureRunGW `json:"gws"`
+}
+
+// FixtureRunGW is one row of a FixtureRun: a fixture, or a blank marker.
+type FixtureRunGW struct {
+	Event         int      `json:"event"`
+	Blank         bool     `json:"blank,omitempty"`
+	FixtureID     int      `json:"fixture_id,omitempty"`
+	OpponentID    int      `json:"opponent_id,omitempty"`
+	OpponentShort string   `json:"opponent_short,omitempty"`
+	Venue         string   `json:"venue,omitempty"`
+	Score         *float64 `json:"score,omitempty"`
+	SeasonScore   *float64 `json:"season_score,omitempty"`
+	RecentScore   *float64 `json:"recent_score,omitempty"`
 }
 
 type FixtureDifficultyItem struct {
@@ -146,7 +194,7 @@ func blendedFixtureScore(concededSeason map[int]map[string]map[int]avgStat, conc
 
 func buildFixtureDifficulty(cfg ServerConfig, args FixtureDifficultyArgs) (FixtureDifficultyOutput, error) {
 	if args.LeagueID == 0 {
-		return FixtureDifficultyOutput{}, fmt.Errorf("league_id is required")
+		return FixtureDifficultyOutput{}, invalidArgumentf("league_id is required")
 	}
 	h := 0
 	if args.Horizon != nil {
@@ -169,22 +217,97 @@ func buildFixtureDifficulty(cfg ServerConfig, args FixtureDifficultyArgs) (Fixtu
 		return FixtureDifficultyOutput{}, err
 	}
 
+	gwCount := 1
+	if args.GWCount != nil && *args.GWCount != 0 {
+		gwCount = *args.GWCount
+	}
+	if gwCount < 1 || gwCount > maxFixtureGWCount {
+		return FixtureDifficultyOutput{}, invalidArgumentf("gw_count must be between 1 and %d, got %d", maxFixtureGWCount, gwCount)
+	}
+
 	elements, teamShort, fixturesByGW, err := loadBootstrapData(cfg.RawRoot)
 	if err != nil {
 		return FixtureDifficultyOutput{}, err
 	}
+	teamID, onlyPos, err := resolveFixtureFilter(args, elements, teamShort)
+	if err != nil {
+		return FixtureDifficultyOutput{}, err
+	}
 
 	seasonWeight, recentWeight := horizonWeights(h)
-	concededSeason := computePointsConcededByPosition(cfg.RawRoot, elements, asOfGW, asOfGW)
-	concededRecent := computePointsConcededByPosition(cfg.RawRoot, elements, asOfGW, h)
+	concededSeason, concededRecent, strengthFallback := coldStartConceded(cfg.RawRoot,
+		computePointsConcededByPosition(cfg.RawRoot, elements, asOfGW, asOfGW),
+		computePointsConcededByPosition(cfg.RawRoot, elements, asOfGW, h))
+
+	limit := 0
+	if args.Limit != nil {
+		limit = *args.Limit
+	}
+	includeRaw := false
+	if args.IncludeRaw != nil {
+		includeRaw = *args.IncludeRaw
+	}
+
+	out := FixtureDifficultyOutput{
+		LeagueID:  args.LeagueID,
+		AsOfGW:    asOfGW,
+		NextGW:    nextGW,
+		Horizon:   h,
+		GWCount:   gwCount,
+		TeamShort: teamShort[teamID],
+		Warning:   bootstrapFixturesWarning(fixturesByGW),
+	}
+	if args.ElementID != nil {
+		out.ElementID = *args.ElementID
+	}
+	out.Weights.Season = seasonWeight
+	out.Weights.Recent = recentWeight
+	if coldStart(asOfGW) {
+		out.ColdStart = true
+		out.Notes = append(out.Notes, fmt.Sprintf("Cold start: %d GWs finished, so points conceded rest on very few matches.", max(asOfGW, 0)))
+	}
+	if strengthFallback {
+		out.Notes = append(out.Notes, fmt.Sprintf("No points-conceded history yet: scores come from bootstrap team strength ratings (GK/DEF against the opponent's attack, MID/FWD against its defence), %.0f being an average opponent.", strengthBaseline))
+	}
+
+	if gwCount > 1 {
+		indexByGW := make(map[int]map[int][]FixtureContext, gwCount)
+		gws := make([]int, 0, gwCount)
+		for gw := nextGW; gw < nextGW+gwCount; gw++ {
+			fx := scheduleFixtures(cfg.RawRoot, fixturesByGW, gw)
+			if len(fx) == 0 {
+				out.UnknownGWs = append(out.UnknownGWs, gw)
+				continue
+			}
+			gws = append(gws, gw)
+			indexByGW[gw] = buildFixtureIndex(fx, teamShort)
+		}
+		out.Runs = map[string][]FixtureRun{}
+		for pos := 1; pos <= 4; pos++ {
+			if onlyPos != 0 && pos != onlyPos {
+				continue
+			}
+			mult := func(opponentID int, venue string) (float64, float64, float64) {
+				return blendedFixtureScore(concededSeason, concededRecent, opponentID, venue, pos, seasonWeight, recentWeight)
+			}
+			out.Runs[positionLabel(pos)] = rankFixtureRuns(teamShort, teamID, gws, indexByGW, mult, limit, includeRaw)
+		}
+		return out, nil
+	}
 
 	fixtureList := fixturesByGW[nextGW]
 	contexts := buildFixtureContexts(fixtureList, teamShort)
 
 	positions := map[string][]FixtureDifficultyItem{}
 	for pos := 1; pos <= 4; pos++ {
+		if onlyPos != 0 && pos != onlyPos {
+			continue
+		}
 		rows := make([]fixtureRankItem, 0, len(contexts))
 		for _, ctx := range contexts {
+			if teamID != 0 && ctx.TeamID != teamID {
+				continue
+			}
 			seasonScore, recentScore, blended := blendedFixtureScore(concededSeason, concededRecent, ctx.OpponentID, ctx.Venue, pos, seasonWeight, recentWeight)
 			rows = append(rows, fixtureRankItem{
 				FixtureID:     ctx.FixtureID,
@@ -210,16 +333,13 @@ func buildFixtureDifficulty(cfg ServerConfig, args FixtureDifficultyArgs) (Fixtu
 			return rows[i].OpponentShort < rows[j].OpponentShort
 		})
 
-		limit := 0
-		if args.Limit != nil {
-			limit = *args.Limit
-		}
-		if limit <= 0 || limit > len(rows) {
-			limit = len(rows)
+		n := limit
+		if n <= 0 || n > len(rows) {
+			n = len(rows)
 		}
 
-		out := make([]FixtureDifficultyItem, 0, limit)
-		for i := 0; i < limit; i++ {
+		items := make([]FixtureDifficultyItem, 0, n)
+		for i := 0; i < n; i++ {
 			r := rows[i]
 			item := FixtureDifficultyItem{
 				Rank:          i + 1,
@@ -231,10 +351,6 @@ func buildFixtureDifficulty(cfg ServerConfig, args FixtureDifficultyArgs) (Fixtu
 				OpponentShort: r.OpponentShort,
 				Venue:         r.Venue,
 			}
-			includeRaw := false
-			if args.IncludeRaw != nil {
-				includeRaw = *args.IncludeRaw
-			}
 			if includeRaw {
 				score := r.Score
 				season := r.SeasonScore
@@ -243,23 +359,121 @@ func buildFixtureDifficulty(cfg ServerConfig, args FixtureDifficultyArgs) (Fixtu
 				item.SeasonScore = &season
 				item.RecentScore = &recent
 			}
-			out = append(out, item)
+			items = append(items, item)
 		}
 
-		positions[positionLabel(pos)] = out
+		positions[positionLabel(pos)] = items
 	}
+	out.Positions = positions
 
-	out := FixtureDifficultyOutput{
-		LeagueID:  args.LeagueID,
-		AsOfGW:    asOfGW,
-		NextGW:    nextGW,
-		Horizon:   h,
-		Positions: positions,
+	return out, nil
+}
+
+// resolveFixtureFilter turns the team and element_id arguments into a team
+// id and position type to narrow to; 0 means no filter. A player's team
+// must agree with team when both are given.
+func resolveFixtureFilter(args FixtureDifficultyArgs, elements []elementInfo, teamShort map[int]string) (int, int, error) {
+	teamID := 0
+	if args.Team != nil && strings.TrimSpace(*args.Team) != "" {
+		id, err := resolveTeamID(*args.Team, teamShort)
+		if err != nil {
+			return 0, 0, err
+		}
+		teamID = id
 	}
-	out.Weights.Season = seasonWeight
-	out.Weights.Recent = recentWeight
+	if args.ElementID == nil || *args.ElementID == 0 {
+		return teamID, 0, nil
+	}
+	player, err := resolvePlayer(elements, args.ElementID, nil)
+	if err != nil {
+		return 0, 0, err
+	}
+	if teamID != 0 && player.TeamID != teamID {
+		return 0, 0, invalidArgumentf("element %d plays for %s, not %s", player.ID, teamShort[player.TeamID], teamShort[teamID])
+	}
+	return player.TeamID, player.PositionType, nil
+}
 
-	return out, nil
+// resolveTeamID turns a team short name (any case) or id into a team id.
+func resolveTeamID(name string, teamShort map[int]string) (int, error) {
+	name = strings.TrimSpace(name)
+	if id, err := strconv.Atoi(name); err == nil {
+		if _, ok := teamShort[id]; ok {
+			return id, nil
+		}
+	} else {
+		for id, short := range teamShort {
+			if strings.EqualFold(short, name) {
+				return id, nil
+			}
+		}
+	}
+	return 0, invalidArgumentf("unknown team %q: want a short name such as ARS or a team id", name)
+}
+
+// rankFixtureRuns scores every team's (or only teamID's) fixtures across
+// gws for one position and ranks the runs by average score, easiest first.
+// A team with only blanks in the window has no average and ranks last.
+func rankFixtureRuns(teamShort map[int]string, teamID int, gws []int, indexByGW map[int]map[int][]FixtureContext, score func(opponentID int, venue string) (float64, float64, float64), limit int, includeRaw bool) []FixtureRun {
+	teamIDs := make([]int, 0, len(teamShort))
+	for id := range teamShort {
+		if teamID == 0 || id == teamID {
+			teamIDs = append(teamIDs, id)
+		}
+	}
+	sort.Ints(teamIDs)
+
+	runs := make([]FixtureRun, 0, len(teamIDs))
+	for _, id := range teamIDs {
+		run := FixtureRun{TeamID: id, TeamShort: teamShort[id], GWs: []FixtureRunGW{}}
+		total := 0.0
+		for _, gw := range gws {
+			contexts := indexByGW[gw][id]
+			if len(contexts) == 0 {
+				run.GWs = append(run.GWs, FixtureRunGW{Event: gw, Blank: true})
+				continue
+			}
+			for _, ctx := range contexts {
+				seasonScore, recentScore, blended := score(ctx.OpponentID, ctx.Venue)
+				row := FixtureRunGW{
+					Event:         gw,
+					FixtureID:     ctx.FixtureID,
+					OpponentID:    ctx.OpponentID,
+					OpponentShort: ctx.OpponentShort,
+					Venue:         ctx.Venue,
+					Score:         &blended,
+				}
+				if includeRaw {
+					row.SeasonScore = &seasonScore
+					row.RecentScore = &recentScore
+				}
+				run.GWs = append(run.GWs, row)
+				run.Fixtures++
+				total += blended
+			}
+		}
+		if run.Fixtures > 0 {
+			run.AverageScore = total / float64(run.Fixtures)
+		}
+		runs = append(runs, run)
+	}
+
+	sort.SliceStable(runs, func(i, j int) bool {
+		if (runs[i].Fixtures == 0) != (runs[j].Fixtures == 0) {
+			return runs[j].Fixtures == 0
+		}
+		if runs[i].AverageScore != runs[j].AverageScore {
+			return runs[i].AverageScore > runs[j].AverageScore
+		}
+		return runs[i].TeamShort < runs[j].TeamShort
+	})
+	if limit > 0 && limit < len(runs) {
+		runs = runs[:limit]
+	}
+	for i := range runs {
+		runs[i].Rank = i + 1
+	}
+	return runs
 }
 
 func buildFixtureContexts(fixtures []fixture, teamShort map[int]string) []FixtureContext {
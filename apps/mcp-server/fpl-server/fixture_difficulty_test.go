@@ -0,0 +1,156 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBuildFixtureDifficulty_DoubleGW(t *testing.T) {
+	dir, cfg := tmpCfg(t)
+	writeDGWFixture(t, dir)
+
+	asOf, next := dgwHistGW, dgwGW
+	includeRaw := true
+	out, err := buildFixtureDifficulty(cfg, FixtureDifficultyArgs{LeagueID: 1, AsOfGW: &asOf, NextGW: &next, IncludeRaw: &includeRaw})
+	if err != nil {
+		t.Fatalf("buildFixtureDifficulty: %v", err)
+	}
+	mids := out.Positions["MID"]
+	if len(mids) != 4 {
+		t.Fatalf("MID rows = %d, want one per side of both fixtures", len(mids))
+	}
+	ars := 0
+	for _, row := range mids {
+		if row.TeamShort == "ARS" {
+			ars++
+		}
+	}
+	if ars != 2 {
+		t.Errorf("ARS rows = %d, want 2 in a double gameweek", ars)
+	}
+	// CHE conceded 6 MID points in GW9 once Saka's double is split; the full
+	// 12 would mean the DGW total was charged to both opponents.
+	top := mids[0]
+	if top.TeamShort != "ARS" || top.OpponentShort != "CHE" || top.Score == nil || !approxEqual(*top.Score, 6) {
+		t.Errorf("top MID fixture = %+v, want ARS at CHE scoring 6", top)
+	}
+}
+
+func TestBuildFixtureDifficulty_TeamFilter(t *testing.T) {
+	dir, cfg := tmpCfg(t)
+	writeDGWFixture(t, dir)
+
+	asOf, next := dgwHistGW, dgwGW
+	team := "che"
+	out, err := buildFixtureDifficulty(cfg, FixtureDifficultyArgs{LeagueID: 1, AsOfGW: &asOf, NextGW: &next, Team: &team})
+	if err != nil {
+		t.Fatalf("buildFixtureDifficulty: %v", err)
+	}
+	if out.TeamShort != "CHE" || len(out.Positions) != 4 {
+		t.Fatalf("team %q, %d positions; want CHE across all 4", out.TeamShort, len(out.Positions))
+	}
+	if mids := out.Positions["MID"]; len(mids) != 1 || mids[0].OpponentShort != "ARS" || mids[0].Rank != 1 {
+		t.Errorf("CHE MID rows = %+v, want only CHE v ARS", mids)
+	}
+}
+
+func TestBuildFixtureDifficulty_MultiGW(t *testing.T) {
+	dir, cfg := tmpCfg(t)
+	writeDGWFixture(t, dir)
+	// GW11 only exists in live.json: LIV v CHE, so ARS and MCI blank. GW12
+	// has no fixture data at all.
+	writeJSON(t, filepath.Join(dir, "gw", "11", "live.json"), map[string]any{
+		"elements": map[string]any{},
+		"fixtures": []any{
+			map[string]any{"id": 111, "event": 11, "team_h": 3, "team_a": 2},
+		},
+	})
+
+	asOf, next, count, saka := dgwHistGW, dgwGW, 3, 1
+	out, err := buildFixtureDifficulty(cfg, FixtureDifficultyArgs{LeagueID: 1, AsOfGW: &asOf, NextGW: &next, GWCount: &count, ElementID: &saka})
+	if err != nil {
+		t.Fatalf("buildFixtureDifficulty: %v", err)
+	}
+	if out.Positions != nil || len(out.Runs) != 1 || len(out.Runs["MID"]) != 1 {
+		t.Fatalf("runs = %+v, want one ARS MID run only", out.Runs)
+	}
+	if len(out.UnknownGWs) != 1 || out.UnknownGWs[0] != 12 {
+		t.Errorf("unknown GWs = %v, want [12]", out.UnknownGWs)
+	}
+	run := out.Runs["MID"][0]
+	if run.TeamShort != "ARS" || run.Fixtures != 2 || len(run.GWs) != 3 {
+		t.Fatalf("run = %+v, want ARS with a GW10 double and a GW11 blank", run)
+	}
+	if run.GWs[0].Event != 10 || run.GWs[1].Event != 10 || !run.GWs[2].Blank || run.GWs[2].Event != 11 {
+		t.Errorf("run rows = %+v", run.GWs)
+	}
+	// Only the CHE fixture has history: 6 at CHE and 0 v MCI average 3.
+	if !approxEqual(run.AverageScore, 3) {
+		t.Errorf("average = %v, want 3", run.AverageScore)
+	}
+}
+
+func TestBuildFixtureDifficulty_BadFilters(t *testing.T) {
+	dir, cfg := tmpCfg(t)
+	writeDGWFixture(t, dir)
+
+	asOf, next := dgwHistGW, dgwGW
+	nine, saka, missing := 9, 1, 99
+	team, bogus := "LIV", "XYZ"
+	for name, args := range map[string]FixtureDifficultyArgs{
+		"gw_count over max":  {GWCount: &nine},
+		"unknown team":       {Team: &bogus},
+		"player not in team": {Team: &team, ElementID: &saka},
+		"unknown element":    {ElementID: &missing},
+	} {
+		args.LeagueID, args.AsOfGW, args.NextGW = 1, &asOf, &next
+		_, err := buildFixtureDifficulty(cfg, args)
+		want := codeInvalidArgument
+		if name == "unknown element" {
+			want = codeNotFound
+		}
+		if got := classifyError(err).Code; got != want {
+			t.Errorf("%s: code = %s, want %s (err %v)", name, got, want, err)
+		}
+	}
+}
+
+// TestBuildFixtureDifficulty_FixtureShapes reruns the double gameweek with
+// bootstrap fixtures as a flat array, then with none at all.
+func TestBuildFixtureDifficulty_FixtureShapes(t *testing.T) {
+	dir, cfg := tmpCfg(t)
+	writeDGWFixture(t, dir)
+	path := filepath.Join(dir, "bootstrap", "bootstrap-static.json")
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var bootstrap map[string]any
+	if err := json.Unmarshal(raw, &bootstrap); err != nil {
+		t.Fatal(err)
+	}
+	bootstrap["fixtures"] = bootstrap["fixtures"].(map[string]any)["10"]
+	writeJSON(t, path, bootstrap)
+
+	asOf, next := dgwHistGW, dgwGW
+	out, err := buildFixtureDifficulty(cfg, FixtureDifficultyArgs{LeagueID: 1, AsOfGW: &asOf, NextGW: &next})
+	if err != nil {
+		t.Fatalf("array fixtures: %v", err)
+	}
+	if len(out.Positions["MID"]) != 4 || out.Warning != "" {
+		t.Errorf("array fixtures: %d MID rows, warning %q; want the double gameweek", len(out.Positions["MID"]), out.Warning)
+	}
+
+	bootstrap["fixtures"] = map[string]any{}
+	writeJSON(t, path, bootstrap)
+	out, err = buildFixtureDifficulty(cfg, FixtureDifficultyArgs{LeagueID: 1, AsOfGW: &asOf, NextGW: &next})
+	if err != nil {
+		t.Fatalf("no fixtures: %v", err)
+	}
+	if !strings.HasPrefix(out.Warning, "DATA_MISSING") {
+		t.Errorf("no fixtures: warning %q", out.Warning)
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/summary"
+)
+
+type FixturesArgs struct {
+	LeagueID int    `json:"league_id" jsonschema:"Draft league id (required)"`
+	AsOfGW   *int   `json:"as_of_gw,omitempty" jsonschema:"Start from gameweek (0 = current)"`
+	GW       *int   `json:"gw,omitempty" jsonschema:"Alias for as_of_gw"`
+	Horizon  *int   `json:"horizon,omitempty" jsonschema:"How many GWs forward (default 5)"`
+	TZ       string `json:"tz,omitempty" jsonschema:"IANA time zone for kickoff_local, e.g. America/New_York (default UTC)"`
+	PageSize *int   `json:"page_size,omitempty" jsonschema:"Fixtures per page (default all in one response)"`
+	Cursor   string `json:"cursor,omitempty" jsonschema:"next_cursor from the previous page"`
+}
+
+// LocalFixture is a fixture with its kickoff in the caller's time zone.
+type LocalFixture struct {
+	summary.FixtureSummary
+	KickoffLocal *LocalTime `json:"kickoff_local,omitempty"`
+}
+
+// FixturesOutput is the output of the fixtures tool: the derived upcoming
+// fixtures summary with local kickoffs added.
+type FixturesOutput struct {
+	summary.UpcomingFixturesSummary
+	TZ       string         `json:"tz"`
+	Fixtures []LocalFixture `json:"fixtures"`
+	Page     *PageInfo      `json:"page,omitempty"`
+	GWNote   *GWNote        `json:"gw_note,omitempty"`
+}
+
+// buildFixtures loads the upcoming fixtures summary and converts each
+// kickoff into args.TZ, measuring how far off it is from now.
+func buildFixtures(cfg ServerConfig, args FixturesArgs, now time.Time) (FixturesOutput, error) {
+	if args.LeagueID == 0 {
+		return FixturesOutput{}, invalidArgumentf("league_id is required")
+	}
+	loc, err := loadTimezone(args.TZ)
+	if err != nil {
+		return FixturesOutput{}, err
+	}
+	asOf := 0
+	if args.AsOfGW != nil {
+		asOf = *args.AsOfGW
+	} else if args.GW != nil {
+		asOf = *args.GW
+	}
+	gw, note, err := resolveEffectiveGW(cfg, asOf, gwModeCurrent)
+	if err != nil {
+		return FixturesOutput{}, err
+	}
+	h := 0
+	if args.Horizon != nil {
+		h = *args.Horizon
+	}
+	if h <= 0 {
+		h = 5
+	}
+	relPath := fmt.Sprintf("summary/fixtures/%d/from_gw/%d_h%d.json", args.LeagueID, gw, h)
+	f, err := loadSummaryFileWithMeta(cfg, args.LeagueID, gw, relPath, []int{h}, []string{"low", "med", "high"})
+	if err != nil {
+		return FixturesOutput{}, err
+	}
+	var upcoming summary.UpcomingFixturesSummary
+	if err := json.Unmarshal(f.Bytes, &upcoming); err != nil {
+		return FixturesOutput{}, fmt.Errorf("parse %s: %w", relPath, err)
+	}
+
+	out := FixturesOutput{
+		UpcomingFixturesSummary: upcoming,
+		TZ:                      loc.String(),
+		Fixtures:                make([]LocalFixture, 0, len(upcoming.Fixtures)),
+		GWNote:                  note,
+	}
+	fixtures := upcoming.Fixtures
+	if page := (PageArgs{PageSize: args.PageSize, Cursor: args.Cursor}); page.paged() {
+		var info PageInfo
+		fixtures, info, err = paginate(fixtures, page, fmt.Sprintf("fixtures|%d|%d|%d", args.LeagueID, gw, h), f.Hash)
+		if err != nil {
+			return FixturesOutput{}, err
+		}
+		out.Page = &info
+	}
+	for _, fx := range fixtures {
+		out.Fixtures = append(out.Fixtures, LocalFixture{
+			FixtureSummary: fx,
+			KickoffLocal:   localTimeOf(fx.KickoffUTC, loc, now),
+		})
+	}
+	return out, nil
+}
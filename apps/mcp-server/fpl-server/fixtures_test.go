@@ -0,0 +1,47 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestBuildFixtures_LocalKickoffs(t *testing.T) {
+	dir, cfg := resourceCfg(t)
+	writeFullGameJSON(t, dir, 29, false, 30, true, "n")
+	writeJSON(t, filepath.Join(dir, "summary/fixtures/100/from_gw/29_h2.json"), map[string]any{
+		"league_id": 100, "as_of_gw": 29, "horizon": 2,
+		"fixtures": []any{
+			map[string]any{"fixture_id": 1, "event": 29, "team_h_short": "LIV", "team_a_short": "MCI", "kickoff_utc": "2026-03-07T15:00:00Z"},
+			map[string]any{"fixture_id": 2, "event": 30, "team_h_short": "ARS", "team_a_short": "CHE", "kickoff_utc": ""},
+		},
+	})
+
+	h := 2
+	now := time.Date(2026, 3, 5, 10, 0, 0, 0, time.UTC)
+	out, err := buildFixtures(cfg, FixturesArgs{LeagueID: 100, Horizon: &h, TZ: "America/New_York"}, now)
+	if err != nil {
+		t.Fatalf("buildFixtures: %v", err)
+	}
+	if out.TZ != "America/New_York" || out.AsOfGW != 29 || len(out.Fixtures) != 2 {
+		t.Fatalf("out = %+v", out)
+	}
+	f := out.Fixtures[0]
+	if f.KickoffUTC != "2026-03-07T15:00:00Z" || f.TeamHShort != "LIV" {
+		t.Errorf("fixture = %+v, want the summary fields kept", f.FixtureSummary)
+	}
+	want := LocalTime{Time: "2026-03-07T10:00:00-05:00", Display: "Sat 7 Mar, 10:00 AM EST", Relative: "in 2d 5h"}
+	if f.KickoffLocal == nil || *f.KickoffLocal != want {
+		t.Errorf("kickoff_local = %+v, want %+v", f.KickoffLocal, want)
+	}
+	if out.Fixtures[1].KickoffLocal != nil {
+		t.Errorf("unscheduled fixture kickoff_local = %+v, want none", out.Fixtures[1].KickoffLocal)
+	}
+
+	if _, err := buildFixtures(cfg, FixturesArgs{LeagueID: 100, Horizon: &h, TZ: "EST"}, now); err != nil {
+		t.Errorf("tz EST: %v", err)
+	}
+	if _, err := buildFixtures(cfg, FixturesArgs{LeagueID: 100, TZ: "New York"}, now); classifyError(err).Code != codeInvalidArgument {
+		t.Errorf("bad tz: err = %v", err)
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import "sync"
+
+// flightGroup collapses concurrent calls that share a key into one: the first
+// caller runs fn and everyone who arrives while it is running waits for and
+// shares its result. Once fn returns the key is forgotten, so a later call
+// runs fn again.
+type flightGroup[T any] struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall[T]
+}
+
+type flightCall[T any] struct {
+	done chan struct{}
+	val  T
+	err  error
+}
+
+// Do runs fn for key, or waits for the run already in flight. shared reports
+// whether the result came from another caller's run.
+func (g *flightGroup[T]) Do(key string, fn func() (T, error)) (val T, err error, shared bool) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*flightCall[T])
+	}
+	if c, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		<-c.done
+		return c.val, c.err, true
+	}
+	c := &flightCall[T]{done: make(chan struct{})}
+	g.calls[key] = c
+	g.mu.Unlock()
+
+	defer func() {
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		close(c.done)
+	}()
+	c.val, c.err = fn()
+	return c.val, c.err, false
+}
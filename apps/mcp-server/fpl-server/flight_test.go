@@ -0,0 +1,157 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+)
+
+func TestFlightGroup_ForgetsFinishedKeys(t *testing.T) {
+	var g flightGroup[int]
+	runs := 0
+	fn := func() (int, error) {
+		runs++
+		return runs, errors.New("boom")
+	}
+	for want := 1; want <= 2; want++ {
+		v, err, shared := g.Do("k", fn)
+		if v != want || err == nil || shared {
+			t.Errorf("call %d: v=%d err=%v shared=%v, want a fresh run with its error", want, v, err, shared)
+		}
+	}
+}
+
+func TestLoadSummaryFile_ConcurrentComputeRunsOnce(t *testing.T) {
+	dir, cfg := resourceCfg(t)
+	cfg.ComputeMissing = true
+	cfg.WriteDerived = true
+	writeLeagueDetailsFixture(t, dir, 100, []any{
+		map[string]any{"id": 1, "entry_id": 200, "entry_name": "Alpha FC"},
+		map[string]any{"id": 2, "entry_id": 201, "entry_name": "Beta FC"},
+	}, []any{})
+	writeJSON(t, filepath.Join(dir, "league/100/transactions.json"), map[string]any{"transactions": []any{}})
+	writeJSON(t, filepath.Join(dir, "league/100/trades.json"), map[string]any{"trades": []any{}})
+
+	const callers = 20
+	relPath := "summary/transactions/100/gw/1.json"
+	before := map[string]float64{}
+	for _, src := range []string{"disk", "computed", "shared"} {
+		before[src] = mcpMetrics.summaryLoads.Value(src)
+	}
+
+	start := make(chan struct{})
+	results := make([][]byte, callers)
+	errs := make([]error, callers)
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			results[i], errs[i] = loadSummaryFile(cfg, 100, 1, relPath, nil, nil)
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	for i := range results {
+		if errs[i] != nil {
+			t.Fatalf("caller %d: %v", i, errs[i])
+		}
+		var v map[string]any
+		if err := json.Unmarshal(results[i], &v); err != nil {
+			t.Fatalf("caller %d got invalid JSON: %v", i, err)
+		}
+	}
+	delta := func(src string) float64 { return mcpMetrics.summaryLoads.Value(src) - before[src] }
+	if got := delta("computed"); got != 1 {
+		t.Errorf("computations = %v, want exactly 1", got)
+	}
+	if got := delta("computed") + delta("shared") + delta("disk"); got != callers {
+		t.Errorf("loads accounted for = %v, want %d", got, callers)
+	}
+}
+
+// TestLoadSummaryFile_FamiliesShareOneBuild: the league and matchup files
+// come from the same BuildLeagueSummaries run, so concurrent loads of both
+// build once, as league_dashboard's fan-out does.
+func TestLoadSummaryFile_FamiliesShareOneBuild(t *testing.T) {
+	dir, cfg := resourceCfg(t)
+	cfg.ComputeMissing = true
+	cfg.WriteDerived = true
+	writeGW1Fixture(t, dir, gw1Live(), true)
+
+	const callers = 20
+	paths := []string{"summary/league/100/gw/1.json", "summary/matchup/100/gw/1.json"}
+	before := map[string]float64{}
+	for _, src := range []string{"disk", "computed", "shared"} {
+		before[src] = mcpMetrics.summaryLoads.Value(src)
+	}
+
+	start := make(chan struct{})
+	errs := make([]error, callers)
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			var b []byte
+			b, errs[i] = loadSummaryFile(cfg, 100, 1, paths[i%len(paths)], nil, nil)
+			if errs[i] == nil && !json.Valid(b) {
+				errs[i] = errors.New("invalid JSON")
+			}
+		}(i)
+	}
+	// Hold the build on the league lock until every caller has had time to
+	// reach its flight.
+	lock := leagueLocks.get(cfg.DerivedRoot, 100)
+	lock.Lock()
+	close(start)
+	time.Sleep(50 * time.Millisecond)
+	lock.Unlock()
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("caller %d (%s): %v", i, paths[i%len(paths)], err)
+		}
+	}
+	delta := func(src string) float64 { return mcpMetrics.summaryLoads.Value(src) - before[src] }
+	if got := delta("computed"); got != 1 {
+		t.Errorf("builds = %v, want exactly 1 for both families", got)
+	}
+	if got := delta("computed") + delta("shared") + delta("disk"); got != callers {
+		t.Errorf("loads accounted for = %v, want %d", got, callers)
+	}
+}
+
+func TestEnsureLedger_Concurrent(t *testing.T) {
+	dir, _ := resourceCfg(t)
+	writeJSON(t, filepath.Join(dir, "draft/100/choices.json"), map[string]any{
+		"choices": []any{
+			map[string]any{"entry": 200, "element": 1, "round": 1, "pick": 1, "index": 1},
+		},
+	})
+	st := store.NewJSONStore(dir)
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := ensureLedger(st, dir, 100, nil); err != nil {
+				t.Errorf("ensureLedger: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	b, err := store.ReadDerived(filepath.Join(dir, "ledger/100/event_0.json"))
+	if err != nil || !json.Valid(b) {
+		t.Errorf("ledger = %q err = %v, want valid JSON", b, err)
+	}
+}
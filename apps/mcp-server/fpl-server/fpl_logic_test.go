@@ -352,6 +352,71 @@ func TestBuildManagerSeason_MissingLeagueID(t *testing.T) {
 	}
 }
 
+func TestBuildManagerSeason_LuckIndex(t *testing.T) {
+	tmp := t.TempDir()
+	match := func(gw, e1, p1, e2, p2 int) map[string]any {
+		return map[string]any{"event": gw, "finished": true, "started": true,
+			"league_entry_1": e1, "league_entry_1_points": p1,
+			"league_entry_2": e2, "league_entry_2_points": p2}
+	}
+	// Alpha posts the second-best score every week but always meets the
+	// one team that beat it; Beta wins every week on lower scores.
+	writeLeagueDetails(t, tmp, 888, map[string]any{
+		"league_entries": []map[string]any{
+			{"id": 1, "entry_id": 101, "entry_name": "Alpha FC"},
+			{"id": 2, "entry_id": 102, "entry_name": "Beta United"},
+			{"id": 3, "entry_id": 103, "entry_name": "Gamma Town"},
+			{"id": 4, "entry_id": 104, "entry_name": "Delta City"},
+		},
+		"matches": []map[string]any{
+			match(1, 1, 80, 2, 90), match(1, 3, 50, 4, 40),
+			match(2, 1, 75, 3, 85), match(2, 2, 60, 4, 30),
+			match(3, 1, 70, 4, 72), match(3, 2, 50, 3, 40),
+			{"event": 4, "finished": false, "league_entry_1": 1, "league_entry_2": 2},
+		},
+	})
+	cfg := ServerConfig{RawRoot: tmp}
+
+	alpha := 101
+	out, err := buildManagerSeason(cfg, ManagerSeasonArgs{LeagueID: 888, EntryID: &alpha})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.Record != (SeasonRecord{Losses: 3}) || out.AllPlay != (SeasonRecord{Wins: 6, Losses: 3}) {
+		t.Errorf("record %+v all-play %+v, want 0-0-3 and 6-0-3", out.Record, out.AllPlay)
+	}
+	if math.Abs(out.ExpectedWins-2) > 1e-9 || math.Abs(out.LuckIndex+2) > 1e-9 {
+		t.Errorf("expected wins %.3f luck %.3f, want 2 and -2", out.ExpectedWins, out.LuckIndex)
+	}
+	if out.PointsAgainst != 247 || out.AvgMarginOfVictory != 0 || math.Abs(out.AvgMarginOfDefeat-22.0/3) > 1e-9 {
+		t.Errorf("against %d victory %.2f defeat %.2f", out.PointsAgainst, out.AvgMarginOfVictory, out.AvgMarginOfDefeat)
+	}
+	if out.BeatMedianCount != 3 || len(out.Gameweeks) != 3 {
+		t.Fatalf("beat median %d of %d GWs, want 3 of 3", out.BeatMedianCount, len(out.Gameweeks))
+	}
+	if gw := out.Gameweeks[0]; gw.LeagueMedian != 65 || !gw.BeatMedian || gw.OpponentScore != 90 || gw.AllPlay.Wins != 2 {
+		t.Errorf("GW1 = %+v, want median 65 beaten", gw)
+	}
+
+	beta := 102
+	out, err = buildManagerSeason(cfg, ManagerSeasonArgs{LeagueID: 888, EntryID: &beta})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(out.LuckIndex-4.0/3) > 1e-9 || out.BeatMedianCount != 1 {
+		t.Errorf("Beta luck %.3f beat median %d, want +1.333 and 1", out.LuckIndex, out.BeatMedianCount)
+	}
+	if math.Abs(out.AvgMarginOfVictory-50.0/3) > 1e-9 {
+		t.Errorf("Beta avg margin of victory = %.3f, want 16.667", out.AvgMarginOfVictory)
+	}
+}
+
+func TestMedianScore(t *testing.T) {
+	if got := medianScore([]int{40, 90, 50}); got != 50 {
+		t.Errorf("medianScore odd = %v", got)
+	}
+}
+
 // ---------------------------------------------------------------------------
 // buildManagerStreak
 // ---------------------------------------------------------------------------
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/clock"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+)
+
+// FreeAgentScores is the derived summary/free_agent_scores file: the
+// components of the waiver score that come from scanning live.json files
+// (minutes, xG/xA, bonus, saves, consistency, clean sheets and points
+// conceded). They are identical for every waiver_recommendations call in a
+// GW, so the first call writes them and later calls read one file instead of
+// walking the horizon. Fixture scores for the target GW are cheap and still
+// computed per call from the conceded tables.
+//
+// LiveGWs and LiveModified record the live.json files the scores were built
+// from; when either no longer matches what is on disk the file is stale and
+// rebuilt.
+type FreeAgentScores struct {
+	LeagueID       int                                `json:"league_id"`
+	Gameweek       int                                `json:"gameweek"`
+	Horizon        int                                `json:"horizon"`
+	GeneratedAtUTC string                             `json:"generated_at_utc"`
+	LiveGWs        []int                              `json:"live_gws"`
+	LiveModified   string                             `json:"live_modified"`
+	Players        []FreeAgentScore                   `json:"players"`
+	CleanSheetRate map[int]float64                    `json:"clean_sheet_rate"` // by team
+	ConcededSeason map[int]map[string]map[int]avgStat `json:"conceded_season"`
+	ConcededRecent map[int]map[string]map[int]avgStat `json:"conceded_recent"`
+}
+
+// FreeAgentScore is one element's live-data components. XG, XA and
+// SavesPer90 are per 90 minutes over the horizon; Minutes60Season counts
+// from the league's first GW.
+type FreeAgentScore struct {
+	Element         int     `json:"element"`
+	Minutes60Season int     `json:"minutes_60_season"`
+	Minutes60Last3  int     `json:"minutes_60_last3"`
+	XG              float64 `json:"xg"`
+	XA              float64 `json:"xa"`
+	Bonus           float64 `json:"bonus"`
+	SavesPer90      float64 `json:"saves_per90"`
+	AvgPoints       float64 `json:"avg_points"`
+	StdDevPoints    float64 `json:"stddev_points"`
+}
+
+// horizonStats holds the live-data inputs to waiver scoring for one as-of GW
+// and horizon.
+type horizonStats struct {
+	season60       map[int]int
+	last3          map[int]int
+	xg             map[int]float64
+	xa             map[int]float64
+	bonus          map[int]float64
+	avgPoints      map[int]float64
+	stddevPoints   map[int]float64
+	defensive      defensiveStats
+	concededSeason map[int]map[string]map[int]avgStat
+	concededRecent map[int]map[string]map[int]avgStat
+}
+
+func freeAgentScoresPath(leagueID int, gw int, horizon int) string {
+	return fmt.Sprintf("summary/free_agent_scores/%d/gw/%d_h%d.json", leagueID, gw, horizon)
+}
+
+// computeHorizonStats scans the live files behind waiver scoring. Season
+// minute counts start at the league's startGW.
+func computeHorizonStats(rawRoot string, elements []elementInfo, startGW int, asOfGW int, horizon int) (horizonStats, error) {
+	var s horizonStats
+	var err error
+	if s.season60, s.last3, s.xg, err = computeAvailabilityAndXG(rawRoot, elements, startGW, asOfGW, horizon); err != nil {
+		return horizonStats{}, err
+	}
+	s.xa, s.bonus = computeXAAndBonus(rawRoot, asOfGW, horizon)
+	s.defensive = computeDefensiveStats(rawRoot, elements, asOfGW, horizon)
+	if s.avgPoints, s.stddevPoints, err = computeConsistencyStats(rawRoot, elements, asOfGW, horizon); err != nil {
+		return horizonStats{}, err
+	}
+	s.concededSeason = computePointsConcededByPosition(rawRoot, elements, asOfGW, asOfGW)
+	s.concededRecent = computePointsConcededByPosition(rawRoot, elements, asOfGW, horizon)
+	return s, nil
+}
+
+// liveFilesState lists the GWs from startGW to asOfGW with a live.json and
+// the newest of their mtimes.
+func liveFilesState(rawRoot string, startGW int, asOfGW int) ([]int, string) {
+	gws := make([]int, 0, asOfGW)
+	var newest time.Time
+	for gw := startGW; gw <= asOfGW; gw++ {
+		info, err := os.Stat(filepath.Join(rawRoot, "gw", fmt.Sprint(gw), "live.json"))
+		if err != nil {
+			continue
+		}
+		gws = append(gws, gw)
+		if info.ModTime().After(newest) {
+			newest = info.ModTime()
+		}
+	}
+	if newest.IsZero() {
+		return gws, ""
+	}
+	return gws, newest.UTC().Format(time.RFC3339Nano)
+}
+
+func (s horizonStats) file(leagueID int, gw int, horizon int, liveGWs []int, liveModified string, now time.Time) FreeAgentScores {
+	ids := make(map[int]bool)
+	for _, m := range []map[int]float64{s.xg, s.xa, s.bonus, s.avgPoints, s.stddevPoints, s.defensive.savesPer90} {
+		for id := range m {
+			ids[id] = true
+		}
+	}
+	for _, m := range []map[int]int{s.season60, s.last3} {
+		for id := range m {
+			ids[id] = true
+		}
+	}
+	out := FreeAgentScores{
+		LeagueID:       leagueID,
+		Gameweek:       gw,
+		Horizon:        horizon,
+		GeneratedAtUTC: clock.UTC(now),
+		LiveGWs:        liveGWs,
+		LiveModified:   liveModified,
+		Players:        make([]FreeAgentScore, 0, len(ids)),
+		CleanSheetRate: s.defensive.cleanSheetRate,
+		ConcededSeason: s.concededSeason,
+		ConcededRecent: s.concededRecent,
+	}
+	for id := range ids {
+		out.Players = append(out.Players, FreeAgentScore{
+			Element:         id,
+			Minutes60Season: s.season60[id],
+			Minutes60Last3:  s.last3[id],
+			XG:              s.xg[id],
+			XA:              s.xa[id],
+			Bonus:           s.bonus[id],
+			SavesPer90:      s.defensive.savesPer90[id],
+			AvgPoints:       s.avgPoints[id],
+			StdDevPoints:    s.stddevPoints[id],
+		})
+	}
+	sort.Slice(out.Players, func(i, j int) bool { return out.Players[i].Element < out.Players[j].Element })
+	return out
+}
+
+func (f FreeAgentScores) stats() horizonStats {
+	n := len(f.Players)
+	s := horizonStats{
+		season60:       make(map[int]int, n),
+		last3:          make(map[int]int, n),
+		xg:             make(map[int]float64, n),
+		xa:             make(map[int]float64, n),
+		bonus:          make(map[int]float64, n),
+		avgPoints:      make(map[int]float64, n),
+		stddevPoints:   make(map[int]float64, n),
+		defensive:      defensiveStats{savesPer90: make(map[int]float64, n), cleanSheetRate: f.CleanSheetRate},
+		concededSeason: f.ConcededSeason,
+		concededRecent: f.ConcededRecent,
+	}
+	if s.defensive.cleanSheetRate == nil {
+		s.defensive.cleanSheetRate = make(map[int]float64)
+	}
+	for _, p := range f.Players {
+		s.season60[p.Element] = p.Minutes60Season
+		s.last3[p.Element] = p.Minutes60Last3
+		s.xg[p.Element] = p.XG
+		s.xa[p.Element] = p.XA
+		s.bonus[p.Element] = p.Bonus
+		s.defensive.savesPer90[p.Element] = p.SavesPer90
+		s.avgPoints[p.Element] = p.AvgPoints
+		s.stddevPoints[p.Element] = p.StdDevPoints
+	}
+	return s
+}
+
+func sameGWs(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// loadHorizonStats returns the horizon stats from the free_agent_scores file
+// when it matches the live files on disk, and otherwise computes them and,
+// with WriteDerived on, writes the file for the next call.
+func loadHorizonStats(cfg ServerConfig, leagueID int, elements []elementInfo, asOfGW int, horizon int) (horizonStats, error) {
+	startGW := leagueStartGW(store.NewJSONStore(cfg.RawRoot), leagueID)
+	liveGWs, liveModified := liveFilesState(cfg.RawRoot, startGW, asOfGW)
+	path := filepath.Join(cfg.DerivedRoot, freeAgentScoresPath(leagueID, asOfGW, horizon))
+	if cfg.DerivedRoot != "" {
+		start := time.Now()
+		b, err := store.ReadDerived(path)
+		cfg.timing.since(start, false)
+		if err == nil {
+			var f FreeAgentScores
+			if json.Unmarshal(b, &f) == nil && f.LiveModified == liveModified && sameGWs(f.LiveGWs, liveGWs) {
+				mcpMetrics.summaryLoads.Inc(sourceDisk)
+				cfg.timing.served(sourceDisk)
+				return f.stats(), nil
+			}
+		}
+	}
+	start := time.Now()
+	s, err := computeHorizonStats(cfg.RawRoot, elements, startGW, asOfGW, horizon)
+	cfg.timing.since(start, true)
+	if err != nil {
+		return horizonStats{}, err
+	}
+	mcpMetrics.summaryLoads.Inc(sourceComputed)
+	cfg.timing.served(sourceComputed)
+	if cfg.WriteDerived && cfg.DerivedRoot != "" {
+		// A failed write only costs the next call a recompute.
+		_ = store.WriteDerivedJSON(path, s.file(leagueID, asOfGW, horizon, liveGWs, liveModified, cfg.now()))
+	}
+	return s, nil
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+)
+
+// writeHorizonLive writes GWs 1..n of live.json for the writeBootstrap
+// players, Salah scoring gw points with 0.1*gw xG in 90 minutes.
+func writeHorizonLive(t *testing.T, dir string, n int) {
+	t.Helper()
+	for gw := 1; gw <= n; gw++ {
+		writeJSON(t, filepath.Join(dir, "gw", itoa(gw), "live.json"), map[string]any{
+			"elements": map[string]any{
+				"1": map[string]any{"stats": map[string]any{"minutes": 90, "total_points": gw, "expected_goals": 0.1 * float64(gw), "bonus": 1}},
+				"3": map[string]any{"stats": map[string]any{"minutes": 90, "total_points": 2, "clean_sheets": 1}},
+			},
+			"fixtures": []any{map[string]any{"id": gw, "team_h": 10, "team_a": 11}},
+		})
+	}
+}
+
+func TestLoadHorizonStats_CachesUntilLiveChanges(t *testing.T) {
+	dir, cfg := resourceCfg(t)
+	cfg.WriteDerived = true
+	writeBootstrap(t, dir)
+	writeHorizonLive(t, dir, 3)
+	elements, _, _, err := loadBootstrapData(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want, err := computeHorizonStats(dir, elements, 1, 3, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := loadHorizonStats(cfg, 100, elements, 3, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.season60[1] != 3 || got.last3[1] != 3 || math.Abs(got.xg[1]-want.xg[1]) > 1e-9 || got.avgPoints[1] != 2.5 {
+		t.Errorf("computed stats = %+v", got)
+	}
+
+	path := filepath.Join(dir, freeAgentScoresPath(100, 3, 2))
+	b, err := store.ReadDerived(path)
+	if err != nil {
+		t.Fatalf("free_agent_scores not written: %v", err)
+	}
+	var f FreeAgentScores
+	if err := json.Unmarshal(b, &f); err != nil {
+		t.Fatal(err)
+	}
+	if len(f.LiveGWs) != 3 || f.LiveModified == "" || f.CleanSheetRate[10] != 1 || f.ConcededRecent[11]["AWAY"][3].Count != 2 {
+		t.Errorf("file = %+v", f)
+	}
+
+	// A current file is used as is: mark it and read the mark back.
+	for i := range f.Players {
+		if f.Players[i].Element == 1 {
+			f.Players[i].XG = 99
+		}
+	}
+	if err := store.WriteDerivedJSON(path, f); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := loadHorizonStats(cfg, 100, elements, 3, 2); got.xg[1] != 99 {
+		t.Errorf("warm xg = %v, want the cached 99", got.xg[1])
+	}
+	if got, _ := loadHorizonStats(cfg, 100, elements, 3, 2); got.defensive.cleanSheetRate[10] != 1 || got.concededSeason[11]["AWAY"][3].Count != 3 {
+		t.Errorf("warm defensive/conceded = %+v / %+v", got.defensive, got.concededSeason[11])
+	}
+
+	// A rewritten live.json makes the file stale.
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(filepath.Join(dir, "gw", "2", "live.json"), later, later); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := loadHorizonStats(cfg, 100, elements, 3, 2); got.xg[1] == 99 {
+		t.Error("stale file reused after live.json changed")
+	}
+
+	// So does a live.json that appears for a GW the file didn't see.
+	f.LiveGWs = []int{1, 3}
+	_, f.LiveModified = liveFilesState(dir, 1, 3)
+	if err := store.WriteDerivedJSON(path, f); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := loadHorizonStats(cfg, 100, elements, 3, 2); got.xg[1] == 99 {
+		t.Error("stale file reused after the set of live GWs changed")
+	}
+}
+
+func TestLoadHorizonStats_NoDerivedRoot(t *testing.T) {
+	dir, cfg := tmpCfg(t)
+	cfg.WriteDerived = true
+	writeBootstrap(t, dir)
+	writeHorizonLive(t, dir, 2)
+	elements, _, _, err := loadBootstrapData(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := loadHorizonStats(cfg, 100, elements, 2, 5); err != nil || got.season60[1] != 2 {
+		t.Fatalf("loadHorizonStats = %+v, %v", got, err)
+	}
+	if _, err := os.Stat(freeAgentScoresPath(100, 2, 5)); !os.IsNotExist(err) {
+		t.Errorf("file written relative to the working directory (err=%v)", err)
+	}
+}
+
+// BenchmarkHorizonStats compares a cold waiver call, which walks every
+// live.json up to GW30 (already parsed and cached by livestats), with a warm
+// one that reads the free_agent_scores file. With 600 players the cold path
+// takes about 5.5ms and the warm path about 1.5ms; a first call in a process
+// also pays the live.json parses. The cold cost grows with the GW and horizon,
+// the warm one only with the player count.
+func BenchmarkHorizonStats(b *testing.B) {
+	dir := b.TempDir()
+	cfg := ServerConfig{RawRoot: dir, DerivedRoot: dir, WriteDerived: true}
+	elements := make([]elementInfo, 0, 600)
+	live := make(map[string]any, 600)
+	for id := 1; id <= 600; id++ {
+		elements = append(elements, elementInfo{ID: id, TeamID: id%20 + 1, PositionType: id%4 + 1})
+		live[itoa(id)] = map[string]any{"stats": map[string]any{"minutes": 90, "total_points": id % 9, "expected_goals": 0.2, "saves": id % 3}}
+	}
+	fixtures := make([]any, 0, 10)
+	for i := 0; i < 10; i++ {
+		fixtures = append(fixtures, map[string]any{"id": i, "team_h": 2*i + 1, "team_a": 2*i + 2})
+	}
+	for gw := 1; gw <= 30; gw++ {
+		raw, err := json.Marshal(map[string]any{"elements": live, "fixtures": fixtures})
+		if err != nil {
+			b.Fatal(err)
+		}
+		path := filepath.Join(dir, "gw", itoa(gw), "live.json")
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			b.Fatal(err)
+		}
+		if err := os.WriteFile(path, raw, 0o644); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	b.Run("cold", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := computeHorizonStats(dir, elements, 1, 30, 10); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	if _, err := loadHorizonStats(cfg, 1, elements, 30, 10); err != nil {
+		b.Fatal(err)
+	}
+	b.Run("warm", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := loadHorizonStats(cfg, 1, elements, 30, 10); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// defaultStaleAfter is how long after a passed deadline the newest live.json
+// may predate it before responses are flagged stale.
+const defaultStaleAfter = 24 * time.Hour
+
+// historicalTools answer about the past (draft, rosters at a given GW), so a
+// missed refresh can't make them wrong and they carry no data_freshness.
+var historicalTools = map[string]bool{
+	"draft_picks":       true,
+	"draft_board":       true,
+	"historical_roster": true,
+}
+
+// DataFreshness is appended to every tool result as data_freshness so the
+// caller can tell when it is reading data from a refresh that never ran.
+type DataFreshness struct {
+	GameJSONModified string `json:"game_json_modified,omitempty"`
+	CurrentEvent     int    `json:"current_event"`
+	LatestLiveGW     int    `json:"latest_live_gw"`
+	LatestLiveAt     string `json:"latest_live_modified,omitempty"`
+	LastDeadline     string `json:"last_deadline,omitempty"`
+	Stale            bool   `json:"stale"`
+	StaleReason      string `json:"stale_reason,omitempty"`
+}
+
+// toolRegistry collects the tools listed at /tools and holds the config
+// addTool needs to annotate their results, and the values it fills example
+// placeholders with.
+type toolRegistry struct {
+	cfg           ServerConfig
+	tools         []toolInfo
+	exampleValues map[string]any
+}
+
+// dataFreshness describes the raw data under cfg.RawRoot as of now. The data
+// is stale when the most recent deadline before now is more than
+// cfg.StaleAfter after the newest live.json was written: the refresh that
+// should have followed that deadline didn't happen.
+func dataFreshness(cfg ServerConfig, now time.Time) DataFreshness {
+	var out DataFreshness
+	if mt, ok := fileModTime(filepath.Join(cfg.RawRoot, "game", "game.json")); ok {
+		out.GameJSONModified = mt.UTC().Format(time.RFC3339)
+	}
+	if meta, err := loadGameStatusMeta(cfg); err == nil {
+		out.CurrentEvent = meta.CurrentEvent
+	}
+	liveGW, liveAt, haveLive := newestLive(cfg.RawRoot)
+	if haveLive {
+		out.LatestLiveGW = liveGW
+		out.LatestLiveAt = liveAt.UTC().Format(time.RFC3339)
+	}
+
+	var deadline time.Time
+	if deadlines, err := bootstrapDeadlines(cfg.RawRoot); err == nil {
+		for _, t := range deadlines {
+			if !t.After(now) && t.After(deadline) {
+				deadline = t
+			}
+		}
+	}
+	if deadline.IsZero() {
+		return out
+	}
+	out.LastDeadline = deadline.UTC().Format(time.RFC3339)
+
+	staleAfter := cfg.StaleAfter
+	if staleAfter <= 0 {
+		staleAfter = defaultStaleAfter
+	}
+	switch {
+	case !haveLive:
+		out.Stale = true
+		out.StaleReason = "no gw live.json on disk"
+	case deadline.Sub(liveAt) > staleAfter:
+		out.Stale = true
+		out.StaleReason = fmt.Sprintf("newest live.json (GW%d) is %s older than the %s deadline",
+			liveGW, deadline.Sub(liveAt).Round(time.Hour), out.LastDeadline)
+	}
+	return out
+}
+
+type deadlineCacheEntry struct {
+	modTime   time.Time
+	size      int64
+	deadlines []time.Time
+}
+
+var (
+	deadlineCacheMu sync.Mutex
+	deadlineCache   = make(map[string]deadlineCacheEntry)
+)
+
+// bootstrapDeadlines returns the GW deadlines listed in rawRoot's bootstrap.
+// Every tool result needs them for data_freshness, so they are cached by
+// path and reparsed only when the file's mtime or size changes, as
+// livestats.LoadGW does for live.json.
+func bootstrapDeadlines(rawRoot string) ([]time.Time, error) {
+	path := filepath.Join(rawRoot, "bootstrap", "bootstrap-static.json")
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	deadlineCacheMu.Lock()
+	e, ok := deadlineCache[path]
+	deadlineCacheMu.Unlock()
+	if ok && e.modTime.Equal(info.ModTime()) && e.size == info.Size() {
+		return e.deadlines, nil
+	}
+
+	events, err := loadBootstrapEvents(rawRoot)
+	if err != nil {
+		return nil, err
+	}
+	deadlines := make([]time.Time, 0, len(events))
+	for _, ev := range events {
+		if t, err := time.Parse(time.RFC3339, ev.DeadlineTime); err == nil {
+			deadlines = append(deadlines, t)
+		}
+	}
+	deadlineCacheMu.Lock()
+	deadlineCache[path] = deadlineCacheEntry{modTime: info.ModTime(), size: info.Size(), deadlines: deadlines}
+	deadlineCacheMu.Unlock()
+	return deadlines, nil
+}
+
+// withFreshness appends data_freshness to successful results whose text is a
+// JSON object. Errors and non-object bodies pass through untouched. The
+// league is read from the league_id argument, so per-league roots are
+// annotated from their own data.
+func withFreshness[T any](cfg ServerConfig, handler func(context.Context, *mcp.CallToolRequest, T) (*mcp.CallToolResult, any, error)) func(context.Context, *mcp.CallToolRequest, T) (*mcp.CallToolResult, any, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, args T) (*mcp.CallToolResult, any, error) {
+		res, out, err := handler(ctx, req, args)
+		if err != nil || res == nil || res.IsError {
+			return res, out, err
+		}
+		var league struct {
+			LeagueID int `json:"league_id"`
+		}
+		if b, err := json.Marshal(args); err == nil {
+			_ = json.Unmarshal(b, &league)
+		}
+		freshness := dataFreshness(cfg.forLeague(league.LeagueID), cfg.now())
+		for _, c := range res.Content {
+			if text, ok := c.(*mcp.TextContent); ok {
+				text.Text = appendFreshness(text.Text, freshness)
+			}
+		}
+		return res, out, err
+	}
+}
+
+// appendFreshness adds a data_freshness key to the end of the JSON object in
+// body, keeping the body's own key order and indentation.
+func appendFreshness(body string, f DataFreshness) string {
+	trimmed := bytes.TrimRight([]byte(body), " \t\r\n")
+	if !json.Valid(trimmed) || len(trimmed) < 2 || trimmed[0] != '{' {
+		return body
+	}
+	inner := bytes.TrimRight(trimmed[:len(trimmed)-1], " \t\r\n")
+	fb, err := json.MarshalIndent(f, "  ", "  ")
+	if err != nil {
+		return body
+	}
+	var buf bytes.Buffer
+	buf.Write(inner)
+	if len(inner) > 1 {
+		buf.WriteByte(',')
+	}
+	buf.WriteString("\n  \"data_freshness\": ")
+	buf.Write(fb)
+	buf.WriteString("\n}")
+	return buf.String()
+}
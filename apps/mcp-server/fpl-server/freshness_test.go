@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// writeFreshnessFixture writes game.json, a GW5 live.json written at liveAt
+// and GW5/GW6 deadlines at now-48h and now-1h.
+func writeFreshnessFixture(t *testing.T, dir string, now time.Time, liveAt time.Time) {
+	t.Helper()
+	writeFullGameJSON(t, dir, 5, true, 6, true, "")
+	writeBootstrapEvents(t, dir, []map[string]any{
+		{"id": 5, "finished": true, "deadline_time": now.Add(-48 * time.Hour).UTC().Format(time.RFC3339)},
+		{"id": 6, "finished": false, "deadline_time": now.Add(-time.Hour).UTC().Format(time.RFC3339)},
+		{"id": 7, "finished": false, "deadline_time": now.Add(6 * 24 * time.Hour).UTC().Format(time.RFC3339)},
+	}, nil)
+	writeLiveJSON(t, dir, 5, map[string]any{})
+	if err := os.Chtimes(filepath.Join(dir, "gw", "5", "live.json"), liveAt, liveAt); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDataFreshness_MissedRefresh(t *testing.T) {
+	dir, cfg := tmpCfg(t)
+	now := time.Now()
+	// The last refresh ran three days before the GW6 deadline and none
+	// followed it.
+	writeFreshnessFixture(t, dir, now, now.Add(-73*time.Hour))
+
+	f := dataFreshness(cfg, now)
+	if !f.Stale {
+		t.Fatalf("Stale = false, want true: %+v", f)
+	}
+	if f.CurrentEvent != 5 || f.LatestLiveGW != 5 || f.GameJSONModified == "" {
+		t.Errorf("freshness = %+v, want current_event 5, live GW5 and a game.json mtime", f)
+	}
+	if want := now.Add(-time.Hour).UTC().Format(time.RFC3339); f.LastDeadline != want {
+		t.Errorf("LastDeadline = %q, want the GW6 deadline %q", f.LastDeadline, want)
+	}
+	if !strings.Contains(f.StaleReason, "GW5") {
+		t.Errorf("StaleReason = %q, want it to name the live GW", f.StaleReason)
+	}
+}
+
+func TestDataFreshness_Fresh(t *testing.T) {
+	dir, cfg := tmpCfg(t)
+	now := time.Now()
+	writeFreshnessFixture(t, dir, now, now.Add(-3*time.Hour))
+	if f := dataFreshness(cfg, now); f.Stale {
+		t.Errorf("refresh 2h before the deadline flagged stale: %+v", f)
+	}
+
+	// A tighter threshold makes the same data stale.
+	cfg.StaleAfter = time.Hour
+	if f := dataFreshness(cfg, now); !f.Stale {
+		t.Errorf("StaleAfter=1h: Stale = false, want true: %+v", f)
+	}
+}
+
+func TestDataFreshness_NoPassedDeadline(t *testing.T) {
+	dir, cfg := tmpCfg(t)
+	now := time.Now()
+	writeFullGameJSON(t, dir, 0, false, 1, false, "")
+	writeBootstrapEvents(t, dir, []map[string]any{
+		{"id": 1, "finished": false, "deadline_time": now.Add(24 * time.Hour).UTC().Format(time.RFC3339)},
+	}, nil)
+	f := dataFreshness(cfg, now)
+	if f.Stale || f.LastDeadline != "" {
+		t.Errorf("pre-season freshness = %+v, want not stale and no deadline", f)
+	}
+}
+
+func TestBootstrapDeadlines_CachesUntilFileChanges(t *testing.T) {
+	dir, _ := tmpCfg(t)
+	path := filepath.Join(dir, "bootstrap", "bootstrap-static.json")
+	t0 := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+	write := func(deadline string, mtime time.Time) {
+		t.Helper()
+		writeBootstrapEvents(t, dir, []map[string]any{{"id": 1, "deadline_time": deadline}}, nil)
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("2025-08-15T17:30:00Z", t0)
+
+	first, err := bootstrapDeadlines(dir)
+	if err != nil || len(first) != 1 {
+		t.Fatalf("bootstrapDeadlines = %v, %v", first, err)
+	}
+	second, err := bootstrapDeadlines(dir)
+	if err != nil || &first[0] != &second[0] {
+		t.Error("second read should come from the cache")
+	}
+
+	write("2025-08-22T17:30:00Z", t0.Add(time.Minute))
+	third, err := bootstrapDeadlines(dir)
+	if err != nil || len(third) != 1 || !third[0].Equal(time.Date(2025, 8, 22, 17, 30, 0, 0, time.UTC)) {
+		t.Errorf("after rewrite deadlines = %v, %v, want the new deadline", third, err)
+	}
+}
+
+func TestAppendFreshness(t *testing.T) {
+	f := DataFreshness{CurrentEvent: 5, Stale: true}
+
+	got := appendFreshness("{\n  \"b\": 1,\n  \"a\": 2\n}", f)
+	if !strings.HasPrefix(got, "{\n  \"b\": 1,\n  \"a\": 2,\n  \"data_freshness\": {") {
+		t.Errorf("key order/indent not kept:\n%s", got)
+	}
+	var parsed struct {
+		B             int           `json:"b"`
+		DataFreshness DataFreshness `json:"data_freshness"`
+	}
+	if err := json.Unmarshal([]byte(got), &parsed); err != nil {
+		t.Fatalf("result is not valid JSON: %v\n%s", err, got)
+	}
+	if parsed.B != 1 || !parsed.DataFreshness.Stale || parsed.DataFreshness.CurrentEvent != 5 {
+		t.Errorf("parsed = %+v", parsed)
+	}
+
+	if got := appendFreshness(`{}`, f); !json.Valid([]byte(got)) || !strings.Contains(got, "data_freshness") {
+		t.Errorf("empty object: %s", got)
+	}
+	for _, body := range []string{`[1, 2]`, `not json`, `"text"`} {
+		if got := appendFreshness(body, f); got != body {
+			t.Errorf("appendFreshness(%q) = %q, want it unchanged", body, got)
+		}
+	}
+}
+
+func TestWithFreshness(t *testing.T) {
+	_, cfg := tmpCfg(t)
+	type args struct {
+		LeagueID int `json:"league_id"`
+	}
+	ok := withFreshness(cfg, func(context.Context, *mcp.CallToolRequest, args) (*mcp.CallToolResult, any, error) {
+		return toolMarshal(map[string]int{"gw": 5})
+	})
+	res, _, _ := ok(context.Background(), nil, args{LeagueID: 1})
+	if text := res.Content[0].(*mcp.TextContent).Text; !strings.Contains(text, `"data_freshness"`) {
+		t.Errorf("success result not annotated:\n%s", text)
+	}
+
+	failed := withFreshness(cfg, func(context.Context, *mcp.CallToolRequest, args) (*mcp.CallToolResult, any, error) {
+		return toolError(errors.New("boom")), nil, nil
+	})
+	res, _, _ = failed(context.Background(), nil, args{})
+	if text := res.Content[0].(*mcp.TextContent).Text; strings.Contains(text, "data_freshness") {
+		t.Errorf("error result annotated:\n%s", text)
+	}
+}
@@ -8,12 +8,21 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/fixtures"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/leagueconfig"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/summary"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
-// GameStatusArgs is the input schema for game_status (no parameters).
-type GameStatusArgs struct{}
+// GameStatusArgs is the input schema for game_status. With no league_id the
+// result is the global game view.
+type GameStatusArgs struct {
+	LeagueID int    `json:"league_id,omitempty" jsonschema:"Draft league id; adds the league's trade and playoff schedule"`
+	TZ       string `json:"tz,omitempty" jsonschema:"IANA time zone for the *_local times, e.g. America/New_York (default UTC)"`
+}
 
 // FixtureProgress tracks how many fixtures have started/finished in a GW.
 type FixtureProgress struct {
@@ -22,19 +31,44 @@ type FixtureProgress struct {
 	Finished int `json:"finished"`
 }
 
-// GameStatusResult is the output of the game_status tool.
+// GameStatusResult is the output of the game_status tool. Times are UTC as
+// the API gives them; each *_local field is the same time in TZ.
 type GameStatusResult struct {
-	CurrentGW          int             `json:"current_gw"`
-	CurrentGWFinished  bool            `json:"current_gw_finished"`
-	NextGW             int             `json:"next_gw"`
-	WaiversProcessed   bool            `json:"waivers_processed"`
-	ProcessingStatus   string          `json:"processing_status"`
-	NextDeadline       string          `json:"next_deadline"`
-	NextWaiversDue     string          `json:"next_waivers_due"`
-	NextTradesDue      string          `json:"next_trades_due"`
-	NextGWFirstKickoff string          `json:"next_gw_first_kickoff,omitempty"`
-	CurrentGWFixtures  FixtureProgress `json:"current_gw_fixtures"`
-	PointsStatus       string          `json:"points_status"`
+	CurrentGW               int             `json:"current_gw"`
+	CurrentGWFinished       bool            `json:"current_gw_finished"`
+	NextGW                  int             `json:"next_gw"`
+	WaiversProcessed        bool            `json:"waivers_processed"`
+	ProcessingStatus        string          `json:"processing_status"`
+	TZ                      string          `json:"tz"`
+	NextDeadline            string          `json:"next_deadline"`
+	NextDeadlineLocal       *LocalTime      `json:"next_deadline_local,omitempty"`
+	NextWaiversDue          string          `json:"next_waivers_due"`
+	NextWaiversDueLocal     *LocalTime      `json:"next_waivers_due_local,omitempty"`
+	NextTradesDue           string          `json:"next_trades_due"`
+	NextTradesDueLocal      *LocalTime      `json:"next_trades_due_local,omitempty"`
+	NextGWFirstKickoff      string          `json:"next_gw_first_kickoff,omitempty"`
+	NextGWFirstKickoffLocal *LocalTime      `json:"next_gw_first_kickoff_local,omitempty"`
+	CurrentGWFixtures       FixtureProgress `json:"current_gw_fixtures"`
+	PointsStatus            string          `json:"points_status"`
+	League                  *LeagueSchedule `json:"league,omitempty"`
+}
+
+// LeagueSchedule is the league-specific part of game_status. With playoffs
+// (ko_rounds > 0) the regular season ends ko_rounds GWs before stop_event
+// and waivers and trades lock from the first playoff GW, so the regular
+// season's last GW is also the trade deadline.
+type LeagueSchedule struct {
+	LeagueID              int        `json:"league_id"`
+	TradesEnabled         bool       `json:"trades_enabled"`
+	StartGW               int        `json:"start_gw"`
+	StopGW                int        `json:"stop_gw"`
+	PlayoffRounds         int        `json:"playoff_rounds"`
+	RegularSeasonEndGW    int        `json:"regular_season_end_gw"`
+	RegularSeasonGWsLeft  int        `json:"regular_season_gws_remaining"`
+	TradeDeadlineGW       int        `json:"trade_deadline_gw,omitempty"`
+	TradeDeadline         string     `json:"trade_deadline,omitempty"`
+	TradeDeadlineLocal    *LocalTime `json:"trade_deadline_local,omitempty"`
+	LastWaiversBeforeLock bool       `json:"last_waivers_before_playoff_lock"`
 }
 
 // gameStatusMeta extends GameMeta with additional fields from game.json.
@@ -55,15 +89,6 @@ type bootstrapEvent struct {
 	TradesTime   string `json:"trades_time"`
 }
 
-// bootstrapFixture represents one fixture from bootstrap-static.json fixtures map.
-type bootstrapFixture struct {
-	ID          int    `json:"id"`
-	Event       int    `json:"event"`
-	KickoffTime string `json:"kickoff_time"`
-	Started     bool   `json:"started"`
-	Finished    bool   `json:"finished"`
-}
-
 // liveFixture is the subset of fields from a live.json fixture entry
 // needed for fixture progress tracking.
 type liveFixture struct {
@@ -121,21 +146,19 @@ func loadBootstrapEvents(rawRoot string) ([]bootstrapEvent, error) {
 	return resp.Events.Data, nil
 }
 
-// loadBootstrapFixturesForGW reads fixtures[gw] from bootstrap-static.json.
-// Returns nil (no error) if the GW key is absent (bootstrap drops current GW once started).
-func loadBootstrapFixturesForGW(rawRoot string, gw int) ([]bootstrapFixture, error) {
+// loadBootstrapFixturesForGW reads gw's fixtures from bootstrap-static.json.
+// Returns nil (no error) if the GW is absent (bootstrap drops current GW once started).
+func loadBootstrapFixturesForGW(rawRoot string, gw int) ([]fixture, error) {
 	path := filepath.Join(rawRoot, "bootstrap", "bootstrap-static.json")
 	raw, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("bootstrap-static.json: %w", err)
 	}
-	var resp struct {
-		Fixtures map[string][]bootstrapFixture `json:"fixtures"`
-	}
-	if err := json.Unmarshal(raw, &resp); err != nil {
-		return nil, fmt.Errorf("parse bootstrap fixtures: %w", err)
+	set, err := fixtures.Parse(raw)
+	if err != nil {
+		return nil, err
 	}
-	return resp.Fixtures[strconv.Itoa(gw)], nil
+	return set.Event(gw), nil
 }
 
 // currentGWFixtureProgress counts started/finished fixtures for a GW.
@@ -185,26 +208,35 @@ func derivePointsStatus(finished bool, fixtures FixtureProgress) string {
 	return "pending"
 }
 
-// earliestKickoff finds the earliest kickoff_time from bootstrap fixtures for a GW.
+// earliestKickoff finds the earliest kickoff_time from bootstrap fixtures for
+// a GW. Kickoffs are compared as times since the API doesn't always send them
+// in one format.
 func earliestKickoff(rawRoot string, gw int) string {
-	fixtures, err := loadBootstrapFixturesForGW(rawRoot, gw)
-	if err != nil || len(fixtures) == 0 {
+	gwFixtures, err := loadBootstrapFixturesForGW(rawRoot, gw)
+	if err != nil || len(gwFixtures) == 0 {
 		return ""
 	}
 	earliest := ""
-	for _, f := range fixtures {
-		if f.KickoffTime == "" {
+	var earliestAt time.Time
+	for _, f := range gwFixtures {
+		at, ok := summary.ParseAPITime(f.Kickoff)
+		if !ok {
 			continue
 		}
-		if earliest == "" || f.KickoffTime < earliest {
-			earliest = f.KickoffTime
+		if earliest == "" || at.Before(earliestAt) {
+			earliest, earliestAt = f.Kickoff, at
 		}
 	}
 	return earliest
 }
 
-// buildGameStatus assembles the full game status response.
-func buildGameStatus(cfg ServerConfig) (*GameStatusResult, error) {
+// buildGameStatus assembles the full game status response, measuring the
+// *_local times' relative durations against now.
+func buildGameStatus(cfg ServerConfig, args GameStatusArgs, now time.Time) (*GameStatusResult, error) {
+	loc, err := loadTimezone(args.TZ)
+	if err != nil {
+		return nil, err
+	}
 	meta, err := loadGameStatusMeta(cfg)
 	if err != nil {
 		return nil, fmt.Errorf("game.json: %w", err)
@@ -230,27 +262,86 @@ func buildGameStatus(cfg ServerConfig) (*GameStatusResult, error) {
 		NextGW:            meta.NextEvent,
 		WaiversProcessed:  meta.WaiversProcessed,
 		ProcessingStatus:  meta.ProcessingStatus,
+		TZ:                loc.String(),
 	}
 
 	if nextEvent != nil {
 		result.NextDeadline = nextEvent.DeadlineTime
+		result.NextDeadlineLocal = localTimeOf(nextEvent.DeadlineTime, loc, now)
 		result.NextWaiversDue = nextEvent.WaiversTime
+		result.NextWaiversDueLocal = localTimeOf(nextEvent.WaiversTime, loc, now)
 		result.NextTradesDue = nextEvent.TradesTime
+		result.NextTradesDueLocal = localTimeOf(nextEvent.TradesTime, loc, now)
 	}
 
 	result.NextGWFirstKickoff = earliestKickoff(cfg.RawRoot, meta.NextEvent)
+	result.NextGWFirstKickoffLocal = localTimeOf(result.NextGWFirstKickoff, loc, now)
 
 	result.CurrentGWFixtures = currentGWFixtureProgress(cfg.RawRoot, meta.CurrentEvent)
 
 	result.PointsStatus = derivePointsStatus(meta.CurrentEventFinished, result.CurrentGWFixtures)
 
+	if args.LeagueID != 0 {
+		league, err := buildLeagueSchedule(cfg, args.LeagueID, meta, events, nextEvent)
+		if err != nil {
+			return nil, err
+		}
+		if league.TradeDeadline != "" {
+			league.TradeDeadlineLocal = localTimeOf(league.TradeDeadline, loc, now)
+		}
+		result.League = league
+	}
+
 	return result, nil
 }
 
+// buildLeagueSchedule reads the league settings from league/{id}/details.json
+// and places the current GW within the league's season.
+func buildLeagueSchedule(cfg ServerConfig, leagueID int, meta gameStatusMeta, events []bootstrapEvent, next *bootstrapEvent) (*LeagueSchedule, error) {
+	settings, err := leagueconfig.Load(store.NewJSONStore(cfg.RawRoot), leagueID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &LeagueSchedule{
+		LeagueID:      leagueID,
+		TradesEnabled: settings.Trades.Enabled,
+		StartGW:       settings.StartEvent,
+		StopGW:        settings.StopEvent,
+		PlayoffRounds: settings.KORounds,
+	}
+	if out.StopGW == 0 {
+		for _, ev := range events {
+			out.StopGW = max(out.StopGW, ev.ID)
+		}
+	}
+	out.RegularSeasonEndGW = out.StopGW - out.PlayoffRounds
+
+	// The first regular-season GW that hasn't finished.
+	firstOpen := max(out.StartGW, meta.CurrentEvent)
+	if meta.CurrentEventFinished && meta.CurrentEvent >= out.StartGW {
+		firstOpen = meta.CurrentEvent + 1
+	}
+	out.RegularSeasonGWsLeft = max(out.RegularSeasonEndGW-firstOpen+1, 0)
+
+	if out.PlayoffRounds > 0 {
+		if out.TradesEnabled {
+			out.TradeDeadlineGW = out.RegularSeasonEndGW
+			for _, ev := range events {
+				if ev.ID == out.RegularSeasonEndGW {
+					out.TradeDeadline = ev.TradesTime
+				}
+			}
+		}
+		out.LastWaiversBeforeLock = next != nil && next.ID == out.RegularSeasonEndGW
+	}
+	return out, nil
+}
+
 // gameStatusHandler is the MCP tool handler for game_status.
 func gameStatusHandler(cfg ServerConfig) func(context.Context, *mcp.CallToolRequest, GameStatusArgs) (*mcp.CallToolResult, any, error) {
 	return func(ctx context.Context, req *mcp.CallToolRequest, args GameStatusArgs) (*mcp.CallToolResult, any, error) {
-		out, err := buildGameStatus(cfg)
+		out, err := buildGameStatus(cfg.forLeague(args.LeagueID), args, cfg.now())
 		if err != nil {
 			return toolError(err), nil, nil
 		}
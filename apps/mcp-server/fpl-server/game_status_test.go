@@ -1,8 +1,10 @@
 package main
 
 import (
+	"fmt"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 // writeFullGameJSON writes game/game.json with all status fields.
@@ -54,7 +56,7 @@ func TestBuildGameStatus(t *testing.T) {
 			map[string]any{"id": 262, "event": 27, "team_h": 3, "team_a": 4, "started": true, "finished": true},
 		})
 
-		out, err := buildGameStatus(cfg)
+		out, err := buildGameStatus(cfg, GameStatusArgs{}, time.Now())
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -93,7 +95,7 @@ func TestBuildGameStatus(t *testing.T) {
 			map[string]any{"id": 284, "event": 28, "team_h": 9, "team_a": 10, "started": false, "finished": false},
 		})
 
-		out, err := buildGameStatus(cfg)
+		out, err := buildGameStatus(cfg, GameStatusArgs{}, time.Now())
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -123,7 +125,7 @@ func TestBuildGameStatus(t *testing.T) {
 			},
 		})
 
-		out, err := buildGameStatus(cfg)
+		out, err := buildGameStatus(cfg, GameStatusArgs{}, time.Now())
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -153,7 +155,7 @@ func TestBuildGameStatus(t *testing.T) {
 			map[string]any{"id": 261, "event": 27, "team_h": 1, "team_a": 2, "started": true, "finished": true},
 		})
 
-		out, err := buildGameStatus(cfg)
+		out, err := buildGameStatus(cfg, GameStatusArgs{}, time.Now())
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -164,7 +166,7 @@ func TestBuildGameStatus(t *testing.T) {
 
 	t.Run("MissingGameJSON", func(t *testing.T) {
 		_, cfg := tmpCfg(t)
-		_, err := buildGameStatus(cfg)
+		_, err := buildGameStatus(cfg, GameStatusArgs{}, time.Now())
 		if err == nil {
 			t.Fatal("expected error for missing game.json")
 		}
@@ -179,7 +181,7 @@ func TestBuildGameStatus(t *testing.T) {
 			{"id": 28, "finished": false, "deadline_time": "2026-02-27T18:30:00Z", "waivers_time": "2026-02-26T18:30:00Z", "trades_time": "2026-02-25T18:30:00Z"},
 		}, nil)
 
-		out, err := buildGameStatus(cfg)
+		out, err := buildGameStatus(cfg, GameStatusArgs{}, time.Now())
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -211,7 +213,7 @@ func TestBuildGameStatus(t *testing.T) {
 			map[string]any{"id": 281, "event": 28, "team_h": 3, "team_a": 4, "started": true, "finished": false},
 		})
 
-		out, err := buildGameStatus(cfg)
+		out, err := buildGameStatus(cfg, GameStatusArgs{}, time.Now())
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -228,4 +230,141 @@ func TestBuildGameStatus(t *testing.T) {
 			t.Errorf("points_status=%q want live", out.PointsStatus)
 		}
 	})
+
+	t.Run("LocalTimes", func(t *testing.T) {
+		dir, cfg := tmpCfg(t)
+		writeFullGameJSON(t, dir, 27, true, 28, true, "n")
+		writeBootstrapEvents(t, dir, twoEvents, map[string]any{
+			"28": []any{
+				// A zone-less kickoff is UTC and still sorts before 20:00Z.
+				map[string]any{"id": 280, "event": 28, "kickoff_time": "2026-02-27T20:00:00Z"},
+				map[string]any{"id": 281, "event": 28, "kickoff_time": "2026-02-27 12:30:00"},
+			},
+		})
+
+		now := time.Date(2026, 2, 25, 14, 0, 0, 0, time.UTC)
+		out, err := buildGameStatus(cfg, GameStatusArgs{TZ: "America/New_York"}, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if out.TZ != "America/New_York" || out.NextDeadline != "2026-02-27T18:30:00Z" {
+			t.Errorf("tz=%q next_deadline=%q, want the UTC field kept", out.TZ, out.NextDeadline)
+		}
+		want := LocalTime{Time: "2026-02-27T13:30:00-05:00", Display: "Fri 27 Feb, 1:30 PM EST", Relative: "in 2d 4h"}
+		if out.NextDeadlineLocal == nil || *out.NextDeadlineLocal != want {
+			t.Errorf("next_deadline_local = %+v, want %+v", out.NextDeadlineLocal, want)
+		}
+		if out.NextTradesDueLocal == nil || out.NextTradesDueLocal.Relative != "in 4h 30m" {
+			t.Errorf("next_trades_due_local = %+v, want in 4h 30m", out.NextTradesDueLocal)
+		}
+		if out.NextGWFirstKickoff != "2026-02-27 12:30:00" || out.NextGWFirstKickoffLocal.Display != "Fri 27 Feb, 7:30 AM EST" {
+			t.Errorf("first kickoff = %q / %+v", out.NextGWFirstKickoff, out.NextGWFirstKickoffLocal)
+		}
+
+		_, err = buildGameStatus(cfg, GameStatusArgs{TZ: "Eastern"}, now)
+		ce := classifyError(err)
+		if ce.Code != codeInvalidArgument || ce.Details["valid_examples"] == nil {
+			t.Errorf("bad tz: err = %+v", ce)
+		}
+	})
+}
+
+// TestCurrentGWFixtureProgress_DoubleGW checks that progress counts fixtures,
+// not teams: Arsenal's two matches are two fixtures and Liverpool's blank
+// adds nothing.
+func TestCurrentGWFixtureProgress_DoubleGW(t *testing.T) {
+	dir, _ := tmpCfg(t)
+	writeDGWFixture(t, dir)
+
+	progress := currentGWFixtureProgress(dir, dgwGW)
+	if progress.Total != 2 || progress.Started != 1 || progress.Finished != 1 {
+		t.Errorf("progress = %+v, want total 2, started 1, finished 1", progress)
+	}
+}
+
+func TestBuildGameStatus_LeagueSchedule(t *testing.T) {
+	events := func(from, to int) []map[string]any {
+		out := make([]map[string]any, 0, to-from+1)
+		for gw := from; gw <= to; gw++ {
+			out = append(out, map[string]any{
+				"id": gw, "finished": gw <= 35,
+				"deadline_time": fmt.Sprintf("2026-05-%02dT18:30:00Z", gw-30),
+				"trades_time":   fmt.Sprintf("2026-05-%02dT10:00:00Z", gw-30),
+			})
+		}
+		return out
+	}
+	writeSettings := func(t *testing.T, dir string, league map[string]any) {
+		t.Helper()
+		writeJSON(t, filepath.Join(dir, "league/100/details.json"), map[string]any{
+			"league": league, "league_entries": []any{}, "matches": []any{},
+		})
+	}
+
+	t.Run("NoLeague", func(t *testing.T) {
+		dir, cfg := tmpCfg(t)
+		writeFullGameJSON(t, dir, 35, true, 36, true, "n")
+		writeBootstrapEvents(t, dir, events(31, 38), nil)
+		out, err := buildGameStatus(cfg, GameStatusArgs{}, time.Now())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if out.League != nil {
+			t.Errorf("league = %+v without league_id, want nil", out.League)
+		}
+	})
+
+	t.Run("PlayoffsWithTrades", func(t *testing.T) {
+		dir, cfg := tmpCfg(t)
+		writeFullGameJSON(t, dir, 35, true, 36, true, "n")
+		writeBootstrapEvents(t, dir, events(31, 38), nil)
+		writeSettings(t, dir, map[string]any{"trades": "y", "start_event": 1, "stop_event": 38, "ko_rounds": 2})
+
+		out, err := buildGameStatus(cfg, GameStatusArgs{LeagueID: 100}, time.Now())
+		if err != nil {
+			t.Fatal(err)
+		}
+		l := out.League
+		if l == nil {
+			t.Fatal("league missing")
+		}
+		if !l.TradesEnabled || l.RegularSeasonEndGW != 36 || l.RegularSeasonGWsLeft != 1 {
+			t.Errorf("league = %+v, want trades on, regular season ending GW36 with 1 left", l)
+		}
+		if l.TradeDeadlineGW != 36 || l.TradeDeadline != "2026-05-06T10:00:00Z" {
+			t.Errorf("trade deadline = GW%d %q, want GW36 2026-05-06T10:00:00Z", l.TradeDeadlineGW, l.TradeDeadline)
+		}
+		if !l.LastWaiversBeforeLock {
+			t.Error("GW36 waivers are the last before playoffs; want last_waivers_before_playoff_lock")
+		}
+	})
+
+	t.Run("NoTradesNoPlayoffs", func(t *testing.T) {
+		dir, cfg := tmpCfg(t)
+		writeFullGameJSON(t, dir, 33, false, 34, false, "n")
+		writeBootstrapEvents(t, dir, events(31, 38), nil)
+		writeSettings(t, dir, map[string]any{"trades": "n", "start_event": 1, "stop_event": 38, "ko_rounds": 0})
+
+		out, err := buildGameStatus(cfg, GameStatusArgs{LeagueID: 100}, time.Now())
+		if err != nil {
+			t.Fatal(err)
+		}
+		l := out.League
+		if l.TradesEnabled || l.TradeDeadlineGW != 0 || l.TradeDeadline != "" || l.LastWaiversBeforeLock {
+			t.Errorf("league = %+v, want trades off and no deadline or lock", l)
+		}
+		// GW33 is in progress, so 33..38 remain.
+		if l.RegularSeasonEndGW != 38 || l.RegularSeasonGWsLeft != 6 {
+			t.Errorf("regular season end/left = %d/%d, want 38/6", l.RegularSeasonEndGW, l.RegularSeasonGWsLeft)
+		}
+	})
+
+	t.Run("MissingDetails", func(t *testing.T) {
+		dir, cfg := tmpCfg(t)
+		writeFullGameJSON(t, dir, 35, true, 36, true, "n")
+		writeBootstrapEvents(t, dir, events(31, 38), nil)
+		if _, err := buildGameStatus(cfg, GameStatusArgs{LeagueID: 100}, time.Now()); err == nil {
+			t.Error("want an error when league details are missing")
+		}
+	})
 }
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/livestats"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/summary"
+)
+
+// GameweekReportArgs are the input arguments for the gameweek_report tool.
+type GameweekReportArgs struct {
+	LeagueID int `json:"league_id" jsonschema:"Draft league id (required)"`
+	GW       int `json:"gw" jsonschema:"Gameweek (0 = current)"`
+}
+
+// StandingsMove is one manager's rank change versus the previous gameweek.
+// RankChange is positive when the manager moved up the table.
+type StandingsMove struct {
+	EntryID      int    `json:"entry_id"`
+	EntryName    string `json:"entry_name"`
+	Rank         int    `json:"rank"`
+	PreviousRank int    `json:"previous_rank,omitempty"`
+	RankChange   int    `json:"rank_change"`
+	MatchPoints  int    `json:"match_points"`
+}
+
+// WeeklyScorer is a manager's total for the gameweek.
+type WeeklyScorer struct {
+	EntryID   int    `json:"entry_id"`
+	EntryName string `json:"entry_name"`
+	Points    int    `json:"points"`
+}
+
+// WaiverPickup is a player added this gameweek and their points in it.
+type WaiverPickup struct {
+	EntryID   int    `json:"entry_id"`
+	EntryName string `json:"entry_name"`
+	Element   int    `json:"element"`
+	Name      string `json:"name"`
+	Team      string `json:"team"`
+	Kind      string `json:"kind"` // "waiver" or "free_agent"
+	Points    int    `json:"points"`
+}
+
+// BenchDecision is the manager who left the most points on the bench.
+type BenchDecision struct {
+	EntryID           int    `json:"entry_id"`
+	EntryName         string `json:"entry_name"`
+	BenchPointsPlayed int    `json:"bench_points_played"`
+	BenchPoints       int    `json:"bench_points"`
+}
+
+// GameweekReportOutput is the output of the gameweek_report tool.
+type GameweekReportOutput struct {
+	LeagueID           int                        `json:"league_id"`
+	Gameweek           int                        `json:"gameweek"`
+	Results            []summary.MatchupBreakdown `json:"results"`
+	StandingsMovement  []StandingsMove            `json:"standings_movement"`
+	HighestScorer      *WeeklyScorer              `json:"highest_scorer,omitempty"`
+	LowestScorer       *WeeklyScorer              `json:"lowest_scorer,omitempty"`
+	ClosestResult      *summary.MatchupBreakdown  `json:"closest_result,omitempty"`
+	BestWaiverPickup   *WaiverPickup              `json:"best_waiver_pickup,omitempty"`
+	WorstBenchDecision *BenchDecision             `json:"worst_bench_decision,omitempty"`
+	GWNote             *GWNote                    `json:"gw_note,omitempty"`
+}
+
+func buildGameweekReport(cfg ServerConfig, args GameweekReportArgs) (GameweekReportOutput, error) {
+	if args.LeagueID == 0 {
+		return GameweekReportOutput{}, invalidArgumentf("league_id is required")
+	}
+	gw, note, err := resolveEffectiveGW(cfg, args.GW, gwModeLatestFinished)
+	if err != nil {
+		return GameweekReportOutput{}, err
+	}
+	out := GameweekReportOutput{LeagueID: args.LeagueID, Gameweek: gw, GWNote: note}
+
+	var matchups summary.MatchupSummary
+	if err := loadSummaryInto(cfg, args.LeagueID, gw, fmt.Sprintf("summary/matchup/%d/gw/%d.json", args.LeagueID, gw), &matchups); err != nil {
+		return GameweekReportOutput{}, err
+	}
+	// Player attribution is matchup_breakdown's; the report keeps to totals.
+	for i := range matchups.Matchups {
+		matchups.Matchups[i].PlayerDetail = nil
+	}
+	out.Results = matchups.Matchups
+	out.HighestScorer, out.LowestScorer = weeklyExtremes(matchups.Matchups)
+	out.ClosestResult = closestResult(matchups.Matchups)
+
+	var standings summary.StandingsSummary
+	if err := loadSummaryInto(cfg, args.LeagueID, gw, fmt.Sprintf("summary/standings/%d/gw/%d.json", args.LeagueID, gw), &standings); err != nil {
+		return GameweekReportOutput{}, err
+	}
+	out.StandingsMovement = standingsMovement(standings)
+
+	var lineup summary.LineupEfficiencySummary
+	if err := loadSummaryInto(cfg, args.LeagueID, gw, fmt.Sprintf("summary/lineup_efficiency/%d/gw/%d.json", args.LeagueID, gw), &lineup); err != nil {
+		return GameweekReportOutput{}, err
+	}
+	out.WorstBenchDecision = worstBenchDecision(lineup)
+
+	var txs summary.TransactionsSummary
+	if err := loadSummaryInto(cfg, args.LeagueID, gw, fmt.Sprintf("summary/transactions/%d/gw/%d.json", args.LeagueID, gw), &txs); err != nil {
+		return GameweekReportOutput{}, err
+	}
+	if len(txs.Entries) > 0 {
+		elements, teamShort, _, err := loadBootstrapData(cfg.RawRoot)
+		if err != nil {
+			return GameweekReportOutput{}, err
+		}
+		live, err := loadLiveStats(cfg.RawRoot, gw)
+		if err != nil {
+			return GameweekReportOutput{}, err
+		}
+		out.BestWaiverPickup = bestWaiverPickup(txs, elements, teamShort, live)
+	}
+
+	return out, nil
+}
+
+// loadSummaryInto loads a derived summary (computing it if allowed) and
+// decodes it into v.
+func loadSummaryInto(cfg ServerConfig, leagueID int, gw int, relPath string, v any) error {
+	raw, err := loadSummaryFile(cfg, leagueID, gw, relPath, nil, nil)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
+
+func weeklyExtremes(matchups []summary.MatchupBreakdown) (*WeeklyScorer, *WeeklyScorer) {
+	var high, low *WeeklyScorer
+	consider := func(id int, name string, pts int) {
+		if id == 0 {
+			return
+		}
+		if high == nil || pts > high.Points {
+			high = &WeeklyScorer{EntryID: id, EntryName: name, Points: pts}
+		}
+		if low == nil || pts < low.Points {
+			low = &WeeklyScorer{EntryID: id, EntryName: name, Points: pts}
+		}
+	}
+	for _, m := range matchups {
+		consider(m.EntryID, m.EntryName, m.Total)
+		consider(m.OpponentID, m.OpponentName, m.OpponentTotal)
+	}
+	return high, low
+}
+
+func closestResult(matchups []summary.MatchupBreakdown) *summary.MatchupBreakdown {
+	var best *summary.MatchupBreakdown
+	bestMargin := 0
+	for i := range matchups {
+		margin := matchups[i].Total - matchups[i].OpponentTotal
+		if margin < 0 {
+			margin = -margin
+		}
+		if best == nil || margin < bestMargin {
+			best = &matchups[i]
+			bestMargin = margin
+		}
+	}
+	return best
+}
+
+// standingsMovement lists cur's rows by rank with the movement the standings
+// build recorded against the previous GW; a league's first GW has none.
+func standingsMovement(cur summary.StandingsSummary) []StandingsMove {
+	out := make([]StandingsMove, 0, len(cur.Rows))
+	for _, r := range cur.Rows {
+		out = append(out, StandingsMove{
+			EntryID:      r.EntryID,
+			EntryName:    r.EntryName,
+			Rank:         r.Rank,
+			PreviousRank: r.PrevRank,
+			RankChange:   r.RankChange,
+			MatchPoints:  r.MatchPoints,
+		})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Rank < out[j].Rank })
+	return out
+}
+
+func worstBenchDecision(lineup summary.LineupEfficiencySummary) *BenchDecision {
+	var worst *BenchDecision
+	for _, e := range lineup.Entries {
+		if e.MissingSnapshot || e.BenchPointsPlayed <= 0 {
+			continue
+		}
+		if worst == nil || e.BenchPointsPlayed > worst.BenchPointsPlayed {
+			worst = &BenchDecision{
+				EntryID:           e.EntryID,
+				EntryName:         e.EntryName,
+				BenchPointsPlayed: e.BenchPointsPlayed,
+				BenchPoints:       e.BenchPoints,
+			}
+		}
+	}
+	return worst
+}
+
+func bestWaiverPickup(txs summary.TransactionsSummary, elements []elementInfo, teamShort map[int]string, live map[int]livestats.ElementStats) *WaiverPickup {
+	byID := make(map[int]elementInfo, len(elements))
+	for _, e := range elements {
+		byID[e.ID] = e
+	}
+	var best *WaiverPickup
+	consider := func(entry summary.EntryTransactions, ids []int, kind string) {
+		for _, id := range ids {
+			pts := live[id].TotalPoints
+			if best != nil && pts <= best.Points {
+				continue
+			}
+			info := byID[id]
+			best = &WaiverPickup{
+				EntryID:   entry.EntryID,
+				EntryName: entry.EntryName,
+				Element:   id,
+				Name:      info.Name,
+				Team:      teamShort[info.TeamID],
+				Kind:      kind,
+				Points:    pts,
+			}
+		}
+	}
+	for _, e := range txs.Entries {
+		consider(e, e.WaiverIn, "waiver")
+		consider(e, e.FreeIn, "free_agent")
+	}
+	return best
+}
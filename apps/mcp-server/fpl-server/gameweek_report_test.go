@@ -0,0 +1,123 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// writeGameweekReportFixture writes precomputed GW2 summaries for league
+// 100 with three managers, and GW2 live stats for the
+// bootstrap players from writeBootstrap.
+func writeGameweekReportFixture(t *testing.T, dir string) {
+	t.Helper()
+	writeBootstrap(t, dir)
+	writeLiveJSON(t, dir, 2, map[string]any{
+		"1": map[string]any{"stats": map[string]any{"minutes": 90, "total_points": 12}},
+		"2": map[string]any{"stats": map[string]any{"minutes": 90, "total_points": 2}},
+	})
+	writeJSON(t, filepath.Join(dir, "summary/matchup/100/gw/2.json"), map[string]any{
+		"matchups": []any{
+			map[string]any{"entry_id": 1, "entry_name": "A", "opponent_entry_id": 2, "opponent_name": "B", "total": 60, "opponent_total": 58, "result": "W"},
+			map[string]any{"entry_id": 3, "entry_name": "C", "opponent_entry_id": 4, "opponent_name": "D", "total": 30, "opponent_total": 75, "result": "L"},
+		},
+	})
+	writeJSON(t, filepath.Join(dir, "summary/standings/100/gw/2.json"), map[string]any{
+		"rows": []any{
+			map[string]any{"entry_id": 4, "entry_name": "D", "rank": 2, "prev_rank": 1, "rank_change": -1},
+			map[string]any{"entry_id": 1, "entry_name": "A", "rank": 1, "prev_rank": 3, "rank_change": 2},
+		},
+	})
+	writeJSON(t, filepath.Join(dir, "summary/lineup_efficiency/100/gw/2.json"), map[string]any{
+		"entries": []any{
+			map[string]any{"entry_id": 1, "entry_name": "A", "bench_points": 8, "bench_points_played": 6},
+			map[string]any{"entry_id": 3, "entry_name": "C", "bench_points": 14, "bench_points_played": 11},
+		},
+	})
+	writeJSON(t, filepath.Join(dir, "summary/transactions/100/gw/2.json"), map[string]any{
+		"entries": []any{
+			map[string]any{"entry_id": 1, "entry_name": "A", "waiver_in": []int{2}},
+			map[string]any{"entry_id": 3, "entry_name": "C", "free_in": []int{1}},
+		},
+	})
+}
+
+func TestBuildGameweekReport(t *testing.T) {
+	dir, cfg := tmpCfg(t)
+	cfg.DerivedRoot = dir
+	writeGameweekReportFixture(t, dir)
+
+	out, err := buildGameweekReport(cfg, GameweekReportArgs{LeagueID: 100, GW: 2})
+	if err != nil {
+		t.Fatalf("buildGameweekReport: %v", err)
+	}
+
+	if len(out.Results) != 2 {
+		t.Errorf("results = %d, want 2", len(out.Results))
+	}
+	if out.HighestScorer == nil || out.HighestScorer.EntryID != 4 || out.HighestScorer.Points != 75 {
+		t.Errorf("highest scorer = %+v, want D with 75", out.HighestScorer)
+	}
+	if out.LowestScorer == nil || out.LowestScorer.EntryID != 3 {
+		t.Errorf("lowest scorer = %+v, want C", out.LowestScorer)
+	}
+	if out.ClosestResult == nil || out.ClosestResult.EntryID != 1 {
+		t.Errorf("closest result = %+v, want A vs B", out.ClosestResult)
+	}
+	if out.WorstBenchDecision == nil || out.WorstBenchDecision.EntryID != 3 || out.WorstBenchDecision.BenchPointsPlayed != 11 {
+		t.Errorf("worst bench = %+v, want C with 11", out.WorstBenchDecision)
+	}
+	if out.BestWaiverPickup == nil || out.BestWaiverPickup.Element != 1 || out.BestWaiverPickup.Points != 12 || out.BestWaiverPickup.Kind != "free_agent" {
+		t.Errorf("best pickup = %+v, want Salah (free agent, 12)", out.BestWaiverPickup)
+	}
+
+	if len(out.StandingsMovement) != 2 {
+		t.Fatalf("standings movement = %d rows, want 2", len(out.StandingsMovement))
+	}
+	top := out.StandingsMovement[0]
+	if top.EntryID != 1 || top.PreviousRank != 3 || top.RankChange != 2 {
+		t.Errorf("top row = %+v, want A up 2 from 3rd", top)
+	}
+	if out.StandingsMovement[1].RankChange != -1 {
+		t.Errorf("D rank change = %d, want -1", out.StandingsMovement[1].RankChange)
+	}
+}
+
+func TestBuildGameweekReport_MissingLeagueID(t *testing.T) {
+	_, cfg := tmpCfg(t)
+	if _, err := buildGameweekReport(cfg, GameweekReportArgs{GW: 2}); err == nil {
+		t.Fatal("expected error when league_id is missing")
+	}
+}
+
+// TestBuildGameweekReport_LeagueFirstGW: a league that started at GW3 has no
+// GW2 standings, and its first report shows no movement.
+func TestBuildGameweekReport_LeagueFirstGW(t *testing.T) {
+	dir, cfg := tmpCfg(t)
+	cfg.DerivedRoot = dir
+	writeJSON(t, filepath.Join(dir, "summary/matchup/100/gw/3.json"), map[string]any{
+		"matchups": []any{
+			map[string]any{"entry_id": 1, "entry_name": "A", "opponent_entry_id": 2, "opponent_name": "B", "total": 50, "opponent_total": 40, "result": "W"},
+		},
+	})
+	writeJSON(t, filepath.Join(dir, "summary/standings/100/gw/3.json"), map[string]any{
+		"rows": []any{
+			map[string]any{"entry_id": 1, "entry_name": "A", "rank": 1, "match_points": 3},
+			map[string]any{"entry_id": 2, "entry_name": "B", "rank": 2},
+		},
+	})
+	writeJSON(t, filepath.Join(dir, "summary/lineup_efficiency/100/gw/3.json"), map[string]any{"entries": []any{}})
+	writeJSON(t, filepath.Join(dir, "summary/transactions/100/gw/3.json"), map[string]any{"entries": []any{}})
+
+	out, err := buildGameweekReport(cfg, GameweekReportArgs{LeagueID: 100, GW: 3})
+	if err != nil {
+		t.Fatalf("buildGameweekReport: %v", err)
+	}
+	if len(out.StandingsMovement) != 2 {
+		t.Fatalf("standings movement = %+v, want 2 rows", out.StandingsMovement)
+	}
+	for _, m := range out.StandingsMovement {
+		if m.PreviousRank != 0 || m.RankChange != 0 {
+			t.Errorf("entry %d movement = %+v, want none in the league's first GW", m.EntryID, m)
+		}
+	}
+}
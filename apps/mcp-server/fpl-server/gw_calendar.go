@@ -0,0 +1,227 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+)
+
+type GWCalendarArgs struct {
+	LeagueID int  `json:"league_id" jsonschema:"Draft league id (required)"`
+	Horizon  *int `json:"horizon,omitempty" jsonschema:"GWs to cover from the current one (default rest of season)"`
+}
+
+// CalendarTeam is one Premier League team's row of the calendar. Fixtures is
+// aligned with GWCalendarOutput.GWs.
+type CalendarTeam struct {
+	TeamID   int    `json:"team_id"`
+	Team     string `json:"team"`
+	Fixtures []int  `json:"fixtures"`
+	Blanks   []int  `json:"blanks"`
+	Doubles  []int  `json:"doubles"`
+}
+
+// CalendarGW is a GW in which some teams blank or double.
+type CalendarGW struct {
+	GW    int      `json:"gw"`
+	Teams []string `json:"teams"`
+}
+
+// CalendarImpactGW counts an entry's starters who blank or double in one GW.
+type CalendarImpactGW struct {
+	GW            int      `json:"gw"`
+	Blanks        int      `json:"blanks"`
+	Doubles       int      `json:"doubles"`
+	BlankPlayers  []string `json:"blank_players,omitempty"`
+	DoublePlayers []string `json:"double_players,omitempty"`
+}
+
+// CalendarImpact is one entry's exposure to the calendar. ByGW is aligned
+// with GWCalendarOutput.GWs; Blanks and Doubles total it.
+type CalendarImpact struct {
+	EntryID   int                `json:"entry_id"`
+	EntryName string             `json:"entry_name"`
+	LineupGW  int                `json:"lineup_gw,omitempty"`
+	Starters  int                `json:"starters"`
+	Blanks    int                `json:"blanks"`
+	Doubles   int                `json:"doubles"`
+	ByGW      []CalendarImpactGW `json:"by_gw"`
+}
+
+type GWCalendarOutput struct {
+	LeagueID   int              `json:"league_id"`
+	RosterGW   int              `json:"roster_gw"`
+	FromGW     int              `json:"from_gw"`
+	ThroughGW  int              `json:"through_gw"`
+	GWs        []int            `json:"gws"`
+	UnknownGWs []int            `json:"unknown_gws,omitempty"`
+	Teams      []CalendarTeam   `json:"teams"`
+	Doubles    []CalendarGW     `json:"doubles"`
+	Blanks     []CalendarGW     `json:"blanks"`
+	Managers   []CalendarImpact `json:"managers"`
+	GWNote     *GWNote          `json:"gw_note,omitempty"`
+	Notes      []string         `json:"notes"`
+}
+
+func buildGWCalendar(cfg ServerConfig, args GWCalendarArgs) (GWCalendarOutput, error) {
+	if args.LeagueID == 0 {
+		return GWCalendarOutput{}, invalidArgumentf("league_id is required")
+	}
+	if args.Horizon != nil && *args.Horizon < 0 {
+		return GWCalendarOutput{}, invalidArgumentf("horizon must not be negative, got %d", *args.Horizon)
+	}
+
+	fromGW, note, err := resolveEffectiveGW(cfg, 0, gwModeCurrent)
+	if err != nil {
+		return GWCalendarOutput{}, err
+	}
+	asOfGW, nextGW, err := resolveAsOfAndNextGW(cfg, 0, 0)
+	if err != nil {
+		return GWCalendarOutput{}, err
+	}
+	elements, teamShort, fixturesByGW, err := loadBootstrapData(cfg.RawRoot)
+	if err != nil {
+		return GWCalendarOutput{}, err
+	}
+
+	throughGW := fromGW
+	for gw := range fixturesByGW {
+		if gw > throughGW {
+			throughGW = gw
+		}
+	}
+	if args.Horizon != nil && *args.Horizon > 0 && fromGW+*args.Horizon-1 < throughGW {
+		throughGW = fromGW + *args.Horizon - 1
+	}
+
+	out := GWCalendarOutput{
+		LeagueID:  args.LeagueID,
+		RosterGW:  resolveRosterGW(asOfGW, nextGW),
+		FromGW:    fromGW,
+		ThroughGW: throughGW,
+		GWs:       []int{},
+		Teams:     []CalendarTeam{},
+		Doubles:   []CalendarGW{},
+		Blanks:    []CalendarGW{},
+		Managers:  []CalendarImpact{},
+		GWNote:    note,
+	}
+
+	// Fixture counts per GW, then team. A GW with no fixtures at all is
+	// unknown (not yet scheduled), not a league-wide blank.
+	counts := make(map[int]map[int]int)
+	for gw := fromGW; gw <= throughGW; gw++ {
+		fx := scheduleFixtures(cfg.RawRoot, fixturesByGW, gw)
+		if len(fx) == 0 {
+			out.UnknownGWs = append(out.UnknownGWs, gw)
+			continue
+		}
+		byTeam := make(map[int]int)
+		for _, f := range fx {
+			byTeam[f.TeamH]++
+			byTeam[f.TeamA]++
+		}
+		counts[gw] = byTeam
+		out.GWs = append(out.GWs, gw)
+	}
+
+	teamIDs := make([]int, 0, len(teamShort))
+	for id := range teamShort {
+		teamIDs = append(teamIDs, id)
+	}
+	sort.Slice(teamIDs, func(i, j int) bool { return teamShort[teamIDs[i]] < teamShort[teamIDs[j]] })
+	blanksByGW := make(map[int][]string)
+	doublesByGW := make(map[int][]string)
+	for _, id := range teamIDs {
+		row := CalendarTeam{TeamID: id, Team: teamShort[id], Fixtures: make([]int, 0, len(out.GWs)), Blanks: []int{}, Doubles: []int{}}
+		for _, gw := range out.GWs {
+			n := counts[gw][id]
+			row.Fixtures = append(row.Fixtures, n)
+			switch {
+			case n == 0:
+				row.Blanks = append(row.Blanks, gw)
+				blanksByGW[gw] = append(blanksByGW[gw], row.Team)
+			case n > 1:
+				row.Doubles = append(row.Doubles, gw)
+				doublesByGW[gw] = append(doublesByGW[gw], row.Team)
+			}
+		}
+		out.Teams = append(out.Teams, row)
+	}
+	for _, gw := range out.GWs {
+		if teams := doublesByGW[gw]; len(teams) > 0 {
+			out.Doubles = append(out.Doubles, CalendarGW{GW: gw, Teams: teams})
+		}
+		if teams := blanksByGW[gw]; len(teams) > 0 {
+			out.Blanks = append(out.Blanks, CalendarGW{GW: gw, Teams: teams})
+		}
+	}
+
+	ld, _, err := loadLeagueDetails(store.NewJSONStore(cfg.RawRoot), args.LeagueID)
+	if err != nil {
+		return GWCalendarOutput{}, err
+	}
+	ownership, err := loadOwnershipAtGW(cfg, args.LeagueID, out.RosterGW)
+	if err != nil {
+		return GWCalendarOutput{}, err
+	}
+	elementByID := make(map[int]elementInfo, len(elements))
+	for _, e := range elements {
+		elementByID[e.ID] = e
+	}
+	wholeRoster := 0
+	for _, e := range ld.LeagueEntries {
+		impact := CalendarImpact{EntryID: e.EntryID, EntryName: e.EntryName, ByGW: make([]CalendarImpactGW, 0, len(out.GWs))}
+		// Starters come from the latest lineup, limited to players still on
+		// the roster; without a lineup the whole roster counts.
+		roster := ownership[e.EntryID]
+		var starters []elementInfo
+		if lineupGW, picks, err := loadLatestPicks(cfg.RawRoot, e.EntryID, nextGW); err == nil {
+			impact.LineupGW = lineupGW
+			for _, p := range picks {
+				if info, ok := elementByID[p.Element]; ok && p.Position <= 11 && roster[p.Element] {
+					starters = append(starters, info)
+				}
+			}
+		} else {
+			wholeRoster++
+			for id := range roster {
+				if info, ok := elementByID[id]; ok {
+					starters = append(starters, info)
+				}
+			}
+			sort.Slice(starters, func(i, j int) bool { return starters[i].ID < starters[j].ID })
+		}
+		impact.Starters = len(starters)
+		for _, gw := range out.GWs {
+			row := CalendarImpactGW{GW: gw}
+			for _, s := range starters {
+				switch n := counts[gw][s.TeamID]; {
+				case n == 0:
+					row.Blanks++
+					row.BlankPlayers = append(row.BlankPlayers, s.Name)
+				case n > 1:
+					row.Doubles++
+					row.DoublePlayers = append(row.DoublePlayers, s.Name)
+				}
+			}
+			impact.Blanks += row.Blanks
+			impact.Doubles += row.Doubles
+			impact.ByGW = append(impact.ByGW, row)
+		}
+		out.Managers = append(out.Managers, impact)
+	}
+
+	out.Notes = []string{
+		"Fixture counts come from bootstrap fixtures, with gw/N/live.json standing in for GWs that have started.",
+		fmt.Sprintf("Manager impact uses rosters as of GW %d and each entry's latest lineup; a starter whose team has 0 fixtures blanks and 2+ doubles.", out.RosterGW),
+	}
+	if len(out.UnknownGWs) > 0 {
+		out.Notes = append(out.Notes, fmt.Sprintf("GWs %v have no fixtures listed yet and are left out.", out.UnknownGWs))
+	}
+	if wholeRoster > 0 {
+		out.Notes = append(out.Notes, fmt.Sprintf("%d entries have no lineup on disk, so their whole roster counts as starters.", wholeRoster))
+	}
+	return out, nil
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeCalendarFixture sets up GW10 in progress (fixtures only in live.json),
+// GW11 with Arsenal doubling and City blanking, and a normal GW12. Alpha
+// starts Saka and benches Salah; Beta has no lineup on disk.
+func writeCalendarFixture(t *testing.T, dir string) {
+	t.Helper()
+	writeJSON(t, filepath.Join(dir, "bootstrap", "bootstrap-static.json"), map[string]any{
+		"elements": []any{
+			map[string]any{"id": 1, "web_name": "Saka", "team": 1, "element_type": 3, "status": "a"},
+			map[string]any{"id": 2, "web_name": "Palmer", "team": 2, "element_type": 3, "status": "a"},
+			map[string]any{"id": 3, "web_name": "Salah", "team": 3, "element_type": 3, "status": "a"},
+			map[string]any{"id": 4, "web_name": "Haaland", "team": 4, "element_type": 4, "status": "a"},
+		},
+		"teams": []any{
+			map[string]any{"id": 1, "short_name": "ARS"},
+			map[string]any{"id": 2, "short_name": "CHE"},
+			map[string]any{"id": 3, "short_name": "LIV"},
+			map[string]any{"id": 4, "short_name": "MCI"},
+		},
+		"fixtures": map[string]any{
+			"11": []any{
+				map[string]any{"id": 111, "event": 11, "team_h": 1, "team_a": 2},
+				map[string]any{"id": 112, "event": 11, "team_h": 3, "team_a": 1},
+			},
+			"12": []any{
+				map[string]any{"id": 121, "event": 12, "team_h": 1, "team_a": 4},
+				map[string]any{"id": 122, "event": 12, "team_h": 2, "team_a": 3},
+			},
+		},
+	})
+	writeFullGameJSON(t, dir, 10, false, 11, true, "")
+	writeJSON(t, filepath.Join(dir, "gw", "10", "live.json"), map[string]any{
+		"elements": map[string]any{},
+		"fixtures": []any{
+			map[string]any{"id": 101, "event": 10, "team_h": 4, "team_a": 3},
+			map[string]any{"id": 102, "event": 10, "team_h": 2, "team_a": 1},
+		},
+	})
+	writeLeagueDetailsFixture(t, dir, 100, []any{
+		map[string]any{"id": 1, "entry_id": 200, "entry_name": "Alpha FC"},
+		map[string]any{"id": 2, "entry_id": 201, "entry_name": "Beta FC"},
+	}, []any{})
+	writeJSON(t, filepath.Join(dir, "draft/100/choices.json"), map[string]any{
+		"choices": []any{
+			map[string]any{"entry": 200, "element": 1, "round": 1, "pick": 1, "index": 1},
+			map[string]any{"entry": 201, "element": 2, "round": 1, "pick": 2, "index": 2},
+			map[string]any{"entry": 200, "element": 3, "round": 2, "pick": 1, "index": 3},
+			map[string]any{"entry": 201, "element": 4, "round": 2, "pick": 2, "index": 4},
+		},
+	})
+	writeJSON(t, filepath.Join(dir, "league/100/transactions.json"), map[string]any{"transactions": []any{}})
+	writeJSON(t, filepath.Join(dir, "league/100/trades.json"), map[string]any{"trades": []any{}})
+	writeJSON(t, filepath.Join(dir, "entry/200/gw/10.json"), map[string]any{
+		"picks": []any{
+			map[string]any{"element": 1, "position": 1},
+			map[string]any{"element": 3, "position": 12},
+		},
+	})
+}
+
+func TestBuildGWCalendar(t *testing.T) {
+	dir, cfg := resourceCfg(t)
+	writeCalendarFixture(t, dir)
+
+	out, err := buildGWCalendar(cfg, GWCalendarArgs{LeagueID: 100})
+	if err != nil {
+		t.Fatalf("buildGWCalendar: %v", err)
+	}
+	if !reflect.DeepEqual(out.GWs, []int{10, 11, 12}) {
+		t.Fatalf("gws = %v, want [10 11 12] with GW10 from live.json", out.GWs)
+	}
+	want := map[string][]int{"ARS": {1, 2, 1}, "CHE": {1, 1, 1}, "LIV": {1, 1, 1}, "MCI": {1, 0, 1}}
+	if len(out.Teams) != 4 {
+		t.Fatalf("teams = %d rows, want 4", len(out.Teams))
+	}
+	for _, row := range out.Teams {
+		if !reflect.DeepEqual(row.Fixtures, want[row.Team]) {
+			t.Errorf("%s fixtures = %v, want %v", row.Team, row.Fixtures, want[row.Team])
+		}
+	}
+	if !reflect.DeepEqual(out.Doubles, []CalendarGW{{GW: 11, Teams: []string{"ARS"}}}) {
+		t.Errorf("doubles = %+v, want ARS in GW11", out.Doubles)
+	}
+	if !reflect.DeepEqual(out.Blanks, []CalendarGW{{GW: 11, Teams: []string{"MCI"}}}) {
+		t.Errorf("blanks = %+v, want MCI in GW11", out.Blanks)
+	}
+
+	if len(out.Managers) != 2 {
+		t.Fatalf("managers = %d, want 2", len(out.Managers))
+	}
+	alpha, beta := out.Managers[0], out.Managers[1]
+	// Salah is benched, so only Saka's double counts.
+	if alpha.Starters != 1 || alpha.Doubles != 1 || alpha.Blanks != 0 || alpha.LineupGW != 10 {
+		t.Errorf("alpha = %+v, want 1 starter with 1 double", alpha)
+	}
+	if got := alpha.ByGW[1]; got.GW != 11 || !reflect.DeepEqual(got.DoublePlayers, []string{"Saka"}) {
+		t.Errorf("alpha GW11 = %+v, want Saka doubling", got)
+	}
+	// No lineup: the whole roster counts, and Haaland blanks in GW11.
+	if beta.Starters != 2 || beta.Blanks != 1 || beta.Doubles != 0 {
+		t.Errorf("beta = %+v, want 2 starters with 1 blank", beta)
+	}
+
+	h := 2
+	short, err := buildGWCalendar(cfg, GWCalendarArgs{LeagueID: 100, Horizon: &h})
+	if err != nil {
+		t.Fatalf("horizon 2: %v", err)
+	}
+	if short.ThroughGW != 11 || !reflect.DeepEqual(short.GWs, []int{10, 11}) {
+		t.Errorf("horizon 2 = through %d gws %v, want through 11", short.ThroughGW, short.GWs)
+	}
+}
+
+func TestBuildGWCalendar_BadArgs(t *testing.T) {
+	_, cfg := resourceCfg(t)
+	if _, err := buildGWCalendar(cfg, GWCalendarArgs{}); classifyError(err).Code != codeInvalidArgument {
+		t.Errorf("missing league_id: err = %v, want INVALID_ARGUMENT", err)
+	}
+	h := -1
+	if _, err := buildGWCalendar(cfg, GWCalendarArgs{LeagueID: 1, Horizon: &h}); classifyError(err).Code != codeInvalidArgument {
+		t.Errorf("negative horizon: err = %v, want INVALID_ARGUMENT", err)
+	}
+}
@@ -16,6 +16,7 @@ type HeadToHeadArgs struct {
 	EntryNameA *string `json:"entry_name_a,omitempty" jsonschema:"First team name (if entry_id_a not provided)"`
 	EntryIDB   *int    `json:"entry_id_b,omitempty" jsonschema:"Second team entry id"`
 	EntryNameB *string `json:"entry_name_b,omitempty" jsonschema:"Second team name (if entry_id_b not provided)"`
+	Season     string  `json:"season,omitempty" jsonschema:"Season label like 2024-25 for an archived season (default current)"`
 }
 
 // H2HMatch describes a single match between the two teams.
@@ -46,7 +47,11 @@ type HeadToHeadOutput struct {
 
 func buildHeadToHead(cfg ServerConfig, args HeadToHeadArgs) (HeadToHeadOutput, error) {
 	if args.LeagueID == 0 {
-		return HeadToHeadOutput{}, fmt.Errorf("league_id is required")
+		return HeadToHeadOutput{}, invalidArgumentf("league_id is required")
+	}
+	cfg, err := cfg.forSeason(args.Season)
+	if err != nil {
+		return HeadToHeadOutput{}, err
 	}
 
 	path := filepath.Join(cfg.RawRoot, fmt.Sprintf("league/%d/details.json", args.LeagueID))
@@ -68,27 +73,25 @@ func buildHeadToHead(cfg ServerConfig, args HeadToHeadArgs) (HeadToHeadOutput, e
 		leagueEntryByEntry[e.EntryID] = e.ID
 	}
 
-	resolveEntry := func(id *int, name *string, label string) (int, error) {
+	resolveSide := func(id *int, name *string, label string) (int, error) {
 		if id != nil && *id != 0 {
 			return *id, nil
 		}
 		if name == nil || strings.TrimSpace(*name) == "" {
-			return 0, fmt.Errorf("%s: entry_id or entry_name is required", label)
+			return 0, invalidArgumentf("%s: entry_id or entry_name is required", label)
 		}
-		n := strings.TrimSpace(*name)
-		for _, e := range details.LeagueEntries {
-			if strings.EqualFold(e.EntryName, n) || strings.EqualFold(e.ShortName, n) {
-				return e.EntryID, nil
-			}
+		entryID, err := resolveEntry(details.LeagueEntries, *name)
+		if err != nil {
+			return 0, fmt.Errorf("%s: %w", label, err)
 		}
-		return 0, fmt.Errorf("%s: no entry found for name: %s", label, n)
+		return entryID, nil
 	}
 
-	entryIDA, err := resolveEntry(args.EntryIDA, args.EntryNameA, "team_a")
+	entryIDA, err := resolveSide(args.EntryIDA, args.EntryNameA, "team_a")
 	if err != nil {
 		return HeadToHeadOutput{}, err
 	}
-	entryIDB, err := resolveEntry(args.EntryIDB, args.EntryNameB, "team_b")
+	entryIDB, err := resolveSide(args.EntryIDB, args.EntryNameB, "team_b")
 	if err != nil {
 		return HeadToHeadOutput{}, err
 	}
@@ -96,10 +99,10 @@ func buildHeadToHead(cfg ServerConfig, args HeadToHeadArgs) (HeadToHeadOutput, e
 	leagueEntryIDA := leagueEntryByEntry[entryIDA]
 	leagueEntryIDB := leagueEntryByEntry[entryIDB]
 	if leagueEntryIDA == 0 {
-		return HeadToHeadOutput{}, fmt.Errorf("team_a not found: %d", entryIDA)
+		return HeadToHeadOutput{}, notFoundf("team_a not found: %d", entryIDA)
 	}
 	if leagueEntryIDB == 0 {
-		return HeadToHeadOutput{}, fmt.Errorf("team_b not found: %d", entryIDB)
+		return HeadToHeadOutput{}, notFoundf("team_b not found: %d", entryIDB)
 	}
 
 	recordA := H2HTeamRecord{EntryID: entryIDA, EntryName: nameByEntry[entryIDA]}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/ledger"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+)
+
+// HistoricalRosterArgs are the input arguments for the historical_roster tool.
+type HistoricalRosterArgs struct {
+	LeagueID  int     `json:"league_id" jsonschema:"Draft league id (required)"`
+	EntryID   *int    `json:"entry_id,omitempty" jsonschema:"Entry id"`
+	EntryName *string `json:"entry_name,omitempty" jsonschema:"Entry name (if entry_id not provided)"`
+	GW        *int    `json:"gw,omitempty" jsonschema:"Gameweek (0 = latest with results)"`
+}
+
+// HistoricalPick is one player in an entry's lineup for a past gameweek.
+type HistoricalPick struct {
+	Element      int    `json:"element"`
+	Name         string `json:"name"`
+	Team         string `json:"team"`
+	PositionType int    `json:"position_type"`
+	PositionSlot int    `json:"position_slot"`
+	OnBench      bool   `json:"on_bench"`
+	Minutes      int    `json:"minutes"`
+	Points       int    `json:"points"`
+}
+
+// HistoricalSub is an automatic substitution made in the gameweek.
+type HistoricalSub struct {
+	ElementIn  int    `json:"element_in"`
+	NameIn     string `json:"name_in"`
+	ElementOut int    `json:"element_out"`
+	NameOut    string `json:"name_out"`
+}
+
+// HistoricalRosterOutput is the output of the historical_roster tool.
+type HistoricalRosterOutput struct {
+	LeagueID       int              `json:"league_id"`
+	EntryID        int              `json:"entry_id"`
+	EntryName      string           `json:"entry_name"`
+	Gameweek       int              `json:"gameweek"`
+	StartersPoints int              `json:"starters_points"` // as picked, before auto subs
+	BenchPoints    int              `json:"bench_points"`
+	Starters       []HistoricalPick `json:"starters"`
+	Bench          []HistoricalPick `json:"bench"`
+	AutoSubs       []HistoricalSub  `json:"auto_subs"`
+	GWNote         *GWNote          `json:"gw_note,omitempty"`
+	// PlayerNamesUnavailable is playerNames.Unavailable.
+	PlayerNamesUnavailable bool `json:"player_names_unavailable,omitempty"`
+}
+
+// loadEntrySnapshot reads the derived snapshot for entryID at gw, building it
+// from the raw entry event when it is missing.
+func loadEntrySnapshot(cfg ServerConfig, leagueID int, entryID int, gw int) (ledger.EntrySnapshot, error) {
+	st := store.NewJSONStore(cfg.RawRoot)
+	relPath := fmt.Sprintf("snapshots/%d/entry/%d/gw/%d.json", leagueID, entryID, gw)
+	raw, err := loadDerivedFile(cfg, relPath, func(root string) error {
+		return withRawBackfill(cfg, leagueID, rawNeed{FromGW: gw, ToGW: gw, EntryIDs: []int{entryID}}, func() error {
+			return ensureSnapshots(st, root, leagueID, []int{entryID}, gw, gw, cfg.Clock)
+		})
+	})
+	if err != nil {
+		return ledger.EntrySnapshot{}, err
+	}
+	var snap ledger.EntrySnapshot
+	if err := json.Unmarshal(raw, &snap); err != nil {
+		return ledger.EntrySnapshot{}, err
+	}
+	return snap, nil
+}
+
+func buildHistoricalRoster(cfg ServerConfig, args HistoricalRosterArgs) (HistoricalRosterOutput, error) {
+	if args.LeagueID == 0 {
+		return HistoricalRosterOutput{}, invalidArgumentf("league_id is required")
+	}
+	requested := 0
+	if args.GW != nil {
+		requested = *args.GW
+	}
+	gw, note, err := resolveEffectiveGW(cfg, requested, gwModeLatestFinished)
+	if err != nil {
+		return HistoricalRosterOutput{}, err
+	}
+
+	st := store.NewJSONStore(cfg.RawRoot)
+	raw, err := st.ReadRaw(fmt.Sprintf("league/%d/details.json", args.LeagueID))
+	if err != nil {
+		return HistoricalRosterOutput{}, err
+	}
+	var details leagueDetailsRaw
+	if err := json.Unmarshal(raw, &details); err != nil {
+		return HistoricalRosterOutput{}, err
+	}
+	if start := details.League.StartEvent; gw < start {
+		return HistoricalRosterOutput{}, invalidArgumentf("league %d starts at GW %d; GW %d is before it", args.LeagueID, start, gw)
+	}
+	nameByEntry := make(map[int]string, len(details.LeagueEntries))
+	for _, e := range details.LeagueEntries {
+		nameByEntry[e.EntryID] = e.EntryName
+	}
+
+	entryID := 0
+	if args.EntryID != nil {
+		entryID = *args.EntryID
+	}
+	if entryID == 0 {
+		name := ""
+		if args.EntryName != nil {
+			name = strings.TrimSpace(*args.EntryName)
+		}
+		if name == "" {
+			return HistoricalRosterOutput{}, invalidArgumentf("entry_id or entry_name is required")
+		}
+		id, err := resolveEntry(details.LeagueEntries, name)
+		if err != nil {
+			return HistoricalRosterOutput{}, err
+		}
+		entryID = id
+	}
+	entryName, ok := nameByEntry[entryID]
+	if !ok {
+		return HistoricalRosterOutput{}, notFoundf("entry not found: %d", entryID)
+	}
+
+	snap, err := loadEntrySnapshot(cfg, args.LeagueID, entryID, gw)
+	if err != nil {
+		return HistoricalRosterOutput{}, err
+	}
+	live, err := loadLiveStats(cfg.RawRoot, gw)
+	if err != nil {
+		return HistoricalRosterOutput{}, err
+	}
+	players, err := loadPlayerNames(cfg.RawRoot)
+	if err != nil {
+		return HistoricalRosterOutput{}, err
+	}
+
+	out := HistoricalRosterOutput{
+		LeagueID:               args.LeagueID,
+		EntryID:                entryID,
+		EntryName:              entryName,
+		Gameweek:               gw,
+		Starters:               make([]HistoricalPick, 0, 11),
+		Bench:                  make([]HistoricalPick, 0, 4),
+		AutoSubs:               make([]HistoricalSub, 0, len(snap.Subs)),
+		GWNote:                 note,
+		PlayerNamesUnavailable: players.Unavailable,
+	}
+	for _, p := range snap.Picks {
+		meta, _ := players.get(p.Element)
+		stats := live[p.Element]
+		pick := HistoricalPick{
+			Element:      p.Element,
+			Name:         meta.Name,
+			Team:         players.team(meta.TeamID),
+			PositionType: meta.PositionType,
+			PositionSlot: p.Position,
+			OnBench:      p.Position > 11,
+			Minutes:      stats.Minutes,
+			Points:       stats.TotalPoints,
+		}
+		if pick.OnBench {
+			out.Bench = append(out.Bench, pick)
+			out.BenchPoints += pick.Points
+		} else {
+			out.Starters = append(out.Starters, pick)
+			out.StartersPoints += pick.Points
+		}
+	}
+	for _, s := range snap.Subs {
+		in, _ := players.get(s.ElementIn)
+		dropped, _ := players.get(s.ElementOut)
+		out.AutoSubs = append(out.AutoSubs, HistoricalSub{
+			ElementIn:  s.ElementIn,
+			NameIn:     in.Name,
+			ElementOut: s.ElementOut,
+			NameOut:    dropped.Name,
+		})
+	}
+	return out, nil
+}
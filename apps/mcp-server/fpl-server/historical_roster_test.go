@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+)
+
+func TestBuildHistoricalRoster(t *testing.T) {
+	dir, cfg := tmpCfg(t)
+	cfg.DerivedRoot = filepath.Join(dir, "derived")
+	cfg.ComputeMissing = true
+	cfg.WriteDerived = true
+	writeBootstrap(t, dir)
+	writeFullGameJSON(t, dir, 13, false, 14, false, "")
+	writeLeagueDetailsFixture(t, dir, 100, []any{
+		map[string]any{"id": 1, "entry_id": 200, "entry_name": "Alpha FC", "short_name": "AFC"},
+	}, []any{})
+	writeJSON(t, filepath.Join(dir, "entry/200/gw/12.json"), map[string]any{
+		"picks": []any{
+			map[string]any{"element": 1, "position": 1},
+			map[string]any{"element": 3, "position": 2},
+			map[string]any{"element": 2, "position": 12},
+		},
+		"subs": []any{map[string]any{"element_in": 2, "element_out": 3, "event": 12}},
+	})
+	writeLiveJSON(t, dir, 12, map[string]any{
+		"1": map[string]any{"stats": map[string]any{"minutes": 90, "total_points": 12}},
+		"2": map[string]any{"stats": map[string]any{"minutes": 90, "total_points": 8}},
+		"3": map[string]any{"stats": map[string]any{"minutes": 0, "total_points": 0}},
+	})
+
+	// GW13 has not kicked off, so gw=0 falls back to GW12.
+	name := "AFC"
+	out, err := buildHistoricalRoster(cfg, HistoricalRosterArgs{LeagueID: 100, EntryName: &name})
+	if err != nil {
+		t.Fatalf("buildHistoricalRoster: %v", err)
+	}
+	if out.Gameweek != 12 || out.GWNote == nil {
+		t.Errorf("gameweek = %d note = %+v, want 12 with a note", out.Gameweek, out.GWNote)
+	}
+	if len(out.Starters) != 2 || len(out.Bench) != 1 {
+		t.Fatalf("starters=%d bench=%d, want 2 and 1", len(out.Starters), len(out.Bench))
+	}
+	if out.Starters[0].Name != "Salah" || out.Starters[0].Points != 12 {
+		t.Errorf("first starter = %+v", out.Starters[0])
+	}
+	if out.StartersPoints != 12 || out.BenchPoints != 8 {
+		t.Errorf("starters=%d bench=%d points, want 12 and 8", out.StartersPoints, out.BenchPoints)
+	}
+	if len(out.AutoSubs) != 1 || out.AutoSubs[0].NameIn != "Haaland" {
+		t.Errorf("auto subs = %+v", out.AutoSubs)
+	}
+	if _, err := os.Stat(filepath.Join(cfg.DerivedRoot, "snapshots/100/entry/200/gw/12.json")); err != nil {
+		t.Errorf("snapshot not written with WriteDerived=true: %v", err)
+	}
+
+	if _, err := buildHistoricalRoster(cfg, HistoricalRosterArgs{LeagueID: 100}); classifyError(err).Code != codeInvalidArgument {
+		t.Errorf("no entry: err = %v, want INVALID_ARGUMENT", err)
+	}
+}
+
+func TestLeagueStartEvent(t *testing.T) {
+	dir, cfg := tmpCfg(t)
+	cfg.DerivedRoot = filepath.Join(dir, "derived")
+	cfg.ComputeMissing = true
+	writeBootstrap(t, dir)
+	writeFullGameJSON(t, dir, 11, true, 12, false, "")
+	writeJSON(t, filepath.Join(dir, "league/100/details.json"), map[string]any{
+		"league": map[string]any{"start_event": 10},
+		"league_entries": []any{
+			map[string]any{"id": 1, "entry_id": 200, "entry_name": "Alpha FC", "short_name": "AFC"},
+		},
+		"matches": []any{},
+	})
+	// Only GW10 onwards exist on disk, as for a league formed mid-season.
+	for _, gw := range []int{10, 11} {
+		writeJSON(t, filepath.Join(dir, fmt.Sprintf("entry/200/gw/%d.json", gw)), map[string]any{
+			"picks": []any{map[string]any{"element": 1, "position": 1}},
+		})
+	}
+
+	st := store.NewJSONStore(dir)
+	if err := ensureSnapshots(st, cfg.DerivedRoot, 100, []int{200}, 1, 11, cfg.Clock); err != nil {
+		t.Fatalf("ensureSnapshots read GWs before the league start: %v", err)
+	}
+	for gw, want := range map[int]bool{9: false, 10: true, 11: true} {
+		_, err := os.Stat(filepath.Join(cfg.DerivedRoot, fmt.Sprintf("snapshots/100/entry/200/gw/%d.json", gw)))
+		if (err == nil) != want {
+			t.Errorf("GW%d snapshot exists = %v, want %v", gw, err == nil, want)
+		}
+	}
+
+	entries, err := buildLeagueEntries(cfg, 100)
+	if err != nil {
+		t.Fatalf("buildLeagueEntries: %v", err)
+	}
+	if entries.StartGW != 10 {
+		t.Errorf("start_gw = %d, want 10", entries.StartGW)
+	}
+
+	name := "AFC"
+	gw := 5
+	if _, err := buildHistoricalRoster(cfg, HistoricalRosterArgs{LeagueID: 100, EntryName: &name, GW: &gw}); classifyError(err).Code != codeInvalidArgument {
+		t.Errorf("GW before start: err = %v, want INVALID_ARGUMENT", err)
+	}
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	// A finished GW's summaries never change, so clients may keep them for a
+	// day. Anything for the current GW can move with live data.
+	summaryMaxAgeFinished = 24 * time.Hour
+	summaryMaxAgeLive     = 60 * time.Second
+)
+
+// summaryFile is a derived summary together with where it was read from.
+// Path may point into a temporary directory that is already gone when
+// derived writes are disabled.
+type summaryFile struct {
+	Bytes   []byte
+	Path    string
+	ModTime time.Time
+	Hash    string // hex sha256 of Bytes
+}
+
+func newSummaryFile(path string, b []byte) summaryFile {
+	sum := sha256.Sum256(b)
+	f := summaryFile{Bytes: b, Path: path, Hash: hex.EncodeToString(sum[:])}
+	if info, err := store.StatDerived(path); err == nil {
+		f.ModTime = info.ModTime()
+	}
+	return f
+}
+
+// ETag is a strong entity tag built from the content hash, so it changes
+// whenever the file's bytes do and survives a rebuild that writes the same
+// content.
+func (f summaryFile) ETag() string {
+	return `"` + f.Hash + `"`
+}
+
+// summaryMaxAge picks a Cache-Control max-age for target's summary: a day
+// once its GW is finished, a minute otherwise or when game.json can't be
+// read. An alias always gets the minute: before kickoff "current" resolves to
+// the finished GW, and a day-long cache would keep serving that after the
+// next GW starts.
+func summaryMaxAge(cfg ServerConfig, target resourceTarget) time.Duration {
+	if target.Alias {
+		return summaryMaxAgeLive
+	}
+	gw := target.GW
+	meta, err := loadGameStatusMeta(cfg)
+	if err != nil {
+		return summaryMaxAgeLive
+	}
+	if gw < meta.CurrentEvent || (gw == meta.CurrentEvent && meta.CurrentEventFinished) {
+		return summaryMaxAgeFinished
+	}
+	return summaryMaxAgeLive
+}
+
+// etagMatches reports whether an If-None-Match header value matches etag.
+// Weak comparison applies, as RFC 9110 requires for If-None-Match.
+func etagMatches(header string, etag string) bool {
+	for _, part := range strings.Split(header, ",") {
+		part = strings.TrimSpace(part)
+		if part == "*" || strings.TrimPrefix(part, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// serveSummaryFile writes f with ETag, Last-Modified, and Cache-Control
+// headers, or a bare 304 when the request's If-None-Match already matches.
+func serveSummaryFile(w http.ResponseWriter, r *http.Request, f summaryFile, maxAge time.Duration) {
+	etag := f.ETag()
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", fmt.Sprintf("private, max-age=%d", int(maxAge.Seconds())))
+	if !f.ModTime.IsZero() {
+		w.Header().Set("Last-Modified", f.ModTime.UTC().Format(http.TimeFormat))
+	}
+	if inm := r.Header.Get("If-None-Match"); inm != "" && etagMatches(inm, etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", resourceMIMEType)
+	w.Write(f.Bytes)
+}
+
+// httpStatusFor maps an error onto the HTTP status for plain HTTP routes.
+func httpStatusFor(err error) int {
+	var rpcErr *jsonrpc.Error
+	if errors.As(err, &rpcErr) && rpcErr.Code == mcp.CodeResourceNotFound {
+		return http.StatusNotFound
+	}
+	switch classifyError(err).Code {
+	case codeInvalidArgument:
+		return http.StatusBadRequest
+	case codeNotFound, codeDataMissing:
+		return http.StatusNotFound
+	case codeStaleData:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// resourceHTTPHandler serves GET /resources?uri=<resource uri>, the plain HTTP
+// counterpart of resources/read, with conditional GET support so polling
+// clients only re-download a summary when it changes.
+func resourceHTTPHandler(cfg ServerConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, `{"error":"method not allowed"}`, http.StatusMethodNotAllowed)
+			return
+		}
+		fail := func(err error) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(httpStatusFor(err))
+			w.Write(marshalToolError(err))
+		}
+		uri := r.URL.Query().Get("uri")
+		if uri == "" {
+			fail(invalidArgumentf("uri query parameter is required"))
+			return
+		}
+		target, err := resolveResource(cfg, uri)
+		if err != nil {
+			fail(err)
+			return
+		}
+		f, err := loadSummaryFileWithMeta(cfg, target.LeagueID, target.GW, target.RelPath, target.Horizons, nil)
+		if err != nil {
+			fail(err)
+			return
+		}
+		serveSummaryFile(w, r, f, summaryMaxAge(cfg.forLeague(target.LeagueID), target))
+	}
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func getResource(t *testing.T, h http.HandlerFunc, uri string, ifNoneMatch string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/resources?uri="+url.QueryEscape(uri), nil)
+	if ifNoneMatch != "" {
+		req.Header.Set("If-None-Match", ifNoneMatch)
+	}
+	rec := httptest.NewRecorder()
+	h(rec, req)
+	return rec
+}
+
+func TestResourceHTTPHandler_ConditionalGET(t *testing.T) {
+	dir, cfg := resourceCfg(t)
+	writeFullGameJSON(t, dir, 8, false, 9, false, "")
+	writeLiveJSON(t, dir, 7, map[string]any{})
+	writeLiveJSON(t, dir, 8, map[string]any{})
+	summaryPath := filepath.Join(dir, "summary/league/42/gw/3.json")
+	writeJSON(t, summaryPath, map[string]any{"gw": 3, "rev": 1})
+	h := resourceHTTPHandler(cfg)
+
+	rec := getResource(t, h, "league-summary://42/gw/3", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+	etag := rec.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("missing ETag")
+	}
+	if cc := rec.Header().Get("Cache-Control"); cc != "private, max-age=86400" {
+		t.Errorf("Cache-Control = %q, want a day for finished GW3", cc)
+	}
+	if rec.Header().Get("Last-Modified") == "" {
+		t.Error("missing Last-Modified")
+	}
+
+	rec = getResource(t, h, "league-summary://42/gw/3", etag)
+	if rec.Code != http.StatusNotModified || rec.Body.Len() != 0 {
+		t.Errorf("If-None-Match: status = %d body %q, want empty 304", rec.Code, rec.Body.String())
+	}
+	if rec = getResource(t, h, "league-summary://42/gw/3", `"other", W/`+etag); rec.Code != http.StatusNotModified {
+		t.Errorf("weak etag in list: status = %d, want 304", rec.Code)
+	}
+
+	// Rewriting the summary changes the ETag, so the old tag no longer matches.
+	writeJSON(t, summaryPath, map[string]any{"gw": 3, "rev": 2})
+	rec = getResource(t, h, "league-summary://42/gw/3", etag)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("after rewrite: status = %d, want 200", rec.Code)
+	}
+	if got := rec.Header().Get("ETag"); got == etag || got == "" {
+		t.Errorf("ETag after rewrite = %q, want a new tag", got)
+	}
+}
+
+func TestResourceHTTPHandler_LiveMaxAge(t *testing.T) {
+	dir, cfg := resourceCfg(t)
+	writeFullGameJSON(t, dir, 8, false, 9, false, "")
+	writeLiveJSON(t, dir, 8, map[string]any{})
+	writeJSON(t, filepath.Join(dir, "summary/fixtures/42/from_gw/8_h5.json"), map[string]any{"from_gw": 8})
+
+	rec := getResource(t, resourceHTTPHandler(cfg), "fixtures://42/next5", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body.String())
+	}
+	if cc := rec.Header().Get("Cache-Control"); cc != "private, max-age=60" {
+		t.Errorf("Cache-Control = %q, want 60s for the in-progress GW", cc)
+	}
+}
+
+func TestResourceHTTPHandler_AliasBeforeKickoff(t *testing.T) {
+	dir, cfg := resourceCfg(t)
+	// GW9 is current but hasn't kicked off, so "current" is finished GW8.
+	writeFullGameJSON(t, dir, 9, false, 10, false, "")
+	writeLiveJSON(t, dir, 8, map[string]any{})
+	writeJSON(t, filepath.Join(dir, "summary/standings/42/gw/8.json"), map[string]any{"gw": 8})
+	writeJSON(t, filepath.Join(dir, "summary/league/42/gw/8.json"), map[string]any{"gw": 8})
+	h := resourceHTTPHandler(cfg)
+
+	for _, uri := range []string{"standings://42/current", "league-summary://42/gw/0"} {
+		rec := getResource(t, h, uri, "")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d: %s", uri, rec.Code, rec.Body.String())
+		}
+		if cc := rec.Header().Get("Cache-Control"); cc != "private, max-age=60" {
+			t.Errorf("%s: Cache-Control = %q, want 60s since the alias moves at kickoff", uri, cc)
+		}
+	}
+	// The same GW named explicitly never changes.
+	if cc := getResource(t, h, "league-summary://42/gw/8", "").Header().Get("Cache-Control"); cc != "private, max-age=86400" {
+		t.Errorf("explicit GW8: Cache-Control = %q, want a day", cc)
+	}
+}
+
+func TestResourceHTTPHandler_GzippedSummary(t *testing.T) {
+	dir, cfg := resourceCfg(t)
+	writeFullGameJSON(t, dir, 8, false, 9, false, "")
+	writeLiveJSON(t, dir, 8, map[string]any{})
+	body := []byte(`{"gw":3}` + "\n")
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(body); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "summary/league/42/gw/3.json.gz")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	rec := getResource(t, resourceHTTPHandler(cfg), "league-summary://42/gw/3", "")
+	if rec.Code != http.StatusOK || rec.Body.String() != string(body) {
+		t.Fatalf("status = %d body %q, want the decompressed summary", rec.Code, rec.Body.String())
+	}
+	if want := newSummaryFile("", body).ETag(); rec.Header().Get("ETag") != want {
+		t.Errorf("ETag = %q, want %q (hash of the decompressed bytes)", rec.Header().Get("ETag"), want)
+	}
+	if rec.Header().Get("Last-Modified") == "" {
+		t.Error("missing Last-Modified for a .gz summary")
+	}
+}
+
+func TestResourceHTTPHandler_Errors(t *testing.T) {
+	dir, cfg := resourceCfg(t)
+	writeFullGameJSON(t, dir, 8, false, 9, false, "")
+	writeLiveJSON(t, dir, 8, map[string]any{})
+	h := resourceHTTPHandler(cfg)
+
+	cases := []struct {
+		uri  string
+		want int
+	}{
+		{"", http.StatusBadRequest},
+		{"unknown://42/current", http.StatusNotFound},
+		{"league-summary://42/gw/x", http.StatusBadRequest},
+		{"league-summary://42/gw/3", http.StatusNotFound}, // no derived file, compute disabled
+	}
+	for _, c := range cases {
+		if rec := getResource(t, h, c.uri, ""); rec.Code != c.want {
+			t.Errorf("%q: status = %d, want %d", c.uri, rec.Code, c.want)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	h(rec, httptest.NewRequest(http.MethodPost, "/resources", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST: status = %d, want 405", rec.Code)
+	}
+}
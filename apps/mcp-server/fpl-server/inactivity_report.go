@@ -0,0 +1,399 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/ledger"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/summary"
+)
+
+// Inactivity signals, with the weight each adds to a manager's severity.
+// A lineup left untouched and a player left in the XI while out are the
+// strongest signs; no transactions or one dead starter happen to active
+// managers too.
+const (
+	signalNoTransactions     = "no_transactions"
+	signalZeroMinuteStarters = "zero_minute_starters"
+	signalUnchangedLineup    = "unchanged_lineup"
+	signalUnavailableInXI    = "unavailable_in_xi"
+)
+
+var inactivityWeights = map[string]int{
+	signalNoTransactions:     2,
+	signalZeroMinuteStarters: 2,
+	signalUnchangedLineup:    3,
+	signalUnavailableInXI:    3,
+}
+
+// Inactivity levels in ManagerInactivity.Level, by severity.
+const (
+	inactivityNone   = "active"
+	inactivityLow    = "low"
+	inactivityMedium = "medium"
+	inactivityHigh   = "high"
+)
+
+// InactivityReportArgs are the input arguments for the inactivity_report
+// tool. Every threshold is a count of GWs within the window.
+type InactivityReportArgs struct {
+	LeagueID       int  `json:"league_id" jsonschema:"Draft league id (required)"`
+	GW             int  `json:"gw,omitempty" jsonschema:"Last gameweek to examine (0 = latest finished)"`
+	Window         *int `json:"window,omitempty" jsonschema:"GWs to examine, ending at gw (default 4); no transactions in the whole window is a signal"`
+	ZeroMinuteGWs  *int `json:"zero_minute_gws,omitempty" jsonschema:"Consecutive GWs with a 0-minute starter to flag (default 2)"`
+	UnchangedGWs   *int `json:"unchanged_gws,omitempty" jsonschema:"Consecutive GWs with an identical lineup to flag (default 3)"`
+	UnavailableGWs *int `json:"unavailable_gws,omitempty" jsonschema:"Consecutive GWs the same player starts without playing to flag (default 2)"`
+}
+
+// InactivityThresholds are the thresholds a report was run with.
+type InactivityThresholds struct {
+	Window         int `json:"window"`
+	ZeroMinuteGWs  int `json:"zero_minute_gws"`
+	UnchangedGWs   int `json:"unchanged_gws"`
+	UnavailableGWs int `json:"unavailable_gws"`
+}
+
+// InactivitySignal is one signal a manager tripped, with its evidence.
+type InactivitySignal struct {
+	Signal   string   `json:"signal"`
+	Weight   int      `json:"weight"`
+	GWs      []int    `json:"gws,omitempty"`
+	Players  []string `json:"players,omitempty"`
+	Evidence string   `json:"evidence"`
+}
+
+// ManagerInactivity is one manager's inactivity signals. Severity sums the
+// signals' weights.
+type ManagerInactivity struct {
+	EntryID   int                `json:"entry_id"`
+	EntryName string             `json:"entry_name"`
+	Severity  int                `json:"severity"`
+	Level     string             `json:"level"`
+	Signals   []InactivitySignal `json:"signals"`
+}
+
+// InactivityReportOutput is the output of the inactivity_report tool.
+// Managers are ordered most inactive first.
+type InactivityReportOutput struct {
+	LeagueID   int                  `json:"league_id"`
+	FromGW     int                  `json:"from_gw"`
+	ThroughGW  int                  `json:"through_gw"`
+	Thresholds InactivityThresholds `json:"thresholds"`
+	Managers   []ManagerInactivity  `json:"managers"`
+	GWNote     *GWNote              `json:"gw_note,omitempty"`
+	Notes      []string             `json:"notes,omitempty"`
+}
+
+// entryWeek is what the report reads about one entry in one GW. A GW
+// without a usable snapshot has no lineup and breaks every run.
+type entryWeek struct {
+	gw          int
+	lineup      string
+	hasLineup   bool
+	zeroMinutes []int
+	hasLineupEf bool
+}
+
+func buildInactivityReport(cfg ServerConfig, args InactivityReportArgs) (InactivityReportOutput, error) {
+	if args.LeagueID == 0 {
+		return InactivityReportOutput{}, invalidArgumentf("league_id is required")
+	}
+	th := InactivityThresholds{Window: 4, ZeroMinuteGWs: 2, UnchangedGWs: 3, UnavailableGWs: 2}
+	for _, a := range []struct {
+		arg *int
+		dst *int
+	}{{args.Window, &th.Window}, {args.ZeroMinuteGWs, &th.ZeroMinuteGWs}, {args.UnchangedGWs, &th.UnchangedGWs}, {args.UnavailableGWs, &th.UnavailableGWs}} {
+		if a.arg != nil && *a.arg > 0 {
+			*a.dst = *a.arg
+		}
+	}
+
+	throughGW, note, err := resolveEffectiveGW(cfg, args.GW, gwModeLatestFinished)
+	if err != nil {
+		return InactivityReportOutput{}, err
+	}
+	st := store.NewJSONStore(cfg.RawRoot)
+	raw, err := st.ReadRaw(fmt.Sprintf("league/%d/details.json", args.LeagueID))
+	if err != nil {
+		return InactivityReportOutput{}, err
+	}
+	var details leagueDetailsRaw
+	if err := json.Unmarshal(raw, &details); err != nil {
+		return InactivityReportOutput{}, err
+	}
+	fromGW := max(throughGW-th.Window+1, details.League.StartEvent, 1)
+	out := InactivityReportOutput{
+		LeagueID:   args.LeagueID,
+		FromGW:     fromGW,
+		ThroughGW:  throughGW,
+		Thresholds: th,
+		Managers:   make([]ManagerInactivity, 0, len(details.LeagueEntries)),
+		GWNote:     note,
+	}
+
+	transactions, err := loadTransactionsRaw(st, args.LeagueID)
+	if err != nil {
+		return InactivityReportOutput{}, err
+	}
+	trades, err := loadTradesRaw(st, args.LeagueID)
+	if err != nil {
+		return InactivityReportOutput{}, err
+	}
+	// Any claim counts, won or not, and so do waivers already run for the
+	// GW after the window: each shows the manager was there.
+	moves := make(map[int]int)
+	for _, tx := range transactions {
+		if tx.Event >= fromGW && tx.Event <= throughGW+1 {
+			moves[tx.Entry]++
+		}
+	}
+	for _, tr := range trades {
+		if tr.Event >= fromGW && tr.Event <= throughGW+1 {
+			moves[tr.OfferedEntry]++
+			moves[tr.ReceivedEntry]++
+		}
+	}
+
+	lineupEf := make(map[int]map[int]summary.LineupEfficiencyEntry)
+	missingLineupEf := make([]int, 0)
+	for gw := fromGW; gw <= throughGW; gw++ {
+		var le summary.LineupEfficiencySummary
+		err := loadSummaryInto(cfg, args.LeagueID, gw, fmt.Sprintf("summary/lineup_efficiency/%d/gw/%d.json", args.LeagueID, gw), &le)
+		if err != nil {
+			if classifyError(err).Code != codeDataMissing {
+				return InactivityReportOutput{}, err
+			}
+			missingLineupEf = append(missingLineupEf, gw)
+			continue
+		}
+		byEntry := make(map[int]summary.LineupEfficiencyEntry, len(le.Entries))
+		for _, e := range le.Entries {
+			byEntry[e.EntryID] = e
+		}
+		lineupEf[gw] = byEntry
+	}
+	players, err := loadPlayerNames(cfg.RawRoot)
+	if err != nil {
+		return InactivityReportOutput{}, err
+	}
+
+	missingSnapshots := 0
+	for _, e := range details.LeagueEntries {
+		weeks := make([]entryWeek, 0, throughGW-fromGW+1)
+		for gw := fromGW; gw <= throughGW; gw++ {
+			w := entryWeek{gw: gw}
+			snap, err := loadEntrySnapshot(cfg, args.LeagueID, e.EntryID, gw)
+			switch {
+			case err == nil && !snap.Missing:
+				w.lineup, w.hasLineup = lineupSignature(snap), true
+			case err == nil || classifyError(err).Code == codeDataMissing:
+				missingSnapshots++
+			default:
+				return InactivityReportOutput{}, err
+			}
+			if le, ok := lineupEf[gw][e.EntryID]; ok && !le.MissingSnapshot {
+				w.zeroMinutes, w.hasLineupEf = le.ZeroMinuteStarters, true
+			}
+			weeks = append(weeks, w)
+		}
+		m := ManagerInactivity{EntryID: e.EntryID, EntryName: e.EntryName, Signals: inactivitySignals(weeks, moves[e.EntryID], fromGW, throughGW, th, players)}
+		for _, s := range m.Signals {
+			m.Severity += s.Weight
+		}
+		m.Level = inactivityLevel(m.Severity)
+		out.Managers = append(out.Managers, m)
+	}
+	sort.SliceStable(out.Managers, func(i, j int) bool {
+		if out.Managers[i].Severity != out.Managers[j].Severity {
+			return out.Managers[i].Severity > out.Managers[j].Severity
+		}
+		return out.Managers[i].EntryID < out.Managers[j].EntryID
+	})
+
+	if len(missingLineupEf) > 0 {
+		out.Notes = append(out.Notes, fmt.Sprintf("No lineup_efficiency summary for GW %v; the 0-minute starter signals skip those GWs.", missingLineupEf))
+	}
+	if missingSnapshots > 0 {
+		out.Notes = append(out.Notes, fmt.Sprintf("%d entry GWs have no lineup snapshot; an unchanged-lineup run can't span them.", missingSnapshots))
+	}
+	if players.Unavailable {
+		out.Notes = append(out.Notes, "bootstrap-static.json is unavailable, so players are named by element id without their current status.")
+	}
+	return out, nil
+}
+
+// lineupSignature is the snapshot's picks by slot, so two GWs with the same
+// signature had the same XI in the same order and the same bench order.
+func lineupSignature(snap ledger.EntrySnapshot) string {
+	picks := append([]ledger.EntryPick(nil), snap.Picks...)
+	sort.Slice(picks, func(i, j int) bool { return picks[i].Position < picks[j].Position })
+	parts := make([]string, 0, len(picks))
+	for _, p := range picks {
+		parts = append(parts, strconv.Itoa(p.Position)+":"+strconv.Itoa(p.Element))
+	}
+	return strings.Join(parts, ",")
+}
+
+// inactivitySignals checks one entry's weeks (in GW order) against th.
+func inactivitySignals(weeks []entryWeek, moves int, fromGW int, throughGW int, th InactivityThresholds, players playerNames) []InactivitySignal {
+	signal := func(name string, gws []int, names []string, evidence string) InactivitySignal {
+		return InactivitySignal{Signal: name, Weight: inactivityWeights[name], GWs: gws, Players: names, Evidence: evidence}
+	}
+	out := make([]InactivitySignal, 0)
+
+	if moves == 0 && throughGW-fromGW+1 >= th.Window {
+		out = append(out, signal(signalNoTransactions, nil, nil,
+			fmt.Sprintf("No waiver claims, free-agent moves or trades in GW %d–%d.", fromGW, throughGW)))
+	}
+
+	// Longest run of GWs with a 0-minute starter.
+	var run, best []int
+	for _, w := range weeks {
+		if w.hasLineupEf && len(w.zeroMinutes) > 0 {
+			run = append(run, w.gw)
+			if len(run) > len(best) {
+				best = append([]int(nil), run...)
+			}
+		} else {
+			run = nil
+		}
+	}
+	if len(best) >= th.ZeroMinuteGWs {
+		out = append(out, signal(signalZeroMinuteStarters, best, nil,
+			fmt.Sprintf("Started a player who didn't play in %d consecutive GWs (%s).", len(best), gwSpan(best))))
+	}
+
+	// Longest run of identical lineups.
+	run, best = nil, nil
+	prev := ""
+	for _, w := range weeks {
+		switch {
+		case !w.hasLineup:
+			run = nil
+		case len(run) > 0 && w.lineup == prev:
+			run = append(run, w.gw)
+		default:
+			run = []int{w.gw}
+		}
+		prev = w.lineup
+		if len(run) > len(best) {
+			best = append([]int(nil), run...)
+		}
+	}
+	if len(best) >= th.UnchangedGWs {
+		out = append(out, signal(signalUnchangedLineup, best, nil,
+			fmt.Sprintf("Same starting XI and bench order in %d consecutive GWs (%s).", len(best), gwSpan(best))))
+	}
+
+	// The same player started without playing, GW after GW.
+	streak := make(map[int][]int)
+	longest := make(map[int][]int)
+	for _, w := range weeks {
+		dead := make(map[int]bool, len(w.zeroMinutes))
+		if w.hasLineupEf {
+			for _, el := range w.zeroMinutes {
+				dead[el] = true
+				streak[el] = append(streak[el], w.gw)
+				if len(streak[el]) > len(longest[el]) {
+					longest[el] = append([]int(nil), streak[el]...)
+				}
+			}
+		}
+		for el := range streak {
+			if !dead[el] {
+				delete(streak, el)
+			}
+		}
+	}
+	flagged := make([]int, 0)
+	for el, gws := range longest {
+		if len(gws) >= th.UnavailableGWs {
+			flagged = append(flagged, el)
+		}
+	}
+	if len(flagged) > 0 {
+		sort.Ints(flagged)
+		names := make([]string, 0, len(flagged))
+		gwSet := make(map[int]bool)
+		for _, el := range flagged {
+			meta, _ := players.get(el)
+			name := meta.Name
+			if name == "" {
+				name = fmt.Sprintf("element %d", el)
+			}
+			if meta.Status != "" && meta.Status != "a" {
+				name += " (" + statusLabel(meta.Status) + ")"
+			}
+			names = append(names, fmt.Sprintf("%s: %s", name, gwSpan(longest[el])))
+			for _, gw := range longest[el] {
+				gwSet[gw] = true
+			}
+		}
+		gws := make([]int, 0, len(gwSet))
+		for gw := range gwSet {
+			gws = append(gws, gw)
+		}
+		sort.Ints(gws)
+		out = append(out, signal(signalUnavailableInXI, gws, names,
+			fmt.Sprintf("Kept starting %d player(s) who weren't playing for %d+ GWs in a row.", len(flagged), th.UnavailableGWs)))
+	}
+	return out
+}
+
+// inactivityLevel buckets a severity score (0–10 with every signal).
+func inactivityLevel(severity int) string {
+	switch {
+	case severity >= 7:
+		return inactivityHigh
+	case severity >= 4:
+		return inactivityMedium
+	case severity > 0:
+		return inactivityLow
+	}
+	return inactivityNone
+}
+
+// gwSpan renders consecutive GWs as "GW5–7", or "GW5" for one.
+func gwSpan(gws []int) string {
+	if len(gws) == 0 {
+		return ""
+	}
+	if len(gws) == 1 {
+		return fmt.Sprintf("GW%d", gws[0])
+	}
+	return fmt.Sprintf("GW%d–%d", gws[0], gws[len(gws)-1])
+}
+
+// withInactivity adds the managers flagged by a default inactivity report
+// through gw to a league_summary payload as "inactivity". The summary is
+// returned unchanged when the report can't be built.
+func withInactivity(cfg ServerConfig, leagueID int, gw int, raw []byte) []byte {
+	report, err := buildInactivityReport(cfg, InactivityReportArgs{LeagueID: leagueID, GW: gw})
+	if err != nil {
+		return raw
+	}
+	flagged := make([]ManagerInactivity, 0)
+	for _, m := range report.Managers {
+		if m.Severity > 0 {
+			flagged = append(flagged, m)
+		}
+	}
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return raw
+	}
+	b, err := json.Marshal(flagged)
+	if err != nil {
+		return raw
+	}
+	obj["inactivity"] = b
+	out, err := json.MarshalIndent(obj, "", "  ")
+	if err != nil {
+		return raw
+	}
+	return out
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/ledger"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/summary"
+)
+
+// writeInactivityFixture gives writeClaimFixture's league three GWs of
+// lineups. Alpha never touches a lineup and starts Salah without him
+// playing in GW2 and GW3; Beta reorders every week and put in a (failed)
+// claim; Gamma has a dead starter in GW1 and GW3 but no GW2 snapshot.
+func writeInactivityFixture(t *testing.T, dir string) {
+	t.Helper()
+	writeClaimFixture(t, dir)
+	writeJSON(t, filepath.Join(dir, "league/100/transactions.json"), map[string]any{"transactions": []any{
+		map[string]any{"id": 1, "entry": 201, "event": 2, "kind": "w", "result": "di", "element_in": 10, "element_out": 4},
+	}})
+	snap := func(entry, gw int, elements ...int) {
+		picks := make([]ledger.EntryPick, 0, len(elements))
+		for i, el := range elements {
+			picks = append(picks, ledger.EntryPick{Element: el, Position: i + 1, Multiplier: 1})
+		}
+		writeJSON(t, filepath.Join(dir, fmt.Sprintf("snapshots/100/entry/%d/gw/%d.json", entry, gw)),
+			ledger.EntrySnapshot{LeagueID: 100, EntryID: entry, Gameweek: gw, Picks: picks})
+	}
+	for gw := 1; gw <= 3; gw++ {
+		snap(200, gw, 1, 2, 3, 6, 7, 8)
+	}
+	snap(201, 1, 4, 11)
+	snap(201, 2, 11, 4)
+	snap(201, 3, 4, 11)
+	snap(202, 1, 5)
+	snap(202, 3, 5)
+	zero := map[int]map[int][]int{
+		1: {200: {}, 201: {}, 202: {5}},
+		2: {200: {1}, 201: {}},
+		3: {200: {1}, 201: {}, 202: {5}},
+	}
+	for gw, byEntry := range zero {
+		le := summary.LineupEfficiencySummary{LeagueID: 100, Gameweek: gw}
+		for entry, els := range byEntry {
+			le.Entries = append(le.Entries, summary.LineupEfficiencyEntry{EntryID: entry, ZeroMinuteStarters: els, ZeroMinuteStarterCount: len(els)})
+		}
+		writeJSON(t, filepath.Join(dir, fmt.Sprintf("summary/lineup_efficiency/100/gw/%d.json", gw)), le)
+	}
+}
+
+func TestBuildInactivityReport(t *testing.T) {
+	dir, cfg := resourceCfg(t)
+	writeInactivityFixture(t, dir)
+
+	window := 3
+	out, err := buildInactivityReport(cfg, InactivityReportArgs{LeagueID: 100, Window: &window})
+	if err != nil {
+		t.Fatalf("buildInactivityReport: %v", err)
+	}
+	if out.FromGW != 1 || out.ThroughGW != 3 || len(out.Managers) != 3 {
+		t.Fatalf("output = %+v", out)
+	}
+	byEntry := make(map[int]ManagerInactivity)
+	for _, m := range out.Managers {
+		byEntry[m.EntryID] = m
+	}
+	signals := func(m ManagerInactivity) map[string]InactivitySignal {
+		got := make(map[string]InactivitySignal)
+		for _, s := range m.Signals {
+			got[s.Signal] = s
+		}
+		return got
+	}
+
+	alpha := byEntry[200]
+	if out.Managers[0].EntryID != 200 || alpha.Severity != 10 || alpha.Level != inactivityHigh || len(alpha.Signals) != 4 {
+		t.Errorf("Alpha = %+v, want every signal first in the list", alpha)
+	}
+	got := signals(alpha)
+	if s := got[signalUnchangedLineup]; len(s.GWs) != 3 || s.Evidence != "Same starting XI and bench order in 3 consecutive GWs (GW1–3)." {
+		t.Errorf("unchanged lineup = %+v", s)
+	}
+	if s := got[signalUnavailableInXI]; len(s.Players) != 1 || s.Players[0] != "Salah: GW2–3" {
+		t.Errorf("unavailable in XI = %+v", s)
+	}
+	if s := got[signalZeroMinuteStarters]; len(s.GWs) != 2 || s.GWs[0] != 2 {
+		t.Errorf("zero-minute starters = %+v", s)
+	}
+
+	if beta := byEntry[201]; beta.Severity != 0 || beta.Level != inactivityNone {
+		t.Errorf("Beta = %+v, want active: a denied claim still counts", beta)
+	}
+	// Gamma's dead starters aren't consecutive GWs, and the missing GW2
+	// snapshot is noted.
+	gamma := byEntry[202]
+	if gamma.Severity != 2 || gamma.Level != inactivityLow || gamma.Signals[0].Signal != signalNoTransactions {
+		t.Errorf("Gamma = %+v, want only no_transactions", gamma)
+	}
+	if len(out.Notes) != 1 {
+		t.Errorf("notes = %v, want the missing snapshot", out.Notes)
+	}
+
+	// A stricter threshold clears Alpha's lineup signal.
+	unchanged := 4
+	out, err = buildInactivityReport(cfg, InactivityReportArgs{LeagueID: 100, Window: &window, UnchangedGWs: &unchanged})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := signals(out.Managers[0])[signalUnchangedLineup]; ok || out.Managers[0].Severity != 7 {
+		t.Errorf("with unchanged_gws=4: %+v", out.Managers[0])
+	}
+}
+
+func TestWithInactivity(t *testing.T) {
+	dir, cfg := resourceCfg(t)
+	writeInactivityFixture(t, dir)
+
+	raw := withInactivity(cfg, 100, 3, []byte(`{"league_id": 100, "gameweek": 3, "entries": []}`))
+	var obj struct {
+		LeagueID   int                 `json:"league_id"`
+		Inactivity []ManagerInactivity `json:"inactivity"`
+	}
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		t.Fatal(err)
+	}
+	// The default window is four GWs, longer than the three played, so no
+	// one is flagged for a lack of transactions.
+	if obj.LeagueID != 100 || len(obj.Inactivity) != 1 || obj.Inactivity[0].EntryID != 200 || obj.Inactivity[0].Severity != 8 {
+		t.Errorf("league_summary inactivity = %+v", obj.Inactivity)
+	}
+
+	// Without league data the summary passes through untouched.
+	_, empty := resourceCfg(t)
+	in := []byte(`{"league_id": 100}`)
+	if got := withInactivity(empty, 100, 3, in); string(got) != string(in) {
+		t.Errorf("summary changed without league data: %s", got)
+	}
+}
+
+func TestBuildInactivityReport_Errors(t *testing.T) {
+	_, cfg := resourceCfg(t)
+	if _, err := buildInactivityReport(cfg, InactivityReportArgs{}); classifyError(err).Code != codeInvalidArgument {
+		t.Errorf("missing league: err = %v", err)
+	}
+}
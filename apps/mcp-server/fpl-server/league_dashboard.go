@@ -0,0 +1,227 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// defaultDashboardMaxBytes is the league_dashboard response size guard used
+// when --dashboard-max-bytes is unset.
+const defaultDashboardMaxBytes = 256 << 10
+
+// dashboardWorkers bounds how many sections league_dashboard loads at once;
+// a cold section can compute a whole GW of summaries.
+const dashboardWorkers = 4
+
+// dashboardSection loads one section's JSON for a GW argument.
+type dashboardSection struct {
+	// tool is the tool that returns the whole section, named in the
+	// truncation marker.
+	tool string
+	load func(cfg ServerConfig, leagueID int, gw int) ([]byte, error)
+}
+
+// dashboardSectionOrder lists the sections league_dashboard accepts, in the
+// order they run when none are requested.
+var dashboardSectionOrder = []string{"standings", "league_summary", "matchups", "transactions", "lineup_efficiency", "fixtures", "waiver_targets"}
+
+var dashboardSections = map[string]dashboardSection{
+	"standings": {tool: "standings", load: func(cfg ServerConfig, leagueID int, gw int) ([]byte, error) {
+		return buildStandings(cfg, StandingsArgs{LeagueID: leagueID, GW: gw})
+	}},
+	"league_summary": {tool: "league_summary", load: finishedGWSummary("summary/league/%d/gw/%d.json")},
+	"matchups": {tool: "matchup_breakdown", load: func(cfg ServerConfig, leagueID int, gw int) ([]byte, error) {
+		return buildMatchupBreakdown(cfg, MatchupBreakdownArgs{LeagueID: leagueID, GW: gw})
+	}},
+	"lineup_efficiency": {tool: "lineup_efficiency", load: finishedGWSummary("summary/lineup_efficiency/%d/gw/%d.json")},
+	"transactions": {tool: "transactions", load: func(cfg ServerConfig, leagueID int, gw int) ([]byte, error) {
+		gw, err := resolveGW(cfg, gw)
+		if err != nil {
+			return nil, err
+		}
+		return loadSummaryFile(cfg, leagueID, gw, fmt.Sprintf("summary/transactions/%d/gw/%d.json", leagueID, gw), nil, nil)
+	}},
+	"fixtures": {tool: "fixtures", load: func(cfg ServerConfig, leagueID int, gw int) ([]byte, error) {
+		out, err := buildFixtures(cfg, FixturesArgs{LeagueID: leagueID, AsOfGW: &gw}, cfg.now())
+		if err != nil {
+			return nil, err
+		}
+		return json.MarshalIndent(out, "", "  ")
+	}},
+	"waiver_targets": {tool: "waiver_targets", load: func(cfg ServerConfig, leagueID int, gw int) ([]byte, error) {
+		gw, note, err := resolveEffectiveGW(cfg, gw, gwModeLatestFinished)
+		if err != nil {
+			return nil, err
+		}
+		relPath := fmt.Sprintf("summary/waiver_targets/%d/gw/%d_h5_risk-med.json", leagueID, gw)
+		raw, err := loadSummaryFile(cfg, leagueID, gw, relPath, []int{5}, []string{"med"})
+		if err != nil {
+			return nil, err
+		}
+		return withGWNote(raw, note), nil
+	}},
+}
+
+// finishedGWSummary loads a per-GW summary the way its own tool does, with
+// gw=0 resolving to the latest finished GW.
+func finishedGWSummary(pathFormat string) func(ServerConfig, int, int) ([]byte, error) {
+	return func(cfg ServerConfig, leagueID int, gw int) ([]byte, error) {
+		gw, note, err := resolveEffectiveGW(cfg, gw, gwModeLatestFinished)
+		if err != nil {
+			return nil, err
+		}
+		raw, err := loadSummaryFile(cfg, leagueID, gw, fmt.Sprintf(pathFormat, leagueID, gw), nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		return withGWNote(raw, note), nil
+	}
+}
+
+// LeagueDashboardArgs is the input schema for league_dashboard.
+type LeagueDashboardArgs struct {
+	LeagueID int      `json:"league_id" jsonschema:"Draft league id (required)"`
+	GW       int      `json:"gw" jsonschema:"Gameweek (0 = each section's own default)"`
+	Sections []string `json:"sections,omitempty" jsonschema:"Any of standings, league_summary, matchups, transactions, lineup_efficiency, fixtures, waiver_targets (default all)"`
+}
+
+// LeagueDashboardOutput is the output of league_dashboard. A section that
+// failed is in Errors instead of Sections; one dropped by the size guard is
+// replaced by a dashboardTruncated marker and listed in Truncated.
+type LeagueDashboardOutput struct {
+	LeagueID  int                        `json:"league_id"`
+	GW        int                        `json:"gw"`
+	Sections  map[string]json.RawMessage `json:"sections"`
+	Errors    map[string]errorObject     `json:"errors,omitempty"`
+	Truncated []string                   `json:"truncated,omitempty"`
+	MaxBytes  int                        `json:"max_bytes"`
+}
+
+// dashboardTruncated stands in for a section the size guard dropped.
+type dashboardTruncated struct {
+	Truncated bool   `json:"truncated"`
+	Bytes     int    `json:"bytes"`
+	Hint      string `json:"hint"`
+}
+
+// parseDashboardSections validates and de-duplicates the requested
+// sections; none means all of them.
+func parseDashboardSections(requested []string) ([]string, error) {
+	if len(requested) == 0 {
+		return dashboardSectionOrder, nil
+	}
+	out := make([]string, 0, len(requested))
+	seen := make(map[string]bool, len(requested))
+	for _, s := range requested {
+		s = strings.ToLower(strings.TrimSpace(s))
+		if _, ok := dashboardSections[s]; !ok {
+			return nil, invalidArgumentf("unknown section %q (want %s)", s, strings.Join(dashboardSectionOrder, ", "))
+		}
+		if !seen[s] {
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+	return out, nil
+}
+
+// buildLeagueDashboard loads the requested sections concurrently. Only a bad
+// argument fails the call; a section that can't be loaded is reported in
+// Errors alongside the rest.
+func buildLeagueDashboard(cfg ServerConfig, args LeagueDashboardArgs) ([]byte, error) {
+	if args.LeagueID == 0 {
+		return nil, invalidArgumentf("league_id is required")
+	}
+	names, err := parseDashboardSections(args.Sections)
+	if err != nil {
+		return nil, err
+	}
+
+	type result struct {
+		raw []byte
+		err error
+	}
+	results := make([]result, len(names))
+	sem := make(chan struct{}, dashboardWorkers)
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, section dashboardSection) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			raw, err := section.load(cfg, args.LeagueID, args.GW)
+			results[i] = result{raw: raw, err: err}
+		}(i, dashboardSections[name])
+	}
+	wg.Wait()
+
+	maxBytes := cfg.DashboardMaxBytes
+	if maxBytes <= 0 {
+		maxBytes = defaultDashboardMaxBytes
+	}
+	out := LeagueDashboardOutput{
+		LeagueID: args.LeagueID,
+		GW:       args.GW,
+		Sections: make(map[string]json.RawMessage, len(names)),
+		MaxBytes: maxBytes,
+	}
+	for i, name := range names {
+		if results[i].err != nil {
+			if out.Errors == nil {
+				out.Errors = make(map[string]errorObject)
+			}
+			out.Errors[name] = newErrorObject(results[i].err)
+			continue
+		}
+		out.Sections[name] = results[i].raw
+	}
+	return out.marshalWithin(maxBytes)
+}
+
+// marshalWithin marshals o, swapping the largest remaining sections for a
+// truncation marker until the response fits in maxBytes or every section
+// has been dropped.
+func (o LeagueDashboardOutput) marshalWithin(maxBytes int) ([]byte, error) {
+	for {
+		b, err := json.MarshalIndent(o, "", "  ")
+		if err != nil || len(b) <= maxBytes {
+			return b, err
+		}
+		largest := ""
+		for name, raw := range o.Sections {
+			if o.isTruncated(name) {
+				continue
+			}
+			if largest == "" || len(raw) > len(o.Sections[largest]) || (len(raw) == len(o.Sections[largest]) && name < largest) {
+				largest = name
+			}
+		}
+		if largest == "" {
+			return b, nil
+		}
+		marker, err := json.Marshal(dashboardTruncated{
+			Truncated: true,
+			Bytes:     len(o.Sections[largest]),
+			Hint:      fmt.Sprintf("over the %d-byte dashboard limit; call %s for this section", maxBytes, dashboardSections[largest].tool),
+		})
+		if err != nil {
+			return nil, err
+		}
+		o.Sections[largest] = marker
+		o.Truncated = append(o.Truncated, largest)
+		sort.Strings(o.Truncated)
+	}
+}
+
+func (o LeagueDashboardOutput) isTruncated(name string) bool {
+	for _, t := range o.Truncated {
+		if t == name {
+			return true
+		}
+	}
+	return false
+}
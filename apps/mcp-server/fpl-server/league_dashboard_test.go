@@ -0,0 +1,117 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeDashboardFixture writes GW 5 (finished) standings and league summary
+// files for league 100, with the league summary padded to about summaryBytes.
+func writeDashboardFixture(t *testing.T, dir string, summaryBytes int) {
+	t.Helper()
+	writeFullGameJSON(t, dir, 5, true, 6, true, "n")
+	writeJSON(t, filepath.Join(dir, "summary/standings/100/gw/5.json"), map[string]any{
+		"league_id": 100, "gameweek": 5,
+		"rows": []any{map[string]any{"entry_id": 200, "entry_name": "Alpha FC", "rank": 1}},
+	})
+	entries := make([]any, 0)
+	for i := 0; i*60 < summaryBytes; i++ {
+		entries = append(entries, map[string]any{"entry_id": 1000 + i, "entry_name": fmt.Sprintf("Team %03d", i)})
+	}
+	writeJSON(t, filepath.Join(dir, "summary/league/100/gw/5.json"), map[string]any{
+		"league_id": 100, "gameweek": 5, "entries": entries,
+	})
+}
+
+func decodeDashboard(t *testing.T, raw []byte) LeagueDashboardOutput {
+	t.Helper()
+	var out LeagueDashboardOutput
+	if err := json.Unmarshal(raw, &out); err != nil {
+		t.Fatalf("unmarshal: %v (%s)", err, raw)
+	}
+	return out
+}
+
+func TestBuildLeagueDashboard_PartialFailure(t *testing.T) {
+	dir, cfg := resourceCfg(t)
+	writeDashboardFixture(t, dir, 0)
+
+	raw, err := buildLeagueDashboard(cfg, LeagueDashboardArgs{LeagueID: 100, Sections: []string{"standings", "League_Summary", "matchups", "standings"}})
+	if err != nil {
+		t.Fatalf("buildLeagueDashboard: %v", err)
+	}
+	out := decodeDashboard(t, raw)
+	if len(out.Sections) != 2 || out.Sections["standings"] == nil || out.Sections["league_summary"] == nil {
+		t.Fatalf("sections = %v, want standings and league_summary", out.Sections)
+	}
+	var standings struct {
+		Gameweek int `json:"gameweek"`
+	}
+	if err := json.Unmarshal(out.Sections["standings"], &standings); err != nil || standings.Gameweek != 5 {
+		t.Errorf("standings section = %s", out.Sections["standings"])
+	}
+	if e, ok := out.Errors["matchups"]; !ok || e.Code != codeDataMissing || !strings.Contains(e.Message, "summary/matchup/100/gw/5.json") {
+		t.Errorf("errors = %+v, want matchups DATA_MISSING", out.Errors)
+	}
+	if len(out.Truncated) != 0 || out.MaxBytes != defaultDashboardMaxBytes {
+		t.Errorf("truncated %v max %d", out.Truncated, out.MaxBytes)
+	}
+}
+
+func TestBuildLeagueDashboard_Errors(t *testing.T) {
+	_, cfg := resourceCfg(t)
+	if _, err := buildLeagueDashboard(cfg, LeagueDashboardArgs{}); classifyError(err).Code != codeInvalidArgument {
+		t.Errorf("missing league: err = %v", err)
+	}
+	_, err := buildLeagueDashboard(cfg, LeagueDashboardArgs{LeagueID: 100, Sections: []string{"standings", "gossip"}})
+	if classifyError(err).Code != codeInvalidArgument || !strings.Contains(err.Error(), "gossip") {
+		t.Errorf("unknown section: err = %v", err)
+	}
+
+	// Every section failing is still a successful call.
+	raw, err := buildLeagueDashboard(cfg, LeagueDashboardArgs{LeagueID: 100})
+	if err != nil {
+		t.Fatalf("all sections missing: %v", err)
+	}
+	if out := decodeDashboard(t, raw); len(out.Errors) != len(dashboardSectionOrder) || len(out.Sections) != 0 {
+		t.Errorf("errors %d sections %d, want every section in errors", len(out.Errors), len(out.Sections))
+	}
+}
+
+func TestBuildLeagueDashboard_SizeGuard(t *testing.T) {
+	dir, cfg := resourceCfg(t)
+	writeDashboardFixture(t, dir, 8000)
+	cfg.DashboardMaxBytes = 2000
+
+	raw, err := buildLeagueDashboard(cfg, LeagueDashboardArgs{LeagueID: 100, Sections: []string{"standings", "league_summary"}})
+	if err != nil {
+		t.Fatalf("buildLeagueDashboard: %v", err)
+	}
+	if len(raw) > cfg.DashboardMaxBytes {
+		t.Errorf("response is %d bytes, want at most %d", len(raw), cfg.DashboardMaxBytes)
+	}
+	out := decodeDashboard(t, raw)
+	if len(out.Truncated) != 1 || out.Truncated[0] != "league_summary" {
+		t.Fatalf("truncated = %v, want only the largest section", out.Truncated)
+	}
+	var marker dashboardTruncated
+	if err := json.Unmarshal(out.Sections["league_summary"], &marker); err != nil || !marker.Truncated || marker.Bytes <= cfg.DashboardMaxBytes || !strings.Contains(marker.Hint, "call league_summary") {
+		t.Errorf("marker = %+v (%v)", marker, err)
+	}
+	if strings.Contains(string(out.Sections["standings"]), "truncated") {
+		t.Errorf("standings was truncated: %s", out.Sections["standings"])
+	}
+
+	// With a limit nothing fits under, every section is dropped.
+	cfg.DashboardMaxBytes = 10
+	raw, err = buildLeagueDashboard(cfg, LeagueDashboardArgs{LeagueID: 100, Sections: []string{"standings", "league_summary"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out := decodeDashboard(t, raw); len(out.Truncated) != 2 || out.Truncated[0] != "league_summary" || out.Truncated[1] != "standings" {
+		t.Errorf("truncated = %v, want both", out.Truncated)
+	}
+}
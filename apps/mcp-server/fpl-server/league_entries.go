@@ -18,14 +18,17 @@ type LeagueEntryInfo struct {
 	LeagueEntryID int    `json:"league_entry_id"`
 }
 
+// LeagueEntriesOutput lists a league's teams. StartGW is the league's first
+// gameweek; leagues created mid-season start after GW1.
 type LeagueEntriesOutput struct {
 	LeagueID int               `json:"league_id"`
+	StartGW  int               `json:"start_gw"`
 	Teams    []LeagueEntryInfo `json:"teams"`
 }
 
 func buildLeagueEntries(cfg ServerConfig, leagueID int) (LeagueEntriesOutput, error) {
 	if leagueID == 0 {
-		return LeagueEntriesOutput{}, fmt.Errorf("league_id is required")
+		return LeagueEntriesOutput{}, invalidArgumentf("league_id is required")
 	}
 	path := filepath.Join(cfg.RawRoot, fmt.Sprintf("league/%d/details.json", leagueID))
 	raw, err := os.ReadFile(path)
@@ -45,5 +48,5 @@ func buildLeagueEntries(cfg ServerConfig, leagueID int) (LeagueEntriesOutput, er
 			LeagueEntryID: e.ID,
 		})
 	}
-	return LeagueEntriesOutput{LeagueID: leagueID, Teams: teams}, nil
+	return LeagueEntriesOutput{LeagueID: leagueID, StartGW: max(details.League.StartEvent, 1), Teams: teams}, nil
 }
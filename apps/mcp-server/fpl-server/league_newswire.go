@@ -0,0 +1,373 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/availability"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+)
+
+// breakoutPoints is the GW score that makes an unowned player's performance
+// newsworthy.
+const breakoutPoints = 12
+
+// Newswire item types.
+const (
+	newsStatusChange = "status_change"
+	newsReturned     = "returned_unowned"
+	newsWaiver       = "waiver"
+	newsFreeAgent    = "free_agent"
+	newsTrade        = "trade"
+	newsBreakout     = "unowned_breakout"
+)
+
+// newsTypeRank orders items of different types within the same GW and time.
+var newsTypeRank = map[string]int{
+	newsStatusChange: 0,
+	newsReturned:     1,
+	newsBreakout:     2,
+	newsTrade:        3,
+	newsWaiver:       4,
+	newsFreeAgent:    5,
+}
+
+// LeagueNewswireArgs are the input arguments for the league_newswire tool.
+type LeagueNewswireArgs struct {
+	LeagueID  int  `json:"league_id" jsonschema:"Draft league id (required)"`
+	WindowGWs *int `json:"window_gws,omitempty" jsonschema:"GWs to cover, ending with the current one (default 2)"`
+	MaxItems  *int `json:"max_items,omitempty" jsonschema:"Most items to return, newest first (default 30)"`
+}
+
+// NewswireItem is one event in the feed. TimeUTC is set when the source
+// carries a timestamp; Summary is a sentence that can be read out as is.
+type NewswireItem struct {
+	Type     string `json:"type"`
+	Gameweek int    `json:"gameweek"`
+	TimeUTC  string `json:"time_utc,omitempty"`
+	Elements []int  `json:"elements,omitempty"`
+	EntryIDs []int  `json:"entry_ids,omitempty"`
+	Summary  string `json:"summary"`
+}
+
+// LeagueNewswireOutput is the result of the league_newswire tool.
+type LeagueNewswireOutput struct {
+	LeagueID  int `json:"league_id"`
+	FromGW    int `json:"from_gw"`
+	ThroughGW int `json:"through_gw"`
+	// Total counts the items before MaxItems was applied.
+	Total    int            `json:"total"`
+	MaxItems int            `json:"max_items"`
+	Items    []NewswireItem `json:"items"`
+	Notes    []string       `json:"notes"`
+	// PlayerNamesUnavailable is playerNames.Unavailable.
+	PlayerNamesUnavailable bool `json:"player_names_unavailable,omitempty"`
+}
+
+// newswire collects items from each source along with the lookups they share.
+type newswire struct {
+	cfg       ServerConfig
+	leagueID  int
+	fromGW    int
+	throughGW int
+	players   playerNames
+	entryName map[int]string
+	items     []NewswireItem
+	notes     []string
+}
+
+func buildLeagueNewswire(cfg ServerConfig, args LeagueNewswireArgs) (LeagueNewswireOutput, error) {
+	if args.LeagueID == 0 {
+		return LeagueNewswireOutput{}, invalidArgumentf("league_id is required")
+	}
+	window := 2
+	if args.WindowGWs != nil && *args.WindowGWs > 0 {
+		window = *args.WindowGWs
+	}
+	maxItems := 30
+	if args.MaxItems != nil && *args.MaxItems > 0 {
+		maxItems = *args.MaxItems
+	}
+	throughGW, err := resolveGW(cfg, 0)
+	if err != nil {
+		return LeagueNewswireOutput{}, err
+	}
+	st := store.NewJSONStore(cfg.RawRoot)
+	ld, _, err := loadLeagueDetails(st, args.LeagueID)
+	if err != nil {
+		return LeagueNewswireOutput{}, err
+	}
+	players, err := loadPlayerNames(cfg.RawRoot)
+	if err != nil {
+		return LeagueNewswireOutput{}, err
+	}
+	w := &newswire{
+		cfg:       cfg,
+		leagueID:  args.LeagueID,
+		fromGW:    max(throughGW-window+1, ld.StartGW()),
+		throughGW: throughGW,
+		players:   players,
+		entryName: make(map[int]string, len(ld.LeagueEntries)),
+		items:     make([]NewswireItem, 0),
+		notes:     make([]string, 0),
+	}
+	for _, e := range ld.LeagueEntries {
+		w.entryName[e.EntryID] = e.EntryName
+	}
+
+	// A source whose data hasn't been fetched is noted and skipped so the
+	// rest of the feed still comes through.
+	sources := []struct {
+		name string
+		add  func() error
+	}{
+		{"status changes", w.addAvailability},
+		{"waivers and free agents", w.addTransactions},
+		{"trades", w.addTrades},
+		{"unowned performances", w.addBreakouts},
+	}
+	for _, src := range sources {
+		if err := src.add(); err != nil {
+			if classifyError(err).Code != codeDataMissing {
+				return LeagueNewswireOutput{}, err
+			}
+			w.notes = append(w.notes, fmt.Sprintf("No %s: %v", src.name, err))
+		}
+	}
+
+	sortNewswire(w.items)
+	out := LeagueNewswireOutput{
+		LeagueID:               args.LeagueID,
+		FromGW:                 w.fromGW,
+		ThroughGW:              throughGW,
+		Total:                  len(w.items),
+		MaxItems:               maxItems,
+		Items:                  w.items,
+		Notes:                  w.notes,
+		PlayerNamesUnavailable: players.Unavailable,
+	}
+	if len(out.Items) > maxItems {
+		out.Items = out.Items[:maxItems]
+	}
+	if out.Total == 0 {
+		out.Notes = append(out.Notes, fmt.Sprintf("Nothing to report for GW %d-%d.", out.FromGW, out.ThroughGW))
+	}
+	return out, nil
+}
+
+// sortNewswire orders items newest first: by GW, then by time, with untimed
+// items (end-of-GW results) ahead of timed ones in the same GW.
+func sortNewswire(items []NewswireItem) {
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i], items[j]
+		if a.Gameweek != b.Gameweek {
+			return a.Gameweek > b.Gameweek
+		}
+		if (a.TimeUTC == "") != (b.TimeUTC == "") {
+			return a.TimeUTC == ""
+		}
+		if a.TimeUTC != b.TimeUTC {
+			return a.TimeUTC > b.TimeUTC
+		}
+		return newsTypeRank[a.Type] < newsTypeRank[b.Type]
+	})
+}
+
+// playerLabel names an element for a summary line: "Salah (LIV MID)".
+func (w *newswire) playerLabel(id int) string {
+	meta, ok := w.players.get(id)
+	if !ok {
+		return fmt.Sprintf("element %d", id)
+	}
+	if w.players.Unavailable {
+		return meta.Name
+	}
+	return fmt.Sprintf("%s (%s %s)", meta.Name, w.players.team(meta.TeamID), positionLabel(meta.PositionType))
+}
+
+// addAvailability reports the availability_watch changes since the previous
+// bootstrap refresh. Free agents back to fit are their own item type.
+func (w *newswire) addAvailability() error {
+	watch, err := buildAvailabilityWatch(w.cfg, AvailabilityWatchArgs{LeagueID: w.leagueID})
+	if err != nil {
+		return err
+	}
+	w.notes = append(w.notes, watch.Notes...)
+	for _, c := range watch.Changes {
+		label := w.playerLabel(c.Element)
+		item := NewswireItem{
+			Type:     newsStatusChange,
+			Gameweek: w.throughGW,
+			TimeUTC:  watch.RefreshUTC,
+			Elements: []int{c.Element},
+		}
+		switch {
+		case c.OwnerEntryID == 0 && c.Severity == availability.SeverityReturned:
+			item.Type = newsReturned
+			item.Summary = fmt.Sprintf("%s is available again and unowned", label)
+		case c.OwnerEntryID != 0:
+			item.EntryIDs = []int{c.OwnerEntryID}
+			item.Summary = fmt.Sprintf("%s's %s %s", c.OwnerName, label, statusPhrase(c))
+		default:
+			item.Summary = fmt.Sprintf("Free agent %s %s", label, statusPhrase(c))
+		}
+		w.items = append(w.items, item)
+	}
+	return nil
+}
+
+// statusPhrase describes an availability change: "is now doubtful: Knock -
+// 75% chance of playing".
+func statusPhrase(c AvailabilityChange) string {
+	switch c.Kind {
+	case availability.KindAdded:
+		return "was added to the game"
+	case availability.KindRemoved:
+		return "was removed from the game"
+	}
+	var phrase string
+	switch c.Severity {
+	case availability.SeverityOut:
+		phrase = "is now out"
+	case availability.SeverityDoubtful:
+		phrase = "is now doubtful"
+	case availability.SeverityReturned:
+		phrase = "is available again"
+	default:
+		phrase = "has new news"
+	}
+	if c.New != nil && strings.TrimSpace(c.New.News) != "" {
+		phrase += ": " + strings.TrimSpace(c.New.News)
+	}
+	return phrase
+}
+
+// addTransactions reports approved waiver and free-agent moves in the window.
+func (w *newswire) addTransactions() error {
+	transactions, err := loadTransactionsRaw(store.NewJSONStore(w.cfg.RawRoot), w.leagueID)
+	if err != nil {
+		return err
+	}
+	for _, tx := range approvedTransactions(transactions, w.fromGW, w.throughGW) {
+		item := NewswireItem{
+			Type:     newsWaiver,
+			Gameweek: tx.Event,
+			TimeUTC:  newswireTime(tx.Added),
+			EntryIDs: []int{tx.Entry},
+		}
+		how := "claimed %s off waivers"
+		if tx.Kind == "f" {
+			item.Type = newsFreeAgent
+			how = "signed %s as a free agent"
+		}
+		summary := fmt.Sprintf("%s "+how, w.entryName[tx.Entry], w.playerLabel(tx.ElementIn))
+		item.Elements = append(item.Elements, tx.ElementIn)
+		if tx.ElementOut != 0 {
+			summary += ", dropping " + w.playerLabel(tx.ElementOut)
+			item.Elements = append(item.Elements, tx.ElementOut)
+		}
+		item.Summary = summary
+		w.items = append(w.items, item)
+	}
+	return nil
+}
+
+// addTrades reports trades processed in the window.
+func (w *newswire) addTrades() error {
+	trades, err := loadTradesRaw(store.NewJSONStore(w.cfg.RawRoot), w.leagueID)
+	if err != nil {
+		return err
+	}
+	for _, tr := range trades {
+		if tr.State != "p" || tr.Event < w.fromGW || tr.Event > w.throughGW {
+			continue
+		}
+		fromOfferer, fromReceiver := tradeSides(tr)
+		var sent, received []string
+		for _, el := range fromOfferer {
+			sent = append(sent, w.playerLabel(el))
+		}
+		for _, el := range fromReceiver {
+			received = append(received, w.playerLabel(el))
+		}
+		elements := slices.Concat(fromOfferer, fromReceiver)
+		w.items = append(w.items, NewswireItem{
+			Type:     newsTrade,
+			Gameweek: tr.Event,
+			TimeUTC:  newswireTime(tr.ResponseTime),
+			Elements: elements,
+			EntryIDs: []int{tr.OfferedEntry, tr.ReceivedEntry},
+			Summary: fmt.Sprintf("%s traded %s to %s for %s",
+				w.entryName[tr.OfferedEntry], joinOrNothing(sent), w.entryName[tr.ReceivedEntry], joinOrNothing(received)),
+		})
+	}
+	return nil
+}
+
+func joinOrNothing(names []string) string {
+	if len(names) == 0 {
+		return "nothing"
+	}
+	return strings.Join(names, " and ")
+}
+
+// addBreakouts reports unowned players who scored breakoutPoints or more in
+// the latest finished GW.
+func (w *newswire) addBreakouts() error {
+	gw, _, err := resolveAsOfAndNextGW(w.cfg, 0, 0)
+	if err != nil {
+		return err
+	}
+	if gw < w.fromGW || gw > w.throughGW {
+		return nil
+	}
+	live, err := loadLiveStats(w.cfg.RawRoot, gw)
+	if err != nil {
+		return err
+	}
+	ownership, err := loadOwnershipAtGW(w.cfg, w.leagueID, gw)
+	if err != nil {
+		return err
+	}
+	owned := make(map[int]bool)
+	for _, roster := range ownership {
+		for element := range roster {
+			owned[element] = true
+		}
+	}
+	elements := make([]int, 0)
+	for element, stats := range live {
+		if !owned[element] && stats.TotalPoints >= breakoutPoints {
+			elements = append(elements, element)
+		}
+	}
+	sort.Slice(elements, func(i, j int) bool {
+		if live[elements[i]].TotalPoints != live[elements[j]].TotalPoints {
+			return live[elements[i]].TotalPoints > live[elements[j]].TotalPoints
+		}
+		return elements[i] < elements[j]
+	})
+	for _, element := range elements {
+		stats := live[element]
+		w.items = append(w.items, NewswireItem{
+			Type:     newsBreakout,
+			Gameweek: gw,
+			Elements: []int{element},
+			Summary:  fmt.Sprintf("Unowned %s scored %d points in GW %d", w.playerLabel(element), stats.TotalPoints, gw),
+		})
+	}
+	return nil
+}
+
+// newswireTime normalizes an API timestamp to RFC3339 UTC, or "" when it
+// can't be parsed.
+func newswireTime(s string) string {
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeNewswireFixture extends writeClaimFixture with a bootstrap refresh in
+// which Kudus (11, free agent) returns from injury and Saka (4) picks up a
+// knock, moves across GW1-3, and GW3 points where Kudus scores 14.
+func writeNewswireFixture(t *testing.T, dir string) {
+	t.Helper()
+	writeClaimFixture(t, dir)
+	el := func(id int, name string, pos int, status string, news string) map[string]any {
+		return map[string]any{"id": id, "web_name": name, "team": 10, "element_type": pos, "status": status, "news": news, "total_points": 40}
+	}
+	teams := []any{map[string]any{"id": 10, "short_name": "LIV"}}
+	unchanged := []any{
+		el(1, "Salah", 3, "a", ""), el(2, "Palmer", 3, "a", ""), el(3, "Gabriel", 2, "a", ""),
+		el(5, "Isak", 4, "a", ""), el(10, "Mbeumo", 3, "a", ""), el(13, "Munoz", 2, "a", ""),
+	}
+	writeJSON(t, filepath.Join(dir, "bootstrap", "bootstrap-static.prev.json"), map[string]any{
+		"elements": append([]any{el(4, "Saka", 3, "a", ""), el(11, "Kudus", 3, "i", "Ankle")}, unchanged...),
+		"teams":    teams,
+	})
+	writeJSON(t, filepath.Join(dir, "bootstrap", "bootstrap-static.json"), map[string]any{
+		"elements": append([]any{el(4, "Saka", 3, "d", "Knock"), el(11, "Kudus", 3, "a", "")}, unchanged...),
+		"teams":    teams,
+		"fixtures": map[string]any{},
+	})
+	writeJSON(t, filepath.Join(dir, "league/100/transactions.json"), map[string]any{"transactions": []any{
+		map[string]any{"id": 1, "entry": 201, "element_in": 12, "element_out": 0, "event": 1, "kind": "f", "result": "a", "added": "2025-08-20T10:00:00Z"},
+		map[string]any{"id": 2, "entry": 202, "element_in": 10, "element_out": 5, "event": 3, "kind": "w", "result": "a", "added": "2025-09-01T09:00:00.5Z"},
+		map[string]any{"id": 3, "entry": 201, "element_in": 11, "element_out": 4, "event": 3, "kind": "w", "result": "di", "added": "2025-09-01T09:00:00Z"},
+		map[string]any{"id": 4, "entry": 200, "element_in": 13, "element_out": 3, "event": 2, "kind": "f", "result": "a", "added": "2025-08-25T12:00:00Z"},
+	}})
+	writeJSON(t, filepath.Join(dir, "league/100/trades.json"), map[string]any{"trades": []any{
+		map[string]any{"id": 9, "event": 3, "offered_entry": 200, "received_entry": 201, "state": "p", "response_time": "2025-09-02T08:00:00Z",
+			"tradeitem_set": []any{map[string]any{"element_out": 2, "element_in": 4}}},
+		map[string]any{"id": 10, "event": 3, "offered_entry": 201, "received_entry": 202, "state": "r"},
+	}})
+	writeLiveJSON(t, dir, 3, map[string]any{"13": makeStats(3), "11": makeStats(14), "1": makeStats(15)})
+}
+
+func TestBuildLeagueNewswire(t *testing.T) {
+	dir, cfg := resourceCfg(t)
+	writeNewswireFixture(t, dir)
+
+	out, err := buildLeagueNewswire(cfg, LeagueNewswireArgs{LeagueID: 100})
+	if err != nil {
+		t.Fatalf("buildLeagueNewswire: %v", err)
+	}
+	if out.FromGW != 2 || out.ThroughGW != 3 {
+		t.Errorf("window GW %d-%d, want 2-3", out.FromGW, out.ThroughGW)
+	}
+	// GW3 first: the unowned breakout (untimed, end of GW), then the rest
+	// newest first; the GW1 signing is outside the window and the rejected
+	// claim and trade are not news.
+	want := []struct {
+		typ     string
+		gw      int
+		summary string
+	}{
+		{newsBreakout, 3, "Unowned Kudus (LIV MID) scored 14 points in GW 3"},
+		{newsStatusChange, 3, ""},
+		{newsReturned, 3, "Kudus (LIV MID) is available again and unowned"},
+		{newsTrade, 3, "Alpha FC traded Palmer (LIV MID) to Beta FC for Saka (LIV MID)"},
+		{newsWaiver, 3, "Gamma FC claimed Mbeumo (LIV MID) off waivers, dropping Isak (LIV FWD)"},
+		{newsFreeAgent, 2, "Alpha FC signed Munoz (LIV DEF) as a free agent, dropping Gabriel (LIV DEF)"},
+	}
+	if len(out.Items) != len(want) || out.Total != len(want) {
+		t.Fatalf("items = %+v, want %d", out.Items, len(want))
+	}
+	for i, w := range want {
+		it := out.Items[i]
+		if it.Type != w.typ || it.Gameweek != w.gw || (w.summary != "" && it.Summary != w.summary) {
+			t.Errorf("item %d = %s GW%d %q, want %s GW%d %q", i, it.Type, it.Gameweek, it.Summary, w.typ, w.gw, w.summary)
+		}
+	}
+	// Saka moved to Alpha in the GW3 trade.
+	if s := out.Items[1].Summary; s != "Alpha FC's Saka (LIV MID) is now doubtful: Knock" {
+		t.Errorf("status change summary = %q", s)
+	}
+	if it := out.Items[4]; it.TimeUTC != "2025-09-01T09:00:00Z" || len(it.Elements) != 2 || it.EntryIDs[0] != 202 {
+		t.Errorf("waiver item = %+v", it)
+	}
+
+	two := 2
+	capped, err := buildLeagueNewswire(cfg, LeagueNewswireArgs{LeagueID: 100, MaxItems: &two})
+	if err != nil || len(capped.Items) != 2 || capped.Total != len(want) {
+		t.Errorf("max_items 2: %d of %d items, err %v", len(capped.Items), capped.Total, err)
+	}
+}
+
+func TestBuildLeagueNewswire_MissingSources(t *testing.T) {
+	dir, cfg := resourceCfg(t)
+	writeClaimFixture(t, dir)
+
+	out, err := buildLeagueNewswire(cfg, LeagueNewswireArgs{LeagueID: 100})
+	if err != nil {
+		t.Fatalf("buildLeagueNewswire: %v", err)
+	}
+	// No previous bootstrap and no GW3 live data: both are noted, and the
+	// empty transactions and trades still count as sources.
+	if len(out.Items) != 0 {
+		t.Errorf("items = %+v, want none", out.Items)
+	}
+	var live bool
+	for _, n := range out.Notes {
+		if strings.HasPrefix(n, "No unowned performances") {
+			live = true
+		}
+	}
+	if !live || len(out.Notes) != 3 {
+		t.Errorf("notes = %q", out.Notes)
+	}
+	if _, err := buildLeagueNewswire(cfg, LeagueNewswireArgs{}); classifyError(err).Code != codeInvalidArgument {
+		t.Errorf("missing league: err = %v", err)
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// leagueRootsFlag collects repeatable --league-root league_id=path values.
+// Each path is a data directory laid out like the default one, i.e. with
+// raw/ and derived/ subdirectories.
+type leagueRootsFlag map[int]string
+
+func (f leagueRootsFlag) String() string {
+	ids := make([]int, 0, len(f))
+	for id := range f {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	parts := make([]string, 0, len(ids))
+	for _, id := range ids {
+		parts = append(parts, fmt.Sprintf("%d=%s", id, f[id]))
+	}
+	return strings.Join(parts, ",")
+}
+
+func (f leagueRootsFlag) Set(v string) error {
+	idStr, path, ok := strings.Cut(v, "=")
+	if !ok {
+		return fmt.Errorf("expected league_id=path, got %q", v)
+	}
+	id, err := strconv.Atoi(strings.TrimSpace(idStr))
+	if err != nil || id <= 0 {
+		return fmt.Errorf("invalid league id %q", idStr)
+	}
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return fmt.Errorf("empty path for league %d", id)
+	}
+	f[id] = path
+	return nil
+}
+
+// forLeague returns the config to use for reads scoped to leagueID. Leagues
+// with a configured --league-root get the current season of that directory's
+// raw/ and derived/ roots; every other league (and leagueID 0) keeps the
+// default roots.
+func (cfg ServerConfig) forLeague(leagueID int) ServerConfig {
+	root, ok := cfg.LeagueRoots[leagueID]
+	if !ok {
+		return cfg
+	}
+	return cfg.withSeasonRoots(filepath.Join(root, "raw"), filepath.Join(root, "derived"))
+}
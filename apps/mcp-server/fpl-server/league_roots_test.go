@@ -0,0 +1,96 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestLeagueRootsFlag_Set(t *testing.T) {
+	f := leagueRootsFlag{}
+	if err := f.Set("100=/srv/a"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if err := f.Set(" 200 = /srv/b "); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if f[100] != "/srv/a" || f[200] != "/srv/b" {
+		t.Errorf("got %v", map[int]string(f))
+	}
+	if got := f.String(); got != "100=/srv/a,200=/srv/b" {
+		t.Errorf("String() = %q", got)
+	}
+	for _, bad := range []string{"100", "abc=/srv", "0=/srv", "100="} {
+		if err := f.Set(bad); err == nil {
+			t.Errorf("Set(%q): expected error", bad)
+		}
+	}
+}
+
+func TestForLeague_DefaultsForUnconfiguredLeague(t *testing.T) {
+	cfg := ServerConfig{
+		RawRoot:     "data/raw",
+		DerivedRoot: "data/derived",
+		LeagueRoots: map[int]string{100: "/srv/a"},
+	}
+	got := cfg.forLeague(999)
+	if got.RawRoot != "data/raw" || got.DerivedRoot != "data/derived" {
+		t.Errorf("unconfigured league: got raw=%s derived=%s", got.RawRoot, got.DerivedRoot)
+	}
+	got = cfg.forLeague(100)
+	if got.RawRoot != filepath.Join("/srv/a", "raw") || got.DerivedRoot != filepath.Join("/srv/a", "derived") {
+		t.Errorf("configured league: got raw=%s derived=%s", got.RawRoot, got.DerivedRoot)
+	}
+	if again := got.forLeague(100); again.RawRoot != got.RawRoot {
+		t.Errorf("forLeague not idempotent: %s vs %s", again.RawRoot, got.RawRoot)
+	}
+}
+
+// TestForLeague_IndependentBootstraps confirms two leagues served by the same
+// process read players from their own bootstrap files.
+func TestForLeague_IndependentBootstraps(t *testing.T) {
+	rootA := t.TempDir()
+	rootB := t.TempDir()
+	rawA := filepath.Join(rootA, "raw")
+	rawB := filepath.Join(rootB, "raw")
+
+	writeBootstrap(t, rawA)
+	writeJSON(t, filepath.Join(rawB, "bootstrap", "bootstrap-static.json"), map[string]any{
+		"elements": []any{
+			map[string]any{"id": 1, "web_name": "Palmer", "team": 20, "element_type": 3, "status": "a", "total_points": 160},
+		},
+		"teams":    []any{map[string]any{"id": 20, "short_name": "CHE"}},
+		"fixtures": map[string]any{},
+	})
+	choices := map[string]any{
+		"choices": []any{
+			map[string]any{"entry": 200, "entry_name": "Alpha FC", "element": 1, "round": 1, "pick": 1, "index": 1},
+		},
+	}
+	writeJSON(t, filepath.Join(rawA, "draft/100/choices.json"), choices)
+	writeJSON(t, filepath.Join(rawB, "draft/200/choices.json"), choices)
+
+	cfg := ServerConfig{
+		RawRoot:     t.TempDir(),
+		LeagueRoots: map[int]string{100: rootA, 200: rootB},
+	}
+
+	outA, err := buildDraftPicks(cfg.forLeague(100), DraftPicksArgs{LeagueID: 100})
+	if err != nil {
+		t.Fatalf("league 100: %v", err)
+	}
+	outB, err := buildDraftPicks(cfg.forLeague(200), DraftPicksArgs{LeagueID: 200})
+	if err != nil {
+		t.Fatalf("league 200: %v", err)
+	}
+	if outA.Picks[0].PlayerName != "Salah" {
+		t.Errorf("league 100 element 1 = %q, want Salah", outA.Picks[0].PlayerName)
+	}
+	if outB.Picks[0].PlayerName != "Palmer" {
+		t.Errorf("league 200 element 1 = %q, want Palmer", outB.Picks[0].PlayerName)
+	}
+
+	// A league without its own root falls back to the (empty) default root.
+	if _, err := buildDraftPicks(cfg.forLeague(300), DraftPicksArgs{LeagueID: 300}); err == nil {
+		t.Error("expected error for league with no data in the default root")
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/leagueconfig"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/summary"
+)
+
+type LeagueSettingsArgs struct {
+	LeagueID int `json:"league_id" jsonschema:"Draft league id (required)"`
+}
+
+// LeagueSettingsOutput is the league's settings plus the standings
+// tiebreaker chain the server applies to it. When the settings don't name a
+// waiver day it comes from the next waivers time in bootstrap-static.json
+// (UTC), also given as NextWaiversUTC.
+type LeagueSettingsOutput struct {
+	leagueconfig.Config
+	Tiebreakers    []string `json:"tiebreakers"`
+	NextWaiversUTC string   `json:"next_waivers_utc,omitempty"`
+	Notes          []string `json:"notes,omitempty"`
+}
+
+func buildLeagueSettings(cfg ServerConfig, args LeagueSettingsArgs) (LeagueSettingsOutput, error) {
+	if args.LeagueID == 0 {
+		return LeagueSettingsOutput{}, invalidArgumentf("league_id is required")
+	}
+	lc, err := leagueconfig.Load(store.NewJSONStore(cfg.RawRoot), args.LeagueID)
+	if err != nil {
+		return LeagueSettingsOutput{}, err
+	}
+	out := LeagueSettingsOutput{Config: lc, Tiebreakers: cfg.Tiebreakers[args.LeagueID]}
+	if len(out.Tiebreakers) == 0 {
+		out.Tiebreakers = summary.DefaultTiebreakers
+	}
+	if len(lc.Defaulted) > 0 {
+		out.Notes = append(out.Notes, "Settings listed under defaulted are not in the league details and show the game's defaults.")
+	}
+
+	events, err := loadBootstrapEvents(cfg.RawRoot)
+	if err != nil {
+		out.Notes = append(out.Notes, "No bootstrap-static.json, so the next waivers time is unknown.")
+		return out, nil
+	}
+	for _, ev := range events {
+		if ev.Finished || ev.WaiversTime == "" {
+			continue
+		}
+		if at, ok := summary.ParseAPITime(ev.WaiversTime); ok {
+			out.NextWaiversUTC = ev.WaiversTime
+			if out.Waivers.Day == "" {
+				out.Waivers.Day = at.UTC().Weekday().String()
+			}
+		}
+		break
+	}
+	return out, nil
+}
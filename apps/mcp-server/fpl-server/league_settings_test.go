@@ -0,0 +1,65 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/leagueconfig"
+)
+
+func TestBuildLeagueSettings(t *testing.T) {
+	dir, cfg := tmpCfg(t)
+	writeJSON(t, filepath.Join(dir, "league/100/details.json"), map[string]any{
+		"league":         map[string]any{"name": "Sunday League", "scoring": "h", "trades": "y", "transaction_mode": "W", "start_event": 1, "draft_dt": "2025-08-10T18:00:00Z"},
+		"league_entries": []any{},
+		"matches":        []any{},
+	})
+	writeBootstrapEvents(t, dir, []map[string]any{
+		{"id": 1, "finished": true, "waivers_time": "2025-08-14T17:30:00Z"},
+		{"id": 2, "finished": false, "waivers_time": "2025-08-21T17:30:00Z"},
+	}, nil)
+	cfg.Tiebreakers = map[int][]string{100: {"h2h", "points_for"}}
+
+	out, err := buildLeagueSettings(cfg, LeagueSettingsArgs{LeagueID: 100})
+	if err != nil {
+		t.Fatalf("buildLeagueSettings: %v", err)
+	}
+	if out.LeagueID != 100 || out.Name != "Sunday League" || out.Scoring != leagueconfig.ScoringH2H || !out.Trades.Enabled {
+		t.Errorf("settings = %+v", out.Config)
+	}
+	// 21 August 2025 is a Thursday.
+	if out.Waivers.Day != "Thursday" || out.NextWaiversUTC != "2025-08-21T17:30:00Z" {
+		t.Errorf("waivers = %+v, next %q", out.Waivers, out.NextWaiversUTC)
+	}
+	if !reflect.DeepEqual(out.Tiebreakers, []string{"h2h", "points_for"}) {
+		t.Errorf("tiebreakers = %v", out.Tiebreakers)
+	}
+	if out.Squad.PositionLimits != leagueconfig.DefaultSquadLimits() || len(out.Defaulted) != 2 || len(out.Notes) != 1 {
+		t.Errorf("squad %+v, defaulted %v, notes %q", out.Squad, out.Defaulted, out.Notes)
+	}
+
+	if _, err := buildLeagueSettings(cfg, LeagueSettingsArgs{LeagueID: 101}); classifyError(err).Code != codeDataMissing {
+		t.Errorf("unknown league: err = %v, want DATA_MISSING", err)
+	}
+}
+
+// TestLoadSquadLimits_LeagueOverride checks that a league's own squad limits
+// reach the squad legality checks.
+func TestLoadSquadLimits_LeagueOverride(t *testing.T) {
+	dir, cfg := tmpCfg(t)
+	writeJSON(t, filepath.Join(dir, "league/100/details.json"), map[string]any{
+		"league": map[string]any{"squad_limits": map[string]any{"gk": 2, "def": 5, "mid": 4, "fwd": 4}},
+	})
+	limits, err := loadSquadLimits(cfg, 100)
+	if err != nil {
+		t.Fatalf("loadSquadLimits: %v", err)
+	}
+	counts := map[int]int{1: 2, 2: 5, 3: 4, 4: 3}
+	if !claimKeepsSquadLegal(limits, counts, 4, 3) {
+		t.Error("a fourth FWD is illegal under a 4-FWD league")
+	}
+	if claimKeepsSquadLegal(leagueconfig.DefaultSquadLimits(), counts, 4, 3) {
+		t.Error("a fourth FWD is legal under the default limits")
+	}
+}
@@ -2,17 +2,26 @@ package main
 
 import (
 	"context"
-	"crypto/subtle"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"io/fs"
 	"log"
+	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"slices"
 	"strings"
+	"syscall"
+	"time"
 
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/clock"
 	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/ledger"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/positions"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/scoring"
 	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
 	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/summary"
 
@@ -24,6 +33,55 @@ type ServerConfig struct {
 	DerivedRoot    string
 	WriteDerived   bool
 	ComputeMissing bool
+	// RawSeasonsRoot and DerivedSeasonsRoot are the --raw-root and
+	// --derived-root directories, which may hold one directory per season;
+	// RawRoot and DerivedRoot are the current season's directories inside
+	// them (see ServerConfig.withSeasonRoots and forSeason).
+	RawSeasonsRoot     string
+	DerivedSeasonsRoot string
+	// LeagueRoots maps league ids to per-league data directories; see
+	// ServerConfig.forLeague.
+	LeagueRoots map[int]string
+	// Tiebreakers maps league ids to the standings tiebreaker chain their
+	// rules use, when it isn't summary.DefaultTiebreakers.
+	Tiebreakers map[int][]string
+	// DerivedFormat is how computed summaries are written. Reads accept any
+	// format, so it can change without rebuilding the derived tree.
+	DerivedFormat store.DerivedFormat
+	// StaleAfter is how far the newest live.json may trail the last passed
+	// deadline before tool results are marked stale (0 = defaultStaleAfter).
+	StaleAfter time.Duration
+	// DashboardMaxBytes caps the league_dashboard response
+	// (0 = defaultDashboardMaxBytes).
+	DashboardMaxBytes int
+	// Scoring is the --scoring-config rules projections use; nil means
+	// official FPL scoring. A league's own settings can override it (see
+	// loadScoringRules).
+	Scoring *scoring.ScoringRules
+	// DefaultLeagueID is the league tool examples are filled in with
+	// (0 = leave them as placeholders).
+	DefaultLeagueID int
+	// Clock stamps computed summaries and results that report "now"; nil
+	// means the wall clock. Tests fix it so their output is reproducible.
+	Clock clock.Clock
+	// Fetcher makes the fetch client for a raw root when
+	// --allow-fetch-on-miss is on, so builds can fetch the raw files they
+	// find missing; nil reports them as DATA_MISSING instead.
+	// FetchOnMissLimit caps the fetches per tool call
+	// (0 = defaultFetchOnMissLimit).
+	Fetcher          func(rawRoot string) rawFetcher
+	FetchOnMissLimit int
+	// fetches is the current tool call's fetch-on-miss budget, set by
+	// forCall.
+	fetches *fetchBudget
+	// timing is the current tool call's, set by forCall; nil outside a
+	// call.
+	timing *callTiming
+}
+
+// now is cfg.Clock's current time.
+func (cfg ServerConfig) now() time.Time {
+	return clock.Or(cfg.Clock).Now()
 }
 
 type LeagueGWArgs struct {
@@ -37,26 +95,6 @@ type LeagueGWAndHorizonArgs struct {
 	Horizon  int `json:"horizon" jsonschema:"Rolling horizon in GWs (default 5)"`
 }
 
-type LeagueGWAndRiskArgs struct {
-	LeagueID int    `json:"league_id" jsonschema:"Draft league id (required)"`
-	GW       int    `json:"gw" jsonschema:"Gameweek (0 = current)"`
-	Horizon  int    `json:"horizon" jsonschema:"Rolling horizon in GWs (default 5)"`
-	Risk     string `json:"risk" jsonschema:"Risk level: low|med|high (default med)"`
-}
-
-type PlayerFormArgs struct {
-	LeagueID int `json:"league_id" jsonschema:"Draft league id (required)"`
-	Horizon  int `json:"horizon" jsonschema:"Rolling horizon in GWs (default 5)"`
-	AsOfGW   int `json:"as_of_gw" jsonschema:"As-of gameweek (0 = current)"`
-}
-
-type FixturesArgs struct {
-	LeagueID int  `json:"league_id" jsonschema:"Draft league id (required)"`
-	AsOfGW   *int `json:"as_of_gw,omitempty" jsonschema:"Start from gameweek (0 = current)"`
-	GW       *int `json:"gw,omitempty" jsonschema:"Alias for as_of_gw"`
-	Horizon  *int `json:"horizon,omitempty" jsonschema:"How many GWs forward (default 5)"`
-}
-
 type ManagerLookupArgs struct {
 	LeagueID int `json:"league_id" jsonschema:"Draft league id (required)"`
 	EntryID  int `json:"entry_id" jsonschema:"Entry id (required)"`
@@ -67,12 +105,14 @@ type PlayerLookupArgs struct {
 }
 
 type toolInfo struct {
-	Name        string `json:"name"`
-	Description string `json:"description"`
+	Name        string        `json:"name"`
+	Description string        `json:"description"`
+	Examples    []ToolExample `json:"examples,omitempty"`
 }
 
 func main() {
 	var (
+		transport      = flag.String("transport", transportHTTP, "MCP transport: http (streamable HTTP with auth) or stdio (for local clients that spawn the server)")
 		addr           = flag.String("addr", ":8080", "HTTP listen address")
 		mcpPath        = flag.String("path", "/mcp", "HTTP path for MCP endpoint")
 		rawRoot        = flag.String("raw-root", "data/raw", "root directory for raw JSON")
@@ -81,141 +121,276 @@ func main() {
 		computeMissing = flag.Bool("compute-missing", true, "compute summaries if missing")
 		requireAuth    = flag.Bool("require-auth", true, "require API key auth via FPL_MCP_API_KEY")
 		authHeader     = flag.String("auth-header", "X-API-Key", "HTTP header to read API key from")
+		derivedCompact = flag.Bool("derived-compact", false, "write derived JSON without indentation")
+		derivedGzip    = flag.Bool("derived-gzip", false, "gzip derived JSON as .json.gz (implies --derived-compact)")
+		compactLedger  = flag.Bool("derived-compact-ledger", false, "apply --derived-compact/--derived-gzip to the ledger and snapshots too")
+		staleHours     = flag.Float64("stale-after-hours", defaultStaleAfter.Hours(), "flag results stale when the newest live.json predates the last passed deadline by more than this")
+		dashboardMax   = flag.Int("dashboard-max-bytes", defaultDashboardMaxBytes, "largest league_dashboard response; the biggest sections are truncated to fit")
+		scoringConfig  = flag.String("scoring-config", "data/config/scoring.json", "optional JSON scoring overrides for projections; official FPL scoring when the file doesn't exist")
+		logLevel       = flag.String("log-level", "info", "request log level: debug, info, warn or error")
+		slowCallMS     = flag.Int("slow-call-ms", int(defaultSlowCall/time.Millisecond), "log tool calls at least this slow at warn with a timing breakdown (0 = off)")
+		defaultLeague  = flag.Int("default-league", defaultLeagueFromEnv(), "league id tool examples are filled in with (default $LEAGUE_ID)")
+		emitExamplesOn = flag.Bool("emit-examples", false, "run every tool example against the local data and save the outputs under <derived-root>/examples before serving")
+		fetchOnMiss    = flag.Bool("allow-fetch-on-miss", false, "fetch the raw live and entry event files a computed summary needs when they were never fetched")
+		fetchLimit     = flag.Int("fetch-on-miss-limit", defaultFetchOnMissLimit, "most raw files one tool call may fetch with --allow-fetch-on-miss")
+		leagueRoots    = leagueRootsFlag{}
+		tiebreakers    = leagueTiebreakersFlag{}
 	)
+	flag.Var(leagueRoots, "league-root", "per-league data root as league_id=path (repeatable); path holds raw/ and derived/")
+	flag.Var(tiebreakers, "tiebreakers", "standings tiebreaker chain as league_id=h2h,points_for,points_diff (repeatable)")
 	flag.Parse()
+	if *transport != transportHTTP && *transport != transportStdio {
+		log.Fatalf("--transport must be http or stdio, got %q", *transport)
+	}
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(*logLevel)); err != nil {
+		log.Fatalf("--log-level: %v", err)
+	}
+	// Request logs and the server's own messages go to stderr as JSON
+	// lines; stdout belongs to the stdio transport.
+	requestLog = newRequestLogger(os.Stderr, level, time.Duration(*slowCallMS)*time.Millisecond)
+	slog.SetDefault(requestLog.logger)
 
 	cfg := ServerConfig{
-		RawRoot:        *rawRoot,
-		DerivedRoot:    *derivedRoot,
 		WriteDerived:   *writeDerived,
 		ComputeMissing: *computeMissing,
+		LeagueRoots:    leagueRoots,
+		Tiebreakers:    tiebreakers,
+		DerivedFormat: store.DerivedFormat{
+			Compact:       *derivedCompact,
+			Gzip:          *derivedGzip,
+			IncludeLedger: *compactLedger,
+		},
+		StaleAfter:        time.Duration(*staleHours * float64(time.Hour)),
+		DashboardMaxBytes: *dashboardMax,
+		DefaultLeagueID:   *defaultLeague,
+		FetchOnMissLimit:  *fetchLimit,
+	}
+	if *fetchOnMiss {
+		cfg.Fetcher = newFetchClient
+	}
+	if rules, found, err := scoring.Load(*scoringConfig); err != nil {
+		log.Fatal(err)
+	} else if found {
+		cfg.Scoring = &rules
+	}
+	cfg = cfg.withSeasonRoots(*rawRoot, *derivedRoot)
+	store.SetDerivedFormat(cfg.DerivedFormat)
+
+	if *emitExamplesOn {
+		// Examples that fail to run are saved untested; only a failed
+		// write is worth reporting, and it shouldn't stop the server.
+		if err := emitExamples(context.Background(), cfg); err != nil {
+			log.Printf("emit-examples: %v", err)
+		}
+	}
+
+	server, watcher, tools := buildServer(cfg)
+	go watcher.run(context.Background(), resourcePollInterval)
+
+	if *transport == transportStdio {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		log.Printf("MCP stdio server running")
+		if err := runStdio(ctx, server, &mcp.StdioTransport{}); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	handler := mcp.NewStreamableHTTPHandler(func(r *http.Request) *mcp.Server {
+		return server
+	}, &mcp.StreamableHTTPOptions{JSONResponse: true})
+
+	apiKey := strings.TrimSpace(os.Getenv("FPL_MCP_API_KEY"))
+	if *requireAuth && apiKey == "" {
+		log.Fatal("FPL_MCP_API_KEY is required (set env var or run with --require-auth=false)")
 	}
 
+	withAuth := keyAuth(apiKey, *authHeader)
+
+	http.HandleFunc("/health", withAuth(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"status":"ok"}`))
+	}))
+
+	http.HandleFunc("/tools", withAuth(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		b, err := json.MarshalIndent(map[string]any{"tools": tools}, "", "  ")
+		if err != nil {
+			http.Error(w, `{"error":"failed to marshal tool list"}`, http.StatusInternalServerError)
+			return
+		}
+		w.Write(b)
+	}))
+
+	registerDataAgeGauge(mcpMetrics, cfg)
+	http.HandleFunc("/metrics", withAuth(mcpMetrics.registry.Handler().ServeHTTP))
+	http.HandleFunc("/resources", withAuth(resourceHTTPHandler(cfg)))
+
+	if adminKey := strings.TrimSpace(os.Getenv(adminKeyEnv)); adminKey != "" {
+		if adminKey == apiKey {
+			log.Fatalf("%s must differ from FPL_MCP_API_KEY", adminKeyEnv)
+		}
+		registerAdminRoutes(http.DefaultServeMux, cfg, adminKey, *authHeader)
+	} else {
+		log.Printf("%s is not set; /admin routes are disabled", adminKeyEnv)
+	}
+
+	http.HandleFunc(*mcpPath, withAuth(func(w http.ResponseWriter, r *http.Request) {
+		handler.ServeHTTP(w, r)
+	}))
+
+	log.Printf("MCP HTTP server listening on %s%s", *addr, *mcpPath)
+	if err := http.ListenAndServe(*addr, nil); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// buildServer registers every tool and resource on a new MCP server. The
+// HTTP and stdio transports both serve it; the caller runs the returned
+// watcher to push resources/updated notifications.
+func buildServer(cfg ServerConfig) (*mcp.Server, *resourceWatcher, []toolInfo) {
+	watcher := newResourceWatcher(cfg)
 	server := mcp.NewServer(
 		&mcp.Implementation{
 			Name:    "fpl-draft-mcp",
 			Version: "0.2.0",
 		},
-		nil,
+		&mcp.ServerOptions{
+			SubscribeHandler:   watcher.subscribe,
+			UnsubscribeHandler: watcher.unsubscribe,
+		},
 	)
+	watcher.server = server
+	registerResources(server, cfg)
 
-	registry := make([]toolInfo, 0, 16)
+	registry := toolRegistry{cfg: cfg, tools: make([]toolInfo, 0, 16), exampleValues: exampleValues(cfg)}
 
 	addTool(server, &registry, &mcp.Tool{
 		Name:        "player_form",
-		Description: "Rolling points/minutes/ownership for each player",
+		Description: "Rolling points/minutes/ownership per player, filtered by position, team, ownership (any/owned/unowned/mine) and minimum minutes, sorted by points, minutes, ownership or risk and capped at limit (default 50), or paged with page_size and cursor; format=markdown|csv returns a table",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, args PlayerFormArgs) (*mcp.CallToolResult, any, error) {
-		leagueID := args.LeagueID
-		if leagueID == 0 {
-			return toolError(fmt.Errorf("league_id is required")), nil, nil
-		}
-		h := args.Horizon
-		if h <= 0 {
-			h = 5
-		}
-		gw, err := resolveGW(cfg, args.AsOfGW)
+		out, err := buildPlayerForm(cfg.forCall(ctx, args.LeagueID), args)
 		if err != nil {
 			return toolError(err), nil, nil
 		}
-		relPath := fmt.Sprintf("summary/player_form/%d/h%d.json", leagueID, h)
-		return toolJSON(loadSummaryFile(cfg, leagueID, gw, relPath, []int{h}, []string{"low", "med", "high"}))
-	})
+		raw, err := json.MarshalIndent(out, "", "  ")
+		return toolFormatted(args.Format, playerFormTable, raw, err)
+	}, ToolExample{Args: map[string]any{"league_id": exampleLeague, "horizon": 5, "as_of_gw": 0, "owned": "unowned", "sort_by": "points_per_gw", "limit": 10}})
 
 	addTool(server, &registry, &mcp.Tool{
 		Name:        "waiver_targets",
-		Description: "Ranked add suggestions for your league",
-	}, func(ctx context.Context, req *mcp.CallToolRequest, args LeagueGWAndRiskArgs) (*mcp.CallToolResult, any, error) {
-		leagueID := args.LeagueID
-		if leagueID == 0 {
-			return toolError(fmt.Errorf("league_id is required")), nil, nil
-		}
-		gw, err := resolveGW(cfg, args.GW)
+		Description: "Ranked add suggestions for your league, the best 15 at each position; position_type (1=GK 2=DEF 3=MID 4=FWD) returns one position's list; need_aware re-ranks them by your roster's positional need (your points/GW per position vs the league average) and keeps each player's global rank",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args WaiverTargetsArgs) (*mcp.CallToolResult, any, error) {
+		raw, err := buildWaiverTargets(cfg.forCall(ctx, args.LeagueID), args)
+		return toolJSON(raw, err)
+	}, ToolExample{Args: map[string]any{"league_id": exampleLeague, "gw": 0, "horizon": 5, "risk": "med", "need_aware": true, "entry_id": exampleEntry}})
+
+	addTool(server, &registry, &mcp.Tool{
+		Name:        "waiver_recommendations",
+		Description: "Personalized waiver report (fixtures/form/points/xG) with drop suggestions, flagging drops that fill an upcoming opponent's weakest position (opponent_risk); format=markdown|csv returns the adds as a table; model=heuristic|poisson attaches a target-GW points projection to each add and drop; include_elements/include_players force named free agents into the list (forced: true) with the filters they fail",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args WaiverRecommendationsArgs) (*mcp.CallToolResult, any, error) {
+		out, err := buildWaiverRecommendations(cfg.forCall(ctx, args.LeagueID), args)
+		return toolFormatted(args.Format, waiverRecommendationsTable, out, err)
+	}, ToolExample{Args: map[string]any{"league_id": exampleLeague, "entry_id": exampleEntry}})
+
+	addTool(server, &registry, &mcp.Tool{
+		Name:        "recommendation_review",
+		Description: "Score past waiver_recommendations adds against their suggested drops over the following GWs: per-entry and league hit rates, whether managers followed them, best and worst calls, and calibration by score bucket",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args RecommendationReviewArgs) (*mcp.CallToolResult, any, error) {
+		out, err := buildRecommendationReview(cfg.forCall(ctx, args.LeagueID), args)
 		if err != nil {
 			return toolError(err), nil, nil
 		}
-		h := args.Horizon
-		if h <= 0 {
-			h = 5
-		}
-		risk := normalizeRisk(args.Risk)
-		relPath := fmt.Sprintf("summary/waiver_targets/%d/gw/%d_h%d_risk-%s.json", leagueID, gw, h, risk)
-		return toolJSON(loadSummaryFile(cfg, leagueID, gw, relPath, []int{h}, []string{risk}))
-	})
+		return toolMarshal(out)
+	}, ToolExample{Args: map[string]any{"league_id": exampleLeague}})
 
 	addTool(server, &registry, &mcp.Tool{
-		Name:        "waiver_recommendations",
-		Description: "Personalized waiver report (fixtures/form/points/xG) with drop suggestions",
-	}, func(ctx context.Context, req *mcp.CallToolRequest, args WaiverRecommendationsArgs) (*mcp.CallToolResult, any, error) {
-		out, err := buildWaiverRecommendations(cfg, args)
+		Name:        "claim_simulator",
+		Description: "Dry-run your ordered waiver claims (add/drop pairs) against the league's waiver order and other managers' claims, given or estimated from waiver_targets: outcome probabilities, your roster in each scenario, and which claims are most at risk",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args ClaimSimulatorArgs) (*mcp.CallToolResult, any, error) {
+		out, err := buildClaimSimulator(cfg.forCall(ctx, args.LeagueID), args)
 		if err != nil {
 			return toolError(err), nil, nil
 		}
-		return toolJSONBytes(out), nil, nil
-	})
+		return toolMarshal(out)
+	}, ToolExample{Args: map[string]any{"league_id": exampleLeague, "entry_id": exampleEntry, "claims": []any{map[string]any{"add": "<free_agent_element_id>", "drop": "<rostered_element_id>"}}}})
+
+	addTool(server, &registry, &mcp.Tool{
+		Name:        "league_dashboard",
+		Description: "Several league summaries in one call: any of standings, league_summary, matchups, transactions, lineup_efficiency, fixtures and waiver_targets (default all), keyed by section. A section that fails is reported under errors without failing the rest; the largest sections are truncated when the response would exceed the server's size limit",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args LeagueDashboardArgs) (*mcp.CallToolResult, any, error) {
+		out, err := buildLeagueDashboard(cfg.forCall(ctx, args.LeagueID), args)
+		return toolJSON(out, err)
+	}, ToolExample{Args: map[string]any{"league_id": exampleLeague, "gw": 0, "sections": []any{"standings", "matchups"}}})
 
 	addTool(server, &registry, &mcp.Tool{
 		Name:        "league_summary",
-		Description: "League weekly summary (roster, points, bench, record, opponent)",
-	}, func(ctx context.Context, req *mcp.CallToolRequest, args LeagueGWArgs) (*mcp.CallToolResult, any, error) {
+		Description: "League weekly summary (roster with each player's points and minutes, negative-points deductions, bench, record, opponent), the week's awards (top and lowest scorer, MVP, bust, bench hero, narrowest victory, every tie listed) plus managers flagged by inactivity_report's default checks under inactivity; format=markdown|csv returns a results table",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args LeagueGWFormatArgs) (*mcp.CallToolResult, any, error) {
 		leagueID := args.LeagueID
 		if leagueID == 0 {
-			return toolError(fmt.Errorf("league_id is required")), nil, nil
+			return toolError(invalidArgumentf("league_id is required")), nil, nil
 		}
-		gw, err := resolveGW(cfg, args.GW)
+		cfg := cfg.forCall(ctx, leagueID)
+		gw, note, err := resolveEffectiveGW(cfg, args.GW, gwModeLatestFinished)
 		if err != nil {
 			return toolError(err), nil, nil
 		}
 		relPath := fmt.Sprintf("summary/league/%d/gw/%d.json", leagueID, gw)
-		return toolJSON(loadSummaryFile(cfg, leagueID, gw, relPath, nil, nil))
-	})
+		raw, err := loadSummaryFile(cfg, leagueID, gw, relPath, nil, nil)
+		if err == nil {
+			raw = withInactivity(cfg, leagueID, gw, raw)
+		}
+		return toolFormatted(args.Format, leagueSummaryTable, withGWNote(raw, note), err)
+	}, ToolExample{Args: map[string]any{"league_id": exampleLeague, "gw": 0}})
 
 	addTool(server, &registry, &mcp.Tool{
 		Name:        "matchup_breakdown",
-		Description: "Points by position for each matchup (why you won/lost)",
-	}, func(ctx context.Context, req *mcp.CallToolRequest, args LeagueGWArgs) (*mcp.CallToolResult, any, error) {
-		leagueID := args.LeagueID
-		if leagueID == 0 {
-			return toolError(fmt.Errorf("league_id is required")), nil, nil
-		}
-		gw, err := resolveGW(cfg, args.GW)
-		if err != nil {
-			return toolError(err), nil, nil
-		}
-		relPath := fmt.Sprintf("summary/matchup/%d/gw/%d.json", leagueID, gw)
-		return toolJSON(loadSummaryFile(cfg, leagueID, gw, relPath, nil, nil))
-	})
+		Description: "Points by position and by player for each matchup, with any negative-points deductions (why you won/lost); include_players adds each side's starters with points and minutes, top scorer, biggest dud and the players who alone outscored the final margin",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args MatchupBreakdownArgs) (*mcp.CallToolResult, any, error) {
+		return toolJSON(buildMatchupBreakdown(cfg.forCall(ctx, args.LeagueID), args))
+	}, ToolExample{Args: map[string]any{"league_id": exampleLeague, "gw": 0, "include_players": true}})
 
 	addTool(server, &registry, &mcp.Tool{
 		Name:        "standings",
-		Description: "League standings table snapshot for a gameweek",
-	}, func(ctx context.Context, req *mcp.CallToolRequest, args LeagueGWArgs) (*mcp.CallToolResult, any, error) {
-		leagueID := args.LeagueID
-		if leagueID == 0 {
-			return toolError(fmt.Errorf("league_id is required")), nil, nil
-		}
-		gw, err := resolveGW(cfg, args.GW)
+		Description: "League standings table snapshot for a gameweek. Ties on match points are broken by the tiebreakers chain (h2h, points_for, points_diff; default points_diff then points_for) and each tied row carries a seeding_explanation. Rows also carry pythagorean expected wins (pythagorean_exponent, default 2.37), wins_over_expected and an all-play record; sort_by=expected_wins|all_play|luck reorders by them; format=markdown|csv returns a table",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args StandingsArgs) (*mcp.CallToolResult, any, error) {
+		raw, err := buildStandings(cfg.forCall(ctx, args.LeagueID), args)
+		return toolFormatted(args.Format, standingsTable, raw, err)
+	}, ToolExample{Args: map[string]any{"league_id": exampleLeague, "gw": 0, "sort_by": "luck"}})
+
+	addTool(server, &registry, &mcp.Tool{
+		Name:        "power_rankings",
+		Description: "Weekly power rankings: each entry's power_score blends recent (last 3 GWs) and season scoring, roster strength (rostered players' last-5 ppg) and all-play record, weights configurable (default 0.35/0.25/0.25/0.15), with movement against last GW's saved ranking and a justification naming the dominant factor",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args PowerRankingsArgs) (*mcp.CallToolResult, any, error) {
+		out, err := buildPowerRankings(cfg.forCall(ctx, args.LeagueID), args)
 		if err != nil {
 			return toolError(err), nil, nil
 		}
-		relPath := fmt.Sprintf("summary/standings/%d/gw/%d.json", leagueID, gw)
-		return toolJSON(loadSummaryFile(cfg, leagueID, gw, relPath, nil, nil))
-	})
+		return toolMarshal(out)
+	}, ToolExample{Args: map[string]any{"league_id": exampleLeague}})
 
 	addTool(server, &registry, &mcp.Tool{
-		Name:        "transactions",
-		Description: "Weekly waivers/free agents/trades digest per manager",
-	}, func(ctx context.Context, req *mcp.CallToolRequest, args LeagueGWArgs) (*mcp.CallToolResult, any, error) {
-		leagueID := args.LeagueID
-		if leagueID == 0 {
-			return toolError(fmt.Errorf("league_id is required")), nil, nil
-		}
-		gw, err := resolveGW(cfg, args.GW)
+		Name:        "manager_elo",
+		Description: "Elo rating per manager from finished H2H matches: everyone starts at 1500, each GW moves ratings by k_factor (default 20) scaled by the winning margin (mov_scaling, default 1); returns current and peak ratings, a per-GW trajectory for charting and win probabilities for upcoming matchups (horizon GWs, default 5)",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args ManagerEloArgs) (*mcp.CallToolResult, any, error) {
+		out, err := buildManagerElo(cfg.forCall(ctx, args.LeagueID), args)
 		if err != nil {
 			return toolError(err), nil, nil
 		}
-		relPath := fmt.Sprintf("summary/transactions/%d/gw/%d.json", leagueID, gw)
-		return toolJSON(loadSummaryFile(cfg, leagueID, gw, relPath, nil, nil))
-	})
+		return toolMarshal(out)
+	}, ToolExample{Args: map[string]any{"league_id": exampleLeague}})
+
+	addTool(server, &registry, &mcp.Tool{
+		Name:        "transactions",
+		Description: "Weekly waivers/free agents/trades digest per manager; page_size and cursor page through the managers; format=markdown|csv returns a table",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args TransactionsArgs) (*mcp.CallToolResult, any, error) {
+		raw, err := buildTransactions(cfg.forCall(ctx, args.LeagueID), args)
+		return toolFormatted(args.Format, transactionsTable, raw, err)
+	}, ToolExample{Args: map[string]any{"league_id": exampleLeague, "gw": 0}})
 
 	addTool(server, &registry, &mcp.Tool{
 		Name:        "lineup_efficiency",
@@ -223,15 +398,17 @@ func main() {
 	}, func(ctx context.Context, req *mcp.CallToolRequest, args LeagueGWArgs) (*mcp.CallToolResult, any, error) {
 		leagueID := args.LeagueID
 		if leagueID == 0 {
-			return toolError(fmt.Errorf("league_id is required")), nil, nil
+			return toolError(invalidArgumentf("league_id is required")), nil, nil
 		}
-		gw, err := resolveGW(cfg, args.GW)
+		cfg := cfg.forCall(ctx, leagueID)
+		gw, note, err := resolveEffectiveGW(cfg, args.GW, gwModeLatestFinished)
 		if err != nil {
 			return toolError(err), nil, nil
 		}
 		relPath := fmt.Sprintf("summary/lineup_efficiency/%d/gw/%d.json", leagueID, gw)
-		return toolJSON(loadSummaryFile(cfg, leagueID, gw, relPath, nil, nil))
-	})
+		raw, err := loadSummaryFile(cfg, leagueID, gw, relPath, nil, nil)
+		return toolJSON(withGWNote(raw, note), err)
+	}, ToolExample{Args: map[string]any{"league_id": exampleLeague, "gw": 0}})
 
 	addTool(server, &registry, &mcp.Tool{
 		Name:        "strength_of_schedule",
@@ -239,277 +416,547 @@ func main() {
 	}, func(ctx context.Context, req *mcp.CallToolRequest, args LeagueGWArgs) (*mcp.CallToolResult, any, error) {
 		leagueID := args.LeagueID
 		if leagueID == 0 {
-			return toolError(fmt.Errorf("league_id is required")), nil, nil
+			return toolError(invalidArgumentf("league_id is required")), nil, nil
 		}
-		gw, err := resolveGW(cfg, args.GW)
+		cfg := cfg.forCall(ctx, leagueID)
+		gw, note, err := resolveEffectiveGW(cfg, args.GW, gwModeLatestFinished)
 		if err != nil {
 			return toolError(err), nil, nil
 		}
 		relPath := fmt.Sprintf("summary/strength_of_schedule/%d/gw/%d.json", leagueID, gw)
-		return toolJSON(loadSummaryFile(cfg, leagueID, gw, relPath, nil, nil))
-	})
+		raw, err := loadSummaryFile(cfg, leagueID, gw, relPath, nil, nil)
+		return toolJSON(withGWNote(raw, note), err)
+	}, ToolExample{Args: map[string]any{"league_id": exampleLeague, "gw": 0}})
 
 	addTool(server, &registry, &mcp.Tool{
 		Name:        "ownership_scarcity",
-		Description: "Ownership counts by position and hoarders",
+		Description: "Ownership counts by position and hoarders, plus each entry's depth_chart (players per position ranked by last-5 points per GW, starters of the best formation, starter_bench_gap) and thin_positions where no bench player of 2.5+ points per GW covers an injured starter",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, args LeagueGWArgs) (*mcp.CallToolResult, any, error) {
 		leagueID := args.LeagueID
 		if leagueID == 0 {
-			return toolError(fmt.Errorf("league_id is required")), nil, nil
+			return toolError(invalidArgumentf("league_id is required")), nil, nil
 		}
-		gw, err := resolveGW(cfg, args.GW)
+		cfg := cfg.forCall(ctx, leagueID)
+		gw, note, err := resolveEffectiveGW(cfg, args.GW, gwModeLatestFinished)
 		if err != nil {
 			return toolError(err), nil, nil
 		}
 		relPath := fmt.Sprintf("summary/ownership_scarcity/%d/gw/%d.json", leagueID, gw)
-		return toolJSON(loadSummaryFile(cfg, leagueID, gw, relPath, nil, nil))
-	})
+		raw, err := loadSummaryFile(cfg, leagueID, gw, relPath, nil, nil)
+		return toolJSON(withGWNote(raw, note), err)
+	}, ToolExample{Args: map[string]any{"league_id": exampleLeague, "gw": 0}})
 
 	addTool(server, &registry, &mcp.Tool{
 		Name:        "fixtures",
-		Description: "Upcoming fixtures from bootstrap-static",
+		Description: "Upcoming fixtures from bootstrap-static, with kickoffs also shown in tz (IANA name, default UTC) and relative to now; page_size and cursor page through long horizons",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, args FixturesArgs) (*mcp.CallToolResult, any, error) {
-		leagueID := args.LeagueID
-		if leagueID == 0 {
-			return toolError(fmt.Errorf("league_id is required")), nil, nil
-		}
-		asOf := 0
-		if args.AsOfGW != nil {
-			asOf = *args.AsOfGW
-		} else if args.GW != nil {
-			asOf = *args.GW
-		}
-		gw, err := resolveGW(cfg, asOf)
+		out, err := buildFixtures(cfg.forCall(ctx, args.LeagueID), args, cfg.now())
 		if err != nil {
 			return toolError(err), nil, nil
 		}
-		h := 0
-		if args.Horizon != nil {
-			h = *args.Horizon
-		}
-		if h <= 0 {
-			h = 5
-		}
-		relPath := fmt.Sprintf("summary/fixtures/%d/from_gw/%d_h%d.json", leagueID, gw, h)
-		return toolJSON(loadSummaryFile(cfg, leagueID, gw, relPath, []int{h}, []string{"low", "med", "high"}))
-	})
+		return toolMarshal(out)
+	}, ToolExample{Args: map[string]any{"league_id": exampleLeague, "horizon": 3, "tz": "Europe/London"}})
 
 	addTool(server, &registry, &mcp.Tool{
 		Name:        "fixture_difficulty",
-		Description: "Rank next-gameweek fixtures by opponent points conceded per position (home/away), with season/recent blend",
+		Description: "Rank next-gameweek fixtures by opponent points conceded per position (home/away), with season/recent blend; filter to a team or player and rank runs of up to 8 GWs with gw_count",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, args FixtureDifficultyArgs) (*mcp.CallToolResult, any, error) {
-		out, err := buildFixtureDifficulty(cfg, args)
+		out, err := buildFixtureDifficulty(cfg.forCall(ctx, args.LeagueID), args)
 		if err != nil {
 			return toolError(err), nil, nil
 		}
 		return toolMarshal(out)
-	})
+	}, ToolExample{Args: map[string]any{"league_id": exampleLeague, "gw_count": 5}})
+
+	addTool(server, &registry, &mcp.Tool{
+		Name:        "schedule_swing",
+		Description: "Rank PL teams by how their fixtures change: average blended difficulty over the next short_horizon GWs vs the following long_horizon, per position, with the top unrostered players from each team",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args ScheduleSwingArgs) (*mcp.CallToolResult, any, error) {
+		out, err := buildScheduleSwing(cfg.forCall(ctx, args.LeagueID), args)
+		if err != nil {
+			return toolError(err), nil, nil
+		}
+		return toolMarshal(out)
+	}, ToolExample{Args: map[string]any{"league_id": exampleLeague}})
+
+	addTool(server, &registry, &mcp.Tool{
+		Name:        "team_sos",
+		Description: "Rest-of-season strength of schedule for each Premier League team: average blended points conceded by their remaining opponents per position (home/away aware), ranked easiest first with fixture lists; entry_id places that roster's players on easy or hard runs",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args TeamSOSArgs) (*mcp.CallToolResult, any, error) {
+		out, err := buildTeamSOS(cfg.forCall(ctx, args.LeagueID), args)
+		if err != nil {
+			return toolError(err), nil, nil
+		}
+		return toolMarshal(out)
+	}, ToolExample{Args: map[string]any{"league_id": exampleLeague, "entry_id": exampleEntry}})
+
+	addTool(server, &registry, &mcp.Tool{
+		Name:        "team_defense_profile",
+		Description: "Per Premier League team (or all): points conceded to each position at home and away over the season and the last horizon GWs (default 5), with league ranks and the recent-minus-season trend, plus the attacking view of points scored by the team's own players; format=markdown|csv returns a table",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args TeamDefenseProfileArgs) (*mcp.CallToolResult, any, error) {
+		out, err := buildTeamDefenseProfile(cfg.forCall(ctx, 0), args)
+		if err != nil {
+			return toolError(err), nil, nil
+		}
+		raw, err := json.MarshalIndent(out, "", "  ")
+		return toolFormatted(args.Format, teamDefenseProfileTable, raw, err)
+	}, ToolExample{Args: map[string]any{"team": "all", "horizon": 5}})
 
 	addTool(server, &registry, &mcp.Tool{
 		Name:        "player_lookup",
 		Description: "Lookup a player by element id",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, args PlayerLookupArgs) (*mcp.CallToolResult, any, error) {
 		if args.ElementID == 0 {
-			return toolError(fmt.Errorf("element_id is required")), nil, nil
+			return toolError(invalidArgumentf("element_id is required")), nil, nil
 		}
 		out, err := lookupPlayer(cfg, args.ElementID)
 		if err != nil {
 			return toolError(err), nil, nil
 		}
 		return toolJSONBytes(out), nil, nil
-	})
+	}, ToolExample{Args: map[string]any{"element_id": exampleElement}})
+
+	addTool(server, &registry, &mcp.Tool{
+		Name:        "roster_changes",
+		Description: "What changed in an entry's roster between two gameweeks (from_gw to to_gw, default the last two), GW by GW: players added and removed with the waiver, free-agent move or trade behind each, bench/XI moves and armband changes; changes no move explains are flagged as anomalies",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args RosterChangesArgs) (*mcp.CallToolResult, any, error) {
+		out, err := buildRosterChanges(cfg.forCall(ctx, args.LeagueID), args)
+		if err != nil {
+			return toolError(err), nil, nil
+		}
+		return toolMarshal(out)
+	}, ToolExample{Args: map[string]any{"league_id": exampleLeague, "entry_id": exampleEntry}})
 
 	addTool(server, &registry, &mcp.Tool{
 		Name:        "manager_lookup",
 		Description: "Lookup a manager by entry id",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, args ManagerLookupArgs) (*mcp.CallToolResult, any, error) {
 		if args.LeagueID == 0 {
-			return toolError(fmt.Errorf("league_id is required")), nil, nil
+			return toolError(invalidArgumentf("league_id is required")), nil, nil
 		}
 		if args.EntryID == 0 {
-			return toolError(fmt.Errorf("entry_id is required")), nil, nil
+			return toolError(invalidArgumentf("entry_id is required")), nil, nil
 		}
-		out, err := lookupManager(cfg, args.LeagueID, args.EntryID)
+		out, err := lookupManager(cfg.forCall(ctx, args.LeagueID), args.LeagueID, args.EntryID)
 		if err != nil {
 			return toolError(err), nil, nil
 		}
 		return toolJSONBytes(out), nil, nil
-	})
+	}, ToolExample{Args: map[string]any{"league_id": exampleLeague, "entry_id": exampleEntry}})
 
 	addTool(server, &registry, &mcp.Tool{
 		Name:        "manager_schedule",
 		Description: "Manager schedule from league details (no entry snapshots required)",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, args ManagerScheduleArgs) (*mcp.CallToolResult, any, error) {
-		out, err := buildManagerSchedule(cfg, args)
+		out, err := buildManagerSchedule(cfg.forCall(ctx, args.LeagueID), args)
 		if err != nil {
 			return toolError(err), nil, nil
 		}
 		return toolMarshal(out)
-	})
+	}, ToolExample{Args: map[string]any{"league_id": exampleLeague, "entry_id": exampleEntry}})
 
 	addTool(server, &registry, &mcp.Tool{
 		Name:        "manager_streak",
 		Description: "Win-streak stats for a manager using league details",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, args ManagerStreakArgs) (*mcp.CallToolResult, any, error) {
-		out, err := buildManagerStreak(cfg, args)
+		out, err := buildManagerStreak(cfg.forCall(ctx, args.LeagueID), args)
 		if err != nil {
 			return toolError(err), nil, nil
 		}
 		return toolMarshal(out)
-	})
+	}, ToolExample{Args: map[string]any{"league_id": exampleLeague, "entry_id": exampleEntry}})
 
 	addTool(server, &registry, &mcp.Tool{
 		Name:        "league_entries",
 		Description: "List league teams (entry id/name) from league details",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, args LeagueEntriesArgs) (*mcp.CallToolResult, any, error) {
-		out, err := buildLeagueEntries(cfg, args.LeagueID)
+		out, err := buildLeagueEntries(cfg.forCall(ctx, args.LeagueID), args.LeagueID)
 		if err != nil {
 			return toolError(err), nil, nil
 		}
 		return toolMarshal(out)
-	})
+	}, ToolExample{Args: map[string]any{"league_id": exampleLeague}})
+
+	addTool(server, &registry, &mcp.Tool{
+		Name:        "league_settings",
+		Description: "League configuration from its details: scoring type (h2h/classic), squad size and position limits, waiver mode and day, trades, draft date and status, admin info, and the standings tiebreaker chain in effect. Settings the details don't carry show the game's defaults and are listed under defaulted",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args LeagueSettingsArgs) (*mcp.CallToolResult, any, error) {
+		out, err := buildLeagueSettings(cfg.forCall(ctx, args.LeagueID), args)
+		if err != nil {
+			return toolError(err), nil, nil
+		}
+		return toolMarshal(out)
+	}, ToolExample{Args: map[string]any{"league_id": exampleLeague}})
 
 	addTool(server, &registry, &mcp.Tool{
 		Name:        "current_roster",
 		Description: "Show a manager's current squad (starters + bench) with player names, teams, and positions",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, args CurrentRosterArgs) (*mcp.CallToolResult, any, error) {
-		out, err := buildCurrentRoster(cfg, args)
+		out, err := buildCurrentRoster(cfg.forCall(ctx, args.LeagueID), args)
 		if err != nil {
 			return toolError(err), nil, nil
 		}
 		return toolMarshal(out)
-	})
+	}, ToolExample{Args: map[string]any{"league_id": exampleLeague, "entry_id": exampleEntry}})
 
 	addTool(server, &registry, &mcp.Tool{
 		Name:        "draft_picks",
 		Description: "Full draft history for the league or a specific team: round, pick, player, team, position",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, args DraftPicksArgs) (*mcp.CallToolResult, any, error) {
-		out, err := buildDraftPicks(cfg, args)
+		out, err := buildDraftPicks(cfg.forCall(ctx, args.LeagueID), args)
 		if err != nil {
 			return toolError(err), nil, nil
 		}
 		return toolMarshal(out)
-	})
+	}, ToolExample{Args: map[string]any{"league_id": exampleLeague, "entry_id": exampleEntry}})
+
+	addTool(server, &registry, &mcp.Tool{
+		Name:        "draft_board",
+		Description: "Draft grid by round from the derived ledger, optionally filtered by round or entry, with each pick's player, team, position, and season points so far; page_size and cursor page through the picks in draft order",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args DraftBoardArgs) (*mcp.CallToolResult, any, error) {
+		out, err := buildDraftBoard(cfg.forCall(ctx, args.LeagueID), args)
+		if err != nil {
+			return toolError(err), nil, nil
+		}
+		return toolMarshal(out)
+	}, ToolExample{Args: map[string]any{"league_id": exampleLeague}})
+
+	addTool(server, &registry, &mcp.Tool{
+		Name:        "draft_rankings",
+		Description: "Preseason draft prep from bootstrap alone (no gameweek data needed): positional tier lists and an overall board ordered by value over replacement for the league's size and squad limits, with the value drop-off between tiers. Values start from season points (last season's until the game resets them, else list price) scaled by availability and team strength; scoring=official ignores league scoring rules",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args DraftRankingsArgs) (*mcp.CallToolResult, any, error) {
+		out, err := buildDraftRankings(cfg.forCall(ctx, args.LeagueID), args)
+		if err != nil {
+			return toolError(err), nil, nil
+		}
+		return toolMarshal(out)
+	}, ToolExample{Args: map[string]any{"league_id": exampleLeague, "limit": 20}})
+
+	addTool(server, &registry, &mcp.Tool{
+		Name:        "draft_what_if",
+		Description: "Replay an entry's season with a different draft pick: the alternate starts whenever the original actually started (from lineup snapshots) and scores their real points, giving the per-GW and cumulative points delta, matches whose result would flip and the adjusted W/D/L record. Refuses when the alternate was already drafted before that slot. Pick the slot by original_element or round",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args DraftWhatIfArgs) (*mcp.CallToolResult, any, error) {
+		out, err := buildDraftWhatIf(cfg.forCall(ctx, args.LeagueID), args)
+		if err != nil {
+			return toolError(err), nil, nil
+		}
+		return toolMarshal(out)
+	}, ToolExample{Args: map[string]any{"league_id": exampleLeague, "entry_id": exampleEntry, "round": 1, "alternate_element": exampleElement}})
+
+	addTool(server, &registry, &mcp.Tool{
+		Name:        "historical_roster",
+		Description: "An entry's lineup for a past gameweek from its snapshot: starters and bench with the points each player scored that GW, plus automatic subs",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args HistoricalRosterArgs) (*mcp.CallToolResult, any, error) {
+		out, err := buildHistoricalRoster(cfg.forCall(ctx, args.LeagueID), args)
+		if err != nil {
+			return toolError(err), nil, nil
+		}
+		return toolMarshal(out)
+	}, ToolExample{Args: map[string]any{"league_id": exampleLeague, "entry_id": exampleEntry, "gw": 1}})
+
+	addTool(server, &registry, &mcp.Tool{
+		Name:        "entry_points",
+		Description: "An entry's derived points results for a gameweek or start_gw..end_gw range: each counted player's points after auto subs, and a discrepancy against the official H2H score with the delta and likely cause (bonus_not_final, auto_subs, late_stat_amendment)",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args EntryPointsArgs) (*mcp.CallToolResult, any, error) {
+		out, err := buildEntryPoints(cfg.forCall(ctx, args.LeagueID), args)
+		if err != nil {
+			return toolError(err), nil, nil
+		}
+		return toolMarshal(out)
+	}, ToolExample{Args: map[string]any{"league_id": exampleLeague, "entry_id": exampleEntry}})
 
 	addTool(server, &registry, &mcp.Tool{
 		Name:        "manager_season",
-		Description: "Season-long results for a manager: GW-by-GW scores, W/D/L record, highest/lowest scoring week",
+		Description: "Season-long results for a manager: GW-by-GW scores, W/D/L record, highest/lowest scoring week, margins, all-play record and luck index",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, args ManagerSeasonArgs) (*mcp.CallToolResult, any, error) {
-		out, err := buildManagerSeason(cfg, args)
+		out, err := buildManagerSeason(cfg.forCall(ctx, args.LeagueID), args)
 		if err != nil {
 			return toolError(err), nil, nil
 		}
 		return toolMarshal(out)
-	})
+	}, ToolExample{Args: map[string]any{"league_id": exampleLeague, "entry_id": exampleEntry}})
 
 	addTool(server, &registry, &mcp.Tool{
 		Name:        "transaction_analysis",
 		Description: "League-wide transaction analysis for a gameweek: most targeted positions, top added/dropped players, per-manager breakdown",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, args TransactionAnalysisArgs) (*mcp.CallToolResult, any, error) {
-		out, err := buildTransactionAnalysis(cfg, args)
+		out, err := buildTransactionAnalysis(cfg.forCall(ctx, args.LeagueID), args)
 		if err != nil {
 			return toolError(err), nil, nil
 		}
 		return toolMarshal(out)
-	})
+	}, ToolExample{Args: map[string]any{"league_id": exampleLeague, "gw": 0}})
+
+	addTool(server, &registry, &mcp.Tool{
+		Name:        "waiver_wire_trends",
+		Description: "League-wide add/drop momentum over the last N GWs (default 4): rising adds, players dropped by multiple managers, churn per manager, and second-chance pickups with points scored for each owner",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args WaiverWireTrendsArgs) (*mcp.CallToolResult, any, error) {
+		out, err := buildWaiverWireTrends(cfg.forCall(ctx, args.LeagueID), args)
+		if err != nil {
+			return toolError(err), nil, nil
+		}
+		return toolMarshal(out)
+	}, ToolExample{Args: map[string]any{"league_id": exampleLeague}})
+
+	addTool(server, &registry, &mcp.Tool{
+		Name:        "inactivity_report",
+		Description: "Flags managers who look inactive over the last window GWs (default 4): no transactions at all, 0-minute starters in consecutive GWs, the same lineup GW after GW, and the same non-playing player left in the XI. Each manager gets a severity score and level with the evidence for every signal; thresholds are arguments",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args InactivityReportArgs) (*mcp.CallToolResult, any, error) {
+		out, err := buildInactivityReport(cfg.forCall(ctx, args.LeagueID), args)
+		if err != nil {
+			return toolError(err), nil, nil
+		}
+		return toolMarshal(out)
+	}, ToolExample{Args: map[string]any{"league_id": exampleLeague}})
+
+	addTool(server, &registry, &mcp.Tool{
+		Name:        "player_usage",
+		Description: "One player's season in the league GW by GW: who owned him, whether he was started, benched or unowned, his points, and the draft pick and every waiver, free-agent and trade move involving him; totals points per owner, points while benched and points while unowned",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args PlayerUsageArgs) (*mcp.CallToolResult, any, error) {
+		out, err := buildPlayerUsage(cfg.forCall(ctx, args.LeagueID), args)
+		if err != nil {
+			return toolError(err), nil, nil
+		}
+		return toolMarshal(out)
+	}, ToolExample{Args: map[string]any{"league_id": exampleLeague, "element_id": exampleElement}})
+
+	addTool(server, &registry, &mcp.Tool{
+		Name:        "manager_tendencies",
+		Description: "Season transaction profile per manager: claims per GW, add hit rate vs the dropped player over 3 GWs, position bias, GWs held before dropping, trade frequency and acceptance, and panic drops (dropped player scored 10+ in the next 2 GWs). Optional entry_id focuses on one manager",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args ManagerTendenciesArgs) (*mcp.CallToolResult, any, error) {
+		out, err := buildManagerTendencies(cfg.forCall(ctx, args.LeagueID), args)
+		if err != nil {
+			return toolError(err), nil, nil
+		}
+		return toolMarshal(out)
+	}, ToolExample{Args: map[string]any{"league_id": exampleLeague}})
+
+	addTool(server, &registry, &mcp.Tool{
+		Name:        "trade_history",
+		Description: "Every processed trade in the league with the players exchanged and a retrospective grade: points each received player scored for their new owner until dropped or traded on, a per-side total and a winner-so-far verdict, plus a net-points-via-trades leaderboard. include_unprocessed adds offered/rejected/vetoed trades ungraded",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args TradeHistoryArgs) (*mcp.CallToolResult, any, error) {
+		out, err := buildTradeHistory(cfg.forCall(ctx, args.LeagueID), args)
+		if err != nil {
+			return toolError(err), nil, nil
+		}
+		return toolMarshal(out)
+	}, ToolExample{Args: map[string]any{"league_id": exampleLeague}})
+
+	addTool(server, &registry, &mcp.Tool{
+		Name:        "trade_review",
+		Description: "Neutral fairness review of a pending trade (trade_id, or entry_id/partner_entry_id with give/receive element ids): rest-of-season projection of each side, best-XI points by position before and after vs the league average, both teams' remaining matches priced with and without the trade, and a 0-100 fairness score labelled reasonable, lopsided or review recommended",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args TradeReviewArgs) (*mcp.CallToolResult, any, error) {
+		out, err := buildTradeReview(cfg.forCall(ctx, args.LeagueID), args)
+		if err != nil {
+			return toolError(err), nil, nil
+		}
+		return toolMarshal(out)
+	}, ToolExample{Args: map[string]any{"league_id": exampleLeague, "trade_id": 1}})
 
 	addTool(server, &registry, &mcp.Tool{
 		Name:        "player_gw_stats",
-		Description: "Per-gameweek stats for a specific player: minutes, points, goals, assists, xG, xA across a GW range",
+		Description: "Per-gameweek stats for a specific player: minutes, points, goals, assists, xG, xA across a GW range, with each GW's opponent, venue and result (doubles and blanks flagged) and a home/away and vs-top-6 split",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, args PlayerGWStatsArgs) (*mcp.CallToolResult, any, error) {
 		out, err := buildPlayerGWStats(cfg, args)
 		if err != nil {
 			return toolError(err), nil, nil
 		}
 		return toolMarshal(out)
-	})
+	}, ToolExample{Args: map[string]any{"element_id": exampleElement}})
+
+	addTool(server, &registry, &mcp.Tool{
+		Name:        "provisional_bonus",
+		Description: "Live bonus for a gameweek: per started fixture the 3/2/1 the current BPS would earn (tied BPS share the higher award), marked provisional until FPL populates the fixture's bonus and confirmed after, with each player's projected points and per-manager bonus totals",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args ProvisionalBonusArgs) (*mcp.CallToolResult, any, error) {
+		out, err := buildProvisionalBonus(cfg.forCall(ctx, args.LeagueID), args)
+		if err != nil {
+			return toolError(err), nil, nil
+		}
+		return toolMarshal(out)
+	}, ToolExample{Args: map[string]any{"league_id": exampleLeague, "gw": 0}})
+
+	addTool(server, &registry, &mcp.Tool{
+		Name:        "player_consistency",
+		Description: "Boom/bust profile for a player over a horizon: per-GW points, mean, stddev, CV, 10th/90th percentile floor and ceiling, share of GWs at or above a points threshold, plus the same metrics over 60+ minute GWs only",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args PlayerConsistencyArgs) (*mcp.CallToolResult, any, error) {
+		out, err := buildPlayerConsistency(cfg, args)
+		if err != nil {
+			return toolError(err), nil, nil
+		}
+		return toolMarshal(out)
+	}, ToolExample{Args: map[string]any{"element_id": exampleElement}})
+
+	addTool(server, &registry, &mcp.Tool{
+		Name:        "availability_watch",
+		Description: "Injury and availability changes since the previous bootstrap refresh (status, chance of playing, news) for rostered players and free agents above a points threshold, tagged out/doubtful/returned with the owning manager",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args AvailabilityWatchArgs) (*mcp.CallToolResult, any, error) {
+		out, err := buildAvailabilityWatch(cfg.forCall(ctx, args.LeagueID), args)
+		if err != nil {
+			return toolError(err), nil, nil
+		}
+		return toolMarshal(out)
+	}, ToolExample{Args: map[string]any{"league_id": exampleLeague}})
+
+	addTool(server, &registry, &mcp.Tool{
+		Name:        "league_newswire",
+		Description: "Newest-first league news feed over the last window_gws GWs (default 2): availability changes, approved waivers and free agents, processed trades, unowned players back from injury and unowned players scoring 12+ in the latest finished GW, each with a one-line summary; capped at max_items (default 30)",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args LeagueNewswireArgs) (*mcp.CallToolResult, any, error) {
+		out, err := buildLeagueNewswire(cfg.forCall(ctx, args.LeagueID), args)
+		if err != nil {
+			return toolError(err), nil, nil
+		}
+		return toolMarshal(out)
+	}, ToolExample{Args: map[string]any{"league_id": exampleLeague}})
 
 	addTool(server, &registry, &mcp.Tool{
 		Name:        "head_to_head",
 		Description: "Head-to-head record between two managers: all matches played, scores, and W/D/L tally",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, args HeadToHeadArgs) (*mcp.CallToolResult, any, error) {
-		out, err := buildHeadToHead(cfg, args)
+		out, err := buildHeadToHead(cfg.forCall(ctx, args.LeagueID), args)
 		if err != nil {
 			return toolError(err), nil, nil
 		}
 		return toolMarshal(out)
-	})
+	}, ToolExample{Args: map[string]any{"league_id": exampleLeague, "entry_id_a": exampleEntry, "entry_id_b": exampleOpponent}})
 
 	addTool(server, &registry, &mcp.Tool{
-		Name:        "game_status",
-		Description: "Current game state: GW progress, deadlines (waivers/trades/lineup lock), fixture status, points finality",
-	}, gameStatusHandler(cfg))
+		Name:        "positional_edge",
+		Description: "Per-manager positional battles across finished GWs: how often they win the GK/DEF/MID/FWD group against their weekly opponent, average margin per group, and which group most often costs them matches",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args PositionalEdgeArgs) (*mcp.CallToolResult, any, error) {
+		out, err := buildPositionalEdge(cfg.forCall(ctx, args.LeagueID), args)
+		if err != nil {
+			return toolError(err), nil, nil
+		}
+		return toolMarshal(out)
+	}, ToolExample{Args: map[string]any{"league_id": exampleLeague, "entry_id": exampleEntry}})
 
 	addTool(server, &registry, &mcp.Tool{
-		Name:        "epl_fixtures",
-		Description: "Premier League fixture results for a specific gameweek",
-	}, eplFixturesHandler(cfg))
+		Name:        "gameweek_report",
+		Description: "Weekly write-up data for a league GW: results with position breakdowns, standings movement, top/bottom scorers, closest result, best waiver pickup, worst bench decision",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args GameweekReportArgs) (*mcp.CallToolResult, any, error) {
+		out, err := buildGameweekReport(cfg.forCall(ctx, args.LeagueID), args)
+		if err != nil {
+			return toolError(err), nil, nil
+		}
+		return toolMarshal(out)
+	}, ToolExample{Args: map[string]any{"league_id": exampleLeague, "gw": 0}})
 
 	addTool(server, &registry, &mcp.Tool{
-		Name:        "epl_standings",
-		Description: "Current Premier League season standings table",
-	}, eplStandingsHandler(cfg))
+		Name:        "optimal_standings",
+		Description: "Best-ball league table: standings replayed as if every manager fielded their optimal legal XI each finished GW, side by side with the real table, with rank deltas, points left on benches and matches whose result would have flipped",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args OptimalStandingsArgs) (*mcp.CallToolResult, any, error) {
+		out, err := buildOptimalStandings(cfg.forCall(ctx, args.LeagueID), args)
+		if err != nil {
+			return toolError(err), nil, nil
+		}
+		return toolMarshal(out)
+	}, ToolExample{Args: map[string]any{"league_id": exampleLeague}})
 
-	handler := mcp.NewStreamableHTTPHandler(func(r *http.Request) *mcp.Server {
-		return server
-	}, &mcp.StreamableHTTPOptions{JSONResponse: true})
+	addTool(server, &registry, &mcp.Tool{
+		Name:        "roster_outlook",
+		Description: "Rest-of-season points projection for an entry's roster: per-player baseline × fixture multipliers with blank/double GWs, team total vs league average. model=heuristic|poisson picks the projection (default heuristic)",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args RosterOutlookArgs) (*mcp.CallToolResult, any, error) {
+		out, err := buildRosterOutlook(cfg.forCall(ctx, args.LeagueID), args)
+		if err != nil {
+			return toolError(err), nil, nil
+		}
+		return toolMarshal(out)
+	}, ToolExample{Args: map[string]any{"league_id": exampleLeague, "entry_id": exampleEntry}})
 
-	apiKey := strings.TrimSpace(os.Getenv("FPL_MCP_API_KEY"))
-	if *requireAuth && apiKey == "" {
-		log.Fatal("FPL_MCP_API_KEY is required (set env var or run with --require-auth=false)")
-	}
+	addTool(server, &registry, &mcp.Tool{
+		Name:        "gw_calendar",
+		Description: "Blank and double gameweek calendar: fixture counts per Premier League team for each remaining GW (or horizon GWs), the GWs with doubles and blanks, and how many of each league manager's starters blank or double per GW",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args GWCalendarArgs) (*mcp.CallToolResult, any, error) {
+		out, err := buildGWCalendar(cfg.forCall(ctx, args.LeagueID), args)
+		if err != nil {
+			return toolError(err), nil, nil
+		}
+		return toolMarshal(out)
+	}, ToolExample{Args: map[string]any{"league_id": exampleLeague, "horizon": 6}})
 
-	withAuth := func(next http.HandlerFunc) http.HandlerFunc {
-		return func(w http.ResponseWriter, r *http.Request) {
-			if apiKey == "" {
-				next(w, r)
-				return
-			}
-			key := strings.TrimSpace(r.Header.Get(*authHeader))
-			if key == "" {
-				if authz := r.Header.Get("Authorization"); strings.HasPrefix(strings.ToLower(authz), "bearer ") {
-					key = strings.TrimSpace(authz[7:])
-				}
-			}
-			if subtle.ConstantTimeCompare([]byte(key), []byte(apiKey)) != 1 {
-				w.Header().Set("Content-Type", "application/json")
-				w.WriteHeader(http.StatusUnauthorized)
-				w.Write([]byte(`{"error":"unauthorized"}`))
-				return
-			}
-			next(w, r)
+	addTool(server, &registry, &mcp.Tool{
+		Name:        "team_coverage",
+		Description: "Premier League team exposure for an entry (or all entries): players per PL team, starters blanking per GW, shared kickoff slots, repeated opponents, and fixtures with your players on both sides",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args TeamCoverageArgs) (*mcp.CallToolResult, any, error) {
+		out, err := buildTeamCoverage(cfg.forCall(ctx, args.LeagueID), args)
+		if err != nil {
+			return toolError(err), nil, nil
 		}
-	}
+		return toolMarshal(out)
+	}, ToolExample{Args: map[string]any{"league_id": exampleLeague, "entry_id": exampleEntry}})
 
-	http.HandleFunc("/health", withAuth(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte(`{"status":"ok"}`))
-	}))
+	addTool(server, &registry, &mcp.Tool{
+		Name:        "deadline_checklist",
+		Description: "Everything to check before the next deadline for an entry: time remaining, flagged or blanking starters, bench players projected to outscore a starter (model=heuristic|poisson), pending waiver claims, and whether waivers have processed. tz (IANA name) adds the deadline in local time",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args DeadlineChecklistArgs) (*mcp.CallToolResult, any, error) {
+		out, err := buildDeadlineChecklist(cfg.forCall(ctx, args.LeagueID), args, cfg.now())
+		if err != nil {
+			return toolError(err), nil, nil
+		}
+		return toolMarshal(out)
+	}, ToolExample{Args: map[string]any{"league_id": exampleLeague, "entry_id": exampleEntry}})
 
-	http.HandleFunc("/tools", withAuth(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		b, err := json.MarshalIndent(map[string]any{"tools": registry}, "", "  ")
+	addTool(server, &registry, &mcp.Tool{
+		Name:        "opponent_scout",
+		Description: "Scouting report on your next opponent (or any entry via opponent_entry_id): roster by position with last-5 form and this GW's fixture difficulty, season scoring average/stddev and points share by position, how often they start zero-minute players, recent transactions, and flagged or blanking players",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args OpponentScoutArgs) (*mcp.CallToolResult, any, error) {
+		out, err := buildOpponentScout(cfg.forCall(ctx, args.LeagueID), args)
 		if err != nil {
-			http.Error(w, `{"error":"failed to marshal tool list"}`, http.StatusInternalServerError)
-			return
+			return toolError(err), nil, nil
 		}
-		w.Write(b)
-	}))
+		return toolMarshal(out)
+	}, ToolExample{Args: map[string]any{"league_id": exampleLeague, "entry_id": exampleEntry}})
 
-	http.HandleFunc(*mcpPath, withAuth(func(w http.ResponseWriter, r *http.Request) {
-		handler.ServeHTTP(w, r)
-	}))
+	addTool(server, &registry, &mcp.Tool{
+		Name:        "game_status",
+		Description: "Current game state: GW progress, deadlines (waivers/trades/lineup lock), fixture status, points finality. With league_id, also the league's trade setting, trade deadline, regular-season GWs left and the last waivers before the playoff lock. tz (IANA name, default UTC) sets the zone of the *_local times",
+	}, gameStatusHandler(cfg), ToolExample{Args: map[string]any{"league_id": exampleLeague}})
 
-	log.Printf("MCP HTTP server listening on %s%s", *addr, *mcpPath)
-	if err := http.ListenAndServe(*addr, nil); err != nil {
-		log.Fatal(err)
-	}
+	addTool(server, &registry, &mcp.Tool{
+		Name:        "backfill_status",
+		Description: "Which of a league's gameweeks have complete raw data (live.json plus every entry's event) and which are missing, with the files absent per GW and the cmd/dev command that fetches them; fetch_on_miss says whether the server fetches them itself (--allow-fetch-on-miss)",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args BackfillStatusArgs) (*mcp.CallToolResult, any, error) {
+		out, err := buildBackfillStatus(cfg.forCall(ctx, args.LeagueID), args)
+		if err != nil {
+			return toolError(err), nil, nil
+		}
+		return toolMarshal(out)
+	}, ToolExample{Args: map[string]any{"league_id": exampleLeague}})
+
+	addTool(server, &registry, &mcp.Tool{
+		Name:        "epl_fixtures",
+		Description: "Premier League fixture results for a specific gameweek",
+	}, eplFixturesHandler(cfg), ToolExample{Args: map[string]any{"gw": 0}})
+
+	addTool(server, &registry, &mcp.Tool{
+		Name:        "epl_standings",
+		Description: "Current Premier League season standings table",
+	}, eplStandingsHandler(cfg), ToolExample{Args: map[string]any{}})
+
+	addTool(server, &registry, &mcp.Tool{
+		Name:        "raw_query",
+		Description: "Escape hatch for a raw API field no other tool exposes (a squad number, team strength ratings): reads one raw file (bootstrap, league_details, transactions, trades, game, gw_live, entry_event) and returns only the dot-path fields asked for, e.g. elements[].web_name or teams[].strength_overall_home. Paths must name at least one key; the result is capped in size",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args RawQueryArgs) (*mcp.CallToolResult, any, error) {
+		out, err := buildRawQuery(cfg.forCall(ctx, args.LeagueID), args)
+		if err != nil {
+			return toolError(err), nil, nil
+		}
+		return toolMarshal(out)
+	}, ToolExample{Args: map[string]any{"endpoint": "bootstrap", "fields": []string{"teams[].short_name", "teams[].strength_overall_home"}}})
+
+	return server, watcher, registry.tools
 }
 
-func addTool[T any](server *mcp.Server, registry *[]toolInfo, tool *mcp.Tool, handler func(context.Context, *mcp.CallToolRequest, T) (*mcp.CallToolResult, any, error)) {
-	*registry = append(*registry, toolInfo{Name: tool.Name, Description: tool.Description})
-	mcp.AddTool(server, tool, handler)
+// addTool registers tool on server and lists it, with its examples, at
+// /tools. The examples' args are also appended to the MCP description.
+func addTool[T any](server *mcp.Server, registry *toolRegistry, tool *mcp.Tool, handler func(context.Context, *mcp.CallToolRequest, T) (*mcp.CallToolResult, any, error), examples ...ToolExample) {
+	examples = templateExamples(registry.cfg, tool.Name, examples, registry.exampleValues)
+	registry.tools = append(registry.tools, toolInfo{Name: tool.Name, Description: tool.Description, Examples: examples})
+	tool.Description += describeExamples(examples)
+	if !historicalTools[tool.Name] {
+		handler = withFreshness(registry.cfg, handler)
+	}
+	mcp.AddTool(server, tool, instrumentTool(tool.Name, handler))
 }
 
 func resolveGW(cfg ServerConfig, gw int) (int, error) {
@@ -528,7 +975,7 @@ func resolveGW(cfg ServerConfig, gw int) (int, error) {
 		return 0, err
 	}
 	if game.CurrentEvent == 0 {
-		return 0, fmt.Errorf("current_event missing in game.json")
+		return 0, staleData(gamePath, "current_event missing in game.json")
 	}
 	return game.CurrentEvent, nil
 }
@@ -550,19 +997,108 @@ func normalizeRisk(r string) string {
 }
 
 func loadSummaryFile(cfg ServerConfig, leagueID int, gw int, relPath string, horizons []int, risks []string) ([]byte, error) {
+	f, err := loadSummaryFileWithMeta(cfg, leagueID, gw, relPath, horizons, risks)
+	if err != nil {
+		return nil, err
+	}
+	return f.Bytes, nil
+}
+
+// loadSummaryFileWithMeta is loadSummaryFile plus the path, mtime and content
+// hash of the file that was read, for callers that set HTTP cache headers.
+func loadSummaryFileWithMeta(cfg ServerConfig, leagueID int, gw int, relPath string, horizons []int, risks []string) (summaryFile, error) {
 	if leagueID == 0 {
-		return nil, fmt.Errorf("league_id is required")
+		return summaryFile{}, invalidArgumentf("league_id is required")
 	}
 	if gw == 0 {
-		return nil, fmt.Errorf("gw is required")
+		return summaryFile{}, invalidArgumentf("gw is required")
 	}
+	cfg = cfg.forLeague(leagueID)
 	absPath := filepath.Join(cfg.DerivedRoot, relPath)
-	if b, err := os.ReadFile(absPath); err == nil {
-		return b, nil
+	start := time.Now()
+	b, err := store.ReadDerived(absPath)
+	cfg.timing.since(start, false)
+	// An outdated file is rebuilt when the server may compute, and served
+	// as it is otherwise.
+	if err == nil && (!cfg.ComputeMissing || !summaryOutdated(relPath, b)) {
+		mcpMetrics.summaryLoads.Inc(sourceDisk)
+		cfg.timing.served(sourceDisk)
+		return newSummaryFile(absPath, b), nil
 	}
 	if !cfg.ComputeMissing {
-		return nil, fmt.Errorf("missing summary file: %s", absPath)
+		mcpMetrics.summaryLoads.Inc(sourceMissing)
+		cfg.timing.served(sourceMissing)
+		return summaryFile{}, dataMissing(absPath, nil)
 	}
+	// BuildLeagueSummaries writes every per-GW family at once, so loads of
+	// any family for one league and GW share a flight. A caller that shared
+	// another family's build reads its own file from disk afterwards, and
+	// builds it itself if that build didn't write it. A build into a
+	// temporary root can't be read back by other callers, so without
+	// WriteDerived each path flies alone.
+	key := fmt.Sprintf("%s|%d|%d", cfg.DerivedRoot, leagueID, gw)
+	if !cfg.WriteDerived {
+		key += "|" + relPath
+	}
+	for {
+		res, err, shared := summaryFlights.Do(key, func() (summaryFlight, error) {
+			// A flight that finished just before this one started may have
+			// written the file already.
+			if cfg.WriteDerived {
+				if b, err := store.ReadDerived(absPath); err == nil && !summaryOutdated(relPath, b) {
+					mcpMetrics.summaryLoads.Inc(sourceDisk)
+					cfg.timing.served(sourceDisk)
+					return summaryFlight{relPath, newSummaryFile(absPath, b)}, nil
+				}
+			}
+			defer cfg.timing.since(time.Now(), true)
+			// Wait out an admin recompute or delete of this league's files.
+			lock := leagueLocks.get(cfg.DerivedRoot, leagueID)
+			lock.RLock()
+			defer lock.RUnlock()
+			f, err := computeSummaryFile(cfg, leagueID, gw, relPath, horizons, risks)
+			if err != nil {
+				return summaryFlight{relPath, f}, err
+			}
+			mcpMetrics.summaryLoads.Inc(sourceComputed)
+			cfg.timing.served(sourceComputed)
+			return summaryFlight{relPath, f}, nil
+		})
+		if shared && res.relPath != relPath {
+			b, rerr := store.ReadDerived(absPath)
+			if rerr != nil || summaryOutdated(relPath, b) {
+				continue
+			}
+			res.file, err = newSummaryFile(absPath, b), nil
+		}
+		if shared && err == nil {
+			mcpMetrics.summaryLoads.Inc(sourceShared)
+			cfg.timing.served(sourceShared)
+		}
+		return res.file, err
+	}
+}
+
+// summaryOutdated reports whether b, read from relPath, has an older layout
+// than the build now writes.
+func summaryOutdated(relPath string, b []byte) bool {
+	return strings.HasPrefix(relPath, "summary/waiver_targets/") && summary.WaiverTargetsOutdated(b)
+}
+
+// summaryFlights makes concurrent loads of a league's missing summaries for
+// one GW share one computation, so they neither race on its files nor build
+// them twice.
+var summaryFlights flightGroup[summaryFlight]
+
+// summaryFlight is the file a summary flight loaded and the path it was for.
+type summaryFlight struct {
+	relPath string
+	file    summaryFile
+}
+
+// computeSummaryFile builds a missing summary and reads it back, into the
+// derived root with WriteDerived on and a temporary directory otherwise.
+func computeSummaryFile(cfg ServerConfig, leagueID int, gw int, relPath string, horizons []int, risks []string) (summaryFile, error) {
 	h := horizons
 	if len(h) == 0 {
 		h = []int{5}
@@ -576,35 +1112,80 @@ func loadSummaryFile(cfg ServerConfig, leagueID int, gw int, relPath string, hor
 	if !cfg.WriteDerived {
 		tmp, err := os.MkdirTemp("", "fpl-summary-*")
 		if err != nil {
-			return nil, err
+			return summaryFile{}, err
 		}
 		root = tmp
 		cleanup = func() { _ = os.RemoveAll(tmp) }
 	}
 	defer cleanup()
 
-	st := store.NewJSONStore(cfg.RawRoot)
-	if strings.HasPrefix(relPath, "summary/transactions/") {
-		if err := summary.BuildTransactionsSummary(st, root, leagueID, gw); err != nil {
-			return nil, err
+	build := func() error {
+		return buildSummary(store.NewJSONStore(cfg.RawRoot), root, leagueID, gw, relPath, h, r, cfg.Tiebreakers[leagueID], cfg.Clock)
+	}
+	if need, ok := summaryRawNeed(relPath, gw, h); ok {
+		if err := withRawBackfill(cfg, leagueID, need, build); err != nil {
+			return summaryFile{}, err
 		}
-		return os.ReadFile(filepath.Join(root, relPath))
+	} else if err := build(); err != nil {
+		return summaryFile{}, err
 	}
-	ld, entryIDs, err := loadLeagueDetails(st, leagueID)
+	path := filepath.Join(root, relPath)
+	b, err := store.ReadDerived(path)
 	if err != nil {
-		return nil, err
+		return summaryFile{}, err
 	}
-	if err := ensureLedger(st, root, leagueID); err != nil {
-		return nil, err
-	}
-	if err := ensureSnapshots(st, root, leagueID, entryIDs, gw, gw); err != nil {
-		return nil, err
+	return newSummaryFile(path, b), nil
+}
+
+// summaryRawNeed is the raw GW data buildSummary reads for relPath at gw.
+// ok is false for families built from league-wide files alone.
+func summaryRawNeed(relPath string, gw int, h []int) (need rawNeed, ok bool) {
+	switch {
+	case strings.HasPrefix(relPath, "summary/transactions/"),
+		strings.HasPrefix(relPath, "summary/standings/"),
+		strings.HasPrefix(relPath, "summary/fixtures/"):
+		return rawNeed{}, false
+	case strings.HasPrefix(relPath, "summary/player_form/"):
+		return rawNeed{FromGW: gw - slices.Max(h) + 1, ToGW: gw, Live: true, EntryIDs: []int{}}, true
+	case strings.HasPrefix(relPath, "summary/optimal_standings/"):
+		return rawNeed{FromGW: 1, ToGW: gw, Live: true}, true
 	}
+	return rawNeed{FromGW: gw, ToGW: gw, Live: true}, true
+}
 
-	if err := summary.BuildLeagueSummaries(st, root, leagueID, ld, entryIDs, gw, gw, h, r); err != nil {
-		return nil, err
+// buildSummary computes the summary family relPath belongs to into root.
+// Standings are ranked with tiebreakers, the league's configured chain.
+func buildSummary(st *store.JSONStore, root string, leagueID int, gw int, relPath string, h []int, r []string, tiebreakers []string, clk clock.Clock) error {
+	switch {
+	case strings.HasPrefix(relPath, "summary/transactions/"):
+		return summary.BuildTransactionsSummary(st, root, leagueID, gw, clk)
+	case strings.HasPrefix(relPath, "summary/standings/"):
+		return summary.BuildStandingsSummary(st, root, leagueID, gw, tiebreakers, clk)
+	case strings.HasPrefix(relPath, "summary/fixtures/"):
+		return summary.BuildFixturesSummary(st, root, leagueID, gw, h, clk)
+	case strings.HasPrefix(relPath, "summary/player_form/"):
+		if err := ensureLedger(st, root, leagueID, clk); err != nil {
+			return err
+		}
+		return summary.BuildPlayerFormSummary(st, root, leagueID, gw, h, clk)
+	case strings.HasPrefix(relPath, "summary/optimal_standings/"):
+		return buildOptimalStandingsSummary(st, root, leagueID, gw, clk)
 	}
-	return os.ReadFile(filepath.Join(root, relPath))
+	ld, entryIDs, err := loadLeagueDetails(st, leagueID)
+	if err != nil {
+		return err
+	}
+	if err := ensureLedger(st, root, leagueID, clk); err != nil {
+		return err
+	}
+	// An entry whose picks were never fetched is left without a snapshot;
+	// BuildLeagueSummaries reconstructs its roster.
+	for _, entryID := range entryIDs {
+		if err := ensureSnapshots(st, root, leagueID, []int{entryID}, gw, gw, clk); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	return summary.BuildLeagueSummaries(st, root, leagueID, ld, entryIDs, gw, gw, h, r, summary.BuildOptions{OnlyGWs: []int{gw}, Clock: clk, Tiebreakers: tiebreakers})
 }
 
 func loadLeagueDetails(st *store.JSONStore, leagueID int) (summary.LeagueDetails, []int, error) {
@@ -623,47 +1204,100 @@ func loadLeagueDetails(st *store.JSONStore, leagueID int) (summary.LeagueDetails
 	return ld, entryIDs, nil
 }
 
-func ensureLedger(st *store.JSONStore, derivedRoot string, leagueID int) error {
+// derivedFlights guards ensureLedger and ensureSnapshots, keyed by the file
+// being written, so concurrent builds write each one once.
+var derivedFlights flightGroup[struct{}]
+
+func ensureLedger(st *store.JSONStore, derivedRoot string, leagueID int, clk clock.Clock) error {
 	ledgerPath := filepath.Join(derivedRoot, fmt.Sprintf("ledger/%d/event_0.json", leagueID))
-	if _, err := os.Stat(ledgerPath); err == nil {
+	if _, err := store.StatDerived(ledgerPath); err == nil {
 		return nil
 	}
-	raw, err := st.ReadRaw(fmt.Sprintf("draft/%d/choices.json", leagueID))
+	_, err, _ := derivedFlights.Do(ledgerPath, func() (struct{}, error) {
+		if _, err := store.StatDerived(ledgerPath); err == nil {
+			return struct{}{}, nil
+		}
+		raw, err := st.ReadRaw(fmt.Sprintf("draft/%d/choices.json", leagueID))
+		if err != nil {
+			return struct{}{}, err
+		}
+		var resp ledger.DraftChoicesResponse
+		if err := json.Unmarshal(raw, &resp); err != nil {
+			return struct{}{}, err
+		}
+		out := ledger.BuildDraftLedger(leagueID, resp.Choices, clk)
+		return struct{}{}, ledger.WriteDraftLedger(ledgerPath, out)
+	})
+	return err
+}
+
+// leagueStartGW is the league's first gameweek from its details, or 1 when
+// they can't be read.
+func leagueStartGW(st *store.JSONStore, leagueID int) int {
+	ld, _, err := loadLeagueDetails(st, leagueID)
 	if err != nil {
-		return err
-	}
-	var resp ledger.DraftChoicesResponse
-	if err := json.Unmarshal(raw, &resp); err != nil {
-		return err
+		return 1
 	}
-	out := ledger.BuildDraftLedger(leagueID, resp.Choices)
-	return ledger.WriteDraftLedger(ledgerPath, out)
+	return ld.StartGW()
 }
 
-func ensureSnapshots(st *store.JSONStore, derivedRoot string, leagueID int, entryIDs []int, minGW int, maxGW int) error {
+// ensureSnapshots derives the entry snapshots for minGW..maxGW that don't
+// exist yet. GWs before the league's start_event have no raw picks and are
+// skipped; an entry that joined later gets a missing stub for the GWs before
+// it (see ledger.ReadEntrySnapshot).
+func ensureSnapshots(st *store.JSONStore, derivedRoot string, leagueID int, entryIDs []int, minGW int, maxGW int, clk clock.Clock) error {
+	minGW = max(minGW, leagueStartGW(st, leagueID))
 	for gw := minGW; gw <= maxGW; gw++ {
 		for _, entryID := range entryIDs {
 			snapPath := filepath.Join(derivedRoot, fmt.Sprintf("snapshots/%d/entry/%d/gw/%d.json", leagueID, entryID, gw))
-			if _, err := os.Stat(snapPath); err == nil {
+			if _, err := store.StatDerived(snapPath); err == nil {
 				continue
 			}
-			raw, err := st.ReadRaw(fmt.Sprintf("entry/%d/gw/%d.json", entryID, gw))
+			_, err, _ := derivedFlights.Do(snapPath, func() (struct{}, error) {
+				if _, err := store.StatDerived(snapPath); err == nil {
+					return struct{}{}, nil
+				}
+				snap, err := ledger.ReadEntrySnapshot(st, leagueID, entryID, gw, clk)
+				if err != nil {
+					return struct{}{}, err
+				}
+				return struct{}{}, ledger.WriteEntrySnapshot(snapPath, snap)
+			})
 			if err != nil {
 				return err
 			}
-			var resp ledger.EntryEventRaw
-			if err := json.Unmarshal(raw, &resp); err != nil {
-				return err
-			}
-			snap := ledger.BuildEntrySnapshot(leagueID, entryID, gw, resp)
-			if err := ledger.WriteEntrySnapshot(snapPath, snap); err != nil {
-				return err
-			}
 		}
 	}
 	return nil
 }
 
+// loadDerivedFile reads relPath under the derived root. A missing file is
+// built by build(root) when compute-missing is enabled, into a temporary
+// directory unless derived writes are enabled, the same way loadSummaryFile
+// handles summaries.
+func loadDerivedFile(cfg ServerConfig, relPath string, build func(root string) error) ([]byte, error) {
+	absPath := filepath.Join(cfg.DerivedRoot, relPath)
+	if b, err := store.ReadDerived(absPath); err == nil {
+		return b, nil
+	}
+	if !cfg.ComputeMissing {
+		return nil, dataMissing(absPath, nil)
+	}
+	root := cfg.DerivedRoot
+	if !cfg.WriteDerived {
+		tmp, err := os.MkdirTemp("", "fpl-derived-*")
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = os.RemoveAll(tmp) }()
+		root = tmp
+	}
+	if err := build(root); err != nil {
+		return nil, err
+	}
+	return store.ReadDerived(filepath.Join(root, relPath))
+}
+
 func lookupPlayer(cfg ServerConfig, elementID int) ([]byte, error) {
 	raw, err := os.ReadFile(filepath.Join(cfg.RawRoot, "bootstrap", "bootstrap-static.json"))
 	if err != nil {
@@ -706,10 +1340,12 @@ func lookupPlayer(cfg ServerConfig, elementID int) ([]byte, error) {
 			"team_short":    teamShort[e.Team],
 			"position_type": e.ElementType,
 			"status":        e.Status,
+			// Reclassifications seen across the GWs the derive has recorded.
+			"position_changes": positions.Changes(cfg.DerivedRoot, e.ID, e.ElementType),
 		}
 		return json.MarshalIndent(out, "", "  ")
 	}
-	return nil, fmt.Errorf("player not found: %d", elementID)
+	return nil, notFoundf("player not found: %d", elementID)
 }
 
 func lookupManager(cfg ServerConfig, leagueID int, entryID int) ([]byte, error) {
@@ -739,7 +1375,7 @@ func lookupManager(cfg ServerConfig, leagueID int, entryID int) ([]byte, error)
 			return json.MarshalIndent(out, "", "  ")
 		}
 	}
-	return nil, fmt.Errorf("manager not found: %d", entryID)
+	return nil, notFoundf("manager not found: %d", entryID)
 }
 
 func toolJSON(res []byte, err error) (*mcp.CallToolResult, any, error) {
@@ -768,11 +1404,13 @@ func toolJSONBytes(res []byte) *mcp.CallToolResult {
 	}
 }
 
+// toolError returns an IsError result whose text content is the JSON body
+// {"error": {"code", "message", "details"}} built by marshalToolError.
 func toolError(err error) *mcp.CallToolResult {
 	return &mcp.CallToolResult{
 		IsError: true,
 		Content: []mcp.Content{
-			&mcp.TextContent{Text: fmt.Sprintf("error: %v", err)},
+			&mcp.TextContent{Text: string(marshalToolError(err))},
 		},
 	}
 }
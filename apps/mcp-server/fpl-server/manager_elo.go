@@ -0,0 +1,62 @@
+package main
+
+import (
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/summary"
+)
+
+// defaultEloHorizon is how many GWs of upcoming matches manager_elo prices.
+const defaultEloHorizon = 5
+
+// ManagerEloArgs are the input arguments for the manager_elo tool.
+type ManagerEloArgs struct {
+	LeagueID   int      `json:"league_id" jsonschema:"Draft league id (required)"`
+	KFactor    *float64 `json:"k_factor,omitempty" jsonschema:"Most a rating moves on an even match won by a hair (default 20)"`
+	MOVScaling *float64 `json:"mov_scaling,omitempty" jsonschema:"Margin-of-victory weight: K is scaled by 1 + mov_scaling x ln(1 + margin/10) (default 1, 0 = plain Elo)"`
+	Horizon    *int     `json:"horizon,omitempty" jsonschema:"GWs of upcoming matches to price (default 5, 0 = rest of season)"`
+}
+
+// ManagerEloOutput is the output of the manager_elo tool.
+type ManagerEloOutput struct {
+	summary.EloRatings
+	Notes []string `json:"notes"`
+}
+
+func buildManagerElo(cfg ServerConfig, args ManagerEloArgs) (ManagerEloOutput, error) {
+	if args.LeagueID == 0 {
+		return ManagerEloOutput{}, invalidArgumentf("league_id is required")
+	}
+	params := summary.EloParams{K: summary.DefaultEloK, MOVScaling: summary.DefaultEloMOVScaling}
+	if args.KFactor != nil {
+		params.K = *args.KFactor
+	}
+	if args.MOVScaling != nil {
+		params.MOVScaling = *args.MOVScaling
+	}
+	if err := params.Validate(); err != nil {
+		return ManagerEloOutput{}, invalidArgumentf("%v", err)
+	}
+	horizon := defaultEloHorizon
+	if args.Horizon != nil {
+		horizon = *args.Horizon
+	}
+	if horizon < 0 {
+		return ManagerEloOutput{}, invalidArgumentf("horizon must not be negative, got %d", horizon)
+	}
+
+	ld, _, err := loadLeagueDetails(store.NewJSONStore(cfg.RawRoot), args.LeagueID)
+	if err != nil {
+		return ManagerEloOutput{}, err
+	}
+	out := ManagerEloOutput{
+		EloRatings: summary.ComputeElo(args.LeagueID, ld, params, horizon),
+		Notes: []string{
+			"Everyone starts at 1500. After each finished GW a side gains K x (1 + mov_scaling x ln(1 + margin/10)) x (result - expected), a draw scoring 0.5, and its opponent loses the same.",
+			"win_prob is the Elo expected score, 1 / (1 + 10^(-gap/400)); draws are rare enough in draft H2H that it reads as a win probability.",
+		},
+	}
+	if out.ThroughGW == 0 {
+		out.Notes = append(out.Notes, "No finished matches yet, so every rating is the starting 1500.")
+	}
+	return out, nil
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestBuildManagerElo(t *testing.T) {
+	dir, cfg := resourceCfg(t)
+	writeLeagueDetailsFixture(t, dir, 100, []any{
+		map[string]any{"id": 1, "entry_id": 200, "entry_name": "Alpha FC"},
+		map[string]any{"id": 2, "entry_id": 201, "entry_name": "Beta FC"},
+	}, []any{
+		map[string]any{"event": 1, "finished": true, "started": true, "league_entry_1": 1, "league_entry_1_points": 60, "league_entry_2": 2, "league_entry_2_points": 40},
+		map[string]any{"event": 2, "finished": false, "started": false, "league_entry_1": 2, "league_entry_1_points": 0, "league_entry_2": 1, "league_entry_2_points": 0},
+	})
+
+	flat := 0.0
+	out, err := buildManagerElo(cfg, ManagerEloArgs{LeagueID: 100, MOVScaling: &flat})
+	if err != nil {
+		t.Fatalf("buildManagerElo: %v", err)
+	}
+	if out.ThroughGW != 1 || out.Rows[0].EntryID != 200 || out.Rows[0].Rating != 1510 || out.Rows[1].Rating != 1490 {
+		t.Errorf("rows = %+v through GW%d, want Alpha 1510 and Beta 1490 after GW1", out.Rows, out.ThroughGW)
+	}
+	if len(out.Upcoming) != 1 || out.Upcoming[0].EntryID1 != 201 || out.Upcoming[0].WinProb1 >= 0.5 {
+		t.Errorf("upcoming = %+v, want Beta the underdog in GW2", out.Upcoming)
+	}
+}
+
+func TestBuildManagerElo_BadArgs(t *testing.T) {
+	_, cfg := resourceCfg(t)
+	zero, neg := 0.0, -1.0
+	h := -1
+	for name, args := range map[string]ManagerEloArgs{
+		"missing league_id": {},
+		"k_factor 0":        {LeagueID: 100, KFactor: &zero},
+		"negative scaling":  {LeagueID: 100, MOVScaling: &neg},
+		"negative horizon":  {LeagueID: 100, Horizon: &h},
+	} {
+		if _, err := buildManagerElo(cfg, args); classifyError(err).Code != codeInvalidArgument {
+			t.Errorf("%s: err = %v, want INVALID_ARGUMENT", name, err)
+		}
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/summary"
 )
 
 type ManagerScheduleArgs struct {
@@ -37,13 +39,9 @@ type ManagerScheduleOutput struct {
 }
 
 type leagueDetailsRaw struct {
-	LeagueEntries []struct {
-		ID        int    `json:"id"`
-		EntryID   int    `json:"entry_id"`
-		EntryName string `json:"entry_name"`
-		ShortName string `json:"short_name"`
-	} `json:"league_entries"`
-	Matches []struct {
+	League        leagueSettings        `json:"league"`
+	LeagueEntries []summary.LeagueEntry `json:"league_entries"`
+	Matches       []struct {
 		Event              int  `json:"event"`
 		Finished           bool `json:"finished"`
 		Started            bool `json:"started"`
@@ -54,9 +52,20 @@ type leagueDetailsRaw struct {
 	} `json:"matches"`
 }
 
+// leagueSettings is the league object in details.json. Trades is "y" or
+// "n"; KORounds is the number of playoff GWs at the end of the season.
+type leagueSettings struct {
+	Name            string `json:"name"`
+	Trades          string `json:"trades"`
+	TransactionMode string `json:"transaction_mode"`
+	StartEvent      int    `json:"start_event"`
+	StopEvent       int    `json:"stop_event"`
+	KORounds        int    `json:"ko_rounds"`
+}
+
 func buildManagerSchedule(cfg ServerConfig, args ManagerScheduleArgs) (ManagerScheduleOutput, error) {
 	if args.LeagueID == 0 {
-		return ManagerScheduleOutput{}, fmt.Errorf("league_id is required")
+		return ManagerScheduleOutput{}, invalidArgumentf("league_id is required")
 	}
 
 	path := filepath.Join(cfg.RawRoot, fmt.Sprintf("league/%d/details.json", args.LeagueID))
@@ -103,27 +112,19 @@ func buildManagerSchedule(cfg ServerConfig, args ManagerScheduleArgs) (ManagerSc
 			name = strings.TrimSpace(strings.Join([]string{first, last}, " "))
 		}
 		if name == "" {
-			return ManagerScheduleOutput{}, fmt.Errorf("entry_id or entry_name is required")
-		}
-		matches := make([]int, 0)
-		for _, e := range details.LeagueEntries {
-			if strings.EqualFold(e.EntryName, name) || strings.EqualFold(e.ShortName, name) {
-				matches = append(matches, e.EntryID)
-			}
-		}
-		if len(matches) == 0 {
-			return ManagerScheduleOutput{}, fmt.Errorf("no entry found for name: %s", name)
+			return ManagerScheduleOutput{}, invalidArgumentf("entry_id or entry_name is required")
 		}
-		if len(matches) > 1 {
-			return ManagerScheduleOutput{}, fmt.Errorf("ambiguous entry_name: %s", name)
+		id, err := resolveEntry(details.LeagueEntries, name)
+		if err != nil {
+			return ManagerScheduleOutput{}, err
 		}
-		entryID = matches[0]
+		entryID = id
 	}
 
 	leagueEntryID = leagueEntryByEntry[entryID]
 	entryName = nameByEntry[entryID]
 	if leagueEntryID == 0 {
-		return ManagerScheduleOutput{}, fmt.Errorf("entry not found: %d", entryID)
+		return ManagerScheduleOutput{}, notFoundf("entry not found: %d", entryID)
 	}
 
 	minGW := 1
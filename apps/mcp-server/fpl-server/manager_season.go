@@ -8,6 +8,8 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/summary"
 )
 
 // ManagerSeasonArgs are the input arguments for the manager_season tool.
@@ -15,6 +17,7 @@ type ManagerSeasonArgs struct {
 	LeagueID  int     `json:"league_id" jsonschema:"Draft league id (required)"`
 	EntryID   *int    `json:"entry_id,omitempty" jsonschema:"Entry id"`
 	EntryName *string `json:"entry_name,omitempty" jsonschema:"Entry name (if entry_id not provided)"`
+	Season    string  `json:"season,omitempty" jsonschema:"Season label like 2024-25 for an archived season (default current)"`
 }
 
 // SeasonGameweek holds results for a single gameweek in a manager's season.
@@ -26,6 +29,12 @@ type SeasonGameweek struct {
 	OpponentScore int    `json:"opponent_score"`
 	Result        string `json:"result"`
 	Finished      bool   `json:"finished"`
+	// LeagueMedian is the median score across every entry that GW.
+	LeagueMedian float64 `json:"league_median"`
+	BeatMedian   bool    `json:"beat_median"`
+	// AllPlay is the record this score would have had against every other
+	// entry that GW.
+	AllPlay SeasonRecord `json:"all_play"`
 }
 
 // SeasonRecord holds season-level W/D/L.
@@ -36,23 +45,41 @@ type SeasonRecord struct {
 }
 
 // ManagerSeasonOutput is the output of the manager_season tool.
+//
+// ExpectedWins is the all-play win rate (draws counting half) times the
+// matches played, and LuckIndex is actual wins, again with draws as half,
+// less ExpectedWins: positive means the schedule has been kind.
 type ManagerSeasonOutput struct {
-	LeagueID    int              `json:"league_id"`
-	EntryID     int              `json:"entry_id"`
-	EntryName   string           `json:"entry_name"`
-	Record      SeasonRecord     `json:"record"`
-	TotalPoints int              `json:"total_points"`
-	HighestGW   int              `json:"highest_scoring_gw"`
-	HighestPts  int              `json:"highest_score"`
-	LowestGW    int              `json:"lowest_scoring_gw"`
-	LowestPts   int              `json:"lowest_score"`
-	AvgScore    float64          `json:"avg_score"`
-	Gameweeks   []SeasonGameweek `json:"gameweeks"`
+	LeagueID           int              `json:"league_id"`
+	EntryID            int              `json:"entry_id"`
+	EntryName          string           `json:"entry_name"`
+	Record             SeasonRecord     `json:"record"`
+	TotalPoints        int              `json:"total_points"`
+	PointsAgainst      int              `json:"points_against"`
+	HighestGW          int              `json:"highest_scoring_gw"`
+	HighestPts         int              `json:"highest_score"`
+	LowestGW           int              `json:"lowest_scoring_gw"`
+	LowestPts          int              `json:"lowest_score"`
+	AvgScore           float64          `json:"avg_score"`
+	AvgMarginOfVictory float64          `json:"avg_margin_of_victory"`
+	AvgMarginOfDefeat  float64          `json:"avg_margin_of_defeat"`
+	AllPlay            SeasonRecord     `json:"all_play_record"`
+	ExpectedWins       float64          `json:"expected_wins"`
+	LuckIndex          float64          `json:"luck_index"`
+	BeatMedianCount    int              `json:"beat_median_count"`
+	Gameweeks          []SeasonGameweek `json:"gameweeks"`
+	// TransactionBudget is the entry's moves against the league's cap
+	// through the current GW, in capped leagues only.
+	TransactionBudget *summary.TransactionBudget `json:"transaction_budget,omitempty"`
 }
 
 func buildManagerSeason(cfg ServerConfig, args ManagerSeasonArgs) (ManagerSeasonOutput, error) {
 	if args.LeagueID == 0 {
-		return ManagerSeasonOutput{}, fmt.Errorf("league_id is required")
+		return ManagerSeasonOutput{}, invalidArgumentf("league_id is required")
+	}
+	cfg, err := cfg.forSeason(args.Season)
+	if err != nil {
+		return ManagerSeasonOutput{}, err
 	}
 
 	path := filepath.Join(cfg.RawRoot, fmt.Sprintf("league/%d/details.json", args.LeagueID))
@@ -84,27 +111,36 @@ func buildManagerSeason(cfg ServerConfig, args ManagerSeasonArgs) (ManagerSeason
 			name = strings.TrimSpace(*args.EntryName)
 		}
 		if name == "" {
-			return ManagerSeasonOutput{}, fmt.Errorf("entry_id or entry_name is required")
-		}
-		for _, e := range details.LeagueEntries {
-			if strings.EqualFold(e.EntryName, name) || strings.EqualFold(e.ShortName, name) {
-				entryID = e.EntryID
-				break
-			}
+			return ManagerSeasonOutput{}, invalidArgumentf("entry_id or entry_name is required")
 		}
-		if entryID == 0 {
-			return ManagerSeasonOutput{}, fmt.Errorf("no entry found for name: %s", name)
+		id, err := resolveEntry(details.LeagueEntries, name)
+		if err != nil {
+			return ManagerSeasonOutput{}, err
 		}
+		entryID = id
 	}
 
 	leagueEntryID := leagueEntryByEntry[entryID]
 	entryName := nameByEntry[entryID]
 	if leagueEntryID == 0 {
-		return ManagerSeasonOutput{}, fmt.Errorf("entry not found: %d", entryID)
+		return ManagerSeasonOutput{}, notFoundf("entry not found: %d", entryID)
+	}
+
+	// Every entry's score in each finished GW, for the all-play record and
+	// the weekly median.
+	scoresByGW := make(map[int][]int)
+	for _, m := range details.Matches {
+		if m.Finished {
+			scoresByGW[m.Event] = append(scoresByGW[m.Event], m.LeagueEntry1Points, m.LeagueEntry2Points)
+		}
 	}
 
 	// Walk all matches for this entry.
 	gameweeks := make([]SeasonGameweek, 0)
+	pointsAgainst := 0
+	victoryMargin, defeatMargin := 0, 0
+	allPlay := SeasonRecord{}
+	expectedWins := 0.0
 	record := SeasonRecord{}
 	totalPts := 0
 	highestGW, highestPts := 0, -1
@@ -137,6 +173,8 @@ func buildManagerSeason(cfg ServerConfig, args ManagerSeasonArgs) (ManagerSeason
 		if !m.Finished {
 			continue
 		}
+		week := scoresByGW[m.Event]
+		median := medianScore(week)
 		gw := SeasonGameweek{
 			Gameweek:      m.Event,
 			Score:         score,
@@ -145,18 +183,30 @@ func buildManagerSeason(cfg ServerConfig, args ManagerSeasonArgs) (ManagerSeason
 			OpponentScore: oppScore,
 			Result:        result,
 			Finished:      m.Finished,
+			LeagueMedian:  median,
+			BeatMedian:    float64(score) > median,
 		}
+		gw.AllPlay.Wins, gw.AllPlay.Draws, gw.AllPlay.Losses = summary.AllPlayRecord(score, week)
 		gameweeks = append(gameweeks, gw)
 
 		totalPts += score
+		pointsAgainst += oppScore
 		finishedCount++
 		switch result {
 		case "W":
 			record.Wins++
+			victoryMargin += score - oppScore
 		case "D":
 			record.Draws++
 		case "L":
 			record.Losses++
+			defeatMargin += oppScore - score
+		}
+		allPlay.Wins += gw.AllPlay.Wins
+		allPlay.Draws += gw.AllPlay.Draws
+		allPlay.Losses += gw.AllPlay.Losses
+		if opponents := len(week) - 1; opponents > 0 {
+			expectedWins += (float64(gw.AllPlay.Wins) + 0.5*float64(gw.AllPlay.Draws)) / float64(opponents)
 		}
 		if score > highestPts {
 			highestPts = score
@@ -177,6 +227,20 @@ func buildManagerSeason(cfg ServerConfig, args ManagerSeasonArgs) (ManagerSeason
 	if finishedCount > 0 {
 		avg = float64(totalPts) / float64(finishedCount)
 	}
+	avgVictory, avgDefeat := 0.0, 0.0
+	if record.Wins > 0 {
+		avgVictory = float64(victoryMargin) / float64(record.Wins)
+	}
+	if record.Losses > 0 {
+		avgDefeat = float64(defeatMargin) / float64(record.Losses)
+	}
+	beatMedian := 0
+	for _, gw := range gameweeks {
+		if gw.BeatMedian {
+			beatMedian++
+		}
+	}
+	actualWins := float64(record.Wins) + 0.5*float64(record.Draws)
 	if highestPts == -1 {
 		highestPts = 0
 	}
@@ -184,17 +248,50 @@ func buildManagerSeason(cfg ServerConfig, args ManagerSeasonArgs) (ManagerSeason
 		lowestPts = 0
 	}
 
+	// The budget counts through the current GW, or the last finished one
+	// without game.json. It is extra to the season record, so a league whose
+	// transactions can't be read just goes without it.
+	budgetGW := 0
+	if meta, err := loadGameMeta(cfg); err == nil {
+		budgetGW = meta.CurrentEvent
+	} else if len(gameweeks) > 0 {
+		budgetGW = gameweeks[len(gameweeks)-1].Gameweek
+	}
+	budget, _ := loadTransactionBudget(cfg, args.LeagueID, entryID, budgetGW)
+
 	return ManagerSeasonOutput{
-		LeagueID:    args.LeagueID,
-		EntryID:     entryID,
-		EntryName:   entryName,
-		Record:      record,
-		TotalPoints: totalPts,
-		HighestGW:   highestGW,
-		HighestPts:  highestPts,
-		LowestGW:    lowestGW,
-		LowestPts:   lowestPts,
-		AvgScore:    avg,
-		Gameweeks:   gameweeks,
+		LeagueID:           args.LeagueID,
+		EntryID:            entryID,
+		EntryName:          entryName,
+		Record:             record,
+		TotalPoints:        totalPts,
+		PointsAgainst:      pointsAgainst,
+		HighestGW:          highestGW,
+		HighestPts:         highestPts,
+		LowestGW:           lowestGW,
+		LowestPts:          lowestPts,
+		AvgScore:           avg,
+		AvgMarginOfVictory: avgVictory,
+		AvgMarginOfDefeat:  avgDefeat,
+		AllPlay:            allPlay,
+		ExpectedWins:       expectedWins,
+		LuckIndex:          actualWins - expectedWins,
+		BeatMedianCount:    beatMedian,
+		Gameweeks:          gameweeks,
+		TransactionBudget:  budget,
 	}, nil
 }
+
+// medianScore is the median of scores, or 0 when there are none.
+func medianScore(scores []int) float64 {
+	if len(scores) == 0 {
+		return 0
+	}
+	sorted := append([]int(nil), scores...)
+	sort.Ints(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return float64(sorted[mid])
+	}
+	return float64(sorted[mid-1]+sorted[mid]) / 2
+}
@@ -31,7 +31,7 @@ type ManagerStreakOutput struct {
 
 func buildManagerStreak(cfg ServerConfig, args ManagerStreakArgs) (ManagerStreakOutput, error) {
 	if args.LeagueID == 0 {
-		return ManagerStreakOutput{}, fmt.Errorf("league_id is required")
+		return ManagerStreakOutput{}, invalidArgumentf("league_id is required")
 	}
 	path := filepath.Join(cfg.RawRoot, fmt.Sprintf("league/%d/details.json", args.LeagueID))
 	raw, err := os.ReadFile(path)
@@ -75,27 +75,19 @@ func buildManagerStreak(cfg ServerConfig, args ManagerStreakArgs) (ManagerStreak
 			name = strings.TrimSpace(strings.Join([]string{first, last}, " "))
 		}
 		if name == "" {
-			return ManagerStreakOutput{}, fmt.Errorf("entry_id or entry_name is required")
+			return ManagerStreakOutput{}, invalidArgumentf("entry_id or entry_name is required")
 		}
-		matches := make([]int, 0)
-		for _, e := range details.LeagueEntries {
-			if strings.EqualFold(e.EntryName, name) || strings.EqualFold(e.ShortName, name) {
-				matches = append(matches, e.EntryID)
-			}
-		}
-		if len(matches) == 0 {
-			return ManagerStreakOutput{}, fmt.Errorf("no entry found for name: %s", name)
-		}
-		if len(matches) > 1 {
-			return ManagerStreakOutput{}, fmt.Errorf("ambiguous entry_name: %s", name)
+		id, err := resolveEntry(details.LeagueEntries, name)
+		if err != nil {
+			return ManagerStreakOutput{}, err
 		}
-		entryID = matches[0]
+		entryID = id
 	}
 
 	leagueEntryID = leagueEntryByEntry[entryID]
 	entryName = nameByEntry[entryID]
 	if leagueEntryID == 0 {
-		return ManagerStreakOutput{}, fmt.Errorf("entry not found: %d", entryID)
+		return ManagerStreakOutput{}, notFoundf("entry not found: %d", entryID)
 	}
 
 	startGW := 1
@@ -0,0 +1,281 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/livestats"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+)
+
+const (
+	// tendencyHitWindow is how many GWs, starting with the claim's own event,
+	// an add and its drop are compared over.
+	tendencyHitWindow = 3
+	// panicDropWindow and panicDropPoints define a panic drop: the dropped
+	// player scores panicDropPoints or more in the panicDropWindow GWs after
+	// leaving the roster.
+	panicDropWindow = 2
+	panicDropPoints = 10
+)
+
+// ManagerTendenciesArgs are the input arguments for the manager_tendencies tool.
+type ManagerTendenciesArgs struct {
+	LeagueID int  `json:"league_id" jsonschema:"Draft league id (required)"`
+	EntryID  *int `json:"entry_id,omitempty" jsonschema:"Only profile this entry"`
+}
+
+// PanicDrop is a player dropped just before a points explosion.
+type PanicDrop struct {
+	Element     int    `json:"element"`
+	PlayerName  string `json:"player_name"`
+	Team        string `json:"team"`
+	DroppedGW   int    `json:"dropped_gw"`
+	PointsAfter int    `json:"points_after"` // over the dropped GW and the one after
+}
+
+// ManagerTendencies is one manager's season-long transaction profile.
+type ManagerTendencies struct {
+	EntryID     int     `json:"entry_id"`
+	EntryName   string  `json:"entry_name"`
+	Claims      int     `json:"claims"` // approved waiver and free-agent moves
+	ClaimsPerGW float64 `json:"claims_per_gw"`
+	// Graded counts claims with both an add and a drop whose full hit window
+	// has been played; Hits are those where the add outscored the drop.
+	Graded         int            `json:"graded"`
+	Hits           int            `json:"hits"`
+	HitRate        float64        `json:"hit_rate"`
+	AddsByPosition map[string]int `json:"adds_by_position"`
+	TopPosition    string         `json:"top_position,omitempty"`
+	// AvgGWsHeld is the mean number of GWs a player stayed on the roster
+	// before this manager dropped them. Drafted players count from GW1.
+	AvgGWsHeld      float64 `json:"avg_gws_held"`
+	TradesOffered   int     `json:"trades_offered"`
+	TradesReceived  int     `json:"trades_received"`
+	TradesCompleted int     `json:"trades_completed"`
+	TradesPerGW     float64 `json:"trades_per_gw"`
+	// TradeAcceptRate is the share of trades offered to this manager that
+	// they accepted, out of those they answered.
+	TradeAcceptRate float64     `json:"trade_accept_rate"`
+	PanicDrops      []PanicDrop `json:"panic_drops"`
+}
+
+// ManagerTendenciesOutput is the output of the manager_tendencies tool.
+type ManagerTendenciesOutput struct {
+	LeagueID  int                 `json:"league_id"`
+	ThroughGW int                 `json:"through_gw"`
+	Managers  []ManagerTendencies `json:"managers"`
+	GWNote    *GWNote             `json:"gw_note,omitempty"`
+}
+
+// windowPoints sums element's points over [fromGW, fromGW+n-1], stopping at
+// throughGW. The bool is false when the window runs past throughGW.
+func windowPoints(pointsByGW map[int]map[int]livestats.ElementStats, element int, fromGW int, n int, throughGW int) (int, bool) {
+	total := 0
+	for gw := fromGW; gw < fromGW+n; gw++ {
+		if gw > throughGW {
+			return total, false
+		}
+		total += pointsByGW[gw][element].TotalPoints
+	}
+	return total, true
+}
+
+// heldBeforeDrop returns, for each (entry, element) drop in moves, how many
+// GWs the entry had held the player. A move in event e takes effect for GW e,
+// so a player acquired in GW a and dropped in GW d was held d-a GWs.
+func heldBeforeDrop(moves []ownershipMove) map[int][]int {
+	acquired := make(map[int]int) // element -> GW the current owner got them
+	out := make(map[int][]int)
+	for _, m := range moves {
+		if m.From != 0 && m.To == 0 {
+			since, ok := acquired[m.Element]
+			if !ok {
+				since = 1
+			}
+			out[m.From] = append(out[m.From], m.Event-since)
+		}
+		if m.To != 0 {
+			acquired[m.Element] = m.Event
+		} else {
+			delete(acquired, m.Element)
+		}
+	}
+	return out
+}
+
+// tradeAccepted reports whether the receiving manager said yes to a trade.
+// Offered ("o") and withdrawn ("w") trades were never answered.
+func tradeAccepted(state string) (accepted bool, answered bool) {
+	switch state {
+	case "a", "p", "v":
+		return true, true
+	case "r":
+		return false, true
+	}
+	return false, false
+}
+
+func buildManagerTendencies(cfg ServerConfig, args ManagerTendenciesArgs) (ManagerTendenciesOutput, error) {
+	if args.LeagueID == 0 {
+		return ManagerTendenciesOutput{}, invalidArgumentf("league_id is required")
+	}
+	throughGW, note, err := resolveEffectiveGW(cfg, 0, gwModeLatestFinished)
+	if err != nil {
+		return ManagerTendenciesOutput{}, err
+	}
+
+	st := store.NewJSONStore(cfg.RawRoot)
+	transactions, err := loadTransactionsRaw(st, args.LeagueID)
+	if err != nil {
+		return ManagerTendenciesOutput{}, err
+	}
+	trades, err := loadTradesRaw(st, args.LeagueID)
+	if err != nil {
+		return ManagerTendenciesOutput{}, err
+	}
+	raw, err := st.ReadRaw(fmt.Sprintf("league/%d/details.json", args.LeagueID))
+	if err != nil {
+		return ManagerTendenciesOutput{}, err
+	}
+	var details leagueDetailsRaw
+	if err := json.Unmarshal(raw, &details); err != nil {
+		return ManagerTendenciesOutput{}, err
+	}
+	entries := details.LeagueEntries
+	if args.EntryID != nil {
+		entries = nil
+		for _, e := range details.LeagueEntries {
+			if e.EntryID == *args.EntryID {
+				entries = append(entries, e)
+			}
+		}
+		if len(entries) == 0 {
+			return ManagerTendenciesOutput{}, notFoundf("entry %d not found in league %d", *args.EntryID, args.LeagueID)
+		}
+	}
+	elements, teamShort, _, err := loadBootstrapData(cfg.RawRoot)
+	if err != nil {
+		return ManagerTendenciesOutput{}, err
+	}
+	playerByID := make(map[int]elementInfo, len(elements))
+	for _, e := range elements {
+		playerByID[e.ID] = e
+	}
+
+	pointsByGW := make(map[int]map[int]livestats.ElementStats, throughGW)
+	for gw := 1; gw <= throughGW; gw++ {
+		if live, err := loadLiveStats(cfg.RawRoot, gw); err == nil {
+			pointsByGW[gw] = live
+		}
+	}
+
+	claims := approvedTransactions(transactions, 1, throughGW)
+	held := heldBeforeDrop(ownershipMoves(claims, trades))
+
+	out := ManagerTendenciesOutput{
+		LeagueID:  args.LeagueID,
+		ThroughGW: throughGW,
+		Managers:  make([]ManagerTendencies, 0, len(entries)),
+		GWNote:    note,
+	}
+	for _, e := range entries {
+		m := ManagerTendencies{
+			EntryID:        e.EntryID,
+			EntryName:      e.EntryName,
+			AddsByPosition: map[string]int{"GK": 0, "DEF": 0, "MID": 0, "FWD": 0},
+			PanicDrops:     []PanicDrop{},
+		}
+		for _, tx := range claims {
+			if tx.Entry != e.EntryID {
+				continue
+			}
+			m.Claims++
+			if meta, ok := playerByID[tx.ElementIn]; ok {
+				m.AddsByPosition[positionLabel(meta.PositionType)]++
+			}
+			if tx.ElementIn != 0 && tx.ElementOut != 0 {
+				in, inDone := windowPoints(pointsByGW, tx.ElementIn, tx.Event, tendencyHitWindow, throughGW)
+				dropped, _ := windowPoints(pointsByGW, tx.ElementOut, tx.Event, tendencyHitWindow, throughGW)
+				if inDone {
+					m.Graded++
+					if in > dropped {
+						m.Hits++
+					}
+				}
+			}
+			if tx.ElementOut != 0 {
+				// Partial windows still count: 10 points in one GW is already
+				// an explosion.
+				pts, _ := windowPoints(pointsByGW, tx.ElementOut, tx.Event, panicDropWindow, throughGW)
+				if pts >= panicDropPoints {
+					meta := playerByID[tx.ElementOut]
+					m.PanicDrops = append(m.PanicDrops, PanicDrop{
+						Element:     tx.ElementOut,
+						PlayerName:  meta.Name,
+						Team:        teamShort[meta.TeamID],
+						DroppedGW:   tx.Event,
+						PointsAfter: pts,
+					})
+				}
+			}
+		}
+		if throughGW > 0 {
+			m.ClaimsPerGW = float64(m.Claims) / float64(throughGW)
+		}
+		if m.Graded > 0 {
+			m.HitRate = float64(m.Hits) / float64(m.Graded)
+		}
+		best := 0
+		for _, pos := range []string{"GK", "DEF", "MID", "FWD"} {
+			if n := m.AddsByPosition[pos]; n > best {
+				best = n
+				m.TopPosition = pos
+			}
+		}
+		if spans := held[e.EntryID]; len(spans) > 0 {
+			total := 0
+			for _, n := range spans {
+				total += n
+			}
+			m.AvgGWsHeld = float64(total) / float64(len(spans))
+		}
+
+		answered, accepted := 0, 0
+		for _, tr := range trades {
+			if tr.Event > throughGW {
+				continue
+			}
+			if tr.OfferedEntry == e.EntryID {
+				m.TradesOffered++
+			}
+			if tr.ReceivedEntry == e.EntryID {
+				m.TradesReceived++
+				if yes, ok := tradeAccepted(tr.State); ok {
+					answered++
+					if yes {
+						accepted++
+					}
+				}
+			}
+			if tr.State == "p" && (tr.OfferedEntry == e.EntryID || tr.ReceivedEntry == e.EntryID) {
+				m.TradesCompleted++
+			}
+		}
+		if throughGW > 0 {
+			m.TradesPerGW = float64(m.TradesCompleted) / float64(throughGW)
+		}
+		if answered > 0 {
+			m.TradeAcceptRate = float64(accepted) / float64(answered)
+		}
+		out.Managers = append(out.Managers, m)
+	}
+	sort.SliceStable(out.Managers, func(i, j int) bool {
+		if out.Managers[i].Claims != out.Managers[j].Claims {
+			return out.Managers[i].Claims > out.Managers[j].Claims
+		}
+		return out.Managers[i].EntryID < out.Managers[j].EntryID
+	})
+	return out, nil
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestHeldBeforeDrop(t *testing.T) {
+	moves := []ownershipMove{
+		{Event: 3, Element: 10, From: 200},          // drafted, dropped GW3: 2 GWs
+		{Event: 4, Element: 10, To: 201},            // picked up GW4
+		{Event: 6, Element: 10, From: 201, To: 202}, // traded, not a drop
+		{Event: 9, Element: 10, From: 202},          // held since GW6: 3 GWs
+	}
+	got := heldBeforeDrop(moves)
+	if h := got[200]; len(h) != 1 || h[0] != 2 {
+		t.Errorf("entry 200 held = %v, want [2]", h)
+	}
+	if h := got[202]; len(h) != 1 || h[0] != 3 {
+		t.Errorf("entry 202 held = %v, want [3]", h)
+	}
+	if h := got[201]; len(h) != 0 {
+		t.Errorf("entry 201 held = %v, want none (left by trade)", h)
+	}
+}
+
+func TestBuildManagerTendencies(t *testing.T) {
+	dir, cfg := tmpCfg(t)
+	writeBootstrap(t, dir)
+	writeFullGameJSON(t, dir, 5, true, 6, false, "")
+	// Salah 5/GW, Alexander-Arnold 2/GW, Haaland quiet until 8 and 6 in
+	// GW4-5, element 8 scores 3/GW and element 9 nothing.
+	for gw := 1; gw <= 5; gw++ {
+		haaland := 2
+		switch gw {
+		case 4:
+			haaland = 8
+		case 5:
+			haaland = 6
+		}
+		writeLiveJSON(t, dir, gw, map[string]any{
+			"1": map[string]any{"stats": map[string]any{"total_points": 5}},
+			"2": map[string]any{"stats": map[string]any{"total_points": haaland}},
+			"3": map[string]any{"stats": map[string]any{"total_points": 2}},
+			"8": map[string]any{"stats": map[string]any{"total_points": 3}},
+			"9": map[string]any{"stats": map[string]any{"total_points": 0}},
+		})
+	}
+	writeLeagueDetailsFixture(t, dir, 100, []any{
+		map[string]any{"id": 1, "entry_id": 200, "entry_name": "Alpha FC"},
+		map[string]any{"id": 2, "entry_id": 201, "entry_name": "Beta United"},
+	}, []any{})
+	writeJSON(t, filepath.Join(dir, "league/100/transactions.json"), map[string]any{
+		"transactions": []any{
+			// Hit: Salah 15 vs Alexander-Arnold 6 over GW2-4.
+			map[string]any{"id": 1, "entry": 200, "event": 2, "element_in": 1, "element_out": 3, "kind": "w", "result": "a"},
+			// Ungraded (window runs past GW5) and a panic drop of Haaland.
+			map[string]any{"id": 2, "entry": 200, "event": 4, "element_in": 3, "element_out": 2, "kind": "f", "result": "a"},
+			// Miss: 0 vs 9.
+			map[string]any{"id": 3, "entry": 201, "event": 3, "element_in": 9, "element_out": 8, "kind": "w", "result": "a"},
+			// Failed claims are ignored.
+			map[string]any{"id": 4, "entry": 201, "event": 3, "element_in": 1, "element_out": 8, "kind": "w", "result": "do"},
+		},
+	})
+	writeJSON(t, filepath.Join(dir, "league/100/trades.json"), map[string]any{
+		"trades": []any{
+			map[string]any{"id": 1, "event": 3, "offered_entry": 200, "received_entry": 201, "state": "p", "tradeitem_set": []any{map[string]any{"element_out": 20, "element_in": 21}}},
+			map[string]any{"id": 2, "event": 4, "offered_entry": 200, "received_entry": 201, "state": "r"},
+			map[string]any{"id": 3, "event": 5, "offered_entry": 201, "received_entry": 200, "state": "o"},
+		},
+	})
+
+	out, err := buildManagerTendencies(cfg, ManagerTendenciesArgs{LeagueID: 100})
+	if err != nil {
+		t.Fatalf("buildManagerTendencies: %v", err)
+	}
+	if out.ThroughGW != 5 || len(out.Managers) != 2 || out.Managers[0].EntryID != 200 {
+		t.Fatalf("through=%d managers=%+v", out.ThroughGW, out.Managers)
+	}
+
+	a := out.Managers[0]
+	if a.Claims != 2 || !approxEqual(a.ClaimsPerGW, 0.4) {
+		t.Errorf("claims = %d (%v/GW), want 2 (0.4/GW)", a.Claims, a.ClaimsPerGW)
+	}
+	if a.Graded != 1 || a.Hits != 1 || !approxEqual(a.HitRate, 1) {
+		t.Errorf("graded=%d hits=%d rate=%v, want 1/1", a.Graded, a.Hits, a.HitRate)
+	}
+	if a.AddsByPosition["MID"] != 1 || a.AddsByPosition["DEF"] != 1 || a.TopPosition != "DEF" {
+		t.Errorf("adds by position = %v top=%q", a.AddsByPosition, a.TopPosition)
+	}
+	// Alexander-Arnold held GW1 only, Haaland GW1-3.
+	if !approxEqual(a.AvgGWsHeld, 2) {
+		t.Errorf("avg held = %v, want 2", a.AvgGWsHeld)
+	}
+	if len(a.PanicDrops) != 1 || a.PanicDrops[0].PlayerName != "Haaland" || a.PanicDrops[0].PointsAfter != 14 {
+		t.Errorf("panic drops = %+v, want Haaland with 14", a.PanicDrops)
+	}
+	if a.TradesOffered != 2 || a.TradesReceived != 1 || a.TradesCompleted != 1 || a.TradeAcceptRate != 0 {
+		t.Errorf("trades = %+v", a)
+	}
+
+	b := out.Managers[1]
+	if b.Claims != 1 || b.Graded != 1 || b.Hits != 0 || len(b.PanicDrops) != 0 {
+		t.Errorf("beta = %+v, want one graded miss", b)
+	}
+	if b.TradesReceived != 2 || !approxEqual(b.TradeAcceptRate, 0.5) {
+		t.Errorf("beta trades received=%d accept=%v, want 2 and 0.5", b.TradesReceived, b.TradeAcceptRate)
+	}
+
+	entry := 201
+	out, err = buildManagerTendencies(cfg, ManagerTendenciesArgs{LeagueID: 100, EntryID: &entry})
+	if err != nil || len(out.Managers) != 1 || out.Managers[0].EntryID != 201 {
+		t.Errorf("entry filter: err=%v managers=%+v", err, out.Managers)
+	}
+
+	if _, err := buildManagerTendencies(cfg, ManagerTendenciesArgs{}); classifyError(err).Code != codeInvalidArgument {
+		t.Errorf("no league: err = %v", err)
+	}
+	missing := 999
+	if _, err := buildManagerTendencies(cfg, ManagerTendenciesArgs{LeagueID: 100, EntryID: &missing}); classifyError(err).Code != codeNotFound {
+		t.Errorf("unknown entry: err = %v", err)
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// MatchupBreakdownArgs are the input arguments for the matchup_breakdown
+// tool.
+type MatchupBreakdownArgs struct {
+	LeagueID       int  `json:"league_id" jsonschema:"Draft league id (required)"`
+	GW             int  `json:"gw" jsonschema:"Gameweek (0 = latest finished)"`
+	IncludePlayers bool `json:"include_players,omitempty" jsonschema:"Include player_detail: each side's starters with points and minutes, top scorer, biggest dud and the difference makers who outscored the margin"`
+}
+
+// buildMatchupBreakdown loads the GW's matchup summary. The derived file
+// always carries each matchup's player_detail; it is dropped here unless
+// includePlayers asks for it.
+func buildMatchupBreakdown(cfg ServerConfig, args MatchupBreakdownArgs) ([]byte, error) {
+	if args.LeagueID == 0 {
+		return nil, invalidArgumentf("league_id is required")
+	}
+	gw, note, err := resolveEffectiveGW(cfg, args.GW, gwModeLatestFinished)
+	if err != nil {
+		return nil, err
+	}
+	relPath := fmt.Sprintf("summary/matchup/%d/gw/%d.json", args.LeagueID, gw)
+	raw, err := loadSummaryFile(cfg, args.LeagueID, gw, relPath, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if !args.IncludePlayers {
+		raw = withoutPlayerDetail(raw)
+	}
+	return withGWNote(raw, note), nil
+}
+
+// withoutPlayerDetail drops player_detail from each matchup in a matchup
+// summary, leaving everything else as it was. raw is returned unchanged if
+// it doesn't parse.
+func withoutPlayerDetail(raw []byte) []byte {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return raw
+	}
+	var matchups []map[string]json.RawMessage
+	if err := json.Unmarshal(obj["matchups"], &matchups); err != nil {
+		return raw
+	}
+	for _, m := range matchups {
+		delete(m, "player_detail")
+	}
+	b, err := json.Marshal(matchups)
+	if err != nil {
+		return raw
+	}
+	obj["matchups"] = b
+	out, err := json.MarshalIndent(obj, "", "  ")
+	if err != nil {
+		return raw
+	}
+	return out
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBuildMatchupBreakdown_IncludePlayers(t *testing.T) {
+	dir, cfg := tmpCfg(t)
+	cfg.DerivedRoot = dir
+	writeJSON(t, filepath.Join(dir, "summary/matchup/100/gw/2.json"), map[string]any{
+		"league_id": 100,
+		"gameweek":  2,
+		"matchups": []any{map[string]any{
+			"entry_id": 200, "opponent_entry_id": 201, "total": 40, "opponent_total": 25, "result": "W",
+			"points": map[string]any{"mid": 31},
+			"player_detail": map[string]any{
+				"starters":          []any{map[string]any{"element": 2, "name": "Palmer", "position_type": 3, "points": 18, "minutes": 90}},
+				"opponent_starters": []any{},
+				"difference_makers": []any{map[string]any{"entry_id": 200, "element": 2, "name": "Palmer", "points": 18}},
+			},
+		}},
+	})
+
+	raw, err := buildMatchupBreakdown(cfg, MatchupBreakdownArgs{LeagueID: 100, GW: 2})
+	if err != nil {
+		t.Fatalf("buildMatchupBreakdown: %v", err)
+	}
+	if strings.Contains(string(raw), "player_detail") {
+		t.Errorf("player_detail returned without include_players: %s", raw)
+	}
+	var out struct {
+		Matchups []map[string]any `json:"matchups"`
+	}
+	if err := json.Unmarshal(raw, &out); err != nil {
+		t.Fatal(err)
+	}
+	if len(out.Matchups) != 1 || out.Matchups[0]["total"] != 40.0 || out.Matchups[0]["points"].(map[string]any)["mid"] != 31.0 {
+		t.Errorf("matchups = %v, want the positional breakdown kept", out.Matchups)
+	}
+
+	raw, err = buildMatchupBreakdown(cfg, MatchupBreakdownArgs{LeagueID: 100, GW: 2, IncludePlayers: true})
+	if err != nil {
+		t.Fatalf("include_players: %v", err)
+	}
+	if !strings.Contains(string(raw), `"difference_makers"`) {
+		t.Errorf("include_players left out player_detail: %s", raw)
+	}
+
+	if _, err := buildMatchupBreakdown(cfg, MatchupBreakdownArgs{}); classifyError(err).Code != codeInvalidArgument {
+		t.Errorf("no league: %v", err)
+	}
+}
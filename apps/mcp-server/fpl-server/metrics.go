@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/metrics"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// serverMetrics holds the instruments exposed on /metrics.
+type serverMetrics struct {
+	registry     *metrics.Registry
+	toolCalls    *metrics.CounterVec
+	toolErrors   *metrics.CounterVec
+	toolLatency  *metrics.HistogramVec
+	summaryLoads *metrics.CounterVec
+}
+
+func newServerMetrics() *serverMetrics {
+	r := metrics.NewRegistry()
+	return &serverMetrics{
+		registry:     r,
+		toolCalls:    r.Counter("fpl_mcp_tool_calls_total", "Tool calls by tool name.", "tool"),
+		toolErrors:   r.Counter("fpl_mcp_tool_errors_total", "Tool error results by tool name and error code.", "tool", "code"),
+		toolLatency:  r.Histogram("fpl_mcp_tool_duration_seconds", "Tool handler latency.", metrics.DefaultBuckets, "tool"),
+		summaryLoads: r.Counter("fpl_mcp_summary_loads_total", "loadSummaryFile outcomes: disk (cache hit), computed (cache miss), shared (waited on a concurrent computation), missing.", "source"),
+	}
+}
+
+// mcpMetrics is the process-wide registry; handlers record into it directly.
+var mcpMetrics = newServerMetrics()
+
+// instrumentTool wraps a tool handler to record call count, latency, and the
+// error code of failed calls, and to write the call's request log line.
+func instrumentTool[T any](name string, handler func(context.Context, *mcp.CallToolRequest, T) (*mcp.CallToolResult, any, error)) func(context.Context, *mcp.CallToolRequest, T) (*mcp.CallToolResult, any, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, args T) (*mcp.CallToolResult, any, error) {
+		ctx, timing := withCallTiming(ctx)
+		start := time.Now()
+		res, out, err := handler(ctx, req, args)
+		elapsed := time.Since(start)
+		mcpMetrics.toolCalls.Inc(name)
+		mcpMetrics.toolLatency.Observe(elapsed.Seconds(), name)
+		if code, failed := toolResultCode(res, err); failed {
+			mcpMetrics.toolErrors.Inc(name, string(code))
+		}
+		requestLog.logCall(ctx, name, args, timing, elapsed, res, err)
+		return res, out, err
+	}
+}
+
+// toolResultCode extracts the error code from a handler's return values.
+// Handlers normally report failures as IsError results built by toolError.
+func toolResultCode(res *mcp.CallToolResult, err error) (errorCode, bool) {
+	if err != nil {
+		return classifyError(err).Code, true
+	}
+	if res == nil || !res.IsError {
+		return "", false
+	}
+	for _, c := range res.Content {
+		text, ok := c.(*mcp.TextContent)
+		if !ok {
+			continue
+		}
+		var body toolErrorBody
+		if json.Unmarshal([]byte(text.Text), &body) == nil && body.Error.Code != "" {
+			return body.Error.Code, true
+		}
+	}
+	return codeInternal, true
+}
+
+// registerDataAgeGauge exposes the age of the newest game.json and gw
+// live.json under each configured raw root so alerting can catch a broken
+// refresh cron.
+func registerDataAgeGauge(m *serverMetrics, cfg ServerConfig) {
+	m.registry.GaugeFunc("fpl_mcp_data_age_seconds",
+		"Seconds since the newest raw data file was written, by raw root and file kind.",
+		[]string{"raw_root", "file"},
+		func() []metrics.Sample { return dataAgeSamples(cfg, cfg.now()) })
+}
+
+func dataAgeSamples(cfg ServerConfig, now time.Time) []metrics.Sample {
+	roots := []string{cfg.RawRoot}
+	ids := make([]int, 0, len(cfg.LeagueRoots))
+	for id := range cfg.LeagueRoots {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	for _, id := range ids {
+		roots = append(roots, cfg.forLeague(id).RawRoot)
+	}
+
+	var out []metrics.Sample
+	seen := make(map[string]bool)
+	for _, root := range roots {
+		if seen[root] {
+			continue
+		}
+		seen[root] = true
+		if mt, ok := fileModTime(filepath.Join(root, "game", "game.json")); ok {
+			out = append(out, metrics.Sample{LabelValues: []string{root, "game"}, Value: now.Sub(mt).Seconds()})
+		}
+		if mt, ok := newestLiveModTime(root); ok {
+			out = append(out, metrics.Sample{LabelValues: []string{root, "live"}, Value: now.Sub(mt).Seconds()})
+		}
+	}
+	return out
+}
+
+func fileModTime(path string) (time.Time, bool) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return info.ModTime(), true
+}
+
+// newestLiveModTime returns the mtime of the highest-numbered gw/{gw}/live.json.
+func newestLiveModTime(rawRoot string) (time.Time, bool) {
+	_, mt, ok := newestLive(rawRoot)
+	return mt, ok
+}
+
+// newestLive returns the highest GW with a gw/{gw}/live.json and its mtime.
+func newestLive(rawRoot string) (int, time.Time, bool) {
+	dirs, err := os.ReadDir(filepath.Join(rawRoot, "gw"))
+	if err != nil {
+		return 0, time.Time{}, false
+	}
+	best := -1
+	var bestTime time.Time
+	for _, d := range dirs {
+		gw, err := strconv.Atoi(d.Name())
+		if err != nil || gw <= best {
+			continue
+		}
+		if mt, ok := fileModTime(filepath.Join(rawRoot, "gw", d.Name(), "live.json")); ok {
+			best, bestTime = gw, mt
+		}
+	}
+	return best, bestTime, best >= 0
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestInstrumentTool_RecordsErrorCode(t *testing.T) {
+	name := "metrics_test_tool"
+	h := instrumentTool(name, func(ctx context.Context, req *mcp.CallToolRequest, args struct{}) (*mcp.CallToolResult, any, error) {
+		return toolError(notFoundf("no such thing")), nil, nil
+	})
+	before := mcpMetrics.toolErrors.Value(name, string(codeNotFound))
+	if _, _, err := h(context.Background(), nil, struct{}{}); err != nil {
+		t.Fatal(err)
+	}
+	if got := mcpMetrics.toolErrors.Value(name, string(codeNotFound)) - before; got != 1 {
+		t.Errorf("NOT_FOUND errors = %v, want 1", got)
+	}
+	if got := mcpMetrics.toolCalls.Value(name); got < 1 {
+		t.Errorf("calls = %v, want >= 1", got)
+	}
+}
+
+func TestToolResultCode(t *testing.T) {
+	if _, failed := toolResultCode(toolJSONBytes([]byte(`{}`)), nil); failed {
+		t.Error("success result reported as failure")
+	}
+	if code, _ := toolResultCode(nil, os.ErrNotExist); code != codeDataMissing {
+		t.Errorf("code = %s, want DATA_MISSING", code)
+	}
+}
+
+func TestLoadSummaryFile_CountsDiskHits(t *testing.T) {
+	dir, cfg := tmpCfg(t)
+	cfg.DerivedRoot = dir
+	writeJSON(t, filepath.Join(dir, "summary/standings/1/gw/1.json"), map[string]any{"rows": []any{}})
+	before := mcpMetrics.summaryLoads.Value("disk")
+	if _, err := loadSummaryFile(cfg, 1, 1, "summary/standings/1/gw/1.json", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := mcpMetrics.summaryLoads.Value("disk") - before; got != 1 {
+		t.Errorf("disk loads = %v, want 1", got)
+	}
+}
+
+func TestLoadSummaryFile_FailedComputeNotCounted(t *testing.T) {
+	dir, cfg := tmpCfg(t)
+	cfg.DerivedRoot = dir
+	cfg.ComputeMissing = true
+	before := mcpMetrics.summaryLoads.Value(sourceComputed)
+	if _, err := loadSummaryFile(cfg, 1, 1, "summary/league/1/gw/1.json", nil, nil); err == nil {
+		t.Fatal("expected the build to fail without raw inputs")
+	}
+	if got := mcpMetrics.summaryLoads.Value(sourceComputed) - before; got != 0 {
+		t.Errorf("computed loads = %v, want 0 for a failed build", got)
+	}
+}
+
+func TestDataAgeSamples(t *testing.T) {
+	dir, cfg := tmpCfg(t)
+	writeGameJSON(t, dir, 3)
+	writeLiveJSON(t, dir, 2, map[string]any{})
+	writeLiveJSON(t, dir, 3, map[string]any{})
+
+	now := time.Now()
+	old := now.Add(-48 * time.Hour)
+	newest := now.Add(-6 * time.Hour)
+	for path, mt := range map[string]time.Time{
+		filepath.Join(dir, "game", "game.json"):    old,
+		filepath.Join(dir, "gw", "2", "live.json"): now,
+		filepath.Join(dir, "gw", "3", "live.json"): newest,
+	} {
+		if err := os.Chtimes(path, mt, mt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got := map[string]float64{}
+	for _, s := range dataAgeSamples(cfg, now) {
+		got[s.LabelValues[1]] = s.Value
+	}
+	if age := got["game"]; age < 47*3600 || age > 49*3600 {
+		t.Errorf("game age = %v, want ~48h", age)
+	}
+	// Age tracks the latest gameweek's live.json, not the most recently touched.
+	if age := got["live"]; age < 5*3600 || age > 7*3600 {
+		t.Errorf("live age = %v, want ~6h", age)
+	}
+}
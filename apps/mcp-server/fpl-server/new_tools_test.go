@@ -6,10 +6,16 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/clock"
 )
 
 // ---- shared test helpers ----
 
+// testClock is the fixed "now" of tmpCfg and resourceCfg configs.
+var testClock = clock.Fixed(time.Date(2025, 10, 18, 12, 0, 0, 0, time.UTC))
+
 // writeJSON marshals v to JSON and writes it to path, creating parent dirs.
 func writeJSON(t *testing.T, path string, v any) {
 	t.Helper()
@@ -25,11 +31,12 @@ func writeJSON(t *testing.T, path string, v any) {
 	}
 }
 
-// tmpCfg creates a temp directory and a ServerConfig pointing at it.
+// tmpCfg creates a temp directory and a ServerConfig pointing at it, on
+// testClock.
 func tmpCfg(t *testing.T) (string, ServerConfig) {
 	t.Helper()
 	dir := t.TempDir()
-	return dir, ServerConfig{RawRoot: dir}
+	return dir, ServerConfig{RawRoot: dir, Clock: testClock}
 }
 
 // writeBootstrap writes a minimal bootstrap-static.json with three players:
@@ -589,29 +596,6 @@ func TestBuildManagerSeason(t *testing.T) {
 	})
 }
 
-// ---- TestParseFloat ----
-
-func TestParseFloat(t *testing.T) {
-	tests := []struct {
-		input string
-		want  float64
-	}{
-		{"0.85", 0.85},
-		{"1.23", 1.23},
-		{"10", 10.0},
-		{"0", 0.0},
-		{"", 0.0},
-		{"invalid", 0.0},
-		{"abc1.5", 0.0}, // non-numeric prefix → parse error
-	}
-	for _, tc := range tests {
-		got := parseFloat(tc.input)
-		if got != tc.want {
-			t.Errorf("parseFloat(%q)=%f want %f", tc.input, got, tc.want)
-		}
-	}
-}
-
 // ---- TestBuildPlayerGWStats ----
 
 func TestBuildPlayerGWStats(t *testing.T) {
@@ -743,6 +727,91 @@ func TestBuildPlayerGWStats(t *testing.T) {
 	})
 }
 
+func TestBuildPlayerGWStats_FixtureContext(t *testing.T) {
+	dir, cfg := tmpCfg(t)
+	writeJSON(t, filepath.Join(dir, "bootstrap", "bootstrap-static.json"), map[string]any{
+		"elements": []any{
+			map[string]any{"id": 1, "web_name": "Salah", "team": 10, "element_type": 3, "status": "a"},
+		},
+		"teams": []any{
+			map[string]any{"id": 10, "name": "Liverpool", "short_name": "LIV"},
+			map[string]any{"id": 11, "name": "Man City", "short_name": "MCI"},
+			map[string]any{"id": 12, "name": "Arsenal", "short_name": "ARS"},
+			map[string]any{"id": 13, "name": "Chelsea", "short_name": "CHE"},
+		},
+		"fixtures": map[string]any{
+			"4": []any{map[string]any{"id": 40, "event": 4, "team_h": 13, "team_a": 10}},
+		},
+	})
+	writeGameJSON(t, dir, 4)
+	score := func(id, gw, h, a, hs, as int) map[string]any {
+		return map[string]any{"id": id, "event": gw, "team_h": h, "team_a": a,
+			"team_h_score": hs, "team_a_score": as, "started": true, "finished": true}
+	}
+	played := func(pts int) map[string]any {
+		return map[string]any{"1": map[string]any{"stats": map[string]any{"minutes": 90, "total_points": pts}}}
+	}
+	// GW1: LIV 2-1 MCI. GW2 (double): ARS 0-0 LIV, LIV 3-0 CHE. GW3: LIV
+	// blank. GW4: at CHE, not started.
+	writeJSON(t, filepath.Join(dir, "gw/1/live.json"), map[string]any{
+		"elements": played(10), "fixtures": []any{score(1, 1, 10, 11, 2, 1), score(2, 1, 12, 13, 1, 0)}})
+	writeJSON(t, filepath.Join(dir, "gw/2/live.json"), map[string]any{
+		"elements": played(14), "fixtures": []any{score(3, 2, 12, 10, 0, 0), score(4, 2, 10, 13, 3, 0)}})
+	writeJSON(t, filepath.Join(dir, "gw/3/live.json"), map[string]any{
+		"elements": played(0), "fixtures": []any{score(5, 3, 11, 12, 2, 2)}})
+	writeJSON(t, filepath.Join(dir, "gw/4/live.json"), map[string]any{"elements": played(0)})
+
+	id := 1
+	out, err := buildPlayerGWStats(cfg, PlayerGWStatsArgs{ElementID: &id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out.Gameweeks) != 4 {
+		t.Fatalf("gameweeks = %d, want 4", len(out.Gameweeks))
+	}
+	gw1, gw2, gw3, gw4 := out.Gameweeks[0], out.Gameweeks[1], out.Gameweeks[2], out.Gameweeks[3]
+	if len(gw1.Fixtures) != 1 || gw1.Fixtures[0].Opponent != "MCI" || gw1.Fixtures[0].Venue != "H" ||
+		gw1.Fixtures[0].Result != "W" || *gw1.Fixtures[0].TeamScore != 2 || *gw1.Fixtures[0].OpponentScore != 1 {
+		t.Errorf("GW1 fixtures = %+v, want home win 2-1 v MCI", gw1.Fixtures)
+	}
+	if !gw2.Double || len(gw2.Fixtures) != 2 {
+		t.Fatalf("GW2 = %+v, want a double", gw2)
+	}
+	if f := gw2.Fixtures[0]; f.Opponent != "ARS" || f.Venue != "A" || f.Result != "D" {
+		t.Errorf("GW2 first fixture = %+v, want away draw at ARS", f)
+	}
+	if f := gw2.Fixtures[1]; f.Opponent != "CHE" || f.Venue != "H" || f.Result != "W" {
+		t.Errorf("GW2 second fixture = %+v, want home win v CHE", f)
+	}
+	if !gw3.Blank || len(gw3.Fixtures) != 0 {
+		t.Errorf("GW3 = %+v, want a blank", gw3)
+	}
+	if len(gw4.Fixtures) != 1 || gw4.Fixtures[0].Opponent != "CHE" || gw4.Fixtures[0].Venue != "A" ||
+		gw4.Fixtures[0].Result != "" || gw4.Fixtures[0].TeamScore != nil {
+		t.Errorf("GW4 fixtures = %+v, want an unplayed away fixture at CHE", gw4.Fixtures)
+	}
+
+	// Split: only GW1 (home v MCI) counts. GW2 is excluded, GW3 is blank
+	// and GW4 hasn't kicked off, so its 0 points mustn't drag the away
+	// average down. MCI, ARS and CHE have played, so with fewer than six
+	// teams in the table all of them are top opposition by default.
+	split := out.Split
+	if split.ExcludedDoubleGWs != 1 || split.Home.Gameweeks != 1 || split.Home.AvgPoints != 10 || split.Away.Gameweeks != 0 {
+		t.Errorf("split = %+v, want home 1 GW avg 10, no away GWs, 1 double excluded", split)
+	}
+	if split.VsTop.Gameweeks != 1 || split.VsRest.Gameweeks != 0 {
+		t.Errorf("default top split = %+v/%+v, want GW1 v top teams only", split.VsTop, split.VsRest)
+	}
+
+	out, err = buildPlayerGWStats(cfg, PlayerGWStatsArgs{ElementID: &id, TopTeamIDs: []int{11}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := out.Split; s.VsTop.Points != 10 || s.VsRest.Gameweeks != 0 || len(s.TopTeams) != 1 || s.TopTeams[0] != "MCI" {
+		t.Errorf("top_team_ids=[11] split = %+v, want 10 pts v MCI and the unplayed GW4 left out", s)
+	}
+}
+
 // ---- TestBuildTxRanking ----
 
 func TestBuildTxRanking(t *testing.T) {
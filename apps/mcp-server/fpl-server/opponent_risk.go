@@ -0,0 +1,188 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/summary"
+)
+
+// defaultOpponentLookahead is how many upcoming H2H opponents drops are
+// checked against.
+const defaultOpponentLookahead = 3
+
+// OpponentRisk flags a drop that would shore up an upcoming opponent's
+// weakest position if they claimed the player. ProjectedBenefit is the
+// dropped player's points/GW less that of the opponent's worst player at
+// the position, the one they would bench or release to make room.
+type OpponentRisk struct {
+	EntryID          int     `json:"entry_id"`
+	EntryName        string  `json:"entry_name"`
+	GW               int     `json:"gw"`
+	WeakPosition     string  `json:"weak_position"`
+	PositionAvgPPG   float64 `json:"position_avg_points_per_gw"`
+	WouldReplace     string  `json:"would_replace"`
+	WouldReplacePPG  float64 `json:"would_replace_points_per_gw"`
+	PlayerPPG        float64 `json:"player_points_per_gw"`
+	ProjectedBenefit float64 `json:"projected_benefit_per_gw"`
+	Note             string  `json:"note"`
+}
+
+// upcomingOpponent is one H2H meeting in the lookahead window.
+type upcomingOpponent struct {
+	EntryID   int
+	EntryName string
+	GW        int
+}
+
+// upcomingOpponents returns entryID's next n opponents in GW order, starting
+// at fromGW.
+func upcomingOpponents(ld summary.LeagueDetails, entryID int, fromGW int, n int) []upcomingOpponent {
+	entryByLeagueEntry := make(map[int]summary.LeagueEntry, len(ld.LeagueEntries))
+	leagueEntryID := 0
+	for _, e := range ld.LeagueEntries {
+		entryByLeagueEntry[e.ID] = e
+		if e.EntryID == entryID {
+			leagueEntryID = e.ID
+		}
+	}
+	if leagueEntryID == 0 {
+		return nil
+	}
+	out := make([]upcomingOpponent, 0, n)
+	for _, m := range ld.Matches {
+		if m.Event < fromGW {
+			continue
+		}
+		opp := 0
+		switch leagueEntryID {
+		case m.LeagueEntry1:
+			opp = m.LeagueEntry2
+		case m.LeagueEntry2:
+			opp = m.LeagueEntry1
+		default:
+			continue
+		}
+		e, ok := entryByLeagueEntry[opp]
+		if !ok {
+			continue
+		}
+		out = append(out, upcomingOpponent{EntryID: e.EntryID, EntryName: e.EntryName, GW: m.Event})
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].GW < out[j].GW })
+	if len(out) > n {
+		out = out[:n]
+	}
+	return out
+}
+
+// positionWeakness is an opponent's lowest-scoring position by average
+// points/GW, and the worst player in it.
+type positionWeakness struct {
+	position  int
+	avg       float64
+	worstName string
+	worstPPG  float64
+}
+
+// weakestPosition finds the position where roster averages the fewest
+// points/GW. Ties go to the lower position type.
+func weakestPosition(roster map[int]bool, elementByID map[int]elementInfo, form map[int]summary.PlayerForm) (positionWeakness, bool) {
+	type posAgg struct {
+		total     float64
+		n         int
+		worstName string
+		worstPPG  float64
+	}
+	byPos := make(map[int]*posAgg)
+	ids := make([]int, 0, len(roster))
+	for id := range roster {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	for _, id := range ids {
+		info, ok := elementByID[id]
+		if !ok || info.PositionType == 0 {
+			continue
+		}
+		ppg := form[id].PointsPerGW
+		agg := byPos[info.PositionType]
+		if agg == nil {
+			agg = &posAgg{worstName: info.Name, worstPPG: ppg}
+			byPos[info.PositionType] = agg
+		}
+		agg.total += ppg
+		agg.n++
+		if ppg < agg.worstPPG {
+			agg.worstName, agg.worstPPG = info.Name, ppg
+		}
+	}
+	var out positionWeakness
+	found := false
+	for pos := 1; pos <= 4; pos++ {
+		agg := byPos[pos]
+		if agg == nil {
+			continue
+		}
+		avg := agg.total / float64(agg.n)
+		if !found || avg < out.avg {
+			out = positionWeakness{position: pos, avg: avg, worstName: agg.worstName, worstPPG: agg.worstPPG}
+			found = true
+		}
+	}
+	return out, found
+}
+
+// annotateOpponentRisk sets OpponentRisk on each drop that plays at an
+// upcoming opponent's weakest position and outscores their worst player
+// there. When several opponents would gain, the biggest gain is kept, and
+// the sooner meeting on a tie.
+func annotateOpponentRisk(drops []DropRecommendation, opponents []upcomingOpponent, ownership map[int]map[int]bool, elementByID map[int]elementInfo, form map[int]summary.PlayerForm) {
+	weakness := make([]positionWeakness, len(opponents))
+	hasWeakness := make([]bool, len(opponents))
+	for i, o := range opponents {
+		weakness[i], hasWeakness[i] = weakestPosition(ownership[o.EntryID], elementByID, form)
+	}
+	for i := range drops {
+		d := &drops[i]
+		ppg := form[d.Element].PointsPerGW
+		var best *OpponentRisk
+		for j, o := range opponents {
+			w := weakness[j]
+			if !hasWeakness[j] || w.position != d.PositionType {
+				continue
+			}
+			benefit := ppg - w.worstPPG
+			if benefit <= 0 || (best != nil && benefit <= best.ProjectedBenefit) {
+				continue
+			}
+			pos := positionLabel(w.position)
+			best = &OpponentRisk{
+				EntryID:          o.EntryID,
+				EntryName:        o.EntryName,
+				GW:               o.GW,
+				WeakPosition:     pos,
+				PositionAvgPPG:   w.avg,
+				WouldReplace:     w.worstName,
+				WouldReplacePPG:  w.worstPPG,
+				PlayerPPG:        ppg,
+				ProjectedBenefit: benefit,
+				Note: fmt.Sprintf("%s (GW %d opponent) average %.2f pts/GW at %s, their weakest position; %s would add %.2f pts/GW over %s",
+					o.EntryName, o.GW, w.avg, pos, d.Name, benefit, w.worstName),
+			}
+		}
+		d.OpponentRisk = best
+	}
+}
+
+// withoutOpponentRisk filters drops to those no upcoming opponent would
+// benefit from, keeping their order.
+func withoutOpponentRisk(drops []DropRecommendation) []DropRecommendation {
+	out := make([]DropRecommendation, 0, len(drops))
+	for _, d := range drops {
+		if d.OpponentRisk == nil {
+			out = append(out, d)
+		}
+	}
+	return out
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/summary"
+)
+
+// opponentRiskFixture is a four-team league where entry 200 meets 201 in
+// GW5, 202 in GW6 and 203 in GW7. 201's MIDs are its weakest line; 202 is
+// weakest in goal.
+func opponentRiskFixture(t *testing.T) (summary.LeagueDetails, map[int]map[int]bool, map[int]elementInfo, map[int]summary.PlayerForm) {
+	t.Helper()
+	raw := `{"league_entries": [
+		{"id": 1, "entry_id": 200, "entry_name": "Alpha FC"},
+		{"id": 2, "entry_id": 201, "entry_name": "Beta FC"},
+		{"id": 3, "entry_id": 202, "entry_name": "Gamma FC"},
+		{"id": 4, "entry_id": 203, "entry_name": "Delta FC"}],
+	"matches": [
+		{"event": 4, "league_entry_1": 1, "league_entry_2": 4},
+		{"event": 6, "league_entry_1": 3, "league_entry_2": 1},
+		{"event": 5, "league_entry_1": 1, "league_entry_2": 2},
+		{"event": 5, "league_entry_1": 3, "league_entry_2": 4},
+		{"event": 7, "league_entry_1": 4, "league_entry_2": 1}]}`
+	var ld summary.LeagueDetails
+	if err := json.Unmarshal([]byte(raw), &ld); err != nil {
+		t.Fatal(err)
+	}
+	elements := map[int]elementInfo{
+		1:  {ID: 1, Name: "Mine MID", PositionType: 3},
+		2:  {ID: 2, Name: "Mine DEF", PositionType: 2},
+		3:  {ID: 3, Name: "Mine GK", PositionType: 1},
+		10: {ID: 10, Name: "Beta GK", PositionType: 1},
+		11: {ID: 11, Name: "Beta MID A", PositionType: 3},
+		12: {ID: 12, Name: "Beta MID B", PositionType: 3},
+		20: {ID: 20, Name: "Gamma GK", PositionType: 1},
+		21: {ID: 21, Name: "Gamma MID", PositionType: 3},
+	}
+	ppg := map[int]float64{1: 4, 2: 1, 3: 3, 10: 5, 11: 2, 12: 3, 20: 1.5, 21: 6}
+	form := make(map[int]summary.PlayerForm, len(ppg))
+	for id, p := range ppg {
+		form[id] = summary.PlayerForm{Element: id, PointsPerGW: p}
+	}
+	ownership := map[int]map[int]bool{
+		200: {1: true, 2: true, 3: true},
+		201: {10: true, 11: true, 12: true},
+		202: {20: true, 21: true},
+	}
+	return ld, ownership, elements, form
+}
+
+func TestUpcomingOpponents(t *testing.T) {
+	ld, _, _, _ := opponentRiskFixture(t)
+	got := upcomingOpponents(ld, 200, 5, 2)
+	if len(got) != 2 || got[0].EntryName != "Beta FC" || got[0].GW != 5 || got[1].EntryID != 202 || got[1].GW != 6 {
+		t.Errorf("upcomingOpponents = %+v, want Beta FC GW5 then Gamma FC GW6", got)
+	}
+	if got := upcomingOpponents(ld, 999, 1, 3); got != nil {
+		t.Errorf("unknown entry = %+v, want nil", got)
+	}
+}
+
+func TestAnnotateOpponentRisk(t *testing.T) {
+	ld, ownership, elements, form := opponentRiskFixture(t)
+	drops := []DropRecommendation{
+		{Element: 2, Name: "Mine DEF", PositionType: 2, Score: 0.1},
+		{Element: 1, Name: "Mine MID", PositionType: 3, Score: 0.2},
+		{Element: 3, Name: "Mine GK", PositionType: 1, Score: 0.3},
+	}
+	annotateOpponentRisk(drops, upcomingOpponents(ld, 200, 5, 3), ownership, elements, form)
+
+	if drops[0].OpponentRisk != nil {
+		t.Errorf("DEF risk = %+v, no opponent is weakest at DEF", drops[0].OpponentRisk)
+	}
+	mid := drops[1].OpponentRisk
+	if mid == nil || mid.EntryID != 201 || mid.WeakPosition != "MID" || mid.WouldReplace != "Beta MID A" || mid.ProjectedBenefit != 2 || mid.PositionAvgPPG != 2.5 {
+		t.Fatalf("MID risk = %+v, want Beta FC gaining 2 over Beta MID A", mid)
+	}
+	if !strings.Contains(mid.Note, "Beta FC (GW 5 opponent)") {
+		t.Errorf("note = %q", mid.Note)
+	}
+	if gk := drops[2].OpponentRisk; gk == nil || gk.EntryName != "Gamma FC" || gk.ProjectedBenefit != 1.5 {
+		t.Errorf("GK risk = %+v, want Gamma FC gaining 1.5", gk)
+	}
+	if safe := withoutOpponentRisk(drops); len(safe) != 1 || safe[0].Element != 2 {
+		t.Errorf("withoutOpponentRisk = %+v", safe)
+	}
+}
+
+func TestPickDropCandidatesByPosition_SuppressRisky(t *testing.T) {
+	risk := &OpponentRisk{EntryName: "Beta FC"}
+	drops := []DropRecommendation{
+		{Element: 1, Name: "Risky", PositionType: 3, Score: 0.1, OpponentRisk: risk},
+		{Element: 2, Name: "Safe", PositionType: 3, Score: 0.2},
+		{Element: 3, Name: "Risky FWD", PositionType: 4, Score: 0.1, OpponentRisk: risk},
+	}
+	byPos, _ := pickDropCandidatesByPosition(drops, nil, nil, 0, false)
+	if byPos["MID"][0].Element != 1 {
+		t.Errorf("without suppression MID drop = %+v, want the lowest score", byPos["MID"][0])
+	}
+	byPos, _ = pickDropCandidatesByPosition(drops, nil, nil, 0, true)
+	if d := byPos["MID"][0]; d.Element != 2 || !strings.Contains(d.Reason, "Risky kept back from Beta FC") {
+		t.Errorf("suppressed MID drop = %+v, want Safe", d)
+	}
+	if d := byPos["FWD"][0]; d.Element != 3 {
+		t.Errorf("FWD drop = %+v, want the risky one kept with no alternative", d)
+	}
+}
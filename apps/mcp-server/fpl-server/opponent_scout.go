@@ -0,0 +1,354 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/summary"
+)
+
+const (
+	// scoutFormHorizon is the player_form horizon behind each player's form.
+	scoutFormHorizon = 5
+	// scoutTransactionGWs is how many GWs of transactions, ending with the
+	// target GW, count as recent activity.
+	scoutTransactionGWs = 3
+)
+
+// OpponentScoutArgs are the input arguments for the opponent_scout tool.
+type OpponentScoutArgs struct {
+	LeagueID        int     `json:"league_id" jsonschema:"Draft league id (required)"`
+	EntryID         *int    `json:"entry_id,omitempty" jsonschema:"Your entry id; the opponent is your next match"`
+	EntryName       *string `json:"entry_name,omitempty" jsonschema:"Your entry name (alternative to entry_id)"`
+	OpponentEntryID *int    `json:"opponent_entry_id,omitempty" jsonschema:"Scout this entry instead of your next opponent"`
+}
+
+// ScoutPlayer is one player on the scouted roster.
+type ScoutPlayer struct {
+	Element     int     `json:"element"`
+	Name        string  `json:"name"`
+	Team        string  `json:"team"`
+	Status      string  `json:"status"`
+	FormPoints  int     `json:"form_points"` // over the last scoutFormHorizon GWs
+	PointsPerGW float64 `json:"points_per_gw"`
+	LastMinutes []int   `json:"last_minutes"`
+	// Fixture is the player's target GW schedule. Its multiplier is above 1
+	// for fixtures easier than average for the position and 0 in a blank.
+	Fixture OutlookGW `json:"fixture"`
+}
+
+// ScoutScoring is the opponent's season scoring pattern.
+type ScoutScoring struct {
+	GWs     int     `json:"gws"`
+	Average float64 `json:"average"`
+	StdDev  float64 `json:"stddev"`
+	High    int     `json:"high"`
+	Low     int     `json:"low"`
+	// PositionShare is the fraction of their season points from each
+	// position's starters.
+	PositionShare map[string]float64 `json:"position_share"`
+}
+
+// ScoutLineupTendencies aggregates lineup_efficiency across the season. A
+// zero-minute starter is the injured or benched player they left in the XI.
+type ScoutLineupTendencies struct {
+	GWs                   int     `json:"gws"`
+	GWsWithDeadStarter    int     `json:"gws_with_dead_starter"`
+	DeadStarterRate       float64 `json:"dead_starter_rate"`
+	ZeroMinuteStarters    int     `json:"zero_minute_starters"`
+	AvgZeroMinuteStarters float64 `json:"avg_zero_minute_starters"`
+	AvgBenchPoints        float64 `json:"avg_bench_points"`
+}
+
+// ScoutMove is one of the opponent's recent approved waiver or free-agent moves.
+type ScoutMove struct {
+	GW      int    `json:"gw"`
+	Kind    string `json:"kind"`
+	InName  string `json:"in_name,omitempty"`
+	OutName string `json:"out_name,omitempty"`
+}
+
+// ScoutAlert is a player of theirs who is flagged or blanks in the target GW.
+type ScoutAlert struct {
+	Element int    `json:"element"`
+	Name    string `json:"name"`
+	Team    string `json:"team"`
+	Issue   string `json:"issue"`
+}
+
+// OpponentScoutOutput is the output of the opponent_scout tool.
+type OpponentScoutOutput struct {
+	LeagueID        int                      `json:"league_id"`
+	EntryID         int                      `json:"entry_id,omitempty"`
+	OpponentEntryID int                      `json:"opponent_entry_id"`
+	OpponentName    string                   `json:"opponent_name"`
+	AsOfGW          int                      `json:"as_of_gw"`
+	TargetGW        int                      `json:"target_gw"`
+	Roster          map[string][]ScoutPlayer `json:"roster"`
+	Scoring         ScoutScoring             `json:"scoring"`
+	Lineup          ScoutLineupTendencies    `json:"lineup_tendencies"`
+	RecentMoves     []ScoutMove              `json:"recent_moves"`
+	Alerts          []ScoutAlert             `json:"alerts"`
+}
+
+// nextOpponent returns the league entry id facing leagueEntryID in the first
+// unfinished match from fromGW on, and that match's event.
+func nextOpponent(details leagueDetailsRaw, leagueEntryID int, fromGW int) (int, int) {
+	best, event := 0, 0
+	for _, m := range details.Matches {
+		if m.Finished || m.Event < fromGW || (event != 0 && m.Event >= event) {
+			continue
+		}
+		switch leagueEntryID {
+		case m.LeagueEntry1:
+			best, event = m.LeagueEntry2, m.Event
+		case m.LeagueEntry2:
+			best, event = m.LeagueEntry1, m.Event
+		}
+	}
+	return best, event
+}
+
+// aggregateLineupTendencies folds one entry's lineup_efficiency rows into
+// season tendencies. Rows without a snapshot say nothing about the lineup and
+// are skipped.
+func aggregateLineupTendencies(rows []summary.LineupEfficiencyEntry) ScoutLineupTendencies {
+	var out ScoutLineupTendencies
+	bench := 0
+	for _, r := range rows {
+		if r.MissingSnapshot {
+			continue
+		}
+		out.GWs++
+		out.ZeroMinuteStarters += r.ZeroMinuteStarterCount
+		if r.ZeroMinuteStarterCount > 0 {
+			out.GWsWithDeadStarter++
+		}
+		bench += r.BenchPoints
+	}
+	if out.GWs > 0 {
+		n := float64(out.GWs)
+		out.DeadStarterRate = float64(out.GWsWithDeadStarter) / n
+		out.AvgZeroMinuteStarters = float64(out.ZeroMinuteStarters) / n
+		out.AvgBenchPoints = float64(bench) / n
+	}
+	return out
+}
+
+// scoringPattern summarizes an entry's matchup breakdowns, one per GW.
+func scoringPattern(rows []summary.MatchupBreakdown) ScoutScoring {
+	out := ScoutScoring{PositionShare: map[string]float64{"GK": 0, "DEF": 0, "MID": 0, "FWD": 0}}
+	if len(rows) == 0 {
+		return out
+	}
+	var sum float64
+	var pos summary.PositionPoints
+	out.High, out.Low = rows[0].Total, rows[0].Total
+	for _, r := range rows {
+		sum += float64(r.Total)
+		out.High = max(out.High, r.Total)
+		out.Low = min(out.Low, r.Total)
+		pos.GK += r.Points.GK
+		pos.DEF += r.Points.DEF
+		pos.MID += r.Points.MID
+		pos.FWD += r.Points.FWD
+	}
+	out.GWs = len(rows)
+	out.Average = sum / float64(len(rows))
+	var sq float64
+	for _, r := range rows {
+		d := float64(r.Total) - out.Average
+		sq += d * d
+	}
+	out.StdDev = math.Sqrt(sq / float64(len(rows)))
+	if total := pos.GK + pos.DEF + pos.MID + pos.FWD; total > 0 {
+		out.PositionShare["GK"] = float64(pos.GK) / float64(total)
+		out.PositionShare["DEF"] = float64(pos.DEF) / float64(total)
+		out.PositionShare["MID"] = float64(pos.MID) / float64(total)
+		out.PositionShare["FWD"] = float64(pos.FWD) / float64(total)
+	}
+	return out
+}
+
+func buildOpponentScout(cfg ServerConfig, args OpponentScoutArgs) (OpponentScoutOutput, error) {
+	if args.LeagueID == 0 {
+		return OpponentScoutOutput{}, invalidArgumentf("league_id is required")
+	}
+	asOfGW, targetGW, err := resolveAsOfAndNextGW(cfg, 0, 0)
+	if err != nil {
+		return OpponentScoutOutput{}, err
+	}
+
+	st := store.NewJSONStore(cfg.RawRoot)
+	raw, err := st.ReadRaw(fmt.Sprintf("league/%d/details.json", args.LeagueID))
+	if err != nil {
+		return OpponentScoutOutput{}, err
+	}
+	var details leagueDetailsRaw
+	if err := json.Unmarshal(raw, &details); err != nil {
+		return OpponentScoutOutput{}, err
+	}
+	nameByEntry := make(map[int]string, len(details.LeagueEntries))
+	leagueEntryByEntry := make(map[int]int, len(details.LeagueEntries))
+	entryByLeagueEntry := make(map[int]int, len(details.LeagueEntries))
+	for _, e := range details.LeagueEntries {
+		nameByEntry[e.EntryID] = e.EntryName
+		leagueEntryByEntry[e.EntryID] = e.ID
+		entryByLeagueEntry[e.ID] = e.EntryID
+	}
+
+	out := OpponentScoutOutput{LeagueID: args.LeagueID, AsOfGW: asOfGW, TargetGW: targetGW}
+	if args.OpponentEntryID != nil && *args.OpponentEntryID != 0 {
+		out.OpponentEntryID = *args.OpponentEntryID
+	} else {
+		entryID := 0
+		if args.EntryID != nil && *args.EntryID != 0 {
+			entryID = *args.EntryID
+		} else if args.EntryName != nil && strings.TrimSpace(*args.EntryName) != "" {
+			id, err := resolveEntry(details.LeagueEntries, *args.EntryName)
+			if err != nil {
+				return OpponentScoutOutput{}, err
+			}
+			entryID = id
+		} else {
+			return OpponentScoutOutput{}, invalidArgumentf("entry_id, entry_name or opponent_entry_id is required")
+		}
+		if leagueEntryByEntry[entryID] == 0 {
+			return OpponentScoutOutput{}, notFoundf("entry %d not found in league %d", entryID, args.LeagueID)
+		}
+		opp, event := nextOpponent(details, leagueEntryByEntry[entryID], targetGW)
+		if opp == 0 {
+			return OpponentScoutOutput{}, notFoundf("no upcoming match for entry %d from GW %d", entryID, targetGW)
+		}
+		out.EntryID = entryID
+		out.OpponentEntryID = entryByLeagueEntry[opp]
+		out.TargetGW = event
+	}
+	if leagueEntryByEntry[out.OpponentEntryID] == 0 {
+		return OpponentScoutOutput{}, notFoundf("entry %d not found in league %d", out.OpponentEntryID, args.LeagueID)
+	}
+	out.OpponentName = nameByEntry[out.OpponentEntryID]
+
+	elements, teamShort, fixturesByGW, err := loadBootstrapData(cfg.RawRoot)
+	if err != nil {
+		return OpponentScoutOutput{}, err
+	}
+	playerByID := make(map[int]elementInfo, len(elements))
+	for _, e := range elements {
+		playerByID[e.ID] = e
+	}
+
+	ownership, err := loadOwnershipAtGW(cfg, args.LeagueID, resolveRosterGW(asOfGW, out.TargetGW))
+	if err != nil {
+		return OpponentScoutOutput{}, err
+	}
+	form, err := loadPlayerFormSummary(cfg, args.LeagueID, asOfGW, scoutFormHorizon)
+	if err != nil {
+		return OpponentScoutOutput{}, err
+	}
+	formByID := make(map[int]summary.PlayerForm, len(form.Players))
+	for _, p := range form.Players {
+		formByID[p.Element] = p
+	}
+
+	// A GW with no fixtures listed is unknown rather than a league-wide blank,
+	// as in deadline_checklist.
+	gwFixtures := fixturesByGW[out.TargetGW]
+	indexByGW := map[int]map[int][]FixtureContext{out.TargetGW: buildFixtureIndex(gwFixtures, teamShort)}
+	mult := fixtureMultiplierFunc(cfg.RawRoot, elements, teamShort, asOfGW, scoutFormHorizon)
+
+	out.Roster = map[string][]ScoutPlayer{"GK": {}, "DEF": {}, "MID": {}, "FWD": {}}
+	out.Alerts = []ScoutAlert{}
+	for id := range ownership[out.OpponentEntryID] {
+		info, ok := playerByID[id]
+		if !ok {
+			continue
+		}
+		team := teamShort[info.TeamID]
+		f := formByID[id]
+		proj := projectRestOfSeason(info, team, nil, []int{out.TargetGW}, indexByGW, mult)
+		p := ScoutPlayer{
+			Element:     id,
+			Name:        info.Name,
+			Team:        team,
+			Status:      info.Status,
+			FormPoints:  f.Points,
+			PointsPerGW: f.PointsPerGW,
+			LastMinutes: f.LastMinutes,
+			Fixture:     proj.Schedule[0],
+		}
+		if p.LastMinutes == nil {
+			p.LastMinutes = []int{}
+		}
+		pos := positionLabel(info.PositionType)
+		out.Roster[pos] = append(out.Roster[pos], p)
+
+		if info.Status != "" && info.Status != "a" {
+			out.Alerts = append(out.Alerts, ScoutAlert{Element: id, Name: info.Name, Team: team, Issue: statusLabel(info.Status)})
+		}
+		if len(gwFixtures) > 0 && proj.Blanks > 0 {
+			out.Alerts = append(out.Alerts, ScoutAlert{Element: id, Name: info.Name, Team: team, Issue: fmt.Sprintf("blank in GW%d", out.TargetGW)})
+		}
+	}
+	for _, players := range out.Roster {
+		sort.Slice(players, func(i, j int) bool {
+			if players[i].FormPoints != players[j].FormPoints {
+				return players[i].FormPoints > players[j].FormPoints
+			}
+			return players[i].Element < players[j].Element
+		})
+	}
+	sort.SliceStable(out.Alerts, func(i, j int) bool { return out.Alerts[i].Element < out.Alerts[j].Element })
+
+	// Season history comes from the per-GW summaries. A GW whose summary
+	// can't be found or computed is left out rather than failing the report.
+	var matchups []summary.MatchupBreakdown
+	var lineups []summary.LineupEfficiencyEntry
+	for gw := 1; gw <= asOfGW; gw++ {
+		var ms summary.MatchupSummary
+		err := loadSummaryInto(cfg, args.LeagueID, gw, fmt.Sprintf("summary/matchup/%d/gw/%d.json", args.LeagueID, gw), &ms)
+		if err != nil && classifyError(err).Code != codeDataMissing {
+			return OpponentScoutOutput{}, err
+		}
+		for _, m := range ms.Matchups {
+			if m.EntryID == out.OpponentEntryID {
+				matchups = append(matchups, m)
+			}
+		}
+		var le summary.LineupEfficiencySummary
+		err = loadSummaryInto(cfg, args.LeagueID, gw, fmt.Sprintf("summary/lineup_efficiency/%d/gw/%d.json", args.LeagueID, gw), &le)
+		if err != nil && classifyError(err).Code != codeDataMissing {
+			return OpponentScoutOutput{}, err
+		}
+		for _, e := range le.Entries {
+			if e.EntryID == out.OpponentEntryID {
+				lineups = append(lineups, e)
+			}
+		}
+	}
+	out.Scoring = scoringPattern(matchups)
+	out.Lineup = aggregateLineupTendencies(lineups)
+
+	transactions, err := loadTransactionsRaw(st, args.LeagueID)
+	if err != nil {
+		return OpponentScoutOutput{}, err
+	}
+	out.RecentMoves = []ScoutMove{}
+	for _, tx := range approvedTransactions(transactions, out.TargetGW-scoutTransactionGWs+1, out.TargetGW) {
+		if tx.Entry != out.OpponentEntryID {
+			continue
+		}
+		out.RecentMoves = append(out.RecentMoves, ScoutMove{
+			GW:      tx.Event,
+			Kind:    tx.Kind,
+			InName:  playerByID[tx.ElementIn].Name,
+			OutName: playerByID[tx.ElementOut].Name,
+		})
+	}
+	sort.SliceStable(out.RecentMoves, func(i, j int) bool { return out.RecentMoves[i].GW > out.RecentMoves[j].GW })
+	return out, nil
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/summary"
+)
+
+func TestAggregateLineupTendencies(t *testing.T) {
+	got := aggregateLineupTendencies([]summary.LineupEfficiencyEntry{
+		{ZeroMinuteStarterCount: 2, BenchPoints: 4},
+		{ZeroMinuteStarterCount: 0, BenchPoints: 8},
+		{ZeroMinuteStarterCount: 1, BenchPoints: 0},
+		{ZeroMinuteStarterCount: 11, MissingSnapshot: true}, // ignored
+	})
+	if got.GWs != 3 || got.GWsWithDeadStarter != 2 || got.ZeroMinuteStarters != 3 {
+		t.Fatalf("tendencies = %+v", got)
+	}
+	if !approxEqual(got.DeadStarterRate, 2.0/3) || !approxEqual(got.AvgZeroMinuteStarters, 1) || !approxEqual(got.AvgBenchPoints, 4) {
+		t.Errorf("rates = %+v", got)
+	}
+	if empty := aggregateLineupTendencies(nil); empty != (ScoutLineupTendencies{}) {
+		t.Errorf("no rows = %+v, want zero", empty)
+	}
+}
+
+func TestBuildOpponentScout(t *testing.T) {
+	dir, cfg := resourceCfg(t)
+	writeJSON(t, filepath.Join(dir, "bootstrap", "bootstrap-static.json"), map[string]any{
+		"elements": []any{
+			map[string]any{"id": 1, "web_name": "Salah", "team": 10, "element_type": 3, "status": "a"},
+			map[string]any{"id": 2, "web_name": "Haaland", "team": 11, "element_type": 4, "status": "a"},
+			map[string]any{"id": 3, "web_name": "Alexander-Arnold", "team": 10, "element_type": 2, "status": "i"},
+			map[string]any{"id": 4, "web_name": "Saka", "team": 12, "element_type": 3, "status": "a"},
+		},
+		"teams": []any{
+			map[string]any{"id": 10, "short_name": "LIV"},
+			map[string]any{"id": 11, "short_name": "MCI"},
+			map[string]any{"id": 12, "short_name": "ARS"},
+		},
+		// LIV blank GW4.
+		"fixtures": map[string]any{
+			"4": []any{map[string]any{"id": 40, "team_h": 11, "team_a": 12}},
+		},
+	})
+	writeFullGameJSON(t, dir, 3, true, 4, false, "")
+	writeDraftChoicesFixture(t, dir)
+	writeLeagueDetailsFixture(t, dir, 100, []any{
+		map[string]any{"id": 1, "entry_id": 200, "entry_name": "Alpha FC", "short_name": "AFC"},
+		map[string]any{"id": 2, "entry_id": 201, "entry_name": "Beta FC", "short_name": "BFC"},
+	}, []any{
+		map[string]any{"event": 3, "finished": true, "league_entry_1": 1, "league_entry_2": 2},
+		map[string]any{"event": 5, "league_entry_1": 2, "league_entry_2": 1},
+		map[string]any{"event": 4, "league_entry_1": 2, "league_entry_2": 1},
+	})
+	writeJSON(t, filepath.Join(dir, "league/100/transactions.json"), map[string]any{
+		"transactions": []any{
+			map[string]any{"id": 1, "entry": 201, "event": 1, "element_in": 4, "element_out": 0, "kind": "f", "result": "a"},
+			map[string]any{"id": 2, "entry": 201, "event": 4, "element_in": 4, "element_out": 0, "kind": "w", "result": "do"},
+			map[string]any{"id": 3, "entry": 201, "event": 3, "element_in": 4, "element_out": 0, "kind": "w", "result": "a"},
+		},
+	})
+	writeJSON(t, filepath.Join(dir, "league/100/trades.json"), map[string]any{"trades": []any{}})
+	writeJSON(t, filepath.Join(dir, "summary/player_form/100/h5.json"), summary.PlayerFormSummary{
+		Players: []summary.PlayerForm{
+			{Element: 1, Points: 30, PointsPerGW: 6, LastMinutes: []int{90, 90, 90}},
+			{Element: 4, Points: 12, PointsPerGW: 4, LastMinutes: []int{90, 0, 90}},
+		},
+	})
+	// GW3 summaries are missing and should be skipped.
+	for gw, total := range map[int]int{1: 40, 2: 60} {
+		writeJSON(t, filepath.Join(dir, "summary/matchup/100/gw", itoa(gw)+".json"), summary.MatchupSummary{
+			Matchups: []summary.MatchupBreakdown{
+				{EntryID: 200, Total: 99},
+				{EntryID: 201, Total: total, Points: summary.PositionPoints{GK: total / 10, DEF: total / 10, MID: total / 2, FWD: total - total/10*2 - total/2}},
+			},
+		})
+		writeJSON(t, filepath.Join(dir, "summary/lineup_efficiency/100/gw", itoa(gw)+".json"), summary.LineupEfficiencySummary{
+			Entries: []summary.LineupEfficiencyEntry{
+				{EntryID: 201, ZeroMinuteStarterCount: 2 - gw, BenchPoints: gw * 3},
+			},
+		})
+	}
+
+	entry := 200
+	out, err := buildOpponentScout(cfg, OpponentScoutArgs{LeagueID: 100, EntryID: &entry})
+	if err != nil {
+		t.Fatalf("buildOpponentScout: %v", err)
+	}
+	if out.OpponentEntryID != 201 || out.OpponentName != "Beta FC" || out.TargetGW != 4 || out.AsOfGW != 3 {
+		t.Fatalf("opponent=%d %q target=%d asOf=%d", out.OpponentEntryID, out.OpponentName, out.TargetGW, out.AsOfGW)
+	}
+
+	mids := out.Roster["MID"]
+	if len(mids) != 2 || mids[0].Name != "Salah" || mids[0].FormPoints != 30 || mids[1].Name != "Saka" {
+		t.Fatalf("MID = %+v", mids)
+	}
+	if !mids[0].Fixture.Blank || mids[1].Fixture.Fixtures != 1 || mids[1].Fixture.Opponents[0] != "MCI (A)" {
+		t.Errorf("fixtures = %+v / %+v", mids[0].Fixture, mids[1].Fixture)
+	}
+	if defs := out.Roster["DEF"]; len(defs) != 1 || len(defs[0].LastMinutes) != 0 || defs[0].LastMinutes == nil {
+		t.Errorf("DEF = %+v, want Alexander-Arnold with empty minutes", defs)
+	}
+	if len(out.Roster["FWD"]) != 0 {
+		t.Errorf("FWD = %+v, want none (Haaland is ours)", out.Roster["FWD"])
+	}
+
+	// Alexander-Arnold is injured and blanks; Salah blanks.
+	if len(out.Alerts) != 3 || out.Alerts[0].Element != 1 || out.Alerts[1].Issue != "injured" || out.Alerts[2].Issue != "blank in GW4" {
+		t.Errorf("alerts = %+v", out.Alerts)
+	}
+
+	s := out.Scoring
+	if s.GWs != 2 || !approxEqual(s.Average, 50) || !approxEqual(s.StdDev, 10) || s.High != 60 || s.Low != 40 {
+		t.Errorf("scoring = %+v", s)
+	}
+	if !approxEqual(s.PositionShare["MID"], 0.5) || !approxEqual(s.PositionShare["GK"], 0.1) {
+		t.Errorf("position share = %v", s.PositionShare)
+	}
+	if l := out.Lineup; l.GWs != 2 || l.GWsWithDeadStarter != 1 || !approxEqual(l.AvgBenchPoints, 4.5) {
+		t.Errorf("lineup tendencies = %+v", l)
+	}
+	if len(out.RecentMoves) != 1 || out.RecentMoves[0].GW != 3 || out.RecentMoves[0].InName != "Saka" {
+		t.Errorf("recent moves = %+v", out.RecentMoves)
+	}
+
+	t.Run("ExplicitOpponent", func(t *testing.T) {
+		opp := 200
+		out, err := buildOpponentScout(cfg, OpponentScoutArgs{LeagueID: 100, OpponentEntryID: &opp})
+		if err != nil {
+			t.Fatalf("buildOpponentScout: %v", err)
+		}
+		if out.OpponentName != "Alpha FC" || len(out.Roster["FWD"]) != 1 || out.Scoring.Average != 99 {
+			t.Errorf("scout of 200 = %+v", out)
+		}
+	})
+
+	t.Run("ByName", func(t *testing.T) {
+		name := "bfc"
+		out, err := buildOpponentScout(cfg, OpponentScoutArgs{LeagueID: 100, EntryName: &name})
+		if err != nil || out.OpponentEntryID != 200 {
+			t.Errorf("by name: err=%v opponent=%d, want 200", err, out.OpponentEntryID)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		if _, err := buildOpponentScout(cfg, OpponentScoutArgs{LeagueID: 100}); classifyError(err).Code != codeInvalidArgument {
+			t.Errorf("no entry: err = %v", err)
+		}
+		name := "nobody"
+		if _, err := buildOpponentScout(cfg, OpponentScoutArgs{LeagueID: 100, EntryName: &name}); classifyError(err).Code != codeNotFound {
+			t.Errorf("unknown name: err = %v", err)
+		}
+		missing := 999
+		if _, err := buildOpponentScout(cfg, OpponentScoutArgs{LeagueID: 100, OpponentEntryID: &missing}); classifyError(err).Code != codeNotFound {
+			t.Errorf("unknown opponent: err = %v", err)
+		}
+	})
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/clock"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/summary"
+)
+
+// OptimalStandingsArgs are the input arguments for the optimal_standings tool.
+type OptimalStandingsArgs struct {
+	LeagueID  int `json:"league_id" jsonschema:"Draft league id (required)"`
+	ThroughGW int `json:"through_gw,omitempty" jsonschema:"Last gameweek to include (default: latest finished)"`
+}
+
+// OptimalStandingsOutput is the output of the optimal_standings tool.
+type OptimalStandingsOutput struct {
+	summary.OptimalStandingsSummary
+	GWNote *GWNote `json:"gw_note,omitempty"`
+}
+
+func buildOptimalStandings(cfg ServerConfig, args OptimalStandingsArgs) (OptimalStandingsOutput, error) {
+	if args.LeagueID == 0 {
+		return OptimalStandingsOutput{}, invalidArgumentf("league_id is required")
+	}
+	gw, note, err := resolveEffectiveGW(cfg, args.ThroughGW, gwModeLatestFinished)
+	if err != nil {
+		return OptimalStandingsOutput{}, err
+	}
+	var out OptimalStandingsOutput
+	relPath := fmt.Sprintf("summary/optimal_standings/%d/through_gw/%d.json", args.LeagueID, gw)
+	if err := loadSummaryInto(cfg, args.LeagueID, gw, relPath, &out.OptimalStandingsSummary); err != nil {
+		return OptimalStandingsOutput{}, err
+	}
+	out.GWNote = note
+	return out, nil
+}
+
+// buildOptimalStandingsSummary derives any missing snapshots through gw and
+// then the optimal standings. A GW whose raw picks were never fetched is left
+// without snapshots; the summary scores those entry-GWs at their real total.
+func buildOptimalStandingsSummary(st *store.JSONStore, root string, leagueID int, gw int, clk clock.Clock) error {
+	ld, entryIDs, err := loadLeagueDetails(st, leagueID)
+	if err != nil {
+		return err
+	}
+	for g := ld.StartGW(); g <= gw; g++ {
+		for _, entryID := range entryIDs {
+			if err := ensureSnapshots(st, root, leagueID, []int{entryID}, g, g, clk); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return err
+			}
+		}
+	}
+	return summary.BuildOptimalStandings(st, root, leagueID, gw, clk)
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestBuildOptimalStandings(t *testing.T) {
+	dir, cfg := resourceCfg(t)
+	cfg.ComputeMissing = true
+	writeBootstrap(t, dir)
+	writeFullGameJSON(t, dir, 1, true, 2, false, "")
+	writeLeagueDetailsFixture(t, dir, 100, []any{
+		map[string]any{"id": 1, "entry_id": 200, "entry_name": "Alpha FC"},
+		map[string]any{"id": 2, "entry_id": 201, "entry_name": "Beta FC"},
+	}, []any{
+		map[string]any{"event": 1, "finished": true, "league_entry_1": 1, "league_entry_1_points": 10, "league_entry_2": 2, "league_entry_2_points": 12},
+	})
+	writeLiveJSON(t, dir, 1, map[string]any{
+		"1": map[string]any{"stats": map[string]any{"minutes": 90, "total_points": 8}},
+		"2": map[string]any{"stats": map[string]any{"minutes": 90, "total_points": 5}},
+	})
+	// Alpha benched Salah's 8; Beta's GW1 picks were never fetched.
+	writeJSON(t, filepath.Join(dir, "entry/200/gw/1.json"), map[string]any{
+		"picks": []any{
+			map[string]any{"element": 2, "position": 1},
+			map[string]any{"element": 1, "position": 12},
+		},
+	})
+
+	out, err := buildOptimalStandings(cfg, OptimalStandingsArgs{LeagueID: 100})
+	if err != nil {
+		t.Fatalf("buildOptimalStandings: %v", err)
+	}
+	if out.ThroughGW != 1 || out.MissingSnapshots != 1 {
+		t.Errorf("through_gw=%d missing=%d, want 1/1", out.ThroughGW, out.MissingSnapshots)
+	}
+	if len(out.Flipped) != 1 || out.Flipped[0].EntryID != 200 || out.Flipped[0].OptimalPoints != 13 {
+		t.Fatalf("Flipped = %+v, want Alpha's 10-12 loss as a 13-12 win", out.Flipped)
+	}
+	if len(out.Actual) != 2 || out.Actual[0].EntryID != 201 || out.Optimal[0].EntryID != 200 {
+		t.Errorf("leaders actual=%+v optimal=%+v, want Beta then Alpha", out.Actual, out.Optimal)
+	}
+}
+
+func TestBuildOptimalStandings_Errors(t *testing.T) {
+	_, cfg := resourceCfg(t)
+	if _, err := buildOptimalStandings(cfg, OptimalStandingsArgs{}); classifyError(err).Code != codeInvalidArgument {
+		t.Errorf("missing league_id: err = %v, want invalid argument", err)
+	}
+	if _, err := buildOptimalStandings(cfg, OptimalStandingsArgs{LeagueID: 100, ThroughGW: 3}); classifyError(err).Code != codeDataMissing {
+		t.Errorf("no data: err = %v, want data missing", err)
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+)
+
+// cursorHashLen is how many hex digits of the source hash a cursor keeps;
+// enough to notice a regenerated file without bloating the token.
+const cursorHashLen = 16
+
+// PageArgs are a large-output tool's page_size and cursor arguments. The
+// first call sets page_size; each later call passes back the previous page's
+// next_cursor (page_size may be left out then).
+type PageArgs struct {
+	PageSize *int
+	Cursor   string
+}
+
+// paged reports whether the caller asked for pages at all.
+func (a PageArgs) paged() bool {
+	return a.PageSize != nil || a.Cursor != ""
+}
+
+// PageInfo describes one page. NextCursor is empty on the last page.
+// Restart is set when the cursor was issued against data that has since
+// been regenerated: the page is then the first one of the new data, and the
+// caller should discard what it already has.
+type PageInfo struct {
+	PageSize   int    `json:"page_size"`
+	Offset     int    `json:"offset"`
+	Total      int    `json:"total"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	Restart    bool   `json:"restart,omitempty"`
+}
+
+// pageCursor is the decoded form of a cursor: the query it belongs to (the
+// sort order and filters), where the next page starts, the page size and a
+// prefix of the hash of the data it was cut from.
+type pageCursor struct {
+	Key      string `json:"k"`
+	Offset   int    `json:"o"`
+	PageSize int    `json:"n"`
+	Hash     string `json:"h"`
+}
+
+func encodeCursor(c pageCursor) string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeCursor(s string) (pageCursor, error) {
+	var c pageCursor
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || json.Unmarshal(b, &c) != nil || c.Offset < 0 || c.PageSize < 1 {
+		return pageCursor{}, invalidArgumentf("cursor is not one this tool issued")
+	}
+	return c, nil
+}
+
+// hashBytes is the hex sha256 of b, for sources that aren't read through
+// loadSummaryFileWithMeta.
+func hashBytes(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// paginate cuts one page from items, which must already be in their final
+// order. key names that order and any filters, so a cursor can't be replayed
+// against a different query; source is the hash of the data the items came
+// from. A cursor cut from other data restarts at the first page.
+func paginate[T any](items []T, args PageArgs, key string, source string) ([]T, PageInfo, error) {
+	if len(source) > cursorHashLen {
+		source = source[:cursorHashLen]
+	}
+	info := PageInfo{Total: len(items)}
+	if args.Cursor != "" {
+		c, err := decodeCursor(args.Cursor)
+		if err != nil {
+			return nil, PageInfo{}, err
+		}
+		if c.Key != key {
+			return nil, PageInfo{}, invalidArgumentf("cursor was issued for a different query; repeat the first call's filters and sort")
+		}
+		info.PageSize = c.PageSize
+		if c.Hash == source {
+			info.Offset = c.Offset
+		} else {
+			info.Restart = true
+		}
+	}
+	if args.PageSize != nil {
+		if *args.PageSize < 1 {
+			return nil, PageInfo{}, invalidArgumentf("page_size must be at least 1, got %d", *args.PageSize)
+		}
+		info.PageSize = *args.PageSize
+	}
+	start := min(info.Offset, len(items))
+	end := min(start+info.PageSize, len(items))
+	if end < len(items) {
+		info.NextCursor = encodeCursor(pageCursor{Key: key, Offset: end, PageSize: info.PageSize, Hash: source})
+	}
+	return items[start:end], info, nil
+}
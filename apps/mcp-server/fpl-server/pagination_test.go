@@ -0,0 +1,89 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestPaginate_WalksEveryPage(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5, 6, 7}
+	size := 3
+	page := PageArgs{PageSize: &size}
+	var got []int
+	for i := 0; i < 10; i++ {
+		part, info, err := paginate(items, page, "k", "abc")
+		if err != nil {
+			t.Fatalf("page %d: %v", i, err)
+		}
+		if info.Total != 7 || info.PageSize != 3 || info.Offset != len(got) || info.Restart {
+			t.Errorf("page %d info = %+v", i, info)
+		}
+		got = append(got, part...)
+		if info.NextCursor == "" {
+			break
+		}
+		// Later pages need only the cursor.
+		page = PageArgs{Cursor: info.NextCursor}
+	}
+	if !slices.Equal(got, []int{1, 2, 3, 4, 5, 6, 7}) {
+		t.Errorf("pages joined = %v", got)
+	}
+}
+
+func TestPaginate_CursorRoundTrip(t *testing.T) {
+	c := pageCursor{Key: "player_form|100|3", Offset: 40, PageSize: 20, Hash: "0123456789abcdef"}
+	got, err := decodeCursor(encodeCursor(c))
+	if err != nil || got != c {
+		t.Errorf("round trip = %+v, %v; want %+v", got, err, c)
+	}
+	for _, bad := range []string{"not base64!", "e30", encodeCursor(pageCursor{Key: "k", Offset: -1, PageSize: 5})} {
+		if _, _, err := paginate([]int{1}, PageArgs{Cursor: bad}, "k", ""); classifyError(err).Code != codeInvalidArgument {
+			t.Errorf("cursor %q: err = %v, want invalid_argument", bad, err)
+		}
+	}
+}
+
+func TestPaginate_LastPage(t *testing.T) {
+	size := 5
+	items, info, err := paginate([]string{"a", "b"}, PageArgs{PageSize: &size}, "k", "h")
+	if err != nil || len(items) != 2 || info.NextCursor != "" || info.Total != 2 {
+		t.Errorf("short list = %v %+v %v, want everything on one page and no cursor", items, info, err)
+	}
+	// Exactly a page's worth has no next page either.
+	size = 2
+	if _, info, _ := paginate([]string{"a", "b"}, PageArgs{PageSize: &size}, "k", "h"); info.NextCursor != "" {
+		t.Errorf("full page = %+v, want no next cursor", info)
+	}
+	items, info, err = paginate([]string{}, PageArgs{PageSize: &size}, "k", "h")
+	if err != nil || len(items) != 0 || info.NextCursor != "" {
+		t.Errorf("empty list = %v %+v %v", items, info, err)
+	}
+	zero := 0
+	if _, _, err := paginate([]string{"a"}, PageArgs{PageSize: &zero}, "k", "h"); classifyError(err).Code != codeInvalidArgument {
+		t.Errorf("page_size 0: err = %v, want invalid_argument", err)
+	}
+}
+
+func TestPaginate_StaleCursorRestarts(t *testing.T) {
+	size := 2
+	_, first, _ := paginate([]int{1, 2, 3, 4}, PageArgs{PageSize: &size}, "k", "old-hash")
+
+	// The data was regenerated: the cursor's offset means nothing now.
+	items, info, err := paginate([]int{9, 8, 7, 6}, PageArgs{Cursor: first.NextCursor}, "k", "new-hash")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.Restart || info.Offset != 0 || !slices.Equal(items, []int{9, 8}) || info.NextCursor == "" {
+		t.Errorf("stale cursor = %v %+v, want a restart from the first page", items, info)
+	}
+	// The restarted page's cursor belongs to the new data.
+	items, info, _ = paginate([]int{9, 8, 7, 6}, PageArgs{Cursor: info.NextCursor}, "k", "new-hash")
+	if info.Restart || !slices.Equal(items, []int{7, 6}) {
+		t.Errorf("after restart = %v %+v", items, info)
+	}
+
+	// A cursor from another query is refused outright.
+	if _, _, err := paginate([]int{1, 2, 3, 4}, PageArgs{Cursor: first.NextCursor}, "other", "old-hash"); classifyError(err).Code != codeInvalidArgument {
+		t.Errorf("other query: err = %v, want invalid_argument", err)
+	}
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+const (
+	// consistencyMinGWs is the fewest GWs needed before a profile is labelled.
+	consistencyMinGWs = 3
+	// boomBustCV and steadyCV bound the coefficient of variation for the
+	// "boom_bust" and "steady" labels; anything between is "balanced".
+	boomBustCV = 1.0
+	steadyCV   = 0.5
+	// consistencyInsufficient labels a series too short to profile.
+	consistencyInsufficient = "insufficient_data"
+)
+
+// PlayerConsistencyArgs are the input arguments for the player_consistency tool.
+type PlayerConsistencyArgs struct {
+	ElementID  *int    `json:"element_id,omitempty" jsonschema:"Player element id"`
+	PlayerName *string `json:"player_name,omitempty" jsonschema:"Player name (if element_id not provided)"`
+	Horizon    *int    `json:"horizon,omitempty" jsonschema:"GWs to look back over (default 10)"`
+	Threshold  *int    `json:"threshold,omitempty" jsonschema:"Points counted as a good GW (default 5)"`
+	GW         *int    `json:"gw,omitempty" jsonschema:"Last gameweek to include (0 = latest with results)"`
+}
+
+// ConsistencyGW is one gameweek in the points series.
+type ConsistencyGW struct {
+	Gameweek int `json:"gameweek"`
+	Minutes  int `json:"minutes"`
+	Points   int `json:"points"`
+}
+
+// ConsistencyProfile summarises the spread of a points series. Floor and
+// Ceiling are the 10th and 90th percentiles.
+type ConsistencyProfile struct {
+	GWs               int     `json:"gws"`
+	Mean              float64 `json:"mean"`
+	StdDev            float64 `json:"stddev"`
+	CV                float64 `json:"cv"`
+	Floor             float64 `json:"floor"`
+	Ceiling           float64 `json:"ceiling"`
+	AboveThresholdPct float64 `json:"above_threshold_pct"`
+	Label             string  `json:"label"`
+}
+
+// PlayerConsistencyOutput is the output of the player_consistency tool.
+type PlayerConsistencyOutput struct {
+	ElementID    int                `json:"element_id"`
+	PlayerName   string             `json:"player_name"`
+	Team         string             `json:"team"`
+	PositionType int                `json:"position_type"`
+	FromGW       int                `json:"from_gw"`
+	ThroughGW    int                `json:"through_gw"`
+	Horizon      int                `json:"horizon"`
+	Threshold    int                `json:"threshold"`
+	Series       []ConsistencyGW    `json:"series"`
+	All          ConsistencyProfile `json:"all"`
+	// Played60 only counts GWs with 60+ minutes, so injury or bench absences
+	// don't show up as busts.
+	Played60 ConsistencyProfile `json:"played_60"`
+	GWNote   *GWNote            `json:"gw_note,omitempty"`
+	// ColdStart is set when the series ends before 3 GWs have been played.
+	ColdStart bool     `json:"cold_start,omitempty"`
+	Notes     []string `json:"notes,omitempty"`
+}
+
+// percentile interpolates linearly between the closest ranks of sorted.
+func percentile(sorted []int, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	pos := p * float64(len(sorted)-1)
+	lo := int(math.Floor(pos))
+	hi := int(math.Ceil(pos))
+	frac := pos - float64(lo)
+	return float64(sorted[lo]) + frac*float64(sorted[hi]-sorted[lo])
+}
+
+// consistencyProfile computes the spread metrics for a points series. The
+// standard deviation is the population one, as in computeConsistencyStats.
+func consistencyProfile(pts []int, threshold int) ConsistencyProfile {
+	prof := ConsistencyProfile{GWs: len(pts)}
+	if len(pts) == 0 {
+		prof.Label = consistencyInsufficient
+		return prof
+	}
+	sum, sumSq, above := 0.0, 0.0, 0
+	for _, p := range pts {
+		sum += float64(p)
+		sumSq += float64(p) * float64(p)
+		if p >= threshold {
+			above++
+		}
+	}
+	n := float64(len(pts))
+	prof.Mean = sum / n
+	variance := sumSq/n - prof.Mean*prof.Mean
+	if variance < 0 {
+		variance = 0
+	}
+	prof.StdDev = math.Sqrt(variance)
+	if prof.Mean > 0 {
+		prof.CV = prof.StdDev / prof.Mean
+	}
+	sorted := append([]int(nil), pts...)
+	sort.Ints(sorted)
+	prof.Floor = percentile(sorted, 0.10)
+	prof.Ceiling = percentile(sorted, 0.90)
+	prof.AboveThresholdPct = 100 * float64(above) / n
+
+	switch {
+	case len(pts) < consistencyMinGWs:
+		prof.Label = consistencyInsufficient
+	case prof.Mean <= 0 || prof.CV >= boomBustCV:
+		prof.Label = "boom_bust"
+	case prof.CV <= steadyCV:
+		prof.Label = "steady"
+	default:
+		prof.Label = "balanced"
+	}
+	return prof
+}
+
+func buildPlayerConsistency(cfg ServerConfig, args PlayerConsistencyArgs) (PlayerConsistencyOutput, error) {
+	elements, teamShort, _, err := loadBootstrapData(cfg.RawRoot)
+	if err != nil {
+		return PlayerConsistencyOutput{}, err
+	}
+	meta, err := resolvePlayer(elements, args.ElementID, args.PlayerName)
+	if err != nil {
+		return PlayerConsistencyOutput{}, err
+	}
+
+	horizon := 10
+	if args.Horizon != nil && *args.Horizon > 0 {
+		horizon = *args.Horizon
+	}
+	threshold := 5
+	if args.Threshold != nil {
+		threshold = *args.Threshold
+	}
+	requested := 0
+	if args.GW != nil {
+		requested = *args.GW
+	}
+	throughGW, note, err := resolveEffectiveGW(cfg, requested, gwModeLatestFinished)
+	if err != nil {
+		return PlayerConsistencyOutput{}, err
+	}
+	fromGW := throughGW - horizon + 1
+	if fromGW < 1 {
+		fromGW = 1
+	}
+
+	out := PlayerConsistencyOutput{
+		ElementID:    meta.ID,
+		PlayerName:   meta.Name,
+		Team:         teamShort[meta.TeamID],
+		PositionType: meta.PositionType,
+		FromGW:       fromGW,
+		ThroughGW:    throughGW,
+		Horizon:      horizon,
+		Threshold:    threshold,
+		Series:       make([]ConsistencyGW, 0, horizon),
+		GWNote:       note,
+	}
+	all := make([]int, 0, horizon)
+	played := make([]int, 0, horizon)
+	for gw := fromGW; gw <= throughGW; gw++ {
+		live, err := loadLiveStats(cfg.RawRoot, gw)
+		if err != nil {
+			continue
+		}
+		// As in computeConsistencyStats, a GW without a live entry for the
+		// player is skipped rather than counted as a zero.
+		s, ok := live[meta.ID]
+		if !ok {
+			continue
+		}
+		out.Series = append(out.Series, ConsistencyGW{Gameweek: gw, Minutes: s.Minutes, Points: s.TotalPoints})
+		all = append(all, s.TotalPoints)
+		if s.Minutes >= 60 {
+			played = append(played, s.TotalPoints)
+		}
+	}
+	out.All = consistencyProfile(all, threshold)
+	out.Played60 = consistencyProfile(played, threshold)
+	if coldStart(throughGW) {
+		out.ColdStart = true
+		out.Notes = append(out.Notes, fmt.Sprintf("Cold start: only %d GW(s) of results, so stddev, cv, floor and ceiling describe too few points to mean anything; both profiles are labelled %s.", len(out.Series), consistencyInsufficient))
+	}
+	return out, nil
+}
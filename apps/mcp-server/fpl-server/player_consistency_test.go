@@ -0,0 +1,84 @@
+package main
+
+import "testing"
+
+func TestConsistencyProfile(t *testing.T) {
+	t.Run("Steady", func(t *testing.T) {
+		p := consistencyProfile([]int{6, 5, 7, 6, 6}, 5)
+		if p.Label != "steady" || !approxEqual(p.Mean, 6) || !approxEqual(p.AboveThresholdPct, 100) {
+			t.Errorf("profile = %+v, want steady mean 6, 100%% above", p)
+		}
+	})
+
+	t.Run("BoomBust", func(t *testing.T) {
+		// 1,1,2,2,14: mean 4, stddev 5.02, CV 1.25.
+		p := consistencyProfile([]int{2, 14, 1, 2, 1}, 5)
+		if p.Label != "boom_bust" || !approxEqual(p.AboveThresholdPct, 20) {
+			t.Errorf("profile = %+v, want boom_bust with 20%% above", p)
+		}
+		// Floor: 10th percentile of 1,1,2,2,14 sits at rank 0.4 -> 1.
+		// Ceiling: 90th at rank 3.6 -> 2 + 0.6*12 = 9.2.
+		if !approxEqual(p.Floor, 1) || !approxEqual(p.Ceiling, 9.2) {
+			t.Errorf("floor/ceiling = %v/%v, want 1/9.2", p.Floor, p.Ceiling)
+		}
+	})
+
+	t.Run("TooFewGWs", func(t *testing.T) {
+		if p := consistencyProfile([]int{10, 2}, 5); p.Label != "insufficient_data" || p.GWs != 2 {
+			t.Errorf("profile = %+v, want insufficient_data", p)
+		}
+		if p := consistencyProfile(nil, 5); p.Label != "insufficient_data" || p.Mean != 0 {
+			t.Errorf("empty profile = %+v", p)
+		}
+	})
+}
+
+func TestBuildPlayerConsistency(t *testing.T) {
+	dir, cfg := tmpCfg(t)
+	writeBootstrap(t, dir)
+	writeFullGameJSON(t, dir, 6, true, 7, false, "")
+	// Salah: GW3 missing from live data, GW5 a two-minute cameo.
+	series := map[int][2]int{1: {90, 8}, 2: {90, 6}, 4: {85, 12}, 5: {2, 1}, 6: {90, 7}}
+	for gw := 1; gw <= 6; gw++ {
+		elements := map[string]any{}
+		if s, ok := series[gw]; ok {
+			elements["1"] = map[string]any{"stats": map[string]any{"minutes": s[0], "total_points": s[1]}}
+		}
+		writeLiveJSON(t, dir, gw, elements)
+	}
+
+	name := "salah"
+	out, err := buildPlayerConsistency(cfg, PlayerConsistencyArgs{PlayerName: &name})
+	if err != nil {
+		t.Fatalf("buildPlayerConsistency: %v", err)
+	}
+	if out.ElementID != 1 || out.FromGW != 1 || out.ThroughGW != 6 || out.Horizon != 10 || out.Threshold != 5 {
+		t.Errorf("header = %+v", out)
+	}
+	if len(out.Series) != 5 || out.All.GWs != 5 {
+		t.Errorf("series = %+v, want 5 GWs (GW3 skipped)", out.Series)
+	}
+	if !approxEqual(out.All.AboveThresholdPct, 80) {
+		t.Errorf("all above threshold = %v, want 80", out.All.AboveThresholdPct)
+	}
+	if out.Played60.GWs != 4 || !approxEqual(out.Played60.AboveThresholdPct, 100) || !approxEqual(out.Played60.Mean, 8.25) {
+		t.Errorf("played_60 = %+v, want 4 GWs averaging 8.25, all above threshold", out.Played60)
+	}
+
+	horizon, gw := 2, 6
+	out, err = buildPlayerConsistency(cfg, PlayerConsistencyArgs{PlayerName: &name, Horizon: &horizon, GW: &gw})
+	if err != nil {
+		t.Fatalf("buildPlayerConsistency: %v", err)
+	}
+	if out.FromGW != 5 || len(out.Series) != 2 || out.All.Label != "insufficient_data" {
+		t.Errorf("horizon 2: from=%d series=%d label=%s", out.FromGW, len(out.Series), out.All.Label)
+	}
+
+	if _, err := buildPlayerConsistency(cfg, PlayerConsistencyArgs{}); classifyError(err).Code != codeInvalidArgument {
+		t.Errorf("no player: err = %v, want INVALID_ARGUMENT", err)
+	}
+	missing := 999
+	if _, err := buildPlayerConsistency(cfg, PlayerConsistencyArgs{ElementID: &missing}); classifyError(err).Code != codeNotFound {
+		t.Errorf("unknown element: err = %v, want NOT_FOUND", err)
+	}
+}
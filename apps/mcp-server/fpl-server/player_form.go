@@ -0,0 +1,212 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/summary"
+)
+
+// defaultPlayerFormLimit caps player_form output; the unfiltered summary has
+// every player in the game.
+const defaultPlayerFormLimit = 50
+
+// player_form owned filters.
+const (
+	ownedAny     = "any"
+	ownedOwned   = "owned"
+	ownedUnowned = "unowned"
+	ownedMine    = "mine"
+)
+
+// player_form sort_by keys.
+const (
+	sortPointsGW  = "points_per_gw"
+	sortMinutesGW = "minutes_per_gw"
+	sortOwnership = "ownership_pct"
+	sortRisk      = "risk_score"
+)
+
+type PlayerFormArgs struct {
+	LeagueID     int     `json:"league_id" jsonschema:"Draft league id (required)"`
+	Horizon      int     `json:"horizon" jsonschema:"Rolling horizon in GWs (default 5)"`
+	AsOfGW       int     `json:"as_of_gw" jsonschema:"As-of gameweek (0 = current)"`
+	PositionType *int    `json:"position_type,omitempty" jsonschema:"Only this position: 1=GK 2=DEF 3=MID 4=FWD"`
+	Team         *string `json:"team,omitempty" jsonschema:"Only this Premier League team (short name, e.g. ARS)"`
+	Owned        *string `json:"owned,omitempty" jsonschema:"Ownership filter: any|owned|unowned|mine (default any; mine needs entry_id or entry_name)"`
+	EntryID      *int    `json:"entry_id,omitempty" jsonschema:"Entry id for owned=mine"`
+	EntryName    *string `json:"entry_name,omitempty" jsonschema:"Entry name for owned=mine (if entry_id not provided)"`
+	MinMinutes   *int    `json:"min_minutes,omitempty" jsonschema:"Minimum minutes over the horizon"`
+	SortBy       *string `json:"sort_by,omitempty" jsonschema:"points_per_gw|minutes_per_gw|ownership_pct|risk_score (default points_per_gw; risk_score sorts lowest first)"`
+	Limit        *int    `json:"limit,omitempty" jsonschema:"Maximum players returned (default 50)"`
+	PageSize     *int    `json:"page_size,omitempty" jsonschema:"Players per page; pages through every match instead of stopping at limit"`
+	Cursor       string  `json:"cursor,omitempty" jsonschema:"next_cursor from the previous page"`
+	Format       string  `json:"format,omitempty" jsonschema:"json|markdown|csv (default json)"`
+}
+
+// PlayerFormOutput is the player_form summary narrowed to the players that
+// pass the filters. Matched counts them before Limit is applied. Page is
+// set instead of applying Limit when the caller pages.
+type PlayerFormOutput struct {
+	summary.PlayerFormSummary
+	SortBy  string    `json:"sort_by"`
+	Matched int       `json:"matched"`
+	Page    *PageInfo `json:"page,omitempty"`
+	GWNote  *GWNote   `json:"gw_note,omitempty"`
+}
+
+func buildPlayerForm(cfg ServerConfig, args PlayerFormArgs) (PlayerFormOutput, error) {
+	if args.LeagueID == 0 {
+		return PlayerFormOutput{}, invalidArgumentf("league_id is required")
+	}
+	if args.PositionType != nil && (*args.PositionType < 1 || *args.PositionType > 4) {
+		return PlayerFormOutput{}, invalidArgumentf("position_type must be 1-4, got %d", *args.PositionType)
+	}
+	owned := ownedAny
+	if args.Owned != nil && strings.TrimSpace(*args.Owned) != "" {
+		owned = strings.ToLower(strings.TrimSpace(*args.Owned))
+	}
+	switch owned {
+	case ownedAny, ownedOwned, ownedUnowned, ownedMine:
+	default:
+		return PlayerFormOutput{}, invalidArgumentf("owned must be any, owned, unowned or mine, got %q", owned)
+	}
+	sortBy := sortPointsGW
+	if args.SortBy != nil && strings.TrimSpace(*args.SortBy) != "" {
+		sortBy = strings.ToLower(strings.TrimSpace(*args.SortBy))
+	}
+	switch sortBy {
+	case sortPointsGW, sortMinutesGW, sortOwnership, sortRisk:
+	default:
+		return PlayerFormOutput{}, invalidArgumentf("sort_by must be points_per_gw, minutes_per_gw, ownership_pct or risk_score, got %q", sortBy)
+	}
+	limit := defaultPlayerFormLimit
+	if args.Limit != nil && *args.Limit > 0 {
+		limit = *args.Limit
+	}
+	h := args.Horizon
+	if h <= 0 {
+		h = 5
+	}
+
+	gw, note, err := resolveEffectiveGW(cfg, args.AsOfGW, gwModeLatestFinished)
+	if err != nil {
+		return PlayerFormOutput{}, err
+	}
+	form, hash, err := loadPlayerFormSummaryWithHash(cfg, args.LeagueID, gw, h)
+	if err != nil {
+		return PlayerFormOutput{}, err
+	}
+
+	// "mine" is resolved against the ownership replay at the summary's GW,
+	// the same point the summary's ownership counts come from.
+	var mine map[int]bool
+	mineEntry := 0
+	if owned == ownedMine {
+		entryID, err := resolvePlayerFormEntry(cfg, args)
+		if err != nil {
+			return PlayerFormOutput{}, err
+		}
+		ownership, err := loadOwnershipAtGW(cfg, args.LeagueID, gw)
+		if err != nil {
+			return PlayerFormOutput{}, err
+		}
+		var ok bool
+		if mine, ok = ownership[entryID]; !ok {
+			return PlayerFormOutput{}, notFoundf("entry %d not found in league %d", entryID, args.LeagueID)
+		}
+		mineEntry = entryID
+	}
+	team := ""
+	if args.Team != nil {
+		team = strings.TrimSpace(*args.Team)
+	}
+
+	players := make([]summary.PlayerForm, 0, len(form.Players))
+	for _, p := range form.Players {
+		if args.PositionType != nil && p.PositionType != *args.PositionType {
+			continue
+		}
+		if team != "" && !strings.EqualFold(p.Team, team) {
+			continue
+		}
+		if args.MinMinutes != nil && p.Minutes < *args.MinMinutes {
+			continue
+		}
+		switch owned {
+		case ownedOwned:
+			if p.Ownership == 0 {
+				continue
+			}
+		case ownedUnowned:
+			if p.Ownership > 0 {
+				continue
+			}
+		case ownedMine:
+			if !mine[p.Element] {
+				continue
+			}
+		}
+		players = append(players, p)
+	}
+	sort.SliceStable(players, func(i, j int) bool {
+		a, b := players[i], players[j]
+		switch sortBy {
+		case sortMinutesGW:
+			if a.MinutesPerGW != b.MinutesPerGW {
+				return a.MinutesPerGW > b.MinutesPerGW
+			}
+		case sortOwnership:
+			if a.OwnershipPct != b.OwnershipPct {
+				return a.OwnershipPct > b.OwnershipPct
+			}
+		case sortRisk:
+			if a.RiskScore != b.RiskScore {
+				return a.RiskScore < b.RiskScore
+			}
+		}
+		if a.PointsPerGW != b.PointsPerGW {
+			return a.PointsPerGW > b.PointsPerGW
+		}
+		return a.Element < b.Element
+	})
+
+	out := PlayerFormOutput{PlayerFormSummary: form, SortBy: sortBy, Matched: len(players), GWNote: note}
+	if page := (PageArgs{PageSize: args.PageSize, Cursor: args.Cursor}); page.paged() {
+		position, minMinutes := 0, 0
+		if args.PositionType != nil {
+			position = *args.PositionType
+		}
+		if args.MinMinutes != nil {
+			minMinutes = *args.MinMinutes
+		}
+		key := fmt.Sprintf("player_form|%d|%d|%d|%d|%s|%s|%d|%d|%s", args.LeagueID, gw, h, position, strings.ToUpper(team), owned, mineEntry, minMinutes, sortBy)
+		players, info, err := paginate(players, page, key, hash)
+		if err != nil {
+			return PlayerFormOutput{}, err
+		}
+		out.Players, out.Page = players, &info
+		return out, nil
+	}
+	if len(players) > limit {
+		players = players[:limit]
+	}
+	out.Players = players
+	return out, nil
+}
+
+func resolvePlayerFormEntry(cfg ServerConfig, args PlayerFormArgs) (int, error) {
+	if args.EntryID != nil && *args.EntryID != 0 {
+		return *args.EntryID, nil
+	}
+	if args.EntryName == nil || strings.TrimSpace(*args.EntryName) == "" {
+		return 0, invalidArgumentf("owned=mine requires entry_id or entry_name")
+	}
+	ld, _, err := loadLeagueDetails(store.NewJSONStore(cfg.RawRoot), args.LeagueID)
+	if err != nil {
+		return 0, err
+	}
+	return resolveEntry(ld.LeagueEntries, *args.EntryName)
+}
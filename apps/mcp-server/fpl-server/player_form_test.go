@@ -0,0 +1,143 @@
+package main
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/summary"
+)
+
+// writePlayerFormFixture adds an h5 player_form summary to writeClaimFixture's
+// league, where Alpha (200) owns 1, 2, 3 and 6-8, Beta 4 and Gamma 5.
+func writePlayerFormFixture(t *testing.T, dir string) {
+	t.Helper()
+	writeClaimFixture(t, dir)
+	p := func(id int, team string, pos int, minutes int, ppg float64, own int, risk float64) summary.PlayerForm {
+		return summary.PlayerForm{Element: id, Team: team, PositionType: pos, Minutes: minutes, PointsPerGW: ppg, MinutesPerGW: float64(minutes) / 5, Ownership: own, OwnershipPct: float64(own) / 3, RiskScore: risk}
+	}
+	writeJSON(t, filepath.Join(dir, "summary/player_form/100/h5.json"), summary.PlayerFormSummary{
+		LeagueID: 100, AsOfGW: 3, Horizon: 5,
+		Players: []summary.PlayerForm{
+			p(1, "LIV", 3, 450, 8, 1, 0),
+			p(2, "CHE", 3, 400, 6, 1, 0.1),
+			p(3, "ARS", 2, 450, 4, 1, 0),
+			p(4, "ARS", 3, 300, 7, 1, 0.3),
+			p(6, "MCI", 4, 200, 5, 1, 0.5),
+			p(10, "LIV", 3, 90, 3, 0, 0.8),
+			p(11, "ARS", 3, 350, 2, 0, 0.2),
+			p(13, "ARS", 2, 450, 3.5, 0, 0),
+		},
+	})
+}
+
+func formElements(out PlayerFormOutput) []int {
+	ids := make([]int, 0, len(out.Players))
+	for _, p := range out.Players {
+		ids = append(ids, p.Element)
+	}
+	return ids
+}
+
+func TestBuildPlayerForm_TeamAndPosition(t *testing.T) {
+	dir, cfg := resourceCfg(t)
+	writePlayerFormFixture(t, dir)
+	mid := 3
+	team := "ars"
+	out, err := buildPlayerForm(cfg, PlayerFormArgs{LeagueID: 100, PositionType: &mid, Team: &team})
+	if err != nil {
+		t.Fatalf("buildPlayerForm: %v", err)
+	}
+	if got := formElements(out); len(got) != 2 || got[0] != 4 || got[1] != 11 || out.Matched != 2 {
+		t.Errorf("ARS midfielders = %v (matched %d), want [4 11]", got, out.Matched)
+	}
+
+	unowned := "unowned"
+	minutes := 300
+	sortBy := "risk_score"
+	out, err = buildPlayerForm(cfg, PlayerFormArgs{LeagueID: 100, Owned: &unowned, MinMinutes: &minutes, SortBy: &sortBy})
+	if err != nil {
+		t.Fatalf("buildPlayerForm: %v", err)
+	}
+	if got := formElements(out); len(got) != 2 || got[0] != 13 || got[1] != 11 {
+		t.Errorf("unowned 300+ minutes by risk = %v, want [13 11]", got)
+	}
+
+	limit := 3
+	out, _ = buildPlayerForm(cfg, PlayerFormArgs{LeagueID: 100, Limit: &limit})
+	if got := formElements(out); len(got) != 3 || got[0] != 1 || got[1] != 4 || out.Matched != 8 {
+		t.Errorf("limit 3 = %v (matched %d), want [1 4 2] of 8", got, out.Matched)
+	}
+}
+
+func TestBuildPlayerForm_Mine(t *testing.T) {
+	dir, cfg := resourceCfg(t)
+	writePlayerFormFixture(t, dir)
+	mine := "mine"
+	name := "Alpha FC"
+	out, err := buildPlayerForm(cfg, PlayerFormArgs{LeagueID: 100, Owned: &mine, EntryName: &name})
+	if err != nil {
+		t.Fatalf("buildPlayerForm: %v", err)
+	}
+	// Saka (4) is owned, but by Beta.
+	if got := formElements(out); len(got) != 4 || got[0] != 1 || got[1] != 2 || got[2] != 6 || got[3] != 3 {
+		t.Errorf("mine = %v, want [1 2 6 3]", got)
+	}
+
+	if _, err := buildPlayerForm(cfg, PlayerFormArgs{LeagueID: 100, Owned: &mine}); classifyError(err).Code != codeInvalidArgument {
+		t.Errorf("mine without entry: err = %v", err)
+	}
+	other := 999
+	if _, err := buildPlayerForm(cfg, PlayerFormArgs{LeagueID: 100, Owned: &mine, EntryID: &other}); classifyError(err).Code != codeNotFound {
+		t.Errorf("mine for unknown entry: err = %v", err)
+	}
+	bad := "popular"
+	if _, err := buildPlayerForm(cfg, PlayerFormArgs{LeagueID: 100, SortBy: &bad}); classifyError(err).Code != codeInvalidArgument {
+		t.Errorf("bad sort_by: err = %v", err)
+	}
+}
+
+func TestBuildPlayerForm_Pages(t *testing.T) {
+	dir, cfg := resourceCfg(t)
+	writePlayerFormFixture(t, dir)
+	// Paging ignores limit and walks every match.
+	size, limit := 3, 2
+	args := PlayerFormArgs{LeagueID: 100, PageSize: &size, Limit: &limit}
+	var got []int
+	for {
+		out, err := buildPlayerForm(cfg, args)
+		if err != nil {
+			t.Fatalf("buildPlayerForm: %v", err)
+		}
+		if out.Page == nil || out.Page.Total != 8 || out.Matched != 8 {
+			t.Fatalf("page = %+v (matched %d), want 8 in total", out.Page, out.Matched)
+		}
+		got = append(got, formElements(out)...)
+		if out.Page.NextCursor == "" {
+			break
+		}
+		args = PlayerFormArgs{LeagueID: 100, Cursor: out.Page.NextCursor}
+	}
+	if want := []int{1, 4, 2, 6, 3, 13, 10, 11}; !slices.Equal(got, want) {
+		t.Errorf("all pages = %v, want %v", got, want)
+	}
+
+	first, _ := buildPlayerForm(cfg, PlayerFormArgs{LeagueID: 100, PageSize: &size})
+	// Another sort order can't reuse the cursor.
+	sortBy := "risk_score"
+	if _, err := buildPlayerForm(cfg, PlayerFormArgs{LeagueID: 100, Cursor: first.Page.NextCursor, SortBy: &sortBy}); classifyError(err).Code != codeInvalidArgument {
+		t.Errorf("cursor with another sort: err = %v", err)
+	}
+	// The summary is rebuilt between calls: start over.
+	writeJSON(t, filepath.Join(dir, "summary/player_form/100/h5.json"), summary.PlayerFormSummary{
+		LeagueID: 100, AsOfGW: 4, Horizon: 5,
+		Players: []summary.PlayerForm{{Element: 1, PointsPerGW: 9}, {Element: 2, PointsPerGW: 1}},
+	})
+	out, err := buildPlayerForm(cfg, PlayerFormArgs{LeagueID: 100, Cursor: first.Page.NextCursor})
+	if err != nil {
+		t.Fatalf("buildPlayerForm: %v", err)
+	}
+	if !out.Page.Restart || !slices.Equal(formElements(out), []int{1, 2}) {
+		t.Errorf("after rebuild = %v %+v, want a restart from the first page", formElements(out), out.Page)
+	}
+}
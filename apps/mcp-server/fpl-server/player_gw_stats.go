@@ -1,20 +1,34 @@
 package main
 
 import (
-	"encoding/json"
-	"fmt"
-	"os"
-	"path/filepath"
-	"strconv"
+	"sort"
 	"strings"
 )
 
+// topOppositionCount is how many teams from the top of the PL table count as
+// top opposition in the player_gw_stats split.
+const topOppositionCount = 6
+
 // PlayerGWStatsArgs are the input arguments for the player_gw_stats tool.
 type PlayerGWStatsArgs struct {
 	ElementID  *int    `json:"element_id,omitempty" jsonschema:"Player element id"`
 	PlayerName *string `json:"player_name,omitempty" jsonschema:"Player name (if element_id not provided)"`
 	StartGW    *int    `json:"start_gw,omitempty" jsonschema:"First gameweek to include (0 = 1)"`
 	EndGW      *int    `json:"end_gw,omitempty" jsonschema:"Last gameweek to include (0 = current)"`
+	TopTeamIDs []int   `json:"top_team_ids,omitempty" jsonschema:"Team ids counted as top opposition in the split (default: current PL top 6)"`
+}
+
+// PlayerFixture is one of the player's team's fixtures in a gameweek. Scores
+// and Result are set once the match has started; Result is provisional until
+// Finished.
+type PlayerFixture struct {
+	Opponent      string `json:"opponent"`
+	OpponentID    int    `json:"opponent_id"`
+	Venue         string `json:"venue"` // "H" or "A"
+	TeamScore     *int   `json:"team_score,omitempty"`
+	OpponentScore *int   `json:"opponent_score,omitempty"`
+	Result        string `json:"result,omitempty"`
+	Finished      bool   `json:"finished"`
 }
 
 // PlayerGWEntry holds a player's stats for one gameweek.
@@ -28,6 +42,36 @@ type PlayerGWEntry struct {
 	BPS         int     `json:"bps"`
 	XG          float64 `json:"expected_goals"`
 	XA          float64 `json:"expected_assists"`
+	// Fixtures is empty in a blank GW and has two entries in a double; the
+	// stats above are the GW totals either way.
+	Fixtures []PlayerFixture `json:"fixtures"`
+	Double   bool            `json:"double_gw,omitempty"`
+	Blank    bool            `json:"blank_gw,omitempty"`
+}
+
+// SplitAverage is the player's average over a subset of gameweeks.
+type SplitAverage struct {
+	Gameweeks int     `json:"gameweeks"`
+	Points    int     `json:"points"`
+	AvgPoints float64 `json:"avg_points"`
+}
+
+func (a *SplitAverage) add(points int) {
+	a.Gameweeks++
+	a.Points += points
+	a.AvgPoints = float64(a.Points) / float64(a.Gameweeks)
+}
+
+// PlayerGWSplit compares home and away and top versus other opposition. Only
+// single-fixture GWs whose match has kicked off count, since a double's
+// points can't be split between its matches and an unplayed one has none.
+type PlayerGWSplit struct {
+	Home              SplitAverage `json:"home"`
+	Away              SplitAverage `json:"away"`
+	VsTop             SplitAverage `json:"vs_top"`
+	VsRest            SplitAverage `json:"vs_rest"`
+	TopTeams          []string     `json:"top_teams"`
+	ExcludedDoubleGWs int          `json:"excluded_double_gws"`
 }
 
 // PlayerGWStatsOutput is the output of the player_gw_stats tool.
@@ -42,52 +86,51 @@ type PlayerGWStatsOutput struct {
 	AvgPoints    float64         `json:"avg_points"`
 	TotalMinutes int             `json:"total_minutes"`
 	Gameweeks    []PlayerGWEntry `json:"gameweeks"`
+	Split        PlayerGWSplit   `json:"split"`
 }
 
-func buildPlayerGWStats(cfg ServerConfig, args PlayerGWStatsArgs) (PlayerGWStatsOutput, error) {
-	// Resolve element ID (by id or by name search).
-	elements, teamShort, _, err := loadBootstrapData(cfg.RawRoot)
-	if err != nil {
-		return PlayerGWStatsOutput{}, err
+// resolvePlayer finds a player by element id, or by web_name when no id is
+// given: an exact (case-insensitive) match wins over the first partial match.
+func resolvePlayer(elements []elementInfo, elementID *int, playerName *string) (elementInfo, error) {
+	id := 0
+	if elementID != nil {
+		id = *elementID
 	}
-	playerByID := make(map[int]elementInfo, len(elements))
-	for _, e := range elements {
-		playerByID[e.ID] = e
-	}
-
-	elementID := 0
-	if args.ElementID != nil {
-		elementID = *args.ElementID
-	}
-	if elementID == 0 {
-		if args.PlayerName == nil || strings.TrimSpace(*args.PlayerName) == "" {
-			return PlayerGWStatsOutput{}, fmt.Errorf("element_id or player_name is required")
+	if id == 0 {
+		if playerName == nil || strings.TrimSpace(*playerName) == "" {
+			return elementInfo{}, invalidArgumentf("element_id or player_name is required")
 		}
-		needle := strings.ToLower(strings.TrimSpace(*args.PlayerName))
-		// First try exact web_name match, then partial.
+		needle := strings.ToLower(strings.TrimSpace(*playerName))
 		for _, e := range elements {
 			if strings.ToLower(e.Name) == needle {
-				elementID = e.ID
-				break
+				return e, nil
 			}
 		}
-		if elementID == 0 {
-			for _, e := range elements {
-				if strings.Contains(strings.ToLower(e.Name), needle) {
-					elementID = e.ID
-					break
-				}
+		for _, e := range elements {
+			if strings.Contains(strings.ToLower(e.Name), needle) {
+				return e, nil
 			}
 		}
-		if elementID == 0 {
-			return PlayerGWStatsOutput{}, fmt.Errorf("player not found: %s", *args.PlayerName)
+		return elementInfo{}, notFoundf("player not found: %s", *playerName)
+	}
+	for _, e := range elements {
+		if e.ID == id {
+			return e, nil
 		}
 	}
+	return elementInfo{}, notFoundf("element not found: %d", id)
+}
 
-	meta, ok := playerByID[elementID]
-	if !ok {
-		return PlayerGWStatsOutput{}, fmt.Errorf("element not found: %d", elementID)
+func buildPlayerGWStats(cfg ServerConfig, args PlayerGWStatsArgs) (PlayerGWStatsOutput, error) {
+	elements, teamShort, bootstrapFixtures, err := loadBootstrapData(cfg.RawRoot)
+	if err != nil {
+		return PlayerGWStatsOutput{}, err
+	}
+	meta, err := resolvePlayer(elements, args.ElementID, args.PlayerName)
+	if err != nil {
+		return PlayerGWStatsOutput{}, err
 	}
+	elementID := meta.ID
 
 	// Resolve GW range.
 	startGW := 1
@@ -116,42 +159,18 @@ func buildPlayerGWStats(cfg ServerConfig, args PlayerGWStatsArgs) (PlayerGWStats
 	gwCount := 0
 
 	for gw := startGW; gw <= endGW; gw++ {
-		livePath := filepath.Join(cfg.RawRoot, fmt.Sprintf("gw/%d/live.json", gw))
-		liveRaw, err := os.ReadFile(livePath)
+		live, err := loadLiveStats(cfg.RawRoot, gw)
 		if err != nil {
 			// GW data not yet fetched — skip silently.
 			continue
 		}
-
-		var liveResp struct {
-			Elements map[string]struct {
-				Stats struct {
-					Minutes     int     `json:"minutes"`
-					TotalPoints int     `json:"total_points"`
-					GoalsScored int     `json:"goals_scored"`
-					Assists     int     `json:"assists"`
-					CleanSheets int     `json:"clean_sheets"`
-					BPS         int     `json:"bps"`
-					XG          float64 `json:"expected_goals"`
-					XA          float64 `json:"expected_assists"`
-				} `json:"stats"`
-			} `json:"elements"`
-		}
-		if err := json.Unmarshal(liveRaw, &liveResp); err != nil {
-			continue
-		}
-
-		key := fmt.Sprintf("%d", elementID)
-		data, found := liveResp.Elements[key]
+		s, found := live[elementID]
 		if !found {
 			continue
 		}
 
-		s := data.Stats
-		xg := s.XG
-		xa := s.XA
-
 		entry := PlayerGWEntry{
+			Fixtures:    teamFixtures(cfg.RawRoot, gw, meta.TeamID, teamShort, bootstrapFixtures[gw]),
 			Gameweek:    gw,
 			Minutes:     s.Minutes,
 			Points:      s.TotalPoints,
@@ -159,9 +178,11 @@ func buildPlayerGWStats(cfg ServerConfig, args PlayerGWStatsArgs) (PlayerGWStats
 			Assists:     s.Assists,
 			CleanSheets: s.CleanSheets,
 			BPS:         s.BPS,
-			XG:          xg,
-			XA:          xa,
+			XG:          s.XG,
+			XA:          s.XA,
 		}
+		entry.Double = len(entry.Fixtures) > 1
+		entry.Blank = len(entry.Fixtures) == 0
 		gwEntries = append(gwEntries, entry)
 		totalPts += s.TotalPoints
 		totalMins += s.Minutes
@@ -174,6 +195,7 @@ func buildPlayerGWStats(cfg ServerConfig, args PlayerGWStatsArgs) (PlayerGWStats
 	}
 
 	return PlayerGWStatsOutput{
+		Split:        buildPlayerGWSplit(gwEntries, topOpposition(cfg, args.TopTeamIDs), teamShort),
 		ElementID:    elementID,
 		PlayerName:   meta.Name,
 		Team:         teamShort[meta.TeamID],
@@ -187,14 +209,100 @@ func buildPlayerGWStats(cfg ServerConfig, args PlayerGWStatsArgs) (PlayerGWStats
 	}, nil
 }
 
-// parseFloat parses a string float, returning 0.0 on error.
-func parseFloat(s string) float64 {
-	if s == "" {
-		return 0
+// teamFixtures returns team's fixtures in gw with scores from live.json,
+// falling back to the bootstrap schedule when live.json lists no fixtures
+// (a GW that hasn't started).
+func teamFixtures(rawRoot string, gw int, team int, teamShort map[int]string, scheduled []fixture) []PlayerFixture {
+	fixtures, err := loadFixtureResults(rawRoot, gw)
+	if err != nil || len(fixtures) == 0 {
+		fixtures = fixtures[:0]
+		for _, f := range scheduled {
+			fixtures = append(fixtures, rawFixture{ID: f.ID, Event: gw, TeamH: f.TeamH, TeamA: f.TeamA})
+		}
+	}
+	out := make([]PlayerFixture, 0, 1)
+	for _, f := range fixtures {
+		var pf PlayerFixture
+		switch team {
+		case f.TeamH:
+			pf = PlayerFixture{OpponentID: f.TeamA, Venue: "H", TeamScore: f.TeamHS, OpponentScore: f.TeamAS}
+		case f.TeamA:
+			pf = PlayerFixture{OpponentID: f.TeamH, Venue: "A", TeamScore: f.TeamAS, OpponentScore: f.TeamHS}
+		default:
+			continue
+		}
+		pf.Opponent = teamShort[pf.OpponentID]
+		pf.Finished = f.Finished
+		if pf.TeamScore != nil && pf.OpponentScore != nil {
+			pf.Result = resultFromScore(*pf.TeamScore, *pf.OpponentScore)
+		}
+		out = append(out, pf)
 	}
-	f, err := strconv.ParseFloat(s, 64)
+	return out
+}
+
+// topOpposition returns the team ids counted as top opposition: ids when
+// given, otherwise the top of the PL table built from results so far. It is
+// empty when there are no results to rank.
+func topOpposition(cfg ServerConfig, ids []int) map[int]bool {
+	out := make(map[int]bool)
+	if len(ids) > 0 {
+		for _, id := range ids {
+			out[id] = true
+		}
+		return out
+	}
+	table, err := buildEPLStandings(cfg)
 	if err != nil {
-		return 0
+		return out
+	}
+	teams, err := loadTeams(cfg.RawRoot)
+	if err != nil {
+		return out
+	}
+	idByShort := make(map[string]int, len(teams))
+	for id, t := range teams {
+		idByShort[t.ShortName] = id
+	}
+	for _, row := range table.Standings {
+		if len(out) == topOppositionCount {
+			break
+		}
+		if row.Played > 0 {
+			out[idByShort[row.Short]] = true
+		}
+	}
+	return out
+}
+
+func buildPlayerGWSplit(rows []PlayerGWEntry, top map[int]bool, teamShort map[int]string) PlayerGWSplit {
+	split := PlayerGWSplit{TopTeams: make([]string, 0, len(top))}
+	for id := range top {
+		split.TopTeams = append(split.TopTeams, teamShort[id])
+	}
+	sort.Strings(split.TopTeams)
+	for _, r := range rows {
+		if r.Double {
+			split.ExcludedDoubleGWs++
+			continue
+		}
+		if r.Blank {
+			continue
+		}
+		f := r.Fixtures[0]
+		if f.TeamScore == nil {
+			continue
+		}
+		if f.Venue == "H" {
+			split.Home.add(r.Points)
+		} else {
+			split.Away.add(r.Points)
+		}
+		if top[f.OpponentID] {
+			split.VsTop.add(r.Points)
+		} else {
+			split.VsRest.add(r.Points)
+		}
 	}
-	return f
+	return split
 }
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+)
+
+// playerNames is the bootstrap player and team lookup for tools that read
+// bootstrap-static.json only to label element ids. Unavailable is set when
+// bootstrap is missing, unparseable or lists no players (as it can be during
+// the preseason reshape); every id is then found, named after the id alone
+// with no team or position, and the tool copies the flag into its output's
+// player_names_unavailable instead of failing.
+type playerNames struct {
+	byID        map[int]elementInfo
+	teamShort   map[int]string
+	Unavailable bool
+}
+
+// loadPlayerNames loads the player lookup, degrading rather than failing on
+// a missing or reshaped bootstrap. Other read errors are returned.
+func loadPlayerNames(rawRoot string) (playerNames, error) {
+	elements, teamShort, _, err := loadBootstrapData(rawRoot)
+	if err != nil {
+		var syntaxErr *json.SyntaxError
+		var typeErr *json.UnmarshalTypeError
+		if errors.Is(err, fs.ErrNotExist) || errors.As(err, &syntaxErr) || errors.As(err, &typeErr) {
+			return playerNames{Unavailable: true}, nil
+		}
+		return playerNames{}, err
+	}
+	if len(elements) == 0 {
+		return playerNames{Unavailable: true}, nil
+	}
+	byID := make(map[int]elementInfo, len(elements))
+	for _, e := range elements {
+		byID[e.ID] = e
+	}
+	return playerNames{byID: byID, teamShort: teamShort}, nil
+}
+
+// get returns the metadata for element id. ok is false when bootstrap doesn't
+// list it; in degraded mode every id is found with only its id as a name.
+func (p playerNames) get(id int) (elementInfo, bool) {
+	if p.Unavailable {
+		return elementInfo{ID: id, Name: fmt.Sprintf("element %d", id)}, true
+	}
+	e, ok := p.byID[id]
+	return e, ok
+}
+
+// team returns the short name of team id, or "" when it isn't known.
+func (p playerNames) team(id int) string {
+	return p.teamShort[id]
+}
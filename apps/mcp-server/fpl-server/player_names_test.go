@@ -0,0 +1,284 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// writeLeagueOnlyRoot writes the data a league has before bootstrap is
+// usable: league details for league 100 (two entries, GW 1-2 played) and a
+// game.json with GW 2 finished. cfg computes missing summaries into dir.
+func writeLeagueOnlyRoot(t *testing.T) (string, ServerConfig) {
+	t.Helper()
+	dir := t.TempDir()
+	writeFullGameJSON(t, dir, 2, true, 3, true, "n")
+	writeLeagueDetailsFixture(t, dir, 100,
+		[]any{
+			map[string]any{"id": 1, "entry_id": 200, "entry_name": "Alpha FC", "short_name": "AFC"},
+			map[string]any{"id": 2, "entry_id": 201, "entry_name": "Beta FC", "short_name": "BFC"},
+		},
+		[]any{
+			map[string]any{"event": 1, "finished": true, "started": true, "league_entry_1": 1, "league_entry_1_points": 50, "league_entry_2": 2, "league_entry_2_points": 40},
+			map[string]any{"event": 2, "finished": true, "started": true, "league_entry_1": 1, "league_entry_1_points": 30, "league_entry_2": 2, "league_entry_2_points": 45},
+			map[string]any{"event": 3, "finished": false, "started": false, "league_entry_1": 2, "league_entry_1_points": 0, "league_entry_2": 1, "league_entry_2_points": 0},
+		})
+	return dir, ServerConfig{RawRoot: dir, DerivedRoot: dir, WriteDerived: true, ComputeMissing: true}
+}
+
+// toolOutcome is what a tool does against writeLeagueOnlyRoot.
+type toolOutcome int
+
+const (
+	outcomeOK      toolOutcome = iota // answers from league details alone
+	outcomeDegrade                    // answers with player_names_unavailable set
+	outcomeMissing                    // fails with DATA_MISSING
+)
+
+func (o toolOutcome) String() string {
+	return [...]string{"ok", "degrade", "DATA_MISSING"}[o]
+}
+
+// toolDependencies is every registered tool's outcome against a raw root
+// with only league details and game.json. TestToolDependencyMatrix fails for
+// a registered tool missing from it, so a new tool has to declare one.
+var toolDependencies = map[string]toolOutcome{
+	"standings":        outcomeOK,
+	"manager_elo":      outcomeOK,
+	"manager_lookup":   outcomeOK,
+	"manager_schedule": outcomeOK,
+	"manager_streak":   outcomeOK,
+	"manager_season":   outcomeOK,
+	"league_entries":   outcomeOK,
+	"league_settings":  outcomeOK,
+	"head_to_head":     outcomeOK,
+	"league_dashboard": outcomeOK,
+	"backfill_status":  outcomeOK,
+
+	"league_newswire": outcomeDegrade,
+
+	"player_form":            outcomeMissing,
+	"waiver_targets":         outcomeMissing,
+	"waiver_recommendations": outcomeMissing,
+	"recommendation_review":  outcomeMissing,
+	"claim_simulator":        outcomeMissing,
+	"league_summary":         outcomeMissing,
+	"matchup_breakdown":      outcomeMissing,
+	"power_rankings":         outcomeMissing,
+	"transactions":           outcomeMissing,
+	"lineup_efficiency":      outcomeMissing,
+	"strength_of_schedule":   outcomeMissing,
+	"ownership_scarcity":     outcomeMissing,
+	"fixtures":               outcomeMissing,
+	"fixture_difficulty":     outcomeMissing,
+	"schedule_swing":         outcomeMissing,
+	"team_sos":               outcomeMissing,
+	"team_defense_profile":   outcomeMissing,
+	"player_lookup":          outcomeMissing,
+	"roster_changes":         outcomeMissing,
+	"current_roster":         outcomeMissing,
+	"draft_picks":            outcomeMissing,
+	"draft_board":            outcomeMissing,
+	"draft_rankings":         outcomeMissing,
+	"draft_what_if":          outcomeMissing,
+	"historical_roster":      outcomeMissing,
+	"entry_points":           outcomeMissing,
+	"transaction_analysis":   outcomeMissing,
+	"waiver_wire_trends":     outcomeMissing,
+	"inactivity_report":      outcomeMissing,
+	"player_usage":           outcomeMissing,
+	"manager_tendencies":     outcomeMissing,
+	"trade_history":          outcomeMissing,
+	"trade_review":           outcomeMissing,
+	"player_gw_stats":        outcomeMissing,
+	"provisional_bonus":      outcomeMissing,
+	"player_consistency":     outcomeMissing,
+	"availability_watch":     outcomeMissing,
+	"positional_edge":        outcomeMissing,
+	"gameweek_report":        outcomeMissing,
+	"optimal_standings":      outcomeMissing,
+	"roster_outlook":         outcomeMissing,
+	"gw_calendar":            outcomeMissing,
+	"team_coverage":          outcomeMissing,
+	"deadline_checklist":     outcomeMissing,
+	"opponent_scout":         outcomeMissing,
+	"game_status":            outcomeMissing,
+	"epl_fixtures":           outcomeMissing,
+	"epl_standings":          outcomeMissing,
+	"raw_query":              outcomeMissing,
+}
+
+// matrixPlaceholders fills the example placeholders league details can't:
+// element ids, which come from bootstrap.
+var matrixPlaceholders = map[string]any{
+	exampleElement:            1,
+	"<free_agent_element_id>": 1,
+	"<rostered_element_id>":   2,
+}
+
+// callToolOnRoot registers every tool for cfg, with league 100 filling the
+// example placeholders, and calls tool over an in-memory session. args nil
+// uses the tool's first registered example.
+func callToolOnRoot(t *testing.T, cfg ServerConfig, tool string, args map[string]any) *mcp.CallToolResult {
+	t.Helper()
+	cfg.DefaultLeagueID = 100
+	server, _, tools := buildServer(cfg)
+	if args == nil {
+		for _, info := range tools {
+			if info.Name == tool && len(info.Examples) > 0 {
+				missing := make(map[string]bool)
+				args = fillPlaceholders(info.Examples[0].Args, matrixPlaceholders, missing).(map[string]any)
+				if len(missing) > 0 {
+					t.Fatalf("example args %v keep placeholders %v", args, missing)
+				}
+			}
+		}
+	}
+	ctx := context.Background()
+	st, ct := mcp.NewInMemoryTransports()
+	ss, err := server.Connect(ctx, st, nil)
+	if err != nil {
+		t.Fatalf("server connect: %v", err)
+	}
+	t.Cleanup(func() { _ = ss.Close() })
+	cs, err := mcp.NewClient(&mcp.Implementation{Name: "client", Version: "0"}, nil).Connect(ctx, ct, nil)
+	if err != nil {
+		t.Fatalf("client connect: %v", err)
+	}
+	t.Cleanup(func() { _ = cs.Close() })
+	res, err := cs.CallTool(ctx, &mcp.CallToolParams{Name: tool, Arguments: args})
+	if err != nil {
+		t.Fatalf("call %s %v: %v", tool, args, err)
+	}
+	return res
+}
+
+// checkOutcome fails t unless res is want: DATA_MISSING for outcomeMissing,
+// and otherwise a result with player_names_unavailable set exactly when want
+// is outcomeDegrade.
+func checkOutcome(t *testing.T, res *mcp.CallToolResult, want toolOutcome) {
+	t.Helper()
+	if res.IsError {
+		body := decodeToolError(t, res)
+		if want != outcomeMissing || body.Error.Code != codeDataMissing {
+			t.Fatalf("got %s (%s), want %s", body.Error.Code, body.Error.Message, want)
+		}
+		return
+	}
+	if want == outcomeMissing {
+		t.Fatal("succeeded, want DATA_MISSING")
+	}
+	var out struct {
+		PlayerNamesUnavailable bool `json:"player_names_unavailable"`
+	}
+	if err := json.Unmarshal([]byte(resultText(t, res)), &out); err != nil {
+		t.Fatalf("result is not JSON: %v", err)
+	}
+	if got := out.PlayerNamesUnavailable; got != (want == outcomeDegrade) {
+		t.Fatalf("player_names_unavailable = %v, want %s", got, want)
+	}
+}
+
+// TestToolDependencyMatrix runs every registered tool, with its first
+// example's args, against a raw root with only league details and
+// game.json. League-structure tools must answer from those alone, tools that
+// only label players must degrade, and everything else must fail with
+// DATA_MISSING, never a raw read error or INTERNAL.
+func TestToolDependencyMatrix(t *testing.T) {
+	_, cfg := writeLeagueOnlyRoot(t)
+	_, _, tools := buildServer(cfg)
+	registered := make(map[string]bool, len(tools))
+	for _, tool := range tools {
+		registered[tool.Name] = true
+		t.Run(tool.Name, func(t *testing.T) {
+			want, ok := toolDependencies[tool.Name]
+			if !ok {
+				t.Fatal("no outcome in toolDependencies")
+			}
+			_, cfg := writeLeagueOnlyRoot(t)
+			checkOutcome(t, callToolOnRoot(t, cfg, tool.Name, nil), want)
+		})
+	}
+	for name := range toolDependencies {
+		if !registered[name] {
+			t.Errorf("toolDependencies lists %s, which is not registered", name)
+		}
+	}
+
+	// Calls beyond the examples whose outcome differs by argument.
+	t.Run("standings_h2h", func(t *testing.T) {
+		_, cfg := writeLeagueOnlyRoot(t)
+		res := callToolOnRoot(t, cfg, "standings", map[string]any{"league_id": 100, "gw": 0, "tiebreakers": []string{"h2h"}})
+		checkOutcome(t, res, outcomeOK)
+	})
+}
+
+func TestLoadPlayerNames_Degraded(t *testing.T) {
+	for name, write := range map[string]func(t *testing.T, dir string){
+		"missing": func(t *testing.T, dir string) {},
+		"no elements": func(t *testing.T, dir string) {
+			writeJSON(t, filepath.Join(dir, "bootstrap", "bootstrap-static.json"), map[string]any{"elements": []any{}})
+		},
+		"reshaped": func(t *testing.T, dir string) {
+			writeJSON(t, filepath.Join(dir, "bootstrap", "bootstrap-static.json"), map[string]any{"elements": map[string]any{"data": []any{}}})
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			write(t, dir)
+			p, err := loadPlayerNames(dir)
+			if err != nil {
+				t.Fatalf("loadPlayerNames: %v", err)
+			}
+			if !p.Unavailable {
+				t.Fatal("Unavailable = false, want true")
+			}
+			if e, ok := p.get(7); !ok || e.ID != 7 || e.Name != "element 7" {
+				t.Errorf("get(7) = %+v, %v", e, ok)
+			}
+		})
+	}
+
+	dir := t.TempDir()
+	writeBootstrap(t, dir)
+	p, err := loadPlayerNames(dir)
+	if err != nil || p.Unavailable {
+		t.Fatalf("full bootstrap: %+v, %v", p, err)
+	}
+	if e, ok := p.get(1); !ok || e.Name != "Salah" || p.team(e.TeamID) != "LIV" {
+		t.Errorf("get(1) = %+v, %v", e, ok)
+	}
+	if _, ok := p.get(99); ok {
+		t.Error("get(99) found an element bootstrap doesn't list")
+	}
+}
+
+func TestBuildDraftPicks_WithoutBootstrap(t *testing.T) {
+	dir, cfg := tmpCfg(t)
+	writeDraftChoicesFixture(t, dir)
+
+	out, err := buildDraftPicks(cfg, DraftPicksArgs{LeagueID: 100})
+	if err != nil {
+		t.Fatalf("buildDraftPicks: %v", err)
+	}
+	if !out.PlayerNamesUnavailable {
+		t.Error("player_names_unavailable not set")
+	}
+	if len(out.Picks) == 0 {
+		t.Fatal("no picks")
+	}
+	for _, p := range out.Picks {
+		if want := fmt.Sprintf("element %d", p.Element); p.PlayerName != want || p.Team != "" {
+			t.Errorf("pick %+v, want name %q and no team", p, want)
+		}
+	}
+	b, _ := json.Marshal(out)
+	var raw map[string]any
+	if err := json.Unmarshal(b, &raw); err != nil || raw["player_names_unavailable"] != true {
+		t.Errorf("json = %s", b)
+	}
+}
@@ -0,0 +1,303 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/leagueconfig"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+)
+
+// Player usage in a gameweek, reported in PlayerUsageGW.Status.
+const (
+	usageStarted       = "started"
+	usageBenched       = "benched"
+	usageUnowned       = "unowned"
+	usageLineupUnknown = "lineup_unknown"
+)
+
+// PlayerUsageArgs are the input arguments for the player_usage tool.
+type PlayerUsageArgs struct {
+	LeagueID   int     `json:"league_id" jsonschema:"Draft league id (required)"`
+	ElementID  *int    `json:"element_id,omitempty" jsonschema:"Player element id"`
+	PlayerName *string `json:"player_name,omitempty" jsonschema:"Player name (if element_id not provided)"`
+}
+
+// UsageEvent is the draft pick or a move that changed who owned the player.
+// Kind is "draft", "waiver", "free_agent" or "trade"; an entry id of 0 is
+// the free-agent pool.
+type UsageEvent struct {
+	Gameweek int    `json:"gameweek"`
+	Kind     string `json:"kind"`
+	FromID   int    `json:"from_entry_id,omitempty"`
+	From     string `json:"from,omitempty"`
+	ToID     int    `json:"to_entry_id,omitempty"`
+	To       string `json:"to,omitempty"`
+	Round    int    `json:"round,omitempty"`
+	Pick     int    `json:"pick,omitempty"`
+}
+
+// PlayerUsageGW is the player's gameweek in the league. Counted is whether
+// his points went into the owner's score (started and not subbed off, or
+// subbed on from the bench); Wasted is set when he scored while benched or
+// unowned. AutoSub is "in" or "out" when an automatic substitution moved him.
+type PlayerUsageGW struct {
+	Gameweek  int          `json:"gameweek"`
+	OwnerID   int          `json:"owner_entry_id,omitempty"`
+	OwnerName string       `json:"owner,omitempty"`
+	Status    string       `json:"status"`
+	AutoSub   string       `json:"auto_sub,omitempty"`
+	Minutes   int          `json:"minutes"`
+	Points    int          `json:"points"`
+	Counted   bool         `json:"counted"`
+	Wasted    bool         `json:"wasted,omitempty"`
+	Events    []UsageEvent `json:"events,omitempty"`
+}
+
+// OwnerUsage totals the player's gameweeks under one owner.
+type OwnerUsage struct {
+	EntryID       int    `json:"entry_id"`
+	EntryName     string `json:"entry_name"`
+	Gameweeks     int    `json:"gameweeks"`
+	Started       int    `json:"started"`
+	Benched       int    `json:"benched"`
+	Points        int    `json:"points"`
+	CountedPoints int    `json:"counted_points"`
+	BenchedPoints int    `json:"benched_points"`
+	FirstGW       int    `json:"first_gw"`
+	LastGW        int    `json:"last_gw"`
+}
+
+// PlayerUsageOutput is the output of the player_usage tool. WastedPoints is
+// PointsBenched plus PointsUnowned. Upcoming holds moves already processed
+// for a GW after ThroughGW.
+type PlayerUsageOutput struct {
+	LeagueID      int             `json:"league_id"`
+	ElementID     int             `json:"element_id"`
+	PlayerName    string          `json:"player_name"`
+	Team          string          `json:"team"`
+	PositionType  int             `json:"position_type"`
+	FromGW        int             `json:"from_gw"`
+	ThroughGW     int             `json:"through_gw"`
+	TotalPoints   int             `json:"total_points"`
+	PointsCounted int             `json:"points_counted"`
+	PointsBenched int             `json:"points_benched"`
+	PointsUnowned int             `json:"points_unowned"`
+	WastedPoints  int             `json:"wasted_points"`
+	Owners        []OwnerUsage    `json:"owners"`
+	Timeline      []PlayerUsageGW `json:"timeline"`
+	Upcoming      []UsageEvent    `json:"upcoming,omitempty"`
+	Notes         []string        `json:"notes,omitempty"`
+}
+
+// buildPlayerUsage walks the league's season GW by GW for one player: who
+// owned him, whether they started him, and what he scored.
+func buildPlayerUsage(cfg ServerConfig, args PlayerUsageArgs) (PlayerUsageOutput, error) {
+	if args.LeagueID == 0 {
+		return PlayerUsageOutput{}, invalidArgumentf("league_id is required")
+	}
+	elements, teamShort, _, err := loadBootstrapData(cfg.RawRoot)
+	if err != nil {
+		return PlayerUsageOutput{}, err
+	}
+	meta, err := resolvePlayer(elements, args.ElementID, args.PlayerName)
+	if err != nil {
+		return PlayerUsageOutput{}, err
+	}
+	throughGW, _, err := resolveEffectiveGW(cfg, 0, gwModeLatestFinished)
+	if err != nil {
+		return PlayerUsageOutput{}, err
+	}
+	st := store.NewJSONStore(cfg.RawRoot)
+	settings, err := leagueconfig.Load(st, args.LeagueID)
+	if err != nil {
+		return PlayerUsageOutput{}, err
+	}
+	ld, _, err := loadLeagueDetails(st, args.LeagueID)
+	if err != nil {
+		return PlayerUsageOutput{}, err
+	}
+	nameByEntry := make(map[int]string, len(ld.LeagueEntries))
+	for _, e := range ld.LeagueEntries {
+		nameByEntry[e.EntryID] = e.EntryName
+	}
+	ownership, err := loadOwnershipTimeline(cfg, args.LeagueID)
+	if err != nil {
+		return PlayerUsageOutput{}, err
+	}
+
+	fromGW := settings.StartEvent
+	out := PlayerUsageOutput{
+		LeagueID:     args.LeagueID,
+		ElementID:    meta.ID,
+		PlayerName:   meta.Name,
+		Team:         teamShort[meta.TeamID],
+		PositionType: meta.PositionType,
+		FromGW:       fromGW,
+		ThroughGW:    throughGW,
+		Owners:       []OwnerUsage{},
+		Timeline:     []PlayerUsageGW{},
+	}
+	if throughGW < fromGW {
+		out.Notes = append(out.Notes, fmt.Sprintf("League %d starts at GW %d and no GW since has finished.", args.LeagueID, fromGW))
+		return out, nil
+	}
+
+	// Moves go on the row of the GW they take effect for; the draft pick on
+	// the league's first GW.
+	eventsByGW := make(map[int][]UsageEvent)
+	for _, p := range ownership.ledger.Picks {
+		if p.Element == meta.ID {
+			eventsByGW[fromGW] = append(eventsByGW[fromGW], UsageEvent{
+				Gameweek: fromGW, Kind: "draft", ToID: p.EntryID, To: nameByEntry[p.EntryID], Round: p.Round, Pick: p.Pick,
+			})
+		}
+	}
+	for _, m := range ownershipMoves(ownership.transactions, ownership.trades) {
+		if m.Element != meta.ID {
+			continue
+		}
+		ev := UsageEvent{Gameweek: m.Event, Kind: moveKindLabel(m.Kind), FromID: m.From, From: nameByEntry[m.From], ToID: m.To, To: nameByEntry[m.To]}
+		switch {
+		case m.Event > throughGW:
+			out.Upcoming = append(out.Upcoming, ev)
+		case m.Event < fromGW:
+			eventsByGW[fromGW] = append(eventsByGW[fromGW], ev)
+		default:
+			eventsByGW[m.Event] = append(eventsByGW[m.Event], ev)
+		}
+	}
+
+	owners := make(map[int]*OwnerUsage)
+	missingLive := make([]int, 0)
+	for gw := fromGW; gw <= throughGW; gw++ {
+		live, err := loadLiveStats(cfg.RawRoot, gw)
+		if err != nil {
+			missingLive = append(missingLive, gw)
+			continue
+		}
+		stats := live[meta.ID]
+		row := PlayerUsageGW{
+			Gameweek: gw,
+			Status:   usageUnowned,
+			Minutes:  stats.Minutes,
+			Points:   stats.TotalPoints,
+			Events:   eventsByGW[gw],
+		}
+		out.TotalPoints += row.Points
+
+		owner := ownership.ownerAt(gw, meta.ID)
+		if owner == 0 {
+			row.Wasted = row.Points != 0
+			out.PointsUnowned += row.Points
+			out.Timeline = append(out.Timeline, row)
+			continue
+		}
+		row.OwnerID = owner
+		row.OwnerName = nameByEntry[owner]
+		if err := setLineupStatus(cfg, args.LeagueID, gw, &row, meta.ID); err != nil {
+			return PlayerUsageOutput{}, err
+		}
+
+		ou, ok := owners[owner]
+		if !ok {
+			ou = &OwnerUsage{EntryID: owner, EntryName: row.OwnerName, FirstGW: gw}
+			owners[owner] = ou
+		}
+		ou.Gameweeks++
+		ou.LastGW = gw
+		ou.Points += row.Points
+		switch row.Status {
+		case usageStarted:
+			ou.Started++
+		case usageBenched:
+			ou.Benched++
+		}
+		if row.Counted {
+			ou.CountedPoints += row.Points
+			out.PointsCounted += row.Points
+		}
+		if row.Status == usageBenched && !row.Counted {
+			ou.BenchedPoints += row.Points
+			out.PointsBenched += row.Points
+			row.Wasted = row.Points != 0
+		}
+		out.Timeline = append(out.Timeline, row)
+	}
+	out.WastedPoints = out.PointsBenched + out.PointsUnowned
+
+	for _, ou := range owners {
+		out.Owners = append(out.Owners, *ou)
+	}
+	sort.Slice(out.Owners, func(i, j int) bool {
+		if out.Owners[i].FirstGW != out.Owners[j].FirstGW {
+			return out.Owners[i].FirstGW < out.Owners[j].FirstGW
+		}
+		return out.Owners[i].EntryID < out.Owners[j].EntryID
+	})
+
+	if len(missingLive) > 0 {
+		out.Notes = append(out.Notes, fmt.Sprintf("No live data for GW %v, so those GWs are left out.", missingLive))
+	}
+	for _, row := range out.Timeline {
+		if row.Status == usageLineupUnknown {
+			out.Notes = append(out.Notes, "Some owned GWs have no lineup snapshot; their points are in the owner's total but not counted, benched or wasted.")
+			break
+		}
+	}
+	return out, nil
+}
+
+// setLineupStatus fills row's Status, AutoSub and Counted from the owner's
+// snapshot for gw. A missing snapshot leaves the lineup unknown rather than
+// failing the whole season.
+func setLineupStatus(cfg ServerConfig, leagueID int, gw int, row *PlayerUsageGW, element int) error {
+	row.Status = usageLineupUnknown
+	snap, err := loadEntrySnapshot(cfg, leagueID, row.OwnerID, gw)
+	if err != nil {
+		if classifyError(err).Code == codeDataMissing {
+			return nil
+		}
+		return err
+	}
+	if snap.Missing {
+		return nil
+	}
+	for _, p := range snap.Picks {
+		if p.Element != element {
+			continue
+		}
+		row.Status = usageStarted
+		if p.Position > 11 {
+			row.Status = usageBenched
+		}
+	}
+	if row.Status == usageLineupUnknown {
+		// The picks don't include him: the snapshot predates the move that
+		// brought him in, so there is nothing to say about his lineup.
+		return nil
+	}
+	row.Counted = row.Status == usageStarted
+	for _, s := range snap.Subs {
+		switch element {
+		case s.ElementIn:
+			row.AutoSub = "in"
+			row.Counted = true
+		case s.ElementOut:
+			row.AutoSub = "out"
+			row.Counted = false
+		}
+	}
+	return nil
+}
+
+// moveKindLabel names an ownershipMove kind for UsageEvent.Kind.
+func moveKindLabel(kind string) string {
+	switch kind {
+	case "w":
+		return "waiver"
+	case "f":
+		return "free_agent"
+	}
+	return kind
+}
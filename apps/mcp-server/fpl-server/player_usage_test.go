@@ -0,0 +1,127 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// writeUsageFixture follows Palmer (2) through writeClaimFixture's league:
+// Alpha drafts and benches him in GW1, drops him for Mbeumo in GW2's
+// waivers, Gamma picks him up as a free agent for GW3 and starts him, then
+// trades him to Beta for Saka ahead of GW4.
+func writeUsageFixture(t *testing.T, dir string) {
+	t.Helper()
+	writeClaimFixture(t, dir)
+	writeJSON(t, filepath.Join(dir, "league/100/transactions.json"), map[string]any{"transactions": []any{
+		map[string]any{"id": 1, "entry": 200, "event": 2, "kind": "w", "result": "a", "element_in": 10, "element_out": 2, "added": "2025-08-20T10:00:00Z"},
+		map[string]any{"id": 2, "entry": 202, "event": 3, "kind": "f", "result": "a", "element_in": 2, "element_out": 5, "added": "2025-08-28T10:00:00Z"},
+		map[string]any{"id": 3, "entry": 201, "event": 3, "kind": "w", "result": "di", "element_in": 2, "element_out": 4, "added": "2025-08-27T10:00:00Z"},
+	}})
+	writeJSON(t, filepath.Join(dir, "league/100/trades.json"), map[string]any{"trades": []any{
+		map[string]any{"id": 7, "event": 4, "state": "p", "offered_entry": 202, "received_entry": 201, "response_time": "2025-09-01T10:00:00Z",
+			"tradeitem_set": []any{map[string]any{"element_out": 2, "element_in": 4}}},
+	}})
+	writeJSON(t, filepath.Join(dir, "entry/200/gw/1.json"), map[string]any{"picks": []any{
+		map[string]any{"element": 1, "position": 1},
+		map[string]any{"element": 2, "position": 12},
+	}})
+	writeJSON(t, filepath.Join(dir, "entry/202/gw/3.json"), map[string]any{"picks": []any{
+		map[string]any{"element": 2, "position": 1},
+	}})
+	writeLiveJSON(t, dir, 1, map[string]any{"2": makeStats(7)})
+	writeLiveJSON(t, dir, 2, map[string]any{"2": makeStats(9)})
+	writeLiveJSON(t, dir, 3, map[string]any{"2": makeStats(5)})
+}
+
+func TestBuildPlayerUsage(t *testing.T) {
+	dir, cfg := resourceCfg(t)
+	cfg.ComputeMissing = true
+	writeUsageFixture(t, dir)
+
+	name := "palmer"
+	out, err := buildPlayerUsage(cfg, PlayerUsageArgs{LeagueID: 100, PlayerName: &name})
+	if err != nil {
+		t.Fatalf("buildPlayerUsage: %v", err)
+	}
+	if out.ElementID != 2 || out.FromGW != 1 || out.ThroughGW != 3 || len(out.Timeline) != 3 {
+		t.Fatalf("output = %+v", out)
+	}
+	want := []struct {
+		owner   int
+		status  string
+		points  int
+		counted bool
+		wasted  bool
+		event   string
+	}{
+		{200, usageBenched, 7, false, true, "draft"},
+		{0, usageUnowned, 9, false, true, "waiver"},
+		{202, usageStarted, 5, true, false, "free_agent"},
+	}
+	for i, w := range want {
+		row := out.Timeline[i]
+		if row.OwnerID != w.owner || row.Status != w.status || row.Points != w.points || row.Counted != w.counted || row.Wasted != w.wasted {
+			t.Errorf("GW%d = %+v, want owner %d %s %d pts counted=%v wasted=%v", row.Gameweek, row, w.owner, w.status, w.points, w.counted, w.wasted)
+		}
+		if len(row.Events) != 1 || row.Events[0].Kind != w.event {
+			t.Errorf("GW%d events = %+v, want one %s", row.Gameweek, row.Events, w.event)
+		}
+	}
+	if ev := out.Timeline[1].Events[0]; ev.FromID != 200 || ev.From != "Alpha FC" || ev.ToID != 0 {
+		t.Errorf("GW2 drop = %+v", ev)
+	}
+	if out.TotalPoints != 21 || out.PointsCounted != 5 || out.PointsBenched != 7 || out.PointsUnowned != 9 || out.WastedPoints != 16 {
+		t.Errorf("totals = %d counted %d benched %d unowned %d wasted %d", out.TotalPoints, out.PointsCounted, out.PointsBenched, out.PointsUnowned, out.WastedPoints)
+	}
+	if len(out.Owners) != 2 || out.Owners[0].EntryID != 200 || out.Owners[0].BenchedPoints != 7 || out.Owners[1].EntryID != 202 || out.Owners[1].CountedPoints != 5 {
+		t.Errorf("owners = %+v", out.Owners)
+	}
+	if len(out.Upcoming) != 1 || out.Upcoming[0].Kind != "trade" || out.Upcoming[0].ToID != 201 {
+		t.Errorf("upcoming = %+v, want the GW4 trade to Beta", out.Upcoming)
+	}
+}
+
+func TestBuildPlayerUsage_AutoSubAndMissingLineup(t *testing.T) {
+	dir, cfg := resourceCfg(t)
+	cfg.ComputeMissing = true
+	writeUsageFixture(t, dir)
+	// GW1 Palmer comes off the bench; Gamma's GW3 lineup is gone.
+	writeJSON(t, filepath.Join(dir, "entry/200/gw/1.json"), map[string]any{
+		"picks": []any{
+			map[string]any{"element": 1, "position": 1},
+			map[string]any{"element": 2, "position": 12},
+		},
+		"subs": []any{map[string]any{"element_in": 2, "element_out": 1, "event": 1}},
+	})
+	writeJSON(t, filepath.Join(dir, "entry/202/gw/3.json"), map[string]any{"picks": []any{}})
+
+	id := 2
+	out, err := buildPlayerUsage(cfg, PlayerUsageArgs{LeagueID: 100, ElementID: &id})
+	if err != nil {
+		t.Fatalf("buildPlayerUsage: %v", err)
+	}
+	if row := out.Timeline[0]; row.Status != usageBenched || row.AutoSub != "in" || !row.Counted || row.Wasted {
+		t.Errorf("GW1 = %+v, want benched, subbed in and counted", row)
+	}
+	if row := out.Timeline[2]; row.Status != usageLineupUnknown || row.Counted || row.Wasted {
+		t.Errorf("GW3 = %+v, want an unknown lineup", row)
+	}
+	if out.PointsCounted != 7 || out.PointsBenched != 0 || out.WastedPoints != 9 || len(out.Notes) != 1 {
+		t.Errorf("counted %d benched %d wasted %d notes %v", out.PointsCounted, out.PointsBenched, out.WastedPoints, out.Notes)
+	}
+}
+
+func TestBuildPlayerUsage_Errors(t *testing.T) {
+	dir, cfg := resourceCfg(t)
+	writeUsageFixture(t, dir)
+	if _, err := buildPlayerUsage(cfg, PlayerUsageArgs{}); classifyError(err).Code != codeInvalidArgument {
+		t.Errorf("missing league: err = %v", err)
+	}
+	if _, err := buildPlayerUsage(cfg, PlayerUsageArgs{LeagueID: 100}); classifyError(err).Code != codeInvalidArgument {
+		t.Errorf("missing player: err = %v", err)
+	}
+	other := 999
+	if _, err := buildPlayerUsage(cfg, PlayerUsageArgs{LeagueID: 100, ElementID: &other}); classifyError(err).Code != codeNotFound {
+		t.Errorf("unknown player: err = %v", err)
+	}
+}
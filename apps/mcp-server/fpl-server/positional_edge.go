@@ -0,0 +1,238 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/summary"
+)
+
+// PositionalEdgeArgs are the input arguments for the positional_edge tool.
+type PositionalEdgeArgs struct {
+	LeagueID  int     `json:"league_id" jsonschema:"Draft league id (required)"`
+	EntryID   *int    `json:"entry_id,omitempty" jsonschema:"Only report this entry"`
+	EntryName *string `json:"entry_name,omitempty" jsonschema:"Only report this entry (alternative to entry_id)"`
+	ThroughGW int     `json:"through_gw,omitempty" jsonschema:"Last gameweek to include (default: latest finished)"`
+}
+
+// PositionBattle is one manager's record in a position group against their
+// weekly opponents. MatchesCost counts matches lost while winning the other
+// three groups; MatchesCarried, matches won while losing them.
+type PositionBattle struct {
+	Position       string  `json:"position"`
+	Won            int     `json:"won"`
+	Drawn          int     `json:"drawn"`
+	Lost           int     `json:"lost"`
+	WinRate        float64 `json:"win_rate"`
+	AvgMargin      float64 `json:"avg_margin"`
+	MatchesCost    int     `json:"matches_cost"`
+	MatchesCarried int     `json:"matches_carried"`
+}
+
+// PositionalEdgeEntry is one manager's positional battles across the season.
+type PositionalEdgeEntry struct {
+	EntryID   int              `json:"entry_id"`
+	EntryName string           `json:"entry_name"`
+	Played    int              `json:"played"`
+	Record    SeasonRecord     `json:"record"`
+	Positions []PositionBattle `json:"positions"`
+	// Weakest is the group with the lowest average margin; Costliest the one
+	// that has cost the most matches (empty when none has).
+	Weakest   string `json:"weakest_position"`
+	Costliest string `json:"costliest_position,omitempty"`
+	Summary   string `json:"summary"`
+}
+
+// PositionalEdgeOutput is the output of the positional_edge tool.
+type PositionalEdgeOutput struct {
+	LeagueID  int                   `json:"league_id"`
+	ThroughGW int                   `json:"through_gw"`
+	Entries   []PositionalEdgeEntry `json:"entries"`
+	GWNote    *GWNote               `json:"gw_note,omitempty"`
+}
+
+// positionGroups are the PositionPoints groups in report order.
+var positionGroups = []string{"GK", "DEF", "MID", "FWD"}
+
+func positionGroupPoints(p summary.PositionPoints) [4]int {
+	return [4]int{p.GK, p.DEF, p.MID, p.FWD}
+}
+
+// positionGroupNames spells out groups for the summary sentence.
+var positionGroupNames = map[string]string{"GK": "goalkeeping", "DEF": "defence", "MID": "midfield", "FWD": "attack"}
+
+type positionalTally struct {
+	entryID int
+	name    string
+	played  int
+	record  SeasonRecord
+	won     [4]int
+	drawn   [4]int
+	lost    [4]int
+	margin  [4]int
+	cost    [4]int
+	carried [4]int
+}
+
+// add records one match from the side whose groups are pts.
+func (t *positionalTally) add(pts, opp summary.PositionPoints, total, oppTotal int) {
+	t.played++
+	result := resultFromScore(total, oppTotal)
+	switch result {
+	case "W":
+		t.record.Wins++
+	case "D":
+		t.record.Draws++
+	default:
+		t.record.Losses++
+	}
+	a, b := positionGroupPoints(pts), positionGroupPoints(opp)
+	wonGroups, lostGroups := 0, 0
+	for i := range a {
+		d := a[i] - b[i]
+		t.margin[i] += d
+		switch {
+		case d > 0:
+			t.won[i]++
+			wonGroups++
+		case d < 0:
+			t.lost[i]++
+			lostGroups++
+		default:
+			t.drawn[i]++
+		}
+	}
+	for i := range a {
+		d := a[i] - b[i]
+		if result == "L" && d < 0 && wonGroups == 3 {
+			t.cost[i]++
+		}
+		if result == "W" && d > 0 && lostGroups == 3 {
+			t.carried[i]++
+		}
+	}
+}
+
+func (t *positionalTally) entry() PositionalEdgeEntry {
+	out := PositionalEdgeEntry{
+		EntryID:   t.entryID,
+		EntryName: t.name,
+		Played:    t.played,
+		Record:    t.record,
+		Positions: make([]PositionBattle, 0, len(positionGroups)),
+	}
+	weakest, costliest := -1, -1
+	for i, pos := range positionGroups {
+		b := PositionBattle{
+			Position:       pos,
+			Won:            t.won[i],
+			Drawn:          t.drawn[i],
+			Lost:           t.lost[i],
+			MatchesCost:    t.cost[i],
+			MatchesCarried: t.carried[i],
+		}
+		if t.played > 0 {
+			b.WinRate = float64(t.won[i]) / float64(t.played)
+			b.AvgMargin = float64(t.margin[i]) / float64(t.played)
+		}
+		out.Positions = append(out.Positions, b)
+		if weakest < 0 || t.margin[i] < t.margin[weakest] {
+			weakest = i
+		}
+		if t.cost[i] > 0 && (costliest < 0 || t.cost[i] > t.cost[costliest] ||
+			(t.cost[i] == t.cost[costliest] && t.margin[i] < t.margin[costliest])) {
+			costliest = i
+		}
+	}
+	if t.played == 0 {
+		return out
+	}
+	out.Weakest = positionGroups[weakest]
+	w := out.Positions[weakest]
+	record := fmt.Sprintf("%d-%d", t.record.Wins, t.record.Losses)
+	if t.record.Draws > 0 {
+		record = fmt.Sprintf("%d-%d-%d", t.record.Wins, t.record.Draws, t.record.Losses)
+	}
+	out.Summary = fmt.Sprintf("%s (%s): weakest in %s, losing that battle %d of %d weeks (avg %+.1f).",
+		t.name, record, positionGroupNames[w.Position], w.Lost, t.played, w.AvgMargin)
+	if costliest >= 0 {
+		out.Costliest = positionGroups[costliest]
+		n := t.cost[costliest]
+		plural := "es"
+		if n == 1 {
+			plural = ""
+		}
+		out.Summary += fmt.Sprintf(" Their %s alone has cost them %d match%s they otherwise won on position groups.",
+			positionGroupNames[out.Costliest], n, plural)
+	}
+	return out
+}
+
+func buildPositionalEdge(cfg ServerConfig, args PositionalEdgeArgs) (PositionalEdgeOutput, error) {
+	if args.LeagueID == 0 {
+		return PositionalEdgeOutput{}, invalidArgumentf("league_id is required")
+	}
+	throughGW, note, err := resolveEffectiveGW(cfg, args.ThroughGW, gwModeLatestFinished)
+	if err != nil {
+		return PositionalEdgeOutput{}, err
+	}
+
+	focus := 0
+	if args.EntryID != nil {
+		focus = *args.EntryID
+	}
+	if focus == 0 && args.EntryName != nil && strings.TrimSpace(*args.EntryName) != "" {
+		ld, _, err := loadLeagueDetails(store.NewJSONStore(cfg.RawRoot), args.LeagueID)
+		if err != nil {
+			return PositionalEdgeOutput{}, err
+		}
+		if focus, err = resolveEntry(ld.LeagueEntries, *args.EntryName); err != nil {
+			return PositionalEdgeOutput{}, err
+		}
+	}
+
+	tallies := make(map[int]*positionalTally)
+	tally := func(id int, name string) *positionalTally {
+		t, ok := tallies[id]
+		if !ok {
+			t = &positionalTally{entryID: id, name: name}
+			tallies[id] = t
+		}
+		return t
+	}
+	for gw := 1; gw <= throughGW; gw++ {
+		var matchups summary.MatchupSummary
+		if err := loadSummaryInto(cfg, args.LeagueID, gw, fmt.Sprintf("summary/matchup/%d/gw/%d.json", args.LeagueID, gw), &matchups); err != nil {
+			return PositionalEdgeOutput{}, err
+		}
+		for _, m := range matchups.Matchups {
+			if m.EntryID == 0 || m.OpponentID == 0 {
+				continue
+			}
+			tally(m.EntryID, m.EntryName).add(m.Points, m.Opponent, m.Total, m.OpponentTotal)
+			tally(m.OpponentID, m.OpponentName).add(m.Opponent, m.Points, m.OpponentTotal, m.Total)
+		}
+	}
+
+	out := PositionalEdgeOutput{
+		LeagueID:  args.LeagueID,
+		ThroughGW: throughGW,
+		Entries:   make([]PositionalEdgeEntry, 0, len(tallies)),
+		GWNote:    note,
+	}
+	if focus != 0 {
+		t, ok := tallies[focus]
+		if !ok {
+			return PositionalEdgeOutput{}, notFoundf("entry %d has no matches through GW %d", focus, throughGW)
+		}
+		out.Entries = append(out.Entries, t.entry())
+		return out, nil
+	}
+	for _, t := range tallies {
+		out.Entries = append(out.Entries, t.entry())
+	}
+	sort.Slice(out.Entries, func(i, j int) bool { return out.Entries[i].EntryID < out.Entries[j].EntryID })
+	return out, nil
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"math"
+	"path/filepath"
+	"testing"
+)
+
+// writePositionalEdgeFixture writes three GWs of Alpha (200) vs Beta (201)
+// matchup summaries for league 100:
+//
+//	GW1: Alpha wins GK/MID/FWD but loses DEF 2-20 and the match 38-45
+//	GW2: Alpha wins GK/MID/FWD, loses DEF, match drawn 25-25
+//	GW3: Alpha loses GK/MID/FWD but wins DEF 20-2 and the match 31-26
+func writePositionalEdgeFixture(t *testing.T, dir string) {
+	t.Helper()
+	pp := func(gk, def, mid, fwd int) map[string]any {
+		return map[string]any{"gk": gk, "def": def, "mid": mid, "fwd": fwd}
+	}
+	weeks := []struct{ a, b map[string]any }{
+		{pp(6, 2, 20, 10), pp(2, 20, 15, 8)},
+		{pp(3, 1, 12, 9), pp(2, 10, 8, 5)},
+		{pp(2, 20, 5, 4), pp(6, 2, 10, 8)},
+	}
+	total := func(p map[string]any) int {
+		return p["gk"].(int) + p["def"].(int) + p["mid"].(int) + p["fwd"].(int)
+	}
+	for i, w := range weeks {
+		writeJSON(t, filepath.Join(dir, "summary/matchup/100/gw", itoa(i+1)+".json"), map[string]any{
+			"matchups": []any{map[string]any{
+				"entry_id": 200, "entry_name": "Alpha FC", "opponent_entry_id": 201, "opponent_name": "Beta FC",
+				"points": w.a, "opponent": w.b, "total": total(w.a), "opponent_total": total(w.b),
+			}},
+		})
+	}
+	writeLeagueDetailsFixture(t, dir, 100, []any{
+		map[string]any{"id": 1, "entry_id": 200, "entry_name": "Alpha FC"},
+		map[string]any{"id": 2, "entry_id": 201, "entry_name": "Beta FC"},
+	}, []any{})
+}
+
+func TestBuildPositionalEdge(t *testing.T) {
+	dir, cfg := resourceCfg(t)
+	writePositionalEdgeFixture(t, dir)
+
+	out, err := buildPositionalEdge(cfg, PositionalEdgeArgs{LeagueID: 100, ThroughGW: 3})
+	if err != nil {
+		t.Fatalf("buildPositionalEdge: %v", err)
+	}
+	if len(out.Entries) != 2 {
+		t.Fatalf("entries = %d, want 2", len(out.Entries))
+	}
+	alpha, beta := out.Entries[0], out.Entries[1]
+	if alpha.EntryID != 200 || alpha.Played != 3 || alpha.Record != (SeasonRecord{Wins: 1, Draws: 1, Losses: 1}) {
+		t.Errorf("alpha = %+v, want 3 played, 1-1-1", alpha)
+	}
+	def := alpha.Positions[1]
+	if def.Position != "DEF" || def.Won != 1 || def.Lost != 2 || def.MatchesCost != 1 || def.MatchesCarried != 1 {
+		t.Errorf("alpha DEF = %+v, want won 1 lost 2, cost 1 carried 1", def)
+	}
+	if math.Abs(def.AvgMargin-(-3)) > 1e-9 || math.Abs(def.WinRate-1.0/3) > 1e-9 {
+		t.Errorf("alpha DEF margin/rate = %v/%v, want -3/0.333", def.AvgMargin, def.WinRate)
+	}
+	if alpha.Weakest != "DEF" || alpha.Costliest != "DEF" {
+		t.Errorf("alpha weakest/costliest = %s/%s, want DEF/DEF", alpha.Weakest, alpha.Costliest)
+	}
+	if want := "Alpha FC (1-1-1): weakest in defence, losing that battle 2 of 3 weeks (avg -3.0). Their defence alone has cost them 1 match they otherwise won on position groups."; alpha.Summary != want {
+		t.Errorf("summary = %q\nwant      %q", alpha.Summary, want)
+	}
+	// GW3 from Beta's side: won GK/MID/FWD, lost DEF and the match.
+	if beta.Positions[1].MatchesCost != 1 || beta.Positions[0].Won != 1 {
+		t.Errorf("beta = %+v", beta.Positions)
+	}
+
+	// Focus by name; GW1 alone.
+	name := "beta"
+	out, err = buildPositionalEdge(cfg, PositionalEdgeArgs{LeagueID: 100, EntryName: &name, ThroughGW: 1})
+	if err != nil {
+		t.Fatalf("buildPositionalEdge(beta): %v", err)
+	}
+	if len(out.Entries) != 1 || out.Entries[0].EntryID != 201 || out.Entries[0].Costliest != "" {
+		t.Errorf("focus = %+v, want Beta only with no costly group after GW1", out.Entries)
+	}
+	if out.Entries[0].Positions[1].MatchesCarried != 1 {
+		t.Errorf("beta GW1 DEF carried = %d, want 1", out.Entries[0].Positions[1].MatchesCarried)
+	}
+}
+
+func TestBuildPositionalEdge_Errors(t *testing.T) {
+	dir, cfg := resourceCfg(t)
+	writePositionalEdgeFixture(t, dir)
+
+	if _, err := buildPositionalEdge(cfg, PositionalEdgeArgs{}); classifyError(err).Code != codeInvalidArgument {
+		t.Errorf("missing league_id: err = %v, want INVALID_ARGUMENT", err)
+	}
+	missing := 999
+	_, err := buildPositionalEdge(cfg, PositionalEdgeArgs{LeagueID: 100, EntryID: &missing, ThroughGW: 3})
+	if err == nil || classifyError(err).Code != codeNotFound {
+		t.Errorf("unknown entry: err = %v, want NOT_FOUND", err)
+	}
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"path/filepath"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/fixtures"
+)
+
+// fixtureSchedule maps each fixture bootstrap lists to its GW, 0 while it is
+// postponed without a new one. Bootstrap drops a GW once it has started, so
+// fixtures already played are usually missing from it.
+type fixtureSchedule map[int]int
+
+// loadFixtureSchedule reads the schedule from bootstrap; without a readable
+// bootstrap it is empty and moves nothing.
+func loadFixtureSchedule(rawRoot string) fixtureSchedule {
+	set, err := fixtures.Load(filepath.Join(rawRoot, "bootstrap", "bootstrap-static.json"))
+	if err != nil {
+		return nil
+	}
+	out := make(fixtureSchedule, len(set.Fixtures))
+	for _, f := range set.Fixtures {
+		out[f.ID] = f.Event
+	}
+	return out
+}
+
+// movedFrom reports whether a fixture that gw's live.json lists has been
+// postponed out of gw since the file was fetched: its own event says
+// another GW, or bootstrap now has it in another GW or in none.
+func (s fixtureSchedule) movedFrom(f fixture, gw int) bool {
+	if f.Event != 0 && f.Event != gw {
+		return true
+	}
+	event, ok := s[f.ID]
+	return ok && event != gw
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestBuildGWCalendar_PostponedFixture replays a mid-season postponement:
+// GW10's live.json was fetched while City v Liverpool was still on, then
+// bootstrap moved it out of GW10 with no new date. Both teams blank in
+// GW10 and the fixture shows in no GW.
+func TestBuildGWCalendar_PostponedFixture(t *testing.T) {
+	dir, cfg := resourceCfg(t)
+	writeCalendarFixture(t, dir)
+	writeJSON(t, filepath.Join(dir, "bootstrap", "bootstrap-static.json"), map[string]any{
+		"elements": []any{
+			map[string]any{"id": 1, "web_name": "Saka", "team": 1, "element_type": 3, "status": "a"},
+			map[string]any{"id": 2, "web_name": "Palmer", "team": 2, "element_type": 3, "status": "a"},
+			map[string]any{"id": 3, "web_name": "Salah", "team": 3, "element_type": 3, "status": "a"},
+			map[string]any{"id": 4, "web_name": "Haaland", "team": 4, "element_type": 4, "status": "a"},
+		},
+		"teams": []any{
+			map[string]any{"id": 1, "short_name": "ARS"},
+			map[string]any{"id": 2, "short_name": "CHE"},
+			map[string]any{"id": 3, "short_name": "LIV"},
+			map[string]any{"id": 4, "short_name": "MCI"},
+		},
+		"fixtures": map[string]any{
+			"null": []any{
+				map[string]any{"id": 101, "event": nil, "team_h": 4, "team_a": 3},
+			},
+			"11": []any{
+				map[string]any{"id": 111, "event": 11, "team_h": 1, "team_a": 2},
+				map[string]any{"id": 112, "event": 11, "team_h": 3, "team_a": 1},
+			},
+			"12": []any{
+				map[string]any{"id": 121, "event": 12, "team_h": 1, "team_a": 4},
+				map[string]any{"id": 122, "event": 12, "team_h": 2, "team_a": 3},
+			},
+		},
+	})
+
+	out, err := buildGWCalendar(cfg, GWCalendarArgs{LeagueID: 100})
+	if err != nil {
+		t.Fatalf("buildGWCalendar: %v", err)
+	}
+	want := map[string][]int{"ARS": {1, 2, 1}, "CHE": {1, 1, 1}, "LIV": {0, 1, 1}, "MCI": {0, 0, 1}}
+	for _, row := range out.Teams {
+		if !reflect.DeepEqual(row.Fixtures, want[row.Team]) {
+			t.Errorf("%s fixtures = %v, want %v", row.Team, row.Fixtures, want[row.Team])
+		}
+	}
+	wantBlanks := []CalendarGW{{GW: 10, Teams: []string{"LIV", "MCI"}}, {GW: 11, Teams: []string{"MCI"}}}
+	if !reflect.DeepEqual(out.Blanks, wantBlanks) {
+		t.Errorf("blanks = %+v, want %+v", out.Blanks, wantBlanks)
+	}
+}
+
+// TestComputePointsConcededByPosition_RescheduledFixture checks that a
+// fixture postponed from GW5 and played in GW7 counts once, from GW7's
+// data, even though GW5's stale live.json still lists it.
+func TestComputePointsConcededByPosition_RescheduledFixture(t *testing.T) {
+	dir := t.TempDir()
+	writeJSON(t, filepath.Join(dir, "gw", "5", "live.json"), map[string]any{
+		"elements": map[string]any{
+			"10": map[string]any{"stats": map[string]any{"minutes": 0, "total_points": 0}},
+		},
+		"fixtures": []any{
+			map[string]any{"id": 100, "event": 5, "team_h": 1, "team_a": 2},
+		},
+	})
+	writeJSON(t, filepath.Join(dir, "gw", "6", "live.json"), map[string]any{
+		"elements": map[string]any{},
+		"fixtures": []any{},
+	})
+	writeJSON(t, filepath.Join(dir, "gw", "7", "live.json"), map[string]any{
+		"elements": map[string]any{
+			"10": map[string]any{"stats": map[string]any{"minutes": 90, "total_points": 10}},
+		},
+		"fixtures": []any{
+			map[string]any{"id": 100, "event": 7, "team_h": 1, "team_a": 2},
+		},
+	})
+	elements := []elementInfo{{ID: 10, TeamID: 1, PositionType: 3}}
+
+	conceded := computePointsConcededByPosition(dir, elements, 7, 3)
+
+	if got := conceded[2]["AWAY"][3]; got.Count != 1 || got.Sum != 10 {
+		t.Errorf("team 2 AWAY MID conceded: sum=%.0f count=%d, want sum=10 count=1", got.Sum, got.Count)
+	}
+}
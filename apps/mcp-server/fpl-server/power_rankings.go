@@ -0,0 +1,127 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/summary"
+)
+
+// powerFormHorizon is the player-form window behind roster strength.
+const powerFormHorizon = 5
+
+// PowerRankingsArgs are the input arguments for the power_rankings tool.
+type PowerRankingsArgs struct {
+	LeagueID       int      `json:"league_id" jsonschema:"Draft league id (required)"`
+	GW             *int     `json:"gw,omitempty" jsonschema:"Gameweek (0 = latest finished)"`
+	WeightRecent   *float64 `json:"weight_recent,omitempty" jsonschema:"Weight for points for over the last 3 GWs (default 0.35)"`
+	WeightSeason   *float64 `json:"weight_season,omitempty" jsonschema:"Weight for season points for per GW (default 0.25)"`
+	WeightRoster   *float64 `json:"weight_roster,omitempty" jsonschema:"Weight for roster strength, the sum of rostered players' last-5 points per GW (default 0.25)"`
+	WeightSchedule *float64 `json:"weight_schedule,omitempty" jsonschema:"Weight for the all-play record (default 0.15)"`
+}
+
+// PowerRankingsOutput is the output of the power_rankings tool.
+type PowerRankingsOutput struct {
+	summary.PowerRankings
+	Notes  []string `json:"notes"`
+	GWNote *GWNote  `json:"gw_note,omitempty"`
+}
+
+func powerRankingsPath(leagueID int, gw int) string {
+	return fmt.Sprintf("summary/power_rankings/%d/gw/%d.json", leagueID, gw)
+}
+
+func buildPowerRankings(cfg ServerConfig, args PowerRankingsArgs) (PowerRankingsOutput, error) {
+	if args.LeagueID == 0 {
+		return PowerRankingsOutput{}, invalidArgumentf("league_id is required")
+	}
+	weights := summary.DefaultPowerWeights
+	for _, w := range []struct {
+		arg *float64
+		dst *float64
+	}{
+		{args.WeightRecent, &weights.Recent},
+		{args.WeightSeason, &weights.Season},
+		{args.WeightRoster, &weights.Roster},
+		{args.WeightSchedule, &weights.Schedule},
+	} {
+		if w.arg != nil {
+			*w.dst = *w.arg
+		}
+	}
+	if err := weights.Validate(); err != nil {
+		return PowerRankingsOutput{}, invalidArgumentf("%v", err)
+	}
+	weights = weights.Normalized()
+	defaultWeights := weights == summary.DefaultPowerWeights.Normalized()
+
+	requested := 0
+	if args.GW != nil {
+		requested = *args.GW
+	}
+	gw, note, err := resolveEffectiveGW(cfg, requested, gwModeLatestFinished)
+	if err != nil {
+		return PowerRankingsOutput{}, err
+	}
+	st := store.NewJSONStore(cfg.RawRoot)
+	ld, entryIDs, err := loadLeagueDetails(st, args.LeagueID)
+	if err != nil {
+		return PowerRankingsOutput{}, err
+	}
+	standings := summary.ComputeStandings(args.LeagueID, ld, entryIDs, gw, cfg.Tiebreakers[args.LeagueID])
+
+	ownership, err := loadOwnershipAtGW(cfg, args.LeagueID, gw)
+	if err != nil {
+		return PowerRankingsOutput{}, err
+	}
+	form, err := loadPlayerFormSummary(cfg, args.LeagueID, gw, powerFormHorizon)
+	if err != nil {
+		return PowerRankingsOutput{}, err
+	}
+	ppg := make(map[int]float64, len(form.Players))
+	for _, p := range form.Players {
+		ppg[p.Element] = p.PointsPerGW
+	}
+	strength := make(map[int]float64, len(ownership))
+	for entryID, roster := range ownership {
+		for element := range roster {
+			strength[entryID] += ppg[element]
+		}
+	}
+
+	out := PowerRankingsOutput{
+		PowerRankings: summary.ComputePowerRankings(args.LeagueID, gw, standings.Rows, strength, weights, summary.NewBuildStamp(cfg.Clock, st)),
+		Notes: []string{
+			"power_score = sum of weight x min-max position in the league for recent (last 3 GWs) and season points for per GW, roster strength and all-play percentage; components holds each term.",
+			fmt.Sprintf("Roster strength sums the last-%d points per GW of each player on the roster at the end of the GW.", powerFormHorizon),
+		},
+		GWNote: note,
+	}
+
+	if cfg.DerivedRoot == "" {
+		return out, nil
+	}
+	prevPath := filepath.Join(cfg.DerivedRoot, powerRankingsPath(args.LeagueID, gw-1))
+	switch raw, err := store.ReadDerived(prevPath); {
+	case err != nil:
+		out.Notes = append(out.Notes, fmt.Sprintf("No saved GW %d power ranking, so no movement; one is saved each time the default weights run.", gw-1))
+	default:
+		var prev summary.PowerRankings
+		if err := json.Unmarshal(raw, &prev); err != nil {
+			return PowerRankingsOutput{}, fmt.Errorf("parse GW %d power rankings: %w", gw-1, err)
+		}
+		if prev.Weights != weights {
+			out.Notes = append(out.Notes, fmt.Sprintf("The saved GW %d power ranking used other weights, so no movement.", gw-1))
+			break
+		}
+		summary.ApplyPowerMovement(&out.PowerRankings, prev)
+	}
+	// Only default-weight runs are kept, so next week's movement compares
+	// like with like; a failed write only costs that movement.
+	if defaultWeights && cfg.WriteDerived {
+		_ = store.WriteDerivedJSON(filepath.Join(cfg.DerivedRoot, powerRankingsPath(args.LeagueID, gw)), out.PowerRankings)
+	}
+	return out, nil
+}
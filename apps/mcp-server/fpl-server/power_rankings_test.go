@@ -0,0 +1,93 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/summary"
+)
+
+func TestBuildPowerRankings_MovementAcrossSavedWeeks(t *testing.T) {
+	dir, cfg := resourceCfg(t)
+	cfg.WriteDerived = true
+	writeFullGameJSON(t, dir, 2, true, 3, false, "")
+	// Alpha drafts Haaland; Beta drafts Salah and Alexander-Arnold.
+	writeDraftChoicesFixture(t, dir)
+	writeJSON(t, filepath.Join(dir, "league/100/transactions.json"), map[string]any{"transactions": []any{}})
+	writeJSON(t, filepath.Join(dir, "league/100/trades.json"), map[string]any{"trades": []any{}})
+	writeLeagueDetailsFixture(t, dir, 100, []any{
+		map[string]any{"id": 1, "entry_id": 200, "entry_name": "Alpha FC", "short_name": "AFC"},
+		map[string]any{"id": 2, "entry_id": 201, "entry_name": "Beta FC", "short_name": "BFC"},
+	}, []any{
+		map[string]any{"event": 1, "finished": true, "started": true, "league_entry_1": 1, "league_entry_1_points": 60, "league_entry_2": 2, "league_entry_2_points": 40},
+		map[string]any{"event": 2, "finished": true, "started": true, "league_entry_1": 1, "league_entry_1_points": 30, "league_entry_2": 2, "league_entry_2_points": 70},
+	})
+	writeJSON(t, filepath.Join(dir, "summary/player_form/100/h5.json"), summary.PlayerFormSummary{
+		Players: []summary.PlayerForm{
+			{Element: 1, PointsPerGW: 6},
+			{Element: 2, PointsPerGW: 5},
+			{Element: 3, PointsPerGW: 4},
+		},
+	})
+
+	gw := 1
+	week1, err := buildPowerRankings(cfg, PowerRankingsArgs{LeagueID: 100, GW: &gw})
+	if err != nil {
+		t.Fatalf("GW1: %v", err)
+	}
+	if got := week1.Rows[0]; got.EntryID != 200 || got.Movement != nil {
+		t.Fatalf("GW1 leader = %+v, want Alpha with no movement", got)
+	}
+	if _, err := os.Stat(filepath.Join(dir, powerRankingsPath(100, 1))); err != nil {
+		t.Fatalf("GW1 ranking not saved: %v", err)
+	}
+
+	// Beta outscores Alpha over the season by GW2 and has the stronger
+	// roster (10 pts/GW to 5).
+	gw = 2
+	week2, err := buildPowerRankings(cfg, PowerRankingsArgs{LeagueID: 100, GW: &gw})
+	if err != nil {
+		t.Fatalf("GW2: %v", err)
+	}
+	want := []struct{ entry, prev, movement int }{{201, 2, 1}, {200, 1, -1}}
+	for i, w := range want {
+		r := week2.Rows[i]
+		if r.EntryID != w.entry || r.Rank != i+1 || r.PrevRank != w.prev || r.Movement == nil || *r.Movement != w.movement {
+			t.Errorf("GW2 row %d = %+v, want entry %d prev %d movement %d", i, r, w.entry, w.prev, w.movement)
+		}
+	}
+	if r := week2.Rows[0]; r.RosterStrength != 10 {
+		t.Errorf("Beta roster strength = %v, want 10", r.RosterStrength)
+	}
+
+	// Other weights neither compare with nor overwrite the saved ranking.
+	recent := 1.0
+	zero := 0.0
+	custom, err := buildPowerRankings(cfg, PowerRankingsArgs{LeagueID: 100, GW: &gw, WeightRecent: &recent, WeightSeason: &zero, WeightRoster: &zero, WeightSchedule: &zero})
+	if err != nil {
+		t.Fatalf("custom weights: %v", err)
+	}
+	for _, r := range custom.Rows {
+		if r.Movement != nil {
+			t.Errorf("custom-weight row %+v has movement", r)
+		}
+	}
+	saved, err := os.ReadFile(filepath.Join(dir, powerRankingsPath(100, 2)))
+	if err != nil {
+		t.Fatalf("GW2 ranking not saved: %v", err)
+	}
+	if !strings.Contains(string(saved), `"recent": 0.35`) {
+		t.Errorf("saved GW2 ranking was overwritten by a custom-weight run: %s", saved)
+	}
+}
+
+func TestBuildPowerRankings_InvalidWeights(t *testing.T) {
+	_, cfg := resourceCfg(t)
+	neg := -0.5
+	_, err := buildPowerRankings(cfg, PowerRankingsArgs{LeagueID: 100, WeightRoster: &neg})
+	if got := classifyError(err).Code; got != codeInvalidArgument {
+		t.Errorf("code = %s (%v), want INVALID_ARGUMENT", got, err)
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/projection"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/scoring"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+)
+
+// parseProjectionModel validates a tool's model argument.
+func parseProjectionModel(model string) (string, error) {
+	m, err := projection.ParseModel(model)
+	if err != nil {
+		return "", invalidArgumentf("%v", err)
+	}
+	return m, nil
+}
+
+func projectionPlayers(elements []elementInfo) map[int]projection.Player {
+	out := make(map[int]projection.Player, len(elements))
+	for _, e := range elements {
+		out[e.ID] = projection.Player{ID: e.ID, TeamID: e.TeamID, Position: e.PositionType}
+	}
+	return out
+}
+
+// projectionEnv adapts the fixture index and multiplier the tools already
+// build into a projection.Env.
+func projectionEnv(elements []elementInfo, indexByGW map[int]map[int][]FixtureContext, mult outlookMultiplier, history map[int]projection.History, rules scoring.ScoringRules) projection.Env {
+	fixtures := make(map[int]map[int][]projection.Fixture, len(indexByGW))
+	for gw, byTeam := range indexByGW {
+		fixtures[gw] = make(map[int][]projection.Fixture, len(byTeam))
+		for team, contexts := range byTeam {
+			for _, ctx := range contexts {
+				fixtures[gw][team] = append(fixtures[gw][team], projection.Fixture{ID: ctx.FixtureID, OpponentID: ctx.OpponentID, Venue: ctx.Venue})
+			}
+		}
+	}
+	return projection.Env{
+		Players:  projectionPlayers(elements),
+		Fixtures: fixtures,
+		Strength: projection.Strength(mult),
+		History:  history,
+		Rules:    rules,
+	}
+}
+
+// loadProjectionModel builds the named model (already validated by
+// parseProjectionModel) from form over the window GWs ending at asOfGW,
+// scoring under rules. The history is returned too for callers that show
+// the per-fixture baseline.
+func loadProjectionModel(rawRoot string, name string, elements []elementInfo, indexByGW map[int]map[int][]FixtureContext, mult outlookMultiplier, asOfGW int, window int, rules scoring.ScoringRules) (projection.Model, map[int]projection.History, error) {
+	history := projection.LoadHistory(store.NewJSONStore(rawRoot), projectionPlayers(elements), asOfGW, window, rules)
+	model, err := projection.New(name, projectionEnv(elements, indexByGW, mult, history, rules))
+	if err != nil {
+		return nil, nil, invalidArgumentf("%v", err)
+	}
+	return model, history, nil
+}
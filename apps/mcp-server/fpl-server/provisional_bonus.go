@@ -0,0 +1,192 @@
+package main
+
+import (
+	"sort"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/livestats"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+)
+
+// ProvisionalBonusArgs are the input arguments for the provisional_bonus tool.
+type ProvisionalBonusArgs struct {
+	LeagueID int `json:"league_id" jsonschema:"Draft league id (required)"`
+	GW       int `json:"gw" jsonschema:"Gameweek (0 = current)"`
+}
+
+// BonusPlayer is one player in a bonus place. For a provisional fixture
+// ProjectedPoints adds the bonus to TotalPoints; once confirmed the bonus is
+// already part of TotalPoints.
+type BonusPlayer struct {
+	Element         int    `json:"element"`
+	Name            string `json:"name"`
+	Team            string `json:"team"`
+	Fixture         int    `json:"fixture"`
+	BPS             int    `json:"bps"`
+	Status          string `json:"status"`
+	Bonus           int    `json:"bonus"`
+	TotalPoints     int    `json:"total_points"`
+	ProjectedPoints int    `json:"projected_points"`
+	OwnerEntryID    int    `json:"owner_entry_id,omitempty"`
+	OwnerName       string `json:"owner_name,omitempty"`
+}
+
+// BonusFixture is the bonus table for one started fixture.
+type BonusFixture struct {
+	Fixture  int           `json:"fixture"`
+	Home     string        `json:"home"`
+	Away     string        `json:"away"`
+	Finished bool          `json:"finished"`
+	Status   string        `json:"status"`
+	Players  []BonusPlayer `json:"players"`
+}
+
+// EntryBonus totals the bonus going to one manager's rostered players.
+type EntryBonus struct {
+	EntryID     int           `json:"entry_id"`
+	EntryName   string        `json:"entry_name"`
+	Provisional int           `json:"provisional"`
+	Confirmed   int           `json:"confirmed"`
+	Players     []BonusPlayer `json:"players"`
+}
+
+// ProvisionalBonusOutput is the result of the provisional_bonus tool.
+type ProvisionalBonusOutput struct {
+	LeagueID int            `json:"league_id"`
+	GW       int            `json:"gw"`
+	GWNote   *GWNote        `json:"gw_note,omitempty"`
+	Fixtures []BonusFixture `json:"fixtures"`
+	Entries  []EntryBonus   `json:"entries"`
+	Notes    []string       `json:"notes"`
+}
+
+func buildProvisionalBonus(cfg ServerConfig, args ProvisionalBonusArgs) (ProvisionalBonusOutput, error) {
+	if args.LeagueID == 0 {
+		return ProvisionalBonusOutput{}, invalidArgumentf("league_id is required")
+	}
+	gw, note, err := resolveEffectiveGW(cfg, args.GW, gwModeLatestFinished)
+	if err != nil {
+		return ProvisionalBonusOutput{}, err
+	}
+	st := store.NewJSONStore(cfg.RawRoot)
+	live, err := livestats.LoadGW(st, gw)
+	if err != nil {
+		return ProvisionalBonusOutput{}, err
+	}
+	elements, teamShort, _, err := loadBootstrapData(cfg.RawRoot)
+	if err != nil {
+		return ProvisionalBonusOutput{}, err
+	}
+	ownership, err := loadOwnershipAtGW(cfg, args.LeagueID, gw)
+	if err != nil {
+		return ProvisionalBonusOutput{}, err
+	}
+	ld, _, err := loadLeagueDetails(st, args.LeagueID)
+	if err != nil {
+		return ProvisionalBonusOutput{}, err
+	}
+
+	info := make(map[int]elementInfo, len(elements))
+	teamOf := make(map[int]int, len(elements))
+	for _, e := range elements {
+		info[e.ID] = e
+		teamOf[e.ID] = e.TeamID
+	}
+	owner := make(map[int]int)
+	for entryID, owned := range ownership {
+		for element := range owned {
+			owner[element] = entryID
+		}
+	}
+	entries := make(map[int]*EntryBonus, len(ld.LeagueEntries))
+	for _, e := range ld.LeagueEntries {
+		entries[e.EntryID] = &EntryBonus{EntryID: e.EntryID, EntryName: e.EntryName, Players: make([]BonusPlayer, 0)}
+	}
+
+	out := ProvisionalBonusOutput{
+		LeagueID: args.LeagueID,
+		GW:       gw,
+		GWNote:   note,
+		Fixtures: make([]BonusFixture, 0),
+		Entries:  make([]EntryBonus, 0, len(entries)),
+		Notes:    make([]string, 0),
+	}
+	provisional := 0
+	for _, fb := range livestats.ProvisionalBonus(live, teamOf) {
+		f := BonusFixture{
+			Fixture:  fb.Fixture,
+			Home:     teamShort[fb.TeamH],
+			Away:     teamShort[fb.TeamA],
+			Finished: fb.Finished,
+			Status:   fb.Status,
+			Players:  make([]BonusPlayer, 0, len(fb.Bonus)),
+		}
+		if fb.Status == livestats.BonusProvisional {
+			provisional++
+		}
+		for element, bonus := range fb.Bonus {
+			entryID := owner[element]
+			total := live.Elements[element].TotalPoints
+			p := BonusPlayer{
+				Element:         element,
+				Name:            info[element].Name,
+				Team:            teamShort[info[element].TeamID],
+				Fixture:         fb.Fixture,
+				BPS:             fb.BPS[element],
+				Status:          fb.Status,
+				Bonus:           bonus,
+				TotalPoints:     total,
+				ProjectedPoints: total,
+				OwnerEntryID:    entryID,
+			}
+			if fb.Status == livestats.BonusProvisional {
+				p.ProjectedPoints += bonus
+			}
+			if e := entries[entryID]; e != nil {
+				p.OwnerName = e.EntryName
+				e.Players = append(e.Players, p)
+				if fb.Status == livestats.BonusProvisional {
+					e.Provisional += bonus
+				} else {
+					e.Confirmed += bonus
+				}
+			}
+			f.Players = append(f.Players, p)
+		}
+		sortBonusPlayers(f.Players)
+		out.Fixtures = append(out.Fixtures, f)
+	}
+
+	for _, e := range entries {
+		sortBonusPlayers(e.Players)
+		out.Entries = append(out.Entries, *e)
+	}
+	sort.Slice(out.Entries, func(i, j int) bool {
+		a, b := out.Entries[i], out.Entries[j]
+		if a.Provisional+a.Confirmed != b.Provisional+b.Confirmed {
+			return a.Provisional+a.Confirmed > b.Provisional+b.Confirmed
+		}
+		return a.EntryID < b.EntryID
+	})
+
+	switch {
+	case len(out.Fixtures) == 0:
+		out.Notes = append(out.Notes, "No fixtures have started in this gameweek yet.")
+	case provisional > 0:
+		out.Notes = append(out.Notes, "Provisional bonus is the 3/2/1 the current BPS would earn and is not yet in total_points; it can change until FPL confirms the fixture's bonus.")
+	}
+	return out, nil
+}
+
+// sortBonusPlayers orders players by bonus, then BPS, then element id.
+func sortBonusPlayers(players []BonusPlayer) {
+	sort.Slice(players, func(i, j int) bool {
+		a, b := players[i], players[j]
+		if a.Bonus != b.Bonus {
+			return a.Bonus > b.Bonus
+		}
+		if a.BPS != b.BPS {
+			return a.BPS > b.BPS
+		}
+		return a.Element < b.Element
+	})
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestBuildProvisionalBonus(t *testing.T) {
+	dir, cfg := resourceCfg(t)
+	writeClaimFixture(t, dir)
+	played := func(points, bps int) map[string]any {
+		return map[string]any{"stats": map[string]any{"minutes": 90, "total_points": points, "bps": bps}}
+	}
+	bpsRow := func(element, value int) map[string]any {
+		return map[string]any{"element": element, "value": value}
+	}
+	writeJSON(t, filepath.Join(dir, "gw", "3", "live.json"), map[string]any{
+		"elements": map[string]any{
+			"1": played(8, 30), "4": played(6, 30), "10": played(2, 18), "11": played(1, 5),
+			"6": played(13, 40), "5": map[string]any{"stats": map[string]any{"minutes": 90, "total_points": 9, "bonus": 2, "bps": 28}},
+		},
+		"fixtures": []any{
+			// Salah and Saka tie for first, so Mbeumo takes the 1.
+			map[string]any{"id": 1, "team_h": 10, "team_a": 20, "started": true, "finished": false, "stats": []any{
+				map[string]any{"identifier": "bps", "h": []any{bpsRow(1, 30), bpsRow(4, 30)}, "a": []any{bpsRow(10, 18), bpsRow(11, 5)}},
+			}},
+			map[string]any{"id": 2, "team_h": 30, "team_a": 40, "started": true, "finished": true, "stats": []any{
+				map[string]any{"identifier": "bonus", "h": []any{bpsRow(6, 3)}, "a": []any{bpsRow(5, 2)}},
+				map[string]any{"identifier": "bps", "h": []any{bpsRow(6, 40)}, "a": []any{bpsRow(5, 28)}},
+			}},
+			map[string]any{"id": 3, "team_h": 50, "team_a": 60, "started": false},
+		},
+	})
+
+	out, err := buildProvisionalBonus(cfg, ProvisionalBonusArgs{LeagueID: 100, GW: 3})
+	if err != nil {
+		t.Fatalf("buildProvisionalBonus: %v", err)
+	}
+	if out.GW != 3 || len(out.Fixtures) != 2 || len(out.Notes) != 1 {
+		t.Fatalf("gw %d fixtures %+v notes %v, want the 2 started fixtures and a provisional note", out.GW, out.Fixtures, out.Notes)
+	}
+
+	f1 := out.Fixtures[0]
+	if f1.Status != "provisional" || f1.Home != "LIV" || len(f1.Players) != 3 {
+		t.Fatalf("fixture 1 = %+v", f1)
+	}
+	want := []struct{ element, bonus, projected int }{{1, 3, 11}, {4, 3, 9}, {10, 1, 3}}
+	for i, w := range want {
+		p := f1.Players[i]
+		if p.Element != w.element || p.Bonus != w.bonus || p.ProjectedPoints != w.projected {
+			t.Errorf("fixture 1 player %d = %+v, want element %d bonus %d projected %d", i, p, w.element, w.bonus, w.projected)
+		}
+	}
+	if f1.Players[0].OwnerName != "Alpha FC" || f1.Players[2].OwnerEntryID != 0 {
+		t.Errorf("owners = %+v", f1.Players)
+	}
+
+	// Confirmed bonus is already in total_points, so nothing is added.
+	f2 := out.Fixtures[1]
+	if f2.Status != "confirmed" || len(f2.Players) != 2 || f2.Players[0].Element != 6 || f2.Players[0].ProjectedPoints != 13 {
+		t.Errorf("fixture 2 = %+v", f2)
+	}
+
+	totals := map[int][2]int{}
+	for _, e := range out.Entries {
+		totals[e.EntryID] = [2]int{e.Provisional, e.Confirmed}
+	}
+	if totals[200] != [2]int{3, 3} || totals[201] != [2]int{3, 0} || totals[202] != [2]int{0, 2} {
+		t.Errorf("entry totals = %v", totals)
+	}
+	if out.Entries[0].EntryID != 200 {
+		t.Errorf("entries not sorted by total bonus: %+v", out.Entries)
+	}
+}
+
+func TestBuildProvisionalBonus_Errors(t *testing.T) {
+	dir, cfg := resourceCfg(t)
+	writeClaimFixture(t, dir)
+	if _, err := buildProvisionalBonus(cfg, ProvisionalBonusArgs{}); classifyError(err).Code != codeInvalidArgument {
+		t.Errorf("missing league: err = %v", err)
+	}
+	if _, err := buildProvisionalBonus(cfg, ProvisionalBonusArgs{LeagueID: 100, GW: 3}); classifyError(err).Code != codeDataMissing {
+		t.Errorf("missing live.json: err = %v", err)
+	}
+}
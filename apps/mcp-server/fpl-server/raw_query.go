@@ -0,0 +1,132 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/rawquery"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+)
+
+// raw_query limits: a projection is for a field or two, not a file dump.
+const (
+	rawQueryMaxFields = 20
+	rawQueryMaxBytes  = 256 << 10
+)
+
+// RawQueryArgs are the input arguments for the raw_query tool.
+type RawQueryArgs struct {
+	Endpoint string   `json:"endpoint" jsonschema:"Raw file: bootstrap|league_details|transactions|trades|game|gw_live|entry_event (required)"`
+	LeagueID int      `json:"league_id,omitempty" jsonschema:"Draft league id (league_details, transactions, trades)"`
+	EntryID  int      `json:"entry_id,omitempty" jsonschema:"Entry id (entry_event)"`
+	GW       int      `json:"gw,omitempty" jsonschema:"Gameweek (gw_live, entry_event)"`
+	Fields   []string `json:"fields" jsonschema:"Dot-paths to return, e.g. elements[].web_name, teams[].strength_overall_home, elements.*.stats.minutes; key[] steps into an array, * into every value (required)"`
+}
+
+// RawQueryOutput is the output of the raw_query tool. Data is the raw file
+// cut down to the requested fields, in the file's own shape.
+type RawQueryOutput struct {
+	Endpoint  string   `json:"endpoint"`
+	Path      string   `json:"path"`
+	Fields    []string `json:"fields"`
+	Unmatched []string `json:"unmatched"`
+	Bytes     int      `json:"bytes"`
+	Data      any      `json:"data"`
+}
+
+// rawQueryEndpoints maps each endpoint to its raw file, checking the
+// parameters that name it.
+var rawQueryEndpoints = map[string]func(args RawQueryArgs) (string, error){
+	"bootstrap": func(RawQueryArgs) (string, error) { return "bootstrap/bootstrap-static.json", nil },
+	"game":      func(RawQueryArgs) (string, error) { return "game/game.json", nil },
+	"league_details": func(a RawQueryArgs) (string, error) {
+		return leagueRawPath(a, "details")
+	},
+	"transactions": func(a RawQueryArgs) (string, error) {
+		return leagueRawPath(a, "transactions")
+	},
+	"trades": func(a RawQueryArgs) (string, error) {
+		return leagueRawPath(a, "trades")
+	},
+	"gw_live": func(a RawQueryArgs) (string, error) {
+		if a.GW <= 0 {
+			return "", invalidArgumentf("gw is required for gw_live")
+		}
+		return fmt.Sprintf("gw/%d/live.json", a.GW), nil
+	},
+	"entry_event": func(a RawQueryArgs) (string, error) {
+		if a.EntryID <= 0 || a.GW <= 0 {
+			return "", invalidArgumentf("entry_id and gw are required for entry_event")
+		}
+		return fmt.Sprintf("entry/%d/gw/%d.json", a.EntryID, a.GW), nil
+	},
+}
+
+func leagueRawPath(a RawQueryArgs, name string) (string, error) {
+	if a.LeagueID <= 0 {
+		return "", invalidArgumentf("league_id is required for this endpoint")
+	}
+	return fmt.Sprintf("league/%d/%s.json", a.LeagueID, name), nil
+}
+
+func buildRawQuery(cfg ServerConfig, args RawQueryArgs) (RawQueryOutput, error) {
+	endpoint := strings.ToLower(strings.TrimSpace(args.Endpoint))
+	resolve, ok := rawQueryEndpoints[endpoint]
+	if !ok {
+		names := make([]string, 0, len(rawQueryEndpoints))
+		for name := range rawQueryEndpoints {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return RawQueryOutput{}, invalidArgumentf("unknown endpoint %q (want one of %s)", args.Endpoint, strings.Join(names, ", "))
+	}
+	if len(args.Fields) == 0 {
+		return RawQueryOutput{}, invalidArgumentf("fields is required: list the dot-paths to return")
+	}
+	if len(args.Fields) > rawQueryMaxFields {
+		return RawQueryOutput{}, invalidArgumentf("at most %d fields per query, got %d", rawQueryMaxFields, len(args.Fields))
+	}
+	paths := make([]rawquery.Path, 0, len(args.Fields))
+	for _, f := range args.Fields {
+		p, err := rawquery.Parse(strings.TrimSpace(f))
+		if err != nil {
+			return RawQueryOutput{}, invalidArgumentf("%v", err)
+		}
+		paths = append(paths, p)
+	}
+	rel, err := resolve(args)
+	if err != nil {
+		return RawQueryOutput{}, err
+	}
+
+	raw, err := store.NewJSONStore(cfg.RawRoot).ReadRaw(rel)
+	if err != nil {
+		return RawQueryOutput{}, err
+	}
+	var doc any
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return RawQueryOutput{}, fmt.Errorf("parse %s: %w", rel, err)
+	}
+	data, unmatched := rawquery.Project(doc, paths)
+	b, err := json.Marshal(data)
+	if err != nil {
+		return RawQueryOutput{}, err
+	}
+	if len(b) > rawQueryMaxBytes {
+		return RawQueryOutput{}, invalidArgumentf("the projection is %d bytes, over the %d-byte limit; ask for fewer fields or narrow a * to one key", len(b), rawQueryMaxBytes)
+	}
+	fields := make([]string, len(paths))
+	for i, p := range paths {
+		fields[i] = p.String()
+	}
+	return RawQueryOutput{
+		Endpoint:  endpoint,
+		Path:      rel,
+		Fields:    fields,
+		Unmatched: unmatched,
+		Bytes:     len(b),
+		Data:      data,
+	}, nil
+}
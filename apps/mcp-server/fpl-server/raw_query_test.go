@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBuildRawQuery(t *testing.T) {
+	dir, cfg := resourceCfg(t)
+	writeJSON(t, filepath.Join(dir, "bootstrap", "bootstrap-static.json"), map[string]any{
+		"elements": []any{
+			map[string]any{"id": 1, "web_name": "Salah", "squad_number": 11},
+			map[string]any{"id": 2, "web_name": "Haaland", "squad_number": 9},
+		},
+		"teams": []any{map[string]any{"id": 10, "short_name": "LIV", "strength_overall_home": 1340}},
+	})
+	writeJSON(t, filepath.Join(dir, "entry/200/gw/3.json"), map[string]any{
+		"picks": []any{map[string]any{"element": 1, "position": 1}},
+	})
+
+	out, err := buildRawQuery(cfg, RawQueryArgs{Endpoint: "bootstrap", Fields: []string{"elements[].squad_number", "elements[].web_name", "teams[].nope"}})
+	if err != nil {
+		t.Fatalf("buildRawQuery: %v", err)
+	}
+	data, _ := out.Data.(map[string]any)
+	elements, _ := data["elements"].([]any)
+	if len(elements) != 2 || len(data) != 1 || out.Path != "bootstrap/bootstrap-static.json" {
+		t.Fatalf("data = %v, want only elements", out.Data)
+	}
+	if e := elements[1].(map[string]any); e["web_name"] != "Haaland" || e["squad_number"] != float64(9) || e["id"] != nil {
+		t.Errorf("element 2 = %v, want web_name and squad_number only", e)
+	}
+	if len(out.Unmatched) != 1 || out.Unmatched[0] != "teams[].nope" || out.Bytes == 0 {
+		t.Errorf("unmatched = %v bytes = %d", out.Unmatched, out.Bytes)
+	}
+
+	out, err = buildRawQuery(cfg, RawQueryArgs{Endpoint: "entry_event", EntryID: 200, GW: 3, Fields: []string{"picks[].element"}})
+	if err != nil || out.Path != "entry/200/gw/3.json" {
+		t.Errorf("entry_event = %+v, %v", out, err)
+	}
+
+	manyFields := make([]string, rawQueryMaxFields+1)
+	for i := range manyFields {
+		manyFields[i] = fmt.Sprintf("f%d", i)
+	}
+	for _, c := range []struct {
+		name string
+		args RawQueryArgs
+		code errorCode
+	}{
+		{"unknown endpoint", RawQueryArgs{Endpoint: "fixtures", Fields: []string{"a"}}, codeInvalidArgument},
+		{"no fields", RawQueryArgs{Endpoint: "bootstrap"}, codeInvalidArgument},
+		{"wildcard only", RawQueryArgs{Endpoint: "bootstrap", Fields: []string{"*"}}, codeInvalidArgument},
+		{"too many fields", RawQueryArgs{Endpoint: "bootstrap", Fields: manyFields}, codeInvalidArgument},
+		{"league endpoint without league", RawQueryArgs{Endpoint: "trades", Fields: []string{"trades[].id"}}, codeInvalidArgument},
+		{"gw_live without gw", RawQueryArgs{Endpoint: "gw_live", Fields: []string{"elements"}}, codeInvalidArgument},
+		{"file not fetched", RawQueryArgs{Endpoint: "gw_live", GW: 9, Fields: []string{"elements"}}, codeDataMissing},
+	} {
+		if _, err := buildRawQuery(cfg, c.args); classifyError(err).Code != c.code {
+			t.Errorf("%s: err = %v, want %s", c.name, err, c.code)
+		}
+	}
+}
+
+func TestBuildRawQuery_SizeLimit(t *testing.T) {
+	dir, cfg := resourceCfg(t)
+	big := make([]any, 0, 3000)
+	for i := 0; i < 3000; i++ {
+		big = append(big, map[string]any{"id": i, "news": strings.Repeat("x", 100)})
+	}
+	writeJSON(t, filepath.Join(dir, "bootstrap", "bootstrap-static.json"), map[string]any{"elements": big})
+	if _, err := buildRawQuery(cfg, RawQueryArgs{Endpoint: "bootstrap", Fields: []string{"elements[].news"}}); classifyError(err).Code != codeInvalidArgument || !strings.Contains(err.Error(), "limit") {
+		t.Errorf("err = %v, want the size limit", err)
+	}
+	if out, err := buildRawQuery(cfg, RawQueryArgs{Endpoint: "bootstrap", Fields: []string{"elements[].id"}}); err != nil || out.Bytes > rawQueryMaxBytes {
+		t.Errorf("ids only: %v", err)
+	}
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// recoLogTopN is how many adds and drops each reco_log line keeps.
+const recoLogTopN = 3
+
+// recoLogMu serialises appends so concurrent waiver_recommendations calls
+// can't interleave partial lines.
+var recoLogMu sync.Mutex
+
+// RecoLogEntry is one waiver_recommendations run as recorded in
+// reco_log/{league}.jsonl.
+type RecoLogEntry struct {
+	LeagueID int           `json:"league_id"`
+	EntryID  int           `json:"entry_id"`
+	AsOfGW   int           `json:"as_of_gw"`
+	TargetGW int           `json:"target_gw"`
+	LoggedAt string        `json:"logged_at"`
+	Adds     []RecoLogPick `json:"adds"`
+	Drops    []RecoLogPick `json:"drops"`
+}
+
+// RecoLogPick is a recommended player. For an add, SuggestedDrop is the
+// element the report paired it with (0 when there was none).
+type RecoLogPick struct {
+	Element           int     `json:"element"`
+	Name              string  `json:"name"`
+	PositionType      int     `json:"position_type"`
+	Score             float64 `json:"score"`
+	SuggestedDrop     int     `json:"suggested_drop,omitempty"`
+	SuggestedDropName string  `json:"suggested_drop_name,omitempty"`
+}
+
+func recoLogPath(derivedRoot string, leagueID int) string {
+	return filepath.Join(derivedRoot, "reco_log", fmt.Sprintf("%d.jsonl", leagueID))
+}
+
+// recoLogEntryFor keeps the top adds and drops of report.
+func recoLogEntryFor(report WaiverRecommendationsReport, now time.Time) RecoLogEntry {
+	e := RecoLogEntry{
+		LeagueID: report.LeagueID,
+		EntryID:  report.EntryID,
+		AsOfGW:   report.AsOfGW,
+		TargetGW: report.TargetGW,
+		LoggedAt: now.UTC().Format(time.RFC3339),
+		Adds:     []RecoLogPick{},
+		Drops:    []RecoLogPick{},
+	}
+	for i, a := range report.Adds {
+		if i == recoLogTopN {
+			break
+		}
+		pick := RecoLogPick{Element: a.Element, Name: a.Name, PositionType: a.PositionType, Score: a.Score.WeightedScore}
+		if a.SuggestedDrop != nil {
+			pick.SuggestedDrop = a.SuggestedDrop.Element
+			pick.SuggestedDropName = a.SuggestedDrop.Name
+		}
+		e.Adds = append(e.Adds, pick)
+	}
+	for i, d := range report.Drops {
+		if i == recoLogTopN {
+			break
+		}
+		e.Drops = append(e.Drops, RecoLogPick{Element: d.Element, Name: d.Name, PositionType: d.PositionType, Score: d.Score})
+	}
+	return e
+}
+
+// appendRecoLog appends one line for e to the league's log. The log is
+// append-only; readRecoLog collapses repeated runs for the same entry and GW.
+func appendRecoLog(derivedRoot string, e RecoLogEntry) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	path := recoLogPath(derivedRoot, e.LeagueID)
+	recoLogMu.Lock()
+	defer recoLogMu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(b, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// readRecoLog returns the logged runs for a league, keeping only the latest
+// run per (entry, target GW) so a manager who asks several times before a
+// deadline is counted once. Order is first appearance in the log. skipped
+// counts lines that didn't decode.
+func readRecoLog(derivedRoot string, leagueID int) (entries []RecoLogEntry, skipped int, err error) {
+	path := recoLogPath(derivedRoot, leagueID)
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, 0, &codedError{
+				Code:    codeDataMissing,
+				Message: fmt.Sprintf("no recommendations logged for league %d", leagueID),
+				Details: map[string]any{"path": path, "hint": "waiver_recommendations logs its picks while the server runs with --write-derived"},
+				Err:     err,
+			}
+		}
+		return nil, 0, err
+	}
+	defer f.Close()
+
+	type key struct{ entry, gw int }
+	index := make(map[key]int)
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 64<<10), 1<<20)
+	for sc.Scan() {
+		line := sc.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var e RecoLogEntry
+		if err := json.Unmarshal(line, &e); err != nil || e.EntryID == 0 || e.TargetGW == 0 {
+			skipped++
+			continue
+		}
+		k := key{e.EntryID, e.TargetGW}
+		if i, ok := index[k]; ok {
+			entries[i] = e
+			continue
+		}
+		index[k] = len(entries)
+		entries = append(entries, e)
+	}
+	return entries, skipped, sc.Err()
+}
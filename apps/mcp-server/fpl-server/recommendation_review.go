@@ -0,0 +1,299 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/livestats"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+)
+
+// reviewBestWorst is how many best and worst calls recommendation_review
+// lists.
+const reviewBestWorst = 3
+
+// reviewBuckets is how many equal-width calibration buckets split the
+// [0, 1] weighted-score range.
+const reviewBuckets = 5
+
+type RecommendationReviewArgs struct {
+	LeagueID int  `json:"league_id" jsonschema:"Draft league id (required)"`
+	EntryID  *int `json:"entry_id,omitempty" jsonschema:"Only this entry's recommendations"`
+	Horizon  *int `json:"horizon,omitempty" jsonschema:"Finished GWs from the target GW to score each call over (default 3)"`
+}
+
+// ReviewedCall is one logged add judged against its suggested drop. Margin is
+// AddPoints - DropPoints over the finished GWs in the window; a call is a hit
+// when the add outscored the drop.
+type ReviewedCall struct {
+	EntryID     int     `json:"entry_id"`
+	EntryName   string  `json:"entry_name,omitempty"`
+	TargetGW    int     `json:"target_gw"`
+	Element     int     `json:"element"`
+	Name        string  `json:"name"`
+	Score       float64 `json:"score"`
+	AddPoints   int     `json:"add_points"`
+	DropElement int     `json:"drop_element,omitempty"`
+	DropName    string  `json:"drop_name,omitempty"`
+	DropPoints  int     `json:"drop_points"`
+	Margin      int     `json:"margin"`
+	Hit         bool    `json:"hit"`
+	// Acted is set when the entry claimed the player between the as-of and
+	// target GWs. DroppedSuggested is set when that claim released the
+	// suggested drop.
+	Acted            bool  `json:"acted"`
+	DroppedSuggested bool  `json:"dropped_suggested,omitempty"`
+	GWsScored        []int `json:"gws_scored"`
+}
+
+// ReviewHitRate aggregates a set of calls. ActedHitRate covers only the calls
+// the manager followed.
+type ReviewHitRate struct {
+	Calls        int     `json:"calls"`
+	Hits         int     `json:"hits"`
+	HitRate      float64 `json:"hit_rate"`
+	AvgMargin    float64 `json:"avg_margin"`
+	Acted        int     `json:"acted"`
+	ActedRate    float64 `json:"acted_rate"`
+	ActedHits    int     `json:"acted_hits"`
+	ActedHitRate float64 `json:"acted_hit_rate"`
+}
+
+type EntryReview struct {
+	EntryID   int           `json:"entry_id"`
+	EntryName string        `json:"entry_name,omitempty"`
+	Record    ReviewHitRate `json:"record"`
+}
+
+// ScoreBucket groups calls by the weighted score they were made with, so a
+// well-calibrated model shows hit rate rising with score.
+type ScoreBucket struct {
+	MinScore float64       `json:"min_score"`
+	MaxScore float64       `json:"max_score"`
+	Record   ReviewHitRate `json:"record"`
+}
+
+type RecommendationReviewOutput struct {
+	LeagueID         int            `json:"league_id"`
+	Horizon          int            `json:"horizon"`
+	LatestFinishedGW int            `json:"latest_finished_gw"`
+	LoggedRuns       int            `json:"logged_runs"`
+	Pending          int            `json:"pending"`
+	League           ReviewHitRate  `json:"league"`
+	Entries          []EntryReview  `json:"entries"`
+	BestCalls        []ReviewedCall `json:"best_calls"`
+	WorstCalls       []ReviewedCall `json:"worst_calls"`
+	Calibration      []ScoreBucket  `json:"calibration"`
+	Calls            []ReviewedCall `json:"calls"`
+	Notes            []string       `json:"notes"`
+}
+
+func (r *ReviewHitRate) add(c ReviewedCall) {
+	// AvgMargin holds the running total until finish.
+	r.Calls++
+	r.AvgMargin += float64(c.Margin)
+	if c.Hit {
+		r.Hits++
+	}
+	if c.Acted {
+		r.Acted++
+		if c.Hit {
+			r.ActedHits++
+		}
+	}
+}
+
+func (r *ReviewHitRate) finish() {
+	if r.Calls == 0 {
+		return
+	}
+	n := float64(r.Calls)
+	r.HitRate = float64(r.Hits) / n
+	r.AvgMargin /= n
+	r.ActedRate = float64(r.Acted) / n
+	if r.Acted > 0 {
+		r.ActedHitRate = float64(r.ActedHits) / float64(r.Acted)
+	}
+}
+
+// scoreBucketIndex places a weighted score in its calibration bucket. Scores
+// are min-max blends so they sit in [0, 1]; anything outside is clamped.
+func scoreBucketIndex(score float64) int {
+	switch {
+	case math.IsNaN(score) || score < 0:
+		return 0
+	case score >= 1:
+		return reviewBuckets - 1
+	}
+	return int(score * reviewBuckets)
+}
+
+func buildRecommendationReview(cfg ServerConfig, args RecommendationReviewArgs) (RecommendationReviewOutput, error) {
+	if args.LeagueID == 0 {
+		return RecommendationReviewOutput{}, invalidArgumentf("league_id is required")
+	}
+	horizon := 3
+	if args.Horizon != nil && *args.Horizon > 0 {
+		horizon = *args.Horizon
+	}
+
+	runs, skipped, err := readRecoLog(cfg.DerivedRoot, args.LeagueID)
+	if err != nil {
+		return RecommendationReviewOutput{}, err
+	}
+	latestFinished, _, err := resolveAsOfAndNextGW(cfg, 0, 0)
+	if err != nil {
+		return RecommendationReviewOutput{}, err
+	}
+	st := store.NewJSONStore(cfg.RawRoot)
+	transactions, err := loadTransactionsRaw(st, args.LeagueID)
+	if err != nil {
+		return RecommendationReviewOutput{}, err
+	}
+	entryNames := make(map[int]string)
+	if ld, _, err := loadLeagueDetails(st, args.LeagueID); err == nil {
+		for _, e := range ld.LeagueEntries {
+			entryNames[e.EntryID] = e.EntryName
+		}
+	}
+
+	liveByGW := make(map[int]map[int]livestats.ElementStats)
+	liveFor := func(gw int) (map[int]livestats.ElementStats, bool) {
+		if stats, ok := liveByGW[gw]; ok {
+			return stats, stats != nil
+		}
+		stats, err := loadLiveStats(cfg.RawRoot, gw)
+		if err != nil {
+			stats = nil
+		}
+		liveByGW[gw] = stats
+		return stats, stats != nil
+	}
+
+	out := RecommendationReviewOutput{
+		LeagueID:         args.LeagueID,
+		Horizon:          horizon,
+		LatestFinishedGW: latestFinished,
+		Entries:          []EntryReview{},
+		BestCalls:        []ReviewedCall{},
+		WorstCalls:       []ReviewedCall{},
+		Calls:            []ReviewedCall{},
+	}
+	for _, run := range runs {
+		if args.EntryID != nil && *args.EntryID != 0 && run.EntryID != *args.EntryID {
+			continue
+		}
+		out.LoggedRuns++
+		var gws []int
+		for gw := run.TargetGW; gw < run.TargetGW+horizon && gw <= latestFinished; gw++ {
+			if _, ok := liveFor(gw); ok {
+				gws = append(gws, gw)
+			}
+		}
+		if len(gws) == 0 {
+			out.Pending += len(run.Adds)
+			continue
+		}
+		for _, pick := range run.Adds {
+			c := ReviewedCall{
+				EntryID:     run.EntryID,
+				EntryName:   entryNames[run.EntryID],
+				TargetGW:    run.TargetGW,
+				Element:     pick.Element,
+				Name:        pick.Name,
+				Score:       pick.Score,
+				DropElement: pick.SuggestedDrop,
+				DropName:    pick.SuggestedDropName,
+				GWsScored:   gws,
+			}
+			for _, gw := range gws {
+				stats, _ := liveFor(gw)
+				c.AddPoints += stats[pick.Element].TotalPoints
+				if pick.SuggestedDrop != 0 {
+					c.DropPoints += stats[pick.SuggestedDrop].TotalPoints
+				}
+			}
+			c.Margin = c.AddPoints - c.DropPoints
+			c.Hit = c.Margin > 0
+			for _, tx := range transactions {
+				if tx.Entry == run.EntryID && tx.ElementIn == pick.Element && tx.Result == "a" && (tx.Kind == "w" || tx.Kind == "f") &&
+					tx.Event >= run.AsOfGW && tx.Event <= run.TargetGW {
+					c.Acted = true
+					if pick.SuggestedDrop != 0 && tx.ElementOut == pick.SuggestedDrop {
+						c.DroppedSuggested = true
+					}
+				}
+			}
+			out.Calls = append(out.Calls, c)
+		}
+	}
+
+	byEntry := make(map[int]*EntryReview)
+	buckets := make([]ScoreBucket, reviewBuckets)
+	for i := range buckets {
+		buckets[i].MinScore = float64(i) / reviewBuckets
+		buckets[i].MaxScore = float64(i+1) / reviewBuckets
+	}
+	for _, c := range out.Calls {
+		out.League.add(c)
+		er := byEntry[c.EntryID]
+		if er == nil {
+			er = &EntryReview{EntryID: c.EntryID, EntryName: c.EntryName}
+			byEntry[c.EntryID] = er
+		}
+		er.Record.add(c)
+		buckets[scoreBucketIndex(c.Score)].Record.add(c)
+	}
+	out.League.finish()
+	for _, er := range byEntry {
+		er.Record.finish()
+		out.Entries = append(out.Entries, *er)
+	}
+	sort.Slice(out.Entries, func(i, j int) bool {
+		if out.Entries[i].Record.HitRate != out.Entries[j].Record.HitRate {
+			return out.Entries[i].Record.HitRate > out.Entries[j].Record.HitRate
+		}
+		return out.Entries[i].EntryID < out.Entries[j].EntryID
+	})
+	for i := range buckets {
+		buckets[i].Record.finish()
+	}
+	out.Calibration = buckets
+
+	ranked := append([]ReviewedCall(nil), out.Calls...)
+	sort.SliceStable(ranked, func(i, j int) bool { return ranked[i].Margin > ranked[j].Margin })
+	for i := 0; i < len(ranked) && i < reviewBestWorst; i++ {
+		if ranked[i].Margin > 0 {
+			out.BestCalls = append(out.BestCalls, ranked[i])
+		}
+	}
+	for i := len(ranked) - 1; i >= 0 && len(ranked)-1-i < reviewBestWorst; i-- {
+		if ranked[i].Margin < 0 {
+			out.WorstCalls = append(out.WorstCalls, ranked[i])
+		}
+	}
+
+	out.Notes = []string{
+		fmt.Sprintf("Each logged add is scored on total points over up to %d finished GWs from its target GW, against its suggested drop (0 when there was none).", horizon),
+		"Repeated waiver_recommendations runs for the same entry and target GW count once, using the latest run.",
+		"acted means the entry claimed the player by waiver or free agency between the run's as-of and target GWs.",
+	}
+	if out.Pending > 0 {
+		out.Notes = append(out.Notes, fmt.Sprintf("%d calls target GWs that haven't finished and are pending.", out.Pending))
+	}
+	var missingLive []int
+	for gw, stats := range liveByGW {
+		if stats == nil {
+			missingLive = append(missingLive, gw)
+		}
+	}
+	if len(missingLive) > 0 {
+		sort.Ints(missingLive)
+		out.Notes = append(out.Notes, fmt.Sprintf("GWs %v have no live.json and were left out of scoring.", missingLive))
+	}
+	if skipped > 0 {
+		out.Notes = append(out.Notes, fmt.Sprintf("%d unreadable log lines were skipped.", skipped))
+	}
+	return out, nil
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeLiveTotals(t *testing.T, dir string, gw int, points map[string]int) {
+	t.Helper()
+	elements := make(map[string]any, len(points))
+	for id, p := range points {
+		elements[id] = map[string]any{"stats": map[string]any{"minutes": 90, "total_points": p}}
+	}
+	writeJSON(t, filepath.Join(dir, "gw", itoa(gw), "live.json"), map[string]any{"elements": elements, "fixtures": []any{}})
+}
+
+func TestRecoLogEntryFor_KeepsTopPicks(t *testing.T) {
+	report := WaiverRecommendationsReport{LeagueID: 5, EntryID: 101, AsOfGW: 3, TargetGW: 4}
+	for id := 1; id <= 5; id++ {
+		add := AddRecommendation{Element: id, Score: ScoreComponents{WeightedScore: 1 - float64(id)/10}}
+		if id == 1 {
+			add.SuggestedDrop = &DropRecommendation{Element: 50, Name: "Fifty"}
+		}
+		report.Adds = append(report.Adds, add)
+		report.Drops = append(report.Drops, DropRecommendation{Element: 50 + id})
+	}
+	e := recoLogEntryFor(report, time.Date(2025, 10, 3, 9, 0, 0, 0, time.UTC))
+	if len(e.Adds) != recoLogTopN || len(e.Drops) != recoLogTopN {
+		t.Fatalf("adds %d drops %d, want %d of each", len(e.Adds), len(e.Drops), recoLogTopN)
+	}
+	if e.Adds[0].SuggestedDrop != 50 || e.Adds[0].SuggestedDropName != "Fifty" || !approxEqual(e.Adds[0].Score, 0.9) || e.Adds[1].SuggestedDrop != 0 {
+		t.Errorf("adds = %+v", e.Adds)
+	}
+	if e.LoggedAt != "2025-10-03T09:00:00Z" || e.TargetGW != 4 {
+		t.Errorf("entry = %+v", e)
+	}
+}
+
+func TestReadRecoLog_DedupsPerEntryAndGW(t *testing.T) {
+	dir := t.TempDir()
+	for _, e := range []RecoLogEntry{
+		{LeagueID: 5, EntryID: 101, AsOfGW: 3, TargetGW: 4, Adds: []RecoLogPick{{Element: 12}}},
+		{LeagueID: 5, EntryID: 102, AsOfGW: 3, TargetGW: 4, Adds: []RecoLogPick{{Element: 13}}},
+		{LeagueID: 5, EntryID: 101, AsOfGW: 3, TargetGW: 4, Adds: []RecoLogPick{{Element: 10}}},
+	} {
+		if err := appendRecoLog(dir, e); err != nil {
+			t.Fatalf("appendRecoLog: %v", err)
+		}
+	}
+	f, err := os.OpenFile(recoLogPath(dir, 5), os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString("{not json\n")
+	f.Close()
+
+	runs, skipped, err := readRecoLog(dir, 5)
+	if err != nil {
+		t.Fatalf("readRecoLog: %v", err)
+	}
+	if skipped != 1 || len(runs) != 2 {
+		t.Fatalf("runs = %+v skipped %d, want 2 runs and 1 skipped line", runs, skipped)
+	}
+	if runs[0].EntryID != 101 || runs[0].Adds[0].Element != 10 {
+		t.Errorf("first run = %+v, want entry 101's latest run", runs[0])
+	}
+
+	if _, _, err := readRecoLog(dir, 6); classifyError(err).Code != codeDataMissing {
+		t.Errorf("missing log: err = %v, want DATA_MISSING", err)
+	}
+}
+
+func TestBuildRecommendationReview(t *testing.T) {
+	dir, cfg := resourceCfg(t)
+	writeFullGameJSON(t, dir, 5, true, 6, true, "y")
+	writeJSON(t, filepath.Join(dir, "league", "5", "transactions.json"), map[string]any{
+		"transactions": []any{
+			map[string]any{"id": 1, "entry": 101, "element_in": 10, "element_out": 20, "event": 4, "kind": "w", "result": "a"},
+			map[string]any{"id": 2, "entry": 102, "element_in": 13, "element_out": 22, "event": 4, "kind": "w", "result": "do"},
+		},
+	})
+	writeLiveTotals(t, dir, 4, map[string]int{"10": 6, "20": 2, "11": 1, "21": 5, "13": 3})
+	writeLiveTotals(t, dir, 5, map[string]int{"10": 4, "20": 2, "11": 2, "21": 2, "13": 0})
+	for _, e := range []RecoLogEntry{
+		{LeagueID: 5, EntryID: 101, AsOfGW: 3, TargetGW: 4, Adds: []RecoLogPick{{Element: 12, Score: 0.1}}},
+		{LeagueID: 5, EntryID: 101, AsOfGW: 3, TargetGW: 4, Adds: []RecoLogPick{
+			{Element: 10, Name: "Ten", Score: 0.9, SuggestedDrop: 20},
+			{Element: 11, Name: "Eleven", Score: 0.3, SuggestedDrop: 21},
+		}},
+		{LeagueID: 5, EntryID: 102, AsOfGW: 3, TargetGW: 4, Adds: []RecoLogPick{{Element: 13, Name: "Thirteen", Score: 0.5}}},
+		{LeagueID: 5, EntryID: 101, AsOfGW: 5, TargetGW: 6, Adds: []RecoLogPick{{Element: 14, Score: 0.7}}},
+	} {
+		if err := appendRecoLog(dir, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	horizon := 2
+	out, err := buildRecommendationReview(cfg, RecommendationReviewArgs{LeagueID: 5, Horizon: &horizon})
+	if err != nil {
+		t.Fatalf("buildRecommendationReview: %v", err)
+	}
+	if out.LoggedRuns != 3 || out.Pending != 1 || len(out.Calls) != 3 {
+		t.Fatalf("runs %d pending %d calls %d, want 3, 1, 3", out.LoggedRuns, out.Pending, len(out.Calls))
+	}
+	ten := out.Calls[0]
+	if ten.Element != 10 || ten.AddPoints != 10 || ten.DropPoints != 4 || !ten.Hit || !ten.Acted || !ten.DroppedSuggested {
+		t.Errorf("call for 10 = %+v, want +6 hit, acted, dropped 20", ten)
+	}
+	if out.Calls[2].Acted {
+		t.Errorf("entry 102's rejected claim counted as acted")
+	}
+	if l := out.League; l.Calls != 3 || l.Hits != 2 || l.Acted != 1 || !approxEqual(l.AvgMargin, 5.0/3) {
+		t.Errorf("league = %+v, want 2/3 hits, 1 acted, avg margin 1.67", l)
+	}
+	if len(out.Entries) != 2 || out.Entries[0].EntryID != 102 || out.Entries[1].Record.HitRate != 0.5 {
+		t.Errorf("entries = %+v, want 102 (1/1) ahead of 101 (1/2)", out.Entries)
+	}
+	if len(out.BestCalls) != 2 || out.BestCalls[0].Element != 10 || len(out.WorstCalls) != 1 || out.WorstCalls[0].Element != 11 {
+		t.Errorf("best %+v worst %+v", out.BestCalls, out.WorstCalls)
+	}
+	if len(out.Calibration) != reviewBuckets || out.Calibration[4].Record.Hits != 1 || out.Calibration[1].Record.Calls != 1 || out.Calibration[1].Record.Hits != 0 {
+		t.Errorf("calibration = %+v", out.Calibration)
+	}
+
+	only := 102
+	out, err = buildRecommendationReview(cfg, RecommendationReviewArgs{LeagueID: 5, EntryID: &only, Horizon: &horizon})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out.Calls) != 1 || out.Pending != 0 {
+		t.Errorf("entry filter: calls %d pending %d, want 1 and 0", len(out.Calls), out.Pending)
+	}
+}
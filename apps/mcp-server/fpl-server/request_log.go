@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// defaultSlowCall is the --slow-call-ms default: calls at least this long
+// are logged at warn with a timing breakdown.
+const defaultSlowCall = 2 * time.Second
+
+// requestLogger writes one JSON line per tool call. Slow is the threshold
+// for escalating to warn (0 turns it off).
+type requestLogger struct {
+	logger *slog.Logger
+	slow   time.Duration
+}
+
+func newRequestLogger(w io.Writer, level slog.Level, slow time.Duration) requestLogger {
+	return requestLogger{logger: slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level})), slow: slow}
+}
+
+// requestLog is the process-wide tool call log; main configures it from
+// --log-level and --slow-call-ms.
+var requestLog = newRequestLogger(io.Discard, slog.LevelInfo, defaultSlowCall)
+
+// Summary sources, as counted by fpl_mcp_summary_loads_total.
+const (
+	sourceDisk     = "disk"
+	sourceComputed = "computed"
+	sourceShared   = "shared"
+	sourceMissing  = "missing"
+)
+
+// callTiming accumulates where one tool call spent its time: reading derived
+// files, computing ones that were missing, and how its summaries were
+// served. It travels in the call's context and, for the builders that
+// record into it, in ServerConfig (see forCall). A nil *callTiming records
+// nothing.
+type callTiming struct {
+	mu      sync.Mutex
+	read    time.Duration
+	compute time.Duration
+	sources map[string]int
+}
+
+type callTimingKey struct{}
+
+func withCallTiming(ctx context.Context) (context.Context, *callTiming) {
+	t := &callTiming{sources: make(map[string]int)}
+	return context.WithValue(ctx, callTimingKey{}, t), t
+}
+
+func callTimingFrom(ctx context.Context) *callTiming {
+	t, _ := ctx.Value(callTimingKey{}).(*callTiming)
+	return t
+}
+
+// forCall is forLeague for a tool handler: it also carries the call's
+// timing so summary loads and the waiver builder can record into it, and
+// starts the call's fetch-on-miss budget.
+func (cfg ServerConfig) forCall(ctx context.Context, leagueID int) ServerConfig {
+	cfg = cfg.forLeague(leagueID)
+	cfg.timing = callTimingFrom(ctx)
+	if cfg.Fetcher != nil {
+		cfg.fetches = newFetchBudget(cfg.FetchOnMissLimit)
+	}
+	return cfg
+}
+
+// since adds the time elapsed since start to the read or compute total.
+func (t *callTiming) since(start time.Time, compute bool) {
+	if t == nil {
+		return
+	}
+	d := time.Since(start)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if compute {
+		t.compute += d
+	} else {
+		t.read += d
+	}
+}
+
+func (t *callTiming) served(source string) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.sources[source]++
+}
+
+// source is how the call's summaries were served: "computed" if any was
+// built, else "shared", "disk" or "missing", or "" when it loaded none.
+func (t *callTiming) source() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, s := range []string{sourceComputed, sourceShared, sourceDisk, sourceMissing} {
+		if t.sources[s] > 0 {
+			return s
+		}
+	}
+	return ""
+}
+
+// callArgs picks league_id and gw out of a tool's arguments when it has
+// them.
+func callArgs(args any) []slog.Attr {
+	raw, err := json.Marshal(args)
+	if err != nil {
+		return nil
+	}
+	var ids struct {
+		LeagueID *int `json:"league_id"`
+		GW       *int `json:"gw"`
+	}
+	if json.Unmarshal(raw, &ids) != nil {
+		return nil
+	}
+	var out []slog.Attr
+	if ids.LeagueID != nil && *ids.LeagueID != 0 {
+		out = append(out, slog.Int("league_id", *ids.LeagueID))
+	}
+	if ids.GW != nil {
+		out = append(out, slog.Int("gw", *ids.GW))
+	}
+	return out
+}
+
+// responseBytes is the size of a result's text content.
+func responseBytes(res *mcp.CallToolResult) int {
+	if res == nil {
+		return 0
+	}
+	n := 0
+	for _, c := range res.Content {
+		if text, ok := c.(*mcp.TextContent); ok {
+			n += len(text.Text)
+		}
+	}
+	return n
+}
+
+// logCall writes the log line for one finished tool call.
+func (l requestLogger) logCall(ctx context.Context, name string, args any, t *callTiming, elapsed time.Duration, res *mcp.CallToolResult, err error) {
+	level, msg := slog.LevelInfo, "tool call"
+	if l.slow > 0 && elapsed >= l.slow {
+		level, msg = slog.LevelWarn, "slow tool call"
+	}
+	if !l.logger.Enabled(ctx, level) {
+		return
+	}
+	attrs := []slog.Attr{slog.String("tool", name)}
+	attrs = append(attrs, callArgs(args)...)
+	attrs = append(attrs,
+		slog.Float64("duration_ms", durationMS(elapsed)),
+		slog.String("outcome", "ok"),
+	)
+	if code, failed := toolResultCode(res, err); failed {
+		attrs[len(attrs)-1] = slog.String("outcome", "error")
+		attrs = append(attrs, slog.String("error_code", string(code)))
+	}
+	attrs = append(attrs, slog.Int("response_bytes", responseBytes(res)))
+	if src := t.source(); src != "" {
+		attrs = append(attrs, slog.String("summary_source", src))
+	}
+	if level == slog.LevelWarn {
+		t.mu.Lock()
+		read, compute := t.read, t.compute
+		t.mu.Unlock()
+		attrs = append(attrs, slog.Group("timing",
+			slog.Float64("read_ms", durationMS(read)),
+			slog.Float64("compute_ms", durationMS(compute)),
+			slog.Float64("other_ms", durationMS(max(elapsed-read-compute, 0))),
+		))
+	}
+	l.logger.LogAttrs(ctx, level, msg, attrs...)
+}
+
+func durationMS(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
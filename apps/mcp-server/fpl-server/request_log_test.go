@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// captureRequestLog points requestLog at a buffer for the test and returns a
+// function reading back the logged lines.
+func captureRequestLog(t *testing.T, level slog.Level, slow time.Duration) func() []map[string]any {
+	t.Helper()
+	var buf bytes.Buffer
+	prev := requestLog
+	requestLog = newRequestLogger(&buf, level, slow)
+	t.Cleanup(func() { requestLog = prev })
+	return func() []map[string]any {
+		var lines []map[string]any
+		for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+			if line == "" {
+				continue
+			}
+			var m map[string]any
+			if err := json.Unmarshal([]byte(line), &m); err != nil {
+				t.Fatalf("log line %q: %v", line, err)
+			}
+			lines = append(lines, m)
+		}
+		return lines
+	}
+}
+
+func TestRequestLog_OKAndErrorCalls(t *testing.T) {
+	dir, cfg := tmpCfg(t)
+	cfg.DerivedRoot = dir
+	writeJSON(t, filepath.Join(dir, "summary/standings/1/gw/2.json"), map[string]any{"rows": []any{}})
+	lines := captureRequestLog(t, slog.LevelInfo, 0)
+
+	ok := instrumentTool("log_test_ok", func(ctx context.Context, req *mcp.CallToolRequest, args LeagueGWArgs) (*mcp.CallToolResult, any, error) {
+		raw, err := loadSummaryFile(cfg.forCall(ctx, args.LeagueID), args.LeagueID, args.GW, "summary/standings/1/gw/2.json", nil, nil)
+		return toolJSON(raw, err)
+	})
+	if _, _, err := ok(context.Background(), nil, LeagueGWArgs{LeagueID: 1, GW: 2}); err != nil {
+		t.Fatal(err)
+	}
+	failing := instrumentTool("log_test_error", func(ctx context.Context, req *mcp.CallToolRequest, args PlayerLookupArgs) (*mcp.CallToolResult, any, error) {
+		return toolError(notFoundf("no such player")), nil, nil
+	})
+	if _, _, err := failing(context.Background(), nil, PlayerLookupArgs{ElementID: 9}); err != nil {
+		t.Fatal(err)
+	}
+
+	got := lines()
+	if len(got) != 2 {
+		t.Fatalf("got %d log lines, want 2: %v", len(got), got)
+	}
+	first := got[0]
+	if first["level"] != "INFO" || first["msg"] != "tool call" || first["tool"] != "log_test_ok" || first["outcome"] != "ok" {
+		t.Errorf("ok call = %v", first)
+	}
+	if first["league_id"] != 1.0 || first["gw"] != 2.0 || first["summary_source"] != sourceDisk || first["response_bytes"].(float64) <= 0 {
+		t.Errorf("ok call fields = %v", first)
+	}
+	if _, ok := first["duration_ms"].(float64); !ok {
+		t.Errorf("ok call has no duration: %v", first)
+	}
+	if _, ok := first["error_code"]; ok {
+		t.Errorf("ok call has an error code: %v", first)
+	}
+
+	second := got[1]
+	if second["tool"] != "log_test_error" || second["outcome"] != "error" || second["error_code"] != string(codeNotFound) {
+		t.Errorf("error call = %v", second)
+	}
+	for _, key := range []string{"league_id", "gw", "summary_source", "timing"} {
+		if _, ok := second[key]; ok {
+			t.Errorf("error call has %s: %v", key, second)
+		}
+	}
+}
+
+func TestRequestLog_SlowCallBreakdown(t *testing.T) {
+	lines := captureRequestLog(t, slog.LevelWarn, time.Millisecond)
+	h := instrumentTool("log_test_slow", func(ctx context.Context, req *mcp.CallToolRequest, args struct{}) (*mcp.CallToolResult, any, error) {
+		timing := callTimingFrom(ctx)
+		start := time.Now()
+		time.Sleep(2 * time.Millisecond)
+		timing.since(start, true)
+		timing.served(sourceComputed)
+		return toolJSONBytes([]byte(`{}`)), nil, nil
+	})
+	fast := instrumentTool("log_test_fast", func(ctx context.Context, req *mcp.CallToolRequest, args struct{}) (*mcp.CallToolResult, any, error) {
+		return toolJSONBytes([]byte(`{}`)), nil, nil
+	})
+	if _, _, err := h(context.Background(), nil, struct{}{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := fast(context.Background(), nil, struct{}{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// At warn only the slow call is logged.
+	got := lines()
+	if len(got) != 1 || got[0]["tool"] != "log_test_slow" || got[0]["level"] != "WARN" || got[0]["msg"] != "slow tool call" {
+		t.Fatalf("lines = %v, want the slow call alone", got)
+	}
+	timing, ok := got[0]["timing"].(map[string]any)
+	if !ok || timing["compute_ms"].(float64) < 2 || timing["read_ms"] != 0.0 || got[0]["summary_source"] != sourceComputed {
+		t.Errorf("slow call = %v", got[0])
+	}
+}
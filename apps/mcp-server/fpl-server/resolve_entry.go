@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/summary"
+)
+
+// entryNames returns the names an entry can be looked up by: its team name,
+// short name and the manager's "first last".
+func entryNames(e summary.LeagueEntry) []string {
+	names := []string{e.EntryName, e.ShortName}
+	if manager := strings.TrimSpace(e.PlayerFirstName + " " + e.PlayerLastName); manager != "" {
+		names = append(names, manager)
+	}
+	return names
+}
+
+// resolveEntry returns the entry id that name refers to. Matching is case
+// insensitive and tries, in order: exact entry_name, exact short_name, exact
+// manager "first last", then a prefix and finally a substring of any of
+// those. The first tier with any match wins; several matches in that tier is
+// an error listing them rather than a guess.
+func resolveEntry(entries []summary.LeagueEntry, name string) (int, error) {
+	n := strings.ToLower(strings.TrimSpace(name))
+	if n == "" {
+		return 0, invalidArgumentf("entry_id or entry_name is required")
+	}
+	exact := func(i int) func(summary.LeagueEntry) bool {
+		return func(e summary.LeagueEntry) bool {
+			names := entryNames(e)
+			return i < len(names) && strings.ToLower(names[i]) == n
+		}
+	}
+	fuzzy := func(match func(s, sub string) bool) func(summary.LeagueEntry) bool {
+		return func(e summary.LeagueEntry) bool {
+			for _, s := range entryNames(e) {
+				if s != "" && match(strings.ToLower(s), n) {
+					return true
+				}
+			}
+			return false
+		}
+	}
+	tiers := []func(summary.LeagueEntry) bool{
+		exact(0),
+		exact(1),
+		exact(2),
+		fuzzy(strings.HasPrefix),
+		fuzzy(strings.Contains),
+	}
+	for _, tier := range tiers {
+		var matches []summary.LeagueEntry
+		for _, e := range entries {
+			if tier(e) {
+				matches = append(matches, e)
+			}
+		}
+		switch len(matches) {
+		case 0:
+			continue
+		case 1:
+			return matches[0].EntryID, nil
+		}
+		candidates := make([]string, 0, len(matches))
+		for _, e := range matches {
+			candidates = append(candidates, fmt.Sprintf("%s (%d)", e.EntryName, e.EntryID))
+		}
+		return 0, invalidArgumentf("ambiguous entry_name %q matches %s", strings.TrimSpace(name), strings.Join(candidates, ", "))
+	}
+	return 0, notFoundf("no entry found for name: %s", strings.TrimSpace(name))
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/summary"
+)
+
+func TestResolveEntry(t *testing.T) {
+	entries := []summary.LeagueEntry{
+		{ID: 1, EntryID: 200, EntryName: "Alpha FC", ShortName: "AFC", PlayerFirstName: "Jamie", PlayerLastName: "Smith"},
+		{ID: 2, EntryID: 201, EntryName: "Beta United", ShortName: "BET", PlayerFirstName: "Alex", PlayerLastName: "Jones"},
+		{ID: 3, EntryID: 202, EntryName: "AFC Wimbledon Fans", ShortName: "WIM", PlayerFirstName: "Sam", PlayerLastName: "Smithson"},
+	}
+	cases := []struct {
+		name string
+		want int
+	}{
+		{"alpha fc", 200},
+		{"  BET ", 201},
+		// "AFC" is Alpha's short name exactly, which wins over Wimbledon's prefix.
+		{"AFC", 200},
+		{"alex jones", 201},
+		{"beta", 201},
+		{"wimbledon", 202},
+		{"smithson", 202},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := resolveEntry(entries, tc.name)
+			if err != nil || got != tc.want {
+				t.Errorf("resolveEntry(%q) = %d, %v; want %d", tc.name, got, err, tc.want)
+			}
+		})
+	}
+
+	// "smith" prefixes no name but is inside both managers' surnames.
+	_, err := resolveEntry(entries, "smith")
+	if classifyError(err).Code != codeInvalidArgument || !strings.Contains(err.Error(), "Alpha FC (200)") || !strings.Contains(err.Error(), "AFC Wimbledon Fans (202)") {
+		t.Errorf("ambiguous: err = %v, want invalid argument listing both candidates", err)
+	}
+	if _, err := resolveEntry(entries, "gamma"); classifyError(err).Code != codeNotFound {
+		t.Errorf("unknown: err = %v, want not found", err)
+	}
+	if _, err := resolveEntry(entries, " "); classifyError(err).Code != codeInvalidArgument {
+		t.Errorf("blank: err = %v, want invalid argument", err)
+	}
+}
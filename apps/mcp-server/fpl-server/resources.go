@@ -0,0 +1,296 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	resourceMIMEType     = "application/json"
+	resourcePollInterval = 30 * time.Second
+	resourceFixturesSpan = 5
+)
+
+// resourceTemplates are the read-only summary artifacts exposed as MCP
+// resources. They are served from the same derived files as the matching
+// tools, so a client can attach them as context without a tool round-trip.
+var resourceTemplates = []*mcp.ResourceTemplate{
+	{
+		Name:        "standings",
+		URITemplate: "standings://{league}/current",
+		Description: "League table as of the current gameweek",
+		MIMEType:    resourceMIMEType,
+	},
+	{
+		Name:        "league_summary",
+		URITemplate: "league-summary://{league}/gw/{gw}",
+		Description: "League summary for a gameweek (gw 0 = current)",
+		MIMEType:    resourceMIMEType,
+	},
+	{
+		Name:        "fixtures",
+		URITemplate: "fixtures://{league}/next5",
+		Description: "Head-to-head fixtures for the next 5 gameweeks from the current one",
+		MIMEType:    resourceMIMEType,
+	},
+}
+
+// resourceTarget is a parsed resource URI mapped onto its derived summary file.
+// Alias is set for URIs that name the current GW rather than a number, whose
+// target moves when the next GW starts.
+type resourceTarget struct {
+	LeagueID int
+	GW       int
+	RelPath  string
+	Horizons []int
+	Alias    bool
+}
+
+// resolveResource parses a resource URI and resolves it to a derived file.
+// "current" variants resolve the GW via resolveEffectiveGW on every call, so
+// the target moves forward as game.json advances.
+func resolveResource(cfg ServerConfig, uri string) (resourceTarget, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return resourceTarget{}, err
+	}
+	leagueID, err := strconv.Atoi(u.Host)
+	if err != nil || leagueID <= 0 {
+		return resourceTarget{}, invalidArgumentf("invalid league id in resource uri: %s", uri)
+	}
+	cfg = cfg.forLeague(leagueID)
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+
+	switch {
+	case u.Scheme == "standings" && len(parts) == 1 && parts[0] == "current":
+		gw, _, err := resolveEffectiveGW(cfg, 0, gwModeLatestFinished)
+		if err != nil {
+			return resourceTarget{}, err
+		}
+		return resourceTarget{
+			LeagueID: leagueID,
+			GW:       gw,
+			RelPath:  fmt.Sprintf("summary/standings/%d/gw/%d.json", leagueID, gw),
+			Alias:    true,
+		}, nil
+	case u.Scheme == "league-summary" && len(parts) == 2 && parts[0] == "gw":
+		gw, err := strconv.Atoi(parts[1])
+		if err != nil || gw < 0 {
+			return resourceTarget{}, invalidArgumentf("invalid gw in resource uri: %s", uri)
+		}
+		alias := gw == 0
+		gw, _, err = resolveEffectiveGW(cfg, gw, gwModeLatestFinished)
+		if err != nil {
+			return resourceTarget{}, err
+		}
+		return resourceTarget{
+			LeagueID: leagueID,
+			GW:       gw,
+			RelPath:  fmt.Sprintf("summary/league/%d/gw/%d.json", leagueID, gw),
+			Alias:    alias,
+		}, nil
+	case u.Scheme == "fixtures" && len(parts) == 1 && parts[0] == "next5":
+		gw, _, err := resolveEffectiveGW(cfg, 0, gwModeCurrent)
+		if err != nil {
+			return resourceTarget{}, err
+		}
+		return resourceTarget{
+			LeagueID: leagueID,
+			GW:       gw,
+			RelPath:  fmt.Sprintf("summary/fixtures/%d/from_gw/%d_h%d.json", leagueID, gw, resourceFixturesSpan),
+			Horizons: []int{resourceFixturesSpan},
+			Alias:    true,
+		}, nil
+	}
+	return resourceTarget{}, mcp.ResourceNotFoundError(uri)
+}
+
+func resourceHandler(cfg ServerConfig) mcp.ResourceHandler {
+	return func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		uri := req.Params.URI
+		target, err := resolveResource(cfg, uri)
+		if err != nil {
+			return nil, err
+		}
+		b, err := loadSummaryFile(cfg, target.LeagueID, target.GW, target.RelPath, target.Horizons, nil)
+		if err != nil {
+			return nil, err
+		}
+		return &mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{
+			{URI: uri, MIMEType: resourceMIMEType, Text: string(b)},
+		}}, nil
+	}
+}
+
+// registerResources adds the resource templates to the server, plus concrete
+// "current"/"next5" resources for each league found under the raw root so
+// resources/list has something to show.
+func registerResources(server *mcp.Server, cfg ServerConfig) {
+	h := resourceHandler(cfg)
+	for _, t := range resourceTemplates {
+		server.AddResourceTemplate(t, h)
+	}
+	for _, leagueID := range configuredLeagues(cfg) {
+		server.AddResource(&mcp.Resource{
+			Name:     fmt.Sprintf("standings_%d", leagueID),
+			URI:      fmt.Sprintf("standings://%d/current", leagueID),
+			MIMEType: resourceMIMEType,
+		}, h)
+		server.AddResource(&mcp.Resource{
+			Name:     fmt.Sprintf("fixtures_%d", leagueID),
+			URI:      fmt.Sprintf("fixtures://%d/next5", leagueID),
+			MIMEType: resourceMIMEType,
+		}, h)
+	}
+}
+
+// configuredLeagues returns the leagues found under the default raw root
+// plus any league with its own --league-root, sorted by id.
+func configuredLeagues(cfg ServerConfig) []int {
+	seen := make(map[int]bool)
+	out := discoverLeagues(cfg.RawRoot)
+	for _, id := range out {
+		seen[id] = true
+	}
+	for id := range cfg.LeagueRoots {
+		if !seen[id] {
+			out = append(out, id)
+		}
+	}
+	sort.Ints(out)
+	return out
+}
+
+// discoverLeagues returns league ids that have a details.json in the raw root.
+func discoverLeagues(rawRoot string) []int {
+	dirs, err := os.ReadDir(filepath.Join(rawRoot, "league"))
+	if err != nil {
+		return nil
+	}
+	out := make([]int, 0, len(dirs))
+	for _, d := range dirs {
+		id, err := strconv.Atoi(d.Name())
+		if err != nil || !d.IsDir() {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(rawRoot, "league", d.Name(), "details.json")); err != nil {
+			continue
+		}
+		out = append(out, id)
+	}
+	return out
+}
+
+// resourceWatcher polls the derived files behind subscribed resource URIs and
+// sends resources/updated when a file's mtime changes or a "current" URI
+// starts pointing at a different file. The SDK tracks which sessions are
+// subscribed; the watcher only needs to know which URIs to check.
+type resourceWatcher struct {
+	cfg    ServerConfig
+	server *mcp.Server
+
+	mu   sync.Mutex
+	subs map[string]*watchedResource
+}
+
+type watchedResource struct {
+	refs    int
+	relPath string
+	modTime time.Time
+}
+
+func newResourceWatcher(cfg ServerConfig) *resourceWatcher {
+	return &resourceWatcher{cfg: cfg, subs: make(map[string]*watchedResource)}
+}
+
+func (w *resourceWatcher) subscribe(ctx context.Context, req *mcp.SubscribeRequest) error {
+	uri := req.Params.URI
+	target, err := resolveResource(w.cfg, uri)
+	if err != nil {
+		return err
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if s, ok := w.subs[uri]; ok {
+		s.refs++
+		return nil
+	}
+	w.subs[uri] = &watchedResource{
+		refs:    1,
+		relPath: target.RelPath,
+		modTime: w.modTime(target),
+	}
+	return nil
+}
+
+func (w *resourceWatcher) unsubscribe(ctx context.Context, req *mcp.UnsubscribeRequest) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if s, ok := w.subs[req.Params.URI]; ok {
+		s.refs--
+		if s.refs <= 0 {
+			delete(w.subs, req.Params.URI)
+		}
+	}
+	return nil
+}
+
+func (w *resourceWatcher) modTime(target resourceTarget) time.Time {
+	info, err := store.StatDerived(filepath.Join(w.cfg.forLeague(target.LeagueID).DerivedRoot, target.RelPath))
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+// changed re-resolves every subscribed URI and returns those whose backing
+// file moved or was modified since the last check.
+func (w *resourceWatcher) changed() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var out []string
+	for uri, s := range w.subs {
+		target, err := resolveResource(w.cfg, uri)
+		if err != nil {
+			continue
+		}
+		mt := w.modTime(target)
+		if target.RelPath != s.relPath || !mt.Equal(s.modTime) {
+			s.relPath = target.RelPath
+			s.modTime = mt
+			out = append(out, uri)
+		}
+	}
+	return out
+}
+
+// run polls until ctx is cancelled.
+func (w *resourceWatcher) run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, uri := range w.changed() {
+				if err := w.server.ResourceUpdated(ctx, &mcp.ResourceUpdatedNotificationParams{URI: uri}); err != nil {
+					log.Printf("resource update notify %s: %v", uri, err)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// resourceCfg returns a config whose raw and derived roots share a temp dir
+// and which never computes missing summaries.
+func resourceCfg(t *testing.T) (string, ServerConfig) {
+	t.Helper()
+	dir := t.TempDir()
+	return dir, ServerConfig{RawRoot: dir, DerivedRoot: dir, Clock: testClock}
+}
+
+// ---------------------------------------------------------------------------
+// resolveResource
+// ---------------------------------------------------------------------------
+
+func TestResolveResource(t *testing.T) {
+	dir, cfg := resourceCfg(t)
+	writeGameJSON(t, dir, 7)
+	writeLiveJSON(t, dir, 7, map[string]any{})
+
+	cases := []struct {
+		uri     string
+		gw      int
+		relPath string
+	}{
+		{"standings://42/current", 7, "summary/standings/42/gw/7.json"},
+		{"league-summary://42/gw/3", 3, "summary/league/42/gw/3.json"},
+		{"league-summary://42/gw/0", 7, "summary/league/42/gw/7.json"},
+		{"fixtures://42/next5", 7, "summary/fixtures/42/from_gw/7_h5.json"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.uri, func(t *testing.T) {
+			got, err := resolveResource(cfg, tc.uri)
+			if err != nil {
+				t.Fatalf("resolveResource: %v", err)
+			}
+			if got.LeagueID != 42 || got.GW != tc.gw || got.RelPath != tc.relPath {
+				t.Errorf("got %+v, want league 42 gw %d path %s", got, tc.gw, tc.relPath)
+			}
+		})
+	}
+
+	for _, bad := range []string{"standings://abc/current", "standings://42/gw/1", "league-summary://42/gw/x", "unknown://42/current"} {
+		if _, err := resolveResource(cfg, bad); err == nil {
+			t.Errorf("%s: expected error", bad)
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// read handler over an in-memory session
+// ---------------------------------------------------------------------------
+
+func TestReadResource_ServesDerivedFile(t *testing.T) {
+	dir, cfg := resourceCfg(t)
+	writeGameJSON(t, dir, 4)
+	writeLiveJSON(t, dir, 4, map[string]any{})
+	writeJSON(t, filepath.Join(dir, "summary/standings/42/gw/4.json"), map[string]any{"gw": 4})
+	writeLeagueDetailsFixture(t, dir, 42, nil, nil)
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "0"}, nil)
+	registerResources(server, cfg)
+
+	ctx := context.Background()
+	st, ct := mcp.NewInMemoryTransports()
+	if _, err := server.Connect(ctx, st, nil); err != nil {
+		t.Fatalf("server connect: %v", err)
+	}
+	client := mcp.NewClient(&mcp.Implementation{Name: "client", Version: "0"}, nil)
+	cs, err := client.Connect(ctx, ct, nil)
+	if err != nil {
+		t.Fatalf("client connect: %v", err)
+	}
+	defer cs.Close()
+
+	list, err := cs.ListResources(ctx, nil)
+	if err != nil {
+		t.Fatalf("list resources: %v", err)
+	}
+	if len(list.Resources) != 2 {
+		t.Errorf("expected 2 concrete resources for league 42, got %d", len(list.Resources))
+	}
+
+	res, err := cs.ReadResource(ctx, &mcp.ReadResourceParams{URI: "standings://42/current"})
+	if err != nil {
+		t.Fatalf("read resource: %v", err)
+	}
+	if len(res.Contents) != 1 {
+		t.Fatalf("expected 1 content, got %d", len(res.Contents))
+	}
+	c := res.Contents[0]
+	if c.MIMEType != "application/json" {
+		t.Errorf("mime type = %q, want application/json", c.MIMEType)
+	}
+	if c.Text != `{"gw":4}` {
+		t.Errorf("text = %q, want derived file contents", c.Text)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// resourceWatcher
+// ---------------------------------------------------------------------------
+
+func TestResourceWatcher_DetectsChanges(t *testing.T) {
+	dir, cfg := resourceCfg(t)
+	writeGameJSON(t, dir, 4)
+	writeLiveJSON(t, dir, 4, map[string]any{})
+	path := filepath.Join(dir, "summary/standings/42/gw/4.json")
+	writeJSON(t, path, map[string]any{"gw": 4})
+
+	w := newResourceWatcher(cfg)
+	ctx := context.Background()
+	req := &mcp.SubscribeRequest{Params: &mcp.SubscribeParams{URI: "standings://42/current"}}
+	if err := w.subscribe(ctx, req); err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	if got := w.changed(); len(got) != 0 {
+		t.Errorf("expected no changes right after subscribe, got %v", got)
+	}
+
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+	if got := w.changed(); len(got) != 1 {
+		t.Errorf("expected mtime change to be reported, got %v", got)
+	}
+
+	// Advancing current_event re-points the "current" URI at a new file.
+	writeGameJSON(t, dir, 5)
+	writeLiveJSON(t, dir, 5, map[string]any{})
+	if got := w.changed(); len(got) != 1 {
+		t.Errorf("expected GW rollover to be reported, got %v", got)
+	}
+
+	if err := w.unsubscribe(ctx, &mcp.UnsubscribeRequest{Params: &mcp.UnsubscribeParams{URI: "standings://42/current"}}); err != nil {
+		t.Fatalf("unsubscribe: %v", err)
+	}
+	if len(w.subs) != 0 {
+		t.Errorf("expected no subscriptions after unsubscribe, got %d", len(w.subs))
+	}
+}
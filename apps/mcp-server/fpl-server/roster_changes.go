@@ -0,0 +1,492 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/ledger"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/reconcile"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+)
+
+// RosterChangesArgs are the input arguments for the roster_changes tool.
+type RosterChangesArgs struct {
+	LeagueID  int     `json:"league_id" jsonschema:"Draft league id (required)"`
+	EntryID   *int    `json:"entry_id,omitempty" jsonschema:"Entry id"`
+	EntryName *string `json:"entry_name,omitempty" jsonschema:"Entry name (if entry_id not provided)"`
+	FromGW    *int    `json:"from_gw,omitempty" jsonschema:"Gameweek to diff from (default the one before to_gw)"`
+	ToGW      *int    `json:"to_gw,omitempty" jsonschema:"Gameweek to diff to (0 = latest with results)"`
+}
+
+// RosterPlayer names a player in a roster change.
+type RosterPlayer struct {
+	Element  int    `json:"element"`
+	Name     string `json:"name"`
+	Team     string `json:"team"`
+	Position string `json:"position"`
+}
+
+// RosterMoveSource is the transaction or trade behind a roster change. Kind
+// is waiver, free_agent or trade; WaiverRound is 1-based and only set when
+// the API gave the claim's round. SwappedFor is what went the other way.
+type RosterMoveSource struct {
+	Kind           string         `json:"kind"`
+	ID             int            `json:"id"`
+	GW             int            `json:"gw"`
+	WaiverRound    *int           `json:"waiver_round,omitempty"`
+	CounterpartyID int            `json:"counterparty_id,omitempty"`
+	Counterparty   string         `json:"counterparty,omitempty"`
+	SwappedFor     []RosterPlayer `json:"swapped_for"`
+}
+
+// RosterChange is a player added or removed between two snapshots. Starter
+// is their slot in the snapshot that has them; Source is nil when no move
+// explains the change (see the GW's anomalies).
+type RosterChange struct {
+	RosterPlayer
+	Starter bool              `json:"starter"`
+	Source  *RosterMoveSource `json:"source"`
+}
+
+// RosterSlotChange is a player kept between snapshots whose slot (1-11 the
+// XI, 12-15 the bench) moved across the XI/bench line.
+type RosterSlotChange struct {
+	RosterPlayer
+	FromSlot int `json:"from_slot"`
+	ToSlot   int `json:"to_slot"`
+}
+
+// RosterArmbandChange is a change of captain or vice-captain; From or To is
+// nil when the snapshot has none.
+type RosterArmbandChange struct {
+	Role string        `json:"role"`
+	From *RosterPlayer `json:"from"`
+	To   *RosterPlayer `json:"to"`
+}
+
+// RosterMultiplierChange is a kept player's multiplier change beyond the
+// 1/0 that moving between the XI and the bench gives.
+type RosterMultiplierChange struct {
+	RosterPlayer
+	From int `json:"from"`
+	To   int `json:"to"`
+}
+
+// RosterAnomaly is a roster change the moves on disk don't account for, or
+// a move the snapshot doesn't reflect. Kind is unexplained_removal,
+// unexplained_addition, move_not_applied or drop_not_applied.
+type RosterAnomaly struct {
+	RosterPlayer
+	Kind   string            `json:"kind"`
+	Source *RosterMoveSource `json:"source,omitempty"`
+	Detail string            `json:"detail"`
+}
+
+// RosterGWChanges is what changed in an entry's snapshot at GW since PrevGW,
+// the last earlier GW with a snapshot. PassedThrough lists players added and
+// dropped again between the two, so in neither snapshot.
+type RosterGWChanges struct {
+	GW              int                      `json:"gw"`
+	PrevGW          int                      `json:"prev_gw"`
+	MissingSnapshot bool                     `json:"missing_snapshot,omitempty"`
+	Added           []RosterChange           `json:"added"`
+	Removed         []RosterChange           `json:"removed"`
+	PassedThrough   []RosterChange           `json:"passed_through"`
+	ToXI            []RosterSlotChange       `json:"to_xi"`
+	ToBench         []RosterSlotChange       `json:"to_bench"`
+	Armband         []RosterArmbandChange    `json:"armband"`
+	Multipliers     []RosterMultiplierChange `json:"multipliers"`
+	Anomalies       []RosterAnomaly          `json:"anomalies"`
+}
+
+// RosterChangesOutput is the output of the roster_changes tool, one entry
+// per GW after FromGW, oldest first.
+type RosterChangesOutput struct {
+	LeagueID  int               `json:"league_id"`
+	EntryID   int               `json:"entry_id"`
+	EntryName string            `json:"entry_name"`
+	FromGW    int               `json:"from_gw"`
+	ToGW      int               `json:"to_gw"`
+	Anomalies int               `json:"anomalies"`
+	Changes   []RosterGWChanges `json:"changes"`
+	Notes     []string          `json:"notes"`
+	// PlayerNamesUnavailable is playerNames.Unavailable.
+	PlayerNamesUnavailable bool `json:"player_names_unavailable,omitempty"`
+}
+
+// rosterMove is one accepted transaction or processed trade from the
+// entry's side: the players it brought in and sent out.
+type rosterMove struct {
+	source RosterMoveSource
+	time   string
+	in     []int
+	out    []int
+}
+
+func buildRosterChanges(cfg ServerConfig, args RosterChangesArgs) (RosterChangesOutput, error) {
+	if args.LeagueID == 0 {
+		return RosterChangesOutput{}, invalidArgumentf("league_id is required")
+	}
+	requested := 0
+	if args.ToGW != nil {
+		requested = *args.ToGW
+	}
+	toGW, _, err := resolveEffectiveGW(cfg, requested, gwModeLatestFinished)
+	if err != nil {
+		return RosterChangesOutput{}, err
+	}
+	fromGW := toGW - 1
+	if args.FromGW != nil && *args.FromGW > 0 {
+		fromGW = *args.FromGW
+	}
+	if fromGW >= toGW {
+		return RosterChangesOutput{}, invalidArgumentf("from_gw (%d) must be before to_gw (%d)", fromGW, toGW)
+	}
+
+	st := store.NewJSONStore(cfg.RawRoot)
+	raw, err := st.ReadRaw(fmt.Sprintf("league/%d/details.json", args.LeagueID))
+	if err != nil {
+		return RosterChangesOutput{}, err
+	}
+	var details leagueDetailsRaw
+	if err := json.Unmarshal(raw, &details); err != nil {
+		return RosterChangesOutput{}, err
+	}
+	if start := details.League.StartEvent; fromGW < start {
+		return RosterChangesOutput{}, invalidArgumentf("league %d starts at GW %d; from_gw %d is before it", args.LeagueID, start, fromGW)
+	}
+	nameByEntry := make(map[int]string, len(details.LeagueEntries))
+	for _, e := range details.LeagueEntries {
+		nameByEntry[e.EntryID] = e.EntryName
+	}
+	entryID := 0
+	if args.EntryID != nil {
+		entryID = *args.EntryID
+	}
+	if entryID == 0 {
+		name := ""
+		if args.EntryName != nil {
+			name = strings.TrimSpace(*args.EntryName)
+		}
+		if name == "" {
+			return RosterChangesOutput{}, invalidArgumentf("entry_id or entry_name is required")
+		}
+		id, err := resolveEntry(details.LeagueEntries, name)
+		if err != nil {
+			return RosterChangesOutput{}, err
+		}
+		entryID = id
+	}
+	entryName, ok := nameByEntry[entryID]
+	if !ok {
+		return RosterChangesOutput{}, notFoundf("entry not found: %d", entryID)
+	}
+
+	transactions, err := loadTransactionsRaw(st, args.LeagueID)
+	if err != nil {
+		return RosterChangesOutput{}, err
+	}
+	trades, err := loadTradesRaw(st, args.LeagueID)
+	if err != nil {
+		return RosterChangesOutput{}, err
+	}
+	players, err := loadPlayerNames(cfg.RawRoot)
+	if err != nil {
+		return RosterChangesOutput{}, err
+	}
+	player := func(id int) RosterPlayer {
+		meta, ok := players.get(id)
+		if !ok {
+			return RosterPlayer{Element: id, Name: fmt.Sprintf("element %d", id)}
+		}
+		p := RosterPlayer{Element: id, Name: meta.Name, Team: players.team(meta.TeamID)}
+		if meta.PositionType != 0 {
+			p.Position = positionLabel(meta.PositionType)
+		}
+		return p
+	}
+	moves := entryRosterMoves(entryID, transactions, trades, nameByEntry, player)
+
+	out := RosterChangesOutput{
+		LeagueID:               args.LeagueID,
+		EntryID:                entryID,
+		EntryName:              entryName,
+		FromGW:                 fromGW,
+		ToGW:                   toGW,
+		Changes:                make([]RosterGWChanges, 0, toGW-fromGW),
+		Notes:                  []string{},
+		PlayerNamesUnavailable: players.Unavailable,
+	}
+
+	prev, err := loadEntrySnapshot(cfg, args.LeagueID, entryID, fromGW)
+	if err != nil {
+		return RosterChangesOutput{}, err
+	}
+	if prev.Missing {
+		return RosterChangesOutput{}, invalidArgumentf("entry %d has no picks before GW %d (joined the league mid-season); start from_gw there", entryID, prev.FirstAvailableGW)
+	}
+	prevGW := fromGW
+	for gw := fromGW + 1; gw <= toGW; gw++ {
+		snap, err := loadEntrySnapshot(cfg, args.LeagueID, entryID, gw)
+		if err != nil || snap.Missing {
+			ch := newRosterGWChanges(gw, prevGW)
+			ch.MissingSnapshot = true
+			out.Changes = append(out.Changes, ch)
+			out.Notes = append(out.Notes, fmt.Sprintf("no GW %d snapshot; its moves are counted in the next GW with one", gw))
+			continue
+		}
+		ch := diffRosterSnapshots(prev, snap, prevGW, moves, player)
+		out.Anomalies += len(ch.Anomalies)
+		out.Changes = append(out.Changes, ch)
+		prev, prevGW = snap, gw
+	}
+	if out.Anomalies > 0 {
+		out.Notes = append(out.Notes, "anomalies are roster changes no transaction or trade on disk explains, or moves the snapshot doesn't show; the derived reconcile report checks the whole league GW by GW")
+	}
+	return out, nil
+}
+
+// entryRosterMoves returns entryID's accepted waivers and free-agent moves
+// and its processed trades in the order the ledger applies them: by GW, then
+// time, then id.
+func entryRosterMoves(entryID int, transactions []reconcile.Transaction, trades []reconcile.Trade, nameByEntry map[int]string, player func(int) RosterPlayer) []rosterMove {
+	moves := make([]rosterMove, 0)
+	for _, tx := range transactions {
+		if tx.Entry != entryID || tx.Result != "a" || (tx.Kind != "w" && tx.Kind != "f") {
+			continue
+		}
+		m := rosterMove{
+			source: RosterMoveSource{Kind: "free_agent", ID: tx.ID, GW: tx.Event, SwappedFor: []RosterPlayer{}},
+			time:   tx.Added,
+		}
+		if tx.Kind == "w" {
+			m.source.Kind = "waiver"
+			if tx.Index != nil {
+				round := *tx.Index + 1
+				m.source.WaiverRound = &round
+			}
+		}
+		if tx.ElementIn != 0 {
+			m.in = append(m.in, tx.ElementIn)
+		}
+		if tx.ElementOut != 0 {
+			m.out = append(m.out, tx.ElementOut)
+		}
+		moves = append(moves, m)
+	}
+	for _, tr := range trades {
+		if tr.State != "p" || (tr.OfferedEntry != entryID && tr.ReceivedEntry != entryID) {
+			continue
+		}
+		other := tr.ReceivedEntry
+		if other == entryID {
+			other = tr.OfferedEntry
+		}
+		m := rosterMove{
+			source: RosterMoveSource{Kind: "trade", ID: tr.ID, GW: tr.Event, CounterpartyID: other, Counterparty: nameByEntry[other], SwappedFor: []RosterPlayer{}},
+			time:   tr.ResponseTime,
+		}
+		// The offering entry gives element_out and gets element_in.
+		for _, item := range tr.TradeItems {
+			give, get := item.ElementOut, item.ElementIn
+			if tr.ReceivedEntry == entryID {
+				give, get = get, give
+			}
+			if get != 0 {
+				m.in = append(m.in, get)
+			}
+			if give != 0 {
+				m.out = append(m.out, give)
+			}
+		}
+		moves = append(moves, m)
+	}
+	sort.SliceStable(moves, func(i, j int) bool {
+		a, b := moves[i], moves[j]
+		if a.source.GW != b.source.GW {
+			return a.source.GW < b.source.GW
+		}
+		if a.time != b.time {
+			return a.time < b.time
+		}
+		return a.source.ID < b.source.ID
+	})
+	return moves
+}
+
+// diffRosterSnapshots compares the snapshots for prevGW and next.Gameweek,
+// matching each added or removed player to the last move in between that
+// brought them in or sent them out. Matching is by player, not position, so
+// several moves at one position in a week still pair up exactly.
+func diffRosterSnapshots(prev, next ledger.EntrySnapshot, prevGW int, moves []rosterMove, player func(int) RosterPlayer) RosterGWChanges {
+	gw := next.Gameweek
+	ch := newRosterGWChanges(gw, prevGW)
+	before := make(map[int]ledger.EntryPick, len(prev.Picks))
+	for _, p := range prev.Picks {
+		before[p.Element] = p
+	}
+	after := make(map[int]ledger.EntryPick, len(next.Picks))
+	for _, p := range next.Picks {
+		after[p.Element] = p
+	}
+
+	// Replay the window's moves over the earlier roster, remembering the
+	// last move to bring in or send out each player.
+	expected := make(map[int]bool, len(before))
+	for id := range before {
+		expected[id] = true
+	}
+	lastIn := make(map[int]*RosterMoveSource)
+	lastOut := make(map[int]*RosterMoveSource)
+	for i := range moves {
+		m := &moves[i]
+		if m.source.GW <= prevGW || m.source.GW > gw {
+			continue
+		}
+		src := m.source
+		for _, id := range m.out {
+			src.SwappedFor = append(src.SwappedFor, player(id))
+		}
+		inSrc := src
+		outSrc := m.source
+		for _, id := range m.in {
+			outSrc.SwappedFor = append(outSrc.SwappedFor, player(id))
+		}
+		for _, id := range m.out {
+			delete(expected, id)
+			lastOut[id] = &outSrc
+		}
+		for _, id := range m.in {
+			expected[id] = true
+			lastIn[id] = &inSrc
+		}
+	}
+
+	for _, id := range sortedPickIDs(after) {
+		if _, ok := before[id]; ok {
+			continue
+		}
+		c := RosterChange{RosterPlayer: player(id), Starter: after[id].Position <= 11, Source: lastIn[id]}
+		ch.Added = append(ch.Added, c)
+		if c.Source == nil {
+			ch.Anomalies = append(ch.Anomalies, RosterAnomaly{RosterPlayer: c.RosterPlayer, Kind: "unexplained_addition",
+				Detail: fmt.Sprintf("on the GW %d roster but no transaction or trade since GW %d brought them in", gw, prevGW)})
+		}
+	}
+	for _, id := range sortedPickIDs(before) {
+		if _, ok := after[id]; ok {
+			continue
+		}
+		c := RosterChange{RosterPlayer: player(id), Starter: before[id].Position <= 11, Source: lastOut[id]}
+		ch.Removed = append(ch.Removed, c)
+		if c.Source == nil {
+			ch.Anomalies = append(ch.Anomalies, RosterAnomaly{RosterPlayer: c.RosterPlayer, Kind: "unexplained_removal",
+				Detail: fmt.Sprintf("gone from the GW %d roster but no transaction or trade since GW %d sent them out", gw, prevGW)})
+		}
+	}
+	// Players a move touched whose end state the snapshot disagrees with.
+	touched := make([]int, 0, len(lastIn)+len(lastOut))
+	for id := range lastIn {
+		touched = append(touched, id)
+	}
+	for id := range lastOut {
+		if _, ok := lastIn[id]; !ok {
+			touched = append(touched, id)
+		}
+	}
+	sort.Ints(touched)
+	for _, id := range touched {
+		_, was := before[id]
+		_, is := after[id]
+		switch {
+		case !was && !is && expected[id]:
+			ch.Anomalies = append(ch.Anomalies, RosterAnomaly{RosterPlayer: player(id), Kind: "move_not_applied", Source: lastIn[id],
+				Detail: fmt.Sprintf("%s %d brought them in but the GW %d roster doesn't have them", moveLabel(lastIn[id]), lastIn[id].ID, gw)})
+		case !was && !is:
+			ch.PassedThrough = append(ch.PassedThrough, RosterChange{RosterPlayer: player(id), Source: lastOut[id]})
+		case was && is && !expected[id]:
+			ch.Anomalies = append(ch.Anomalies, RosterAnomaly{RosterPlayer: player(id), Kind: "drop_not_applied", Source: lastOut[id],
+				Detail: fmt.Sprintf("%s %d sent them out but they are still on the GW %d roster", moveLabel(lastOut[id]), lastOut[id].ID, gw)})
+		}
+	}
+
+	for _, id := range sortedPickIDs(after) {
+		old, ok := before[id]
+		if !ok {
+			continue
+		}
+		cur := after[id]
+		switch {
+		case old.Position > 11 && cur.Position <= 11:
+			ch.ToXI = append(ch.ToXI, RosterSlotChange{RosterPlayer: player(id), FromSlot: old.Position, ToSlot: cur.Position})
+		case old.Position <= 11 && cur.Position > 11:
+			ch.ToBench = append(ch.ToBench, RosterSlotChange{RosterPlayer: player(id), FromSlot: old.Position, ToSlot: cur.Position})
+		}
+		if old.Multiplier != cur.Multiplier && max(old.Multiplier, cur.Multiplier) > 1 {
+			ch.Multipliers = append(ch.Multipliers, RosterMultiplierChange{RosterPlayer: player(id), From: old.Multiplier, To: cur.Multiplier})
+		}
+	}
+	armband := func(role string, pick func(ledger.EntryPick) bool) {
+		from, to := 0, 0
+		for _, p := range prev.Picks {
+			if pick(p) {
+				from = p.Element
+			}
+		}
+		for _, p := range next.Picks {
+			if pick(p) {
+				to = p.Element
+			}
+		}
+		if from == to {
+			return
+		}
+		change := RosterArmbandChange{Role: role}
+		if from != 0 {
+			p := player(from)
+			change.From = &p
+		}
+		if to != 0 {
+			p := player(to)
+			change.To = &p
+		}
+		ch.Armband = append(ch.Armband, change)
+	}
+	armband("captain", func(p ledger.EntryPick) bool { return p.IsCaptain })
+	armband("vice_captain", func(p ledger.EntryPick) bool { return p.IsViceCaptain })
+	return ch
+}
+
+func newRosterGWChanges(gw int, prevGW int) RosterGWChanges {
+	return RosterGWChanges{
+		GW:            gw,
+		PrevGW:        prevGW,
+		Added:         []RosterChange{},
+		Removed:       []RosterChange{},
+		PassedThrough: []RosterChange{},
+		ToXI:          []RosterSlotChange{},
+		ToBench:       []RosterSlotChange{},
+		Armband:       []RosterArmbandChange{},
+		Multipliers:   []RosterMultiplierChange{},
+		Anomalies:     []RosterAnomaly{},
+	}
+}
+
+// moveLabel names a move's kind for an anomaly's detail.
+func moveLabel(src *RosterMoveSource) string {
+	if src.Kind == "trade" {
+		return "trade"
+	}
+	return "transaction"
+}
+
+func sortedPickIDs(picks map[int]ledger.EntryPick) []int {
+	ids := make([]int, 0, len(picks))
+	for id := range picks {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
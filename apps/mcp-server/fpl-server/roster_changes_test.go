@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+)
+
+// writeRosterChangesFixture writes league 100 with Alpha (200) and Beta
+// (201) and Alpha's GW1-3 picks. In GW2 Alpha claimed 6 for 4 on waivers and
+// then swapped 6 for 5 as a free agent, moved 2 to the bench for 3 and gave
+// the armband to 3. In GW3 Alpha traded 5 to Beta for 7; 2 vanished with no
+// move, and a free-agent swap of 3 for 8 never reached the snapshot.
+func writeRosterChangesFixture(t *testing.T, dir string) {
+	t.Helper()
+	elements := []any{}
+	for id := 1; id <= 8; id++ {
+		elements = append(elements, map[string]any{"id": id, "web_name": fmt.Sprintf("P%d", id), "team": 1, "element_type": 1 + id%4, "status": "a"})
+	}
+	writeJSON(t, filepath.Join(dir, "bootstrap", "bootstrap-static.json"), map[string]any{
+		"elements": elements,
+		"teams":    []any{map[string]any{"id": 1, "short_name": "ARS"}},
+	})
+	writeFullGameJSON(t, dir, 3, true, 4, false, "")
+	writeLeagueDetailsFixture(t, dir, 100, []any{
+		map[string]any{"id": 1, "entry_id": 200, "entry_name": "Alpha FC", "short_name": "AFC"},
+		map[string]any{"id": 2, "entry_id": 201, "entry_name": "Beta FC", "short_name": "BFC"},
+	}, []any{})
+	pick := func(el, pos int, captain bool) map[string]any {
+		mult := 1
+		if pos > 11 {
+			mult = 0
+		}
+		if captain {
+			mult = 2
+		}
+		return map[string]any{"element": el, "position": pos, "multiplier": mult, "is_captain": captain}
+	}
+	snapshots := map[int][]any{
+		1: {pick(1, 1, true), pick(2, 2, false), pick(4, 3, false), pick(3, 12, false)},
+		2: {pick(1, 1, false), pick(3, 2, true), pick(5, 3, false), pick(2, 12, false)},
+		3: {pick(1, 1, false), pick(3, 2, true), pick(7, 3, false)},
+	}
+	for gw, picks := range snapshots {
+		writeJSON(t, filepath.Join(dir, fmt.Sprintf("entry/200/gw/%d.json", gw)), map[string]any{"picks": picks, "subs": []any{}})
+	}
+	writeJSON(t, filepath.Join(dir, "league/100/transactions.json"), map[string]any{"transactions": []any{
+		map[string]any{"id": 10, "entry": 200, "event": 2, "kind": "w", "result": "a", "element_in": 6, "element_out": 4, "index": 0, "added": "2025-08-20T10:00:00Z"},
+		map[string]any{"id": 11, "entry": 200, "event": 2, "kind": "f", "result": "a", "element_in": 5, "element_out": 6, "added": "2025-08-21T10:00:00Z"},
+		map[string]any{"id": 12, "entry": 200, "event": 2, "kind": "w", "result": "do", "element_in": 8, "element_out": 1, "index": 1, "added": "2025-08-20T10:00:00Z"},
+		map[string]any{"id": 13, "entry": 200, "event": 3, "kind": "f", "result": "a", "element_in": 8, "element_out": 3, "added": "2025-08-28T10:00:00Z"},
+	}})
+	writeJSON(t, filepath.Join(dir, "league/100/trades.json"), map[string]any{"trades": []any{
+		map[string]any{"id": 20, "event": 3, "offered_entry": 200, "received_entry": 201, "state": "p", "response_time": "2025-08-27T10:00:00Z",
+			"tradeitem_set": []any{map[string]any{"element_out": 5, "element_in": 7}}},
+	}})
+}
+
+func TestBuildRosterChanges(t *testing.T) {
+	dir, cfg := tmpCfg(t)
+	cfg.DerivedRoot = filepath.Join(dir, "derived")
+	cfg.ComputeMissing = true
+	writeRosterChangesFixture(t, dir)
+
+	entry, from := 200, 1
+	out, err := buildRosterChanges(cfg, RosterChangesArgs{LeagueID: 100, EntryID: &entry, FromGW: &from})
+	if err != nil {
+		t.Fatalf("buildRosterChanges: %v", err)
+	}
+	if out.FromGW != 1 || out.ToGW != 3 || len(out.Changes) != 2 || out.Changes[0].GW != 2 || out.Changes[1].PrevGW != 2 {
+		t.Fatalf("out = %+v", out)
+	}
+
+	gw2 := out.Changes[0]
+	if len(gw2.Added) != 1 || gw2.Added[0].Element != 5 || gw2.Added[0].Source == nil || gw2.Added[0].Source.ID != 11 || gw2.Added[0].Source.Kind != "free_agent" {
+		t.Errorf("GW2 added = %+v, want 5 from free-agent move 11", gw2.Added)
+	}
+	removed := gw2.Removed
+	if len(removed) != 1 || removed[0].Element != 4 || removed[0].Source == nil || removed[0].Source.Kind != "waiver" {
+		t.Fatalf("GW2 removed = %+v, want 4 by waiver 10", removed)
+	}
+	if src := removed[0].Source; src.ID != 10 || src.WaiverRound == nil || *src.WaiverRound != 1 || len(src.SwappedFor) != 1 || src.SwappedFor[0].Element != 6 {
+		t.Errorf("GW2 removal source = %+v, want waiver 10 in round 1 for 6", src)
+	}
+	if len(gw2.PassedThrough) != 1 || gw2.PassedThrough[0].Element != 6 || gw2.PassedThrough[0].Source.ID != 11 {
+		t.Errorf("GW2 passed through = %+v, want 6 dropped by move 11", gw2.PassedThrough)
+	}
+	if len(gw2.ToXI) != 1 || gw2.ToXI[0].Element != 3 || len(gw2.ToBench) != 1 || gw2.ToBench[0].Element != 2 {
+		t.Errorf("GW2 slots: to XI %+v, to bench %+v", gw2.ToXI, gw2.ToBench)
+	}
+	if len(gw2.Armband) != 1 || gw2.Armband[0].From.Element != 1 || gw2.Armband[0].To.Element != 3 {
+		t.Errorf("GW2 armband = %+v, want captain 1 -> 3", gw2.Armband)
+	}
+	if len(gw2.Multipliers) != 2 || len(gw2.Anomalies) != 0 {
+		t.Errorf("GW2 multipliers = %+v anomalies = %+v", gw2.Multipliers, gw2.Anomalies)
+	}
+
+	gw3 := out.Changes[1]
+	if len(gw3.Added) != 1 || gw3.Added[0].Source == nil || gw3.Added[0].Source.Kind != "trade" || gw3.Added[0].Source.Counterparty != "Beta FC" {
+		t.Errorf("GW3 added = %+v, want 7 by trade with Beta FC", gw3.Added)
+	}
+	kinds := map[string]int{}
+	for _, a := range gw3.Anomalies {
+		kinds[a.Kind] = a.Element
+	}
+	want := map[string]int{"unexplained_removal": 2, "move_not_applied": 8, "drop_not_applied": 3}
+	if fmt.Sprint(kinds) != fmt.Sprint(want) || out.Anomalies != 3 {
+		t.Errorf("GW3 anomalies = %+v, want %v", gw3.Anomalies, want)
+	}
+
+	name, last := "Alpha FC", 3
+	if _, err := buildRosterChanges(cfg, RosterChangesArgs{LeagueID: 100, EntryName: &name, FromGW: &last}); classifyError(err).Code != codeInvalidArgument {
+		t.Errorf("from_gw == to_gw: err = %v, want INVALID_ARGUMENT", err)
+	}
+	unknown := 999
+	if _, err := buildRosterChanges(cfg, RosterChangesArgs{LeagueID: 100, EntryID: &unknown}); classifyError(err).Code != codeNotFound {
+		t.Errorf("unknown entry: err = %v, want NOT_FOUND", err)
+	}
+}
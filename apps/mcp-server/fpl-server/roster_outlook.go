@@ -0,0 +1,312 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/projection"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/scoring"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+)
+
+const (
+	// outlookMinMultiplier/outlookMaxMultiplier clamp a single fixture's
+	// difficulty multiplier so one extreme opponent can't dominate.
+	outlookMinMultiplier = 0.5
+	outlookMaxMultiplier = 1.5
+)
+
+type RosterOutlookArgs struct {
+	LeagueID   int    `json:"league_id" jsonschema:"Draft league id (required)"`
+	EntryID    int    `json:"entry_id" jsonschema:"Entry id (required)"`
+	FormWindow *int   `json:"form_window,omitempty" jsonschema:"GWs of form used for the points baseline (default 5)"`
+	ThroughGW  *int   `json:"through_gw,omitempty" jsonschema:"Last gameweek to project (default = last GW with fixtures)"`
+	Model      string `json:"model,omitempty" jsonschema:"Projection model: heuristic|poisson (default heuristic)"`
+}
+
+// OutlookGW is one remaining gameweek in a player's projection. Multiplier is
+// the sum of per-fixture difficulty multipliers: 0 in a blank, ~2 in a double.
+type OutlookGW struct {
+	GW         int      `json:"gw"`
+	Fixtures   int      `json:"fixtures"`
+	Opponents  []string `json:"opponents,omitempty"`
+	Multiplier float64  `json:"multiplier"`
+	Projected  float64  `json:"projected"`
+	Blank      bool     `json:"blank,omitempty"`
+	Double     bool     `json:"double,omitempty"`
+}
+
+type RosterOutlookPlayer struct {
+	Element            int         `json:"element"`
+	Name               string      `json:"name"`
+	Team               string      `json:"team"`
+	PositionType       int         `json:"position_type"`
+	BaselinePerFixture float64     `json:"baseline_per_fixture"`
+	Projected          float64     `json:"projected_points"`
+	Variance           float64     `json:"variance"`
+	Blanks             int         `json:"blanks"`
+	Doubles            int         `json:"doubles"`
+	Schedule           []OutlookGW `json:"schedule"`
+}
+
+type RosterOutlookOutput struct {
+	LeagueID        int                   `json:"league_id"`
+	EntryID         int                   `json:"entry_id"`
+	AsOfGW          int                   `json:"as_of_gw"`
+	FromGW          int                   `json:"from_gw"`
+	ThroughGW       int                   `json:"through_gw"`
+	FormWindow      int                   `json:"form_window"`
+	Model           string                `json:"model"`
+	Scoring         ProjectionScoring     `json:"scoring"`
+	Players         []RosterOutlookPlayer `json:"players"`
+	TeamTotal       float64               `json:"team_total"`
+	LeagueAverage   float64               `json:"league_average"`
+	VsLeagueAverage float64               `json:"vs_league_average"`
+	LeagueRank      int                   `json:"league_rank"`
+	LeagueEntries   int                   `json:"league_entries"`
+	Notes           []string              `json:"notes"`
+}
+
+// outlookMultiplier scores one fixture for a position, relative to the average
+// fixture for that position (1.0 = average).
+type outlookMultiplier func(opponentID int, venue string, pos int) float64
+
+func buildRosterOutlook(cfg ServerConfig, args RosterOutlookArgs) (RosterOutlookOutput, error) {
+	if args.LeagueID == 0 {
+		return RosterOutlookOutput{}, invalidArgumentf("league_id is required")
+	}
+	if args.EntryID == 0 {
+		return RosterOutlookOutput{}, invalidArgumentf("entry_id is required")
+	}
+	window := 5
+	if args.FormWindow != nil && *args.FormWindow > 0 {
+		window = *args.FormWindow
+	}
+	modelName, err := parseProjectionModel(args.Model)
+	if err != nil {
+		return RosterOutlookOutput{}, err
+	}
+
+	asOfGW, nextGW, err := resolveAsOfAndNextGW(cfg, 0, 0)
+	if err != nil {
+		return RosterOutlookOutput{}, err
+	}
+	elements, teamShort, fixturesByGW, err := loadBootstrapData(cfg.RawRoot)
+	if err != nil {
+		return RosterOutlookOutput{}, err
+	}
+
+	throughGW := 0
+	for gw := range fixturesByGW {
+		if gw > throughGW {
+			throughGW = gw
+		}
+	}
+	if args.ThroughGW != nil && *args.ThroughGW > 0 {
+		throughGW = *args.ThroughGW
+	}
+	// GWs with no fixtures listed at all are unknown (not yet scheduled or
+	// missing from bootstrap), not a league-wide blank, so they're skipped.
+	gws := make([]int, 0)
+	indexByGW := make(map[int]map[int][]FixtureContext)
+	for gw := nextGW; gw <= throughGW; gw++ {
+		if len(fixturesByGW[gw]) == 0 {
+			continue
+		}
+		gws = append(gws, gw)
+		indexByGW[gw] = buildFixtureIndex(fixturesByGW[gw], teamShort)
+	}
+
+	ownership, err := loadOwnershipAtGW(cfg, args.LeagueID, resolveRosterGW(asOfGW, nextGW))
+	if err != nil {
+		return RosterOutlookOutput{}, err
+	}
+	if _, ok := ownership[args.EntryID]; !ok {
+		return RosterOutlookOutput{}, notFoundf("entry %d not found in league %d", args.EntryID, args.LeagueID)
+	}
+
+	rules, err := loadScoringRules(cfg, args.LeagueID)
+	if err != nil {
+		return RosterOutlookOutput{}, err
+	}
+	mult := fixtureMultiplierFunc(cfg.RawRoot, elements, teamShort, asOfGW, window)
+	model, history, err := loadProjectionModel(cfg.RawRoot, modelName, elements, indexByGW, mult, asOfGW, window, rules.Rules)
+	if err != nil {
+		return RosterOutlookOutput{}, err
+	}
+
+	elementByID := make(map[int]elementInfo, len(elements))
+	for _, e := range elements {
+		elementByID[e.ID] = e
+	}
+	projections := make(map[int]RosterOutlookPlayer)
+	project := func(id int) (RosterOutlookPlayer, bool) {
+		if p, ok := projections[id]; ok {
+			return p, true
+		}
+		info, ok := elementByID[id]
+		if !ok {
+			return RosterOutlookPlayer{}, false
+		}
+		p := projectRestOfSeason(info, teamShort[info.TeamID], model, gws, indexByGW, mult)
+		p.BaselinePerFixture = history[id].PointsPerFixture()
+		projections[id] = p
+		return p, true
+	}
+
+	out := RosterOutlookOutput{
+		LeagueID:   args.LeagueID,
+		EntryID:    args.EntryID,
+		AsOfGW:     asOfGW,
+		FromGW:     nextGW,
+		ThroughGW:  throughGW,
+		FormWindow: window,
+		Model:      modelName,
+		Players:    make([]RosterOutlookPlayer, 0, len(ownership[args.EntryID])),
+		Notes: []string{
+			projectionNote(modelName),
+			fmt.Sprintf("Fixture multipliers are blended points conceded by position relative to the position average, clamped to [%.1f, %.1f].", outlookMinMultiplier, outlookMaxMultiplier),
+			"Blank GWs contribute 0; double GWs contribute both fixtures.",
+		},
+	}
+	for id := range ownership[args.EntryID] {
+		if p, ok := project(id); ok {
+			out.Players = append(out.Players, p)
+			out.TeamTotal += p.Projected
+		}
+	}
+	sort.Slice(out.Players, func(i, j int) bool {
+		if out.Players[i].Projected != out.Players[j].Projected {
+			return out.Players[i].Projected > out.Players[j].Projected
+		}
+		return out.Players[i].Element < out.Players[j].Element
+	})
+
+	totals := make([]float64, 0, len(ownership))
+	for _, roster := range ownership {
+		total := 0.0
+		for id := range roster {
+			if p, ok := project(id); ok {
+				total += p.Projected
+			}
+		}
+		totals = append(totals, total)
+	}
+	out.LeagueEntries = len(totals)
+	out.LeagueRank = 1
+	sum := 0.0
+	for _, t := range totals {
+		sum += t
+		if t > out.TeamTotal {
+			out.LeagueRank++
+		}
+	}
+	if len(totals) > 0 {
+		out.LeagueAverage = sum / float64(len(totals))
+	}
+	out.VsLeagueAverage = out.TeamTotal - out.LeagueAverage
+	return out, nil
+}
+
+// projectionNote describes how model turns form into a GW projection.
+func projectionNote(model string) string {
+	if model == projection.ModelPoisson {
+		return "Projection (poisson) = expected goals, assists, clean sheets, goals conceded, saves, bonus and defensive contributions from per-90 rates over the form window, scaled by expected minutes and fixture multiplier, at FPL scoring."
+	}
+	return "Projection = points per fixture over the form window × sum of per-fixture difficulty multipliers for each remaining GW."
+}
+
+// projectRestOfSeason lays out each remaining GW's fixtures and projects them
+// with model; a nil model leaves the projections at zero. A team with no
+// fixture in a GW blanks; two or more is a double.
+func projectRestOfSeason(info elementInfo, team string, model projection.Model, gws []int, indexByGW map[int]map[int][]FixtureContext, mult outlookMultiplier) RosterOutlookPlayer {
+	p := RosterOutlookPlayer{
+		Element:      info.ID,
+		Name:         info.Name,
+		Team:         team,
+		PositionType: info.PositionType,
+		Schedule:     make([]OutlookGW, 0, len(gws)),
+	}
+	for _, gw := range gws {
+		fixtures := indexByGW[gw][info.TeamID]
+		row := OutlookGW{GW: gw, Fixtures: len(fixtures)}
+		for _, fx := range fixtures {
+			row.Multiplier += mult(fx.OpponentID, fx.Venue, info.PositionType)
+			row.Opponents = append(row.Opponents, fmt.Sprintf("%s (%s)", fx.OpponentShort, fx.Venue[:1]))
+		}
+		switch {
+		case len(fixtures) == 0:
+			row.Blank = true
+			p.Blanks++
+		case len(fixtures) > 1:
+			row.Double = true
+			p.Doubles++
+		}
+		if model != nil {
+			proj := model.ProjectPlayer(info.ID, gw)
+			row.Projected = proj.ExpectedPoints
+			p.Variance += proj.Variance
+		}
+		p.Projected += row.Projected
+		p.Schedule = append(p.Schedule, row)
+	}
+	return p
+}
+
+// baselinePointsPerFixture is each player's points per team fixture over the
+// window ending at asOfGW, the heuristic model's baseline. Dividing by
+// fixtures rather than GWs keeps a past double gameweek from inflating it.
+// GWs where the player is absent from live data are skipped.
+func baselinePointsPerFixture(rawRoot string, elements []elementInfo, asOfGW int, window int) map[int]float64 {
+	history := projection.LoadHistory(store.NewJSONStore(rawRoot), projectionPlayers(elements), asOfGW, window, scoring.Default())
+	out := make(map[int]float64, len(history))
+	for id, h := range history {
+		out[id] = h.PointsPerFixture()
+	}
+	return out
+}
+
+// fixtureMultiplierFunc builds an outlookMultiplier from season and recent
+// points conceded, normalized by the mean blended score across every team and
+// venue for the position.
+func fixtureMultiplierFunc(rawRoot string, elements []elementInfo, teamShort map[int]string, asOfGW int, window int) outlookMultiplier {
+	seasonWeight, recentWeight := horizonWeights(window)
+	concededSeason := computePointsConcededByPosition(rawRoot, elements, asOfGW, asOfGW)
+	concededRecent := computePointsConcededByPosition(rawRoot, elements, asOfGW, window)
+
+	posMean := make(map[int]float64)
+	for pos := 1; pos <= 4; pos++ {
+		sum, n := 0.0, 0
+		for teamID := range teamShort {
+			for _, venue := range []string{"HOME", "AWAY"} {
+				_, _, b := blendedFixtureScore(concededSeason, concededRecent, teamID, venue, pos, seasonWeight, recentWeight)
+				if b > 0 {
+					sum += b
+					n++
+				}
+			}
+		}
+		if n > 0 {
+			posMean[pos] = sum / float64(n)
+		}
+	}
+
+	return func(opponentID int, venue string, pos int) float64 {
+		mean := posMean[pos]
+		if mean == 0 {
+			return 1
+		}
+		_, _, b := blendedFixtureScore(concededSeason, concededRecent, opponentID, venue, pos, seasonWeight, recentWeight)
+		if b == 0 {
+			return 1
+		}
+		m := b / mean
+		if m < outlookMinMultiplier {
+			return outlookMinMultiplier
+		}
+		if m > outlookMaxMultiplier {
+			return outlookMaxMultiplier
+		}
+		return m
+	}
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"math"
+	"path/filepath"
+	"testing"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/projection"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/scoring"
+)
+
+func approxEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestProjectRestOfSeason_BlankAndDouble(t *testing.T) {
+	info := elementInfo{ID: 1, Name: "Salah", TeamID: 10, PositionType: 3}
+	teamShort := map[int]string{10: "LIV", 11: "MCI", 12: "ARS"}
+	indexByGW := map[int]map[int][]FixtureContext{
+		3: buildFixtureIndex([]fixture{{ID: 1, Event: 3, TeamH: 10, TeamA: 11}}, teamShort),
+		4: buildFixtureIndex([]fixture{{ID: 2, Event: 4, TeamH: 12, TeamA: 11}}, teamShort),
+		5: buildFixtureIndex([]fixture{
+			{ID: 3, Event: 5, TeamH: 10, TeamA: 12},
+			{ID: 4, Event: 5, TeamH: 11, TeamA: 10},
+		}, teamShort),
+	}
+	flat := func(int, string, int) float64 { return 1 }
+
+	model := projection.NewHeuristic(projectionEnv([]elementInfo{info}, indexByGW, flat, map[int]projection.History{1: {Fixtures: 1, Points: 6}}, scoring.Default()))
+
+	p := projectRestOfSeason(info, "LIV", model, []int{3, 4, 5}, indexByGW, flat)
+
+	if p.Blanks != 1 || p.Doubles != 1 {
+		t.Errorf("blanks=%d doubles=%d, want 1 and 1", p.Blanks, p.Doubles)
+	}
+	if len(p.Schedule) != 3 {
+		t.Fatalf("schedule = %d rows, want 3", len(p.Schedule))
+	}
+	if gw4 := p.Schedule[1]; !gw4.Blank || gw4.Projected != 0 {
+		t.Errorf("GW4 = %+v, want blank with 0 projected", gw4)
+	}
+	if gw5 := p.Schedule[2]; !gw5.Double || gw5.Fixtures != 2 || !approxEqual(gw5.Projected, 12) {
+		t.Errorf("GW5 = %+v, want double projecting 12", gw5)
+	}
+	if !approxEqual(p.Projected, 6+0+12) {
+		t.Errorf("projected = %v, want 18", p.Projected)
+	}
+	if got := p.Schedule[0].Opponents; len(got) != 1 || got[0] != "MCI (H)" {
+		t.Errorf("GW3 opponents = %v, want [MCI (H)]", got)
+	}
+}
+
+func TestProjectRestOfSeason_UsesMultiplier(t *testing.T) {
+	info := elementInfo{ID: 2, TeamID: 11, PositionType: 4}
+	teamShort := map[int]string{10: "LIV", 11: "MCI"}
+	indexByGW := map[int]map[int][]FixtureContext{
+		3: buildFixtureIndex([]fixture{{ID: 1, Event: 3, TeamH: 10, TeamA: 11}}, teamShort),
+	}
+	awayBoost := func(_ int, venue string, _ int) float64 {
+		if venue == "AWAY" {
+			return 1.5
+		}
+		return 1
+	}
+	model := projection.NewHeuristic(projectionEnv([]elementInfo{info}, indexByGW, awayBoost, map[int]projection.History{2: {Fixtures: 1, Points: 4}}, scoring.Default()))
+	p := projectRestOfSeason(info, "MCI", model, []int{3}, indexByGW, awayBoost)
+	if !approxEqual(p.Projected, 6) {
+		t.Errorf("projected = %v, want 6 (4 × 1.5)", p.Projected)
+	}
+}
+
+func TestBaselinePointsPerFixture_DoubleGameweekNotInflated(t *testing.T) {
+	dir, _ := tmpCfg(t)
+	elements := []elementInfo{{ID: 1, TeamID: 10, PositionType: 3}}
+	writeJSON(t, filepath.Join(dir, "gw/1/live.json"), map[string]any{
+		"elements": map[string]any{"1": map[string]any{"stats": map[string]any{"total_points": 6}}},
+		"fixtures": []any{map[string]any{"id": 1, "team_h": 10, "team_a": 11}},
+	})
+	// GW2 is a double: 10 points over two fixtures.
+	writeJSON(t, filepath.Join(dir, "gw/2/live.json"), map[string]any{
+		"elements": map[string]any{"1": map[string]any{"stats": map[string]any{"total_points": 10}}},
+		"fixtures": []any{
+			map[string]any{"id": 2, "team_h": 10, "team_a": 12},
+			map[string]any{"id": 3, "team_h": 11, "team_a": 10},
+		},
+	})
+	got := baselinePointsPerFixture(dir, elements, 2, 5)
+	if !approxEqual(got[1], 16.0/3.0) {
+		t.Errorf("baseline = %v, want 16/3 points per fixture", got[1])
+	}
+}
+
+func TestBuildRosterOutlook(t *testing.T) {
+	dir, cfg := tmpCfg(t)
+	cfg.DerivedRoot = dir
+	writeJSON(t, filepath.Join(dir, "bootstrap", "bootstrap-static.json"), map[string]any{
+		"elements": []any{
+			map[string]any{"id": 1, "web_name": "Salah", "team": 10, "element_type": 3, "status": "a"},
+			map[string]any{"id": 2, "web_name": "Haaland", "team": 11, "element_type": 4, "status": "a"},
+			map[string]any{"id": 3, "web_name": "Alexander-Arnold", "team": 10, "element_type": 2, "status": "a"},
+		},
+		"teams": []any{
+			map[string]any{"id": 10, "short_name": "LIV"},
+			map[string]any{"id": 11, "short_name": "MCI"},
+			map[string]any{"id": 12, "short_name": "ARS"},
+		},
+		"fixtures": map[string]any{
+			"3": []any{map[string]any{"id": 5, "team_h": 10, "team_a": 11}},
+			"4": []any{map[string]any{"id": 6, "team_h": 12, "team_a": 11}},
+			"5": []any{
+				map[string]any{"id": 7, "team_h": 10, "team_a": 12},
+				map[string]any{"id": 8, "team_h": 11, "team_a": 10},
+			},
+		},
+	})
+	writeJSON(t, filepath.Join(dir, "game", "game.json"), map[string]any{
+		"current_event": 2, "current_event_finished": true, "next_event": 3,
+	})
+	for gw, pts := range map[int][3]int{1: {6, 8, 2}, 2: {4, 10, 6}} {
+		writeJSON(t, filepath.Join(dir, "gw", itoa(gw), "live.json"), map[string]any{
+			"elements": map[string]any{
+				"1": map[string]any{"stats": map[string]any{"total_points": pts[0]}},
+				"2": map[string]any{"stats": map[string]any{"total_points": pts[1]}},
+				"3": map[string]any{"stats": map[string]any{"total_points": pts[2]}},
+			},
+			"fixtures": []any{map[string]any{"id": gw, "team_h": 10, "team_a": 11}},
+		})
+	}
+	writeJSON(t, filepath.Join(dir, "draft/100/choices.json"), map[string]any{
+		"choices": []any{
+			map[string]any{"entry": 200, "element": 1, "round": 1, "pick": 1, "index": 1},
+			map[string]any{"entry": 201, "element": 2, "round": 1, "pick": 2, "index": 2},
+			map[string]any{"entry": 200, "element": 3, "round": 2, "pick": 1, "index": 3},
+		},
+	})
+	writeJSON(t, filepath.Join(dir, "league/100/transactions.json"), map[string]any{"transactions": []any{}})
+	writeJSON(t, filepath.Join(dir, "league/100/trades.json"), map[string]any{"trades": []any{}})
+
+	out, err := buildRosterOutlook(cfg, RosterOutlookArgs{LeagueID: 100, EntryID: 200})
+	if err != nil {
+		t.Fatalf("buildRosterOutlook: %v", err)
+	}
+	if out.FromGW != 3 || out.ThroughGW != 5 {
+		t.Errorf("range = %d..%d, want 3..5", out.FromGW, out.ThroughGW)
+	}
+	if len(out.Players) != 2 {
+		t.Fatalf("players = %d, want 2", len(out.Players))
+	}
+	for _, p := range out.Players {
+		if p.Blanks != 1 || p.Doubles != 1 {
+			t.Errorf("%s: blanks=%d doubles=%d, want LIV blank GW4 and double GW5", p.Name, p.Blanks, p.Doubles)
+		}
+		sum := 0.0
+		for _, row := range p.Schedule {
+			sum += row.Projected
+		}
+		if !approxEqual(sum, p.Projected) {
+			t.Errorf("%s: schedule sum %v != projected %v", p.Name, sum, p.Projected)
+		}
+	}
+	if out.LeagueEntries != 2 {
+		t.Errorf("league entries = %d, want 2", out.LeagueEntries)
+	}
+	if !approxEqual(out.VsLeagueAverage, out.TeamTotal-out.LeagueAverage) {
+		t.Errorf("vs league average = %v", out.VsLeagueAverage)
+	}
+
+	if out.Model != projection.ModelHeuristic {
+		t.Errorf("model = %q, want heuristic by default", out.Model)
+	}
+
+	poisson, err := buildRosterOutlook(cfg, RosterOutlookArgs{LeagueID: 100, EntryID: 200, Model: "poisson"})
+	if err != nil {
+		t.Fatalf("buildRosterOutlook poisson: %v", err)
+	}
+	if poisson.Model != projection.ModelPoisson || len(poisson.Players) != 2 {
+		t.Fatalf("poisson outlook = model %q with %d players", poisson.Model, len(poisson.Players))
+	}
+	for _, p := range poisson.Players {
+		// Live files here carry only total_points, so the Poisson model
+		// sees no minutes and projects nothing.
+		if p.Projected != 0 || p.Blanks != 1 {
+			t.Errorf("%s: poisson projected=%v blanks=%d, want 0 and 1", p.Name, p.Projected, p.Blanks)
+		}
+	}
+	if _, err := buildRosterOutlook(cfg, RosterOutlookArgs{LeagueID: 100, EntryID: 200, Model: "xgboost"}); classifyError(err).Code != codeInvalidArgument {
+		t.Errorf("bad model: err = %v, want INVALID_ARGUMENT", err)
+	}
+
+	if _, err := buildRosterOutlook(cfg, RosterOutlookArgs{LeagueID: 100, EntryID: 999}); classifyError(err).Code != codeNotFound {
+		t.Errorf("unknown entry: err = %v, want NOT_FOUND", err)
+	}
+}
+
+func TestBuildRosterOutlook_MissingArgs(t *testing.T) {
+	_, cfg := tmpCfg(t)
+	if _, err := buildRosterOutlook(cfg, RosterOutlookArgs{EntryID: 1}); err == nil {
+		t.Fatal("expected error when league_id is missing")
+	}
+	if _, err := buildRosterOutlook(cfg, RosterOutlookArgs{LeagueID: 1}); err == nil {
+		t.Fatal("expected error when entry_id is missing")
+	}
+}
@@ -0,0 +1,270 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+)
+
+const (
+	// swingFormWindow is the recent-form window (GWs) fed into the
+	// season/recent points-conceded blend, matching fixture_difficulty.
+	swingFormWindow = 5
+	// swingFlatThreshold is the smallest average-multiplier change reported
+	// as a real swing rather than "flat".
+	swingFlatThreshold = 0.05
+	// swingTargets is how many unrostered players are listed per team.
+	swingTargets = 2
+)
+
+type ScheduleSwingArgs struct {
+	LeagueID     int  `json:"league_id" jsonschema:"Draft league id (required)"`
+	ShortHorizon *int `json:"short_horizon,omitempty" jsonschema:"GWs in the near window (default 3)"`
+	LongHorizon  *int `json:"long_horizon,omitempty" jsonschema:"GWs in the following window (default 6)"`
+	Limit        *int `json:"limit,omitempty" jsonschema:"Limit teams returned (0 = all)"`
+}
+
+// SwingFixture is one fixture inside a swing window. Multiplier is the
+// average of the four per-position multipliers (1.0 = average fixture,
+// higher = easier).
+type SwingFixture struct {
+	GW         int     `json:"gw"`
+	FixtureID  int     `json:"fixture_id"`
+	Opponent   string  `json:"opponent"`
+	Venue      string  `json:"venue"`
+	Multiplier float64 `json:"multiplier"`
+}
+
+// SwingWindow summarises a team's fixtures over a run of gameweeks. Averages
+// are per fixture; blank GWs are listed but don't pull the average down.
+type SwingWindow struct {
+	FromGW     int                `json:"from_gw"`
+	ToGW       int                `json:"to_gw"`
+	Fixtures   []SwingFixture     `json:"fixtures"`
+	Blanks     []int              `json:"blanks,omitempty"`
+	Average    float64            `json:"average"`
+	ByPosition map[string]float64 `json:"by_position"`
+}
+
+// SwingTarget is an unrostered player from a team worth a look.
+type SwingTarget struct {
+	Element      int    `json:"element"`
+	Name         string `json:"name"`
+	PositionType int    `json:"position_type"`
+	SeasonPoints int    `json:"season_points"`
+}
+
+// TeamSwing compares a team's near and following fixture windows. Swing is
+// long average minus short average: positive means the schedule gets easier.
+type TeamSwing struct {
+	TeamID        int                `json:"team_id"`
+	Team          string             `json:"team"`
+	Swing         float64            `json:"swing"`
+	Direction     string             `json:"direction"`
+	PositionSwing map[string]float64 `json:"position_swing"`
+	Short         SwingWindow        `json:"short"`
+	Long          SwingWindow        `json:"long"`
+	Targets       []SwingTarget      `json:"targets"`
+}
+
+type ScheduleSwingOutput struct {
+	LeagueID     int         `json:"league_id"`
+	AsOfGW       int         `json:"as_of_gw"`
+	FromGW       int         `json:"from_gw"`
+	ShortHorizon int         `json:"short_horizon"`
+	LongHorizon  int         `json:"long_horizon"`
+	Teams        []TeamSwing `json:"teams"`
+	Notes        []string    `json:"notes"`
+}
+
+// scheduleFixtures returns the fixtures for gw from bootstrap, falling back to
+// gw/N/live.json because bootstrap drops a GW once it has started.
+func scheduleFixtures(rawRoot string, fixturesByGW map[int][]fixture, gw int) []fixture {
+	if fx := fixturesByGW[gw]; len(fx) > 0 {
+		return fx
+	}
+	fx, err := loadFixturesFromLive(rawRoot, gw)
+	if err != nil {
+		return nil
+	}
+	return fx
+}
+
+// swingWindow scores teamID's fixtures across gws. GWs with no fixtures at all
+// are unknown and skipped; GWs where other teams play but teamID doesn't are
+// blanks.
+func swingWindow(teamID int, gws []int, from, to int, indexByGW map[int]map[int][]FixtureContext, mult outlookMultiplier) SwingWindow {
+	w := SwingWindow{FromGW: from, ToGW: to, Fixtures: []SwingFixture{}, ByPosition: map[string]float64{}}
+	posSum := make(map[int]float64)
+	total := 0.0
+	for _, gw := range gws {
+		if gw < from || gw > to {
+			continue
+		}
+		contexts := indexByGW[gw][teamID]
+		if len(contexts) == 0 {
+			w.Blanks = append(w.Blanks, gw)
+			continue
+		}
+		for _, ctx := range contexts {
+			sum := 0.0
+			for pos := 1; pos <= 4; pos++ {
+				m := mult(ctx.OpponentID, ctx.Venue, pos)
+				posSum[pos] += m
+				sum += m
+			}
+			w.Fixtures = append(w.Fixtures, SwingFixture{
+				GW:         gw,
+				FixtureID:  ctx.FixtureID,
+				Opponent:   ctx.OpponentShort,
+				Venue:      ctx.Venue,
+				Multiplier: sum / 4,
+			})
+			total += sum / 4
+		}
+	}
+	if n := float64(len(w.Fixtures)); n > 0 {
+		w.Average = total / n
+		for pos := 1; pos <= 4; pos++ {
+			w.ByPosition[positionLabel(pos)] = posSum[pos] / n
+		}
+	}
+	return w
+}
+
+// compareWindows fills Swing, Direction and PositionSwing. A team with no
+// fixtures in either window has no measurable swing and is marked "unknown".
+func compareWindows(ts *TeamSwing) {
+	ts.PositionSwing = map[string]float64{}
+	if len(ts.Short.Fixtures) == 0 || len(ts.Long.Fixtures) == 0 {
+		ts.Direction = "unknown"
+		return
+	}
+	ts.Swing = ts.Long.Average - ts.Short.Average
+	for label, short := range ts.Short.ByPosition {
+		ts.PositionSwing[label] = ts.Long.ByPosition[label] - short
+	}
+	switch {
+	case ts.Swing >= swingFlatThreshold:
+		ts.Direction = "improving"
+	case ts.Swing <= -swingFlatThreshold:
+		ts.Direction = "worsening"
+	default:
+		ts.Direction = "flat"
+	}
+}
+
+func buildScheduleSwing(cfg ServerConfig, args ScheduleSwingArgs) (ScheduleSwingOutput, error) {
+	if args.LeagueID == 0 {
+		return ScheduleSwingOutput{}, invalidArgumentf("league_id is required")
+	}
+	short := 3
+	if args.ShortHorizon != nil && *args.ShortHorizon > 0 {
+		short = *args.ShortHorizon
+	}
+	long := 6
+	if args.LongHorizon != nil && *args.LongHorizon > 0 {
+		long = *args.LongHorizon
+	}
+
+	asOfGW, nextGW, err := resolveAsOfAndNextGW(cfg, 0, 0)
+	if err != nil {
+		return ScheduleSwingOutput{}, err
+	}
+	elements, teamShort, fixturesByGW, err := loadBootstrapData(cfg.RawRoot)
+	if err != nil {
+		return ScheduleSwingOutput{}, err
+	}
+
+	shortEnd := nextGW + short - 1
+	longEnd := shortEnd + long
+	gws := make([]int, 0, short+long)
+	indexByGW := make(map[int]map[int][]FixtureContext)
+	for gw := nextGW; gw <= longEnd; gw++ {
+		fx := scheduleFixtures(cfg.RawRoot, fixturesByGW, gw)
+		if len(fx) == 0 {
+			continue
+		}
+		gws = append(gws, gw)
+		indexByGW[gw] = buildFixtureIndex(fx, teamShort)
+	}
+
+	ownership, err := loadOwnershipAtGW(cfg, args.LeagueID, resolveRosterGW(asOfGW, nextGW))
+	if err != nil {
+		return ScheduleSwingOutput{}, err
+	}
+	owned := make(map[int]bool)
+	for _, roster := range ownership {
+		for id := range roster {
+			owned[id] = true
+		}
+	}
+	targetsByTeam := make(map[int][]SwingTarget)
+	for _, e := range elements {
+		if owned[e.ID] || e.Status != "a" {
+			continue
+		}
+		targetsByTeam[e.TeamID] = append(targetsByTeam[e.TeamID], SwingTarget{
+			Element:      e.ID,
+			Name:         e.Name,
+			PositionType: e.PositionType,
+			SeasonPoints: e.TotalPoints,
+		})
+	}
+
+	mult := fixtureMultiplierFunc(cfg.RawRoot, elements, teamShort, asOfGW, swingFormWindow)
+	seasonWeight, recentWeight := horizonWeights(swingFormWindow)
+
+	teams := make([]TeamSwing, 0, len(teamShort))
+	for teamID, name := range teamShort {
+		ts := TeamSwing{
+			TeamID: teamID,
+			Team:   name,
+			Short:  swingWindow(teamID, gws, nextGW, shortEnd, indexByGW, mult),
+			Long:   swingWindow(teamID, gws, shortEnd+1, longEnd, indexByGW, mult),
+		}
+		compareWindows(&ts)
+
+		targets := targetsByTeam[teamID]
+		sort.Slice(targets, func(i, j int) bool {
+			if targets[i].SeasonPoints != targets[j].SeasonPoints {
+				return targets[i].SeasonPoints > targets[j].SeasonPoints
+			}
+			return targets[i].Name < targets[j].Name
+		})
+		if len(targets) > swingTargets {
+			targets = targets[:swingTargets]
+		}
+		ts.Targets = append([]SwingTarget{}, targets...)
+		teams = append(teams, ts)
+	}
+	// Most improving first, most worsening last; teams with no measurable
+	// swing sort after both.
+	sort.Slice(teams, func(i, j int) bool {
+		ui, uj := teams[i].Direction == "unknown", teams[j].Direction == "unknown"
+		if ui != uj {
+			return uj
+		}
+		if teams[i].Swing != teams[j].Swing {
+			return teams[i].Swing > teams[j].Swing
+		}
+		return teams[i].Team < teams[j].Team
+	})
+	if args.Limit != nil && *args.Limit > 0 && *args.Limit < len(teams) {
+		teams = teams[:*args.Limit]
+	}
+
+	return ScheduleSwingOutput{
+		LeagueID:     args.LeagueID,
+		AsOfGW:       asOfGW,
+		FromGW:       nextGW,
+		ShortHorizon: short,
+		LongHorizon:  long,
+		Teams:        teams,
+		Notes: []string{
+			fmt.Sprintf("Short window GW %d-%d, long window GW %d-%d.", nextGW, shortEnd, shortEnd+1, longEnd),
+			fmt.Sprintf("Multipliers are blended points conceded by position (season %.2f / last %d GWs %.2f) relative to the position average; 1.0 = average, higher = easier.", seasonWeight, swingFormWindow, recentWeight),
+			"swing = long average - short average. Improving teams are buys for later; worsening teams are buys now or sells before the run ends.",
+			"Targets are the highest-scoring available players from each team not on any roster.",
+		},
+	}, nil
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestSwingWindowAndCompare(t *testing.T) {
+	teamShort := map[int]string{10: "LIV", 11: "MCI", 12: "ARS"}
+	indexByGW := map[int]map[int][]FixtureContext{
+		3: buildFixtureIndex([]fixture{{ID: 1, Event: 3, TeamH: 10, TeamA: 11}}, teamShort),
+		4: buildFixtureIndex([]fixture{{ID: 2, Event: 4, TeamH: 12, TeamA: 11}}, teamShort),
+		5: buildFixtureIndex([]fixture{{ID: 3, Event: 5, TeamH: 10, TeamA: 12}}, teamShort),
+	}
+	// MCI are a hard opponent for everyone; ARS are easy for attackers only.
+	mult := func(opponentID int, venue string, pos int) float64 {
+		switch {
+		case opponentID == 11:
+			return 0.5
+		case opponentID == 12 && pos >= 3:
+			return 1.5
+		default:
+			return 1
+		}
+	}
+	gws := []int{3, 4, 5}
+
+	ts := TeamSwing{
+		Team:  "LIV",
+		Short: swingWindow(10, gws, 3, 3, indexByGW, mult),
+		Long:  swingWindow(10, gws, 4, 5, indexByGW, mult),
+	}
+	if !approxEqual(ts.Short.Average, 0.5) || len(ts.Short.Fixtures) != 1 || ts.Short.Fixtures[0].Opponent != "MCI" {
+		t.Errorf("short window = %+v, want one fixture v MCI at 0.5", ts.Short)
+	}
+	if len(ts.Long.Blanks) != 1 || ts.Long.Blanks[0] != 4 {
+		t.Errorf("long blanks = %v, want [4]", ts.Long.Blanks)
+	}
+	if !approxEqual(ts.Long.Average, 1.25) || !approxEqual(ts.Long.ByPosition["FWD"], 1.5) || !approxEqual(ts.Long.ByPosition["DEF"], 1) {
+		t.Errorf("long window = %+v, want ARS at 1.25 overall", ts.Long)
+	}
+
+	compareWindows(&ts)
+	if ts.Direction != "improving" || !approxEqual(ts.Swing, 0.75) {
+		t.Errorf("swing = %v %s, want 0.75 improving", ts.Swing, ts.Direction)
+	}
+	if !approxEqual(ts.PositionSwing["MID"], 1) || !approxEqual(ts.PositionSwing["GK"], 0.5) {
+		t.Errorf("position swing = %v", ts.PositionSwing)
+	}
+
+	empty := TeamSwing{Short: swingWindow(10, gws, 4, 4, indexByGW, mult)}
+	compareWindows(&empty)
+	if empty.Direction != "unknown" || empty.Swing != 0 {
+		t.Errorf("blank-only windows = %+v, want unknown", empty)
+	}
+}
+
+func TestBuildScheduleSwing(t *testing.T) {
+	dir, cfg := tmpCfg(t)
+	cfg.DerivedRoot = dir
+	writeJSON(t, filepath.Join(dir, "bootstrap", "bootstrap-static.json"), map[string]any{
+		"elements": []any{
+			map[string]any{"id": 1, "web_name": "Salah", "team": 10, "element_type": 3, "status": "a", "total_points": 150},
+			map[string]any{"id": 2, "web_name": "Haaland", "team": 11, "element_type": 4, "status": "a", "total_points": 180},
+			map[string]any{"id": 3, "web_name": "Alexander-Arnold", "team": 10, "element_type": 2, "status": "a", "total_points": 90},
+			map[string]any{"id": 4, "web_name": "Jones", "team": 10, "element_type": 3, "status": "i", "total_points": 120},
+		},
+		"teams": []any{
+			map[string]any{"id": 10, "short_name": "LIV"},
+			map[string]any{"id": 11, "short_name": "MCI"},
+			map[string]any{"id": 12, "short_name": "ARS"},
+		},
+		// GW3 has started, so bootstrap no longer lists it.
+		"fixtures": map[string]any{
+			"4": []any{map[string]any{"id": 6, "team_h": 12, "team_a": 11}},
+			"5": []any{map[string]any{"id": 7, "team_h": 10, "team_a": 12}},
+		},
+	})
+	writeFullGameJSON(t, dir, 2, true, 3, false, "")
+	for gw := 1; gw <= 2; gw++ {
+		writeJSON(t, filepath.Join(dir, "gw", itoa(gw), "live.json"), map[string]any{
+			"elements": map[string]any{
+				"1": map[string]any{"stats": map[string]any{"total_points": 8}},
+				"2": map[string]any{"stats": map[string]any{"total_points": 2}},
+			},
+			"fixtures": []any{map[string]any{"id": gw, "team_h": 10, "team_a": 11}},
+		})
+	}
+	writeJSON(t, filepath.Join(dir, "gw", "3", "live.json"), map[string]any{
+		"elements": map[string]any{},
+		"fixtures": []any{map[string]any{"id": 5, "team_h": 10, "team_a": 11}},
+	})
+	writeJSON(t, filepath.Join(dir, "draft/100/choices.json"), map[string]any{
+		"choices": []any{
+			map[string]any{"entry": 200, "element": 1, "round": 1, "pick": 1, "index": 1},
+		},
+	})
+	writeJSON(t, filepath.Join(dir, "league/100/transactions.json"), map[string]any{"transactions": []any{}})
+	writeJSON(t, filepath.Join(dir, "league/100/trades.json"), map[string]any{"trades": []any{}})
+
+	short, long := 1, 2
+	out, err := buildScheduleSwing(cfg, ScheduleSwingArgs{LeagueID: 100, ShortHorizon: &short, LongHorizon: &long})
+	if err != nil {
+		t.Fatalf("buildScheduleSwing: %v", err)
+	}
+	if out.FromGW != 3 || out.ShortHorizon != 1 || out.LongHorizon != 2 || len(out.Teams) != 3 {
+		t.Fatalf("from=%d short=%d long=%d teams=%d", out.FromGW, out.ShortHorizon, out.LongHorizon, len(out.Teams))
+	}
+	// ARS have no GW3 fixture, so their swing can't be measured.
+	if last := out.Teams[2]; last.Team != "ARS" || last.Direction != "unknown" {
+		t.Errorf("last team = %s %s, want ARS unknown", last.Team, last.Direction)
+	}
+	for _, ts := range out.Teams {
+		if ts.Team != "LIV" {
+			continue
+		}
+		if len(ts.Short.Fixtures) != 1 || ts.Short.Fixtures[0].FixtureID != 5 {
+			t.Errorf("LIV short = %+v, want GW3 fixture from live.json", ts.Short.Fixtures)
+		}
+		if len(ts.Long.Blanks) != 1 || ts.Long.Blanks[0] != 4 {
+			t.Errorf("LIV long blanks = %v, want [4]", ts.Long.Blanks)
+		}
+		if !approxEqual(ts.Swing, ts.Long.Average-ts.Short.Average) {
+			t.Errorf("LIV swing = %v, want long - short", ts.Swing)
+		}
+		// Salah is rostered and Jones is injured.
+		if len(ts.Targets) != 1 || ts.Targets[0].Name != "Alexander-Arnold" {
+			t.Errorf("LIV targets = %+v, want Alexander-Arnold", ts.Targets)
+		}
+	}
+
+	limit := 1
+	out, err = buildScheduleSwing(cfg, ScheduleSwingArgs{LeagueID: 100, Limit: &limit})
+	if err != nil {
+		t.Fatalf("buildScheduleSwing: %v", err)
+	}
+	if len(out.Teams) != 1 {
+		t.Errorf("limit 1: teams = %d", len(out.Teams))
+	}
+
+	if _, err := buildScheduleSwing(cfg, ScheduleSwingArgs{}); classifyError(err).Code != codeInvalidArgument {
+		t.Errorf("missing league_id: err = %v", err)
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/leagueconfig"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/scoring"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+)
+
+// Where a league's scoring rules came from, reported in
+// ProjectionScoring.Source.
+const (
+	scoringSourceDefault = "default"
+	scoringSourceConfig  = "config"
+	scoringSourceLeague  = "league"
+)
+
+// ProjectionScoring is the scoring a projection was made under, echoed in
+// projection tool outputs.
+type ProjectionScoring struct {
+	Source string               `json:"source"`
+	Rules  scoring.ScoringRules `json:"rules"`
+}
+
+// loadScoringRules resolves leagueID's scoring: the --scoring-config rules
+// (official FPL scoring when there are none), overridden by a scoring_rules
+// object in the league settings of league/{id}/details.json when it has one.
+// Missing league details just leave the server-wide rules in effect.
+func loadScoringRules(cfg ServerConfig, leagueID int) (ProjectionScoring, error) {
+	out := ProjectionScoring{Source: scoringSourceDefault, Rules: scoring.Default()}
+	if cfg.Scoring != nil {
+		out = ProjectionScoring{Source: scoringSourceConfig, Rules: *cfg.Scoring}
+	}
+	if leagueID == 0 {
+		return out, nil
+	}
+	settings, err := leagueconfig.Load(store.NewJSONStore(cfg.RawRoot), leagueID)
+	if errors.Is(err, fs.ErrNotExist) {
+		return out, nil
+	}
+	if err != nil {
+		return ProjectionScoring{}, err
+	}
+	if len(settings.ScoringRules) == 0 {
+		return out, nil
+	}
+	rules, err := scoring.Apply(out.Rules, settings.ScoringRules)
+	if err != nil {
+		return ProjectionScoring{}, fmt.Errorf("league/%d/details.json: %w", leagueID, err)
+	}
+	return ProjectionScoring{Source: scoringSourceLeague, Rules: rules}, nil
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/scoring"
+)
+
+func TestLoadScoringRules(t *testing.T) {
+	dir, cfg := tmpCfg(t)
+
+	got, err := loadScoringRules(cfg, 100)
+	if err != nil || got.Source != scoringSourceDefault || !got.Rules.IsDefault() {
+		t.Fatalf("no config or details: %+v, %v; want default rules", got, err)
+	}
+
+	custom := scoring.Default()
+	custom.Goal.MID = 6
+	cfg.Scoring = &custom
+	writeLeagueDetailsFixture(t, dir, 100, []any{}, []any{})
+	got, err = loadScoringRules(cfg, 100)
+	if err != nil || got.Source != scoringSourceConfig || got.Rules.Goal.MID != 6 {
+		t.Fatalf("config rules: %+v, %v", got, err)
+	}
+
+	// League settings override the config field by field.
+	writeJSON(t, filepath.Join(dir, "league/100/details.json"), map[string]any{
+		"league": map[string]any{"scoring": "h", "scoring_rules": map[string]any{"clean_sheet": map[string]any{"mid": 0}}},
+	})
+	got, err = loadScoringRules(cfg, 100)
+	if err != nil || got.Source != scoringSourceLeague || got.Rules.Goal.MID != 6 || got.Rules.CleanSheet.MID != 0 || got.Rules.CleanSheet.DEF != 4 {
+		t.Fatalf("league rules: %+v, %v", got, err)
+	}
+
+	writeJSON(t, filepath.Join(dir, "league/100/details.json"), map[string]any{
+		"league": map[string]any{"scoring_rules": map[string]any{"goals": 6}},
+	})
+	if _, err := loadScoringRules(cfg, 100); err == nil || !strings.Contains(err.Error(), "details.json") {
+		t.Errorf("unknown field: err = %v, want one naming details.json", err)
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+)
+
+// withSeasonRoots points cfg at the current season under the given raw and
+// derived roots: the newest season directory, or the roots themselves in the
+// flat layout (see store.CurrentSeason).
+func (cfg ServerConfig) withSeasonRoots(rawBase string, derivedBase string) ServerConfig {
+	season := store.CurrentSeason(rawBase)
+	cfg.RawSeasonsRoot = rawBase
+	cfg.DerivedSeasonsRoot = derivedBase
+	cfg.RawRoot = filepath.Join(rawBase, season)
+	cfg.DerivedRoot = filepath.Join(derivedBase, season)
+	return cfg
+}
+
+// forSeason returns the config to use for reads of an archived season. ""
+// and "current" keep cfg as is; any other value must be a season label like
+// "2024-25" with a directory under the raw root, or the current season while
+// the raw root is still in the flat layout.
+func (cfg ServerConfig) forSeason(season string) (ServerConfig, error) {
+	season = strings.TrimSpace(season)
+	if season == "" || strings.EqualFold(season, "current") {
+		return cfg, nil
+	}
+	if !store.ValidSeason(season) {
+		return ServerConfig{}, invalidArgumentf("season must be a label like 2025-26 or \"current\", got %q", season)
+	}
+	rawBase, derivedBase := cfg.RawSeasonsRoot, cfg.DerivedSeasonsRoot
+	if rawBase == "" {
+		rawBase, derivedBase = cfg.RawRoot, cfg.DerivedRoot
+	}
+	rawRoot, err := store.SeasonRoot(rawBase, season, store.SeasonForDate(cfg.now()))
+	if errors.Is(err, os.ErrNotExist) {
+		return ServerConfig{}, notFoundf("no data for season %s (have %s)", season, describeSeasons(rawBase))
+	}
+	if err != nil {
+		return ServerConfig{}, err
+	}
+	cfg.RawRoot = rawRoot
+	// The API only serves the current season.
+	if season != store.SeasonForDate(cfg.now()) {
+		cfg.Fetcher = nil
+	}
+	cfg.DerivedRoot = derivedBase
+	if rawRoot != rawBase {
+		cfg.DerivedRoot = filepath.Join(derivedBase, season)
+	}
+	return cfg, nil
+}
+
+func describeSeasons(rawBase string) string {
+	seasons := store.Seasons(rawBase)
+	if len(seasons) == 0 {
+		return "only the current season"
+	}
+	return strings.Join(seasons, ", ")
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+)
+
+func TestForSeason_SeasonLayout(t *testing.T) {
+	base := t.TempDir()
+	raw, derived := filepath.Join(base, "raw"), filepath.Join(base, "derived")
+	for _, season := range []string{"2024-25", "2025-26"} {
+		writeBootstrap(t, filepath.Join(raw, season))
+		writeDraftChoicesFixture(t, filepath.Join(raw, season))
+	}
+	// Last season's draft had one extra pick.
+	writeJSON(t, filepath.Join(raw, "2024-25", "draft/100/choices.json"), map[string]any{
+		"choices": []any{
+			map[string]any{"entry": 200, "entry_name": "Alpha FC", "element": 1, "round": 1, "pick": 1, "index": 1},
+			map[string]any{"entry": 201, "entry_name": "Beta FC", "element": 2, "round": 1, "pick": 2, "index": 2},
+			map[string]any{"entry": 201, "entry_name": "Beta FC", "element": 3, "round": 2, "pick": 1, "index": 3},
+			map[string]any{"entry": 200, "entry_name": "Alpha FC", "element": 4, "round": 2, "pick": 2, "index": 4},
+		},
+	})
+	cfg := ServerConfig{ComputeMissing: true, WriteDerived: true}.withSeasonRoots(raw, derived)
+	if cfg.RawRoot != filepath.Join(raw, "2025-26") || cfg.DerivedRoot != filepath.Join(derived, "2025-26") {
+		t.Fatalf("current roots = %s, %s; want the 2025-26 dirs", cfg.RawRoot, cfg.DerivedRoot)
+	}
+
+	cur, err := buildDraftBoard(cfg, DraftBoardArgs{LeagueID: 100})
+	if err != nil {
+		t.Fatalf("current season: %v", err)
+	}
+	old, err := buildDraftBoard(cfg, DraftBoardArgs{LeagueID: 100, Season: "2024-25"})
+	if err != nil {
+		t.Fatalf("archived season: %v", err)
+	}
+	if cur.TotalPicks != 3 || old.TotalPicks != 4 {
+		t.Errorf("picks current=%d archived=%d, want 3 and 4", cur.TotalPicks, old.TotalPicks)
+	}
+	// The archived season's ledger is written inside that season's derived dir.
+	if _, err := store.StatDerived(filepath.Join(derived, "2024-25", "ledger/100/event_0.json")); err != nil {
+		t.Errorf("archived ledger not under derived/2024-25: %v", err)
+	}
+
+	if _, err := buildDraftBoard(cfg, DraftBoardArgs{LeagueID: 100, Season: "2019-20"}); classifyError(err).Code != codeNotFound {
+		t.Errorf("missing season: err = %v, want NOT_FOUND", err)
+	}
+	if _, err := buildDraftBoard(cfg, DraftBoardArgs{LeagueID: 100, Season: "last year"}); classifyError(err).Code != codeInvalidArgument {
+		t.Errorf("bad label: err = %v, want INVALID_ARGUMENT", err)
+	}
+}
+
+func TestForSeason_FlatLayout(t *testing.T) {
+	dir, cfg := tmpCfg(t)
+	writeBootstrap(t, dir)
+	cfg = cfg.withSeasonRoots(dir, filepath.Join(dir, "derived"))
+	if cfg.RawRoot != dir {
+		t.Fatalf("flat raw root = %s, want %s", cfg.RawRoot, dir)
+	}
+	for _, season := range []string{"", "current", store.SeasonForDate(testClock.Now())} {
+		got, err := cfg.forSeason(season)
+		if err != nil || got.RawRoot != dir {
+			t.Errorf("forSeason(%q) = %s, %v; want the flat root", season, got.RawRoot, err)
+		}
+	}
+	if _, err := cfg.forSeason("2001-02"); classifyError(err).Code != codeNotFound {
+		t.Errorf("past season in the flat layout: err = %v, want NOT_FOUND", err)
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/leagueconfig"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/summary"
+)
+
+// loadSquadLimits returns the maximum number of players per position type in
+// leagueID's squads, from its league settings (2 GK / 5 DEF / 5 MID / 3 FWD
+// unless they say otherwise).
+func loadSquadLimits(cfg ServerConfig, leagueID int) (leagueconfig.PositionLimits, error) {
+	lc, err := leagueconfig.Load(store.NewJSONStore(cfg.RawRoot), leagueID)
+	if err != nil {
+		return leagueconfig.PositionLimits{}, err
+	}
+	return lc.Squad.PositionLimits, nil
+}
+
+// squadPositionCounts counts the roster by position type.
+func squadPositionCounts(roster []summary.RosterPlayer) map[int]int {
+	counts := make(map[int]int, 4)
+	for _, p := range roster {
+		counts[p.PositionType]++
+	}
+	return counts
+}
+
+// legalDropForAdd picks the drop to pair with an add of the given position.
+// When the squad is already at the limit for addPos only a same-position drop
+// keeps it legal; otherwise the lowest-scoring droppable player anywhere on
+// the roster may go. drops must be sorted by ascending score and already
+// exclude undroppable players.
+//
+// ok is false only when addPos is at its limit and no same-position player is
+// droppable, so the squad limit is what rules every drop out. A nil drop with
+// ok true means the add does not outscore the best legal drop, or that
+// nobody is droppable at all, which the drop candidates already warn about.
+func legalDropForAdd(limits leagueconfig.PositionLimits, drops []DropRecommendation, counts map[int]int, addPos int, addScore float64) (drop *DropRecommendation, ok bool) {
+	atCap := counts[addPos] >= limits.At(addPos)
+	for _, d := range drops {
+		if atCap && d.PositionType != addPos {
+			continue
+		}
+		if addScore <= d.Score {
+			return nil, true
+		}
+		out := d
+		if d.PositionType == addPos {
+			out.Reason = "Lowest weighted score at position"
+		} else {
+			out.Reason = fmt.Sprintf("Lowest weighted score on roster (%s below %d-player limit)", positionLabel(addPos), limits.At(addPos))
+		}
+		return &out, true
+	}
+	return nil, !atCap
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/leagueconfig"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/summary"
+)
+
+// fullSquad returns a legal 2/5/5/3 roster with element ids 1..15.
+func fullSquad() []summary.RosterPlayer {
+	out := make([]summary.RosterPlayer, 0, 15)
+	id := 1
+	for pos := 1; pos <= 4; pos++ {
+		for i := 0; i < leagueconfig.DefaultSquadLimits().At(pos); i++ {
+			out = append(out, summary.RosterPlayer{Element: id, PositionType: pos})
+			id++
+		}
+	}
+	return out
+}
+
+func TestSquadPositionCounts(t *testing.T) {
+	counts := squadPositionCounts(fullSquad())
+	for pos := 1; pos <= 4; pos++ {
+		if limit := leagueconfig.DefaultSquadLimits().At(pos); counts[pos] != limit {
+			t.Errorf("pos %d: count=%d want %d", pos, counts[pos], limit)
+		}
+	}
+}
+
+// TestLegalDropForAdd_FWDAtCap is the regression case: a roster already at
+// 3 FWD must not pair a FWD add with a lower-scoring DEF drop.
+func TestLegalDropForAdd_FWDAtCap(t *testing.T) {
+	counts := squadPositionCounts(fullSquad())
+	drops := []DropRecommendation{
+		{Element: 3, PositionType: 2, Score: 0.10},
+		{Element: 13, PositionType: 4, Score: 0.30},
+		{Element: 8, PositionType: 3, Score: 0.40},
+	}
+
+	t.Run("SamePositionDrop", func(t *testing.T) {
+		drop, ok := legalDropForAdd(leagueconfig.DefaultSquadLimits(), drops, counts, 4, 0.80)
+		if !ok || drop == nil {
+			t.Fatalf("expected a legal drop, got drop=%v ok=%v", drop, ok)
+		}
+		if drop.PositionType != 4 || drop.Element != 13 {
+			t.Errorf("drop = element %d pos %d, want FWD element 13", drop.Element, drop.PositionType)
+		}
+	})
+
+	t.Run("NoDroppableFWD", func(t *testing.T) {
+		noFWD := []DropRecommendation{drops[0], drops[2]}
+		drop, ok := legalDropForAdd(leagueconfig.DefaultSquadLimits(), noFWD, counts, 4, 0.80)
+		if ok || drop != nil {
+			t.Errorf("expected no legal drop, got drop=%v ok=%v", drop, ok)
+		}
+	})
+
+	t.Run("AddNotBetter", func(t *testing.T) {
+		drop, ok := legalDropForAdd(leagueconfig.DefaultSquadLimits(), drops, counts, 4, 0.20)
+		if !ok || drop != nil {
+			t.Errorf("expected legal but no suggestion, got drop=%v ok=%v", drop, ok)
+		}
+	})
+}
+
+// TestLegalDropForAdd_CrossPositionWithRoom allows dropping from another
+// position when the add's position is below its limit.
+func TestLegalDropForAdd_CrossPositionWithRoom(t *testing.T) {
+	roster := fullSquad()[:14] // only 2 FWD
+	counts := squadPositionCounts(roster)
+	drops := []DropRecommendation{
+		{Element: 3, PositionType: 2, Score: 0.10},
+		{Element: 13, PositionType: 4, Score: 0.30},
+	}
+	drop, ok := legalDropForAdd(leagueconfig.DefaultSquadLimits(), drops, counts, 4, 0.80)
+	if !ok || drop == nil {
+		t.Fatalf("expected a legal drop, got drop=%v ok=%v", drop, ok)
+	}
+	if drop.Element != 3 {
+		t.Errorf("drop element = %d, want lowest-scoring DEF 3", drop.Element)
+	}
+}
+
+// TestLegalDropForAdd_NothingDroppableWithRoom is not a squad-limit problem:
+// below the FWD limit any drop would be legal, the undroppable list just
+// leaves none to suggest.
+func TestLegalDropForAdd_NothingDroppableWithRoom(t *testing.T) {
+	counts := squadPositionCounts(fullSquad()[:14]) // only 2 FWD
+	drop, ok := legalDropForAdd(leagueconfig.DefaultSquadLimits(), nil, counts, 4, 0.80)
+	if !ok || drop != nil {
+		t.Errorf("expected legal with no suggestion, got drop=%v ok=%v", drop, ok)
+	}
+	if _, ok := legalDropForAdd(leagueconfig.DefaultSquadLimits(), nil, squadPositionCounts(fullSquad()), 4, 0.80); ok {
+		t.Error("at the FWD limit with nothing droppable should have no legal drop")
+	}
+}
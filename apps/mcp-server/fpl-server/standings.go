@@ -0,0 +1,131 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/summary"
+)
+
+type StandingsArgs struct {
+	LeagueID    int      `json:"league_id" jsonschema:"Draft league id (required)"`
+	GW          int      `json:"gw" jsonschema:"Gameweek (0 = current)"`
+	Season      string   `json:"season,omitempty" jsonschema:"Season label like 2024-25 for an archived season (default current)"`
+	Tiebreakers []string `json:"tiebreakers,omitempty" jsonschema:"Tiebreakers after match points, in order: h2h, points_for, points_diff (default the league's configured chain, else points_diff then points_for)"`
+	SortBy      string   `json:"sort_by,omitempty" jsonschema:"match_points|expected_wins|all_play|luck (default match_points); rank stays the league position"`
+	// PythagoreanExponent is the k in PF^k / (PF^k + PA^k).
+	PythagoreanExponent *float64 `json:"pythagorean_exponent,omitempty" jsonschema:"Exponent k for expected wins (default 2.37)"`
+	Format              string   `json:"format,omitempty" jsonschema:"json|markdown|csv (default json)"`
+}
+
+// leagueTiebreakersFlag collects repeatable --tiebreakers
+// league_id=h2h,points_for,points_diff values.
+type leagueTiebreakersFlag map[int][]string
+
+func (f leagueTiebreakersFlag) String() string {
+	ids := make([]int, 0, len(f))
+	for id := range f {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	parts := make([]string, 0, len(ids))
+	for _, id := range ids {
+		parts = append(parts, fmt.Sprintf("%d=%s", id, strings.Join(f[id], ",")))
+	}
+	return strings.Join(parts, ";")
+}
+
+func (f leagueTiebreakersFlag) Set(v string) error {
+	idStr, list, ok := strings.Cut(v, "=")
+	if !ok {
+		return fmt.Errorf("expected league_id=tiebreaker,..., got %q", v)
+	}
+	id, err := strconv.Atoi(strings.TrimSpace(idStr))
+	if err != nil || id <= 0 {
+		return fmt.Errorf("invalid league id %q", idStr)
+	}
+	chain, err := summary.ParseTiebreakers(strings.Split(list, ","))
+	if err != nil {
+		return err
+	}
+	f[id] = chain
+	return nil
+}
+
+// StandingsOutput is a standings table recomputed with a non-default
+// tiebreaker chain, sort order or pythagorean exponent.
+type StandingsOutput struct {
+	summary.StandingsSummary
+	Tiebreakers         []string `json:"tiebreakers"`
+	SortBy              string   `json:"sort_by"`
+	PythagoreanExponent float64  `json:"pythagorean_exponent"`
+	GWNote              *GWNote  `json:"gw_note,omitempty"`
+}
+
+// buildStandings returns the standings JSON for a GW. The derived file is
+// served unchanged under summary.DefaultTiebreakers. A chain from the call,
+// or else from the league's --tiebreakers config, recomputes the table from
+// league details: the server builds derived files with the configured chain,
+// but one written by cmd/dev without the same --tiebreakers is in default
+// order. So does a sort_by other than match points or
+// a pythagorean_exponent, since a derived file from before expected wins
+// existed has no weekly scores to rebuild all-play from.
+func buildStandings(cfg ServerConfig, args StandingsArgs) ([]byte, error) {
+	if args.LeagueID == 0 {
+		return nil, invalidArgumentf("league_id is required")
+	}
+	chain := cfg.Tiebreakers[args.LeagueID]
+	if len(args.Tiebreakers) > 0 {
+		var err error
+		if chain, err = summary.ParseTiebreakers(args.Tiebreakers); err != nil {
+			return nil, invalidArgumentf("%v", err)
+		}
+	}
+	sortBy, err := summary.ParseSortBy(args.SortBy)
+	if err != nil {
+		return nil, invalidArgumentf("%v", err)
+	}
+	exponent := summary.DefaultPythagoreanExponent
+	if args.PythagoreanExponent != nil {
+		if exponent = *args.PythagoreanExponent; exponent <= 0 || exponent > 20 {
+			return nil, invalidArgumentf("pythagorean_exponent must be above 0 and at most 20, got %g", exponent)
+		}
+	}
+	cfg, err = cfg.forSeason(args.Season)
+	if err != nil {
+		return nil, err
+	}
+	gw, note, err := resolveEffectiveGW(cfg, args.GW, gwModeLatestFinished)
+	if err != nil {
+		return nil, err
+	}
+	if summary.IsDefaultTiebreakers(chain) && sortBy == summary.SortMatchPoints && args.PythagoreanExponent == nil {
+		relPath := fmt.Sprintf("summary/standings/%d/gw/%d.json", args.LeagueID, gw)
+		raw, err := loadSummaryFile(cfg, args.LeagueID, gw, relPath, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		return withGWNote(raw, note), nil
+	}
+
+	st := store.NewJSONStore(cfg.RawRoot)
+	ld, entryIDs, err := loadLeagueDetails(st, args.LeagueID)
+	if err != nil {
+		return nil, err
+	}
+	out := StandingsOutput{
+		StandingsSummary:    summary.ComputeStandings(args.LeagueID, ld, entryIDs, gw, chain),
+		Tiebreakers:         chain,
+		SortBy:              sortBy,
+		PythagoreanExponent: exponent,
+		GWNote:              note,
+	}
+	out.BuildStamp = summary.NewBuildStamp(cfg.Clock, st)
+	summary.ApplyPythagorean(out.Rows, exponent)
+	summary.SortStandings(out.Rows, sortBy)
+	return json.MarshalIndent(out, "", "  ")
+}
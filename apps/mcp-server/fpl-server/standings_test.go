@@ -0,0 +1,203 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLeagueTiebreakersFlag_Set(t *testing.T) {
+	f := leagueTiebreakersFlag{}
+	if err := f.Set("100=h2h, points_for"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if err := f.Set("200=points_for"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if got := f.String(); got != "100=h2h,points_for;200=points_for" {
+		t.Errorf("String() = %q", got)
+	}
+	for _, bad := range []string{"100", "x=h2h", "100=goals", "100=h2h,h2h"} {
+		if err := f.Set(bad); err == nil {
+			t.Errorf("Set(%q): expected error", bad)
+		}
+	}
+}
+
+func TestBuildStandings_Tiebreakers(t *testing.T) {
+	dir, cfg := resourceCfg(t)
+	writeFullGameJSON(t, dir, 1, true, 2, false, "")
+	writeLeagueDetailsFixture(t, dir, 100, []any{
+		map[string]any{"id": 1, "entry_id": 200, "entry_name": "Alpha FC"},
+		map[string]any{"id": 2, "entry_id": 201, "entry_name": "Beta FC"},
+		map[string]any{"id": 3, "entry_id": 202, "entry_name": "Gamma FC"},
+		map[string]any{"id": 4, "entry_id": 203, "entry_name": "Delta FC"},
+	}, []any{
+		// Alpha and Beta finish level on 3 match points; Beta has the better
+		// points difference, Alpha won the meeting.
+		map[string]any{"event": 1, "finished": true, "league_entry_1": 1, "league_entry_1_points": 50, "league_entry_2": 2, "league_entry_2_points": 48},
+		map[string]any{"event": 1, "finished": true, "league_entry_1": 3, "league_entry_1_points": 70, "league_entry_2": 1, "league_entry_2_points": 40},
+		map[string]any{"event": 1, "finished": true, "league_entry_1": 2, "league_entry_1_points": 80, "league_entry_2": 4, "league_entry_2_points": 30},
+	})
+	// The derived file stands in for the pipeline's default-chain output.
+	writeJSON(t, filepath.Join(dir, "summary/standings/100/gw/1.json"), map[string]any{
+		"league_id": 100, "gameweek": 1,
+		"rows": []any{map[string]any{"entry_id": 201, "rank": 2}, map[string]any{"entry_id": 200, "rank": 3}},
+	})
+
+	order := func(raw []byte) []int {
+		t.Helper()
+		var out struct {
+			Rows []struct {
+				EntryID int `json:"entry_id"`
+			} `json:"rows"`
+		}
+		if err := json.Unmarshal(raw, &out); err != nil {
+			t.Fatal(err)
+		}
+		ids := make([]int, 0, len(out.Rows))
+		for _, r := range out.Rows {
+			ids = append(ids, r.EntryID)
+		}
+		return ids
+	}
+
+	raw, err := buildStandings(cfg, StandingsArgs{LeagueID: 100, GW: 1})
+	if err != nil {
+		t.Fatalf("default chain: %v", err)
+	}
+	if got := order(raw); len(got) != 2 || got[0] != 201 {
+		t.Errorf("default chain rows = %v, want the derived file", got)
+	}
+
+	raw, err = buildStandings(cfg, StandingsArgs{LeagueID: 100, GW: 1, Tiebreakers: []string{"h2h", "points_diff"}})
+	if err != nil {
+		t.Fatalf("h2h chain: %v", err)
+	}
+	if got := order(raw); len(got) != 4 || got[0] != 202 || got[1] != 200 || got[2] != 201 {
+		t.Errorf("h2h chain order = %v, want Gamma, Alpha, Beta, Delta", got)
+	}
+
+	// A league-level chain applies when the call doesn't pass one.
+	cfg.Tiebreakers = map[int][]string{100: {"h2h"}}
+	raw, err = buildStandings(cfg, StandingsArgs{LeagueID: 100, GW: 1})
+	if err != nil {
+		t.Fatalf("configured chain: %v", err)
+	}
+	var out StandingsOutput
+	if err := json.Unmarshal(raw, &out); err != nil {
+		t.Fatal(err)
+	}
+	if len(out.Tiebreakers) != 1 || out.Rows[1].EntryID != 200 || out.Rows[1].SeedingExplanation == "" {
+		t.Errorf("configured chain = %v rows %+v", out.Tiebreakers, out.Rows)
+	}
+
+	if _, err := buildStandings(cfg, StandingsArgs{LeagueID: 100, Tiebreakers: []string{"goals"}}); classifyError(err).Code != codeInvalidArgument {
+		t.Errorf("unknown tiebreaker: err = %v", err)
+	}
+	if _, err := buildStandings(cfg, StandingsArgs{}); classifyError(err).Code != codeInvalidArgument {
+		t.Errorf("missing league: err = %v", err)
+	}
+}
+
+func TestLoadSummaryFile_StandingsUseConfiguredChain(t *testing.T) {
+	dir, cfg := resourceCfg(t)
+	cfg.ComputeMissing = true
+	cfg.WriteDerived = true
+	cfg.Tiebreakers = map[int][]string{100: {"h2h", "points_diff"}}
+	writeFullGameJSON(t, dir, 1, true, 2, false, "")
+	writeLeagueDetailsFixture(t, dir, 100, []any{
+		map[string]any{"id": 1, "entry_id": 200, "entry_name": "Alpha FC"},
+		map[string]any{"id": 2, "entry_id": 201, "entry_name": "Beta FC"},
+		map[string]any{"id": 3, "entry_id": 202, "entry_name": "Gamma FC"},
+		map[string]any{"id": 4, "entry_id": 203, "entry_name": "Delta FC"},
+	}, []any{
+		map[string]any{"event": 1, "finished": true, "league_entry_1": 1, "league_entry_1_points": 50, "league_entry_2": 2, "league_entry_2_points": 48},
+		map[string]any{"event": 1, "finished": true, "league_entry_1": 3, "league_entry_1_points": 70, "league_entry_2": 1, "league_entry_2_points": 40},
+		map[string]any{"event": 1, "finished": true, "league_entry_1": 2, "league_entry_1_points": 80, "league_entry_2": 4, "league_entry_2_points": 30},
+	})
+
+	// The derived file resources and the dashboard read seeds the same way
+	// the standings tool does.
+	raw, err := loadSummaryFile(cfg, 100, 1, "summary/standings/100/gw/1.json", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out StandingsOutput
+	if err := json.Unmarshal(raw, &out); err != nil {
+		t.Fatal(err)
+	}
+	if len(out.Rows) != 4 || out.Rows[0].EntryID != 202 || out.Rows[1].EntryID != 200 || out.Rows[2].EntryID != 201 {
+		t.Errorf("derived rows = %+v, want Gamma, Alpha, Beta, Delta", out.Rows)
+	}
+}
+
+func TestBuildStandings_SortByLuck(t *testing.T) {
+	dir, cfg := resourceCfg(t)
+	writeFullGameJSON(t, dir, 1, true, 2, false, "")
+	writeLeagueDetailsFixture(t, dir, 100, []any{
+		map[string]any{"id": 1, "entry_id": 200, "entry_name": "Alpha FC"},
+		map[string]any{"id": 2, "entry_id": 201, "entry_name": "Beta FC"},
+		map[string]any{"id": 3, "entry_id": 202, "entry_name": "Gamma FC"},
+		map[string]any{"id": 4, "entry_id": 203, "entry_name": "Delta FC"},
+	}, []any{
+		// Alpha scrapes a win on 90 for, 118 against; Beta outscores
+		// everyone and still goes 1-1.
+		map[string]any{"event": 1, "finished": true, "league_entry_1": 1, "league_entry_1_points": 50, "league_entry_2": 2, "league_entry_2_points": 48},
+		map[string]any{"event": 1, "finished": true, "league_entry_1": 3, "league_entry_1_points": 70, "league_entry_2": 1, "league_entry_2_points": 40},
+		map[string]any{"event": 1, "finished": true, "league_entry_1": 2, "league_entry_1_points": 80, "league_entry_2": 4, "league_entry_2_points": 30},
+	})
+	writeJSON(t, filepath.Join(dir, "summary/standings/100/gw/1.json"), map[string]any{"league_id": 100, "gameweek": 1, "rows": []any{}})
+	fetched := time.Date(2025, 10, 18, 9, 15, 0, 0, time.UTC)
+	if err := os.Chtimes(filepath.Join(dir, "league/100/details.json"), fetched, fetched); err != nil {
+		t.Fatal(err)
+	}
+
+	raw, err := buildStandings(cfg, StandingsArgs{LeagueID: 100, GW: 1, SortBy: "luck"})
+	if err != nil {
+		t.Fatalf("sort_by luck: %v", err)
+	}
+	var out StandingsOutput
+	if err := json.Unmarshal(raw, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.SortBy != "luck" || out.PythagoreanExponent != 2.37 || len(out.Rows) != 4 {
+		t.Fatalf("out = %+v", out)
+	}
+	want := []int{200, 202, 203, 201}
+	for i, id := range want {
+		if out.Rows[i].EntryID != id {
+			t.Errorf("rows[%d] = %d, want %d", i, out.Rows[i].EntryID, id)
+		}
+	}
+	if first := out.Rows[0]; first.Rank != 3 || first.WinsOverExpected <= 0 {
+		t.Errorf("Alpha = %+v, want league rank 3 and lucky", first)
+	}
+	// Computed per call: stamped by the config's clock, as of the details
+	// fetch.
+	if out.GeneratedAtUTC != "2025-10-18T12:00:00Z" || out.DataAsOfUTC != "2025-10-18T09:15:00Z" {
+		t.Errorf("stamp = %+v", out.BuildStamp)
+	}
+
+	k := 1.0
+	raw, err = buildStandings(cfg, StandingsArgs{LeagueID: 100, GW: 1, PythagoreanExponent: &k})
+	if err != nil {
+		t.Fatalf("exponent: %v", err)
+	}
+	if err := json.Unmarshal(raw, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.PythagoreanExponent != 1 || out.Rows[0].EntryID != 201 || out.Rows[0].PythagoreanPct != 0.615 {
+		t.Errorf("k=1 rows = %+v", out.Rows)
+	}
+
+	bad := 0.0
+	if _, err := buildStandings(cfg, StandingsArgs{LeagueID: 100, PythagoreanExponent: &bad}); classifyError(err).Code != codeInvalidArgument {
+		t.Errorf("zero exponent: err = %v", err)
+	}
+	if _, err := buildStandings(cfg, StandingsArgs{LeagueID: 100, SortBy: "goals"}); classifyError(err).Code != codeInvalidArgument {
+		t.Errorf("unknown sort_by: err = %v", err)
+	}
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"os"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// --transport values.
+const (
+	transportHTTP  = "http"
+	transportStdio = "stdio"
+)
+
+// runStdio serves server over t until the client disconnects or ctx is
+// cancelled. There is no HTTP listener, so no auth, /health or /tools; the
+// client spawning the process is trusted. stdout carries the protocol, so
+// logging is pinned to stderr. The client hanging up, whether seen as EOF
+// on read or a closed pipe on write, is a clean exit.
+func runStdio(ctx context.Context, server *mcp.Server, t mcp.Transport) error {
+	log.SetOutput(os.Stderr)
+	err := server.Run(ctx, t)
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrClosedPipe) || errors.Is(err, context.Canceled) {
+		return nil
+	}
+	return err
+}
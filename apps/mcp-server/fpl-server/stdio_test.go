@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// TestRunStdio_ToolCalls drives buildServer's tools over newline-delimited
+// JSON-RPC on a pair of pipes, the framing --transport stdio uses on
+// stdin/stdout.
+func TestRunStdio_ToolCalls(t *testing.T) {
+	dir, cfg := resourceCfg(t)
+	writeClaimFixture(t, dir)
+	server, _, tools := buildServer(cfg)
+
+	clientToServer, serverIn := io.Pipe()
+	clientIn, serverToClient := io.Pipe()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		done <- runStdio(ctx, server, &mcp.IOTransport{Reader: clientToServer, Writer: serverToClient})
+	}()
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "client", Version: "0"}, nil)
+	cs, err := client.Connect(ctx, &mcp.IOTransport{Reader: clientIn, Writer: serverIn}, nil)
+	if err != nil {
+		t.Fatalf("client connect: %v", err)
+	}
+
+	list, err := cs.ListTools(ctx, nil)
+	if err != nil {
+		t.Fatalf("list tools: %v", err)
+	}
+	if len(list.Tools) != len(tools) {
+		t.Errorf("stdio lists %d tools, registry has %d", len(list.Tools), len(tools))
+	}
+
+	text := func(res *mcp.CallToolResult) string {
+		if len(res.Content) != 1 {
+			t.Fatalf("content = %+v, want one block", res.Content)
+		}
+		return res.Content[0].(*mcp.TextContent).Text
+	}
+	res, err := cs.CallTool(ctx, &mcp.CallToolParams{Name: "league_entries", Arguments: map[string]any{"league_id": 100}})
+	if err != nil || res.IsError {
+		t.Fatalf("league_entries: %v %+v", err, res)
+	}
+	if got := text(res); !strings.Contains(got, "Alpha FC") || !strings.Contains(got, "Gamma FC") {
+		t.Errorf("league_entries = %s", got)
+	}
+	res, err = cs.CallTool(ctx, &mcp.CallToolParams{Name: "player_lookup", Arguments: map[string]any{"element_id": 1}})
+	if err != nil || res.IsError || !strings.Contains(text(res), "Salah") {
+		t.Errorf("player_lookup: %v %+v", err, res)
+	}
+	res, err = cs.CallTool(ctx, &mcp.CallToolParams{Name: "player_lookup", Arguments: map[string]any{"element_id": 999}})
+	if err != nil || !res.IsError || !strings.Contains(text(res), string(codeNotFound)) {
+		t.Errorf("player_lookup for unknown element: %v %+v", err, res)
+	}
+
+	// Closing the client's end is a clean shutdown, as when the spawning
+	// client exits.
+	cs.Close()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("runStdio after client close: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("runStdio did not return after the client closed")
+	}
+}
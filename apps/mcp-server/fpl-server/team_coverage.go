@@ -0,0 +1,396 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/ledger"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/summary"
+)
+
+const (
+	// coverageSlotStarters is how many starters kicking off together trigger a
+	// same-slot warning.
+	coverageSlotStarters = 4
+	// coverageOpponentPlayers is how many players facing one opponent over the
+	// horizon trigger an opponent warning.
+	coverageOpponentPlayers = 4
+	// coverageConflictPlayers is the minimum number of players in one match,
+	// with at least one on each side, to report a conflict.
+	coverageConflictPlayers = 3
+)
+
+// TeamCoverageArgs are the input arguments for the team_coverage tool.
+type TeamCoverageArgs struct {
+	LeagueID  int     `json:"league_id" jsonschema:"Draft league id (required)"`
+	EntryID   *int    `json:"entry_id,omitempty" jsonschema:"Entry id"`
+	EntryName *string `json:"entry_name,omitempty" jsonschema:"Entry name (if entry_id not provided)"`
+	All       *bool   `json:"all,omitempty" jsonschema:"Report every entry in the league"`
+	Horizon   *int    `json:"horizon,omitempty" jsonschema:"Upcoming GWs to check (default 3)"`
+}
+
+// TeamExposure counts an entry's players from one Premier League team.
+type TeamExposure struct {
+	TeamID   int      `json:"team_id"`
+	Team     string   `json:"team"`
+	Players  int      `json:"players"`
+	Starters int      `json:"starters"`
+	Bench    int      `json:"bench"`
+	Names    []string `json:"names"`
+}
+
+// BlankExposure lists starters whose team has no fixture in a GW.
+type BlankExposure struct {
+	GW       int      `json:"gw"`
+	Starters int      `json:"starters"`
+	Names    []string `json:"names,omitempty"`
+}
+
+// SlotConcentration is a kickoff time shared by several starters.
+type SlotConcentration struct {
+	GW         int      `json:"gw"`
+	KickoffUTC string   `json:"kickoff_utc"`
+	Starters   int      `json:"starters"`
+	Names      []string `json:"names"`
+}
+
+// OpponentConcentration is an opponent faced by several players over the horizon.
+type OpponentConcentration struct {
+	Opponent string   `json:"opponent"`
+	Players  int      `json:"players"`
+	GWs      []int    `json:"gws"`
+	Names    []string `json:"names"`
+}
+
+// FixtureConflict is a match with the entry's players on both sides.
+type FixtureConflict struct {
+	FixtureID   int      `json:"fixture_id"`
+	GW          int      `json:"gw"`
+	KickoffUTC  string   `json:"kickoff_utc"`
+	Home        string   `json:"home"`
+	Away        string   `json:"away"`
+	HomePlayers []string `json:"home_players"`
+	AwayPlayers []string `json:"away_players"`
+}
+
+// EntryCoverage is one entry's team exposure report.
+type EntryCoverage struct {
+	EntryID   int                     `json:"entry_id"`
+	EntryName string                  `json:"entry_name"`
+	Teams     []TeamExposure          `json:"teams"`
+	Blanks    []BlankExposure         `json:"blanks"`
+	Slots     []SlotConcentration     `json:"slot_concentration"`
+	Opponents []OpponentConcentration `json:"opponent_concentration"`
+	Conflicts []FixtureConflict       `json:"conflicts"`
+	Warnings  []string                `json:"warnings,omitempty"`
+}
+
+// TeamCoverageOutput is the output of the team_coverage tool.
+type TeamCoverageOutput struct {
+	LeagueID int             `json:"league_id"`
+	RosterGW int             `json:"roster_gw"`
+	FromGW   int             `json:"from_gw"`
+	Horizon  int             `json:"horizon"`
+	Entries  []EntryCoverage `json:"entries"`
+	GWNote   *GWNote         `json:"gw_note,omitempty"`
+}
+
+// coveragePlayer is one rostered player with the fields the report needs.
+type coveragePlayer struct {
+	Element int
+	Name    string
+	TeamID  int
+	Starter bool
+}
+
+func buildTeamCoverage(cfg ServerConfig, args TeamCoverageArgs) (TeamCoverageOutput, error) {
+	if args.LeagueID == 0 {
+		return TeamCoverageOutput{}, invalidArgumentf("league_id is required")
+	}
+	horizon := 3
+	if args.Horizon != nil && *args.Horizon > 0 {
+		horizon = *args.Horizon
+	}
+
+	st := store.NewJSONStore(cfg.RawRoot)
+	raw, err := st.ReadRaw(fmt.Sprintf("league/%d/details.json", args.LeagueID))
+	if err != nil {
+		return TeamCoverageOutput{}, err
+	}
+	var details leagueDetailsRaw
+	if err := json.Unmarshal(raw, &details); err != nil {
+		return TeamCoverageOutput{}, err
+	}
+	nameByEntry := make(map[int]string, len(details.LeagueEntries))
+	for _, e := range details.LeagueEntries {
+		nameByEntry[e.EntryID] = e.EntryName
+	}
+
+	var entryIDs []int
+	if args.All != nil && *args.All {
+		for _, e := range details.LeagueEntries {
+			entryIDs = append(entryIDs, e.EntryID)
+		}
+	} else {
+		entryID := 0
+		if args.EntryID != nil {
+			entryID = *args.EntryID
+		}
+		if entryID == 0 {
+			name := ""
+			if args.EntryName != nil {
+				name = strings.TrimSpace(*args.EntryName)
+			}
+			if name == "" {
+				return TeamCoverageOutput{}, invalidArgumentf("entry_id, entry_name, or all is required")
+			}
+			id, err := resolveEntry(details.LeagueEntries, name)
+			if err != nil {
+				return TeamCoverageOutput{}, err
+			}
+			entryID = id
+		}
+		if _, ok := nameByEntry[entryID]; !ok {
+			return TeamCoverageOutput{}, notFoundf("entry not found: %d", entryID)
+		}
+		entryIDs = []int{entryID}
+	}
+
+	rosterGW, note, err := resolveEffectiveGW(cfg, 0, gwModeLatestFinished)
+	if err != nil {
+		return TeamCoverageOutput{}, err
+	}
+	fromGW, _, err := resolveEffectiveGW(cfg, 0, gwModeCurrent)
+	if err != nil {
+		return TeamCoverageOutput{}, err
+	}
+
+	relPath := fmt.Sprintf("summary/fixtures/%d/from_gw/%d_h%d.json", args.LeagueID, fromGW, horizon)
+	fixturesRaw, err := loadSummaryFile(cfg, args.LeagueID, fromGW, relPath, []int{horizon}, nil)
+	if err != nil {
+		return TeamCoverageOutput{}, err
+	}
+	var fixtures summary.UpcomingFixturesSummary
+	if err := json.Unmarshal(fixturesRaw, &fixtures); err != nil {
+		return TeamCoverageOutput{}, err
+	}
+
+	elements, teamShort, _, err := loadBootstrapData(cfg.RawRoot)
+	if err != nil {
+		return TeamCoverageOutput{}, err
+	}
+	elementByID := make(map[int]elementInfo, len(elements))
+	for _, e := range elements {
+		elementByID[e.ID] = e
+	}
+
+	if err := ensureSnapshots(st, cfg.DerivedRoot, args.LeagueID, entryIDs, rosterGW, rosterGW, cfg.Clock); err != nil {
+		return TeamCoverageOutput{}, err
+	}
+
+	out := TeamCoverageOutput{
+		LeagueID: args.LeagueID,
+		RosterGW: rosterGW,
+		FromGW:   fromGW,
+		Horizon:  horizon,
+		Entries:  make([]EntryCoverage, 0, len(entryIDs)),
+		GWNote:   note,
+	}
+	for _, entryID := range entryIDs {
+		snapPath := filepath.Join(cfg.DerivedRoot, fmt.Sprintf("snapshots/%d/entry/%d/gw/%d.json", args.LeagueID, entryID, rosterGW))
+		b, err := store.ReadDerived(snapPath)
+		if err != nil {
+			return TeamCoverageOutput{}, err
+		}
+		var snap ledger.EntrySnapshot
+		if err := json.Unmarshal(b, &snap); err != nil {
+			return TeamCoverageOutput{}, err
+		}
+		players := make([]coveragePlayer, 0, len(snap.Picks))
+		for _, p := range snap.Picks {
+			info, ok := elementByID[p.Element]
+			if !ok {
+				continue
+			}
+			players = append(players, coveragePlayer{
+				Element: p.Element,
+				Name:    info.Name,
+				TeamID:  info.TeamID,
+				Starter: p.Position <= 11,
+			})
+		}
+		cov := entryCoverage(players, fixtures.Fixtures, teamShort, fromGW, horizon)
+		cov.EntryID = entryID
+		cov.EntryName = nameByEntry[entryID]
+		out.Entries = append(out.Entries, cov)
+	}
+	return out, nil
+}
+
+// entryCoverage builds the exposure report for one roster against the
+// fixtures in [fromGW, fromGW+horizon).
+func entryCoverage(players []coveragePlayer, fixtures []summary.FixtureSummary, teamShort map[int]string, fromGW int, horizon int) EntryCoverage {
+	cov := EntryCoverage{
+		Teams:     []TeamExposure{},
+		Blanks:    []BlankExposure{},
+		Slots:     []SlotConcentration{},
+		Opponents: []OpponentConcentration{},
+		Conflicts: []FixtureConflict{},
+	}
+
+	byTeam := make(map[int]*TeamExposure)
+	for _, p := range players {
+		te, ok := byTeam[p.TeamID]
+		if !ok {
+			te = &TeamExposure{TeamID: p.TeamID, Team: teamShort[p.TeamID]}
+			byTeam[p.TeamID] = te
+		}
+		te.Players++
+		if p.Starter {
+			te.Starters++
+		} else {
+			te.Bench++
+		}
+		te.Names = append(te.Names, p.Name)
+	}
+	for _, te := range byTeam {
+		cov.Teams = append(cov.Teams, *te)
+	}
+	sort.Slice(cov.Teams, func(i, j int) bool {
+		if cov.Teams[i].Players != cov.Teams[j].Players {
+			return cov.Teams[i].Players > cov.Teams[j].Players
+		}
+		return cov.Teams[i].Team < cov.Teams[j].Team
+	})
+
+	endGW := fromGW + horizon - 1
+	fixturesByGW := make(map[int][]summary.FixtureSummary)
+	for _, f := range fixtures {
+		if f.Event < fromGW || f.Event > endGW {
+			continue
+		}
+		fixturesByGW[f.Event] = append(fixturesByGW[f.Event], f)
+	}
+	gws := make([]int, 0, len(fixturesByGW))
+	for gw := range fixturesByGW {
+		gws = append(gws, gw)
+	}
+	sort.Ints(gws)
+
+	// opponentAgg counts players by element id; two players can share a
+	// web_name.
+	type opponentAgg struct {
+		gws     map[int]bool
+		players map[int]string
+	}
+	opponents := make(map[int]*opponentAgg)
+
+	for _, gw := range gws {
+		playing := make(map[int]bool)
+		slots := make(map[string][]string)
+		for _, f := range fixturesByGW[gw] {
+			playing[f.TeamH] = true
+			playing[f.TeamA] = true
+
+			var home, away []string
+			for _, p := range players {
+				var opp int
+				switch p.TeamID {
+				case f.TeamH:
+					home = append(home, p.Name)
+					opp = f.TeamA
+				case f.TeamA:
+					away = append(away, p.Name)
+					opp = f.TeamH
+				default:
+					continue
+				}
+				agg, ok := opponents[opp]
+				if !ok {
+					agg = &opponentAgg{gws: map[int]bool{}, players: map[int]string{}}
+					opponents[opp] = agg
+				}
+				agg.gws[gw] = true
+				agg.players[p.Element] = p.Name
+				if p.Starter && f.KickoffUTC != "" {
+					slots[f.KickoffUTC] = append(slots[f.KickoffUTC], p.Name)
+				}
+			}
+			if len(home) > 0 && len(away) > 0 && len(home)+len(away) >= coverageConflictPlayers {
+				cov.Conflicts = append(cov.Conflicts, FixtureConflict{
+					FixtureID:   f.FixtureID,
+					GW:          gw,
+					KickoffUTC:  f.KickoffUTC,
+					Home:        f.TeamHShort,
+					Away:        f.TeamAShort,
+					HomePlayers: home,
+					AwayPlayers: away,
+				})
+				cov.Warnings = append(cov.Warnings, fmt.Sprintf("GW %d %s v %s: %d of your players on opposite sides", gw, f.TeamHShort, f.TeamAShort, len(home)+len(away)))
+			}
+		}
+
+		blank := BlankExposure{GW: gw}
+		for _, p := range players {
+			if p.Starter && !playing[p.TeamID] {
+				blank.Starters++
+				blank.Names = append(blank.Names, p.Name)
+			}
+		}
+		cov.Blanks = append(cov.Blanks, blank)
+
+		kickoffs := make([]string, 0, len(slots))
+		for k := range slots {
+			kickoffs = append(kickoffs, k)
+		}
+		sort.Strings(kickoffs)
+		for _, k := range kickoffs {
+			names := slots[k]
+			if len(names) < coverageSlotStarters {
+				continue
+			}
+			cov.Slots = append(cov.Slots, SlotConcentration{GW: gw, KickoffUTC: k, Starters: len(names), Names: names})
+			cov.Warnings = append(cov.Warnings, fmt.Sprintf("%d of your starters play in the same slot (%s, GW %d)", len(names), formatKickoffSlot(k), gw))
+		}
+	}
+
+	for opp, agg := range opponents {
+		if len(agg.players) < coverageOpponentPlayers {
+			continue
+		}
+		oc := OpponentConcentration{Opponent: teamShort[opp], Players: len(agg.players)}
+		for gw := range agg.gws {
+			oc.GWs = append(oc.GWs, gw)
+		}
+		sort.Ints(oc.GWs)
+		for _, n := range agg.players {
+			oc.Names = append(oc.Names, n)
+		}
+		sort.Strings(oc.Names)
+		cov.Opponents = append(cov.Opponents, oc)
+	}
+	sort.Slice(cov.Opponents, func(i, j int) bool {
+		if cov.Opponents[i].Players != cov.Opponents[j].Players {
+			return cov.Opponents[i].Players > cov.Opponents[j].Players
+		}
+		return cov.Opponents[i].Opponent < cov.Opponents[j].Opponent
+	})
+	for _, oc := range cov.Opponents {
+		cov.Warnings = append(cov.Warnings, fmt.Sprintf("%d of your players face %s over the next %d GWs", oc.Players, oc.Opponent, horizon))
+	}
+	return cov
+}
+
+// formatKickoffSlot renders an RFC 3339 kickoff as e.g. "Sat 15:00 UTC".
+func formatKickoffSlot(kickoff string) string {
+	t, err := time.Parse(time.RFC3339, kickoff)
+	if err != nil {
+		return kickoff
+	}
+	return t.UTC().Format("Mon 15:04 UTC")
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/summary"
+)
+
+func TestEntryCoverage(t *testing.T) {
+	teamShort := map[int]string{10: "LIV", 11: "MCI", 12: "ARS", 13: "CHE"}
+	players := []coveragePlayer{
+		{Element: 1, Name: "Salah", TeamID: 10, Starter: true},
+		{Element: 2, Name: "Alexander-Arnold", TeamID: 10, Starter: true},
+		{Element: 3, Name: "Haaland", TeamID: 11, Starter: true},
+		{Element: 4, Name: "Saka", TeamID: 12, Starter: true},
+		{Element: 5, Name: "Palmer", TeamID: 13, Starter: true},
+		{Element: 6, Name: "Gvardiol", TeamID: 11, Starter: false},
+		{Element: 7, Name: "Raya", TeamID: 12, Starter: false},
+	}
+	sat3pm := "2025-10-18T14:00:00Z"
+	fixtures := []summary.FixtureSummary{
+		{FixtureID: 1, Event: 8, TeamH: 10, TeamA: 11, TeamHShort: "LIV", TeamAShort: "MCI", KickoffUTC: sat3pm},
+		{FixtureID: 2, Event: 8, TeamH: 12, TeamA: 13, TeamHShort: "ARS", TeamAShort: "CHE", KickoffUTC: sat3pm},
+		// GW 9: CHE blank.
+		{FixtureID: 3, Event: 9, TeamH: 12, TeamA: 11, TeamHShort: "ARS", TeamAShort: "MCI", KickoffUTC: "2025-10-25T16:30:00Z"},
+		{FixtureID: 4, Event: 9, TeamH: 10, TeamA: 14, TeamHShort: "LIV", TeamAShort: "TOT", KickoffUTC: "2025-10-26T14:00:00Z"},
+		// Outside the horizon.
+		{FixtureID: 5, Event: 10, TeamH: 13, TeamA: 11, TeamHShort: "CHE", TeamAShort: "MCI"},
+	}
+
+	cov := entryCoverage(players, fixtures, teamShort, 8, 2)
+
+	t.Run("TeamExposure", func(t *testing.T) {
+		if len(cov.Teams) != 4 {
+			t.Fatalf("teams = %d, want 4", len(cov.Teams))
+		}
+		// Ties on player count are ordered by team name.
+		if got := cov.Teams[0]; got.Team != "ARS" || got.Players != 2 {
+			t.Errorf("first team = %+v, want ARS with 2 players", got)
+		}
+		if last := cov.Teams[3]; last.Team != "CHE" || last.Players != 1 {
+			t.Errorf("last team = %+v, want CHE with 1 player", last)
+		}
+		for _, te := range cov.Teams {
+			if te.Team == "MCI" && (te.Starters != 1 || te.Bench != 1) {
+				t.Errorf("MCI = %+v, want 1 starter and 1 bench", te)
+			}
+		}
+	})
+
+	t.Run("Blanks", func(t *testing.T) {
+		if len(cov.Blanks) != 2 {
+			t.Fatalf("blanks = %+v, want rows for GW 8 and 9", cov.Blanks)
+		}
+		if cov.Blanks[0].Starters != 0 {
+			t.Errorf("GW8 blanks = %+v, want none", cov.Blanks[0])
+		}
+		if b := cov.Blanks[1]; b.GW != 9 || b.Starters != 1 || b.Names[0] != "Palmer" {
+			t.Errorf("GW9 blanks = %+v, want Palmer", b)
+		}
+	})
+
+	t.Run("SameSlot", func(t *testing.T) {
+		if len(cov.Slots) != 1 {
+			t.Fatalf("slots = %+v, want one", cov.Slots)
+		}
+		if s := cov.Slots[0]; s.GW != 8 || s.Starters != 5 || s.KickoffUTC != sat3pm {
+			t.Errorf("slot = %+v, want 5 GW8 starters at %s", s, sat3pm)
+		}
+	})
+
+	t.Run("Conflicts", func(t *testing.T) {
+		// LIV v TOT only has players on one side and is not a conflict.
+		var ids []int
+		for _, c := range cov.Conflicts {
+			ids = append(ids, c.FixtureID)
+		}
+		if len(ids) != 3 || ids[0] != 1 || ids[1] != 2 || ids[2] != 3 {
+			t.Fatalf("conflict fixtures = %v, want [1 2 3]", ids)
+		}
+		if c := cov.Conflicts[0]; len(c.HomePlayers) != 2 || len(c.AwayPlayers) != 2 {
+			t.Errorf("LIV v MCI = %+v, want 2 v 2", c)
+		}
+	})
+
+	t.Run("Opponents", func(t *testing.T) {
+		if len(cov.Opponents) != 1 {
+			t.Fatalf("opponents = %+v, want one", cov.Opponents)
+		}
+		if o := cov.Opponents[0]; o.Opponent != "MCI" || o.Players != 4 || len(o.GWs) != 2 {
+			t.Errorf("opponent = %+v, want MCI faced by 4 players in 2 GWs", o)
+		}
+	})
+
+	t.Run("Warnings", func(t *testing.T) {
+		joined := strings.Join(cov.Warnings, "\n")
+		for _, want := range []string{"5 of your starters play in the same slot (Sat 14:00 UTC, GW 8)", "4 of your players face MCI over the next 2 GWs"} {
+			if !strings.Contains(joined, want) {
+				t.Errorf("warnings missing %q:\n%s", want, joined)
+			}
+		}
+	})
+}
+
+// Two players sharing a web_name are still two players facing the opponent.
+func TestEntryCoverage_OpponentCountsElements(t *testing.T) {
+	teamShort := map[int]string{10: "LIV", 11: "MCI", 12: "ARS"}
+	players := []coveragePlayer{
+		{Element: 1, Name: "Gabriel", TeamID: 12},
+		{Element: 2, Name: "Gabriel", TeamID: 12},
+		{Element: 3, Name: "Saka", TeamID: 12},
+		{Element: 4, Name: "Salah", TeamID: 10},
+	}
+	fixtures := []summary.FixtureSummary{
+		{FixtureID: 1, Event: 8, TeamH: 12, TeamA: 11, TeamHShort: "ARS", TeamAShort: "MCI"},
+		{FixtureID: 2, Event: 9, TeamH: 11, TeamA: 10, TeamHShort: "MCI", TeamAShort: "LIV"},
+	}
+	cov := entryCoverage(players, fixtures, teamShort, 8, 2)
+	if len(cov.Opponents) != 1 {
+		t.Fatalf("opponents = %+v, want MCI", cov.Opponents)
+	}
+	if o := cov.Opponents[0]; o.Players != 4 || strings.Join(o.Names, ",") != "Gabriel,Gabriel,Saka,Salah" {
+		t.Errorf("opponent = %+v, want 4 players with both Gabriels named", o)
+	}
+}
+
+func TestBuildTeamCoverage(t *testing.T) {
+	dir, cfg := tmpCfg(t)
+	cfg.DerivedRoot = dir
+	cfg.ComputeMissing = true
+	writeJSON(t, filepath.Join(dir, "bootstrap", "bootstrap-static.json"), map[string]any{
+		"elements": []any{
+			map[string]any{"id": 1, "web_name": "Salah", "team": 10, "element_type": 3, "status": "a"},
+			map[string]any{"id": 2, "web_name": "Haaland", "team": 11, "element_type": 4, "status": "a"},
+			map[string]any{"id": 3, "web_name": "Alexander-Arnold", "team": 10, "element_type": 2, "status": "a"},
+		},
+		"teams": []any{
+			map[string]any{"id": 10, "short_name": "LIV"},
+			map[string]any{"id": 11, "short_name": "MCI"},
+		},
+		"fixtures": map[string]any{
+			"3": []any{map[string]any{"id": 5, "event": 3, "team_h": 10, "team_a": 11, "kickoff_time": "2025-08-30T14:00:00Z"}},
+		},
+	})
+	writeFullGameJSON(t, dir, 2, true, 3, false, "")
+	writeLiveJSON(t, dir, 2, map[string]any{})
+	writeLeagueDetailsFixture(t, dir, 100, []any{
+		map[string]any{"id": 1, "entry_id": 200, "entry_name": "Alpha FC", "short_name": "AFC"},
+		map[string]any{"id": 2, "entry_id": 201, "entry_name": "Beta FC", "short_name": "BFC"},
+	}, []any{})
+	writeJSON(t, filepath.Join(dir, "entry/200/gw/2.json"), map[string]any{
+		"picks": []any{
+			map[string]any{"element": 1, "position": 1},
+			map[string]any{"element": 3, "position": 2},
+			map[string]any{"element": 2, "position": 12},
+		},
+	})
+	writeJSON(t, filepath.Join(dir, "entry/201/gw/2.json"), map[string]any{
+		"picks": []any{map[string]any{"element": 2, "position": 1}},
+	})
+
+	t.Run("ByName", func(t *testing.T) {
+		name := "afc"
+		out, err := buildTeamCoverage(cfg, TeamCoverageArgs{LeagueID: 100, EntryName: &name})
+		if err != nil {
+			t.Fatalf("buildTeamCoverage: %v", err)
+		}
+		if out.RosterGW != 2 || out.FromGW != 3 || out.Horizon != 3 {
+			t.Errorf("roster_gw=%d from_gw=%d horizon=%d, want 2/3/3", out.RosterGW, out.FromGW, out.Horizon)
+		}
+		if len(out.Entries) != 1 || out.Entries[0].EntryID != 200 {
+			t.Fatalf("entries = %+v, want only 200", out.Entries)
+		}
+		e := out.Entries[0]
+		if len(e.Teams) != 2 || e.Teams[0].Team != "LIV" || e.Teams[0].Starters != 2 {
+			t.Errorf("teams = %+v, want LIV with 2 starters first", e.Teams)
+		}
+		if len(e.Conflicts) != 1 || e.Conflicts[0].FixtureID != 5 {
+			t.Errorf("conflicts = %+v, want LIV v MCI", e.Conflicts)
+		}
+	})
+
+	t.Run("All", func(t *testing.T) {
+		all := true
+		out, err := buildTeamCoverage(cfg, TeamCoverageArgs{LeagueID: 100, All: &all})
+		if err != nil {
+			t.Fatalf("buildTeamCoverage: %v", err)
+		}
+		if len(out.Entries) != 2 {
+			t.Errorf("entries = %d, want 2", len(out.Entries))
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		if _, err := buildTeamCoverage(cfg, TeamCoverageArgs{LeagueID: 100}); classifyError(err).Code != codeInvalidArgument {
+			t.Errorf("no entry: err = %v, want INVALID_ARGUMENT", err)
+		}
+		missing := 999
+		if _, err := buildTeamCoverage(cfg, TeamCoverageArgs{LeagueID: 100, EntryID: &missing}); classifyError(err).Code != codeNotFound {
+			t.Errorf("unknown entry: err = %v, want NOT_FOUND", err)
+		}
+	})
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// TeamDefenseProfileArgs are the input arguments for the team_defense_profile
+// tool.
+type TeamDefenseProfileArgs struct {
+	Team    string `json:"team,omitempty" jsonschema:"Premier League team (short name, e.g. ARS, or team id) or all (default all)"`
+	Horizon *int   `json:"horizon,omitempty" jsonschema:"Recent window in GWs (default 5)"`
+	Format  string `json:"format,omitempty" jsonschema:"json|markdown|csv (default json)"`
+}
+
+// TeamVenueSplit is a team's points per match at one position and venue,
+// over the season and the recent window. Ranks are among the teams with
+// matches in that cell, 1 for the most points, and ties share a rank. Trend
+// is recent minus season. A window with no matches leaves its value null.
+type TeamVenueSplit struct {
+	Season        *float64 `json:"season"`
+	SeasonMatches int      `json:"season_matches"`
+	SeasonRank    *int     `json:"season_rank"`
+	Recent        *float64 `json:"recent"`
+	RecentMatches int      `json:"recent_matches"`
+	RecentRank    *int     `json:"recent_rank"`
+	Trend         *float64 `json:"trend"`
+}
+
+// TeamPositionSplit is one position's home and away splits.
+type TeamPositionSplit struct {
+	Position string         `json:"position"`
+	Home     TeamVenueSplit `json:"home"`
+	Away     TeamVenueSplit `json:"away"`
+}
+
+// TeamDefenseProfile is a team's points conceded to each position (what
+// opposing GK, DEF, MID and FWD scored against it) and the attacking view,
+// the points its own players scored, both GK to FWD.
+type TeamDefenseProfile struct {
+	TeamID   int                 `json:"team_id"`
+	Team     string              `json:"team"`
+	Conceded []TeamPositionSplit `json:"conceded"`
+	Scored   []TeamPositionSplit `json:"scored"`
+}
+
+type TeamDefenseProfileOutput struct {
+	AsOfGW       int                  `json:"as_of_gw"`
+	Horizon      int                  `json:"horizon"`
+	RecentFromGW int                  `json:"recent_from_gw"`
+	Teams        []TeamDefenseProfile `json:"teams"`
+	Notes        []string             `json:"notes"`
+}
+
+func buildTeamDefenseProfile(cfg ServerConfig, args TeamDefenseProfileArgs) (TeamDefenseProfileOutput, error) {
+	horizon := 5
+	if args.Horizon != nil {
+		if *args.Horizon < 1 {
+			return TeamDefenseProfileOutput{}, invalidArgumentf("horizon must be at least 1, got %d", *args.Horizon)
+		}
+		horizon = *args.Horizon
+	}
+	asOfGW, _, err := resolveAsOfAndNextGW(cfg, 0, 0)
+	if err != nil {
+		return TeamDefenseProfileOutput{}, err
+	}
+	elements, teamShort, _, err := loadBootstrapData(cfg.RawRoot)
+	if err != nil {
+		return TeamDefenseProfileOutput{}, err
+	}
+	teamID := 0
+	if name := strings.TrimSpace(args.Team); name != "" && !strings.EqualFold(name, "all") {
+		if teamID, err = resolveTeamID(name, teamShort); err != nil {
+			return TeamDefenseProfileOutput{}, err
+		}
+	}
+	horizon = min(horizon, asOfGW)
+
+	seasonConceded, seasonScored := computeTeamPointsByPosition(cfg.RawRoot, elements, asOfGW, asOfGW)
+	recentConceded, recentScored := computeTeamPointsByPosition(cfg.RawRoot, elements, asOfGW, horizon)
+
+	teamIDs := make([]int, 0, len(teamShort))
+	for id := range teamShort {
+		if teamID == 0 || id == teamID {
+			teamIDs = append(teamIDs, id)
+		}
+	}
+	sort.Ints(teamIDs)
+
+	// Ranks are over every team, so one team's profile ranks the same as it
+	// does in the full table.
+	concededRanks := [2]map[string]map[int]map[int]int{rankTeamPoints(seasonConceded), rankTeamPoints(recentConceded)}
+	scoredRanks := [2]map[string]map[int]map[int]int{rankTeamPoints(seasonScored), rankTeamPoints(recentScored)}
+
+	out := TeamDefenseProfileOutput{
+		AsOfGW:       asOfGW,
+		Horizon:      horizon,
+		RecentFromGW: max(asOfGW-horizon+1, 1),
+		Teams:        make([]TeamDefenseProfile, 0, len(teamIDs)),
+		Notes: []string{
+			"conceded is what opposing players at each position scored against the team per match; scored is what the team's own players at each position scored per match",
+			"home and away are the team's venue; rank 1 is the most points among the teams, so a high conceded rank marks a soft defence and a high scored rank a strong attack",
+			"trend is recent minus season: positive means more points lately",
+		},
+	}
+	for _, id := range teamIDs {
+		out.Teams = append(out.Teams, TeamDefenseProfile{
+			TeamID:   id,
+			Team:     teamShort[id],
+			Conceded: teamPositionSplits(id, seasonConceded, recentConceded, concededRanks),
+			Scored:   teamPositionSplits(id, seasonScored, recentScored, scoredRanks),
+		})
+	}
+	if len(seasonConceded) == 0 {
+		out.Notes = append(out.Notes, "no finished GW has live data yet, so every split is empty")
+	} else if coldStart(asOfGW) {
+		out.Notes = append(out.Notes, fmt.Sprintf("only %d GW(s) played: each split is a match or two", asOfGW))
+	}
+	return out, nil
+}
+
+// teamPositionSplits builds a team's splits GK to FWD from the season and
+// recent tallies and their ranks.
+func teamPositionSplits(teamID int, season, recent map[int]map[string]map[int]avgStat, ranks [2]map[string]map[int]map[int]int) []TeamPositionSplit {
+	split := func(venue string, pos int) TeamVenueSplit {
+		s := season[teamID][venue][pos]
+		r := recent[teamID][venue][pos]
+		v := TeamVenueSplit{SeasonMatches: s.Count, RecentMatches: r.Count}
+		if s.Count > 0 {
+			avg := round3(s.Sum / float64(s.Count))
+			rank := ranks[0][venue][pos][teamID]
+			v.Season, v.SeasonRank = &avg, &rank
+		}
+		if r.Count > 0 {
+			avg := round3(r.Sum / float64(r.Count))
+			rank := ranks[1][venue][pos][teamID]
+			v.Recent, v.RecentRank = &avg, &rank
+		}
+		if v.Season != nil && v.Recent != nil {
+			trend := round3(*v.Recent - *v.Season)
+			v.Trend = &trend
+		}
+		return v
+	}
+	out := make([]TeamPositionSplit, 0, 4)
+	for pos := 1; pos <= 4; pos++ {
+		out = append(out, TeamPositionSplit{
+			Position: positionLabel(pos),
+			Home:     split("HOME", pos),
+			Away:     split("AWAY", pos),
+		})
+	}
+	return out
+}
+
+// rankTeamPoints ranks the teams in each venue and position cell of a tally
+// by points per match, most first; tied teams share a rank.
+func rankTeamPoints(tally map[int]map[string]map[int]avgStat) map[string]map[int]map[int]int {
+	out := make(map[string]map[int]map[int]int, 2)
+	for _, venue := range []string{"HOME", "AWAY"} {
+		out[venue] = make(map[int]map[int]int, 4)
+		for pos := 1; pos <= 4; pos++ {
+			type entry struct {
+				team int
+				avg  float64
+			}
+			entries := make([]entry, 0, len(tally))
+			for team, venues := range tally {
+				if s := venues[venue][pos]; s.Count > 0 {
+					entries = append(entries, entry{team, round3(s.Sum / float64(s.Count))})
+				}
+			}
+			sort.Slice(entries, func(i, j int) bool {
+				if entries[i].avg != entries[j].avg {
+					return entries[i].avg > entries[j].avg
+				}
+				return entries[i].team < entries[j].team
+			})
+			ranks := make(map[int]int, len(entries))
+			for i, e := range entries {
+				if i > 0 && e.avg == entries[i-1].avg {
+					ranks[e.team] = ranks[entries[i-1].team]
+				} else {
+					ranks[e.team] = i + 1
+				}
+			}
+			out[venue][pos] = ranks
+		}
+	}
+	return out
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestTeamDefenseProfile(t *testing.T) {
+	dir, cfg := resourceCfg(t)
+	writeGW1Fixture(t, dir, gw1Live(), true)
+
+	// GW1 is BUR (home) v ARS: Arsenal's GK and DEF took 6 each and their
+	// midfield 14, while BUR's DEF scored 1 and FWD 2.
+	out, err := buildTeamDefenseProfile(cfg, TeamDefenseProfileArgs{Team: "ars"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.AsOfGW != 1 || out.Horizon != 1 || len(out.Teams) != 1 || out.Teams[0].Team != "ARS" {
+		t.Fatalf("out = %+v", out)
+	}
+	ars := out.Teams[0]
+	if len(ars.Conceded) != 4 || len(ars.Scored) != 4 || ars.Conceded[0].Position != "GK" || ars.Scored[3].Position != "FWD" {
+		t.Fatalf("positions should run GK to FWD: %+v", ars)
+	}
+	def := ars.Conceded[1].Away
+	if def.Season == nil || *def.Season != 1 || *def.SeasonRank != 1 || def.SeasonMatches != 1 || *def.Trend != 0 {
+		t.Errorf("ARS conceded DEF away = %+v, want 1 point ranked 1 with no trend", def)
+	}
+	if home := ars.Conceded[1].Home; home.Season != nil || home.SeasonRank != nil || home.Trend != nil {
+		t.Errorf("ARS played no home game, so home should be empty: %+v", home)
+	}
+	if mid := ars.Scored[2].Away; mid.Season == nil || *mid.Season != 14 {
+		t.Errorf("ARS scored MID away = %+v, want 14", mid)
+	}
+
+	all, err := buildTeamDefenseProfile(cfg, TeamDefenseProfileArgs{Team: "all"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all.Teams) != 2 || all.Teams[1].Team != "BUR" {
+		t.Fatalf("all teams = %+v", all.Teams)
+	}
+	if bur := all.Teams[1].Conceded[2].Home; bur.Season == nil || *bur.Season != 14 {
+		t.Errorf("BUR conceded MID at home = %+v, want ARS's 14", bur)
+	}
+
+	if _, err := buildTeamDefenseProfile(cfg, TeamDefenseProfileArgs{Team: "XYZ"}); classifyError(err).Code != codeInvalidArgument {
+		t.Errorf("unknown team: err = %v", err)
+	}
+	zero := 0
+	if _, err := buildTeamDefenseProfile(cfg, TeamDefenseProfileArgs{Horizon: &zero}); classifyError(err).Code != codeInvalidArgument {
+		t.Errorf("horizon 0: err = %v", err)
+	}
+}
+
+func TestRankTeamPoints_TiesShareRank(t *testing.T) {
+	tally := map[int]map[string]map[int]avgStat{
+		1: {"HOME": {2: {Sum: 6, Count: 2}}, "AWAY": {}},
+		2: {"HOME": {2: {Sum: 9, Count: 3}}, "AWAY": {}},
+		3: {"HOME": {2: {Sum: 8, Count: 2}}, "AWAY": {}},
+		4: {"HOME": {}, "AWAY": {}},
+	}
+	ranks := rankTeamPoints(tally)["HOME"][2]
+	if ranks[3] != 1 || ranks[1] != 2 || ranks[2] != 2 {
+		t.Errorf("ranks = %v, want team 3 first and 1 and 2 tied second", ranks)
+	}
+	if _, ok := ranks[4]; ok {
+		t.Error("a team with no matches shouldn't be ranked")
+	}
+}
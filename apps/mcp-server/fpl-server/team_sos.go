@@ -0,0 +1,315 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+)
+
+// sosFormWindow is the recent-form window (GWs) in the season/recent
+// points-conceded blend, matching fixture_difficulty's default horizon.
+const sosFormWindow = 5
+
+type TeamSOSArgs struct {
+	LeagueID int  `json:"league_id" jsonschema:"Draft league id (required)"`
+	Horizon  *int `json:"horizon,omitempty" jsonschema:"GWs to cover from the next one (default rest of season)"`
+	EntryID  *int `json:"entry_id,omitempty" jsonschema:"Entry id whose players to place on easy or hard runs"`
+}
+
+// SOSFixture is one remaining fixture for a team. Score is the opponent's
+// blended points conceded averaged over the four positions.
+type SOSFixture struct {
+	GW         int     `json:"gw"`
+	FixtureID  int     `json:"fixture_id"`
+	OpponentID int     `json:"opponent_id"`
+	Opponent   string  `json:"opponent"`
+	Venue      string  `json:"venue"`
+	Score      float64 `json:"score"`
+}
+
+// TeamSOS is a Premier League team's remaining schedule. Averages are per
+// fixture, so a double counts both games and a blank adds nothing; higher
+// means opponents who concede more, i.e. an easier run.
+type TeamSOS struct {
+	Rank       int                `json:"rank"`
+	TeamID     int                `json:"team_id"`
+	Team       string             `json:"team"`
+	Fixtures   int                `json:"fixtures"`
+	Average    float64            `json:"average"`
+	ByPosition map[string]float64 `json:"by_position"`
+	Doubles    []int              `json:"doubles"`
+	Blanks     []int              `json:"blanks"`
+	Schedule   []SOSFixture       `json:"schedule"`
+}
+
+// SOSPlayer is a rostered player placed by their team's run at their position.
+type SOSPlayer struct {
+	Element  int     `json:"element"`
+	Name     string  `json:"name"`
+	Position string  `json:"position"`
+	Team     string  `json:"team"`
+	Rank     int     `json:"rank"`
+	Average  float64 `json:"average"`
+	Fixtures int     `json:"fixtures"`
+}
+
+// SOSRoster splits an entry's players into thirds by how their team's
+// remaining schedule ranks for their position. NoFixtures holds players whose
+// team has nothing left in the window.
+type SOSRoster struct {
+	EntryID    int         `json:"entry_id"`
+	EntryName  string      `json:"entry_name"`
+	Easy       []SOSPlayer `json:"easy"`
+	Neutral    []SOSPlayer `json:"neutral"`
+	Hard       []SOSPlayer `json:"hard"`
+	NoFixtures []SOSPlayer `json:"no_fixtures,omitempty"`
+	Summary    string      `json:"summary"`
+}
+
+type TeamSOSOutput struct {
+	LeagueID   int        `json:"league_id"`
+	AsOfGW     int        `json:"as_of_gw"`
+	FromGW     int        `json:"from_gw"`
+	ThroughGW  int        `json:"through_gw"`
+	UnknownGWs []int      `json:"unknown_gws,omitempty"`
+	Teams      []TeamSOS  `json:"teams"`
+	Roster     *SOSRoster `json:"roster,omitempty"`
+	Notes      []string   `json:"notes"`
+}
+
+func buildTeamSOS(cfg ServerConfig, args TeamSOSArgs) (TeamSOSOutput, error) {
+	if args.LeagueID == 0 {
+		return TeamSOSOutput{}, invalidArgumentf("league_id is required")
+	}
+	if args.Horizon != nil && *args.Horizon < 0 {
+		return TeamSOSOutput{}, invalidArgumentf("horizon must not be negative, got %d", *args.Horizon)
+	}
+
+	asOfGW, nextGW, err := resolveAsOfAndNextGW(cfg, 0, 0)
+	if err != nil {
+		return TeamSOSOutput{}, err
+	}
+	elements, teamShort, fixturesByGW, err := loadBootstrapData(cfg.RawRoot)
+	if err != nil {
+		return TeamSOSOutput{}, err
+	}
+
+	throughGW := nextGW
+	for gw := range fixturesByGW {
+		throughGW = max(throughGW, gw)
+	}
+	if args.Horizon != nil && *args.Horizon > 0 {
+		throughGW = min(throughGW, nextGW+*args.Horizon-1)
+	}
+
+	out := TeamSOSOutput{
+		LeagueID:  args.LeagueID,
+		AsOfGW:    asOfGW,
+		FromGW:    nextGW,
+		ThroughGW: throughGW,
+		Teams:     []TeamSOS{},
+	}
+
+	// A GW with no fixtures at all isn't scheduled yet; it is left out
+	// rather than counted as a league-wide blank.
+	gws := make([]int, 0, throughGW-nextGW+1)
+	indexByGW := make(map[int]map[int][]FixtureContext)
+	for gw := nextGW; gw <= throughGW; gw++ {
+		fx := scheduleFixtures(cfg.RawRoot, fixturesByGW, gw)
+		if len(fx) == 0 {
+			out.UnknownGWs = append(out.UnknownGWs, gw)
+			continue
+		}
+		gws = append(gws, gw)
+		indexByGW[gw] = buildFixtureIndex(fx, teamShort)
+	}
+
+	seasonWeight, recentWeight := horizonWeights(sosFormWindow)
+	concededSeason := computePointsConcededByPosition(cfg.RawRoot, elements, asOfGW, asOfGW)
+	concededRecent := computePointsConcededByPosition(cfg.RawRoot, elements, asOfGW, sosFormWindow)
+	score := func(opponentID int, venue string, pos int) float64 {
+		_, _, b := blendedFixtureScore(concededSeason, concededRecent, opponentID, venue, pos, seasonWeight, recentWeight)
+		return b
+	}
+
+	for id, short := range teamShort {
+		out.Teams = append(out.Teams, teamSchedule(id, short, gws, indexByGW, score))
+	}
+	sort.Slice(out.Teams, func(i, j int) bool {
+		a, b := out.Teams[i], out.Teams[j]
+		if (a.Fixtures == 0) != (b.Fixtures == 0) {
+			return b.Fixtures == 0
+		}
+		if a.Average != b.Average {
+			return a.Average > b.Average
+		}
+		return a.Team < b.Team
+	})
+	for i := range out.Teams {
+		out.Teams[i].Rank = i + 1
+	}
+
+	out.Notes = []string{
+		fmt.Sprintf("Score is the opponent's points conceded to each position, home/away aware, blending season and last %d GWs (%.2f/%.2f).", sosFormWindow, seasonWeight, recentWeight),
+		"Averages are per fixture: doubles count both games and blanks add nothing. Higher is easier.",
+	}
+	if len(out.UnknownGWs) > 0 {
+		out.Notes = append(out.Notes, fmt.Sprintf("GWs %v have no fixtures listed yet and are left out.", out.UnknownGWs))
+	}
+
+	if args.EntryID == nil || *args.EntryID == 0 {
+		return out, nil
+	}
+	roster, err := sosRoster(cfg, args.LeagueID, *args.EntryID, resolveRosterGW(asOfGW, nextGW), elements, teamShort, out.Teams)
+	if err != nil {
+		return TeamSOSOutput{}, err
+	}
+	out.Roster = &roster
+	return out, nil
+}
+
+// teamSchedule scores teamID's fixtures across gws for every position.
+func teamSchedule(teamID int, short string, gws []int, indexByGW map[int]map[int][]FixtureContext, score outlookMultiplier) TeamSOS {
+	row := TeamSOS{
+		TeamID:     teamID,
+		Team:       short,
+		ByPosition: map[string]float64{},
+		Doubles:    []int{},
+		Blanks:     []int{},
+		Schedule:   []SOSFixture{},
+	}
+	posSum := make(map[int]float64)
+	total := 0.0
+	for _, gw := range gws {
+		contexts := indexByGW[gw][teamID]
+		switch {
+		case len(contexts) == 0:
+			row.Blanks = append(row.Blanks, gw)
+		case len(contexts) > 1:
+			row.Doubles = append(row.Doubles, gw)
+		}
+		for _, ctx := range contexts {
+			sum := 0.0
+			for pos := 1; pos <= 4; pos++ {
+				s := score(ctx.OpponentID, ctx.Venue, pos)
+				posSum[pos] += s
+				sum += s
+			}
+			row.Schedule = append(row.Schedule, SOSFixture{
+				GW:         gw,
+				FixtureID:  ctx.FixtureID,
+				OpponentID: ctx.OpponentID,
+				Opponent:   ctx.OpponentShort,
+				Venue:      ctx.Venue,
+				Score:      sum / 4,
+			})
+			total += sum / 4
+		}
+	}
+	if n := float64(len(row.Schedule)); n > 0 {
+		row.Fixtures = len(row.Schedule)
+		row.Average = total / n
+		for pos := 1; pos <= 4; pos++ {
+			row.ByPosition[positionLabel(pos)] = posSum[pos] / n
+		}
+	}
+	return row
+}
+
+// sosRoster places entryID's players by where their team's run ranks at
+// their position: the top third of teams with fixtures is easy, the bottom
+// third hard.
+func sosRoster(cfg ServerConfig, leagueID int, entryID int, rosterGW int, elements []elementInfo, teamShort map[int]string, teams []TeamSOS) (SOSRoster, error) {
+	ld, _, err := loadLeagueDetails(store.NewJSONStore(cfg.RawRoot), leagueID)
+	if err != nil {
+		return SOSRoster{}, err
+	}
+	roster := SOSRoster{EntryID: entryID, Easy: []SOSPlayer{}, Neutral: []SOSPlayer{}, Hard: []SOSPlayer{}}
+	for _, e := range ld.LeagueEntries {
+		if e.EntryID == entryID {
+			roster.EntryName = e.EntryName
+		}
+	}
+	if roster.EntryName == "" {
+		return SOSRoster{}, notFoundf("entry %d is not in league %d", entryID, leagueID)
+	}
+	ownership, err := loadOwnershipAtGW(cfg, leagueID, rosterGW)
+	if err != nil {
+		return SOSRoster{}, err
+	}
+
+	byTeam := make(map[int]TeamSOS, len(teams))
+	for _, t := range teams {
+		byTeam[t.TeamID] = t
+	}
+	// Rank teams separately per position so a defender is judged on
+	// opponents' defensive record and a forward on their attacking one.
+	withFixtures := make([]TeamSOS, 0, len(teams))
+	for _, t := range teams {
+		if t.Fixtures > 0 {
+			withFixtures = append(withFixtures, t)
+		}
+	}
+	ranked := len(withFixtures)
+	third := ranked / 3
+	rankByPos := make(map[int]map[int]int)
+	for pos := 1; pos <= 4; pos++ {
+		label := positionLabel(pos)
+		sort.SliceStable(withFixtures, func(i, j int) bool {
+			return withFixtures[i].ByPosition[label] > withFixtures[j].ByPosition[label]
+		})
+		rankByPos[pos] = make(map[int]int, len(withFixtures))
+		for i, t := range withFixtures {
+			rankByPos[pos][t.TeamID] = i + 1
+		}
+	}
+
+	for _, e := range elements {
+		if !ownership[entryID][e.ID] {
+			continue
+		}
+		t := byTeam[e.TeamID]
+		p := SOSPlayer{
+			Element:  e.ID,
+			Name:     e.Name,
+			Position: positionLabel(e.PositionType),
+			Team:     teamShort[e.TeamID],
+			Rank:     rankByPos[e.PositionType][e.TeamID],
+			Average:  t.ByPosition[positionLabel(e.PositionType)],
+			Fixtures: t.Fixtures,
+		}
+		switch {
+		case p.Rank == 0:
+			roster.NoFixtures = append(roster.NoFixtures, p)
+		case p.Rank <= third:
+			roster.Easy = append(roster.Easy, p)
+		case p.Rank > ranked-third:
+			roster.Hard = append(roster.Hard, p)
+		default:
+			roster.Neutral = append(roster.Neutral, p)
+		}
+	}
+	for _, group := range [][]SOSPlayer{roster.Easy, roster.Neutral, roster.Hard} {
+		sort.Slice(group, func(i, j int) bool {
+			if group[i].Rank != group[j].Rank {
+				return group[i].Rank < group[j].Rank
+			}
+			return group[i].Name < group[j].Name
+		})
+	}
+
+	names := func(ps []SOSPlayer) string {
+		if len(ps) == 0 {
+			return "none"
+		}
+		out := make([]string, len(ps))
+		for i, p := range ps {
+			out[i] = p.Name
+		}
+		return strings.Join(out, ", ")
+	}
+	roster.Summary = fmt.Sprintf("Your players on easy runs: %s; on hard runs: %s.", names(roster.Easy), names(roster.Hard))
+	return roster, nil
+}
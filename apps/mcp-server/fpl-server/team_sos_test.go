@@ -0,0 +1,148 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeTeamSOSFixture sets up two finished GWs of LIV v MCI in which Salah
+// scores 8 and Haaland 2, then GW3 (in live.json only), GW4 with LIV blanking
+// and GW5 with ARS doubling. Alpha owns a midfielder from each team.
+func writeTeamSOSFixture(t *testing.T, dir string) {
+	t.Helper()
+	writeJSON(t, filepath.Join(dir, "bootstrap", "bootstrap-static.json"), map[string]any{
+		"elements": []any{
+			map[string]any{"id": 1, "web_name": "Salah", "team": 10, "element_type": 3, "status": "a"},
+			map[string]any{"id": 2, "web_name": "Haaland", "team": 11, "element_type": 4, "status": "a"},
+			map[string]any{"id": 3, "web_name": "Saka", "team": 12, "element_type": 3, "status": "a"},
+			map[string]any{"id": 4, "web_name": "Foden", "team": 11, "element_type": 3, "status": "a"},
+		},
+		"teams": []any{
+			map[string]any{"id": 10, "short_name": "LIV"},
+			map[string]any{"id": 11, "short_name": "MCI"},
+			map[string]any{"id": 12, "short_name": "ARS"},
+		},
+		"fixtures": map[string]any{
+			"4": []any{map[string]any{"id": 6, "team_h": 12, "team_a": 11}},
+			"5": []any{
+				map[string]any{"id": 7, "team_h": 10, "team_a": 12},
+				map[string]any{"id": 8, "team_h": 11, "team_a": 12},
+			},
+		},
+	})
+	writeFullGameJSON(t, dir, 2, true, 3, false, "")
+	for gw := 1; gw <= 2; gw++ {
+		writeJSON(t, filepath.Join(dir, "gw", itoa(gw), "live.json"), map[string]any{
+			"elements": map[string]any{
+				"1": map[string]any{"stats": map[string]any{"total_points": 8}},
+				"2": map[string]any{"stats": map[string]any{"total_points": 2}},
+			},
+			"fixtures": []any{map[string]any{"id": gw, "team_h": 10, "team_a": 11}},
+		})
+	}
+	writeJSON(t, filepath.Join(dir, "gw", "3", "live.json"), map[string]any{
+		"elements": map[string]any{},
+		"fixtures": []any{map[string]any{"id": 5, "team_h": 10, "team_a": 11}},
+	})
+	writeLeagueDetailsFixture(t, dir, 100, []any{
+		map[string]any{"id": 1, "entry_id": 200, "entry_name": "Alpha FC"},
+	}, []any{})
+	writeJSON(t, filepath.Join(dir, "draft/100/choices.json"), map[string]any{
+		"choices": []any{
+			map[string]any{"entry": 200, "element": 1, "round": 1, "pick": 1, "index": 1},
+			map[string]any{"entry": 200, "element": 3, "round": 2, "pick": 1, "index": 2},
+			map[string]any{"entry": 200, "element": 4, "round": 3, "pick": 1, "index": 3},
+		},
+	})
+	writeJSON(t, filepath.Join(dir, "league/100/transactions.json"), map[string]any{"transactions": []any{}})
+	writeJSON(t, filepath.Join(dir, "league/100/trades.json"), map[string]any{"trades": []any{}})
+}
+
+func TestBuildTeamSOS(t *testing.T) {
+	dir, cfg := tmpCfg(t)
+	cfg.DerivedRoot = dir
+	writeTeamSOSFixture(t, dir)
+
+	entryID := 200
+	out, err := buildTeamSOS(cfg, TeamSOSArgs{LeagueID: 100, EntryID: &entryID})
+	if err != nil {
+		t.Fatalf("buildTeamSOS: %v", err)
+	}
+	if out.FromGW != 3 || out.ThroughGW != 5 || len(out.Teams) != 3 {
+		t.Fatalf("from=%d through=%d teams=%d, want GW3-5 and 3 teams", out.FromGW, out.ThroughGW, len(out.Teams))
+	}
+	// MCI concede to midfielders and LIV to forwards, so ARS (two games v
+	// MCI) have the easiest run and MCI the hardest.
+	order := []string{out.Teams[0].Team, out.Teams[1].Team, out.Teams[2].Team}
+	if !reflect.DeepEqual(order, []string{"ARS", "LIV", "MCI"}) {
+		t.Errorf("order = %v, want ARS LIV MCI", order)
+	}
+	byTeam := map[string]TeamSOS{}
+	for _, row := range out.Teams {
+		byTeam[row.Team] = row
+	}
+	ars, liv := byTeam["ARS"], byTeam["LIV"]
+	if ars.Fixtures != 3 || !reflect.DeepEqual(ars.Doubles, []int{5}) || !approxEqual(ars.Average, 1.5) {
+		t.Errorf("ARS = %+v, want 3 fixtures averaging 1.5 with a GW5 double", ars)
+	}
+	// The blank adds nothing to the average rather than a zero.
+	if liv.Fixtures != 2 || !reflect.DeepEqual(liv.Blanks, []int{4}) || !approxEqual(liv.Average, 1) {
+		t.Errorf("LIV = %+v, want 2 fixtures averaging 1 with a GW4 blank", liv)
+	}
+	if len(liv.Schedule) != 2 || liv.Schedule[0].FixtureID != 5 || liv.Schedule[0].Opponent != "MCI" || liv.Schedule[0].Venue != "HOME" {
+		t.Errorf("LIV schedule = %+v, want GW3 v MCI from live.json first", liv.Schedule)
+	}
+	if !approxEqual(liv.ByPosition["MID"], 4) || !approxEqual(liv.ByPosition["FWD"], 0) {
+		t.Errorf("LIV by position = %v, want MID 4 FWD 0", liv.ByPosition)
+	}
+
+	if out.Roster == nil {
+		t.Fatal("roster missing with entry_id set")
+	}
+	names := func(ps []SOSPlayer) []string {
+		out := []string{}
+		for _, p := range ps {
+			out = append(out, p.Name)
+		}
+		return out
+	}
+	if got := names(out.Roster.Easy); !reflect.DeepEqual(got, []string{"Saka"}) {
+		t.Errorf("easy = %v, want Saka", got)
+	}
+	if got := names(out.Roster.Neutral); !reflect.DeepEqual(got, []string{"Salah"}) {
+		t.Errorf("neutral = %v, want Salah", got)
+	}
+	if got := names(out.Roster.Hard); !reflect.DeepEqual(got, []string{"Foden"}) {
+		t.Errorf("hard = %v, want Foden", got)
+	}
+	if out.Roster.Summary != "Your players on easy runs: Saka; on hard runs: Foden." {
+		t.Errorf("summary = %q", out.Roster.Summary)
+	}
+
+	h := 1
+	short, err := buildTeamSOS(cfg, TeamSOSArgs{LeagueID: 100, Horizon: &h})
+	if err != nil {
+		t.Fatalf("horizon 1: %v", err)
+	}
+	if short.ThroughGW != 3 || short.Roster != nil {
+		t.Errorf("horizon 1 = through %d roster %v, want through 3 and no roster", short.ThroughGW, short.Roster)
+	}
+}
+
+func TestBuildTeamSOS_BadArgs(t *testing.T) {
+	dir, cfg := tmpCfg(t)
+	cfg.DerivedRoot = dir
+	if _, err := buildTeamSOS(cfg, TeamSOSArgs{}); classifyError(err).Code != codeInvalidArgument {
+		t.Errorf("missing league_id: err = %v, want INVALID_ARGUMENT", err)
+	}
+	h := -1
+	if _, err := buildTeamSOS(cfg, TeamSOSArgs{LeagueID: 100, Horizon: &h}); classifyError(err).Code != codeInvalidArgument {
+		t.Errorf("negative horizon: err = %v, want INVALID_ARGUMENT", err)
+	}
+	writeTeamSOSFixture(t, dir)
+	stranger := 999
+	if _, err := buildTeamSOS(cfg, TeamSOSArgs{LeagueID: 100, EntryID: &stranger}); classifyError(err).Code != codeNotFound {
+		t.Errorf("unknown entry: err = %v, want NOT_FOUND", err)
+	}
+}
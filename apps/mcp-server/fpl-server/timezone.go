@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	_ "time/tzdata" // IANA names resolve even where the host has no zoneinfo
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/summary"
+)
+
+// commonTimezones are suggested when a tz argument doesn't resolve.
+var commonTimezones = []string{
+	"UTC",
+	"Europe/London",
+	"Europe/Dublin",
+	"America/New_York",
+	"America/Chicago",
+	"America/Denver",
+	"America/Los_Angeles",
+	"Asia/Kolkata",
+	"Asia/Singapore",
+	"Australia/Sydney",
+}
+
+// localDisplayLayout renders e.g. "Sat 7 Mar, 10:00 AM EST".
+const localDisplayLayout = "Mon 2 Jan, 3:04 PM MST"
+
+// loadTimezone resolves an IANA zone name; empty means UTC. "Local" is
+// rejected since it would mean the server's zone, not the caller's.
+func loadTimezone(name string) (*time.Location, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil || name == "Local" {
+		return nil, &codedError{
+			Code:    codeInvalidArgument,
+			Message: fmt.Sprintf("unknown tz %q: want an IANA time zone name such as America/New_York", name),
+			Details: map[string]any{"valid_examples": commonTimezones},
+		}
+	}
+	return loc, nil
+}
+
+// LocalTime is an API timestamp converted to the caller's time zone.
+type LocalTime struct {
+	Time     string `json:"time"`
+	Display  string `json:"display"`
+	Relative string `json:"relative"`
+}
+
+// localTimeOf converts an API timestamp into loc, with its distance from
+// now. It returns nil when s is empty or unparseable, so the UTC field is
+// left to stand alone.
+func localTimeOf(s string, loc *time.Location, now time.Time) *LocalTime {
+	t, ok := summary.ParseAPITime(s)
+	if !ok {
+		return nil
+	}
+	local := t.In(loc)
+	return &LocalTime{
+		Time:     local.Format(time.RFC3339),
+		Display:  local.Format(localDisplayLayout),
+		Relative: formatRelative(t.Sub(now)),
+	}
+}
+
+// formatRelative renders d as "in 2d 4h" or "3h 10m ago", keeping the two
+// largest units.
+func formatRelative(d time.Duration) string {
+	past := d < 0
+	if past {
+		d = -d
+	}
+	mins := int64(d / time.Minute)
+	if mins == 0 {
+		return "now"
+	}
+	days, hours, mins := mins/(24*60), (mins/60)%24, mins%60
+	var span string
+	switch {
+	case days > 0:
+		span = fmt.Sprintf("%dd %dh", days, hours)
+	case hours > 0:
+		span = fmt.Sprintf("%dh %dm", hours, mins)
+	default:
+		span = fmt.Sprintf("%dm", mins)
+	}
+	if past {
+		return span + " ago"
+	}
+	return "in " + span
+}
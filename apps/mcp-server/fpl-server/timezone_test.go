@@ -0,0 +1,49 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatRelative(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{52*time.Hour + 10*time.Minute, "in 2d 4h"},
+		{3*time.Hour + 5*time.Minute, "in 3h 5m"},
+		{-(40*time.Minute + 30*time.Second), "40m ago"},
+		{-26 * time.Hour, "1d 2h ago"},
+		{20 * time.Second, "now"},
+	}
+	for _, tt := range tests {
+		if got := formatRelative(tt.d); got != tt.want {
+			t.Errorf("formatRelative(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestLocalTimeOf(t *testing.T) {
+	loc, err := loadTimezone("America/Los_Angeles")
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2025, 8, 16, 0, 0, 0, 0, time.UTC)
+	// Summer time, and a kickoff sent without seconds.
+	got := localTimeOf("2025-08-16T11:30Z", loc, now)
+	want := LocalTime{Time: "2025-08-16T04:30:00-07:00", Display: "Sat 16 Aug, 4:30 AM PDT", Relative: "in 11h 30m"}
+	if got == nil || *got != want {
+		t.Errorf("localTimeOf = %+v, want %+v", got, want)
+	}
+	if got := localTimeOf("", loc, now); got != nil {
+		t.Errorf("empty time = %+v, want nil", got)
+	}
+	if loc, err := loadTimezone(" "); err != nil || loc != time.UTC {
+		t.Errorf("blank tz = %v, %v; want UTC", loc, err)
+	}
+	for _, bad := range []string{"US Eastern", "Local", "Mars/Olympus"} {
+		if _, err := loadTimezone(bad); classifyError(err).Code != codeInvalidArgument {
+			t.Errorf("loadTimezone(%q) err = %v, want INVALID_ARGUMENT", bad, err)
+		}
+	}
+}
@@ -0,0 +1,273 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/livestats"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/reconcile"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+)
+
+// TradeHistoryArgs are the input arguments for the trade_history tool.
+type TradeHistoryArgs struct {
+	LeagueID           int   `json:"league_id" jsonschema:"Draft league id (required)"`
+	ThroughGW          int   `json:"through_gw,omitempty" jsonschema:"Grade trades through this gameweek (default: latest finished)"`
+	IncludeUnprocessed *bool `json:"include_unprocessed,omitempty" jsonschema:"Also list offered, rejected, vetoed and withdrawn trades (ungraded)"`
+}
+
+// TradedPlayer is one player a side received, with the points they scored
+// for that side. FromGW-ToGW is the ownership window: it ends when the
+// player is dropped or traded on, and is empty (ToGW < FromGW) for a trade
+// that hasn't reached a finished GW yet.
+type TradedPlayer struct {
+	Element      int    `json:"element"`
+	PlayerName   string `json:"player_name"`
+	Team         string `json:"team"`
+	PositionType int    `json:"position_type"`
+	FromGW       int    `json:"from_gw"`
+	ToGW         int    `json:"to_gw"`
+	Points       int    `json:"points"`
+}
+
+// TradeSide is what one manager got out of a trade.
+type TradeSide struct {
+	EntryID   int            `json:"entry_id"`
+	EntryName string         `json:"entry_name"`
+	Received  []TradedPlayer `json:"received"`
+	Points    int            `json:"points"`
+}
+
+// TradeRecord is one trade with its retrospective grade. Margin is the
+// offering side's points minus the receiving side's.
+type TradeRecord struct {
+	TradeID       int       `json:"trade_id"`
+	Gameweek      int       `json:"gameweek"`
+	State         string    `json:"state"`
+	OfferedBy     TradeSide `json:"offered_by"`
+	OfferedTo     TradeSide `json:"offered_to"`
+	Graded        bool      `json:"graded"`
+	Margin        int       `json:"margin"`
+	WinnerEntryID int       `json:"winner_entry_id,omitempty"`
+	Verdict       string    `json:"verdict"`
+}
+
+// TradeNet is one manager's running total across their processed trades.
+type TradeNet struct {
+	EntryID        int    `json:"entry_id"`
+	EntryName      string `json:"entry_name"`
+	Trades         int    `json:"trades"`
+	PointsReceived int    `json:"points_received"`
+	PointsSent     int    `json:"points_sent"` // scored by players they gave away, for the other side
+	Net            int    `json:"net"`
+}
+
+// TradeHistoryOutput is the output of the trade_history tool.
+type TradeHistoryOutput struct {
+	LeagueID    int           `json:"league_id"`
+	ThroughGW   int           `json:"through_gw"`
+	Trades      []TradeRecord `json:"trades"`
+	Leaderboard []TradeNet    `json:"leaderboard"`
+	GWNote      *GWNote       `json:"gw_note,omitempty"`
+	// PlayerNamesUnavailable is playerNames.Unavailable.
+	PlayerNamesUnavailable bool `json:"player_names_unavailable,omitempty"`
+}
+
+// tradeStateLabels spells out the single-letter trade states in trades.json.
+var tradeStateLabels = map[string]string{
+	"o": "offered",
+	"a": "accepted",
+	"p": "processed",
+	"r": "rejected",
+	"v": "vetoed",
+	"w": "withdrawn",
+	"e": "expired",
+	"i": "invalid",
+}
+
+func tradeStateLabel(state string) string {
+	if label, ok := tradeStateLabels[state]; ok {
+		return label
+	}
+	return state
+}
+
+// tradeSides splits a trade's items by direction: element_out leaves the
+// offering entry, element_in leaves the receiving one.
+func tradeSides(tr reconcile.Trade) (fromOfferer, fromReceiver []int) {
+	for _, item := range tr.TradeItems {
+		if item.ElementOut != 0 {
+			fromOfferer = append(fromOfferer, item.ElementOut)
+		}
+		if item.ElementIn != 0 {
+			fromReceiver = append(fromReceiver, item.ElementIn)
+		}
+	}
+	return fromOfferer, fromReceiver
+}
+
+// tradeWindow returns the GWs in [fromGW, throughGW] that entry owned element
+// from a move taking effect in fromGW. The window is empty when the player
+// changed hands again within fromGW itself.
+func tradeWindow(moves []ownershipMove, element int, entry int, fromGW int, throughGW int) (int, int) {
+	for _, s := range ownerStints(moves, element, fromGW, throughGW) {
+		if s.FromGW == fromGW {
+			if s.EntryID == entry {
+				return s.FromGW, s.ToGW
+			}
+			break
+		}
+	}
+	return fromGW, fromGW - 1
+}
+
+func buildTradeHistory(cfg ServerConfig, args TradeHistoryArgs) (TradeHistoryOutput, error) {
+	if args.LeagueID == 0 {
+		return TradeHistoryOutput{}, invalidArgumentf("league_id is required")
+	}
+	throughGW, note, err := resolveEffectiveGW(cfg, args.ThroughGW, gwModeLatestFinished)
+	if err != nil {
+		return TradeHistoryOutput{}, err
+	}
+	includeUnprocessed := args.IncludeUnprocessed != nil && *args.IncludeUnprocessed
+
+	st := store.NewJSONStore(cfg.RawRoot)
+	transactions, err := loadTransactionsRaw(st, args.LeagueID)
+	if err != nil {
+		return TradeHistoryOutput{}, err
+	}
+	trades, err := loadTradesRaw(st, args.LeagueID)
+	if err != nil {
+		return TradeHistoryOutput{}, err
+	}
+	raw, err := st.ReadRaw(fmt.Sprintf("league/%d/details.json", args.LeagueID))
+	if err != nil {
+		return TradeHistoryOutput{}, err
+	}
+	var details leagueDetailsRaw
+	if err := json.Unmarshal(raw, &details); err != nil {
+		return TradeHistoryOutput{}, err
+	}
+	nameByEntry := make(map[int]string, len(details.LeagueEntries))
+	for _, e := range details.LeagueEntries {
+		nameByEntry[e.EntryID] = e.EntryName
+	}
+	players, err := loadPlayerNames(cfg.RawRoot)
+	if err != nil {
+		return TradeHistoryOutput{}, err
+	}
+
+	pointsByGW := make(map[int]map[int]livestats.ElementStats, throughGW)
+	for gw := 1; gw <= throughGW; gw++ {
+		if live, err := loadLiveStats(cfg.RawRoot, gw); err == nil {
+			pointsByGW[gw] = live
+		}
+	}
+
+	// Trades after throughGW can't change who owned whom through it.
+	processed := make([]reconcile.Trade, 0, len(trades))
+	for _, tr := range trades {
+		if tr.State == "p" && tr.Event <= throughGW {
+			processed = append(processed, tr)
+		}
+	}
+	moves := ownershipMoves(approvedTransactions(transactions, 1, throughGW), processed)
+
+	received := func(tr reconcile.Trade, entry int, elementIDs []int, graded bool) TradeSide {
+		side := TradeSide{EntryID: entry, EntryName: nameByEntry[entry], Received: make([]TradedPlayer, 0, len(elementIDs))}
+		for _, id := range elementIDs {
+			meta, _ := players.get(id)
+			p := TradedPlayer{Element: id, PlayerName: meta.Name, Team: players.team(meta.TeamID), PositionType: meta.PositionType}
+			if graded {
+				p.FromGW, p.ToGW = tradeWindow(moves, id, entry, tr.Event, throughGW)
+				for gw := p.FromGW; gw <= p.ToGW; gw++ {
+					p.Points += pointsByGW[gw][id].TotalPoints
+				}
+				side.Points += p.Points
+			}
+			side.Received = append(side.Received, p)
+		}
+		return side
+	}
+
+	out := TradeHistoryOutput{
+		LeagueID:               args.LeagueID,
+		ThroughGW:              throughGW,
+		Trades:                 make([]TradeRecord, 0),
+		Leaderboard:            make([]TradeNet, 0),
+		GWNote:                 note,
+		PlayerNamesUnavailable: players.Unavailable,
+	}
+	netByEntry := make(map[int]*TradeNet)
+	net := func(entry int) *TradeNet {
+		if n, ok := netByEntry[entry]; ok {
+			return n
+		}
+		n := &TradeNet{EntryID: entry, EntryName: nameByEntry[entry]}
+		netByEntry[entry] = n
+		return n
+	}
+	for _, tr := range trades {
+		if args.ThroughGW != 0 && tr.Event > throughGW {
+			continue
+		}
+		graded := tr.State == "p"
+		if !graded && !includeUnprocessed {
+			continue
+		}
+		toReceiver, toOfferer := tradeSides(tr)
+		rec := TradeRecord{
+			TradeID:   tr.ID,
+			Gameweek:  tr.Event,
+			State:     tradeStateLabel(tr.State),
+			OfferedBy: received(tr, tr.OfferedEntry, toOfferer, graded),
+			OfferedTo: received(tr, tr.ReceivedEntry, toReceiver, graded),
+			Graded:    graded,
+		}
+		switch {
+		case !graded:
+			rec.Verdict = "not processed"
+		case tr.Event > throughGW:
+			rec.Verdict = "too early to call"
+		default:
+			rec.Margin = rec.OfferedBy.Points - rec.OfferedTo.Points
+			switch {
+			case rec.Margin > 0:
+				rec.WinnerEntryID = rec.OfferedBy.EntryID
+				rec.Verdict = fmt.Sprintf("%s winning by %d", rec.OfferedBy.EntryName, rec.Margin)
+			case rec.Margin < 0:
+				rec.WinnerEntryID = rec.OfferedTo.EntryID
+				rec.Verdict = fmt.Sprintf("%s winning by %d", rec.OfferedTo.EntryName, -rec.Margin)
+			default:
+				rec.Verdict = "even"
+			}
+		}
+		if graded {
+			for _, pair := range [][2]TradeSide{{rec.OfferedBy, rec.OfferedTo}, {rec.OfferedTo, rec.OfferedBy}} {
+				n := net(pair[0].EntryID)
+				n.Trades++
+				n.PointsReceived += pair[0].Points
+				n.PointsSent += pair[1].Points
+				n.Net = n.PointsReceived - n.PointsSent
+			}
+		}
+		out.Trades = append(out.Trades, rec)
+	}
+	sort.SliceStable(out.Trades, func(i, j int) bool {
+		if out.Trades[i].Gameweek != out.Trades[j].Gameweek {
+			return out.Trades[i].Gameweek < out.Trades[j].Gameweek
+		}
+		return out.Trades[i].TradeID < out.Trades[j].TradeID
+	})
+	for _, n := range netByEntry {
+		out.Leaderboard = append(out.Leaderboard, *n)
+	}
+	sort.Slice(out.Leaderboard, func(i, j int) bool {
+		if out.Leaderboard[i].Net != out.Leaderboard[j].Net {
+			return out.Leaderboard[i].Net > out.Leaderboard[j].Net
+		}
+		return out.Leaderboard[i].EntryID < out.Leaderboard[j].EntryID
+	})
+	return out, nil
+}
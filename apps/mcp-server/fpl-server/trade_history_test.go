@@ -0,0 +1,107 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func writeTradeHistoryFixture(t *testing.T, dir string) {
+	t.Helper()
+	writeBootstrap(t, dir)
+	writeFullGameJSON(t, dir, 4, true, 5, false, "")
+	writeLeagueDetailsFixture(t, dir, 100, []any{
+		map[string]any{"id": 1, "entry_id": 200, "entry_name": "Alpha FC"},
+		map[string]any{"id": 2, "entry_id": 201, "entry_name": "Beta FC"},
+	}, []any{})
+	for gw := 1; gw <= 4; gw++ {
+		writeLiveJSON(t, dir, gw, map[string]any{
+			"1": map[string]any{"stats": map[string]any{"total_points": 10}},
+			"2": map[string]any{"stats": map[string]any{"total_points": 5}},
+			"3": map[string]any{"stats": map[string]any{"total_points": 2}},
+		})
+	}
+	// Alpha sends Salah (1) to Beta for Haaland (2) from GW2; Beta drops
+	// Salah for Alexander-Arnold (3) from GW4.
+	writeJSON(t, filepath.Join(dir, "league/100/trades.json"), map[string]any{
+		"trades": []any{
+			map[string]any{"id": 2, "event": 3, "offered_entry": 201, "received_entry": 200, "state": "r",
+				"tradeitem_set": []any{map[string]any{"element_out": 3, "element_in": 2}}},
+			map[string]any{"id": 1, "event": 2, "offered_entry": 200, "received_entry": 201, "state": "p",
+				"tradeitem_set": []any{map[string]any{"element_out": 1, "element_in": 2}}},
+		},
+	})
+	writeJSON(t, filepath.Join(dir, "league/100/transactions.json"), map[string]any{
+		"transactions": []any{
+			map[string]any{"id": 9, "entry": 201, "event": 4, "element_in": 3, "element_out": 1, "kind": "w", "result": "a"},
+		},
+	})
+}
+
+func TestBuildTradeHistory(t *testing.T) {
+	dir, cfg := tmpCfg(t)
+	writeTradeHistoryFixture(t, dir)
+
+	out, err := buildTradeHistory(cfg, TradeHistoryArgs{LeagueID: 100})
+	if err != nil {
+		t.Fatalf("buildTradeHistory: %v", err)
+	}
+	if out.ThroughGW != 4 || len(out.Trades) != 1 {
+		t.Fatalf("through_gw=%d trades=%+v, want GW4 and only the processed trade", out.ThroughGW, out.Trades)
+	}
+	tr := out.Trades[0]
+	alpha, beta := tr.OfferedBy, tr.OfferedTo
+	if len(alpha.Received) != 1 || alpha.Received[0].Element != 2 || alpha.Received[0].FromGW != 2 || alpha.Received[0].ToGW != 4 || alpha.Points != 15 {
+		t.Errorf("Alpha side = %+v, want Haaland GW2-4 for 15", alpha)
+	}
+	// Salah stops counting for Beta once dropped.
+	if len(beta.Received) != 1 || beta.Received[0].ToGW != 3 || beta.Points != 20 {
+		t.Errorf("Beta side = %+v, want Salah GW2-3 for 20", beta)
+	}
+	if tr.Margin != -5 || tr.WinnerEntryID != 201 || tr.Verdict != "Beta FC winning by 5" {
+		t.Errorf("grade = margin %d winner %d %q", tr.Margin, tr.WinnerEntryID, tr.Verdict)
+	}
+	if len(out.Leaderboard) != 2 || out.Leaderboard[0].EntryID != 201 || out.Leaderboard[0].Net != 5 || out.Leaderboard[1].Net != -5 {
+		t.Errorf("leaderboard = %+v, want Beta +5 then Alpha -5", out.Leaderboard)
+	}
+
+	t.Run("ThroughGW", func(t *testing.T) {
+		out, err := buildTradeHistory(cfg, TradeHistoryArgs{LeagueID: 100, ThroughGW: 2})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := out.Trades[0]; got.OfferedBy.Points != 5 || got.OfferedTo.Points != 10 {
+			t.Errorf("through GW2 points = %d/%d, want 5/10", got.OfferedBy.Points, got.OfferedTo.Points)
+		}
+	})
+
+	t.Run("IncludeUnprocessed", func(t *testing.T) {
+		yes := true
+		out, err := buildTradeHistory(cfg, TradeHistoryArgs{LeagueID: 100, IncludeUnprocessed: &yes})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(out.Trades) != 2 {
+			t.Fatalf("trades = %+v, want both", out.Trades)
+		}
+		rejected := out.Trades[1]
+		if rejected.State != "rejected" || rejected.Graded || rejected.OfferedBy.Points != 0 || rejected.Verdict != "not processed" {
+			t.Errorf("rejected trade = %+v", rejected)
+		}
+		if len(out.Leaderboard) != 2 || out.Leaderboard[0].Trades != 1 {
+			t.Errorf("leaderboard = %+v, want the rejected trade left out", out.Leaderboard)
+		}
+	})
+}
+
+func TestTradeWindow_RetradedSameGW(t *testing.T) {
+	moves := []ownershipMove{
+		{Event: 3, ID: 1, Element: 10, From: 200, To: 201},
+		{Event: 3, ID: 2, Element: 10, From: 201, To: 202},
+	}
+	if from, to := tradeWindow(moves, 10, 201, 3, 5); to >= from {
+		t.Errorf("window = %d-%d, want empty for a player passed straight on", from, to)
+	}
+	if from, to := tradeWindow(moves, 10, 202, 3, 5); from != 3 || to != 5 {
+		t.Errorf("window = %d-%d, want 3-5", from, to)
+	}
+}
@@ -0,0 +1,552 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/projection"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/reconcile"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/summary"
+)
+
+// Fairness thresholds. The score starts as the smaller side's share of the
+// projected points changing hands (100 = dead even) and loses up to
+// tradeStandingsPenaltyMax when the trade lifts a contender at a bottom
+// team's expense.
+const (
+	tradeFairReasonable       = 80
+	tradeFairLopsided         = 60
+	tradeStandingsSwing       = 1.0 // expected match points a contender must gain to count
+	tradeStandingsPenaltyPer  = 10.0
+	tradeStandingsPenaltyMax  = 25.0
+	tradeReviewDefaultWindow  = 5
+	tradeReviewMatchWinPoints = 3
+)
+
+// TradeReviewArgs are the input arguments for the trade_review tool. A
+// trade is either trade_id from trades.json or entry_id, partner_entry_id,
+// give and receive.
+type TradeReviewArgs struct {
+	LeagueID   int    `json:"league_id" jsonschema:"Draft league id (required)"`
+	TradeID    *int   `json:"trade_id,omitempty" jsonschema:"Pending (offered or accepted) trade id from trades.json"`
+	EntryID    *int   `json:"entry_id,omitempty" jsonschema:"Entry sending give and getting receive (without trade_id)"`
+	PartnerID  *int   `json:"partner_entry_id,omitempty" jsonschema:"Entry on the other side (without trade_id)"`
+	Give       []int  `json:"give,omitempty" jsonschema:"Element ids entry_id sends to partner_entry_id"`
+	Receive    []int  `json:"receive,omitempty" jsonschema:"Element ids partner_entry_id sends to entry_id"`
+	FormWindow *int   `json:"form_window,omitempty" jsonschema:"GWs of form used for the projections (default 5)"`
+	Model      string `json:"model,omitempty" jsonschema:"Projection model: heuristic|poisson (default heuristic)"`
+}
+
+// TradeReviewPlayer is one player changing hands, with their
+// rest-of-season projection.
+type TradeReviewPlayer struct {
+	Element      int     `json:"element"`
+	Name         string  `json:"name"`
+	Team         string  `json:"team"`
+	PositionType int     `json:"position_type"`
+	Projected    float64 `json:"projected_points"`
+}
+
+// PositionImpact is a side's best-XI rest-of-season points from one
+// position before and after the trade, against the league average before
+// it.
+type PositionImpact struct {
+	Position       string  `json:"position"`
+	Before         float64 `json:"before"`
+	After          float64 `json:"after"`
+	LeagueAverage  float64 `json:"league_average"`
+	BeforeVsLeague float64 `json:"before_vs_league"`
+	AfterVsLeague  float64 `json:"after_vs_league"`
+}
+
+// TradeStandingsImpact projects a side's final table position from its
+// current match points plus the expected match points of each remaining
+// match, with and without the trade.
+type TradeStandingsImpact struct {
+	MatchPoints         int     `json:"match_points"`
+	RemainingMatches    int     `json:"remaining_matches"`
+	ExpectedFinalBefore float64 `json:"expected_final_match_points_before"`
+	ExpectedFinalAfter  float64 `json:"expected_final_match_points_after"`
+	Change              float64 `json:"change"`
+	ProjectedRankBefore int     `json:"projected_rank_before"`
+	ProjectedRankAfter  int     `json:"projected_rank_after"`
+	// Tier is contender (top half before the trade), bottom (bottom half)
+	// or middle.
+	Tier string `json:"tier"`
+}
+
+// TradeReviewSide is the trade from one manager's point of view.
+type TradeReviewSide struct {
+	EntryID           int                  `json:"entry_id"`
+	EntryName         string               `json:"entry_name"`
+	Sends             []TradeReviewPlayer  `json:"sends"`
+	Receives          []TradeReviewPlayer  `json:"receives"`
+	ProjectedSent     float64              `json:"projected_sent"`
+	ProjectedReceived float64              `json:"projected_received"`
+	ProjectedNet      float64              `json:"projected_net"`
+	Positions         []PositionImpact     `json:"positions"`
+	Standings         TradeStandingsImpact `json:"standings"`
+}
+
+// TradeReviewOutput is the output of the trade_review tool.
+type TradeReviewOutput struct {
+	LeagueID  int               `json:"league_id"`
+	TradeID   int               `json:"trade_id,omitempty"`
+	State     string            `json:"state,omitempty"`
+	AsOfGW    int               `json:"as_of_gw"`
+	FromGW    int               `json:"from_gw"`
+	ThroughGW int               `json:"through_gw"`
+	Model     string            `json:"model"`
+	Sides     []TradeReviewSide `json:"sides"`
+	Fairness  int               `json:"fairness_score"`
+	Verdict   string            `json:"verdict"` // reasonable|lopsided|review recommended
+	Reasons   []string          `json:"reasons"`
+	Notes     []string          `json:"notes"`
+}
+
+// xiPlayer is one player's projection for best-XI selection.
+type xiPlayer struct {
+	position int
+	mean     float64
+	variance float64
+}
+
+// bestProjectedXI picks the legal XI (one GK, 3-5 DEF, 2-5 MID, 1-3 FWD)
+// with the highest projected total and returns its points by position, its
+// total and its variance. A short squad fields whoever it has.
+func bestProjectedXI(players []xiPlayer) (byPos [5]float64, total float64, variance float64) {
+	lists := make(map[int][]xiPlayer, 4)
+	for _, p := range players {
+		lists[p.position] = append(lists[p.position], p)
+	}
+	for pos := range lists {
+		sort.Slice(lists[pos], func(i, j int) bool { return lists[pos][i].mean > lists[pos][j].mean })
+	}
+	take := func(pos int, n int) (float64, float64) {
+		sum, v := 0.0, 0.0
+		for i := 0; i < n && i < len(lists[pos]); i++ {
+			sum += lists[pos][i].mean
+			v += lists[pos][i].variance
+		}
+		return sum, v
+	}
+	best := math.Inf(-1)
+	for d := 3; d <= 5; d++ {
+		for m := 2; m <= 5; m++ {
+			f := 10 - d - m
+			if f < 1 || f > 3 {
+				continue
+			}
+			var pts, vars [5]float64
+			sum, v := 0.0, 0.0
+			for pos, n := range map[int]int{1: 1, 2: d, 3: m, 4: f} {
+				pts[pos], vars[pos] = take(pos, n)
+				sum += pts[pos]
+				v += vars[pos]
+			}
+			if sum > best {
+				best, byPos, total, variance = sum, pts, sum, v
+			}
+		}
+	}
+	return byPos, total, variance
+}
+
+// winProbability is the chance a side projecting meanA with varianceA
+// outscores one projecting meanB with varianceB, treating the margin as
+// normal. With no variance either way the higher projection wins outright.
+func winProbability(meanA, varianceA, meanB, varianceB float64) float64 {
+	diff := meanA - meanB
+	sd := math.Sqrt(varianceA + varianceB)
+	if sd == 0 {
+		switch {
+		case diff > 0:
+			return 1
+		case diff < 0:
+			return 0
+		}
+		return 0.5
+	}
+	return 0.5 * (1 + math.Erf(diff/(sd*math.Sqrt2)))
+}
+
+// pendingTrade resolves the trade under review to the two entries and what
+// each sends.
+func pendingTrade(st *store.JSONStore, args TradeReviewArgs) (tr reconcile.Trade, sendA []int, sendB []int, err error) {
+	if args.TradeID != nil {
+		if args.EntryID != nil || args.PartnerID != nil || len(args.Give) > 0 || len(args.Receive) > 0 {
+			return tr, nil, nil, invalidArgumentf("pass either trade_id or entry_id, partner_entry_id, give and receive, not both")
+		}
+		trades, err := loadTradesRaw(st, args.LeagueID)
+		if err != nil {
+			return tr, nil, nil, err
+		}
+		for _, t := range trades {
+			if t.ID != *args.TradeID {
+				continue
+			}
+			if t.State != "o" && t.State != "a" {
+				return tr, nil, nil, invalidArgumentf("trade %d is %s; only offered or accepted trades can be reviewed (see trade_history for processed ones)", t.ID, tradeStateLabel(t.State))
+			}
+			sendA, sendB = tradeSides(t)
+			return t, sendA, sendB, nil
+		}
+		return tr, nil, nil, notFoundf("trade %d not found in league %d", *args.TradeID, args.LeagueID)
+	}
+	if args.EntryID == nil || args.PartnerID == nil {
+		return tr, nil, nil, invalidArgumentf("trade_id, or entry_id and partner_entry_id, is required")
+	}
+	if *args.EntryID == *args.PartnerID {
+		return tr, nil, nil, invalidArgumentf("entry_id and partner_entry_id must differ")
+	}
+	if len(args.Give) == 0 && len(args.Receive) == 0 {
+		return tr, nil, nil, invalidArgumentf("give or receive must list at least one element")
+	}
+	return reconcile.Trade{OfferedEntry: *args.EntryID, ReceivedEntry: *args.PartnerID}, args.Give, args.Receive, nil
+}
+
+func buildTradeReview(cfg ServerConfig, args TradeReviewArgs) (TradeReviewOutput, error) {
+	if args.LeagueID == 0 {
+		return TradeReviewOutput{}, invalidArgumentf("league_id is required")
+	}
+	window := tradeReviewDefaultWindow
+	if args.FormWindow != nil && *args.FormWindow > 0 {
+		window = *args.FormWindow
+	}
+	modelName, err := parseProjectionModel(args.Model)
+	if err != nil {
+		return TradeReviewOutput{}, err
+	}
+	st := store.NewJSONStore(cfg.RawRoot)
+	tr, sendA, sendB, err := pendingTrade(st, args)
+	if err != nil {
+		return TradeReviewOutput{}, err
+	}
+
+	asOfGW, nextGW, err := resolveAsOfAndNextGW(cfg, 0, 0)
+	if err != nil {
+		return TradeReviewOutput{}, err
+	}
+	ownership, err := loadOwnershipAtGW(cfg, args.LeagueID, resolveRosterGW(asOfGW, nextGW))
+	if err != nil {
+		return TradeReviewOutput{}, err
+	}
+	entryA, entryB := tr.OfferedEntry, tr.ReceivedEntry
+	for _, side := range []struct {
+		entry int
+		sends []int
+	}{{entryA, sendA}, {entryB, sendB}} {
+		roster, ok := ownership[side.entry]
+		if !ok {
+			return TradeReviewOutput{}, notFoundf("entry %d not found in league %d", side.entry, args.LeagueID)
+		}
+		for _, id := range side.sends {
+			if !roster[id] {
+				return TradeReviewOutput{}, invalidArgumentf("element %d is not on entry %d's roster", id, side.entry)
+			}
+		}
+	}
+	ld, _, err := loadLeagueDetails(st, args.LeagueID)
+	if err != nil {
+		return TradeReviewOutput{}, err
+	}
+
+	elements, teamShort, fixturesByGW, err := loadBootstrapData(cfg.RawRoot)
+	if err != nil {
+		return TradeReviewOutput{}, err
+	}
+	throughGW := 0
+	for gw := range fixturesByGW {
+		throughGW = max(throughGW, gw)
+	}
+	// As in roster_outlook, a GW with no fixtures listed is unknown rather
+	// than a league-wide blank.
+	gws := make([]int, 0)
+	indexByGW := make(map[int]map[int][]FixtureContext)
+	for gw := nextGW; gw <= throughGW; gw++ {
+		if len(fixturesByGW[gw]) == 0 {
+			continue
+		}
+		gws = append(gws, gw)
+		indexByGW[gw] = buildFixtureIndex(fixturesByGW[gw], teamShort)
+	}
+	rules, err := loadScoringRules(cfg, args.LeagueID)
+	if err != nil {
+		return TradeReviewOutput{}, err
+	}
+	mult := fixtureMultiplierFunc(cfg.RawRoot, elements, teamShort, asOfGW, window)
+	model, _, err := loadProjectionModel(cfg.RawRoot, modelName, elements, indexByGW, mult, asOfGW, window, rules.Rules)
+	if err != nil {
+		return TradeReviewOutput{}, err
+	}
+	elementByID := make(map[int]elementInfo, len(elements))
+	for _, e := range elements {
+		elementByID[e.ID] = e
+	}
+
+	// perGW[id][gw] is a player's projection for one remaining GW; ros is
+	// the rest-of-season total.
+	perGW := make(map[int]map[int]projection.Projection)
+	ros := make(map[int]float64)
+	project := func(id int) {
+		if _, ok := perGW[id]; ok {
+			return
+		}
+		perGW[id] = make(map[int]projection.Projection, len(gws))
+		if _, ok := elementByID[id]; !ok {
+			return
+		}
+		for _, gw := range gws {
+			p := model.ProjectPlayer(id, gw)
+			perGW[id][gw] = p
+			ros[id] += p.ExpectedPoints
+		}
+	}
+	for _, roster := range ownership {
+		for id := range roster {
+			project(id)
+		}
+	}
+
+	after := make(map[int]map[int]bool, len(ownership))
+	for entry, roster := range ownership {
+		after[entry] = roster
+	}
+	swap := func(from map[int]bool, out []int, in []int) map[int]bool {
+		next := make(map[int]bool, len(from))
+		for id := range from {
+			next[id] = true
+		}
+		for _, id := range out {
+			delete(next, id)
+		}
+		for _, id := range in {
+			next[id] = true
+		}
+		return next
+	}
+	after[entryA] = swap(ownership[entryA], sendA, sendB)
+	after[entryB] = swap(ownership[entryB], sendB, sendA)
+
+	xi := func(roster map[int]bool, gw int) ([5]float64, float64, float64) {
+		players := make([]xiPlayer, 0, len(roster))
+		for id := range roster {
+			info, ok := elementByID[id]
+			if !ok {
+				continue
+			}
+			p := xiPlayer{position: info.PositionType, mean: ros[id]}
+			if gw > 0 {
+				p.mean, p.variance = perGW[id][gw].ExpectedPoints, perGW[id][gw].Variance
+			}
+			players = append(players, p)
+		}
+		return bestProjectedXI(players)
+	}
+
+	// Positional strength: the best rest-of-season XI's points by position.
+	var leagueAvg [5]float64
+	for _, roster := range ownership {
+		byPos, _, _ := xi(roster, 0)
+		for pos := 1; pos <= 4; pos++ {
+			leagueAvg[pos] += byPos[pos] / float64(len(ownership))
+		}
+	}
+	positions := func(entry int) []PositionImpact {
+		before, _, _ := xi(ownership[entry], 0)
+		next, _, _ := xi(after[entry], 0)
+		out := make([]PositionImpact, 0, 4)
+		for pos := 1; pos <= 4; pos++ {
+			out = append(out, PositionImpact{
+				Position:       positionLabel(pos),
+				Before:         round3(before[pos]),
+				After:          round3(next[pos]),
+				LeagueAverage:  round3(leagueAvg[pos]),
+				BeforeVsLeague: round3(before[pos] - leagueAvg[pos]),
+				AfterVsLeague:  round3(next[pos] - leagueAvg[pos]),
+			})
+		}
+		return out
+	}
+
+	standings := projectTradeStandings(ld, gws, func(entry int, gw int, traded bool) (float64, float64) {
+		rosters := ownership
+		if traded {
+			rosters = after
+		}
+		_, mean, variance := xi(rosters[entry], gw)
+		return mean, variance
+	})
+
+	nameByEntry := make(map[int]string, len(ld.LeagueEntries))
+	for _, e := range ld.LeagueEntries {
+		nameByEntry[e.EntryID] = e.EntryName
+	}
+	players := func(ids []int) ([]TradeReviewPlayer, float64) {
+		out := make([]TradeReviewPlayer, 0, len(ids))
+		total := 0.0
+		for _, id := range ids {
+			info := elementByID[id]
+			out = append(out, TradeReviewPlayer{Element: id, Name: info.Name, Team: teamShort[info.TeamID], PositionType: info.PositionType, Projected: round3(ros[id])})
+			total += ros[id]
+		}
+		return out, total
+	}
+	side := func(entry int, sends []int, receives []int) TradeReviewSide {
+		s := TradeReviewSide{EntryID: entry, EntryName: nameByEntry[entry], Positions: positions(entry), Standings: standings[entry]}
+		var sent, received float64
+		s.Sends, sent = players(sends)
+		s.Receives, received = players(receives)
+		s.ProjectedSent, s.ProjectedReceived, s.ProjectedNet = round3(sent), round3(received), round3(received-sent)
+		return s
+	}
+
+	out := TradeReviewOutput{
+		LeagueID:  args.LeagueID,
+		TradeID:   tr.ID,
+		AsOfGW:    asOfGW,
+		FromGW:    nextGW,
+		ThroughGW: throughGW,
+		Model:     modelName,
+		Sides:     []TradeReviewSide{side(entryA, sendA, sendB), side(entryB, sendB, sendA)},
+		Reasons:   make([]string, 0),
+		Notes: []string{
+			projectionNote(modelName),
+			"Positional strength is the best legal rest-of-season XI's points from each position; the league average is over every roster before the trade.",
+			fmt.Sprintf("Standings: each remaining match is priced from both sides' best projected XI that GW, treating the margin as normal, at %d match points a win. Expected final match points rank the table with and without the trade.", tradeReviewMatchWinPoints),
+			fmt.Sprintf("Fairness starts at 100 x the smaller side's share of the projected points changing hands and loses %.0f per expected match point a contender gains from a bottom-half team, up to %.0f. %d+ is reasonable, %d+ lopsided, below that review recommended.", tradeStandingsPenaltyPer, tradeStandingsPenaltyMax, tradeFairReasonable, tradeFairLopsided),
+		},
+	}
+	if tr.ID != 0 {
+		out.State = tradeStateLabel(tr.State)
+	}
+	out.Fairness, out.Verdict, out.Reasons = tradeFairness(out.Sides[0], out.Sides[1])
+	return out, nil
+}
+
+// projectTradeStandings prices every remaining match from score(entry, gw,
+// traded), a side's projected mean and variance that GW, once without the
+// trade and once with it, and ranks the expected final table both ways.
+// Matches in GWs outside gws (no fixtures listed yet) aren't priced.
+func projectTradeStandings(ld summary.LeagueDetails, gws []int, score func(entry int, gw int, traded bool) (float64, float64)) map[int]TradeStandingsImpact {
+	priced := make(map[int]bool, len(gws))
+	for _, gw := range gws {
+		priced[gw] = true
+	}
+	entryByLeagueEntry := make(map[int]int, len(ld.LeagueEntries))
+	out := make(map[int]TradeStandingsImpact, len(ld.LeagueEntries))
+	for _, e := range ld.LeagueEntries {
+		entryByLeagueEntry[e.ID] = e.EntryID
+		out[e.EntryID] = TradeStandingsImpact{}
+	}
+	expBefore := make(map[int]float64, len(out))
+	expAfter := make(map[int]float64, len(out))
+	for _, m := range ld.Matches {
+		a, b := entryByLeagueEntry[m.LeagueEntry1], entryByLeagueEntry[m.LeagueEntry2]
+		if a == 0 || b == 0 {
+			continue
+		}
+		ra, rb := out[a], out[b]
+		switch {
+		case m.Finished:
+			switch {
+			case m.LeagueEntry1Points > m.LeagueEntry2Points:
+				ra.MatchPoints += tradeReviewMatchWinPoints
+			case m.LeagueEntry1Points < m.LeagueEntry2Points:
+				rb.MatchPoints += tradeReviewMatchWinPoints
+			default:
+				ra.MatchPoints++
+				rb.MatchPoints++
+			}
+		case priced[m.Event]:
+			ra.RemainingMatches++
+			rb.RemainingMatches++
+			for _, traded := range []bool{false, true} {
+				ma, va := score(a, m.Event, traded)
+				mb, vb := score(b, m.Event, traded)
+				p := winProbability(ma, va, mb, vb)
+				exp := expBefore
+				if traded {
+					exp = expAfter
+				}
+				exp[a] += tradeReviewMatchWinPoints * p
+				exp[b] += tradeReviewMatchWinPoints * (1 - p)
+			}
+		}
+		out[a], out[b] = ra, rb
+	}
+	rank := func(final map[int]float64, entry int) int {
+		r := 1
+		for other, v := range final {
+			if other != entry && v > final[entry] {
+				r++
+			}
+		}
+		return r
+	}
+	finalBefore := make(map[int]float64, len(out))
+	finalAfter := make(map[int]float64, len(out))
+	for entry, r := range out {
+		finalBefore[entry] = float64(r.MatchPoints) + expBefore[entry]
+		finalAfter[entry] = float64(r.MatchPoints) + expAfter[entry]
+	}
+	n := len(out)
+	for entry, r := range out {
+		r.ExpectedFinalBefore = round3(finalBefore[entry])
+		r.ExpectedFinalAfter = round3(finalAfter[entry])
+		r.Change = round3(finalAfter[entry] - finalBefore[entry])
+		r.ProjectedRankBefore = rank(finalBefore, entry)
+		r.ProjectedRankAfter = rank(finalAfter, entry)
+		switch {
+		case r.ProjectedRankBefore <= n/2:
+			r.Tier = "contender"
+		case r.ProjectedRankBefore > n-n/2:
+			r.Tier = "bottom"
+		default:
+			r.Tier = "middle"
+		}
+		out[entry] = r
+	}
+	return out
+}
+
+// tradeFairness scores a trade from both sides and explains the score.
+func tradeFairness(a, b TradeReviewSide) (int, string, []string) {
+	reasons := make([]string, 0, 3)
+	share := 1.0
+	if hi := max(a.ProjectedReceived, b.ProjectedReceived); hi > 0 {
+		share = min(a.ProjectedReceived, b.ProjectedReceived) / hi
+	}
+	score := 100 * share
+	winner, loser := a, b
+	if b.ProjectedNet > a.ProjectedNet {
+		winner, loser = b, a
+	}
+	if winner.ProjectedNet > 0 {
+		reasons = append(reasons, fmt.Sprintf("%s gets %.1f projected points for %.1f (%+.1f); %s's side is worth %.0f%% of it.",
+			winner.EntryName, winner.ProjectedReceived, winner.ProjectedSent, winner.ProjectedNet, loser.EntryName, share*100))
+	} else {
+		reasons = append(reasons, "Both sides send and receive the same projected points.")
+	}
+	for _, pair := range [][2]TradeReviewSide{{a, b}, {b, a}} {
+		up, down := pair[0].Standings, pair[1].Standings
+		if up.Tier != "contender" || down.Tier != "bottom" || up.Change < tradeStandingsSwing || down.Change >= 0 {
+			continue
+		}
+		penalty := min(tradeStandingsPenaltyPer*up.Change, tradeStandingsPenaltyMax)
+		score -= penalty
+		reasons = append(reasons, fmt.Sprintf("Contender %s gains %.1f expected match points (projected rank %d -> %d) while bottom-half %s loses %.1f.",
+			pair[0].EntryName, up.Change, up.ProjectedRankBefore, up.ProjectedRankAfter, pair[1].EntryName, -down.Change))
+	}
+	fairness := int(math.Round(max(score, 0)))
+	verdict := "review recommended"
+	switch {
+	case fairness >= tradeFairReasonable:
+		verdict = "reasonable"
+	case fairness >= tradeFairLopsided:
+		verdict = "lopsided"
+	}
+	return fairness, verdict, reasons
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"math"
+	"path/filepath"
+	"testing"
+)
+
+func TestBestProjectedXI(t *testing.T) {
+	players := []xiPlayer{{position: 1, mean: 4, variance: 1}, {position: 1, mean: 9}}
+	for i := 0; i < 6; i++ {
+		players = append(players, xiPlayer{position: 2, mean: float64(1 + i)}, xiPlayer{position: 3, mean: float64(2 + i), variance: 1})
+	}
+	players = append(players, xiPlayer{position: 4, mean: 10, variance: 2}, xiPlayer{position: 4, mean: 0.5})
+
+	// One GK (9), DEF 6+5+4+3, MID 7+6+5+4+3, FWD 10: 4-5-1 beats a fifth
+	// defender (2 for a 3-point MID) or a second forward (0.5).
+	byPos, total, variance := bestProjectedXI(players)
+	if byPos[1] != 9 || byPos[2] != 18 || byPos[3] != 25 || byPos[4] != 10 || total != 62 {
+		t.Errorf("XI = %v total %v, want GK 9 DEF 18 MID 25 FWD 10 = 62", byPos, total)
+	}
+	if variance != 7 {
+		t.Errorf("variance = %v, want the 5 MIDs' 5 plus the forward's 2", variance)
+	}
+
+	byPos, total, _ = bestProjectedXI([]xiPlayer{{position: 3, mean: 5}, {position: 4, mean: 2}})
+	if byPos[3] != 5 || byPos[4] != 2 || total != 7 {
+		t.Errorf("short squad XI = %v total %v, want whoever it has", byPos, total)
+	}
+}
+
+func TestWinProbability(t *testing.T) {
+	if p := winProbability(50, 100, 50, 100); p != 0.5 {
+		t.Errorf("even match = %v, want 0.5", p)
+	}
+	// A 10-point edge against a margin sd of 10 is one sd: ~0.841.
+	if p := winProbability(60, 50, 50, 50); math.Abs(p-0.8413) > 1e-4 {
+		t.Errorf("one-sd favourite = %.4f, want 0.8413", p)
+	}
+	if winProbability(3, 0, 2, 0) != 1 || winProbability(2, 0, 3, 0) != 0 || winProbability(2, 0, 2, 0) != 0.5 {
+		t.Error("zero variance should settle the match on the projections")
+	}
+}
+
+func TestTradeFairness(t *testing.T) {
+	side := func(name string, sent, received float64, tier string, change float64) TradeReviewSide {
+		return TradeReviewSide{EntryName: name, ProjectedSent: sent, ProjectedReceived: received, ProjectedNet: received - sent,
+			Standings: TradeStandingsImpact{Tier: tier, Change: change}}
+	}
+	if score, verdict, _ := tradeFairness(side("A", 40, 36, "middle", 0.5), side("B", 36, 40, "middle", -0.5)); score != 90 || verdict != "reasonable" {
+		t.Errorf("near-even swap = %d %s, want 90 reasonable", score, verdict)
+	}
+	if score, verdict, _ := tradeFairness(side("A", 30, 45, "middle", 0), side("B", 45, 30, "middle", 0)); score != 67 || verdict != "lopsided" {
+		t.Errorf("2:3 swap = %d %s, want 67 lopsided", score, verdict)
+	}
+	// The same 9:10 values, but a contender gains 1.5 expected match points
+	// off a bottom team: 90 - 15.
+	score, verdict, reasons := tradeFairness(side("A", 36, 40, "contender", 1.5), side("B", 40, 36, "bottom", -1.5))
+	if score != 75 || verdict != "lopsided" || len(reasons) != 2 {
+		t.Errorf("contender over bottom = %d %s %v, want 75 lopsided with a standings reason", score, verdict, reasons)
+	}
+}
+
+// writeTradeReviewFixture: three all-MID rosters on one PL team, each player
+// scoring the same every GW so projections carry no variance. Alpha (1, 2, 7)
+// fields 13 a GW, Beta (4, 10) 14 and Gamma (11) 5. Alpha and Gamma have 4
+// match points, Beta none; GW4 is Alpha v Beta, GW5 Alpha v Gamma.
+func writeTradeReviewFixture(t *testing.T, dir string) {
+	t.Helper()
+	points := map[int]int{1: 6, 2: 6, 7: 1, 4: 12, 10: 2, 11: 5}
+	elements := []any{}
+	for id, name := range map[int]string{1: "Salah", 2: "Palmer", 7: "Watkins", 4: "Saka", 10: "Mbeumo", 11: "Kudus"} {
+		elements = append(elements, map[string]any{"id": id, "web_name": name, "team": 10, "element_type": 3, "status": "a"})
+	}
+	writeJSON(t, filepath.Join(dir, "bootstrap", "bootstrap-static.json"), map[string]any{
+		"elements": elements,
+		"teams":    []any{map[string]any{"id": 10, "short_name": "LIV"}, map[string]any{"id": 11, "short_name": "MCI"}},
+		"fixtures": map[string]any{
+			"4": []any{map[string]any{"id": 40, "team_h": 10, "team_a": 11}},
+			"5": []any{map[string]any{"id": 50, "team_h": 11, "team_a": 10}},
+		},
+	})
+	writeFullGameJSON(t, dir, 3, true, 4, false, "")
+	for gw := 1; gw <= 3; gw++ {
+		live := map[string]any{}
+		for id, pts := range points {
+			live[itoa(id)] = map[string]any{"stats": map[string]any{"total_points": pts, "minutes": 90}}
+		}
+		writeJSON(t, filepath.Join(dir, "gw", itoa(gw), "live.json"), map[string]any{
+			"elements": live,
+			"fixtures": []any{map[string]any{"id": gw, "team_h": 10, "team_a": 11}},
+		})
+	}
+	match := func(gw, a, ap, b, bp int, finished bool) map[string]any {
+		return map[string]any{"event": gw, "finished": finished, "league_entry_1": a, "league_entry_1_points": ap, "league_entry_2": b, "league_entry_2_points": bp}
+	}
+	writeLeagueDetailsFixture(t, dir, 100, []any{
+		map[string]any{"id": 1, "entry_id": 200, "entry_name": "Alpha FC"},
+		map[string]any{"id": 2, "entry_id": 201, "entry_name": "Beta FC"},
+		map[string]any{"id": 3, "entry_id": 202, "entry_name": "Gamma FC"},
+	}, []any{
+		match(1, 1, 50, 2, 40, true),
+		match(2, 3, 45, 2, 30, true),
+		match(3, 1, 40, 3, 40, true),
+		match(4, 1, 0, 2, 0, false),
+		match(5, 1, 0, 3, 0, false),
+	})
+	choices := []any{}
+	for entry, ids := range map[int][]int{200: {1, 2, 7}, 201: {4, 10}, 202: {11}} {
+		for _, id := range ids {
+			choices = append(choices, map[string]any{"entry": entry, "element": id, "round": 1, "pick": 1, "index": id})
+		}
+	}
+	writeJSON(t, filepath.Join(dir, "draft/100/choices.json"), map[string]any{"choices": choices})
+	writeJSON(t, filepath.Join(dir, "league/100/transactions.json"), map[string]any{"transactions": []any{}})
+	writeJSON(t, filepath.Join(dir, "league/100/trades.json"), map[string]any{"trades": []any{
+		// Alpha offers Watkins for Saka.
+		map[string]any{"id": 7, "event": 4, "state": "o", "offered_entry": 200, "received_entry": 201,
+			"tradeitem_set": []any{map[string]any{"element_out": 7, "element_in": 4}}},
+		map[string]any{"id": 8, "event": 3, "state": "r", "offered_entry": 202, "received_entry": 201,
+			"tradeitem_set": []any{map[string]any{"element_out": 11, "element_in": 10}}},
+	}})
+}
+
+func TestBuildTradeReview(t *testing.T) {
+	dir, cfg := resourceCfg(t)
+	writeTradeReviewFixture(t, dir)
+	id := 7
+	out, err := buildTradeReview(cfg, TradeReviewArgs{LeagueID: 100, TradeID: &id})
+	if err != nil {
+		t.Fatalf("buildTradeReview: %v", err)
+	}
+	if out.State != "offered" || out.FromGW != 4 || len(out.Sides) != 2 {
+		t.Fatalf("state=%s from=%d sides=%d", out.State, out.FromGW, len(out.Sides))
+	}
+	alpha, beta := out.Sides[0], out.Sides[1]
+	if alpha.EntryID != 200 || len(alpha.Sends) != 1 || alpha.Sends[0].Name != "Watkins" || alpha.Receives[0].Name != "Saka" {
+		t.Errorf("alpha side = %+v, want Watkins for Saka", alpha)
+	}
+	if alpha.ProjectedNet <= 0 || beta.ProjectedNet != -alpha.ProjectedNet {
+		t.Errorf("nets = %v / %v, want Alpha up and Beta down by as much", alpha.ProjectedNet, beta.ProjectedNet)
+	}
+	if mid := beta.Positions[2]; mid.Position != "MID" || mid.After >= mid.Before || mid.AfterVsLeague >= mid.BeforeVsLeague {
+		t.Errorf("beta MID = %+v, want weaker after", mid)
+	}
+
+	// Without the trade Alpha loses GW4 to Beta and beats Gamma: 4 + 3 = 7,
+	// Beta 3, Gamma 4. With it Alpha wins both and Beta none.
+	wantStandings := map[int]TradeStandingsImpact{
+		200: {MatchPoints: 4, RemainingMatches: 2, ExpectedFinalBefore: 7, ExpectedFinalAfter: 10, Change: 3, ProjectedRankBefore: 1, ProjectedRankAfter: 1, Tier: "contender"},
+		201: {MatchPoints: 0, RemainingMatches: 1, ExpectedFinalBefore: 3, ExpectedFinalAfter: 0, Change: -3, ProjectedRankBefore: 3, ProjectedRankAfter: 3, Tier: "bottom"},
+	}
+	for _, s := range out.Sides {
+		if s.Standings != wantStandings[s.EntryID] {
+			t.Errorf("entry %d standings = %+v, want %+v", s.EntryID, s.Standings, wantStandings[s.EntryID])
+		}
+	}
+	if out.Fairness != 0 || out.Verdict != "review recommended" || len(out.Reasons) != 2 {
+		t.Errorf("fairness = %d %s %v, want 0 and review recommended with a standings reason", out.Fairness, out.Verdict, out.Reasons)
+	}
+
+	// The same trade given explicitly from Beta's side mirrors it.
+	betaID, alphaID := 201, 200
+	explicit, err := buildTradeReview(cfg, TradeReviewArgs{LeagueID: 100, EntryID: &betaID, PartnerID: &alphaID, Give: []int{4}, Receive: []int{7}})
+	if err != nil {
+		t.Fatalf("explicit trade: %v", err)
+	}
+	if explicit.TradeID != 0 || explicit.State != "" || explicit.Fairness != out.Fairness || explicit.Sides[0].ProjectedNet != beta.ProjectedNet {
+		t.Errorf("explicit = %+v, want the mirror of trade 7", explicit)
+	}
+}
+
+func TestBuildTradeReview_Errors(t *testing.T) {
+	dir, cfg := resourceCfg(t)
+	writeTradeReviewFixture(t, dir)
+	processed, missing := 8, 99
+	alpha, beta := 200, 201
+	cases := []struct {
+		name string
+		args TradeReviewArgs
+		code errorCode
+	}{
+		{"no league", TradeReviewArgs{TradeID: &missing}, codeInvalidArgument},
+		{"no trade", TradeReviewArgs{LeagueID: 100}, codeInvalidArgument},
+		{"rejected trade", TradeReviewArgs{LeagueID: 100, TradeID: &processed}, codeInvalidArgument},
+		{"unknown trade", TradeReviewArgs{LeagueID: 100, TradeID: &missing}, codeNotFound},
+		{"both forms", TradeReviewArgs{LeagueID: 100, TradeID: &missing, Give: []int{1}}, codeInvalidArgument},
+		{"not on roster", TradeReviewArgs{LeagueID: 100, EntryID: &alpha, PartnerID: &beta, Give: []int{4}}, codeInvalidArgument},
+		{"same entry", TradeReviewArgs{LeagueID: 100, EntryID: &alpha, PartnerID: &alpha, Give: []int{1}}, codeInvalidArgument},
+		{"nothing moves", TradeReviewArgs{LeagueID: 100, EntryID: &alpha, PartnerID: &beta}, codeInvalidArgument},
+	}
+	for _, c := range cases {
+		if _, err := buildTradeReview(cfg, c.args); classifyError(err).Code != c.code {
+			t.Errorf("%s: err = %v, want %s", c.name, err, c.code)
+		}
+	}
+}
@@ -59,7 +59,7 @@ type TransactionAnalysisOutput struct {
 
 func buildTransactionAnalysis(cfg ServerConfig, args TransactionAnalysisArgs) (TransactionAnalysisOutput, error) {
 	if args.LeagueID == 0 {
-		return TransactionAnalysisOutput{}, fmt.Errorf("league_id is required")
+		return TransactionAnalysisOutput{}, invalidArgumentf("league_id is required")
 	}
 
 	gw, err := resolveGW(cfg, args.GW)
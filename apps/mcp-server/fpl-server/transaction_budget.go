@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/leagueconfig"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/summary"
+)
+
+// loadTransactionBudget reads entryID's moves through gw against the
+// league's transaction cap. It returns a nil budget for an uncapped league.
+func loadTransactionBudget(cfg ServerConfig, leagueID int, entryID int, gw int) (*summary.TransactionBudget, error) {
+	st := store.NewJSONStore(cfg.RawRoot)
+	settings, err := leagueconfig.Load(st, leagueID)
+	if err != nil {
+		return nil, err
+	}
+	if !settings.Waivers.Capped() {
+		return nil, nil
+	}
+	transactions, err := loadTransactionsRaw(st, leagueID)
+	if err != nil {
+		return nil, err
+	}
+	return summary.BuildTransactionBudget(settings.Waivers, transactions, entryID, gw, settings.StartEvent, settings.LastGW()), nil
+}
+
+// budgetRemainingText says what is left of a budget's caps.
+func budgetRemainingText(b *summary.TransactionBudget) string {
+	parts := make([]string, 0, 2)
+	if b.Remaining != nil {
+		parts = append(parts, fmt.Sprintf("%d of %d left for the season", *b.Remaining, b.SeasonLimit))
+	}
+	if b.RemainingThisGW != nil {
+		parts = append(parts, fmt.Sprintf("%d of %d left this GW", *b.RemainingThisGW, b.GWLimit))
+	}
+	return strings.Join(parts, ", ")
+}
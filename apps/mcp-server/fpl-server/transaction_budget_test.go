@@ -0,0 +1,40 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadTransactionBudget(t *testing.T) {
+	dir := t.TempDir()
+	cfg := ServerConfig{RawRoot: dir}
+	details := minimalDetails()
+	writeLeagueDetails(t, dir, 888, details)
+	writeJSON(t, filepath.Join(dir, "league/888/transactions.json"), map[string]any{"transactions": []any{
+		map[string]any{"id": 1, "entry": 101, "event": 2, "kind": "w", "result": "a", "element_in": 10, "element_out": 2},
+		map[string]any{"id": 2, "entry": 101, "event": 4, "kind": "f", "result": "a", "element_in": 11, "element_out": 3},
+		map[string]any{"id": 3, "entry": 102, "event": 4, "kind": "w", "result": "a", "element_in": 12, "element_out": 4},
+	}})
+	alpha := 101
+
+	// Uncapped: no budget anywhere.
+	if b, err := loadTransactionBudget(cfg, 888, alpha, 4); err != nil || b != nil {
+		t.Fatalf("uncapped budget = %+v, %v", b, err)
+	}
+	out, err := buildManagerSeason(cfg, ManagerSeasonArgs{LeagueID: 888, EntryID: &alpha})
+	if err != nil || out.TransactionBudget != nil {
+		t.Fatalf("uncapped manager_season budget = %+v, %v", out.TransactionBudget, err)
+	}
+
+	// Capped at 10 a season and 1 a GW: Alpha's GW4 free agent used the GW.
+	details["league"] = map[string]any{"id": 888, "transaction_mode": "w", "max_transactions": 10, "max_transactions_per_event": 1, "stop_event": 8}
+	writeLeagueDetails(t, dir, 888, details)
+	out, err = buildManagerSeason(cfg, ManagerSeasonArgs{LeagueID: 888, EntryID: &alpha})
+	if err != nil {
+		t.Fatalf("buildManagerSeason: %v", err)
+	}
+	b := out.TransactionBudget
+	if b == nil || b.Used != 2 || *b.Remaining != 8 || *b.RemainingThisGW != 0 || b.GWsLeft != 4 || !b.Exhausted() {
+		t.Errorf("capped budget = %+v, want 2 used, 8 left, GW4 used up with 4 GWs to go", b)
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/summary"
+)
+
+// TransactionsArgs are the input arguments for the transactions tool.
+type TransactionsArgs struct {
+	LeagueID int    `json:"league_id" jsonschema:"Draft league id (required)"`
+	GW       int    `json:"gw" jsonschema:"Gameweek (0 = current)"`
+	Format   string `json:"format,omitempty" jsonschema:"json|markdown|csv (default json)"`
+	PageSize *int   `json:"page_size,omitempty" jsonschema:"Managers per page, in league order (default all)"`
+	Cursor   string `json:"cursor,omitempty" jsonschema:"next_cursor from the previous page"`
+}
+
+// TransactionsOutput is a page of the GW's transactions summary.
+type TransactionsOutput struct {
+	summary.TransactionsSummary
+	Page *PageInfo `json:"page,omitempty"`
+}
+
+// buildTransactions returns the derived transactions summary for the GW,
+// cut to one page of managers when the caller pages. Unpaged calls get the
+// file as stored.
+func buildTransactions(cfg ServerConfig, args TransactionsArgs) ([]byte, error) {
+	if args.LeagueID == 0 {
+		return nil, invalidArgumentf("league_id is required")
+	}
+	gw, err := resolveGW(cfg, args.GW)
+	if err != nil {
+		return nil, err
+	}
+	relPath := fmt.Sprintf("summary/transactions/%d/gw/%d.json", args.LeagueID, gw)
+	f, err := loadSummaryFileWithMeta(cfg, args.LeagueID, gw, relPath, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	page := PageArgs{PageSize: args.PageSize, Cursor: args.Cursor}
+	if !page.paged() {
+		return f.Bytes, nil
+	}
+	var out TransactionsOutput
+	if err := json.Unmarshal(f.Bytes, &out.TransactionsSummary); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", relPath, err)
+	}
+	var info PageInfo
+	if out.Entries, info, err = paginate(out.Entries, page, fmt.Sprintf("transactions|%d|%d", args.LeagueID, gw), f.Hash); err != nil {
+		return nil, err
+	}
+	out.Page = &info
+	return json.MarshalIndent(out, "", "  ")
+}
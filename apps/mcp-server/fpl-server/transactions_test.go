@@ -0,0 +1,92 @@
+package main
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func writeTransactionsSummary(t *testing.T, dir string, entryIDs ...int) {
+	t.Helper()
+	entries := []any{}
+	for _, id := range entryIDs {
+		entries = append(entries, map[string]any{"entry_id": id, "waiver_in": []int{id * 10}})
+	}
+	writeJSON(t, filepath.Join(dir, "summary/transactions/100/gw/3.json"), map[string]any{
+		"league_id": 100, "gameweek": 3, "entries": entries,
+	})
+}
+
+func TestBuildTransactions_Pages(t *testing.T) {
+	dir, cfg := tmpCfg(t)
+	cfg.DerivedRoot = dir
+	writeTransactionsSummary(t, dir, 200, 201, 202, 203, 204)
+
+	size := 2
+	args := TransactionsArgs{LeagueID: 100, GW: 3, PageSize: &size}
+	var got []int
+	for i := 0; i < 5; i++ {
+		raw, err := buildTransactions(cfg, args)
+		if err != nil {
+			t.Fatalf("page %d: %v", i, err)
+		}
+		var out TransactionsOutput
+		if err := json.Unmarshal(raw, &out); err != nil {
+			t.Fatal(err)
+		}
+		if out.Page == nil || out.Page.Total != 5 || out.Gameweek != 3 {
+			t.Fatalf("page %d = %+v gw %d, want a page of 5 managers in GW 3", i, out.Page, out.Gameweek)
+		}
+		for _, e := range out.Entries {
+			got = append(got, e.EntryID)
+		}
+		if out.Page.NextCursor == "" {
+			break
+		}
+		args = TransactionsArgs{LeagueID: 100, GW: 3, Cursor: out.Page.NextCursor}
+	}
+	if !slices.Equal(got, []int{200, 201, 202, 203, 204}) {
+		t.Errorf("pages joined = %v", got)
+	}
+}
+
+func TestBuildTransactions_StaleCursorRestarts(t *testing.T) {
+	dir, cfg := tmpCfg(t)
+	cfg.DerivedRoot = dir
+	writeTransactionsSummary(t, dir, 200, 201, 202, 203)
+
+	size := 2
+	raw, err := buildTransactions(cfg, TransactionsArgs{LeagueID: 100, GW: 3, PageSize: &size})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var first TransactionsOutput
+	if err := json.Unmarshal(raw, &first); err != nil {
+		t.Fatal(err)
+	}
+
+	// The summary is rebuilt between pages.
+	writeTransactionsSummary(t, dir, 300, 301, 302, 303)
+	raw, err = buildTransactions(cfg, TransactionsArgs{LeagueID: 100, GW: 3, Cursor: first.Page.NextCursor})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var next TransactionsOutput
+	if err := json.Unmarshal(raw, &next); err != nil {
+		t.Fatal(err)
+	}
+	if !next.Page.Restart || len(next.Entries) != 2 || next.Entries[0].EntryID != 300 {
+		t.Errorf("after rebuild = %+v %+v, want a restart from the new first page", next.Page, next.Entries)
+	}
+
+	// Unpaged calls get the file untouched, with no page block.
+	raw, err = buildTransactions(cfg, TransactionsArgs{LeagueID: 100, GW: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var whole TransactionsOutput
+	if err := json.Unmarshal(raw, &whole); err != nil || whole.Page != nil || len(whole.Entries) != 4 {
+		t.Errorf("unpaged = %+v entries %d, %v", whole.Page, len(whole.Entries), err)
+	}
+}
@@ -1,39 +1,58 @@
 package main
 
 import (
-	"bytes"
 	"encoding/json"
 	"fmt"
 	"math"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/fixtures"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/jsonutil"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/livestats"
 	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/model"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/projection"
 	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/reconcile"
 	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
 	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/summary"
 )
 
+// returningMinutes60Season is the season count of 60-minute GWs that lets a
+// "returning" player skip the last-3 availability rule.
+const returningMinutes60Season = 5
+
 type WaiverRecommendationsArgs struct {
-	LeagueID       int      `json:"league_id" jsonschema:"Draft league id (required)"`
-	EntryID        *int     `json:"entry_id,omitempty" jsonschema:"Entry id (required if entry_name not provided)"`
-	EntryName      *string  `json:"entry_name,omitempty" jsonschema:"Entry name (if entry_id not provided)"`
-	First          *string  `json:"first,omitempty" jsonschema:"First name (optional helper)"`
-	Last           *string  `json:"last,omitempty" jsonschema:"Last name (optional helper)"`
-	GW             *int     `json:"gw,omitempty" jsonschema:"Target gameweek for waivers (0 = next gameweek)"`
-	Horizon        *int     `json:"horizon,omitempty" jsonschema:"Rolling horizon in GWs (default 5)"`
-	WeightFixtures *float64 `json:"weight_fixtures,omitempty" jsonschema:"Weight for fixture score (default 0.35)"`
-	WeightForm     *float64 `json:"weight_form,omitempty" jsonschema:"Weight for form score (default 0.25)"`
-	WeightTotal    *float64 `json:"weight_total_points,omitempty" jsonschema:"Weight for total points (default 0.25)"`
-	WeightXG       *float64 `json:"weight_xg,omitempty" jsonschema:"Weight for expected goals (default 0.15)"`
-	Limit          *int     `json:"limit,omitempty" jsonschema:"How many add recommendations (default 5)"`
-	UndroppableIDs *[]int   `json:"undroppable_ids,omitempty" jsonschema:"Element ids that should never be dropped"`
-	TargetPosition *int     `json:"target_position,omitempty" jsonschema:"Position to target (1=GK,2=DEF,3=MID,4=FWD)"`
-	TargetType     *string  `json:"target_type,omitempty" jsonschema:"overall|next_fixture|consistency (default overall)"`
-	ConsistencyK   *float64 `json:"consistency_k,omitempty" jsonschema:"Penalty factor for consistency score (default 0.63)"`
+	LeagueID          int       `json:"league_id" jsonschema:"Draft league id (required)"`
+	EntryID           *int      `json:"entry_id,omitempty" jsonschema:"Entry id (required if entry_name not provided)"`
+	EntryName         *string   `json:"entry_name,omitempty" jsonschema:"Entry name (if entry_id not provided)"`
+	First             *string   `json:"first,omitempty" jsonschema:"First name (optional helper)"`
+	Last              *string   `json:"last,omitempty" jsonschema:"Last name (optional helper)"`
+	GW                *int      `json:"gw,omitempty" jsonschema:"Target gameweek for waivers (0 = the current gameweek until it finishes, then the next)"`
+	Horizon           *int      `json:"horizon,omitempty" jsonschema:"Rolling horizon in GWs (default 5)"`
+	WeightFixtures    *float64  `json:"weight_fixtures,omitempty" jsonschema:"Weight for fixture score (default 0.35)"`
+	WeightForm        *float64  `json:"weight_form,omitempty" jsonschema:"Weight for form score (default 0.25)"`
+	WeightTotal       *float64  `json:"weight_total_points,omitempty" jsonschema:"Weight for total points (default 0.25)"`
+	WeightXG          *float64  `json:"weight_xg,omitempty" jsonschema:"Weight for expected goals, or the defensive component for GK/DEF (default 0.15)"`
+	WeightXA          *float64  `json:"weight_xa,omitempty" jsonschema:"Weight for expected assists per 90 (default 0.05)"`
+	WeightBonus       *float64  `json:"weight_bonus,omitempty" jsonschema:"Weight for bonus points per GW (default 0.05)"`
+	Limit             *int      `json:"limit,omitempty" jsonschema:"How many add recommendations (default 5)"`
+	UndroppableIDs    *[]int    `json:"undroppable_ids,omitempty" jsonschema:"Element ids that should never be dropped"`
+	TargetPosition    *int      `json:"target_position,omitempty" jsonschema:"Position to target (1=GK,2=DEF,3=MID,4=FWD)"`
+	TargetType        *string   `json:"target_type,omitempty" jsonschema:"overall|next_fixture|consistency (default overall)"`
+	ConsistencyK      *float64  `json:"consistency_k,omitempty" jsonschema:"Penalty factor for consistency score (default 0.63)"`
+	Lookahead         *int      `json:"lookahead,omitempty" jsonschema:"Upcoming H2H opponents to check drops against (default 3)"`
+	SuppressRisky     *bool     `json:"suppress_risky_drops,omitempty" jsonschema:"Avoid suggesting drops that fill an upcoming opponent's weakest position when a safe alternative exists"`
+	Model             string    `json:"model,omitempty" jsonschema:"Attach a target-GW points projection to each add and suggested drop: heuristic|poisson (default none)"`
+	CongestionPenalty *float64  `json:"congestion_penalty,omitempty" jsonschema:"Fraction taken off a fixture's score when the team has under 4 days rest before it (default 0.1, 0 to turn off)"`
+	FAABReserve       *int      `json:"faab_reserve,omitempty" jsonschema:"FAAB leagues: budget to keep back from any bid (default 10% of the season budget)"`
+	IncludeElements   *[]int    `json:"include_elements,omitempty" jsonschema:"Element ids to score and list (forced) even when a filter or the limit would leave them out"`
+	IncludePlayers    *[]string `json:"include_players,omitempty" jsonschema:"Player names to force in the same way as include_elements"`
+	Format            string    `json:"format,omitempty" jsonschema:"json|markdown|csv (default json)"`
 }
 
 type WaiverRecommendationsReport struct {
@@ -47,37 +66,80 @@ type WaiverRecommendationsReport struct {
 	WeightForm          float64 `json:"weight_form"`
 	WeightTotal         float64 `json:"weight_total_points"`
 	WeightXG            float64 `json:"weight_xg"`
+	WeightXA            float64 `json:"weight_xa"`
+	WeightBonus         float64 `json:"weight_bonus"`
 	FixtureSeasonWeight float64 `json:"fixture_season_weight"`
 	FixtureRecentWeight float64 `json:"fixture_recent_weight"`
 	ScoringFormula      string  `json:"scoring_formula"`
 	TargetPosition      int     `json:"target_position,omitempty"`
 	TargetType          string  `json:"target_type,omitempty"`
 	ConsistencyK        float64 `json:"consistency_k"`
-	Filters             struct {
-		Minutes60Last3  int `json:"minutes_60_last3_required"`
-		Minutes60Season int `json:"minutes_60_season_required"`
+	CongestionPenalty   float64 `json:"congestion_penalty"`
+	Model               string  `json:"model,omitempty"`
+	// ColdStart is set before 3 GWs have finished; the notes say which
+	// fallbacks the report used.
+	ColdStart bool `json:"cold_start,omitempty"`
+	Filters   struct {
+		Minutes60Last3           int `json:"minutes_60_last3_required"`
+		Minutes60Season          int `json:"minutes_60_season_required"`
+		Minutes60SeasonReturning int `json:"minutes_60_season_returning_required"`
 	} `json:"filters"`
+	SquadCounts     map[string]int                  `json:"squad_counts"`
 	Adds            []AddRecommendation             `json:"top_adds"`
 	Drops           []DropRecommendation            `json:"drop_candidates"`
 	DropsByPosition map[string][]DropRecommendation `json:"drop_candidates_by_position,omitempty"`
-	Warnings        []string                        `json:"warnings,omitempty"`
-	Notes           []string                        `json:"notes"`
+	Scoring         *ProjectionScoring              `json:"scoring,omitempty"` // rules the projections used; set with Model
+	FAAB            *FAABStatus                     `json:"faab,omitempty"`    // FAAB leagues only
+	// TransactionBudget is the entry's moves against the league's cap
+	// through the target GW, in capped leagues only.
+	TransactionBudget *summary.TransactionBudget `json:"transaction_budget,omitempty"`
+	// ForcedErrors lists the include_elements / include_players requests
+	// that couldn't be scored, one per candidate.
+	ForcedErrors []ForcedCandidateError `json:"forced_errors,omitempty"`
+	Warnings     []string               `json:"warnings,omitempty"`
+	Notes        []string               `json:"notes"`
+	GWNote       *GWNote                `json:"gw_note,omitempty"`
+}
+
+// ForcedCandidateError explains why a requested forced candidate isn't in
+// top_adds. Input is the id or name as requested.
+type ForcedCandidateError struct {
+	Input   string `json:"input"`
+	Element int    `json:"element,omitempty"`
+	Name    string `json:"name,omitempty"`
+	Error   string `json:"error"`
 }
 
 type ScoreComponents struct {
-	FixturesRaw      float64 `json:"fixtures_raw"`
-	FixturesSeason   float64 `json:"fixtures_season"`
-	FixturesRecent   float64 `json:"fixtures_recent"`
-	FormRaw          float64 `json:"form_raw"`
-	TotalRaw         float64 `json:"total_raw"`
-	XGRaw            float64 `json:"xg_raw"`
+	// Profile is profileAttacking or profileDefensive; see scoringProfile.
+	Profile        string  `json:"profile"`
+	FixturesRaw    float64 `json:"fixtures_raw"`
+	FixturesSeason float64 `json:"fixtures_season"`
+	FixturesRecent float64 `json:"fixtures_recent"`
+	FormRaw        float64 `json:"form_raw"`
+	TotalRaw       float64 `json:"total_raw"`
+	XGRaw          float64 `json:"xg_raw"`
+	XARaw          float64 `json:"xa_raw"`
+	BonusRaw       float64 `json:"bonus_raw"`
+	// SavesPer90, CleanSheetRate and AttackConceded feed DefensiveNorm and
+	// are only set for the defensive profile. AttackConceded is the MID+FWD
+	// points the player's own team concedes per fixture at the target venue.
+	SavesPer90       float64 `json:"saves_per90,omitempty"`
+	CleanSheetRate   float64 `json:"clean_sheet_rate,omitempty"`
+	AttackConceded   float64 `json:"attack_conceded,omitempty"`
 	AvgPoints        float64 `json:"avg_points"`
 	StdDevPoints     float64 `json:"stddev_points"`
 	ConsistencyScore float64 `json:"consistency_score"`
+	// ConsistencyLabel is "insufficient_data" while there are too few GWs
+	// for the spread to mean anything, when a zero stddev isn't steadiness.
+	ConsistencyLabel string  `json:"consistency_label,omitempty"`
 	FixturesNorm     float64 `json:"fixtures_norm"`
 	FormNorm         float64 `json:"form_norm"`
 	TotalNorm        float64 `json:"total_norm"`
 	XGNorm           float64 `json:"xg_norm"`
+	XANorm           float64 `json:"xa_norm"`
+	BonusNorm        float64 `json:"bonus_norm"`
+	DefensiveNorm    float64 `json:"defensive_norm,omitempty"`
 	WeightedScore    float64 `json:"weighted_score"`
 }
 
@@ -89,11 +151,17 @@ type FixtureContext struct {
 	OpponentID    int    `json:"opponent_id"`
 	OpponentShort string `json:"opponent_short"`
 	Venue         string `json:"venue"`
+	// Congestion is set by waiver_recommendations for fixtures with a known
+	// kickoff.
+	Congestion *Congestion `json:"congestion,omitempty"`
 }
 
 type AvailabilityInfo struct {
 	Minutes60Last3  int `json:"minutes_60_last3"`
 	Minutes60Season int `json:"minutes_60_season"`
+	// ColdStartEligible is set before 3 GWs have finished for a player who
+	// passes the relaxed rule (coldStartEligible) in place of the 60-minute one.
+	ColdStartEligible bool `json:"cold_start_eligible,omitempty"`
 }
 
 type AddRecommendation struct {
@@ -111,7 +179,27 @@ type AddRecommendation struct {
 	PreviousOwners     []string            `json:"previous_owners,omitempty"`
 	PreviousOwnerCount int                 `json:"previous_owner_count,omitempty"`
 	SuggestedDrop      *DropRecommendation `json:"suggested_drop,omitempty"`
-	Reasons            []string            `json:"reasons"`
+	// Projection is the target-GW points projection, set when a model is
+	// requested.
+	Projection *projection.Projection `json:"projection,omitempty"`
+	// NoLegalDrop is set when every drop that would keep the squad within
+	// the league's squad limits is undroppable.
+	NoLegalDrop bool `json:"no_legal_drop,omitempty"`
+	// SuggestedBid, RemainingBudget and MaxBid are set in FAAB leagues only.
+	// SuggestedBid is the league's winning bid at the player's score
+	// percentile in the pool, capped at MaxBid; it is left out until the
+	// league has a winning bid.
+	SuggestedBid    *int `json:"suggested_bid,omitempty"`
+	RemainingBudget *int `json:"remaining_budget,omitempty"`
+	MaxBid          *int `json:"max_bid,omitempty"`
+	// Urgency estimates the chance another manager claims the player this
+	// waiver cycle.
+	Urgency *ClaimUrgency `json:"urgency,omitempty"`
+	// Forced marks a candidate listed because include_elements or
+	// include_players asked for it; it sits outside the limit and its
+	// reasons name any filter it fails.
+	Forced  bool     `json:"forced,omitempty"`
+	Reasons []string `json:"reasons"`
 }
 
 type DropRecommendation struct {
@@ -121,6 +209,10 @@ type DropRecommendation struct {
 	PositionType int     `json:"position_type"`
 	Score        float64 `json:"score"`
 	Reason       string  `json:"reason"`
+	// OpponentRisk is set when one of the next lookahead opponents is weakest
+	// at this player's position and would gain by claiming the player.
+	OpponentRisk *OpponentRisk          `json:"opponent_risk,omitempty"`
+	Projection   *projection.Projection `json:"projection,omitempty"`
 }
 
 type scoredPlayer struct {
@@ -130,6 +222,10 @@ type scoredPlayer struct {
 	// In a normal GW this has length 1; in a double gameweek, length 2.
 	fixtures     []FixtureContext
 	availability AvailabilityInfo
+	// forced is set for include_elements candidates; failed lists the
+	// filters they would otherwise have been dropped by.
+	forced bool
+	failed []string
 }
 
 type elementInfo struct {
@@ -139,24 +235,101 @@ type elementInfo struct {
 	PositionType int
 	Status       string
 	TotalPoints  int
+	// Minutes and Starts are bootstrap's season totals.
+	Minutes int
+	Starts  int
+}
+
+// fixture is a PL fixture. Kickoff is the API's kickoff_time, empty when it
+// was null (not yet scheduled).
+type fixture = fixtures.Fixture
+
+// scoreWeights holds the normalized per-component weights used to build
+// ScoreComponents.WeightedScore. The fields always sum to 1.
+type scoreWeights struct {
+	Fix   float64
+	Form  float64
+	Total float64
+	XG    float64
+	XA    float64
+	Bonus float64
+}
+
+// resolveScoreWeights applies defaults and renormalizes the weight args.
+// When no weight is supplied the defaults are 0.35/0.25/0.25/0.15 for
+// fixtures/form/total/xG plus 0.05 each for xA and bonus, all scaled so the
+// sum is 1. Callers that pass only the original four weights get xA and
+// bonus weights of zero, so their rankings are unchanged.
+func resolveScoreWeights(args WaiverRecommendationsArgs) scoreWeights {
+	var w scoreWeights
+	if args.WeightFixtures != nil {
+		w.Fix = *args.WeightFixtures
+	}
+	if args.WeightForm != nil {
+		w.Form = *args.WeightForm
+	}
+	if args.WeightTotal != nil {
+		w.Total = *args.WeightTotal
+	}
+	if args.WeightXG != nil {
+		w.XG = *args.WeightXG
+	}
+	if args.WeightXA != nil {
+		w.XA = *args.WeightXA
+	}
+	if args.WeightBonus != nil {
+		w.Bonus = *args.WeightBonus
+	}
+	if w == (scoreWeights{}) {
+		w = scoreWeights{Fix: 0.35, Form: 0.25, Total: 0.25, XG: 0.15, XA: 0.05, Bonus: 0.05}
+	}
+	sum := w.Fix + w.Form + w.Total + w.XG + w.XA + w.Bonus
+	if sum == 0 {
+		sum = 1
+	}
+	w.Fix /= sum
+	w.Form /= sum
+	w.Total /= sum
+	w.XG /= sum
+	w.XA /= sum
+	w.Bonus /= sum
+	return w
 }
 
-type fixture struct {
-	ID    int
-	Event int
-	TeamH int
-	TeamA int
+// Scoring profiles reported in ScoreComponents.Profile.
+const (
+	profileAttacking = "attacking"
+	profileDefensive = "defensive"
+)
+
+// scoringProfile returns the profile a player at pos is scored with.
+// Goalkeepers and defenders get their points from clean sheets and saves, not
+// goals, so their xG component is replaced by DefensiveNorm.
+func scoringProfile(pos int) string {
+	if pos == 1 || pos == 2 {
+		return profileDefensive
+	}
+	return profileAttacking
 }
 
-type liveStats struct {
-	Minutes     int
-	TotalPoints int
-	XG          float64
+// weightedScore combines the normalized components of s using w. The xG
+// weight applies to DefensiveNorm instead for the defensive profile.
+func weightedScore(w scoreWeights, s ScoreComponents) float64 {
+	attack := s.XGNorm
+	if s.Profile == profileDefensive {
+		attack = s.DefensiveNorm
+	}
+	return w.Fix*s.FixturesNorm +
+		w.Form*s.FormNorm +
+		w.Total*s.TotalNorm +
+		w.XG*attack +
+		w.XA*s.XANorm +
+		w.Bonus*s.BonusNorm
 }
 
 func buildWaiverRecommendations(cfg ServerConfig, args WaiverRecommendationsArgs) ([]byte, error) {
 	if args.LeagueID == 0 {
-		return nil, fmt.Errorf("league_id is required")
+		return nil, invalidArgumentf("league_id is required")
 	}
 
 	entryID := 0
@@ -179,21 +352,16 @@ func buildWaiverRecommendations(cfg ServerConfig, args WaiverRecommendationsArgs
 			name = strings.TrimSpace(strings.Join([]string{first, last}, " "))
 		}
 		if name == "" {
-			return nil, fmt.Errorf("entry_id or entry_name is required")
+			return nil, invalidArgumentf("entry_id or entry_name is required")
 		}
 		st := store.NewJSONStore(cfg.RawRoot)
 		ld, _, err := loadLeagueDetails(st, args.LeagueID)
 		if err != nil {
 			return nil, err
 		}
-		for _, e := range ld.LeagueEntries {
-			if strings.EqualFold(e.EntryName, name) {
-				entryID = e.EntryID
-				break
-			}
-		}
-		if entryID == 0 {
-			return nil, fmt.Errorf("entry not found for name: %s", name)
+		entryID, err = resolveEntry(ld.LeagueEntries, name)
+		if err != nil {
+			return nil, err
 		}
 	}
 	h := 0
@@ -203,6 +371,13 @@ func buildWaiverRecommendations(cfg ServerConfig, args WaiverRecommendationsArgs
 	if h <= 0 {
 		h = 5
 	}
+	modelName := ""
+	if strings.TrimSpace(args.Model) != "" {
+		var err error
+		if modelName, err = parseProjectionModel(args.Model); err != nil {
+			return nil, err
+		}
+	}
 	limit := 0
 	if args.Limit != nil {
 		limit = *args.Limit
@@ -210,33 +385,7 @@ func buildWaiverRecommendations(cfg ServerConfig, args WaiverRecommendationsArgs
 	if limit <= 0 {
 		limit = 5
 	}
-	wFix := 0.0
-	wForm := 0.0
-	wTotal := 0.0
-	wXG := 0.0
-	if args.WeightFixtures != nil {
-		wFix = *args.WeightFixtures
-	}
-	if args.WeightForm != nil {
-		wForm = *args.WeightForm
-	}
-	if args.WeightTotal != nil {
-		wTotal = *args.WeightTotal
-	}
-	if args.WeightXG != nil {
-		wXG = *args.WeightXG
-	}
-	if wFix == 0 && wForm == 0 && wTotal == 0 && wXG == 0 {
-		wFix, wForm, wTotal, wXG = 0.35, 0.25, 0.25, 0.15
-	}
-	weightSum := wFix + wForm + wTotal + wXG
-	if weightSum == 0 {
-		weightSum = 1
-	}
-	wFix /= weightSum
-	wForm /= weightSum
-	wTotal /= weightSum
-	wXG /= weightSum
+	weights := resolveScoreWeights(args)
 
 	consistencyK := 0.0
 	if args.ConsistencyK != nil {
@@ -270,11 +419,16 @@ func buildWaiverRecommendations(cfg ServerConfig, args WaiverRecommendationsArgs
 	if args.GW != nil {
 		nextGWArg = *args.GW
 	}
-	asOfGW, nextGW, err := resolveAsOfAndNextGW(cfg, 0, nextGWArg)
+	// The target is a forward-looking GW, resolved like fixtures: the
+	// current GW until it finishes, then next_event.
+	targetGW, gwNote, err := resolveEffectiveGW(cfg, nextGWArg, gwModeCurrent)
+	if err != nil {
+		return nil, err
+	}
+	asOfGW, _, err := resolveAsOfAndNextGW(cfg, 0, targetGW)
 	if err != nil {
 		return nil, err
 	}
-	targetGW := nextGW
 
 	// rosterGW is the gameweek used to read each entry's current squad. It
 	// must be target-1 (not asOfGW) so that waivers processed at the GW
@@ -282,13 +436,40 @@ func buildWaiverRecommendations(cfg ServerConfig, args WaiverRecommendationsArgs
 	// hasn't yet advanced past targetGW-1.
 	rosterGW := resolveRosterGW(asOfGW, targetGW)
 
+	// Raw reads are timed into the call's read total; summaries and the
+	// horizon stats time themselves.
+	readStart := time.Now()
 	bootstrap, teamShort, fixturesByGW, err := loadBootstrapData(cfg.RawRoot)
+	cfg.timing.since(readStart, false)
 	if err != nil {
 		return nil, err
 	}
 	fixtureByTeam := buildFixtureIndex(fixturesByGW[targetGW], teamShort)
+	congestionPenalty := defaultCongestionPenalty
+	if args.CongestionPenalty != nil {
+		congestionPenalty = min(max(*args.CongestionPenalty, 0), 1)
+	}
+	readStart = time.Now()
+	kickoffs := loadTeamKickoffs(cfg.RawRoot, fixturesByGW, asOfGW, targetGW)
+	cfg.timing.since(readStart, false)
+	annotateCongestion(fixtureByTeam, fixturesByGW[targetGW], kickoffs, congestionPenalty)
+	var model projection.Model
+	var rules *ProjectionScoring
+	if modelName != "" {
+		r, err := loadScoringRules(cfg, args.LeagueID)
+		if err != nil {
+			return nil, err
+		}
+		rules = &r
+		mult := fixtureMultiplierFunc(cfg.RawRoot, bootstrap, teamShort, asOfGW, h)
+		if model, _, err = loadProjectionModel(cfg.RawRoot, modelName, bootstrap, map[int]map[int][]FixtureContext{targetGW: fixtureByTeam}, mult, asOfGW, h, r.Rules); err != nil {
+			return nil, err
+		}
+	}
 
-	owned, roster, err := buildOwnershipAndRoster(cfg, args.LeagueID, entryID, rosterGW, bootstrap, teamShort)
+	readStart = time.Now()
+	ownership, owned, roster, err := buildOwnershipAndRoster(cfg, args.LeagueID, entryID, rosterGW, bootstrap, teamShort)
+	cfg.timing.since(readStart, false)
 	if err != nil {
 		return nil, err
 	}
@@ -302,21 +483,24 @@ func buildWaiverRecommendations(cfg ServerConfig, args WaiverRecommendationsArgs
 		formByElement[p.Element] = p
 	}
 
-	seasonMinutes60, last3Minutes60, xgByElement, err := computeAvailabilityAndXG(cfg.RawRoot, bootstrap, asOfGW, h)
-	if err != nil {
-		return nil, err
-	}
-
-	avgPtsByElement, stddevPtsByElement, err := computeConsistencyStats(cfg.RawRoot, bootstrap, asOfGW, h)
+	// The live-data components come from the free_agent_scores file when
+	// it is current; only the per-request work below runs on a warm call.
+	hs, err := loadHorizonStats(cfg, args.LeagueID, bootstrap, asOfGW, h)
 	if err != nil {
 		return nil, err
 	}
+	seasonMinutes60, last3Minutes60, xgByElement := hs.season60, hs.last3, hs.xg
+	xaByElement, bonusByElement := hs.xa, hs.bonus
+	defensive := hs.defensive
+	avgPtsByElement, stddevPtsByElement := hs.avgPoints, hs.stddevPoints
 
 	seasonWeight, recentWeight := horizonWeights(h)
-	concededSeason := computePointsConcededByPosition(cfg.RawRoot, bootstrap, asOfGW, asOfGW)
-	concededRecent := computePointsConcededByPosition(cfg.RawRoot, bootstrap, asOfGW, h)
+	concededSeason, concededRecent, strengthFallback := coldStartConceded(cfg.RawRoot, hs.concededSeason, hs.concededRecent)
+	cold, started := coldStart(asOfGW), seasonStarted(bootstrap)
 
+	readStart = time.Now()
 	everOwnersByElement, err := buildEverOwners(cfg, args.LeagueID)
+	cfg.timing.since(readStart, false)
 	if err != nil {
 		return nil, err
 	}
@@ -330,6 +514,48 @@ func buildWaiverRecommendations(cfg ServerConfig, args WaiverRecommendationsArgs
 		}
 	}
 
+	scorePlayer := func(info elementInfo, teamFixtures []FixtureContext) scoredPlayer {
+		seasonScore, recentScore, blended := sumFixtureScores(teamFixtures, concededSeason, concededRecent, info.PositionType, seasonWeight, recentWeight)
+
+		form := formByElement[info.ID]
+		xg := xgByElement[info.ID]
+		xa := xaByElement[info.ID]
+		bonus := bonusByElement[info.ID]
+		avgPts := avgPtsByElement[info.ID]
+		stddev := stddevPtsByElement[info.ID]
+		consistency := avgPts - consistencyK*stddev
+		score := ScoreComponents{
+			Profile:          scoringProfile(info.PositionType),
+			FixturesRaw:      blended,
+			FixturesSeason:   seasonScore,
+			FixturesRecent:   recentScore,
+			FormRaw:          form.PointsPerGW,
+			TotalRaw:         float64(info.TotalPoints),
+			XGRaw:            xg,
+			XARaw:            xa,
+			BonusRaw:         bonus,
+			AvgPoints:        avgPts,
+			StdDevPoints:     stddev,
+			ConsistencyScore: consistency,
+		}
+		if score.Profile == profileDefensive {
+			defensive.fill(&score, info, teamFixtures, concededSeason, concededRecent, seasonWeight, recentWeight)
+		}
+		if cold {
+			score.ConsistencyLabel = consistencyInsufficient
+		}
+		return scoredPlayer{
+			info:     info,
+			fixtures: teamFixtures,
+			availability: AvailabilityInfo{
+				Minutes60Last3:    last3Minutes60[info.ID],
+				Minutes60Season:   seasonMinutes60[info.ID],
+				ColdStartEligible: cold && coldStartEligible(info, started),
+			},
+			score: score,
+		}
+	}
+
 	candidates := make([]scoredPlayer, 0)
 	for _, info := range bootstrap {
 		if info.PositionType == 0 {
@@ -344,64 +570,19 @@ func buildWaiverRecommendations(cfg ServerConfig, args WaiverRecommendationsArgs
 		if owned[info.ID] {
 			continue
 		}
-		last3 := last3Minutes60[info.ID]
-		season := seasonMinutes60[info.ID]
-		if last3 < 3 && season < 10 {
+		if !minutesEligible(last3Minutes60[info.ID], seasonMinutes60[info.ID], formByElement[info.ID].MinutesPattern) && !(cold && coldStartEligible(info, started)) {
 			continue
 		}
 		teamFixtures, ok := fixtureByTeam[info.TeamID]
 		if !ok || len(teamFixtures) == 0 {
 			continue
 		}
-		// Average fixture scores across all fixtures for this team in the target
-		// GW.  In a normal GW there is exactly one fixture; in a double gameweek
-		// (DGW) there are two and we average so that DGW teams receive a bonus
-		// proportional to having two chances to score FPL points.
-		var totalSeason, totalRecent, totalBlended float64
-		for _, fx := range teamFixtures {
-			s, r, b := blendedFixtureScore(concededSeason, concededRecent, fx.OpponentID, fx.Venue, info.PositionType, seasonWeight, recentWeight)
-			totalSeason += s
-			totalRecent += r
-			totalBlended += b
-		}
-		n := float64(len(teamFixtures))
-		seasonScore := totalSeason / n
-		recentScore := totalRecent / n
-		blended := totalBlended / n
-
-		form := formByElement[info.ID]
-		xg := xgByElement[info.ID]
-		avgPts := avgPtsByElement[info.ID]
-		stddev := stddevPtsByElement[info.ID]
-		consistency := avgPts - consistencyK*stddev
-		candidates = append(candidates, scoredPlayer{
-			info:     info,
-			fixtures: teamFixtures,
-			availability: AvailabilityInfo{
-				Minutes60Last3:  last3,
-				Minutes60Season: season,
-			},
-			score: ScoreComponents{
-				FixturesRaw:      blended,
-				FixturesSeason:   seasonScore,
-				FixturesRecent:   recentScore,
-				FormRaw:          form.PointsPerGW,
-				TotalRaw:         float64(info.TotalPoints),
-				XGRaw:            xg,
-				AvgPoints:        avgPts,
-				StdDevPoints:     stddev,
-				ConsistencyScore: consistency,
-			},
-		})
+		candidates = append(candidates, scorePlayer(info, teamFixtures))
 	}
 
 	minmax := normalizeScores(candidates)
 	for i := range candidates {
-		candidates[i].score.WeightedScore =
-			wFix*candidates[i].score.FixturesNorm +
-				wForm*candidates[i].score.FormNorm +
-				wTotal*candidates[i].score.TotalNorm +
-				wXG*candidates[i].score.XGNorm
+		candidates[i].score.WeightedScore = weightedScore(weights, candidates[i].score)
 	}
 	sort.Slice(candidates, func(i, j int) bool {
 		switch targetType {
@@ -419,27 +600,121 @@ func buildWaiverRecommendations(cfg ServerConfig, args WaiverRecommendationsArgs
 			return candidates[i].score.WeightedScore > candidates[j].score.WeightedScore
 		}
 	})
+	positionOf := make(map[int]int, len(bootstrap))
+	for _, e := range bootstrap {
+		positionOf[e.ID] = e.PositionType
+	}
+	faab, bids, err := loadFAABStatus(cfg, args.LeagueID, entryID, args.FAABReserve, positionOf)
+	if err != nil {
+		return nil, err
+	}
+	budget, err := loadTransactionBudget(cfg, args.LeagueID, entryID, targetGW)
+	if err != nil {
+		return nil, err
+	}
+	scorePct := scorePercentiles(candidates)
+	pool := candidates
 	if len(candidates) > limit {
 		candidates = candidates[:limit]
 	}
 
-	rosterScored := scoreRoster(bootstrap, teamShort, formByElement, xgByElement, fixtureByTeam, roster, concededSeason, concededRecent, seasonWeight, recentWeight, minmax, wFix, wForm, wTotal, wXG)
-	dropsByPos, warnings := pickDropCandidatesByPosition(rosterScored, undroppable, candidates, targetPosition)
+	// Forced candidates are scored against the organic pool's ranges and
+	// listed after it, so they never push an organic add past the limit.
+	ld, _, err := loadLeagueDetails(store.NewJSONStore(cfg.RawRoot), args.LeagueID)
+	if err != nil {
+		return nil, err
+	}
+	forcedInfos, forcedErrors := resolveForcedCandidates(bootstrap, args.IncludeElements, args.IncludePlayers, ownership, entryID, ld.LeagueEntries)
+	var forced []scoredPlayer
+	for _, info := range forcedInfos {
+		if i := slices.IndexFunc(candidates, func(c scoredPlayer) bool { return c.info.ID == info.ID }); i >= 0 {
+			candidates[i].forced = true
+			continue
+		}
+		p := scorePlayer(info, fixtureByTeam[info.TeamID])
+		p.forced = true
+		p.failed = forcedFilterFailures(info, p.availability, formByElement[info.ID].MinutesPattern, targetPosition, targetGW, len(p.fixtures))
+		minmax.apply(&p)
+		p.score.WeightedScore = weightedScore(weights, p.score)
+		scorePct[info.ID] = poolPercentile(pool, p.score.WeightedScore)
+		forced = append(forced, p)
+	}
+
+	rosterScored := scoreRoster(bootstrap, teamShort, formByElement, xgByElement, xaByElement, bonusByElement, defensive, fixtureByTeam, roster, concededSeason, concededRecent, seasonWeight, recentWeight, minmax, weights)
+
+	lookahead := defaultOpponentLookahead
+	if args.Lookahead != nil && *args.Lookahead > 0 {
+		lookahead = *args.Lookahead
+	}
+	suppressRisky := args.SuppressRisky != nil && *args.SuppressRisky
+	elementByID := make(map[int]elementInfo, len(bootstrap))
+	for _, e := range bootstrap {
+		elementByID[e.ID] = e
+	}
+	opponents := upcomingOpponents(ld, entryID, targetGW, lookahead)
+	annotateOpponentRisk(rosterScored, opponents, ownership, elementByID, formByElement)
+
+	readStart = time.Now()
+	history, lastGWStats, err := loadClaimHistory(cfg, args.LeagueID, ld.StartGW(), asOfGW)
+	cfg.timing.since(readStart, false)
+	if err != nil {
+		return nil, err
+	}
+	needs := rivalNeeds(ownership, entryID, elementByID, formByElement)
+
+	dropsByPos, warnings := pickDropCandidatesByPosition(rosterScored, undroppable, candidates, targetPosition, suppressRisky)
 	dropCandidates := flattenDrops(dropsByPos)
 
-	adds := make([]AddRecommendation, 0, len(candidates))
-	for _, c := range candidates {
+	limits, err := loadSquadLimits(cfg, args.LeagueID)
+	if err != nil {
+		return nil, err
+	}
+	squadCounts := squadPositionCounts(roster)
+	droppable := make([]DropRecommendation, 0, len(rosterScored))
+	for _, d := range rosterScored {
+		if !undroppable[d.Element] {
+			droppable = append(droppable, d)
+		}
+	}
+	safeDroppable := withoutOpponentRisk(droppable)
+
+	adds := make([]AddRecommendation, 0, len(candidates)+len(forced))
+	for _, c := range slices.Concat(candidates, forced) {
 		// Build fixture reason text: list all fixtures for DGW teams.
 		fixtureReasonParts := make([]string, 0, len(c.fixtures))
 		for _, fx := range c.fixtures {
-			fixtureReasonParts = append(fixtureReasonParts, fmt.Sprintf("vs %s (%s)", fx.OpponentShort, strings.ToLower(fx.Venue)))
+			part := fmt.Sprintf("vs %s (%s)", fx.OpponentShort, strings.ToLower(fx.Venue))
+			if fx.Congestion != nil && fx.Congestion.RestDays != nil {
+				part = fmt.Sprintf("vs %s (%s, %.1f days rest)", fx.OpponentShort, strings.ToLower(fx.Venue), *fx.Congestion.RestDays)
+			}
+			fixtureReasonParts = append(fixtureReasonParts, part)
 		}
-		reasons := []string{
+		reasons := append([]string(nil), c.failed...)
+		reasons = append(reasons,
 			fmt.Sprintf("fixture score %.2f (%s)", c.score.FixturesRaw, strings.Join(fixtureReasonParts, ", ")),
 			fmt.Sprintf("form %.2f pts/GW", c.score.FormRaw),
 			fmt.Sprintf("season points %.0f", c.score.TotalRaw),
-			fmt.Sprintf("xG %.2f", c.score.XGRaw),
+		)
+		for _, fx := range c.fixtures {
+			if r := congestionReason(fx); r != "" {
+				reasons = append(reasons, r)
+			}
 		}
+		if c.score.Profile == profileDefensive {
+			if c.info.PositionType == 1 {
+				reasons = append(reasons, fmt.Sprintf("saves/90 %.2f", c.score.SavesPer90))
+			}
+			reasons = append(reasons,
+				fmt.Sprintf("team clean sheets %.0f%%", c.score.CleanSheetRate*100),
+				fmt.Sprintf("team concedes %.2f MID/FWD pts/fixture", c.score.AttackConceded),
+			)
+		} else {
+			reasons = append(reasons, fmt.Sprintf("xG %.2f", c.score.XGRaw))
+		}
+		reasons = append(reasons,
+			fmt.Sprintf("xA/90 %.2f", c.score.XARaw),
+			fmt.Sprintf("bonus %.2f/GW", c.score.BonusRaw),
+		)
 		// primaryFixture is the first fixture stored; for a DGW this is
 		// just the first alphabetically/in order, but all fixtures are in Fixtures.
 		var primaryFixture FixtureContext
@@ -459,14 +734,56 @@ func buildWaiverRecommendations(cfg ServerConfig, args WaiverRecommendationsArgs
 			Score:              c.score,
 			PreviousOwners:     prevOwners,
 			PreviousOwnerCount: len(prevOwners),
+			Forced:             c.forced,
 			Reasons:            reasons,
 		}
-		if drop := bestDropForPosition(dropsByPos, c.info.PositionType, c.score.WeightedScore); drop != nil {
-			add.SuggestedDrop = drop
+		urgency := claimUrgency(history, scorePct[c.info.ID], asOfGW, lastGWStats[c.info.ID].TotalPoints, needs[c.info.PositionType])
+		add.Urgency = &urgency
+		drop, legal := legalDropForAdd(limits, droppable, squadCounts, c.info.PositionType, c.score.WeightedScore)
+		if suppressRisky && drop != nil && drop.OpponentRisk != nil {
+			if safe, _ := legalDropForAdd(limits, safeDroppable, squadCounts, c.info.PositionType, c.score.WeightedScore); safe != nil {
+				safe.Reason += fmt.Sprintf("; %s kept back from %s", drop.Name, drop.OpponentRisk.EntryName)
+				drop = safe
+			}
+		}
+		if model != nil {
+			p := model.ProjectPlayer(c.info.ID, targetGW)
+			add.Projection = &p
+			if drop != nil {
+				d := *drop
+				dp := model.ProjectPlayer(d.Element, targetGW)
+				d.Projection = &dp
+				drop = &d
+			}
+		}
+		if faab != nil {
+			remaining, maxBid := faab.Remaining, faab.MaxBid
+			add.RemainingBudget, add.MaxBid = &remaining, &maxBid
+			pct := scorePct[c.info.ID]
+			if bid, ok := suggestBid(bids.forPosition(c.info.PositionType), pct, maxBid); ok {
+				add.SuggestedBid = &bid
+				add.Reasons = append(add.Reasons, fmt.Sprintf("suggested bid %d: score percentile %.0f in the pool mapped onto the league's winning bids (max %d keeping %d in reserve)", bid, pct*100, maxBid, faab.Reserve))
+			}
+		}
+		add.SuggestedDrop = drop
+		if !legal {
+			add.NoLegalDrop = true
+			pos := positionLabel(c.info.PositionType)
+			warnings = append(warnings, fmt.Sprintf("%s: squad already has %d %s (limit %d) and no %s is droppable; no legal drop for this add.",
+				c.info.Name, squadCounts[c.info.PositionType], pos, limits.At(c.info.PositionType), pos))
 		}
 		adds = append(adds, add)
 	}
 
+	squadCountsByLabel := make(map[string]int, 4)
+	for pos := 1; pos <= 4; pos++ {
+		squadCountsByLabel[positionLabel(pos)] = squadCounts[pos]
+	}
+
+	if w := bootstrapFixturesWarning(fixturesByGW); w != "" {
+		warnings = append(warnings, w)
+	}
+
 	report := WaiverRecommendationsReport{
 		LeagueID:            args.LeagueID,
 		EntryID:             entryID,
@@ -474,28 +791,77 @@ func buildWaiverRecommendations(cfg ServerConfig, args WaiverRecommendationsArgs
 		RosterGW:            rosterGW,
 		TargetGW:            targetGW,
 		Horizon:             h,
-		WeightFixtures:      wFix,
-		WeightForm:          wForm,
-		WeightTotal:         wTotal,
-		WeightXG:            wXG,
+		WeightFixtures:      weights.Fix,
+		WeightForm:          weights.Form,
+		WeightTotal:         weights.Total,
+		WeightXG:            weights.XG,
+		WeightXA:            weights.XA,
+		WeightBonus:         weights.Bonus,
 		FixtureSeasonWeight: seasonWeight,
 		FixtureRecentWeight: recentWeight,
-		ScoringFormula:      "weighted_score = w_fix*fixture_norm + w_form*form_norm + w_total*total_norm + w_xg*xg_norm (each norm is min-max across the candidate pool)",
+		ScoringFormula:      "weighted_score = w_fix*fixture_norm + w_form*form_norm + w_total*total_norm + w_xg*xg_norm + w_xa*xa_norm + w_bonus*bonus_norm (each norm is min-max across the candidate pool); GK/DEF use defensive_norm in place of xg_norm",
+		SquadCounts:         squadCountsByLabel,
 		Adds:                adds,
 		Drops:               dropCandidates,
 		DropsByPosition:     dropsByPos,
+		ForcedErrors:        forcedErrors,
 		Warnings:            warnings,
+		GWNote:              gwNote,
 		Notes: []string{
 			"Uses unrostered pool only, status=available (status 'a').",
-			"Eligibility: 60+ mins in each of last 3 GWs OR 60+ mins in at least 10 GWs this season.",
-			"Fixture score uses opponent points conceded by position, split home/away, blended season and recent horizon.",
+			"Eligibility: 60+ mins in each of last 3 GWs OR 60+ mins in at least 10 GWs this season (5 for players whose minutes pattern is \"returning\").",
+			"Fixture score uses opponent points conceded by position, split home/away, blended season and recent horizon; double gameweeks sum both fixtures.",
+			fmt.Sprintf("A fixture with under %d days rest since the team's previous PL kickoff loses %.0f%% of its fixture score (congestion_penalty); cup and European matches aren't in the data.", shortRestDays, congestionPenalty*100),
+			"GK/DEF are scored on the defensive profile: defensive_norm averages team clean-sheet rate, MID/FWD points the team concedes (inverted) and, for GK, saves per 90.",
+			"Suggested drops keep the squad within 2 GK / 5 DEF / 5 MID / 3 FWD.",
+			"urgency is the chance another manager claims the add this cycle: the league's past claim rate for free agents in the add's score decile (shrunk towards 10% while history is thin), raised by a big last GW and by rivals whose weakest position is the add's.",
+			fmt.Sprintf("opponent_risk marks drops at the weakest position (avg pts/GW) of one of your next %d opponents that would outscore their worst player there; suppress_risky_drops steers suggestions to other drops.", lookahead),
 		},
 	}
+	if len(forced) > 0 || len(forcedErrors) > 0 {
+		report.Notes = append(report.Notes, "forced adds come from include_elements/include_players: they are listed after the limit, normalised against the organic pool's ranges (so a norm can fall outside 0-1), and their reasons start with any filter they fail.")
+	}
 	report.Filters.Minutes60Last3 = 3
 	report.Filters.Minutes60Season = 10
+	report.Filters.Minutes60SeasonReturning = returningMinutes60Season
 	report.TargetPosition = targetPosition
 	report.TargetType = targetType
 	report.ConsistencyK = consistencyK
+	report.CongestionPenalty = congestionPenalty
+	report.Model = modelName
+	if cold {
+		report.ColdStart = true
+		report.Notes = append(report.Notes, fmt.Sprintf("Cold start: %d GWs finished, fewer than the %d the 60-minute rule needs, so a player is eligible once they have started a match (any available player before anyone has played), and consistency_label marks the spread as insufficient_data.", max(asOfGW, 0), coldStartGWs))
+	}
+	if strengthFallback {
+		report.Notes = append(report.Notes, "Cold start: no points-conceded history yet, so fixture scores come from bootstrap team strength ratings (GK/DEF against the opponent's attack, MID/FWD against its defence).")
+	}
+	report.Scoring = rules
+	if faab != nil {
+		report.FAAB = faab
+		report.Notes = append(report.Notes, fmt.Sprintf("FAAB: suggested_bid maps the add's weighted-score percentile in the candidate pool onto the league's winning bids for its position (all positions when a position has under %d), capped at remaining budget less faab_reserve.", minTierBids))
+		if len(bids.all) == 0 {
+			report.Notes = append(report.Notes, "FAAB: no winning bids in transactions yet, so no suggested_bid.")
+		}
+	}
+
+	if budget != nil {
+		report.TransactionBudget = budget
+		if budget.Exhausted() {
+			report.Warnings = append(report.Warnings, fmt.Sprintf("Transaction cap: %s, so any claim for GW%d would exceed it.", budget.Warning, targetGW))
+		} else {
+			report.Notes = append(report.Notes, fmt.Sprintf("Transaction cap: each claim uses one move; %s.", budgetRemainingText(budget)))
+			if budget.Warning != "" {
+				report.Warnings = append(report.Warnings, "Transaction cap: "+budget.Warning+".")
+			}
+		}
+	}
+
+	if cfg.WriteDerived && cfg.DerivedRoot != "" {
+		// The log only feeds recommendation_review; losing a line must not
+		// fail the recommendation itself.
+		_ = appendRecoLog(cfg.DerivedRoot, recoLogEntryFor(report, cfg.now()))
+	}
 
 	return json.MarshalIndent(report, "", "  ")
 }
@@ -514,16 +880,23 @@ func loadLeagueSummary(cfg ServerConfig, leagueID int, gw int) (summary.LeagueWe
 }
 
 func loadPlayerFormSummary(cfg ServerConfig, leagueID int, gw int, horizon int) (summary.PlayerFormSummary, error) {
+	out, _, err := loadPlayerFormSummaryWithHash(cfg, leagueID, gw, horizon)
+	return out, err
+}
+
+// loadPlayerFormSummaryWithHash is loadPlayerFormSummary plus the content
+// hash of the file it read.
+func loadPlayerFormSummaryWithHash(cfg ServerConfig, leagueID int, gw int, horizon int) (summary.PlayerFormSummary, string, error) {
 	relPath := fmt.Sprintf("summary/player_form/%d/h%d.json", leagueID, horizon)
-	raw, err := loadSummaryFile(cfg, leagueID, gw, relPath, []int{horizon}, []string{"low", "med", "high"})
+	f, err := loadSummaryFileWithMeta(cfg, leagueID, gw, relPath, []int{horizon}, []string{"low", "med", "high"})
 	if err != nil {
-		return summary.PlayerFormSummary{}, err
+		return summary.PlayerFormSummary{}, "", err
 	}
 	var out summary.PlayerFormSummary
-	if err := json.Unmarshal(raw, &out); err != nil {
-		return summary.PlayerFormSummary{}, err
+	if err := json.Unmarshal(f.Bytes, &out); err != nil {
+		return summary.PlayerFormSummary{}, "", err
 	}
-	return out, nil
+	return out, f.Hash, nil
 }
 
 // resolveRosterGW returns the gameweek to use when loading an entry's roster
@@ -549,30 +922,91 @@ func resolveRosterGW(asOf, target int) int {
 	return rosterGW
 }
 
-func buildOwnershipAndRoster(cfg ServerConfig, leagueID int, entryID int, asOfGW int, elements []elementInfo, teamShort map[int]string) (map[int]bool, []summary.RosterPlayer, error) {
+// loadOwnershipAtGW replays the draft ledger, transactions, and trades to
+// give every entry's roster (entry id -> element ids) as of gw.
+func loadOwnershipAtGW(cfg ServerConfig, leagueID int, gw int) (map[int]map[int]bool, error) {
+	timeline, err := loadOwnershipTimeline(cfg, leagueID)
+	if err != nil {
+		return nil, err
+	}
+	return timeline.at(gw), nil
+}
+
+// ownershipTimeline replays a league's ownership for any GW from a single
+// read of its draft ledger, transactions and trades. Each GW's map is
+// computed once and kept, so walking a season doesn't re-read the files.
+// The derived reconcile.OwnershipTimeline answers instead when it was built
+// from the same moves, so no GW needs a replay from the draft.
+type ownershipTimeline struct {
+	ledger       model.DraftLedger
+	transactions []reconcile.Transaction
+	trades       []reconcile.Trade
+	derived      *reconcile.OwnershipTimeline
+	byGW         map[int]map[int]map[int]bool
+}
+
+func loadOwnershipTimeline(cfg ServerConfig, leagueID int) (*ownershipTimeline, error) {
 	st := store.NewJSONStore(cfg.RawRoot)
-	if err := ensureLedger(st, cfg.DerivedRoot, leagueID); err != nil {
-		return nil, nil, err
+	if err := ensureLedger(st, cfg.DerivedRoot, leagueID, cfg.Clock); err != nil {
+		return nil, err
 	}
 	ledgerPath := filepath.Join(cfg.DerivedRoot, fmt.Sprintf("ledger/%d/event_0.json", leagueID))
-	raw, err := os.ReadFile(ledgerPath)
+	raw, err := store.ReadDerived(ledgerPath)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
-	var ledgerOut model.DraftLedger
-	if err := json.Unmarshal(raw, &ledgerOut); err != nil {
-		return nil, nil, err
+	out := &ownershipTimeline{byGW: make(map[int]map[int]map[int]bool)}
+	if err := json.Unmarshal(raw, &out.ledger); err != nil {
+		return nil, err
 	}
-	transactions, err := loadTransactionsRaw(st, leagueID)
-	if err != nil {
-		return nil, nil, err
+	if out.transactions, err = loadTransactionsRaw(st, leagueID); err != nil {
+		return nil, err
 	}
-	trades, err := loadTradesRaw(st, leagueID)
-	if err != nil {
-		return nil, nil, err
+	if out.trades, err = loadTradesRaw(st, leagueID); err != nil {
+		return nil, err
 	}
+	if derived, err := reconcile.LoadOwnershipTimeline(cfg.DerivedRoot, leagueID); err == nil && derived.Current(out.transactions, out.trades) {
+		out.derived = derived
+	}
+	return out, nil
+}
+
+// at returns every entry's roster as of gw. Callers must not modify it.
+func (o *ownershipTimeline) at(gw int) map[int]map[int]bool {
+	if m, ok := o.byGW[gw]; ok {
+		return m
+	}
+	var m map[int]map[int]bool
+	if o.derived != nil {
+		m = o.derived.OwnersAt(gw)
+	} else {
+		m = reconcile.BuildOwnershipMapAtGW(&o.ledger, o.transactions, o.trades, gw)
+	}
+	o.byGW[gw] = m
+	return m
+}
 
-	ownership := reconcile.BuildOwnershipMapAtGW(&ledgerOut, transactions, trades, asOfGW)
+// ownerAt returns the entry holding element at gw, or 0 when it is a free
+// agent.
+func (o *ownershipTimeline) ownerAt(gw int, element int) int {
+	if o.derived != nil {
+		return o.derived.OwnerAt(element, gw)
+	}
+	for entryID, roster := range o.at(gw) {
+		if roster[element] {
+			return entryID
+		}
+	}
+	return 0
+}
+
+// buildOwnershipAndRoster returns the league's ownership map at asOfGW, the
+// set of rostered elements, and entryID's roster.
+func buildOwnershipAndRoster(cfg ServerConfig, leagueID int, entryID int, asOfGW int, elements []elementInfo, teamShort map[int]string) (map[int]map[int]bool, map[int]bool, []summary.RosterPlayer, error) {
+	ownership, err := loadOwnershipAtGW(cfg, leagueID, asOfGW)
+	if err != nil {
+		return nil, nil, nil, err
+	}
 	owned := make(map[int]bool)
 	for _, roster := range ownership {
 		for elementID := range roster {
@@ -608,7 +1042,7 @@ func buildOwnershipAndRoster(cfg ServerConfig, leagueID int, entryID int, asOfGW
 		}
 		return roster[i].Name < roster[j].Name
 	})
-	return owned, roster, nil
+	return ownership, owned, roster, nil
 }
 
 func buildEverOwners(cfg ServerConfig, leagueID int) (map[int][]string, error) {
@@ -622,11 +1056,11 @@ func buildEverOwners(cfg ServerConfig, leagueID int) (map[int][]string, error) {
 		entryNameByID[e.EntryID] = e.EntryName
 	}
 
-	if err := ensureLedger(st, cfg.DerivedRoot, leagueID); err != nil {
+	if err := ensureLedger(st, cfg.DerivedRoot, leagueID, cfg.Clock); err != nil {
 		return nil, err
 	}
 	ledgerPath := filepath.Join(cfg.DerivedRoot, fmt.Sprintf("ledger/%d/event_0.json", leagueID))
-	raw, err := os.ReadFile(ledgerPath)
+	raw, err := store.ReadDerived(ledgerPath)
 	if err != nil {
 		return nil, err
 	}
@@ -712,23 +1146,19 @@ func loadBootstrapData(rawRoot string) ([]elementInfo, map[int]string, map[int][
 	}
 	var resp struct {
 		Elements []struct {
-			ID          int    `json:"id"`
-			WebName     string `json:"web_name"`
-			Team        int    `json:"team"`
-			ElementType int    `json:"element_type"`
-			Status      string `json:"status"`
-			TotalPoints int    `json:"total_points"`
+			ID          int              `json:"id"`
+			WebName     string           `json:"web_name"`
+			Team        int              `json:"team"`
+			ElementType int              `json:"element_type"`
+			Status      string           `json:"status"`
+			TotalPoints jsonutil.FlexInt `json:"total_points"`
+			Minutes     jsonutil.FlexInt `json:"minutes"`
+			Starts      jsonutil.FlexInt `json:"starts"`
 		} `json:"elements"`
 		Teams []struct {
 			ID        int    `json:"id"`
 			ShortName string `json:"short_name"`
 		} `json:"teams"`
-		Fixtures map[string][]struct {
-			ID    int `json:"id"`
-			Event int `json:"event"`
-			TeamH int `json:"team_h"`
-			TeamA int `json:"team_a"`
-		} `json:"fixtures"`
 	}
 	if err := json.Unmarshal(raw, &resp); err != nil {
 		return nil, nil, nil, err
@@ -747,26 +1177,27 @@ func loadBootstrapData(rawRoot string) ([]elementInfo, map[int]string, map[int][
 			TeamID:       e.Team,
 			PositionType: e.ElementType,
 			Status:       e.Status,
-			TotalPoints:  e.TotalPoints,
+			TotalPoints:  int(e.TotalPoints),
+			Minutes:      int(e.Minutes),
+			Starts:       int(e.Starts),
 		})
 	}
 
-	fixtures := make(map[int][]fixture)
-	for k, list := range resp.Fixtures {
-		gw, err := strconv.Atoi(k)
-		if err != nil {
-			continue
-		}
-		for _, f := range list {
-			fixtures[gw] = append(fixtures[gw], fixture{
-				ID:    f.ID,
-				Event: gw,
-				TeamH: f.TeamH,
-				TeamA: f.TeamA,
-			})
-		}
+	set, err := fixtures.Parse(raw)
+	if err != nil {
+		return nil, nil, nil, err
 	}
-	return elements, teams, fixtures, nil
+	return elements, teams, set.ByEvent(), nil
+}
+
+// bootstrapFixturesWarning is fixtures.MissingWarning when loadBootstrapData
+// found no fixtures at all, so fixture scores read as neutral rather than
+// silently as zero.
+func bootstrapFixturesWarning(byGW map[int][]fixture) string {
+	if len(byGW) > 0 {
+		return ""
+	}
+	return fixtures.MissingWarning
 }
 
 func loadTransactionsRaw(st *store.JSONStore, leagueID int) ([]reconcile.Transaction, error) {
@@ -793,10 +1224,26 @@ func loadTradesRaw(st *store.JSONStore, leagueID int) ([]reconcile.Trade, error)
 	return resp.Trades, nil
 }
 
+// sumFixtureScores adds up the season, recent, and blended fixture scores over
+// every fixture a team has in the target GW. Summing (not averaging) is what
+// gives a double-gameweek player credit for two chances to score. Only the
+// blended score carries a fixture's congestion penalty.
+func sumFixtureScores(fixtures []FixtureContext, concededSeason map[int]map[string]map[int]avgStat, concededRecent map[int]map[string]map[int]avgStat, pos int, seasonWeight float64, recentWeight float64) (float64, float64, float64) {
+	var season, recent, blended float64
+	for _, fx := range fixtures {
+		s, r, b := blendedFixtureScore(concededSeason, concededRecent, fx.OpponentID, fx.Venue, pos, seasonWeight, recentWeight)
+		season += s
+		recent += r
+		blended += b * congestionFactor(fx)
+	}
+	return season, recent, blended
+}
+
 // buildFixtureIndex maps each team ID to all its fixtures in the given list.
 // In a normal gameweek every team has exactly one entry; in a double gameweek
 // (DGW) a team may appear twice and both fixtures are retained.  Callers must
-// average scores across all fixtures when a team has more than one.
+// sum scores across all fixtures when a team has more than one (see
+// sumFixtureScores).
 func buildFixtureIndex(fixtures []fixture, teamShort map[int]string) map[int][]FixtureContext {
 	out := make(map[int][]FixtureContext)
 	for _, f := range fixtures {
@@ -824,7 +1271,105 @@ func buildFixtureIndex(fixtures []fixture, teamShort map[int]string) map[int][]F
 	return out
 }
 
-func computeAvailabilityAndXG(rawRoot string, elements []elementInfo, asOfGW int, horizon int) (map[int]int, map[int]int, map[int]float64, error) {
+// minutesEligible applies the waiver availability rule: 60+ minutes in each
+// of the last 3 GWs or in 10+ GWs this season. A player back from a ban or
+// injury fails the last-3 rule through no fault of their role, so a
+// "returning" pattern only needs returningMinutes60Season.
+// resolveForcedCandidates resolves include_elements and include_players to
+// the free agents to force into the report, in request order without
+// repeats. Unknown players and players on a roster come back as errors
+// rather than candidates: an owned player can't be added, whatever his score.
+func resolveForcedCandidates(elements []elementInfo, ids *[]int, names *[]string, ownership map[int]map[int]bool, entryID int, entries []summary.LeagueEntry) ([]elementInfo, []ForcedCandidateError) {
+	type request struct {
+		input string
+		info  elementInfo
+		err   error
+	}
+	var requests []request
+	if ids != nil {
+		for _, id := range *ids {
+			info, err := resolvePlayer(elements, &id, nil)
+			requests = append(requests, request{input: strconv.Itoa(id), info: info, err: err})
+		}
+	}
+	if names != nil {
+		for _, name := range *names {
+			info, err := resolvePlayer(elements, nil, &name)
+			requests = append(requests, request{input: name, info: info, err: err})
+		}
+	}
+
+	entryNames := make(map[int]string, len(entries))
+	for _, e := range entries {
+		entryNames[e.EntryID] = e.EntryName
+	}
+	var out []elementInfo
+	var errs []ForcedCandidateError
+	seen := make(map[int]bool)
+	for _, r := range requests {
+		if r.err != nil {
+			errs = append(errs, ForcedCandidateError{Input: r.input, Error: r.err.Error()})
+			continue
+		}
+		if seen[r.info.ID] {
+			continue
+		}
+		seen[r.info.ID] = true
+		owners := make([]int, 0, 1)
+		for owner, roster := range ownership {
+			if roster[r.info.ID] {
+				owners = append(owners, owner)
+			}
+		}
+		if len(owners) > 0 {
+			sort.Ints(owners)
+			owner := entryNames[owners[0]]
+			if owner == "" {
+				owner = fmt.Sprintf("entry %d", owners[0])
+			}
+			msg := fmt.Sprintf("owned by %s; only free agents can be added", owner)
+			if owners[0] == entryID {
+				msg = "already on your roster; only free agents can be added"
+			}
+			errs = append(errs, ForcedCandidateError{Input: r.input, Element: r.info.ID, Name: r.info.Name, Error: msg})
+			continue
+		}
+		out = append(out, r.info)
+	}
+	return out, errs
+}
+
+// forcedFilterFailures spells out each filter that keeps info out of the
+// organic candidate pool.
+func forcedFilterFailures(info elementInfo, avail AvailabilityInfo, pattern string, targetPosition int, targetGW int, fixtureCount int) []string {
+	var out []string
+	if info.Status != "a" {
+		out = append(out, fmt.Sprintf("fails availability: status %q, not 'a'", info.Status))
+	}
+	if targetPosition != 0 && info.PositionType != targetPosition {
+		out = append(out, fmt.Sprintf("outside target_position: %s, not %s", positionLabel(info.PositionType), positionLabel(targetPosition)))
+	}
+	if !avail.ColdStartEligible && !minutesEligible(avail.Minutes60Last3, avail.Minutes60Season, pattern) {
+		seasonNeed := 10
+		if pattern == summary.MinutesReturning {
+			seasonNeed = returningMinutes60Season
+		}
+		out = append(out, fmt.Sprintf("fails 60-min eligibility: %d of last 3, %d this season (needs 3 of 3 or %d)", avail.Minutes60Last3, avail.Minutes60Season, seasonNeed))
+	}
+	if fixtureCount == 0 {
+		out = append(out, fmt.Sprintf("no fixture in GW %d", targetGW))
+	}
+	return out
+}
+
+func minutesEligible(last3 int, season int, pattern string) bool {
+	if last3 >= 3 || season >= 10 {
+		return true
+	}
+	return pattern == summary.MinutesReturning && season >= returningMinutes60Season
+}
+
+func computeAvailabilityAndXG(rawRoot string, elements []elementInfo, startGW int, asOfGW int, horizon int) (map[int]int, map[int]int, map[int]float64, error) {
 	season60 := make(map[int]int)
 	last3 := make(map[int]int)
 	xg := make(map[int]float64)
@@ -834,6 +1379,8 @@ func computeAvailabilityAndXG(rawRoot string, elements []elementInfo, asOfGW int
 	if startH < 1 {
 		startH = 1
 	}
+	// The season count starts with the league; the last-3 rule and xG don't
+	// depend on when the league began.
 	for gw := 1; gw <= asOfGW; gw++ {
 		live, err := loadLiveStats(rawRoot, gw)
 		if err != nil {
@@ -841,7 +1388,9 @@ func computeAvailabilityAndXG(rawRoot string, elements []elementInfo, asOfGW int
 		}
 		for id, stats := range live {
 			if stats.Minutes >= 60 {
-				season60[id]++
+				if gw >= startGW {
+					season60[id]++
+				}
 				if gw >= asOfGW-2 {
 					last3[id]++
 				}
@@ -860,6 +1409,128 @@ func computeAvailabilityAndXG(rawRoot string, elements []elementInfo, asOfGW int
 	return season60, last3, xg, nil
 }
 
+// computeXAAndBonus returns expected assists per 90 minutes and average bonus
+// points per gameweek over the rolling horizon ending at asOfGW. Bonus is
+// averaged over the GWs in which the player appears in the live data.
+func computeXAAndBonus(rawRoot string, asOfGW int, horizon int) (map[int]float64, map[int]float64) {
+	xa := make(map[int]float64)
+	bonus := make(map[int]float64)
+	if asOfGW < 1 {
+		return xa, bonus
+	}
+	start := asOfGW - horizon + 1
+	if start < 1 {
+		start = 1
+	}
+	minutes := make(map[int]int)
+	appearances := make(map[int]int)
+	for gw := start; gw <= asOfGW; gw++ {
+		live, err := loadLiveStats(rawRoot, gw)
+		if err != nil {
+			continue
+		}
+		for id, stats := range live {
+			xa[id] += stats.XA
+			minutes[id] += stats.Minutes
+			bonus[id] += float64(stats.Bonus)
+			appearances[id]++
+		}
+	}
+	for id := range xa {
+		if minutes[id] > 0 {
+			xa[id] = (xa[id] / float64(minutes[id])) * 90
+		} else {
+			xa[id] = 0
+		}
+	}
+	for id, n := range appearances {
+		bonus[id] /= float64(n)
+	}
+	return xa, bonus
+}
+
+// defensiveStats holds the horizon inputs to the defensive scoring profile.
+type defensiveStats struct {
+	savesPer90     map[int]float64 // by element
+	cleanSheetRate map[int]float64 // by team: share of fixtures kept clean
+}
+
+// computeDefensiveStats returns saves per 90 minutes for each element and the
+// clean-sheet rate of each team over the rolling horizon ending at asOfGW. A
+// team kept a clean sheet in a fixture when any of its players was credited
+// with one, so a double gameweek can contribute two.
+func computeDefensiveStats(rawRoot string, elements []elementInfo, asOfGW int, horizon int) defensiveStats {
+	out := defensiveStats{savesPer90: make(map[int]float64), cleanSheetRate: make(map[int]float64)}
+	if asOfGW < 1 {
+		return out
+	}
+	elementTeam := make(map[int]int, len(elements))
+	for _, e := range elements {
+		elementTeam[e.ID] = e.TeamID
+	}
+	start := asOfGW - horizon + 1
+	if start < 1 {
+		start = 1
+	}
+	minutes := make(map[int]int)
+	cleanSheets := make(map[int]int)
+	fixtures := make(map[int]int)
+	for gw := start; gw <= asOfGW; gw++ {
+		gwData, err := loadLiveGWData(rawRoot, gw)
+		if err != nil {
+			continue
+		}
+		teamCS := make(map[int]int)
+		for id, stats := range gwData.Stats {
+			out.savesPer90[id] += float64(stats.Saves)
+			minutes[id] += stats.Minutes
+			if team := elementTeam[id]; team != 0 && stats.CleanSheets > teamCS[team] {
+				teamCS[team] = stats.CleanSheets
+			}
+		}
+		for _, f := range gwData.Fixtures {
+			fixtures[f.TeamH]++
+			fixtures[f.TeamA]++
+		}
+		for team, cs := range teamCS {
+			cleanSheets[team] += cs
+		}
+	}
+	for id := range out.savesPer90 {
+		if minutes[id] > 0 {
+			out.savesPer90[id] = out.savesPer90[id] / float64(minutes[id]) * 90
+		} else {
+			out.savesPer90[id] = 0
+		}
+	}
+	for team, n := range fixtures {
+		out.cleanSheetRate[team] = math.Min(1, float64(cleanSheets[team])/float64(n))
+	}
+	return out
+}
+
+// fill sets the defensive raw components of s for a player with the given
+// target-GW fixtures. AttackConceded reads the conceded tables with the
+// player's own team in the opponent slot: what that team lets opposing
+// midfielders and forwards score, averaged across its fixtures.
+func (d defensiveStats) fill(s *ScoreComponents, info elementInfo, fixtures []FixtureContext, concededSeason map[int]map[string]map[int]avgStat, concededRecent map[int]map[string]map[int]avgStat, seasonWeight float64, recentWeight float64) {
+	if info.PositionType == 1 {
+		s.SavesPer90 = d.savesPer90[info.ID]
+	}
+	s.CleanSheetRate = d.cleanSheetRate[info.TeamID]
+	if len(fixtures) == 0 {
+		return
+	}
+	var conceded float64
+	for _, fx := range fixtures {
+		for _, pos := range []int{3, 4} {
+			_, _, b := blendedFixtureScore(concededSeason, concededRecent, info.TeamID, fx.Venue, pos, seasonWeight, recentWeight)
+			conceded += b
+		}
+	}
+	s.AttackConceded = conceded / float64(len(fixtures))
+}
+
 func computeConsistencyStats(rawRoot string, elements []elementInfo, asOfGW int, horizon int) (map[int]float64, map[int]float64, error) {
 	if asOfGW < 1 {
 		return map[int]float64{}, map[int]float64{}, nil
@@ -918,115 +1589,50 @@ func computeConsistencyStats(rawRoot string, elements []elementInfo, asOfGW int,
 	return avg, stddev, nil
 }
 
-func loadLiveStats(rawRoot string, gw int) (map[int]liveStats, error) {
-	path := filepath.Join(rawRoot, "gw", strconv.Itoa(gw), "live.json")
-	raw, err := os.ReadFile(path)
+// loadLiveStats returns per-element stats for gw from the shared livestats
+// cache.
+func loadLiveStats(rawRoot string, gw int) (map[int]livestats.ElementStats, error) {
+	data, err := livestats.LoadGW(store.NewJSONStore(rawRoot), gw)
 	if err != nil {
 		return nil, err
 	}
-	dec := json.NewDecoder(bytes.NewReader(raw))
-	dec.UseNumber()
-	var resp struct {
-		Elements map[string]struct {
-			Stats map[string]any `json:"stats"`
-		} `json:"elements"`
-	}
-	if err := dec.Decode(&resp); err != nil {
-		return nil, err
-	}
-	out := make(map[int]liveStats, len(resp.Elements))
-	for k, v := range resp.Elements {
-		id, err := strconv.Atoi(k)
-		if err != nil {
-			continue
-		}
-		minutes := int(asNumber(v.Stats["minutes"]))
-		total := int(asNumber(v.Stats["total_points"]))
-		xg := asFloat(v.Stats["expected_goals"])
-		out[id] = liveStats{
-			Minutes:     minutes,
-			TotalPoints: total,
-			XG:          xg,
-		}
-	}
-	return out, nil
-}
-
-func asNumber(v any) float64 {
-	switch t := v.(type) {
-	case json.Number:
-		f, _ := t.Float64()
-		return f
-	case float64:
-		return t
-	case string:
-		f, _ := strconv.ParseFloat(t, 64)
-		return f
-	default:
-		return 0
-	}
-}
-
-func asFloat(v any) float64 {
-	return asNumber(v)
+	return data.Elements, nil
 }
 
 // liveGWData holds the element stats and fixtures decoded from a single
 // gw/N/live.json read, avoiding a second file-open for callers that need both.
 type liveGWData struct {
-	Stats    map[int]liveStats
+	Stats    map[int]livestats.ElementStats
 	Fixtures []fixture
 }
 
-// loadLiveGWData reads gw/N/live.json once and returns both element stats and
-// fixture pairings. Use this instead of calling loadLiveStats and
-// loadFixturesFromLive separately inside the same loop iteration.
+// loadLiveGWData returns both element stats and fixture pairings for gw.
+// Use this instead of calling loadLiveStats and loadFixturesFromLive
+// separately inside the same loop iteration.
 func loadLiveGWData(rawRoot string, gw int) (liveGWData, error) {
-	path := filepath.Join(rawRoot, "gw", strconv.Itoa(gw), "live.json")
-	raw, err := os.ReadFile(path)
+	data, err := livestats.LoadGW(store.NewJSONStore(rawRoot), gw)
 	if err != nil {
 		return liveGWData{}, err
 	}
-	dec := json.NewDecoder(bytes.NewReader(raw))
-	dec.UseNumber() // required: elements are decoded into map[string]any
-	var resp struct {
-		Elements map[string]struct {
-			Stats map[string]any `json:"stats"`
-		} `json:"elements"`
-		Fixtures []struct {
-			ID    int `json:"id"`
-			TeamH int `json:"team_h"`
-			TeamA int `json:"team_a"`
-		} `json:"fixtures"`
-	}
-	if err := dec.Decode(&resp); err != nil {
-		return liveGWData{}, err
-	}
-
-	stats := make(map[int]liveStats, len(resp.Elements))
-	for k, v := range resp.Elements {
-		id, err := strconv.Atoi(k)
-		if err != nil {
-			continue
+	schedule := loadFixtureSchedule(rawRoot)
+	fixtures := make([]fixture, 0, len(data.Fixtures))
+	for _, f := range data.Fixtures {
+		fx := fixture{
+			ID:      f.ID,
+			Event:   f.Event,
+			TeamH:   f.TeamH,
+			TeamA:   f.TeamA,
+			Kickoff: f.KickoffTime,
 		}
-		stats[id] = liveStats{
-			Minutes:     int(asNumber(v.Stats["minutes"])),
-			TotalPoints: int(asNumber(v.Stats["total_points"])),
-			XG:          asFloat(v.Stats["expected_goals"]),
+		// A fixture postponed after the file was fetched wasn't played in
+		// gw; its teams blank there.
+		if schedule.movedFrom(fx, gw) {
+			continue
 		}
+		fx.Event = gw
+		fixtures = append(fixtures, fx)
 	}
-
-	fixtures := make([]fixture, 0, len(resp.Fixtures))
-	for _, f := range resp.Fixtures {
-		fixtures = append(fixtures, fixture{
-			ID:    f.ID,
-			Event: gw,
-			TeamH: f.TeamH,
-			TeamA: f.TeamA,
-		})
-	}
-
-	return liveGWData{Stats: stats, Fixtures: fixtures}, nil
+	return liveGWData{Stats: data.Elements, Fixtures: fixtures}, nil
 }
 
 // loadFixturesFromLive loads the fixtures array embedded in gw/N/live.json.
@@ -1045,6 +1651,14 @@ func loadFixturesFromLive(rawRoot string, gw int) ([]fixture, error) {
 // gw/N/live.json rather than bootstrap-static.json because the bootstrap
 // only contains upcoming GW fixtures and lacks historical data.
 func computePointsConcededByPosition(rawRoot string, elements []elementInfo, asOfGW int, horizon int) map[int]map[string]map[int]avgStat {
+	conceded, _ := computeTeamPointsByPosition(rawRoot, elements, asOfGW, horizon)
+	return conceded
+}
+
+// computeTeamPointsByPosition is computePointsConcededByPosition with the
+// attacking side of the same fixtures: scored[team][venue][pos] is what the
+// team's own players at pos scored in its games at venue.
+func computeTeamPointsByPosition(rawRoot string, elements []elementInfo, asOfGW int, horizon int) (conceded, scored map[int]map[string]map[int]avgStat) {
 	elementTeam := make(map[int]int, len(elements))
 	elementPos := make(map[int]int, len(elements))
 	for _, e := range elements {
@@ -1056,13 +1670,35 @@ func computePointsConcededByPosition(rawRoot string, elements []elementInfo, asO
 	if start < 1 {
 		start = 1
 	}
-	conceded := make(map[int]map[string]map[int]avgStat)
+	// A fixture played after a postponement can still sit in the stale
+	// live.json of its original GW, so each fixture id counts once, in the
+	// latest GW listing it.
+	byGW := make(map[int]liveGWData)
+	latest := make(map[int]int)
 	for gw := start; gw <= asOfGW; gw++ {
 		// Single file read supplies both element stats and fixture pairings.
 		gwData, err := loadLiveGWData(rawRoot, gw)
 		if err != nil {
 			continue
 		}
+		byGW[gw] = gwData
+		for _, f := range gwData.Fixtures {
+			latest[f.ID] = gw
+		}
+	}
+	conceded = make(map[int]map[string]map[int]avgStat)
+	scored = make(map[int]map[string]map[int]avgStat)
+	for gw := start; gw <= asOfGW; gw++ {
+		gwData, ok := byGW[gw]
+		if !ok {
+			continue
+		}
+		played := make([]fixture, 0, len(gwData.Fixtures))
+		for _, f := range gwData.Fixtures {
+			if latest[f.ID] == gw {
+				played = append(played, f)
+			}
+		}
 		pointsByTeamPos := make(map[int]map[int]int)
 		for id, stats := range gwData.Stats {
 			team := elementTeam[id]
@@ -1076,26 +1712,38 @@ func computePointsConcededByPosition(rawRoot string, elements []elementInfo, asO
 			pointsByTeamPos[team][pos] += stats.TotalPoints
 		}
 
-		for _, f := range gwData.Fixtures {
+		// Live points are GW totals, so a team in a double gameweek has its
+		// total split evenly across its fixtures rather than charged in full
+		// to both opponents.
+		fixturesByTeam := make(map[int]int)
+		for _, f := range played {
+			fixturesByTeam[f.TeamH]++
+			fixturesByTeam[f.TeamA]++
+		}
+		for _, f := range played {
 			home := f.TeamH
 			away := f.TeamA
 			homePts := pointsByTeamPos[home]
 			awayPts := pointsByTeamPos[away]
 
 			for pos, pts := range awayPts {
-				addConceded(conceded, home, "HOME", pos, float64(pts))
+				share := float64(pts) / float64(fixturesByTeam[away])
+				addConceded(conceded, home, "HOME", pos, share)
+				addConceded(scored, away, "AWAY", pos, share)
 			}
 			for pos, pts := range homePts {
-				addConceded(conceded, away, "AWAY", pos, float64(pts))
+				share := float64(pts) / float64(fixturesByTeam[home])
+				addConceded(conceded, away, "AWAY", pos, share)
+				addConceded(scored, home, "HOME", pos, share)
 			}
 		}
 	}
-	return conceded
+	return conceded, scored
 }
 
 type avgStat struct {
-	Sum   float64
-	Count int
+	Sum   float64 `json:"sum"`
+	Count int     `json:"count"`
 }
 
 func addConceded(store map[int]map[string]map[int]avgStat, teamID int, venue string, pos int, val float64) {
@@ -1133,6 +1781,24 @@ type scoreMinMax struct {
 	FormMin, FormMax   float64
 	TotalMin, TotalMax float64
 	XGMin, XGMax       float64
+	XAMin, XAMax       float64
+	BonusMin, BonusMax float64
+	// Defensive ranges cover defensive-profile players only, and saves only
+	// goalkeepers.
+	SavesMin, SavesMax       float64
+	CSMin, CSMax             float64
+	ConcededMin, ConcededMax float64
+}
+
+// defensiveNorm averages the normalized clean-sheet rate, inverted attack
+// conceded and, for goalkeepers, saves per 90.
+func defensiveNorm(pos int, s ScoreComponents, mm scoreMinMax) float64 {
+	sum := minMax(s.CleanSheetRate, mm.CSMin, mm.CSMax) +
+		minMax(-s.AttackConceded, -mm.ConcededMax, -mm.ConcededMin)
+	if pos == 1 {
+		return (sum + minMax(s.SavesPer90, mm.SavesMin, mm.SavesMax)) / 3
+	}
+	return sum / 2
 }
 
 func normalizeScores(players []scoredPlayer) scoreMinMax {
@@ -1140,6 +1806,11 @@ func normalizeScores(players []scoredPlayer) scoreMinMax {
 	var minForm, maxForm = math.Inf(1), math.Inf(-1)
 	var minTotal, maxTotal = math.Inf(1), math.Inf(-1)
 	var minXG, maxXG = math.Inf(1), math.Inf(-1)
+	var minXA, maxXA = math.Inf(1), math.Inf(-1)
+	var minBonus, maxBonus = math.Inf(1), math.Inf(-1)
+	var minSaves, maxSaves = math.Inf(1), math.Inf(-1)
+	var minCS, maxCS = math.Inf(1), math.Inf(-1)
+	var minConceded, maxConceded = math.Inf(1), math.Inf(-1)
 	for _, p := range players {
 		minFix = math.Min(minFix, p.score.FixturesRaw)
 		maxFix = math.Max(maxFix, p.score.FixturesRaw)
@@ -1149,18 +1820,49 @@ func normalizeScores(players []scoredPlayer) scoreMinMax {
 		maxTotal = math.Max(maxTotal, p.score.TotalRaw)
 		minXG = math.Min(minXG, p.score.XGRaw)
 		maxXG = math.Max(maxXG, p.score.XGRaw)
+		minXA = math.Min(minXA, p.score.XARaw)
+		maxXA = math.Max(maxXA, p.score.XARaw)
+		minBonus = math.Min(minBonus, p.score.BonusRaw)
+		maxBonus = math.Max(maxBonus, p.score.BonusRaw)
+		if p.score.Profile == profileDefensive {
+			minCS = math.Min(minCS, p.score.CleanSheetRate)
+			maxCS = math.Max(maxCS, p.score.CleanSheetRate)
+			minConceded = math.Min(minConceded, p.score.AttackConceded)
+			maxConceded = math.Max(maxConceded, p.score.AttackConceded)
+			if p.info.PositionType == 1 {
+				minSaves = math.Min(minSaves, p.score.SavesPer90)
+				maxSaves = math.Max(maxSaves, p.score.SavesPer90)
+			}
+		}
 	}
-	for i := range players {
-		players[i].score.FixturesNorm = minMax(players[i].score.FixturesRaw, minFix, maxFix)
-		players[i].score.FormNorm = minMax(players[i].score.FormRaw, minForm, maxForm)
-		players[i].score.TotalNorm = minMax(players[i].score.TotalRaw, minTotal, maxTotal)
-		players[i].score.XGNorm = minMax(players[i].score.XGRaw, minXG, maxXG)
-	}
-	return scoreMinMax{
+	mm := scoreMinMax{
 		FixMin: minFix, FixMax: maxFix,
 		FormMin: minForm, FormMax: maxForm,
 		TotalMin: minTotal, TotalMax: maxTotal,
 		XGMin: minXG, XGMax: maxXG,
+		XAMin: minXA, XAMax: maxXA,
+		BonusMin: minBonus, BonusMax: maxBonus,
+		SavesMin: minSaves, SavesMax: maxSaves,
+		CSMin: minCS, CSMax: maxCS,
+		ConcededMin: minConceded, ConcededMax: maxConceded,
+	}
+	for i := range players {
+		mm.apply(&players[i])
+	}
+	return mm
+}
+
+// apply sets p's normalised components from mm's ranges. A player outside
+// the pool mm came from can land outside [0, 1].
+func (mm scoreMinMax) apply(p *scoredPlayer) {
+	p.score.FixturesNorm = minMax(p.score.FixturesRaw, mm.FixMin, mm.FixMax)
+	p.score.FormNorm = minMax(p.score.FormRaw, mm.FormMin, mm.FormMax)
+	p.score.TotalNorm = minMax(p.score.TotalRaw, mm.TotalMin, mm.TotalMax)
+	p.score.XGNorm = minMax(p.score.XGRaw, mm.XGMin, mm.XGMax)
+	p.score.XANorm = minMax(p.score.XARaw, mm.XAMin, mm.XAMax)
+	p.score.BonusNorm = minMax(p.score.BonusRaw, mm.BonusMin, mm.BonusMax)
+	if p.score.Profile == profileDefensive {
+		p.score.DefensiveNorm = defensiveNorm(p.info.PositionType, p.score, mm)
 	}
 }
 
@@ -1171,7 +1873,7 @@ func minMax(v, min, max float64) float64 {
 	return (v - min) / (max - min)
 }
 
-func scoreRoster(elements []elementInfo, teamShort map[int]string, form map[int]summary.PlayerForm, xg map[int]float64, fixtures map[int][]FixtureContext, roster []summary.RosterPlayer, concededSeason map[int]map[string]map[int]avgStat, concededRecent map[int]map[string]map[int]avgStat, seasonWeight float64, recentWeight float64, minmax scoreMinMax, wFix, wForm, wTotal, wXG float64) []DropRecommendation {
+func scoreRoster(elements []elementInfo, teamShort map[int]string, form map[int]summary.PlayerForm, xg map[int]float64, xa map[int]float64, bonus map[int]float64, defensive defensiveStats, fixtures map[int][]FixtureContext, roster []summary.RosterPlayer, concededSeason map[int]map[string]map[int]avgStat, concededRecent map[int]map[string]map[int]avgStat, seasonWeight float64, recentWeight float64, minmax scoreMinMax, weights scoreWeights) []DropRecommendation {
 	elementByID := make(map[int]elementInfo, len(elements))
 	for _, e := range elements {
 		elementByID[e.ID] = e
@@ -1190,16 +1892,23 @@ func scoreRoster(elements []elementInfo, teamShort map[int]string, form map[int]
 		var totalBlended float64
 		for _, fx := range teamFixtures {
 			_, _, b := blendedFixtureScore(concededSeason, concededRecent, fx.OpponentID, fx.Venue, info.PositionType, seasonWeight, recentWeight)
-			totalBlended += b
+			totalBlended += b * congestionFactor(fx)
 		}
 		blended := totalBlended / float64(len(teamFixtures))
-		formScore := form[info.ID].PointsPerGW
-		totalScore := float64(info.TotalPoints)
-		xgScore := xg[info.ID]
-		weighted := wFix*minMax(blended, minmax.FixMin, minmax.FixMax) +
-			wForm*minMax(formScore, minmax.FormMin, minmax.FormMax) +
-			wTotal*minMax(totalScore, minmax.TotalMin, minmax.TotalMax) +
-			wXG*minMax(xgScore, minmax.XGMin, minmax.XGMax)
+		score := ScoreComponents{
+			Profile:      scoringProfile(info.PositionType),
+			FixturesNorm: minMax(blended, minmax.FixMin, minmax.FixMax),
+			FormNorm:     minMax(form[info.ID].PointsPerGW, minmax.FormMin, minmax.FormMax),
+			TotalNorm:    minMax(float64(info.TotalPoints), minmax.TotalMin, minmax.TotalMax),
+			XGNorm:       minMax(xg[info.ID], minmax.XGMin, minmax.XGMax),
+			XANorm:       minMax(xa[info.ID], minmax.XAMin, minmax.XAMax),
+			BonusNorm:    minMax(bonus[info.ID], minmax.BonusMin, minmax.BonusMax),
+		}
+		if score.Profile == profileDefensive {
+			defensive.fill(&score, info, teamFixtures, concededSeason, concededRecent, seasonWeight, recentWeight)
+			score.DefensiveNorm = defensiveNorm(info.PositionType, score, minmax)
+		}
+		weighted := weightedScore(weights, score)
 		drops = append(drops, DropRecommendation{
 			Element:      info.ID,
 			Name:         info.Name,
@@ -1214,7 +1923,10 @@ func scoreRoster(elements []elementInfo, teamShort map[int]string, form map[int]
 	return drops
 }
 
-func pickDropCandidatesByPosition(drops []DropRecommendation, undroppable map[int]bool, adds []scoredPlayer, targetPos int) (map[string][]DropRecommendation, []string) {
+// pickDropCandidatesByPosition picks the lowest-scoring droppable player at
+// each position. With suppressRisky, a player carrying OpponentRisk is
+// passed over for the next-lowest one that doesn't.
+func pickDropCandidatesByPosition(drops []DropRecommendation, undroppable map[int]bool, adds []scoredPlayer, targetPos int, suppressRisky bool) (map[string][]DropRecommendation, []string) {
 	bestAddByPos := make(map[int]float64)
 	for _, a := range adds {
 		if a.score.WeightedScore > bestAddByPos[a.info.PositionType] {
@@ -1252,6 +1964,13 @@ func pickDropCandidatesByPosition(drops []DropRecommendation, undroppable map[in
 		totalDroppable += len(posDrops)
 
 		pick := posDrops[0]
+		reason := "Lowest weighted score at position"
+		if suppressRisky && pick.OpponentRisk != nil {
+			if safe := withoutOpponentRisk(posDrops); len(safe) > 0 {
+				reason = fmt.Sprintf("Lowest weighted score at position; %s kept back from %s", pick.Name, pick.OpponentRisk.EntryName)
+				pick = safe[0]
+			}
+		}
 		if pos == 1 {
 			bestAdd := bestAddByPos[pos]
 			if bestAdd <= pick.Score {
@@ -1262,7 +1981,7 @@ func pickDropCandidatesByPosition(drops []DropRecommendation, undroppable map[in
 			}
 		}
 
-		pick.Reason = "Lowest weighted score at position"
+		pick.Reason = reason
 		byPos[posLabel] = []DropRecommendation{pick}
 	}
 
@@ -1283,18 +2002,3 @@ func flattenDrops(byPos map[string][]DropRecommendation) []DropRecommendation {
 	}
 	return out
 }
-
-func bestDropForPosition(dropsByPos map[string][]DropRecommendation, pos int, addScore float64) *DropRecommendation {
-	label := positionLabel(pos)
-	list := dropsByPos[label]
-	if len(list) == 0 {
-		return nil
-	}
-	d := list[0]
-	if addScore <= d.Score {
-		return nil
-	}
-	out := d
-	out.Reason = "Lowest weighted score at position"
-	return &out
-}
@@ -5,7 +5,11 @@ import (
 	"math"
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/reconcile"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/summary"
 )
 
 // ---------------------------------------------------------------------------
@@ -305,6 +309,77 @@ func TestComputePointsConcededByPosition_UsesLiveFixtures(t *testing.T) {
 	}
 }
 
+// TestComputePointsConcededByPosition_DoubleGW checks that a DGW team's GW
+// total is split across its two opponents instead of charged to each in full.
+func TestLoadBootstrapData_QuotedStats(t *testing.T) {
+	dir, cfg := tmpCfg(t)
+	writeJSON(t, filepath.Join(dir, "bootstrap", "bootstrap-static.json"), map[string]any{
+		"elements": []any{
+			map[string]any{"id": 1, "web_name": "Salah", "team": 10, "element_type": 3, "total_points": "150", "minutes": "2700", "starts": 30},
+			map[string]any{"id": 2, "web_name": "Haaland", "team": 11, "element_type": 4, "total_points": 180, "minutes": nil},
+		},
+		"teams": []any{map[string]any{"id": 10, "short_name": "LIV"}},
+	})
+	elements, _, _, err := loadBootstrapData(cfg.RawRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e := elements[0]; e.TotalPoints != 150 || e.Minutes != 2700 || e.Starts != 30 {
+		t.Errorf("quoted stats = %+v", e)
+	}
+	if e := elements[1]; e.TotalPoints != 180 || e.Minutes != 0 {
+		t.Errorf("plain/null stats = %+v", e)
+	}
+}
+
+func TestComputePointsConcededByPosition_DoubleGW(t *testing.T) {
+	dir, cfg := tmpCfg(t)
+	writeDGWFixture(t, dir)
+	elements, _, _, err := loadBootstrapData(cfg.RawRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conceded := computePointsConcededByPosition(dir, elements, dgwHistGW, 1)
+
+	if got := conceded[2]["AWAY"][3]; got.Count != 1 || got.Sum != 6 {
+		t.Errorf("CHE AWAY MID conceded: sum=%.1f count=%d, want 6 from one fixture", got.Sum, got.Count)
+	}
+	if got := conceded[3]["HOME"][3]; got.Count != 1 || got.Sum != 6 {
+		t.Errorf("LIV HOME MID conceded: sum=%.1f count=%d, want 6 from one fixture", got.Sum, got.Count)
+	}
+	// Arsenal's opponents each played once, so nothing is split.
+	if got := conceded[dgwARS]["AWAY"][3]; got.Sum != 5 {
+		t.Errorf("ARS AWAY MID conceded = %.1f, want 5", got.Sum)
+	}
+}
+
+// TestSumFixtureScores_DoubleGW checks that a DGW candidate's fixture score is
+// the sum of both fixtures, not their average.
+func TestSumFixtureScores_DoubleGW(t *testing.T) {
+	dir, cfg := tmpCfg(t)
+	writeDGWFixture(t, dir)
+	elements, teamShort, fixturesByGW, err := loadBootstrapData(cfg.RawRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conceded := computePointsConcededByPosition(dir, elements, dgwHistGW, 1)
+	idx := buildFixtureIndex(fixturesByGW[dgwGW], teamShort)
+	if len(idx[dgwARS]) != 2 {
+		t.Fatalf("ARS fixtures = %d, want 2", len(idx[dgwARS]))
+	}
+
+	_, _, blended := sumFixtureScores(idx[dgwARS], conceded, conceded, 3, 0.5, 0.5)
+	want := 0.0
+	for _, fx := range idx[dgwARS] {
+		_, _, b := blendedFixtureScore(conceded, conceded, fx.OpponentID, fx.Venue, 3, 0.5, 0.5)
+		want += b
+	}
+	if !approxEqual(blended, want) || !approxEqual(blended, 6) {
+		t.Errorf("ARS blended = %v, want %v (0 v MCI + 6 at CHE)", blended, want)
+	}
+}
+
 // TestLoadFixturesFromLive verifies that fixtures embedded in a live.json file
 // are correctly parsed into the fixture struct.
 func TestLoadFixturesFromLive(t *testing.T) {
@@ -349,3 +424,304 @@ func TestLoadFixturesFromLive(t *testing.T) {
 
 // Suppress unused import if math was already imported.
 var _ = math.Pi
+
+// ---------------------------------------------------------------------------
+// loadLiveStats / scoring weights — xA and bonus
+// ---------------------------------------------------------------------------
+
+// TestLoadLiveStats_StringEncodedStats verifies that expected_assists and
+// expected_goals, which the API returns as decimal strings, are parsed
+// alongside the integer bonus field.
+func TestLoadLiveStats_StringEncodedStats(t *testing.T) {
+	rawRoot := t.TempDir()
+	writeLiveJSON(t, rawRoot, 3, map[string]any{
+		"7": map[string]any{"stats": map[string]any{
+			"minutes":          90,
+			"total_points":     9,
+			"expected_goals":   "0.61",
+			"expected_assists": "0.27",
+			"bonus":            2,
+		}},
+	})
+
+	live, err := loadLiveStats(rawRoot, 3)
+	if err != nil {
+		t.Fatalf("loadLiveStats: %v", err)
+	}
+	got := live[7]
+	if got.XG != 0.61 || got.XA != 0.27 || got.Bonus != 2 {
+		t.Errorf("stats = xg %v xa %v bonus %d, want 0.61/0.27/2", got.XG, got.XA, got.Bonus)
+	}
+}
+
+// TestComputeXAAndBonus verifies per-90 xA and per-GW bonus across the horizon.
+func TestComputeXAAndBonus(t *testing.T) {
+	rawRoot := t.TempDir()
+	writeLiveJSON(t, rawRoot, 1, map[string]any{
+		"5": map[string]any{"stats": map[string]any{"minutes": 45, "expected_assists": "0.20", "bonus": 1}},
+	})
+	writeLiveJSON(t, rawRoot, 2, map[string]any{
+		"5": map[string]any{"stats": map[string]any{"minutes": 45, "expected_assists": "0.10", "bonus": 3}},
+	})
+
+	xa, bonus := computeXAAndBonus(rawRoot, 2, 5)
+	if math.Abs(xa[5]-0.30) > 1e-9 {
+		t.Errorf("xA/90 = %f, want 0.30", xa[5])
+	}
+	if math.Abs(bonus[5]-2.0) > 1e-9 {
+		t.Errorf("bonus/GW = %f, want 2.0", bonus[5])
+	}
+}
+
+// TestResolveScoreWeights_ZeroNewWeightsMatchLegacyFormula confirms that when
+// only the original four weights are supplied, xA and bonus get zero weight and
+// the weighted score is identical to the pre-xA formula.
+func TestResolveScoreWeights_ZeroNewWeightsMatchLegacyFormula(t *testing.T) {
+	f := func(v float64) *float64 { return &v }
+	w := resolveScoreWeights(WaiverRecommendationsArgs{
+		WeightFixtures: f(0.35),
+		WeightForm:     f(0.25),
+		WeightTotal:    f(0.25),
+		WeightXG:       f(0.15),
+	})
+	if w.XA != 0 || w.Bonus != 0 {
+		t.Fatalf("expected zero xA/bonus weights, got %v/%v", w.XA, w.Bonus)
+	}
+
+	players := []ScoreComponents{
+		{FixturesNorm: 0.9, FormNorm: 0.2, TotalNorm: 0.5, XGNorm: 0.1, XANorm: 1, BonusNorm: 1},
+		{FixturesNorm: 0.4, FormNorm: 0.8, TotalNorm: 0.7, XGNorm: 0.6, XANorm: 0, BonusNorm: 0},
+	}
+	for i, s := range players {
+		legacy := 0.35*s.FixturesNorm + 0.25*s.FormNorm + 0.25*s.TotalNorm + 0.15*s.XGNorm
+		if got := weightedScore(w, s); math.Abs(got-legacy) > 1e-9 {
+			t.Errorf("player %d: weighted = %f, want legacy %f", i, got, legacy)
+		}
+	}
+}
+
+// TestResolveScoreWeights_Defaults verifies the default split is renormalized
+// to sum to 1 with small xA/bonus contributions.
+func TestResolveScoreWeights_Defaults(t *testing.T) {
+	w := resolveScoreWeights(WaiverRecommendationsArgs{})
+	sum := w.Fix + w.Form + w.Total + w.XG + w.XA + w.Bonus
+	if math.Abs(sum-1) > 1e-9 {
+		t.Errorf("weights sum = %f, want 1", sum)
+	}
+	if math.Abs(w.XA-0.05/1.10) > 1e-9 || math.Abs(w.Bonus-0.05/1.10) > 1e-9 {
+		t.Errorf("xA/bonus = %f/%f, want %f each", w.XA, w.Bonus, 0.05/1.10)
+	}
+}
+
+func TestMinutesEligible(t *testing.T) {
+	cases := []struct {
+		name    string
+		last3   int
+		season  int
+		pattern string
+		want    bool
+	}{
+		{"last3", 3, 3, summary.MinutesNailed, true},
+		{"season", 1, 10, summary.MinutesRotation, true},
+		{"neither", 2, 9, summary.MinutesRotation, false},
+		{"returning with season count", 1, 5, summary.MinutesReturning, true},
+		{"returning without season count", 2, 4, summary.MinutesReturning, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := minutesEligible(tc.last3, tc.season, tc.pattern); got != tc.want {
+				t.Errorf("minutesEligible(%d, %d, %q) = %v, want %v", tc.last3, tc.season, tc.pattern, got, tc.want)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Defensive scoring profile (GK/DEF)
+// ---------------------------------------------------------------------------
+
+func TestComputeDefensiveStats(t *testing.T) {
+	dir := t.TempDir()
+	elements := []elementInfo{
+		{ID: 1, TeamID: 1, PositionType: 1}, // GK, team 1
+		{ID: 2, TeamID: 1, PositionType: 2}, // DEF, team 1
+		{ID: 3, TeamID: 2, PositionType: 1}, // GK, team 2
+	}
+	fixture := []any{map[string]any{"id": 1, "team_h": 1, "team_a": 2}}
+	writeJSON(t, filepath.Join(dir, "gw", "1", "live.json"), map[string]any{
+		"fixtures": fixture,
+		"elements": map[string]any{
+			"1": map[string]any{"stats": map[string]any{"minutes": 90, "saves": 6, "clean_sheets": 1}},
+			"2": map[string]any{"stats": map[string]any{"minutes": 45, "clean_sheets": 0}},
+			"3": map[string]any{"stats": map[string]any{"minutes": 90, "saves": 1}},
+		},
+	})
+	writeJSON(t, filepath.Join(dir, "gw", "2", "live.json"), map[string]any{
+		"fixtures": fixture,
+		"elements": map[string]any{
+			"1": map[string]any{"stats": map[string]any{"minutes": 90, "saves": 3}},
+			"3": map[string]any{"stats": map[string]any{"minutes": 90, "saves": 2, "clean_sheets": 1}},
+		},
+	})
+
+	d := computeDefensiveStats(dir, elements, 2, 5)
+	if !approxEqual(d.savesPer90[1], 4.5) || !approxEqual(d.savesPer90[3], 1.5) {
+		t.Errorf("saves/90 = %v/%v, want 4.5/1.5", d.savesPer90[1], d.savesPer90[3])
+	}
+	// The subbed-off defender's 0 doesn't cancel the GK's clean sheet.
+	if !approxEqual(d.cleanSheetRate[1], 0.5) || !approxEqual(d.cleanSheetRate[2], 0.5) {
+		t.Errorf("clean-sheet rates = %v/%v, want 0.5 each", d.cleanSheetRate[1], d.cleanSheetRate[2])
+	}
+}
+
+// TestNormalizeScores_GKPool checks that goalkeepers are ranked on the
+// defensive component: a GK with the most saves, the best clean-sheet record
+// and the meanest defence beats one with higher xG.
+func TestNormalizeScores_GKPool(t *testing.T) {
+	gk := func(id int, saves, cs, conceded, xg float64) scoredPlayer {
+		return scoredPlayer{
+			info: elementInfo{ID: id, PositionType: 1},
+			score: ScoreComponents{
+				Profile: scoringProfile(1), SavesPer90: saves, CleanSheetRate: cs,
+				AttackConceded: conceded, XGRaw: xg,
+			},
+		}
+	}
+	pool := []scoredPlayer{
+		gk(1, 4.0, 0.6, 2.0, 0.0),
+		gk(2, 1.0, 0.2, 8.0, 0.3), // an xG this high for a GK is noise
+		gk(3, 2.5, 0.4, 5.0, 0.0),
+	}
+	mm := normalizeScores(pool)
+	want := []float64{1, 0, 0.5}
+	for i, p := range pool {
+		if !approxEqual(p.score.DefensiveNorm, want[i]) {
+			t.Errorf("GK %d defensive_norm = %v, want %v", p.info.ID, p.score.DefensiveNorm, want[i])
+		}
+	}
+
+	w := resolveScoreWeights(WaiverRecommendationsArgs{})
+	for i := range pool {
+		pool[i].score.WeightedScore = weightedScore(w, pool[i].score)
+	}
+	if !(pool[0].score.WeightedScore > pool[2].score.WeightedScore && pool[2].score.WeightedScore > pool[1].score.WeightedScore) {
+		t.Errorf("weighted scores = %v/%v/%v, want GK 1 > GK 3 > GK 2",
+			pool[0].score.WeightedScore, pool[1].score.WeightedScore, pool[2].score.WeightedScore)
+	}
+
+	// A defender has no saves term; its norm averages the other two.
+	def := ScoreComponents{Profile: scoringProfile(2), CleanSheetRate: 0.6, AttackConceded: 5.0}
+	if got := defensiveNorm(2, def, mm); !approxEqual(got, 0.75) {
+		t.Errorf("DEF defensive_norm = %v, want 0.75", got)
+	}
+}
+
+func TestScoringProfile(t *testing.T) {
+	for pos, want := range map[int]string{1: profileDefensive, 2: profileDefensive, 3: profileAttacking, 4: profileAttacking} {
+		if got := scoringProfile(pos); got != want {
+			t.Errorf("scoringProfile(%d) = %q, want %q", pos, got, want)
+		}
+	}
+}
+
+func TestOwnershipTimeline_DerivedWhenCurrent(t *testing.T) {
+	dir, cfg := tmpCfg(t)
+	cfg.DerivedRoot = filepath.Join(dir, "derived")
+	writeDraftChoicesFixture(t, dir)
+	writeJSON(t, filepath.Join(dir, "league/100/transactions.json"), map[string]any{"transactions": []any{
+		map[string]any{"id": 1, "entry": 200, "element_in": 5, "element_out": 2, "event": 2, "kind": "w", "result": "a"},
+	}})
+	writeJSON(t, filepath.Join(dir, "league/100/trades.json"), map[string]any{"trades": []any{}})
+
+	replay, err := loadOwnershipTimeline(cfg, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replay.derived != nil {
+		t.Fatal("used a derived timeline that was never written")
+	}
+
+	// A timeline from before the waiver is stale and ignored.
+	if err := reconcile.WriteOwnershipTimeline(cfg.DerivedRoot, reconcile.BuildOwnershipTimeline(100, &replay.ledger, nil, nil, nil)); err != nil {
+		t.Fatal(err)
+	}
+	if stale, err := loadOwnershipTimeline(cfg, 100); err != nil || stale.derived != nil {
+		t.Fatalf("stale timeline: derived=%v err=%v", stale.derived != nil, err)
+	}
+
+	if err := reconcile.WriteOwnershipTimeline(cfg.DerivedRoot, reconcile.BuildOwnershipTimeline(100, &replay.ledger, replay.transactions, replay.trades, nil)); err != nil {
+		t.Fatal(err)
+	}
+	derived, err := loadOwnershipTimeline(cfg, 100)
+	if err != nil || derived.derived == nil {
+		t.Fatalf("current timeline not used: err=%v", err)
+	}
+	for gw := 1; gw <= 3; gw++ {
+		if got, want := derived.at(gw), replay.at(gw); !reflect.DeepEqual(got, want) {
+			t.Errorf("GW%d at = %v, replay = %v", gw, got, want)
+		}
+	}
+	if derived.ownerAt(2, 5) != 200 || derived.ownerAt(2, 2) != 0 || derived.ownerAt(1, 2) != 200 {
+		t.Errorf("ownerAt: 5@2=%d 2@2=%d 2@1=%d", derived.ownerAt(2, 5), derived.ownerAt(2, 2), derived.ownerAt(1, 2))
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Forced candidates (include_elements / include_players)
+// ---------------------------------------------------------------------------
+
+func TestResolveForcedCandidates(t *testing.T) {
+	elements := []elementInfo{
+		{ID: 1, Name: "Salah", PositionType: 3, Status: "a"},
+		{ID: 2, Name: "Haaland", PositionType: 4, Status: "a"},
+		{ID: 3, Name: "Alexander-Arnold", PositionType: 2, Status: "a"},
+		{ID: 4, Name: "Rashford", PositionType: 3, Status: "d"},
+	}
+	ownership := map[int]map[int]bool{
+		100: {1: true},
+		200: {2: true},
+	}
+	entries := []summary.LeagueEntry{
+		{EntryID: 100, EntryName: "Mine"},
+		{EntryID: 200, EntryName: "Rivals"},
+	}
+	ids := []int{2, 3, 99, 1}
+	names := []string{"rashford", "arnold"}
+
+	got, errs := resolveForcedCandidates(elements, &ids, &names, ownership, 100, entries)
+
+	gotIDs := make([]int, 0, len(got))
+	for _, e := range got {
+		gotIDs = append(gotIDs, e.ID)
+	}
+	if !reflect.DeepEqual(gotIDs, []int{3, 4}) {
+		t.Errorf("forced = %v, want [3 4] (TAA once, Rashford by name)", gotIDs)
+	}
+	want := []ForcedCandidateError{
+		{Input: "2", Element: 2, Name: "Haaland", Error: "owned by Rivals; only free agents can be added"},
+		{Input: "99", Error: "element not found: 99"},
+		{Input: "1", Element: 1, Name: "Salah", Error: "already on your roster; only free agents can be added"},
+	}
+	if !reflect.DeepEqual(errs, want) {
+		t.Errorf("errors = %+v\nwant %+v", errs, want)
+	}
+}
+
+func TestForcedFilterFailures(t *testing.T) {
+	info := elementInfo{ID: 4, PositionType: 3, Status: "d"}
+	got := forcedFilterFailures(info, AvailabilityInfo{Minutes60Last3: 2, Minutes60Season: 4}, summary.MinutesReturning, 4, 12, 0)
+	want := []string{
+		`fails availability: status "d", not 'a'`,
+		"outside target_position: MID, not FWD",
+		"fails 60-min eligibility: 2 of last 3, 4 this season (needs 3 of 3 or 5)",
+		"no fixture in GW 12",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("failures = %q\nwant %q", got, want)
+	}
+
+	info.Status = "a"
+	if got := forcedFilterFailures(info, AvailabilityInfo{Minutes60Last3: 3}, summary.MinutesNailed, 0, 12, 1); len(got) != 0 {
+		t.Errorf("eligible player failures = %q, want none", got)
+	}
+}
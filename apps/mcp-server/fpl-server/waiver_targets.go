@@ -0,0 +1,268 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/summary"
+)
+
+// Need multipliers are the league's average points/GW at a position over
+// the entry's, clamped to this range so one bad group can't bury every
+// other position.
+const (
+	minNeedMultiplier = 0.75
+	maxNeedMultiplier = 1.5
+)
+
+// Position need levels reported in PositionNeed.Level: high when the entry's
+// group scores under 85% of the league average, low over 115%.
+const (
+	needHigh   = "high"
+	needMedium = "medium"
+	needLow    = "low"
+)
+
+type WaiverTargetsArgs struct {
+	LeagueID     int     `json:"league_id" jsonschema:"Draft league id (required)"`
+	GW           int     `json:"gw" jsonschema:"Gameweek (0 = current)"`
+	Horizon      int     `json:"horizon" jsonschema:"Rolling horizon in GWs (default 5)"`
+	Risk         string  `json:"risk" jsonschema:"Risk level: low|med|high (default med)"`
+	PositionType *int    `json:"position_type,omitempty" jsonschema:"Only this position's targets: 1=GK 2=DEF 3=MID 4=FWD"`
+	NeedAware    bool    `json:"need_aware,omitempty" jsonschema:"Re-rank targets by your roster's positional need (needs entry_id or entry_name)"`
+	EntryID      *int    `json:"entry_id,omitempty" jsonschema:"Your entry id for need_aware"`
+	EntryName    *string `json:"entry_name,omitempty" jsonschema:"Your entry name for need_aware (if entry_id not provided)"`
+}
+
+// PositionNeed compares an entry's points/GW at one position with the
+// league's. EntryPPG and LeagueAvgPPG average the points/GW of each rostered
+// player in the group; the league figure averages those group averages over
+// every entry.
+type PositionNeed struct {
+	PositionType int     `json:"position_type"`
+	Position     string  `json:"position"`
+	Players      int     `json:"players"`
+	EntryPPG     float64 `json:"entry_ppg"`
+	LeagueAvgPPG float64 `json:"league_avg_ppg"`
+	Multiplier   float64 `json:"multiplier"`
+	Level        string  `json:"level"`
+	Summary      string  `json:"summary"`
+}
+
+// WaiverTargetsPositionOutput is the waiver_targets output for one
+// position_type: Targets holds only that position.
+type WaiverTargetsPositionOutput struct {
+	summary.WaiverTargetsSummary
+	PositionType int `json:"position_type"`
+}
+
+// NeedAwareTarget is a waiver target re-scored by positional need.
+// GlobalRank is its place in the unweighted league-wide ranking.
+type NeedAwareTarget struct {
+	summary.WaiverTarget
+	GlobalRank     int     `json:"global_rank"`
+	NeedMultiplier float64 `json:"need_multiplier"`
+	NeedScore      float64 `json:"need_score"`
+}
+
+// WaiverTargetsNeedOutput is the waiver_targets output with need_aware set.
+type WaiverTargetsNeedOutput struct {
+	LeagueID  int    `json:"league_id"`
+	Gameweek  int    `json:"gameweek"`
+	Horizon   int    `json:"horizon"`
+	RiskLevel string `json:"risk"`
+	summary.BuildStamp
+	EntryID      int               `json:"entry_id"`
+	NeedAware    bool              `json:"need_aware"`
+	PositionType int               `json:"position_type,omitempty"`
+	Needs        []PositionNeed    `json:"needs"`
+	Targets      []NeedAwareTarget `json:"targets"`
+	GWNote       *GWNote           `json:"gw_note,omitempty"`
+}
+
+// buildWaiverTargets returns the league's waiver_targets summary, or one
+// position's list of it, or with need_aware those targets re-ranked for one
+// entry's roster. The summary keeps each position's top 15, so need_aware
+// reorders those rather than reaching further down.
+func buildWaiverTargets(cfg ServerConfig, args WaiverTargetsArgs) ([]byte, error) {
+	if args.LeagueID == 0 {
+		return nil, invalidArgumentf("league_id is required")
+	}
+	if args.PositionType != nil && (*args.PositionType < 1 || *args.PositionType > 4) {
+		return nil, invalidArgumentf("position_type must be 1-4, got %d", *args.PositionType)
+	}
+	gw, note, err := resolveEffectiveGW(cfg, args.GW, gwModeLatestFinished)
+	if err != nil {
+		return nil, err
+	}
+	h := args.Horizon
+	if h <= 0 {
+		h = 5
+	}
+	risk := normalizeRisk(args.Risk)
+	relPath := fmt.Sprintf("summary/waiver_targets/%d/gw/%d_h%d_risk-%s.json", args.LeagueID, gw, h, risk)
+	raw, err := loadSummaryFile(cfg, args.LeagueID, gw, relPath, []int{h}, []string{risk})
+	if err != nil {
+		return nil, err
+	}
+	if !args.NeedAware && args.PositionType == nil {
+		return withGWNote(raw, note), nil
+	}
+	var targets summary.WaiverTargetsSummary
+	if err := json.Unmarshal(raw, &targets); err != nil {
+		return nil, err
+	}
+	ranked := targets.Targets
+	if args.PositionType != nil {
+		ranked = targets.Position(*args.PositionType)
+	}
+	if !args.NeedAware {
+		out := WaiverTargetsPositionOutput{WaiverTargetsSummary: targets, PositionType: *args.PositionType}
+		out.Targets = ranked
+		out.ByPosition = nil
+		b, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return withGWNote(b, note), nil
+	}
+
+	entryID, err := resolveWaiverTargetsEntry(cfg, args)
+	if err != nil {
+		return nil, err
+	}
+	form, err := loadPlayerFormSummary(cfg, args.LeagueID, gw, h)
+	if err != nil {
+		return nil, err
+	}
+	ownership, err := loadOwnershipAtGW(cfg, args.LeagueID, gw)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := ownership[entryID]; !ok {
+		return nil, notFoundf("entry %d not found in league %d", entryID, args.LeagueID)
+	}
+
+	needs := positionNeeds(form, ownership, entryID)
+	multiplier := make(map[int]float64, len(needs))
+	for _, n := range needs {
+		multiplier[n.PositionType] = n.Multiplier
+	}
+	out := WaiverTargetsNeedOutput{
+		LeagueID:   targets.LeagueID,
+		Gameweek:   targets.Gameweek,
+		Horizon:    targets.Horizon,
+		RiskLevel:  targets.RiskLevel,
+		BuildStamp: targets.BuildStamp,
+		EntryID:    entryID,
+		NeedAware:  true,
+		Needs:      needs,
+		Targets:    make([]NeedAwareTarget, 0, len(ranked)),
+		GWNote:     note,
+	}
+	if args.PositionType != nil {
+		out.PositionType = *args.PositionType
+	}
+	globalRank := make(map[int]int, len(targets.Targets))
+	for i, t := range targets.Targets {
+		globalRank[t.Element] = i + 1
+	}
+	for _, t := range ranked {
+		m, ok := multiplier[t.PositionType]
+		if !ok {
+			m = 1
+		}
+		out.Targets = append(out.Targets, NeedAwareTarget{WaiverTarget: t, GlobalRank: globalRank[t.Element], NeedMultiplier: m, NeedScore: t.Score * m})
+	}
+	sort.SliceStable(out.Targets, func(i, j int) bool {
+		return out.Targets[i].NeedScore > out.Targets[j].NeedScore
+	})
+	return json.MarshalIndent(out, "", "  ")
+}
+
+// positionNeeds averages form points/GW per position for entryID's roster
+// and for the league, and turns the gap into a need multiplier per
+// position. A position the entry has nobody at gets the largest multiplier.
+func positionNeeds(form summary.PlayerFormSummary, ownership map[int]map[int]bool, entryID int) []PositionNeed {
+	ppg := make(map[int]float64, len(form.Players))
+	pos := make(map[int]int, len(form.Players))
+	for _, p := range form.Players {
+		ppg[p.Element] = p.PointsPerGW
+		pos[p.Element] = p.PositionType
+	}
+	// groupAvg is an entry's average points/GW and player count per
+	// position type.
+	groupAvg := func(roster map[int]bool) ([5]float64, [5]int) {
+		var sum [5]float64
+		var n [5]int
+		for el := range roster {
+			p := pos[el]
+			if p < 1 || p > 4 {
+				continue
+			}
+			sum[p] += ppg[el]
+			n[p]++
+		}
+		for p := 1; p <= 4; p++ {
+			if n[p] > 0 {
+				sum[p] /= float64(n[p])
+			}
+		}
+		return sum, n
+	}
+
+	var leagueSum [5]float64
+	var leagueN [5]int
+	for _, roster := range ownership {
+		avg, n := groupAvg(roster)
+		for p := 1; p <= 4; p++ {
+			if n[p] > 0 {
+				leagueSum[p] += avg[p]
+				leagueN[p]++
+			}
+		}
+	}
+	mine, mineN := groupAvg(ownership[entryID])
+
+	out := make([]PositionNeed, 0, 4)
+	for p := 1; p <= 4; p++ {
+		n := PositionNeed{PositionType: p, Position: positionLabel(p), Players: mineN[p], EntryPPG: mine[p], Multiplier: 1, Level: needMedium}
+		if leagueN[p] > 0 {
+			n.LeagueAvgPPG = leagueSum[p] / float64(leagueN[p])
+		}
+		switch {
+		case n.LeagueAvgPPG <= 0:
+		case n.Players == 0 || n.EntryPPG <= 0:
+			n.Multiplier = maxNeedMultiplier
+		default:
+			n.Multiplier = min(max(n.LeagueAvgPPG/n.EntryPPG, minNeedMultiplier), maxNeedMultiplier)
+		}
+		switch {
+		case n.LeagueAvgPPG <= 0:
+		case n.EntryPPG < 0.85*n.LeagueAvgPPG:
+			n.Level = needHigh
+		case n.EntryPPG > 1.15*n.LeagueAvgPPG:
+			n.Level = needLow
+		}
+		n.Summary = fmt.Sprintf("Your %s group averages %.1f ppg vs league %.1f — %s need", n.Position, n.EntryPPG, n.LeagueAvgPPG, n.Level)
+		out = append(out, n)
+	}
+	return out
+}
+
+func resolveWaiverTargetsEntry(cfg ServerConfig, args WaiverTargetsArgs) (int, error) {
+	if args.EntryID != nil && *args.EntryID != 0 {
+		return *args.EntryID, nil
+	}
+	if args.EntryName == nil || strings.TrimSpace(*args.EntryName) == "" {
+		return 0, invalidArgumentf("need_aware requires entry_id or entry_name")
+	}
+	ld, _, err := loadLeagueDetails(store.NewJSONStore(cfg.RawRoot), args.LeagueID)
+	if err != nil {
+		return 0, err
+	}
+	return resolveEntry(ld.LeagueEntries, *args.EntryName)
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/summary"
+)
+
+// writeNeedFixture adds GW3 h5 player_form and waiver_targets summaries to
+// writeClaimFixture's league. Alpha (200) owns MIDs at 8 and 6, a DEF at 4
+// and a FWD at 5 ppg; Beta (201) a MID at 7; Gamma (202) a FWD at 9. The
+// unowned targets are Mbeumo (MID), Munoz (DEF) and Wissa (FWD).
+func writeNeedFixture(t *testing.T, dir string) {
+	t.Helper()
+	writeClaimFixture(t, dir)
+	p := func(id int, pos int, ppg float64, own int) summary.PlayerForm {
+		return summary.PlayerForm{Element: id, Team: "LIV", PositionType: pos, Minutes: 450, PointsPerGW: ppg, Ownership: own}
+	}
+	writeJSON(t, filepath.Join(dir, "summary/player_form/100/h5.json"), summary.PlayerFormSummary{
+		LeagueID: 100, AsOfGW: 3, Horizon: 5,
+		Players: []summary.PlayerForm{
+			p(1, 3, 8, 1), p(2, 3, 6, 1), p(3, 2, 4, 1), p(4, 3, 7, 1), p(5, 4, 9, 1), p(6, 4, 5, 1),
+			p(10, 3, 3, 0), p(12, 4, 2.5, 0), p(13, 2, 2.8, 0),
+		},
+	})
+	target := func(id int, pos int, score float64) summary.WaiverTarget {
+		return summary.WaiverTarget{Element: id, Team: "LIV", PositionType: pos, Minutes: 450, PointsPerGW: score, Score: score}
+	}
+	writeJSON(t, filepath.Join(dir, "summary/waiver_targets/100/gw/3_h5_risk-med.json"), summary.WaiverTargetsSummary{
+		LeagueID: 100, Gameweek: 3, Horizon: 5, RiskLevel: "med",
+		Targets: []summary.WaiverTarget{target(10, 3, 3), target(13, 2, 2.8), target(12, 4, 2.5)},
+	})
+}
+
+func TestBuildWaiverTargets_NeedAware(t *testing.T) {
+	dir, cfg := resourceCfg(t)
+	writeNeedFixture(t, dir)
+
+	raw, err := buildWaiverTargets(cfg, WaiverTargetsArgs{LeagueID: 100})
+	if err != nil {
+		t.Fatalf("buildWaiverTargets: %v", err)
+	}
+	var global summary.WaiverTargetsSummary
+	if err := json.Unmarshal(raw, &global); err != nil || len(global.Targets) != 3 || global.Targets[0].Element != 10 {
+		t.Fatalf("global ranking = %s (%v), want the summary unchanged", raw, err)
+	}
+
+	name := "alpha"
+	raw, err = buildWaiverTargets(cfg, WaiverTargetsArgs{LeagueID: 100, NeedAware: true, EntryName: &name})
+	if err != nil {
+		t.Fatalf("buildWaiverTargets need_aware: %v", err)
+	}
+	var out WaiverTargetsNeedOutput
+	if err := json.Unmarshal(raw, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.EntryID != 200 || !out.NeedAware || len(out.Needs) != 4 {
+		t.Fatalf("output = %+v", out)
+	}
+	// FWD: Alpha's 5 vs the league's (5+9)/2 = 7. MID: 7 vs 7. DEF: only
+	// Alpha has one. GK: nobody does, so there is nothing to compare.
+	want := []struct {
+		pos        string
+		mine, lg   float64
+		multiplier float64
+		level      string
+	}{
+		{"GK", 0, 0, 1, needMedium},
+		{"DEF", 4, 4, 1, needMedium},
+		{"MID", 7, 7, 1, needMedium},
+		{"FWD", 5, 7, 1.4, needHigh},
+	}
+	for i, w := range want {
+		n := out.Needs[i]
+		if n.Position != w.pos || n.EntryPPG != w.mine || n.LeagueAvgPPG != w.lg || math.Abs(n.Multiplier-w.multiplier) > 1e-9 || n.Level != w.level {
+			t.Errorf("need %d = %+v, want %s %.1f vs %.1f x%.2f %s", i, n, w.pos, w.mine, w.lg, w.multiplier, w.level)
+		}
+	}
+	if s := out.Needs[3].Summary; s != "Your FWD group averages 5.0 ppg vs league 7.0 — high need" {
+		t.Errorf("FWD summary = %q", s)
+	}
+	// Wissa's 2.5 x 1.4 = 3.5 jumps from third to first.
+	wantOrder := []struct{ element, globalRank int }{{12, 3}, {10, 1}, {13, 2}}
+	if len(out.Targets) != len(wantOrder) {
+		t.Fatalf("targets = %+v", out.Targets)
+	}
+	for i, w := range wantOrder {
+		if tg := out.Targets[i]; tg.Element != w.element || tg.GlobalRank != w.globalRank {
+			t.Errorf("target %d = %d (global %d), want %d (global %d)", i, tg.Element, tg.GlobalRank, w.element, w.globalRank)
+		}
+	}
+	if s := out.Targets[0].NeedScore; math.Abs(s-3.5) > 1e-9 {
+		t.Errorf("Wissa need score = %v, want 3.5", s)
+	}
+
+	// Beta has no FWD at all: the largest multiplier.
+	beta := 201
+	raw, err = buildWaiverTargets(cfg, WaiverTargetsArgs{LeagueID: 100, NeedAware: true, EntryID: &beta})
+	if err != nil {
+		t.Fatalf("buildWaiverTargets need_aware Beta: %v", err)
+	}
+	out = WaiverTargetsNeedOutput{}
+	if err := json.Unmarshal(raw, &out); err != nil || out.Needs[3].Multiplier != maxNeedMultiplier || out.Needs[3].Players != 0 {
+		t.Errorf("Beta FWD need = %+v (%v)", out.Needs, err)
+	}
+}
+
+func TestBuildWaiverTargets_NeedAwareErrors(t *testing.T) {
+	dir, cfg := resourceCfg(t)
+	writeNeedFixture(t, dir)
+	if _, err := buildWaiverTargets(cfg, WaiverTargetsArgs{LeagueID: 100, NeedAware: true}); classifyError(err).Code != codeInvalidArgument {
+		t.Errorf("need_aware without entry: err = %v", err)
+	}
+	other := 999
+	if _, err := buildWaiverTargets(cfg, WaiverTargetsArgs{LeagueID: 100, NeedAware: true, EntryID: &other}); classifyError(err).Code != codeNotFound {
+		t.Errorf("need_aware for unknown entry: err = %v", err)
+	}
+	if _, err := buildWaiverTargets(cfg, WaiverTargetsArgs{}); classifyError(err).Code != codeInvalidArgument {
+		t.Errorf("missing league: err = %v", err)
+	}
+	bad := 5
+	if _, err := buildWaiverTargets(cfg, WaiverTargetsArgs{LeagueID: 100, PositionType: &bad}); classifyError(err).Code != codeInvalidArgument {
+		t.Errorf("position_type 5: err = %v", err)
+	}
+}
+
+func TestBuildWaiverTargets_PositionType(t *testing.T) {
+	dir, cfg := resourceCfg(t)
+	writeNeedFixture(t, dir)
+
+	// The fixture's file predates by_position and the config can't rebuild
+	// it, so the position comes from the merged list.
+	def := 2
+	raw, err := buildWaiverTargets(cfg, WaiverTargetsArgs{LeagueID: 100, PositionType: &def})
+	if err != nil {
+		t.Fatalf("position_type: %v", err)
+	}
+	var out WaiverTargetsPositionOutput
+	if err := json.Unmarshal(raw, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.PositionType != 2 || len(out.Targets) != 1 || out.Targets[0].Element != 13 || out.ByPosition != nil {
+		t.Errorf("DEF targets = %s", raw)
+	}
+
+	fwd := 4
+	_, err = buildWaiverTargets(cfg, WaiverTargetsArgs{LeagueID: 100, PositionType: &fwd, NeedAware: true, EntryID: &fwd})
+	if classifyError(err).Code != codeNotFound {
+		t.Errorf("unknown entry: err = %v", err)
+	}
+	alpha := 200
+	raw, err = buildWaiverTargets(cfg, WaiverTargetsArgs{LeagueID: 100, PositionType: &fwd, NeedAware: true, EntryID: &alpha})
+	if err != nil {
+		t.Fatalf("need_aware position_type: %v", err)
+	}
+	var need WaiverTargetsNeedOutput
+	if err := json.Unmarshal(raw, &need); err != nil {
+		t.Fatal(err)
+	}
+	if need.PositionType != 4 || len(need.Targets) != 1 || need.Targets[0].Element != 12 || need.Targets[0].GlobalRank != 3 {
+		t.Errorf("need_aware FWD = %+v", need)
+	}
+}
+
+func TestBuildWaiverTargets_RebuildsOldFormat(t *testing.T) {
+	dir, cfg := resourceCfg(t)
+	cfg.ComputeMissing = true
+	cfg.WriteDerived = true
+	writeGW1Fixture(t, dir, gw1Live(), true)
+	// One GW into a five-GW horizon, every free agent is high risk.
+	relPath := "summary/waiver_targets/100/gw/1_h5_risk-high.json"
+	writeJSON(t, filepath.Join(dir, relPath), map[string]any{"league_id": 100, "gameweek": 1, "targets": []any{}})
+
+	def := 2
+	raw, err := buildWaiverTargets(cfg, WaiverTargetsArgs{LeagueID: 100, GW: 1, Risk: "high", PositionType: &def})
+	if err != nil {
+		t.Fatalf("buildWaiverTargets: %v", err)
+	}
+	var out WaiverTargetsPositionOutput
+	if err := json.Unmarshal(raw, &out); err != nil {
+		t.Fatal(err)
+	}
+	// Esteve is the only unowned defender.
+	if out.SchemaVersion != summary.WaiverTargetsSchemaVersion || len(out.Targets) != 1 || out.Targets[0].Element != 8 {
+		t.Errorf("rebuilt DEF targets = %s", raw)
+	}
+	b, err := os.ReadFile(filepath.Join(dir, relPath))
+	if err != nil || summary.WaiverTargetsOutdated(b) {
+		t.Errorf("file on disk still outdated (err = %v)", err)
+	}
+}
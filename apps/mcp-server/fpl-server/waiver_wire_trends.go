@@ -0,0 +1,467 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/livestats"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/reconcile"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+)
+
+// WaiverWireTrendsArgs are the input arguments for the waiver_wire_trends tool.
+type WaiverWireTrendsArgs struct {
+	LeagueID int  `json:"league_id" jsonschema:"Draft league id (required)"`
+	Window   *int `json:"window,omitempty" jsonschema:"Number of recent GWs to aggregate (default 4)"`
+}
+
+// RisingAdd is a player whose add count went up in the latest GW of the window.
+type RisingAdd struct {
+	Element      int    `json:"element"`
+	PlayerName   string `json:"player_name"`
+	Team         string `json:"team"`
+	PositionType int    `json:"position_type"`
+	AddsByGW     []int  `json:"adds_by_gw"` // aligned with WaiverWireTrendsOutput.GWs
+	TotalAdds    int    `json:"total_adds"`
+	Change       int    `json:"change"` // latest GW adds minus the previous GW's
+}
+
+// AbandonedPlayer is a player dropped by two or more managers in the window.
+type AbandonedPlayer struct {
+	Element      int      `json:"element"`
+	PlayerName   string   `json:"player_name"`
+	Team         string   `json:"team"`
+	PositionType int      `json:"position_type"`
+	Drops        int      `json:"drops"`
+	DroppedBy    []string `json:"dropped_by"`
+}
+
+// ManagerChurn is one manager's transaction volume over the window.
+type ManagerChurn struct {
+	EntryID      int     `json:"entry_id"`
+	EntryName    string  `json:"entry_name"`
+	Transactions int     `json:"transactions"`
+	PerGW        float64 `json:"per_gw"`
+}
+
+// OwnerStint is a span of GWs one manager owned a player, clipped to the window.
+type OwnerStint struct {
+	EntryID   int    `json:"entry_id"`
+	EntryName string `json:"entry_name"`
+	FromGW    int    `json:"from_gw"`
+	ToGW      int    `json:"to_gw"`
+	Points    int    `json:"points"`
+}
+
+// SecondChancePlayer was dropped by one manager and picked up by another.
+type SecondChancePlayer struct {
+	Element      int          `json:"element"`
+	PlayerName   string       `json:"player_name"`
+	Team         string       `json:"team"`
+	PositionType int          `json:"position_type"`
+	DroppedBy    string       `json:"dropped_by"`
+	DroppedGW    int          `json:"dropped_gw"`
+	AddedBy      string       `json:"added_by"`
+	AddedGW      int          `json:"added_gw"`
+	Stints       []OwnerStint `json:"stints"`
+}
+
+// WaiverWireTrendsOutput is the output of the waiver_wire_trends tool.
+type WaiverWireTrendsOutput struct {
+	LeagueID      int                  `json:"league_id"`
+	FromGW        int                  `json:"from_gw"`
+	ThroughGW     int                  `json:"through_gw"`
+	GWs           []int                `json:"gws"`
+	Transactions  int                  `json:"transactions"`
+	RisingAdds    []RisingAdd          `json:"rising_adds"`
+	Abandoned     []AbandonedPlayer    `json:"abandoned"`
+	Churn         []ManagerChurn       `json:"churn"`
+	SecondChances []SecondChancePlayer `json:"second_chances"`
+}
+
+// ownershipMove moves one element between owners. Entry 0 is the free-agent
+// pool. Kind is the transaction kind ("w" or "f") or "trade".
+type ownershipMove struct {
+	Event   int
+	Time    string
+	ID      int
+	Kind    string
+	Element int
+	From    int
+	To      int
+}
+
+// secondChance is a drop by one entry followed by an add by another.
+type secondChance struct {
+	Element   int
+	DroppedBy int
+	DroppedGW int
+	AddedBy   int
+	AddedGW   int
+}
+
+func buildWaiverWireTrends(cfg ServerConfig, args WaiverWireTrendsArgs) (WaiverWireTrendsOutput, error) {
+	if args.LeagueID == 0 {
+		return WaiverWireTrendsOutput{}, invalidArgumentf("league_id is required")
+	}
+	window := 4
+	if args.Window != nil && *args.Window > 0 {
+		window = *args.Window
+	}
+	throughGW, err := resolveGW(cfg, 0)
+	if err != nil {
+		return WaiverWireTrendsOutput{}, err
+	}
+	fromGW := throughGW - window + 1
+	if fromGW < 1 {
+		fromGW = 1
+	}
+
+	st := store.NewJSONStore(cfg.RawRoot)
+	transactions, err := loadTransactionsRaw(st, args.LeagueID)
+	if err != nil {
+		return WaiverWireTrendsOutput{}, err
+	}
+	trades, err := loadTradesRaw(st, args.LeagueID)
+	if err != nil {
+		return WaiverWireTrendsOutput{}, err
+	}
+	raw, err := st.ReadRaw(fmt.Sprintf("league/%d/details.json", args.LeagueID))
+	if err != nil {
+		return WaiverWireTrendsOutput{}, err
+	}
+	var details leagueDetailsRaw
+	if err := json.Unmarshal(raw, &details); err != nil {
+		return WaiverWireTrendsOutput{}, err
+	}
+	nameByEntry := make(map[int]string, len(details.LeagueEntries))
+	for _, e := range details.LeagueEntries {
+		nameByEntry[e.EntryID] = e.EntryName
+	}
+	elements, teamShort, _, err := loadBootstrapData(cfg.RawRoot)
+	if err != nil {
+		return WaiverWireTrendsOutput{}, err
+	}
+	playerByID := make(map[int]elementInfo, len(elements))
+	for _, e := range elements {
+		playerByID[e.ID] = e
+	}
+
+	inWindow := approvedTransactions(transactions, fromGW, throughGW)
+	gws := make([]int, 0, throughGW-fromGW+1)
+	for gw := fromGW; gw <= throughGW; gw++ {
+		gws = append(gws, gw)
+	}
+
+	out := WaiverWireTrendsOutput{
+		LeagueID:      args.LeagueID,
+		FromGW:        fromGW,
+		ThroughGW:     throughGW,
+		GWs:           gws,
+		Transactions:  len(inWindow),
+		RisingAdds:    []RisingAdd{},
+		Abandoned:     []AbandonedPlayer{},
+		Churn:         []ManagerChurn{},
+		SecondChances: []SecondChancePlayer{},
+	}
+
+	for _, r := range risingAdds(inWindow, fromGW, throughGW) {
+		meta, ok := playerByID[r.Element]
+		if !ok {
+			continue
+		}
+		r.PlayerName = meta.Name
+		r.Team = teamShort[meta.TeamID]
+		r.PositionType = meta.PositionType
+		out.RisingAdds = append(out.RisingAdds, r)
+	}
+
+	for element, droppers := range multiDropped(inWindow) {
+		meta, ok := playerByID[element]
+		if !ok {
+			continue
+		}
+		names := make([]string, 0, len(droppers))
+		for _, entryID := range droppers {
+			names = append(names, nameByEntry[entryID])
+		}
+		out.Abandoned = append(out.Abandoned, AbandonedPlayer{
+			Element:      element,
+			PlayerName:   meta.Name,
+			Team:         teamShort[meta.TeamID],
+			PositionType: meta.PositionType,
+			Drops:        len(droppers),
+			DroppedBy:    names,
+		})
+	}
+	sort.Slice(out.Abandoned, func(i, j int) bool {
+		if out.Abandoned[i].Drops != out.Abandoned[j].Drops {
+			return out.Abandoned[i].Drops > out.Abandoned[j].Drops
+		}
+		return out.Abandoned[i].Element < out.Abandoned[j].Element
+	})
+
+	txByEntry := make(map[int]int)
+	for _, tx := range inWindow {
+		txByEntry[tx.Entry]++
+	}
+	for _, e := range details.LeagueEntries {
+		n := txByEntry[e.EntryID]
+		out.Churn = append(out.Churn, ManagerChurn{
+			EntryID:      e.EntryID,
+			EntryName:    e.EntryName,
+			Transactions: n,
+			PerGW:        float64(n) / float64(len(gws)),
+		})
+	}
+	sort.Slice(out.Churn, func(i, j int) bool {
+		if out.Churn[i].Transactions != out.Churn[j].Transactions {
+			return out.Churn[i].Transactions > out.Churn[j].Transactions
+		}
+		return out.Churn[i].EntryID < out.Churn[j].EntryID
+	})
+
+	chances := findSecondChances(inWindow)
+	if len(chances) > 0 {
+		moves := ownershipMoves(transactions, trades)
+		pointsByGW := make(map[int]map[int]livestats.ElementStats, len(gws))
+		for _, gw := range gws {
+			// GWs without live data (not started yet) simply score nothing.
+			if live, err := loadLiveStats(cfg.RawRoot, gw); err == nil {
+				pointsByGW[gw] = live
+			}
+		}
+		for _, sc := range chances {
+			meta, ok := playerByID[sc.Element]
+			if !ok {
+				continue
+			}
+			stints := ownerStints(moves, sc.Element, fromGW, throughGW)
+			for i := range stints {
+				stints[i].EntryName = nameByEntry[stints[i].EntryID]
+				for gw := stints[i].FromGW; gw <= stints[i].ToGW; gw++ {
+					stints[i].Points += pointsByGW[gw][sc.Element].TotalPoints
+				}
+			}
+			out.SecondChances = append(out.SecondChances, SecondChancePlayer{
+				Element:      sc.Element,
+				PlayerName:   meta.Name,
+				Team:         teamShort[meta.TeamID],
+				PositionType: meta.PositionType,
+				DroppedBy:    nameByEntry[sc.DroppedBy],
+				DroppedGW:    sc.DroppedGW,
+				AddedBy:      nameByEntry[sc.AddedBy],
+				AddedGW:      sc.AddedGW,
+				Stints:       stints,
+			})
+		}
+	}
+	return out, nil
+}
+
+// approvedTransactions returns the approved waiver and free-agent
+// transactions in [fromGW, throughGW], ordered by event, time, then id.
+func approvedTransactions(transactions []reconcile.Transaction, fromGW int, throughGW int) []reconcile.Transaction {
+	out := make([]reconcile.Transaction, 0)
+	for _, tx := range transactions {
+		if tx.Event < fromGW || tx.Event > throughGW {
+			continue
+		}
+		if tx.Result != "a" || (tx.Kind != "w" && tx.Kind != "f") {
+			continue
+		}
+		out = append(out, tx)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Event != out[j].Event {
+			return out[i].Event < out[j].Event
+		}
+		if out[i].Added != out[j].Added {
+			return out[i].Added < out[j].Added
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out
+}
+
+// risingAdds returns players added more often in throughGW than in the GW
+// before it, biggest increase first. Metadata fields are left empty.
+func risingAdds(txs []reconcile.Transaction, fromGW int, throughGW int) []RisingAdd {
+	if throughGW <= fromGW {
+		return nil
+	}
+	n := throughGW - fromGW + 1
+	adds := make(map[int][]int)
+	for _, tx := range txs {
+		if tx.ElementIn == 0 {
+			continue
+		}
+		if _, ok := adds[tx.ElementIn]; !ok {
+			adds[tx.ElementIn] = make([]int, n)
+		}
+		adds[tx.ElementIn][tx.Event-fromGW]++
+	}
+	out := make([]RisingAdd, 0)
+	for element, byGW := range adds {
+		change := byGW[n-1] - byGW[n-2]
+		if change <= 0 {
+			continue
+		}
+		total := 0
+		for _, c := range byGW {
+			total += c
+		}
+		out = append(out, RisingAdd{Element: element, AddsByGW: byGW, TotalAdds: total, Change: change})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Change != out[j].Change {
+			return out[i].Change > out[j].Change
+		}
+		if out[i].TotalAdds != out[j].TotalAdds {
+			return out[i].TotalAdds > out[j].TotalAdds
+		}
+		return out[i].Element < out[j].Element
+	})
+	return out
+}
+
+// multiDropped maps each player dropped by two or more distinct entries to
+// those entries, in the order they dropped the player.
+func multiDropped(txs []reconcile.Transaction) map[int][]int {
+	droppers := make(map[int][]int)
+	seen := make(map[[2]int]bool)
+	for _, tx := range txs {
+		if tx.ElementOut == 0 {
+			continue
+		}
+		key := [2]int{tx.ElementOut, tx.Entry}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		droppers[tx.ElementOut] = append(droppers[tx.ElementOut], tx.Entry)
+	}
+	for element, entries := range droppers {
+		if len(entries) < 2 {
+			delete(droppers, element)
+		}
+	}
+	return droppers
+}
+
+// findSecondChances walks txs (already in order) and records every add of a
+// player by a different entry than the one that last dropped it. A manager
+// re-adding their own drop is not a second chance.
+func findSecondChances(txs []reconcile.Transaction) []secondChance {
+	type drop struct{ entry, gw int }
+	lastDrop := make(map[int]drop)
+	out := make([]secondChance, 0)
+	for _, tx := range txs {
+		if tx.ElementOut != 0 {
+			lastDrop[tx.ElementOut] = drop{entry: tx.Entry, gw: tx.Event}
+		}
+		if tx.ElementIn == 0 {
+			continue
+		}
+		d, ok := lastDrop[tx.ElementIn]
+		if !ok {
+			continue
+		}
+		delete(lastDrop, tx.ElementIn)
+		if d.entry == tx.Entry {
+			continue
+		}
+		out = append(out, secondChance{
+			Element:   tx.ElementIn,
+			DroppedBy: d.entry,
+			DroppedGW: d.gw,
+			AddedBy:   tx.Entry,
+			AddedGW:   tx.Event,
+		})
+	}
+	return out
+}
+
+// ownershipMoves flattens approved transactions and processed trades into
+// single-element moves, in the order reconcile.BuildOwnershipMapAtGW
+// applies them.
+func ownershipMoves(transactions []reconcile.Transaction, trades []reconcile.Trade) []ownershipMove {
+	moves := make([]ownershipMove, 0, len(transactions)*2)
+	for _, tx := range transactions {
+		if tx.Result != "a" || (tx.Kind != "w" && tx.Kind != "f") {
+			continue
+		}
+		if tx.ElementOut != 0 {
+			moves = append(moves, ownershipMove{Event: tx.Event, Time: tx.Added, ID: tx.ID, Kind: tx.Kind, Element: tx.ElementOut, From: tx.Entry})
+		}
+		if tx.ElementIn != 0 {
+			moves = append(moves, ownershipMove{Event: tx.Event, Time: tx.Added, ID: tx.ID, Kind: tx.Kind, Element: tx.ElementIn, To: tx.Entry})
+		}
+	}
+	for _, tr := range trades {
+		if tr.State != "p" {
+			continue
+		}
+		for _, item := range tr.TradeItems {
+			if item.ElementOut != 0 {
+				moves = append(moves, ownershipMove{Event: tr.Event, Time: tr.ResponseTime, ID: tr.ID, Kind: "trade", Element: item.ElementOut, From: tr.OfferedEntry, To: tr.ReceivedEntry})
+			}
+			if item.ElementIn != 0 {
+				moves = append(moves, ownershipMove{Event: tr.Event, Time: tr.ResponseTime, ID: tr.ID, Kind: "trade", Element: item.ElementIn, From: tr.ReceivedEntry, To: tr.OfferedEntry})
+			}
+		}
+	}
+	sort.SliceStable(moves, func(i, j int) bool {
+		if moves[i].Event != moves[j].Event {
+			return moves[i].Event < moves[j].Event
+		}
+		if moves[i].Time != moves[j].Time {
+			return moves[i].Time < moves[j].Time
+		}
+		return moves[i].ID < moves[j].ID
+	})
+	return moves
+}
+
+// ownerStints returns who owned element for which GWs in [fromGW, throughGW].
+// A move in event e takes effect for GW e. Owners before the first move are
+// inferred from that move's From (the drafting manager). Free-agent spans
+// are omitted and EntryName/Points are left for the caller.
+func ownerStints(moves []ownershipMove, element int, fromGW int, throughGW int) []OwnerStint {
+	type span struct{ owner, from int }
+	spans := make([]span, 0)
+	for _, m := range moves {
+		if m.Element != element {
+			continue
+		}
+		if len(spans) == 0 {
+			spans = append(spans, span{owner: m.From, from: 1})
+		}
+		if last := &spans[len(spans)-1]; last.from == m.Event {
+			// Several moves in one event: only the final owner holds the GW.
+			last.owner = m.To
+			continue
+		}
+		spans = append(spans, span{owner: m.To, from: m.Event})
+	}
+	out := make([]OwnerStint, 0, len(spans))
+	for i, s := range spans {
+		to := throughGW
+		if i+1 < len(spans) {
+			to = spans[i+1].from - 1
+		}
+		from := s.from
+		if from < fromGW {
+			from = fromGW
+		}
+		if to > throughGW {
+			to = throughGW
+		}
+		if s.owner == 0 || from > to {
+			continue
+		}
+		out = append(out, OwnerStint{EntryID: s.owner, FromGW: from, ToGW: to})
+	}
+	return out
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/reconcile"
+)
+
+func wireTx(id, entry, event, in, out int) reconcile.Transaction {
+	return reconcile.Transaction{ID: id, Entry: entry, Event: event, ElementIn: in, ElementOut: out, Kind: "w", Result: "a"}
+}
+
+func TestApprovedTransactions_FiltersAndOrders(t *testing.T) {
+	txs := []reconcile.Transaction{
+		wireTx(9, 200, 5, 1, 2),
+		wireTx(3, 201, 5, 3, 4),
+		wireTx(1, 200, 4, 5, 6),
+		wireTx(2, 200, 2, 7, 8), // before the window
+		{ID: 4, Entry: 201, Event: 5, ElementIn: 9, ElementOut: 10, Kind: "w", Result: "di"},
+	}
+	got := approvedTransactions(txs, 3, 5)
+	if len(got) != 3 {
+		t.Fatalf("got %d transactions, want 3", len(got))
+	}
+	if got[0].ID != 1 || got[1].ID != 3 || got[2].ID != 9 {
+		t.Errorf("order = %d,%d,%d, want 1,3,9", got[0].ID, got[1].ID, got[2].ID)
+	}
+}
+
+func TestRisingAdds(t *testing.T) {
+	txs := []reconcile.Transaction{
+		wireTx(1, 200, 3, 10, 0),
+		wireTx(2, 201, 4, 0, 10),
+		wireTx(3, 202, 4, 10, 0), // 10: 1 add in GW3, 1 in GW4 — flat
+		wireTx(4, 200, 4, 11, 0),
+		wireTx(5, 201, 4, 0, 11),
+		wireTx(6, 202, 4, 11, 0), // 11: 0 then 2 — rising
+		wireTx(7, 200, 3, 12, 0), // 12: 1 then 0 — falling
+	}
+	got := risingAdds(txs, 3, 4)
+	if len(got) != 1 {
+		t.Fatalf("rising = %+v, want only element 11", got)
+	}
+	if r := got[0]; r.Element != 11 || r.Change != 2 || r.TotalAdds != 2 || r.AddsByGW[0] != 0 || r.AddsByGW[1] != 2 {
+		t.Errorf("rising = %+v", r)
+	}
+	if got := risingAdds(txs, 4, 4); got != nil {
+		t.Errorf("single-GW window = %+v, want nil", got)
+	}
+}
+
+func TestMultiDropped(t *testing.T) {
+	txs := []reconcile.Transaction{
+		wireTx(1, 200, 3, 0, 10),
+		wireTx(2, 201, 3, 10, 0),
+		wireTx(3, 201, 4, 0, 10),
+		wireTx(4, 200, 4, 0, 11),
+		wireTx(5, 200, 5, 11, 0),
+		wireTx(6, 200, 5, 0, 11), // same manager twice counts once
+	}
+	got := multiDropped(txs)
+	if len(got) != 1 {
+		t.Fatalf("multi-dropped = %v, want only element 10", got)
+	}
+	if d := got[10]; len(d) != 2 || d[0] != 200 || d[1] != 201 {
+		t.Errorf("droppers of 10 = %v, want [200 201]", d)
+	}
+}
+
+func TestFindSecondChances(t *testing.T) {
+	t.Run("DifferentManager", func(t *testing.T) {
+		txs := []reconcile.Transaction{
+			wireTx(1, 200, 3, 20, 10),
+			wireTx(2, 201, 4, 10, 21),
+		}
+		got := findSecondChances(txs)
+		if len(got) != 1 {
+			t.Fatalf("got %+v, want one", got)
+		}
+		if sc := got[0]; sc.Element != 10 || sc.DroppedBy != 200 || sc.DroppedGW != 3 || sc.AddedBy != 201 || sc.AddedGW != 4 {
+			t.Errorf("second chance = %+v", sc)
+		}
+	})
+
+	t.Run("SameManagerReAdd", func(t *testing.T) {
+		txs := []reconcile.Transaction{
+			wireTx(1, 200, 3, 20, 10),
+			wireTx(2, 200, 4, 10, 20),
+		}
+		if got := findSecondChances(txs); len(got) != 0 {
+			t.Errorf("got %+v, want none", got)
+		}
+	})
+
+	t.Run("AddBeforeDropIsNotSecondChance", func(t *testing.T) {
+		// Within one event the add (id 1) precedes the drop (id 2), so the
+		// drop is the later move and nobody has re-added the player yet.
+		txs := approvedTransactions([]reconcile.Transaction{
+			wireTx(2, 200, 3, 20, 10),
+			wireTx(1, 201, 3, 10, 21),
+		}, 1, 5)
+		if got := findSecondChances(txs); len(got) != 0 {
+			t.Errorf("got %+v, want none", got)
+		}
+	})
+}
+
+func TestOwnerStints(t *testing.T) {
+	transactions := []reconcile.Transaction{
+		wireTx(1, 200, 3, 0, 10), // drafted by 200, dropped for GW3
+		wireTx(2, 201, 5, 10, 0), // 201 picks up for GW5
+	}
+	trades := []reconcile.Trade{{
+		ID: 1, Event: 7, OfferedEntry: 201, ReceivedEntry: 202, State: "p",
+		TradeItems: []reconcile.TradeItem{{ElementOut: 10}},
+	}}
+	moves := ownershipMoves(transactions, trades)
+
+	got := ownerStints(moves, 10, 2, 8)
+	want := []OwnerStint{
+		{EntryID: 200, FromGW: 2, ToGW: 2},
+		{EntryID: 201, FromGW: 5, ToGW: 6},
+		{EntryID: 202, FromGW: 7, ToGW: 8},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("stints = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("stint %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	t.Run("SameEventDropAndAdd", func(t *testing.T) {
+		moves := ownershipMoves([]reconcile.Transaction{
+			wireTx(1, 200, 4, 20, 10),
+			wireTx(2, 201, 4, 10, 21),
+		}, nil)
+		got := ownerStints(moves, 10, 1, 5)
+		if len(got) != 2 || got[0].EntryID != 200 || got[0].ToGW != 3 || got[1].EntryID != 201 || got[1].FromGW != 4 {
+			t.Errorf("stints = %+v, want 200 through GW3 then 201 from GW4", got)
+		}
+	})
+}
+
+func TestBuildWaiverWireTrends(t *testing.T) {
+	dir, cfg := tmpCfg(t)
+	writeBootstrap(t, dir)
+	writeGameJSON(t, dir, 4)
+	writeLeagueDetailsFixture(t, dir, 100, []any{
+		map[string]any{"id": 1, "entry_id": 200, "entry_name": "Alpha FC", "short_name": "AFC"},
+		map[string]any{"id": 2, "entry_id": 201, "entry_name": "Beta FC", "short_name": "BFC"},
+	}, []any{})
+	tx := func(id, entry, event, in, out int) map[string]any {
+		return map[string]any{"id": id, "entry": entry, "event": event, "element_in": in, "element_out": out, "kind": "w", "result": "a"}
+	}
+	// Salah (1): drafted by Alpha, dropped for GW3, Beta picks Salah up for GW4.
+	writeJSON(t, filepath.Join(dir, "league/100/transactions.json"), map[string]any{
+		"transactions": []any{
+			tx(1, 200, 3, 3, 1),
+			tx(2, 201, 4, 1, 2),
+		},
+	})
+	writeJSON(t, filepath.Join(dir, "league/100/trades.json"), map[string]any{"trades": []any{}})
+	for gw, pts := range map[int]int{1: 5, 2: 7, 3: 12, 4: 9} {
+		writeLiveJSON(t, dir, gw, map[string]any{
+			"1": map[string]any{"stats": map[string]any{"total_points": pts}},
+		})
+	}
+
+	window := 3
+	out, err := buildWaiverWireTrends(cfg, WaiverWireTrendsArgs{LeagueID: 100, Window: &window})
+	if err != nil {
+		t.Fatalf("buildWaiverWireTrends: %v", err)
+	}
+	if out.FromGW != 2 || out.ThroughGW != 4 || out.Transactions != 2 {
+		t.Errorf("window %d..%d with %d transactions, want 2..4 with 2", out.FromGW, out.ThroughGW, out.Transactions)
+	}
+	if len(out.Churn) != 2 || out.Churn[0].Transactions != 1 {
+		t.Errorf("churn = %+v", out.Churn)
+	}
+	if len(out.SecondChances) != 1 {
+		t.Fatalf("second chances = %+v, want Salah", out.SecondChances)
+	}
+	sc := out.SecondChances[0]
+	if sc.PlayerName != "Salah" || sc.DroppedBy != "Alpha FC" || sc.AddedBy != "Beta FC" {
+		t.Errorf("second chance = %+v", sc)
+	}
+	if len(sc.Stints) != 2 || sc.Stints[0].Points != 7 || sc.Stints[1].Points != 9 {
+		t.Errorf("stints = %+v, want Alpha 7 (GW2) and Beta 9 (GW4)", sc.Stints)
+	}
+	if len(out.RisingAdds) != 1 || out.RisingAdds[0].Element != 1 {
+		t.Errorf("rising = %+v, want Salah", out.RisingAdds)
+	}
+
+	if _, err := buildWaiverWireTrends(cfg, WaiverWireTrendsArgs{}); classifyError(err).Code != codeInvalidArgument {
+		t.Errorf("missing league_id: err = %v", err)
+	}
+}
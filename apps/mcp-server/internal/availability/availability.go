@@ -0,0 +1,167 @@
+// Package availability diffs two bootstrap-static snapshots for changes in
+// player status, chance of playing and news.
+package availability
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/jsonutil"
+)
+
+// Severity tags for a change, judged from the new state.
+const (
+	SeverityOut      = "out"
+	SeverityDoubtful = "doubtful"
+	SeverityReturned = "returned"
+)
+
+// Change kinds.
+const (
+	KindChanged = "changed"
+	KindAdded   = "added"   // in the current bootstrap only
+	KindRemoved = "removed" // in the previous bootstrap only
+)
+
+// Player is the subset of a bootstrap element the diff needs.
+type Player struct {
+	ID              int    `json:"id"`
+	Name            string `json:"web_name"`
+	Team            int    `json:"team"`
+	PositionType    int    `json:"element_type"`
+	Status          string `json:"status"`
+	ChanceOfPlaying *int   `json:"chance_of_playing_next_round"`
+	News            string `json:"news"`
+	TotalPoints     int    `json:"total_points"`
+}
+
+// UnmarshalJSON reads chance_of_playing_next_round and total_points with
+// jsonutil, so a quoted number decodes like a plain one. A null chance stays
+// nil.
+func (p *Player) UnmarshalJSON(b []byte) error {
+	type plain Player
+	aux := struct {
+		*plain
+		ChanceOfPlaying *jsonutil.FlexInt `json:"chance_of_playing_next_round"`
+		TotalPoints     jsonutil.FlexInt  `json:"total_points"`
+	}{plain: (*plain)(p)}
+	if err := json.Unmarshal(b, &aux); err != nil {
+		return err
+	}
+	p.ChanceOfPlaying = nil
+	if aux.ChanceOfPlaying != nil {
+		chance := int(*aux.ChanceOfPlaying)
+		p.ChanceOfPlaying = &chance
+	}
+	p.TotalPoints = int(aux.TotalPoints)
+	return nil
+}
+
+// State is the availability part of a Player. A nil ChanceOfPlaying means
+// the API gave none, which for status "a" is the same as 100.
+type State struct {
+	Status          string `json:"status"`
+	ChanceOfPlaying *int   `json:"chance_of_playing,omitempty"`
+	News            string `json:"news,omitempty"`
+}
+
+// Change is one player whose availability differs between two snapshots.
+// Player is the current element, or the previous one for KindRemoved. Old
+// is nil for KindAdded and New is nil for KindRemoved. Severity is empty
+// when the player is available and was before (a news-only change, or a
+// new player arriving fit).
+type Change struct {
+	Player   Player `json:"player"`
+	Kind     string `json:"kind"`
+	Old      *State `json:"old,omitempty"`
+	New      *State `json:"new,omitempty"`
+	Severity string `json:"severity,omitempty"`
+}
+
+// Parse decodes the elements of a bootstrap-static.json body by id.
+func Parse(raw []byte) (map[int]Player, error) {
+	var resp struct {
+		Elements []Player `json:"elements"`
+	}
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return nil, err
+	}
+	out := make(map[int]Player, len(resp.Elements))
+	for _, p := range resp.Elements {
+		out[p.ID] = p
+	}
+	return out, nil
+}
+
+func (p Player) state() *State {
+	return &State{Status: p.Status, ChanceOfPlaying: p.ChanceOfPlaying, News: p.News}
+}
+
+func (s *State) equal(o *State) bool {
+	if s.Status != o.Status || s.News != o.News {
+		return false
+	}
+	if s.ChanceOfPlaying == nil || o.ChanceOfPlaying == nil {
+		return s.ChanceOfPlaying == nil && o.ChanceOfPlaying == nil
+	}
+	return *s.ChanceOfPlaying == *o.ChanceOfPlaying
+}
+
+// available reports whether s is fully fit: status "a" with no reduced
+// chance of playing.
+func (s *State) available() bool {
+	return s.Status == "a" && (s.ChanceOfPlaying == nil || *s.ChanceOfPlaying >= 100)
+}
+
+// classify tags a change. Injured, suspended, unavailable, ineligible, a 0%
+// chance and leaving the game entirely are out; status "d" or any other
+// reduced chance is doubtful; becoming fully fit after not being is a
+// return.
+func classify(old, cur *State) string {
+	if cur == nil {
+		return SeverityOut
+	}
+	switch cur.Status {
+	case "i", "s", "u", "n":
+		return SeverityOut
+	}
+	if cur.ChanceOfPlaying != nil && *cur.ChanceOfPlaying <= 0 {
+		return SeverityOut
+	}
+	if !cur.available() {
+		return SeverityDoubtful
+	}
+	if old != nil && !old.available() {
+		return SeverityReturned
+	}
+	return ""
+}
+
+// Diff lists the players whose status, chance of playing or news differs
+// between prev and cur, including players that appear in or disappear from
+// bootstrap, ordered by element id.
+func Diff(prev, cur map[int]Player) []Change {
+	out := make([]Change, 0)
+	for id, p := range cur {
+		q, ok := prev[id]
+		if !ok {
+			s := p.state()
+			out = append(out, Change{Player: p, Kind: KindAdded, New: s, Severity: classify(nil, s)})
+			continue
+		}
+		old, s := q.state(), p.state()
+		if old.equal(s) {
+			continue
+		}
+		out = append(out, Change{Player: p, Kind: KindChanged, Old: old, New: s, Severity: classify(old, s)})
+	}
+	for id, q := range prev {
+		if _, ok := cur[id]; ok {
+			continue
+		}
+		old := q.state()
+		out = append(out, Change{Player: q, Kind: KindRemoved, Old: old, Severity: classify(old, nil)})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Player.ID < out[j].Player.ID })
+	return out
+}
@@ -0,0 +1,98 @@
+package availability
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	raw := []byte(`{"elements": [
+		{"id": 1, "web_name": "Salah", "team": 10, "element_type": 3, "status": "a", "chance_of_playing_next_round": null, "news": "", "total_points": 80},
+		{"id": 2, "web_name": "Saka", "team": 1, "element_type": 3, "status": "d", "chance_of_playing_next_round": 75, "news": "Knock - 75% chance of playing"}
+	]}`)
+	got, err := Parse(raw)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(got) != 2 || got[1].ChanceOfPlaying != nil || got[1].TotalPoints != 80 {
+		t.Errorf("salah = %+v", got[1])
+	}
+	if p := got[2]; p.Status != "d" || p.ChanceOfPlaying == nil || *p.ChanceOfPlaying != 75 || p.News == "" {
+		t.Errorf("saka = %+v", p)
+	}
+}
+
+// The API has sent numeric fields quoted; they decode like plain numbers.
+func TestParse_QuotedNumbers(t *testing.T) {
+	raw := []byte(`{"elements": [
+		{"id": 2, "web_name": "Saka", "status": "d", "chance_of_playing_next_round": "75", "total_points": "41"}
+	]}`)
+	got, err := Parse(raw)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if p := got[2]; p.ChanceOfPlaying == nil || *p.ChanceOfPlaying != 75 || p.TotalPoints != 41 || p.Name != "Saka" {
+		t.Errorf("saka = %+v", p)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	pct := func(v int) *int { return &v }
+	prev := map[int]Player{
+		1: {ID: 1, Name: "Salah", Status: "a"},
+		2: {ID: 2, Name: "Saka", Status: "a"},
+		3: {ID: 3, Name: "Isak", Status: "i", ChanceOfPlaying: pct(0), News: "Hamstring"},
+		4: {ID: 4, Name: "Palmer", Status: "a", News: ""},
+		5: {ID: 5, Name: "Rodri", Status: "d", ChanceOfPlaying: pct(50)},
+		6: {ID: 6, Name: "Sold", Status: "a"},
+	}
+	cur := map[int]Player{
+		1: {ID: 1, Name: "Salah", Status: "a"},                                             // unchanged
+		2: {ID: 2, Name: "Saka", Status: "d", ChanceOfPlaying: pct(75), News: "Knock"},     // doubtful
+		3: {ID: 3, Name: "Isak", Status: "a", ChanceOfPlaying: pct(100)},                   // returned
+		4: {ID: 4, Name: "Palmer", Status: "a", News: "Expected back for training"},        // news only
+		5: {ID: 5, Name: "Rodri", Status: "d", ChanceOfPlaying: pct(0), News: "Surgery"},   // out
+		7: {ID: 7, Name: "Signing", Status: "u", News: "Joined on loan, not eligible yet"}, // appeared
+	}
+	got := Diff(prev, cur)
+	want := []struct {
+		id       int
+		kind     string
+		severity string
+	}{
+		{2, KindChanged, SeverityDoubtful},
+		{3, KindChanged, SeverityReturned},
+		{4, KindChanged, ""},
+		{5, KindChanged, SeverityOut},
+		{6, KindRemoved, SeverityOut},
+		{7, KindAdded, SeverityOut},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d changes, want %d: %+v", len(got), len(want), got)
+	}
+	for i, w := range want {
+		c := got[i]
+		if c.Player.ID != w.id || c.Kind != w.kind || c.Severity != w.severity {
+			t.Errorf("change %d = %d/%s/%q, want %d/%s/%q", i, c.Player.ID, c.Kind, c.Severity, w.id, w.kind, w.severity)
+		}
+	}
+
+	if c := got[0]; c.Old == nil || c.Old.Status != "a" || c.New == nil || *c.New.ChanceOfPlaying != 75 {
+		t.Errorf("saka old/new = %+v / %+v", c.Old, c.New)
+	}
+	if c := got[4]; c.New != nil || c.Old == nil || c.Player.Name != "Sold" {
+		t.Errorf("removed player = %+v, want old state only", c)
+	}
+	if c := got[5]; c.Old != nil || c.New == nil || c.New.Status != "u" {
+		t.Errorf("new player = %+v, want new state only", c)
+	}
+}
+
+func TestDiff_NilChanceMatchesAvailable(t *testing.T) {
+	pct := func(v int) *int { return &v }
+	prev := map[int]Player{1: {ID: 1, Status: "d", ChanceOfPlaying: pct(75)}}
+	cur := map[int]Player{1: {ID: 1, Status: "a"}}
+	if got := Diff(prev, cur); len(got) != 1 || got[0].Severity != SeverityReturned {
+		t.Errorf("diff = %+v, want one return", got)
+	}
+	if got := Diff(cur, cur); len(got) != 0 {
+		t.Errorf("identical snapshots = %+v, want no changes", got)
+	}
+}
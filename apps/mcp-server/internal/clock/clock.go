@@ -0,0 +1,38 @@
+// Package clock is the time source for everything that stamps or schedules
+// by the wall clock. Code takes a Clock instead of calling time.Now, so a
+// test (or a reproducible rebuild) can pin the time with Fixed.
+package clock
+
+import "time"
+
+// Clock reports the current time.
+type Clock interface {
+	Now() time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+// Real is the wall clock.
+var Real Clock = realClock{}
+
+// Fixed is a clock stopped at one instant.
+type Fixed time.Time
+
+func (f Fixed) Now() time.Time { return time.Time(f) }
+
+// Or returns c, or Real when c is nil, so a zero-valued config or options
+// struct keeps the wall clock.
+func Or(c Clock) Clock {
+	if c == nil {
+		return Real
+	}
+	return c
+}
+
+// UTC formats t the way generated_at_utc and the other *_utc fields are
+// written: RFC 3339 in UTC, to the second.
+func UTC(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,31 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFixed(t *testing.T) {
+	at := time.Date(2025, 10, 18, 14, 0, 0, 0, time.FixedZone("BST", 3600))
+	c := Fixed(at)
+	if !c.Now().Equal(at) || !c.Now().Equal(c.Now()) {
+		t.Errorf("Fixed.Now() = %v, want %v every time", c.Now(), at)
+	}
+	if got := UTC(c.Now()); got != "2025-10-18T13:00:00Z" {
+		t.Errorf("UTC = %q", got)
+	}
+}
+
+func TestOr(t *testing.T) {
+	if Or(nil) != Real {
+		t.Error("Or(nil) is not the real clock")
+	}
+	f := Fixed(time.Unix(0, 0))
+	if Or(f) != f {
+		t.Error("Or dropped the given clock")
+	}
+	before := time.Now()
+	if now := Or(nil).Now(); now.Before(before) {
+		t.Errorf("real clock went backwards: %v < %v", now, before)
+	}
+}
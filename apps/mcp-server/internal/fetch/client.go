@@ -1,6 +1,7 @@
 package fetch
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -9,6 +10,10 @@ import (
 	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
 )
 
+// ErrNotFound is wrapped by a fetch the API answered with 404, such as an
+// entry event for a GW before the entry joined the league.
+var ErrNotFound = errors.New("not found")
+
 type Client struct {
 	HTTP         *http.Client
 	Store        *store.JSONStore
@@ -39,6 +44,21 @@ func (c *Client) FetchRaw(urlPath string, relPath string, force bool) ([]byte, e
 		return c.Store.ReadRaw(relPath)
 	}
 
+	body, err := c.get(urlPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if !c.DisableWrite {
+		if err := c.Store.WriteRaw(relPath, body, c.PrettyWrite); err != nil {
+			return nil, err
+		}
+	}
+	return body, nil
+}
+
+// get performs a single GET against BaseURL+urlPath without touching the store.
+func (c *Client) get(urlPath string) ([]byte, error) {
 	if c.Sleep > 0 {
 		time.Sleep(c.Sleep)
 	}
@@ -57,14 +77,11 @@ func (c *Client) FetchRaw(urlPath string, relPath string, force bool) ([]byte, e
 	defer resp.Body.Close()
 
 	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("GET %s: %w", urlPath, ErrNotFound)
+	}
 	if resp.StatusCode < 200 || resp.StatusCode > 299 {
 		return nil, fmt.Errorf("GET %s failed: %d body=%s", urlPath, resp.StatusCode, string(body))
 	}
-
-	if !c.DisableWrite {
-		if err := c.Store.WriteRaw(relPath, body, c.PrettyWrite); err != nil {
-			return nil, err
-		}
-	}
 	return body, nil
 }
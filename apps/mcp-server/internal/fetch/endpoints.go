@@ -13,13 +13,27 @@ func (c *Client) LeagueDetails(leagueID int, force bool) error {
 }
 
 // /bootstrap-static
+//
+// A refetch keeps the copy it replaces as bootstrap-static.prev.json, so
+// status and news changes between two refreshes can be diffed.
 func (c *Client) BootstrapStatic(force bool) error {
-	_, err := c.FetchRaw(
-		"/bootstrap-static",
-		"bootstrap/bootstrap-static.json",
-		force,
-	)
-	return err
+	const relPath = "bootstrap/bootstrap-static.json"
+	if !force && c.UseCache && c.Store.Exists(relPath) {
+		return nil
+	}
+	body, err := c.get("/bootstrap-static")
+	if err != nil {
+		return err
+	}
+	if c.DisableWrite {
+		return nil
+	}
+	if prev, err := c.Store.ReadRaw(relPath); err == nil {
+		if err := c.Store.WriteRaw("bootstrap/bootstrap-static.prev.json", prev, false); err != nil {
+			return err
+		}
+	}
+	return c.Store.WriteRaw(relPath, body, c.PrettyWrite)
 }
 
 // /draft/{league_id}/choices
@@ -33,13 +47,22 @@ func (c *Client) DraftChoices(leagueID int, force bool) error {
 }
 
 // /draft/league/{league_id}/transactions
+//
+// Busy leagues get a truncated response, so pages are followed until complete
+// and written as one merged file (see transactions.go).
 func (c *Client) LeagueTransactions(leagueID int, force bool) error {
-	_, err := c.FetchRaw(
-		fmt.Sprintf("/draft/league/%d/transactions", leagueID),
-		fmt.Sprintf("league/%d/transactions.json", leagueID),
-		force,
-	)
-	return err
+	relPath := fmt.Sprintf("league/%d/transactions.json", leagueID)
+	if !force && c.UseCache && c.Store.Exists(relPath) {
+		return nil
+	}
+	body, err := c.fetchAllTransactions(fmt.Sprintf("/draft/league/%d/transactions", leagueID))
+	if err != nil {
+		return err
+	}
+	if c.DisableWrite {
+		return nil
+	}
+	return c.Store.WriteRaw(relPath, body, c.PrettyWrite)
 }
 
 // /draft/league/{league_id}/trades
@@ -68,6 +91,8 @@ func (c *Client) EventLive(gw int, force bool) error {
 }
 
 // /entry/{entry_id}/event/{gw}
+//
+// A GW before the entry joined the league is a 404, returned as ErrNotFound.
 func (c *Client) EntryEvent(entryID int, gw int, force bool) error {
 	_, err := c.FetchRaw(
 		fmt.Sprintf("/entry/%d/event/%d", entryID, gw),
@@ -0,0 +1,37 @@
+package fetch
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestBootstrapStatic_KeepsPreviousCopy(t *testing.T) {
+	version := 0
+	c, st := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		version++
+		fmt.Fprintf(w, `{"version":%d}`, version)
+	})
+	c.PrettyWrite = false
+
+	if err := c.BootstrapStatic(true); err != nil {
+		t.Fatalf("first fetch: %v", err)
+	}
+	if st.Exists("bootstrap/bootstrap-static.prev.json") {
+		t.Error("prev written with nothing to rotate")
+	}
+	if err := c.BootstrapStatic(false); err != nil || version != 1 {
+		t.Fatalf("cached fetch: err=%v calls=%d, want the cache", err, version)
+	}
+	if err := c.BootstrapStatic(true); err != nil {
+		t.Fatalf("refetch: %v", err)
+	}
+	cur, _ := st.ReadRaw("bootstrap/bootstrap-static.json")
+	prev, err := st.ReadRaw("bootstrap/bootstrap-static.prev.json")
+	if err != nil {
+		t.Fatalf("prev not kept: %v", err)
+	}
+	if string(cur) != `{"version":2}` || string(prev) != `{"version":1}` {
+		t.Errorf("current=%s prev=%s, want versions 2 and 1", cur, prev)
+	}
+}
@@ -0,0 +1,129 @@
+package fetch
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// maxTransactionPages bounds pagination in case the API keeps reporting more
+// data without ever returning new transactions.
+const maxTransactionPages = 200
+
+// pagingKeys are the top-level fields the transactions endpoint may use to
+// signal truncation. The API documents none of them, so all three are
+// checked; they describe a single page and are dropped from the merged file.
+var pagingKeys = []string{"count", "next", "has_next"}
+
+// transactionsPage is one response from the transactions endpoint. The paging
+// fields are optional: untruncated responses carry only "transactions".
+// Fields holds every top-level key as returned so keys this client doesn't
+// know about survive the merge.
+type transactionsPage struct {
+	Transactions []json.RawMessage          `json:"transactions"`
+	Count        *int                       `json:"count,omitempty"`
+	Next         *string                    `json:"next,omitempty"`
+	HasNext      *bool                      `json:"has_next,omitempty"`
+	Fields       map[string]json.RawMessage `json:"-"`
+}
+
+func decodeTransactionsPage(body []byte) (transactionsPage, error) {
+	var p transactionsPage
+	if err := json.Unmarshal(body, &p); err != nil {
+		return p, err
+	}
+	if err := json.Unmarshal(body, &p.Fields); err != nil {
+		return p, err
+	}
+	return p, nil
+}
+
+// truncated reports whether the page signals more data beyond the seen
+// transactions collected so far.
+func (p transactionsPage) truncated(seen int) bool {
+	if p.HasNext != nil && *p.HasNext {
+		return true
+	}
+	if p.Next != nil && *p.Next != "" {
+		return true
+	}
+	return p.Count != nil && *p.Count > seen
+}
+
+// nextURL returns the path of the page after pageNum. An explicit "next" link
+// wins; otherwise ?page= is appended to base.
+func (c *Client) nextURL(p transactionsPage, base string, pageNum int) string {
+	if p.Next != nil && *p.Next != "" {
+		next := strings.TrimPrefix(*p.Next, c.BaseURL)
+		if strings.HasPrefix(next, "/") {
+			return next
+		}
+	}
+	return fmt.Sprintf("%s?page=%d", base, pageNum+1)
+}
+
+// fetchAllTransactions pages through urlPath and returns a single
+// {"transactions": [...]} document with duplicates removed by id and entries
+// sorted by id, matching the single-page on-disk format. Top-level keys other
+// than the paging fields are passed through from the first page.
+func (c *Client) fetchAllTransactions(urlPath string) ([]byte, error) {
+	byID := make(map[int]json.RawMessage)
+	var fields map[string]json.RawMessage
+	next := urlPath
+	for pageNum := 1; pageNum <= maxTransactionPages; pageNum++ {
+		body, err := c.get(next)
+		if err != nil {
+			return nil, err
+		}
+		page, err := decodeTransactionsPage(body)
+		if err != nil {
+			return nil, fmt.Errorf("decode transactions page %d: %w", pageNum, err)
+		}
+		if pageNum == 1 {
+			fields = page.Fields
+		}
+		added := 0
+		for _, raw := range page.Transactions {
+			var tx struct {
+				ID int `json:"id"`
+			}
+			if err := json.Unmarshal(raw, &tx); err != nil {
+				return nil, fmt.Errorf("decode transaction on page %d: %w", pageNum, err)
+			}
+			if _, ok := byID[tx.ID]; !ok {
+				byID[tx.ID] = raw
+				added++
+			}
+		}
+		// Stop once the API says we're done, or when a page brings nothing
+		// new (guards against servers that ignore the page parameter).
+		if !page.truncated(len(byID)) || added == 0 {
+			break
+		}
+		next = c.nextURL(page, urlPath, pageNum)
+	}
+
+	ids := make([]int, 0, len(byID))
+	for id := range byID {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	merged := make([]json.RawMessage, 0, len(ids))
+	for _, id := range ids {
+		merged = append(merged, byID[id])
+	}
+	// Other top-level keys come from the first page, the most recent one.
+	if fields == nil {
+		fields = make(map[string]json.RawMessage)
+	}
+	for _, key := range pagingKeys {
+		delete(fields, key)
+	}
+	txs, err := json.Marshal(merged)
+	if err != nil {
+		return nil, err
+	}
+	fields["transactions"] = txs
+	return json.Marshal(fields)
+}
@@ -0,0 +1,135 @@
+package fetch
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) (*Client, *store.JSONStore) {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	st := store.NewJSONStore(t.TempDir())
+	c := NewClient(st)
+	c.BaseURL = srv.URL
+	c.Sleep = 0
+	return c, st
+}
+
+func readTransactionIDs(t *testing.T, st *store.JSONStore, leagueID int) []int {
+	t.Helper()
+	raw, err := st.ReadRaw(fmt.Sprintf("league/%d/transactions.json", leagueID))
+	if err != nil {
+		t.Fatalf("read transactions: %v", err)
+	}
+	var resp struct {
+		Transactions []struct {
+			ID int `json:"id"`
+		} `json:"transactions"`
+	}
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		t.Fatalf("decode transactions: %v", err)
+	}
+	ids := make([]int, 0, len(resp.Transactions))
+	for _, tx := range resp.Transactions {
+		ids = append(ids, tx.ID)
+	}
+	return ids
+}
+
+func TestLeagueTransactions_SinglePage(t *testing.T) {
+	calls := 0
+	c, st := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Write([]byte(`{"transactions":[{"id":2,"event":3},{"id":1,"event":1}]}`))
+	})
+	if err := c.LeagueTransactions(7, true); err != nil {
+		t.Fatalf("LeagueTransactions: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1 for an untruncated response", calls)
+	}
+	if got := readTransactionIDs(t, st, 7); fmt.Sprint(got) != "[1 2]" {
+		t.Errorf("ids = %v, want [1 2]", got)
+	}
+}
+
+func TestLeagueTransactions_PagesAndDedupes(t *testing.T) {
+	c, st := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("page") {
+		case "":
+			w.Write([]byte(`{"count":4,"has_next":true,"transactions":[{"id":4},{"id":3}]}`))
+		case "2":
+			// Page 2 overlaps page 1 on id 3.
+			w.Write([]byte(`{"count":4,"has_next":false,"transactions":[{"id":3},{"id":2},{"id":1}]}`))
+		default:
+			t.Errorf("unexpected page %q", r.URL.Query().Get("page"))
+			w.Write([]byte(`{"transactions":[]}`))
+		}
+	})
+	if err := c.LeagueTransactions(7, true); err != nil {
+		t.Fatalf("LeagueTransactions: %v", err)
+	}
+	if got := readTransactionIDs(t, st, 7); fmt.Sprint(got) != "[1 2 3 4]" {
+		t.Errorf("ids = %v, want [1 2 3 4]", got)
+	}
+}
+
+func TestLeagueTransactions_StopsWhenPageAddsNothing(t *testing.T) {
+	calls := 0
+	c, st := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		// Claims more data but ignores the page parameter.
+		w.Write([]byte(`{"count":10,"transactions":[{"id":1}]}`))
+	})
+	if err := c.LeagueTransactions(7, true); err != nil {
+		t.Fatalf("LeagueTransactions: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("calls = %d, want 2 (second page repeats the first)", calls)
+	}
+	if got := readTransactionIDs(t, st, 7); fmt.Sprint(got) != "[1]" {
+		t.Errorf("ids = %v, want [1]", got)
+	}
+}
+
+func TestLeagueTransactions_KeepsUnknownTopLevelKeys(t *testing.T) {
+	c, st := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("page") {
+		case "":
+			w.Write([]byte(`{"count":2,"has_next":true,"league":{"id":7},"transactions":[{"id":2}]}`))
+		default:
+			w.Write([]byte(`{"count":2,"has_next":false,"league":{"id":0},"transactions":[{"id":1}]}`))
+		}
+	})
+	if err := c.LeagueTransactions(7, true); err != nil {
+		t.Fatalf("LeagueTransactions: %v", err)
+	}
+	raw, err := st.ReadRaw("league/7/transactions.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		t.Fatal(err)
+	}
+	var league struct {
+		ID int `json:"id"`
+	}
+	if err := json.Unmarshal(doc["league"], &league); err != nil || league.ID != 7 {
+		t.Errorf("league = %s, want the first page's value passed through", doc["league"])
+	}
+	for _, key := range pagingKeys {
+		if _, ok := doc[key]; ok {
+			t.Errorf("paging key %q written to the merged file", key)
+		}
+	}
+	if got := readTransactionIDs(t, st, 7); fmt.Sprint(got) != "[1 2]" {
+		t.Errorf("ids = %v, want [1 2]", got)
+	}
+}
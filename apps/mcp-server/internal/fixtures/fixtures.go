@@ -0,0 +1,243 @@
+// Package fixtures reads the Premier League fixtures out of
+// bootstrap-static.json. The draft API has served them both as an object
+// keyed by GW ({"1": [...], "2": [...]}) and as a flat array whose entries
+// carry their own event; both decode to the same []Fixture here, so a shape
+// change can't silently leave every caller with no fixtures.
+//
+// A postponed fixture loses its GW: the API sends event null until it is
+// rescheduled. Such fixtures keep Event 0 here and are left out of the per-GW
+// views; Unscheduled lists them.
+package fixtures
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Shape is the form the fixtures field was found in.
+type Shape string
+
+const (
+	// ShapeMap is the object keyed by GW.
+	ShapeMap Shape = "map"
+	// ShapeArray is a flat array with an event per fixture.
+	ShapeArray Shape = "array"
+	// ShapeEmpty is a missing, null or empty fixtures field.
+	ShapeEmpty Shape = "empty"
+)
+
+// Fixture is one Premier League fixture. Kickoff is the API's kickoff_time,
+// empty when it was null (not yet scheduled). Event is 0 for a postponed
+// fixture not yet given a new GW.
+type Fixture struct {
+	ID       int
+	Event    int
+	TeamH    int
+	TeamA    int
+	Kickoff  string
+	Started  bool
+	Finished bool
+}
+
+type rawFixture struct {
+	ID          int    `json:"id"`
+	Event       int    `json:"event"`
+	TeamH       int    `json:"team_h"`
+	TeamA       int    `json:"team_a"`
+	KickoffTime string `json:"kickoff_time"`
+	Started     bool   `json:"started"`
+	Finished    bool   `json:"finished"`
+}
+
+func (f rawFixture) fixture(event int) Fixture {
+	if f.Event != 0 {
+		event = f.Event
+	}
+	return Fixture{ID: f.ID, Event: event, TeamH: f.TeamH, TeamA: f.TeamA, Kickoff: f.KickoffTime, Started: f.Started, Finished: f.Finished}
+}
+
+// Set is the fixtures read from one bootstrap, ordered by GW then id.
+type Set struct {
+	Fixtures []Fixture
+	Shape    Shape
+}
+
+// ByEvent groups the scheduled fixtures by GW.
+func (s Set) ByEvent() map[int][]Fixture {
+	out := make(map[int][]Fixture)
+	for _, f := range s.Fixtures {
+		if f.Event == 0 {
+			continue
+		}
+		out[f.Event] = append(out[f.Event], f)
+	}
+	return out
+}
+
+// Event returns gw's fixtures, nil when bootstrap lists none (it drops a GW
+// once it has started).
+func (s Set) Event(gw int) []Fixture {
+	var out []Fixture
+	for _, f := range s.Fixtures {
+		if gw != 0 && f.Event == gw {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// Unscheduled returns the postponed fixtures awaiting a new GW.
+func (s Set) Unscheduled() []Fixture {
+	var out []Fixture
+	for _, f := range s.Fixtures {
+		if f.Event == 0 {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// MissingWarning is the tool-output warning for a bootstrap with no
+// fixtures.
+const MissingWarning = "DATA_MISSING: bootstrap-static.json lists no fixtures, so fixture-based scores are neutral rather than measured; refresh the bootstrap."
+
+// Warning is MissingWarning for an empty set and "" otherwise.
+func (s Set) Warning() string {
+	if s.Shape != ShapeEmpty {
+		return ""
+	}
+	return MissingWarning
+}
+
+// Parse reads the fixtures field of a bootstrap-static.json payload, as a
+// map keyed by GW first and a flat array after that. A fixtures field of any
+// other type is an error. A non-map shape is logged as a warning when it
+// differs from the last one seen.
+func Parse(bootstrap []byte) (Set, error) {
+	var resp struct {
+		Fixtures json.RawMessage `json:"fixtures"`
+	}
+	if err := json.Unmarshal(bootstrap, &resp); err != nil {
+		return Set{}, fmt.Errorf("parse bootstrap fixtures: %w", err)
+	}
+	set, err := parseField(resp.Fixtures)
+	if err != nil {
+		return Set{}, err
+	}
+	warnShape(set)
+	return set, nil
+}
+
+type cacheEntry struct {
+	modTime time.Time
+	size    int64
+	set     Set
+}
+
+var (
+	cacheMu sync.Mutex
+	cache   = make(map[string]cacheEntry)
+)
+
+// Load parses the bootstrap-static.json at path, reusing the last parse
+// while the file is unchanged. Callers share the cached Set and must not
+// modify it.
+func Load(path string) (Set, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return Set{}, err
+	}
+	cacheMu.Lock()
+	e, ok := cache[path]
+	cacheMu.Unlock()
+	if ok && e.modTime.Equal(info.ModTime()) && e.size == info.Size() {
+		return e.set, nil
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return Set{}, err
+	}
+	set, err := Parse(raw)
+	if err != nil {
+		return Set{}, err
+	}
+	cacheMu.Lock()
+	cache[path] = cacheEntry{modTime: info.ModTime(), size: info.Size(), set: set}
+	cacheMu.Unlock()
+	return set, nil
+}
+
+func parseField(raw json.RawMessage) (Set, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return Set{Shape: ShapeEmpty}, nil
+	}
+	var byGW map[string][]rawFixture
+	if err := json.Unmarshal(raw, &byGW); err == nil {
+		set := Set{Shape: ShapeMap}
+		for key, list := range byGW {
+			// A "null" key holds fixtures awaiting a new GW.
+			gw, err := strconv.Atoi(key)
+			if err != nil && key != "null" {
+				continue
+			}
+			for _, f := range list {
+				set.Fixtures = append(set.Fixtures, f.fixture(gw))
+			}
+		}
+		return set.sorted(), nil
+	}
+	var flat []rawFixture
+	if err := json.Unmarshal(raw, &flat); err != nil {
+		return Set{}, fmt.Errorf("parse bootstrap fixtures: neither a map keyed by GW nor an array: %w", err)
+	}
+	set := Set{Shape: ShapeArray}
+	for _, f := range flat {
+		set.Fixtures = append(set.Fixtures, f.fixture(0))
+	}
+	return set.sorted(), nil
+}
+
+// sorted orders the fixtures and marks a set with none as empty.
+func (s Set) sorted() Set {
+	if len(s.Fixtures) == 0 {
+		return Set{Shape: ShapeEmpty}
+	}
+	sort.Slice(s.Fixtures, func(i, j int) bool {
+		if s.Fixtures[i].Event != s.Fixtures[j].Event {
+			return s.Fixtures[i].Event < s.Fixtures[j].Event
+		}
+		return s.Fixtures[i].ID < s.Fixtures[j].ID
+	})
+	return s
+}
+
+var (
+	shapeMu   sync.Mutex
+	lastShape = ShapeMap
+)
+
+// warnShape logs set's shape when it isn't the one last seen, so a changed
+// bootstrap is reported once rather than on every read.
+func warnShape(set Set) {
+	shapeMu.Lock()
+	changed := set.Shape != lastShape
+	lastShape = set.Shape
+	shapeMu.Unlock()
+	if !changed {
+		return
+	}
+	switch set.Shape {
+	case ShapeEmpty:
+		slog.Warn("bootstrap fixtures missing", "code", "DATA_MISSING", "shape", string(set.Shape))
+	case ShapeArray:
+		slog.Warn("bootstrap fixtures shape changed", "shape", string(set.Shape), "fixtures", len(set.Fixtures))
+	default:
+		slog.Info("bootstrap fixtures shape changed", "shape", string(set.Shape), "fixtures", len(set.Fixtures))
+	}
+}
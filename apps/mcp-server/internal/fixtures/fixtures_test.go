@@ -0,0 +1,140 @@
+package fixtures
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func parseFile(t *testing.T, name string) Set {
+	t.Helper()
+	raw, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	set, err := Parse(raw)
+	if err != nil {
+		t.Fatalf("Parse(%s): %v", name, err)
+	}
+	return set
+}
+
+// captureLog points the default logger at a buffer for the test and resets
+// the last shape seen to the map shape.
+func captureLog(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&buf, nil)))
+	lastShape = ShapeMap
+	t.Cleanup(func() {
+		slog.SetDefault(prev)
+		lastShape = ShapeMap
+	})
+	return &buf
+}
+
+func TestParse_BothShapes(t *testing.T) {
+	logs := captureLog(t)
+	byMap := parseFile(t, "bootstrap_map.json")
+	if byMap.Shape != ShapeMap || len(byMap.Fixtures) != 3 {
+		t.Fatalf("map shape = %+v", byMap)
+	}
+	// A fixture without an event takes its GW from the key.
+	if f := byMap.Fixtures[0]; f.ID != 41 || f.Event != 5 || f.Kickoff != "" {
+		t.Errorf("first map fixture = %+v", f)
+	}
+	if logs.Len() != 0 {
+		t.Errorf("map shape logged: %s", logs)
+	}
+
+	byArray := parseFile(t, "bootstrap_array.json")
+	if byArray.Shape != ShapeArray || len(byArray.Fixtures) != 3 {
+		t.Fatalf("array shape = %+v", byArray)
+	}
+	for i := range byArray.Fixtures {
+		a, m := byArray.Fixtures[i], byMap.Fixtures[i]
+		if a.ID != m.ID || a.Event != m.Event || a.TeamH != m.TeamH || a.TeamA != m.TeamA || a.Kickoff != m.Kickoff {
+			t.Errorf("fixture %d: array %+v, map %+v", i, a, m)
+		}
+	}
+	if !byArray.Fixtures[1].Started {
+		t.Errorf("array fixture 42 lost started: %+v", byArray.Fixtures[1])
+	}
+	if got := byArray.ByEvent(); len(got[5]) != 2 || len(got[6]) != 1 {
+		t.Errorf("ByEvent = %+v", got)
+	}
+	if got := byArray.Event(6); len(got) != 1 || got[0].ID != 51 {
+		t.Errorf("Event(6) = %+v", got)
+	}
+	if byArray.Warning() != "" {
+		t.Errorf("warning with fixtures: %q", byArray.Warning())
+	}
+
+	// The switch to an array is logged once.
+	if !strings.Contains(logs.String(), "shape=array") || strings.Count(logs.String(), "shape changed") != 1 {
+		t.Errorf("logs = %s", logs)
+	}
+	parseFile(t, "bootstrap_array.json")
+	if strings.Count(logs.String(), "shape changed") != 1 {
+		t.Errorf("same shape logged again: %s", logs)
+	}
+}
+
+func TestParse_Empty(t *testing.T) {
+	logs := captureLog(t)
+	set := parseFile(t, "bootstrap_empty.json")
+	if set.Shape != ShapeEmpty || len(set.Fixtures) != 0 || set.Event(5) != nil {
+		t.Fatalf("empty = %+v", set)
+	}
+	if !strings.HasPrefix(set.Warning(), "DATA_MISSING") {
+		t.Errorf("warning = %q", set.Warning())
+	}
+	if !strings.Contains(logs.String(), "level=WARN") || !strings.Contains(logs.String(), "code=DATA_MISSING") {
+		t.Errorf("logs = %s", logs)
+	}
+
+	for _, raw := range []string{`{}`, `{"fixtures": null}`, `{"fixtures": []}`, `{"fixtures": {"x": []}}`} {
+		set, err := Parse([]byte(raw))
+		if err != nil || set.Shape != ShapeEmpty {
+			t.Errorf("Parse(%s) = %+v, %v; want empty", raw, set, err)
+		}
+	}
+	if _, err := Parse([]byte(`{"fixtures": "soon"}`)); err == nil {
+		t.Error("a string fixtures field parsed")
+	}
+}
+
+func TestParse_Postponed(t *testing.T) {
+	captureLog(t)
+	for name, raw := range map[string]string{
+		"array": `{"fixtures": [
+			{"id": 41, "event": 5, "team_h": 1, "team_a": 2},
+			{"id": 42, "event": null, "team_h": 3, "team_a": 4},
+			{"id": 51, "event": 6, "team_h": 2, "team_a": 1}]}`,
+		"map": `{"fixtures": {
+			"5": [{"id": 41, "team_h": 1, "team_a": 2}],
+			"6": [{"id": 51, "team_h": 2, "team_a": 1}],
+			"null": [{"id": 42, "team_h": 3, "team_a": 4}]}}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			set, err := Parse([]byte(raw))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := set.Unscheduled(); len(got) != 1 || got[0].ID != 42 || got[0].Event != 0 {
+				t.Errorf("Unscheduled = %+v, want fixture 42", got)
+			}
+			byEvent := set.ByEvent()
+			if _, ok := byEvent[0]; ok || len(byEvent[5]) != 1 || len(byEvent[6]) != 1 {
+				t.Errorf("ByEvent = %+v, want GW 5 and 6 only", byEvent)
+			}
+			if got := set.Event(0); got != nil {
+				t.Errorf("Event(0) = %+v, want nil", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+// Package jsonutil decodes the numbers and flags the FPL API sends in more
+// than one shape. Stats such as expected_goals and ict_index arrive as
+// strings ("0.75"), older cached files have the same fields as JSON numbers,
+// and any of them can be null; a plain int or float64 field zeroes or
+// rejects the whole document when a type flips. Decode those fields with
+// FlexInt, FlexFloat and FlexBool, or convert an already-decoded value with
+// Int, Float and Bool.
+//
+// Every form decodes the same way: a JSON number, a quoted number, a
+// json.Number, and null or "" (zero). Anything else is an error rather than
+// a silent zero.
+package jsonutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Float converts a decoded JSON value to a float64.
+func Float(v any) (float64, error) {
+	switch x := v.(type) {
+	case nil:
+		return 0, nil
+	case float64:
+		return x, nil
+	case int:
+		return float64(x), nil
+	case json.Number:
+		return parseFloat(string(x))
+	case string:
+		return parseFloat(x)
+	}
+	return 0, fmt.Errorf("jsonutil: %T is not a number", v)
+}
+
+// Int converts a decoded JSON value to an int, truncating any fraction
+// toward zero.
+func Int(v any) (int, error) {
+	switch x := v.(type) {
+	case int:
+		return x, nil
+	case json.Number:
+		if n, err := strconv.Atoi(strings.TrimSpace(string(x))); err == nil {
+			return n, nil
+		}
+	case string:
+		if n, err := strconv.Atoi(strings.TrimSpace(x)); err == nil {
+			return n, nil
+		}
+	}
+	f, err := Float(v)
+	if err != nil {
+		return 0, err
+	}
+	if f >= math.MaxInt64 || f <= math.MinInt64 {
+		return 0, fmt.Errorf("jsonutil: %v overflows an int", f)
+	}
+	return int(f), nil
+}
+
+// Bool converts a decoded JSON value to a bool. Besides true and false it
+// takes "true"/"false" and numbers, where any non-zero value is true.
+func Bool(v any) (bool, error) {
+	switch x := v.(type) {
+	case bool:
+		return x, nil
+	case string:
+		if b, err := strconv.ParseBool(strings.TrimSpace(x)); err == nil {
+			return b, nil
+		}
+	}
+	f, err := Float(v)
+	if err != nil {
+		return false, fmt.Errorf("jsonutil: %v is not a bool", v)
+	}
+	return f != 0, nil
+}
+
+func parseFloat(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("jsonutil: parse number %q: %w", s, err)
+	}
+	// ParseFloat takes "NaN" and "Inf", which no JSON number can be.
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, fmt.Errorf("jsonutil: parse number %q: not finite", s)
+	}
+	return f, nil
+}
+
+// scalar decodes one JSON scalar without a full decoder: a string as its
+// contents, a number as a json.Number and null as nil.
+func scalar(b []byte) (any, error) {
+	s := strings.TrimSpace(string(b))
+	switch {
+	case s == "null":
+		return nil, nil
+	case s == "true" || s == "false":
+		return s == "true", nil
+	case strings.HasPrefix(s, `"`):
+		var str string
+		if err := json.Unmarshal([]byte(s), &str); err != nil {
+			return nil, err
+		}
+		return str, nil
+	case strings.HasPrefix(s, "{") || strings.HasPrefix(s, "["):
+		return nil, fmt.Errorf("jsonutil: %.20s is not a scalar", s)
+	}
+	return json.Number(s), nil
+}
+
+// FlexFloat is a float64 field that decodes with Float.
+type FlexFloat float64
+
+func (f *FlexFloat) UnmarshalJSON(b []byte) error {
+	v, err := scalar(b)
+	if err != nil {
+		return err
+	}
+	n, err := Float(v)
+	if err != nil {
+		return err
+	}
+	*f = FlexFloat(n)
+	return nil
+}
+
+// FlexInt is an int field that decodes with Int.
+type FlexInt int
+
+func (i *FlexInt) UnmarshalJSON(b []byte) error {
+	v, err := scalar(b)
+	if err != nil {
+		return err
+	}
+	n, err := Int(v)
+	if err != nil {
+		return err
+	}
+	*i = FlexInt(n)
+	return nil
+}
+
+// FlexBool is a bool field that decodes with Bool.
+type FlexBool bool
+
+func (f *FlexBool) UnmarshalJSON(b []byte) error {
+	v, err := scalar(b)
+	if err != nil {
+		return err
+	}
+	x, err := Bool(v)
+	if err != nil {
+		return err
+	}
+	*f = FlexBool(x)
+	return nil
+}
@@ -0,0 +1,172 @@
+package jsonutil
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+	"testing"
+	"testing/quick"
+)
+
+func TestFloat(t *testing.T) {
+	tests := []struct {
+		in      any
+		want    float64
+		wantErr bool
+	}{
+		{nil, 0, false},
+		{0.85, 0.85, false},
+		{7, 7, false},
+		{json.Number("1.23"), 1.23, false},
+		{"0.85", 0.85, false},
+		{" 10 ", 10, false},
+		{"", 0, false},
+		{"invalid", 0, true},
+		{"abc1.5", 0, true},
+		{"NaN", 0, true},
+		{"Inf", 0, true},
+		{true, 0, true},
+		{[]any{1.0}, 0, true},
+	}
+	for _, tc := range tests {
+		got, err := Float(tc.in)
+		if (err != nil) != tc.wantErr || got != tc.want {
+			t.Errorf("Float(%#v) = %v, %v; want %v (error %v)", tc.in, got, err, tc.want, tc.wantErr)
+		}
+	}
+}
+
+func TestInt(t *testing.T) {
+	tests := []struct {
+		in      any
+		want    int
+		wantErr bool
+	}{
+		{nil, 0, false},
+		{90.0, 90, false},
+		{json.Number("90"), 90, false},
+		{json.Number("1e2"), 100, false},
+		{"-3", -3, false},
+		{"2.9", 2, false},
+		{"-2.9", -2, false},
+		{"", 0, false},
+		{"x", 0, true},
+		{1e30, 0, true},
+		{map[string]any{}, 0, true},
+	}
+	for _, tc := range tests {
+		got, err := Int(tc.in)
+		if (err != nil) != tc.wantErr || got != tc.want {
+			t.Errorf("Int(%#v) = %v, %v; want %v (error %v)", tc.in, got, err, tc.want, tc.wantErr)
+		}
+	}
+}
+
+func TestBool(t *testing.T) {
+	tests := []struct {
+		in      any
+		want    bool
+		wantErr bool
+	}{
+		{nil, false, false},
+		{true, true, false},
+		{"true", true, false},
+		{"False", false, false},
+		{"1", true, false},
+		{0.0, false, false},
+		{json.Number("2"), true, false},
+		{"", false, false},
+		{"yes", false, true},
+	}
+	for _, tc := range tests {
+		got, err := Bool(tc.in)
+		if (err != nil) != tc.wantErr || got != tc.want {
+			t.Errorf("Bool(%#v) = %v, %v; want %v (error %v)", tc.in, got, err, tc.want, tc.wantErr)
+		}
+	}
+}
+
+func TestFlexFields(t *testing.T) {
+	var v struct {
+		A FlexFloat `json:"a"`
+		B FlexFloat `json:"b"`
+		C FlexInt   `json:"c"`
+		D FlexInt   `json:"d"`
+		E FlexBool  `json:"e"`
+		F FlexBool  `json:"f"`
+		G FlexFloat `json:"g"`
+	}
+	raw := `{"a": "0.75", "b": 1.5, "c": "12", "d": null, "e": "true", "f": 1, "g": ""}`
+	if err := json.Unmarshal([]byte(raw), &v); err != nil {
+		t.Fatal(err)
+	}
+	if v.A != 0.75 || v.B != 1.5 || v.C != 12 || v.D != 0 || !bool(v.E) || !bool(v.F) || v.G != 0 {
+		t.Errorf("decoded %+v", v)
+	}
+	for _, bad := range []string{`{"a": "abc"}`, `{"a": {}}`, `{"c": [1]}`, `{"e": "maybe"}`} {
+		if err := json.Unmarshal([]byte(bad), &v); err == nil {
+			t.Errorf("%s: expected an error", bad)
+		}
+	}
+}
+
+// Every encoding of the same value decodes to the same value.
+func TestFlexFloat_EncodingsAgree(t *testing.T) {
+	check := func(f float64) bool {
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return true
+		}
+		num := strconv.FormatFloat(f, 'g', -1, 64)
+		var a, b FlexFloat
+		if err := json.Unmarshal([]byte(num), &a); err != nil {
+			return false
+		}
+		if err := json.Unmarshal([]byte(strconv.Quote(num)), &b); err != nil {
+			return false
+		}
+		c, err := Float(json.Number(num))
+		return err == nil && float64(a) == f && a == b && c == f
+	}
+	if err := quick.Check(check, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestFlexInt_EncodingsAgree(t *testing.T) {
+	check := func(n int32) bool {
+		s := strconv.Itoa(int(n))
+		var a, b, c FlexInt
+		if json.Unmarshal([]byte(s), &a) != nil || json.Unmarshal([]byte(strconv.Quote(s)), &b) != nil {
+			return false
+		}
+		// A whole number written as a decimal, as a float-typed stat would be.
+		if json.Unmarshal([]byte(strconv.Quote(s+".0")), &c) != nil {
+			return false
+		}
+		return int(a) == int(n) && a == b && b == c
+	}
+	if err := quick.Check(check, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func FuzzFlexFloat(f *testing.F) {
+	for _, seed := range []string{`"0.75"`, `1.5`, `null`, `""`, `"abc"`, `"1e400"`, `-0`, `{}`} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, raw string) {
+		var v FlexFloat
+		if err := json.Unmarshal([]byte(raw), &v); err != nil {
+			return
+		}
+		if math.IsNaN(float64(v)) || math.IsInf(float64(v), 0) {
+			t.Fatalf("%q decoded to %v", raw, v)
+		}
+		// Whatever decoded must decode the same from its quoted form.
+		var again FlexFloat
+		quoted := strconv.Quote(strconv.FormatFloat(float64(v), 'g', -1, 64))
+		if err := json.Unmarshal([]byte(quoted), &again); err != nil || again != v {
+			t.Fatalf("%q = %v but %s = %v, %v", raw, v, quoted, again, err)
+		}
+	})
+}
@@ -0,0 +1,299 @@
+// Package leagueconfig reads a draft league's settings from the league object
+// of league/{id}/details.json. Settings the payload doesn't carry, or carries
+// in a shape older than expected, fall back to the game's defaults rather
+// than failing, and are listed in Config.Defaulted.
+package leagueconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+)
+
+// Scoring types, decoded from the league's "scoring" code ("h" or "c").
+const (
+	ScoringH2H     = "h2h"
+	ScoringClassic = "classic"
+)
+
+// Waiver modes, decoded from "transaction_mode": "w" is priority waivers,
+// "b" (or "faab") blind bidding from a budget.
+const (
+	ModeWaivers = "waivers"
+	ModeFAAB    = "faab"
+)
+
+// DefaultFAABBudget is the season budget of a FAAB league whose settings
+// don't give one.
+const DefaultFAABBudget = 100
+
+// SeasonGWs is the last GW of a season whose league settings don't give a
+// stop_event.
+const SeasonGWs = 38
+
+// PositionLimits is a squad count per position type (1=GK, 2=DEF, 3=MID,
+// 4=FWD).
+type PositionLimits struct {
+	GK  int `json:"gk"`
+	DEF int `json:"def"`
+	MID int `json:"mid"`
+	FWD int `json:"fwd"`
+}
+
+// At returns the limit for position type pos, or 0 for an unknown one.
+func (p PositionLimits) At(pos int) int {
+	switch pos {
+	case 1:
+		return p.GK
+	case 2:
+		return p.DEF
+	case 3:
+		return p.MID
+	case 4:
+		return p.FWD
+	}
+	return 0
+}
+
+// Total is the squad size the limits add up to.
+func (p PositionLimits) Total() int { return p.GK + p.DEF + p.MID + p.FWD }
+
+// DefaultSquadLimits is the FPL draft squad: 2 GK / 5 DEF / 5 MID / 3 FWD.
+func DefaultSquadLimits() PositionLimits { return PositionLimits{GK: 2, DEF: 5, MID: 5, FWD: 3} }
+
+// Squad is the roster shape.
+type Squad struct {
+	Size           int            `json:"size"`
+	PositionLimits PositionLimits `json:"position_limits"`
+}
+
+// Waivers is how players are acquired. Mode is the decoded
+// transaction_mode, or the raw code when it isn't one this package knows.
+// Budget is each entry's season FAAB budget, set only in FAAB mode.
+// SeasonLimit and GWLimit cap each entry's approved waiver and free-agent
+// moves over the season and within one GW; 0 is no cap.
+type Waivers struct {
+	Mode        string `json:"mode"`
+	ModeCode    string `json:"mode_code,omitempty"`
+	Day         string `json:"day,omitempty"`
+	Budget      int    `json:"budget,omitempty"`
+	SeasonLimit int    `json:"season_limit,omitempty"`
+	GWLimit     int    `json:"gw_limit,omitempty"`
+}
+
+// FAAB reports whether the league bids for waivers from a budget.
+func (w Waivers) FAAB() bool { return w.Mode == ModeFAAB }
+
+// Capped reports whether the league limits the number of moves at all.
+func (w Waivers) Capped() bool { return w.SeasonLimit > 0 || w.GWLimit > 0 }
+
+// Trades is whether managers can trade with each other.
+type Trades struct {
+	Enabled bool   `json:"enabled"`
+	Code    string `json:"code,omitempty"`
+}
+
+// Draft is when and how the league drafted.
+type Draft struct {
+	Date                 string `json:"date,omitempty"`
+	Status               string `json:"status,omitempty"`
+	Type                 string `json:"type,omitempty"`
+	PickTimeLimitSeconds int    `json:"pick_time_limit_seconds,omitempty"`
+	TZ                   string `json:"tz,omitempty"`
+}
+
+// Admin is the league's administrative info.
+type Admin struct {
+	AdminEntry     int  `json:"admin_entry,omitempty"`
+	MinEntries     int  `json:"min_entries,omitempty"`
+	MaxEntries     int  `json:"max_entries,omitempty"`
+	Closed         bool `json:"closed"`
+	MakeCodePublic bool `json:"make_code_public"`
+}
+
+// Config is a league's settings. StopEvent is 0 when the payload doesn't say
+// (the season's last GW); KORounds is the number of playoff GWs at its end.
+type Config struct {
+	LeagueID   int     `json:"league_id"`
+	Name       string  `json:"name"`
+	Scoring    string  `json:"scoring"`
+	StartEvent int     `json:"start_event"`
+	StopEvent  int     `json:"stop_event,omitempty"`
+	KORounds   int     `json:"ko_rounds"`
+	Squad      Squad   `json:"squad"`
+	Waivers    Waivers `json:"waivers"`
+	Trades     Trades  `json:"trades"`
+	Draft      Draft   `json:"draft"`
+	Admin      Admin   `json:"admin"`
+	// Defaulted names the settings that were absent or unreadable and so
+	// hold the default.
+	Defaulted []string `json:"defaulted,omitempty"`
+	// ScoringRules is the league's scoring_rules object, if any, for
+	// scoring.Apply.
+	ScoringRules json.RawMessage `json:"-"`
+}
+
+// LastGW is the league's final GW: stop_event, or SeasonGWs without one.
+func (c Config) LastGW() int {
+	if c.StopEvent > 0 {
+		return c.StopEvent
+	}
+	return SeasonGWs
+}
+
+// Default is the settings of a league whose details carry none: head to
+// head from GW1, the standard squad, waivers and trades on.
+func Default() Config {
+	return Config{
+		Scoring:    ScoringH2H,
+		StartEvent: 1,
+		Squad:      Squad{Size: DefaultSquadLimits().Total(), PositionLimits: DefaultSquadLimits()},
+		Waivers:    Waivers{Mode: ModeWaivers},
+		Trades:     Trades{Enabled: true},
+	}
+}
+
+// Load reads leagueID's settings from league/{id}/details.json in st.
+func Load(st *store.JSONStore, leagueID int) (Config, error) {
+	path := fmt.Sprintf("league/%d/details.json", leagueID)
+	raw, err := st.ReadRaw(path)
+	if err != nil {
+		return Config{}, err
+	}
+	cfg, err := Parse(raw)
+	if err != nil {
+		return Config{}, fmt.Errorf("%s: %w", path, err)
+	}
+	if cfg.LeagueID == 0 {
+		cfg.LeagueID = leagueID
+	}
+	return cfg, nil
+}
+
+// Parse reads the settings from a details.json payload. Only a payload that
+// isn't a JSON object, or whose league isn't one, is an error; each setting
+// is read on its own so one of an unexpected type just keeps its default.
+func Parse(raw []byte) (Config, error) {
+	var details struct {
+		League json.RawMessage `json:"league"`
+	}
+	if err := json.Unmarshal(raw, &details); err != nil {
+		return Config{}, err
+	}
+	obj := map[string]json.RawMessage{}
+	if len(details.League) > 0 && string(details.League) != "null" {
+		if err := json.Unmarshal(details.League, &obj); err != nil {
+			return Config{}, fmt.Errorf("league settings: %w", err)
+		}
+	}
+
+	cfg := Default()
+	defaulted := map[string]bool{}
+	// read decodes obj[key] into dst, reporting whether it was there and
+	// readable. dst is left as it was otherwise.
+	read := func(key string, dst any) bool {
+		v, ok := obj[key]
+		if !ok || string(v) == "null" {
+			return false
+		}
+		if err := json.Unmarshal(v, dst); err != nil {
+			return false
+		}
+		return true
+	}
+	// setting is read for a setting with a default worth reporting.
+	setting := func(key string, dst any) bool {
+		if read(key, dst) {
+			return true
+		}
+		defaulted[key] = true
+		return false
+	}
+
+	read("id", &cfg.LeagueID)
+	read("name", &cfg.Name)
+
+	var code string
+	if setting("scoring", &code) {
+		switch strings.ToLower(code) {
+		case "h":
+			cfg.Scoring = ScoringH2H
+		case "c":
+			cfg.Scoring = ScoringClassic
+		default:
+			cfg.Scoring = code
+		}
+	}
+	if setting("start_event", &cfg.StartEvent) && cfg.StartEvent < 1 {
+		cfg.StartEvent = 1
+	}
+	read("stop_event", &cfg.StopEvent)
+	read("ko_rounds", &cfg.KORounds)
+
+	var limits PositionLimits
+	if setting("squad_limits", &limits) && limits.Total() > 0 {
+		cfg.Squad.PositionLimits = limits
+		cfg.Squad.Size = limits.Total()
+	} else {
+		defaulted["squad_limits"] = true
+	}
+	if !setting("squad_size", &cfg.Squad.Size) || cfg.Squad.Size <= 0 {
+		cfg.Squad.Size = cfg.Squad.PositionLimits.Total()
+	}
+
+	code = ""
+	if setting("transaction_mode", &code) {
+		cfg.Waivers.ModeCode = code
+		switch strings.ToLower(code) {
+		case "w":
+			cfg.Waivers.Mode = ModeWaivers
+		case "b", "faab":
+			cfg.Waivers.Mode = ModeFAAB
+		default:
+			cfg.Waivers.Mode = code
+		}
+	}
+	read("waiver_day", &cfg.Waivers.Day)
+	if cfg.Waivers.FAAB() && (!setting("faab_budget", &cfg.Waivers.Budget) || cfg.Waivers.Budget <= 0) {
+		cfg.Waivers.Budget = DefaultFAABBudget
+	}
+	// Transaction caps are optional: most leagues have none, so a missing
+	// one isn't reported as defaulted.
+	if read("max_transactions", &cfg.Waivers.SeasonLimit) && cfg.Waivers.SeasonLimit < 0 {
+		cfg.Waivers.SeasonLimit = 0
+	}
+	if read("max_transactions_per_event", &cfg.Waivers.GWLimit) && cfg.Waivers.GWLimit < 0 {
+		cfg.Waivers.GWLimit = 0
+	}
+
+	code = ""
+	if setting("trades", &code) {
+		cfg.Trades.Code = code
+		cfg.Trades.Enabled = !strings.EqualFold(code, "n")
+	}
+
+	read("draft_dt", &cfg.Draft.Date)
+	read("draft_status", &cfg.Draft.Status)
+	read("draft_type", &cfg.Draft.Type)
+	read("draft_pick_time_limit", &cfg.Draft.PickTimeLimitSeconds)
+	read("draft_tz_show", &cfg.Draft.TZ)
+
+	read("admin_entry", &cfg.Admin.AdminEntry)
+	read("min_entries", &cfg.Admin.MinEntries)
+	read("max_entries", &cfg.Admin.MaxEntries)
+	read("closed", &cfg.Admin.Closed)
+	read("make_code_public", &cfg.Admin.MakeCodePublic)
+
+	if v, ok := obj["scoring_rules"]; ok && string(v) != "null" {
+		cfg.ScoringRules = v
+	}
+
+	for k := range defaulted {
+		cfg.Defaulted = append(cfg.Defaulted, k)
+	}
+	sort.Strings(cfg.Defaulted)
+	return cfg, nil
+}
@@ -0,0 +1,121 @@
+package leagueconfig
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse_FullSettings(t *testing.T) {
+	raw := []byte(`{"league": {
+		"id": 14204, "name": "Sunday League", "admin_entry": 200, "closed": true,
+		"draft_dt": "2025-08-10T18:00:00Z", "draft_status": "post", "draft_pick_time_limit": 60, "draft_tz_show": "Europe/London",
+		"ko_rounds": 2, "make_code_public": false, "max_entries": 16, "min_entries": 2,
+		"scoring": "c", "start_event": 3, "stop_event": 38, "trades": "n", "transaction_mode": "W",
+		"squad_limits": {"gk": 2, "def": 6, "mid": 5, "fwd": 3}
+	}, "league_entries": []}`)
+	c, err := Parse(raw)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if c.LeagueID != 14204 || c.Name != "Sunday League" || c.Scoring != ScoringClassic || c.StartEvent != 3 || c.StopEvent != 38 || c.KORounds != 2 {
+		t.Errorf("league = %+v", c)
+	}
+	if c.Trades.Enabled || c.Trades.Code != "n" || c.Waivers.Mode != ModeWaivers || c.Waivers.ModeCode != "W" {
+		t.Errorf("trades %+v, waivers %+v", c.Trades, c.Waivers)
+	}
+	if c.Squad.Size != 16 || c.Squad.PositionLimits.At(2) != 6 {
+		t.Errorf("squad = %+v, want 16 with 6 DEF", c.Squad)
+	}
+	wantDraft := Draft{Date: "2025-08-10T18:00:00Z", Status: "post", PickTimeLimitSeconds: 60, TZ: "Europe/London"}
+	if c.Draft != wantDraft || c.Admin != (Admin{AdminEntry: 200, MinEntries: 2, MaxEntries: 16, Closed: true}) {
+		t.Errorf("draft %+v, admin %+v", c.Draft, c.Admin)
+	}
+	if !reflect.DeepEqual(c.Defaulted, []string{"squad_size"}) {
+		t.Errorf("defaulted = %v, want [squad_size]", c.Defaulted)
+	}
+}
+
+func TestParse_OldOrMissingSettings(t *testing.T) {
+	// No league object at all: every default.
+	c, err := Parse([]byte(`{"league_entries": [], "matches": []}`))
+	if err != nil {
+		t.Fatalf("Parse without league: %v", err)
+	}
+	want := Default()
+	if c.Scoring != want.Scoring || c.StartEvent != 1 || c.Squad != want.Squad || c.Waivers != want.Waivers || c.Trades != want.Trades {
+		t.Errorf("defaults = %+v, want %+v", c, want)
+	}
+	if len(c.Defaulted) != 6 {
+		t.Errorf("defaulted = %v, want all six settings", c.Defaulted)
+	}
+	if c.Squad.PositionLimits != DefaultSquadLimits() || c.Squad.Size != 15 {
+		t.Errorf("squad = %+v, want 2/5/5/3", c.Squad)
+	}
+
+	// Settings of an unexpected type keep their default; the rest are read,
+	// and unknown fields are ignored.
+	c, err = Parse([]byte(`{"league": {"scoring": "h", "trades": true, "start_event": "5", "draft_pick_time_limit": "60s", "ko_rounds": 1, "variety": "x", "scoring_rules": {"goal": {"mid": 6}}}}`))
+	if err != nil {
+		t.Fatalf("Parse with odd types: %v", err)
+	}
+	if c.Scoring != ScoringH2H || !c.Trades.Enabled || c.StartEvent != 1 || c.Draft.PickTimeLimitSeconds != 0 || c.KORounds != 1 {
+		t.Errorf("odd types = %+v", c)
+	}
+	if string(c.ScoringRules) != `{"goal": {"mid": 6}}` {
+		t.Errorf("scoring_rules = %s", c.ScoringRules)
+	}
+
+	if _, err := Parse([]byte(`{"league": [1, 2]}`)); err == nil {
+		t.Error("Parse accepted a league that isn't an object")
+	}
+}
+
+func TestParse_FAAB(t *testing.T) {
+	c, err := Parse([]byte(`{"league": {"transaction_mode": "b", "faab_budget": 200}}`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if !c.Waivers.FAAB() || c.Waivers.Budget != 200 || c.Waivers.ModeCode != "b" {
+		t.Errorf("waivers = %+v, want FAAB with 200", c.Waivers)
+	}
+
+	// A FAAB league without a budget gets the default one.
+	c, _ = Parse([]byte(`{"league": {"transaction_mode": "FAAB"}}`))
+	if !c.Waivers.FAAB() || c.Waivers.Budget != DefaultFAABBudget {
+		t.Errorf("waivers = %+v, want the default budget", c.Waivers)
+	}
+	found := false
+	for _, k := range c.Defaulted {
+		found = found || k == "faab_budget"
+	}
+	if !found {
+		t.Errorf("defaulted = %v, want faab_budget", c.Defaulted)
+	}
+
+	// Priority waivers carry no budget.
+	c, _ = Parse([]byte(`{"league": {"transaction_mode": "w", "faab_budget": 100}}`))
+	if c.Waivers.FAAB() || c.Waivers.Budget != 0 {
+		t.Errorf("waivers = %+v, want priority waivers", c.Waivers)
+	}
+}
+
+func TestParse_TransactionLimits(t *testing.T) {
+	c, err := Parse([]byte(`{"league": {"transaction_mode": "w", "max_transactions": 30, "max_transactions_per_event": 2, "stop_event": 34}}`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if !c.Waivers.Capped() || c.Waivers.SeasonLimit != 30 || c.Waivers.GWLimit != 2 || c.LastGW() != 34 {
+		t.Errorf("waivers = %+v last GW %d, want 30 a season, 2 a GW to GW34", c.Waivers, c.LastGW())
+	}
+
+	// No cap, or a nonsense one, is uncapped and not a defaulted setting.
+	c, _ = Parse([]byte(`{"league": {"transaction_mode": "w", "max_transactions": -1, "max_transactions_per_event": "2"}}`))
+	if c.Waivers.Capped() || c.LastGW() != SeasonGWs {
+		t.Errorf("waivers = %+v last GW %d, want uncapped to GW%d", c.Waivers, c.LastGW(), SeasonGWs)
+	}
+	for _, k := range c.Defaulted {
+		if k == "max_transactions" || k == "max_transactions_per_event" {
+			t.Errorf("defaulted = %v, want the caps left out", c.Defaulted)
+		}
+	}
+}
@@ -1,13 +1,11 @@
 package ledger
 
 import (
-	"encoding/json"
-	"os"
-	"path/filepath"
 	"sort"
-	"time"
 
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/clock"
 	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/model"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
 )
 
 type DraftChoicesResponse struct {
@@ -26,7 +24,7 @@ type DraftChoice struct {
 	League     int    `json:"league"`
 }
 
-func BuildDraftLedger(leagueID int, choices []DraftChoice) *model.DraftLedger {
+func BuildDraftLedger(leagueID int, choices []DraftChoice, clk clock.Clock) *model.DraftLedger {
 	sort.Slice(choices, func(i, j int) bool {
 		return choices[i].Index < choices[j].Index
 	})
@@ -76,7 +74,7 @@ func BuildDraftLedger(leagueID int, choices []DraftChoice) *model.DraftLedger {
 	return &model.DraftLedger{
 		LeagueID:       leagueID,
 		Event:          0,
-		GeneratedAtUTC: time.Now().UTC().Format(time.RFC3339),
+		GeneratedAtUTC: clock.UTC(clock.Or(clk).Now()),
 		Managers:       managers,
 		Squads:         squads,
 		Picks:          picks,
@@ -84,15 +82,5 @@ func BuildDraftLedger(leagueID int, choices []DraftChoice) *model.DraftLedger {
 }
 
 func WriteDraftLedger(path string, ledger *model.DraftLedger) error {
-	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
-		return err
-	}
-
-	b, err := json.MarshalIndent(ledger, "", "  ")
-	if err != nil {
-		return err
-	}
-
-	b = append(b, '\n')
-	return os.WriteFile(path, b, 0o644)
+	return store.WriteLedgerJSON(path, ledger)
 }
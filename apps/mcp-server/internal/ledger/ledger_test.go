@@ -7,6 +7,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/clock"
 )
 
 // ---------------------------------------------------------------------------
@@ -14,7 +16,7 @@ import (
 // ---------------------------------------------------------------------------
 
 func TestBuildDraftLedger_Empty(t *testing.T) {
-	ledger := BuildDraftLedger(1, nil)
+	ledger := BuildDraftLedger(1, nil, nil)
 
 	if ledger.LeagueID != 1 {
 		t.Errorf("LeagueID = %d, want 1", ledger.LeagueID)
@@ -38,7 +40,7 @@ func TestBuildDraftLedger_SortsByIndex(t *testing.T) {
 		{Entry: 2, EntryName: "Beta", Element: 40, Round: 2, Pick: 4, Index: 2},
 	}
 
-	l := BuildDraftLedger(10, choices)
+	l := BuildDraftLedger(10, choices, nil)
 
 	// Picks must be in ascending Index order.
 	for i := 1; i < len(l.Picks); i++ {
@@ -56,7 +58,7 @@ func TestBuildDraftLedger_ManagersDeduplicatedAndSorted(t *testing.T) {
 		{Entry: 5, EntryName: "Five", Element: 55, Index: 3}, // duplicate entry
 	}
 
-	l := BuildDraftLedger(1, choices)
+	l := BuildDraftLedger(1, choices, nil)
 
 	if len(l.Managers) != 2 {
 		t.Errorf("Managers len = %d, want 2 (duplicate entry deduplicated)", len(l.Managers))
@@ -74,7 +76,7 @@ func TestBuildDraftLedger_SquadsAggregatePerEntry(t *testing.T) {
 		{Entry: 1, EntryName: "A", Element: 30, Index: 3},
 	}
 
-	l := BuildDraftLedger(1, choices)
+	l := BuildDraftLedger(1, choices, nil)
 
 	squadByEntry := make(map[int][]int)
 	for _, s := range l.Squads {
@@ -95,7 +97,7 @@ func TestBuildDraftLedger_SquadsSortedByEntryID(t *testing.T) {
 		{Entry: 2, EntryName: "Two", Element: 20, Index: 2},
 	}
 
-	l := BuildDraftLedger(1, choices)
+	l := BuildDraftLedger(1, choices, nil)
 
 	if l.Squads[0].EntryID != 2 || l.Squads[1].EntryID != 9 {
 		t.Errorf("Squads not sorted by EntryID: %v", l.Squads)
@@ -108,7 +110,7 @@ func TestBuildDraftLedger_PicksPreserveFields(t *testing.T) {
 			ChoiceTime: "2024-08-01T10:00:00Z", WasAuto: true, League: 99},
 	}
 
-	l := BuildDraftLedger(99, choices)
+	l := BuildDraftLedger(99, choices, nil)
 
 	if len(l.Picks) != 1 {
 		t.Fatalf("Picks len = %d, want 1", len(l.Picks))
@@ -120,10 +122,10 @@ func TestBuildDraftLedger_PicksPreserveFields(t *testing.T) {
 	}
 }
 
-func TestBuildDraftLedger_GeneratedAtUTCIsRFC3339(t *testing.T) {
-	l := BuildDraftLedger(1, nil)
-	if _, err := time.Parse(time.RFC3339, l.GeneratedAtUTC); err != nil {
-		t.Errorf("GeneratedAtUTC %q is not RFC3339: %v", l.GeneratedAtUTC, err)
+func TestBuildDraftLedger_GeneratedAtUTCFromClock(t *testing.T) {
+	l := BuildDraftLedger(1, nil, clock.Fixed(time.Date(2025, 8, 10, 18, 30, 0, 0, time.UTC)))
+	if l.GeneratedAtUTC != "2025-08-10T18:30:00Z" {
+		t.Errorf("GeneratedAtUTC = %q, want the clock's time", l.GeneratedAtUTC)
 	}
 }
 
@@ -134,7 +136,7 @@ func TestWriteDraftLedger(t *testing.T) {
 	choices := []DraftChoice{
 		{Entry: 1, EntryName: "A", Element: 10, Index: 1},
 	}
-	l := BuildDraftLedger(1, choices)
+	l := BuildDraftLedger(1, choices, nil)
 
 	if err := WriteDraftLedger(path, l); err != nil {
 		t.Fatalf("WriteDraftLedger error: %v", err)
@@ -153,7 +155,6 @@ func TestWriteDraftLedger(t *testing.T) {
 // ---------------------------------------------------------------------------
 
 func TestBuildEntrySnapshot_FieldsPreserved(t *testing.T) {
-	// FPL Draft has no captain mechanic — EntryPick only carries Element and Position.
 	raw := EntryEventRaw{
 		EntryHistory: json.RawMessage(`{"total_points":120}`),
 		Picks: []EntryPick{
@@ -165,7 +166,7 @@ func TestBuildEntrySnapshot_FieldsPreserved(t *testing.T) {
 		},
 	}
 
-	snap := BuildEntrySnapshot(10, 20, 3, raw)
+	snap := BuildEntrySnapshot(10, 20, 3, raw, nil)
 
 	if snap.LeagueID != 10 || snap.EntryID != 20 || snap.Gameweek != 3 {
 		t.Errorf("IDs not propagated: league=%d entry=%d gw=%d", snap.LeagueID, snap.EntryID, snap.Gameweek)
@@ -181,15 +182,56 @@ func TestBuildEntrySnapshot_FieldsPreserved(t *testing.T) {
 	}
 }
 
-func TestBuildEntrySnapshot_GeneratedAtUTCIsRFC3339(t *testing.T) {
-	snap := BuildEntrySnapshot(1, 1, 1, EntryEventRaw{})
-	if _, err := time.Parse(time.RFC3339, snap.GeneratedAtUTC); err != nil {
-		t.Errorf("GeneratedAtUTC %q is not RFC3339: %v", snap.GeneratedAtUTC, err)
+func TestEntryEventRaw_PickFlagsAndAutomaticSubs(t *testing.T) {
+	body := `{
+		"entry_history": {},
+		"picks": [
+			{"element": 5, "position": 1, "multiplier": 1, "is_captain": true, "is_vice_captain": false},
+			{"element": 9, "position": 12, "multiplier": 0, "is_captain": false, "is_vice_captain": true}
+		],
+		"automatic_subs": [{"element_in": 9, "element_out": 5, "event": 3}]
+	}`
+	var raw EntryEventRaw
+	if err := json.Unmarshal([]byte(body), &raw); err != nil {
+		t.Fatal(err)
+	}
+	snap := BuildEntrySnapshot(1, 2, 3, raw, nil)
+	if p := snap.Picks[0]; !p.IsCaptain || p.IsViceCaptain || p.Multiplier != 1 {
+		t.Errorf("starter pick = %+v", p)
+	}
+	if p := snap.Picks[1]; p.IsCaptain || !p.IsViceCaptain || p.Multiplier != 0 {
+		t.Errorf("bench pick = %+v", p)
+	}
+	if len(snap.Subs) != 1 || snap.Subs[0].ElementIn != 9 {
+		t.Errorf("Subs = %+v, want the automatic_subs array", snap.Subs)
+	}
+}
+
+func TestEntrySnapshot_OldFileDefaults(t *testing.T) {
+	// Snapshots written before multiplier was recorded only carry element
+	// and position.
+	body := `{"entry_id": 2, "gameweek": 3, "picks": [{"element": 5, "position": 11}, {"element": 9, "position": 12}], "subs": []}`
+	var snap EntrySnapshot
+	if err := json.Unmarshal([]byte(body), &snap); err != nil {
+		t.Fatal(err)
+	}
+	if snap.Picks[0].Multiplier != 1 || snap.Picks[1].Multiplier != 0 || snap.Picks[0].IsCaptain {
+		t.Errorf("picks = %+v, want multiplier 1 for the XI and 0 for the bench", snap.Picks)
+	}
+	if snap.Picks[0].Element != 5 || snap.Picks[1].Position != 12 {
+		t.Errorf("element/position lost: %+v", snap.Picks)
+	}
+}
+
+func TestBuildEntrySnapshot_GeneratedAtUTCFromClock(t *testing.T) {
+	snap := BuildEntrySnapshot(1, 1, 1, EntryEventRaw{}, clock.Fixed(time.Date(2025, 8, 16, 14, 0, 0, 0, time.FixedZone("BST", 3600))))
+	if snap.GeneratedAtUTC != "2025-08-16T13:00:00Z" {
+		t.Errorf("GeneratedAtUTC = %q, want the clock's time in UTC", snap.GeneratedAtUTC)
 	}
 }
 
 func TestBuildEntrySnapshot_EmptyPicksAndSubs(t *testing.T) {
-	snap := BuildEntrySnapshot(1, 1, 1, EntryEventRaw{})
+	snap := BuildEntrySnapshot(1, 1, 1, EntryEventRaw{}, nil)
 	if snap.Picks != nil {
 		t.Errorf("Picks should be nil for empty raw, got %v", snap.Picks)
 	}
@@ -205,7 +247,7 @@ func TestWriteEntrySnapshot(t *testing.T) {
 	raw := EntryEventRaw{
 		Picks: []EntryPick{{Element: 1, Position: 1}},
 	}
-	snap := BuildEntrySnapshot(1, 2, 3, raw)
+	snap := BuildEntrySnapshot(1, 2, 3, raw, nil)
 
 	if err := WriteEntrySnapshot(path, snap); err != nil {
 		t.Fatalf("WriteEntrySnapshot error: %v", err)
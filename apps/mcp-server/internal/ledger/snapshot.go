@@ -2,63 +2,155 @@ package ledger
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
 	"os"
-	"path/filepath"
-	"time"
+	"strconv"
+	"strings"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/clock"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
 )
 
+// EntryEventRaw is the entry/{id}/event/{gw} response. The draft API lists
+// the GW's automatic substitutions under "subs"; AutomaticSubs picks up the
+// same array when it is sent under the classic game's "automatic_subs" key.
 type EntryEventRaw struct {
-	EntryHistory json.RawMessage `json:"entry_history"`
-	Picks        []EntryPick     `json:"picks"`
-	Subs         []EntrySub      `json:"subs"`
+	EntryHistory  json.RawMessage `json:"entry_history"`
+	Picks         []EntryPick     `json:"picks"`
+	Subs          []EntrySub      `json:"subs"`
+	AutomaticSubs []EntrySub      `json:"automatic_subs"`
 }
 
 // EntryPick is one player in a draft lineup. FPL Draft has no captain
-// mechanic — every player scores their raw points. The is_captain,
-// is_vice_captain, and multiplier fields sent by the FPL API are ignored.
+// mechanic — every player scores their raw points — but is_captain,
+// is_vice_captain and multiplier are recorded as the API sends them so a
+// snapshot shows the lineup exactly as submitted.
 type EntryPick struct {
-	Element  int `json:"element"`
-	Position int `json:"position"`
+	Element       int  `json:"element"`
+	Position      int  `json:"position"`
+	Multiplier    int  `json:"multiplier"`
+	IsCaptain     bool `json:"is_captain"`
+	IsViceCaptain bool `json:"is_vice_captain"`
+}
+
+// UnmarshalJSON defaults Multiplier for picks written before it was
+// recorded: 1 for the starting XI and 0 for the bench, as the API sends it.
+func (p *EntryPick) UnmarshalJSON(b []byte) error {
+	type plain EntryPick
+	aux := struct {
+		*plain
+		Multiplier *int `json:"multiplier"`
+	}{plain: (*plain)(p)}
+	if err := json.Unmarshal(b, &aux); err != nil {
+		return err
+	}
+	switch {
+	case aux.Multiplier != nil:
+		p.Multiplier = *aux.Multiplier
+	case p.Position <= 11:
+		p.Multiplier = 1
+	default:
+		p.Multiplier = 0
+	}
+	return nil
 }
 
+// EntrySub is one automatic substitution: ElementOut, a starter who didn't
+// play, replaced by ElementIn from the bench.
 type EntrySub struct {
 	ElementIn  int `json:"element_in"`
 	ElementOut int `json:"element_out"`
 	Event      int `json:"event"`
 }
 
+// EntrySnapshot is an entry's lineup for one GW. Missing marks a stub for
+// a GW before the entry joined the league (a mid-season replacement has no
+// picks before then); it has no picks and FirstAvailableGW is the entry's
+// first GW with an event file.
 type EntrySnapshot struct {
-	LeagueID       int             `json:"league_id"`
-	EntryID        int             `json:"entry_id"`
-	Gameweek       int             `json:"gameweek"`
-	GeneratedAtUTC string          `json:"generated_at_utc"`
-	EntryHistory   json.RawMessage `json:"entry_history"`
-	Picks          []EntryPick     `json:"picks"`
-	Subs           []EntrySub      `json:"subs"`
+	LeagueID         int             `json:"league_id"`
+	EntryID          int             `json:"entry_id"`
+	Gameweek         int             `json:"gameweek"`
+	GeneratedAtUTC   string          `json:"generated_at_utc"`
+	EntryHistory     json.RawMessage `json:"entry_history"`
+	Picks            []EntryPick     `json:"picks"`
+	Subs             []EntrySub      `json:"subs"`
+	Missing          bool            `json:"missing,omitempty"`
+	FirstAvailableGW int             `json:"first_available_gw,omitempty"`
 }
 
-func BuildEntrySnapshot(leagueID int, entryID int, gw int, raw EntryEventRaw) *EntrySnapshot {
+func BuildEntrySnapshot(leagueID int, entryID int, gw int, raw EntryEventRaw, clk clock.Clock) *EntrySnapshot {
 	return &EntrySnapshot{
 		LeagueID:       leagueID,
 		EntryID:        entryID,
 		Gameweek:       gw,
-		GeneratedAtUTC: time.Now().UTC().Format(time.RFC3339),
+		GeneratedAtUTC: clock.UTC(clock.Or(clk).Now()),
 		EntryHistory:   raw.EntryHistory,
 		Picks:          raw.Picks,
-		Subs:           raw.Subs,
+		Subs:           raw.automaticSubs(),
 	}
 }
 
-func WriteEntrySnapshot(path string, snapshot *EntrySnapshot) error {
-	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
-		return err
+// ReadEntrySnapshot builds entryID's GW snapshot from the raw
+// entry/{id}/gw/{gw}.json. When that file is missing but the entry has an
+// event file for a later GW, the entry joined after gw: a Missing stub is
+// returned instead of the read error. A file missing for any other reason is
+// still an error.
+func ReadEntrySnapshot(st *store.JSONStore, leagueID int, entryID int, gw int, clk clock.Clock) (*EntrySnapshot, error) {
+	raw, err := st.ReadRaw(fmt.Sprintf("entry/%d/gw/%d.json", entryID, gw))
+	if errors.Is(err, fs.ErrNotExist) {
+		if first := FirstEntryEventGW(st, entryID); first > gw {
+			return &EntrySnapshot{
+				LeagueID:         leagueID,
+				EntryID:          entryID,
+				Gameweek:         gw,
+				GeneratedAtUTC:   clock.UTC(clock.Or(clk).Now()),
+				Picks:            []EntryPick{},
+				Subs:             []EntrySub{},
+				Missing:          true,
+				FirstAvailableGW: first,
+			}, nil
+		}
+	}
+	if err != nil {
+		return nil, err
 	}
+	var resp EntryEventRaw
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return nil, err
+	}
+	return BuildEntrySnapshot(leagueID, entryID, gw, resp, clk), nil
+}
 
-	b, err := json.MarshalIndent(snapshot, "", "  ")
+// FirstEntryEventGW is the earliest GW with a raw entry/{id}/gw/{n}.json, or
+// 0 when the entry has none.
+func FirstEntryEventGW(st *store.JSONStore, entryID int) int {
+	files, err := os.ReadDir(st.Path(fmt.Sprintf("entry/%d/gw", entryID)))
 	if err != nil {
-		return err
+		return 0
 	}
+	first := 0
+	for _, f := range files {
+		gw, err := strconv.Atoi(strings.TrimSuffix(f.Name(), ".json"))
+		if err != nil || gw <= 0 || f.IsDir() {
+			continue
+		}
+		if first == 0 || gw < first {
+			first = gw
+		}
+	}
+	return first
+}
 
-	b = append(b, '\n')
-	return os.WriteFile(path, b, 0o644)
+func (raw EntryEventRaw) automaticSubs() []EntrySub {
+	if len(raw.Subs) > 0 {
+		return raw.Subs
+	}
+	return raw.AutomaticSubs
+}
+
+func WriteEntrySnapshot(path string, snapshot *EntrySnapshot) error {
+	return store.WriteLedgerJSON(path, snapshot)
 }
@@ -0,0 +1,131 @@
+package livestats
+
+import "sort"
+
+// Bonus statuses reported in FixtureBonus.Status.
+const (
+	BonusConfirmed   = "confirmed"
+	BonusProvisional = "provisional"
+)
+
+// FixtureBonus is the bonus picture for one started fixture. Bonus holds the
+// awarded bonus once FPL has populated it (Status confirmed, and already in
+// total_points) and otherwise the 3/2/1 the current BPS would earn.
+type FixtureBonus struct {
+	Fixture  int
+	TeamH    int
+	TeamA    int
+	Finished bool
+	Status   string
+	// BPS is every player who has appeared in the fixture.
+	BPS   map[int]int
+	Bonus map[int]int
+}
+
+// BonusForBPS awards 3, 2 and 1 bonus by BPS. A player's place is one more
+// than the number of players with strictly higher BPS, and places 1-3 earn
+// 3-1 points, which gives FPL's tie rules: players tied on BPS share the
+// higher award and the places they fill are skipped. Two tied for first get 3
+// each and the next player 1; three tied for first get 3 each and nobody else
+// scores; a tie for second gives 3, 2, 2; a tie for third gives 1 to each.
+func BonusForBPS(bps map[int]int) map[int]int {
+	values := make([]int, 0, len(bps))
+	for _, v := range bps {
+		values = append(values, v)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(values)))
+	out := make(map[int]int)
+	for id, v := range bps {
+		place := 1 + sort.Search(len(values), func(i int) bool { return values[i] <= v })
+		if place <= 3 {
+			out[id] = 4 - place
+		}
+	}
+	return out
+}
+
+// ProvisionalBonus returns the bonus for each started fixture in gw, in
+// fixture id order. Players in a fixture come from the fixture's bps table
+// when live.json has one, then from each player's explain data, and for
+// players with neither from teamOf (element to team id) when their team plays
+// once in the GW. Only players with minutes count, so an unused substitute
+// can't take a bonus place.
+func ProvisionalBonus(gw *GW, teamOf map[int]int) []FixtureBonus {
+	fixturesByTeam := make(map[int][]int)
+	byID := make(map[int]*FixtureBonus)
+	out := make([]FixtureBonus, 0, len(gw.Fixtures))
+	for _, f := range gw.Fixtures {
+		fixturesByTeam[f.TeamH] = append(fixturesByTeam[f.TeamH], f.ID)
+		fixturesByTeam[f.TeamA] = append(fixturesByTeam[f.TeamA], f.ID)
+		if !f.Started {
+			continue
+		}
+		out = append(out, FixtureBonus{Fixture: f.ID, TeamH: f.TeamH, TeamA: f.TeamA, Finished: f.Finished, BPS: make(map[int]int)})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Fixture < out[j].Fixture })
+	for i := range out {
+		byID[out[i].Fixture] = &out[i]
+	}
+
+	confirmed := make(map[int]map[int]int)
+	for id, table := range gw.FixtureBonus {
+		if len(table) > 0 {
+			confirmed[id] = table
+		}
+	}
+	for id, s := range gw.Elements {
+		if s.Minutes <= 0 {
+			continue
+		}
+		if len(s.ByFixture) > 0 {
+			for fix, fs := range s.ByFixture {
+				fb := byID[fix]
+				if fb == nil || fs.Minutes <= 0 {
+					continue
+				}
+				if fs.Bonus > 0 {
+					if confirmed[fix] == nil {
+						confirmed[fix] = make(map[int]int)
+					}
+					confirmed[fix][id] = fs.Bonus
+				}
+				if _, ok := gw.FixtureBPS[fix]; ok {
+					continue
+				}
+				bps := fs.BPS
+				if !fs.HasBPS && len(s.ByFixture) == 1 {
+					bps = s.BPS
+				}
+				fb.BPS[id] = bps
+			}
+			continue
+		}
+		fixtures := fixturesByTeam[teamOf[id]]
+		if len(fixtures) != 1 {
+			continue
+		}
+		if fb := byID[fixtures[0]]; fb != nil {
+			if _, ok := gw.FixtureBPS[fb.Fixture]; !ok {
+				fb.BPS[id] = s.BPS
+			}
+		}
+	}
+	for i := range out {
+		fb := &out[i]
+		if table, ok := gw.FixtureBPS[fb.Fixture]; ok {
+			for id, v := range table {
+				if gw.Elements[id].Minutes > 0 {
+					fb.BPS[id] = v
+				}
+			}
+		}
+		if table, ok := confirmed[fb.Fixture]; ok {
+			fb.Status = BonusConfirmed
+			fb.Bonus = table
+			continue
+		}
+		fb.Status = BonusProvisional
+		fb.Bonus = BonusForBPS(fb.BPS)
+	}
+	return out
+}
@@ -0,0 +1,120 @@
+package livestats
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestBonusForBPS_Ties(t *testing.T) {
+	tests := []struct {
+		name string
+		bps  map[int]int
+		want map[int]int
+	}{
+		{"clear top three", map[int]int{1: 40, 2: 30, 3: 20, 4: 10}, map[int]int{1: 3, 2: 2, 3: 1}},
+		{"tie for first", map[int]int{1: 40, 2: 40, 3: 30, 4: 20}, map[int]int{1: 3, 2: 3, 3: 1}},
+		{"three tied for first", map[int]int{1: 40, 2: 40, 3: 40, 4: 30}, map[int]int{1: 3, 2: 3, 3: 3}},
+		{"four tied for first", map[int]int{1: 9, 2: 9, 3: 9, 4: 9, 5: 8}, map[int]int{1: 3, 2: 3, 3: 3, 4: 3}},
+		{"tie for second", map[int]int{1: 40, 2: 30, 3: 30, 4: 20}, map[int]int{1: 3, 2: 2, 3: 2}},
+		{"three tied for second", map[int]int{1: 40, 2: 30, 3: 30, 4: 30, 5: 20}, map[int]int{1: 3, 2: 2, 3: 2, 4: 2}},
+		{"tie for third", map[int]int{1: 40, 2: 30, 3: 20, 4: 20, 5: 10}, map[int]int{1: 3, 2: 2, 3: 1, 4: 1}},
+		{"tie for first and third", map[int]int{1: 40, 2: 40, 3: 20, 4: 20}, map[int]int{1: 3, 2: 3, 3: 1, 4: 1}},
+		{"negative BPS still ranks", map[int]int{1: 2, 2: -1, 3: -3, 4: -5}, map[int]int{1: 3, 2: 2, 3: 1}},
+		{"fewer than three players", map[int]int{1: 12, 2: 5}, map[int]int{1: 3, 2: 2}},
+		{"nobody has played", map[int]int{}, map[int]int{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := BonusForBPS(tt.bps)
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("BonusForBPS(%v) = %v, want %v", tt.bps, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProvisionalBonus(t *testing.T) {
+	raw := []byte(`{
+		"elements": {
+			"1": {"stats": {"minutes": 90, "bps": 35}},
+			"2": {"stats": {"minutes": 90, "bps": 28}},
+			"3": {"stats": {"minutes": 0, "bps": 0}},
+			"4": {"stats": {"minutes": 60, "bps": 31}},
+			"10": {"stats": {"minutes": 90, "bps": 20, "bonus": 3}, "explain": [
+				{"fixture": 2, "stats": [{"identifier": "minutes", "points": 2, "value": 90}, {"identifier": "bonus", "points": 3, "value": 3}]}
+			]},
+			"11": {"stats": {"minutes": 80, "bps": 18}, "explain": [
+				{"fixture": 2, "stats": [{"identifier": "minutes", "points": 2, "value": 80}]}
+			]},
+			"20": {"stats": {"minutes": 90, "bps": 22}, "explain": [
+				{"fixture": 3, "stats": [{"identifier": "minutes", "points": 2, "value": 90}]}
+			]},
+			"21": {"stats": {"minutes": 90, "bps": 22}, "explain": [
+				{"fixture": 3, "stats": [{"identifier": "minutes", "points": 2, "value": 90}]}
+			]},
+			"22": {"stats": {"minutes": 45, "bps": 9}, "explain": [
+				{"fixture": 3, "stats": [{"identifier": "minutes", "points": 1, "value": 45}]}
+			]},
+			"30": {"stats": {"minutes": 90, "bps": 50}}
+		},
+		"fixtures": [
+			{"id": 1, "team_h": 1, "team_a": 2, "started": true, "finished": false},
+			{"id": 2, "team_h": 3, "team_a": 4, "started": true, "finished": true,
+				"stats": [{"identifier": "bonus", "h": [{"element": 10, "value": 3}], "a": []}]},
+			{"id": 3, "team_h": 5, "team_a": 6, "started": true, "finished": false,
+				"stats": [{"identifier": "bps", "h": [{"element": 20, "value": 24}, {"element": 22, "value": 9}], "a": [{"element": 21, "value": 24}]}]},
+			{"id": 4, "team_h": 7, "team_a": 8, "started": false}
+		]
+	}`)
+	gw, err := Parse(raw, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Elements 1-4 have no explain data, so their fixture comes from their team.
+	teamOf := map[int]int{1: 1, 2: 1, 3: 2, 4: 2, 30: 7}
+	got := ProvisionalBonus(gw, teamOf)
+	if len(got) != 3 {
+		t.Fatalf("got %d fixtures, want the 3 started ones: %+v", len(got), got)
+	}
+
+	f1 := got[0]
+	if f1.Fixture != 1 || f1.Status != BonusProvisional || len(f1.BPS) != 3 {
+		t.Errorf("fixture 1 = %+v, want provisional from 3 players who played", f1)
+	}
+	if fmt.Sprint(f1.Bonus) != "map[1:3 2:1 4:2]" {
+		t.Errorf("fixture 1 bonus = %v, want 1:3 4:2 2:1", f1.Bonus)
+	}
+
+	f2 := got[1]
+	if f2.Status != BonusConfirmed || fmt.Sprint(f2.Bonus) != "map[10:3]" {
+		t.Errorf("fixture 2 = %+v, want the confirmed table", f2)
+	}
+
+	// Fixture 3's bps table wins over the element stats, tying 20 and 21.
+	f3 := got[2]
+	if f3.Status != BonusProvisional || fmt.Sprint(f3.Bonus) != "map[20:3 21:3 22:1]" {
+		t.Errorf("fixture 3 bonus = %v, want 20 and 21 sharing 3 and 22 on 1", f3.Bonus)
+	}
+}
+
+func TestProvisionalBonus_ConfirmedFromExplain(t *testing.T) {
+	raw := []byte(`{
+		"elements": {
+			"1": {"stats": {"minutes": 90, "bps": 30, "bonus": 2}, "explain": [
+				{"fixture": 5, "stats": [{"identifier": "minutes", "points": 2, "value": 90}, {"identifier": "bonus", "points": 2, "value": 2}]}
+			]},
+			"2": {"stats": {"minutes": 90, "bps": 40}, "explain": [
+				{"fixture": 5, "stats": [{"identifier": "minutes", "points": 2, "value": 90}]}
+			]}
+		},
+		"fixtures": [{"id": 5, "team_h": 1, "team_a": 2, "started": true, "finished": true}]
+	}`)
+	gw, err := Parse(raw, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := ProvisionalBonus(gw, nil)
+	if len(got) != 1 || got[0].Status != BonusConfirmed || fmt.Sprint(got[0].Bonus) != "map[1:2]" {
+		t.Errorf("got %+v, want bonus confirmed from the explain entries", got)
+	}
+}
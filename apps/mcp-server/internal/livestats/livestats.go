@@ -0,0 +1,295 @@
+// Package livestats parses gw/{gw}/live.json into typed per-element stats.
+// It is the one place live stats are decoded, so a stat added to
+// ElementStats is available to every consumer.
+package livestats
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/jsonutil"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+)
+
+// ElementStats is one player's stats for a gameweek, summed across all of
+// their team's fixtures in that GW.
+type ElementStats struct {
+	Minutes         int
+	TotalPoints     int
+	Starts          int
+	GoalsScored     int
+	Assists         int
+	CleanSheets     int
+	GoalsConceded   int
+	OwnGoals        int
+	PenaltiesSaved  int
+	PenaltiesMissed int
+	YellowCards     int
+	RedCards        int
+	Saves           int
+	Bonus           int
+	BPS             int
+	// DefensiveContribution is the clearances/blocks/interceptions/tackles
+	// (plus recoveries for midfielders and forwards) count behind the
+	// defensive contribution bonus. Zero in seasons before it existed.
+	DefensiveContribution int
+	Influence             float64
+	Creativity            float64
+	Threat                float64
+	ICT                   float64
+	XG                    float64
+	XA                    float64
+	XGI                   float64
+	XGC                   float64
+	// Explain is the points per scoring identifier from the API's explain
+	// data (e.g. "red_cards": -3), summed across fixtures. Nil when live.json
+	// has no explain data.
+	Explain map[string]int
+	// ByFixture splits the explain data by fixture id, so a double GW's two
+	// matches can be told apart. Nil when live.json has no explain data.
+	ByFixture map[int]FixtureStats
+}
+
+// FixtureStats is one player's explain data for a single fixture. HasBPS is
+// set when the explain entry lists a "bps" value; otherwise BPS is zero.
+type FixtureStats struct {
+	Minutes int
+	Bonus   int
+	BPS     int
+	HasBPS  bool
+}
+
+// Fixture is one fixture entry from live.json. Event is the fixture's GW
+// when the file was fetched, 0 when it was null or missing. KickoffTime is as the API
+// sent it, empty when null.
+type Fixture struct {
+	ID          int
+	Event       int
+	TeamH       int
+	TeamA       int
+	KickoffTime string
+	Started     bool
+	Finished    bool
+}
+
+// GW is a decoded live.json. Callers share cached values and must not modify
+// them.
+type GW struct {
+	Event    int
+	Elements map[int]ElementStats
+	Fixtures []Fixture
+	// FixtureBPS and FixtureBonus hold each fixture's "bps" and "bonus"
+	// tables from fixtures[].stats, by fixture id then element. A fixture
+	// without those tables has no entry.
+	FixtureBPS   map[int]map[int]int
+	FixtureBonus map[int]map[int]int
+}
+
+// wireStats is an element's stats as live.json sends them. Every field goes
+// through jsonutil: the API has sent expected_goals, ict_index and friends as
+// quoted decimals and as numbers, and may flip any other stat the same way.
+type wireStats struct {
+	Minutes               jsonutil.FlexInt   `json:"minutes"`
+	TotalPoints           jsonutil.FlexInt   `json:"total_points"`
+	Starts                jsonutil.FlexInt   `json:"starts"`
+	GoalsScored           jsonutil.FlexInt   `json:"goals_scored"`
+	Assists               jsonutil.FlexInt   `json:"assists"`
+	CleanSheets           jsonutil.FlexInt   `json:"clean_sheets"`
+	GoalsConceded         jsonutil.FlexInt   `json:"goals_conceded"`
+	OwnGoals              jsonutil.FlexInt   `json:"own_goals"`
+	PenaltiesSaved        jsonutil.FlexInt   `json:"penalties_saved"`
+	PenaltiesMissed       jsonutil.FlexInt   `json:"penalties_missed"`
+	YellowCards           jsonutil.FlexInt   `json:"yellow_cards"`
+	RedCards              jsonutil.FlexInt   `json:"red_cards"`
+	Saves                 jsonutil.FlexInt   `json:"saves"`
+	Bonus                 jsonutil.FlexInt   `json:"bonus"`
+	BPS                   jsonutil.FlexInt   `json:"bps"`
+	DefensiveContribution jsonutil.FlexInt   `json:"defensive_contribution"`
+	Influence             jsonutil.FlexFloat `json:"influence"`
+	Creativity            jsonutil.FlexFloat `json:"creativity"`
+	Threat                jsonutil.FlexFloat `json:"threat"`
+	ICTIndex              jsonutil.FlexFloat `json:"ict_index"`
+	ExpectedGoals         jsonutil.FlexFloat `json:"expected_goals"`
+	ExpectedAssists       jsonutil.FlexFloat `json:"expected_assists"`
+	ExpectedGoalInv       jsonutil.FlexFloat `json:"expected_goal_involvements"`
+	ExpectedGoalsConceded jsonutil.FlexFloat `json:"expected_goals_conceded"`
+}
+
+func (w wireStats) stats() ElementStats {
+	return ElementStats{
+		Minutes:               int(w.Minutes),
+		TotalPoints:           int(w.TotalPoints),
+		Starts:                int(w.Starts),
+		GoalsScored:           int(w.GoalsScored),
+		Assists:               int(w.Assists),
+		CleanSheets:           int(w.CleanSheets),
+		GoalsConceded:         int(w.GoalsConceded),
+		OwnGoals:              int(w.OwnGoals),
+		PenaltiesSaved:        int(w.PenaltiesSaved),
+		PenaltiesMissed:       int(w.PenaltiesMissed),
+		YellowCards:           int(w.YellowCards),
+		RedCards:              int(w.RedCards),
+		Saves:                 int(w.Saves),
+		Bonus:                 int(w.Bonus),
+		BPS:                   int(w.BPS),
+		DefensiveContribution: int(w.DefensiveContribution),
+		Influence:             float64(w.Influence),
+		Creativity:            float64(w.Creativity),
+		Threat:                float64(w.Threat),
+		ICT:                   float64(w.ICTIndex),
+		XG:                    float64(w.ExpectedGoals),
+		XA:                    float64(w.ExpectedAssists),
+		XGI:                   float64(w.ExpectedGoalInv),
+		XGC:                   float64(w.ExpectedGoalsConceded),
+	}
+}
+
+// Parse decodes a live.json body for gw. Element keys that aren't integers
+// are skipped.
+func Parse(raw []byte, gw int) (*GW, error) {
+	var resp struct {
+		Elements map[string]struct {
+			Stats   wireStats `json:"stats"`
+			Explain []struct {
+				Fixture int `json:"fixture"`
+				Stats   []struct {
+					Identifier string           `json:"identifier"`
+					Points     jsonutil.FlexInt `json:"points"`
+					Value      jsonutil.FlexInt `json:"value"`
+				} `json:"stats"`
+			} `json:"explain"`
+		} `json:"elements"`
+		Fixtures []struct {
+			ID       int    `json:"id"`
+			Event    *int   `json:"event"`
+			TeamH    int    `json:"team_h"`
+			TeamA    int    `json:"team_a"`
+			Started  bool   `json:"started"`
+			Finished bool   `json:"finished"`
+			Kickoff  string `json:"kickoff_time"`
+			Stats    []struct {
+				Identifier string        `json:"identifier"`
+				H          []fixtureStat `json:"h"`
+				A          []fixtureStat `json:"a"`
+			} `json:"stats"`
+		} `json:"fixtures"`
+	}
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return nil, fmt.Errorf("parse gw/%d/live.json: %w", gw, err)
+	}
+	out := &GW{
+		Event:        gw,
+		Elements:     make(map[int]ElementStats, len(resp.Elements)),
+		Fixtures:     make([]Fixture, 0, len(resp.Fixtures)),
+		FixtureBPS:   make(map[int]map[int]int),
+		FixtureBonus: make(map[int]map[int]int),
+	}
+	for k, v := range resp.Elements {
+		id, err := strconv.Atoi(k)
+		if err != nil {
+			continue
+		}
+		stats := v.Stats.stats()
+		for _, fx := range v.Explain {
+			if stats.ByFixture == nil {
+				stats.ByFixture = make(map[int]FixtureStats)
+			}
+			fs := stats.ByFixture[fx.Fixture]
+			for _, e := range fx.Stats {
+				if stats.Explain == nil {
+					stats.Explain = make(map[string]int)
+				}
+				stats.Explain[e.Identifier] += int(e.Points)
+				switch e.Identifier {
+				case "minutes":
+					fs.Minutes += int(e.Value)
+				case "bonus":
+					fs.Bonus += int(e.Points)
+				case "bps":
+					fs.BPS += int(e.Value)
+					fs.HasBPS = true
+				}
+			}
+			stats.ByFixture[fx.Fixture] = fs
+		}
+		out.Elements[id] = stats
+	}
+	for _, f := range resp.Fixtures {
+		event := 0
+		if f.Event != nil {
+			event = *f.Event
+		}
+		out.Fixtures = append(out.Fixtures, Fixture{ID: f.ID, Event: event, TeamH: f.TeamH, TeamA: f.TeamA, KickoffTime: f.Kickoff, Started: f.Started, Finished: f.Finished})
+		for _, st := range f.Stats {
+			var dst map[int]map[int]int
+			switch st.Identifier {
+			case "bps":
+				dst = out.FixtureBPS
+			case "bonus":
+				dst = out.FixtureBonus
+			default:
+				continue
+			}
+			table := make(map[int]int, len(st.H)+len(st.A))
+			for _, side := range [][]fixtureStat{st.H, st.A} {
+				for _, v := range side {
+					table[v.Element] = int(v.Value)
+				}
+			}
+			dst[f.ID] = table
+		}
+	}
+	return out, nil
+}
+
+// fixtureStat is one row of a fixtures[].stats table.
+type fixtureStat struct {
+	Element int              `json:"element"`
+	Value   jsonutil.FlexInt `json:"value"`
+}
+
+type cacheEntry struct {
+	modTime time.Time
+	size    int64
+	gw      *GW
+}
+
+var (
+	cacheMu sync.Mutex
+	cache   = make(map[string]cacheEntry)
+)
+
+// LoadGW reads and parses gw/{gw}/live.json from st. Parsed files are cached
+// by path and reused until the file's mtime or size changes, so a refresh
+// pipeline writing new data is picked up on the next call.
+func LoadGW(st *store.JSONStore, gw int) (*GW, error) {
+	rel := fmt.Sprintf("gw/%d/live.json", gw)
+	path := st.Path(rel)
+	info, err := st.Stat(rel)
+	if err != nil {
+		return nil, err
+	}
+
+	cacheMu.Lock()
+	e, ok := cache[path]
+	cacheMu.Unlock()
+	if ok && e.modTime.Equal(info.ModTime()) && e.size == info.Size() {
+		return e.gw, nil
+	}
+
+	raw, err := st.ReadRaw(rel)
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := Parse(raw, gw)
+	if err != nil {
+		return nil, err
+	}
+	cacheMu.Lock()
+	cache[path] = cacheEntry{modTime: info.ModTime(), size: info.Size(), gw: parsed}
+	cacheMu.Unlock()
+	return parsed, nil
+}
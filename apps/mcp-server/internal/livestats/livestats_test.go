@@ -0,0 +1,213 @@
+package livestats
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+	"testing/quick"
+	"time"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+)
+
+func approx(a, b float64) bool { return math.Abs(a-b) < 1e-9 }
+
+func TestParse_StringEncodedNumbers(t *testing.T) {
+	raw := []byte(`{
+		"elements": {
+			"1": {"stats": {
+				"minutes": 90, "total_points": 12, "goals_scored": 2, "assists": 1,
+				"saves": 0, "bonus": 3, "bps": 41, "yellow_cards": 1,
+				"expected_goals": "1.23", "expected_assists": "0.40",
+				"expected_goal_involvements": "1.63", "expected_goals_conceded": "0.90",
+				"influence": "64.2", "creativity": "22.0", "threat": "71.0", "ict_index": "15.7",
+				"defensive_contribution": 4
+			}},
+			"2": {"stats": {
+				"minutes": "90", "total_points": -1, "saves": 6, "goals_conceded": 3,
+				"expected_goals": 0, "ict_index": "", "threat": null
+			}},
+			"not-an-id": {"stats": {"minutes": 90}}
+		},
+		"fixtures": [
+			{"id": 7, "team_h": 1, "team_a": 2, "started": true, "finished": false, "kickoff_time": "2025-09-13T14:00:00Z"},
+			{"id": 8, "team_h": 3, "team_a": 4, "kickoff_time": null}
+		]
+	}`)
+	gw, err := Parse(raw, 4)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if gw.Event != 4 || len(gw.Elements) != 2 {
+		t.Fatalf("event=%d elements=%d, want 4 and 2", gw.Event, len(gw.Elements))
+	}
+
+	a := gw.Elements[1]
+	if a.Minutes != 90 || a.TotalPoints != 12 || a.GoalsScored != 2 || a.Bonus != 3 || a.BPS != 41 || a.YellowCards != 1 || a.DefensiveContribution != 4 {
+		t.Errorf("ints = %+v", a)
+	}
+	if !approx(a.XG, 1.23) || !approx(a.XA, 0.40) || !approx(a.XGI, 1.63) || !approx(a.XGC, 0.90) {
+		t.Errorf("expected stats = %v %v %v %v", a.XG, a.XA, a.XGI, a.XGC)
+	}
+	if !approx(a.Influence, 64.2) || !approx(a.Creativity, 22) || !approx(a.Threat, 71) || !approx(a.ICT, 15.7) {
+		t.Errorf("ict = %v %v %v %v", a.Influence, a.Creativity, a.Threat, a.ICT)
+	}
+
+	b := gw.Elements[2]
+	if b.Minutes != 90 || b.TotalPoints != -1 || b.Saves != 6 || b.GoalsConceded != 3 {
+		t.Errorf("keeper = %+v", b)
+	}
+	if b.XG != 0 || b.ICT != 0 || b.Threat != 0 {
+		t.Errorf("zero/empty/null stats = %v %v %v, want 0", b.XG, b.ICT, b.Threat)
+	}
+
+	if len(gw.Fixtures) != 2 || gw.Fixtures[0] != (Fixture{ID: 7, TeamH: 1, TeamA: 2, KickoffTime: "2025-09-13T14:00:00Z", Started: true}) ||
+		gw.Fixtures[1] != (Fixture{ID: 8, TeamH: 3, TeamA: 4}) {
+		t.Errorf("fixtures = %+v", gw.Fixtures)
+	}
+}
+
+func TestParse_MalformedStat(t *testing.T) {
+	if _, err := Parse([]byte(`{"elements": {"1": {"stats": {"expected_goals": "abc"}}}}`), 1); err == nil {
+		t.Error("expected an error for a non-numeric stat string")
+	}
+}
+
+// statKeys are the stats wireStats decodes.
+var statKeys = []string{
+	"minutes", "total_points", "starts", "goals_scored", "assists", "clean_sheets",
+	"goals_conceded", "own_goals", "penalties_saved", "penalties_missed", "yellow_cards",
+	"red_cards", "saves", "bonus", "bps", "defensive_contribution", "influence",
+	"creativity", "threat", "ict_index", "expected_goals", "expected_assists",
+	"expected_goal_involvements", "expected_goals_conceded",
+}
+
+// An older GW file with every stat as a number and a newer one with every
+// stat quoted decode to the same ElementStats, whatever the values.
+func TestParse_MixedEncodingsAgree(t *testing.T) {
+	check := func(ints [16]int16, floats [8]uint16, quote uint32) bool {
+		plain := make(map[string]any, len(statKeys))
+		mixed := make(map[string]any, len(statKeys))
+		for i, key := range statKeys {
+			var text string
+			if i < len(ints) {
+				text = strconv.Itoa(int(ints[i]))
+			} else {
+				text = strconv.FormatFloat(float64(floats[i-len(ints)])/100, 'f', 2, 64)
+			}
+			plain[key] = json.Number(text)
+			mixed[key] = json.Number(text)
+			if quote&(1<<i) != 0 {
+				mixed[key] = text
+			}
+		}
+		parse := func(stats map[string]any) ElementStats {
+			raw, _ := json.Marshal(map[string]any{"elements": map[string]any{"1": map[string]any{"stats": stats}}})
+			gw, err := Parse(raw, 1)
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			return gw.Elements[1]
+		}
+		a, b := parse(plain), parse(mixed)
+		return reflect.DeepEqual(a, b) && a.Minutes == int(ints[0]) && approx(a.XGC, float64(floats[7])/100)
+	}
+	if err := quick.Check(check, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func FuzzParse(f *testing.F) {
+	f.Add([]byte(`{"elements": {"1": {"stats": {"minutes": 90, "expected_goals": "0.75", "ict_index": null}}}}`))
+	f.Add([]byte(`{"elements": {"2": {"stats": {"minutes": "90", "total_points": "-1"}, "explain": [{"fixture": 3, "stats": [{"identifier": "bps", "points": 0, "value": "17"}]}]}}}`))
+	f.Add([]byte(`{"elements": {"x": {}}, "fixtures": [{"id": 1, "event": null, "stats": [{"identifier": "bps", "h": [{"element": 1, "value": "3"}]}]}]}`))
+	f.Fuzz(func(t *testing.T, raw []byte) {
+		gw, err := Parse(raw, 1)
+		if err != nil {
+			return
+		}
+		for id, s := range gw.Elements {
+			for _, v := range []float64{s.Influence, s.Creativity, s.Threat, s.ICT, s.XG, s.XA, s.XGI, s.XGC} {
+				if math.IsNaN(v) || math.IsInf(v, 0) {
+					t.Fatalf("element %d decoded a non-finite stat: %+v", id, s)
+				}
+			}
+		}
+		// Decoding is deterministic: the same bytes give the same stats.
+		again, err := Parse(raw, 1)
+		if err != nil || fmt.Sprint(again.Elements) != fmt.Sprint(gw.Elements) {
+			t.Fatalf("second parse differs: %v", err)
+		}
+	})
+}
+
+func TestParse_ExplainSummedAcrossFixtures(t *testing.T) {
+	raw := []byte(`{"elements": {
+		"1": {"stats": {"total_points": -2}, "explain": [
+			{"fixture": 1, "stats": [{"identifier": "minutes", "points": 2, "value": 90}, {"identifier": "own_goals", "points": -2, "value": 1}]},
+			{"fixture": 2, "stats": [{"identifier": "minutes", "points": 1, "value": 30}, {"identifier": "red_cards", "points": "-3", "value": 1}]}
+		]},
+		"2": {"stats": {"total_points": 1}}
+	}}`)
+	gw, err := Parse(raw, 5)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	got := gw.Elements[1].Explain
+	if got["minutes"] != 3 || got["own_goals"] != -2 || got["red_cards"] != -3 || len(got) != 3 {
+		t.Errorf("explain = %v, want minutes 3, own_goals -2, red_cards -3", got)
+	}
+	if gw.Elements[2].Explain != nil {
+		t.Errorf("element without explain = %v, want nil", gw.Elements[2].Explain)
+	}
+}
+
+func TestLoadGW_CachesUntilFileChanges(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "gw", "3", "live.json")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	write := func(body string, mtime time.Time) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	st := store.NewJSONStore(dir)
+	t0 := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+	write(`{"elements": {"1": {"stats": {"total_points": 2}}}}`, t0)
+
+	first, err := LoadGW(st, 3)
+	if err != nil {
+		t.Fatalf("LoadGW: %v", err)
+	}
+	second, err := LoadGW(st, 3)
+	if err != nil {
+		t.Fatalf("LoadGW: %v", err)
+	}
+	if first != second {
+		t.Error("second load should come from the cache")
+	}
+
+	write(`{"elements": {"1": {"stats": {"total_points": 9}}}}`, t0.Add(time.Minute))
+	third, err := LoadGW(st, 3)
+	if err != nil {
+		t.Fatalf("LoadGW: %v", err)
+	}
+	if third.Elements[1].TotalPoints != 9 {
+		t.Errorf("after rewrite total_points = %d, want 9", third.Elements[1].TotalPoints)
+	}
+
+	if _, err := LoadGW(st, 4); !os.IsNotExist(err) {
+		t.Errorf("missing GW: err = %v, want not-exist", err)
+	}
+}
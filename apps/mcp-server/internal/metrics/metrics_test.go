@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWriteText(t *testing.T) {
+	r := NewRegistry()
+	calls := r.Counter("tool_calls_total", "Tool calls.", "tool")
+	calls.Inc("b")
+	calls.Inc("a")
+	calls.Add(2, "a")
+
+	lat := r.Histogram("tool_seconds", "Tool latency.", []float64{0.5, 1}, "tool")
+	lat.Observe(0.25, "a")
+	lat.Observe(0.75, "a")
+	lat.Observe(3, "a")
+
+	r.GaugeFunc("data_age_seconds", "Data age.", []string{"file"}, func() []Sample {
+		return []Sample{{LabelValues: []string{"game"}, Value: 42}}
+	})
+
+	var b strings.Builder
+	if err := r.WriteText(&b); err != nil {
+		t.Fatal(err)
+	}
+	got := b.String()
+	want := []string{
+		"# TYPE tool_calls_total counter",
+		`tool_calls_total{tool="a"} 3`,
+		`tool_calls_total{tool="b"} 1`,
+		"# TYPE tool_seconds histogram",
+		`tool_seconds_bucket{tool="a",le="0.5"} 1`,
+		`tool_seconds_bucket{tool="a",le="1"} 2`,
+		`tool_seconds_bucket{tool="a",le="+Inf"} 3`,
+		`tool_seconds_sum{tool="a"} 4`,
+		`tool_seconds_count{tool="a"} 3`,
+		"# TYPE data_age_seconds gauge",
+		`data_age_seconds{file="game"} 42`,
+	}
+	for _, line := range want {
+		if !strings.Contains(got, line+"\n") {
+			t.Errorf("missing line %q in:\n%s", line, got)
+		}
+	}
+	if strings.Index(got, `tool="a"} 3`) > strings.Index(got, `tool="b"} 1`) {
+		t.Error("series should be sorted by label values")
+	}
+}
+
+func TestCounterValue(t *testing.T) {
+	c := NewRegistry().Counter("x_total", "X.", "code")
+	c.Inc("NOT_FOUND")
+	if got := c.Value("NOT_FOUND"); got != 1 {
+		t.Errorf("value = %v, want 1", got)
+	}
+	if got := c.Value("INTERNAL"); got != 0 {
+		t.Errorf("unset value = %v, want 0", got)
+	}
+}
@@ -1,26 +1,22 @@
 package points
 
 import (
-	"encoding/json"
-	"os"
-	"path/filepath"
-	"time"
-
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/clock"
 	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/ledger"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/livestats"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
 )
 
-type LiveStats struct {
-	Minutes     int `json:"minutes"`
-	TotalPoints int `json:"total_points"`
-}
-
 // PlayerPoints holds the per-player scoring breakdown for one gameweek.
 // FPL Draft has no captain mechanic, so points are always raw (no multiplier).
+// SubbedIn marks a bench player who scores in place of a starter through an
+// automatic substitution; Position stays their bench slot.
 type PlayerPoints struct {
-	Element  int `json:"element"`
-	Position int `json:"position"`
-	Minutes  int `json:"minutes"`
-	Points   int `json:"points"`
+	Element  int  `json:"element"`
+	Position int  `json:"position"`
+	Minutes  int  `json:"minutes"`
+	Points   int  `json:"points"`
+	SubbedIn bool `json:"subbed_in,omitempty"`
 }
 
 type Result struct {
@@ -30,14 +26,47 @@ type Result struct {
 	GeneratedAtUTC string         `json:"generated_at_utc"`
 	Players        []PlayerPoints `json:"players"`
 	TotalPoints    int            `json:"total_points"`
+	// AutoSubs are the snapshot's automatic substitutions that were applied.
+	AutoSubs []ledger.EntrySub `json:"auto_subs,omitempty"`
+	// MissingSnapshot is set for a GW before the entry joined the league,
+	// which scores zero.
+	MissingSnapshot bool `json:"missing_snapshot,omitempty"`
 }
 
-func BuildResult(leagueID int, entryID int, gw int, snap *ledger.EntrySnapshot, liveByElement map[int]LiveStats) *Result {
+// BuildResult totals the starting XI's points, after applying the
+// snapshot's automatic substitutions: a starter with no minutes is replaced
+// by the bench player the sub names. A sub that doesn't fit the snapshot (the
+// outgoing player started or isn't in the XI, or the incoming one isn't on the
+// bench) is skipped rather than trusted.
+func BuildResult(leagueID int, entryID int, gw int, snap *ledger.EntrySnapshot, liveByElement map[int]livestats.ElementStats, clk clock.Clock) *Result {
 	players := make([]PlayerPoints, 0, 11)
 	total := 0
 
+	position := make(map[int]int, len(snap.Picks))
 	for _, p := range snap.Picks {
-		if p.Position > 11 {
+		position[p.Element] = p.Position
+	}
+	subbedOut := make(map[int]bool)
+	subbedIn := make(map[int]bool)
+	var applied []ledger.EntrySub
+	for _, s := range snap.Subs {
+		if s.Event != 0 && s.Event != gw {
+			continue
+		}
+		out, in := position[s.ElementOut], position[s.ElementIn]
+		if out == 0 || out > 11 || in <= 11 || subbedOut[s.ElementOut] || subbedIn[s.ElementIn] {
+			continue
+		}
+		if liveByElement[s.ElementOut].Minutes > 0 {
+			continue
+		}
+		subbedOut[s.ElementOut] = true
+		subbedIn[s.ElementIn] = true
+		applied = append(applied, s)
+	}
+
+	for _, p := range snap.Picks {
+		if (p.Position > 11 && !subbedIn[p.Element]) || subbedOut[p.Element] {
 			continue
 		}
 		live := liveByElement[p.Element]
@@ -46,31 +75,24 @@ func BuildResult(leagueID int, entryID int, gw int, snap *ledger.EntrySnapshot,
 			Position: p.Position,
 			Minutes:  live.Minutes,
 			Points:   live.TotalPoints,
+			SubbedIn: subbedIn[p.Element],
 		}
 		players = append(players, pp)
 		total += pp.Points
 	}
 
 	return &Result{
-		LeagueID:       leagueID,
-		EntryID:        entryID,
-		Gameweek:       gw,
-		GeneratedAtUTC: time.Now().UTC().Format(time.RFC3339),
-		Players:        players,
-		TotalPoints:    total,
+		LeagueID:        leagueID,
+		EntryID:         entryID,
+		Gameweek:        gw,
+		GeneratedAtUTC:  clock.UTC(clock.Or(clk).Now()),
+		Players:         players,
+		TotalPoints:     total,
+		AutoSubs:        applied,
+		MissingSnapshot: snap.Missing,
 	}
 }
 
 func WriteResult(path string, result *Result) error {
-	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
-		return err
-	}
-
-	b, err := json.MarshalIndent(result, "", "  ")
-	if err != nil {
-		return err
-	}
-
-	b = append(b, '\n')
-	return os.WriteFile(path, b, 0o644)
+	return store.WriteDerivedJSON(path, result)
 }
@@ -1,12 +1,16 @@
 package points
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/clock"
 	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/ledger"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/livestats"
 )
 
 // makeSnap builds a minimal EntrySnapshot from (element, position) pairs.
@@ -24,12 +28,12 @@ func TestBuildResult_BasicPoints(t *testing.T) {
 		struct{ elem, pos int }{10, 1},
 		struct{ elem, pos int }{20, 2},
 	)
-	live := map[int]LiveStats{
+	live := map[int]livestats.ElementStats{
 		10: {Minutes: 90, TotalPoints: 6},
 		20: {Minutes: 90, TotalPoints: 4},
 	}
 
-	r := BuildResult(1, 2, 3, snap, live)
+	r := BuildResult(1, 2, 3, snap, live, nil)
 
 	if r.TotalPoints != 10 {
 		t.Errorf("TotalPoints = %d, want 10", r.TotalPoints)
@@ -45,12 +49,12 @@ func TestBuildResult_RawPointsOnly(t *testing.T) {
 		struct{ elem, pos int }{10, 1},
 		struct{ elem, pos int }{20, 2},
 	)
-	live := map[int]LiveStats{
+	live := map[int]livestats.ElementStats{
 		10: {Minutes: 90, TotalPoints: 6},
 		20: {Minutes: 90, TotalPoints: 3},
 	}
 
-	r := BuildResult(1, 1, 1, snap, live)
+	r := BuildResult(1, 1, 1, snap, live, nil)
 
 	if r.TotalPoints != 9 {
 		t.Errorf("TotalPoints = %d, want 9 (no captain doubling in draft)", r.TotalPoints)
@@ -68,12 +72,12 @@ func TestBuildResult_BenchPlayersExcluded(t *testing.T) {
 		struct{ elem, pos int }{10, 1},
 		struct{ elem, pos int }{99, 12}, // bench — excluded
 	)
-	live := map[int]LiveStats{
+	live := map[int]livestats.ElementStats{
 		10: {Minutes: 90, TotalPoints: 6},
 		99: {Minutes: 90, TotalPoints: 8}, // bench player scored big — must not count
 	}
 
-	r := BuildResult(1, 1, 1, snap, live)
+	r := BuildResult(1, 1, 1, snap, live, nil)
 
 	if r.TotalPoints != 6 {
 		t.Errorf("TotalPoints = %d, want 6 (bench excluded)", r.TotalPoints)
@@ -89,12 +93,12 @@ func TestBuildResult_MissingLiveStats(t *testing.T) {
 		struct{ elem, pos int }{10, 1},
 		struct{ elem, pos int }{20, 2},
 	)
-	live := map[int]LiveStats{
+	live := map[int]livestats.ElementStats{
 		10: {Minutes: 90, TotalPoints: 5},
 		// 20 absent — defaults to 0
 	}
 
-	r := BuildResult(1, 1, 1, snap, live)
+	r := BuildResult(1, 1, 1, snap, live, nil)
 
 	if r.TotalPoints != 5 {
 		t.Errorf("TotalPoints = %d, want 5 (missing stats = 0)", r.TotalPoints)
@@ -103,7 +107,7 @@ func TestBuildResult_MissingLiveStats(t *testing.T) {
 
 func TestBuildResult_EmptyPicks(t *testing.T) {
 	snap := &ledger.EntrySnapshot{Picks: []ledger.EntryPick{}}
-	r := BuildResult(1, 1, 1, snap, map[int]LiveStats{})
+	r := BuildResult(1, 1, 1, snap, map[int]livestats.ElementStats{}, nil)
 
 	if r.TotalPoints != 0 {
 		t.Errorf("TotalPoints = %d, want 0 for empty picks", r.TotalPoints)
@@ -118,11 +122,11 @@ func TestBuildResult_ZeroPointsPlayer(t *testing.T) {
 	snap := makeSnap(
 		struct{ elem, pos int }{10, 1},
 	)
-	live := map[int]LiveStats{
+	live := map[int]livestats.ElementStats{
 		10: {Minutes: 0, TotalPoints: 0},
 	}
 
-	r := BuildResult(1, 1, 1, snap, live)
+	r := BuildResult(1, 1, 1, snap, live, nil)
 
 	if r.TotalPoints != 0 {
 		t.Errorf("TotalPoints = %d, want 0 (player scored 0)", r.TotalPoints)
@@ -131,9 +135,9 @@ func TestBuildResult_ZeroPointsPlayer(t *testing.T) {
 
 func TestBuildResult_FieldsPopulated(t *testing.T) {
 	snap := makeSnap(struct{ elem, pos int }{10, 1})
-	live := map[int]LiveStats{10: {Minutes: 45, TotalPoints: 2}}
+	live := map[int]livestats.ElementStats{10: {Minutes: 45, TotalPoints: 2}}
 
-	r := BuildResult(42, 99, 7, snap, live)
+	r := BuildResult(42, 99, 7, snap, live, clock.Fixed(time.Date(2025, 9, 20, 17, 0, 0, 0, time.UTC)))
 
 	if r.LeagueID != 42 {
 		t.Errorf("LeagueID = %d, want 42", r.LeagueID)
@@ -144,30 +148,100 @@ func TestBuildResult_FieldsPopulated(t *testing.T) {
 	if r.Gameweek != 7 {
 		t.Errorf("Gameweek = %d, want 7", r.Gameweek)
 	}
-	if r.GeneratedAtUTC == "" {
-		t.Error("GeneratedAtUTC should not be empty")
+	if r.GeneratedAtUTC != "2025-09-20T17:00:00Z" {
+		t.Errorf("GeneratedAtUTC = %q, want the clock's time", r.GeneratedAtUTC)
 	}
 }
 
 func TestBuildResult_Position11IsStarter(t *testing.T) {
 	// Position == 11 is still a starter and must be included.
 	snap := makeSnap(struct{ elem, pos int }{11, 11})
-	live := map[int]LiveStats{11: {Minutes: 90, TotalPoints: 3}}
+	live := map[int]livestats.ElementStats{11: {Minutes: 90, TotalPoints: 3}}
 
-	r := BuildResult(1, 1, 1, snap, live)
+	r := BuildResult(1, 1, 1, snap, live, nil)
 
 	if r.TotalPoints != 3 {
 		t.Errorf("TotalPoints = %d, want 3 (position 11 is a starter)", r.TotalPoints)
 	}
 }
 
+func TestBuildResult_AutoSubsEndToEnd(t *testing.T) {
+	// Raw entry event as fetched for an auto-sub GW: starter 10 didn't
+	// play and bench 20 came on for them.
+	body := `{
+		"entry_history": {"points": 9},
+		"picks": [
+			{"element": 10, "position": 1, "multiplier": 1, "is_captain": false, "is_vice_captain": false},
+			{"element": 11, "position": 2, "multiplier": 1, "is_captain": false, "is_vice_captain": false},
+			{"element": 20, "position": 12, "multiplier": 0, "is_captain": false, "is_vice_captain": false},
+			{"element": 21, "position": 13, "multiplier": 0, "is_captain": false, "is_vice_captain": false}
+		],
+		"subs": [{"element_in": 20, "element_out": 10, "event": 5}]
+	}`
+	var raw ledger.EntryEventRaw
+	if err := json.Unmarshal([]byte(body), &raw); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "5.json")
+	if err := ledger.WriteEntrySnapshot(path, ledger.BuildEntrySnapshot(1, 2, 5, raw, nil)); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var snap ledger.EntrySnapshot
+	if err := json.Unmarshal(b, &snap); err != nil {
+		t.Fatal(err)
+	}
+	live := map[int]livestats.ElementStats{
+		10: {Minutes: 0, TotalPoints: 0},
+		11: {Minutes: 90, TotalPoints: 6},
+		20: {Minutes: 90, TotalPoints: 3},
+		21: {Minutes: 90, TotalPoints: 8},
+	}
+
+	r := BuildResult(1, 2, 5, &snap, live, nil)
+
+	if r.TotalPoints != 9 {
+		t.Errorf("TotalPoints = %d, want 9 (6 + the subbed-in 3)", r.TotalPoints)
+	}
+	if len(r.Players) != 2 || r.Players[1].Element != 20 || !r.Players[1].SubbedIn || r.Players[1].Position != 12 {
+		t.Errorf("Players = %+v, want 11 and subbed-in 20", r.Players)
+	}
+	if len(r.AutoSubs) != 1 {
+		t.Errorf("AutoSubs = %+v, want the one applied sub", r.AutoSubs)
+	}
+}
+
+func TestBuildResult_AutoSubSkippedWhenStarterPlayed(t *testing.T) {
+	snap := makeSnap(
+		struct{ elem, pos int }{10, 1},
+		struct{ elem, pos int }{20, 12},
+	)
+	snap.Subs = []ledger.EntrySub{
+		{ElementIn: 20, ElementOut: 10, Event: 5},
+		{ElementIn: 10, ElementOut: 20, Event: 5}, // out isn't a starter
+	}
+	live := map[int]livestats.ElementStats{
+		10: {Minutes: 1, TotalPoints: 1},
+		20: {Minutes: 90, TotalPoints: 8},
+	}
+
+	r := BuildResult(1, 1, 5, snap, live, nil)
+
+	if r.TotalPoints != 1 || r.AutoSubs != nil {
+		t.Errorf("TotalPoints = %d AutoSubs = %+v, want the subs ignored", r.TotalPoints, r.AutoSubs)
+	}
+}
+
 func TestWriteResult(t *testing.T) {
 	dir := t.TempDir()
 	path := filepath.Join(dir, "sub", "result.json")
 
 	snap := makeSnap(struct{ elem, pos int }{10, 1})
-	live := map[int]LiveStats{10: {TotalPoints: 5}}
-	r := BuildResult(1, 1, 1, snap, live)
+	live := map[int]livestats.ElementStats{10: {TotalPoints: 5}}
+	r := BuildResult(1, 1, 1, snap, live, nil)
 
 	if err := WriteResult(path, r); err != nil {
 		t.Fatalf("WriteResult error: %v", err)
@@ -0,0 +1,127 @@
+// Package positions records each player's position type (1=GK, 2=DEF,
+// 3=MID, 4=FWD) as it stood for a gameweek. FPL occasionally reclassifies a
+// player mid-season; summaries of past GWs read the snapshot for their GW so
+// they keep the grouping the GW was scored under, while current-facing tools
+// keep using bootstrap.
+package positions
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/clock"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+)
+
+// Snapshot is element id -> position type for one GW.
+type Snapshot struct {
+	Gameweek       int         `json:"gameweek"`
+	GeneratedAtUTC string      `json:"generated_at_utc"`
+	ElementTypes   map[int]int `json:"element_types"`
+}
+
+// Change is a reclassification: from GW on, the player is To rather than
+// From.
+type Change struct {
+	GW   int `json:"gw"`
+	From int `json:"from"`
+	To   int `json:"to"`
+}
+
+func dir(derivedRoot string) string {
+	return filepath.Join(derivedRoot, "positions", "gw")
+}
+
+// Path is where gw's snapshot lives under derivedRoot.
+func Path(derivedRoot string, gw int) string {
+	return filepath.Join(dir(derivedRoot), fmt.Sprintf("%d.json", gw))
+}
+
+// Load reads gw's snapshot. A GW without one gives an error wrapping
+// fs.ErrNotExist.
+func Load(derivedRoot string, gw int) (map[int]int, error) {
+	raw, err := store.ReadDerived(Path(derivedRoot, gw))
+	if err != nil {
+		return nil, err
+	}
+	var s Snapshot
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return nil, fmt.Errorf("parse GW %d positions: %w", gw, err)
+	}
+	return s.ElementTypes, nil
+}
+
+// Ensure returns gw's snapshot, writing current (the bootstrap types as of
+// this derive run) as the snapshot, stamped by clk, when gw has none yet. An
+// existing snapshot is never rewritten: it is the record of what the GW was
+// scored under. GWs first derived after a reclassification can only get the
+// new types.
+func Ensure(derivedRoot string, gw int, current map[int]int, clk clock.Clock) (map[int]int, error) {
+	types, err := Load(derivedRoot, gw)
+	if err == nil {
+		return types, nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	s := Snapshot{Gameweek: gw, GeneratedAtUTC: clock.UTC(clock.Or(clk).Now()), ElementTypes: current}
+	if err := store.WriteDerivedJSON(Path(derivedRoot, gw), s); err != nil {
+		return nil, err
+	}
+	return current, nil
+}
+
+// snapshotGWs lists the GWs with a snapshot under derivedRoot, in order.
+func snapshotGWs(derivedRoot string) []int {
+	if derivedRoot == "" {
+		return nil
+	}
+	entries, err := os.ReadDir(dir(derivedRoot))
+	if err != nil {
+		return nil
+	}
+	gws := make([]int, 0, len(entries))
+	for _, e := range entries {
+		name := strings.TrimSuffix(strings.TrimSuffix(e.Name(), ".gz"), ".json")
+		if gw, err := strconv.Atoi(name); err == nil {
+			gws = append(gws, gw)
+		}
+	}
+	// A GW can be on disk both plain and gzipped.
+	sort.Ints(gws)
+	return slices.Compact(gws)
+}
+
+// Changes lists element's reclassifications across the saved snapshots,
+// oldest first. current is its bootstrap type now (0 if unknown); when it
+// differs from the latest snapshot the change takes effect the GW after it.
+func Changes(derivedRoot string, element int, current int) []Change {
+	out := make([]Change, 0)
+	prev, lastGW := 0, 0
+	for _, gw := range snapshotGWs(derivedRoot) {
+		types, err := Load(derivedRoot, gw)
+		if err != nil {
+			continue
+		}
+		t, ok := types[element]
+		if !ok {
+			continue
+		}
+		if prev != 0 && t != prev {
+			out = append(out, Change{GW: gw, From: prev, To: t})
+		}
+		prev, lastGW = t, gw
+	}
+	if prev != 0 && current != 0 && current != prev {
+		out = append(out, Change{GW: lastGW + 1, From: prev, To: current})
+	}
+	return out
+}
@@ -0,0 +1,42 @@
+package positions
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEnsureKeepsFirstRecord(t *testing.T) {
+	root := t.TempDir()
+	if _, err := Ensure(root, 10, map[int]int{1: 3}, nil); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Ensure(root, 10, map[int]int{1: 4}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got[1] != 3 {
+		t.Errorf("GW10 type = %d after a later derive, want the recorded 3", got[1])
+	}
+}
+
+func TestChanges(t *testing.T) {
+	root := t.TempDir()
+	for gw, typ := range map[int]int{10: 3, 11: 3, 20: 4} {
+		if _, err := Ensure(root, gw, map[int]int{1: typ, 2: 2}, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, want := Changes(root, 1, 4), []Change{{GW: 20, From: 3, To: 4}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("changes = %+v, want %+v", got, want)
+	}
+	if got := Changes(root, 2, 2); len(got) != 0 {
+		t.Errorf("unchanged player's changes = %+v, want none", got)
+	}
+	// Bootstrap moved on since the last recorded GW: effective the GW after.
+	if got, want := Changes(root, 2, 3), []Change{{GW: 21, From: 2, To: 3}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("pending change = %+v, want %+v", got, want)
+	}
+	if got := Changes("", 1, 4); len(got) != 0 {
+		t.Errorf("no derived root: changes = %+v, want none", got)
+	}
+}
@@ -0,0 +1,52 @@
+package projection
+
+// HeuristicModel is the points-per-fixture blend the server has always
+// used: a player's points per team fixture over the form window, scaled by
+// each fixture's strength.
+type HeuristicModel struct {
+	env Env
+}
+
+// NewHeuristic returns a HeuristicModel over env.
+func NewHeuristic(env Env) *HeuristicModel {
+	return &HeuristicModel{env: env}
+}
+
+func (m *HeuristicModel) Name() string { return ModelHeuristic }
+
+// Baseline is elementID's points per fixture over the form window.
+func (m *HeuristicModel) Baseline(elementID int) float64 {
+	return m.env.History[elementID].PointsPerFixture()
+}
+
+// ProjectPlayer returns baseline × the summed strength of the GW's fixtures.
+// Components split that into the unadjusted baseline and what the fixtures
+// add or take away. Variance scales the per-fixture variance of past points
+// by each fixture's strength squared.
+func (m *HeuristicModel) ProjectPlayer(elementID int, gw int) Projection {
+	p := Projection{Element: elementID, GW: gw, Model: ModelHeuristic, Components: map[string]float64{}}
+	player, ok := m.env.Players[elementID]
+	if !ok {
+		return p
+	}
+	h := m.env.History[elementID]
+	baseline := h.PointsPerFixture()
+	variance := 0.0
+	if h.Fixtures > 0 {
+		variance = h.PointsSq/float64(h.Fixtures) - baseline*baseline
+		if variance < 0 {
+			variance = 0
+		}
+	}
+	strength := 0.0
+	for _, f := range m.env.Fixtures[gw][player.TeamID] {
+		s := m.env.Strength(f.OpponentID, f.Venue, player.Position)
+		strength += s
+		p.Variance += variance * s * s
+		p.Fixtures++
+	}
+	p.ExpectedPoints = baseline * strength
+	p.Components["baseline"] = baseline * float64(p.Fixtures)
+	p.Components["fixture_adjustment"] = p.ExpectedPoints - p.Components["baseline"]
+	return p
+}
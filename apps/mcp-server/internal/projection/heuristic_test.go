@@ -0,0 +1,45 @@
+package projection
+
+import "testing"
+
+func TestHeuristicModel_BaselineTimesStrength(t *testing.T) {
+	env := Env{
+		Players: map[int]Player{1: {ID: 1, TeamID: 10, Position: 3}},
+		Fixtures: map[int]map[int][]Fixture{
+			3: {10: {{ID: 1, OpponentID: 11, Venue: "HOME"}, {ID: 2, OpponentID: 12, Venue: "AWAY"}}},
+		},
+		Strength: func(_ int, venue string, _ int) float64 {
+			if venue == "AWAY" {
+				return 1.5
+			}
+			return 1
+		},
+		// 4, 8 over two fixtures: mean 6, variance 4.
+		History: map[int]History{1: {Fixtures: 2, Points: 12, PointsSq: 80}},
+	}
+	m := NewHeuristic(env)
+	p := m.ProjectPlayer(1, 3)
+	if !approx(p.ExpectedPoints, 15) || p.Fixtures != 2 {
+		t.Fatalf("projection = %+v, want 6 × (1 + 1.5) = 15", p)
+	}
+	if !approx(p.Components["baseline"], 12) || !approx(p.Components["fixture_adjustment"], 3) {
+		t.Errorf("components = %v", p.Components)
+	}
+	if !approx(p.Variance, 4*1+4*2.25) {
+		t.Errorf("variance = %v, want 13", p.Variance)
+	}
+	if m.ProjectPlayer(2, 3).ExpectedPoints != 0 || m.ProjectPlayer(1, 4).Fixtures != 0 {
+		t.Errorf("unknown player or GW should project zero")
+	}
+}
+
+func TestParseModel(t *testing.T) {
+	for in, want := range map[string]string{"": ModelHeuristic, " Poisson ": ModelPoisson, "heuristic": ModelHeuristic} {
+		if got, err := ParseModel(in); err != nil || got != want {
+			t.Errorf("ParseModel(%q) = %q, %v", in, got, err)
+		}
+	}
+	if _, err := ParseModel("elo"); err == nil {
+		t.Errorf("ParseModel(elo) succeeded")
+	}
+}
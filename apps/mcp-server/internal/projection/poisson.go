@@ -0,0 +1,163 @@
+package projection
+
+import (
+	"math"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/scoring"
+)
+
+// PoissonModel projects each scoring event separately. Goals, assists,
+// goals conceded and saves are Poisson counts from the player's per-90
+// rates, scaled by expected minutes and fixture strength, and converted to
+// points with the env's scoring rules for the position. Attacking rates scale with
+// strength; conceded rates divide by it, so an easy fixture raises both
+// goals and clean sheet chances.
+type PoissonModel struct {
+	env Env
+}
+
+// NewPoisson returns a PoissonModel over env.
+func NewPoisson(env Env) *PoissonModel {
+	return &PoissonModel{env: env}
+}
+
+func (m *PoissonModel) Name() string { return ModelPoisson }
+
+// ProjectPlayer sums fixtureProjection over the team's fixtures in gw.
+func (m *PoissonModel) ProjectPlayer(elementID int, gw int) Projection {
+	p := Projection{Element: elementID, GW: gw, Model: ModelPoisson, Components: map[string]float64{}}
+	player, ok := m.env.Players[elementID]
+	if !ok || player.Position < 1 || player.Position > 4 {
+		return p
+	}
+	rates := ratesFrom(m.env.History[elementID])
+	for _, f := range m.env.Fixtures[gw][player.TeamID] {
+		strength := m.env.Strength(f.OpponentID, f.Venue, player.Position)
+		comp, variance := fixtureProjection(m.env.rules(), rates, player.Position, strength)
+		for k, v := range comp {
+			p.Components[k] += v
+			p.ExpectedPoints += v
+		}
+		p.Variance += variance
+		p.Fixtures++
+	}
+	return p
+}
+
+// playerRates are a History's per-fixture and per-90 rates.
+type playerRates struct {
+	// PPlay and P60 are the chances of playing at all and for 60+ minutes;
+	// Minutes is the expected minutes per fixture.
+	PPlay   float64
+	P60     float64
+	Minutes float64
+	XG90    float64
+	XA90    float64
+	XGC90   float64
+	Saves90 float64
+	// BonusPerApp is bonus per fixture played; DefConRate is the share of
+	// 60+ minute fixtures that reached the defensive contribution threshold.
+	BonusPerApp float64
+	DefConRate  float64
+}
+
+func ratesFrom(h History) playerRates {
+	var r playerRates
+	if h.Fixtures == 0 {
+		return r
+	}
+	n := float64(h.Fixtures)
+	r.PPlay = math.Min(1, float64(h.Apps)/n)
+	r.P60 = math.Min(r.PPlay, float64(h.Apps60)/n)
+	r.Minutes = math.Min(90, float64(h.Minutes)/n)
+	if h.Minutes > 0 {
+		per90 := 90 / float64(h.Minutes)
+		r.XG90 = h.XG * per90
+		r.XA90 = h.XA * per90
+		r.XGC90 = h.XGC * per90
+		r.Saves90 = float64(h.Saves) * per90
+	}
+	if h.Apps > 0 {
+		r.BonusPerApp = float64(h.Bonus) / float64(h.Apps)
+	}
+	if h.Apps60 > 0 {
+		r.DefConRate = float64(h.DefConHits) / float64(h.Apps60)
+	}
+	return r
+}
+
+// fixtureProjection is one fixture's expected points by component under
+// rules, and their total variance.
+func fixtureProjection(rules scoring.ScoringRules, r playerRates, pos int, strength float64) (map[string]float64, float64) {
+	if strength <= 0 {
+		strength = 1
+	}
+	share := r.Minutes / 90
+	comp := make(map[string]float64, 8)
+	variance := 0.0
+
+	// Appearance: the under-60 points for a short run, the 60+ points for
+	// 60 or more.
+	under, full := float64(rules.AppearanceUnder60), float64(rules.Appearance60)
+	comp["appearance"] = under*r.PPlay + (full-under)*r.P60
+	variance += under*under*(r.PPlay-r.P60) + full*full*r.P60 - comp["appearance"]*comp["appearance"]
+
+	goals := r.XG90 * share * strength
+	perGoal := float64(rules.Goal.At(pos))
+	comp["goals"] = perGoal * goals
+	variance += perGoal * perGoal * goals
+
+	assists := r.XA90 * share * strength
+	perAssist := float64(rules.Assist)
+	comp["assists"] = perAssist * assists
+	variance += perAssist * perAssist * assists
+
+	conceded := r.XGC90 * share / strength
+	if cs := float64(rules.CleanSheet.At(pos)); cs != 0 {
+		// A clean sheet needs 60+ minutes and nothing conceded while on.
+		pCS := r.P60 * math.Exp(-conceded)
+		comp["clean_sheet"] = cs * pCS
+		variance += cs * cs * pCS * (1 - pCS)
+	}
+	if penalty := float64(rules.GoalsConceded.At(pos)); penalty != 0 {
+		mean, v := floorDivMoments(conceded, rules.ConcededPerPenalty)
+		comp["goals_conceded"] = penalty * mean
+		variance += penalty * penalty * v
+	}
+	if pos == 1 {
+		mean, v := floorDivMoments(r.Saves90*share/strength, rules.SavesPerPoint)
+		comp["saves"] = mean
+		variance += v
+	}
+
+	comp["bonus"] = r.BonusPerApp * r.PPlay
+	variance += comp["bonus"]
+
+	if rules.DefConThreshold.At(pos) > 0 {
+		pDC := r.P60 * r.DefConRate
+		points := float64(rules.DefensiveContribution)
+		comp["defensive_contribution"] = points * pDC
+		variance += points * points * pDC * (1 - pDC)
+	}
+	return comp, variance
+}
+
+// floorDivMoments returns the mean and variance of floor(X/k) for X ~
+// Poisson(lambda), the shape of "1 point per k saves" style rules.
+func floorDivMoments(lambda float64, k int) (float64, float64) {
+	if lambda <= 0 || k <= 0 {
+		return 0, 0
+	}
+	limit := int(lambda+10*math.Sqrt(lambda)) + 20
+	pmf := math.Exp(-lambda)
+	mean, sq := 0.0, 0.0
+	for x := 0; x <= limit; x++ {
+		if x > 0 {
+			pmf *= lambda / float64(x)
+		}
+		f := float64(x / k)
+		mean += f * pmf
+		sq += f * f * pmf
+	}
+	return mean, sq - mean*mean
+}
@@ -0,0 +1,221 @@
+package projection
+
+import (
+	"math"
+	"testing"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/scoring"
+)
+
+func approx(a, b float64) bool { return math.Abs(a-b) < 1e-9 }
+
+// everyMinute is a player who starts and finishes every fixture.
+var everyMinute = playerRates{PPlay: 1, P60: 1, Minutes: 90}
+
+func TestFixtureProjection_ZeroRatesScoreAppearanceAndCleanSheet(t *testing.T) {
+	// Nothing conceded, scored or saved: a full-match player banks the
+	// appearance and their position's clean sheet, and nothing else.
+	for pos, wantCS := range map[int]float64{1: 4, 2: 4, 3: 1, 4: 0} {
+		comp, _ := fixtureProjection(scoring.Default(), everyMinute, pos, 1)
+		if !approx(comp["appearance"], 2) || !approx(comp["clean_sheet"], wantCS) {
+			t.Errorf("pos %d: appearance %v clean sheet %v, want 2 and %v", pos, comp["appearance"], comp["clean_sheet"], wantCS)
+		}
+		if _, ok := comp["clean_sheet"]; pos == 4 && ok {
+			t.Errorf("FWD has a clean_sheet component")
+		}
+		if comp["goals_conceded"] != 0 || comp["goals"] != 0 || comp["saves"] != 0 {
+			t.Errorf("pos %d: comp = %v", pos, comp)
+		}
+	}
+}
+
+func TestFixtureProjection_CleanSheetProbability(t *testing.T) {
+	r := everyMinute
+	r.XGC90 = 1.2
+	def, _ := fixtureProjection(scoring.Default(), r, 2, 1)
+	if want := 4 * math.Exp(-1.2); !approx(def["clean_sheet"], want) {
+		t.Errorf("DEF clean sheet = %v, want 4·e^-1.2 = %v", def["clean_sheet"], want)
+	}
+	mid, _ := fixtureProjection(scoring.Default(), r, 3, 1)
+	if want := math.Exp(-1.2); !approx(mid["clean_sheet"], want) {
+		t.Errorf("MID clean sheet = %v, want e^-1.2", mid["clean_sheet"])
+	}
+	if _, ok := mid["goals_conceded"]; ok {
+		t.Errorf("MID is penalised for goals conceded: %v", mid)
+	}
+	// An easy fixture (strength 2) halves the conceded rate.
+	easy, _ := fixtureProjection(scoring.Default(), r, 2, 2)
+	if want := 4 * math.Exp(-0.6); !approx(easy["clean_sheet"], want) {
+		t.Errorf("DEF clean sheet in easy fixture = %v, want %v", easy["clean_sheet"], want)
+	}
+}
+
+func TestFixtureProjection_CleanSheetNeedsSixtyMinutes(t *testing.T) {
+	// A player who always comes off the bench for 30 minutes earns the
+	// 1-point appearance but never a clean sheet, even at zero conceded.
+	sub := playerRates{PPlay: 1, P60: 0, Minutes: 30}
+	comp, _ := fixtureProjection(scoring.Default(), sub, 2, 1)
+	if !approx(comp["appearance"], 1) || comp["clean_sheet"] != 0 {
+		t.Errorf("sub DEF = %v, want appearance 1 and no clean sheet", comp)
+	}
+	// Half-time substitutions: the CS chance scales with P60 alone.
+	half := playerRates{PPlay: 1, P60: 0.5, Minutes: 75}
+	comp, _ = fixtureProjection(scoring.Default(), half, 1, 1)
+	if !approx(comp["clean_sheet"], 2) || !approx(comp["appearance"], 1.5) {
+		t.Errorf("half-time GK = %v, want clean sheet 2 and appearance 1.5", comp)
+	}
+}
+
+func TestFixtureProjection_GoalsConcededPenalty(t *testing.T) {
+	r := everyMinute
+	r.XGC90 = 2
+	comp, _ := fixtureProjection(scoring.Default(), r, 1, 1)
+	want, _ := floorDivMoments(2, 2)
+	if !approx(comp["goals_conceded"], -want) || comp["goals_conceded"] >= 0 {
+		t.Errorf("GK goals conceded = %v, want %v", comp["goals_conceded"], -want)
+	}
+	// P(X>=2) + P(X>=4) + ... for Poisson(2).
+	manual := 0.0
+	pmf := math.Exp(-2)
+	for x := 0; x < 60; x++ {
+		if x > 0 {
+			pmf *= 2 / float64(x)
+		}
+		manual += float64(x/2) * pmf
+	}
+	if !approx(want, manual) {
+		t.Errorf("floorDivMoments mean = %v, want %v", want, manual)
+	}
+}
+
+func TestFixtureProjection_GoalPointsByPosition(t *testing.T) {
+	r := everyMinute
+	r.XG90, r.XA90 = 0.5, 0.2
+	for pos, perGoal := range map[int]float64{1: 10, 2: 6, 3: 5, 4: 4} {
+		comp, _ := fixtureProjection(scoring.Default(), r, pos, 1)
+		if !approx(comp["goals"], 0.5*perGoal) || !approx(comp["assists"], 0.6) {
+			t.Errorf("pos %d: goals %v assists %v", pos, comp["goals"], comp["assists"])
+		}
+	}
+	// Half the expected minutes halves the rates; strength scales them.
+	r.Minutes = 45
+	comp, _ := fixtureProjection(scoring.Default(), r, 4, 1.5)
+	if !approx(comp["goals"], 4*0.5*0.5*1.5) {
+		t.Errorf("FWD goals at 45 mins, strength 1.5 = %v", comp["goals"])
+	}
+}
+
+func TestFixtureProjection_SavesOnlyForGK(t *testing.T) {
+	r := everyMinute
+	r.Saves90 = 4
+	gk, _ := fixtureProjection(scoring.Default(), r, 1, 1)
+	want, _ := floorDivMoments(4, 3)
+	if !approx(gk["saves"], want) || want <= 0 {
+		t.Errorf("GK saves = %v, want %v", gk["saves"], want)
+	}
+	def, _ := fixtureProjection(scoring.Default(), r, 2, 1)
+	if _, ok := def["saves"]; ok {
+		t.Errorf("DEF has a saves component: %v", def)
+	}
+}
+
+func TestPoissonModel_SumsFixturesAndComponents(t *testing.T) {
+	env := Env{
+		Players: map[int]Player{7: {ID: 7, TeamID: 1, Position: 2}},
+		Fixtures: map[int]map[int][]Fixture{
+			5: {1: {{ID: 1, OpponentID: 2, Venue: "HOME"}, {ID: 2, OpponentID: 3, Venue: "AWAY"}}},
+			6: {2: {{ID: 3, OpponentID: 3, Venue: "HOME"}}},
+		},
+		Strength: func(int, string, int) float64 { return 1 },
+		History:  map[int]History{7: {Fixtures: 4, Points: 20, Minutes: 360, Apps: 4, Apps60: 4, XGC: 4, XG: 0.4, Bonus: 2, DefConHits: 2}},
+	}
+	m, err := New("Poisson", env)
+	if err != nil || m.Name() != ModelPoisson {
+		t.Fatalf("New = %v, %v", m, err)
+	}
+	p := m.ProjectPlayer(7, 5)
+	single, _ := fixtureProjection(scoring.Default(), ratesFrom(env.History[7]), 2, 1)
+	if p.Fixtures != 2 || !approx(p.Components["clean_sheet"], 2*single["clean_sheet"]) {
+		t.Errorf("double GW = %+v, want two fixtures' worth", p)
+	}
+	sum := 0.0
+	for _, v := range p.Components {
+		sum += v
+	}
+	if !approx(sum, p.ExpectedPoints) || p.Variance <= 0 {
+		t.Errorf("components sum %v vs expected %v, variance %v", sum, p.ExpectedPoints, p.Variance)
+	}
+	if blank := m.ProjectPlayer(7, 6); blank.Fixtures != 0 || blank.ExpectedPoints != 0 {
+		t.Errorf("blank GW = %+v, want zero", blank)
+	}
+}
+
+// TestFixtureProjection_DefaultRulesGolden pins the default-rules projection
+// to the values the model gave before scoring rules were configurable.
+func TestFixtureProjection_DefaultRulesGolden(t *testing.T) {
+	rates := []playerRates{
+		{PPlay: 1, P60: 0.9, Minutes: 84, XG90: 0.45, XA90: 0.21, XGC90: 1.1, Saves90: 2.8, BonusPerApp: 0.7, DefConRate: 0.4},
+		{PPlay: 0.6, P60: 0.3, Minutes: 41, XG90: 0.12, XA90: 0.05, XGC90: 1.6, BonusPerApp: 0.2, DefConRate: 0.1},
+	}
+	golden := []struct {
+		rates    int
+		pos      int
+		strength float64
+		mean     float64
+		variance float64
+	}{
+		{0, 1, 0.8, 7.7707732796329569, 39.568980082602366},
+		{0, 1, 1.25, 10.327648181578608, 59.781608057191647},
+		{0, 2, 0.8, 6.3931389495371995, 18.547691529839586},
+		{0, 2, 1.25, 8.5794087635503473, 26.829123929643099},
+		{0, 3, 0.8, 5.719800847149652, 11.710000064590689},
+		{0, 3, 1.25, 7.0758603232587705, 17.280754927728232},
+		{0, 4, 0.8, 5.1344000000000003, 8.498800000000001},
+		{0, 4, 1.25, 6.1550000000000002, 12.316599999999999},
+		{1, 1, 0.8, 1.748520589005873, 7.2595012258372895},
+		{1, 1, 1.25, 2.3391000312493362, 10.241507291299826},
+		{1, 2, 0.8, 1.6335872556725399, 4.5769678925039567},
+		{1, 2, 1.25, 2.1257666979160028, 5.9845739579664938},
+		{1, 3, 0.8, 1.4739565003160022, 2.2898065519030735},
+		{1, 3, 1.25, 1.6745310835412988, 3.03039233449159},
+		{1, 4, 0.8, 1.3096000000000001, 1.7901333333333334},
+		{1, 4, 1.25, 1.4387500000000002, 2.2759833333333335},
+	}
+	order := []string{"appearance", "goals", "assists", "clean_sheet", "goals_conceded", "saves", "bonus", "defensive_contribution"}
+	for _, g := range golden {
+		comp, variance := fixtureProjection(scoring.Default(), rates[g.rates], g.pos, g.strength)
+		mean := 0.0
+		for _, k := range order {
+			mean += comp[k]
+		}
+		if mean != g.mean || variance != g.variance {
+			t.Errorf("rates %d pos %d strength %v: mean %.17g variance %.17g, want %.17g and %.17g",
+				g.rates, g.pos, g.strength, mean, variance, g.mean, g.variance)
+		}
+	}
+}
+
+func TestFixtureProjection_CustomRules(t *testing.T) {
+	rules := scoring.Default()
+	rules.Goal.MID = 6
+	rules.CleanSheet.MID = 0
+	rules.DefConThreshold.MID = 0
+	rules.GoalsConceded.DEF = -2
+	r := everyMinute
+	r.XG90, r.XGC90, r.DefConRate = 0.5, 2, 0.5
+
+	mid, _ := fixtureProjection(rules, r, 3, 1)
+	if !approx(mid["goals"], 3) {
+		t.Errorf("MID goals at 6 per goal = %v, want 3", mid["goals"])
+	}
+	for _, k := range []string{"clean_sheet", "defensive_contribution"} {
+		if _, ok := mid[k]; ok {
+			t.Errorf("MID has a %s component under rules that don't award it: %v", k, mid)
+		}
+	}
+	def, _ := fixtureProjection(rules, r, 2, 1)
+	want, _ := floorDivMoments(2, 2)
+	if !approx(def["goals_conceded"], -2*want) {
+		t.Errorf("DEF goals conceded = %v, want %v", def["goals_conceded"], -2*want)
+	}
+}
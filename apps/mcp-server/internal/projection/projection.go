@@ -0,0 +1,237 @@
+// Package projection estimates a player's points for a gameweek. Models
+// share one interface, so a tool can swap the estimate behind its `model`
+// argument without changing how it uses the result.
+package projection
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/livestats"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/scoring"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+)
+
+// Model names accepted by ParseModel and New.
+const (
+	ModelHeuristic = "heuristic"
+	ModelPoisson   = "poisson"
+)
+
+// Projection is a player's expected points for one gameweek, summed over
+// the team's fixtures in it. Components attribute ExpectedPoints by source
+// and add up to it; Variance treats the components as independent.
+type Projection struct {
+	Element        int                `json:"element"`
+	GW             int                `json:"gw"`
+	Model          string             `json:"model"`
+	Fixtures       int                `json:"fixtures"`
+	ExpectedPoints float64            `json:"expected_points"`
+	Variance       float64            `json:"variance"`
+	Components     map[string]float64 `json:"components"`
+}
+
+// Model projects players for gameweeks. A player the model knows nothing
+// about, or whose team blanks, projects zero points.
+type Model interface {
+	Name() string
+	ProjectPlayer(elementID int, gw int) Projection
+}
+
+// Player is the bootstrap data a model needs about a player.
+type Player struct {
+	ID       int
+	TeamID   int
+	Position int
+}
+
+// Fixture is one of a team's fixtures. Venue is "HOME" or "AWAY".
+type Fixture struct {
+	ID         int
+	OpponentID int
+	Venue      string
+}
+
+// Strength scores a fixture for a position against the average fixture for
+// that position: 1.0 is average, higher is easier.
+type Strength func(opponentID int, venue string, pos int) float64
+
+// Env is what every model projects from. Fixtures is keyed by GW, then team
+// id; a GW missing from it has no known schedule and projects zero. Rules
+// converts projected stats to points; the zero value means scoring.Default.
+type Env struct {
+	Players  map[int]Player
+	Fixtures map[int]map[int][]Fixture
+	Strength Strength
+	History  map[int]History
+	Rules    scoring.ScoringRules
+}
+
+func (e Env) rules() scoring.ScoringRules { return orDefault(e.Rules) }
+
+// orDefault returns rules, or scoring.Default for the zero value.
+func orDefault(rules scoring.ScoringRules) scoring.ScoringRules {
+	if rules == (scoring.ScoringRules{}) {
+		return scoring.Default()
+	}
+	return rules
+}
+
+// ParseModel normalises a model argument; empty means ModelHeuristic.
+func ParseModel(s string) (string, error) {
+	m := strings.ToLower(strings.TrimSpace(s))
+	switch m {
+	case "":
+		return ModelHeuristic, nil
+	case ModelHeuristic, ModelPoisson:
+		return m, nil
+	}
+	return "", fmt.Errorf("model must be heuristic or poisson, got %q", s)
+}
+
+// New returns the named model over env.
+func New(name string, env Env) (Model, error) {
+	name, err := ParseModel(name)
+	if err != nil {
+		return nil, err
+	}
+	if name == ModelPoisson {
+		return NewPoisson(env), nil
+	}
+	return NewHeuristic(env), nil
+}
+
+// History is a player's record over a form window. Fixtures counts their
+// team's fixtures in the GWs the player appears in live data, so a past
+// double gameweek counts twice rather than inflating per-fixture rates.
+type History struct {
+	Fixtures int
+	Points   int
+	// PointsSq sums each fixture's squared points, splitting a double
+	// gameweek's total evenly, for the per-fixture variance.
+	PointsSq float64
+	Minutes  int
+	// Apps and Apps60 count fixtures played at all and for 60+ minutes.
+	Apps   int
+	Apps60 int
+	XG     float64
+	XA     float64
+	XGC    float64
+	Saves  int
+	Bonus  int
+	// DefConHits counts 60+ minute fixtures that reached the defensive
+	// contribution threshold for the player's position.
+	DefConHits int
+}
+
+// PointsPerFixture is the heuristic baseline: points per team fixture.
+func (h History) PointsPerFixture() float64 {
+	if h.Fixtures == 0 {
+		return 0
+	}
+	return float64(h.Points) / float64(h.Fixtures)
+}
+
+// LoadHistory builds each player's History over the window GWs ending at
+// asOfGW from gw/N/live.json, with points re-scored under rules (zero
+// means scoring.Default). GWs without a live file, and GWs where a player's
+// team had no fixture, are skipped.
+func LoadHistory(st *store.JSONStore, players map[int]Player, asOfGW int, window int, rules scoring.ScoringRules) map[int]History {
+	rules = orDefault(rules)
+	start := asOfGW - window + 1
+	if start < 1 {
+		start = 1
+	}
+	out := make(map[int]History)
+	for gw := start; gw <= asOfGW; gw++ {
+		live, err := livestats.LoadGW(st, gw)
+		if err != nil {
+			continue
+		}
+		perTeam := make(map[int]int)
+		for _, f := range live.Fixtures {
+			perTeam[f.TeamH]++
+			perTeam[f.TeamA]++
+		}
+		for id, stats := range live.Elements {
+			p := players[id]
+			n := perTeam[p.TeamID]
+			if n == 0 {
+				continue
+			}
+			h := out[id]
+			h.addGW(stats, n, p.Position, rules)
+			out[id] = h
+		}
+	}
+	return out
+}
+
+// addGW adds one GW's stats. Points are FPL's total plus the difference
+// rules make to the GW's stat line, so bonus is kept as awarded.
+func (h *History) addGW(stats livestats.ElementStats, fixtures int, pos int, rules scoring.ScoringRules) {
+	points := stats.TotalPoints + rules.Adjustment(pos, statLine(stats))
+	h.Fixtures += fixtures
+	h.Points += points
+	per := float64(points) / float64(fixtures)
+	h.PointsSq += per * per * float64(fixtures)
+	h.Minutes += stats.Minutes
+	h.XG += stats.XG
+	h.XA += stats.XA
+	h.XGC += stats.XGC
+	h.Saves += stats.Saves
+	h.Bonus += stats.Bonus
+	threshold := rules.DefConThreshold.At(pos)
+	for _, mins := range fixtureMinutes(stats, fixtures) {
+		if mins == 0 {
+			continue
+		}
+		h.Apps++
+		if mins >= 60 {
+			h.Apps60++
+			if threshold > 0 && stats.DefensiveContribution >= threshold*fixtures {
+				h.DefConHits++
+			}
+		}
+	}
+}
+
+// statLine is the scoring view of a GW's live stats. In a double GW it sums
+// both fixtures, which Adjustment treats as one.
+func statLine(stats livestats.ElementStats) scoring.StatLine {
+	return scoring.StatLine{
+		Minutes:               stats.Minutes,
+		Goals:                 stats.GoalsScored,
+		Assists:               stats.Assists,
+		CleanSheets:           stats.CleanSheets,
+		GoalsConceded:         stats.GoalsConceded,
+		OwnGoals:              stats.OwnGoals,
+		PenaltiesSaved:        stats.PenaltiesSaved,
+		PenaltiesMissed:       stats.PenaltiesMissed,
+		YellowCards:           stats.YellowCards,
+		RedCards:              stats.RedCards,
+		Saves:                 stats.Saves,
+		DefensiveContribution: stats.DefensiveContribution,
+	}
+}
+
+// fixtureMinutes splits a GW's minutes across its fixtures, from the
+// per-fixture explain data when there is one entry per fixture and evenly
+// otherwise.
+func fixtureMinutes(stats livestats.ElementStats, fixtures int) []int {
+	if fixtures == 1 {
+		return []int{stats.Minutes}
+	}
+	if len(stats.ByFixture) == fixtures {
+		out := make([]int, 0, fixtures)
+		for _, f := range stats.ByFixture {
+			out = append(out, f.Minutes)
+		}
+		return out
+	}
+	out := make([]int, fixtures)
+	for i := range out {
+		out[i] = stats.Minutes / fixtures
+	}
+	return out
+}
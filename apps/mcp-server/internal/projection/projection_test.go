@@ -0,0 +1,81 @@
+package projection
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/scoring"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+)
+
+func writeLive(t *testing.T, dir string, gw int, v any) {
+	t.Helper()
+	path := filepath.Join(dir, "gw", strconv.Itoa(gw), "live.json")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, b, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadHistory_DoubleGameweek(t *testing.T) {
+	dir := t.TempDir()
+	writeLive(t, dir, 1, map[string]any{
+		"elements": map[string]any{"1": map[string]any{"stats": map[string]any{
+			"minutes": 90, "total_points": 6, "expected_goals": "0.40", "defensive_contribution": 11}}},
+		"fixtures": []any{map[string]any{"id": 1, "team_h": 10, "team_a": 11}},
+	})
+	// GW2 is a double with 10 points and 150 minutes across both fixtures.
+	writeLive(t, dir, 2, map[string]any{
+		"elements": map[string]any{"1": map[string]any{"stats": map[string]any{
+			"minutes": 150, "total_points": 10, "expected_goals": "0.60", "defensive_contribution": 20}}},
+		"fixtures": []any{
+			map[string]any{"id": 2, "team_h": 10, "team_a": 12},
+			map[string]any{"id": 3, "team_h": 11, "team_a": 10},
+		},
+	})
+	players := map[int]Player{1: {ID: 1, TeamID: 10, Position: 2}}
+	h := LoadHistory(store.NewJSONStore(dir), players, 2, 5, scoring.Default())[1]
+	if h.Fixtures != 3 || h.Points != 16 || !approx(h.PointsPerFixture(), 16.0/3) {
+		t.Errorf("history = %+v, want 16 points over 3 fixtures", h)
+	}
+	// GW2's 150 minutes split 75/75: both count as 60+.
+	if h.Apps != 3 || h.Apps60 != 3 || h.Minutes != 240 || !approx(h.XG, 1.0) {
+		t.Errorf("history = %+v", h)
+	}
+	// 11 reaches the DEF threshold of 10; 20 over two fixtures reaches it in both.
+	if h.DefConHits != 3 {
+		t.Errorf("defcon hits = %d, want 3", h.DefConHits)
+	}
+	if !approx(h.PointsSq, 36+25+25) {
+		t.Errorf("points squared = %v, want 86", h.PointsSq)
+	}
+}
+
+func TestLoadHistory_RescoresUnderRules(t *testing.T) {
+	dir := t.TempDir()
+	writeLive(t, dir, 1, map[string]any{
+		"elements": map[string]any{"1": map[string]any{"stats": map[string]any{
+			"minutes": 90, "total_points": 8, "goals_scored": 1, "yellow_cards": 1, "bonus": 2}}},
+		"fixtures": []any{map[string]any{"id": 1, "team_h": 10, "team_a": 11}},
+	})
+	players := map[int]Player{1: {ID: 1, TeamID: 10, Position: 3}}
+	st := store.NewJSONStore(dir)
+	if h := LoadHistory(st, players, 1, 5, scoring.ScoringRules{})[1]; h.Points != 8 {
+		t.Errorf("zero rules: points = %d, want FPL's 8", h.Points)
+	}
+	rules := scoring.Default()
+	rules.Goal.MID = 6
+	rules.YellowCard = -3
+	if h := LoadHistory(st, players, 1, 5, rules)[1]; h.Points != 7 || h.PointsSq != 49 {
+		t.Errorf("custom rules: %+v, want 7 points (+1 goal, -2 card)", h)
+	}
+}
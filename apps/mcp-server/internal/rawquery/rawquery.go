@@ -0,0 +1,186 @@
+// Package rawquery projects named fields out of a raw API document, so a
+// caller can read one field no summary carries without pulling the whole
+// file.
+//
+// A path is dot-separated keys. A key suffixed with "[]" steps into every
+// element of the array it names, and "*" steps into every value of an object
+// (keeping its keys) or every element of an array:
+//
+//	elements[].web_name
+//	teams[].strength_overall_home
+//	elements.*.stats.total_points
+//
+// The result keeps the document's shape, cut down to the paths: projecting
+// elements[].id and elements[].web_name gives {"elements": [{"id": ...,
+// "web_name": ...}, ...]}.
+package rawquery
+
+import (
+	"fmt"
+	"strings"
+)
+
+// segment is one step of a path.
+type segment struct {
+	key  string // "" for a bare "*"
+	each bool   // key[]: every element of the array under key
+	all  bool   // *: every value or element of the current node
+}
+
+// Path is a parsed field path.
+type Path struct {
+	raw      string
+	segments []segment
+}
+
+func (p Path) String() string {
+	return p.raw
+}
+
+// Parse parses a path. It rejects empty segments, unknown bracket forms and
+// a path with no named key: "*" or "*.*" would return the whole document.
+func Parse(path string) (Path, error) {
+	p := Path{raw: path}
+	if strings.TrimSpace(path) == "" {
+		return Path{}, fmt.Errorf("empty path")
+	}
+	named := false
+	for _, part := range strings.Split(path, ".") {
+		switch {
+		case part == "":
+			return Path{}, fmt.Errorf("path %q has an empty segment", path)
+		case part == "*":
+			p.segments = append(p.segments, segment{all: true})
+		case strings.HasSuffix(part, "[]"):
+			key := strings.TrimSuffix(part, "[]")
+			if key == "" || strings.ContainsAny(key, "[]*") {
+				return Path{}, fmt.Errorf("path %q: bad segment %q", path, part)
+			}
+			p.segments = append(p.segments, segment{key: key, each: true})
+			named = true
+		case strings.ContainsAny(part, "[]*"):
+			return Path{}, fmt.Errorf("path %q: bad segment %q (use key[] for arrays and * for every value)", path, part)
+		default:
+			p.segments = append(p.segments, segment{key: part})
+			named = true
+		}
+	}
+	if !named {
+		return Path{}, fmt.Errorf("path %q is wildcards only; name at least one field", path)
+	}
+	return p, nil
+}
+
+// Project returns doc cut down to paths, with the paths that matched nothing
+// anywhere in doc. doc is a decoded JSON value (map[string]any, []any and
+// scalars); the result may share values with it.
+func Project(doc any, paths []Path) (out any, unmatched []string) {
+	unmatched = make([]string, 0)
+	for _, p := range paths {
+		v, ok := project(doc, p.segments)
+		if !ok {
+			unmatched = append(unmatched, p.raw)
+			continue
+		}
+		out = merge(out, v)
+	}
+	if out == nil {
+		out = map[string]any{}
+	}
+	return out, unmatched
+}
+
+// project follows segs from v. It reports false when nothing along the path
+// exists; an array or object some of whose members lack the rest of the
+// path keeps only the members that have it (array members become empty
+// objects so indexes line up).
+func project(v any, segs []segment) (any, bool) {
+	if len(segs) == 0 {
+		return v, true
+	}
+	seg, rest := segs[0], segs[1:]
+	if seg.all {
+		return projectEach(v, rest)
+	}
+	obj, ok := v.(map[string]any)
+	if !ok {
+		return nil, false
+	}
+	child, ok := obj[seg.key]
+	if !ok {
+		return nil, false
+	}
+	var sub any
+	if seg.each {
+		if _, isArray := child.([]any); !isArray {
+			return nil, false
+		}
+		sub, ok = projectEach(child, rest)
+	} else {
+		sub, ok = project(child, rest)
+	}
+	if !ok {
+		return nil, false
+	}
+	return map[string]any{seg.key: sub}, true
+}
+
+// projectEach applies segs to every element of an array or value of an
+// object.
+func projectEach(v any, segs []segment) (any, bool) {
+	switch node := v.(type) {
+	case []any:
+		out := make([]any, len(node))
+		matched := false
+		for i, el := range node {
+			p, ok := project(el, segs)
+			if !ok {
+				out[i] = map[string]any{}
+				continue
+			}
+			out[i] = p
+			matched = true
+		}
+		return out, matched || len(node) == 0
+	case map[string]any:
+		out := make(map[string]any, len(node))
+		for k, el := range node {
+			if p, ok := project(el, segs); ok {
+				out[k] = p
+			}
+		}
+		return out, len(out) > 0 || len(node) == 0
+	default:
+		return nil, false
+	}
+}
+
+// merge combines two projections of the same document: objects key by key,
+// arrays element by element. Anything else is the same value from both.
+func merge(a, b any) any {
+	if a == nil {
+		return b
+	}
+	switch x := a.(type) {
+	case map[string]any:
+		y, ok := b.(map[string]any)
+		if !ok {
+			return a
+		}
+		for k, v := range y {
+			x[k] = merge(x[k], v)
+		}
+		return x
+	case []any:
+		y, ok := b.([]any)
+		if !ok || len(x) != len(y) {
+			return a
+		}
+		for i := range x {
+			x[i] = merge(x[i], y[i])
+		}
+		return x
+	default:
+		return a
+	}
+}
@@ -0,0 +1,103 @@
+package rawquery
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+const bootstrapDoc = `{
+	"elements": [
+		{"id": 1, "web_name": "Salah", "squad_number": 11, "team": 10},
+		{"id": 2, "web_name": "Haaland", "team": 11}
+	],
+	"teams": [{"id": 10, "short_name": "LIV", "strength_overall_home": 1340}],
+	"live": {"1": {"stats": {"total_points": 6, "minutes": 90}}, "2": {"stats": {"minutes": 0}}},
+	"game": {"current_event": 7}
+}`
+
+func decode(t *testing.T, s string) any {
+	t.Helper()
+	var v any
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func mustParse(t *testing.T, paths ...string) []Path {
+	t.Helper()
+	out := make([]Path, 0, len(paths))
+	for _, s := range paths {
+		p, err := Parse(s)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", s, err)
+		}
+		out = append(out, p)
+	}
+	return out
+}
+
+func TestProject(t *testing.T) {
+	cases := []struct {
+		name      string
+		paths     []string
+		want      string
+		unmatched []string
+	}{
+		{
+			name:  "array fields merge element by element",
+			paths: []string{"elements[].web_name", "elements[].squad_number"},
+			want:  `{"elements": [{"web_name": "Salah", "squad_number": 11}, {"web_name": "Haaland"}]}`,
+		},
+		{
+			name:  "two top-level keys",
+			paths: []string{"teams[].strength_overall_home", "game.current_event"},
+			want:  `{"teams": [{"strength_overall_home": 1340}], "game": {"current_event": 7}}`,
+		},
+		{
+			name:  "wildcard over object values keeps the keys",
+			paths: []string{"live.*.stats.total_points"},
+			want:  `{"live": {"1": {"stats": {"total_points": 6}}}}`,
+		},
+		{
+			name:  "a subtree",
+			paths: []string{"live.1.stats"},
+			want:  `{"live": {"1": {"stats": {"total_points": 6, "minutes": 90}}}}`,
+		},
+		{
+			name:      "unmatched paths are reported, not fatal",
+			paths:     []string{"game.current_event", "elements[].nope", "teams.short_name", "game[].current_event"},
+			want:      `{"game": {"current_event": 7}}`,
+			unmatched: []string{"elements[].nope", "teams.short_name", "game[].current_event"},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, unmatched := Project(decode(t, bootstrapDoc), mustParse(t, c.paths...))
+			if want := decode(t, c.want); !reflect.DeepEqual(got, want) {
+				b, _ := json.Marshal(got)
+				t.Errorf("got %s, want %s", b, c.want)
+			}
+			if len(c.unmatched) == 0 {
+				c.unmatched = []string{}
+			}
+			if !reflect.DeepEqual(unmatched, c.unmatched) {
+				t.Errorf("unmatched = %v, want %v", unmatched, c.unmatched)
+			}
+		})
+	}
+}
+
+func TestParse_Rejects(t *testing.T) {
+	for _, s := range []string{"", "*", "*.*", "a..b", "[]", "a[0].b", "a[*]", "el*ments", ".a"} {
+		if _, err := Parse(s); err == nil {
+			t.Errorf("Parse(%q) accepted", s)
+		}
+	}
+	for _, s := range []string{"*.web_name", "elements[].*.x", "a.b[].c"} {
+		if _, err := Parse(s); err != nil {
+			t.Errorf("Parse(%q): %v", s, err)
+		}
+	}
+}
@@ -1,30 +1,53 @@
 package reconcile
 
 import (
-	"encoding/json"
-	"os"
-	"path/filepath"
+	"fmt"
 	"sort"
-	"time"
 
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/clock"
 	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/ledger"
 	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/model"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
 )
 
+// EntryMismatch is an entry whose GW snapshot disagrees with the ledger or
+// is missing. FirstAvailableGW is set when the snapshot is a stub for a GW
+// before the entry joined the league.
 type EntryMismatch struct {
-	EntryID         int   `json:"entry_id"`
-	Gameweek        int   `json:"gameweek"`
-	NotOwned        []int `json:"not_owned"`
-	TotalPicks      int   `json:"total_picks"`
-	TotalOwned      int   `json:"total_owned"`
-	MissingSnapshot bool  `json:"missing_snapshot"`
+	EntryID          int   `json:"entry_id"`
+	Gameweek         int   `json:"gameweek"`
+	NotOwned         []int `json:"not_owned"`
+	TotalPicks       int   `json:"total_picks"`
+	TotalOwned       int   `json:"total_owned"`
+	MissingSnapshot  bool  `json:"missing_snapshot"`
+	FirstAvailableGW int   `json:"first_available_gw,omitempty"`
+}
+
+// OwnershipMove identifies the transaction or trade that last moved a player.
+type OwnershipMove struct {
+	Kind  string `json:"kind"` // "transaction" or "trade"
+	ID    int    `json:"id"`
+	Event int    `json:"event"`
+}
+
+// DuplicateOwnership is a player that more than one entry owns at the same GW.
+type DuplicateOwnership struct {
+	Element  int            `json:"element"`
+	Owners   []int          `json:"owners"`
+	LastMove *OwnershipMove `json:"last_move,omitempty"`
 }
 
 type Report struct {
-	LeagueID       int             `json:"league_id"`
-	Gameweek       int             `json:"gameweek"`
-	GeneratedAtUTC string          `json:"generated_at_utc"`
-	Entries        []EntryMismatch `json:"entries"`
+	LeagueID       int    `json:"league_id"`
+	Gameweek       int    `json:"gameweek"`
+	GeneratedAtUTC string `json:"generated_at_utc"`
+	// UnownedRostered counts snapshot picks that the entry did not own at
+	// this GW, summed across entries. Zero here and no duplicates means the
+	// GW reconciled cleanly.
+	UnownedRostered int                  `json:"unowned_rostered"`
+	Entries         []EntryMismatch      `json:"entries"`
+	Duplicates      []DuplicateOwnership `json:"duplicates"`
+	Warnings        []string             `json:"warnings,omitempty"`
 }
 
 type TransactionsResponse struct {
@@ -40,6 +63,12 @@ type Transaction struct {
 	ID         int    `json:"id"`
 	Kind       string `json:"kind"`
 	Result     string `json:"result"`
+	// Bid is the FAAB amount on a waiver claim; nil in priority-waiver
+	// leagues.
+	Bid *int `json:"bid,omitempty"`
+	// Index is the 0-based waiver round a claim was processed in, when the
+	// API sends it.
+	Index *int `json:"index,omitempty"`
 }
 
 type TradesResponse struct {
@@ -74,9 +103,11 @@ func BuildOwnershipMap(ledgerIn *model.DraftLedger) map[int]map[int]bool {
 	return out
 }
 
-func BuildReport(leagueID int, gw int, ledgerIn *model.DraftLedger, transactions []Transaction, trades []Trade, snapshots map[int]*ledger.EntrySnapshot, entryIDs []int) *Report {
+func BuildReport(leagueID int, gw int, ledgerIn *model.DraftLedger, transactions []Transaction, trades []Trade, snapshots map[int]*ledger.EntrySnapshot, entryIDs []int, clk clock.Clock) *Report {
 	owned := BuildOwnershipMapAtGW(ledgerIn, transactions, trades, gw)
 	entries := make([]EntryMismatch, 0)
+	unowned := 0
+	warnings := TruncationWarnings(ledgerIn, transactions, trades, snapshots)
 
 	for _, entryID := range entryIDs {
 		snap := snapshots[entryID]
@@ -88,6 +119,16 @@ func BuildReport(leagueID int, gw int, ledgerIn *model.DraftLedger, transactions
 			})
 			continue
 		}
+		if snap.Missing {
+			entries = append(entries, EntryMismatch{
+				EntryID:          entryID,
+				Gameweek:         gw,
+				MissingSnapshot:  true,
+				FirstAvailableGW: snap.FirstAvailableGW,
+			})
+			warnings = append(warnings, fmt.Sprintf("entry %d has no picks before GW%d (joined the league mid-season)", entryID, snap.FirstAvailableGW))
+			continue
+		}
 
 		notOwned := make([]int, 0)
 		for _, p := range snap.Picks {
@@ -96,6 +137,7 @@ func BuildReport(leagueID int, gw int, ledgerIn *model.DraftLedger, transactions
 			}
 		}
 
+		unowned += len(notOwned)
 		if len(notOwned) > 0 {
 			entries = append(entries, EntryMismatch{
 				EntryID:    entryID,
@@ -108,25 +150,132 @@ func BuildReport(leagueID int, gw int, ledgerIn *model.DraftLedger, transactions
 	}
 
 	return &Report{
-		LeagueID:       leagueID,
-		Gameweek:       gw,
-		GeneratedAtUTC: time.Now().UTC().Format(time.RFC3339),
-		Entries:        entries,
+		LeagueID:        leagueID,
+		Gameweek:        gw,
+		GeneratedAtUTC:  clock.UTC(clock.Or(clk).Now()),
+		UnownedRostered: unowned,
+		Entries:         entries,
+		Duplicates:      DuplicateOwnerships(owned, transactions, trades, gw),
+		Warnings:        warnings,
 	}
 }
 
-func BuildOwnershipMapAtGW(ledgerIn *model.DraftLedger, transactions []Transaction, trades []Trade, gw int) map[int]map[int]bool {
-	owned := BuildOwnershipMap(ledgerIn)
+// DuplicateOwnerships reports every element that appears in more than one
+// entry's ownership map. A correct ledger never produces one; they come from
+// a trade or waiver being applied twice or misparsed. Each duplicate carries
+// the last move up to gw that touched the element, which is usually the
+// culprit.
+func DuplicateOwnerships(owned map[int]map[int]bool, transactions []Transaction, trades []Trade, gw int) []DuplicateOwnership {
+	owners := make(map[int][]int)
+	for entryID, players := range owned {
+		for el := range players {
+			owners[el] = append(owners[el], entryID)
+		}
+	}
+
+	lastMove := make(map[int]*OwnershipMove)
+	for _, op := range orderedOps(transactions, trades, gw) {
+		if op.tx != nil {
+			move := &OwnershipMove{Kind: "transaction", ID: op.tx.ID, Event: op.tx.Event}
+			for _, el := range []int{op.tx.ElementIn, op.tx.ElementOut} {
+				if el != 0 {
+					lastMove[el] = move
+				}
+			}
+			continue
+		}
+		move := &OwnershipMove{Kind: "trade", ID: op.tr.ID, Event: op.tr.Event}
+		for _, item := range op.tr.TradeItems {
+			for _, el := range []int{item.ElementIn, item.ElementOut} {
+				if el != 0 {
+					lastMove[el] = move
+				}
+			}
+		}
+	}
+
+	out := make([]DuplicateOwnership, 0)
+	for el, entryIDs := range owners {
+		if len(entryIDs) < 2 {
+			continue
+		}
+		sort.Ints(entryIDs)
+		out = append(out, DuplicateOwnership{Element: el, Owners: entryIDs, LastMove: lastMove[el]})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Element < out[j].Element })
+	return out
+}
+
+// TruncationWarnings flags a transactions file that looks truncated: its
+// earliest event is after GW1, yet some drafted player is absent from every
+// snapshot without any transaction or trade moving them off a roster. That
+// combination means the move that released them is missing from the file.
+func TruncationWarnings(ledgerIn *model.DraftLedger, transactions []Transaction, trades []Trade, snapshots map[int]*ledger.EntrySnapshot) []string {
+	if len(transactions) == 0 || len(snapshots) == 0 {
+		return nil
+	}
+	earliest := transactions[0].Event
+	for _, tx := range transactions {
+		if tx.Event < earliest {
+			earliest = tx.Event
+		}
+	}
+	if earliest <= 1 {
+		return nil
+	}
+
+	inSnapshot := make(map[int]bool)
+	for _, snap := range snapshots {
+		if snap == nil {
+			continue
+		}
+		for _, p := range snap.Picks {
+			inSnapshot[p.Element] = true
+		}
+	}
+	moved := make(map[int]bool)
+	for _, tx := range transactions {
+		moved[tx.ElementOut] = true
+	}
+	for _, tr := range trades {
+		for _, item := range tr.TradeItems {
+			moved[item.ElementOut] = true
+			moved[item.ElementIn] = true
+		}
+	}
 
-	type ledgerOp struct {
-		event int
-		time  string
-		id    int
-		kind  string
-		tx    *Transaction
-		tr    *Trade
+	missing := make([]int, 0)
+	for _, squad := range ledgerIn.Squads {
+		for _, id := range squad.PlayerIDs {
+			if !inSnapshot[id] && !moved[id] {
+				missing = append(missing, id)
+			}
+		}
 	}
+	if len(missing) == 0 {
+		return nil
+	}
+	sort.Ints(missing)
+	return []string{fmt.Sprintf(
+		"transactions start at GW%d but drafted players %v are on no roster and never left one; transactions.json is probably truncated",
+		earliest, missing,
+	)}
+}
 
+// ledgerOp is one accepted transaction or processed trade, in the order
+// BuildOwnershipMapAtGW applies them.
+type ledgerOp struct {
+	event int
+	time  string
+	id    int
+	kind  string
+	tx    *Transaction
+	tr    *Trade
+}
+
+// orderedOps returns the moves that apply up to and including gw, sorted by
+// event, then time, then id.
+func orderedOps(transactions []Transaction, trades []Trade, gw int) []ledgerOp {
 	ops := make([]ledgerOp, 0, len(transactions)+len(trades))
 	for i := range transactions {
 		tx := transactions[i]
@@ -166,56 +315,61 @@ func BuildOwnershipMapAtGW(ledgerIn *model.DraftLedger, transactions []Transacti
 		}
 		return ops[i].kind < ops[j].kind
 	})
+	return ops
+}
 
-	for _, op := range ops {
-		if op.tx != nil {
-			tx := op.tx
-			if _, ok := owned[tx.Entry]; !ok {
-				owned[tx.Entry] = make(map[int]bool)
-			}
-			if tx.ElementOut != 0 {
-				delete(owned[tx.Entry], tx.ElementOut)
-			}
-			if tx.ElementIn != 0 {
-				owned[tx.Entry][tx.ElementIn] = true
-			}
-			continue
+func BuildOwnershipMapAtGW(ledgerIn *model.DraftLedger, transactions []Transaction, trades []Trade, gw int) map[int]map[int]bool {
+	owned := BuildOwnershipMap(ledgerIn)
+	for _, op := range orderedOps(transactions, trades, gw) {
+		applyOp(owned, op)
+	}
+	return owned
+}
+
+// applyOp moves op's players between the rosters in owned and returns the
+// elements it touched.
+func applyOp(owned map[int]map[int]bool, op ledgerOp) []int {
+	var touched []int
+	if op.tx != nil {
+		tx := op.tx
+		if _, ok := owned[tx.Entry]; !ok {
+			owned[tx.Entry] = make(map[int]bool)
 		}
+		if tx.ElementOut != 0 {
+			delete(owned[tx.Entry], tx.ElementOut)
+			touched = append(touched, tx.ElementOut)
+		}
+		if tx.ElementIn != 0 {
+			owned[tx.Entry][tx.ElementIn] = true
+			touched = append(touched, tx.ElementIn)
+		}
+		return touched
+	}
 
-		if op.tr != nil {
-			tr := op.tr
-			if _, ok := owned[tr.OfferedEntry]; !ok {
-				owned[tr.OfferedEntry] = make(map[int]bool)
-			}
-			if _, ok := owned[tr.ReceivedEntry]; !ok {
-				owned[tr.ReceivedEntry] = make(map[int]bool)
+	if op.tr != nil {
+		tr := op.tr
+		if _, ok := owned[tr.OfferedEntry]; !ok {
+			owned[tr.OfferedEntry] = make(map[int]bool)
+		}
+		if _, ok := owned[tr.ReceivedEntry]; !ok {
+			owned[tr.ReceivedEntry] = make(map[int]bool)
+		}
+		for _, item := range tr.TradeItems {
+			if item.ElementOut != 0 {
+				delete(owned[tr.OfferedEntry], item.ElementOut)
+				owned[tr.ReceivedEntry][item.ElementOut] = true
+				touched = append(touched, item.ElementOut)
 			}
-			for _, item := range tr.TradeItems {
-				if item.ElementOut != 0 {
-					delete(owned[tr.OfferedEntry], item.ElementOut)
-					owned[tr.ReceivedEntry][item.ElementOut] = true
-				}
-				if item.ElementIn != 0 {
-					delete(owned[tr.ReceivedEntry], item.ElementIn)
-					owned[tr.OfferedEntry][item.ElementIn] = true
-				}
+			if item.ElementIn != 0 {
+				delete(owned[tr.ReceivedEntry], item.ElementIn)
+				owned[tr.OfferedEntry][item.ElementIn] = true
+				touched = append(touched, item.ElementIn)
 			}
 		}
 	}
-
-	return owned
+	return touched
 }
 
 func WriteReport(path string, report *Report) error {
-	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
-		return err
-	}
-
-	b, err := json.MarshalIndent(report, "", "  ")
-	if err != nil {
-		return err
-	}
-
-	b = append(b, '\n')
-	return os.WriteFile(path, b, 0o644)
+	return store.WriteDerivedJSON(path, report)
 }
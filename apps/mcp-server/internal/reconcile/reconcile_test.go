@@ -270,7 +270,7 @@ func TestBuildReport_MissingSnapshot(t *testing.T) {
 		playerIDs []int
 	}{1, []int{10}})
 
-	report := BuildReport(1, 3, l, nil, nil, map[int]*ledger.EntrySnapshot{}, []int{1})
+	report := BuildReport(1, 3, l, nil, nil, map[int]*ledger.EntrySnapshot{}, []int{1}, nil)
 
 	if len(report.Entries) != 1 {
 		t.Fatalf("Entries len = %d, want 1", len(report.Entries))
@@ -295,7 +295,7 @@ func TestBuildReport_CleanRoster_NoMismatch(t *testing.T) {
 		},
 	}
 
-	report := BuildReport(1, 3, l, nil, nil, map[int]*ledger.EntrySnapshot{1: snap}, []int{1})
+	report := BuildReport(1, 3, l, nil, nil, map[int]*ledger.EntrySnapshot{1: snap}, []int{1}, nil)
 
 	if len(report.Entries) != 0 {
 		t.Errorf("Entries len = %d, want 0 (no mismatches)", len(report.Entries))
@@ -318,7 +318,7 @@ func TestBuildReport_UnownedPlayerDetected(t *testing.T) {
 		},
 	}
 
-	report := BuildReport(1, 3, l, nil, nil, map[int]*ledger.EntrySnapshot{1: snap}, []int{1})
+	report := BuildReport(1, 3, l, nil, nil, map[int]*ledger.EntrySnapshot{1: snap}, []int{1}, nil)
 
 	if len(report.Entries) != 1 {
 		t.Fatalf("Entries len = %d, want 1 (mismatch)", len(report.Entries))
@@ -327,3 +327,120 @@ func TestBuildReport_UnownedPlayerDetected(t *testing.T) {
 		t.Errorf("NotOwned = %v, want [99]", report.Entries[0].NotOwned)
 	}
 }
+
+// ---------------------------------------------------------------------------
+// TruncationWarnings
+// ---------------------------------------------------------------------------
+
+func TestTruncationWarnings_FlagsMissingDraftedPlayer(t *testing.T) {
+	// Player 20 was drafted but sits on no roster and no move released them,
+	// while the earliest transaction in the file is GW5.
+	l := makeLedger(struct {
+		entryID   int
+		playerIDs []int
+	}{1, []int{10, 20}})
+	txs := []Transaction{makeWaiverTx(50, 1, 30, 10, 5)}
+	snap := &ledger.EntrySnapshot{EntryID: 1, Picks: []ledger.EntryPick{{Element: 30}}}
+
+	warnings := TruncationWarnings(l, txs, nil, map[int]*ledger.EntrySnapshot{1: snap})
+	if len(warnings) != 1 {
+		t.Fatalf("warnings = %v, want 1", warnings)
+	}
+
+	report := BuildReport(1, 5, l, txs, nil, map[int]*ledger.EntrySnapshot{1: snap}, []int{1}, nil)
+	if len(report.Warnings) != 1 {
+		t.Errorf("BuildReport warnings = %v, want 1", report.Warnings)
+	}
+}
+
+func TestTruncationWarnings_NoWarningFromGW1(t *testing.T) {
+	l := makeLedger(struct {
+		entryID   int
+		playerIDs []int
+	}{1, []int{10, 20}})
+	txs := []Transaction{makeWaiverTx(50, 1, 30, 10, 1)}
+	snap := &ledger.EntrySnapshot{EntryID: 1, Picks: []ledger.EntryPick{{Element: 30}}}
+
+	if warnings := TruncationWarnings(l, txs, nil, map[int]*ledger.EntrySnapshot{1: snap}); len(warnings) != 0 {
+		t.Errorf("warnings = %v, want none when transactions start at GW1", warnings)
+	}
+}
+
+func TestTruncationWarnings_AccountedForByMoves(t *testing.T) {
+	l := makeLedger(struct {
+		entryID   int
+		playerIDs []int
+	}{1, []int{10, 20}})
+	txs := []Transaction{
+		makeWaiverTx(50, 1, 30, 10, 4),
+		makeWaiverTx(51, 1, 40, 20, 6),
+	}
+	snap := &ledger.EntrySnapshot{EntryID: 1, Picks: []ledger.EntryPick{{Element: 30}, {Element: 40}}}
+
+	if warnings := TruncationWarnings(l, txs, nil, map[int]*ledger.EntrySnapshot{1: snap}); len(warnings) != 0 {
+		t.Errorf("warnings = %v, want none when every missing player was released", warnings)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// DuplicateOwnerships
+// ---------------------------------------------------------------------------
+
+func TestBuildReport_DoubleAppliedTradeDuplicate(t *testing.T) {
+	// GW2: entry 1 trades player 20 to entry 2 for player 30.
+	// GW3: entry 2 waives 20 away for 50; entry 3 picks 20 up for 60.
+	// GW4: the same trade shows up again under a new id and hands 20 back to
+	// entry 2, so entries 2 and 3 both own player 20.
+	l := makeLedger(
+		struct {
+			entryID   int
+			playerIDs []int
+		}{1, []int{10, 20}},
+		struct {
+			entryID   int
+			playerIDs []int
+		}{2, []int{30, 40}},
+		struct {
+			entryID   int
+			playerIDs []int
+		}{3, []int{60}},
+	)
+	trade := Trade{
+		ID: 1, OfferedEntry: 1, ReceivedEntry: 2, Event: 2, State: "p",
+		ResponseTime: "2024-09-01T10:00:00Z",
+		TradeItems:   []TradeItem{{ElementOut: 20, ElementIn: 30}},
+	}
+	replay := trade
+	replay.ID, replay.Event, replay.ResponseTime = 2, 4, "2024-09-15T10:00:00Z"
+	txs := []Transaction{
+		makeWaiverTx(10, 2, 50, 20, 3),
+		{ID: 11, Entry: 3, ElementIn: 20, ElementOut: 60, Event: 3, Kind: "f", Result: "a"},
+	}
+	snaps := map[int]*ledger.EntrySnapshot{
+		1: {EntryID: 1, Picks: []ledger.EntryPick{{Element: 10}, {Element: 30}}},
+		2: {EntryID: 2, Picks: []ledger.EntryPick{{Element: 40}, {Element: 50}}},
+		3: {EntryID: 3, Picks: []ledger.EntryPick{{Element: 20}, {Element: 99}}},
+	}
+
+	report := BuildReport(1, 4, l, txs, []Trade{trade, replay}, snaps, []int{1, 2, 3}, nil)
+
+	if len(report.Duplicates) != 1 {
+		t.Fatalf("Duplicates = %+v, want player 20 only", report.Duplicates)
+	}
+	d := report.Duplicates[0]
+	if d.Element != 20 || len(d.Owners) != 2 || d.Owners[0] != 2 || d.Owners[1] != 3 {
+		t.Errorf("duplicate = %+v, want element 20 owned by [2 3]", d)
+	}
+	if d.LastMove == nil || d.LastMove.Kind != "trade" || d.LastMove.ID != 2 || d.LastMove.Event != 4 {
+		t.Errorf("last move = %+v, want the replayed trade 2 in GW4", d.LastMove)
+	}
+	if report.UnownedRostered != 1 {
+		t.Errorf("UnownedRostered = %d, want 1 (player 99)", report.UnownedRostered)
+	}
+
+	// Before the replay the ledger is consistent.
+	report = BuildReport(1, 3, l, txs, []Trade{trade, replay}, snaps, []int{1, 2, 3}, nil)
+	if len(report.Duplicates) != 0 {
+		t.Errorf("GW3 duplicates = %+v, want none", report.Duplicates)
+	}
+}
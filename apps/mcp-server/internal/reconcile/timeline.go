@@ -0,0 +1,202 @@
+package reconcile
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"path/filepath"
+	"slices"
+	"sort"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/clock"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/model"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+)
+
+// OwnershipSegment says that from FromGW on EntryID holds the element;
+// EntryID 0 means it is a free agent.
+type OwnershipSegment struct {
+	FromGW  int `json:"from_gw"`
+	EntryID int `json:"entry_id"`
+}
+
+// OwnershipTimeline is the ownership replay for a whole league done once, so
+// a roster at any GW is a lookup rather than a replay from the draft. GW 0 is
+// the drafted squads. Each element's segments are ordered by FromGW; when the
+// replay leaves an element on two rosters (a duplicate the reconcile report
+// flags) both owners get a segment with the same FromGW.
+type OwnershipTimeline struct {
+	LeagueID       int    `json:"league_id"`
+	GeneratedAtUTC string `json:"generated_at_utc"`
+	// ThroughGW is the last GW with an accepted move; every later GW has
+	// the same rosters.
+	ThroughGW int `json:"through_gw"`
+	// Moves counts the accepted transactions and processed trades replayed.
+	Moves int `json:"moves"`
+	// Entries maps each entry to the first GW it has a roster in.
+	Entries  map[int]int                `json:"entries"`
+	Elements map[int][]OwnershipSegment `json:"elements"`
+}
+
+// OwnershipTimelinePath is where the derive pipeline writes leagueID's
+// timeline.
+func OwnershipTimelinePath(derivedRoot string, leagueID int) string {
+	return filepath.Join(derivedRoot, "ownership", fmt.Sprintf("%d.json", leagueID))
+}
+
+// BuildOwnershipTimeline replays the draft, accepted transactions and
+// processed trades once, in the order BuildOwnershipMapAtGW applies them,
+// and records each element's owners at the end of every GW they change.
+func BuildOwnershipTimeline(leagueID int, ledgerIn *model.DraftLedger, transactions []Transaction, trades []Trade, clk clock.Clock) *OwnershipTimeline {
+	owned := BuildOwnershipMap(ledgerIn)
+	t := &OwnershipTimeline{
+		LeagueID:       leagueID,
+		GeneratedAtUTC: clock.UTC(clock.Or(clk).Now()),
+		Entries:        make(map[int]int, len(owned)),
+		Elements:       make(map[int][]OwnershipSegment),
+	}
+	touched := make(map[int]bool)
+	for _, players := range owned {
+		for id := range players {
+			touched[id] = true
+		}
+	}
+	t.record(owned, touched, 0)
+
+	ops := orderedOps(transactions, trades, math.MaxInt)
+	for i := 0; i < len(ops); {
+		gw := ops[i].event
+		touched = make(map[int]bool)
+		for ; i < len(ops) && ops[i].event == gw; i++ {
+			for _, id := range applyOp(owned, ops[i]) {
+				touched[id] = true
+			}
+		}
+		t.record(owned, touched, gw)
+		t.ThroughGW = gw
+	}
+	t.Moves = len(ops)
+	return t
+}
+
+// Current reports whether t was built from the same accepted moves as
+// transactions and trades, i.e. whether raw files refreshed since the last
+// derive have added none.
+func (t *OwnershipTimeline) Current(transactions []Transaction, trades []Trade) bool {
+	ops := orderedOps(transactions, trades, math.MaxInt)
+	through := 0
+	if len(ops) > 0 {
+		through = ops[len(ops)-1].event
+	}
+	return len(ops) == t.Moves && through == t.ThroughGW
+}
+
+// record notes the entries first seen by gw and the owners of each touched
+// element at the end of gw.
+func (t *OwnershipTimeline) record(owned map[int]map[int]bool, touched map[int]bool, gw int) {
+	for entryID := range owned {
+		if _, ok := t.Entries[entryID]; !ok {
+			t.Entries[entryID] = gw
+		}
+	}
+	for id := range touched {
+		owners := make([]int, 0, 1)
+		for entryID, players := range owned {
+			if players[id] {
+				owners = append(owners, entryID)
+			}
+		}
+		sort.Ints(owners)
+
+		segs := t.Elements[id]
+		// A GW 0 move replaces the drafted owners rather than following
+		// them.
+		for len(segs) > 0 && segs[len(segs)-1].FromGW == gw {
+			segs = segs[:len(segs)-1]
+		}
+		if slices.Equal(owners, ownerIDs(segmentsAt(segs, gw))) {
+			t.Elements[id] = segs
+			continue
+		}
+		if len(owners) == 0 {
+			segs = append(segs, OwnershipSegment{FromGW: gw})
+		}
+		for _, entryID := range owners {
+			segs = append(segs, OwnershipSegment{FromGW: gw, EntryID: entryID})
+		}
+		t.Elements[id] = segs
+	}
+}
+
+// segmentsAt returns the segments in force at gw: those sharing the latest
+// FromGW not after it.
+func segmentsAt(segs []OwnershipSegment, gw int) []OwnershipSegment {
+	end := sort.Search(len(segs), func(i int) bool { return segs[i].FromGW > gw })
+	start := end
+	for start > 0 && segs[start-1].FromGW == segs[end-1].FromGW {
+		start--
+	}
+	return segs[start:end]
+}
+
+func ownerIDs(segs []OwnershipSegment) []int {
+	out := make([]int, 0, len(segs))
+	for _, s := range segs {
+		if s.EntryID != 0 {
+			out = append(out, s.EntryID)
+		}
+	}
+	return out
+}
+
+// OwnerAt returns the entry holding element at the end of gw, or 0 when it
+// is a free agent. Of two owners it returns the lower entry id.
+func (t *OwnershipTimeline) OwnerAt(element int, gw int) int {
+	if owners := ownerIDs(segmentsAt(t.Elements[element], gw)); len(owners) > 0 {
+		return owners[0]
+	}
+	return 0
+}
+
+// OwnersAt rebuilds every entry's roster (entry id -> element ids) at the
+// end of gw, the same map BuildOwnershipMapAtGW returns.
+func (t *OwnershipTimeline) OwnersAt(gw int) map[int]map[int]bool {
+	out := make(map[int]map[int]bool, len(t.Entries))
+	for entryID, from := range t.Entries {
+		if from <= gw {
+			out[entryID] = make(map[int]bool)
+		}
+	}
+	for id, segs := range t.Elements {
+		for _, s := range segmentsAt(segs, gw) {
+			if s.EntryID == 0 {
+				continue
+			}
+			if out[s.EntryID] == nil {
+				out[s.EntryID] = make(map[int]bool)
+			}
+			out[s.EntryID][id] = true
+		}
+	}
+	return out
+}
+
+// LoadOwnershipTimeline reads leagueID's timeline from derivedRoot. A league
+// without one gives an error wrapping fs.ErrNotExist; callers fall back to
+// BuildOwnershipMapAtGW.
+func LoadOwnershipTimeline(derivedRoot string, leagueID int) (*OwnershipTimeline, error) {
+	raw, err := store.ReadDerived(OwnershipTimelinePath(derivedRoot, leagueID))
+	if err != nil {
+		return nil, err
+	}
+	var t OwnershipTimeline
+	if err := json.Unmarshal(raw, &t); err != nil {
+		return nil, fmt.Errorf("parse ownership timeline: %w", err)
+	}
+	return &t, nil
+}
+
+// WriteOwnershipTimeline writes t to its path under derivedRoot.
+func WriteOwnershipTimeline(derivedRoot string, t *OwnershipTimeline) error {
+	return store.WriteDerivedJSON(OwnershipTimelinePath(derivedRoot, t.LeagueID), t)
+}
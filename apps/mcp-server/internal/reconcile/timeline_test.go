@@ -0,0 +1,142 @@
+package reconcile
+
+import (
+	"errors"
+	"io/fs"
+	"reflect"
+	"testing"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/model"
+)
+
+// timelineLeague is a season of moves for three drafted entries and one who
+// joins by a free-agent add: failed and rejected waivers, drops and re-adds,
+// a player traded away and back, an unprocessed trade, and two moves in one
+// GW touching the same player.
+func timelineLeague() (*model.DraftLedger, []Transaction, []Trade) {
+	ledgerIn := &model.DraftLedger{Squads: []model.Squad{
+		{EntryID: 1, PlayerIDs: []int{10, 11, 12}},
+		{EntryID: 2, PlayerIDs: []int{20, 21, 22}},
+		{EntryID: 3, PlayerIDs: []int{30, 31}},
+	}}
+	failed := makeWaiverTx(2, 2, 40, 21, 2)
+	failed.Result = "do"
+	rejected := makeWaiverTx(3, 3, 40, 31, 2)
+	rejected.Result = "r"
+	free := makeWaiverTx(8, 4, 50, 0, 5)
+	free.Kind, free.Added = "f", "2024-10-01T10:00:00Z"
+	transactions := []Transaction{
+		makeWaiverTx(1, 1, 40, 12, 2),
+		failed,
+		rejected,
+		makeWaiverTx(4, 2, 12, 22, 3), // 12 re-added by another entry
+		makeWaiverTx(5, 1, 22, 40, 4), // 22 re-added, 40 dropped
+		makeWaiverTx(6, 3, 40, 30, 6), // 40 back on a roster
+		makeWaiverTx(7, 2, 30, 12, 6), // 30 straight back out the same GW
+		free,
+	}
+	trades := []Trade{
+		{ID: 1, Event: 3, State: "p", ResponseTime: "2024-09-20T09:00:00Z", OfferedEntry: 1, ReceivedEntry: 3,
+			TradeItems: []TradeItem{{ElementOut: 10, ElementIn: 31}}},
+		{ID: 2, Event: 4, State: "r", OfferedEntry: 2, ReceivedEntry: 3,
+			TradeItems: []TradeItem{{ElementOut: 20, ElementIn: 10}}},
+		{ID: 3, Event: 7, State: "p", OfferedEntry: 3, ReceivedEntry: 1,
+			TradeItems: []TradeItem{{ElementOut: 10, ElementIn: 31}}},
+	}
+	return ledgerIn, transactions, trades
+}
+
+func TestOwnershipTimeline_MatchesReplayEveryGW(t *testing.T) {
+	ledgerIn, transactions, trades := timelineLeague()
+	timeline := BuildOwnershipTimeline(100, ledgerIn, transactions, trades, nil)
+	if timeline.ThroughGW != 7 {
+		t.Errorf("ThroughGW = %d, want 7", timeline.ThroughGW)
+	}
+	if timeline.Entries[4] != 5 || timeline.Entries[1] != 0 {
+		t.Errorf("Entries = %v", timeline.Entries)
+	}
+
+	elements := []int{10, 11, 12, 20, 21, 22, 30, 31, 40, 50, 99}
+	for gw := 0; gw <= 9; gw++ {
+		want := BuildOwnershipMapAtGW(ledgerIn, transactions, trades, gw)
+		got := timeline.OwnersAt(gw)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("GW%d OwnersAt = %v, replay = %v", gw, got, want)
+		}
+		for _, id := range elements {
+			owner := 0
+			for entryID, roster := range want {
+				if roster[id] {
+					owner = entryID
+				}
+			}
+			if got := timeline.OwnerAt(id, gw); got != owner {
+				t.Errorf("GW%d OwnerAt(%d) = %d, replay owner %d", gw, id, got, owner)
+			}
+		}
+	}
+
+	// 30 went from entry 3 to 2 within GW6, so it keeps one segment for
+	// that GW; 10 went away and came back.
+	want := []OwnershipSegment{{FromGW: 0, EntryID: 3}, {FromGW: 6, EntryID: 2}}
+	if got := timeline.Elements[30]; !reflect.DeepEqual(got, want) {
+		t.Errorf("element 30 = %v, want %v", got, want)
+	}
+	want = []OwnershipSegment{{FromGW: 0, EntryID: 1}, {FromGW: 3, EntryID: 3}, {FromGW: 7, EntryID: 1}}
+	if got := timeline.Elements[10]; !reflect.DeepEqual(got, want) {
+		t.Errorf("element 10 = %v, want %v", got, want)
+	}
+}
+
+func TestOwnershipTimeline_DuplicateOwners(t *testing.T) {
+	ledgerIn := makeLedger(
+		struct {
+			entryID   int
+			playerIDs []int
+		}{1, []int{10}},
+		struct {
+			entryID   int
+			playerIDs []int
+		}{2, []int{20}},
+	)
+	// Entry 2 claims 10 without entry 1 ever dropping him.
+	transactions := []Transaction{makeWaiverTx(1, 2, 10, 20, 2)}
+	timeline := BuildOwnershipTimeline(1, ledgerIn, transactions, nil, nil)
+	for gw := 0; gw <= 3; gw++ {
+		if got, want := timeline.OwnersAt(gw), BuildOwnershipMapAtGW(ledgerIn, transactions, nil, gw); !reflect.DeepEqual(got, want) {
+			t.Errorf("GW%d OwnersAt = %v, replay = %v", gw, got, want)
+		}
+	}
+	if got := timeline.OwnerAt(10, 2); got != 1 {
+		t.Errorf("OwnerAt(10, 2) = %d, want the lower entry id", got)
+	}
+	if got := timeline.OwnerAt(20, 2); got != 0 {
+		t.Errorf("OwnerAt(20, 2) = %d, want a free agent", got)
+	}
+}
+
+func TestOwnershipTimeline_WriteAndLoad(t *testing.T) {
+	root := t.TempDir()
+	if _, err := LoadOwnershipTimeline(root, 100); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("missing timeline: err = %v", err)
+	}
+	ledgerIn, transactions, trades := timelineLeague()
+	if err := WriteOwnershipTimeline(root, BuildOwnershipTimeline(100, ledgerIn, transactions, trades, nil)); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadOwnershipTimeline(root, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := loaded.OwnersAt(4), BuildOwnershipMapAtGW(ledgerIn, transactions, trades, 4); !reflect.DeepEqual(got, want) {
+		t.Errorf("loaded OwnersAt(4) = %v, replay = %v", got, want)
+	}
+	if !loaded.Current(transactions, trades) {
+		t.Error("Current = false for the moves it was built from")
+	}
+	late := makeWaiverTx(9, 1, 60, 11, 7)
+	late.Kind = "f"
+	if loaded.Current(append(transactions, late), trades) {
+		t.Error("Current = true after a free-agent add in its last GW")
+	}
+}
@@ -0,0 +1,274 @@
+// Package render turns summary rows into GitHub-flavoured markdown tables
+// and RFC 4180 CSV. Columns name struct fields by their json tags, so any
+// summary type can be rendered without render-specific tags; which columns
+// appear, and in what order, is chosen per summary by the caller.
+package render
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Output formats accepted by ParseFormat.
+const (
+	FormatJSON     = "json"
+	FormatMarkdown = "markdown"
+	FormatCSV      = "csv"
+)
+
+// ParseFormat normalises a format argument; empty means FormatJSON.
+func ParseFormat(s string) (string, error) {
+	f := strings.ToLower(strings.TrimSpace(s))
+	switch f {
+	case "":
+		return FormatJSON, nil
+	case FormatJSON, FormatMarkdown, FormatCSV:
+		return f, nil
+	case "md":
+		return FormatMarkdown, nil
+	}
+	return "", fmt.Errorf("format must be json, markdown or csv, got %q", s)
+}
+
+// Column is one table column. Field is the json name of the row field,
+// dotted to reach into nested structs ("record.wins"). Decimals sets the
+// places shown for float fields; Format, when set, replaces the default
+// formatting of the field's value.
+type Column struct {
+	Field    string
+	Header   string
+	Decimals int
+	Format   func(v any) string
+}
+
+// Table is a titled set of rows; Rows must be a slice of structs or of
+// pointers to structs.
+type Table struct {
+	Title   string
+	Columns []Column
+	Rows    any
+}
+
+// Render renders t in format, which must be FormatMarkdown or FormatCSV.
+func Render(format string, t Table) ([]byte, error) {
+	switch format {
+	case FormatMarkdown:
+		return Markdown(t)
+	case FormatCSV:
+		return CSV(t)
+	}
+	return nil, fmt.Errorf("render: unsupported format %q", format)
+}
+
+// Markdown renders t as a heading (when it has a title) and a table.
+func Markdown(t Table) ([]byte, error) {
+	cells, err := t.cells()
+	if err != nil {
+		return nil, err
+	}
+	var b bytes.Buffer
+	if t.Title != "" {
+		fmt.Fprintf(&b, "### %s\n\n", t.Title)
+	}
+	headers := make([]string, len(t.Columns))
+	rule := make([]string, len(t.Columns))
+	for i, c := range t.Columns {
+		headers[i] = escapeMarkdown(c.Header)
+		rule[i] = "---"
+		if cells.numeric[i] {
+			rule[i] = "---:"
+		}
+	}
+	writeMarkdownRow(&b, headers)
+	writeMarkdownRow(&b, rule)
+	for _, row := range cells.rows {
+		escaped := make([]string, len(row))
+		for i, v := range row {
+			escaped[i] = escapeMarkdown(v)
+		}
+		writeMarkdownRow(&b, escaped)
+	}
+	return b.Bytes(), nil
+}
+
+// CSV renders t as a header row and one record per row. The title is not
+// part of the output.
+func CSV(t Table) ([]byte, error) {
+	cells, err := t.cells()
+	if err != nil {
+		return nil, err
+	}
+	var b bytes.Buffer
+	w := csv.NewWriter(&b)
+	w.UseCRLF = true
+	headers := make([]string, len(t.Columns))
+	for i, c := range t.Columns {
+		headers[i] = c.Header
+	}
+	if err := w.Write(headers); err != nil {
+		return nil, err
+	}
+	if err := w.WriteAll(cells.rows); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+func writeMarkdownRow(b *bytes.Buffer, cells []string) {
+	b.WriteString("| ")
+	b.WriteString(strings.Join(cells, " | "))
+	b.WriteString(" |\n")
+}
+
+func escapeMarkdown(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+type tableCells struct {
+	rows [][]string
+	// numeric marks columns whose field is a number, which markdown
+	// right-aligns.
+	numeric []bool
+}
+
+func (t Table) cells() (tableCells, error) {
+	rv := reflect.ValueOf(t.Rows)
+	if rv.Kind() != reflect.Slice {
+		return tableCells{}, fmt.Errorf("render: rows must be a slice, got %T", t.Rows)
+	}
+	out := tableCells{rows: make([][]string, 0, rv.Len()), numeric: make([]bool, len(t.Columns))}
+	elem := rv.Type().Elem()
+	if elem.Kind() == reflect.Pointer {
+		elem = elem.Elem()
+	}
+	if elem.Kind() != reflect.Struct {
+		return tableCells{}, fmt.Errorf("render: rows must hold structs, got %s", elem)
+	}
+	paths := make([][]int, len(t.Columns))
+	for i, c := range t.Columns {
+		path, typ, err := fieldPath(elem, c.Field)
+		if err != nil {
+			return tableCells{}, err
+		}
+		paths[i] = path
+		if c.Format != nil {
+			continue
+		}
+		switch typ.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Float32, reflect.Float64:
+			out.numeric[i] = true
+		}
+	}
+	for r := 0; r < rv.Len(); r++ {
+		row := rv.Index(r)
+		if row.Kind() == reflect.Pointer {
+			row = row.Elem()
+		}
+		record := make([]string, len(t.Columns))
+		for i, c := range t.Columns {
+			v := fieldByPath(row, paths[i])
+			if c.Format != nil && v.IsValid() {
+				record[i] = c.Format(v.Interface())
+				continue
+			}
+			record[i] = formatValue(v, c.Decimals)
+		}
+		out.rows = append(out.rows, record)
+	}
+	return out, nil
+}
+
+// fieldPath resolves a dotted json field name to a reflect index path,
+// stepping through pointers and embedded structs.
+func fieldPath(typ reflect.Type, field string) ([]int, reflect.Type, error) {
+	var path []int
+	for _, name := range strings.Split(field, ".") {
+		if typ.Kind() == reflect.Pointer {
+			typ = typ.Elem()
+		}
+		if typ.Kind() != reflect.Struct {
+			return nil, nil, fmt.Errorf("render: %q: %s is not a struct", field, typ)
+		}
+		idx, ok := jsonField(typ, name)
+		if !ok {
+			return nil, nil, fmt.Errorf("render: %s has no json field %q", typ, name)
+		}
+		path = append(path, idx...)
+		typ = typ.FieldByIndex(idx).Type
+	}
+	return path, typ, nil
+}
+
+func jsonField(typ reflect.Type, name string) ([]int, bool) {
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		tag, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if f.Anonymous && tag == "" {
+			inner := f.Type
+			if inner.Kind() == reflect.Pointer {
+				inner = inner.Elem()
+			}
+			if inner.Kind() == reflect.Struct {
+				if idx, ok := jsonField(inner, name); ok {
+					return append([]int{i}, idx...), true
+				}
+			}
+			continue
+		}
+		if tag == name || (tag == "" && f.Name == name) {
+			return []int{i}, true
+		}
+	}
+	return nil, false
+}
+
+// fieldByPath follows path from v, returning the zero Value when a nil
+// pointer is in the way.
+func fieldByPath(v reflect.Value, path []int) reflect.Value {
+	for _, i := range path {
+		if v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return reflect.Value{}
+			}
+			v = v.Elem()
+		}
+		v = v.Field(i)
+	}
+	return v
+}
+
+func formatValue(v reflect.Value, decimals int) string {
+	if !v.IsValid() {
+		return ""
+	}
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return ""
+		}
+		return formatValue(v.Elem(), decimals)
+	case reflect.String:
+		return v.String()
+	case reflect.Bool:
+		if v.Bool() {
+			return "yes"
+		}
+		return "no"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', decimals, 64)
+	case reflect.Slice, reflect.Array:
+		parts := make([]string, v.Len())
+		for i := range parts {
+			parts[i] = formatValue(v.Index(i), decimals)
+		}
+		return strings.Join(parts, ", ")
+	}
+	return fmt.Sprint(v.Interface())
+}
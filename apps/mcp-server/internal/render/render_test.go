@@ -0,0 +1,126 @@
+package render
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+type record struct {
+	Wins int `json:"wins"`
+}
+
+type team struct {
+	Name   string   `json:"name"`
+	Rate   float64  `json:"rate"`
+	Record record   `json:"record"`
+	Picks  []int    `json:"picks"`
+	Drop   *team    `json:"drop,omitempty"`
+	Active bool     `json:"active"`
+	Tags   []string `json:"tags"`
+}
+
+type wrapped struct {
+	team
+	Extra int `json:"extra"`
+}
+
+func sampleTable() Table {
+	return Table{
+		Title: "Sample | table",
+		Columns: []Column{
+			{Field: "name", Header: "Team"},
+			{Field: "rate", Header: "Rate", Decimals: 1},
+			{Field: "record.wins", Header: "W"},
+			{Field: "picks", Header: "Picks"},
+			{Field: "drop.name", Header: "Drop"},
+			{Field: "active", Header: "Active"},
+		},
+		Rows: []team{
+			{Name: "Alpha | FC", Rate: 61.25, Record: record{Wins: 3}, Picks: []int{1, 2}, Drop: &team{Name: "Wood"}, Active: true},
+			{Name: "Beta, \"The\" FC", Rate: 4, Record: record{Wins: 0}},
+		},
+	}
+}
+
+func golden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read golden (run with -update to create): %v", err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("%s mismatch\n--- got ---\n%s\n--- want ---\n%s", name, got, want)
+	}
+}
+
+func TestMarkdown_Golden(t *testing.T) {
+	got, err := Markdown(sampleTable())
+	if err != nil {
+		t.Fatal(err)
+	}
+	golden(t, "sample.md", got)
+}
+
+func TestCSV(t *testing.T) {
+	got, err := CSV(sampleTable())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "Team,Rate,W,Picks,Drop,Active\r\n" +
+		"Alpha | FC,61.2,3,\"1, 2\",Wood,yes\r\n" +
+		"\"Beta, \"\"The\"\" FC\",4.0,0,,,no\r\n"
+	if string(got) != want {
+		t.Errorf("CSV =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestTable_EmbeddedAndFormat(t *testing.T) {
+	tbl := Table{
+		Columns: []Column{
+			{Field: "name", Header: "Team"},
+			{Field: "extra", Header: "Extra", Format: func(v any) string { return fmt.Sprintf("#%d", v) }},
+			{Field: "tags", Header: "Tags"},
+		},
+		Rows: []*wrapped{{team: team{Name: "Gamma", Tags: []string{"a", "b"}}, Extra: 7}},
+	}
+	got, err := Markdown(tbl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	golden(t, "embedded.md", got)
+}
+
+func TestTable_Errors(t *testing.T) {
+	for name, tbl := range map[string]Table{
+		"not a slice":   {Rows: team{}},
+		"not structs":   {Rows: []int{1}},
+		"unknown field": {Columns: []Column{{Field: "goals"}}, Rows: []team{}},
+		"not a struct":  {Columns: []Column{{Field: "name.first"}}, Rows: []team{}},
+	} {
+		if _, err := Markdown(tbl); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for in, want := range map[string]string{"": FormatJSON, " JSON ": FormatJSON, "markdown": FormatMarkdown, "md": FormatMarkdown, "csv": FormatCSV} {
+		if got, err := ParseFormat(in); err != nil || got != want {
+			t.Errorf("ParseFormat(%q) = %q, %v, want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Error("ParseFormat(xml) succeeded")
+	}
+}
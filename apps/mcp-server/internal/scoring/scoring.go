@@ -0,0 +1,189 @@
+// Package scoring holds the rules that turn a player's stats into FPL
+// points. Default is the official FPL scoring; a league that plays with
+// different values overrides just the fields that differ, from the server's
+// scoring config file or its own league details.
+package scoring
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+)
+
+// PositionPoints is a value per position type (1=GK, 2=DEF, 3=MID, 4=FWD).
+type PositionPoints struct {
+	GK  int `json:"gk"`
+	DEF int `json:"def"`
+	MID int `json:"mid"`
+	FWD int `json:"fwd"`
+}
+
+// At returns the value for position type pos, or 0 for an unknown one.
+func (p PositionPoints) At(pos int) int {
+	switch pos {
+	case 1:
+		return p.GK
+	case 2:
+		return p.DEF
+	case 3:
+		return p.MID
+	case 4:
+		return p.FWD
+	}
+	return 0
+}
+
+// ScoringRules is a points value for every scoring event the live data
+// reports. Bonus is awarded by BPS rather than scored, so it isn't here.
+type ScoringRules struct {
+	// AppearanceUnder60 and Appearance60 are the points for playing at all
+	// and for playing 60 minutes or more; they don't stack.
+	AppearanceUnder60 int            `json:"appearance_under_60"`
+	Appearance60      int            `json:"appearance_60"`
+	Goal              PositionPoints `json:"goal"`
+	Assist            int            `json:"assist"`
+	// CleanSheet needs 60+ minutes.
+	CleanSheet PositionPoints `json:"clean_sheet"`
+	// GoalsConceded is scored once per ConcededPerPenalty goals conceded;
+	// it is zero or negative.
+	GoalsConceded      PositionPoints `json:"goals_conceded"`
+	ConcededPerPenalty int            `json:"conceded_per_penalty"`
+	// SavesPerPoint saves earn a GK one point.
+	SavesPerPoint int `json:"saves_per_point"`
+	PenaltySave   int `json:"penalty_save"`
+	PenaltyMiss   int `json:"penalty_miss"`
+	OwnGoal       int `json:"own_goal"`
+	YellowCard    int `json:"yellow_card"`
+	RedCard       int `json:"red_card"`
+	// DefensiveContribution is earned once per fixture by reaching the
+	// position's DefConThreshold; a zero threshold means it can't be earned.
+	DefensiveContribution int            `json:"defensive_contribution"`
+	DefConThreshold       PositionPoints `json:"defensive_contribution_threshold"`
+}
+
+// Default returns the official FPL scoring.
+func Default() ScoringRules {
+	return ScoringRules{
+		AppearanceUnder60:     1,
+		Appearance60:          2,
+		Goal:                  PositionPoints{GK: 10, DEF: 6, MID: 5, FWD: 4},
+		Assist:                3,
+		CleanSheet:            PositionPoints{GK: 4, DEF: 4, MID: 1},
+		GoalsConceded:         PositionPoints{GK: -1, DEF: -1},
+		ConcededPerPenalty:    2,
+		SavesPerPoint:         3,
+		PenaltySave:           5,
+		PenaltyMiss:           -2,
+		OwnGoal:               -2,
+		YellowCard:            -1,
+		RedCard:               -3,
+		DefensiveContribution: 2,
+		DefConThreshold:       PositionPoints{DEF: 10, MID: 12, FWD: 12},
+	}
+}
+
+// IsDefault reports whether r is the official FPL scoring.
+func (r ScoringRules) IsDefault() bool { return r == Default() }
+
+// Validate rejects rules that can't be scored with.
+func (r ScoringRules) Validate() error {
+	if r.ConcededPerPenalty <= 0 {
+		return fmt.Errorf("conceded_per_penalty must be positive, got %d", r.ConcededPerPenalty)
+	}
+	if r.SavesPerPoint <= 0 {
+		return fmt.Errorf("saves_per_point must be positive, got %d", r.SavesPerPoint)
+	}
+	return nil
+}
+
+// Apply returns base with the fields present in raw, a JSON object of
+// ScoringRules fields, overridden. Unknown fields are an error so a typo
+// doesn't silently keep the default.
+func Apply(base ScoringRules, raw []byte) (ScoringRules, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	r := base
+	if err := dec.Decode(&r); err != nil {
+		return ScoringRules{}, fmt.Errorf("scoring rules: %w", err)
+	}
+	if err := r.Validate(); err != nil {
+		return ScoringRules{}, fmt.Errorf("scoring rules: %w", err)
+	}
+	return r, nil
+}
+
+// Load reads scoring overrides from path on top of Default. A missing file
+// isn't an error: Default is returned with found false.
+func Load(path string) (rules ScoringRules, found bool, err error) {
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return Default(), false, nil
+	}
+	if err != nil {
+		return ScoringRules{}, false, err
+	}
+	r, err := Apply(Default(), raw)
+	if err != nil {
+		return ScoringRules{}, false, fmt.Errorf("%s: %w", path, err)
+	}
+	return r, true, nil
+}
+
+// StatLine is what a player did in one fixture.
+type StatLine struct {
+	Minutes               int
+	Goals                 int
+	Assists               int
+	CleanSheets           int
+	GoalsConceded         int
+	OwnGoals              int
+	PenaltiesSaved        int
+	PenaltiesMissed       int
+	YellowCards           int
+	RedCards              int
+	Saves                 int
+	DefensiveContribution int
+}
+
+// Points scores one fixture's stat line for a player at position type pos,
+// excluding bonus.
+func (r ScoringRules) Points(pos int, s StatLine) int {
+	if s.Minutes <= 0 {
+		return 0
+	}
+	pts := r.AppearanceUnder60
+	if s.Minutes >= 60 {
+		pts = r.Appearance60
+		pts += r.CleanSheet.At(pos) * s.CleanSheets
+	}
+	pts += r.Goal.At(pos) * s.Goals
+	pts += r.Assist * s.Assists
+	if r.ConcededPerPenalty > 0 {
+		pts += r.GoalsConceded.At(pos) * (s.GoalsConceded / r.ConcededPerPenalty)
+	}
+	if pos == 1 && r.SavesPerPoint > 0 {
+		pts += s.Saves / r.SavesPerPoint
+	}
+	pts += r.PenaltySave * s.PenaltiesSaved
+	pts += r.PenaltyMiss * s.PenaltiesMissed
+	pts += r.OwnGoal * s.OwnGoals
+	pts += r.YellowCard * s.YellowCards
+	pts += r.RedCard * s.RedCards
+	if t := r.DefConThreshold.At(pos); t > 0 && s.DefensiveContribution >= t {
+		pts += r.DefensiveContribution
+	}
+	return pts
+}
+
+// Adjustment is how many more points r awards a stat line than Default
+// does. Adding it to FPL's own total re-scores a player under r without
+// recomputing bonus; it is 0 under Default.
+func (r ScoringRules) Adjustment(pos int, s StatLine) int {
+	if r.IsDefault() {
+		return 0
+	}
+	return r.Points(pos, s) - Default().Points(pos, s)
+}
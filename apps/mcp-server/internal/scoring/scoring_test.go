@@ -0,0 +1,87 @@
+package scoring
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestDefaultPoints_Golden checks Default against FPL's own totals (less
+// bonus) for single-fixture stat lines.
+func TestDefaultPoints_Golden(t *testing.T) {
+	cases := []struct {
+		name string
+		pos  int
+		line StatLine
+		want int
+	}{
+		{"GK clean sheet, 7 saves, penalty save", 1, StatLine{Minutes: 90, CleanSheets: 1, Saves: 7, PenaltiesSaved: 1}, 2 + 4 + 2 + 5},
+		{"GK conceding 5", 1, StatLine{Minutes: 90, GoalsConceded: 5, Saves: 2}, 2 - 2},
+		{"DEF goal and defcon", 2, StatLine{Minutes: 90, Goals: 1, GoalsConceded: 1, DefensiveContribution: 10}, 2 + 6 + 2},
+		{"DEF own goal and red", 2, StatLine{Minutes: 55, OwnGoals: 1, RedCards: 1, GoalsConceded: 3}, 1 - 2 - 3 - 1},
+		{"MID brace, assist, yellow", 3, StatLine{Minutes: 90, Goals: 2, Assists: 1, CleanSheets: 1, YellowCards: 1}, 2 + 10 + 3 + 1 - 1},
+		{"MID defcon one short", 3, StatLine{Minutes: 90, DefensiveContribution: 11, GoalsConceded: 4}, 2},
+		{"FWD penalty miss off the bench", 4, StatLine{Minutes: 20, PenaltiesMissed: 1, CleanSheets: 1}, 1 - 2},
+		{"FWD hat-trick", 4, StatLine{Minutes: 88, Goals: 3, DefensiveContribution: 12}, 2 + 12 + 2},
+		{"unused sub", 2, StatLine{}, 0},
+	}
+	for _, tc := range cases {
+		if got := Default().Points(tc.pos, tc.line); got != tc.want {
+			t.Errorf("%s: %d points, want %d", tc.name, got, tc.want)
+		}
+		if adj := Default().Adjustment(tc.pos, tc.line); adj != 0 {
+			t.Errorf("%s: default adjustment %d, want 0", tc.name, adj)
+		}
+	}
+}
+
+func TestApply_PartialOverride(t *testing.T) {
+	r, err := Apply(Default(), []byte(`{"goal": {"mid": 6}, "yellow_card": -2}`))
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if r.Goal.MID != 6 || r.Goal.FWD != 4 || r.YellowCard != -2 || r.Assist != 3 {
+		t.Errorf("rules = %+v, want MID goals 6 and yellow -2 over the defaults", r)
+	}
+	if r.IsDefault() {
+		t.Error("IsDefault after an override")
+	}
+	line := StatLine{Minutes: 90, Goals: 1, YellowCards: 1}
+	if adj := r.Adjustment(3, line); adj != 1-1 {
+		t.Errorf("adjustment = %d, want 0 (+1 goal, -1 card)", adj)
+	}
+	if adj := r.Adjustment(4, line); adj != -1 {
+		t.Errorf("FWD adjustment = %d, want -1", adj)
+	}
+
+	for _, bad := range []string{`{"goals": {"mid": 6}}`, `{"saves_per_point": 0}`, `[1]`} {
+		if _, err := Apply(Default(), []byte(bad)); err == nil {
+			t.Errorf("Apply(%s) accepted", bad)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	r, found, err := Load(filepath.Join(dir, "scoring.json"))
+	if err != nil || found || !r.IsDefault() {
+		t.Errorf("missing file: %+v, %v, %v; want Default, not found", r, found, err)
+	}
+
+	path := filepath.Join(dir, "scoring.json")
+	if err := os.WriteFile(path, []byte(`{"clean_sheet": {"mid": 0}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	r, found, err = Load(path)
+	if err != nil || !found || r.CleanSheet.MID != 0 || r.CleanSheet.DEF != 4 {
+		t.Errorf("override file: %+v, %v, %v", r, found, err)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"assist": "three"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := Load(path); err == nil || !strings.Contains(err.Error(), path) {
+		t.Errorf("bad file: err = %v, want one naming the file", err)
+	}
+}
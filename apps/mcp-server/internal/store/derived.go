@@ -0,0 +1,251 @@
+package store
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// gzipExt is appended to a derived file's name when it is stored gzipped.
+const gzipExt = ".gz"
+
+// DerivedFormat controls how derived JSON files are encoded on disk. The zero
+// value writes pretty-printed, uncompressed JSON.
+type DerivedFormat struct {
+	// Compact writes JSON without indentation.
+	Compact bool
+	// Gzip compresses files and stores them as name.json.gz. It implies
+	// Compact: indentation is pure overhead inside a gzip stream.
+	Gzip bool
+	// IncludeLedger applies Compact and Gzip to the draft ledger and entry
+	// snapshots too. They stay pretty by default so they can be read by hand
+	// when ownership looks wrong.
+	IncludeLedger bool
+}
+
+// ledgerFormat is the format WriteLedgerJSON uses under f.
+func (f DerivedFormat) ledgerFormat() DerivedFormat {
+	if f.IncludeLedger {
+		return f
+	}
+	return DerivedFormat{}
+}
+
+var (
+	derivedMu     sync.RWMutex
+	derivedFormat DerivedFormat
+)
+
+// SetDerivedFormat sets the format used by WriteDerivedJSON and
+// WriteLedgerJSON for the rest of the process. Readers handle every format
+// regardless, so files written before a change stay readable.
+func SetDerivedFormat(f DerivedFormat) {
+	derivedMu.Lock()
+	derivedFormat = f
+	derivedMu.Unlock()
+}
+
+// CurrentDerivedFormat returns the format set by SetDerivedFormat.
+func CurrentDerivedFormat() DerivedFormat {
+	derivedMu.RLock()
+	defer derivedMu.RUnlock()
+	return derivedFormat
+}
+
+// WriteDerivedJSON writes v to path, a .json path under the derived root, in
+// the current derived format. With gzip on the file lands at path + ".gz".
+func WriteDerivedJSON(path string, v any) error {
+	return writeJSONFile(path, v, CurrentDerivedFormat())
+}
+
+// WriteLedgerJSON is WriteDerivedJSON for the draft ledger and entry
+// snapshots, which are only compacted when IncludeLedger is set.
+func WriteLedgerJSON(path string, v any) error {
+	return writeJSONFile(path, v, CurrentDerivedFormat().ledgerFormat())
+}
+
+func writeJSONFile(path string, v any, f DerivedFormat) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return writeJSONBytes(path, b, f)
+}
+
+// writeJSONBytes re-encodes the JSON document b in format f and writes it.
+func writeJSONBytes(path string, b []byte, f DerivedFormat) error {
+	var buf bytes.Buffer
+	var err error
+	if f.Compact || f.Gzip {
+		err = json.Compact(&buf, b)
+	} else {
+		err = json.Indent(&buf, b, "", "  ")
+	}
+	if err != nil {
+		return err
+	}
+	buf.WriteByte('\n')
+	return writeEncoded(path, buf.Bytes(), f.Gzip)
+}
+
+// writeEncoded writes b to path, or gzipped to path + ".gz", and removes the
+// other variant so a reader can never pick up a stale copy.
+func writeEncoded(path string, b []byte, gz bool) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	target, stale := path, path+gzipExt
+	if gz {
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		if _, err := zw.Write(b); err != nil {
+			return err
+		}
+		if err := zw.Close(); err != nil {
+			return err
+		}
+		b = buf.Bytes()
+		target, stale = stale, target
+	}
+	if err := writeFileAtomic(target, b); err != nil {
+		return err
+	}
+	if err := os.Remove(stale); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// writeFileAtomic writes b to a temporary file next to path and renames it
+// into place, so a concurrent reader sees either the old file or the whole
+// new one, never a partial write.
+func writeFileAtomic(path string, b []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	name := tmp.Name()
+	if _, err := tmp.Write(b); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(name)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(name)
+		return err
+	}
+	if err := os.Chmod(name, 0o644); err != nil {
+		_ = os.Remove(name)
+		return err
+	}
+	if err := os.Rename(name, path); err != nil {
+		_ = os.Remove(name)
+		return err
+	}
+	return nil
+}
+
+// ResolveDerived returns whichever of path and path + ".gz" exists. When
+// neither does, the error is path's own not-exist error.
+func ResolveDerived(path string) (string, error) {
+	_, err := os.Stat(path)
+	if err == nil || strings.HasSuffix(path, gzipExt) {
+		return path, err
+	}
+	if _, gzErr := os.Stat(path + gzipExt); gzErr == nil {
+		return path + gzipExt, nil
+	}
+	return path, err
+}
+
+// StatDerived is os.Stat for a derived file that may be stored gzipped.
+func StatDerived(path string) (fs.FileInfo, error) {
+	resolved, err := ResolveDerived(path)
+	if err != nil {
+		return nil, err
+	}
+	return os.Stat(resolved)
+}
+
+// ReadDerived reads a derived JSON file written in any DerivedFormat,
+// decompressing the .gz variant when that is the one on disk.
+func ReadDerived(path string) ([]byte, error) {
+	resolved, err := ResolveDerived(path)
+	if err != nil {
+		return nil, err
+	}
+	b, err := os.ReadFile(resolved)
+	if err != nil || !strings.HasSuffix(resolved, gzipExt) {
+		return b, err
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	return io.ReadAll(zr)
+}
+
+// RecompressStats reports what RecompressTree did.
+type RecompressStats struct {
+	Files       int
+	BytesBefore int64
+	BytesAfter  int64
+}
+
+// RecompressTree rewrites every derived JSON file under root in format f,
+// e.g. to gzip a tree written before --derived-gzip was turned on, or to
+// expand one back out. Files under ledger/ and snapshots/ get f's ledger
+// format, matching what WriteLedgerJSON would write.
+func RecompressTree(root string, f DerivedFormat) (RecompressStats, error) {
+	var stats RecompressStats
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		plain := strings.TrimSuffix(path, gzipExt)
+		if !strings.HasSuffix(plain, ".json") {
+			return nil
+		}
+		b, err := ReadDerived(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			// Both variants were listed and the other one's rewrite removed
+			// this copy.
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, plain)
+		if err != nil {
+			return err
+		}
+		format := f
+		if top := strings.SplitN(filepath.ToSlash(rel), "/", 2)[0]; top == "ledger" || top == "snapshots" {
+			format = f.ledgerFormat()
+		}
+		if err := writeJSONBytes(plain, b, format); err != nil {
+			return err
+		}
+		after, err := StatDerived(plain)
+		if err != nil {
+			return err
+		}
+		stats.Files++
+		stats.BytesBefore += info.Size()
+		stats.BytesAfter += after.Size()
+		return nil
+	})
+	return stats, err
+}
@@ -0,0 +1,236 @@
+package store
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func withFormat(t testing.TB, f DerivedFormat) {
+	t.Helper()
+	prev := CurrentDerivedFormat()
+	SetDerivedFormat(f)
+	t.Cleanup(func() { SetDerivedFormat(prev) })
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func TestWriteDerivedJSON_Formats(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "summary", "x.json")
+	v := map[string]any{"gw": 3, "players": []int{1, 2}}
+
+	cases := []struct {
+		name   string
+		format DerivedFormat
+		want   string
+		gz     bool
+	}{
+		{"Pretty", DerivedFormat{}, "{\n  \"gw\": 3,\n  \"players\": [\n    1,\n    2\n  ]\n}\n", false},
+		{"Compact", DerivedFormat{Compact: true}, "{\"gw\":3,\"players\":[1,2]}\n", false},
+		{"Gzip", DerivedFormat{Gzip: true}, "{\"gw\":3,\"players\":[1,2]}\n", true},
+		// Switching back removes the .gz copy written by the previous case.
+		{"BackToPretty", DerivedFormat{}, "{\n  \"gw\": 3,\n  \"players\": [\n    1,\n    2\n  ]\n}\n", false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			withFormat(t, c.format)
+			if err := WriteDerivedJSON(path, v); err != nil {
+				t.Fatalf("WriteDerivedJSON: %v", err)
+			}
+			if exists(path) == c.gz || exists(path+".gz") != c.gz {
+				t.Fatalf("plain=%v gz=%v, want only the gz=%v variant", exists(path), exists(path+".gz"), c.gz)
+			}
+			got, err := ReadDerived(path)
+			if err != nil {
+				t.Fatalf("ReadDerived: %v", err)
+			}
+			if string(got) != c.want {
+				t.Errorf("read back %q, want %q", got, c.want)
+			}
+			if _, err := StatDerived(path); err != nil {
+				t.Errorf("StatDerived: %v", err)
+			}
+		})
+	}
+}
+
+func TestWriteLedgerJSON_PrettyUnlessIncluded(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "ledger", "1", "event_0.json")
+
+	withFormat(t, DerivedFormat{Gzip: true})
+	if err := WriteLedgerJSON(path, map[string]int{"a": 1}); err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := os.ReadFile(path); !bytes.Contains(b, []byte("\n  \"a\"")) {
+		t.Errorf("ledger = %q, want pretty plain JSON", b)
+	}
+
+	SetDerivedFormat(DerivedFormat{Gzip: true, IncludeLedger: true})
+	if err := WriteLedgerJSON(path, map[string]int{"a": 1}); err != nil {
+		t.Fatal(err)
+	}
+	if exists(path) || !exists(path+".gz") {
+		t.Errorf("IncludeLedger: plain=%v gz=%v, want gz only", exists(path), exists(path+".gz"))
+	}
+}
+
+func TestWriteDerivedJSON_ConcurrentReadersSeeWholeFiles(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "summary", "x.json")
+	if err := WriteDerivedJSON(path, []int{}); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func(n int) {
+			defer wg.Done()
+			// Documents of different sizes so a torn write would not parse.
+			if err := WriteDerivedJSON(path, make([]int, n*500)); err != nil {
+				t.Errorf("write: %v", err)
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			b, err := ReadDerived(path)
+			if err != nil {
+				t.Errorf("read: %v", err)
+				return
+			}
+			if !json.Valid(b) {
+				t.Errorf("read a partial file (%d bytes)", len(b))
+			}
+		}()
+	}
+	wg.Wait()
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "x.json" {
+		names := make([]string, 0, len(entries))
+		for _, e := range entries {
+			names = append(names, e.Name())
+		}
+		t.Errorf("summary dir = %v, want only x.json", names)
+	}
+}
+
+func TestReadDerived_Missing(t *testing.T) {
+	if _, err := ReadDerived(filepath.Join(t.TempDir(), "nope.json")); !os.IsNotExist(err) {
+		t.Errorf("err = %v, want not-exist", err)
+	}
+}
+
+func TestRecompressTree(t *testing.T) {
+	dir := t.TempDir()
+	summaryPath := filepath.Join(dir, "summary", "form", "h5.json")
+	snapPath := filepath.Join(dir, "snapshots", "1", "entry", "2", "gw", "3.json")
+	notesPath := filepath.Join(dir, "notes.txt")
+	for _, p := range []string{summaryPath, snapPath} {
+		if err := WriteDerivedJSON(p, map[string]any{"path": filepath.Base(p), "n": []int{1, 2, 3}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(notesPath, []byte("leave me"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err := RecompressTree(dir, DerivedFormat{Gzip: true})
+	if err != nil {
+		t.Fatalf("RecompressTree: %v", err)
+	}
+	if stats.Files != 2 {
+		t.Errorf("files = %d, want 2", stats.Files)
+	}
+	if exists(summaryPath) || !exists(summaryPath+".gz") {
+		t.Error("summary should be gzipped")
+	}
+	if !exists(snapPath) || exists(snapPath+".gz") {
+		t.Error("snapshot should stay plain without IncludeLedger")
+	}
+	if b, _ := os.ReadFile(notesPath); string(b) != "leave me" {
+		t.Errorf("non-JSON file rewritten: %q", b)
+	}
+
+	// And back out again.
+	if _, err := RecompressTree(dir, DerivedFormat{}); err != nil {
+		t.Fatalf("RecompressTree: %v", err)
+	}
+	if !exists(summaryPath) || exists(summaryPath+".gz") {
+		t.Error("summary should be plain again")
+	}
+	if b, err := ReadDerived(summaryPath); err != nil || !bytes.Contains(b, []byte(`"path": "h5.json"`)) {
+		t.Errorf("summary after round trip = %q (%v)", b, err)
+	}
+}
+
+// playerFormFixture resembles a player_form summary: 700 players, each with a
+// handful of numeric fields.
+func playerFormFixture() map[string]any {
+	players := make([]map[string]any, 700)
+	for i := range players {
+		players[i] = map[string]any{
+			"element": i + 1, "name": fmt.Sprintf("Player %d", i+1), "team": "ARS",
+			"position_type": i%4 + 1, "minutes": 450 - i%90, "points": i % 40,
+			"points_per_gw": float64(i%40) / 5, "minutes_per_gw": 90 - float64(i%90)/5,
+			"xa_per90": 0.123, "bonus_per_gw": 0.4, "ownership": i % 10,
+			"ownership_pct": 0.1, "risk_score": 0.25, "minutes_pattern": "nailed",
+			"last_minutes": []int{90, 90, 88, 90, 72},
+		}
+	}
+	return map[string]any{"league_id": 1, "as_of_gw": 10, "horizon": 5, "players": players}
+}
+
+// BenchmarkDerivedFormats writes and reads back one player_form-sized file in
+// each format, reporting its size on disk. For this fixture pretty JSON is
+// about 320KB, compact 195KB and gzipped 7KB (real data compresses less than
+// these repeated rows). Reads take roughly 0.15ms, 0.08ms and 0.37ms: gzip
+// costs a few hundred microseconds per summary load.
+func BenchmarkDerivedFormats(b *testing.B) {
+	v := playerFormFixture()
+	for _, c := range []struct {
+		name   string
+		format DerivedFormat
+	}{
+		{"pretty", DerivedFormat{}},
+		{"compact", DerivedFormat{Compact: true}},
+		{"gzip", DerivedFormat{Gzip: true}},
+	} {
+		path := filepath.Join(b.TempDir(), "h5.json")
+		if err := writeJSONFile(path, v, c.format); err != nil {
+			b.Fatal(err)
+		}
+		info, err := StatDerived(path)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.Run("write/"+c.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if err := writeJSONFile(path, v, c.format); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(info.Size()), "bytes/file")
+		})
+		b.Run("read/"+c.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := ReadDerived(path); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(info.Size()), "bytes/file")
+		})
+	}
+}
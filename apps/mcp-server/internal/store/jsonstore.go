@@ -4,16 +4,62 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"sync"
+	"time"
 )
 
 type JSONStore struct {
 	Root string // e.g. "data/raw"
+
+	// inputs tracks the newest file read through the store; nil for a
+	// store not made by NewJSONStore.
+	inputs *inputTracker
+}
+
+type inputTracker struct {
+	mu     sync.Mutex
+	latest time.Time
 }
 
 func NewJSONStore(root string) *JSONStore {
-	return &JSONStore{Root: root}
+	return &JSONStore{Root: root, inputs: &inputTracker{}}
+}
+
+// note records info's mtime as an input's.
+func (s *JSONStore) note(info fs.FileInfo) {
+	if s.inputs == nil || info == nil {
+		return
+	}
+	s.inputs.mu.Lock()
+	if info.ModTime().After(s.inputs.latest) {
+		s.inputs.latest = info.ModTime()
+	}
+	s.inputs.mu.Unlock()
+}
+
+// LatestInput is the newest modification time among the files read through
+// the store (ReadRaw or Stat), or the zero time before any. Whatever was
+// built from those reads reflects the data as of then.
+func (s *JSONStore) LatestInput() time.Time {
+	if s.inputs == nil {
+		return time.Time{}
+	}
+	s.inputs.mu.Lock()
+	defer s.inputs.mu.Unlock()
+	return s.inputs.latest
+}
+
+// Stat is os.Stat of rel, counted as a read for LatestInput. Callers that
+// cache a parse and only stat the file to validate it use this.
+func (s *JSONStore) Stat(rel string) (fs.FileInfo, error) {
+	info, err := os.Stat(s.Path(rel))
+	if err == nil {
+		s.note(info)
+	}
+	return info, err
 }
 
 func (s *JSONStore) Path(rel string) string {
@@ -51,5 +97,10 @@ func (s *JSONStore) ReadRaw(rel string) ([]byte, error) {
 	if errors.Is(err, os.ErrNotExist) {
 		return nil, err
 	}
+	if err == nil && s.inputs != nil {
+		if info, statErr := os.Stat(path); statErr == nil {
+			s.note(info)
+		}
+	}
 	return b, err
 }
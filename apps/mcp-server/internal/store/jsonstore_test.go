@@ -0,0 +1,55 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestJSONStore_LatestInput(t *testing.T) {
+	root := t.TempDir()
+	st := NewJSONStore(root)
+	older := time.Date(2025, 10, 1, 12, 0, 0, 0, time.UTC)
+	newer := older.Add(3 * time.Hour)
+	for rel, mtime := range map[string]time.Time{"a.json": older, "b.json": newer} {
+		path := filepath.Join(root, rel)
+		if err := os.WriteFile(path, []byte(`{}`), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got := st.LatestInput(); !got.IsZero() {
+		t.Errorf("before any read = %v, want zero", got)
+	}
+	if _, err := st.ReadRaw("a.json"); err != nil {
+		t.Fatal(err)
+	}
+	if got := st.LatestInput(); !got.Equal(older) {
+		t.Errorf("after a.json = %v, want %v", got, older)
+	}
+	// A stat counts, and a missing file doesn't.
+	if _, err := st.Stat("b.json"); err != nil {
+		t.Fatal(err)
+	}
+	_, _ = st.ReadRaw("missing.json")
+	if got := st.LatestInput(); !got.Equal(newer) {
+		t.Errorf("after b.json = %v, want %v", got, newer)
+	}
+	// Reading the older file again doesn't move it back.
+	_, _ = st.ReadRaw("a.json")
+	if got := st.LatestInput(); !got.Equal(newer) {
+		t.Errorf("after re-reading a.json = %v, want %v", got, newer)
+	}
+
+	// Each store tracks its own reads.
+	if got := NewJSONStore(root).LatestInput(); !got.IsZero() {
+		t.Errorf("fresh store = %v, want zero", got)
+	}
+	if got := (&JSONStore{Root: root}).LatestInput(); !got.IsZero() {
+		t.Errorf("literal store = %v, want zero", got)
+	}
+}
@@ -0,0 +1,173 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+)
+
+// A raw or derived root either holds one season directly (the flat layout,
+// data/raw/bootstrap/...) or one directory per season named like "2025-26"
+// (data/raw/2025-26/bootstrap/...). The flat layout is read as the current
+// season as long as no season directories exist; once any do, the newest is
+// current.
+
+var seasonPattern = regexp.MustCompile(`^\d{4}-\d{2}$`)
+
+// ValidSeason reports whether s is a season label such as "2025-26".
+func ValidSeason(s string) bool {
+	if !seasonPattern.MatchString(s) {
+		return false
+	}
+	var start, end int
+	if _, err := fmt.Sscanf(s, "%d-%d", &start, &end); err != nil {
+		return false
+	}
+	return (start+1)%100 == end
+}
+
+// SeasonForDate returns the label of the season t falls in. Seasons roll
+// over on 1 July, between the final GW and the next pre-season.
+func SeasonForDate(t time.Time) string {
+	start := t.Year()
+	if t.Month() < time.July {
+		start--
+	}
+	return fmt.Sprintf("%d-%02d", start, (start+1)%100)
+}
+
+// Seasons lists the season directories under root, oldest first.
+func Seasons(root string) []string {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil
+	}
+	out := make([]string, 0)
+	for _, e := range entries {
+		if e.IsDir() && ValidSeason(e.Name()) {
+			out = append(out, e.Name())
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// CurrentSeason returns the newest season directory under root, or "" for
+// the flat layout. filepath.Join(root, CurrentSeason(root)) is the current
+// season's directory either way.
+func CurrentSeason(root string) string {
+	seasons := Seasons(root)
+	if len(seasons) == 0 {
+		return ""
+	}
+	return seasons[len(seasons)-1]
+}
+
+// IsFlat reports whether root holds a flat-layout tree: some data and no
+// season directories.
+func IsFlat(root string) bool {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return false
+	}
+	flat := false
+	for _, e := range entries {
+		if e.IsDir() && ValidSeason(e.Name()) {
+			return false
+		}
+		flat = true
+	}
+	return flat
+}
+
+// FlatSeason returns the season a flat raw tree holds, judged from the
+// earliest GW deadline in its bootstrap-static.json. ok is false when root
+// is not flat or the bootstrap has no deadlines.
+func FlatSeason(root string) (string, bool) {
+	if !IsFlat(root) {
+		return "", false
+	}
+	raw, err := os.ReadFile(filepath.Join(root, "bootstrap", "bootstrap-static.json"))
+	if err != nil {
+		return "", false
+	}
+	var bs struct {
+		Events struct {
+			Data []struct {
+				DeadlineTime string `json:"deadline_time"`
+			} `json:"data"`
+		} `json:"events"`
+	}
+	if err := json.Unmarshal(raw, &bs); err != nil {
+		return "", false
+	}
+	var first time.Time
+	for _, ev := range bs.Events.Data {
+		t, err := time.Parse(time.RFC3339, ev.DeadlineTime)
+		if err != nil {
+			continue
+		}
+		if first.IsZero() || t.Before(first) {
+			first = t
+		}
+	}
+	if first.IsZero() {
+		return "", false
+	}
+	return SeasonForDate(first), true
+}
+
+// SeasonRoot returns the directory holding season's data under root. An
+// existing season directory always wins; in the flat layout, current (the
+// label of the season being played) maps to root itself. Anything else is
+// reported as not existing.
+func SeasonRoot(root string, season string, current string) (string, error) {
+	dir := filepath.Join(root, season)
+	if info, err := os.Stat(dir); err == nil && info.IsDir() {
+		return dir, nil
+	}
+	if season == current && len(Seasons(root)) == 0 {
+		return root, nil
+	}
+	return "", fmt.Errorf("season %s under %s: %w", season, root, os.ErrNotExist)
+}
+
+// MigrateToSeason moves a flat-layout tree under root into root/season,
+// leaving any season directories where they are. It returns how many
+// top-level entries were moved; a root that doesn't exist moves nothing.
+func MigrateToSeason(root string, season string) (int, error) {
+	if !ValidSeason(season) {
+		return 0, fmt.Errorf("invalid season %q", season)
+	}
+	entries, err := os.ReadDir(root)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	dest := filepath.Join(root, season)
+	moved := 0
+	for _, e := range entries {
+		if e.IsDir() && ValidSeason(e.Name()) {
+			continue
+		}
+		if moved == 0 {
+			if err := os.MkdirAll(dest, 0o755); err != nil {
+				return 0, err
+			}
+		}
+		if _, err := os.Stat(filepath.Join(dest, e.Name())); err == nil {
+			return moved, fmt.Errorf("migrate to season %s: %s already exists", season, filepath.Join(dest, e.Name()))
+		}
+		if err := os.Rename(filepath.Join(root, e.Name()), filepath.Join(dest, e.Name())); err != nil {
+			return moved, err
+		}
+		moved++
+	}
+	return moved, nil
+}
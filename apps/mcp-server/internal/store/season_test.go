@@ -0,0 +1,109 @@
+package store
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, path string, body string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSeasonLabels(t *testing.T) {
+	for _, c := range []struct {
+		date time.Time
+		want string
+	}{
+		{time.Date(2025, 8, 15, 0, 0, 0, 0, time.UTC), "2025-26"},
+		{time.Date(2026, 5, 24, 0, 0, 0, 0, time.UTC), "2025-26"},
+		{time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC), "2026-27"},
+		{time.Date(1999, 9, 1, 0, 0, 0, 0, time.UTC), "1999-00"},
+	} {
+		if got := SeasonForDate(c.date); got != c.want {
+			t.Errorf("SeasonForDate(%s) = %s, want %s", c.date.Format("2006-01-02"), got, c.want)
+		}
+	}
+	for s, want := range map[string]bool{"2025-26": true, "1999-00": true, "2025-27": false, "2025": false, "gw": false, "2025-26x": false} {
+		if got := ValidSeason(s); got != want {
+			t.Errorf("ValidSeason(%q) = %v, want %v", s, got, want)
+		}
+	}
+}
+
+func TestSeasonRoot_FlatLayout(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "bootstrap", "bootstrap-static.json"),
+		`{"events": {"data": [{"id": 2, "deadline_time": "2025-08-22T17:30:00Z"}, {"id": 1, "deadline_time": "2025-08-15T17:30:00Z"}]}}`)
+
+	if !IsFlat(root) || CurrentSeason(root) != "" {
+		t.Fatalf("flat=%v current=%q, want a flat tree with no current season dir", IsFlat(root), CurrentSeason(root))
+	}
+	if s, ok := FlatSeason(root); !ok || s != "2025-26" {
+		t.Errorf("FlatSeason = %q, %v, want 2025-26 from the GW1 deadline", s, ok)
+	}
+	if dir, err := SeasonRoot(root, "2025-26", "2025-26"); err != nil || dir != root {
+		t.Errorf("current season = %q, %v, want the root itself", dir, err)
+	}
+	if _, err := SeasonRoot(root, "2024-25", "2025-26"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("past season in flat layout: err = %v, want not-exist", err)
+	}
+}
+
+func TestSeasonRoot_SeasonLayout(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "2024-25", "game", "game.json"), `{}`)
+	writeFile(t, filepath.Join(root, "2025-26", "game", "game.json"), `{}`)
+
+	if IsFlat(root) || CurrentSeason(root) != "2025-26" {
+		t.Fatalf("flat=%v current=%q, want season dirs with 2025-26 current", IsFlat(root), CurrentSeason(root))
+	}
+	if _, ok := FlatSeason(root); ok {
+		t.Error("FlatSeason ok for a season layout")
+	}
+	if dir, err := SeasonRoot(root, "2024-25", "2026-27"); err != nil || dir != filepath.Join(root, "2024-25") {
+		t.Errorf("archived season = %q, %v", dir, err)
+	}
+	// With season dirs present the root is no longer read as the current season.
+	if _, err := SeasonRoot(root, "2026-27", "2026-27"); err == nil {
+		t.Error("missing current season dir resolved to the root")
+	}
+}
+
+func TestMigrateToSeason(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "game", "game.json"), `{"current_event": 38}`)
+	writeFile(t, filepath.Join(root, "league", "1", "details.json"), `{}`)
+	writeFile(t, filepath.Join(root, "2023-24", "game", "game.json"), `{}`)
+
+	n, err := MigrateToSeason(root, "2024-25")
+	if err != nil || n != 2 {
+		t.Fatalf("MigrateToSeason = %d, %v, want 2 entries moved", n, err)
+	}
+	if !exists(filepath.Join(root, "2024-25", "game", "game.json")) || !exists(filepath.Join(root, "2024-25", "league", "1", "details.json")) {
+		t.Error("flat files not under 2024-25")
+	}
+	if exists(filepath.Join(root, "game")) || !exists(filepath.Join(root, "2023-24", "game", "game.json")) {
+		t.Error("flat copy left behind or existing season moved")
+	}
+	if got := Seasons(root); len(got) != 2 || got[0] != "2023-24" || got[1] != "2024-25" {
+		t.Errorf("seasons = %v", got)
+	}
+
+	// A second flat tree can't overwrite an archived season.
+	writeFile(t, filepath.Join(root, "game", "game.json"), `{}`)
+	if _, err := MigrateToSeason(root, "2024-25"); err == nil {
+		t.Error("migrating over an existing season dir succeeded")
+	}
+	if n, err := MigrateToSeason(filepath.Join(root, "missing"), "2024-25"); err != nil || n != 0 {
+		t.Errorf("missing root = %d, %v, want a no-op", n, err)
+	}
+}
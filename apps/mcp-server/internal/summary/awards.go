@@ -0,0 +1,181 @@
+package summary
+
+import (
+	"errors"
+	"io/fs"
+	"sort"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+)
+
+// premiumCount is how many of the season's top scorers count as premium
+// for the bust award.
+const premiumCount = 30
+
+// ManagerAward is an entry singled out for its GW score.
+type ManagerAward struct {
+	EntryID   int    `json:"entry_id"`
+	EntryName string `json:"entry_name"`
+	Points    int    `json:"points"`
+}
+
+// PlayerAward is a player singled out for his GW score, with the entry whose
+// XI or bench he was in.
+type PlayerAward struct {
+	Element   int    `json:"element"`
+	Name      string `json:"name"`
+	Team      string `json:"team"`
+	Points    int    `json:"points"`
+	EntryID   int    `json:"entry_id"`
+	EntryName string `json:"entry_name"`
+}
+
+// VictoryAward is a won H2H match and its margin.
+type VictoryAward struct {
+	EntryID      int    `json:"entry_id"`
+	EntryName    string `json:"entry_name"`
+	OpponentID   int    `json:"opponent_entry_id"`
+	OpponentName string `json:"opponent_name"`
+	ScoreFor     int    `json:"score_for"`
+	ScoreAgainst int    `json:"score_against"`
+	Margin       int    `json:"margin"`
+}
+
+// WeekAwards are the GW's league awards. Every award lists all tied
+// winners, and is empty when nobody qualifies. MVP is the best starter,
+// Bust the worst starter among the season's top scorers before the GW, and
+// BenchHero the best benched player. Entries without a lineup (not yet in
+// the league) or with a reconstructed one are left out of the player awards.
+type WeekAwards struct {
+	TopScorer        []ManagerAward `json:"top_scorer"`
+	LowestScorer     []ManagerAward `json:"lowest_scorer"`
+	MVP              []PlayerAward  `json:"mvp"`
+	Bust             []PlayerAward  `json:"bust"`
+	BenchHero        []PlayerAward  `json:"bench_hero"`
+	NarrowestVictory []VictoryAward `json:"narrowest_victory"`
+}
+
+// buildWeekAwards picks the awards from the GW's entry summaries, in entry
+// order. premium is the set of premium players; reconstructed marks entries
+// whose roster was guessed.
+func buildWeekAwards(entries []ManagerWeekSummary, premium map[int]bool, reconstructed map[int]bool) WeekAwards {
+	out := WeekAwards{
+		TopScorer:        []ManagerAward{},
+		LowestScorer:     []ManagerAward{},
+		MVP:              []PlayerAward{},
+		Bust:             []PlayerAward{},
+		BenchHero:        []PlayerAward{},
+		NarrowestVictory: []VictoryAward{},
+	}
+	for _, e := range entries {
+		if e.MissingSnapshot {
+			continue
+		}
+		m := ManagerAward{EntryID: e.EntryID, EntryName: e.EntryName, Points: e.Points.Starters}
+		out.TopScorer = keepTied(out.TopScorer, m, managerPoints, true)
+		out.LowestScorer = keepTied(out.LowestScorer, m, managerPoints, false)
+
+		if e.Result == "W" && !e.MissingOpponent {
+			v := VictoryAward{
+				EntryID:      e.EntryID,
+				EntryName:    e.EntryName,
+				OpponentID:   e.OpponentID,
+				OpponentName: e.OpponentName,
+				ScoreFor:     e.ScoreFor,
+				ScoreAgainst: e.ScoreAgainst,
+				Margin:       e.ScoreFor - e.ScoreAgainst,
+			}
+			out.NarrowestVictory = keepTied(out.NarrowestVictory, v, func(v VictoryAward) int { return v.Margin }, false)
+		}
+
+		if reconstructed[e.EntryID] {
+			continue
+		}
+		for _, p := range e.Roster {
+			a := PlayerAward{Element: p.Element, Name: p.Name, Team: p.Team, Points: p.Points, EntryID: e.EntryID, EntryName: e.EntryName}
+			if p.Role != "starter" {
+				out.BenchHero = keepTied(out.BenchHero, a, playerPoints, true)
+				continue
+			}
+			out.MVP = keepTied(out.MVP, a, playerPoints, true)
+			if premium[p.Element] {
+				out.Bust = keepTied(out.Bust, a, playerPoints, false)
+			}
+		}
+	}
+	return out
+}
+
+func managerPoints(a ManagerAward) int { return a.Points }
+func playerPoints(a PlayerAward) int   { return a.Points }
+
+// keepTied folds candidate c into the current winners best: it replaces
+// them when it beats their score (higher when most is set, lower otherwise),
+// joins them on a tie and is dropped otherwise.
+func keepTied[T any](best []T, c T, scoreOf func(T) int, most bool) []T {
+	if len(best) == 0 {
+		return []T{c}
+	}
+	score, cur := scoreOf(c), scoreOf(best[0])
+	switch {
+	case score == cur:
+		return append(best, c)
+	case (score > cur) == most:
+		return []T{c}
+	}
+	return best
+}
+
+// topSeasonScorers returns the n highest season totals in points, plus
+// anyone tied with the last of them.
+func topSeasonScorers(points map[int]int, n int) map[int]bool {
+	ids := make([]int, 0, len(points))
+	for id := range points {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if points[ids[i]] != points[ids[j]] {
+			return points[ids[i]] > points[ids[j]]
+		}
+		return ids[i] < ids[j]
+	})
+	out := make(map[int]bool, n)
+	for i, id := range ids {
+		if i >= n && points[id] != points[ids[n-1]] {
+			break
+		}
+		out[id] = true
+	}
+	return out
+}
+
+// seasonPointsBefore totals each player's points over the GWs before gw
+// that have live data. With none yet (GW1) it falls back to gw itself, so
+// the first week still has premiums to compare.
+func seasonPointsBefore(st *store.JSONStore, gw int) (map[int]int, error) {
+	out := make(map[int]int)
+	add := func(g int) error {
+		live, _, err := loadLiveStatsForPoints(st, g)
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		for id, s := range live {
+			out[id] += s.TotalPoints
+		}
+		return nil
+	}
+	for g := 1; g < gw; g++ {
+		if err := add(g); err != nil {
+			return nil, err
+		}
+	}
+	if len(out) == 0 {
+		if err := add(gw); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
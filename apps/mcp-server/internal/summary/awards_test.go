@@ -0,0 +1,160 @@
+package summary
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+)
+
+func TestBuildWeekAwards(t *testing.T) {
+	starter := func(element int, name string, pts int) RosterPlayer {
+		return RosterPlayer{Element: element, Name: name, Role: "starter", Points: pts}
+	}
+	bench := func(element int, name string, pts int) RosterPlayer {
+		return RosterPlayer{Element: element, Name: name, Role: "bench", Points: pts}
+	}
+	entries := []ManagerWeekSummary{
+		{
+			EntryID: 200, EntryName: "Alpha", OpponentID: 201, OpponentName: "Beta",
+			ScoreFor: 40, ScoreAgainst: 38, Result: "W", Points: PointsSummary{Starters: 40},
+			Roster: []RosterPlayer{starter(1, "Salah", 15), starter(2, "Haaland", 2), bench(3, "Mitoma", 9)},
+		},
+		{
+			EntryID: 201, EntryName: "Beta", OpponentID: 200, OpponentName: "Alpha",
+			ScoreFor: 38, ScoreAgainst: 40, Result: "L", Points: PointsSummary{Starters: 38},
+			Roster: []RosterPlayer{starter(4, "Palmer", 15), starter(5, "Saka", 2), bench(6, "Eze", 9)},
+		},
+		{
+			EntryID: 202, EntryName: "Gamma", OpponentID: 203, OpponentName: "Delta",
+			ScoreFor: 25, ScoreAgainst: 23, Result: "W", Points: PointsSummary{Starters: 25},
+			Roster: []RosterPlayer{starter(7, "Isak", 1)},
+		},
+		{
+			EntryID: 203, EntryName: "Delta", OpponentID: 202, OpponentName: "Gamma",
+			ScoreFor: 23, ScoreAgainst: 25, Result: "L", Points: PointsSummary{Starters: 23},
+			Roster: []RosterPlayer{starter(8, "Watkins", 30), bench(9, "Wissa", 20)},
+		},
+		// Joined later: scores zero but is no lowest scorer.
+		{EntryID: 204, EntryName: "Late", MissingSnapshot: true},
+	}
+	premium := map[int]bool{1: true, 2: true, 4: true, 5: true, 7: true}
+	// Delta's lineup is a guess, so its players win nothing.
+	reconstructed := map[int]bool{203: true}
+
+	a := buildWeekAwards(entries, premium, reconstructed)
+
+	ids := func(n int, get func(i int) int) []int {
+		out := make([]int, n)
+		for i := range out {
+			out[i] = get(i)
+		}
+		return out
+	}
+	check := func(name string, got []int, want ...int) {
+		t.Helper()
+		if len(got) != len(want) {
+			t.Errorf("%s = %v, want %v", name, got, want)
+			return
+		}
+		for i := range got {
+			if got[i] != want[i] {
+				t.Errorf("%s = %v, want %v", name, got, want)
+				return
+			}
+		}
+	}
+	check("top scorer", ids(len(a.TopScorer), func(i int) int { return a.TopScorer[i].EntryID }), 200)
+	check("lowest scorer", ids(len(a.LowestScorer), func(i int) int { return a.LowestScorer[i].EntryID }), 203)
+	// Salah and Palmer tie on 15; both are MVPs, each with his owner.
+	check("mvp", ids(len(a.MVP), func(i int) int { return a.MVP[i].Element }), 1, 4)
+	check("mvp owners", ids(len(a.MVP), func(i int) int { return a.MVP[i].EntryID }), 200, 201)
+	// Isak's 1 is the worst premium score.
+	check("bust", ids(len(a.Bust), func(i int) int { return a.Bust[i].Element }), 7)
+	check("bench hero", ids(len(a.BenchHero), func(i int) int { return a.BenchHero[i].Element }), 3, 6)
+	check("narrowest victory", ids(len(a.NarrowestVictory), func(i int) int { return a.NarrowestVictory[i].EntryID }), 200, 202)
+	if v := a.NarrowestVictory[0]; v.Margin != 2 || v.OpponentID != 201 || v.OpponentName != "Beta" {
+		t.Errorf("narrowest victory = %+v", v)
+	}
+	if a.MVP[1].EntryName != "Beta" || a.MVP[1].Name != "Palmer" {
+		t.Errorf("mvp = %+v", a.MVP[1])
+	}
+
+	// With nothing to award every list is empty, not null.
+	empty := buildWeekAwards(nil, nil, nil)
+	b, err := json.Marshal(empty)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"top_scorer":[],"lowest_scorer":[],"mvp":[],"bust":[],"bench_hero":[],"narrowest_victory":[]}`
+	if string(b) != want {
+		t.Errorf("empty awards = %s, want %s", b, want)
+	}
+}
+
+func TestTopSeasonScorers(t *testing.T) {
+	points := map[int]int{1: 50, 2: 40, 3: 40, 4: 30, 5: 10}
+	got := topSeasonScorers(points, 2)
+	// 3 ties 2 for second, so both are in.
+	if len(got) != 3 || !got[1] || !got[2] || !got[3] {
+		t.Errorf("top 2 = %v, want 1, 2 and 3", got)
+	}
+	if got := topSeasonScorers(points, 10); len(got) != 5 {
+		t.Errorf("top 10 of 5 = %v, want all", got)
+	}
+	if got := topSeasonScorers(nil, 30); len(got) != 0 {
+		t.Errorf("top of none = %v", got)
+	}
+}
+
+func TestBuildLeagueSummaries_Awards(t *testing.T) {
+	root := t.TempDir()
+	ld := writeIncrementalLeague(t, root)
+	st := store.NewJSONStore(root)
+	if err := BuildLeagueSummaries(st, root, 100, ld, []int{200, 201}, 1, 2, []int{5}, []string{"med"}, BuildOptions{Clock: testClock}); err != nil {
+		t.Fatalf("BuildLeagueSummaries: %v", err)
+	}
+	b, err := os.ReadFile(filepath.Join(root, "summary/league/100/gw/2.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var s LeagueWeekSummary
+	if err := json.Unmarshal(b, &s); err != nil {
+		t.Fatal(err)
+	}
+	a := s.Awards
+	// Both entries start Salah on 5, so they share the top and bottom.
+	if len(a.TopScorer) != 2 || len(a.LowestScorer) != 2 || len(a.MVP) != 2 || a.MVP[0].Name != "Salah" {
+		t.Errorf("awards = %+v, want every tie reported", a)
+	}
+	// Salah is a top scorer from GW1, so he's also the bust.
+	if len(a.Bust) != 2 || a.Bust[0].Points != 5 {
+		t.Errorf("bust = %+v", a.Bust)
+	}
+	if len(a.NarrowestVictory) != 1 || a.NarrowestVictory[0].EntryName != "Alpha FC" || a.NarrowestVictory[0].Margin != 2 {
+		t.Errorf("narrowest victory = %+v, want Alpha FC by 2", a.NarrowestVictory)
+	}
+	if len(a.BenchHero) != 0 {
+		t.Errorf("bench hero = %+v, want none without a bench", a.BenchHero)
+	}
+}
+
+func TestSeasonPointsBefore(t *testing.T) {
+	root := t.TempDir()
+	writeLiveJSON(t, root, 1, map[string]any{"1": map[string]any{"stats": map[string]any{"total_points": 6}}})
+	writeLiveJSON(t, root, 3, map[string]any{"1": map[string]any{"stats": map[string]any{"total_points": 4}}})
+	st := store.NewJSONStore(root)
+
+	// GW2 has no live file and is skipped.
+	got, err := seasonPointsBefore(st, 4)
+	if err != nil || got[1] != 10 {
+		t.Errorf("before GW4 = %v, %v; want 10", got, err)
+	}
+	// Nothing before GW1, so GW1 itself counts.
+	got, err = seasonPointsBefore(st, 1)
+	if err != nil || got[1] != 6 {
+		t.Errorf("before GW1 = %v, %v; want 6", got, err)
+	}
+}
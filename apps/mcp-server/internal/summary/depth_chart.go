@@ -0,0 +1,145 @@
+package summary
+
+import (
+	"errors"
+	"io/fs"
+	"math"
+	"sort"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+)
+
+const (
+	// depthHorizon is how many GWs up to the summary's GW the depth chart's
+	// points per GW average over.
+	depthHorizon = 5
+	// viableReplacementPPG is the points per GW a bench player needs to
+	// count as cover for an injured starter: a little above a 60-minute
+	// appearance.
+	viableReplacementPPG = 2.5
+)
+
+// DepthPlayer is one player in a position group of a depth chart.
+type DepthPlayer struct {
+	Element     int     `json:"element"`
+	Name        string  `json:"name"`
+	Team        string  `json:"team"`
+	PointsPerGW float64 `json:"points_per_gw"`
+	Starter     bool    `json:"starter"`
+}
+
+// PositionDepth is an entry's players at one position, best points per GW
+// first. Starters is how many of them the entry's best formation by points
+// per GW would start. StarterBenchGap is the worst starter's points per GW
+// less the best bench player's, nil when nobody is on the bench there.
+type PositionDepth struct {
+	Players         []DepthPlayer `json:"players"`
+	Starters        int           `json:"starters"`
+	StarterBenchGap *float64      `json:"starter_bench_gap,omitempty"`
+}
+
+// DepthChart is an entry's squad by position group.
+type DepthChart struct {
+	GK  PositionDepth `json:"gk"`
+	DEF PositionDepth `json:"def"`
+	MID PositionDepth `json:"mid"`
+	FWD PositionDepth `json:"fwd"`
+}
+
+// depthPositions are the position keys in position-type order (index 1-4),
+// matching the hoarders map.
+var depthPositions = [5]string{"", "gk", "def", "mid", "fwd"}
+
+// buildDepthChart ranks squad's players by ppg within each position and
+// marks the starters of the best legal formation by ppg. A squad that can't
+// fill a formation starts everyone it has. thin lists the positions (gk,
+// def, mid, fwd) where an injured starter has no bench cover of at least
+// viableReplacementPPG, which includes a position played by a single
+// starter and nobody else.
+func buildDepthChart(squad map[int]bool, meta map[int]PlayerMeta, ppg map[int]float64) (chart DepthChart, thin []string) {
+	byPos := make(map[int][]DepthPlayer)
+	for el := range squad {
+		m := meta[el]
+		byPos[m.PositionType] = append(byPos[m.PositionType], DepthPlayer{
+			Element:     el,
+			Name:        m.Name,
+			Team:        m.TeamShort,
+			PointsPerGW: round2(ppg[el]),
+		})
+	}
+	scores := make(map[int][]int, len(byPos))
+	for pos, players := range byPos {
+		sort.Slice(players, func(i, j int) bool {
+			if players[i].PointsPerGW != players[j].PointsPerGW {
+				return players[i].PointsPerGW > players[j].PointsPerGW
+			}
+			return players[i].Element < players[j].Element
+		})
+		for _, p := range players {
+			scores[pos] = append(scores[pos], int(math.Round(p.PointsPerGW*100)))
+		}
+	}
+	starters, _, ok := bestFormation(scores)
+
+	thin = make([]string, 0)
+	groups := [5]*PositionDepth{nil, &chart.GK, &chart.DEF, &chart.MID, &chart.FWD}
+	for pos := 1; pos <= 4; pos++ {
+		players := byPos[pos]
+		if players == nil {
+			players = []DepthPlayer{}
+		}
+		n := len(players)
+		if ok {
+			n = starters[pos]
+		}
+		for i := range players[:n] {
+			players[i].Starter = true
+		}
+		g := groups[pos]
+		*g = PositionDepth{Players: players, Starters: n}
+		if n == 0 {
+			continue
+		}
+		bench := players[n:]
+		if len(bench) > 0 {
+			gap := round2(players[n-1].PointsPerGW - bench[0].PointsPerGW)
+			g.StarterBenchGap = &gap
+		}
+		if len(bench) == 0 || bench[0].PointsPerGW < viableReplacementPPG {
+			thin = append(thin, depthPositions[pos])
+		}
+	}
+	return chart, thin
+}
+
+// recentPointsPerGW averages each player's points over the horizon GWs up
+// to gw, or since GW1 early in the season so the first weeks aren't all
+// thin. A GW without live data counts as zero.
+func recentPointsPerGW(st *store.JSONStore, gw int, horizon int) (map[int]float64, error) {
+	start := gw - horizon + 1
+	if start < 1 {
+		start = 1
+	}
+	totals := make(map[int]int)
+	for g := start; g <= gw; g++ {
+		live, _, err := loadLiveStatsForPoints(st, g)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		for id, s := range live {
+			totals[id] += s.TotalPoints
+		}
+	}
+	out := make(map[int]float64, len(totals))
+	for id, pts := range totals {
+		out[id] = float64(pts) / float64(gw-start+1)
+	}
+	return out, nil
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
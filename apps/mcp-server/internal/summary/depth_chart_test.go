@@ -0,0 +1,121 @@
+package summary
+
+import (
+	"testing"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+)
+
+func TestBuildOwnershipScarcity_DepthChart(t *testing.T) {
+	meta := map[int]PlayerMeta{}
+	ppg := map[int]float64{}
+	squads := map[int]map[int]bool{200: {}, 201: {}}
+	add := func(entryID int, el int, pos int, p float64) {
+		meta[el] = PlayerMeta{ID: el, Name: "P" + itoa(el), PositionType: pos}
+		ppg[el] = p
+		squads[entryID][el] = true
+	}
+	// Hoarder: six defenders, every one of them a 1-point player.
+	add(200, 1, 1, 4)
+	add(200, 2, 1, 3)
+	for el := 11; el <= 16; el++ {
+		add(200, el, 2, 1)
+	}
+	for i, p := range []float64{6, 5, 4, 3} {
+		add(200, 21+i, 3, p)
+	}
+	add(200, 31, 4, 5)
+	add(200, 32, 4, 4)
+	// Deep: one defender fewer, but a bench that can step in.
+	add(201, 3, 1, 4)
+	add(201, 4, 1, 3)
+	for i, p := range []float64{5, 4, 3, 3, 2.6} {
+		add(201, 41+i, 2, p)
+	}
+	for i, p := range []float64{6, 5, 4, 3, 2.8} {
+		add(201, 51+i, 3, p)
+	}
+	for i, p := range []float64{5, 3, 2} {
+		add(201, 61+i, 4, p)
+	}
+
+	s := buildOwnershipScarcity(100, 5, []int{200, 201}, map[int]string{200: "Hoarder", 201: "Deep"}, meta, squads, ppg)
+
+	// By count the hoarder is the deepest at DEF...
+	if h := s.Hoarders["def"]; h[0].EntryID != 200 || h[0].Count != 6 {
+		t.Fatalf("def hoarders = %+v", h)
+	}
+	// ...but losing any defender means starting another 1-pointer.
+	hoarder, deep := s.Entries[0], s.Entries[1]
+	if d := hoarder.DepthChart.DEF; d.Starters != 4 || len(d.Players) != 6 || d.StarterBenchGap == nil || *d.StarterBenchGap != 0 {
+		t.Errorf("hoarder def = %+v", d)
+	}
+	checkThin := func(name string, got []string, want ...string) {
+		t.Helper()
+		if len(got) != len(want) {
+			t.Errorf("%s thin = %v, want %v", name, got, want)
+			return
+		}
+		for i := range got {
+			if got[i] != want[i] {
+				t.Errorf("%s thin = %v, want %v", name, got, want)
+				return
+			}
+		}
+	}
+	// Every midfielder and forward starts, so neither has cover.
+	checkThin("hoarder", hoarder.ThinPositions, "def", "mid", "fwd")
+
+	// Deep plays 4-4-2 with 2.6 and 2.8 on the bench; only the third
+	// forward is too weak to cover.
+	def := deep.DepthChart.DEF
+	if def.Starters != 4 || !def.Players[3].Starter || def.Players[4].Starter || def.Players[4].Element != 45 {
+		t.Errorf("deep def = %+v", def)
+	}
+	if def.StarterBenchGap == nil || *def.StarterBenchGap != 0.4 {
+		t.Errorf("deep def gap = %v, want 0.4", def.StarterBenchGap)
+	}
+	if g := deep.DepthChart.GK; g.Starters != 1 || g.Players[0].Element != 3 || *g.StarterBenchGap != 1 {
+		t.Errorf("deep gk = %+v", g)
+	}
+	checkThin("deep", deep.ThinPositions, "fwd")
+}
+
+func TestBuildDepthChart_ShortSquad(t *testing.T) {
+	meta := map[int]PlayerMeta{1: {ID: 1, PositionType: 2}, 2: {ID: 2, PositionType: 2}}
+	chart, thin := buildDepthChart(map[int]bool{1: true, 2: true}, meta, map[int]float64{1: 2, 2: 7})
+
+	// No formation fits, so both start and nobody covers them.
+	if d := chart.DEF; d.Starters != 2 || d.Players[0].Element != 2 || d.StarterBenchGap != nil {
+		t.Errorf("def = %+v", d)
+	}
+	if len(thin) != 1 || thin[0] != "def" {
+		t.Errorf("thin = %v, want def", thin)
+	}
+	// Empty positions aren't thin: there is no starter to lose.
+	if chart.GK.Players == nil || len(chart.GK.Players) != 0 {
+		t.Errorf("gk = %+v, want an empty list", chart.GK)
+	}
+
+	_, thin = buildDepthChart(nil, nil, nil)
+	if thin == nil || len(thin) != 0 {
+		t.Errorf("empty squad thin = %v", thin)
+	}
+}
+
+func TestRecentPointsPerGW(t *testing.T) {
+	root := t.TempDir()
+	writeLiveJSON(t, root, 1, map[string]any{"1": map[string]any{"stats": map[string]any{"total_points": 6}}})
+	writeLiveJSON(t, root, 3, map[string]any{"1": map[string]any{"stats": map[string]any{"total_points": 4}}})
+	st := store.NewJSONStore(root)
+
+	// Only three GWs so far, and GW2 has no live file: a blank.
+	got, err := recentPointsPerGW(st, 3, 5)
+	if err != nil || got[1] != 10.0/3 {
+		t.Errorf("h5 at GW3 = %v, %v; want 10/3", got, err)
+	}
+	got, err = recentPointsPerGW(st, 3, 1)
+	if err != nil || got[1] != 4 {
+		t.Errorf("h1 at GW3 = %v, %v; want 4", got, err)
+	}
+}
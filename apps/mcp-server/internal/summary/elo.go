@@ -0,0 +1,191 @@
+package summary
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Elo defaults. K is the most a rating moves on an even match decided by
+// the smallest margin; MOVScaling sets how much more a bigger margin moves
+// it.
+const (
+	EloStartRating       = 1500.0
+	DefaultEloK          = 20.0
+	DefaultEloMOVScaling = 1.0
+)
+
+// eloMarginUnit is the margin, in points, that adds ln 2 × MOVScaling to the
+// multiplier: draft H2H margins run to tens of points, not goals.
+const eloMarginUnit = 10.0
+
+// EloParams tunes the rating engine.
+type EloParams struct {
+	K          float64 `json:"k_factor"`
+	MOVScaling float64 `json:"mov_scaling"`
+}
+
+// Validate rejects a K that isn't positive and a negative MOV scaling.
+func (p EloParams) Validate() error {
+	if p.K <= 0 {
+		return fmt.Errorf("k_factor must be above 0, got %g", p.K)
+	}
+	if p.MOVScaling < 0 {
+		return fmt.Errorf("mov_scaling must not be negative, got %g", p.MOVScaling)
+	}
+	return nil
+}
+
+// EloPoint is an entry's rating at the end of a GW.
+type EloPoint struct {
+	GW     int     `json:"gw"`
+	Rating float64 `json:"rating"`
+}
+
+// EloRow is one entry's rating history. PeakGW is 0 when the entry has
+// never been above its starting rating.
+type EloRow struct {
+	EntryID    int        `json:"entry_id"`
+	EntryName  string     `json:"entry_name"`
+	Rank       int        `json:"rank"`
+	Rating     float64    `json:"rating"`
+	Peak       float64    `json:"peak"`
+	PeakGW     int        `json:"peak_gw"`
+	Played     int        `json:"played"`
+	Trajectory []EloPoint `json:"trajectory"`
+}
+
+// EloMatchup is an upcoming match priced from the current ratings.
+// WinProb1 is entry 1's expected score, a draw counting half; the two
+// probabilities sum to 1.
+type EloMatchup struct {
+	GW         int     `json:"gw"`
+	EntryID1   int     `json:"entry_id_1"`
+	EntryName1 string  `json:"entry_name_1"`
+	Rating1    float64 `json:"rating_1"`
+	WinProb1   float64 `json:"win_prob_1"`
+	EntryID2   int     `json:"entry_id_2"`
+	EntryName2 string  `json:"entry_name_2"`
+	Rating2    float64 `json:"rating_2"`
+	WinProb2   float64 `json:"win_prob_2"`
+}
+
+// EloRatings is the manager_elo output.
+type EloRatings struct {
+	LeagueID  int          `json:"league_id"`
+	ThroughGW int          `json:"through_gw"`
+	Params    EloParams    `json:"params"`
+	Rows      []EloRow     `json:"rows"`
+	Upcoming  []EloMatchup `json:"upcoming"`
+}
+
+// EloExpected is the expected score of a side rated ra against one rated
+// rb: 1 / (1 + 10^((rb-ra)/400)).
+func EloExpected(ra float64, rb float64) float64 {
+	return 1 / (1 + math.Pow(10, (rb-ra)/400))
+}
+
+// EloMOVMultiplier scales K by the winning margin:
+// 1 + movScaling × ln(1 + |margin| / 10). A draw, or movScaling 0, leaves K
+// as is.
+func EloMOVMultiplier(margin int, movScaling float64) float64 {
+	return 1 + movScaling*math.Log1p(math.Abs(float64(margin))/eloMarginUnit)
+}
+
+// ComputeElo rates every entry from the finished matches in ld, GW by GW.
+// Everyone starts at EloStartRating; within a GW each match is priced from
+// the ratings the GW started with, then each side moves by
+// K × multiplier × (result - expected), so rating is only exchanged, never
+// created. Unfinished matches in the horizon GWs after the last finished
+// one (0 = all of them) are priced from the final ratings.
+func ComputeElo(leagueID int, ld LeagueDetails, params EloParams, horizon int) EloRatings {
+	type state struct {
+		row    *EloRow
+		rating float64
+	}
+	out := EloRatings{LeagueID: leagueID, Params: params, Rows: make([]EloRow, 0, len(ld.LeagueEntries)), Upcoming: make([]EloMatchup, 0)}
+	for _, e := range ld.LeagueEntries {
+		out.Rows = append(out.Rows, EloRow{EntryID: e.EntryID, EntryName: e.EntryName, Rating: EloStartRating, Peak: EloStartRating, Trajectory: make([]EloPoint, 0)})
+	}
+	byLeagueEntry := make(map[int]*state, len(ld.LeagueEntries))
+	for i, e := range ld.LeagueEntries {
+		byLeagueEntry[e.ID] = &state{row: &out.Rows[i], rating: EloStartRating}
+	}
+
+	finished := make(map[int][]int)
+	gws := make([]int, 0)
+	for i, m := range ld.Matches {
+		if !m.Finished {
+			continue
+		}
+		if _, ok := finished[m.Event]; !ok {
+			gws = append(gws, m.Event)
+		}
+		finished[m.Event] = append(finished[m.Event], i)
+	}
+	sort.Ints(gws)
+
+	for _, gw := range gws {
+		delta := make(map[int]float64)
+		for _, i := range finished[gw] {
+			m := ld.Matches[i]
+			a, b := byLeagueEntry[m.LeagueEntry1], byLeagueEntry[m.LeagueEntry2]
+			if a == nil || b == nil {
+				continue
+			}
+			score := 0.5
+			switch {
+			case m.LeagueEntry1Points > m.LeagueEntry2Points:
+				score = 1
+			case m.LeagueEntry1Points < m.LeagueEntry2Points:
+				score = 0
+			}
+			move := params.K * EloMOVMultiplier(m.LeagueEntry1Points-m.LeagueEntry2Points, params.MOVScaling) * (score - EloExpected(a.rating, b.rating))
+			delta[m.LeagueEntry1] += move
+			delta[m.LeagueEntry2] -= move
+			a.row.Played++
+			b.row.Played++
+		}
+		for id, s := range byLeagueEntry {
+			s.rating += delta[id]
+			r := round3(s.rating)
+			s.row.Trajectory = append(s.row.Trajectory, EloPoint{GW: gw, Rating: r})
+			if r > s.row.Peak {
+				s.row.Peak, s.row.PeakGW = r, gw
+			}
+		}
+		out.ThroughGW = gw
+	}
+	for _, s := range byLeagueEntry {
+		s.row.Rating = round3(s.rating)
+	}
+
+	for _, m := range ld.Matches {
+		if m.Finished || m.Event <= out.ThroughGW || (horizon > 0 && m.Event > out.ThroughGW+horizon) {
+			continue
+		}
+		a, b := byLeagueEntry[m.LeagueEntry1], byLeagueEntry[m.LeagueEntry2]
+		if a == nil || b == nil {
+			continue
+		}
+		p := round3(EloExpected(a.rating, b.rating))
+		out.Upcoming = append(out.Upcoming, EloMatchup{
+			GW:         m.Event,
+			EntryID1:   a.row.EntryID,
+			EntryName1: a.row.EntryName,
+			Rating1:    a.row.Rating,
+			WinProb1:   p,
+			EntryID2:   b.row.EntryID,
+			EntryName2: b.row.EntryName,
+			Rating2:    b.row.Rating,
+			WinProb2:   round3(1 - p),
+		})
+	}
+	sort.SliceStable(out.Upcoming, func(i, j int) bool { return out.Upcoming[i].GW < out.Upcoming[j].GW })
+
+	sort.SliceStable(out.Rows, func(i, j int) bool { return out.Rows[i].Rating > out.Rows[j].Rating })
+	for i := range out.Rows {
+		out.Rows[i].Rank = i + 1
+	}
+	return out
+}
@@ -0,0 +1,125 @@
+package summary
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+// eloLeague is entries 101-104 (A-D). GW1: A beats B by 10, C draws D.
+// GW2: A beats C by 5, B draws D. GW3 (A v D, B v C) and GW5 (A v B) are
+// still to play.
+func eloLeague(t *testing.T) LeagueDetails {
+	t.Helper()
+	m := func(gw int, finished bool, a int, pa int, b int, pb int) map[string]any {
+		return map[string]any{"event": gw, "finished": finished, "league_entry_1": a, "league_entry_1_points": pa, "league_entry_2": b, "league_entry_2_points": pb}
+	}
+	raw, _ := json.Marshal(map[string]any{
+		"league_entries": []map[string]any{
+			{"id": 1, "entry_id": 101, "entry_name": "A"},
+			{"id": 2, "entry_id": 102, "entry_name": "B"},
+			{"id": 3, "entry_id": 103, "entry_name": "C"},
+			{"id": 4, "entry_id": 104, "entry_name": "D"},
+		},
+		"matches": []map[string]any{
+			m(1, true, 1, 60, 2, 50), m(1, true, 3, 40, 4, 40),
+			m(2, true, 1, 55, 3, 50), m(2, true, 2, 45, 4, 45),
+			m(3, false, 1, 0, 4, 0), m(3, false, 2, 0, 3, 0),
+			m(5, false, 1, 0, 2, 0),
+		},
+	})
+	var ld LeagueDetails
+	if err := json.Unmarshal(raw, &ld); err != nil {
+		t.Fatal(err)
+	}
+	return ld
+}
+
+func TestEloMath(t *testing.T) {
+	if got := EloExpected(1600, 1500); math.Abs(got-0.64) > 0.001 {
+		t.Errorf("expected score for +100 = %.4f, want 0.640", got)
+	}
+	if got := EloMOVMultiplier(10, 1); math.Abs(got-(1+math.Ln2)) > 1e-9 {
+		t.Errorf("multiplier for a 10-point margin = %.4f, want 1 + ln 2", got)
+	}
+	if got := EloMOVMultiplier(0, 1); got != 1 {
+		t.Errorf("draw multiplier = %.4f, want 1", got)
+	}
+	if got := EloMOVMultiplier(40, 0); got != 1 {
+		t.Errorf("multiplier with scaling 0 = %.4f, want 1", got)
+	}
+}
+
+func TestComputeElo(t *testing.T) {
+	got := ComputeElo(1, eloLeague(t), EloParams{K: DefaultEloK, MOVScaling: DefaultEloMOVScaling}, 0)
+	if got.ThroughGW != 2 {
+		t.Fatalf("through_gw = %d, want 2", got.ThroughGW)
+	}
+	rows := make(map[string]EloRow)
+	for _, r := range got.Rows {
+		rows[r.EntryName] = r
+	}
+	// GW1: even ratings, so A takes 20 × (1 + ln 2) × 0.5 from B and the
+	// draw moves nothing. GW2: A, now the favourite, gains less for a
+	// narrower win; B gains from drawing the higher-rated D.
+	want := map[string][]float64{
+		"A": {1516.931, 1530.302},
+		"B": {1483.069, 1483.555},
+		"C": {1500, 1486.63},
+		"D": {1500, 1499.513},
+	}
+	sum := 0.0
+	for name, traj := range want {
+		r := rows[name]
+		if len(r.Trajectory) != 2 || r.Trajectory[0].Rating != traj[0] || r.Trajectory[1].Rating != traj[1] || r.Rating != traj[1] {
+			t.Errorf("%s trajectory = %+v rating %.3f, want %v", name, r.Trajectory, r.Rating, traj)
+		}
+		if r.Played != 2 {
+			t.Errorf("%s played = %d, want 2", name, r.Played)
+		}
+		sum += r.Rating
+	}
+	if math.Abs(sum-4*EloStartRating) > 0.01 {
+		t.Errorf("ratings sum to %.3f; Elo should only move rating between entries", sum)
+	}
+	if a := rows["A"]; a.Rank != 1 || a.Peak != 1530.302 || a.PeakGW != 2 {
+		t.Errorf("A = rank %d peak %.3f in GW%d, want 1st peaking at 1530.302 in GW2", a.Rank, a.Peak, a.PeakGW)
+	}
+	if b := rows["B"]; b.Rank != 4 || b.Peak != EloStartRating || b.PeakGW != 0 {
+		t.Errorf("B = rank %d peak %.3f in GW%d, want last, never above the start", b.Rank, b.Peak, b.PeakGW)
+	}
+
+	if len(got.Upcoming) != 3 {
+		t.Fatalf("upcoming = %+v, want GW3's two matches and GW5's", got.Upcoming)
+	}
+	if m := got.Upcoming[0]; m.GW != 3 || m.EntryName1 != "A" || m.WinProb1 != 0.544 || m.WinProb2 != 0.456 {
+		t.Errorf("A v D = %+v, want A at 0.544", m)
+	}
+	if m := got.Upcoming[1]; m.EntryName1 != "B" || m.WinProb1 != 0.496 {
+		t.Errorf("B v C = %+v, want B at 0.496", m)
+	}
+
+	near := ComputeElo(1, eloLeague(t), EloParams{K: DefaultEloK, MOVScaling: DefaultEloMOVScaling}, 1)
+	if len(near.Upcoming) != 2 {
+		t.Errorf("horizon 1 upcoming = %d matches, want GW3's 2", len(near.Upcoming))
+	}
+
+	// Without the margin multiplier GW1's move is exactly K/2.
+	flat := ComputeElo(1, eloLeague(t), EloParams{K: 20, MOVScaling: 0}, 0)
+	for _, r := range flat.Rows {
+		if r.EntryName == "A" && r.Trajectory[0].Rating != 1510 {
+			t.Errorf("A after GW1 with mov_scaling 0 = %.3f, want 1510", r.Trajectory[0].Rating)
+		}
+	}
+}
+
+func TestEloParamsValidate(t *testing.T) {
+	for _, p := range []EloParams{{K: 0, MOVScaling: 1}, {K: 20, MOVScaling: -1}} {
+		if p.Validate() == nil {
+			t.Errorf("%+v validated", p)
+		}
+	}
+	if err := (EloParams{K: 32}).Validate(); err != nil {
+		t.Errorf("K 32 without scaling: %v", err)
+	}
+}
@@ -0,0 +1,118 @@
+package summary
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// DefaultPythagoreanExponent is the k in PF^k / (PF^k + PA^k). Baseball's 2
+// undershoots for draft scores, whose weekly spread is narrow next to their
+// mean; 2.37 fits past H2H seasons better.
+const DefaultPythagoreanExponent = 2.37
+
+// Standings sort orders. Rank stays the league position under every order.
+const (
+	SortMatchPoints  = "match_points"
+	SortExpectedWins = "expected_wins"
+	SortAllPlay      = "all_play"
+	SortLuck         = "luck"
+)
+
+// ParseSortBy normalises a standings sort order; "" is SortMatchPoints.
+func ParseSortBy(sortBy string) (string, error) {
+	switch s := strings.ToLower(strings.TrimSpace(sortBy)); s {
+	case "":
+		return SortMatchPoints, nil
+	case SortMatchPoints, SortExpectedWins, SortAllPlay, SortLuck:
+		return s, nil
+	default:
+		return "", fmt.Errorf("unknown sort_by %q (want %s, %s, %s or %s)", sortBy, SortMatchPoints, SortExpectedWins, SortAllPlay, SortLuck)
+	}
+}
+
+// ApplyPythagorean sets each row's pythagorean expectation under exponent k,
+// the expected wins it implies over the matches played, and how far the
+// actual record (a draw counting half) runs ahead of that. A row with no
+// points either way expects .500.
+func ApplyPythagorean(rows []StandingsRow, k float64) {
+	for i := range rows {
+		r := &rows[i]
+		pct := 0.5
+		if r.PointsFor+r.PointsAgainst > 0 {
+			pf := math.Pow(float64(r.PointsFor), k)
+			pct = pf / (pf + math.Pow(float64(r.PointsAgainst), k))
+		}
+		expected := pct * float64(r.Played)
+		r.PythagoreanPct = round3(pct)
+		r.ExpectedWins = round3(expected)
+		r.WinsOverExpected = round3(float64(r.Wins) + float64(r.Draws)/2 - expected)
+	}
+}
+
+// AllPlayRecord is score's record had it played every score in week, which
+// includes score itself once; that one isn't counted.
+func AllPlayRecord(score int, week []int) (wins, draws, losses int) {
+	self := false
+	for _, other := range week {
+		switch {
+		case other == score && !self:
+			self = true
+		case score > other:
+			wins++
+		case score < other:
+			losses++
+		default:
+			draws++
+		}
+	}
+	return wins, draws, losses
+}
+
+// applyAllPlay sets each row's record had it played every other entry in
+// each GW it played, from the weekly scores in stats.
+func applyAllPlay(rows []StandingsRow, stats map[int]*standingsStat) {
+	scores := make(map[int][]int)
+	for _, s := range stats {
+		for _, r := range s.results {
+			scores[r.event] = append(scores[r.event], r.pointsFor)
+		}
+	}
+	for i := range rows {
+		s := stats[rows[i].EntryID]
+		if s == nil {
+			continue
+		}
+		w, d, l := 0, 0, 0
+		for _, r := range s.results {
+			gw, gd, gl := AllPlayRecord(r.pointsFor, scores[r.event])
+			w, d, l = w+gw, d+gd, l+gl
+		}
+		rows[i].AllPlayWins, rows[i].AllPlayDraws, rows[i].AllPlayLosses = w, d, l
+		if n := w + d + l; n > 0 {
+			rows[i].AllPlayPct = round3((float64(w) + float64(d)/2) / float64(n))
+		}
+	}
+}
+
+// SortStandings reorders rows by sortBy, best first, keeping the league
+// order among rows level on it. Rank is left as the league position.
+func SortStandings(rows []StandingsRow, sortBy string) {
+	var key func(r StandingsRow) float64
+	switch sortBy {
+	case SortExpectedWins:
+		key = func(r StandingsRow) float64 { return r.ExpectedWins }
+	case SortAllPlay:
+		key = func(r StandingsRow) float64 { return r.AllPlayPct }
+	case SortLuck:
+		key = func(r StandingsRow) float64 { return r.WinsOverExpected }
+	default:
+		return
+	}
+	sort.SliceStable(rows, func(i, j int) bool { return key(rows[i]) > key(rows[j]) })
+}
+
+func round3(v float64) float64 {
+	return math.Round(v*1000) / 1000
+}
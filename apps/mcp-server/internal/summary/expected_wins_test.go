@@ -0,0 +1,79 @@
+package summary
+
+import (
+	"math"
+	"testing"
+)
+
+func TestComputeStandings_ExpectedWinsAndAllPlay(t *testing.T) {
+	// D beats C with the week's lowest winning score; B loses to A with the
+	// second-best score of the week.
+	ld := tiebreakLeague(t, "A-B 60 50", "C-D 30 40")
+	rows := ComputeStandings(1, ld, []int{101, 102, 103, 104}, 1, nil).Rows
+	byName := make(map[string]StandingsRow, len(rows))
+	for _, r := range rows {
+		byName[r.EntryName] = r
+	}
+
+	want := 1 / (1 + math.Pow(30.0/40.0, DefaultPythagoreanExponent))
+	if d := byName["D"]; d.PythagoreanPct != round3(want) || d.ExpectedWins != round3(want) || d.WinsOverExpected != round3(1-want) {
+		t.Errorf("D = %+v, want pythagorean %.3f", d, want)
+	}
+	allPlay := map[string][3]int{"A": {3, 0, 0}, "B": {2, 0, 1}, "C": {0, 0, 3}, "D": {1, 0, 2}}
+	for name, rec := range allPlay {
+		r := byName[name]
+		if got := [3]int{r.AllPlayWins, r.AllPlayDraws, r.AllPlayLosses}; got != rec {
+			t.Errorf("%s all-play = %v, want %v", name, got, rec)
+		}
+	}
+	if b := byName["B"]; b.AllPlayPct != 0.667 || b.WinsOverExpected >= 0 {
+		t.Errorf("B = %+v, want an unlucky .667 all-play", b)
+	}
+
+	SortStandings(rows, SortAllPlay)
+	if got := order(rows); got != "ABDC" {
+		t.Errorf("all_play order = %s, want ABDC", got)
+	}
+	if rows[1].Rank != 3 {
+		t.Errorf("B rank = %d after sorting, want its league position 3", rows[1].Rank)
+	}
+	SortStandings(rows, SortLuck)
+	if got := order(rows); got != "ADCB" {
+		t.Errorf("luck order = %s, want ADCB", got)
+	}
+
+	ApplyPythagorean(rows, 2)
+	for _, r := range rows {
+		if r.EntryName == "A" && r.PythagoreanPct != round3(3600.0/(3600+2500)) {
+			t.Errorf("A at k=2 = %v", r.PythagoreanPct)
+		}
+	}
+}
+
+func TestComputeStandings_NoMatchesExpectsHalf(t *testing.T) {
+	rows := ComputeStandings(1, tiebreakLeague(t), []int{101, 102}, 1, nil).Rows
+	for _, r := range rows {
+		if r.PythagoreanPct != 0.5 || r.ExpectedWins != 0 || r.AllPlayPct != 0 {
+			t.Errorf("%s = %+v, want .500 and nothing played", r.EntryName, r)
+		}
+	}
+}
+
+func TestAllPlayRecord(t *testing.T) {
+	// Level with one other entry: one all-play draw, not two.
+	if w, d, l := AllPlayRecord(60, []int{60, 60, 70, 40}); w != 1 || d != 1 || l != 1 {
+		t.Errorf("AllPlayRecord = %d-%d-%d, want 1-1-1", w, d, l)
+	}
+}
+
+func TestParseSortBy(t *testing.T) {
+	if s, err := ParseSortBy(""); err != nil || s != SortMatchPoints {
+		t.Errorf("empty = %q, %v", s, err)
+	}
+	if s, err := ParseSortBy(" Expected_Wins "); err != nil || s != SortExpectedWins {
+		t.Errorf("expected_wins = %q, %v", s, err)
+	}
+	if _, err := ParseSortBy("goals"); err == nil {
+		t.Error("goals: expected error")
+	}
+}
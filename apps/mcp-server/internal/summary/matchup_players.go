@@ -0,0 +1,83 @@
+package summary
+
+import "sort"
+
+// PlayerPoints is one starter's share of an entry's GW score.
+type PlayerPoints struct {
+	Element      int    `json:"element"`
+	Name         string `json:"name"`
+	PositionType int    `json:"position_type"`
+	Points       int    `json:"points"`
+	Minutes      int    `json:"minutes"`
+}
+
+// DifferenceMaker is a winning starter who alone outscored the final margin:
+// without him the result would have gone the other way or been drawn.
+type DifferenceMaker struct {
+	EntryID int `json:"entry_id"`
+	PlayerPoints
+}
+
+// MatchupPlayers attributes a matchup's totals to the starters behind them.
+// TopScorer and Dud are each side's highest- and lowest-scoring starter,
+// nil for a side with no lineup.
+type MatchupPlayers struct {
+	Starters          []PlayerPoints    `json:"starters"`
+	OpponentStarters  []PlayerPoints    `json:"opponent_starters"`
+	TopScorer         *PlayerPoints     `json:"top_scorer,omitempty"`
+	OpponentTopScorer *PlayerPoints     `json:"opponent_top_scorer,omitempty"`
+	Dud               *PlayerPoints     `json:"dud,omitempty"`
+	OpponentDud       *PlayerPoints     `json:"opponent_dud,omitempty"`
+	DifferenceMakers  []DifferenceMaker `json:"difference_makers"`
+}
+
+// extremes returns the first highest- and lowest-scoring of starters, which
+// are in pick order; a dud with fewer minutes is the bigger one.
+func extremes(starters []PlayerPoints) (top *PlayerPoints, dud *PlayerPoints) {
+	for i := range starters {
+		p := starters[i]
+		if top == nil || p.Points > top.Points {
+			top = &p
+		}
+		if dud == nil || p.Points < dud.Points || (p.Points == dud.Points && p.Minutes < dud.Minutes) {
+			dud = &p
+		}
+	}
+	return top, dud
+}
+
+// buildMatchupPlayers attributes a matchup between entryID (total, starters)
+// and opponentID. Difference makers come from the winning side only, highest
+// first; a draw has none.
+func buildMatchupPlayers(entryID int, total int, starters []PlayerPoints, opponentID int, opponentTotal int, opponentStarters []PlayerPoints) *MatchupPlayers {
+	out := &MatchupPlayers{
+		Starters:         starters,
+		OpponentStarters: opponentStarters,
+		DifferenceMakers: []DifferenceMaker{},
+	}
+	if out.Starters == nil {
+		out.Starters = []PlayerPoints{}
+	}
+	if out.OpponentStarters == nil {
+		out.OpponentStarters = []PlayerPoints{}
+	}
+	out.TopScorer, out.Dud = extremes(out.Starters)
+	out.OpponentTopScorer, out.OpponentDud = extremes(out.OpponentStarters)
+
+	winner, margin, winners := entryID, total-opponentTotal, out.Starters
+	if margin < 0 {
+		winner, margin, winners = opponentID, -margin, out.OpponentStarters
+	}
+	if margin == 0 {
+		return out
+	}
+	for _, p := range winners {
+		if p.Points > margin {
+			out.DifferenceMakers = append(out.DifferenceMakers, DifferenceMaker{EntryID: winner, PlayerPoints: p})
+		}
+	}
+	sort.SliceStable(out.DifferenceMakers, func(i, j int) bool {
+		return out.DifferenceMakers[i].Points > out.DifferenceMakers[j].Points
+	})
+	return out
+}
@@ -0,0 +1,43 @@
+package summary
+
+import "testing"
+
+func TestBuildMatchupPlayers(t *testing.T) {
+	mine := []PlayerPoints{
+		{Element: 1, Name: "Raya", PositionType: 1, Points: 2, Minutes: 90},
+		{Element: 2, Name: "Palmer", PositionType: 3, Points: 18, Minutes: 90},
+		{Element: 3, Name: "Saka", PositionType: 3, Points: 13, Minutes: 85},
+		{Element: 4, Name: "Isak", PositionType: 4, Points: 1, Minutes: 90},
+		{Element: 5, Name: "Gabriel", PositionType: 2, Points: 1, Minutes: 20},
+	}
+	theirs := []PlayerPoints{
+		{Element: 11, Name: "Pickford", PositionType: 1, Points: 6, Minutes: 90},
+		{Element: 12, Name: "Salah", PositionType: 3, Points: 15, Minutes: 90},
+	}
+
+	// 35-21: Palmer alone beat the 14-point margin; Saka didn't.
+	d := buildMatchupPlayers(200, 35, mine, 201, 21, theirs)
+	if d.TopScorer == nil || d.TopScorer.Element != 2 || d.OpponentTopScorer.Element != 12 {
+		t.Errorf("top scorers = %+v / %+v", d.TopScorer, d.OpponentTopScorer)
+	}
+	// Isak and Gabriel both scored 1; the one with fewer minutes is the dud.
+	if d.Dud == nil || d.Dud.Element != 5 || d.OpponentDud.Element != 11 {
+		t.Errorf("duds = %+v / %+v", d.Dud, d.OpponentDud)
+	}
+	if len(d.DifferenceMakers) != 1 || d.DifferenceMakers[0].Element != 2 || d.DifferenceMakers[0].EntryID != 200 {
+		t.Errorf("difference makers = %+v", d.DifferenceMakers)
+	}
+
+	// From the loser's side the winners are still the opponent's.
+	d = buildMatchupPlayers(201, 21, theirs, 200, 35, mine)
+	if len(d.DifferenceMakers) != 1 || d.DifferenceMakers[0].EntryID != 200 {
+		t.Errorf("loser's view = %+v", d.DifferenceMakers)
+	}
+
+	// A draw has no difference makers, and a side with no lineup no
+	// extremes.
+	d = buildMatchupPlayers(200, 0, nil, 201, 0, nil)
+	if len(d.DifferenceMakers) != 0 || d.TopScorer != nil || d.Dud != nil || d.Starters == nil {
+		t.Errorf("empty draw = %+v", d)
+	}
+}
@@ -0,0 +1,297 @@
+package summary
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/clock"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/ledger"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/livestats"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+)
+
+// OptimalStandingsRow compares one entry's real and best-ball standings.
+type OptimalStandingsRow struct {
+	EntryID            int    `json:"entry_id"`
+	EntryName          string `json:"entry_name"`
+	ActualRank         int    `json:"actual_rank"`
+	OptimalRank        int    `json:"optimal_rank"`
+	RankDelta          int    `json:"rank_delta"` // positive: they'd sit higher with perfect lineups
+	ActualPoints       int    `json:"actual_points"`
+	OptimalPoints      int    `json:"optimal_points"`
+	BenchPointsLost    int    `json:"bench_points_lost"`
+	ActualMatchPoints  int    `json:"actual_match_points"`
+	OptimalMatchPoints int    `json:"optimal_match_points"`
+}
+
+// FlippedResult is a match whose result changes when both sides field their
+// optimal XI. Points and results are from EntryID's side.
+type FlippedResult struct {
+	Gameweek              int    `json:"gameweek"`
+	EntryID               int    `json:"entry_id"`
+	EntryName             string `json:"entry_name"`
+	OpponentID            int    `json:"opponent_entry_id"`
+	OpponentName          string `json:"opponent_name"`
+	ActualPoints          int    `json:"actual_points"`
+	ActualOpponentPoints  int    `json:"actual_opponent_points"`
+	OptimalPoints         int    `json:"optimal_points"`
+	OptimalOpponentPoints int    `json:"optimal_opponent_points"`
+	ActualResult          string `json:"actual_result"`
+	OptimalResult         string `json:"optimal_result"`
+}
+
+// OptimalStandingsSummary is the league table replayed with every manager
+// fielding their best legal XI in every finished GW through ThroughGW.
+type OptimalStandingsSummary struct {
+	LeagueID  int `json:"league_id"`
+	ThroughGW int `json:"through_gw"`
+	BuildStamp
+	Actual     []StandingsRow        `json:"actual"`
+	Optimal    []StandingsRow        `json:"optimal"`
+	Comparison []OptimalStandingsRow `json:"comparison"`
+	Flipped    []FlippedResult       `json:"flipped"`
+	Commentary []string              `json:"commentary"`
+	// MissingSnapshots counts entry-GWs without a snapshot, scored at their
+	// real total.
+	MissingSnapshots int `json:"missing_snapshots"`
+}
+
+// optimalXIPoints returns the best score any legal XI from snap could have
+// made: one GK, 3-5 DEF, 2-5 MID and 1-3 FWD. Draft has no captains, so the
+// best XI is simply the top scorers at each position for the best formation.
+func optimalXIPoints(meta map[int]PlayerMeta, snap *ledger.EntrySnapshot, liveByElement map[int]livestats.ElementStats) int {
+	byPos := make(map[int][]int, 4)
+	for _, p := range snap.Picks {
+		pos := meta[p.Element].PositionType
+		byPos[pos] = append(byPos[pos], liveByElement[p.Element].TotalPoints)
+	}
+	for pos := range byPos {
+		sort.Sort(sort.Reverse(sort.IntSlice(byPos[pos])))
+	}
+	if _, total, ok := bestFormation(byPos); ok {
+		return total
+	}
+	// An incomplete squad still scores whatever it has.
+	best := 0
+	for _, pts := range byPos {
+		for _, v := range pts {
+			best += v
+		}
+	}
+	return best
+}
+
+// BuildOptimalStandings writes the best-ball standings through throughGW to
+// summary/optimal_standings/{league}/through_gw/{gw}.json. Snapshots for the
+// finished GWs should already exist under derivedRoot; an entry-GW without
+// one keeps its real score.
+func BuildOptimalStandings(st *store.JSONStore, derivedRoot string, leagueID int, throughGW int, clk clock.Clock) error {
+	if leagueID == 0 {
+		return fmt.Errorf("league_id is required")
+	}
+	if throughGW == 0 {
+		return fmt.Errorf("gw is required")
+	}
+	raw, err := st.ReadRaw(fmt.Sprintf("league/%d/details.json", leagueID))
+	if err != nil {
+		return err
+	}
+	var ld LeagueDetails
+	if err := json.Unmarshal(raw, &ld); err != nil {
+		return err
+	}
+	meta, _, err := loadBootstrapMeta(st)
+	if err != nil {
+		return err
+	}
+	metaByGW := make(map[int]map[int]PlayerMeta)
+	out, err := buildOptimalStandings(ld, leagueID, throughGW, func(entryID, gw int) (int, bool, error) {
+		snap, err := loadSnapshot(derivedRoot, leagueID, entryID, gw)
+		if errors.Is(err, fs.ErrNotExist) {
+			return 0, false, nil
+		}
+		if err != nil {
+			return 0, false, err
+		}
+		live, err := livestats.LoadGW(st, gw)
+		if err != nil {
+			return 0, false, err
+		}
+		// Formation rules apply to positions as they were that GW.
+		gwMeta, ok := metaByGW[gw]
+		if !ok {
+			if gwMeta, err = metaAtGW(derivedRoot, meta, gw, clk); err != nil {
+				return 0, false, err
+			}
+			metaByGW[gw] = gwMeta
+		}
+		return optimalXIPoints(gwMeta, snap, live.Elements), true, nil
+	})
+	if err != nil {
+		return err
+	}
+	outPath := filepath.Join(derivedRoot, fmt.Sprintf("summary/optimal_standings/%d/through_gw/%d.json", leagueID, throughGW))
+	return writeSummary(outPath, &out, clk, st)
+}
+
+// buildOptimalStandings replays ld.Matches through throughGW with each side's
+// score replaced by optimal(entryID, gw). The bool is false when there is no
+// snapshot to optimize, in which case the real score stands.
+func buildOptimalStandings(ld LeagueDetails, leagueID int, throughGW int, optimal func(entryID, gw int) (int, bool, error)) (OptimalStandingsSummary, error) {
+	entryIDs := make([]int, 0, len(ld.LeagueEntries))
+	entryNameByID := make(map[int]string, len(ld.LeagueEntries))
+	leagueEntryToEntry := make(map[int]int, len(ld.LeagueEntries))
+	for _, e := range ld.LeagueEntries {
+		entryIDs = append(entryIDs, e.EntryID)
+		entryNameByID[e.EntryID] = e.EntryName
+		leagueEntryToEntry[e.ID] = e.EntryID
+	}
+
+	out := OptimalStandingsSummary{
+		LeagueID:   leagueID,
+		ThroughGW:  throughGW,
+		Flipped:    make([]FlippedResult, 0),
+		Commentary: make([]string, 0),
+	}
+	score := func(entryID, gw, actual int) (int, error) {
+		pts, ok, err := optimal(entryID, gw)
+		if err != nil {
+			return 0, err
+		}
+		if !ok {
+			out.MissingSnapshots++
+			return actual, nil
+		}
+		// Auto-subs can only bring in bench players, so the best XI never
+		// scores less than the real one; keep the real score if data disagrees.
+		return max(pts, actual), nil
+	}
+
+	adjusted := ld
+	adjusted.Matches = append(adjusted.Matches[:0:0], ld.Matches...)
+	for i, m := range adjusted.Matches {
+		if !m.Finished || m.Event > throughGW {
+			continue
+		}
+		aID := leagueEntryToEntry[m.LeagueEntry1]
+		bID := leagueEntryToEntry[m.LeagueEntry2]
+		if aID == 0 || bID == 0 {
+			continue
+		}
+		aPts, err := score(aID, m.Event, m.LeagueEntry1Points)
+		if err != nil {
+			return OptimalStandingsSummary{}, err
+		}
+		bPts, err := score(bID, m.Event, m.LeagueEntry2Points)
+		if err != nil {
+			return OptimalStandingsSummary{}, err
+		}
+		adjusted.Matches[i].LeagueEntry1Points = aPts
+		adjusted.Matches[i].LeagueEntry2Points = bPts
+
+		actual := resultFromScore(m.LeagueEntry1Points, m.LeagueEntry2Points)
+		best := resultFromScore(aPts, bPts)
+		if actual == best {
+			continue
+		}
+		// Report flips from the side that was robbed, i.e. the one the
+		// optimal result favours.
+		f := FlippedResult{
+			Gameweek:              m.Event,
+			EntryID:               aID,
+			OpponentID:            bID,
+			ActualPoints:          m.LeagueEntry1Points,
+			ActualOpponentPoints:  m.LeagueEntry2Points,
+			OptimalPoints:         aPts,
+			OptimalOpponentPoints: bPts,
+			ActualResult:          actual,
+			OptimalResult:         best,
+		}
+		if best == "L" || (best == "D" && actual == "W") {
+			f = FlippedResult{
+				Gameweek:              m.Event,
+				EntryID:               bID,
+				OpponentID:            aID,
+				ActualPoints:          m.LeagueEntry2Points,
+				ActualOpponentPoints:  m.LeagueEntry1Points,
+				OptimalPoints:         bPts,
+				OptimalOpponentPoints: aPts,
+				ActualResult:          resultFromScore(m.LeagueEntry2Points, m.LeagueEntry1Points),
+				OptimalResult:         resultFromScore(bPts, aPts),
+			}
+		}
+		f.EntryName = entryNameByID[f.EntryID]
+		f.OpponentName = entryNameByID[f.OpponentID]
+		out.Flipped = append(out.Flipped, f)
+	}
+	sort.SliceStable(out.Flipped, func(i, j int) bool { return out.Flipped[i].Gameweek < out.Flipped[j].Gameweek })
+
+	out.Actual, _ = computeStandings(ld.Matches, leagueEntryToEntry, entryNameByID, entryIDs, throughGW, nil)
+	var optimalRank map[int]int
+	out.Optimal, optimalRank = computeStandings(adjusted.Matches, leagueEntryToEntry, entryNameByID, entryIDs, throughGW, nil)
+	optimalByEntry := make(map[int]StandingsRow, len(out.Optimal))
+	for _, r := range out.Optimal {
+		optimalByEntry[r.EntryID] = r
+	}
+	out.Comparison = make([]OptimalStandingsRow, 0, len(out.Actual))
+	for _, a := range out.Actual {
+		o := optimalByEntry[a.EntryID]
+		out.Comparison = append(out.Comparison, OptimalStandingsRow{
+			EntryID:            a.EntryID,
+			EntryName:          a.EntryName,
+			ActualRank:         a.Rank,
+			OptimalRank:        optimalRank[a.EntryID],
+			RankDelta:          a.Rank - optimalRank[a.EntryID],
+			ActualPoints:       a.PointsFor,
+			OptimalPoints:      o.PointsFor,
+			BenchPointsLost:    o.PointsFor - a.PointsFor,
+			ActualMatchPoints:  a.MatchPoints,
+			OptimalMatchPoints: o.MatchPoints,
+		})
+	}
+
+	outcome := map[string]string{"W": "won", "D": "drawn", "L": "lost"}
+	for _, f := range out.Flipped {
+		out.Commentary = append(out.Commentary, fmt.Sprintf("GW%d: %s %s %s %d-%d, but would have %s %d-%d with their best XI.",
+			f.Gameweek, f.EntryName, pastTense(f.ActualResult), f.OpponentName, f.ActualPoints, f.ActualOpponentPoints,
+			outcome[f.OptimalResult], f.OptimalPoints, f.OptimalOpponentPoints))
+	}
+	for _, c := range out.Comparison {
+		if c.RankDelta != 0 {
+			out.Commentary = append(out.Commentary, fmt.Sprintf("%s would be %s instead of %s, with %d points left on the bench.",
+				c.EntryName, ordinal(c.OptimalRank), ordinal(c.ActualRank), c.BenchPointsLost))
+		}
+	}
+	return out, nil
+}
+
+// pastTense phrases a W/D/L result as a verb.
+func pastTense(result string) string {
+	switch result {
+	case "W":
+		return "beat"
+	case "D":
+		return "drew with"
+	default:
+		return "lost to"
+	}
+}
+
+func ordinal(n int) string {
+	suffix := "th"
+	if n%100 < 11 || n%100 > 13 {
+		switch n % 10 {
+		case 1:
+			suffix = "st"
+		case 2:
+			suffix = "nd"
+		case 3:
+			suffix = "rd"
+		}
+	}
+	return fmt.Sprintf("%d%s", n, suffix)
+}
@@ -0,0 +1,218 @@
+package summary
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// PowerWeights blends the four power-ranking components. They needn't sum
+// to 1; ComputePowerRankings scales them so they do.
+type PowerWeights struct {
+	Recent   float64 `json:"recent"`
+	Season   float64 `json:"season"`
+	Roster   float64 `json:"roster"`
+	Schedule float64 `json:"schedule"`
+}
+
+// DefaultPowerWeights favours recent scoring over the season's, with
+// roster strength level with the season and the all-play record a
+// schedule-luck correction on top.
+var DefaultPowerWeights = PowerWeights{Recent: 0.35, Season: 0.25, Roster: 0.25, Schedule: 0.15}
+
+func (w PowerWeights) sum() float64 {
+	return w.Recent + w.Season + w.Roster + w.Schedule
+}
+
+// Validate rejects negative weights and an all-zero set.
+func (w PowerWeights) Validate() error {
+	if w.Recent < 0 || w.Season < 0 || w.Roster < 0 || w.Schedule < 0 {
+		return fmt.Errorf("power weights must not be negative")
+	}
+	if w.sum() == 0 {
+		return fmt.Errorf("at least one power weight must be above 0")
+	}
+	return nil
+}
+
+// Normalized scales w to sum to 1, rounded to 3 places.
+func (w PowerWeights) Normalized() PowerWeights {
+	s := w.sum()
+	return PowerWeights{Recent: round3(w.Recent / s), Season: round3(w.Season / s), Roster: round3(w.Roster / s), Schedule: round3(w.Schedule / s)}
+}
+
+// PowerComponents holds each component's share of the power score: its
+// min-max position in the league times its weight.
+type PowerComponents struct {
+	Recent   float64 `json:"recent"`
+	Season   float64 `json:"season"`
+	Roster   float64 `json:"roster"`
+	Schedule float64 `json:"schedule"`
+}
+
+// PowerRankingRow is one entry's power ranking. RecentAvg is points for
+// over the last three matches, SeasonAvg over all of them, RosterStrength
+// the sum of the rostered players' points per GW over the form horizon and
+// AllPlayPct the record against every entry each GW.
+type PowerRankingRow struct {
+	EntryID        int             `json:"entry_id"`
+	EntryName      string          `json:"entry_name"`
+	Rank           int             `json:"rank"`
+	PowerScore     float64         `json:"power_score"`
+	StandingsRank  int             `json:"standings_rank"`
+	RecentAvg      float64         `json:"recent_avg"`
+	SeasonAvg      float64         `json:"season_avg"`
+	RosterStrength float64         `json:"roster_strength"`
+	AllPlayPct     float64         `json:"all_play_pct"`
+	Components     PowerComponents `json:"components"`
+	// PrevRank and Movement compare with the previous GW's ranking; both
+	// are left out when there is none to compare with. Movement is
+	// PrevRank minus Rank, so positive means the entry climbed.
+	PrevRank      int    `json:"prev_rank,omitempty"`
+	Movement      *int   `json:"movement,omitempty"`
+	Justification string `json:"justification"`
+}
+
+// PowerRankings is the power_rankings output for one GW.
+type PowerRankings struct {
+	LeagueID int `json:"league_id"`
+	Gameweek int `json:"gameweek"`
+	BuildStamp
+	Weights PowerWeights      `json:"weights"`
+	Rows    []PowerRankingRow `json:"rows"`
+}
+
+type powerComponent struct {
+	label  string
+	value  func(r PowerRankingRow) float64
+	share  func(c *PowerComponents) *float64
+	weight func(w PowerWeights) float64
+	detail func(v float64) string
+}
+
+var powerComponentsList = []powerComponent{
+	{
+		label:  "recent scoring",
+		value:  func(r PowerRankingRow) float64 { return r.RecentAvg },
+		share:  func(c *PowerComponents) *float64 { return &c.Recent },
+		weight: func(w PowerWeights) float64 { return w.Recent },
+		detail: func(v float64) string { return fmt.Sprintf("%.1f pts/GW over the last 3", v) },
+	},
+	{
+		label:  "season scoring",
+		value:  func(r PowerRankingRow) float64 { return r.SeasonAvg },
+		share:  func(c *PowerComponents) *float64 { return &c.Season },
+		weight: func(w PowerWeights) float64 { return w.Season },
+		detail: func(v float64) string { return fmt.Sprintf("%.1f pts/GW this season", v) },
+	},
+	{
+		label:  "roster strength",
+		value:  func(r PowerRankingRow) float64 { return r.RosterStrength },
+		share:  func(c *PowerComponents) *float64 { return &c.Roster },
+		weight: func(w PowerWeights) float64 { return w.Roster },
+		detail: func(v float64) string { return fmt.Sprintf("roster worth %.1f pts/GW on recent form", v) },
+	},
+	{
+		label:  "all-play record",
+		value:  func(r PowerRankingRow) float64 { return r.AllPlayPct },
+		share:  func(c *PowerComponents) *float64 { return &c.Schedule },
+		weight: func(w PowerWeights) float64 { return w.Schedule },
+		detail: func(v float64) string { return fmt.Sprintf("%.3f against the whole league each week", v) },
+	},
+}
+
+// ComputePowerRankings ranks the standings rows by a weighted blend of
+// recent scoring, season scoring, roster strength (from rosterStrength,
+// keyed by entry id) and all-play percentage. Each component is min-max
+// scaled across the league first, so a component on which everyone is level
+// adds nothing. Entries level on power keep their standings order.
+func ComputePowerRankings(leagueID int, gw int, rows []StandingsRow, rosterStrength map[int]float64, weights PowerWeights, stamp BuildStamp) PowerRankings {
+	weights = weights.Normalized()
+	out := PowerRankings{
+		LeagueID:   leagueID,
+		Gameweek:   gw,
+		BuildStamp: stamp,
+		Weights:    weights,
+		Rows:       make([]PowerRankingRow, 0, len(rows)),
+	}
+	for _, r := range rows {
+		out.Rows = append(out.Rows, PowerRankingRow{
+			EntryID:        r.EntryID,
+			EntryName:      r.EntryName,
+			StandingsRank:  r.Rank,
+			RecentAvg:      r.PointsForLast3,
+			SeasonAvg:      r.PointsForAvg,
+			RosterStrength: round3(rosterStrength[r.EntryID]),
+			AllPlayPct:     r.AllPlayPct,
+		})
+	}
+	sort.SliceStable(out.Rows, func(i, j int) bool { return out.Rows[i].StandingsRank < out.Rows[j].StandingsRank })
+
+	for _, c := range powerComponentsList {
+		lo, hi := math.Inf(1), math.Inf(-1)
+		for _, r := range out.Rows {
+			lo, hi = math.Min(lo, c.value(r)), math.Max(hi, c.value(r))
+		}
+		for i := range out.Rows {
+			norm := 0.0
+			if hi > lo {
+				norm = (c.value(out.Rows[i]) - lo) / (hi - lo)
+			}
+			*c.share(&out.Rows[i].Components) = round3(norm * c.weight(weights))
+		}
+	}
+	for i := range out.Rows {
+		c := out.Rows[i].Components
+		out.Rows[i].PowerScore = round3(c.Recent + c.Season + c.Roster + c.Schedule)
+	}
+	sort.SliceStable(out.Rows, func(i, j int) bool { return out.Rows[i].PowerScore > out.Rows[j].PowerScore })
+	for i := range out.Rows {
+		out.Rows[i].Rank = i + 1
+	}
+	for i := range out.Rows {
+		out.Rows[i].Justification = justifyPower(out.Rows, i)
+	}
+	return out
+}
+
+// justifyPower names the component contributing most to rows[i]'s score,
+// with the entry's value and league rank on it.
+func justifyPower(rows []PowerRankingRow, i int) string {
+	var best *powerComponent
+	bestShare := 0.0
+	for k := range powerComponentsList {
+		c := &powerComponentsList[k]
+		if s := *c.share(&rows[i].Components); s > bestShare {
+			best, bestShare = c, s
+		}
+	}
+	if best == nil {
+		return "No component lifts this entry above the league's lowest yet."
+	}
+	v := best.value(rows[i])
+	place := 1
+	for _, r := range rows {
+		if best.value(r) > v {
+			place++
+		}
+	}
+	return fmt.Sprintf("Driven by %s: %s, %s in the league.", best.label, best.detail(v), ordinal(place))
+}
+
+// ApplyPowerMovement sets each row's previous rank and movement from prev,
+// the previous GW's ranking. Entries missing from prev get neither.
+func ApplyPowerMovement(cur *PowerRankings, prev PowerRankings) {
+	prevRank := make(map[int]int, len(prev.Rows))
+	for _, r := range prev.Rows {
+		prevRank[r.EntryID] = r.Rank
+	}
+	for i := range cur.Rows {
+		p, ok := prevRank[cur.Rows[i].EntryID]
+		if !ok {
+			continue
+		}
+		m := p - cur.Rows[i].Rank
+		cur.Rows[i].PrevRank = p
+		cur.Rows[i].Movement = &m
+	}
+}
@@ -0,0 +1,80 @@
+package summary
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func powerOrder(rows []PowerRankingRow) string {
+	names := make([]string, 0, len(rows))
+	for _, r := range rows {
+		names = append(names, r.EntryName)
+	}
+	return strings.Join(names, "")
+}
+
+func TestComputePowerRankings(t *testing.T) {
+	rows := []StandingsRow{
+		{EntryID: 1, EntryName: "A", Rank: 1, PointsForLast3: 60, PointsForAvg: 55, AllPlayPct: 0.8},
+		{EntryID: 2, EntryName: "B", Rank: 2, PointsForLast3: 40, PointsForAvg: 50, AllPlayPct: 0.6},
+		{EntryID: 3, EntryName: "C", Rank: 3, PointsForLast3: 70, PointsForAvg: 45, AllPlayPct: 0.4},
+		{EntryID: 4, EntryName: "D", Rank: 4, PointsForLast3: 30, PointsForAvg: 40, AllPlayPct: 0.2},
+	}
+	strength := map[int]float64{1: 30, 2: 50, 3: 60, 4: 10}
+
+	got := ComputePowerRankings(1, 5, rows, strength, DefaultPowerWeights, BuildStamp{})
+	if o := powerOrder(got.Rows); o != "ACBD" {
+		t.Fatalf("order = %s, want ACBD", o)
+	}
+	// A: .35*.75 + .25*1 + .25*.4 + .15*1; C: .35*1 + .25/3 + .25*1 + .15/3.
+	for i, want := range []float64{0.7625, 0.7333, 0.5542, 0} {
+		if r := got.Rows[i]; math.Abs(r.PowerScore-want) > 0.002 || r.Rank != i+1 {
+			t.Errorf("row %d = %s rank %d score %.3f, want rank %d score %.3f", i, r.EntryName, r.Rank, r.PowerScore, i+1, want)
+		}
+	}
+	if c := got.Rows[1]; c.StandingsRank != 3 || c.Justification != "Driven by recent scoring: 70.0 pts/GW over the last 3, 1st in the league." {
+		t.Errorf("C = %+v", c)
+	}
+	if d := got.Rows[3]; d.Justification != "No component lifts this entry above the league's lowest yet." {
+		t.Errorf("D justification = %q", d.Justification)
+	}
+
+	rosterOnly := ComputePowerRankings(1, 5, rows, strength, PowerWeights{Roster: 2}, BuildStamp{})
+	if o := powerOrder(rosterOnly.Rows); o != "CBAD" {
+		t.Errorf("roster-only order = %s, want CBAD", o)
+	}
+	if rosterOnly.Weights != (PowerWeights{Roster: 1}) {
+		t.Errorf("weights = %+v, want roster 1", rosterOnly.Weights)
+	}
+	if j := rosterOnly.Rows[0].Justification; j != "Driven by roster strength: roster worth 60.0 pts/GW on recent form, 1st in the league." {
+		t.Errorf("C justification = %q", j)
+	}
+}
+
+func TestApplyPowerMovement(t *testing.T) {
+	cur := PowerRankings{Rows: []PowerRankingRow{{EntryID: 2, Rank: 1}, {EntryID: 1, Rank: 2}, {EntryID: 3, Rank: 3}}}
+	prev := PowerRankings{Rows: []PowerRankingRow{{EntryID: 1, Rank: 1}, {EntryID: 2, Rank: 2}}}
+	ApplyPowerMovement(&cur, prev)
+	if r := cur.Rows[0]; r.PrevRank != 2 || r.Movement == nil || *r.Movement != 1 {
+		t.Errorf("climber = %+v", r)
+	}
+	if r := cur.Rows[1]; r.PrevRank != 1 || r.Movement == nil || *r.Movement != -1 {
+		t.Errorf("faller = %+v", r)
+	}
+	if r := cur.Rows[2]; r.PrevRank != 0 || r.Movement != nil {
+		t.Errorf("new entry = %+v, want no movement", r)
+	}
+}
+
+func TestPowerWeightsValidate(t *testing.T) {
+	if err := DefaultPowerWeights.Validate(); err != nil {
+		t.Errorf("defaults: %v", err)
+	}
+	if err := (PowerWeights{Recent: -1, Season: 2}).Validate(); err == nil {
+		t.Error("negative weight: expected error")
+	}
+	if err := (PowerWeights{}).Validate(); err == nil {
+		t.Error("all zero: expected error")
+	}
+}
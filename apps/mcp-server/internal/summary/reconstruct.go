@@ -0,0 +1,121 @@
+package summary
+
+import (
+	"sort"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/ledger"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/livestats"
+)
+
+// RosterSourceReconstructed marks an entry-GW whose picks were never fetched:
+// its squad comes from the draft ledger and transactions, and its starting
+// XI is a best guess, so starter and bench splits are approximate.
+const RosterSourceReconstructed = "reconstructed"
+
+// bestFormation picks the legal formation (one GK, 3-5 DEF, 2-5 MID, 1-3
+// FWD) that scores most from byPos, each position's points sorted highest
+// first. It returns how many to start at each position type (index 1-4);
+// ok is false when no formation can be filled.
+func bestFormation(byPos map[int][]int) (counts [5]int, total int, ok bool) {
+	top := func(pos, n int) (int, bool) {
+		if len(byPos[pos]) < n {
+			return 0, false
+		}
+		sum := 0
+		for _, v := range byPos[pos][:n] {
+			sum += v
+		}
+		return sum, true
+	}
+	for def := 3; def <= 5; def++ {
+		for fwd := 1; fwd <= 3; fwd++ {
+			mid := 10 - def - fwd
+			if mid < 2 || mid > 5 {
+				continue
+			}
+			gk, ok1 := top(1, 1)
+			d, ok2 := top(2, def)
+			m, ok3 := top(3, mid)
+			f, ok4 := top(4, fwd)
+			if !ok1 || !ok2 || !ok3 || !ok4 {
+				continue
+			}
+			if sum := gk + d + m + f; !ok || sum > total {
+				counts, total, ok = [5]int{0, 1, def, mid, fwd}, sum, true
+			}
+		}
+	}
+	return counts, total, ok
+}
+
+// reconstructSnapshot builds a best-guess snapshot of squad for gw: the
+// highest-scoring legal XI from that GW's points starts (GK, DEF, MID, FWD
+// in order) and the rest fill the bench, spare GK first. A squad too short
+// for any formation starts its top eleven scorers. The snapshot is stamped
+// generatedAt.
+func reconstructSnapshot(leagueID int, entryID int, gw int, squad map[int]bool, meta map[int]PlayerMeta, liveByElement map[int]livestats.ElementStats, generatedAt string) *ledger.EntrySnapshot {
+	elements := make([]int, 0, len(squad))
+	for el, ok := range squad {
+		if ok {
+			elements = append(elements, el)
+		}
+	}
+	points := func(el int) int { return liveByElement[el].TotalPoints }
+	sort.Slice(elements, func(i, j int) bool {
+		if points(elements[i]) != points(elements[j]) {
+			return points(elements[i]) > points(elements[j])
+		}
+		return elements[i] < elements[j]
+	})
+	byPos := make(map[int][]int, 4)
+	for _, el := range elements {
+		pos := meta[el].PositionType
+		byPos[pos] = append(byPos[pos], points(el))
+	}
+
+	starting := make(map[int]bool, 11)
+	starters := make([]int, 0, 11)
+	if counts, _, ok := bestFormation(byPos); ok {
+		for pos := 1; pos <= 4; pos++ {
+			for _, el := range elements {
+				if meta[el].PositionType == pos && counts[pos] > 0 {
+					starters = append(starters, el)
+					starting[el] = true
+					counts[pos]--
+				}
+			}
+		}
+	} else {
+		for _, el := range elements[:min(11, len(elements))] {
+			starters = append(starters, el)
+			starting[el] = true
+		}
+	}
+	bench := make([]int, 0, len(elements)-len(starters))
+	for _, el := range elements {
+		if !starting[el] && meta[el].PositionType == 1 {
+			bench = append(bench, el)
+		}
+	}
+	for _, el := range elements {
+		if !starting[el] && meta[el].PositionType != 1 {
+			bench = append(bench, el)
+		}
+	}
+
+	snap := &ledger.EntrySnapshot{
+		LeagueID:       leagueID,
+		EntryID:        entryID,
+		Gameweek:       gw,
+		GeneratedAtUTC: generatedAt,
+		Picks:          make([]ledger.EntryPick, 0, len(elements)),
+		Subs:           []ledger.EntrySub{},
+	}
+	for i, el := range starters {
+		snap.Picks = append(snap.Picks, ledger.EntryPick{Element: el, Position: i + 1, Multiplier: 1})
+	}
+	for i, el := range bench {
+		snap.Picks = append(snap.Picks, ledger.EntryPick{Element: el, Position: 12 + i})
+	}
+	return snap
+}
@@ -0,0 +1,140 @@
+package summary
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+)
+
+// writeReconstructLeague writes a one-GW league where Alpha (200) has a
+// snapshot and Beta (201) has none at all. Beta drafted 21-35 (2 GK, 5 DEF,
+// 5 MID, 3 FWD) and swapped forward 35 for 36 in GW1.
+func writeReconstructLeague(t *testing.T, root string) LeagueDetails {
+	t.Helper()
+	positions := map[int]int{21: 1, 22: 1, 40: 3}
+	points := map[int]int{21: 2, 22: 6, 23: 1, 24: 2, 25: 8, 26: 0, 27: 3, 28: 10, 29: 2, 30: 2, 31: 1, 32: 0, 33: 5, 34: 0, 35: 0, 36: 9, 40: 5}
+	for id := 23; id <= 27; id++ {
+		positions[id] = 2
+	}
+	for id := 28; id <= 32; id++ {
+		positions[id] = 3
+	}
+	for _, id := range []int{33, 34, 35, 36} {
+		positions[id] = 4
+	}
+	elements := []any{}
+	live := map[string]any{}
+	for id, pos := range positions {
+		elements = append(elements, map[string]any{"id": id, "web_name": "P" + itoa(id), "team": 10, "element_type": pos})
+		live[itoa(id)] = map[string]any{"stats": map[string]any{"minutes": 90, "total_points": points[id]}}
+	}
+	writeTestJSON(t, filepath.Join(root, "bootstrap/bootstrap-static.json"), map[string]any{
+		"elements": elements,
+		"teams":    []any{map[string]any{"id": 10, "short_name": "LIV"}},
+		"fixtures": map[string]any{},
+	})
+	writeLiveJSON(t, root, 1, live)
+
+	beta := []int{}
+	for id := 21; id <= 35; id++ {
+		beta = append(beta, id)
+	}
+	writeTestJSON(t, filepath.Join(root, "ledger/100/event_0.json"), map[string]any{
+		"league_id": 100,
+		"squads":    []any{map[string]any{"entry_id": 200, "player_ids": []int{40}}, map[string]any{"entry_id": 201, "player_ids": beta}},
+	})
+	writeTestJSON(t, filepath.Join(root, "league/100/transactions.json"), map[string]any{"transactions": []any{
+		map[string]any{"id": 1, "entry": 201, "event": 1, "kind": "f", "result": "a", "element_in": 36, "element_out": 35},
+	}})
+	writeTestJSON(t, filepath.Join(root, "league/100/trades.json"), map[string]any{"trades": []any{}})
+	writeTestJSON(t, filepath.Join(root, "snapshots/100/entry/200/gw/1.json"), map[string]any{
+		"entry_id": 200, "gameweek": 1,
+		"picks": []any{map[string]any{"element": 40, "position": 1}},
+	})
+
+	// Beta's official score (47) differs from its guessed XI's 49.
+	details := map[string]any{
+		"league_entries": []any{
+			map[string]any{"id": 1, "entry_id": 200, "entry_name": "Alpha FC"},
+			map[string]any{"id": 2, "entry_id": 201, "entry_name": "Beta FC"},
+		},
+		"matches": []any{map[string]any{
+			"event": 1, "started": true, "finished": true,
+			"league_entry_1": 1, "league_entry_1_points": 5,
+			"league_entry_2": 2, "league_entry_2_points": 47,
+		}},
+	}
+	writeTestJSON(t, filepath.Join(root, "league/100/details.json"), details)
+	raw, err := json.Marshal(details)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ld LeagueDetails
+	if err := json.Unmarshal(raw, &ld); err != nil {
+		t.Fatal(err)
+	}
+	return ld
+}
+
+func TestBuildLeagueSummaries_ReconstructsMissingSnapshots(t *testing.T) {
+	root := t.TempDir()
+	ld := writeReconstructLeague(t, root)
+	if err := BuildLeagueSummaries(store.NewJSONStore(root), root, 100, ld, []int{200, 201}, 1, 1, []int{5}, []string{"med"}, BuildOptions{Clock: testClock}); err != nil {
+		t.Fatalf("BuildLeagueSummaries: %v", err)
+	}
+	read := func(rel string, v any) {
+		t.Helper()
+		b, err := os.ReadFile(filepath.Join(root, rel))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := json.Unmarshal(b, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var league LeagueWeekSummary
+	read("summary/league/100/gw/1.json", &league)
+	alpha, beta := league.Entries[0], league.Entries[1]
+	if alpha.RosterSource != "" || alpha.Points.Starters != 5 {
+		t.Errorf("Alpha = %+v, want its snapshot", alpha)
+	}
+	if beta.RosterSource != RosterSourceReconstructed || beta.Points.Starters != 47 || beta.Points.Bench != 2 || len(beta.Roster) != 15 {
+		t.Fatalf("Beta = %+v, want a reconstructed 15 on the official 47", beta)
+	}
+	// 4-4-2 scores best; the spare GK heads the bench, and the GW1 swap is
+	// in the squad.
+	starters := map[int]bool{}
+	for _, p := range beta.Roster {
+		if p.Role == "starter" {
+			starters[p.Element] = true
+		}
+		if p.Element == 35 {
+			t.Error("Beta's roster still has the forward it dropped")
+		}
+	}
+	for _, id := range []int{22, 25, 27, 24, 23, 28, 29, 30, 31, 36, 33} {
+		if !starters[id] {
+			t.Errorf("element %d not in the guessed XI", id)
+		}
+	}
+	if beta.Roster[0].Element != 22 || beta.Roster[11].Element != 21 {
+		t.Errorf("positions 1 and 12 = %d, %d, want GK 22 starting and GK 21 first on the bench", beta.Roster[0].Element, beta.Roster[11].Element)
+	}
+
+	var matchups MatchupSummary
+	read("summary/matchup/100/gw/1.json", &matchups)
+	m := matchups.Matchups[0]
+	if m.Total != 5 || m.OpponentTotal != 47 || m.Result != "L" || m.RosterSource != "" || m.OpponentRosterSource != RosterSourceReconstructed {
+		t.Errorf("matchup = %d-%d %s (%q / %q)", m.Total, m.OpponentTotal, m.Result, m.RosterSource, m.OpponentRosterSource)
+	}
+
+	var lineup LineupEfficiencySummary
+	read("summary/lineup_efficiency/100/gw/1.json", &lineup)
+	if e := lineup.Entries[1]; !e.MissingSnapshot || e.RosterSource != RosterSourceReconstructed {
+		t.Errorf("lineup efficiency for Beta = %+v, want no lineup to judge", e)
+	}
+}
@@ -0,0 +1,41 @@
+package summary
+
+import (
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/clock"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+)
+
+// BuildStamp dates a summary. GeneratedAtUTC is when it was built, by the
+// build's clock. DataAsOfUTC is the newest modification time among the raw
+// files the build had read when it was written (see store.LatestInput),
+// empty when it read none. Two builds from the same inputs share
+// DataAsOfUTC whenever they ran, so it is the one to compare when diffing
+// derived files; GeneratedAtUTC only says how fresh the file itself is.
+type BuildStamp struct {
+	GeneratedAtUTC string `json:"generated_at_utc"`
+	DataAsOfUTC    string `json:"data_as_of_utc,omitempty"`
+}
+
+// NewBuildStamp stamps a summary built now by clk from st's reads so far.
+func NewBuildStamp(clk clock.Clock, st *store.JSONStore) BuildStamp {
+	b := BuildStamp{GeneratedAtUTC: clock.UTC(clock.Or(clk).Now())}
+	if st != nil {
+		if t := st.LatestInput(); !t.IsZero() {
+			b.DataAsOfUTC = clock.UTC(t)
+		}
+	}
+	return b
+}
+
+func (b *BuildStamp) setBuildStamp(s BuildStamp) { *b = s }
+
+// stampable is a summary embedding BuildStamp.
+type stampable interface {
+	setBuildStamp(BuildStamp)
+}
+
+// writeSummary stamps v with NewBuildStamp and writes it to path.
+func writeSummary(path string, v stampable, clk clock.Clock, st *store.JSONStore) error {
+	v.setBuildStamp(NewBuildStamp(clk, st))
+	return writeJSON(path, v)
+}
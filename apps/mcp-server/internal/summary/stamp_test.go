@@ -0,0 +1,97 @@
+package summary
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/clock"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+)
+
+// touchTree sets every file under root to mtime.
+func touchTree(t *testing.T, root string, mtime time.Time) {
+	t.Helper()
+	err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		return os.Chtimes(path, mtime, mtime)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBuildLeagueSummaries_Stamps(t *testing.T) {
+	root := t.TempDir()
+	ld := writeIncrementalLeague(t, root)
+	inputsAt := time.Date(2025, 10, 12, 9, 30, 0, 0, time.UTC)
+	touchTree(t, root, inputsAt)
+	// GW1's live data was refreshed last; everything read from then on is
+	// as of that refresh.
+	liveAt := inputsAt.Add(2 * time.Hour)
+	if err := os.Chtimes(filepath.Join(root, "gw/1/live.json"), liveAt, liveAt); err != nil {
+		t.Fatal(err)
+	}
+
+	build := func(clk clock.Clock) []byte {
+		t.Helper()
+		if err := BuildLeagueSummaries(store.NewJSONStore(root), root, 100, ld, []int{200, 201}, 1, 1, []int{5}, []string{"med"}, BuildOptions{Force: true, Clock: clk}); err != nil {
+			t.Fatalf("BuildLeagueSummaries: %v", err)
+		}
+		b, err := os.ReadFile(filepath.Join(root, "summary/league/100/gw/1.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+
+	first := build(testClock)
+	var s LeagueWeekSummary
+	if err := json.Unmarshal(first, &s); err != nil {
+		t.Fatal(err)
+	}
+	if s.GeneratedAtUTC != "2025-10-18T12:00:00Z" {
+		t.Errorf("generated_at_utc = %q, want the test clock's time", s.GeneratedAtUTC)
+	}
+	if s.DataAsOfUTC != "2025-10-12T11:30:00Z" {
+		t.Errorf("data_as_of_utc = %q, want the GW1 live refresh", s.DataAsOfUTC)
+	}
+
+	// The same inputs and clock rebuild the same bytes.
+	if again := build(testClock); !bytes.Equal(first, again) {
+		t.Errorf("rebuild differs:\n%s\n---\n%s", first, again)
+	}
+	// A later build of the same inputs keeps data_as_of_utc.
+	later := build(clock.Fixed(time.Date(2025, 10, 19, 8, 0, 0, 0, time.UTC)))
+	var s2 LeagueWeekSummary
+	if err := json.Unmarshal(later, &s2); err != nil {
+		t.Fatal(err)
+	}
+	if s2.GeneratedAtUTC != "2025-10-19T08:00:00Z" || s2.DataAsOfUTC != s.DataAsOfUTC {
+		t.Errorf("later build stamps = %+v, want a new generated_at_utc and the same data_as_of_utc", s2.BuildStamp)
+	}
+}
+
+func TestNewBuildStamp(t *testing.T) {
+	// A store that hasn't read anything leaves data_as_of_utc out.
+	b := NewBuildStamp(testClock, store.NewJSONStore(t.TempDir()))
+	if b.GeneratedAtUTC != "2025-10-18T12:00:00Z" || b.DataAsOfUTC != "" {
+		t.Errorf("stamp = %+v", b)
+	}
+	raw, err := json.Marshal(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(raw) != `{"generated_at_utc":"2025-10-18T12:00:00Z"}` {
+		t.Errorf("json = %s", raw)
+	}
+	// No clock is the wall clock.
+	if b := NewBuildStamp(nil, nil); b.GeneratedAtUTC == "" {
+		t.Error("nil clock left generated_at_utc empty")
+	}
+}
@@ -2,17 +2,22 @@ package summary
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
-	"os"
+	"io/fs"
 	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/clock"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/fixtures"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/leagueconfig"
 	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/ledger"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/livestats"
 	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/model"
-	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/points"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/positions"
 	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/reconcile"
 	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
 )
@@ -33,6 +38,27 @@ type RosterPlayer struct {
 	Position     int    `json:"position"`
 	PositionType int    `json:"position_type"`
 	Role         string `json:"role"`
+	// Points and Minutes are the player's GW totals from live.json.
+	Points  int `json:"points"`
+	Minutes int `json:"minutes"`
+}
+
+// DeductionReason is one negative scoring item, e.g. {"red card", -3}.
+type DeductionReason struct {
+	Reason string `json:"reason"`
+	Points int    `json:"points"`
+}
+
+// Deduction is a rostered player who finished the GW on negative points.
+// Deducted sums the negative items; Points is the GW total after any
+// positives.
+type Deduction struct {
+	Element  int               `json:"element"`
+	Name     string            `json:"name"`
+	Role     string            `json:"role"`
+	Points   int               `json:"points"`
+	Deducted int               `json:"deducted"`
+	Reasons  []DeductionReason `json:"reasons"`
 }
 
 type Record struct {
@@ -57,14 +83,23 @@ type ManagerWeekSummary struct {
 	Record          Record         `json:"record"`
 	Points          PointsSummary  `json:"points"`
 	Roster          []RosterPlayer `json:"roster"`
+	Deductions      []Deduction    `json:"deductions,omitempty"`
 	MissingOpponent bool           `json:"missing_opponent"`
+	// MissingSnapshot is set for a GW before the entry joined the league:
+	// it has no roster and scores zero.
+	MissingSnapshot bool `json:"missing_snapshot,omitempty"`
+	// RosterSource is RosterSourceReconstructed when the entry's picks were
+	// never fetched; Points.Starters is then the official match score when
+	// there is one.
+	RosterSource string `json:"roster_source,omitempty"`
 }
 
 type LeagueWeekSummary struct {
-	LeagueID       int                  `json:"league_id"`
-	Gameweek       int                  `json:"gameweek"`
-	GeneratedAtUTC string               `json:"generated_at_utc"`
-	Entries        []ManagerWeekSummary `json:"entries"`
+	LeagueID int `json:"league_id"`
+	Gameweek int `json:"gameweek"`
+	BuildStamp
+	Entries []ManagerWeekSummary `json:"entries"`
+	Awards  WeekAwards           `json:"awards"`
 }
 
 type PositionPoints struct {
@@ -85,13 +120,30 @@ type MatchupBreakdown struct {
 	Total         int            `json:"total"`
 	OpponentTotal int            `json:"opponent_total"`
 	Result        string         `json:"result"`
+	// Players and OpponentPlayers are each side's roster with GW points.
+	Players            []RosterPlayer `json:"players"`
+	OpponentPlayers    []RosterPlayer `json:"opponent_players"`
+	Deductions         []Deduction    `json:"deductions,omitempty"`
+	OpponentDeductions []Deduction    `json:"opponent_deductions,omitempty"`
+	// MissingSnapshot and OpponentMissingSnapshot mark a side with no
+	// lineup for the GW (it joined the league later), scored as zero.
+	MissingSnapshot         bool `json:"missing_snapshot,omitempty"`
+	OpponentMissingSnapshot bool `json:"opponent_missing_snapshot,omitempty"`
+	// RosterSource and OpponentRosterSource mark a side whose roster was
+	// reconstructed (RosterSourceReconstructed); its total is the official
+	// match score rather than the sum of its guessed XI.
+	RosterSource         string `json:"roster_source,omitempty"`
+	OpponentRosterSource string `json:"opponent_roster_source,omitempty"`
+	// PlayerDetail attributes the totals to individual starters. The
+	// matchup_breakdown tool leaves it out unless asked for it.
+	PlayerDetail *MatchupPlayers `json:"player_detail,omitempty"`
 }
 
 type MatchupSummary struct {
-	LeagueID       int                `json:"league_id"`
-	Gameweek       int                `json:"gameweek"`
-	GeneratedAtUTC string             `json:"generated_at_utc"`
-	Matchups       []MatchupBreakdown `json:"matchups"`
+	LeagueID int `json:"league_id"`
+	Gameweek int `json:"gameweek"`
+	BuildStamp
+	Matchups []MatchupBreakdown `json:"matchups"`
 }
 
 type PlayerForm struct {
@@ -103,17 +155,34 @@ type PlayerForm struct {
 	Points       int     `json:"points"`
 	PointsPerGW  float64 `json:"points_per_gw"`
 	MinutesPerGW float64 `json:"minutes_per_gw"`
+	XAPer90      float64 `json:"xa_per90"`
+	BonusPerGW   float64 `json:"bonus_per_gw"`
 	Ownership    int     `json:"ownership"`
 	OwnershipPct float64 `json:"ownership_pct"`
 	RiskScore    float64 `json:"risk_score"`
+	// MinutesPattern classifies the minutes series over the horizon; see
+	// classifyMinutesPattern.
+	MinutesPattern string `json:"minutes_pattern"`
+	// LastMinutes holds minutes in each of the last five GWs, oldest first,
+	// leaving out GWs where the player's team had no fixture.
+	LastMinutes []int `json:"last_minutes"`
 }
 
+// Minutes patterns reported in PlayerForm.MinutesPattern.
+const (
+	MinutesNailed    = "nailed"
+	MinutesRotation  = "rotation"
+	MinutesSub       = "sub"
+	MinutesReturning = "returning"
+	MinutesDropped   = "dropped"
+)
+
 type PlayerFormSummary struct {
-	LeagueID       int          `json:"league_id"`
-	AsOfGW         int          `json:"as_of_gw"`
-	Horizon        int          `json:"horizon"`
-	GeneratedAtUTC string       `json:"generated_at_utc"`
-	Players        []PlayerForm `json:"players"`
+	LeagueID int `json:"league_id"`
+	AsOfGW   int `json:"as_of_gw"`
+	Horizon  int `json:"horizon"`
+	BuildStamp
+	Players []PlayerForm `json:"players"`
 }
 
 type WaiverTarget struct {
@@ -128,22 +197,88 @@ type WaiverTarget struct {
 	Score        float64 `json:"score"`
 }
 
+// WaiverTargetsSchemaVersion is the layout of waiver_targets files. Version
+// 2 ranks each position separately; earlier files have no schema_version
+// and only a league-wide top 50, which often held no goalkeepers.
+const WaiverTargetsSchemaVersion = 2
+
+// waiverTargetsPerPosition is how many targets each position keeps.
+const waiverTargetsPerPosition = 15
+
+// WaiverTargetsByPosition is the best targets at each position, best score
+// first.
+type WaiverTargetsByPosition struct {
+	GK  []WaiverTarget `json:"gk"`
+	DEF []WaiverTarget `json:"def"`
+	MID []WaiverTarget `json:"mid"`
+	FWD []WaiverTarget `json:"fwd"`
+}
+
+// WaiverTargetsSummary ranks the unowned players within a risk level.
+// Targets merges the ByPosition lists by score for readers that predate
+// them.
 type WaiverTargetsSummary struct {
-	LeagueID       int            `json:"league_id"`
-	Gameweek       int            `json:"gameweek"`
-	Horizon        int            `json:"horizon"`
-	RiskLevel      string         `json:"risk"`
-	GeneratedAtUTC string         `json:"generated_at_utc"`
-	Targets        []WaiverTarget `json:"targets"`
+	SchemaVersion int    `json:"schema_version"`
+	LeagueID      int    `json:"league_id"`
+	Gameweek      int    `json:"gameweek"`
+	Horizon       int    `json:"horizon"`
+	RiskLevel     string `json:"risk"`
+	BuildStamp
+	Targets    []WaiverTarget           `json:"targets"`
+	ByPosition *WaiverTargetsByPosition `json:"by_position,omitempty"`
+}
+
+// Position returns the targets at positionType (1-4). A file from before
+// ByPosition falls back to that position's share of Targets.
+func (s WaiverTargetsSummary) Position(positionType int) []WaiverTarget {
+	if s.ByPosition != nil {
+		lists := [5][]WaiverTarget{nil, s.ByPosition.GK, s.ByPosition.DEF, s.ByPosition.MID, s.ByPosition.FWD}
+		if positionType >= 1 && positionType <= 4 && lists[positionType] != nil {
+			return lists[positionType]
+		}
+		return []WaiverTarget{}
+	}
+	out := make([]WaiverTarget, 0)
+	for _, t := range s.Targets {
+		if t.PositionType == positionType {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// WaiverTargetsOutdated reports whether raw, a waiver_targets file, was
+// written before WaiverTargetsSchemaVersion and should be rebuilt.
+func WaiverTargetsOutdated(raw []byte) bool {
+	var v struct {
+		SchemaVersion int `json:"schema_version"`
+	}
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return true
+	}
+	return v.SchemaVersion < WaiverTargetsSchemaVersion
+}
+
+// LeagueEntry is one team in league details. PlayerFirstName and
+// PlayerLastName are the manager's own name.
+type LeagueEntry struct {
+	ID              int    `json:"id"`
+	EntryID         int    `json:"entry_id"`
+	EntryName       string `json:"entry_name"`
+	ShortName       string `json:"short_name"`
+	PlayerFirstName string `json:"player_first_name"`
+	PlayerLastName  string `json:"player_last_name"`
+}
+
+// LeagueInfo is the league object in league details.
+type LeagueInfo struct {
+	StartEvent int `json:"start_event"`
 }
 
 type LeagueDetails struct {
-	LeagueEntries []struct {
-		ID        int    `json:"id"`
-		EntryID   int    `json:"entry_id"`
-		EntryName string `json:"entry_name"`
-	} `json:"league_entries"`
-	Matches []struct {
+	League        LeagueInfo    `json:"league"`
+	LeagueEntries []LeagueEntry `json:"league_entries"`
+	Matches       []struct {
 		Event              int  `json:"event"`
 		Finished           bool `json:"finished"`
 		Started            bool `json:"started"`
@@ -154,6 +289,13 @@ type LeagueDetails struct {
 	} `json:"matches"`
 }
 
+// StartGW is the league's first gameweek. A league created mid-season has
+// no entry picks or matches before it; details without a start_event date
+// from GW1.
+func (ld LeagueDetails) StartGW() int {
+	return max(ld.League.StartEvent, 1)
+}
+
 type StandingsRow struct {
 	EntryID        int    `json:"entry_id"`
 	EntryName      string `json:"entry_name"`
@@ -166,13 +308,41 @@ type StandingsRow struct {
 	PointsAgainst  int    `json:"points_against"`
 	MatchPoints    int    `json:"match_points"`
 	TotalFPLPoints int    `json:"total_fpl_points"`
+	// Streak is the current run of identical results, e.g. "W3"; Form is
+	// the last five results, oldest first.
+	Streak string `json:"streak,omitempty"`
+	Form   string `json:"form"`
+	// RankChange is PrevRank minus Rank, so positive means the entry
+	// climbed. Both are zero in GW1.
+	PrevRank   int `json:"prev_rank,omitempty"`
+	RankChange int `json:"rank_change"`
+	// PointsForLast3 averages points for over the last three played
+	// matches; Momentum is that minus the season average.
+	PointsForLast3 float64 `json:"points_for_last3"`
+	PointsForAvg   float64 `json:"points_for_avg"`
+	Momentum       float64 `json:"momentum"`
+	// SeedingExplanation says which tiebreaker separated the entry from an
+	// adjacent row level on match points; empty when there is no such tie.
+	SeedingExplanation string `json:"seeding_explanation,omitempty"`
+	// PythagoreanPct is PF^k / (PF^k + PA^k) and ExpectedWins that times
+	// matches played; WinsOverExpected is wins (draws as half) minus
+	// ExpectedWins, so positive means the entry has been lucky.
+	PythagoreanPct   float64 `json:"pythagorean_pct"`
+	ExpectedWins     float64 `json:"expected_wins"`
+	WinsOverExpected float64 `json:"wins_over_expected"`
+	// AllPlay* is the record against every other entry's score in each GW
+	// played, not just the scheduled opponent's.
+	AllPlayWins   int     `json:"all_play_wins"`
+	AllPlayDraws  int     `json:"all_play_draws"`
+	AllPlayLosses int     `json:"all_play_losses"`
+	AllPlayPct    float64 `json:"all_play_pct"`
 }
 
 type StandingsSummary struct {
-	LeagueID       int            `json:"league_id"`
-	Gameweek       int            `json:"gameweek"`
-	GeneratedAtUTC string         `json:"generated_at_utc"`
-	Rows           []StandingsRow `json:"rows"`
+	LeagueID int `json:"league_id"`
+	Gameweek int `json:"gameweek"`
+	BuildStamp
+	Rows []StandingsRow `json:"rows"`
 }
 
 type EntryTransactions struct {
@@ -187,13 +357,16 @@ type EntryTransactions struct {
 	TotalIn   int    `json:"total_in"`
 	TotalOut  int    `json:"total_out"`
 	Net       int    `json:"net"`
+	// Budget is the entry's moves against the league's transaction cap
+	// through this GW, in capped leagues only.
+	Budget *TransactionBudget `json:"transaction_budget,omitempty"`
 }
 
 type TransactionsSummary struct {
-	LeagueID       int                 `json:"league_id"`
-	Gameweek       int                 `json:"gameweek"`
-	GeneratedAtUTC string              `json:"generated_at_utc"`
-	Entries        []EntryTransactions `json:"entries"`
+	LeagueID int `json:"league_id"`
+	Gameweek int `json:"gameweek"`
+	BuildStamp
+	Entries []EntryTransactions `json:"entries"`
 }
 
 // NegativeBenchContributor identifies a bench player whose deduction makes
@@ -205,21 +378,28 @@ type NegativeBenchContributor struct {
 }
 
 type LineupEfficiencyEntry struct {
-	EntryID                   int                        `json:"entry_id"`
-	EntryName                 string                     `json:"entry_name"`
-	BenchPoints               int                        `json:"bench_points"`
-	BenchPointsPlayed         int                        `json:"bench_points_played"`
-	ZeroMinuteStarters        []int                      `json:"zero_minute_starters"`
-	ZeroMinuteStarterCount    int                        `json:"zero_minute_starter_count"`
+	EntryID                int    `json:"entry_id"`
+	EntryName              string `json:"entry_name"`
+	BenchPoints            int    `json:"bench_points"`
+	BenchPointsPlayed      int    `json:"bench_points_played"`
+	ZeroMinuteStarters     []int  `json:"zero_minute_starters"`
+	ZeroMinuteStarterCount int    `json:"zero_minute_starter_count"`
+	// PendingStarters have no minutes yet but their team still has a fixture
+	// to finish this GW (e.g. the second match of a double gameweek).
+	PendingStarters           []int                      `json:"pending_starters,omitempty"`
 	NegativeBenchContributors []NegativeBenchContributor `json:"negative_bench_contributors,omitempty"`
 	MissingSnapshot           bool                       `json:"missing_snapshot"`
+	// RosterSource is RosterSourceReconstructed for a missing snapshot
+	// whose squad was rebuilt for the league summary; there is still no
+	// real lineup to judge.
+	RosterSource string `json:"roster_source,omitempty"`
 }
 
 type LineupEfficiencySummary struct {
-	LeagueID       int                     `json:"league_id"`
-	Gameweek       int                     `json:"gameweek"`
-	GeneratedAtUTC string                  `json:"generated_at_utc"`
-	Entries        []LineupEfficiencyEntry `json:"entries"`
+	LeagueID int `json:"league_id"`
+	Gameweek int `json:"gameweek"`
+	BuildStamp
+	Entries []LineupEfficiencyEntry `json:"entries"`
 }
 
 type PositionCounts struct {
@@ -230,10 +410,16 @@ type PositionCounts struct {
 	Total int `json:"total"`
 }
 
+// OwnershipEntrySummary is an entry's squad by position: the raw counts,
+// and a depth chart ranking each position by recent points per GW, since a
+// pile of low scorers is no real depth. ThinPositions lists the positions
+// where losing a starter leaves no viable bench cover (see buildDepthChart).
 type OwnershipEntrySummary struct {
-	EntryID   int            `json:"entry_id"`
-	EntryName string         `json:"entry_name"`
-	Counts    PositionCounts `json:"counts"`
+	EntryID       int            `json:"entry_id"`
+	EntryName     string         `json:"entry_name"`
+	Counts        PositionCounts `json:"counts"`
+	DepthChart    DepthChart     `json:"depth_chart"`
+	ThinPositions []string       `json:"thin_positions"`
 }
 
 type PositionHoarder struct {
@@ -243,14 +429,14 @@ type PositionHoarder struct {
 }
 
 type OwnershipScarcitySummary struct {
-	LeagueID       int                          `json:"league_id"`
-	Gameweek       int                          `json:"gameweek"`
-	GeneratedAtUTC string                       `json:"generated_at_utc"`
-	LeagueTotals   PositionCounts               `json:"league_totals"`
-	OwnedTotals    PositionCounts               `json:"owned_totals"`
-	UnownedTotals  PositionCounts               `json:"unowned_totals"`
-	Entries        []OwnershipEntrySummary      `json:"entries"`
-	Hoarders       map[string][]PositionHoarder `json:"hoarders"`
+	LeagueID int `json:"league_id"`
+	Gameweek int `json:"gameweek"`
+	BuildStamp
+	LeagueTotals  PositionCounts               `json:"league_totals"`
+	OwnedTotals   PositionCounts               `json:"owned_totals"`
+	UnownedTotals PositionCounts               `json:"unowned_totals"`
+	Entries       []OwnershipEntrySummary      `json:"entries"`
+	Hoarders      map[string][]PositionHoarder `json:"hoarders"`
 }
 
 type StrengthOfScheduleEntry struct {
@@ -267,11 +453,11 @@ type StrengthOfScheduleEntry struct {
 }
 
 type StrengthOfScheduleSummary struct {
-	LeagueID       int                       `json:"league_id"`
-	Gameweek       int                       `json:"gameweek"`
-	GeneratedAtUTC string                    `json:"generated_at_utc"`
-	TopHalfCutoff  int                       `json:"top_half_cutoff"`
-	Entries        []StrengthOfScheduleEntry `json:"entries"`
+	LeagueID int `json:"league_id"`
+	Gameweek int `json:"gameweek"`
+	BuildStamp
+	TopHalfCutoff int                       `json:"top_half_cutoff"`
+	Entries       []StrengthOfScheduleEntry `json:"entries"`
 }
 
 type FixtureSummary struct {
@@ -287,11 +473,17 @@ type FixtureSummary struct {
 }
 
 type UpcomingFixturesSummary struct {
-	LeagueID       int              `json:"league_id"`
-	AsOfGW         int              `json:"as_of_gw"`
-	Horizon        int              `json:"horizon"`
-	GeneratedAtUTC string           `json:"generated_at_utc"`
-	Fixtures       []FixtureSummary `json:"fixtures"`
+	LeagueID int `json:"league_id"`
+	AsOfGW   int `json:"as_of_gw"`
+	Horizon  int `json:"horizon"`
+	BuildStamp
+	Fixtures []FixtureSummary `json:"fixtures"`
+	// UnscheduledFixtures are postponed fixtures bootstrap lists without a
+	// GW; they join Fixtures once rescheduled.
+	UnscheduledFixtures []FixtureSummary `json:"unscheduled_fixtures"`
+	// Warning is set when bootstrap listed no fixtures at all, so an empty
+	// Fixtures isn't mistaken for a blank run of GWs.
+	Warning string `json:"warning,omitempty"`
 }
 
 type bootstrapMeta struct {
@@ -310,7 +502,39 @@ type bootstrapMeta struct {
 	} `json:"teams"`
 }
 
-func BuildLeagueSummaries(st *store.JSONStore, derivedRoot string, leagueID int, ld LeagueDetails, entryIDs []int, minGW int, maxGW int, horizons []int, riskLevels []string) error {
+// BuildOptions controls which gameweeks BuildLeagueSummaries recomputes.
+type BuildOptions struct {
+	// Force rebuilds every gameweek in range, even finished ones whose
+	// outputs already exist.
+	Force bool
+	// OnlyGWs limits the build to these gameweeks within minGW..maxGW.
+	// Empty means the whole range.
+	OnlyGWs []int
+	// Clock stamps the summaries' GeneratedAtUTC; nil is the wall clock.
+	Clock clock.Clock
+	// Tiebreakers is the league's standings chain after match points; nil
+	// is DefaultTiebreakers.
+	Tiebreakers []string
+}
+
+func (o BuildOptions) includes(gw int) bool {
+	if len(o.OnlyGWs) == 0 {
+		return true
+	}
+	for _, g := range o.OnlyGWs {
+		if g == gw {
+			return true
+		}
+	}
+	return false
+}
+
+// BuildLeagueSummaries writes the per-GW summaries for minGW..maxGW, starting
+// no earlier than the league's start_event. Unless opts.Force is set, a
+// gameweek is skipped when its H2H matches are all finished in league details
+// and every output file for it already exists.
+func BuildLeagueSummaries(st *store.JSONStore, derivedRoot string, leagueID int, ld LeagueDetails, entryIDs []int, minGW int, maxGW int, horizons []int, riskLevels []string, opts BuildOptions) error {
+	clk := opts.Clock
 	meta, teamShort, err := loadBootstrapMeta(st)
 	if err != nil {
 		return err
@@ -325,7 +549,7 @@ func BuildLeagueSummaries(st *store.JSONStore, derivedRoot string, leagueID int,
 	}
 
 	ledgerPath := filepath.Join(derivedRoot, fmt.Sprintf("ledger/%d/event_0.json", leagueID))
-	ledgerRaw, err := os.ReadFile(ledgerPath)
+	ledgerRaw, err := store.ReadDerived(ledgerPath)
 	if err != nil {
 		return err
 	}
@@ -341,9 +565,35 @@ func BuildLeagueSummaries(st *store.JSONStore, derivedRoot string, leagueID int,
 	if err != nil {
 		return err
 	}
+	settings := loadTransactionSettings(st, leagueID)
+	// The timeline replaces a replay from the draft for every GW below, and
+	// is written for the server to do the same.
+	timeline := reconcile.BuildOwnershipTimeline(leagueID, &ledgerOut, transactions, trades, clk)
+	if err := reconcile.WriteOwnershipTimeline(derivedRoot, timeline); err != nil {
+		return err
+	}
+
+	// prevRank carries the standings ranks of prevRankGW forward for the
+	// rank change. Skipped GWs break the chain, and the previous ranks are
+	// then recomputed.
+	minGW = max(minGW, ld.StartGW())
 
+	var prevRank map[int]int
+	prevRankGW := 0
 	for gw := minGW; gw <= maxGW; gw++ {
-		liveByElement, err := loadLiveStatsForPoints(st, gw)
+		if !opts.includes(gw) {
+			continue
+		}
+		if !opts.Force && gwFinished(ld, gw) && outputsExist(summaryOutputPaths(derivedRoot, leagueID, gw, gw == maxGW, horizons, riskLevels)) {
+			continue
+		}
+		liveByElement, pendingTeams, err := loadLiveStatsForPoints(st, gw)
+		if err != nil {
+			return err
+		}
+		// Everything scored for the GW groups players by their position
+		// then; player form below looks forward and keeps today's.
+		gwMeta, err := metaAtGW(derivedRoot, meta, gw, clk)
 		if err != nil {
 			return err
 		}
@@ -352,29 +602,64 @@ func BuildLeagueSummaries(st *store.JSONStore, derivedRoot string, leagueID int,
 		entryPointsByPos := make(map[int]PositionPoints)
 		entryTotals := make(map[int]int)
 		entryBenchTotals := make(map[int]int)
+		entryStarters := make(map[int][]PlayerPoints)
 		entryRosters := make(map[int][]RosterPlayer)
 		snapshotsByEntry := make(map[int]*ledger.EntrySnapshot)
 
+		// A snapshot stubbed as missing (the entry joined after gw) has no
+		// picks, so it scores zero; it's left out of snapshotsByEntry so
+		// lineup efficiency flags it. An entry-GW with no snapshot file at
+		// all (its picks failed to fetch) gets a reconstructed one, which is
+		// also left out: its bench is a guess.
+		missingSnapshot := make(map[int]bool)
+		reconstructed := make(map[int]bool)
+		var ownedAtGW map[int]map[int]bool
 		for _, entryID := range entryIDs {
 			snap, err := loadSnapshot(derivedRoot, leagueID, entryID, gw)
+			if errors.Is(err, fs.ErrNotExist) {
+				if ownedAtGW == nil {
+					ownedAtGW = timeline.OwnersAt(gw)
+				}
+				snap, err = reconstructSnapshot(leagueID, entryID, gw, ownedAtGW[entryID], gwMeta, liveByElement, clock.UTC(clock.Or(clk).Now())), nil
+				reconstructed[entryID] = true
+			}
 			if err != nil {
 				return err
 			}
-			snapshotsByEntry[entryID] = snap
-			entryRosters[entryID] = buildRoster(meta, snap)
-			entryTotals[entryID], entryBenchTotals[entryID], entryPointsByPos[entryID] = computePoints(meta, snap, liveByElement)
+			switch {
+			case snap.Missing:
+				missingSnapshot[entryID] = true
+			case !reconstructed[entryID]:
+				snapshotsByEntry[entryID] = snap
+			}
+			entryRosters[entryID] = buildRoster(gwMeta, snap, liveByElement)
+			entryTotals[entryID], entryBenchTotals[entryID], entryPointsByPos[entryID], entryStarters[entryID] = computePoints(gwMeta, snap, liveByElement)
+		}
+		rosterSource := func(entryID int) string {
+			if reconstructed[entryID] {
+				return RosterSourceReconstructed
+			}
+			return ""
 		}
 
 		summary := LeagueWeekSummary{
-			LeagueID:       leagueID,
-			Gameweek:       gw,
-			GeneratedAtUTC: time.Now().UTC().Format(time.RFC3339),
-			Entries:        make([]ManagerWeekSummary, 0, len(entryIDs)),
+			LeagueID: leagueID,
+			Gameweek: gw,
+			Entries:  make([]ManagerWeekSummary, 0, len(entryIDs)),
 		}
 
 		for _, entryID := range entryIDs {
-			opp := matchOpp[entryID]
+			// An entry with no match in a GW others played (one that joined
+			// the league later) has no opponent.
+			opp, ok := matchOpp[entryID]
+			if !ok && len(matchOpp) > 0 {
+				opp.Missing = true
+			}
 			rec := computeRecord(ld.Matches, entryToLeagueEntry[entryID], gw)
+			starters := entryTotals[entryID]
+			if reconstructed[entryID] && ok {
+				starters = opp.ScoreFor
+			}
 			ms := ManagerWeekSummary{
 				EntryID:      entryID,
 				EntryName:    entryNameByID[entryID],
@@ -385,25 +670,32 @@ func BuildLeagueSummaries(st *store.JSONStore, derivedRoot string, leagueID int,
 				Result:       opp.Result,
 				Record:       rec,
 				Points: PointsSummary{
-					Starters: entryTotals[entryID],
+					Starters: starters,
 					Bench:    entryBenchTotals[entryID],
 				},
 				Roster:          entryRosters[entryID],
+				Deductions:      buildDeductions(gwMeta, entryRosters[entryID], liveByElement),
 				MissingOpponent: opp.Missing,
+				MissingSnapshot: missingSnapshot[entryID],
+				RosterSource:    rosterSource(entryID),
 			}
 			summary.Entries = append(summary.Entries, ms)
 		}
+		seasonPoints, err := seasonPointsBefore(st, gw)
+		if err != nil {
+			return err
+		}
+		summary.Awards = buildWeekAwards(summary.Entries, topSeasonScorers(seasonPoints, premiumCount), reconstructed)
 
 		outPath := filepath.Join(derivedRoot, fmt.Sprintf("summary/league/%d/gw/%d.json", leagueID, gw))
-		if err := writeJSON(outPath, summary); err != nil {
+		if err := writeSummary(outPath, &summary, clk, st); err != nil {
 			return err
 		}
 
 		matchup := MatchupSummary{
-			LeagueID:       leagueID,
-			Gameweek:       gw,
-			GeneratedAtUTC: time.Now().UTC().Format(time.RFC3339),
-			Matchups:       make([]MatchupBreakdown, 0),
+			LeagueID: leagueID,
+			Gameweek: gw,
+			Matchups: make([]MatchupBreakdown, 0),
 		}
 		for _, m := range ld.Matches {
 			if m.Event != gw {
@@ -416,6 +708,15 @@ func BuildLeagueSummaries(st *store.JSONStore, derivedRoot string, leagueID int,
 			bID := leagueEntryToEntry[m.LeagueEntry2]
 			aPts := entryPointsByPos[aID]
 			bPts := entryPointsByPos[bID]
+			// The official match points are authoritative; a reconstructed
+			// side's own sum depends on the guessed XI.
+			aTotal, bTotal := entryTotals[aID], entryTotals[bID]
+			if reconstructed[aID] {
+				aTotal = m.LeagueEntry1Points
+			}
+			if reconstructed[bID] {
+				bTotal = m.LeagueEntry2Points
+			}
 			breakdown := MatchupBreakdown{
 				EntryID:       aID,
 				EntryName:     entryNameByID[aID],
@@ -424,61 +725,87 @@ func BuildLeagueSummaries(st *store.JSONStore, derivedRoot string, leagueID int,
 				Points:        aPts,
 				Opponent:      bPts,
 				Diff:          diffPositionPoints(aPts, bPts),
-				Total:         entryTotals[aID],
-				OpponentTotal: entryTotals[bID],
-				Result:        resultFromScore(entryTotals[aID], entryTotals[bID]),
+				Total:         aTotal,
+				OpponentTotal: bTotal,
+				Result:        resultFromScore(aTotal, bTotal),
+
+				Players:            entryRosters[aID],
+				OpponentPlayers:    entryRosters[bID],
+				Deductions:         buildDeductions(gwMeta, entryRosters[aID], liveByElement),
+				OpponentDeductions: buildDeductions(gwMeta, entryRosters[bID], liveByElement),
+
+				MissingSnapshot:         missingSnapshot[aID],
+				OpponentMissingSnapshot: missingSnapshot[bID],
+				RosterSource:            rosterSource(aID),
+				OpponentRosterSource:    rosterSource(bID),
+
+				PlayerDetail: buildMatchupPlayers(aID, aTotal, entryStarters[aID], bID, bTotal, entryStarters[bID]),
 			}
 			matchup.Matchups = append(matchup.Matchups, breakdown)
 		}
 		outMatchup := filepath.Join(derivedRoot, fmt.Sprintf("summary/matchup/%d/gw/%d.json", leagueID, gw))
-		if err := writeJSON(outMatchup, matchup); err != nil {
+		if err := writeSummary(outMatchup, &matchup, clk, st); err != nil {
 			return err
 		}
 
-		standingsRows, standingsRank := computeStandings(ld.Matches, leagueEntryToEntry, entryNameByID, entryIDs, gw)
+		standingsRows, standingsRank := computeStandings(ld.Matches, leagueEntryToEntry, entryNameByID, entryIDs, gw, opts.Tiebreakers)
+		if prevRankGW != gw-1 {
+			prevRank = nil
+			if gw > ld.StartGW() {
+				_, prevRank = computeStandings(ld.Matches, leagueEntryToEntry, entryNameByID, entryIDs, gw-1, opts.Tiebreakers)
+			}
+		}
+		applyRankChange(standingsRows, prevRank)
+		prevRank, prevRankGW = standingsRank, gw
 		standings := StandingsSummary{
-			LeagueID:       leagueID,
-			Gameweek:       gw,
-			GeneratedAtUTC: time.Now().UTC().Format(time.RFC3339),
-			Rows:           standingsRows,
+			LeagueID: leagueID,
+			Gameweek: gw,
+			Rows:     standingsRows,
 		}
 		outStandings := filepath.Join(derivedRoot, fmt.Sprintf("summary/standings/%d/gw/%d.json", leagueID, gw))
-		if err := writeJSON(outStandings, standings); err != nil {
+		if err := writeSummary(outStandings, &standings, clk, st); err != nil {
 			return err
 		}
 
-		txSummary := buildTransactionsDigest(leagueID, gw, entryIDs, entryNameByID, transactions, trades)
+		txSummary := buildTransactionsDigest(leagueID, gw, entryIDs, entryNameByID, transactions, trades, settings)
 		outTx := filepath.Join(derivedRoot, fmt.Sprintf("summary/transactions/%d/gw/%d.json", leagueID, gw))
-		if err := writeJSON(outTx, txSummary); err != nil {
+		if err := writeSummary(outTx, &txSummary, clk, st); err != nil {
 			return err
 		}
 
-		lineup := buildLineupEfficiency(leagueID, gw, entryIDs, entryNameByID, snapshotsByEntry, liveByElement, meta)
+		lineup := buildLineupEfficiency(leagueID, gw, entryIDs, entryNameByID, snapshotsByEntry, liveByElement, pendingTeams, gwMeta)
+		for i := range lineup.Entries {
+			lineup.Entries[i].RosterSource = rosterSource(lineup.Entries[i].EntryID)
+		}
 		outLineup := filepath.Join(derivedRoot, fmt.Sprintf("summary/lineup_efficiency/%d/gw/%d.json", leagueID, gw))
-		if err := writeJSON(outLineup, lineup); err != nil {
+		if err := writeSummary(outLineup, &lineup, clk, st); err != nil {
 			return err
 		}
 
-		ownership := buildOwnershipScarcity(leagueID, gw, entryIDs, entryNameByID, meta, &ledgerOut, transactions, trades)
+		depthPPG, err := recentPointsPerGW(st, gw, depthHorizon)
+		if err != nil {
+			return err
+		}
+		ownership := buildOwnershipScarcity(leagueID, gw, entryIDs, entryNameByID, gwMeta, timeline.OwnersAt(gw), depthPPG)
 		outOwnership := filepath.Join(derivedRoot, fmt.Sprintf("summary/ownership_scarcity/%d/gw/%d.json", leagueID, gw))
-		if err := writeJSON(outOwnership, ownership); err != nil {
+		if err := writeSummary(outOwnership, &ownership, clk, st); err != nil {
 			return err
 		}
 
 		sos := buildStrengthOfSchedule(leagueID, gw, entryIDs, entryNameByID, ld.Matches, leagueEntryToEntry, standingsRank)
 		outSoS := filepath.Join(derivedRoot, fmt.Sprintf("summary/strength_of_schedule/%d/gw/%d.json", leagueID, gw))
-		if err := writeJSON(outSoS, sos); err != nil {
+		if err := writeSummary(outSoS, &sos, clk, st); err != nil {
 			return err
 		}
 
 		for _, horizon := range horizons {
-			form, err := buildPlayerForm(meta, ledgerOut, transactions, trades, entryIDs, gw, horizon, st)
+			form, err := buildPlayerForm(leagueID, meta, timeline.OwnersAt(gw), entryIDs, gw, horizon, st)
 			if err != nil {
 				return err
 			}
 			if gw == maxGW {
 				outForm := filepath.Join(derivedRoot, fmt.Sprintf("summary/player_form/%d/h%d.json", leagueID, horizon))
-				if err := writeJSON(outForm, form); err != nil {
+				if err := writeSummary(outForm, &form, clk, st); err != nil {
 					return err
 				}
 			}
@@ -489,28 +816,107 @@ func BuildLeagueSummaries(st *store.JSONStore, derivedRoot string, leagueID int,
 					return err
 				}
 				outTargets := filepath.Join(derivedRoot, fmt.Sprintf("summary/waiver_targets/%d/gw/%d_h%d_risk-%s.json", leagueID, gw, horizon, risk))
-				if err := writeJSON(outTargets, targets); err != nil {
+				if err := writeSummary(outTargets, &targets, clk, st); err != nil {
 					return err
 				}
 			}
 		}
 	}
 
+	return writeFixturesSummaries(st, derivedRoot, leagueID, maxGW, horizons, teamShort, clk)
+}
+
+// gwFinished reports whether league details has H2H matches for gw and all
+// of them are finished, so the GW's summaries can no longer change.
+func gwFinished(ld LeagueDetails, gw int) bool {
+	found := false
+	for _, m := range ld.Matches {
+		if m.Event != gw {
+			continue
+		}
+		if !m.Finished {
+			return false
+		}
+		found = true
+	}
+	return found
+}
+
+// summaryOutputPaths lists every file BuildLeagueSummaries writes for gw.
+// player_form is only written for the last GW of the range.
+func summaryOutputPaths(derivedRoot string, leagueID int, gw int, withForm bool, horizons []int, riskLevels []string) []string {
+	paths := []string{
+		fmt.Sprintf("summary/league/%d/gw/%d.json", leagueID, gw),
+		fmt.Sprintf("summary/matchup/%d/gw/%d.json", leagueID, gw),
+		fmt.Sprintf("summary/standings/%d/gw/%d.json", leagueID, gw),
+		fmt.Sprintf("summary/transactions/%d/gw/%d.json", leagueID, gw),
+		fmt.Sprintf("summary/lineup_efficiency/%d/gw/%d.json", leagueID, gw),
+		fmt.Sprintf("summary/ownership_scarcity/%d/gw/%d.json", leagueID, gw),
+		fmt.Sprintf("summary/strength_of_schedule/%d/gw/%d.json", leagueID, gw),
+	}
+	for _, horizon := range horizons {
+		if withForm {
+			paths = append(paths, fmt.Sprintf("summary/player_form/%d/h%d.json", leagueID, horizon))
+		}
+		for _, risk := range riskLevels {
+			paths = append(paths, fmt.Sprintf("summary/waiver_targets/%d/gw/%d_h%d_risk-%s.json", leagueID, gw, horizon, risk))
+		}
+	}
+	for i, p := range paths {
+		paths[i] = filepath.Join(derivedRoot, p)
+	}
+	return paths
+}
+
+func outputsExist(paths []string) bool {
+	for _, p := range paths {
+		if strings.Contains(filepath.ToSlash(p), "/summary/waiver_targets/") {
+			// A file in an older layout is rebuilt like a missing one.
+			raw, err := store.ReadDerived(p)
+			if err != nil || WaiverTargetsOutdated(raw) {
+				return false
+			}
+			continue
+		}
+		if _, err := store.StatDerived(p); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// BuildFixturesSummary writes only the upcoming-fixtures summaries from gw.
+// Unlike BuildLeagueSummaries it needs just bootstrap-static, so it works for
+// a gameweek that hasn't kicked off yet.
+func BuildFixturesSummary(st *store.JSONStore, derivedRoot string, leagueID int, gw int, horizons []int, clk clock.Clock) error {
+	if leagueID == 0 {
+		return fmt.Errorf("league_id is required")
+	}
+	if gw == 0 {
+		return fmt.Errorf("gw is required")
+	}
+	_, teamShort, err := loadBootstrapMeta(st)
+	if err != nil {
+		return err
+	}
+	return writeFixturesSummaries(st, derivedRoot, leagueID, gw, horizons, teamShort, clk)
+}
+
+func writeFixturesSummaries(st *store.JSONStore, derivedRoot string, leagueID int, gw int, horizons []int, teamShort map[int]string, clk clock.Clock) error {
 	for _, horizon := range horizons {
-		fixtures, err := buildUpcomingFixtures(st, leagueID, maxGW, horizon, teamShort)
+		fixtures, err := buildUpcomingFixtures(st, leagueID, gw, horizon, teamShort)
 		if err != nil {
 			return err
 		}
-		outFixtures := filepath.Join(derivedRoot, fmt.Sprintf("summary/fixtures/%d/from_gw/%d_h%d.json", leagueID, maxGW, horizon))
-		if err := writeJSON(outFixtures, fixtures); err != nil {
+		outFixtures := filepath.Join(derivedRoot, fmt.Sprintf("summary/fixtures/%d/from_gw/%d_h%d.json", leagueID, gw, horizon))
+		if err := writeSummary(outFixtures, &fixtures, clk, st); err != nil {
 			return err
 		}
 	}
-
 	return nil
 }
 
-func buildPlayerForm(meta map[int]PlayerMeta, ledgerOut model.DraftLedger, transactions []reconcile.Transaction, trades []reconcile.Trade, entryIDs []int, gw int, horizon int, st *store.JSONStore) (PlayerFormSummary, error) {
+func buildPlayerForm(leagueID int, meta map[int]PlayerMeta, ownedByEntry map[int]map[int]bool, entryIDs []int, gw int, horizon int, st *store.JSONStore) (PlayerFormSummary, error) {
 	start := gw - horizon + 1
 	if start < 1 {
 		start = 1
@@ -518,21 +924,52 @@ func buildPlayerForm(meta map[int]PlayerMeta, ledgerOut model.DraftLedger, trans
 	rolling := make(map[int]struct {
 		Points  int
 		Minutes int
+		XA      float64
+		Bonus   int
 	})
-	for g := start; g <= gw; g++ {
-		liveByElement, err := loadLiveStatsForPoints(st, g)
+	// The minutes series reaches back at least five GWs for LastMinutes even
+	// when the horizon is shorter; only [start, gw] feeds the rolling totals
+	// and the pattern.
+	seriesStart := start
+	if gw-4 < seriesStart {
+		seriesStart = gw - 4
+	}
+	if seriesStart < 1 {
+		seriesStart = 1
+	}
+	type gwMinutes struct {
+		gw      int
+		minutes int
+	}
+	series := make(map[int][]gwMinutes)
+	for g := seriesStart; g <= gw; g++ {
+		liveByElement, teamsPlayed, err := loadLiveFormStats(st, g)
 		if err != nil {
+			if g < start {
+				continue
+			}
 			return PlayerFormSummary{}, err
 		}
+		for id, m := range meta {
+			// A blank GW is not an "available" GW and stays out of the series.
+			if teamsPlayed != nil && !teamsPlayed[m.TeamID] {
+				continue
+			}
+			series[id] = append(series[id], gwMinutes{gw: g, minutes: liveByElement[id].Minutes})
+		}
+		if g < start {
+			continue
+		}
 		for id, stats := range liveByElement {
 			cur := rolling[id]
 			cur.Points += stats.TotalPoints
 			cur.Minutes += stats.Minutes
+			cur.XA += stats.XA
+			cur.Bonus += stats.Bonus
 			rolling[id] = cur
 		}
 	}
 
-	ownedByEntry := reconcile.BuildOwnershipMapAtGW(&ledgerOut, transactions, trades, gw)
 	ownership := make(map[int]int)
 	for _, players := range ownedByEntry {
 		for id := range players {
@@ -550,6 +987,13 @@ func buildPlayerForm(meta map[int]PlayerMeta, ledgerOut model.DraftLedger, trans
 			minutesPct = 1
 		}
 		risk := 1 - minutesPct
+		// xA is expressed per 90 minutes so part-time creators are comparable
+		// with nailed starters; bonus is a plain per-GW average like points.
+		var xaPer90 float64
+		if r.Minutes > 0 {
+			xaPer90 = r.XA / float64(r.Minutes) * 90
+		}
+		bonusPerGW := float64(r.Bonus) / float64(horizon)
 		own := ownership[id]
 		// Guard against empty league (len==0) which would produce NaN/+Inf that
 		// json.Marshal cannot serialise, causing a runtime error.
@@ -557,32 +1001,105 @@ func buildPlayerForm(meta map[int]PlayerMeta, ledgerOut model.DraftLedger, trans
 		if len(entryIDs) > 0 {
 			ownPct = float64(own) / float64(len(entryIDs))
 		}
+		var horizonMinutes []int
+		lastMinutes := make([]int, 0, 5)
+		for _, gm := range series[id] {
+			if gm.gw >= start {
+				horizonMinutes = append(horizonMinutes, gm.minutes)
+			}
+			lastMinutes = append(lastMinutes, gm.minutes)
+		}
+		if len(lastMinutes) > 5 {
+			lastMinutes = lastMinutes[len(lastMinutes)-5:]
+		}
 		players = append(players, PlayerForm{
-			Element:      id,
-			Name:         m.Name,
-			Team:         m.TeamShort,
-			PositionType: m.PositionType,
-			Minutes:      r.Minutes,
-			Points:       r.Points,
-			PointsPerGW:  ppg,
-			MinutesPerGW: mpg,
-			Ownership:    own,
-			OwnershipPct: ownPct,
-			RiskScore:    risk,
+			Element:        id,
+			Name:           m.Name,
+			Team:           m.TeamShort,
+			PositionType:   m.PositionType,
+			Minutes:        r.Minutes,
+			Points:         r.Points,
+			PointsPerGW:    ppg,
+			MinutesPerGW:   mpg,
+			XAPer90:        xaPer90,
+			BonusPerGW:     bonusPerGW,
+			Ownership:      own,
+			OwnershipPct:   ownPct,
+			RiskScore:      risk,
+			MinutesPattern: classifyMinutesPattern(horizonMinutes),
+			LastMinutes:    lastMinutes,
 		})
 	}
 	sort.Slice(players, func(i, j int) bool {
 		return players[i].PointsPerGW > players[j].PointsPerGW
 	})
 	return PlayerFormSummary{
-		LeagueID:       ledgerOut.LeagueID,
-		AsOfGW:         gw,
-		Horizon:        horizon,
-		GeneratedAtUTC: time.Now().UTC().Format(time.RFC3339),
-		Players:        players,
+		LeagueID: leagueID,
+		AsOfGW:   gw,
+		Horizon:  horizon,
+		Players:  players,
 	}, nil
 }
 
+// classifyMinutesPattern labels a per-GW minutes series (oldest first, blank
+// GWs already removed). Rules are checked in order:
+//
+//   - nailed: 60+ in every GW
+//   - dropped: the last two or more GWs are zeros after at least one earlier start
+//   - returning: one run of zeros followed by 60+ in every later GW, with only
+//     starts before it (a ban or injury rather than rotation)
+//   - sub: more than half the GWs under 30 minutes
+//   - rotation: anything else, i.e. starts mixed with benchings or cameos
+//
+// An empty series returns "".
+func classifyMinutesPattern(minutes []int) string {
+	if len(minutes) == 0 {
+		return ""
+	}
+	starts := 0
+	under30 := 0
+	for _, m := range minutes {
+		if m >= 60 {
+			starts++
+		}
+		if m < 30 {
+			under30++
+		}
+	}
+	if starts == len(minutes) {
+		return MinutesNailed
+	}
+
+	trailingZeros := 0
+	for i := len(minutes) - 1; i >= 0 && minutes[i] == 0; i-- {
+		trailingZeros++
+	}
+	if trailingZeros >= 2 && starts > 0 {
+		return MinutesDropped
+	}
+
+	if trailingZeros == 0 {
+		zeroRuns := 0
+		onlyStartsOrZeros := true
+		for i, m := range minutes {
+			if m == 0 && (i == 0 || minutes[i-1] != 0) {
+				zeroRuns++
+			}
+			if m != 0 && m < 60 {
+				onlyStartsOrZeros = false
+			}
+		}
+		if zeroRuns == 1 && onlyStartsOrZeros {
+			return MinutesReturning
+		}
+	}
+
+	if under30*2 > len(minutes) {
+		return MinutesSub
+	}
+	return MinutesRotation
+}
+
 func buildWaiverTargets(form PlayerFormSummary, risk string, entryIDs []int) (WaiverTargetsSummary, error) {
 	thresholds := riskThresholds()
 	thr, ok := thresholds[risk]
@@ -615,24 +1132,42 @@ func buildWaiverTargets(form PlayerFormSummary, risk string, entryIDs []int) (Wa
 		})
 	}
 	sort.Slice(targets, func(i, j int) bool {
-		return targets[i].Score > targets[j].Score
+		if targets[i].Score != targets[j].Score {
+			return targets[i].Score > targets[j].Score
+		}
+		return targets[i].Element < targets[j].Element
 	})
-	if len(targets) > 50 {
-		targets = targets[:50]
+	// Cap each position rather than the whole pool, so a GW where
+	// outfielders dominate the scores still has goalkeepers to suggest.
+	var byPos [5][]WaiverTarget
+	merged := make([]WaiverTarget, 0)
+	for _, t := range targets {
+		pos := t.PositionType
+		if pos < 1 || pos > 4 || len(byPos[pos]) == waiverTargetsPerPosition {
+			continue
+		}
+		byPos[pos] = append(byPos[pos], t)
+		merged = append(merged, t)
+	}
+	for pos := 1; pos <= 4; pos++ {
+		if byPos[pos] == nil {
+			byPos[pos] = []WaiverTarget{}
+		}
 	}
 	return WaiverTargetsSummary{
-		LeagueID:       form.LeagueID,
-		Gameweek:       form.AsOfGW,
-		Horizon:        form.Horizon,
-		RiskLevel:      risk,
-		GeneratedAtUTC: time.Now().UTC().Format(time.RFC3339),
-		Targets:        targets,
+		SchemaVersion: WaiverTargetsSchemaVersion,
+		LeagueID:      form.LeagueID,
+		Gameweek:      form.AsOfGW,
+		Horizon:       form.Horizon,
+		RiskLevel:     risk,
+		Targets:       merged,
+		ByPosition:    &WaiverTargetsByPosition{GK: byPos[1], DEF: byPos[2], MID: byPos[3], FWD: byPos[4]},
 	}, nil
 }
 
 func loadSnapshot(derivedRoot string, leagueID int, entryID int, gw int) (*ledger.EntrySnapshot, error) {
 	snapPath := filepath.Join(derivedRoot, fmt.Sprintf("snapshots/%d/entry/%d/gw/%d.json", leagueID, entryID, gw))
-	raw, err := os.ReadFile(snapPath)
+	raw, err := store.ReadDerived(snapPath)
 	if err != nil {
 		return nil, err
 	}
@@ -674,7 +1209,29 @@ func loadBootstrapMeta(st *store.JSONStore) (map[int]PlayerMeta, map[int]string,
 	return meta, teamShort, nil
 }
 
-func buildRoster(meta map[int]PlayerMeta, snap *ledger.EntrySnapshot) []RosterPlayer {
+// metaAtGW is meta with each player's position type as recorded for gw, so
+// a past GW keeps the grouping it was scored under after FPL reclassifies a
+// player. A GW without a record gets meta's current types recorded for it.
+func metaAtGW(derivedRoot string, meta map[int]PlayerMeta, gw int, clk clock.Clock) (map[int]PlayerMeta, error) {
+	current := make(map[int]int, len(meta))
+	for id, m := range meta {
+		current[id] = m.PositionType
+	}
+	types, err := positions.Ensure(derivedRoot, gw, current, clk)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[int]PlayerMeta, len(meta))
+	for id, m := range meta {
+		if t, ok := types[id]; ok {
+			m.PositionType = t
+		}
+		out[id] = m
+	}
+	return out, nil
+}
+
+func buildRoster(meta map[int]PlayerMeta, snap *ledger.EntrySnapshot, liveByElement map[int]livestats.ElementStats) []RosterPlayer {
 	roster := make([]RosterPlayer, 0, len(snap.Picks))
 	for _, p := range snap.Picks {
 		m := meta[p.Element]
@@ -689,6 +1246,8 @@ func buildRoster(meta map[int]PlayerMeta, snap *ledger.EntrySnapshot) []RosterPl
 			Position:     p.Position,
 			PositionType: m.PositionType,
 			Role:         role,
+			Points:       liveByElement[p.Element].TotalPoints,
+			Minutes:      liveByElement[p.Element].Minutes,
 		})
 	}
 	sort.Slice(roster, func(i, j int) bool {
@@ -697,15 +1256,91 @@ func buildRoster(meta map[int]PlayerMeta, snap *ledger.EntrySnapshot) []RosterPl
 	return roster
 }
 
-func computePoints(meta map[int]PlayerMeta, snap *ledger.EntrySnapshot, liveByElement map[int]points.LiveStats) (int, int, PositionPoints) {
+// deductionLabels names the explain identifiers that can score negative.
+var deductionLabels = map[string]string{
+	"red_cards":        "red card",
+	"yellow_cards":     "yellow card",
+	"own_goals":        "own goal",
+	"penalties_missed": "missed pen",
+	"goals_conceded":   "goals conceded",
+}
+
+// deductionReasons lists the negative items behind stats, most costly first.
+// Without explain data they are rebuilt from the counts using the standard
+// scoring (red -3, own goal -2, missed pen -2, yellow -1, and -1 per two
+// goals conceded for GK/DEF).
+func deductionReasons(stats livestats.ElementStats, positionType int) []DeductionReason {
+	out := make([]DeductionReason, 0, 2)
+	if stats.Explain != nil {
+		for id, pts := range stats.Explain {
+			if pts >= 0 {
+				continue
+			}
+			label, ok := deductionLabels[id]
+			if !ok {
+				label = strings.ReplaceAll(id, "_", " ")
+			}
+			out = append(out, DeductionReason{Reason: label, Points: pts})
+		}
+	} else {
+		add := func(id string, pts int) {
+			if pts < 0 {
+				out = append(out, DeductionReason{Reason: deductionLabels[id], Points: pts})
+			}
+		}
+		add("red_cards", -3*stats.RedCards)
+		add("own_goals", -2*stats.OwnGoals)
+		add("penalties_missed", -2*stats.PenaltiesMissed)
+		add("yellow_cards", -stats.YellowCards)
+		if positionType == 1 || positionType == 2 {
+			add("goals_conceded", -(stats.GoalsConceded / 2))
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Points != out[j].Points {
+			return out[i].Points < out[j].Points
+		}
+		return out[i].Reason < out[j].Reason
+	})
+	return out
+}
+
+// buildDeductions lists the roster players on negative GW points and why.
+func buildDeductions(meta map[int]PlayerMeta, roster []RosterPlayer, liveByElement map[int]livestats.ElementStats) []Deduction {
+	var out []Deduction
+	for _, p := range roster {
+		if p.Points >= 0 {
+			continue
+		}
+		reasons := deductionReasons(liveByElement[p.Element], meta[p.Element].PositionType)
+		d := Deduction{Element: p.Element, Name: p.Name, Role: p.Role, Points: p.Points, Reasons: reasons}
+		for _, r := range reasons {
+			d.Deducted += r.Points
+		}
+		out = append(out, d)
+	}
+	return out
+}
+
+// computePoints totals snap's starters and bench, splits the starters'
+// points by position type, and lists each starter's points in pick order.
+func computePoints(meta map[int]PlayerMeta, snap *ledger.EntrySnapshot, liveByElement map[int]livestats.ElementStats) (int, int, PositionPoints, []PlayerPoints) {
 	starter := 0
 	bench := 0
 	pos := PositionPoints{}
+	starters := make([]PlayerPoints, 0, 11)
 	for _, p := range snap.Picks {
 		stats := liveByElement[p.Element]
 		total := stats.TotalPoints
 		if p.Position <= 11 {
 			starter += total
+			starters = append(starters, PlayerPoints{
+				Element:      p.Element,
+				Name:         meta[p.Element].Name,
+				PositionType: meta[p.Element].PositionType,
+				Points:       total,
+				Minutes:      stats.Minutes,
+			})
 			switch meta[p.Element].PositionType {
 			case 1:
 				pos.GK += total
@@ -720,7 +1355,7 @@ func computePoints(meta map[int]PlayerMeta, snap *ledger.EntrySnapshot, liveByEl
 			bench += total
 		}
 	}
-	return starter, bench, pos
+	return starter, bench, pos, starters
 }
 
 type OpponentInfo struct {
@@ -833,6 +1468,60 @@ type standingsStat struct {
 	losses        int
 	pointsFor     int
 	pointsAgainst int
+	results       []standingsResult
+}
+
+type standingsResult struct {
+	event     int
+	result    string
+	pointsFor int
+}
+
+// formLength and momentumWindow are how many recent matches feed
+// StandingsRow.Form and StandingsRow.PointsForLast3.
+const (
+	formLength     = 5
+	momentumWindow = 3
+)
+
+// trajectory fills the streak, form and momentum fields of row from s.
+func (s *standingsStat) trajectory(row *StandingsRow) {
+	if len(s.results) == 0 {
+		return
+	}
+	sort.SliceStable(s.results, func(i, j int) bool { return s.results[i].event < s.results[j].event })
+	last := s.results[len(s.results)-1].result
+	n := 0
+	for i := len(s.results) - 1; i >= 0 && s.results[i].result == last; i-- {
+		n++
+	}
+	row.Streak = fmt.Sprintf("%s%d", last, n)
+
+	var form strings.Builder
+	for _, r := range s.results[max(0, len(s.results)-formLength):] {
+		form.WriteString(r.result)
+	}
+	row.Form = form.String()
+
+	recent := s.results[max(0, len(s.results)-momentumWindow):]
+	sum := 0
+	for _, r := range recent {
+		sum += r.pointsFor
+	}
+	row.PointsForLast3 = float64(sum) / float64(len(recent))
+	row.PointsForAvg = float64(s.pointsFor) / float64(len(s.results))
+	row.Momentum = row.PointsForLast3 - row.PointsForAvg
+}
+
+// applyRankChange sets PrevRank and RankChange on rows from the previous
+// GW's ranks. A nil prevRank (GW1) leaves both zero.
+func applyRankChange(rows []StandingsRow, prevRank map[int]int) {
+	for i := range rows {
+		if p, ok := prevRank[rows[i].EntryID]; ok && p > 0 {
+			rows[i].PrevRank = p
+			rows[i].RankChange = p - rows[i].Rank
+		}
+	}
 }
 
 func computeStandings(matches []struct {
@@ -843,8 +1532,12 @@ func computeStandings(matches []struct {
 	LeagueEntry1Points int  `json:"league_entry_1_points"`
 	LeagueEntry2       int  `json:"league_entry_2"`
 	LeagueEntry2Points int  `json:"league_entry_2_points"`
-}, leagueEntryToEntry map[int]int, entryNameByID map[int]string, entryIDs []int, gw int) ([]StandingsRow, map[int]int) {
+}, leagueEntryToEntry map[int]int, entryNameByID map[int]string, entryIDs []int, gw int, tiebreakers []string) ([]StandingsRow, map[int]int) {
+	if len(tiebreakers) == 0 {
+		tiebreakers = DefaultTiebreakers
+	}
 	stats := make(map[int]*standingsStat, len(entryIDs))
+	h2h := make(h2hTable)
 	for _, entryID := range entryIDs {
 		stats[entryID] = &standingsStat{}
 	}
@@ -866,15 +1559,21 @@ func computeStandings(matches []struct {
 		a.pointsAgainst += m.LeagueEntry2Points
 		b.pointsFor += m.LeagueEntry2Points
 		b.pointsAgainst += m.LeagueEntry1Points
+		a.results = append(a.results, standingsResult{m.Event, resultFromScore(m.LeagueEntry1Points, m.LeagueEntry2Points), m.LeagueEntry1Points})
+		b.results = append(b.results, standingsResult{m.Event, resultFromScore(m.LeagueEntry2Points, m.LeagueEntry1Points), m.LeagueEntry2Points})
 		if m.LeagueEntry1Points > m.LeagueEntry2Points {
 			a.wins++
 			b.losses++
+			h2h.add(aID, bID, 3)
 		} else if m.LeagueEntry1Points < m.LeagueEntry2Points {
 			b.wins++
 			a.losses++
+			h2h.add(bID, aID, 3)
 		} else {
 			a.draws++
 			b.draws++
+			h2h.add(aID, bID, 1)
+			h2h.add(bID, aID, 1)
 		}
 	}
 
@@ -882,7 +1581,7 @@ func computeStandings(matches []struct {
 	for _, entryID := range entryIDs {
 		s := stats[entryID]
 		matchPoints := s.wins*3 + s.draws
-		rows = append(rows, StandingsRow{
+		row := StandingsRow{
 			EntryID:        entryID,
 			EntryName:      entryNameByID[entryID],
 			Played:         s.played,
@@ -893,23 +1592,14 @@ func computeStandings(matches []struct {
 			PointsAgainst:  s.pointsAgainst,
 			MatchPoints:    matchPoints,
 			TotalFPLPoints: s.pointsFor,
-		})
+		}
+		s.trajectory(&row)
+		rows = append(rows, row)
 	}
+	ApplyPythagorean(rows, DefaultPythagoreanExponent)
+	applyAllPlay(rows, stats)
 
-	sort.Slice(rows, func(i, j int) bool {
-		if rows[i].MatchPoints != rows[j].MatchPoints {
-			return rows[i].MatchPoints > rows[j].MatchPoints
-		}
-		diffI := rows[i].PointsFor - rows[i].PointsAgainst
-		diffJ := rows[j].PointsFor - rows[j].PointsAgainst
-		if diffI != diffJ {
-			return diffI > diffJ
-		}
-		if rows[i].PointsFor != rows[j].PointsFor {
-			return rows[i].PointsFor > rows[j].PointsFor
-		}
-		return rows[i].EntryName < rows[j].EntryName
-	})
+	orderStandings(rows, tiebreakers, h2h)
 
 	rankByEntry := make(map[int]int, len(rows))
 	for i := range rows {
@@ -920,7 +1610,17 @@ func computeStandings(matches []struct {
 	return rows, rankByEntry
 }
 
-func buildTransactionsDigest(leagueID int, gw int, entryIDs []int, entryNameByID map[int]string, transactions []reconcile.Transaction, trades []reconcile.Trade) TransactionsSummary {
+// loadTransactionSettings reads the league settings the transaction budget
+// needs. A league whose settings can't be read is treated as uncapped.
+func loadTransactionSettings(st *store.JSONStore, leagueID int) leagueconfig.Config {
+	settings, err := leagueconfig.Load(st, leagueID)
+	if err != nil {
+		return leagueconfig.Default()
+	}
+	return settings
+}
+
+func buildTransactionsDigest(leagueID int, gw int, entryIDs []int, entryNameByID map[int]string, transactions []reconcile.Transaction, trades []reconcile.Trade, settings leagueconfig.Config) TransactionsSummary {
 	byEntry := make(map[int]*EntryTransactions, len(entryIDs))
 	for _, entryID := range entryIDs {
 		byEntry[entryID] = &EntryTransactions{
@@ -993,6 +1693,7 @@ func buildTransactionsDigest(leagueID int, gw int, entryIDs []int, entryNameByID
 		entry.TotalIn = len(entry.WaiverIn) + len(entry.FreeIn) + len(entry.TradeIn)
 		entry.TotalOut = len(entry.WaiverOut) + len(entry.FreeOut) + len(entry.TradeOut)
 		entry.Net = entry.TotalIn - entry.TotalOut
+		entry.Budget = BuildTransactionBudget(settings.Waivers, transactions, entryID, gw, settings.StartEvent, settings.LastGW())
 		entries = append(entries, *entry)
 	}
 
@@ -1001,14 +1702,42 @@ func buildTransactionsDigest(leagueID int, gw int, entryIDs []int, entryNameByID
 	})
 
 	return TransactionsSummary{
-		LeagueID:       leagueID,
-		Gameweek:       gw,
-		GeneratedAtUTC: time.Now().UTC().Format(time.RFC3339),
-		Entries:        entries,
+		LeagueID: leagueID,
+		Gameweek: gw,
+		Entries:  entries,
+	}
+}
+
+// BuildStandingsSummary writes only the standings file for gw. Standings come
+// from league details alone, so unlike BuildLeagueSummaries it needs no
+// bootstrap, snapshots or live data.
+// BuildStandingsSummary writes the standings through gw, breaking ties with
+// tiebreakers (nil is DefaultTiebreakers).
+func BuildStandingsSummary(st *store.JSONStore, derivedRoot string, leagueID int, gw int, tiebreakers []string, clk clock.Clock) error {
+	if leagueID == 0 {
+		return fmt.Errorf("league_id is required")
+	}
+	if gw == 0 {
+		return fmt.Errorf("gw is required")
+	}
+	raw, err := st.ReadRaw(fmt.Sprintf("league/%d/details.json", leagueID))
+	if err != nil {
+		return err
+	}
+	var ld LeagueDetails
+	if err := json.Unmarshal(raw, &ld); err != nil {
+		return err
+	}
+	entryIDs := make([]int, 0, len(ld.LeagueEntries))
+	for _, e := range ld.LeagueEntries {
+		entryIDs = append(entryIDs, e.EntryID)
 	}
+	standings := ComputeStandings(leagueID, ld, entryIDs, gw, tiebreakers)
+	outStandings := filepath.Join(derivedRoot, fmt.Sprintf("summary/standings/%d/gw/%d.json", leagueID, gw))
+	return writeSummary(outStandings, &standings, clk, st)
 }
 
-func BuildTransactionsSummary(st *store.JSONStore, derivedRoot string, leagueID int, gw int) error {
+func BuildTransactionsSummary(st *store.JSONStore, derivedRoot string, leagueID int, gw int, clk clock.Clock) error {
 	if leagueID == 0 {
 		return fmt.Errorf("league_id is required")
 	}
@@ -1037,17 +1766,77 @@ func BuildTransactionsSummary(st *store.JSONStore, derivedRoot string, leagueID
 	if err != nil {
 		return err
 	}
-	txSummary := buildTransactionsDigest(leagueID, gw, entryIDs, entryNameByID, transactions, trades)
+	txSummary := buildTransactionsDigest(leagueID, gw, entryIDs, entryNameByID, transactions, trades, loadTransactionSettings(st, leagueID))
 	outTx := filepath.Join(derivedRoot, fmt.Sprintf("summary/transactions/%d/gw/%d.json", leagueID, gw))
-	return writeJSON(outTx, txSummary)
+	return writeSummary(outTx, &txSummary, clk, st)
+}
+
+// BuildPlayerFormSummary writes only the player_form summaries as of gw. The
+// rolling window is read straight from the live files, so none of the per-GW
+// league summaries need to be rebuilt. The draft ledger must already exist
+// under derivedRoot.
+func BuildPlayerFormSummary(st *store.JSONStore, derivedRoot string, leagueID int, gw int, horizons []int, clk clock.Clock) error {
+	if leagueID == 0 {
+		return fmt.Errorf("league_id is required")
+	}
+	if gw == 0 {
+		return fmt.Errorf("gw is required")
+	}
+	raw, err := st.ReadRaw(fmt.Sprintf("league/%d/details.json", leagueID))
+	if err != nil {
+		return err
+	}
+	var ld LeagueDetails
+	if err := json.Unmarshal(raw, &ld); err != nil {
+		return err
+	}
+	entryIDs := make([]int, 0, len(ld.LeagueEntries))
+	for _, e := range ld.LeagueEntries {
+		entryIDs = append(entryIDs, e.EntryID)
+	}
+	meta, _, err := loadBootstrapMeta(st)
+	if err != nil {
+		return err
+	}
+	ledgerRaw, err := store.ReadDerived(filepath.Join(derivedRoot, fmt.Sprintf("ledger/%d/event_0.json", leagueID)))
+	if err != nil {
+		return err
+	}
+	var ledgerOut model.DraftLedger
+	if err := json.Unmarshal(ledgerRaw, &ledgerOut); err != nil {
+		return err
+	}
+	transactions, err := loadTransactions(st, leagueID)
+	if err != nil {
+		return err
+	}
+	trades, err := loadTrades(st, leagueID)
+	if err != nil {
+		return err
+	}
+	owned := reconcile.BuildOwnershipMapAtGW(&ledgerOut, transactions, trades, gw)
+	for _, horizon := range horizons {
+		form, err := buildPlayerForm(leagueID, meta, owned, entryIDs, gw, horizon, st)
+		if err != nil {
+			return err
+		}
+		outForm := filepath.Join(derivedRoot, fmt.Sprintf("summary/player_form/%d/h%d.json", leagueID, horizon))
+		if err := writeSummary(outForm, &form, clk, st); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-func buildLineupEfficiency(leagueID int, gw int, entryIDs []int, entryNameByID map[int]string, snapshots map[int]*ledger.EntrySnapshot, liveByElement map[int]points.LiveStats, meta map[int]PlayerMeta) LineupEfficiencySummary {
+// buildLineupEfficiency reports bench points and zero-minute starters per
+// entry. Live minutes are GW totals, so a starter is only counted as a
+// zero-minute starter once every fixture for their team is finished;
+// pendingTeams holds the teams that still have one to play.
+func buildLineupEfficiency(leagueID int, gw int, entryIDs []int, entryNameByID map[int]string, snapshots map[int]*ledger.EntrySnapshot, liveByElement map[int]livestats.ElementStats, pendingTeams map[int]bool, meta map[int]PlayerMeta) LineupEfficiencySummary {
 	out := LineupEfficiencySummary{
-		LeagueID:       leagueID,
-		Gameweek:       gw,
-		GeneratedAtUTC: time.Now().UTC().Format(time.RFC3339),
-		Entries:        make([]LineupEfficiencyEntry, 0, len(entryIDs)),
+		LeagueID: leagueID,
+		Gameweek: gw,
+		Entries:  make([]LineupEfficiencyEntry, 0, len(entryIDs)),
 	}
 	for _, entryID := range entryIDs {
 		snap := snapshots[entryID]
@@ -1062,13 +1851,18 @@ func buildLineupEfficiency(leagueID int, gw int, entryIDs []int, entryNameByID m
 		benchPoints := 0
 		benchPointsPlayed := 0
 		zeroMinuteStarters := make([]int, 0)
+		pendingStarters := make([]int, 0)
 		negContribs := make([]NegativeBenchContributor, 0)
 
 		for _, p := range snap.Picks {
 			stats := liveByElement[p.Element]
 			if p.Position <= 11 {
 				if stats.Minutes == 0 {
-					zeroMinuteStarters = append(zeroMinuteStarters, p.Element)
+					if pendingTeams[meta[p.Element].TeamID] {
+						pendingStarters = append(pendingStarters, p.Element)
+					} else {
+						zeroMinuteStarters = append(zeroMinuteStarters, p.Element)
+					}
 				}
 			} else {
 				benchPoints += stats.TotalPoints
@@ -1096,13 +1890,17 @@ func buildLineupEfficiency(leagueID int, gw int, entryIDs []int, entryNameByID m
 		if len(negContribs) > 0 {
 			entry.NegativeBenchContributors = negContribs
 		}
+		if len(pendingStarters) > 0 {
+			entry.PendingStarters = pendingStarters
+		}
 		out.Entries = append(out.Entries, entry)
 	}
 	return out
 }
 
-func buildOwnershipScarcity(leagueID int, gw int, entryIDs []int, entryNameByID map[int]string, meta map[int]PlayerMeta, ledgerOut *model.DraftLedger, transactions []reconcile.Transaction, trades []reconcile.Trade) OwnershipScarcitySummary {
-	owned := reconcile.BuildOwnershipMapAtGW(ledgerOut, transactions, trades, gw)
+// buildOwnershipScarcity counts each entry's squad by position and charts
+// its depth from ppg, each player's recent points per GW.
+func buildOwnershipScarcity(leagueID int, gw int, entryIDs []int, entryNameByID map[int]string, meta map[int]PlayerMeta, owned map[int]map[int]bool, ppg map[int]float64) OwnershipScarcitySummary {
 
 	allTotals := PositionCounts{}
 	for _, m := range meta {
@@ -1117,10 +1915,13 @@ func buildOwnershipScarcity(leagueID int, gw int, entryIDs []int, entryNameByID
 			addPositionCount(&counts, meta[elementID].PositionType)
 			addPositionCount(&ownedTotals, meta[elementID].PositionType)
 		}
+		chart, thin := buildDepthChart(owned[entryID], meta, ppg)
 		entrySummaries = append(entrySummaries, OwnershipEntrySummary{
-			EntryID:   entryID,
-			EntryName: entryNameByID[entryID],
-			Counts:    counts,
+			EntryID:       entryID,
+			EntryName:     entryNameByID[entryID],
+			Counts:        counts,
+			DepthChart:    chart,
+			ThinPositions: thin,
 		})
 	}
 
@@ -1140,14 +1941,13 @@ func buildOwnershipScarcity(leagueID int, gw int, entryIDs []int, entryNameByID
 	}
 
 	return OwnershipScarcitySummary{
-		LeagueID:       leagueID,
-		Gameweek:       gw,
-		GeneratedAtUTC: time.Now().UTC().Format(time.RFC3339),
-		LeagueTotals:   allTotals,
-		OwnedTotals:    ownedTotals,
-		UnownedTotals:  unownedTotals,
-		Entries:        entrySummaries,
-		Hoarders:       hoarders,
+		LeagueID:      leagueID,
+		Gameweek:      gw,
+		LeagueTotals:  allTotals,
+		OwnedTotals:   ownedTotals,
+		UnownedTotals: unownedTotals,
+		Entries:       entrySummaries,
+		Hoarders:      hoarders,
 	}
 }
 
@@ -1239,11 +2039,10 @@ func buildStrengthOfSchedule(leagueID int, gw int, entryIDs []int, entryNameByID
 	})
 
 	return StrengthOfScheduleSummary{
-		LeagueID:       leagueID,
-		Gameweek:       gw,
-		GeneratedAtUTC: time.Now().UTC().Format(time.RFC3339),
-		TopHalfCutoff:  topHalf,
-		Entries:        entries,
+		LeagueID:      leagueID,
+		Gameweek:      gw,
+		TopHalfCutoff: topHalf,
+		Entries:       entries,
 	}
 }
 
@@ -1252,61 +2051,96 @@ func buildUpcomingFixtures(st *store.JSONStore, leagueID int, asOfGW int, horizo
 	if err != nil {
 		return UpcomingFixturesSummary{}, err
 	}
-	var resp struct {
-		Fixtures map[string][]struct {
-			ID          int    `json:"id"`
-			Event       int    `json:"event"`
-			TeamH       int    `json:"team_h"`
-			TeamA       int    `json:"team_a"`
-			KickoffTime string `json:"kickoff_time"`
-			Finished    bool   `json:"finished"`
-			Started     bool   `json:"started"`
-		} `json:"fixtures"`
-	}
-	if err := json.Unmarshal(raw, &resp); err != nil {
+	set, err := fixtures.Parse(raw)
+	if err != nil {
 		return UpcomingFixturesSummary{}, err
 	}
 
-	fixtures := make([]FixtureSummary, 0)
+	summarize := func(f fixtures.Fixture) FixtureSummary {
+		return FixtureSummary{
+			FixtureID:  f.ID,
+			Event:      f.Event,
+			TeamH:      f.TeamH,
+			TeamA:      f.TeamA,
+			TeamHShort: teamShort[f.TeamH],
+			TeamAShort: teamShort[f.TeamA],
+			KickoffUTC: normalizeKickoff(f.Kickoff),
+			Finished:   f.Finished,
+			Started:    f.Started,
+		}
+	}
+	upcoming := make([]FixtureSummary, 0)
 	start := asOfGW
 	if start < 1 {
 		start = 1
 	}
 	end := asOfGW + horizon - 1
-	for gw := start; gw <= end; gw++ {
-		key := strconv.Itoa(gw)
-		list := resp.Fixtures[key]
-		for _, f := range list {
-			fixtures = append(fixtures, FixtureSummary{
-				FixtureID:  f.ID,
-				Event:      f.Event,
-				TeamH:      f.TeamH,
-				TeamA:      f.TeamA,
-				TeamHShort: teamShort[f.TeamH],
-				TeamAShort: teamShort[f.TeamA],
-				KickoffUTC: f.KickoffTime,
-				Finished:   f.Finished,
-				Started:    f.Started,
-			})
+	for _, f := range set.Fixtures {
+		if f.Event < start || f.Event > end {
+			continue
 		}
+		upcoming = append(upcoming, summarize(f))
+	}
+	unscheduled := make([]FixtureSummary, 0)
+	for _, f := range set.Unscheduled() {
+		unscheduled = append(unscheduled, summarize(f))
 	}
 
-	sort.Slice(fixtures, func(i, j int) bool {
-		if fixtures[i].Event != fixtures[j].Event {
-			return fixtures[i].Event < fixtures[j].Event
+	sort.Slice(upcoming, func(i, j int) bool {
+		if upcoming[i].Event != upcoming[j].Event {
+			return upcoming[i].Event < upcoming[j].Event
 		}
-		return fixtures[i].KickoffUTC < fixtures[j].KickoffUTC
+		return upcoming[i].KickoffUTC < upcoming[j].KickoffUTC
 	})
 
 	return UpcomingFixturesSummary{
-		LeagueID:       leagueID,
-		AsOfGW:         asOfGW,
-		Horizon:        horizon,
-		GeneratedAtUTC: time.Now().UTC().Format(time.RFC3339),
-		Fixtures:       fixtures,
+		LeagueID:            leagueID,
+		AsOfGW:              asOfGW,
+		Horizon:             horizon,
+		Fixtures:            upcoming,
+		UnscheduledFixtures: unscheduled,
+		Warning:             set.Warning(),
 	}, nil
 }
 
+// apiTimeLayouts are the timestamp shapes seen in bootstrap and game data:
+// RFC 3339 normally, but kickoffs have also come back without seconds,
+// without a zone or with a space for the T. A missing zone means UTC.
+var apiTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+}
+
+// ParseAPITime parses a timestamp from the FPL API in any of the shapes it
+// has been seen in.
+func ParseAPITime(s string) (time.Time, bool) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, false
+	}
+	for _, layout := range apiTimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.UTC(), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// normalizeKickoff rewrites a kickoff as RFC 3339 UTC so kickoffs sort and
+// compare as strings; one that can't be parsed is kept as given.
+func normalizeKickoff(s string) string {
+	t, ok := ParseAPITime(s)
+	if !ok {
+		return s
+	}
+	return t.Format(time.RFC3339)
+}
+
 func addPositionCount(c *PositionCounts, pos int) {
 	switch pos {
 	case 1:
@@ -1393,38 +2227,41 @@ func riskThresholds() map[string]float64 {
 	}
 }
 
-type liveResponse struct {
-	Elements map[string]struct {
-		Stats struct {
-			Minutes     int `json:"minutes"`
-			TotalPoints int `json:"total_points"`
-		} `json:"stats"`
-	} `json:"elements"`
-}
-
-func loadLiveStatsForPoints(st *store.JSONStore, gw int) (map[int]points.LiveStats, error) {
-	raw, err := st.ReadRaw(fmt.Sprintf("gw/%d/live.json", gw))
+// loadLiveFormStats returns per-element live stats for gw and the set of
+// team ids with a fixture that GW. The team set is nil when live.json carries
+// no fixtures list, meaning every team is treated as having played.
+func loadLiveFormStats(st *store.JSONStore, gw int) (map[int]livestats.ElementStats, map[int]bool, error) {
+	data, err := livestats.LoadGW(st, gw)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-
-	var resp liveResponse
-	if err := json.Unmarshal(raw, &resp); err != nil {
-		return nil, err
+	var teamsPlayed map[int]bool
+	if len(data.Fixtures) > 0 {
+		teamsPlayed = make(map[int]bool, len(data.Fixtures)*2)
+		for _, f := range data.Fixtures {
+			teamsPlayed[f.TeamH] = true
+			teamsPlayed[f.TeamA] = true
+		}
 	}
+	return data.Elements, teamsPlayed, nil
+}
 
-	out := make(map[int]points.LiveStats, len(resp.Elements))
-	for k, v := range resp.Elements {
-		id, err := strconv.Atoi(k)
-		if err != nil {
-			continue
-		}
-		out[id] = points.LiveStats{
-			Minutes:     v.Stats.Minutes,
-			TotalPoints: v.Stats.TotalPoints,
+// loadLiveStatsForPoints returns per-element GW totals and the teams with a
+// fixture in gw that hasn't finished. A team in a double gameweek stays
+// pending until both of its fixtures are done.
+func loadLiveStatsForPoints(st *store.JSONStore, gw int) (map[int]livestats.ElementStats, map[int]bool, error) {
+	data, err := livestats.LoadGW(st, gw)
+	if err != nil {
+		return nil, nil, err
+	}
+	pending := make(map[int]bool)
+	for _, f := range data.Fixtures {
+		if !f.Finished {
+			pending[f.TeamH] = true
+			pending[f.TeamA] = true
 		}
 	}
-	return out, nil
+	return data.Elements, pending, nil
 }
 
 func loadTransactions(st *store.JSONStore, leagueID int) ([]reconcile.Transaction, error) {
@@ -1456,15 +2293,5 @@ func loadTrades(st *store.JSONStore, leagueID int) ([]reconcile.Trade, error) {
 }
 
 func writeJSON(path string, v any) error {
-	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
-		return err
-	}
-
-	b, err := json.MarshalIndent(v, "", "  ")
-	if err != nil {
-		return err
-	}
-
-	b = append(b, '\n')
-	return os.WriteFile(path, b, 0o644)
+	return store.WriteDerivedJSON(path, v)
 }
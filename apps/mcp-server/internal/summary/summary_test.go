@@ -6,11 +6,11 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/clock"
 	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/ledger"
-	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/model"
-	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/points"
-	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/reconcile"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/livestats"
 	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
 )
 
@@ -18,6 +18,9 @@ import (
 // Helpers
 // ---------------------------------------------------------------------------
 
+// testClock pins every build in these tests to one instant.
+var testClock = clock.Fixed(time.Date(2025, 10, 18, 12, 0, 0, 0, time.UTC))
+
 // writeLiveJSON writes a minimal gw/<gw>/live.json with the given element stats.
 func writeLiveJSON(t *testing.T, rawRoot string, gw int, elements map[string]any) {
 	t.Helper()
@@ -78,10 +81,9 @@ func TestBuildPlayerForm_EmptyEntryIDs(t *testing.T) {
 	// Empty entryIDs is the critical edge case: without the guard,
 	// float64(n) / float64(0) produces +Inf which json.Marshal rejects.
 	summary, err := buildPlayerForm(
+		0,
 		meta,
-		model.DraftLedger{},
-		[]reconcile.Transaction{},
-		[]reconcile.Trade{},
+		map[int]map[int]bool{},
 		[]int{}, // empty — triggers division by zero without the guard
 		1,       // gw
 		1,       // horizon
@@ -125,10 +127,9 @@ func TestBuildPlayerForm_NormalLeague(t *testing.T) {
 	}
 
 	summary, err := buildPlayerForm(
+		0,
 		meta,
-		model.DraftLedger{},
-		[]reconcile.Transaction{},
-		[]reconcile.Trade{},
+		map[int]map[int]bool{},
 		[]int{101, 102, 103, 104}, // 4 entries, nobody owns anyone
 		5,
 		1,
@@ -186,18 +187,18 @@ func TestBuildLineupEfficiency_NegativeBenchContributor(t *testing.T) {
 		500: {Picks: picks},
 	}
 
-	liveByElement := map[int]points.LiveStats{}
+	liveByElement := map[int]livestats.ElementStats{}
 	for _, p := range picks {
-		liveByElement[p.Element] = points.LiveStats{Minutes: 90, TotalPoints: 2}
+		liveByElement[p.Element] = livestats.ElementStats{Minutes: 90, TotalPoints: 2}
 	}
 	// Override bench player 99: -2 points deduction, played 90 mins.
-	liveByElement[99] = points.LiveStats{Minutes: 90, TotalPoints: -2}
+	liveByElement[99] = livestats.ElementStats{Minutes: 90, TotalPoints: -2}
 
 	meta := map[int]PlayerMeta{
 		99: {ID: 99, Name: "Deducted Player"},
 	}
 
-	out := buildLineupEfficiency(1, 1, []int{500}, map[int]string{500: "Test FC"}, snapshots, liveByElement, meta)
+	out := buildLineupEfficiency(1, 1, []int{500}, map[int]string{500: "Test FC"}, snapshots, liveByElement, nil, meta)
 
 	if len(out.Entries) != 1 {
 		t.Fatalf("expected 1 entry, got %d", len(out.Entries))
@@ -230,16 +231,16 @@ func TestBuildLineupEfficiency_NegativeBenchContributor(t *testing.T) {
 // non-negative points — it should not pollute clean output.
 func TestBuildLineupEfficiency_NoBenchContributorsWhenPositive(t *testing.T) {
 	picks := make([]ledger.EntryPick, 15)
-	liveByElement := map[int]points.LiveStats{}
+	liveByElement := map[int]livestats.ElementStats{}
 	for i := 0; i < 15; i++ {
 		picks[i] = ledger.EntryPick{Element: i + 1, Position: i + 1}
-		liveByElement[i+1] = points.LiveStats{Minutes: 90, TotalPoints: 5}
+		liveByElement[i+1] = livestats.ElementStats{Minutes: 90, TotalPoints: 5}
 	}
 	snapshots := map[int]*ledger.EntrySnapshot{
 		500: {Picks: picks},
 	}
 	meta := map[int]PlayerMeta{}
-	out := buildLineupEfficiency(1, 1, []int{500}, map[int]string{500: "Clean FC"}, snapshots, liveByElement, meta)
+	out := buildLineupEfficiency(1, 1, []int{500}, map[int]string{500: "Clean FC"}, snapshots, liveByElement, nil, meta)
 
 	if len(out.Entries) != 1 {
 		t.Fatalf("expected 1 entry, got %d", len(out.Entries))
@@ -248,3 +249,785 @@ func TestBuildLineupEfficiency_NoBenchContributorsWhenPositive(t *testing.T) {
 		t.Errorf("expected no negative bench contributors, got %v", out.Entries[0].NegativeBenchContributors)
 	}
 }
+
+// TestBuildLineupEfficiency_DoubleGWPending checks that a starter who sat out
+// the first match of a double gameweek isn't flagged as a zero-minute starter
+// while their team's second fixture is still to be played.
+func TestBuildLineupEfficiency_DoubleGWPending(t *testing.T) {
+	rawRoot := t.TempDir()
+	// Team 10 has played once and plays again; team 30 is done.
+	writeTestJSON(t, filepath.Join(rawRoot, "gw", "1", "live.json"), map[string]any{
+		"elements": map[string]any{
+			"1": map[string]any{"stats": map[string]any{"minutes": 0, "total_points": 0}},
+			"2": map[string]any{"stats": map[string]any{"minutes": 0, "total_points": 0}},
+		},
+		"fixtures": []any{
+			map[string]any{"id": 1, "team_h": 10, "team_a": 20, "finished": true},
+			map[string]any{"id": 2, "team_h": 30, "team_a": 10, "finished": false, "started": false},
+			map[string]any{"id": 3, "team_h": 30, "team_a": 40, "finished": true},
+		},
+	})
+	liveByElement, pendingTeams, err := loadLiveStatsForPoints(store.NewJSONStore(rawRoot), 1)
+	if err != nil {
+		t.Fatalf("loadLiveStatsForPoints: %v", err)
+	}
+	if !pendingTeams[10] || !pendingTeams[30] || pendingTeams[20] || pendingTeams[40] {
+		t.Errorf("pending teams = %v, want 10 and 30", pendingTeams)
+	}
+
+	snapshots := map[int]*ledger.EntrySnapshot{
+		500: {Picks: []ledger.EntryPick{{Element: 1, Position: 1}, {Element: 2, Position: 2}}},
+	}
+	meta := map[int]PlayerMeta{
+		1: {ID: 1, TeamID: 10},
+		2: {ID: 2, TeamID: 20},
+	}
+	out := buildLineupEfficiency(1, 1, []int{500}, map[int]string{500: "DGW FC"}, snapshots, liveByElement, pendingTeams, meta)
+	entry := out.Entries[0]
+	if entry.ZeroMinuteStarterCount != 1 || entry.ZeroMinuteStarters[0] != 2 {
+		t.Errorf("zero-minute starters = %v, want [2]", entry.ZeroMinuteStarters)
+	}
+	if len(entry.PendingStarters) != 1 || entry.PendingStarters[0] != 1 {
+		t.Errorf("pending starters = %v, want [1]", entry.PendingStarters)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// buildPlayerForm — xA per 90 and bonus per GW
+// ---------------------------------------------------------------------------
+
+// TestBuildPlayerForm_XAAndBonus verifies that string-encoded expected_assists
+// values (as returned by the live endpoint) are parsed and aggregated into
+// xa_per90, and that bonus is averaged over the horizon.
+func TestBuildPlayerForm_XAAndBonus(t *testing.T) {
+	rawRoot := t.TempDir()
+	writeLiveJSON(t, rawRoot, 1, map[string]any{
+		"10": map[string]any{"stats": map[string]any{"minutes": 90, "total_points": 8, "expected_assists": "0.45", "bonus": 3}},
+	})
+	writeLiveJSON(t, rawRoot, 2, map[string]any{
+		"10": map[string]any{"stats": map[string]any{"minutes": 90, "total_points": 2, "expected_assists": "0.15", "bonus": 0}},
+	})
+
+	st := store.NewJSONStore(rawRoot)
+	meta := map[int]PlayerMeta{
+		10: {ID: 10, Name: "Saka", PositionType: 3, TeamShort: "ARS"},
+	}
+
+	summary, err := buildPlayerForm(0, meta, nil, []int{1}, 2, 2, st)
+	if err != nil {
+		t.Fatalf("buildPlayerForm returned error: %v", err)
+	}
+	if len(summary.Players) != 1 {
+		t.Fatalf("expected 1 player, got %d", len(summary.Players))
+	}
+	p := summary.Players[0]
+	if math.Abs(p.XAPer90-0.30) > 1e-9 {
+		t.Errorf("XAPer90 = %f, want 0.30", p.XAPer90)
+	}
+	if math.Abs(p.BonusPerGW-1.5) > 1e-9 {
+		t.Errorf("BonusPerGW = %f, want 1.5", p.BonusPerGW)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// BuildLeagueSummaries — incremental rebuilds
+// ---------------------------------------------------------------------------
+
+func writeTestJSON(t *testing.T, path string, v any) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if err := os.WriteFile(path, b, 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+}
+
+// writeIncrementalLeague writes a two-entry league with GWs 1–2 finished and
+// GW 3 in progress, using root as both the raw and derived root.
+func writeIncrementalLeague(t *testing.T, root string) LeagueDetails {
+	t.Helper()
+	writeTestJSON(t, filepath.Join(root, "bootstrap/bootstrap-static.json"), map[string]any{
+		"elements": []any{map[string]any{"id": 1, "web_name": "Salah", "team": 10, "element_type": 3}},
+		"teams":    []any{map[string]any{"id": 10, "short_name": "LIV"}},
+		"fixtures": map[string]any{},
+	})
+	matches := []any{}
+	for gw := 1; gw <= 3; gw++ {
+		matches = append(matches, map[string]any{
+			"event": gw, "started": true, "finished": gw < 3,
+			"league_entry_1": 1, "league_entry_1_points": 10,
+			"league_entry_2": 2, "league_entry_2_points": 8,
+		})
+		writeLiveJSON(t, root, gw, map[string]any{
+			"1": map[string]any{"stats": map[string]any{"minutes": 90, "total_points": 5}},
+		})
+		for _, entryID := range []int{200, 201} {
+			writeTestJSON(t, filepath.Join(root, "snapshots/100/entry", itoa(entryID), "gw", itoa(gw)+".json"), map[string]any{
+				"entry_id": entryID, "gameweek": gw,
+				"picks": []any{map[string]any{"element": 1, "position": 1}},
+			})
+		}
+	}
+	details := map[string]any{
+		"league_entries": []any{
+			map[string]any{"id": 1, "entry_id": 200, "entry_name": "Alpha FC"},
+			map[string]any{"id": 2, "entry_id": 201, "entry_name": "Beta FC"},
+		},
+		"matches": matches,
+	}
+	writeTestJSON(t, filepath.Join(root, "league/100/details.json"), details)
+	writeTestJSON(t, filepath.Join(root, "league/100/transactions.json"), map[string]any{"transactions": []any{}})
+	writeTestJSON(t, filepath.Join(root, "league/100/trades.json"), map[string]any{"trades": []any{}})
+	writeTestJSON(t, filepath.Join(root, "ledger/100/event_0.json"), map[string]any{"league_id": 100})
+
+	raw, err := json.Marshal(details)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ld LeagueDetails
+	if err := json.Unmarshal(raw, &ld); err != nil {
+		t.Fatal(err)
+	}
+	return ld
+}
+
+func TestBuildLeagueSummaries_Incremental(t *testing.T) {
+	const sentinel = `{"sentinel":true}`
+	matchupPath := func(root string, gw int) string {
+		return filepath.Join(root, "summary/matchup/100/gw", itoa(gw)+".json")
+	}
+	readFile := func(t *testing.T, path string) string {
+		t.Helper()
+		b, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("read %s: %v", path, err)
+		}
+		return string(b)
+	}
+	build := func(t *testing.T, root string, ld LeagueDetails, opts BuildOptions) {
+		t.Helper()
+		st := store.NewJSONStore(root)
+		if err := BuildLeagueSummaries(st, root, 100, ld, []int{200, 201}, 1, 3, []int{5}, []string{"med"}, opts); err != nil {
+			t.Fatalf("BuildLeagueSummaries: %v", err)
+		}
+	}
+
+	t.Run("SkipsFinishedGWsWithOutputs", func(t *testing.T) {
+		root := t.TempDir()
+		ld := writeIncrementalLeague(t, root)
+		build(t, root, ld, BuildOptions{Clock: testClock})
+		for gw := 1; gw <= 3; gw++ {
+			if err := os.WriteFile(matchupPath(root, gw), []byte(sentinel), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		build(t, root, ld, BuildOptions{Clock: testClock})
+		for gw := 1; gw <= 2; gw++ {
+			if got := readFile(t, matchupPath(root, gw)); got != sentinel {
+				t.Errorf("GW%d matchup was rewritten although GW%d is finished", gw, gw)
+			}
+		}
+		if got := readFile(t, matchupPath(root, 3)); got == sentinel {
+			t.Error("GW3 matchup was skipped although GW3 is unfinished")
+		}
+	})
+
+	t.Run("RebuildsFinishedGWWithMissingOutput", func(t *testing.T) {
+		root := t.TempDir()
+		ld := writeIncrementalLeague(t, root)
+		build(t, root, ld, BuildOptions{Clock: testClock})
+		if err := os.WriteFile(matchupPath(root, 1), []byte(sentinel), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Remove(filepath.Join(root, "summary/standings/100/gw/1.json")); err != nil {
+			t.Fatal(err)
+		}
+		build(t, root, ld, BuildOptions{Clock: testClock})
+		if got := readFile(t, matchupPath(root, 1)); got == sentinel {
+			t.Error("GW1 was skipped although its standings file was missing")
+		}
+	})
+
+	t.Run("RebuildsFinishedGWWithOutdatedWaiverTargets", func(t *testing.T) {
+		root := t.TempDir()
+		ld := writeIncrementalLeague(t, root)
+		build(t, root, ld, BuildOptions{Clock: testClock})
+		targetsPath := filepath.Join(root, "summary/waiver_targets/100/gw/1_h5_risk-med.json")
+		writeTestJSON(t, targetsPath, map[string]any{"league_id": 100, "gameweek": 1, "targets": []any{}})
+		build(t, root, ld, BuildOptions{Clock: testClock})
+		if raw := readFile(t, targetsPath); WaiverTargetsOutdated([]byte(raw)) {
+			t.Errorf("GW1 waiver targets kept the old layout: %s", raw)
+		}
+	})
+
+	t.Run("OnlyGWsLeavesOtherGWsAlone", func(t *testing.T) {
+		root := t.TempDir()
+		ld := writeIncrementalLeague(t, root)
+		build(t, root, ld, BuildOptions{OnlyGWs: []int{3}, Clock: testClock})
+		for gw := 1; gw <= 2; gw++ {
+			if _, err := os.Stat(matchupPath(root, gw)); !os.IsNotExist(err) {
+				t.Errorf("GW%d matchup written when only GW3 was requested (err=%v)", gw, err)
+			}
+		}
+		if _, err := os.Stat(matchupPath(root, 3)); err != nil {
+			t.Errorf("GW3 matchup not written: %v", err)
+		}
+	})
+
+	t.Run("ForceRebuildsEverything", func(t *testing.T) {
+		root := t.TempDir()
+		ld := writeIncrementalLeague(t, root)
+		build(t, root, ld, BuildOptions{Clock: testClock})
+		if err := os.WriteFile(matchupPath(root, 1), []byte(sentinel), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		build(t, root, ld, BuildOptions{Force: true, Clock: testClock})
+		if got := readFile(t, matchupPath(root, 1)); got == sentinel {
+			t.Error("GW1 matchup not rewritten with Force")
+		}
+	})
+}
+
+func TestBuildWaiverTargets_PerPosition(t *testing.T) {
+	// 60 unowned outfielders outscore the pool's only two goalkeepers; a
+	// league-wide top 50 would have had no GK in it.
+	form := PlayerFormSummary{LeagueID: 100, AsOfGW: 5, Horizon: 5}
+	for i := 0; i < 60; i++ {
+		form.Players = append(form.Players, PlayerForm{Element: 100 + i, PositionType: 2 + i%3, Minutes: 450, PointsPerGW: 5 + float64(i)/10})
+	}
+	form.Players = append(form.Players,
+		PlayerForm{Element: 1, PositionType: 1, Minutes: 450, PointsPerGW: 3},
+		PlayerForm{Element: 2, PositionType: 1, Minutes: 225, PointsPerGW: 4},
+		PlayerForm{Element: 3, PositionType: 1, Minutes: 450, PointsPerGW: 6, Ownership: 1},
+	)
+
+	s, err := buildWaiverTargets(form, "med", []int{200})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.SchemaVersion != WaiverTargetsSchemaVersion || s.ByPosition == nil {
+		t.Fatalf("summary = %+v, want the per-position layout", s)
+	}
+	// Element 2 scores 4 × 0.5 minutes share = 2, below element 1's 3.
+	if gk := s.ByPosition.GK; len(gk) != 2 || gk[0].Element != 1 || gk[1].Element != 2 {
+		t.Errorf("gk = %+v, want the two unowned keepers", gk)
+	}
+	for _, pos := range [][]WaiverTarget{s.ByPosition.DEF, s.ByPosition.MID, s.ByPosition.FWD} {
+		if len(pos) != waiverTargetsPerPosition {
+			t.Errorf("outfield list has %d targets, want %d", len(pos), waiverTargetsPerPosition)
+		}
+	}
+	// The merged list keeps every position's targets, best score first.
+	if len(s.Targets) != 47 || s.Targets[0].Element != 159 || s.Targets[46].Element != 2 {
+		t.Errorf("merged targets = %d, first %d, last %d", len(s.Targets), s.Targets[0].Element, s.Targets[len(s.Targets)-1].Element)
+	}
+	if got := s.Position(1); len(got) != 2 {
+		t.Errorf("Position(1) = %+v", got)
+	}
+
+	// A file from before by_position still answers per position from its
+	// merged list, and counts as outdated.
+	old := WaiverTargetsSummary{Targets: []WaiverTarget{{Element: 7, PositionType: 3}, {Element: 8, PositionType: 1}}}
+	if got := old.Position(1); len(got) != 1 || got[0].Element != 8 {
+		t.Errorf("old Position(1) = %+v", got)
+	}
+	raw, err := json.Marshal(old)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !WaiverTargetsOutdated(raw) || !WaiverTargetsOutdated([]byte(`{"targets":[]}`)) {
+		t.Error("old layout not reported outdated")
+	}
+	if raw, _ := json.Marshal(s); WaiverTargetsOutdated(raw) {
+		t.Error("current layout reported outdated")
+	}
+}
+
+func TestBuildPlayerFormSummary(t *testing.T) {
+	root := t.TempDir()
+	writeIncrementalLeague(t, root)
+	st := store.NewJSONStore(root)
+	if err := BuildPlayerFormSummary(st, root, 100, 3, []int{2}, testClock); err != nil {
+		t.Fatalf("BuildPlayerFormSummary: %v", err)
+	}
+	var form PlayerFormSummary
+	b, err := os.ReadFile(filepath.Join(root, "summary/player_form/100/h2.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(b, &form); err != nil {
+		t.Fatal(err)
+	}
+	if len(form.Players) != 1 {
+		t.Fatalf("players = %d, want 1", len(form.Players))
+	}
+	if _, err := os.Stat(filepath.Join(root, "summary/league/100/gw/3.json")); !os.IsNotExist(err) {
+		t.Errorf("league summary written by BuildPlayerFormSummary (err=%v)", err)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// classifyMinutesPattern / buildPlayerForm minutes series
+// ---------------------------------------------------------------------------
+
+func TestClassifyMinutesPattern(t *testing.T) {
+	cases := []struct {
+		name    string
+		minutes []int
+		want    string
+	}{
+		{"every GW", []int{90, 90, 85, 90, 62}, MinutesNailed},
+		{"back from ban", []int{90, 90, 0, 90, 90}, MinutesReturning},
+		{"back from long injury", []int{0, 0, 0, 70, 90}, MinutesReturning},
+		{"alternating starts", []int{90, 0, 90, 0, 90}, MinutesRotation},
+		{"starts and cameos", []int{90, 15, 90, 20, 75}, MinutesRotation},
+		{"late cameos", []int{20, 15, 0, 25, 10}, MinutesSub},
+		{"never plays", []int{0, 0, 0, 0, 0}, MinutesSub},
+		{"lost place", []int{90, 90, 90, 0, 0}, MinutesDropped},
+		{"single recent zero", []int{90, 90, 90, 90, 0}, MinutesRotation},
+		{"cameo comeback is not returning", []int{90, 0, 30, 90}, MinutesRotation},
+		{"empty", nil, ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := classifyMinutesPattern(tc.minutes); got != tc.want {
+				t.Errorf("classifyMinutesPattern(%v) = %q, want %q", tc.minutes, got, tc.want)
+			}
+		})
+	}
+}
+
+// TestBuildPlayerForm_MinutesSeries checks that LastMinutes covers five GWs
+// even with a shorter horizon, that blank GWs (team absent from the live
+// fixtures list) are skipped, and that the pattern uses only the horizon.
+func TestBuildPlayerForm_MinutesSeries(t *testing.T) {
+	rawRoot := t.TempDir()
+	mins := map[int]int{1: 90, 2: 90, 3: 0, 4: 0, 5: 90, 6: 90}
+	for gw := 1; gw <= 6; gw++ {
+		path := filepath.Join(rawRoot, "gw", itoa(gw), "live.json")
+		fixtures := []any{map[string]any{"team_h": 1, "team_a": 2}}
+		if gw == 4 {
+			// Team 1 blanks in GW4.
+			fixtures = []any{map[string]any{"team_h": 2, "team_a": 3}}
+		}
+		writeTestJSON(t, path, map[string]any{
+			"elements": map[string]any{"10": map[string]any{"stats": map[string]any{"minutes": mins[gw]}}},
+			"fixtures": fixtures,
+		})
+	}
+	st := store.NewJSONStore(rawRoot)
+	meta := map[int]PlayerMeta{10: {ID: 10, Name: "Saka", PositionType: 3, TeamID: 1, TeamShort: "ARS"}}
+
+	form, err := buildPlayerForm(0, meta, nil, nil, 6, 4, st)
+	if err != nil {
+		t.Fatalf("buildPlayerForm: %v", err)
+	}
+	p := form.Players[0]
+	// GWs 2–6 minus the GW4 blank.
+	want := []int{90, 0, 90, 90}
+	if len(p.LastMinutes) != len(want) {
+		t.Fatalf("LastMinutes = %v, want %v", p.LastMinutes, want)
+	}
+	for i := range want {
+		if p.LastMinutes[i] != want[i] {
+			t.Fatalf("LastMinutes = %v, want %v", p.LastMinutes, want)
+		}
+	}
+	// Horizon GWs 3–6 minus the GW4 blank: 0, 90, 90.
+	if p.MinutesPattern != MinutesReturning {
+		t.Errorf("MinutesPattern = %q, want %q", p.MinutesPattern, MinutesReturning)
+	}
+}
+
+func TestOptimalXIPoints(t *testing.T) {
+	// 2 GK, 5 DEF, 5 MID, 3 FWD; element id encodes position (1xx GK, 2xx DEF...).
+	meta := map[int]PlayerMeta{}
+	live := map[int]livestats.ElementStats{}
+	snap := &ledger.EntrySnapshot{}
+	squad := map[int][]int{1: {6, 2}, 2: {1, 1, 1, 1, 9}, 3: {8, 7, 2, 0, 0}, 4: {10, 9, 8}}
+	for pos, pts := range squad {
+		for i, p := range pts {
+			id := pos*100 + i
+			meta[id] = PlayerMeta{ID: id, PositionType: pos}
+			live[id] = livestats.ElementStats{TotalPoints: p}
+			snap.Picks = append(snap.Picks, ledger.EntryPick{Element: id, Position: len(snap.Picks) + 1})
+		}
+	}
+	// Best is 4-3-3: GK 6, DEF 9+1+1+1, MID 8+7+2, FWD 10+9+8.
+	if got := optimalXIPoints(meta, snap, live); got != 62 {
+		t.Errorf("optimalXIPoints = %d, want 62", got)
+	}
+
+	// Without a goalkeeper no formation is legal; the squad scores what it has.
+	short := &ledger.EntrySnapshot{Picks: []ledger.EntryPick{{Element: 200}, {Element: 400}}}
+	if got := optimalXIPoints(meta, short, live); got != 11 {
+		t.Errorf("optimalXIPoints(no GK) = %d, want 11", got)
+	}
+}
+
+func TestBuildOptimalStandings_FlipsAndRanks(t *testing.T) {
+	var ld LeagueDetails
+	raw := `{
+		"league_entries": [
+			{"id": 1, "entry_id": 200, "entry_name": "Alpha FC"},
+			{"id": 2, "entry_id": 201, "entry_name": "Beta FC"}
+		],
+		"matches": [
+			{"event": 1, "finished": true, "league_entry_1": 1, "league_entry_1_points": 50, "league_entry_2": 2, "league_entry_2_points": 45},
+			{"event": 2, "finished": true, "league_entry_1": 1, "league_entry_1_points": 40, "league_entry_2": 2, "league_entry_2_points": 30},
+			{"event": 3, "finished": true, "league_entry_1": 1, "league_entry_1_points": 10, "league_entry_2": 2, "league_entry_2_points": 90}
+		]
+	}`
+	if err := json.Unmarshal([]byte(raw), &ld); err != nil {
+		t.Fatal(err)
+	}
+	optimal := map[[2]int]int{
+		{200, 1}: 52, {201, 1}: 70,
+		{200, 2}: 41, // Beta has no GW2 snapshot
+	}
+	out, err := buildOptimalStandings(ld, 100, 2, func(entryID, gw int) (int, bool, error) {
+		pts, ok := optimal[[2]int{entryID, gw}]
+		return pts, ok, nil
+	})
+	if err != nil {
+		t.Fatalf("buildOptimalStandings: %v", err)
+	}
+	if out.MissingSnapshots != 1 {
+		t.Errorf("MissingSnapshots = %d, want 1", out.MissingSnapshots)
+	}
+	if len(out.Flipped) != 1 {
+		t.Fatalf("Flipped = %+v, want one GW1 flip", out.Flipped)
+	}
+	f := out.Flipped[0]
+	if f.Gameweek != 1 || f.EntryID != 201 || f.ActualResult != "L" || f.OptimalResult != "W" || f.OptimalPoints != 70 {
+		t.Errorf("flip = %+v, want Beta's GW1 loss turned into a 70-52 win", f)
+	}
+
+	// GW3 is past through_gw. Actual: Alpha 2 wins. Optimal: 1-1, Beta ahead on
+	// points difference (100-93).
+	byEntry := map[int]OptimalStandingsRow{}
+	for _, c := range out.Comparison {
+		byEntry[c.EntryID] = c
+	}
+	beta := byEntry[201]
+	if beta.ActualRank != 2 || beta.OptimalRank != 1 || beta.RankDelta != 1 {
+		t.Errorf("Beta ranks = %+v, want 2 -> 1", beta)
+	}
+	if beta.BenchPointsLost != 25 || byEntry[200].BenchPointsLost != 3 {
+		t.Errorf("bench points lost = %d/%d, want 25/3", beta.BenchPointsLost, byEntry[200].BenchPointsLost)
+	}
+	if len(out.Commentary) != 3 {
+		t.Fatalf("Commentary = %q, want a flip line and two rank lines", out.Commentary)
+	}
+	if want := "GW1: Beta FC lost to Alpha FC 45-50, but would have won 70-52 with their best XI."; out.Commentary[0] != want {
+		t.Errorf("Commentary[0] = %q, want %q", out.Commentary[0], want)
+	}
+	if want := "Beta FC would be 1st instead of 2nd, with 25 points left on the bench."; out.Commentary[2] != want {
+		t.Errorf("Commentary[2] = %q, want %q", out.Commentary[2], want)
+	}
+}
+
+func TestComputeStandings_Trajectory(t *testing.T) {
+	entryOf := map[int]int{1: 200, 2: 201}
+	names := map[int]string{200: "Alpha FC", 201: "Beta FC"}
+	// Alpha wins GW1-2, then Beta wins GW3-4 by more and takes first place
+	// on points difference.
+	var ld LeagueDetails
+	raw := `{"matches": [
+		{"event": 1, "finished": true, "league_entry_1": 1, "league_entry_1_points": 50, "league_entry_2": 2, "league_entry_2_points": 40},
+		{"event": 2, "finished": true, "league_entry_1": 1, "league_entry_1_points": 45, "league_entry_2": 2, "league_entry_2_points": 44},
+		{"event": 3, "finished": true, "league_entry_1": 1, "league_entry_1_points": 30, "league_entry_2": 2, "league_entry_2_points": 60},
+		{"event": 4, "finished": true, "league_entry_1": 1, "league_entry_1_points": 20, "league_entry_2": 2, "league_entry_2_points": 50}
+	]}`
+	if err := json.Unmarshal([]byte(raw), &ld); err != nil {
+		t.Fatal(err)
+	}
+	matches := ld.Matches
+
+	rows, _ := computeStandings(matches, entryOf, names, []int{200, 201}, 1, nil)
+	applyRankChange(rows, nil)
+	for _, r := range rows {
+		if r.PrevRank != 0 || r.RankChange != 0 {
+			t.Errorf("GW1 %s prev_rank=%d rank_change=%d, want 0/0", r.EntryName, r.PrevRank, r.RankChange)
+		}
+	}
+	if rows[0].EntryID != 200 || rows[0].Streak != "W1" || rows[0].Form != "W" {
+		t.Errorf("GW1 leader = %+v, want Alpha on W1", rows[0])
+	}
+
+	_, prevRank := computeStandings(matches, entryOf, names, []int{200, 201}, 3, nil)
+	rows, _ = computeStandings(matches, entryOf, names, []int{200, 201}, 4, nil)
+	applyRankChange(rows, prevRank)
+	byEntry := map[int]StandingsRow{}
+	for _, r := range rows {
+		byEntry[r.EntryID] = r
+	}
+	alpha, beta := byEntry[200], byEntry[201]
+	if beta.Rank != 1 || beta.PrevRank != 2 || beta.RankChange != 1 {
+		t.Errorf("Beta rank %d prev %d change %d, want 1/2/+1", beta.Rank, beta.PrevRank, beta.RankChange)
+	}
+	if alpha.RankChange != -1 {
+		t.Errorf("Alpha rank_change = %d, want -1", alpha.RankChange)
+	}
+	if alpha.Streak != "L2" || alpha.Form != "WWLL" || beta.Streak != "W2" || beta.Form != "LLWW" {
+		t.Errorf("streak/form = %s %s / %s %s, want L2 WWLL / W2 LLWW", alpha.Streak, alpha.Form, beta.Streak, beta.Form)
+	}
+	// Beta: last three (44+60+50)/3 vs season 194/4.
+	if math.Abs(beta.PointsForLast3-154.0/3) > 1e-9 || math.Abs(beta.PointsForAvg-48.5) > 1e-9 || math.Abs(beta.Momentum-(154.0/3-48.5)) > 1e-9 {
+		t.Errorf("Beta momentum = %v/%v/%v", beta.PointsForLast3, beta.PointsForAvg, beta.Momentum)
+	}
+}
+
+func TestBuildLeagueSummaries_StandingsRankChange(t *testing.T) {
+	read := func(t *testing.T, root string, gw int) map[int]StandingsRow {
+		t.Helper()
+		b, err := os.ReadFile(filepath.Join(root, "summary/standings/100/gw", itoa(gw)+".json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out StandingsSummary
+		if err := json.Unmarshal(b, &out); err != nil {
+			t.Fatal(err)
+		}
+		rows := map[int]StandingsRow{}
+		for _, r := range out.Rows {
+			rows[r.EntryID] = r
+		}
+		return rows
+	}
+
+	root := t.TempDir()
+	ld := writeIncrementalLeague(t, root)
+	st := store.NewJSONStore(root)
+	if err := BuildLeagueSummaries(st, root, 100, ld, []int{200, 201}, 1, 3, []int{5}, []string{"med"}, BuildOptions{Clock: testClock}); err != nil {
+		t.Fatalf("BuildLeagueSummaries: %v", err)
+	}
+	if r := read(t, root, 1)[201]; r.PrevRank != 0 || r.RankChange != 0 {
+		t.Errorf("GW1 Beta prev_rank=%d rank_change=%d, want 0/0", r.PrevRank, r.RankChange)
+	}
+	if r := read(t, root, 2)[201]; r.PrevRank != 2 || r.RankChange != 0 || r.Streak != "L2" {
+		t.Errorf("GW2 Beta = %+v, want prev_rank 2, no change, L2", r)
+	}
+
+	// Rebuilding GW2 alone has no carried ranks and recomputes GW1's.
+	if err := BuildLeagueSummaries(st, root, 100, ld, []int{200, 201}, 1, 3, []int{5}, []string{"med"}, BuildOptions{OnlyGWs: []int{2}, Force: true, Clock: testClock}); err != nil {
+		t.Fatalf("BuildLeagueSummaries: %v", err)
+	}
+	if r := read(t, root, 2)[200]; r.PrevRank != 1 {
+		t.Errorf("GW2 Alpha prev_rank = %d after a lone rebuild, want 1", r.PrevRank)
+	}
+}
+
+func TestBuildLeagueSummaries_HonorsStartEvent(t *testing.T) {
+	root := t.TempDir()
+	ld := writeIncrementalLeague(t, root)
+	// The league began in GW2, so there are no GW1 snapshots to read.
+	ld.League.StartEvent = 2
+	if err := os.RemoveAll(filepath.Join(root, "snapshots/100/entry/200/gw/1.json")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.RemoveAll(filepath.Join(root, "snapshots/100/entry/201/gw/1.json")); err != nil {
+		t.Fatal(err)
+	}
+	st := store.NewJSONStore(root)
+	if err := BuildLeagueSummaries(st, root, 100, ld, []int{200, 201}, 1, 3, []int{5}, []string{"med"}, BuildOptions{Clock: testClock}); err != nil {
+		t.Fatalf("BuildLeagueSummaries: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "summary/standings/100/gw/1.json")); !os.IsNotExist(err) {
+		t.Errorf("GW1 standings written before the league started (err = %v)", err)
+	}
+	b, err := os.ReadFile(filepath.Join(root, "summary/standings/100/gw/2.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out StandingsSummary
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range out.Rows {
+		if r.PrevRank != 0 || r.RankChange != 0 {
+			t.Errorf("GW2 %s prev_rank=%d rank_change=%d, want none in the league's first GW", r.EntryName, r.PrevRank, r.RankChange)
+		}
+	}
+	if got := (LeagueDetails{}).StartGW(); got != 1 {
+		t.Errorf("StartGW without start_event = %d, want 1", got)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Roster points and deductions
+// ---------------------------------------------------------------------------
+
+func TestBuildDeductions(t *testing.T) {
+	meta := map[int]PlayerMeta{
+		1: {ID: 1, Name: "Red Card Starter", PositionType: 3},
+		2: {ID: 2, Name: "Leaky Defender", PositionType: 2},
+		3: {ID: 3, Name: "Scorer", PositionType: 4},
+	}
+	live := map[int]livestats.ElementStats{
+		// Explain data: appearance +2, own goal -2, red card -3.
+		1: {Minutes: 70, TotalPoints: -3, OwnGoals: 1, RedCards: 1, Explain: map[string]int{"minutes": 2, "own_goals": -2, "red_cards": -3}},
+		// No explain data: rebuilt from counts.
+		2: {Minutes: 90, TotalPoints: -2, GoalsConceded: 4, YellowCards: 1, PenaltiesMissed: 0},
+		3: {Minutes: 90, TotalPoints: 8, RedCards: 1},
+	}
+	snap := &ledger.EntrySnapshot{Picks: []ledger.EntryPick{{Element: 1, Position: 1}, {Element: 2, Position: 2}, {Element: 3, Position: 12}}}
+
+	roster := buildRoster(meta, snap, live)
+	if roster[0].Points != -3 || roster[0].Minutes != 70 || roster[2].Points != 8 || roster[2].Role != "bench" {
+		t.Errorf("roster = %+v, want GW points and minutes carried", roster)
+	}
+
+	got := buildDeductions(meta, roster, live)
+	if len(got) != 2 {
+		t.Fatalf("deductions = %+v, want the two negative players only", got)
+	}
+	red := got[0]
+	if red.Element != 1 || red.Role != "starter" || red.Points != -3 || red.Deducted != -5 {
+		t.Errorf("red card starter = %+v, want points -3, deducted -5", red)
+	}
+	if len(red.Reasons) != 2 || red.Reasons[0] != (DeductionReason{Reason: "red card", Points: -3}) || red.Reasons[1] != (DeductionReason{Reason: "own goal", Points: -2}) {
+		t.Errorf("reasons = %+v, want red card -3 then own goal -2", red.Reasons)
+	}
+	def := got[1]
+	if def.Deducted != -3 || len(def.Reasons) != 2 || def.Reasons[0] != (DeductionReason{Reason: "goals conceded", Points: -2}) {
+		t.Errorf("defender = %+v, want goals conceded -2 and yellow card -1 rebuilt from counts", def)
+	}
+}
+
+func TestBuildLeagueSummaries_RosterPoints(t *testing.T) {
+	root := t.TempDir()
+	ld := writeIncrementalLeague(t, root)
+	st := store.NewJSONStore(root)
+	if err := BuildLeagueSummaries(st, root, 100, ld, []int{200, 201}, 1, 1, []int{5}, []string{"med"}, BuildOptions{Clock: testClock}); err != nil {
+		t.Fatalf("BuildLeagueSummaries: %v", err)
+	}
+	b, err := os.ReadFile(filepath.Join(root, "summary/matchup/100/gw/1.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var matchups MatchupSummary
+	if err := json.Unmarshal(b, &matchups); err != nil {
+		t.Fatal(err)
+	}
+	m := matchups.Matchups[0]
+	if len(m.Players) != 1 || m.Players[0].Points != 5 || m.Players[0].Minutes != 90 || len(m.OpponentPlayers) != 1 {
+		t.Errorf("matchup players = %+v / %+v, want Salah on 5 points in 90 minutes", m.Players, m.OpponentPlayers)
+	}
+	if m.Deductions != nil {
+		t.Errorf("deductions = %+v, want none", m.Deductions)
+	}
+	if d := m.PlayerDetail; d == nil || len(d.Starters) != 1 || d.TopScorer == nil || d.TopScorer.Points != 5 || d.TopScorer.Minutes != 90 {
+		t.Errorf("player detail = %+v, want Salah's 5 as the top score", d)
+	}
+}
+
+func TestParseAPITime(t *testing.T) {
+	want := "2025-03-08T15:00:00Z"
+	for _, s := range []string{
+		"2025-03-08T15:00:00Z",
+		"2025-03-08T15:00:00.000Z",
+		"2025-03-08T16:00:00+01:00",
+		"2025-03-08T15:00Z",
+		"2025-03-08T15:00:00",
+		"2025-03-08 15:00:00",
+		" 2025-03-08 15:00 ",
+	} {
+		if got := normalizeKickoff(s); got != want {
+			t.Errorf("normalizeKickoff(%q) = %q, want %q", s, got, want)
+		}
+	}
+	if _, ok := ParseAPITime("TBC"); ok {
+		t.Error("ParseAPITime(TBC) succeeded")
+	}
+	if got := normalizeKickoff(""); got != "" {
+		t.Errorf("normalizeKickoff(\"\") = %q", got)
+	}
+}
+
+// TestBuildLeagueSummaries_Reclassification moves Salah from MID to FWD
+// between GW10 and GW20. A later full rebuild of GW10 must keep his GW10
+// points under MID, as they were scored, while GW20 groups them under FWD.
+func TestBuildLeagueSummaries_Reclassification(t *testing.T) {
+	root := t.TempDir()
+	writeBootstrap := func(elementType int) {
+		writeTestJSON(t, filepath.Join(root, "bootstrap/bootstrap-static.json"), map[string]any{
+			"elements": []any{map[string]any{"id": 1, "web_name": "Salah", "team": 10, "element_type": elementType}},
+			"teams":    []any{map[string]any{"id": 10, "short_name": "LIV"}},
+			"fixtures": map[string]any{},
+		})
+	}
+	matches := []any{}
+	for _, gw := range []int{10, 20} {
+		matches = append(matches, map[string]any{
+			"event": gw, "started": true, "finished": true,
+			"league_entry_1": 1, "league_entry_1_points": 5,
+			"league_entry_2": 2, "league_entry_2_points": 0,
+		})
+		writeLiveJSON(t, root, gw, map[string]any{
+			"1": map[string]any{"stats": map[string]any{"minutes": 90, "total_points": 5}},
+		})
+		for _, entryID := range []int{200, 201} {
+			picks := []any{}
+			if entryID == 200 {
+				picks = append(picks, map[string]any{"element": 1, "position": 1})
+			}
+			writeTestJSON(t, filepath.Join(root, "snapshots/100/entry", itoa(entryID), "gw", itoa(gw)+".json"), map[string]any{
+				"entry_id": entryID, "gameweek": gw, "picks": picks,
+			})
+		}
+	}
+	details := map[string]any{
+		"league_entries": []any{
+			map[string]any{"id": 1, "entry_id": 200, "entry_name": "Alpha FC"},
+			map[string]any{"id": 2, "entry_id": 201, "entry_name": "Beta FC"},
+		},
+		"matches": matches,
+	}
+	writeTestJSON(t, filepath.Join(root, "league/100/details.json"), details)
+	writeTestJSON(t, filepath.Join(root, "league/100/transactions.json"), map[string]any{"transactions": []any{}})
+	writeTestJSON(t, filepath.Join(root, "league/100/trades.json"), map[string]any{"trades": []any{}})
+	writeTestJSON(t, filepath.Join(root, "ledger/100/event_0.json"), map[string]any{"league_id": 100})
+	raw, _ := json.Marshal(details)
+	var ld LeagueDetails
+	if err := json.Unmarshal(raw, &ld); err != nil {
+		t.Fatal(err)
+	}
+	st := store.NewJSONStore(root)
+	build := func(gw int) {
+		t.Helper()
+		if err := BuildLeagueSummaries(st, root, 100, ld, []int{200, 201}, gw, gw, nil, nil, BuildOptions{Force: true, Clock: testClock}); err != nil {
+			t.Fatalf("build GW%d: %v", gw, err)
+		}
+	}
+	alphaPoints := func(gw int) PositionPoints {
+		t.Helper()
+		b, err := os.ReadFile(filepath.Join(root, "summary/matchup/100/gw", itoa(gw)+".json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var m MatchupSummary
+		if err := json.Unmarshal(b, &m); err != nil {
+			t.Fatal(err)
+		}
+		return m.Matchups[0].Points
+	}
+
+	writeBootstrap(3)
+	build(10)
+	writeBootstrap(4)
+	build(20)
+	build(10)
+
+	if p := alphaPoints(10); p.MID != 5 || p.FWD != 0 {
+		t.Errorf("GW10 points = %+v, want Salah's 5 kept under MID", p)
+	}
+	if p := alphaPoints(20); p.FWD != 5 || p.MID != 0 {
+		t.Errorf("GW20 points = %+v, want Salah's 5 under FWD", p)
+	}
+}
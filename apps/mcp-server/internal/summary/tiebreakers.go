@@ -0,0 +1,199 @@
+package summary
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Standings tiebreakers, applied in order to teams level on match points.
+const (
+	TiebreakH2H        = "h2h"
+	TiebreakPointsFor  = "points_for"
+	TiebreakPointsDiff = "points_diff"
+)
+
+// DefaultTiebreakers is the chain derived standings are built with unless
+// the league configures its own: points difference, then points for.
+var DefaultTiebreakers = []string{TiebreakPointsDiff, TiebreakPointsFor}
+
+// ParseTiebreakers normalises and validates a tiebreaker chain. An empty
+// chain is DefaultTiebreakers.
+func ParseTiebreakers(chain []string) ([]string, error) {
+	if len(chain) == 0 {
+		return DefaultTiebreakers, nil
+	}
+	out := make([]string, 0, len(chain))
+	seen := make(map[string]bool, len(chain))
+	for _, c := range chain {
+		c = strings.ToLower(strings.TrimSpace(c))
+		switch c {
+		case TiebreakH2H, TiebreakPointsFor, TiebreakPointsDiff:
+		default:
+			return nil, fmt.Errorf("unknown tiebreaker %q (want h2h, points_for or points_diff)", c)
+		}
+		if seen[c] {
+			return nil, fmt.Errorf("tiebreaker %q listed twice", c)
+		}
+		seen[c] = true
+		out = append(out, c)
+	}
+	return out, nil
+}
+
+// IsDefaultTiebreakers reports whether chain orders standings the same way
+// as DefaultTiebreakers.
+func IsDefaultTiebreakers(chain []string) bool {
+	if len(chain) == 0 {
+		return true
+	}
+	if len(chain) != len(DefaultTiebreakers) {
+		return false
+	}
+	for i := range chain {
+		if chain[i] != DefaultTiebreakers[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// ComputeStandings builds the standings through gw straight from league
+// details, breaking ties with tiebreakers. Rank movement is measured against
+// gw-1 under the same chain, from the league's second GW on.
+func ComputeStandings(leagueID int, ld LeagueDetails, entryIDs []int, gw int, tiebreakers []string) StandingsSummary {
+	entryNameByID := make(map[int]string, len(ld.LeagueEntries))
+	leagueEntryToEntry := make(map[int]int, len(ld.LeagueEntries))
+	for _, e := range ld.LeagueEntries {
+		entryNameByID[e.EntryID] = e.EntryName
+		leagueEntryToEntry[e.ID] = e.EntryID
+	}
+	rows, _ := computeStandings(ld.Matches, leagueEntryToEntry, entryNameByID, entryIDs, gw, tiebreakers)
+	if gw > ld.StartGW() {
+		_, prevRank := computeStandings(ld.Matches, leagueEntryToEntry, entryNameByID, entryIDs, gw-1, tiebreakers)
+		applyRankChange(rows, prevRank)
+	}
+	return StandingsSummary{
+		LeagueID: leagueID,
+		Gameweek: gw,
+		Rows:     rows,
+	}
+}
+
+// h2hTable holds the match points each entry has taken off each opponent.
+type h2hTable map[int]map[int]int
+
+func (h h2hTable) add(entryID int, opponentID int, points int) {
+	if h[entryID] == nil {
+		h[entryID] = make(map[int]int)
+	}
+	h[entryID][opponentID] += points
+}
+
+// seeding collects, for each entry, why it sits above the next row and
+// below the previous one; a row shows the first when it has one.
+type seeding struct {
+	ahead  map[int]string
+	behind map[int]string
+}
+
+// orderStandings sorts rows by match points and breaks ties with chain,
+// setting SeedingExplanation on every row level on match points with a
+// neighbour.
+func orderStandings(rows []StandingsRow, chain []string, h2h h2hTable) {
+	sort.SliceStable(rows, func(i, j int) bool {
+		if rows[i].MatchPoints != rows[j].MatchPoints {
+			return rows[i].MatchPoints > rows[j].MatchPoints
+		}
+		return rows[i].EntryName < rows[j].EntryName
+	})
+	s := seeding{ahead: make(map[int]string), behind: make(map[int]string)}
+	for i := 0; i < len(rows); {
+		j := i + 1
+		for j < len(rows) && rows[j].MatchPoints == rows[i].MatchPoints {
+			j++
+		}
+		s.breakTie(rows[i:j], chain, 0, h2h)
+		i = j
+	}
+	for i := range rows {
+		id := rows[i].EntryID
+		if note, ok := s.ahead[id]; ok {
+			rows[i].SeedingExplanation = note
+		} else {
+			rows[i].SeedingExplanation = s.behind[id]
+		}
+	}
+}
+
+// breakTie orders group, whose teams are level on match points and on
+// chain[:k], by chain[k]. Any smaller tied subset it leaves goes back through
+// the whole chain so head-to-head is recomputed among just those teams; a
+// criterion that separates nobody falls through to the next. Teams level on
+// every criterion keep entry-name order.
+func (s seeding) breakTie(group []StandingsRow, chain []string, k int, h2h h2hTable) {
+	if len(group) < 2 {
+		return
+	}
+	if k == len(chain) {
+		for i := 1; i < len(group); i++ {
+			a, b := group[i-1], group[i]
+			s.ahead[a.EntryID] = fmt.Sprintf("Level with %s on %d match points and every tiebreaker; listed by entry name", b.EntryName, a.MatchPoints)
+			s.behind[b.EntryID] = fmt.Sprintf("Level with %s on %d match points and every tiebreaker; listed by entry name", a.EntryName, b.MatchPoints)
+		}
+		return
+	}
+
+	criterion := chain[k]
+	value := make(map[int]int, len(group))
+	for _, r := range group {
+		switch criterion {
+		case TiebreakH2H:
+			for _, o := range group {
+				value[r.EntryID] += h2h[r.EntryID][o.EntryID]
+			}
+		case TiebreakPointsFor:
+			value[r.EntryID] = r.PointsFor
+		case TiebreakPointsDiff:
+			value[r.EntryID] = r.PointsFor - r.PointsAgainst
+		}
+	}
+	sort.SliceStable(group, func(i, j int) bool { return value[group[i].EntryID] > value[group[j].EntryID] })
+	if value[group[0].EntryID] == value[group[len(group)-1].EntryID] {
+		s.breakTie(group, chain, k+1, h2h)
+		return
+	}
+
+	// Split into runs of equal value and order each run before labelling the
+	// boundaries, since ordering a run moves its first and last rows.
+	bounds := []int{0}
+	for i := 1; i < len(group); i++ {
+		if value[group[i].EntryID] != value[group[i-1].EntryID] {
+			bounds = append(bounds, i)
+		}
+	}
+	bounds = append(bounds, len(group))
+	for i := 1; i < len(bounds); i++ {
+		s.breakTie(group[bounds[i-1]:bounds[i]], chain, 0, h2h)
+	}
+	for _, i := range bounds[1 : len(bounds)-1] {
+		a, b := group[i-1], group[i]
+		va, vb := value[a.EntryID], value[b.EntryID]
+		s.ahead[a.EntryID] = fmt.Sprintf("Level with %s on %d match points; ahead on %s", b.EntryName, a.MatchPoints, describeTiebreak(criterion, va, vb, len(group)))
+		s.behind[b.EntryID] = fmt.Sprintf("Level with %s on %d match points; behind on %s", a.EntryName, b.MatchPoints, describeTiebreak(criterion, vb, va, len(group)))
+	}
+}
+
+func describeTiebreak(criterion string, mine int, theirs int, tied int) string {
+	switch criterion {
+	case TiebreakH2H:
+		if tied > 2 {
+			return fmt.Sprintf("head-to-head (%d vs %d match points among the %d tied teams)", mine, theirs, tied)
+		}
+		return fmt.Sprintf("head-to-head (%d vs %d match points)", mine, theirs)
+	case TiebreakPointsFor:
+		return fmt.Sprintf("points for (%d vs %d)", mine, theirs)
+	default:
+		return fmt.Sprintf("points difference (%+d vs %+d)", mine, theirs)
+	}
+}
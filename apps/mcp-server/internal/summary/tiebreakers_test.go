@@ -0,0 +1,204 @@
+package summary
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+)
+
+// tiebreakLeague builds league details for entries 101-104 (A-D) with one
+// finished GW1 match per line "a-b scoreA scoreB".
+func tiebreakLeague(t *testing.T, results ...string) LeagueDetails {
+	t.Helper()
+	ids := map[string]int{"A": 1, "B": 2, "C": 3, "D": 4}
+	matches := make([]map[string]any, 0, len(results))
+	for _, r := range results {
+		var a, b string
+		var pa, pb int
+		if _, err := fmt.Sscanf(strings.Replace(r, "-", " ", 1), "%s %s %d %d", &a, &b, &pa, &pb); err != nil {
+			t.Fatalf("bad result %q: %v", r, err)
+		}
+		matches = append(matches, map[string]any{"event": 1, "finished": true, "league_entry_1": ids[a], "league_entry_1_points": pa, "league_entry_2": ids[b], "league_entry_2_points": pb})
+	}
+	raw, _ := json.Marshal(map[string]any{
+		"league_entries": []map[string]any{
+			{"id": 1, "entry_id": 101, "entry_name": "A"},
+			{"id": 2, "entry_id": 102, "entry_name": "B"},
+			{"id": 3, "entry_id": 103, "entry_name": "C"},
+			{"id": 4, "entry_id": 104, "entry_name": "D"},
+		},
+		"matches": matches,
+	})
+	var ld LeagueDetails
+	if err := json.Unmarshal(raw, &ld); err != nil {
+		t.Fatal(err)
+	}
+	return ld
+}
+
+func order(rows []StandingsRow) string {
+	names := make([]string, 0, len(rows))
+	for _, r := range rows {
+		names = append(names, r.EntryName)
+	}
+	return strings.Join(names, "")
+}
+
+func TestComputeStandings_H2HBeforePointsDiff(t *testing.T) {
+	// A and B both finish on 3 match points; B has the better points
+	// difference but A won the meeting.
+	ld := tiebreakLeague(t, "A-B 50 48", "C-A 70 40", "B-D 80 30", "C-D 40 40")
+	entryIDs := []int{101, 102, 103, 104}
+
+	if got := order(ComputeStandings(1, ld, entryIDs, 1, nil).Rows); got != "CBAD" {
+		t.Errorf("default chain order = %s, want CBAD", got)
+	}
+	rows := ComputeStandings(1, ld, entryIDs, 1, []string{TiebreakH2H, TiebreakPointsDiff}).Rows
+	if got := order(rows); got != "CABD" {
+		t.Fatalf("h2h chain order = %s, want CABD", got)
+	}
+	if rows[1].SeedingExplanation != "Level with B on 3 match points; ahead on head-to-head (3 vs 0 match points)" {
+		t.Errorf("A explanation = %q", rows[1].SeedingExplanation)
+	}
+	if rows[2].SeedingExplanation != "Level with A on 3 match points; behind on head-to-head (0 vs 3 match points)" {
+		t.Errorf("B explanation = %q", rows[2].SeedingExplanation)
+	}
+	if rows[0].SeedingExplanation != "" || rows[3].SeedingExplanation != "" {
+		t.Errorf("untied rows explained: %q / %q", rows[0].SeedingExplanation, rows[3].SeedingExplanation)
+	}
+}
+
+func TestComputeStandings_CircularH2HFallsThrough(t *testing.T) {
+	// A beat B, B beat C, C beat A, and all three beat D: level on 6 match
+	// points with 3 each in the mini-table. Points for then splits C off,
+	// leaving A and B level on 150, and head-to-head between just those two
+	// puts A first.
+	ld := tiebreakLeague(t,
+		"A-B 60 50", "B-C 55 45", "C-A 50 40",
+		"A-D 50 30", "B-D 45 30", "C-D 50 30")
+	rows := ComputeStandings(1, ld, []int{101, 102, 103, 104}, 1, []string{TiebreakH2H, TiebreakPointsFor, TiebreakPointsDiff}).Rows
+
+	if got := order(rows); got != "ABCD" {
+		t.Fatalf("order = %s, want ABCD", got)
+	}
+	want := []string{
+		"Level with B on 6 match points; ahead on head-to-head (3 vs 0 match points)",
+		"Level with C on 6 match points; ahead on points for (150 vs 145)",
+		"Level with B on 6 match points; behind on points for (145 vs 150)",
+		"",
+	}
+	for i, w := range want {
+		if rows[i].SeedingExplanation != w {
+			t.Errorf("row %d (%s) explanation = %q, want %q", i, rows[i].EntryName, rows[i].SeedingExplanation, w)
+		}
+		if rows[i].Rank != i+1 {
+			t.Errorf("row %d rank = %d", i, rows[i].Rank)
+		}
+	}
+}
+
+func TestComputeStandings_CircularH2HToPointsDiff(t *testing.T) {
+	// The same circle with every team on 150 points for, so the chain
+	// runs through to points difference.
+	ld := tiebreakLeague(t,
+		"A-B 60 50", "B-C 60 50", "C-A 60 50",
+		"A-D 40 30", "B-D 40 20", "C-D 40 10")
+	rows := ComputeStandings(1, ld, []int{101, 102, 103, 104}, 1, []string{TiebreakH2H, TiebreakPointsFor, TiebreakPointsDiff}).Rows
+
+	if got := order(rows); got != "CBAD" {
+		t.Fatalf("order = %s, want CBAD by points difference", got)
+	}
+	if !strings.Contains(rows[0].SeedingExplanation, "points difference (+30 vs +20)") {
+		t.Errorf("C explanation = %q", rows[0].SeedingExplanation)
+	}
+}
+
+func TestComputeStandings_LevelOnEveryTiebreaker(t *testing.T) {
+	ld := tiebreakLeague(t, "A-B 50 50", "C-D 40 40")
+	rows := ComputeStandings(1, ld, []int{101, 102, 103, 104}, 1, []string{TiebreakH2H}).Rows
+	if got := order(rows); got != "ABCD" {
+		t.Fatalf("order = %s, want ABCD", got)
+	}
+	if !strings.HasSuffix(rows[0].SeedingExplanation, "every tiebreaker; listed by entry name") {
+		t.Errorf("A explanation = %q", rows[0].SeedingExplanation)
+	}
+}
+
+func TestParseTiebreakers(t *testing.T) {
+	got, err := ParseTiebreakers([]string{" H2H", "points_for"})
+	if err != nil || strings.Join(got, ",") != "h2h,points_for" {
+		t.Errorf("ParseTiebreakers = %v, %v", got, err)
+	}
+	if got, _ := ParseTiebreakers(nil); !IsDefaultTiebreakers(got) {
+		t.Errorf("empty chain = %v, want default", got)
+	}
+	for _, bad := range [][]string{{"goals"}, {"h2h", "h2h"}} {
+		if _, err := ParseTiebreakers(bad); err == nil {
+			t.Errorf("ParseTiebreakers(%v) succeeded", bad)
+		}
+	}
+}
+
+func TestBuildLeagueSummaries_ConfiguredTiebreakers(t *testing.T) {
+	// The meeting A won puts A above B under h2h; the default chain has B
+	// ahead on points difference.
+	ld := tiebreakLeague(t, "A-B 50 48", "C-A 70 40", "B-D 80 30", "C-D 40 40")
+	entryIDs := []int{101, 102, 103, 104}
+	root := t.TempDir()
+	writeTestJSON(t, filepath.Join(root, "bootstrap/bootstrap-static.json"), map[string]any{
+		"elements": []any{map[string]any{"id": 1, "web_name": "Salah", "team": 10, "element_type": 3}},
+		"teams":    []any{map[string]any{"id": 10, "short_name": "LIV"}},
+		"fixtures": map[string]any{},
+	})
+	writeLiveJSON(t, root, 1, map[string]any{"1": map[string]any{"stats": map[string]any{"minutes": 90, "total_points": 5}}})
+	for _, entryID := range entryIDs {
+		writeTestJSON(t, filepath.Join(root, "snapshots/100/entry", itoa(entryID), "gw/1.json"), map[string]any{
+			"entry_id": entryID, "gameweek": 1,
+			"picks": []any{map[string]any{"element": 1, "position": 1}},
+		})
+	}
+	writeTestJSON(t, filepath.Join(root, "league/100/details.json"), ld)
+	writeTestJSON(t, filepath.Join(root, "league/100/transactions.json"), map[string]any{"transactions": []any{}})
+	writeTestJSON(t, filepath.Join(root, "league/100/trades.json"), map[string]any{"trades": []any{}})
+	writeTestJSON(t, filepath.Join(root, "ledger/100/event_0.json"), map[string]any{"league_id": 100})
+	st := store.NewJSONStore(root)
+
+	readOrder := func(t *testing.T) string {
+		t.Helper()
+		b, err := os.ReadFile(filepath.Join(root, "summary/standings/100/gw/1.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var s StandingsSummary
+		if err := json.Unmarshal(b, &s); err != nil {
+			t.Fatal(err)
+		}
+		return order(s.Rows)
+	}
+
+	chain := []string{TiebreakH2H, TiebreakPointsDiff}
+	if err := BuildLeagueSummaries(st, root, 100, ld, entryIDs, 1, 1, []int{5}, []string{"med"}, BuildOptions{Clock: testClock, Tiebreakers: chain}); err != nil {
+		t.Fatalf("BuildLeagueSummaries: %v", err)
+	}
+	if got := readOrder(t); got != "CABD" {
+		t.Errorf("league build order = %s, want CABD under h2h", got)
+	}
+
+	if err := BuildStandingsSummary(st, root, 100, 1, nil, testClock); err != nil {
+		t.Fatal(err)
+	}
+	if got := readOrder(t); got != "CBAD" {
+		t.Errorf("default standings build order = %s, want CBAD", got)
+	}
+	if err := BuildStandingsSummary(st, root, 100, 1, chain, testClock); err != nil {
+		t.Fatal(err)
+	}
+	if got := readOrder(t); got != "CABD" {
+		t.Errorf("h2h standings build order = %s, want CABD", got)
+	}
+}
@@ -0,0 +1,83 @@
+package summary
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/leagueconfig"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/reconcile"
+)
+
+// TransactionBudget is an entry's use of the league's cap on moves through a
+// GW. A move is an approved waiver claim or free-agent swap; trades don't
+// count. The season fields are set when the league caps the season, the GW
+// fields when it caps each GW.
+//
+// Pace is the moves a GW so far (GWsElapsed counts from the league's first
+// GW through this one) and SustainablePace the moves a GW that would spend
+// exactly what remains over the GWsLeft after this one.
+type TransactionBudget struct {
+	Used            int      `json:"used"`
+	SeasonLimit     int      `json:"season_limit,omitempty"`
+	Remaining       *int     `json:"remaining,omitempty"`
+	UsedThisGW      int      `json:"used_this_gw"`
+	GWLimit         int      `json:"gw_limit,omitempty"`
+	RemainingThisGW *int     `json:"remaining_this_gw,omitempty"`
+	GWsElapsed      int      `json:"gws_elapsed"`
+	GWsLeft         int      `json:"gws_left"`
+	Pace            float64  `json:"pace"`
+	SustainablePace *float64 `json:"sustainable_pace,omitempty"`
+	Warning         string   `json:"warning,omitempty"`
+}
+
+// Exhausted reports whether another move in the budget's GW would go over
+// either cap.
+func (b *TransactionBudget) Exhausted() bool {
+	return b != nil && ((b.Remaining != nil && *b.Remaining == 0) || (b.RemainingThisGW != nil && *b.RemainingThisGW == 0))
+}
+
+// BuildTransactionBudget counts entryID's moves through gw against the caps
+// in w. It returns nil for an uncapped league.
+func BuildTransactionBudget(w leagueconfig.Waivers, transactions []reconcile.Transaction, entryID int, gw int, startGW int, lastGW int) *TransactionBudget {
+	if !w.Capped() {
+		return nil
+	}
+	b := &TransactionBudget{SeasonLimit: w.SeasonLimit, GWLimit: w.GWLimit}
+	for _, tx := range transactions {
+		if tx.Entry != entryID || tx.Result != "a" || (tx.Kind != "w" && tx.Kind != "f") || tx.Event > gw {
+			continue
+		}
+		b.Used++
+		if tx.Event == gw {
+			b.UsedThisGW++
+		}
+	}
+	b.GWsElapsed = max(gw-max(startGW, 1)+1, 0)
+	b.GWsLeft = max(lastGW-gw, 0)
+	if b.GWsElapsed > 0 {
+		b.Pace = round3(float64(b.Used) / float64(b.GWsElapsed))
+	}
+	if w.GWLimit > 0 {
+		left := max(w.GWLimit-b.UsedThisGW, 0)
+		b.RemainingThisGW = &left
+	}
+	if w.SeasonLimit > 0 {
+		left := max(w.SeasonLimit-b.Used, 0)
+		b.Remaining = &left
+		if b.GWsLeft > 0 {
+			sustainable := round3(float64(left) / float64(b.GWsLeft))
+			b.SustainablePace = &sustainable
+		}
+	}
+
+	switch {
+	case b.Remaining != nil && *b.Remaining == 0:
+		b.Warning = fmt.Sprintf("the season cap (%d) is used up", w.SeasonLimit)
+	case b.RemainingThisGW != nil && *b.RemainingThisGW == 0:
+		b.Warning = fmt.Sprintf("the GW%d cap (%d) is used up", gw, w.GWLimit)
+	case b.SustainablePace != nil && b.Pace > *b.SustainablePace:
+		runsOut := gw + int(math.Ceil(float64(*b.Remaining)/b.Pace))
+		b.Warning = fmt.Sprintf("%.2f moves a GW is ahead of the sustainable %.2f: the %d left run out around GW%d of %d", b.Pace, *b.SustainablePace, *b.Remaining, runsOut, lastGW)
+	}
+	return b
+}
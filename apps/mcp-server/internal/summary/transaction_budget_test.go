@@ -0,0 +1,82 @@
+package summary
+
+import (
+	"testing"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/leagueconfig"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/reconcile"
+)
+
+// budgetTransactions: entry 200 makes one move a GW through GW4, has a GW4
+// claim rejected and a GW5 move after the budget's GW; 201 makes one move.
+func budgetTransactions() []reconcile.Transaction {
+	tx := func(entry, gw int, kind, result string) reconcile.Transaction {
+		return reconcile.Transaction{Entry: entry, Event: gw, Kind: kind, Result: result, ElementIn: gw * 10, ElementOut: gw*10 + 1}
+	}
+	return []reconcile.Transaction{
+		tx(200, 1, "w", "a"), tx(200, 2, "f", "a"), tx(200, 3, "w", "a"), tx(200, 4, "w", "a"),
+		tx(200, 4, "w", "r"), tx(200, 5, "f", "a"), tx(201, 2, "w", "a"),
+	}
+}
+
+func TestBuildTransactionBudget_Pace(t *testing.T) {
+	w := leagueconfig.Waivers{SeasonLimit: 20, GWLimit: 2}
+	b := BuildTransactionBudget(w, budgetTransactions(), 200, 4, 1, 38)
+	if b.Used != 4 || b.UsedThisGW != 1 || *b.Remaining != 16 || *b.RemainingThisGW != 1 {
+		t.Fatalf("budget = %+v, want 4 used (1 this GW), 16 and 1 left", b)
+	}
+	// 4 moves in 4 GWs is 1 a GW; 16 over the 34 GWs after GW4 is 0.471.
+	if b.GWsElapsed != 4 || b.GWsLeft != 34 || b.Pace != 1 || *b.SustainablePace != 0.471 {
+		t.Errorf("pace = %+v, want 1 a GW against 0.471", b)
+	}
+	if want := "1.00 moves a GW is ahead of the sustainable 0.47: the 16 left run out around GW20 of 38"; b.Warning != want {
+		t.Errorf("warning = %q, want %q", b.Warning, want)
+	}
+	if b.Exhausted() {
+		t.Error("budget with moves left reported exhausted")
+	}
+
+	// A league starting at GW3 has only 2 GWs elapsed by GW4.
+	if b := BuildTransactionBudget(w, budgetTransactions(), 201, 4, 3, 38); b.GWsElapsed != 2 || b.Pace != 0.5 || b.Warning != "" {
+		t.Errorf("late start = %+v, want 1 move over 2 GWs and on pace", b)
+	}
+}
+
+func TestBuildTransactionBudget_Exhausted(t *testing.T) {
+	b := BuildTransactionBudget(leagueconfig.Waivers{GWLimit: 1}, budgetTransactions(), 200, 4, 1, 38)
+	if !b.Exhausted() || b.Remaining != nil || b.SustainablePace != nil || b.Warning != "the GW4 cap (1) is used up" {
+		t.Errorf("GW cap = %+v, want GW4 used up and no season fields", b)
+	}
+	b = BuildTransactionBudget(leagueconfig.Waivers{SeasonLimit: 4}, budgetTransactions(), 200, 4, 1, 38)
+	if !b.Exhausted() || *b.Remaining != 0 || b.RemainingThisGW != nil || b.Warning != "the season cap (4) is used up" {
+		t.Errorf("season cap = %+v, want used up", b)
+	}
+	// The last GW has nothing left to pace over.
+	if b := BuildTransactionBudget(leagueconfig.Waivers{SeasonLimit: 10}, budgetTransactions(), 200, 38, 1, 38); b.SustainablePace != nil || b.GWsLeft != 0 {
+		t.Errorf("final GW = %+v, want no sustainable pace", b)
+	}
+}
+
+func TestBuildTransactionsDigest_Budget(t *testing.T) {
+	names := map[int]string{200: "Alpha", 201: "Beta"}
+	uncapped := buildTransactionsDigest(100, 4, []int{200, 201}, names, budgetTransactions(), nil, leagueconfig.Default())
+	for _, e := range uncapped.Entries {
+		if e.Budget != nil {
+			t.Errorf("uncapped %s budget = %+v, want none", e.EntryName, e.Budget)
+		}
+	}
+
+	capped := leagueconfig.Default()
+	capped.Waivers.SeasonLimit = 20
+	out := buildTransactionsDigest(100, 4, []int{200, 201}, names, budgetTransactions(), nil, capped)
+	got := map[string]int{}
+	for _, e := range out.Entries {
+		if e.Budget == nil {
+			t.Fatalf("capped %s has no budget", e.EntryName)
+		}
+		got[e.EntryName] = *e.Budget.Remaining
+	}
+	if got["Alpha"] != 16 || got["Beta"] != 19 {
+		t.Errorf("remaining = %v, want Alpha 16 and Beta 19", got)
+	}
+}
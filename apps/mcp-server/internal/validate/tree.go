@@ -0,0 +1,275 @@
+package validate
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+)
+
+// TreeReport is the result of cross-checking a whole raw tree for one
+// league. Unlike Raw, which checks each file on its own before a derive,
+// Tree checks that the files agree with each other: a tree assembled from
+// copies taken at different refreshes can pass Raw file by file and still
+// reference players or GWs another file doesn't know.
+type TreeReport struct {
+	RawRoot      string    `json:"raw_root"`
+	LeagueID     int       `json:"league_id"`
+	CurrentEvent int       `json:"current_event"`
+	NewestLiveGW int       `json:"newest_live_gw"`
+	FilesChecked int       `json:"files_checked"`
+	Failures     []Failure `json:"failures"`
+}
+
+// OK reports whether every cross-check passed.
+func (r TreeReport) OK() bool {
+	return len(r.Failures) == 0
+}
+
+// Tree cross-checks the raw files for leagueID:
+//   - every pick in an entry event file is a bootstrap element, and one the
+//     same GW's live.json has stats for;
+//   - every league entry has an event file for each GW from its first match
+//     to game.json's current_event;
+//   - every GW with an entry event file has a live.json;
+//   - transactions and trades name only league entries and bootstrap
+//     elements;
+//   - the newest live.json is game.json's current_event or the GW before it.
+//
+// Files that can't be read or parsed are failures too, with check "read" or
+// "parse"; the checks needing them are skipped.
+func Tree(st *store.JSONStore, leagueID int) TreeReport {
+	r := TreeReport{RawRoot: st.Path(""), LeagueID: leagueID, Failures: make([]Failure, 0)}
+	add := func(f Failure) { r.Failures = append(r.Failures, f) }
+	read := func(rel string, gw int, v any) bool {
+		raw, err := st.ReadRaw(rel)
+		if err != nil {
+			add(fail(rel, gw, "read", "%v", err))
+			return false
+		}
+		r.FilesChecked++
+		if err := json.Unmarshal(raw, v); err != nil {
+			add(fail(rel, gw, "parse", "%v", err))
+			return false
+		}
+		return true
+	}
+
+	elements := make(map[int]bool)
+	var bootstrap struct {
+		Elements []struct {
+			ID int `json:"id"`
+		} `json:"elements"`
+	}
+	haveBootstrap := read("bootstrap/bootstrap-static.json", 0, &bootstrap)
+	for _, e := range bootstrap.Elements {
+		elements[e.ID] = true
+	}
+
+	var game struct {
+		CurrentEvent int `json:"current_event"`
+	}
+	if read("game/game.json", 0, &game) {
+		r.CurrentEvent = game.CurrentEvent
+	}
+
+	liveGWs := listGWs(st.Path("gw"), func(name string) (int, bool) {
+		gw, err := strconv.Atoi(name)
+		return gw, err == nil && gw > 0 && st.Exists(fmt.Sprintf("gw/%d/live.json", gw))
+	})
+	if len(liveGWs) > 0 {
+		r.NewestLiveGW = liveGWs[len(liveGWs)-1]
+	}
+	if r.CurrentEvent > 0 {
+		switch {
+		case r.NewestLiveGW > r.CurrentEvent:
+			add(fail("game/game.json", 0, "current_event_behind", "current_event is GW%d but gw/%d/live.json exists; game.json is from an older refresh", r.CurrentEvent, r.NewestLiveGW))
+		case r.NewestLiveGW < r.CurrentEvent-1:
+			add(fail("game/game.json", 0, "live_behind", "current_event is GW%d but the newest live.json is GW%d", r.CurrentEvent, r.NewestLiveGW))
+		}
+	}
+	liveElements := make(map[int]map[int]bool)
+	for _, gw := range liveGWs {
+		rel := fmt.Sprintf("gw/%d/live.json", gw)
+		var live struct {
+			Elements map[string]json.RawMessage `json:"elements"`
+		}
+		if !read(rel, gw, &live) {
+			continue
+		}
+		ids := make(map[int]bool, len(live.Elements))
+		for k := range live.Elements {
+			if id, err := strconv.Atoi(k); err == nil {
+				ids[id] = true
+			}
+		}
+		liveElements[gw] = ids
+	}
+
+	detailsPath := fmt.Sprintf("league/%d/details.json", leagueID)
+	var details struct {
+		League struct {
+			StartEvent int `json:"start_event"`
+		} `json:"league"`
+		LeagueEntries []struct {
+			ID      int `json:"id"`
+			EntryID int `json:"entry_id"`
+		} `json:"league_entries"`
+		Matches []struct {
+			Event        int `json:"event"`
+			LeagueEntry1 int `json:"league_entry_1"`
+			LeagueEntry2 int `json:"league_entry_2"`
+		} `json:"matches"`
+	}
+	if !read(detailsPath, 0, &details) {
+		return r
+	}
+	entries := make(map[int]bool, len(details.LeagueEntries))
+	firstMatch := make(map[int]int)
+	for _, m := range details.Matches {
+		for _, id := range []int{m.LeagueEntry1, m.LeagueEntry2} {
+			if f, ok := firstMatch[id]; !ok || m.Event < f {
+				firstMatch[id] = m.Event
+			}
+		}
+	}
+	throughGW := r.CurrentEvent
+	if throughGW == 0 {
+		throughGW = r.NewestLiveGW
+	}
+
+	for _, le := range details.LeagueEntries {
+		if le.EntryID == 0 {
+			continue
+		}
+		entries[le.EntryID] = true
+		eventGWs := listGWs(st.Path(fmt.Sprintf("entry/%d/gw", le.EntryID)), func(name string) (int, bool) {
+			gw, err := strconv.Atoi(strings.TrimSuffix(name, ".json"))
+			return gw, err == nil && gw > 0 && strings.HasSuffix(name, ".json")
+		})
+		have := make(map[int]bool, len(eventGWs))
+		for _, gw := range eventGWs {
+			have[gw] = true
+		}
+		from := max(details.League.StartEvent, firstMatch[le.ID], 1)
+		var missing []int
+		for gw := from; gw <= throughGW; gw++ {
+			if !have[gw] {
+				missing = append(missing, gw)
+			}
+		}
+		if len(missing) > 0 {
+			add(fail(fmt.Sprintf("entry/%d/gw", le.EntryID), 0, "entry_event_missing", "no event file for GW %s", joinInts(missing)))
+		}
+
+		for _, gw := range eventGWs {
+			rel := fmt.Sprintf("entry/%d/gw/%d.json", le.EntryID, gw)
+			if liveElements[gw] == nil && !st.Exists(fmt.Sprintf("gw/%d/live.json", gw)) {
+				add(fail(rel, gw, "live_missing", "GW%d has entry events but no gw/%d/live.json", gw, gw))
+			}
+			var event struct {
+				Picks []struct {
+					Element int `json:"element"`
+				} `json:"picks"`
+			}
+			if !read(rel, gw, &event) {
+				continue
+			}
+			var unknown, notLive []int
+			for _, p := range event.Picks {
+				switch {
+				case haveBootstrap && !elements[p.Element]:
+					unknown = append(unknown, p.Element)
+				case liveElements[gw] != nil && !liveElements[gw][p.Element]:
+					notLive = append(notLive, p.Element)
+				}
+			}
+			if len(unknown) > 0 {
+				add(fail(rel, gw, "pick_unknown_element", "picks %s are not in bootstrap-static.json", joinInts(unknown)))
+			}
+			if len(notLive) > 0 {
+				add(fail(rel, gw, "pick_not_in_live", "picks %s have no entry in gw/%d/live.json; the two files are probably from different refreshes", joinInts(notLive), gw))
+			}
+		}
+	}
+
+	checkRef := func(rel string, kind string, id int, entry int, elementIDs ...int) {
+		if !entries[entry] {
+			add(fail(rel, 0, "unknown_entry", "%s %d is by entry %d, which isn't in the league", kind, id, entry))
+		}
+		if !haveBootstrap {
+			return
+		}
+		for _, e := range elementIDs {
+			if e != 0 && !elements[e] {
+				add(fail(rel, 0, "unknown_element", "%s %d moves element %d, which isn't in bootstrap-static.json", kind, id, e))
+			}
+		}
+	}
+	txPath := fmt.Sprintf("league/%d/transactions.json", leagueID)
+	var txs struct {
+		Transactions []struct {
+			ID         int `json:"id"`
+			Entry      int `json:"entry"`
+			ElementIn  int `json:"element_in"`
+			ElementOut int `json:"element_out"`
+		} `json:"transactions"`
+	}
+	if read(txPath, 0, &txs) {
+		for _, tx := range txs.Transactions {
+			checkRef(txPath, "transaction", tx.ID, tx.Entry, tx.ElementIn, tx.ElementOut)
+		}
+	}
+	tradesPath := fmt.Sprintf("league/%d/trades.json", leagueID)
+	var trades struct {
+		Trades []struct {
+			ID            int `json:"id"`
+			OfferedEntry  int `json:"offered_entry"`
+			ReceivedEntry int `json:"received_entry"`
+			Items         []struct {
+				ElementIn  int `json:"element_in"`
+				ElementOut int `json:"element_out"`
+			} `json:"tradeitem_set"`
+		} `json:"trades"`
+	}
+	if read(tradesPath, 0, &trades) {
+		for _, tr := range trades.Trades {
+			ids := make([]int, 0, 2*len(tr.Items))
+			for _, it := range tr.Items {
+				ids = append(ids, it.ElementIn, it.ElementOut)
+			}
+			checkRef(tradesPath, "trade", tr.ID, tr.OfferedEntry, ids...)
+			checkRef(tradesPath, "trade", tr.ID, tr.ReceivedEntry)
+		}
+	}
+	return r
+}
+
+// listGWs returns the sorted GWs parsed from the names in dir; parse
+// reports whether a name is one. A missing dir has none.
+func listGWs(dir string, parse func(name string) (int, bool)) []int {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	out := make([]int, 0, len(files))
+	for _, f := range files {
+		if gw, ok := parse(f.Name()); ok {
+			out = append(out, gw)
+		}
+	}
+	sort.Ints(out)
+	return out
+}
+
+func joinInts(ids []int) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.Itoa(id)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,128 @@
+package validate
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+)
+
+// writeTree lays out a consistent raw tree for league 9: entries 100 and
+// 200 over GW1-2, picks from elements 1-15, each GW's live.json covering
+// elements 1-20.
+func writeTree(t *testing.T) (string, func(rel string, v any)) {
+	t.Helper()
+	root := t.TempDir()
+	write := func(rel string, v any) {
+		t.Helper()
+		path := filepath.Join(root, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, mustJSON(t, v), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	picks := func(ids ...int) map[string]any {
+		out := make([]any, len(ids))
+		for i, id := range ids {
+			out[i] = map[string]any{"element": id}
+		}
+		return map[string]any{"picks": out}
+	}
+	write("bootstrap/bootstrap-static.json", map[string]any{"elements": list(500), "teams": list(20)})
+	write("game/game.json", map[string]any{"current_event": 2})
+	write("league/9/details.json", map[string]any{
+		"league_entries": []any{map[string]any{"id": 1, "entry_id": 100}, map[string]any{"id": 2, "entry_id": 200}},
+		"matches": []any{
+			map[string]any{"event": 1, "league_entry_1": 1, "league_entry_2": 2},
+			map[string]any{"event": 2, "league_entry_1": 2, "league_entry_2": 1},
+		},
+	})
+	write("league/9/transactions.json", map[string]any{"transactions": []any{
+		map[string]any{"id": 1, "entry": 100, "element_in": 16, "element_out": 1},
+	}})
+	write("league/9/trades.json", map[string]any{"trades": []any{
+		map[string]any{"id": 1, "offered_entry": 100, "received_entry": 200, "tradeitem_set": []any{map[string]any{"element_in": 2, "element_out": 3}}},
+	}})
+	live := make(map[string]any)
+	for id := 1; id <= 20; id++ {
+		live[strconv.Itoa(id)] = map[string]any{}
+	}
+	for gw := 1; gw <= 2; gw++ {
+		write(filepath.Join("gw", strconv.Itoa(gw), "live.json"), map[string]any{"elements": live})
+		write(filepath.Join("entry/100/gw", strconv.Itoa(gw)+".json"), picks(1, 2, 3))
+		write(filepath.Join("entry/200/gw", strconv.Itoa(gw)+".json"), picks(4, 5, 6))
+	}
+	return root, func(rel string, v any) {
+		t.Helper()
+		if v == nil {
+			if err := os.Remove(filepath.Join(root, rel)); err != nil {
+				t.Fatal(err)
+			}
+			return
+		}
+		write(rel, v)
+	}
+}
+
+func TestTree_Clean(t *testing.T) {
+	root, _ := writeTree(t)
+	r := Tree(store.NewJSONStore(root), 9)
+	if !r.OK() {
+		t.Fatalf("consistent tree failed: %v", r.Failures)
+	}
+	if r.CurrentEvent != 2 || r.NewestLiveGW != 2 || r.FilesChecked != 11 {
+		t.Errorf("report = %+v, want current and newest live GW2 over 11 files", r)
+	}
+}
+
+func TestTree_CrossFileFailures(t *testing.T) {
+	cases := []struct {
+		name  string
+		apply func(set func(string, any))
+		want  []string
+	}{
+		{"pick outside bootstrap", func(set func(string, any)) {
+			set("entry/100/gw/2.json", map[string]any{"picks": []any{map[string]any{"element": 1}, map[string]any{"element": 900}}})
+		}, []string{"pick_unknown_element"}},
+		{"live from an older refresh", func(set func(string, any)) {
+			set("gw/2/live.json", map[string]any{"elements": map[string]any{"1": map[string]any{}, "2": map[string]any{}}})
+		}, []string{"pick_not_in_live", "pick_not_in_live"}},
+		{"missing entry event", func(set func(string, any)) {
+			set("entry/200/gw/1.json", nil)
+		}, []string{"entry_event_missing"}},
+		{"entry events without live", func(set func(string, any)) {
+			set("entry/100/gw/3.json", map[string]any{"picks": []any{}})
+		}, []string{"live_missing"}},
+		{"game.json behind live", func(set func(string, any)) {
+			set("game/game.json", map[string]any{"current_event": 1})
+		}, []string{"current_event_behind"}},
+		{"game.json ahead of live", func(set func(string, any)) {
+			set("game/game.json", map[string]any{"current_event": 4})
+		}, []string{"live_behind", "entry_event_missing", "entry_event_missing"}},
+		{"transaction references", func(set func(string, any)) {
+			set("league/9/transactions.json", map[string]any{"transactions": []any{
+				map[string]any{"id": 7, "entry": 300, "element_in": 901, "element_out": 1},
+			}})
+		}, []string{"unknown_entry", "unknown_element"}},
+		{"trade references", func(set func(string, any)) {
+			set("league/9/trades.json", map[string]any{"trades": []any{
+				map[string]any{"id": 8, "offered_entry": 100, "received_entry": 201, "tradeitem_set": []any{map[string]any{"element_in": 902, "element_out": 3}}},
+			}})
+		}, []string{"unknown_element", "unknown_entry"}},
+		{"unreadable details", func(set func(string, any)) {
+			set("league/9/details.json", nil)
+		}, []string{"read"}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			root, set := writeTree(t)
+			c.apply(set)
+			r := Tree(store.NewJSONStore(root), 9)
+			assertChecks(t, r.Failures, c.want)
+		})
+	}
+}
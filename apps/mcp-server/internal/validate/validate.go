@@ -0,0 +1,272 @@
+// Package validate runs structural sanity checks on raw FPL Draft API files
+// before they are derived, so a bad or partial response is reported instead
+// of overwriting good derived summaries until the next refresh.
+package validate
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/ledger"
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+)
+
+// Thresholds for a complete bootstrap-static.json and entry event file.
+const (
+	MinElements = 400
+	TeamCount   = 20
+	SquadSize   = 15
+)
+
+// Failure is one check that a raw file failed. GW is 0 for league-wide files
+// (bootstrap, league details, transactions); those block the whole league.
+type Failure struct {
+	File   string `json:"file"`
+	Check  string `json:"check"`
+	Detail string `json:"detail"`
+	GW     int    `json:"gw,omitempty"`
+}
+
+func (f Failure) String() string {
+	return fmt.Sprintf("%s: %s: %s", f.File, f.Check, f.Detail)
+}
+
+// Report collects the failures from one validation pass.
+type Report struct {
+	Failures []Failure `json:"failures"`
+}
+
+// LeagueOK reports whether every league-wide file passed.
+func (r Report) LeagueOK() bool {
+	for _, f := range r.Failures {
+		if f.GW == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// BadGWs returns the gameweeks with at least one failed per-GW file.
+func (r Report) BadGWs() map[int]bool {
+	out := make(map[int]bool)
+	for _, f := range r.Failures {
+		if f.GW != 0 {
+			out[f.GW] = true
+		}
+	}
+	return out
+}
+
+func fail(file string, gw int, check string, format string, args ...any) Failure {
+	return Failure{File: file, GW: gw, Check: check, Detail: fmt.Sprintf(format, args...)}
+}
+
+// Bootstrap checks bootstrap-static.json has a full player list and all 20
+// teams.
+func Bootstrap(file string, raw []byte) []Failure {
+	var resp struct {
+		Elements []json.RawMessage `json:"elements"`
+		Teams    []json.RawMessage `json:"teams"`
+	}
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return []Failure{fail(file, 0, "parse", "%v", err)}
+	}
+	var out []Failure
+	if len(resp.Elements) <= MinElements {
+		out = append(out, fail(file, 0, "element_count", "%d elements, want more than %d", len(resp.Elements), MinElements))
+	}
+	if len(resp.Teams) != TeamCount {
+		out = append(out, fail(file, 0, "team_count", "%d teams, want %d", len(resp.Teams), TeamCount))
+	}
+	return out
+}
+
+// Live checks gw/{gw}/live.json has player stats and, once the GW has
+// started, its fixtures.
+func Live(file string, gw int, started bool, raw []byte) []Failure {
+	var resp struct {
+		Elements map[string]json.RawMessage `json:"elements"`
+		Fixtures []json.RawMessage          `json:"fixtures"`
+	}
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return []Failure{fail(file, gw, "parse", "%v", err)}
+	}
+	var out []Failure
+	if len(resp.Elements) == 0 {
+		out = append(out, fail(file, gw, "elements_empty", "elements map is empty"))
+	}
+	if started && len(resp.Fixtures) == 0 {
+		out = append(out, fail(file, gw, "fixtures_empty", "GW%d has started but lists no fixtures", gw))
+	}
+	return out
+}
+
+// LeagueDetails checks league details.json has entries, that every entry
+// referenced by a match is one of them, and that each GW from the league's
+// start_event to throughGW pairs every entry exactly once, from the entry's
+// first match on.
+func LeagueDetails(file string, throughGW int, raw []byte) []Failure {
+	var resp struct {
+		League struct {
+			StartEvent int `json:"start_event"`
+		} `json:"league"`
+		LeagueEntries []struct {
+			ID int `json:"id"`
+		} `json:"league_entries"`
+		Matches []struct {
+			Event        int `json:"event"`
+			LeagueEntry1 int `json:"league_entry_1"`
+			LeagueEntry2 int `json:"league_entry_2"`
+		} `json:"matches"`
+	}
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return []Failure{fail(file, 0, "parse", "%v", err)}
+	}
+	if len(resp.LeagueEntries) < 2 {
+		return []Failure{fail(file, 0, "entry_count", "%d league entries, want at least 2", len(resp.LeagueEntries))}
+	}
+	known := make(map[int]bool, len(resp.LeagueEntries))
+	for _, e := range resp.LeagueEntries {
+		known[e.ID] = true
+	}
+	// firstMatch is each entry's first GW with a match. An entry that joined
+	// mid-season has none before it, which isn't a missing pairing.
+	firstMatch := make(map[int]int)
+	for _, m := range resp.Matches {
+		for _, id := range []int{m.LeagueEntry1, m.LeagueEntry2} {
+			if f, ok := firstMatch[id]; !ok || m.Event < f {
+				firstMatch[id] = m.Event
+			}
+		}
+	}
+
+	var out []Failure
+	unknown := make(map[int]bool)
+	seen := make(map[int]map[int]int) // gw -> league entry -> matches
+	for _, m := range resp.Matches {
+		if seen[m.Event] == nil {
+			seen[m.Event] = make(map[int]int)
+		}
+		for _, id := range []int{m.LeagueEntry1, m.LeagueEntry2} {
+			if !known[id] {
+				unknown[id] = true
+			}
+			seen[m.Event][id]++
+		}
+	}
+	if len(unknown) > 0 {
+		out = append(out, fail(file, 0, "match_entries", "matches reference unknown league entries %v", sortedKeys(unknown)))
+	}
+	for gw := max(resp.League.StartEvent, 1); gw <= throughGW; gw++ {
+		var missing, doubled []int
+		for id := range known {
+			switch n := seen[gw][id]; {
+			case n == 0 && gw < firstMatch[id]:
+				// Not in the league yet.
+			case n == 0:
+				missing = append(missing, id)
+			case n > 1:
+				doubled = append(doubled, id)
+			}
+		}
+		if len(missing) == 0 && len(doubled) == 0 {
+			continue
+		}
+		sort.Ints(missing)
+		sort.Ints(doubled)
+		var parts []string
+		if len(missing) > 0 {
+			parts = append(parts, fmt.Sprintf("no match for %v", missing))
+		}
+		if len(doubled) > 0 {
+			parts = append(parts, fmt.Sprintf("several matches for %v", doubled))
+		}
+		out = append(out, fail(file, 0, "gw_pairs", "GW%d: %s", gw, strings.Join(parts, ", ")))
+	}
+	return out
+}
+
+// Transactions checks a transactions.json or trades.json body parses and has
+// its top-level list, which a truncated response won't.
+func Transactions(file string, key string, raw []byte) []Failure {
+	var resp map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return []Failure{fail(file, 0, "parse", "%v", err)}
+	}
+	var list []json.RawMessage
+	if err := json.Unmarshal(resp[key], &list); err != nil || resp[key] == nil {
+		return []Failure{fail(file, 0, "missing_list", "no %q array", key)}
+	}
+	return nil
+}
+
+// EntryEvent checks an entry's GW picks file has a full squad.
+func EntryEvent(file string, gw int, raw []byte) []Failure {
+	var resp struct {
+		Picks []json.RawMessage `json:"picks"`
+	}
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return []Failure{fail(file, gw, "parse", "%v", err)}
+	}
+	if len(resp.Picks) != SquadSize {
+		return []Failure{fail(file, gw, "pick_count", "%d picks, want %d", len(resp.Picks), SquadSize)}
+	}
+	return nil
+}
+
+// Raw validates the raw files a derive run for leagueID over minGW..maxGW
+// reads. GWs up to currentGW count as started. A missing file is a failure
+// too, since derivation would stop on it, except an entry event before the
+// entry's first one.
+func Raw(st *store.JSONStore, leagueID int, entryIDs []int, minGW int, maxGW int, currentGW int) Report {
+	var r Report
+	check := func(rel string, gw int, fn func([]byte) []Failure) {
+		raw, err := st.ReadRaw(rel)
+		if err != nil {
+			r.Failures = append(r.Failures, fail(rel, gw, "read", "%v", err))
+			return
+		}
+		r.Failures = append(r.Failures, fn(raw)...)
+	}
+
+	check("bootstrap/bootstrap-static.json", 0, func(b []byte) []Failure {
+		return Bootstrap("bootstrap/bootstrap-static.json", b)
+	})
+	detailsPath := fmt.Sprintf("league/%d/details.json", leagueID)
+	check(detailsPath, 0, func(b []byte) []Failure {
+		return LeagueDetails(detailsPath, min(maxGW, currentGW), b)
+	})
+	for _, f := range []struct{ name, key string }{{"transactions", "transactions"}, {"trades", "trades"}} {
+		rel := fmt.Sprintf("league/%d/%s.json", leagueID, f.name)
+		check(rel, 0, func(b []byte) []Failure { return Transactions(rel, f.key, b) })
+	}
+	// An entry that joined mid-season has no event files before its first
+	// one; snapshots stub those GWs.
+	firstEntryGW := make(map[int]int, len(entryIDs))
+	for _, entryID := range entryIDs {
+		firstEntryGW[entryID] = ledger.FirstEntryEventGW(st, entryID)
+	}
+	for gw := minGW; gw <= maxGW; gw++ {
+		livePath := fmt.Sprintf("gw/%d/live.json", gw)
+		check(livePath, gw, func(b []byte) []Failure { return Live(livePath, gw, gw <= currentGW, b) })
+		for _, entryID := range entryIDs {
+			rel := fmt.Sprintf("entry/%d/gw/%d.json", entryID, gw)
+			if gw < firstEntryGW[entryID] && !st.Exists(rel) {
+				continue
+			}
+			check(rel, gw, func(b []byte) []Failure { return EntryEvent(rel, gw, b) })
+		}
+	}
+	return r
+}
+
+func sortedKeys(m map[int]bool) []int {
+	out := make([]int, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	sort.Ints(out)
+	return out
+}
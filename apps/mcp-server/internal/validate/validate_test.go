@@ -0,0 +1,202 @@
+package validate
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/aatrey56/FPL-Draft-Agent/apps/mcp-server/internal/store"
+)
+
+func mustJSON(t *testing.T, v any) []byte {
+	t.Helper()
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func list(n int) []any {
+	out := make([]any, n)
+	for i := range out {
+		out[i] = map[string]any{"id": i + 1}
+	}
+	return out
+}
+
+// checks returns the Check names of fs, in order.
+func checks(fs []Failure) []string {
+	out := make([]string, 0, len(fs))
+	for _, f := range fs {
+		out = append(out, f.Check)
+	}
+	return out
+}
+
+func assertChecks(t *testing.T, got []Failure, want []string) {
+	t.Helper()
+	if g := strings.Join(checks(got), ","); g != strings.Join(want, ",") {
+		t.Errorf("checks = [%s], want [%s] (%v)", g, strings.Join(want, ","), got)
+	}
+}
+
+func TestBootstrap(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  []byte
+		want []string
+	}{
+		{"complete", mustJSON(t, map[string]any{"elements": list(600), "teams": list(20)}), nil},
+		{"empty elements", mustJSON(t, map[string]any{"elements": list(0), "teams": list(20)}), []string{"element_count"}},
+		{"short both", mustJSON(t, map[string]any{"elements": list(400), "teams": list(19)}), []string{"element_count", "team_count"}},
+		{"truncated", []byte(`{"elements": [{"id": 1}`), []string{"parse"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertChecks(t, Bootstrap("bootstrap.json", tt.raw), tt.want)
+		})
+	}
+}
+
+func TestLive(t *testing.T) {
+	full := mustJSON(t, map[string]any{"elements": map[string]any{"1": map[string]any{}}, "fixtures": list(10)})
+	noFixtures := mustJSON(t, map[string]any{"elements": map[string]any{"1": map[string]any{}}, "fixtures": []any{}})
+	tests := []struct {
+		name    string
+		started bool
+		raw     []byte
+		want    []string
+	}{
+		{"complete", true, full, nil},
+		{"empty elements", true, mustJSON(t, map[string]any{"elements": map[string]any{}, "fixtures": list(10)}), []string{"elements_empty"}},
+		{"started without fixtures", true, noFixtures, []string{"fixtures_empty"}},
+		{"future GW without fixtures", false, noFixtures, nil},
+		{"truncated", true, []byte(`{"elements": {`), []string{"parse"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Live("gw/7/live.json", 7, tt.started, tt.raw)
+			assertChecks(t, got, tt.want)
+			for _, f := range got {
+				if f.GW != 7 {
+					t.Errorf("failure %v has GW %d, want 7", f, f.GW)
+				}
+			}
+		})
+	}
+}
+
+func TestLeagueDetails(t *testing.T) {
+	entries := []any{map[string]any{"id": 1}, map[string]any{"id": 2}, map[string]any{"id": 3}, map[string]any{"id": 4}}
+	match := func(gw, a, b int) map[string]any {
+		return map[string]any{"event": gw, "league_entry_1": a, "league_entry_2": b}
+	}
+	tests := []struct {
+		name    string
+		entries []any
+		matches []any
+		want    []string
+	}{
+		{"complete", entries, []any{match(1, 1, 2), match(1, 3, 4), match(2, 1, 3), match(2, 2, 4)}, nil},
+		{"too few entries", entries[:1], nil, []string{"entry_count"}},
+		{"missing GW2 match", entries, []any{match(1, 1, 2), match(1, 3, 4), match(2, 1, 3)}, []string{"gw_pairs"}},
+		{"entry twice in a GW", entries, []any{match(1, 1, 2), match(1, 1, 4), match(2, 1, 3), match(2, 2, 4)}, []string{"gw_pairs"}},
+		{"unknown entry", entries, []any{match(1, 1, 2), match(1, 3, 9), match(1, 4, 9), match(2, 1, 3), match(2, 2, 4)}, []string{"match_entries"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw := mustJSON(t, map[string]any{"league_entries": tt.entries, "matches": tt.matches})
+			assertChecks(t, LeagueDetails("details.json", 2, raw), tt.want)
+		})
+	}
+}
+
+func TestLeagueDetails_StartEvent(t *testing.T) {
+	// A league that began in GW2 has no GW1 matches to pair.
+	raw := mustJSON(t, map[string]any{
+		"league":         map[string]any{"start_event": 2},
+		"league_entries": []any{map[string]any{"id": 1}, map[string]any{"id": 2}},
+		"matches":        []any{map[string]any{"event": 2, "league_entry_1": 1, "league_entry_2": 2}},
+	})
+	assertChecks(t, LeagueDetails("details.json", 2, raw), nil)
+}
+
+func TestTransactionsAndEntryEvent(t *testing.T) {
+	tests := []struct {
+		name string
+		got  []Failure
+		want []string
+	}{
+		{"transactions ok", Transactions("t.json", "transactions", []byte(`{"transactions": []}`)), nil},
+		{"transactions missing list", Transactions("t.json", "transactions", []byte(`{"element_status": []}`)), []string{"missing_list"}},
+		{"transactions truncated", Transactions("t.json", "transactions", []byte(`{"transactions": [{"id": 1},`)), []string{"parse"}},
+		{"full squad", EntryEvent("e.json", 3, mustJSON(t, map[string]any{"picks": list(15)})), nil},
+		{"short squad", EntryEvent("e.json", 3, mustJSON(t, map[string]any{"picks": list(11)})), []string{"pick_count"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertChecks(t, tt.got, tt.want)
+		})
+	}
+}
+
+func TestRaw(t *testing.T) {
+	root := t.TempDir()
+	write := func(rel string, v any) {
+		t.Helper()
+		path := filepath.Join(root, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, mustJSON(t, v), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("bootstrap/bootstrap-static.json", map[string]any{"elements": list(500), "teams": list(20)})
+	write("league/9/details.json", map[string]any{
+		"league_entries": []any{map[string]any{"id": 1}, map[string]any{"id": 2}},
+		"matches": []any{
+			map[string]any{"event": 1, "league_entry_1": 1, "league_entry_2": 2},
+			map[string]any{"event": 2, "league_entry_1": 2, "league_entry_2": 1},
+		},
+	})
+	write("league/9/transactions.json", map[string]any{"transactions": []any{}})
+	write("league/9/trades.json", map[string]any{"trades": []any{}})
+	for gw := 1; gw <= 2; gw++ {
+		write(filepath.Join("gw", strconv.Itoa(gw), "live.json"), map[string]any{"elements": map[string]any{"1": map[string]any{}}, "fixtures": list(10)})
+		for _, entry := range []int{100, 200} {
+			write(filepath.Join("entry", strconv.Itoa(entry), "gw", strconv.Itoa(gw)+".json"), map[string]any{"picks": list(15)})
+		}
+	}
+	st := store.NewJSONStore(root)
+
+	r := Raw(st, 9, []int{100, 200}, 1, 2, 2)
+	if len(r.Failures) != 0 || !r.LeagueOK() {
+		t.Fatalf("clean tree failed: %v", r.Failures)
+	}
+
+	// A GW2 entry file with a short squad blocks only GW2.
+	write("entry/200/gw/2.json", map[string]any{"picks": list(3)})
+	r = Raw(st, 9, []int{100, 200}, 1, 2, 2)
+	if !r.LeagueOK() {
+		t.Errorf("per-GW failure marked the league bad: %v", r.Failures)
+	}
+	if bad := r.BadGWs(); !bad[2] || bad[1] {
+		t.Errorf("BadGWs = %v, want only GW2", bad)
+	}
+	if len(r.Failures) != 1 || r.Failures[0].File != "entry/200/gw/2.json" || r.Failures[0].Check != "pick_count" {
+		t.Errorf("failures = %v, want one pick_count failure for entry/200/gw/2.json", r.Failures)
+	}
+
+	// A missing trades file is league-wide.
+	if err := os.Remove(filepath.Join(root, "league/9/trades.json")); err != nil {
+		t.Fatal(err)
+	}
+	if r = Raw(st, 9, []int{100, 200}, 1, 2, 2); r.LeagueOK() {
+		t.Errorf("missing trades.json passed league checks: %v", r.Failures)
+	}
+}